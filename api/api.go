@@ -0,0 +1,24 @@
+// Package api embeds the OpenAPI contract of the API and the Swagger UI rendering it.
+package api
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// Spec is the OpenAPI 3 contract of the API
+//
+//go:embed openapi.yaml
+var Spec []byte
+
+//go:embed swaggerui
+var swaggerUI embed.FS
+
+// SwaggerUI returns the Swagger UI static assets
+func SwaggerUI() fs.FS {
+	assets, err := fs.Sub(swaggerUI, "swaggerui")
+	if err != nil {
+		panic(err)
+	}
+	return assets
+}
@@ -0,0 +1,304 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+const (
+	maxBroadcastPayloadBytes = 32 << 10
+	maxBroadcastUsers        = 1000
+	// notificationStatsPeriod is the period the notification stats cover, up to now
+	notificationStatsPeriod = 24 * time.Hour
+)
+
+// topicPattern matches the topic names FCM accepts
+var topicPattern = regexp.MustCompile(`^[a-zA-Z0-9-_.~%]{1,900}$`)
+
+// BroadcastPayload targets either a topic or the devices of some users
+type BroadcastPayload struct {
+	Topic    string            `json:"topic"`
+	UserIDs  []string          `json:"user_ids"`
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Data     map[string]string `json:"data"`
+	DeepLink string            `json:"deep_link"`
+}
+
+func (p *BroadcastPayload) validate() []string {
+	var errs []string
+
+	switch {
+	case p.Topic != "" && len(p.UserIDs) > 0:
+		errs = append(errs, "topic: can't be given along with user_ids")
+	case len(p.UserIDs) > maxBroadcastUsers:
+		errs = append(errs, fmt.Sprintf("user_ids: at most %d users are allowed", maxBroadcastUsers))
+	case len(p.UserIDs) == 0 && !topicPattern.MatchString(p.Topic):
+		errs = append(errs, "topic: must be 1 to 900 letters, digits or -_.~%")
+	}
+	if p.Title == "" {
+		errs = append(errs, "title: is required")
+	}
+
+	return errs
+}
+
+// BroadcastNotification sends a notification to every device subscribed to a topic, the all-users
+// one reaching everyone signed in on the apps, or to the devices of the users given, multicast
+func (a *Application) BroadcastNotification(w http.ResponseWriter, r *http.Request) {
+	var payload BroadcastPayload
+	if err := decodeJSON(r, &payload, maxBroadcastPayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		respondError(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed, "invalid broadcast", errs)
+		return
+	}
+
+	n := notify.Notification{
+		Title:    payload.Title,
+		Body:     payload.Body,
+		Data:     payload.Data,
+		DeepLink: payload.DeepLink,
+	}
+	if len(payload.UserIDs) > 0 {
+		logging.FromContext(r.Context()).
+			WithField("user_id", r.Context().Value("userID")).
+			Warnf("broadcasting %q to %d users", payload.Title, len(payload.UserIDs))
+		a.workers.Go(r.Context(), func(ctx context.Context) {
+			tokens, err := a.devicesRepository.ListTokensByUsers(ctx, payload.UserIDs)
+			if err != nil {
+				logging.FromContext(ctx).Errorf("error resolving the devices to broadcast to: %v", err)
+				return
+			}
+			a.notifier.SendMulticast(ctx, tokens, n)
+		})
+		respondNoContent(w, http.StatusAccepted)
+		return
+	}
+
+	logging.FromContext(r.Context()).
+		WithField("user_id", r.Context().Value("userID")).
+		Warnf("broadcasting %q to topic %s", payload.Title, payload.Topic)
+	a.workers.Go(r.Context(), func(ctx context.Context) {
+		a.notifier.SendToTopic(ctx, payload.Topic, n)
+	})
+
+	respondNoContent(w, http.StatusAccepted)
+}
+
+// TestNotificationPayload targets either a user, the device with a token or a topic with a notification
+// sent right away. Without a title, its copy is rendered from the template of its event with Payload.
+type TestNotificationPayload struct {
+	UserID            string                 `json:"user_id"`
+	Token             string                 `json:"token"`
+	Topic             string                 `json:"topic"`
+	Title             string                 `json:"title"`
+	Body              string                 `json:"body"`
+	Data              map[string]string      `json:"data"`
+	DeepLink          string                 `json:"deep_link"`
+	Event             string                 `json:"event"`
+	Silent            bool                   `json:"silent"`
+	Payload           map[string]interface{} `json:"payload"`
+	DryRun            bool                   `json:"dry_run"`
+	BypassPreferences bool                   `json:"bypass_preferences"`
+}
+
+func (p *TestNotificationPayload) validate() []string {
+	var errs []string
+
+	targets := 0
+	for _, target := range []string{p.UserID, p.Token, p.Topic} {
+		if target != "" {
+			targets++
+		}
+	}
+	switch {
+	case targets != 1:
+		errs = append(errs, "user_id: exactly one of user_id, token and topic is required")
+	case p.Topic != "" && !topicPattern.MatchString(p.Topic):
+		errs = append(errs, "topic: must be 1 to 900 letters, digits or -_.~%")
+	}
+	if p.Title == "" && !p.Silent && (p.Event == "" || p.Payload == nil) {
+		errs = append(errs, "title: is required without an event and its payload")
+	}
+
+	return errs
+}
+
+func (p *TestNotificationPayload) target() string {
+	switch {
+	case p.UserID != "":
+		return p.UserID
+	case p.Token != "":
+		return p.Token
+	}
+	return p.Topic
+}
+
+// templatePayload turns the whole numbers of a JSON payload into integers, the templates comparing
+// them to integers
+func templatePayload(payload map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{}, len(payload))
+	for key, value := range payload {
+		if number, ok := value.(float64); ok && number == math.Trunc(number) {
+			value = int(number)
+		}
+		converted[key] = value
+	}
+	return converted
+}
+
+// TestNotificationResult is what became of a test notification: whether a preference of the user
+// suppressed it, what FCM said for each device, and the errors sending or rendering it
+type TestNotificationResult struct {
+	DryRun     bool                           `json:"dry_run"`
+	Suppressed bool                           `json:"suppressed"`
+	Devices    []TestNotificationDeviceResult `json:"devices"`
+	Errors     []string                       `json:"errors"`
+}
+
+// TestNotificationDeviceResult is what FCM said of the delivery to a device
+type TestNotificationDeviceResult struct {
+	Token     string `json:"token"`
+	Delivered bool   `json:"delivered"`
+	Error     string `json:"error,omitempty"`
+	Stale     bool   `json:"stale"`
+}
+
+// TestNotification sends a notification through the templates, the preferences of the user unless
+// bypassed, and FCM, responding with what became of it. It skips the queue, the retries and the
+// history, for the admins to see what FCM said right away.
+func (a *Application) TestNotification(w http.ResponseWriter, r *http.Request) {
+	var payload TestNotificationPayload
+	if err := decodeJSON(r, &payload, maxBroadcastPayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		respondError(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed, "invalid test notification", errs)
+		return
+	}
+
+	n := notify.Notification{
+		Title:    payload.Title,
+		Body:     payload.Body,
+		Data:     payload.Data,
+		DeepLink: payload.DeepLink,
+		Event:    payload.Event,
+		Silent:   payload.Silent,
+	}
+	if payload.Payload != nil {
+		n.Payload = templatePayload(payload.Payload)
+	}
+
+	result := TestNotificationResult{DryRun: payload.DryRun, Devices: []TestNotificationDeviceResult{}, Errors: []string{}}
+	notifier := notify.WithLinks(a.pushNotifier, a.notificationLinks())
+	if !payload.BypassPreferences {
+		notifier = notify.WithPreferences(notifier, notificationPreferences{a.preferencesRepository}, func(context.Context, string) {
+			result.Suppressed = true
+		})
+	}
+	if a.templates != nil {
+		notifier = notify.WithTemplates(notifier, a.templates, a.recipientLocales())
+	}
+
+	actorID, _ := r.Context().Value("userID").(string)
+	logging.FromContext(r.Context()).
+		WithField("user_id", actorID).
+		Warnf("sending test notification %q to %s", payload.Title, payload.target())
+
+	ctx, report := notify.WithReport(r.Context())
+	if payload.DryRun {
+		ctx = notify.WithDryRun(ctx)
+	}
+	switch {
+	case payload.UserID != "":
+		notifier.SendToUser(ctx, payload.UserID, n)
+	case payload.Token != "":
+		notifier.SendMulticast(ctx, []string{payload.Token}, n)
+	default:
+		notifier.SendToTopic(ctx, payload.Topic, n)
+	}
+
+	for _, res := range report.Results() {
+		device := TestNotificationDeviceResult{Token: res.Token, Delivered: res.Err == nil, Stale: res.Stale}
+		if res.Err != nil {
+			device.Error = res.Err.Error()
+		}
+		result.Devices = append(result.Devices, device)
+	}
+	for _, err := range report.Errors() {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	a.auditTestNotification(r.Context(), actorID, payload)
+	respondJSON(w, r, result, http.StatusOK)
+}
+
+// auditTestNotification records the test notification sent. It is sent already at this point, so a
+// failure is logged rather than reported to the client.
+func (a *Application) auditTestNotification(ctx context.Context, actorID string, payload TestNotificationPayload) {
+	entry := &repositories.AuditEntry{
+		ActorID:  actorID,
+		Action:   repositories.AuditNotificationTest,
+		TargetID: payload.target(),
+		Details: map[string]interface{}{
+			"title":              payload.Title,
+			"event":              payload.Event,
+			"dry_run":            payload.DryRun,
+			"bypass_preferences": payload.BypassPreferences,
+		},
+	}
+	auditCtx, cancel := context.WithTimeout(logging.Detach(ctx), auditRecordTimeout)
+	defer cancel()
+	if err := a.auditRepository.Record(auditCtx, []*repositories.AuditEntry{entry}); err != nil {
+		logging.FromContext(ctx).
+			WithError(err).
+			WithField("user_id", actorID).
+			Error("could not record the audit entry of a test notification")
+	}
+}
+
+// NotificationStats summarizes the notifications sent to the users since a time, from their history:
+// how many there were, by delivery status and by event then status
+type NotificationStats struct {
+	Since    time.Time                 `json:"since"`
+	Total    int                       `json:"total"`
+	ByStatus map[string]int            `json:"by_status"`
+	ByEvent  map[string]map[string]int `json:"by_event"`
+}
+
+// GetNotificationStats summarizes the notifications of the last 24 hours, for quick checks of their
+// delivery without the metrics. Only the notifications to users are kept, not the ones to topics.
+func (a *Application) GetNotificationStats(w http.ResponseWriter, r *http.Request) {
+	since := time.Now().Add(-notificationStatsPeriod).UTC()
+	counts, err := a.notificationsRepository.CountSince(r.Context(), since)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	stats := NotificationStats{Since: since, ByStatus: map[string]int{}, ByEvent: map[string]map[string]int{}}
+	for _, count := range counts {
+		event := count.Event
+		if event == "" {
+			event = "none"
+		}
+		if stats.ByEvent[event] == nil {
+			stats.ByEvent[event] = map[string]int{}
+		}
+		stats.Total += count.Count
+		stats.ByStatus[count.Status] += count.Count
+		stats.ByEvent[event][count.Status] += count.Count
+	}
+	respondJSON(w, r, stats, http.StatusOK)
+}
@@ -0,0 +1,234 @@
+package app
+
+import (
+	"appdoki-be/app/notify"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplication_BroadcastNotification(t *testing.T) {
+	newAdminApplication := func() *Application {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		return a
+	}
+	serve := func(a *Application, body string) *http.Response {
+		r := httptest.NewRequest("POST", "/admin/notifications/broadcast", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	t.Run("expect the notification to be sent to the topic", func(t *testing.T) {
+		a := newAdminApplication()
+
+		resp := serve(a, `{"topic":"all-users","title":"Beer o'clock","body":"Fridge restocked","deep_link":"appdoki://beers"}`)
+
+		assertStatusCode(t, resp, http.StatusAccepted)
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		sent := a.notifier.(*notify.Fake).Sent()
+		if len(sent) != 1 || sent[0].Topic != allUsersTopic || sent[0].Notification.Title != "Beer o'clock" || sent[0].Notification.DeepLink != "appdoki://beers" {
+			t.Fatalf("expected the broadcast to be sent, got %+v", sent)
+		}
+	})
+
+	t.Run("expect the notification to be multicast to the devices of the users", func(t *testing.T) {
+		a := newAdminApplication()
+		for _, device := range []*repos.DeviceToken{{Token: "a", UserID: "2"}, {Token: "b", UserID: "3"}, {Token: "c", UserID: "4"}} {
+			a.devicesRepository.Upsert(context.Background(), device)
+		}
+
+		resp := serve(a, `{"user_ids":["2","3"],"title":"Beer o'clock"}`)
+
+		assertStatusCode(t, resp, http.StatusAccepted)
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		sent := a.notifier.(*notify.Fake).Sent()
+		if len(sent) != 1 || !reflect.DeepEqual(sent[0].Tokens, []string{"a", "b"}) || sent[0].Notification.Title != "Beer o'clock" {
+			t.Fatalf("expected the broadcast to be multicast, got %+v", sent)
+		}
+	})
+
+	t.Run("expect a topic along with users to return 422", func(t *testing.T) {
+		resp := serve(newAdminApplication(), `{"topic":"all-users","user_ids":["2"],"title":"Beer o'clock"}`)
+
+		assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("expect an invalid topic to return 422", func(t *testing.T) {
+		resp := serve(newAdminApplication(), `{"topic":"all users","title":"Beer o'clock"}`)
+
+		assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+		assertErrorCode(t, resp, ErrCodeValidationFailed)
+	})
+
+	t.Run("expect other users to be forbidden", func(t *testing.T) {
+		resp := serve(newTestApplication(), `{"topic":"all-users","title":"Beer o'clock"}`)
+
+		assertStatusCode(t, resp, http.StatusForbidden)
+	})
+}
+
+func TestApplication_TestNotification(t *testing.T) {
+	newAdminApplication := func() *Application {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		templates, err := notify.LoadTemplates("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.templates = templates
+		return a
+	}
+	serve := func(a *Application, body string) (*http.Response, TestNotificationResult) {
+		r := httptest.NewRequest("POST", "/admin/notifications/test", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+
+		var result TestNotificationResult
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w.Result(), result
+	}
+
+	t.Run("expect the notification to be rendered and sent to the user right away", func(t *testing.T) {
+		a := newAdminApplication()
+
+		resp, result := serve(a, `{"user_id":"2","event":"beer_received","payload":{"TransferID":42,"Giver":"Alice","Beers":2,"Givers":0}}`)
+
+		assertStatusCode(t, resp, http.StatusOK)
+		sent := a.pushNotifier.(*notify.Fake).Sent()
+		if len(sent) != 1 || sent[0].UserID != "2" || sent[0].Notification.Body != "Alice just rewarded you with 2 beers!" {
+			t.Fatalf("expected the rendered notification to be sent, got %+v", sent)
+		}
+		if result.Suppressed || len(result.Errors) != 0 || len(a.notifier.(*notify.Fake).Sent()) != 0 {
+			t.Fatalf("expected the notification to skip the queue, got %+v", result)
+		}
+
+		entries := a.auditRepository.(*mockAuditRepository).Entries()
+		if len(entries) != 1 || entries[0].Action != repos.AuditNotificationTest || entries[0].TargetID != "2" || entries[0].ActorID != "1" {
+			t.Fatalf("expected the test notification to be audited, got %+v", entries)
+		}
+	})
+
+	t.Run("expect the preferences of the user to apply unless bypassed", func(t *testing.T) {
+		a := newAdminApplication()
+		a.preferencesRepository.SaveNotifications(context.Background(), "2", &repos.NotificationPreferences{})
+
+		resp, result := serve(a, `{"user_id":"2","title":"Cheers!","event":"beer_received"}`)
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if !result.Suppressed || len(a.pushNotifier.(*notify.Fake).Sent()) != 0 {
+			t.Fatalf("expected the notification to be suppressed, got %+v", result)
+		}
+
+		resp, result = serve(a, `{"user_id":"2","title":"Cheers!","event":"beer_received","bypass_preferences":true}`)
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if result.Suppressed || len(a.pushNotifier.(*notify.Fake).Sent()) != 1 {
+			t.Fatalf("expected the preferences to be bypassed, got %+v", result)
+		}
+	})
+
+	t.Run("expect the outcome of the delivery to the device to be returned", func(t *testing.T) {
+		a := newAdminApplication()
+
+		resp, result := serve(a, `{"token":"token-1","title":"Cheers!","dry_run":true}`)
+
+		assertStatusCode(t, resp, http.StatusOK)
+		expected := []TestNotificationDeviceResult{{Token: "token-1", Delivered: true}}
+		if !result.DryRun || !reflect.DeepEqual(result.Devices, expected) {
+			t.Fatalf("expected the delivery to the device, got %+v", result)
+		}
+	})
+
+	t.Run("expect the errors rendering the copy to be returned", func(t *testing.T) {
+		a := newAdminApplication()
+
+		resp, result := serve(a, `{"topic":"beers","event":"beer_received","payload":{"Giver":"Alice"}}`)
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "beer_received") {
+			t.Fatalf("expected the rendering error, got %+v", result)
+		}
+	})
+
+	t.Run("expect a notification without a single target to return 422", func(t *testing.T) {
+		for _, body := range []string{`{"title":"Cheers!"}`, `{"user_id":"2","topic":"beers","title":"Cheers!"}`, `{"user_id":"2"}`} {
+			resp, _ := serve(newAdminApplication(), body)
+
+			assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+			assertErrorCode(t, resp, ErrCodeValidationFailed)
+		}
+	})
+
+	t.Run("expect other users to be forbidden", func(t *testing.T) {
+		resp, _ := serve(newTestApplication(), `{"topic":"beers","title":"Cheers!"}`)
+
+		assertStatusCode(t, resp, http.StatusForbidden)
+	})
+}
+
+func TestApplication_GetNotificationStats(t *testing.T) {
+	t.Run("expect the notifications of the last 24 hours to be counted by status and event", func(t *testing.T) {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		repo := a.notificationsRepository.(*mockNotificationsRepository)
+		for _, n := range []struct {
+			event  string
+			status string
+			age    time.Duration
+		}{
+			{notify.EventBeerReceived, repos.NotificationSent, time.Hour},
+			{notify.EventBeerReceived, repos.NotificationSent, 2 * time.Hour},
+			{notify.EventBeerReceived, repos.NotificationSuppressed, time.Hour},
+			{"", repos.NotificationFailed, time.Hour},
+			{notify.EventBeerReceived, repos.NotificationSent, 25 * time.Hour},
+		} {
+			created, _ := repo.Create(context.Background(), &repos.Notification{UserID: "2", Event: n.event, CreatedAt: time.Now().Add(-n.age)})
+			repo.SetStatus(context.Background(), created.ID, n.status)
+		}
+
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, httptest.NewRequest("GET", "/admin/notifications/stats", nil))
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusOK)
+		var stats NotificationStats
+		if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+			t.Fatal(err)
+		}
+		if stats.Total != 4 || stats.ByStatus[repos.NotificationSent] != 2 || stats.ByStatus[repos.NotificationFailed] != 1 {
+			t.Fatalf("unexpected totals %+v", stats)
+		}
+		beers := stats.ByEvent[notify.EventBeerReceived]
+		if beers[repos.NotificationSent] != 2 || beers[repos.NotificationSuppressed] != 1 || stats.ByEvent["none"][repos.NotificationFailed] != 1 {
+			t.Fatalf("unexpected counts by event %+v", stats.ByEvent)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"appdoki-be/app/repositories"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+// AdminRouter mounts the operations routes, restricted to the admin users
+// and, when configured, to the clients of the allowed networks
+func (a *Application) AdminRouter(router *mux.Router) {
+	admin := router.PathPrefix("/admin").Subrouter()
+	if len(a.adminNetworks) > 0 {
+		admin.Use(mux.MiddlewareFunc(ipAllowlistMiddleware(a.adminNetworks, a.trustedProxies)))
+	}
+
+	adminAccess := roleAccess(repositories.RoleAdmin)
+	a.mount(admin,
+		routeDef{methods: []string{http.MethodGet}, path: "/maintenance", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetMaintenance)},
+		routeDef{methods: []string{http.MethodPost}, path: "/maintenance", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.SetMaintenance)},
+		routeDef{methods: []string{http.MethodGet}, path: "/features", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetFeatures)},
+		routeDef{methods: []string{http.MethodPut}, path: "/features/{name}", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.SetFeature)},
+		routeDef{methods: []string{http.MethodPost}, path: "/users/bulk", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.BulkUsers)},
+		routeDef{methods: []string{http.MethodPost}, path: "/notifications/broadcast", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.BroadcastNotification)},
+		routeDef{methods: []string{http.MethodPost}, path: "/notifications/test", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.TestNotification)},
+		routeDef{methods: []string{http.MethodGet}, path: "/notifications/stats", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetNotificationStats)},
+		routeDef{methods: []string{http.MethodGet}, path: "/notifications/dead-letters", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetDeadLetters)},
+		routeDef{methods: []string{http.MethodPost}, path: "/notifications/dead-letters/retry", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.RetryDeadLetters)},
+		routeDef{methods: []string{http.MethodPost}, path: "/notifications/dead-letters/{id}/retry", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.RetryDeadLetter)},
+		routeDef{methods: []string{http.MethodGet}, path: "/announcements", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetAnnouncements)},
+		routeDef{methods: []string{http.MethodPost}, path: "/announcements", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.CreateAnnouncement)},
+		routeDef{methods: []string{http.MethodGet}, path: "/webhooks", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetWebhookEndpoints)},
+		routeDef{methods: []string{http.MethodPost}, path: "/webhooks", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.CreateWebhookEndpoint)},
+		routeDef{methods: []string{http.MethodDelete}, path: "/webhooks/{id}", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.DeleteWebhookEndpoint)},
+		routeDef{methods: []string{http.MethodGet}, path: "/webhooks/{id}/deliveries", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetWebhookDeliveries)},
+		routeDef{methods: []string{http.MethodPost}, path: "/webhooks/{id}/deliveries/{delivery}/redeliver", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.RedeliverWebhook)},
+		routeDef{methods: []string{http.MethodPut}, path: "/log-level", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.SetLogLevel)},
+		routeDef{methods: []string{http.MethodGet}, path: "/debug/slow", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetSlowEvents)},
+		routeDef{methods: []string{http.MethodGet}, path: "/db/stats", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetDBStats)},
+		routeDef{methods: []string{http.MethodGet}, path: "/routes", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetRoutes)},
+		routeDef{methods: []string{http.MethodGet}, path: "/config", access: adminAccess,
+			handler: a.CacheControl(noStoreCache, a.GetConfig)},
+	)
+}
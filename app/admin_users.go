@@ -0,0 +1,195 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	maxBulkUsers             = 100
+	maxBulkUsersPayloadBytes = 64 << 10
+	auditRecordTimeout       = 5 * time.Second
+)
+
+// Actions of the users bulk endpoint
+const (
+	bulkUsersDeactivate = "deactivate"
+	bulkUsersDelete     = "delete"
+	bulkUsersRole       = "role"
+)
+
+type BulkUsersPayload struct {
+	Action  string          `json:"action"`
+	IDs     []string        `json:"ids"`
+	Params  BulkUsersParams `json:"params"`
+	Confirm bool            `json:"confirm"`
+}
+
+type BulkUsersParams struct {
+	Role string `json:"role"`
+}
+
+func (p *BulkUsersPayload) validate() []string {
+	var errs []string
+
+	switch p.Action {
+	case bulkUsersDeactivate, bulkUsersDelete:
+		if !p.Confirm {
+			errs = append(errs, "confirm: must be true for destructive actions")
+		}
+	case bulkUsersRole:
+		if p.Params.Role != repositories.RoleUser && p.Params.Role != repositories.RoleAdmin {
+			errs = append(errs, "params.role: must be one of user, admin")
+		}
+	default:
+		errs = append(errs, "action: must be one of deactivate, delete, role")
+	}
+
+	return errs
+}
+
+// BulkUsers applies an action to many users at once, responding with the outcome for each of them.
+// Admins can't include themselves, so they don't lock themselves out.
+func (a *Application) BulkUsers(w http.ResponseWriter, r *http.Request) {
+	var payload BulkUsersPayload
+	if err := decodeJSON(r, &payload, maxBulkUsersPayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		respondError(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed, "invalid bulk request", errs)
+		return
+	}
+	ids, err := bulkIDs(payload.IDs, maxBulkUsers)
+	if err != nil {
+		respondRequestError(w, err)
+		return
+	}
+
+	actorID, _ := r.Context().Value("userID").(string)
+	res := newBulkResponse(ids)
+	targets := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == actorID {
+			res.fail(id, ErrCodeForbidden, "admins can't apply bulk actions to themselves")
+			continue
+		}
+		targets = append(targets, id)
+	}
+
+	var done []string
+	switch payload.Action {
+	case bulkUsersDeactivate:
+		done = a.bulkUpdateUsers(r.Context(), res, targets, func(ctx context.Context, ids []string) ([]string, error) {
+			return a.usersRepository.DeactivateMany(ctx, ids)
+		})
+		a.deactivations.forget(done...)
+	case bulkUsersRole:
+		done = a.bulkUpdateUsers(r.Context(), res, targets, func(ctx context.Context, ids []string) ([]string, error) {
+			return a.usersRepository.SetRoleMany(ctx, ids, payload.Params.Role)
+		})
+	case bulkUsersDelete:
+		done = a.bulkDeleteUsers(r.Context(), res, targets)
+	}
+
+	a.auditBulkUsers(r.Context(), actorID, payload, done)
+	if payload.Action != bulkUsersDelete {
+		a.notifyUsersUpdated(r.Context(), done)
+	}
+
+	res.translate(responseLocale(w))
+	respondJSON(w, r, res, http.StatusOK)
+}
+
+// notifyUsersUpdated sends a silent push to the devices of the updated users, for the apps to
+// refresh their profile
+func (a *Application) notifyUsersUpdated(ctx context.Context, ids []string) {
+	for _, id := range ids {
+		a.notifier.SendToUser(ctx, id, notify.Notification{
+			Data:        map[string]string{"user_id": id},
+			Event:       eventUserUpdated,
+			Silent:      true,
+			CollapseKey: eventUserUpdated,
+		})
+	}
+}
+
+// bulkUpdateUsers runs an update over all the users in a single statement, returns the IDs of the updated ones
+func (a *Application) bulkUpdateUsers(ctx context.Context, res *BulkResponse, ids []string,
+	update func(ctx context.Context, ids []string) ([]string, error)) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	updated, err := update(ctx, ids)
+	if err != nil {
+		for _, id := range ids {
+			res.fail(id, ErrCodeInternal, "could not update the user")
+		}
+		return nil
+	}
+
+	found := make(map[string]bool, len(updated))
+	for _, id := range updated {
+		found[id] = true
+	}
+	for _, id := range ids {
+		if found[id] {
+			res.succeed(id)
+		} else {
+			res.fail(id, ErrCodeNotFound, "user not found")
+		}
+	}
+	return updated
+}
+
+// bulkDeleteUsers deletes the users one by one, as each may fail on its own, returns the IDs of the deleted ones
+func (a *Application) bulkDeleteUsers(ctx context.Context, res *BulkResponse, ids []string) []string {
+	deleted := make([]string, 0, len(ids))
+	for _, id := range ids {
+		ok, err := a.usersRepository.Delete(ctx, id)
+		var conflictErr *repositories.ConflictError
+		switch {
+		case errors.As(err, &conflictErr):
+			res.fail(id, ErrCodeConflict, "user is still referenced by other records, deactivate it instead")
+		case err != nil:
+			res.fail(id, ErrCodeInternal, "could not delete the user")
+		case !ok:
+			res.fail(id, ErrCodeNotFound, "user not found")
+		default:
+			res.succeed(id)
+			deleted = append(deleted, id)
+		}
+	}
+	return deleted
+}
+
+// auditBulkUsers records an audit entry per affected user. The changes are already
+// applied at this point, so a failure is logged rather than reported to the client.
+func (a *Application) auditBulkUsers(ctx context.Context, actorID string, payload BulkUsersPayload, ids []string) {
+	action, details := repositories.AuditUserDeactivated, map[string]interface{}(nil)
+	switch payload.Action {
+	case bulkUsersDelete:
+		action = repositories.AuditUserDeleted
+	case bulkUsersRole:
+		action, details = repositories.AuditUserRoleChanged, map[string]interface{}{"role": payload.Params.Role}
+	}
+
+	entries := make([]*repositories.AuditEntry, 0, len(ids))
+	for _, id := range ids {
+		entries = append(entries, &repositories.AuditEntry{ActorID: actorID, Action: action, TargetID: id, Details: details})
+	}
+	auditCtx, cancel := context.WithTimeout(logging.Detach(ctx), auditRecordTimeout)
+	defer cancel()
+	if err := a.auditRepository.Record(auditCtx, entries); err != nil {
+		logging.FromContext(ctx).
+			WithError(err).
+			WithField("user_id", actorID).
+			Errorf("could not record the audit entries of a bulk %s of %d users", payload.Action, len(ids))
+	}
+}
@@ -0,0 +1,170 @@
+package app
+
+import (
+	"appdoki-be/app/notify"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestApplication_BulkUsers(t *testing.T) {
+	newAdminApplication := func() *Application {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		return a
+	}
+	serve := func(a *Application, body string) *http.Response {
+		r := httptest.NewRequest("POST", "/admin/users/bulk", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+		return w.Result()
+	}
+	decode := func(t *testing.T, resp *http.Response) BulkResponse {
+		var res BulkResponse
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	t.Run("expect a role change to report every user and audit each affected one", func(t *testing.T) {
+		a := newAdminApplication()
+		a.usersRepository.(*mockUsersRepository).setRoleManyImpl = func(_ context.Context, IDs []string, role string) ([]string, error) {
+			if role != repos.RoleAdmin {
+				t.Fatalf("expected role '%s', got '%s'", repos.RoleAdmin, role)
+			}
+			return IDs[:1], nil
+		}
+
+		resp := serve(a, `{"action":"role","ids":["2","3","1","2"],"params":{"role":"admin"}}`)
+
+		assertStatusCode(t, resp, http.StatusOK)
+		res := decode(t, resp)
+		if res.Succeeded != 1 || res.Failed != 2 || len(res.Results) != 3 {
+			t.Fatalf("expected 1 success and 2 failures over 3 users, got %+v", res)
+		}
+		expected := []struct{ id, status, code string }{
+			{"2", bulkItemSucceeded, ""},
+			{"3", bulkItemFailed, ErrCodeNotFound},
+			{"1", bulkItemFailed, ErrCodeForbidden},
+		}
+		for i, e := range expected {
+			result := res.Results[i]
+			code := ""
+			if result.Error != nil {
+				code = result.Error.Code
+			}
+			if result.ID != e.id || result.Status != e.status || code != e.code {
+				t.Fatalf("expected result %d to be %+v, got %+v", i, e, result)
+			}
+		}
+
+		entries := a.auditRepository.(*mockAuditRepository).Entries()
+		if len(entries) != 1 || entries[0].TargetID != "2" || entries[0].ActorID != "1" ||
+			entries[0].Action != repos.AuditUserRoleChanged || entries[0].Details["role"] != repos.RoleAdmin {
+			t.Fatalf("expected a role change entry for user 2, got %+v", entries)
+		}
+		sent := a.notifier.(*notify.Fake).Sent()
+		if len(sent) != 1 || sent[0].UserID != "2" || !sent[0].Notification.Silent || sent[0].Notification.Event != eventUserUpdated {
+			t.Fatalf("expected a silent push to user 2, got %+v", sent)
+		}
+	})
+
+	t.Run("expect deletions to fail item by item", func(t *testing.T) {
+		a := newAdminApplication()
+		a.usersRepository.(*mockUsersRepository).deleteImpl = func(_ context.Context, ID string) (bool, error) {
+			switch ID {
+			case "2":
+				return false, &repos.ConflictError{Message: "still referenced by [beer_transfers]"}
+			case "3":
+				return false, nil
+			case "4":
+				return false, fmt.Errorf("connection reset")
+			}
+			return true, nil
+		}
+
+		resp := serve(a, `{"action":"delete","ids":["2","3","4","5"],"confirm":true}`)
+
+		assertStatusCode(t, resp, http.StatusOK)
+		res := decode(t, resp)
+		codes := []string{ErrCodeConflict, ErrCodeNotFound, ErrCodeInternal}
+		for i, code := range codes {
+			if res.Results[i].Error == nil || res.Results[i].Error.Code != code {
+				t.Fatalf("expected result %d to fail with '%s', got %+v", i, code, res.Results[i])
+			}
+		}
+		if res.Results[3].Status != bulkItemSucceeded || res.Succeeded != 1 || res.Failed != 3 {
+			t.Fatalf("expected only user 5 to be deleted, got %+v", res)
+		}
+
+		entries := a.auditRepository.(*mockAuditRepository).Entries()
+		if len(entries) != 1 || entries[0].TargetID != "5" || entries[0].Action != repos.AuditUserDeleted {
+			t.Fatalf("expected a deletion entry for user 5, got %+v", entries)
+		}
+		if sent := a.notifier.(*notify.Fake).Sent(); len(sent) != 0 {
+			t.Fatalf("expected the deleted users not to be notified, got %+v", sent)
+		}
+	})
+
+	t.Run("expect a failed batch update to fail every user", func(t *testing.T) {
+		a := newAdminApplication()
+		a.usersRepository.(*mockUsersRepository).deactivateManyImpl = func(_ context.Context, _ []string) ([]string, error) {
+			return nil, fmt.Errorf("connection reset")
+		}
+
+		resp := serve(a, `{"action":"deactivate","ids":["2","3"],"confirm":true}`)
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if res := decode(t, resp); res.Failed != 2 || res.Succeeded != 0 {
+			t.Fatalf("expected both users to fail, got %+v", res)
+		}
+		if entries := a.auditRepository.(*mockAuditRepository).Entries(); len(entries) != 0 {
+			t.Fatalf("expected no audit entry, got %+v", entries)
+		}
+	})
+
+	t.Run("expect destructive actions to require a confirmation", func(t *testing.T) {
+		for _, action := range []string{bulkUsersDeactivate, bulkUsersDelete} {
+			resp := serve(newAdminApplication(), `{"action":"`+action+`","ids":["2"]}`)
+
+			assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+			assertErrorCode(t, resp, ErrCodeValidationFailed)
+		}
+	})
+
+	t.Run("expect invalid requests to return 422", func(t *testing.T) {
+		tooMany := make([]string, maxBulkUsers+1)
+		for i := range tooMany {
+			tooMany[i] = fmt.Sprintf("%q", fmt.Sprint(i+2))
+		}
+		bodies := []string{
+			`{"action":"promote","ids":["2"]}`,
+			`{"action":"role","ids":["2"],"params":{"role":"owner"}}`,
+			`{"action":"role","ids":[],"params":{"role":"user"}}`,
+			`{"action":"role","ids":[" "],"params":{"role":"user"}}`,
+			`{"action":"role","ids":[` + strings.Join(tooMany, ",") + `],"params":{"role":"user"}}`,
+		}
+		for _, body := range bodies {
+			resp := serve(newAdminApplication(), body)
+
+			assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+			assertErrorCode(t, resp, ErrCodeValidationFailed)
+		}
+	})
+
+	t.Run("expect non admins to get 403", func(t *testing.T) {
+		resp := serve(newTestApplication(), `{"action":"role","ids":["2"],"params":{"role":"admin"}}`)
+
+		assertStatusCode(t, resp, http.StatusForbidden)
+	})
+}
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"appdoki-be/app/filter"
+	"appdoki-be/app/logging"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"context"
+	"fmt"
+	"github.com/lib/pq"
+	"net/http"
+	"strconv"
+)
+
+const (
+	maxAnnouncementPayloadBytes = 8 << 10
+	// announcementTopicThreshold is how many devices an announcement to all the users is multicast to
+	// at most, the topic reaching them beyond
+	announcementTopicThreshold = 500
+)
+
+// the channels the announcements are sent through
+const (
+	announcementTopic     = "topic"
+	announcementMulticast = "multicast"
+)
+
+// AnnouncementPayload is a push to all the users, the ones of a role or the users given. Announcing
+// to all the users needs Confirm.
+type AnnouncementPayload struct {
+	Title    string   `json:"title"`
+	Body     string   `json:"body"`
+	DeepLink string   `json:"deep_link"`
+	Audience string   `json:"audience"`
+	Role     string   `json:"role"`
+	UserIDs  []string `json:"user_ids"`
+	Confirm  bool     `json:"confirm"`
+}
+
+func (p *AnnouncementPayload) validate() []string {
+	var errs []string
+
+	switch p.Audience {
+	case repositories.AudienceAll:
+		if !p.Confirm {
+			errs = append(errs, "confirm: must be true to announce to all the users")
+		}
+	case repositories.AudienceRole:
+		if p.Role != repositories.RoleUser && p.Role != repositories.RoleAdmin {
+			errs = append(errs, "role: must be one of user, admin")
+		}
+	case repositories.AudienceUsers:
+		if len(p.UserIDs) == 0 || len(p.UserIDs) > maxBroadcastUsers {
+			errs = append(errs, fmt.Sprintf("user_ids: 1 to %d users are required", maxBroadcastUsers))
+		}
+	default:
+		errs = append(errs, "audience: must be one of all, role, users")
+	}
+	if p.Role != "" && p.Audience != repositories.AudienceRole {
+		errs = append(errs, "role: is only allowed with the role audience")
+	}
+	if len(p.UserIDs) > 0 && p.Audience != repositories.AudienceUsers {
+		errs = append(errs, "user_ids: are only allowed with the users audience")
+	}
+	if p.Title == "" {
+		errs = append(errs, "title: is required")
+	}
+
+	return errs
+}
+
+// CreateAnnouncement keeps the announcement and sends it in the background, responding with the
+// devices targeted. The announcements to all the users reach them through the all-users topic when
+// they have too many devices to multicast to, the others are multicast to the devices of their users.
+// How many devices it was delivered to is set once sent, unknown for the topic sends.
+func (a *Application) CreateAnnouncement(w http.ResponseWriter, r *http.Request) {
+	var payload AnnouncementPayload
+	if err := decodeJSON(r, &payload, maxAnnouncementPayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		respondError(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed, "invalid announcement", errs)
+		return
+	}
+
+	userIDs, err := a.announcementAudience(r.Context(), payload)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	tokens, err := a.devicesRepository.ListTokensByUsers(r.Context(), userIDs)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	channel := announcementMulticast
+	if payload.Audience == repositories.AudienceAll && len(tokens) > announcementTopicThreshold {
+		channel = announcementTopic
+	}
+
+	actorID, _ := r.Context().Value("userID").(string)
+	announcement, err := a.announcementsRepository.Create(r.Context(), &repositories.Announcement{
+		Title:     payload.Title,
+		Body:      payload.Body,
+		DeepLink:  payload.DeepLink,
+		Audience:  payload.Audience,
+		Role:      payload.Role,
+		UserIDs:   pq.StringArray(payload.UserIDs),
+		Channel:   channel,
+		Targeted:  len(tokens),
+		CreatedBy: &actorID,
+	})
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	logging.FromContext(r.Context()).
+		WithField("user_id", actorID).
+		Warnf("announcing %q to %d devices by %s", payload.Title, len(tokens), channel)
+	a.auditAnnouncement(r.Context(), actorID, announcement)
+	a.workers.Go(r.Context(), func(ctx context.Context) {
+		a.sendAnnouncement(ctx, announcement, tokens)
+	})
+
+	respondJSON(w, r, announcement, http.StatusAccepted)
+}
+
+// announcementAudience resolves the active users the announcement is for
+func (a *Application) announcementAudience(ctx context.Context, payload AnnouncementPayload) ([]string, error) {
+	if payload.Audience == repositories.AudienceUsers {
+		return payload.UserIDs, nil
+	}
+
+	var f filter.Filter
+	if payload.Audience == repositories.AudienceRole {
+		var err error
+		if f, err = filter.Parse("role:"+payload.Role, repositories.UserRoleFilterFields); err != nil {
+			return nil, err
+		}
+	}
+	users, err := a.usersRepository.GetAll(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	return userIDs, nil
+}
+
+// sendAnnouncement pushes the announcement right away, setting how many devices it was delivered to
+func (a *Application) sendAnnouncement(ctx context.Context, announcement *repositories.Announcement, tokens []string) {
+	n := notify.Notification{
+		Title:    announcement.Title,
+		Body:     announcement.Body,
+		DeepLink: announcement.DeepLink,
+	}
+	notifier := notify.WithLinks(a.pushNotifier, a.notificationLinks())
+	sendCtx, report := notify.WithReport(ctx)
+
+	status := repositories.AnnouncementSent
+	var delivered *int
+	if announcement.Channel == announcementTopic {
+		notifier.SendToTopic(sendCtx, allUsersTopic, n)
+		if len(report.Errors()) > 0 {
+			status = repositories.AnnouncementFailed
+		}
+	} else {
+		notifier.SendMulticast(sendCtx, tokens, n)
+		count := 0
+		for _, result := range report.Results() {
+			if result.Err == nil {
+				count++
+			}
+		}
+		delivered = &count
+		if count == 0 && len(report.Errors()) > 0 {
+			status = repositories.AnnouncementFailed
+		}
+	}
+
+	if err := a.announcementsRepository.Finish(ctx, announcement.ID, status, delivered); err != nil {
+		logging.FromContext(ctx).Errorf("error setting the outcome of announcement %d: %v", announcement.ID, err)
+	}
+}
+
+// auditAnnouncement records the announcement, its failure being logged rather than blocking it
+func (a *Application) auditAnnouncement(ctx context.Context, actorID string, announcement *repositories.Announcement) {
+	entry := &repositories.AuditEntry{
+		ActorID:  actorID,
+		Action:   repositories.AuditAnnouncement,
+		TargetID: strconv.FormatInt(announcement.ID, 10),
+		Details: map[string]interface{}{
+			"title":    announcement.Title,
+			"audience": announcement.Audience,
+			"role":     announcement.Role,
+			"users":    len(announcement.UserIDs),
+			"channel":  announcement.Channel,
+			"targeted": announcement.Targeted,
+		},
+	}
+	auditCtx, cancel := context.WithTimeout(logging.Detach(ctx), auditRecordTimeout)
+	defer cancel()
+	if err := a.auditRepository.Record(auditCtx, []*repositories.AuditEntry{entry}); err != nil {
+		logging.FromContext(ctx).
+			WithError(err).
+			WithField("user_id", actorID).
+			Error("could not record the audit entry of an announcement")
+	}
+}
+
+// GetAnnouncements lists the announcements, the latest first
+func (a *Application) GetAnnouncements(w http.ResponseWriter, r *http.Request) {
+	params := newQueryParams(r)
+	limit := params.IntInRange("limit", 20, 1, 100)
+	if err := params.Err(); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+
+	announcements, err := a.announcementsRepository.List(r.Context(), limit)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	respondJSON(w, r, announcements, http.StatusOK)
+}
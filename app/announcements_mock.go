@@ -0,0 +1,56 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"sync"
+	"time"
+)
+
+// mockAnnouncementsRepository keeps the announcements in memory
+type mockAnnouncementsRepository struct {
+	mu            sync.Mutex
+	announcements []*repos.Announcement
+}
+
+func newMockAnnouncementsRepository() *mockAnnouncementsRepository {
+	return &mockAnnouncementsRepository{}
+}
+
+func (r *mockAnnouncementsRepository) Create(_ context.Context, announcement *repos.Announcement) (*repos.Announcement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	created := *announcement
+	created.ID = int64(len(r.announcements) + 1)
+	created.Status = repos.AnnouncementSending
+	created.CreatedAt = time.Now()
+	r.announcements = append(r.announcements, &created)
+	saved := created
+	return &saved, nil
+}
+
+func (r *mockAnnouncementsRepository) Finish(_ context.Context, ID int64, status string, delivered *int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, announcement := range r.announcements {
+		if announcement.ID == ID {
+			sentAt := time.Now()
+			announcement.Status, announcement.Delivered, announcement.SentAt = status, delivered, &sentAt
+		}
+	}
+	return nil
+}
+
+func (r *mockAnnouncementsRepository) List(_ context.Context, limit int) ([]*repos.Announcement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	announcements := []*repos.Announcement{}
+	for i := len(r.announcements) - 1; i >= 0 && len(announcements) < limit; i-- {
+		announcement := *r.announcements[i]
+		announcements = append(announcements, &announcement)
+	}
+	return announcements, nil
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"appdoki-be/app/filter"
+	"appdoki-be/app/notify"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestApplication_CreateAnnouncement(t *testing.T) {
+	newAdminApplication := func(users ...string) *Application {
+		a := newTestApplication()
+		mock := a.usersRepository.(*mockUsersRepository)
+		mock.findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		mock.getAllImpl = func(_ context.Context, _ filter.Filter) ([]*repos.User, error) {
+			found := []*repos.User{}
+			for _, ID := range users {
+				found = append(found, generateRandomUserMockWithID(ID))
+			}
+			return found, nil
+		}
+		return a
+	}
+	announce := func(t *testing.T, a *Application, body string) (*http.Response, *repos.Announcement) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, httptest.NewRequest("POST", "/admin/announcements", strings.NewReader(body)))
+		resp := w.Result()
+
+		var announcement repos.Announcement
+		if resp.StatusCode == http.StatusAccepted {
+			if err := json.NewDecoder(resp.Body).Decode(&announcement); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return resp, &announcement
+	}
+
+	t.Run("expect the announcement to be multicast to the devices of the users, and listed once sent", func(t *testing.T) {
+		a := newAdminApplication()
+		for _, device := range []*repos.DeviceToken{{Token: "a", UserID: "2"}, {Token: "b", UserID: "3"}, {Token: "c", UserID: "4"}} {
+			a.devicesRepository.Upsert(context.Background(), device)
+		}
+
+		resp, announcement := announce(t, a, `{"title":"All hands at 4pm","deep_link":"beers","audience":"users","user_ids":["2","3"]}`)
+
+		assertStatusCode(t, resp, http.StatusAccepted)
+		if announcement.Targeted != 2 || announcement.Channel != announcementMulticast || announcement.Status != repos.AnnouncementSending {
+			t.Fatalf("expected 2 devices to be targeted, got %+v", announcement)
+		}
+		sent := a.pushNotifier.(*notify.Fake).Sent()
+		if len(sent) != 1 || len(sent[0].Tokens) != 2 || sent[0].Notification.Title != "All hands at 4pm" {
+			t.Fatalf("expected the announcement to be multicast, got %+v", sent)
+		}
+
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, httptest.NewRequest("GET", "/admin/announcements", nil))
+		assertStatusCode(t, w.Result(), http.StatusOK)
+		var listed []*repos.Announcement
+		if err := json.NewDecoder(w.Result().Body).Decode(&listed); err != nil {
+			t.Fatal(err)
+		}
+		if len(listed) != 1 || listed[0].Status != repos.AnnouncementSent || listed[0].Delivered == nil || listed[0].SentAt == nil {
+			t.Fatalf("expected the announcement to be listed sent, got %+v", listed)
+		}
+		if entries := a.auditRepository.(*mockAuditRepository).Entries(); len(entries) != 1 || entries[0].Action != repos.AuditAnnouncement {
+			t.Fatalf("expected the announcement to be audited, got %+v", entries)
+		}
+	})
+
+	t.Run("expect an announcement to all the users to need a confirmation", func(t *testing.T) {
+		a := newAdminApplication("2")
+
+		resp, _ := announce(t, a, `{"title":"All hands at 4pm","audience":"all"}`)
+
+		assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+		if sent := a.pushNotifier.(*notify.Fake).Sent(); len(sent) != 0 {
+			t.Fatalf("expected nothing to be sent, got %+v", sent)
+		}
+	})
+
+	t.Run("expect the announcements to all the users with many devices to go through the topic", func(t *testing.T) {
+		a := newAdminApplication("2", "3")
+		for i := 0; i <= announcementTopicThreshold; i++ {
+			a.devicesRepository.Upsert(context.Background(), &repos.DeviceToken{Token: fmt.Sprintf("token-%d", i), UserID: "2"})
+		}
+
+		resp, announcement := announce(t, a, `{"title":"All hands at 4pm","audience":"all","confirm":true}`)
+
+		assertStatusCode(t, resp, http.StatusAccepted)
+		if announcement.Channel != announcementTopic || announcement.Targeted != announcementTopicThreshold+1 {
+			t.Fatalf("expected the announcement to go through the topic, got %+v", announcement)
+		}
+		if sent := a.pushNotifier.(*notify.Fake).Sent(); len(sent) != 1 || sent[0].Topic != allUsersTopic {
+			t.Fatalf("expected the announcement to be sent to the all-users topic, got %+v", sent)
+		}
+		listed, _ := a.announcementsRepository.List(context.Background(), 1)
+		if listed[0].Status != repos.AnnouncementSent || listed[0].Delivered != nil {
+			t.Fatalf("expected the delivery to the topic to be unknown, got %+v", listed[0])
+		}
+	})
+
+	t.Run("expect the announcements to a role to reach the users of the role", func(t *testing.T) {
+		a := newAdminApplication()
+		var role filter.Filter
+		a.usersRepository.(*mockUsersRepository).getAllImpl = func(_ context.Context, f filter.Filter) ([]*repos.User, error) {
+			role = f
+			return []*repos.User{generateRandomUserMockWithID("2")}, nil
+		}
+		a.devicesRepository.Upsert(context.Background(), &repos.DeviceToken{Token: "a", UserID: "2"})
+
+		resp, announcement := announce(t, a, `{"title":"Admins meeting","audience":"role","role":"admin"}`)
+
+		assertStatusCode(t, resp, http.StatusAccepted)
+		if len(role) != 1 || role[0].Column != "role" || role[0].Value != repos.RoleAdmin {
+			t.Fatalf("expected the users to be filtered by role, got %+v", role)
+		}
+		if announcement.Targeted != 1 {
+			t.Fatalf("expected the device of the admin to be targeted, got %+v", announcement)
+		}
+	})
+
+	t.Run("expect an invalid audience to return 422", func(t *testing.T) {
+		for _, body := range []string{
+			`{"title":"Hi","audience":"team"}`,
+			`{"title":"Hi","audience":"role","role":"owner"}`,
+			`{"title":"Hi","audience":"users"}`,
+			`{"title":"Hi","audience":"all","confirm":true,"user_ids":["2"]}`,
+			`{"audience":"users","user_ids":["2"]}`,
+		} {
+			resp, _ := announce(t, newAdminApplication(), body)
+			assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+		}
+	})
+}
@@ -1,55 +1,259 @@
 package app
 
 import (
+	"appdoki-be/app/i18n"
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/ratelimit"
+	"appdoki-be/app/reporting"
 	"appdoki-be/app/repositories"
 	"appdoki-be/config"
 	firebase "firebase.google.com/go/v4"
+	"fmt"
 	"github.com/gorilla/mux"
 	"github.com/jmoiron/sqlx"
 	log "github.com/sirupsen/logrus"
+	"net"
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 )
 
 type Application struct {
-	conf            *config.Config
-	firebaseApp     *firebase.App
-	usersRepository repositories.UsersRepositoryInterface
-	beersRepository repositories.BeersRepositoryInterface
-	notifier        notifier
+	conf                    *config.Config
+	db                      *sqlx.DB
+	replica                 *sqlx.DB
+	firebaseApp             *firebase.App
+	metrics                 metrics.Metrics
+	metricsHandler          http.Handler
+	usersRepository         repositories.UsersRepositoryInterface
+	beersRepository         repositories.BeersRepositoryInterface
+	idempotencyRepository   repositories.IdempotencyRepositoryInterface
+	auditRepository         repositories.AuditRepositoryInterface
+	devicesRepository       repositories.DevicesRepositoryInterface
+	preferencesRepository   repositories.PreferencesRepositoryInterface
+	notificationsRepository repositories.NotificationsRepositoryInterface
+	jobRunsRepository       repositories.JobRunsRepositoryInterface
+	deadLettersRepository   repositories.DeadLettersRepositoryInterface
+	deferredRepository      repositories.DeferredNotificationsRepositoryInterface
+	announcementsRepository repositories.AnnouncementsRepositoryInterface
+	triggersRepository      repositories.TriggersRepositoryInterface
+	webhooksRepository      repositories.WebhooksRepositoryInterface
+	receiptsRepository      repositories.WebhookReceiptsRepositoryInterface
+	dbStatsRepository       repositories.DBStatsRepositoryInterface
+	notifier                notify.Notifier
+	notificationQueue       *notify.Queue
+	quietHours              *notify.QuietHoursNotifier
+	notificationStreams     *notify.Streams
+	events                  *eventDispatcher
+	mailer                  notify.Notifier
+	// pushNotifier sends the test notifications to FCM and Web Push right away, past the queue, retries
+	// and history
+	pushNotifier notify.Notifier
+	templates    *notify.Templates
+	// replaySenders are the senders the dead letters of each channel are replayed through
+	replaySenders  map[string]notify.Sender
+	errorReporter  reporting.ErrorReporter
+	rateLimiter    ratelimit.Store
+	workers        *workerGroup
+	maintenance    *maintenanceMode
+	logger         *log.Logger
+	httpClients    *logging.HTTPClients
+	logLevel       *logLevel
+	features       *featureFlags
+	deactivations  *deactivations
+	trustedProxies []*net.IPNet
+	adminNetworks  []*net.IPNet
+	webhooks       map[string]*webhookSource
+	// outboundWebhooks posts the events to the webhook endpoints registered by the admins
+	outboundWebhooks *notify.Webhooks
+	deprecationLog   *deprecationLog
+	slowLog          *slowLog
+	routeRegistry    *routeRegistry
+	shuttingDown     int32
 }
 
-func NewApplication(conf *config.Config, db *sqlx.DB, firebaseApp *firebase.App) *Application {
-	notifierSrv, err := newNotifier(firebaseApp, conf.AppConfig.TestMode)
+// NewApplication returns the application of conf, logging with logger, built from conf.Logging, its
+// outbound calls made with the httpClients, built from conf.OutboundTLS. The users and the feed are
+// read from the replica of db, when not nil.
+func NewApplication(conf *config.Config, logger *log.Logger, db *sqlx.DB, replica *sqlx.DB, firebaseApp *firebase.App, httpClients *logging.HTTPClients) *Application {
+	errorReporter, err := newErrorReporter(conf.Errors, httpClients)
 	if err != nil {
-		log.Fatal("could not instantiate a notifier")
+		logger.Fatalf("invalid SENTRY_DSN: %+v", err)
 	}
 
-	return &Application{
-		conf:            conf,
-		firebaseApp:     firebaseApp,
-		usersRepository: repositories.NewUsersRepository(db),
-		beersRepository: repositories.NewBeersRepository(db),
-		notifier:        notifierSrv,
+	trustedProxies, err := parseNetworks(conf.Server.TrustedProxies)
+	if err != nil {
+		logger.Fatalf("invalid TRUSTED_PROXIES: %+v", err)
+	}
+	adminNetworks, err := parseNetworks(conf.Admin.AllowedNetworks)
+	if err != nil {
+		logger.Fatalf("invalid ADMIN_ALLOWED_NETWORKS: %+v", err)
+	}
+	if len(adminNetworks) == 0 {
+		logger.Warn("the admin routes answer any network, ADMIN_ALLOWED_NETWORKS is not set")
 	}
+	if err := (digestSchedule{Frequency: conf.Digest.Frequency, Hour: conf.Digest.Hour}).validate(); err != nil {
+		logger.Fatalf("invalid DIGEST_FREQUENCY or DIGEST_HOUR: %v", err)
+	}
+	if !i18n.Supported(conf.I18n.DefaultLocale) {
+		logger.Fatalf("invalid DEFAULT_LOCALE %q, supported locales are %v", conf.I18n.DefaultLocale, i18n.Locales())
+	}
+
+	promMetrics := metrics.NewPrometheus("appdoki")
+	slow := newSlowLog(conf.SlowLog.Size)
+	observeQuery := slowQueryObserver(conf.SlowLog.QueryThreshold, slow)
+	unitOfWork := repositories.NewUnitOfWork(db)
+	unitOfWork.Metrics = promMetrics
+
+	a := &Application{
+		conf:                    conf,
+		db:                      db,
+		replica:                 replica,
+		firebaseApp:             firebaseApp,
+		metrics:                 promMetrics,
+		metricsHandler:          promMetrics.Handler(),
+		usersRepository:         repositories.NewTracedUsersRepository(repositories.NewUsersRepository(db).WithUnitOfWork(unitOfWork).WithReplica(replica), observeQuery),
+		beersRepository:         repositories.NewTracedBeersRepository(repositories.NewBeersRepository(db).WithReplica(replica), observeQuery),
+		idempotencyRepository:   repositories.NewTracedIdempotencyRepository(repositories.NewIdempotencyRepository(db), observeQuery),
+		auditRepository:         repositories.NewTracedAuditRepository(repositories.NewAuditRepository(db).WithUnitOfWork(unitOfWork), observeQuery),
+		devicesRepository:       repositories.NewTracedDevicesRepository(repositories.NewDevicesRepository(db), observeQuery),
+		preferencesRepository:   repositories.NewTracedPreferencesRepository(repositories.NewPreferencesRepository(db), observeQuery),
+		notificationsRepository: repositories.NewTracedNotificationsRepository(repositories.NewNotificationsRepository(db), observeQuery),
+		jobRunsRepository:       repositories.NewTracedJobRunsRepository(repositories.NewJobRunsRepository(db), observeQuery),
+		deadLettersRepository:   repositories.NewTracedDeadLettersRepository(repositories.NewDeadLettersRepository(db), observeQuery),
+		deferredRepository:      repositories.NewTracedDeferredNotificationsRepository(repositories.NewDeferredNotificationsRepository(db), observeQuery),
+		announcementsRepository: repositories.NewTracedAnnouncementsRepository(repositories.NewAnnouncementsRepository(db), observeQuery),
+		triggersRepository:      repositories.NewTracedTriggersRepository(repositories.NewTriggersRepository(db), observeQuery),
+		webhooksRepository:      repositories.NewTracedWebhooksRepository(repositories.NewWebhooksRepository(db), observeQuery),
+		receiptsRepository:      repositories.NewTracedWebhookReceiptsRepository(repositories.NewWebhookReceiptsRepository(db), observeQuery),
+		dbStatsRepository:       repositories.NewTracedDBStatsRepository(repositories.NewDBStatsRepository(db), observeQuery),
+		notificationStreams:     notify.NewStreams(),
+		events:                  newEventDispatcher(),
+		errorReporter:           errorReporter,
+		rateLimiter:             ratelimit.NewMemory(),
+		workers:                 newWorkerGroup(errorReporter),
+		maintenance:             newMaintenanceMode(conf.Maintenance.Enabled),
+		logger:                  logger,
+		httpClients:             httpClients,
+		logLevel:                newLogLevel(logger, conf.Logging.Level),
+		features:                newFeatureFlags(conf.Features.Flags, repositories.NewTracedFeatureFlagsRepository(repositories.NewFeatureFlagsRepository(db), observeQuery)),
+		deactivations:           newDeactivations(deactivationsTTL),
+		trustedProxies:          trustedProxies,
+		adminNetworks:           adminNetworks,
+		deprecationLog:          newDeprecationLog(deprecationLogInterval),
+		slowLog:                 slow,
+		routeRegistry:           newRouteRegistry(),
+	}
+	a.outboundWebhooks = a.newOutboundWebhooks()
+	templates, err := loadNotificationTemplates(conf.Notifications.TemplatesDir)
+	if err != nil {
+		logger.Fatalf("invalid notification templates: %v", err)
+	}
+	if a.notificationQueue, err = a.newNotifier(firebaseApp, templates); err != nil {
+		logger.Fatalf("could not instantiate a notifier: %v", err)
+	}
+	a.notifier = a.notificationQueue
+	a.templates = templates
+	a.registerWebhook("github", githubWebhookSignature, newGitHubProcessor(a.notifier))
+	a.subscribeOnboarding()
+
+	return a
 }
 
+const apiV1Prefix = "/api/v1"
+
 func (a *Application) Routes() http.Handler {
+	router := a.router()
+
+	return middlewareChain(a.middlewares(router), router)
+}
+
+// middlewares returns the middlewares of the routes, the outermost first. The recovery comes right
+// after the request ID, for the panics of every other middleware to be recovered and logged with it.
+func (a *Application) middlewares(router *mux.Router) []middleware {
+	methods := newRouteMethods(router)
+	return []middleware{
+		requestIDMiddleware,
+		recoveryMiddleware(a.logger, a.metrics, a.errorReporter, router),
+		loggerMiddleware(a.logger),
+		localeMiddleware(a.defaultLocale()),
+		responseTimeMiddleware,
+		securityHeadersMiddleware(a.securityHeaders()),
+		bodyLimitMiddleware(a.conf.Server.MaxBodyBytes),
+		trimSuffixMiddleware,
+		corsMiddleware(a.conf.CORS, methods, a.logger),
+		implicitMethodsMiddleware(router, methods),
+		tracingMiddleware(router),
+		metricsMiddleware(a.metrics, router),
+		loggingMiddleware(router, a.conf.SlowLog.RequestThreshold, a.slowLog),
+		clientDeadlineMiddleware(a.conf.Server.MaxClientRequestTimeout),
+		a.maintenanceMiddleware,
+	}
+}
+
+// defaultLocale returns the configured default locale, English when none is configured
+func (a *Application) defaultLocale() string {
+	if a.conf.I18n.DefaultLocale == "" {
+		return i18n.Fallback
+	}
+	return a.conf.I18n.DefaultLocale
+}
+
+// securityHeaders returns the configured security headers, HSTS being always on in TLS mode
+func (a *Application) securityHeaders() config.SecurityHeadersConfig {
+	headers := a.conf.AppConfig.SecurityHeaders
+	if a.conf.Server.TLSEnabled() {
+		headers.HTTPS = true
+	}
+	return headers
+}
+
+// router mounts every route of the application. Routes are declared to a.mount along with the
+// access they require, building the router panics if one was registered some other way.
+func (a *Application) router() *mux.Router {
 	router := mux.NewRouter()
-	router.HandleFunc("/", homeHandler)
+	a.routeRegistry.reset(router)
+	router.NotFoundHandler = http.HandlerFunc(notFoundHandler)
+	router.MethodNotAllowedHandler = methodNotAllowedHandler(newRouteMethods(router))
+	a.mount(router,
+		routeDef{methods: []string{http.MethodGet}, path: "/", access: publicAccess, handler: http.HandlerFunc(homeHandler)},
+	)
+
+	a.MetricsRouter(router)
+	a.HealthRouter(router)
+	a.DocsRouter(router)
+	a.DebugRouter(router)
+	a.AdminRouter(router)
+	a.WebhooksRouter(router)
+	a.BootstrapRouter(router)
+	a.WebPushRouter(router)
+
+	v1 := router.PathPrefix(apiV1Prefix).Subrouter()
+	v1.Use(a.timeoutMiddleware)
+	a.V1Router(v1)
+
+	// unversioned aliases of the v1 routes, kept for one release
+	legacy := router.NewRoute().Subrouter()
+	legacy.Use(a.deprecationMiddleware, a.timeoutMiddleware)
+	a.V1Router(legacy)
 
+	if undeclared := a.routeRegistry.Undeclared(); len(undeclared) > 0 {
+		panic(fmt.Sprintf("routes registered without a declared access: %s", strings.Join(undeclared, "; ")))
+	}
+	return router
+}
+
+// V1Router mounts the routes of the first API version. Routes whose shapes don't change
+// in a later version can be shared by calling their routers from its version router too.
+func (a *Application) V1Router(router *mux.Router) {
 	a.AuthRouter(router)
 	a.UsersRouter(router)
 	a.BeersRouter(router)
-
-	fs := http.FileServer(http.Dir("./swaggerui/"))
-	router.
-		PathPrefix("/docs").
-		Handler(http.StripPrefix("/docs", fs))
-
-	return middlewareChain([]middleware{
-		trimSuffixMiddleware,
-		loggingMiddleware,
-	}, router)
+	a.EventsRouter(router)
 }
 
 type TopicInfo struct {
@@ -62,10 +266,10 @@ type HomeResponse struct {
 	MessagingTopics []TopicInfo
 }
 
-func homeHandler(w http.ResponseWriter, _ *http.Request) {
+func homeHandler(w http.ResponseWriter, r *http.Request) {
 	res := HomeResponse{
 		Version:      "1.0.0",
-		DocsEndpoint: "/docs/",
+		DocsEndpoint: "/api/docs/",
 		MessagingTopics: []TopicInfo{
 			{
 				Topic:       "beers",
@@ -75,8 +279,101 @@ func homeHandler(w http.ResponseWriter, _ *http.Request) {
 				Topic:       "users",
 				Description: "Global notification for joined users",
 			},
+			{
+				Topic:       "integrations",
+				Description: "Global notifications of the events posted by webhooks",
+			},
 		},
 	}
 
-	respondJSON(w, res, http.StatusOK)
+	respondJSON(w, r, res, http.StatusOK)
+}
+
+func notFoundHandler(w http.ResponseWriter, _ *http.Request) {
+	respondError(w, http.StatusNotFound, ErrCodeNotFound, "resource not found", nil)
+}
+
+// methodNotAllowedHandler responds with the methods registered for the path in the Allow header
+func methodNotAllowedHandler(methods *routeMethods) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", strings.Join(methods.allowed(r), ", "))
+		respondError(w, http.StatusMethodNotAllowed, ErrCodeMethodNotAllowed, "method not allowed", nil)
+	})
+}
+
+// routeMethods tells the methods of the routes matching a path. The paths of the routes are compiled
+// once, on first use when the router has every route mounted, rather than on every request.
+type routeMethods struct {
+	router *mux.Router
+	once   sync.Once
+	paths  []pathMethods
+}
+
+// pathMethods is a route path of routeMethods along with the methods registered for it
+type pathMethods struct {
+	path    *regexp.Regexp
+	methods []string
+}
+
+func newRouteMethods(router *mux.Router) *routeMethods {
+	return &routeMethods{router: router}
+}
+
+// index walks the router compiling the path of its routes, the methods of the routes sharing a
+// path being merged
+func (m *routeMethods) index() {
+	byPath := map[string]int{}
+	_ = m.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		pathRegexp, err := route.GetPathRegexp()
+		if err != nil {
+			return nil
+		}
+
+		if i, ok := byPath[pathRegexp]; ok {
+			m.paths[i].methods = append(m.paths[i].methods, methods...)
+			return nil
+		}
+		path, err := regexp.Compile(pathRegexp)
+		if err != nil {
+			return nil
+		}
+		byPath[pathRegexp] = len(m.paths)
+		m.paths = append(m.paths, pathMethods{path: path, methods: append([]string(nil), methods...)})
+		return nil
+	})
+}
+
+// allowed returns the methods of the routes matching the request path, along with the HEAD and
+// OPTIONS methods every route supports. It's nil when no route matches.
+func (m *routeMethods) allowed(r *http.Request) []string {
+	m.once.Do(m.index)
+
+	seen := map[string]bool{}
+	for _, p := range m.paths {
+		if p.path.MatchString(r.URL.Path) {
+			for _, method := range p.methods {
+				seen[method] = true
+			}
+		}
+	}
+
+	if len(seen) == 0 {
+		return nil
+	}
+	// answered by implicitMethodsMiddleware
+	if seen[http.MethodGet] {
+		seen[http.MethodHead] = true
+	}
+	seen[http.MethodOptions] = true
+
+	methods := make([]string, 0, len(seen))
+	for method := range seen {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
 }
@@ -0,0 +1,240 @@
+package app
+
+import (
+	"appdoki-be/app/filter"
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/ratelimit"
+	"appdoki-be/app/reporting"
+	repos "appdoki-be/app/repositories"
+	"appdoki-be/config"
+	"context"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestApplication returns an application in test mode backed by the default mocks
+func newTestApplication() *Application {
+	return &Application{
+		conf:                    &config.Config{AppConfig: config.AppConfig{TestMode: true}},
+		metrics:                 metrics.NewFake(),
+		usersRepository:         getDefaultMockUsersRepository(),
+		beersRepository:         getDefaultMockBeersRepository(),
+		idempotencyRepository:   newMockIdempotencyRepository(),
+		auditRepository:         &mockAuditRepository{},
+		devicesRepository:       newMockDevicesRepository(),
+		preferencesRepository:   newMockPreferencesRepository(),
+		notificationsRepository: newMockNotificationsRepository(),
+		jobRunsRepository:       newMockJobRunsRepository(),
+		deadLettersRepository:   newMockDeadLettersRepository(),
+		deferredRepository:      newMockDeferredNotificationsRepository(),
+		announcementsRepository: newMockAnnouncementsRepository(),
+		triggersRepository:      newMockTriggersRepository(),
+		webhooksRepository:      newMockWebhooksRepository(),
+		receiptsRepository:      newMockWebhookReceiptsRepository(),
+		dbStatsRepository:       &mockDBStatsRepository{},
+		notifier:                notify.NewFake(),
+		notificationStreams:     notify.NewStreams(),
+		events:                  newEventDispatcher(),
+		mailer:                  notify.NewFake(),
+		pushNotifier:            notify.NewFake(),
+		errorReporter:           reporting.NewFake(),
+		rateLimiter:             ratelimit.NewMemory(),
+		workers:                 newWorkerGroup(reporting.Noop{}),
+		maintenance:             newMaintenanceMode(false),
+		logger:                  log.StandardLogger(),
+		logLevel:                newLogLevel(log.StandardLogger(), ""),
+		features:                newFeatureFlags(config.DefaultFeatureFlags, newMockFeatureFlagsRepository()),
+		deprecationLog:          newDeprecationLog(deprecationLogInterval),
+		slowLog:                 newSlowLog(10),
+		routeRegistry:           newRouteRegistry(),
+	}
+}
+
+func TestApplication_Routes(t *testing.T) {
+	routes := newTestApplication().Routes()
+
+	t.Run("expect GET /api/v1/users to return 200", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+		if resp.Header.Get("Deprecation") != "" {
+			t.Fatal("expected versioned route not to be deprecated")
+		}
+	})
+
+	t.Run("expect legacy GET /users to return 200 and be deprecated", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/users", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+		if resp.Header.Get("Deprecation") != "true" {
+			t.Fatalf("expected Deprecation 'true', got '%s'", resp.Header.Get("Deprecation"))
+		}
+		if link := resp.Header.Get("Link"); link != `<http://example.com/api/v1/users>; rel="successor-version"` {
+			t.Fatalf("expected Link to the successor route, got '%s'", link)
+		}
+	})
+
+	t.Run("expect GET /api/v1/beers and legacy GET /beers to return 200", func(t *testing.T) {
+		for _, path := range []string{"/api/v1/beers", "/beers"} {
+			r := httptest.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			routes.ServeHTTP(w, r)
+
+			assertStatusCode(t, w.Result(), http.StatusOK)
+		}
+	})
+
+	t.Run("expect GET /healthz to stay unversioned", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/healthz", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if resp.Header.Get("Deprecation") != "" {
+			t.Fatal("expected health route not to be deprecated")
+		}
+	})
+}
+
+func TestApplication_Routes_Errors(t *testing.T) {
+	routes := newTestApplication().Routes()
+
+	t.Run("expect an unknown path to return 404 and a JSON error", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/nope", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusNotFound)
+		assertJSONContentType(t, resp)
+		assertErrorCode(t, resp, ErrCodeNotFound)
+	})
+
+	t.Run("expect errors to carry the request ID and the response time", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/nope", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		var envelope errorEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatal(err)
+		}
+		if requestID := resp.Header.Get(logging.RequestIDHeader); requestID == "" || envelope.Error.RequestID != requestID {
+			t.Fatalf("expected the error request ID to be '%s', got '%s'", requestID, envelope.Error.RequestID)
+		}
+		if resp.Header.Get(responseTimeHeader) == "" {
+			t.Fatalf("expected the %s header", responseTimeHeader)
+		}
+	})
+
+	t.Run("expect a wrong method to return 405 with the allowed methods", func(t *testing.T) {
+		r := httptest.NewRequest("DELETE", "/api/v1/users/1/beers/2", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusMethodNotAllowed)
+		assertJSONContentType(t, resp)
+		assertErrorCode(t, resp, ErrCodeMethodNotAllowed)
+		if allow := resp.Header.Get("Allow"); allow != "OPTIONS, POST" {
+			t.Fatalf("expected Allow 'OPTIONS, POST', got '%s'", allow)
+		}
+	})
+
+	t.Run("expect a wrong method on a legacy path to return 405", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/users", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusMethodNotAllowed)
+		if allow := resp.Header.Get("Allow"); allow != "GET, HEAD, OPTIONS" {
+			t.Fatalf("expected Allow 'GET, HEAD, OPTIONS', got '%s'", allow)
+		}
+	})
+}
+
+func TestApplication_Routes_EmptyCollections(t *testing.T) {
+	a := newTestApplication()
+	a.usersRepository.(*mockUsersRepository).getAllImpl = func(_ context.Context, _ filter.Filter) ([]*repos.User, error) {
+		return nil, nil
+	}
+	a.beersRepository.(*mockBeersRepository).getBeerTransfersImpl = func(_ context.Context, _ *repos.BeerFeedPaginationOptions) ([]repos.BeerTransferFeedItem, error) {
+		return nil, nil
+	}
+	routes := a.Routes()
+
+	for _, path := range []string{"/api/v1/users", "/api/v1/beers"} {
+		t.Run("expect GET "+path+" to return [] when empty", func(t *testing.T) {
+			r := httptest.NewRequest("GET", path, nil)
+			w := httptest.NewRecorder()
+			routes.ServeHTTP(w, r)
+
+			resp := w.Result()
+
+			assertStatusCode(t, resp, http.StatusOK)
+			if body := strings.TrimSpace(w.Body.String()); body != "[]" {
+				t.Fatalf("expected '[]', got '%s'", body)
+			}
+		})
+	}
+}
+
+func TestRouteMethods(t *testing.T) {
+	noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	router := mux.NewRouter()
+	router.Handle("/things", noop).Methods(http.MethodGet)
+	router.Handle("/things", noop).Methods(http.MethodPost)
+	router.Handle("/things/{id:[0-9]+}", noop).Methods(http.MethodDelete)
+	methods := newRouteMethods(router)
+
+	t.Run("expect the methods of every route of the path", func(t *testing.T) {
+		allowed := methods.allowed(httptest.NewRequest("OPTIONS", "/things", nil))
+		if got := strings.Join(allowed, ", "); got != "GET, HEAD, OPTIONS, POST" {
+			t.Fatalf("expected 'GET, HEAD, OPTIONS, POST', got '%s'", got)
+		}
+
+		allowed = methods.allowed(httptest.NewRequest("OPTIONS", "/things/1", nil))
+		if got := strings.Join(allowed, ", "); got != "DELETE, OPTIONS" {
+			t.Fatalf("expected 'DELETE, OPTIONS', got '%s'", got)
+		}
+	})
+
+	t.Run("expect no methods for an unknown path", func(t *testing.T) {
+		if allowed := methods.allowed(httptest.NewRequest("OPTIONS", "/things/abc", nil)); allowed != nil {
+			t.Fatalf("expected no methods, got %v", allowed)
+		}
+	})
+
+	t.Run("expect the routes to be indexed once", func(t *testing.T) {
+		router.Handle("/others", noop).Methods(http.MethodGet)
+
+		if allowed := methods.allowed(httptest.NewRequest("OPTIONS", "/others", nil)); allowed != nil {
+			t.Fatalf("expected the router not to be walked again, got %v", allowed)
+		}
+	})
+}
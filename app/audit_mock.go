@@ -0,0 +1,26 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"sync"
+)
+
+// mockAuditRepository keeps the recorded audit entries in memory
+type mockAuditRepository struct {
+	mu      sync.Mutex
+	entries []*repos.AuditEntry
+}
+
+func (r *mockAuditRepository) Record(_ context.Context, entries []*repos.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+func (r *mockAuditRepository) Entries() []*repos.AuditEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*repos.AuditEntry(nil), r.entries...)
+}
@@ -1,24 +1,30 @@
 package app
 
 import (
+	"appdoki-be/app/logging"
 	"appdoki-be/app/repositories"
+	"appdoki-be/app/tracing"
 	"appdoki-be/config"
 	"context"
 	"crypto/rand"
 	"encoding/base64"
-	"encoding/json"
-	"github.com/coreos/go-oidc"
+	"errors"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
 	"net/http"
 	"strings"
 	"time"
 )
 
+const maxAuthPayloadBytes = 4 << 10
+
 // AuthHandler holds handler dependencies
 type AuthHandler struct {
-	appConfig config.AppConfig
-	userRepo  repositories.UsersRepositoryInterface
-	notifier  notifier
+	appConfig       config.AppConfig
+	userRepo        repositories.UsersRepositoryInterface
+	preferencesRepo repositories.PreferencesRepositoryInterface
+	events          *eventDispatcher
+	httpClients     *logging.HTTPClients
 }
 
 type AuthCodePayload struct {
@@ -29,38 +35,86 @@ type TokenPayload struct {
 	Token string `json:"token"`
 }
 
+// TokenResponse carries the ID token of the user. LegacyToken is the capitalized field
+// of the first releases, served alongside token until legacyTokenSunset.
+type TokenResponse struct {
+	Token       string `json:"token"`
+	LegacyToken string `json:"Token"`
+}
+
+func newTokenResponse(token string) TokenResponse {
+	return TokenResponse{Token: token, LegacyToken: token}
+}
+
 // NewOAuthHandler returns an initialized users handler with the required dependencies
 func NewAuthHandler(
 	appConfig config.AppConfig,
 	userRepo repositories.UsersRepositoryInterface,
-	notifierSrv notifier) *AuthHandler {
+	preferencesRepo repositories.PreferencesRepositoryInterface,
+	events *eventDispatcher,
+	httpClients *logging.HTTPClients) *AuthHandler {
 	return &AuthHandler{
-		appConfig: appConfig,
-		userRepo:  userRepo,
-		notifier:  notifierSrv,
+		appConfig:       appConfig,
+		userRepo:        userRepo,
+		preferencesRepo: preferencesRepo,
+		events:          events,
+		httpClients:     httpClients,
+	}
+}
+
+// initPreferences sets the preferences of a user signing in for the first time, notified in the
+// locale the client prefers. The sign in goes on when they can't be saved, in English.
+func (h *AuthHandler) initPreferences(w http.ResponseWriter, r *http.Request, userID string) {
+	prefs := repositories.DefaultNotificationPreferences()
+	prefs.Locale = responseLocale(w)
+	if _, err := h.preferencesRepo.SaveNotifications(r.Context(), userID, prefs); err != nil {
+		logging.FromContext(r.Context()).Errorf("error setting the preferences of user %s: %v", userID, err)
 	}
 }
 
+// userCreated sets up the user signing in for the first time, publishing eventUserCreated for the
+// onboarding to go on
+func (h *AuthHandler) userCreated(w http.ResponseWriter, r *http.Request, user *repositories.User) {
+	h.initPreferences(w, r, user.ID)
+	h.events.Publish(r.Context(), eventUserCreated, userEvent{UserID: user.ID, User: user})
+}
+
 // GetURL responds with the URL for OAuth 2.0 provider's consent page
 func (h *AuthHandler) GetURL(w http.ResponseWriter, r *http.Request) {
 	b := make([]byte, 16)
 	rand.Read(b)
 	state := base64.URLEncoding.EncodeToString(b)
 
-	respondJSON(w, struct {
+	respondJSON(w, r, struct {
 		URL string
 	}{
-		URL: h.appConfig.GoogleOauth.AuthCodeURL(state, oauth2.AccessTypeOffline),
+		URL: h.appConfig.OAuthConfig().AuthCodeURL(state, append(h.appConfig.AuthCodeOptions(), oauth2.AccessTypeOffline)...),
 	}, http.StatusOK)
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	oauthState := generateStateOauthCookie(w)
-	u := h.appConfig.GoogleOauth.AuthCodeURL(oauthState)
+	oauthState := generateStateOauthCookie(w, h.appConfig.CookieSecure)
+	u := h.appConfig.OAuthConfig().AuthCodeURL(oauthState, h.appConfig.AuthCodeOptions()...)
 	http.Redirect(w, r, u, http.StatusTemporaryRedirect)
 }
 
-func generateStateOauthCookie(w http.ResponseWriter) string {
+// exchange trades an authorization code for a token with the OAuth 2.0 provider
+func (h *AuthHandler) exchange(ctx context.Context, code string) (token *oauth2.Token, err error) {
+	ctx, span := tracing.Start(ctx, "oauth2.Exchange", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() { tracing.End(span, err) }()
+
+	return h.appConfig.OAuthConfig().Exchange(oauthContext(ctx, h.httpClients), code)
+}
+
+// oauthContext returns a context whose OAuth 2.0 calls propagate the request ID, through the
+// outbound transport
+func oauthContext(ctx context.Context, clients *logging.HTTPClients) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, clients.New(0))
+}
+
+// generateStateOauthCookie sets the state of the OAuth 2.0 flow in a cookie, only sent over
+// HTTPS when secure
+func generateStateOauthCookie(w http.ResponseWriter, secure bool) string {
 	var expiration = time.Now().Add(365 * 24 * time.Hour)
 
 	b := make([]byte, 16)
@@ -70,6 +124,7 @@ func generateStateOauthCookie(w http.ResponseWriter) string {
 		Name:    "oauthstate",
 		Value:   state,
 		Expires: expiration,
+		Secure:  secure,
 	}
 	http.SetCookie(w, &cookie)
 
@@ -78,15 +133,20 @@ func generateStateOauthCookie(w http.ResponseWriter) string {
 
 func (h *AuthHandler) Token(w http.ResponseWriter, r *http.Request) {
 	var codePayload AuthCodePayload
-	err := json.NewDecoder(r.Body).Decode(&codePayload)
-	if err != nil {
-		respondInternalError(w)
+	if err := decodeJSON(r, &codePayload, maxAuthPayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+
+	// the code can only be exchanged once, so not before its ID token can be verified
+	if err := h.appConfig.OIDCProvider.Err(); err != nil {
+		respondAuthUnavailable(w)
 		return
 	}
 
-	token, err := h.appConfig.GoogleOauth.Exchange(context.Background(), codePayload.Code)
+	token, err := h.exchange(r.Context(), codePayload.Code)
 	if err != nil {
-		respondInternalError(w)
+		respondError(w, http.StatusBadRequest, ErrCodeInvalidCode, "invalid authorization code", nil)
 		return
 	}
 
@@ -96,13 +156,9 @@ func (h *AuthHandler) Token(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	verifier := h.appConfig.OIDCProvider.Verifier(&oidc.Config{
-		ClientID: h.appConfig.GoogleOauth.ClientID,
-	})
-
-	idToken, err := verifier.Verify(r.Context(), rawIDToken)
+	idToken, err := h.appConfig.VerifyIDToken(r.Context(), rawIDToken, "web")
 	if err != nil {
-		respondInternalError(w)
+		respondError(w, http.StatusUnauthorized, ErrCodeInvalidToken, "invalid or expired token", nil)
 		return
 	}
 
@@ -116,30 +172,33 @@ func (h *AuthHandler) Token(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, _, err = h.userRepo.FindOrCreateUser(r.Context(), &repositories.User{
+	user, created, err := h.userRepo.FindOrCreateUser(r.Context(), &repositories.User{
 		ID:      idToken.Subject,
 		Name:    idTokenClaims.Name,
 		Email:   idTokenClaims.Email,
 		Picture: idTokenClaims.Picture,
 	})
 	if err != nil {
-		respondInternalError(w)
+		respondRepositoryError(w, err)
 		return
 	}
+	if created {
+		h.userCreated(w, r, user)
+	}
 
-	respondJSON(w, struct {
-		Token string
-	}{
-		Token: rawIDToken,
-	}, http.StatusOK)
+	respondJSON(w, r, newTokenResponse(rawIDToken), http.StatusOK)
 }
 
 func (h *AuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
 	code := r.URL.Query().Get("code")
+	if err := h.appConfig.OIDCProvider.Err(); err != nil {
+		respondAuthUnavailable(w)
+		return
+	}
 
-	token, err := h.appConfig.GoogleOauth.Exchange(context.Background(), code)
+	token, err := h.exchange(r.Context(), code)
 	if err != nil {
-		respondInternalError(w)
+		respondError(w, http.StatusBadRequest, ErrCodeInvalidCode, "invalid authorization code", nil)
 		return
 	}
 
@@ -149,13 +208,9 @@ func (h *AuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	verifier := h.appConfig.OIDCProvider.Verifier(&oidc.Config{
-		ClientID: h.appConfig.GoogleOauth.ClientID,
-	})
-
-	idToken, err := verifier.Verify(r.Context(), rawIDToken)
+	idToken, err := h.appConfig.VerifyIDToken(r.Context(), rawIDToken, "web")
 	if err != nil {
-		respondInternalError(w)
+		respondError(w, http.StatusUnauthorized, ErrCodeInvalidToken, "invalid or expired token", nil)
 		return
 	}
 
@@ -169,34 +224,33 @@ func (h *AuthHandler) Callback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, _, err = h.userRepo.FindOrCreateUser(r.Context(), &repositories.User{
+	user, created, err := h.userRepo.FindOrCreateUser(r.Context(), &repositories.User{
 		ID:      idToken.Subject,
 		Name:    idTokenClaims.Name,
 		Email:   idTokenClaims.Email,
 		Picture: idTokenClaims.Picture,
 	})
 	if err != nil {
-		respondInternalError(w)
+		respondRepositoryError(w, err)
 		return
 	}
+	if created {
+		h.userCreated(w, r, user)
+	}
 
-	respondJSON(w, struct {
-		Token string
-	}{
-		Token: rawIDToken,
-	}, http.StatusOK)
+	respondJSON(w, r, newTokenResponse(rawIDToken), http.StatusOK)
 }
 
 func (h *AuthHandler) FindCreateUser(w http.ResponseWriter, r *http.Request) {
 	platform := parsePlatformHeader(r.Header.Get("platform"))
-	verifier := h.appConfig.OIDCProvider.Verifier(&oidc.Config{
-		ClientID: h.appConfig.GetPlatformClientID(platform),
-	})
-
 	rawIDToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
-	idToken, err := verifier.Verify(r.Context(), rawIDToken)
+	idToken, err := h.appConfig.VerifyIDToken(r.Context(), rawIDToken, platform)
+	if errors.Is(err, config.ErrOIDCUnavailable) {
+		respondAuthUnavailable(w)
+		return
+	}
 	if err != nil {
-		respondInternalError(w)
+		respondError(w, http.StatusUnauthorized, ErrCodeInvalidToken, "invalid or expired token", nil)
 		return
 	}
 
@@ -216,15 +270,14 @@ func (h *AuthHandler) FindCreateUser(w http.ResponseWriter, r *http.Request) {
 		Email:   idTokenClaims.Email,
 		Picture: idTokenClaims.Picture,
 	})
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
 
 	if created == true && user != nil {
-		go func() {
-			userJSON, _ := json.Marshal(user)
-			h.notifier.messageAll(usersTopic, map[string]string{
-				"user": string(userJSON),
-			})
-		}()
+		h.userCreated(w, r, user)
 	}
 
-	respondJSON(w, user, http.StatusOK)
+	respondJSON(w, r, newUserWithRole(user), http.StatusOK)
 }
@@ -1,37 +1,30 @@
 package app
 
 import (
+	"appdoki-be/config"
 	"github.com/gorilla/mux"
 	"net/http"
 )
 
 func (a *Application) AuthRouter(router *mux.Router) {
-	authHandler := NewAuthHandler(a.conf.AppConfig, a.usersRepository, a.notifier)
+	authHandler := NewAuthHandler(a.conf.AppConfig, a.usersRepository, a.preferencesRepository, a.events, a.httpClients)
+	csp := contentSecurityPolicyMiddleware(a.conf.AppConfig.SecurityHeaders.ContentSecurityPolicy)
 
-	// for local testing purposes
-	router.
-		Methods(http.MethodGet).
-		Path("/auth/login").
-		HandlerFunc(authHandler.Login)
-
-	// for local testing purposes
-	router.
-		Methods(http.MethodGet).
-		Path("/auth/google/callback").
-		HandlerFunc(authHandler.Callback)
-
-	//router.
-	//	Methods(http.MethodGet).
-	//	Path("/auth/token").
-	//	HandlerFunc(authHandler.Token)
-
-	router.
-		Methods(http.MethodGet).
-		Path("/auth/url").
-		HandlerFunc(a.JwtVerify(authHandler.GetURL))
-
-	router.
-		Methods(http.MethodGet).
-		Path("/auth/user").
-		HandlerFunc(a.JwtVerify(authHandler.FindCreateUser))
+	a.mount(router,
+		// for local testing purposes
+		routeDef{methods: []string{http.MethodGet}, path: "/auth/login", access: publicAccess,
+			handler: a.RateLimit(readRateLimit, a.CacheControl(noStoreCache, authHandler.Login))},
+		// for local testing purposes, the browser lands there after the consent page
+		routeDef{methods: []string{http.MethodGet}, path: config.OAuthCallbackPath, access: publicAccess,
+			handler: csp(a.Deprecated(a.RateLimit(writeRateLimit, a.CacheControl(noStoreCache, authHandler.Callback)), legacyTokenSunset, deprecationsDocURL))},
+		routeDef{methods: []string{http.MethodPost}, path: "/auth/token", access: publicAccess, feature: "auth_token",
+			handler: a.Deprecated(
+				a.RateLimit(writeRateLimit, a.CacheControl(noStoreCache, authHandler.Token)),
+				legacyTokenSunset, deprecationsDocURL,
+			)},
+		routeDef{methods: []string{http.MethodGet}, path: "/auth/url", access: authenticatedAccess,
+			handler: a.RateLimit(readRateLimit, a.CacheControl(noStoreCache, authHandler.GetURL))},
+		routeDef{methods: []string{http.MethodGet}, path: "/auth/user", access: authenticatedAccess,
+			handler: a.RateLimit(writeRateLimit, a.CacheControl(noStoreCache, authHandler.FindCreateUser))},
+	)
 }
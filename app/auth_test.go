@@ -0,0 +1,66 @@
+package app
+
+import (
+	"encoding/json"
+	"golang.org/x/oauth2"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestAuthHandler_ConsentURL(t *testing.T) {
+	a := newTestApplication()
+	a.conf.AppConfig.GoogleOauth = oauth2.Config{
+		ClientID:    "web-client",
+		RedirectURL: "https://appdokiapi.cloudoki.com/auth/callback",
+		Endpoint:    oauth2.Endpoint{AuthURL: "https://accounts.google.com/o/oauth2/v2/auth"},
+		Scopes:      []string{"openid", "profile", "email", "https://www.googleapis.com/auth/calendar.readonly"},
+	}
+	a.conf.AppConfig.OAuthAuthParams = map[string]string{"prompt": "select_account", "hd": "cloudoki.com"}
+	routes := a.Routes()
+
+	assertConsentURL := func(t *testing.T, consentURL string, accessType string) {
+		t.Helper()
+		u, err := url.Parse(consentURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		query := u.Query()
+		expected := map[string]string{
+			"scope":       "openid profile email https://www.googleapis.com/auth/calendar.readonly",
+			"prompt":      "select_account",
+			"hd":          "cloudoki.com",
+			"client_id":   "web-client",
+			"access_type": accessType,
+		}
+		for name, value := range expected {
+			if query.Get(name) != value {
+				t.Fatalf("expected %s '%s', got '%s' in %s", name, value, query.Get(name), consentURL)
+			}
+		}
+		if query.Get("state") == "" {
+			t.Fatalf("expected a state in %s", consentURL)
+		}
+	}
+
+	t.Run("expect the URL of the consent page to have the configured scopes and parameters", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/auth/url", nil))
+
+		var body struct{ URL string }
+		if err := json.NewDecoder(w.Result().Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		assertConsentURL(t, body.URL, "offline")
+	})
+
+	t.Run("expect the login to redirect to the consent page with them", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/auth/login", nil))
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusTemporaryRedirect)
+		assertConsentURL(t, resp.Header.Get("Location"), "")
+	})
+}
@@ -3,7 +3,6 @@ package app
 import (
 	"appdoki-be/app/repositories"
 	"net/http"
-	"strconv"
 	"time"
 )
 
@@ -21,43 +20,35 @@ func NewBeersHandler(beersRepo repositories.BeersRepositoryInterface) *BeersHand
 
 // Get gets all the beer transfers
 func (h *BeersHandler) Get(w http.ResponseWriter, r *http.Request) {
+	params := newQueryParams(r)
 	options := &repositories.BeerFeedPaginationOptions{
-		Limit:   20,
-		GivenAt: "",
+		Limit:   params.IntInRange("limit", 20, 1, 100),
+		GivenAt: params.Time("givenAt", time.Now()).Format(time.RFC3339Nano),
 	}
 
-	limitParam := r.URL.Query().Get("limit")
-	if len(limitParam) > 0 {
-		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
-		if err != nil {
-			respondJSON(w, &appError{
-				Errors: []string{"invalid limit param"},
-			}, http.StatusBadRequest)
-			return
-		}
-
-		options.Limit = limit
-	}
-
-	options.GivenAt = r.URL.Query().Get("givenAt")
-	if len(options.GivenAt) == 0 {
-		options.GivenAt = time.Now().Format(time.RFC3339)
-	}
-
-	switch r.URL.Query().Get("op") {
+	switch params.OneOf("op", "lt", "lt", "gt") {
 	case "gt":
 		options.SetGtOperator()
-	case "lt":
-		options.SetLtOperator()
 	default:
 		options.SetLtOperator()
 	}
 
+	if err := params.Err(); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+
+	// when the watermark can't be read the feed is served unconditionally
+	lastModified, _ := h.beersRepo.LastModified(r.Context())
+	if notModified(w, r, lastModified) {
+		return
+	}
+
 	feed, err := h.beersRepo.GetBeerTransfers(r.Context(), options)
 	if err != nil {
-		respondInternalError(w)
+		respondRepositoryError(w, err)
 		return
 	}
 
-	respondJSON(w, feed, http.StatusOK)
+	respondJSONStream(w, r, feed, http.StatusOK)
 }
@@ -10,6 +10,8 @@ import (
 type mockBeersRepository struct {
 	getBeerTransferImpl  func(ctx context.Context, id int) (*repos.BeerTransferFeedItem, error)
 	getBeerTransfersImpl func(ctx context.Context, options *repos.BeerFeedPaginationOptions) ([]repos.BeerTransferFeedItem, error)
+	lastModifiedImpl     func(ctx context.Context) (time.Time, error)
+	receivedBetweenImpl  func(ctx context.Context, from time.Time, to time.Time) ([]*repos.BeersReceived, error)
 }
 
 func (r *mockBeersRepository) GetBeerTransfer(ctx context.Context, id int) (*repos.BeerTransferFeedItem, error) {
@@ -20,6 +22,14 @@ func (r *mockBeersRepository) GetBeerTransfers(ctx context.Context, options *rep
 	return r.getBeerTransfersImpl(ctx, options)
 }
 
+func (r *mockBeersRepository) LastModified(ctx context.Context) (time.Time, error) {
+	return r.lastModifiedImpl(ctx)
+}
+
+func (r *mockBeersRepository) ReceivedBetween(ctx context.Context, from time.Time, to time.Time) ([]*repos.BeersReceived, error) {
+	return r.receivedBetweenImpl(ctx, from, to)
+}
+
 func getDefaultMockBeersRepository() *mockBeersRepository {
 	return &mockBeersRepository{
 		getBeerTransferImpl: func(ctx context.Context, id int) (*repos.BeerTransferFeedItem, error) {
@@ -32,6 +42,12 @@ func getDefaultMockBeersRepository() *mockBeersRepository {
 				*generateRandomBeerTransferMock(),
 			}, nil
 		},
+		lastModifiedImpl: func(ctx context.Context) (time.Time, error) {
+			return time.Time{}, nil
+		},
+		receivedBetweenImpl: func(ctx context.Context, from time.Time, to time.Time) ([]*repos.BeersReceived, error) {
+			return []*repos.BeersReceived{}, nil
+		},
 	}
 }
 
@@ -8,8 +8,8 @@ import (
 func (a *Application) BeersRouter(router *mux.Router) {
 	beersHandler := NewBeersHandler(a.beersRepository)
 
-	router.
-		Methods(http.MethodGet).
-		Path("/beers").
-		HandlerFunc(a.JwtVerify(beersHandler.Get))
+	a.mount(router,
+		routeDef{methods: []string{http.MethodGet}, path: "/beers", access: authenticatedAccess, streaming: true,
+			handler: a.RateLimit(readRateLimit, a.CacheControl(privateCache, beersHandler.Get))},
+	)
 }
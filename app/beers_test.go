@@ -31,6 +31,18 @@ func TestBeersHandler_Get(t *testing.T) {
 		assertStatusCode(t, resp, http.StatusBadRequest)
 	})
 
+	t.Run("expect GET /beers to return 400 when op and givenAt params are invalid", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/beers?givenAt=yesterday&op=eq", nil)
+		w := httptest.NewRecorder()
+		router := prepareRouter(http.MethodGet, "/beers", defaultHandler.Get)
+		router.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusBadRequest)
+		assertErrorCode(t, resp, ErrCodeValidationFailed)
+	})
+
 	t.Run("expect GET /beers to return 200 with filter params", func(t *testing.T) {
 		r := httptest.NewRequest("GET", "/beers?givenAt=2020-10-01&limit=10&op=gt", nil)
 		w := httptest.NewRecorder()
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"appdoki-be/app/buildinfo"
+	"appdoki-be/app/repositories"
+	"net/http"
+	"strings"
+)
+
+// BootstrapResponse is everything the web client needs to boot, the frontend depends on its shape
+type BootstrapResponse struct {
+	// the signed in user, null for anonymous callers
+	User *userWithRole `json:"user"`
+	// only set when authenticating with cookies, null while tokens are bearer only
+	CSRFToken *string `json:"csrf_token"`
+	// the unread notifications of the signed in user, null for anonymous callers
+	UnreadCount *int     `json:"unread_count"`
+	Features    []string `json:"features"`
+	APIVersion  string   `json:"api_version"`
+	Version     string   `json:"version"`
+}
+
+// GetBootstrap responds with the current user, if any, the feature flags enabled for them and the API version.
+// It never answers 401, a missing or invalid token booting the client anonymous.
+func (a *Application) GetBootstrap(w http.ResponseWriter, r *http.Request) {
+	res := BootstrapResponse{
+		Features:   []string{},
+		APIVersion: strings.TrimPrefix(apiV1Prefix, "/api/"),
+		Version:    buildinfo.Version,
+	}
+
+	userID, _ := r.Context().Value("userID").(string)
+	if userID != "" {
+		// the user may have just been created by signing in
+		user, err := a.usersRepository.FindByID(repositories.ReadFresh(r.Context()), userID)
+		if err != nil {
+			respondRepositoryError(w, err)
+			return
+		}
+		res.User = newUserWithRole(user)
+
+		unread, err := a.notificationsRepository.CountUnread(r.Context(), userID)
+		if err != nil {
+			respondRepositoryError(w, err)
+			return
+		}
+		res.UnreadCount = &unread
+	}
+
+	res.Features = append(res.Features, a.features.EnabledFor(userID)...)
+
+	respondJSON(w, r, res, http.StatusOK)
+}
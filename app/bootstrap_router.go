@@ -0,0 +1,13 @@
+package app
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func (a *Application) BootstrapRouter(router *mux.Router) {
+	a.mount(router,
+		routeDef{methods: []string{http.MethodGet}, path: "/bootstrap", access: optionalAccess,
+			handler: a.RateLimit(readRateLimit, a.CacheControl(noStoreCache, a.GetBootstrap))},
+	)
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"appdoki-be/api"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"gopkg.in/yaml.v2"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+)
+
+// bootstrapContract lists the fields of BootstrapResponse in the spec, which the frontend depends on
+func bootstrapContract(t *testing.T) []string {
+	var spec struct {
+		Components struct {
+			Schemas map[string]struct {
+				Required   []string               `yaml:"required"`
+				Properties map[string]interface{} `yaml:"properties"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(api.Spec, &spec); err != nil {
+		t.Fatalf("failed to parse the spec: %v", err)
+	}
+
+	schema := spec.Components.Schemas["BootstrapResponse"]
+	fields := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	required := append([]string{}, schema.Required...)
+	sort.Strings(required)
+	if len(fields) == 0 || len(required) != len(fields) {
+		t.Fatalf("expected every field of BootstrapResponse to be required, got %v of %v", required, fields)
+	}
+	return fields
+}
+
+func getBootstrap(t *testing.T, a *Application, token string) (*http.Response, map[string]json.RawMessage) {
+	r := httptest.NewRequest("GET", "/bootstrap", nil)
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	a.Routes().ServeHTTP(w, r)
+
+	resp := w.Result()
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	return resp, body
+}
+
+func TestApplication_GetBootstrap(t *testing.T) {
+	contract := bootstrapContract(t)
+
+	assertContract := func(t *testing.T, body map[string]json.RawMessage) {
+		fields := make([]string, 0, len(body))
+		for name := range body {
+			fields = append(fields, name)
+		}
+		sort.Strings(fields)
+		if len(fields) != len(contract) {
+			t.Fatalf("expected the fields %v, got %v", contract, fields)
+		}
+		for i := range fields {
+			if fields[i] != contract[i] {
+				t.Fatalf("expected the fields %v, got %v", contract, fields)
+			}
+		}
+	}
+
+	t.Run("expect anonymous callers to boot without a user", func(t *testing.T) {
+		a := newTestApplication()
+		resp, body := getBootstrap(t, a, "")
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+		assertContract(t, body)
+		if resp.Header.Get("Cache-Control") != "no-store" {
+			t.Fatalf("expected no-store, got '%s'", resp.Header.Get("Cache-Control"))
+		}
+		if string(body["user"]) != "null" || string(body["csrf_token"]) != "null" || string(body["unread_count"]) != "null" {
+			t.Fatalf("expected no user, CSRF token nor unread count, got %s, %s and %s", body["user"], body["csrf_token"], body["unread_count"])
+		}
+		if string(body["api_version"]) != `"v1"` {
+			t.Fatalf("expected v1, got %s", body["api_version"])
+		}
+	})
+
+	t.Run("expect signed in callers to get their user and the enabled features", func(t *testing.T) {
+		a := newTestApplication()
+		a.features.Set(context.Background(), &repos.FeatureFlag{Name: "users_me", Enabled: true, RolloutPercent: 100})
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			return generateRandomUserMockWithID(ID), nil
+		}
+		a.notificationsRepository.Create(context.Background(), &repos.Notification{UserID: "1", Title: "Cheers!"})
+		resp, body := getBootstrap(t, a, "token")
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertContract(t, body)
+		if string(body["unread_count"]) != "1" {
+			t.Fatalf("expected 1 unread notification, got %s", body["unread_count"])
+		}
+
+		var user repos.User
+		if err := json.Unmarshal(body["user"], &user); err != nil || user.ID != "1" {
+			t.Fatalf("expected user 1, got %s", body["user"])
+		}
+		var features []string
+		if err := json.Unmarshal(body["features"], &features); err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, name := range features {
+			found = found || name == "users_me"
+		}
+		if !found {
+			t.Fatalf("expected users_me to be enabled, got %v", features)
+		}
+	})
+}
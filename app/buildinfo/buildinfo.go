@@ -0,0 +1,10 @@
+// Package buildinfo holds the build details injected at build time:
+// go build -ldflags "-X appdoki-be/app/buildinfo.Version=1.2.3 -X appdoki-be/app/buildinfo.Commit=abc123"
+package buildinfo
+
+// Version and Commit identify the running build, BuildTime is when it was built
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
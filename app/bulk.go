@@ -0,0 +1,121 @@
+package app
+
+import (
+	"appdoki-be/app/i18n"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Statuses of the items of a bulk operation
+const (
+	bulkItemSucceeded = "succeeded"
+	bulkItemFailed    = "failed"
+)
+
+// BulkItemResult is the outcome of one item of a bulk operation,
+// its error having the shape of the error envelope's
+type BulkItemResult struct {
+	ID     string     `json:"id"`
+	Status string     `json:"status"`
+	Error  *errorBody `json:"error,omitempty"`
+}
+
+// BulkResponse reports the outcome of every item of a bulk operation, in the order they were
+// requested. It is sent with a 200 even when some items failed: clients check each status.
+type BulkResponse struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+
+	index map[string]int
+}
+
+func newBulkResponse(ids []string) *BulkResponse {
+	res := &BulkResponse{
+		Results: make([]BulkItemResult, len(ids)),
+		index:   make(map[string]int, len(ids)),
+	}
+	for i, id := range ids {
+		res.Results[i].ID = id
+		res.index[id] = i
+	}
+	return res
+}
+
+// succeed reports the item as done
+func (res *BulkResponse) succeed(id string) {
+	res.set(id, BulkItemResult{ID: id, Status: bulkItemSucceeded})
+}
+
+// fail reports the item as failed with the given error
+func (res *BulkResponse) fail(id string, code string, message string) {
+	res.set(id, BulkItemResult{ID: id, Status: bulkItemFailed, Error: &errorBody{Code: code, Message: message}})
+}
+
+func (res *BulkResponse) set(id string, result BulkItemResult) {
+	i, ok := res.index[id]
+	if !ok {
+		return
+	}
+	switch res.Results[i].Status {
+	case bulkItemSucceeded:
+		res.Succeeded--
+	case bulkItemFailed:
+		res.Failed--
+	}
+	if result.Status == bulkItemSucceeded {
+		res.Succeeded++
+	} else {
+		res.Failed++
+	}
+	res.Results[i] = result
+}
+
+// translate translates the errors of the failed items to the locale
+func (res *BulkResponse) translate(locale string) {
+	for _, result := range res.Results {
+		if result.Error != nil {
+			result.Error.Message = i18n.Translate(locale, result.Error.Code, result.Error.Message, nil)
+		}
+	}
+}
+
+// bulkIDs validates the IDs of the items of a bulk operation, dropping the repeated ones
+func bulkIDs(ids []string, max int) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, &requestError{
+			status:  http.StatusUnprocessableEntity,
+			code:    ErrCodeValidationFailed,
+			message: "field \"{field}\" must not be empty",
+			params:  map[string]string{"field": "ids"},
+		}
+	}
+
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if strings.TrimSpace(id) == "" {
+			return nil, &requestError{
+				status:  http.StatusUnprocessableEntity,
+				code:    ErrCodeValidationFailed,
+				message: "field \"{field}\" must not contain blank IDs",
+				params:  map[string]string{"field": "ids"},
+			}
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	if len(unique) > max {
+		return nil, &requestError{
+			status:  http.StatusUnprocessableEntity,
+			code:    ErrCodeValidationFailed,
+			message: "field \"{field}\" must not hold more than {max} IDs",
+			params:  map[string]string{"field": "ids", "max": strconv.Itoa(max)},
+		}
+	}
+	return unique, nil
+}
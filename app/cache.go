@@ -0,0 +1,68 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// cache classes of the routes
+const (
+	// privateCache lets the client, but no shared cache, reuse read responses for a while
+	privateCache = "private"
+	// noStoreCache keeps sensitive responses, such as tokens, out of every cache
+	noStoreCache = "no-store"
+	// immutableCache lets anyone cache responses which never change for a given URL, such as avatars
+	immutableCache = "immutable"
+)
+
+// CacheControl sets the Cache-Control header of the route class before calling the handler.
+// Error responses always override it with no-store.
+func (a *Application) CacheControl(class string, next http.HandlerFunc) http.HandlerFunc {
+	cacheControl := a.cacheControl(class)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", cacheControl)
+
+		next(w, r)
+	}
+}
+
+// cacheControl returns the Cache-Control header value of a route class
+func (a *Application) cacheControl(class string) string {
+	conf := a.conf.Cache
+	switch class {
+	case privateCache:
+		return fmt.Sprintf("private, max-age=%d", maxAgeSeconds(conf.PrivateMaxAge))
+	case immutableCache:
+		return fmt.Sprintf("public, max-age=%d, immutable", maxAgeSeconds(conf.ImmutableMaxAge))
+	}
+	return noStoreCache
+}
+
+// notModified sets the Last-Modified header of a collection and responds 304 when the client's
+// copy, per its If-Modified-Since header, is still fresh. HTTP dates have a second precision, so
+// collections changed within the current second get no validator, they may change again in that second.
+func notModified(w http.ResponseWriter, r *http.Request, lastModified time.Time) bool {
+	if lastModified.IsZero() || !lastModified.Before(time.Now().Truncate(time.Second)) {
+		return false
+	}
+
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || lastModified.After(since) {
+		return false
+	}
+
+	respondNoContent(w, http.StatusNotModified)
+	return true
+}
+
+func maxAgeSeconds(d time.Duration) int {
+	if d < 0 {
+		return 0
+	}
+	return int(d / time.Second)
+}
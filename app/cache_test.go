@@ -0,0 +1,148 @@
+package app
+
+import (
+	"appdoki-be/app/filter"
+	repos "appdoki-be/app/repositories"
+	"appdoki-be/config"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestApplication_CacheControl(t *testing.T) {
+	a := newTestApplication()
+	a.conf.Cache = config.CacheConfig{
+		PrivateMaxAge:   time.Minute,
+		ImmutableMaxAge: 24 * time.Hour,
+	}
+	routes := a.Routes()
+
+	assertCacheControl := func(t *testing.T, resp *http.Response, expected string) {
+		if cacheControl := resp.Header.Get("Cache-Control"); cacheControl != expected {
+			t.Fatalf("expected Cache-Control '%s', got '%s'", expected, cacheControl)
+		}
+	}
+
+	t.Run("expect GET /api/v1/users/{id} to be privately cacheable", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/users/1", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusOK)
+		assertCacheControl(t, resp, "private, max-age=60")
+	})
+
+	t.Run("expect auth endpoints not to be stored", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/auth/url", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusOK)
+		assertCacheControl(t, resp, "no-store")
+	})
+
+	t.Run("expect writes not to be stored", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/api/v1/users/2/beers/1", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		assertCacheControl(t, w.Result(), "no-store")
+	})
+
+	t.Run("expect errors of cacheable routes not to be stored", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/beers?limit=nope", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusBadRequest)
+		assertCacheControl(t, resp, "no-store")
+	})
+
+	t.Run("expect immutable content to be cacheable by anyone", func(t *testing.T) {
+		ok := func(w http.ResponseWriter, r *http.Request) {
+			respondNoContent(w, http.StatusOK)
+		}
+		router := prepareRouter(http.MethodGet, "/avatars/{id}", a.CacheControl(immutableCache, ok))
+
+		r := httptest.NewRequest("GET", "/avatars/1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assertCacheControl(t, w.Result(), "public, max-age=86400, immutable")
+	})
+}
+
+func TestApplication_Routes_ConditionalGET(t *testing.T) {
+	a := newTestApplication()
+	watermark := time.Now().Add(-time.Hour)
+	var queries int
+	users := a.usersRepository.(*mockUsersRepository)
+	users.lastModifiedImpl = func(_ context.Context) (time.Time, error) {
+		return watermark, nil
+	}
+	users.getAllImpl = func(_ context.Context, _ filter.Filter) ([]*repos.User, error) {
+		queries++
+		return []*repos.User{generateRandomUserMock()}, nil
+	}
+	beers := a.beersRepository.(*mockBeersRepository)
+	beers.lastModifiedImpl = users.lastModifiedImpl
+	beers.getBeerTransfersImpl = func(_ context.Context, _ *repos.BeerFeedPaginationOptions) ([]repos.BeerTransferFeedItem, error) {
+		queries++
+		return []repos.BeerTransferFeedItem{}, nil
+	}
+	routes := a.Routes()
+
+	get := func(path string, ifModifiedSince string) *http.Response {
+		r := httptest.NewRequest("GET", path, nil)
+		if ifModifiedSince != "" {
+			r.Header.Set("If-Modified-Since", ifModifiedSince)
+		}
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	for _, path := range []string{"/api/v1/users", "/api/v1/beers"} {
+		t.Run("expect GET "+path+" to return 304 until a row changes", func(t *testing.T) {
+			watermark = time.Now().Add(-time.Hour)
+			queries = 0
+
+			resp := get(path, "")
+			assertStatusCode(t, resp, http.StatusOK)
+			lastModified := resp.Header.Get("Last-Modified")
+			if lastModified != watermark.UTC().Format(http.TimeFormat) {
+				t.Fatalf("expected Last-Modified '%s', got '%s'", watermark.UTC().Format(http.TimeFormat), lastModified)
+			}
+
+			resp = get(path, lastModified)
+			assertStatusCode(t, resp, http.StatusNotModified)
+			if queries != 1 {
+				t.Fatalf("expected the 304 to skip the collection query, got %d queries", queries)
+			}
+
+			watermark = watermark.Add(2 * time.Second)
+
+			resp = get(path, lastModified)
+			assertStatusCode(t, resp, http.StatusOK)
+			if queries != 2 {
+				t.Fatalf("expected the collection to be queried again, got %d queries", queries)
+			}
+		})
+	}
+
+	t.Run("expect no Last-Modified for a collection changed within the current second", func(t *testing.T) {
+		watermark = time.Now()
+
+		resp := get("/api/v1/users", time.Now().UTC().Format(http.TimeFormat))
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if resp.Header.Get("Last-Modified") != "" {
+			t.Fatal("expected no Last-Modified")
+		}
+	})
+}
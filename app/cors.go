@@ -0,0 +1,75 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/config"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// corsExposedHeaders are the response headers browsers let the clients read
+var corsExposedHeaders = []string{
+	logging.RequestIDHeader,
+	responseTimeHeader,
+	idempotentReplayedHeader,
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"Retry-After",
+	"Deprecation",
+	"Sunset",
+	"Link",
+}
+
+// corsMiddleware lets the browsers of the allowed origins call the API, with their credentials when
+// allowed, which browsers never send to any origin (*). The origins are compiled once, the subdomain
+// patterns being matched on every request. Preflight requests are answered with the methods allowed
+// on the path, before they reach the routes and their auth.
+func corsMiddleware(conf config.CORSConfig, methods *routeMethods, logger *log.Logger) middleware {
+	origins, err := config.NewOriginMatcher(conf.AllowedOrigins)
+	if err != nil {
+		logger.Fatalf("invalid CORS_ALLOWED_ORIGINS: %v", err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		if origins.Empty() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+
+			origin := r.Header.Get("Origin")
+			allowOrigin := origins.Allow(origin)
+			if allowOrigin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+			if conf.AllowCredentials && allowOrigin != "*" {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(corsExposedHeaders, ", "))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			allowed := methods.allowed(r)
+			if len(allowed) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(allowed, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(conf.AllowedHeaders, ", "))
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(maxAgeSeconds(conf.MaxAge)))
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			respondNoContent(w, http.StatusNoContent)
+		})
+	}
+}
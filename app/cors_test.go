@@ -0,0 +1,111 @@
+package app
+
+import (
+	"appdoki-be/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	a := newTestApplication()
+	a.conf.CORS = config.CORSConfig{
+		AllowedOrigins: []string{"https://appdoki.cloudoki.com"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		MaxAge:         time.Minute,
+	}
+	routes := a.Routes()
+
+	preflight := func(origin string) *http.Request {
+		r := httptest.NewRequest("OPTIONS", "/api/v1/users/1/beers/2", nil)
+		r.Header.Set("Origin", origin)
+		r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		return r
+	}
+
+	t.Run("expect preflights from allowed origins to return the CORS headers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, preflight("https://appdoki.cloudoki.com"))
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusNoContent)
+		expected := map[string]string{
+			"Access-Control-Allow-Origin":  "https://appdoki.cloudoki.com",
+			"Access-Control-Allow-Methods": "OPTIONS, POST",
+			"Access-Control-Allow-Headers": "Authorization, Content-Type",
+			"Access-Control-Max-Age":       "60",
+			"Allow":                        "OPTIONS, POST",
+		}
+		for key, value := range expected {
+			if resp.Header.Get(key) != value {
+				t.Fatalf("expected %s '%s', got '%s'", key, value, resp.Header.Get(key))
+			}
+		}
+	})
+
+	t.Run("expect preflights from other origins to get no CORS headers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, preflight("https://evil.com"))
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusNoContent)
+		if resp.Header.Get("Access-Control-Allow-Origin") != "" {
+			t.Fatal("expected no Access-Control-Allow-Origin")
+		}
+	})
+
+	t.Run("expect requests from allowed origins to expose the headers", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/users/1", nil)
+		r.Header.Set("Origin", "https://appdoki.cloudoki.com")
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if resp.Header.Get("Access-Control-Allow-Origin") != "https://appdoki.cloudoki.com" {
+			t.Fatal("expected Access-Control-Allow-Origin to be the request origin")
+		}
+		if resp.Header.Get("Access-Control-Expose-Headers") == "" {
+			t.Fatal("expected Access-Control-Expose-Headers")
+		}
+	})
+
+	t.Run("expect the credentials to be allowed to the listed origins only, never to any", func(t *testing.T) {
+		credentials := func(origins ...string) string {
+			a := newTestApplication()
+			a.conf.CORS = config.CORSConfig{AllowedOrigins: origins, AllowCredentials: true}
+			w := httptest.NewRecorder()
+			a.Routes().ServeHTTP(w, preflight("https://appdoki.cloudoki.com"))
+			return w.Result().Header.Get("Access-Control-Allow-Credentials")
+		}
+
+		if allowed := credentials("https://appdoki.cloudoki.com"); allowed != "true" {
+			t.Fatalf("expected the credentials to be allowed, got '%s'", allowed)
+		}
+		if allowed := credentials("*"); allowed != "" {
+			t.Fatalf("expected no credentials for any origin, got '%s'", allowed)
+		}
+	})
+
+	t.Run("expect the subdomains of a pattern to be allowed", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.CORS = config.CORSConfig{AllowedOrigins: []string{"https://*.appdoki.dev"}}
+		routes := a.Routes()
+
+		for origin, allowed := range map[string]string{
+			"https://pr-123.appdoki.dev": "https://pr-123.appdoki.dev",
+			"https://evil-appdoki.dev":   "",
+		} {
+			w := httptest.NewRecorder()
+			routes.ServeHTTP(w, preflight(origin))
+
+			if w.Result().Header.Get("Access-Control-Allow-Origin") != allowed {
+				t.Fatalf("expected Access-Control-Allow-Origin '%s' for %s", allowed, origin)
+			}
+		}
+	})
+}
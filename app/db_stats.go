@@ -0,0 +1,87 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/repositories"
+	"context"
+	"net/http"
+	"time"
+)
+
+// DBPoolStats is the state of the pool of connections to the primary database
+type DBPoolStats struct {
+	MaxOpen int `json:"max_open"`
+	Open    int `json:"open"`
+	InUse   int `json:"in_use"`
+	Idle    int `json:"idle"`
+	// WaitCount and WaitDurationMs are the totals since the pool was opened, the callers having
+	// waited for a connection
+	WaitCount      int64   `json:"wait_count"`
+	WaitDurationMs float64 `json:"wait_duration_ms"`
+}
+
+// DBStats are the state of the database pool and the estimates of the tables of METRICS_DB_STATS_TABLES
+type DBStats struct {
+	Pool   DBPoolStats               `json:"pool"`
+	Tables []repositories.TableStats `json:"tables"`
+}
+
+// collectDBStats publishes the gauges of the database pool and tables right away, then every
+// interval until ctx is done, for their growth to be graphed between the scrapes
+func (a *Application) collectDBStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := a.publishDBStats(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Errorf("error reading the statistics of the database tables: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishDBStats sets the gauges of the database pool and those of the tables, the ones of the
+// tables being left as they were when their estimates can't be read
+func (a *Application) publishDBStats(ctx context.Context) error {
+	a.recordDBStats()
+
+	tables, err := a.dbStatsRepository.TableStats(ctx, a.conf.Metrics.DBStatsTables)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		labels := metrics.Labels{"table": table.Name}
+		a.metrics.SetGauge(dbTableRowsMetric, float64(table.Rows), labels)
+		a.metrics.SetGauge(dbTableSizeMetric, float64(table.SizeBytes), labels)
+	}
+	return nil
+}
+
+// GetDBStats responds with the state of the database pool and the estimates of the tables, read
+// at once rather than as last published
+func (a *Application) GetDBStats(w http.ResponseWriter, r *http.Request) {
+	tables, err := a.dbStatsRepository.TableStats(r.Context(), a.conf.Metrics.DBStatsTables)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	stats := DBStats{Tables: tables}
+	if a.db != nil {
+		pool := a.db.Stats()
+		stats.Pool = DBPoolStats{
+			MaxOpen:        pool.MaxOpenConnections,
+			Open:           pool.OpenConnections,
+			InUse:          pool.InUse,
+			Idle:           pool.Idle,
+			WaitCount:      pool.WaitCount,
+			WaitDurationMs: durationMs(pool.WaitDuration),
+		}
+	}
+	respondJSON(w, r, stats, http.StatusOK)
+}
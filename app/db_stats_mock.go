@@ -0,0 +1,28 @@
+package app
+
+import (
+	"appdoki-be/app/repositories"
+	"context"
+)
+
+// mockDBStatsRepository answers the estimates of its tables, or err
+type mockDBStatsRepository struct {
+	tables []repositories.TableStats
+	err    error
+}
+
+func (r *mockDBStatsRepository) TableStats(_ context.Context, names []string) ([]repositories.TableStats, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	stats := []repositories.TableStats{}
+	for _, table := range r.tables {
+		for _, name := range names {
+			if table.Name == name {
+				stats = append(stats, table)
+			}
+		}
+	}
+	return stats, nil
+}
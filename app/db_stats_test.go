@@ -0,0 +1,125 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestApplication_collectDBStats(t *testing.T) {
+	// newApplication returns an application whose pool, never connecting, keeps 7 connections at most
+	newApplication := func(t *testing.T) (*Application, *mockDBStatsRepository, *metrics.Fake) {
+		t.Helper()
+		db, err := sqlx.Open("postgres", "postgres://localhost/appdoki")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		db.SetMaxOpenConns(7)
+
+		a := newTestApplication()
+		a.db = db
+		a.conf.Metrics.DBStatsTables = []string{"users", "notifications"}
+		repo := &mockDBStatsRepository{tables: []repos.TableStats{
+			{Name: "users", Rows: 12, SizeBytes: 65536},
+			{Name: "notifications", Rows: 340, SizeBytes: 524288},
+			{Name: "beer_transfers", Rows: 90, SizeBytes: 16384},
+		}}
+		a.dbStatsRepository = repo
+		return a, repo, a.metrics.(*metrics.Fake)
+	}
+	// collectOnce publishes the gauges, ctx being done before the first tick
+	collectOnce := func(a *Application) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		a.collectDBStats(ctx, time.Hour)
+	}
+
+	t.Run("expect the gauges of the pool and of the configured tables to be published", func(t *testing.T) {
+		a, _, fake := newApplication(t)
+
+		collectOnce(a)
+
+		if max := fake.Gauge(dbConnectionsMaxMetric, nil); max != 7 {
+			t.Fatalf("expected the pool to keep 7 connections at most, got %v", max)
+		}
+		users, notifications := metrics.Labels{"table": "users"}, metrics.Labels{"table": "notifications"}
+		if fake.Gauge(dbTableRowsMetric, users) != 12 || fake.Gauge(dbTableSizeMetric, users) != 65536 ||
+			fake.Gauge(dbTableRowsMetric, notifications) != 340 || fake.Gauge(dbTableSizeMetric, notifications) != 524288 {
+			t.Fatalf("expected the rows and size of the users and notifications")
+		}
+		if rows := fake.Gauge(dbTableRowsMetric, metrics.Labels{"table": "beer_transfers"}); rows != 0 {
+			t.Fatalf("expected the tables not configured to be left out, got %v rows", rows)
+		}
+	})
+
+	t.Run("expect the gauges to follow the tables, and to be kept when the estimates can't be read", func(t *testing.T) {
+		a, repo, fake := newApplication(t)
+		users := metrics.Labels{"table": "users"}
+
+		collectOnce(a)
+		repo.tables[0].Rows = 13
+		collectOnce(a)
+		if rows := fake.Gauge(dbTableRowsMetric, users); rows != 13 {
+			t.Fatalf("expected the rows to be updated, got %v", rows)
+		}
+
+		repo.err = errors.New("connection refused")
+		a.db.SetMaxOpenConns(9)
+		collectOnce(a)
+		if rows := fake.Gauge(dbTableRowsMetric, users); rows != 13 {
+			t.Fatalf("expected the last rows to be kept, got %v", rows)
+		}
+		if max := fake.Gauge(dbConnectionsMaxMetric, nil); max != 9 {
+			t.Fatalf("expected the gauges of the pool to be updated still, got %v", max)
+		}
+	})
+}
+
+func TestApplication_GetDBStats(t *testing.T) {
+	t.Run("expect admins to get the pool and the configured tables", func(t *testing.T) {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		a.conf.Metrics.DBStatsTables = []string{"users"}
+		a.dbStatsRepository = &mockDBStatsRepository{tables: []repos.TableStats{
+			{Name: "users", Rows: 12, SizeBytes: 65536},
+			{Name: "notifications", Rows: 340, SizeBytes: 524288},
+		}}
+
+		r := httptest.NewRequest("GET", "/admin/db/stats", nil)
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+
+		var stats DBStats
+		if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+			t.Fatal(err)
+		}
+		if len(stats.Tables) != 1 || stats.Tables[0] != (repos.TableStats{Name: "users", Rows: 12, SizeBytes: 65536}) {
+			t.Fatalf("unexpected tables %+v", stats.Tables)
+		}
+	})
+
+	t.Run("expect other users to be forbidden", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/admin/db/stats", nil)
+		w := httptest.NewRecorder()
+		newTestApplication().Routes().ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusForbidden)
+	})
+}
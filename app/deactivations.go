@@ -0,0 +1,79 @@
+package app
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	deactivationsTTL        = 10 * time.Second
+	maxDeactivationsEntries = 10000
+)
+
+// deactivations caches whether the users are deactivated for a short while, sparing the primary
+// database a query per authenticated request. The deactivations made through this instance are
+// seen at once, those made through the others within the ttl.
+type deactivations struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]deactivation
+}
+
+type deactivation struct {
+	deactivated bool
+	expires     time.Time
+}
+
+func newDeactivations(ttl time.Duration) *deactivations {
+	return &deactivations{ttl: ttl, entries: map[string]deactivation{}}
+}
+
+// get returns whether the user is deactivated, ok being false when it's unknown or expired
+func (d *deactivations) get(userID string, now time.Time) (deactivated bool, ok bool) {
+	if d == nil {
+		return false, false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, found := d.entries[userID]
+	if !found || !now.Before(entry.expires) {
+		return false, false
+	}
+	return entry.deactivated, true
+}
+
+// set remembers whether the user is deactivated for the ttl. The expired entries are evicted when
+// the cache is full, the user being left out when none expired.
+func (d *deactivations) set(userID string, deactivated bool, now time.Time) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, found := d.entries[userID]; !found && len(d.entries) >= maxDeactivationsEntries {
+		for id, entry := range d.entries {
+			if !now.Before(entry.expires) {
+				delete(d.entries, id)
+			}
+		}
+		if len(d.entries) >= maxDeactivationsEntries {
+			return
+		}
+	}
+	d.entries[userID] = deactivation{deactivated: deactivated, expires: now.Add(d.ttl)}
+}
+
+// forget drops the users, for their next request to read whether they're deactivated again
+func (d *deactivations) forget(userIDs ...string) {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, id := range userIDs {
+		delete(d.entries, id)
+	}
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDeactivations(t *testing.T) {
+	now := time.Now()
+
+	t.Run("expect the users to be remembered for the ttl, until forgotten", func(t *testing.T) {
+		d := newDeactivations(time.Minute)
+		d.set("1", true, now)
+		d.set("2", false, now)
+
+		if deactivated, ok := d.get("1", now.Add(59*time.Second)); !ok || !deactivated {
+			t.Fatalf("expected user 1 to be remembered as deactivated, got %v, %v", deactivated, ok)
+		}
+		if deactivated, ok := d.get("2", now); !ok || deactivated {
+			t.Fatalf("expected user 2 to be remembered as active, got %v, %v", deactivated, ok)
+		}
+		if _, ok := d.get("1", now.Add(time.Minute)); ok {
+			t.Fatal("expected user 1 to expire")
+		}
+
+		d.forget("2")
+		if _, ok := d.get("2", now); ok {
+			t.Fatal("expected user 2 to be forgotten")
+		}
+	})
+
+	t.Run("expect a full cache to evict the expired users, and to leave the new ones out otherwise", func(t *testing.T) {
+		d := newDeactivations(time.Minute)
+		for i := 0; i < maxDeactivationsEntries; i++ {
+			d.set(strconv.Itoa(i), false, now)
+		}
+
+		d.set("new", true, now)
+		if _, ok := d.get("new", now); ok {
+			t.Fatal("expected the new user to be left out of the full cache")
+		}
+
+		later := now.Add(time.Minute)
+		d.set("new", true, later)
+		if deactivated, ok := d.get("new", later); !ok || !deactivated || len(d.entries) != 1 {
+			t.Fatalf("expected the expired users to be evicted for the new one, got %v, %v and %d entries", deactivated, ok, len(d.entries))
+		}
+	})
+}
@@ -0,0 +1,245 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// the channels of the notifiers whose sends given up on are kept as dead letters
+const (
+	deadLetterFCM     = "fcm"
+	deadLetterWebPush = "webpush"
+	deadLetterSlack   = "slack"
+	deadLetterEmail   = "email"
+	deadLetterWebhook = "webhook"
+	// deadLetterQueue keeps the sends left in the notifications queue on shutdown
+	deadLetterQueue = "queue"
+)
+
+const (
+	deadLetterStoreTimeout = 5 * time.Second
+	// maxDeadLettersReplay is how many dead letters a bulk retry replays at most
+	maxDeadLettersReplay = 100
+)
+
+// DeadLettersReplay tells how many dead letters a bulk retry queued
+type DeadLettersReplay struct {
+	Queued int `json:"queued"`
+}
+
+// deadLettering returns the failure handler of the notifier of the channel, keeping its sends
+// given up on with their attempts exhausted, to be replayed through sender
+func (a *Application) deadLettering(channel string, sender notify.Sender, failed notify.FailureHandler) notify.FailureHandler {
+	if a.replaySenders == nil {
+		a.replaySenders = map[string]notify.Sender{}
+	}
+	a.replaySenders[channel] = sender
+
+	return func(ctx context.Context, failure notify.Failure) {
+		failed(ctx, failure)
+		if failure.Exhausted() {
+			a.storeDeadLetter(ctx, channel, failure)
+		}
+	}
+}
+
+// storeDeadLetter keeps the failed send, even when given up on as the application stops
+func (a *Application) storeDeadLetter(ctx context.Context, channel string, failure notify.Failure) {
+	job, err := json.Marshal(failure.Job)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error serializing a dead letter: %v", err)
+		return
+	}
+
+	storeCtx, cancel := context.WithTimeout(logging.Detach(ctx), deadLetterStoreTimeout)
+	defer cancel()
+	_, err = a.deadLettersRepository.Create(storeCtx, &repositories.DeadLetter{
+		Channel:  channel,
+		Op:       failure.Op,
+		Target:   failure.Target,
+		Job:      job,
+		Error:    failure.Err.Error(),
+		Attempts: failure.Attempts,
+		Pending:  failure.Interrupted,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error storing a dead letter: %v", err)
+		return
+	}
+	a.metrics.IncCounter(notificationsDeadLetteredMetric, metrics.Labels{"channel": channel, "op": failure.Op})
+}
+
+// replayDeadLetter attempts the send of the dead letter again, removing it once sent
+func (a *Application) replayDeadLetter(ctx context.Context, letter *repositories.DeadLetter, sender notify.Sender) {
+	var job notify.Job
+	err := json.Unmarshal(letter.Job, &job)
+	if err == nil {
+		err = job.Send(ctx, sender)
+	}
+	if err == nil {
+		err = a.deadLettersRepository.Delete(ctx, letter.ID)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error removing dead letter %d: %v", letter.ID, err)
+		}
+		return
+	}
+
+	logging.FromContext(ctx).WithError(err).Warnf("replaying dead letter %d failed", letter.ID)
+	if err := a.deadLettersRepository.RecordAttempt(ctx, letter.ID, err.Error()); err != nil {
+		logging.FromContext(ctx).Errorf("error recording the replay of dead letter %d: %v", letter.ID, err)
+	}
+}
+
+// replayPendingDeadLetters replays the dead letters left as the application last stopped, the
+// ones failing again, or of a channel no longer configured, being kept for the admins to replay
+func (a *Application) replayPendingDeadLetters(ctx context.Context) {
+	replayed := 0
+	var last int64
+	for ctx.Err() == nil {
+		letters, err := a.deadLettersRepository.ListPending(ctx, maxDeadLettersReplay)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error listing the pending dead letters: %v", err)
+			return
+		}
+		// the letters failing to be replayed or deleted stay pending, not to be replayed twice
+		if len(letters) == 0 || letters[0].ID <= last {
+			break
+		}
+		for _, letter := range letters {
+			sender, ok := a.replaySenders[letter.Channel]
+			if !ok {
+				if err := a.deadLettersRepository.RecordAttempt(ctx, letter.ID, "the channel of the dead letter isn't configured"); err != nil {
+					logging.FromContext(ctx).Errorf("error recording the replay of dead letter %d: %v", letter.ID, err)
+				}
+				continue
+			}
+			a.replayDeadLetter(ctx, letter, sender)
+		}
+		replayed += len(letters)
+		last = letters[len(letters)-1].ID
+		if len(letters) < maxDeadLettersReplay {
+			break
+		}
+	}
+	if replayed > 0 {
+		logging.FromContext(ctx).Infof("replayed %d notifications left as the application last stopped", replayed)
+	}
+}
+
+// GetDeadLetters lists the notification sends given up on, the oldest first
+func (a *Application) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	params := newQueryParams(r)
+	limit := params.IntInRange("limit", 20, 1, 100)
+	if err := params.Err(); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+
+	letters, err := a.deadLettersRepository.List(r.Context(), limit)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	respondJSON(w, r, letters, http.StatusOK)
+}
+
+// RetryDeadLetter queues the replay of a dead letter, removed once sent
+func (a *Application) RetryDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "dead letter not found", nil)
+		return
+	}
+
+	letter, err := a.deadLettersRepository.Find(r.Context(), id)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	if letter == nil {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "dead letter not found", nil)
+		return
+	}
+	sender, ok := a.replaySenders[letter.Channel]
+	if !ok {
+		respondError(w, http.StatusConflict, ErrCodeConflict, "the channel of the dead letter isn't configured", nil)
+		return
+	}
+
+	a.workers.Go(r.Context(), func(ctx context.Context) {
+		a.replayDeadLetter(ctx, letter, sender)
+	})
+
+	respondNoContent(w, http.StatusAccepted)
+}
+
+// RetryDeadLetters queues the replay of the oldest dead letters, skipping the ones of the channels
+// no longer configured
+func (a *Application) RetryDeadLetters(w http.ResponseWriter, r *http.Request) {
+	letters, err := a.deadLettersRepository.List(r.Context(), maxDeadLettersReplay)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	queued := make([]*repositories.DeadLetter, 0, len(letters))
+	for _, letter := range letters {
+		if _, ok := a.replaySenders[letter.Channel]; ok {
+			queued = append(queued, letter)
+		}
+	}
+	a.workers.Go(r.Context(), func(ctx context.Context) {
+		for _, letter := range queued {
+			a.replayDeadLetter(ctx, letter, a.replaySenders[letter.Channel])
+		}
+	})
+
+	respondJSON(w, r, DeadLettersReplay{Queued: len(queued)}, http.StatusAccepted)
+}
+
+// queuedSender replays the sends left in the notifications queue through the notifier the queue
+// hands them over to, their payload decoded from JSON being turned back into one the templates render
+type queuedSender struct {
+	notifier notify.Notifier
+}
+
+func (s queuedSender) SendToUser(ctx context.Context, userID string, n notify.Notification) error {
+	s.notifier.SendToUser(ctx, userID, queuedNotification(n))
+	return nil
+}
+
+func (s queuedSender) SendToTopic(ctx context.Context, topic string, n notify.Notification) error {
+	s.notifier.SendToTopic(ctx, topic, queuedNotification(n))
+	return nil
+}
+
+func (s queuedSender) SendMulticast(ctx context.Context, tokens []string, n notify.Notification) error {
+	s.notifier.SendMulticast(ctx, tokens, queuedNotification(n))
+	return nil
+}
+
+func (s queuedSender) SubscribeToTopic(ctx context.Context, tokens []string, topic string) error {
+	s.notifier.SubscribeToTopic(ctx, tokens, topic)
+	return nil
+}
+
+func (s queuedSender) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) error {
+	s.notifier.UnsubscribeFromTopic(ctx, tokens, topic)
+	return nil
+}
+
+func queuedNotification(n notify.Notification) notify.Notification {
+	if payload, ok := n.Payload.(map[string]interface{}); ok {
+		n.Payload = templatePayload(payload)
+	}
+	return n
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"sync"
+	"time"
+)
+
+// mockDeadLettersRepository keeps the dead letters in memory
+type mockDeadLettersRepository struct {
+	mu      sync.Mutex
+	letters []*repos.DeadLetter
+	nextID  int64
+}
+
+func newMockDeadLettersRepository() *mockDeadLettersRepository {
+	return &mockDeadLettersRepository{}
+}
+
+func (r *mockDeadLettersRepository) Create(_ context.Context, letter *repos.DeadLetter) (*repos.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	created := *letter
+	created.ID = r.nextID
+	created.CreatedAt = time.Now()
+	created.UpdatedAt = created.CreatedAt
+	r.letters = append(r.letters, &created)
+	saved := created
+	return &saved, nil
+}
+
+func (r *mockDeadLettersRepository) List(_ context.Context, limit int) ([]*repos.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	letters := []*repos.DeadLetter{}
+	for _, letter := range r.letters {
+		if len(letters) == limit {
+			break
+		}
+		saved := *letter
+		letters = append(letters, &saved)
+	}
+	return letters, nil
+}
+
+func (r *mockDeadLettersRepository) ListPending(_ context.Context, limit int) ([]*repos.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	letters := []*repos.DeadLetter{}
+	for _, letter := range r.letters {
+		if len(letters) == limit {
+			break
+		}
+		if letter.Pending {
+			saved := *letter
+			letters = append(letters, &saved)
+		}
+	}
+	return letters, nil
+}
+
+func (r *mockDeadLettersRepository) Find(_ context.Context, ID int64) (*repos.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, letter := range r.letters {
+		if letter.ID == ID {
+			saved := *letter
+			return &saved, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *mockDeadLettersRepository) RecordAttempt(_ context.Context, ID int64, errMessage string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, letter := range r.letters {
+		if letter.ID == ID {
+			letter.Attempts++
+			letter.Error = errMessage
+			letter.Pending = false
+			letter.UpdatedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (r *mockDeadLettersRepository) Delete(_ context.Context, ID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, letter := range r.letters {
+		if letter.ID == ID {
+			r.letters = append(r.letters[:i], r.letters[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// failingSender fails every send with err, counting them
+type failingSender struct {
+	err   error
+	sends int
+}
+
+func (s *failingSender) SendToUser(context.Context, string, notify.Notification) error {
+	s.sends++
+	return s.err
+}
+func (s *failingSender) SendToTopic(context.Context, string, notify.Notification) error {
+	s.sends++
+	return s.err
+}
+func (s *failingSender) SendMulticast(context.Context, []string, notify.Notification) error {
+	s.sends++
+	return s.err
+}
+func (s *failingSender) SubscribeToTopic(context.Context, []string, string) error {
+	s.sends++
+	return s.err
+}
+func (s *failingSender) UnsubscribeFromTopic(context.Context, []string, string) error {
+	s.sends++
+	return s.err
+}
+
+func TestApplication_deadLetters(t *testing.T) {
+	newAdminApplication := func() *Application {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		return a
+	}
+	giveUp := func(a *Application, sender notify.Sender) {
+		failed := a.deadLettering(deadLetterFCM, sender, func(context.Context, notify.Failure) {})
+		notifier := notify.WithRetry(sender, notify.RetryPolicy{MaxAttempts: 1}, a.workers.Go, failed)
+		notifier.SendToUser(context.Background(), "42", notify.Notification{Title: "Cheers!"})
+	}
+	serve := func(a *Application, method string, target string) *http.Response {
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w.Result()
+	}
+
+	t.Run("expect the sends given up on with their attempts exhausted to be listed", func(t *testing.T) {
+		a := newAdminApplication()
+		giveUp(a, &failingSender{err: notify.Retryable(errors.New("unavailable"))})
+		giveUp(a, &failingSender{err: errors.New("invalid argument")})
+
+		resp := serve(a, "GET", "/admin/notifications/dead-letters")
+
+		assertStatusCode(t, resp, http.StatusOK)
+		var letters []*repos.DeadLetter
+		if err := json.NewDecoder(resp.Body).Decode(&letters); err != nil {
+			t.Fatal(err)
+		}
+		if len(letters) != 1 || letters[0].Channel != deadLetterFCM || letters[0].Op != notify.OpSendToUser || letters[0].Target != "42" || letters[0].Attempts != 1 {
+			t.Fatalf("expected the exhausted send alone, got %+v", letters)
+		}
+		if count := a.metrics.(*metrics.Fake).Counter(notificationsDeadLetteredMetric, metrics.Labels{"channel": deadLetterFCM, "op": notify.OpSendToUser}); count != 1 {
+			t.Fatalf("expected 1 dead letter to be counted, got %v", count)
+		}
+	})
+
+	t.Run("expect a replayed dead letter to be removed", func(t *testing.T) {
+		a := newAdminApplication()
+		sender := &failingSender{err: notify.Retryable(errors.New("unavailable"))}
+		giveUp(a, sender)
+		sender.err = nil
+
+		resp := serve(a, "POST", "/admin/notifications/dead-letters/1/retry")
+
+		assertStatusCode(t, resp, http.StatusAccepted)
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if letters, _ := a.deadLettersRepository.List(context.Background(), 10); sender.sends != 2 || len(letters) != 0 {
+			t.Fatalf("expected the dead letter to be sent and removed, got %d sends and %+v", sender.sends, letters)
+		}
+	})
+
+	t.Run("expect a failed replay to be counted", func(t *testing.T) {
+		a := newAdminApplication()
+		giveUp(a, &failingSender{err: notify.Retryable(errors.New("unavailable"))})
+
+		resp := serve(a, "POST", "/admin/notifications/dead-letters/retry")
+
+		assertStatusCode(t, resp, http.StatusAccepted)
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		letter, _ := a.deadLettersRepository.Find(context.Background(), 1)
+		if letter == nil || letter.Attempts != 2 || letter.Error != "unavailable" {
+			t.Fatalf("expected the dead letter to be kept with its attempt, got %+v", letter)
+		}
+	})
+
+	t.Run("expect an unknown dead letter to return 404", func(t *testing.T) {
+		resp := serve(newAdminApplication(), "POST", "/admin/notifications/dead-letters/1/retry")
+
+		assertStatusCode(t, resp, http.StatusNotFound)
+		assertErrorCode(t, resp, ErrCodeNotFound)
+	})
+}
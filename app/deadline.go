@@ -0,0 +1,120 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const requestTimeoutHeader = "X-Request-Timeout-ms"
+
+// clientDeadlineMiddleware turns the X-Request-Timeout-ms header into a deadline of the request
+// context, capped by maxTimeout, so the database calls of a request the client already gave up on
+// are cancelled. A response which hadn't started when the deadline expired is replaced by a 503.
+func clientDeadlineMiddleware(maxTimeout time.Duration) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := clientTimeout(r, maxTimeout)
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			dw := &deadlineWriter{ResponseWriter: w, ctx: ctx}
+			next.ServeHTTP(dw, r.WithContext(ctx))
+
+			dw.mu.Lock()
+			defer dw.mu.Unlock()
+			if !dw.wroteHeader && ctx.Err() == context.DeadlineExceeded {
+				dw.respondDeadlineExceeded()
+			}
+		})
+	}
+}
+
+// clientTimeout parses the timeout requested by the client, 0 when there's none or it's invalid
+func clientTimeout(r *http.Request, maxTimeout time.Duration) time.Duration {
+	value := r.Header.Get(requestTimeoutHeader)
+	if value == "" || maxTimeout <= 0 {
+		return 0
+	}
+
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms <= 0 {
+		logging.FromContext(r.Context()).Debugf("ignoring invalid %s '%s'", requestTimeoutHeader, value)
+		return 0
+	}
+
+	if ms > maxTimeout.Milliseconds() {
+		return maxTimeout
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// deadlineWriter replaces the response by a deadline_exceeded error when its headers are
+// written after the client deadline expired, the client being gone or about to go
+type deadlineWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	mu          sync.Mutex
+	wroteHeader bool
+	replaced    bool
+}
+
+func (dw *deadlineWriter) WriteHeader(code int) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+
+	dw.writeHeader(code)
+}
+
+func (dw *deadlineWriter) writeHeader(code int) {
+	if dw.wroteHeader {
+		return
+	}
+	if dw.ctx.Err() == context.DeadlineExceeded {
+		dw.respondDeadlineExceeded()
+		return
+	}
+	dw.wroteHeader = true
+	dw.ResponseWriter.WriteHeader(code)
+}
+
+func (dw *deadlineWriter) Write(b []byte) (int, error) {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+
+	dw.writeHeader(http.StatusOK)
+	if dw.replaced {
+		return 0, context.DeadlineExceeded
+	}
+	return dw.ResponseWriter.Write(b)
+}
+
+func (dw *deadlineWriter) Flush() {
+	dw.mu.Lock()
+	defer dw.mu.Unlock()
+
+	if dw.replaced {
+		return
+	}
+	flush(dw.ResponseWriter)
+}
+
+// Unwrap returns the wrapped writer
+func (dw *deadlineWriter) Unwrap() http.ResponseWriter {
+	return dw.ResponseWriter
+}
+
+func (dw *deadlineWriter) respondDeadlineExceeded() {
+	dw.wroteHeader = true
+	dw.replaced = true
+	respondError(dw.ResponseWriter, http.StatusServiceUnavailable, ErrCodeDeadlineExceeded,
+		"the request deadline set by the client was exceeded", nil)
+}
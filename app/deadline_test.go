@@ -0,0 +1,88 @@
+package app
+
+import (
+	"appdoki-be/app/reporting"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientDeadlineMiddleware(t *testing.T) {
+	t.Run("expect the deadline to cancel the repository calls and answer 503", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.Server.MaxClientRequestTimeout = time.Second
+		cancelled := make(chan error, 1)
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(ctx context.Context, _ string) (*repos.User, error) {
+			select {
+			case <-ctx.Done():
+				cancelled <- ctx.Err()
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+				cancelled <- nil
+				return nil, nil
+			}
+		}
+
+		r := httptest.NewRequest("GET", "/api/v1/users/42", nil)
+		r.Header.Set(requestTimeoutHeader, "20")
+		w := httptest.NewRecorder()
+		start := time.Now()
+		a.Routes().ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusServiceUnavailable)
+		assertJSONContentType(t, resp)
+		assertErrorCode(t, resp, ErrCodeDeadlineExceeded)
+		if err := <-cancelled; err != context.DeadlineExceeded {
+			t.Fatalf("expected the repository call to be cancelled, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Fatalf("expected the request to end at its deadline, took %v", elapsed)
+		}
+		if captures := a.errorReporter.(*reporting.Fake).Captures(); len(captures) != 0 {
+			t.Fatalf("expected no capture, got %v", captures)
+		}
+	})
+
+	t.Run("expect responses within the deadline to be passed through", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.Server.MaxClientRequestTimeout = time.Second
+
+		r := httptest.NewRequest("GET", "/api/v1/users/42", nil)
+		r.Header.Set(requestTimeoutHeader, "1000")
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusOK)
+	})
+}
+
+func TestClientTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		max      time.Duration
+		expected time.Duration
+	}{
+		{"expect the requested timeout", "250", time.Second, 250 * time.Millisecond},
+		{"expect the timeout to be capped", "60000", time.Second, time.Second},
+		{"expect invalid timeouts to be ignored", "soon", time.Second, 0},
+		{"expect negative timeouts to be ignored", "-5", time.Second, 0},
+		{"expect the header to be ignored without a max", "250", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set(requestTimeoutHeader, tt.header)
+
+			if timeout := clientTimeout(r, tt.max); timeout != tt.expected {
+				t.Fatalf("expected %v, got %v", tt.expected, timeout)
+			}
+		})
+	}
+}
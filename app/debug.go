@@ -0,0 +1,122 @@
+package app
+
+import (
+	"appdoki-be/app/buildinfo"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+var startedAt = time.Now()
+
+// DebugRouter mounts the pprof profiles and runtime information when enabled,
+// only for requests bearing the debug token
+func (a *Application) DebugRouter(router *mux.Router) {
+	if !a.conf.Debug.Enabled {
+		return
+	}
+
+	if a.conf.Debug.Token == "" {
+		a.logger.Warn("debug endpoints disabled: DEBUG_TOKEN is not set")
+		return
+	}
+
+	debug := router.PathPrefix("/debug").Subrouter()
+	debugAccess := scopeAccess(debugScope)
+	get := []string{http.MethodGet}
+
+	a.mount(debug,
+		routeDef{methods: get, path: "/vars", access: debugAccess, handler: http.HandlerFunc(debugVarsHandler)},
+		// the trailing slash of the index is trimmed by trimSuffixMiddleware
+		routeDef{methods: get, path: "/pprof", access: debugAccess, handler: http.HandlerFunc(pprofIndexHandler)},
+		routeDef{methods: get, path: "/pprof/cmdline", access: debugAccess, handler: http.HandlerFunc(pprof.Cmdline)},
+		routeDef{methods: get, path: "/pprof/profile", access: debugAccess, handler: http.HandlerFunc(pprof.Profile)},
+		routeDef{methods: []string{http.MethodGet, http.MethodPost}, path: "/pprof/symbol", access: debugAccess,
+			handler: http.HandlerFunc(pprof.Symbol)},
+		routeDef{methods: get, path: "/pprof/trace", access: debugAccess, handler: http.HandlerFunc(pprof.Trace)},
+		routeDef{methods: get, path: "/pprof/{profile}", access: debugAccess, handler: http.HandlerFunc(pprof.Index)},
+	)
+}
+
+// debugTokenVerify only lets through requests bearing the given token
+func debugTokenVerify(token string) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !hasBearerToken(r, token) {
+				respondError(w, http.StatusForbidden, ErrCodeForbidden, "a valid debug token is required", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func pprofIndexHandler(w http.ResponseWriter, r *http.Request) {
+	r.URL.Path = "/debug/pprof/"
+	pprof.Index(w, r)
+}
+
+type DebugVars struct {
+	Build      BuildInfo `json:"build"`
+	Uptime     string    `json:"uptime"`
+	Goroutines int       `json:"goroutines"`
+	Memory     MemStats  `json:"memory"`
+	GC         GCStats   `json:"gc"`
+}
+
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+type MemStats struct {
+	Alloc       uint64 `json:"alloc"`
+	TotalAlloc  uint64 `json:"total_alloc"`
+	Sys         uint64 `json:"sys"`
+	HeapObjects uint64 `json:"heap_objects"`
+}
+
+type GCStats struct {
+	NumGC        uint32     `json:"num_gc"`
+	PauseTotalNs uint64     `json:"pause_total_ns"`
+	LastGC       *time.Time `json:"last_gc"`
+}
+
+// debugVarsHandler responds with the build details and runtime statistics
+func debugVarsHandler(w http.ResponseWriter, r *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	vars := DebugVars{
+		Build: BuildInfo{
+			Version:   buildinfo.Version,
+			Commit:    buildinfo.Commit,
+			BuildTime: buildinfo.BuildTime,
+			GoVersion: runtime.Version(),
+		},
+		Uptime:     time.Since(startedAt).Round(time.Second).String(),
+		Goroutines: runtime.NumGoroutine(),
+		Memory: MemStats{
+			Alloc:       mem.Alloc,
+			TotalAlloc:  mem.TotalAlloc,
+			Sys:         mem.Sys,
+			HeapObjects: mem.HeapObjects,
+		},
+		GC: GCStats{
+			NumGC:        mem.NumGC,
+			PauseTotalNs: mem.PauseTotalNs,
+		},
+	}
+	if mem.LastGC > 0 {
+		lastGC := time.Unix(0, int64(mem.LastGC))
+		vars.GC.LastGC = &lastGC
+	}
+
+	w.Header().Set("Cache-Control", noStoreCache)
+	respondJSON(w, r, vars, http.StatusOK)
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"appdoki-be/app/buildinfo"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApplication_DebugRouter(t *testing.T) {
+	newRoutes := func(enabled bool) http.Handler {
+		a := newTestApplication()
+		a.conf.Debug.Enabled = enabled
+		a.conf.Debug.Token = "secret"
+		return a.Routes()
+	}
+
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/heap", "/debug/vars"} {
+		t.Run("expect GET "+path+" to return 404 when disabled", func(t *testing.T) {
+			r := httptest.NewRequest("GET", path, nil)
+			r.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+			newRoutes(false).ServeHTTP(w, r)
+
+			assertStatusCode(t, w.Result(), http.StatusNotFound)
+		})
+
+		t.Run("expect GET "+path+" to return 403 without the token", func(t *testing.T) {
+			r := httptest.NewRequest("GET", path, nil)
+			r.Header.Set("Authorization", "Bearer wrong")
+			w := httptest.NewRecorder()
+			newRoutes(true).ServeHTTP(w, r)
+
+			resp := w.Result()
+			assertStatusCode(t, resp, http.StatusForbidden)
+			assertErrorCode(t, resp, ErrCodeForbidden)
+		})
+
+		t.Run("expect GET "+path+" to return 200 with the token", func(t *testing.T) {
+			r := httptest.NewRequest("GET", path, nil)
+			r.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+			newRoutes(true).ServeHTTP(w, r)
+
+			assertStatusCode(t, w.Result(), http.StatusOK)
+		})
+	}
+
+	t.Run("expect GET /debug/vars to return the build and runtime info", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/debug/vars", nil)
+		r.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		newRoutes(true).ServeHTTP(w, r)
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+
+		var vars DebugVars
+		if err := json.NewDecoder(resp.Body).Decode(&vars); err != nil {
+			t.Fatalf("failed to parse the debug vars: %v", err)
+		}
+		if vars.Build.Version != buildinfo.Version {
+			t.Fatalf("expected version '%s', got '%s'", buildinfo.Version, vars.Build.Version)
+		}
+		if vars.Goroutines == 0 {
+			t.Fatal("expected the goroutines count")
+		}
+	})
+}
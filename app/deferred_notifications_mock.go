@@ -0,0 +1,48 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"sync"
+	"time"
+)
+
+// mockDeferredNotificationsRepository keeps the deferred notifications in memory
+type mockDeferredNotificationsRepository struct {
+	mu       sync.Mutex
+	deferred []*repos.DeferredNotification
+	nextID   int64
+}
+
+func newMockDeferredNotificationsRepository() *mockDeferredNotificationsRepository {
+	return &mockDeferredNotificationsRepository{}
+}
+
+func (r *mockDeferredNotificationsRepository) Create(_ context.Context, deferred *repos.DeferredNotification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	created := *deferred
+	created.ID = r.nextID
+	created.CreatedAt = time.Now()
+	r.deferred = append(r.deferred, &created)
+	return nil
+}
+
+func (r *mockDeferredNotificationsRepository) TakeDue(_ context.Context, now time.Time, limit int) ([]*repos.DeferredNotification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	due := []*repos.DeferredNotification{}
+	kept := r.deferred[:0]
+	for _, deferred := range r.deferred {
+		if len(due) < limit && !deferred.SendAfter.After(now) {
+			due = append(due, deferred)
+		} else {
+			kept = append(kept, deferred)
+		}
+	}
+	r.deferred = kept
+	return due, nil
+}
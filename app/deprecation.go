@@ -0,0 +1,126 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	deprecatedRequestsMetric = "http_deprecated_requests_total"
+	deprecationsDocURL       = "https://github.com/Cloudoki/appdoki-be#deprecations"
+	deprecationLogInterval   = time.Hour
+	maxDeprecationLogEntries = 10000
+)
+
+var (
+	// legacyRoutesSunset is when the unversioned aliases of the v1 routes are removed
+	legacyRoutesSunset = time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+	// legacyTokenSunset is when the capitalized Token field of the token responses is removed
+	legacyTokenSunset = time.Date(2027, time.July, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Deprecated flags the responses of the route as deprecated, announcing when it goes away and
+// where its replacement is documented, and counts its calls. Callers are logged at most once per
+// hour each, by user ID once JwtVerify ran or by IP otherwise. When nested, the outer wrapper wins.
+func (a *Application) Deprecated(next http.HandlerFunc, sunset time.Time, docURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if w.Header().Get("Deprecation") != "" {
+			next(w, r)
+			return
+		}
+
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", docURL))
+
+		route := currentRouteTemplate(r)
+		a.metrics.IncCounter(deprecatedRequestsMetric, metrics.Labels{"route": route, "method": r.Method})
+
+		ctx, caller := withCaller(r.Context())
+		next(w, r.WithContext(ctx))
+
+		a.logDeprecatedCall(r, route, caller.UserID())
+	}
+}
+
+// deprecationMiddleware flags the responses of the unversioned API routes as deprecated
+// and links to their versioned successor
+func (a *Application) deprecationMiddleware(next http.Handler) http.Handler {
+	deprecated := a.Deprecated(next.ServeHTTP, legacyRoutesSunset, deprecationsDocURL)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", a.absoluteURL(r, apiV1Prefix+r.URL.Path, nil)))
+
+		deprecated(w, r)
+	})
+}
+
+// logDeprecatedCall logs who called the deprecated route, unless they were logged within the hour
+func (a *Application) logDeprecatedCall(r *http.Request, route string, userID string) {
+	entry := logging.FromContext(r.Context()).WithField("route", route)
+	key := route + " "
+	if userID != "" {
+		entry = entry.WithField("user_id", userID)
+		key += "user:" + userID
+	} else {
+		ip := clientIP(r, a.trustedProxies).String()
+		entry = entry.WithField("client_ip", ip)
+		key += "ip:" + ip
+	}
+
+	if a.deprecationLog.allow(key, time.Now()) {
+		entry.Warnf("deprecated route %s %s called", r.Method, route)
+	}
+}
+
+// currentRouteTemplate returns the path template of the route handling the request
+func currentRouteTemplate(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unmatched"
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return "unmatched"
+	}
+	return template
+}
+
+// deprecationLog remembers when each caller of a deprecated route was last logged
+type deprecationLog struct {
+	mu       sync.Mutex
+	interval time.Duration
+	logged   map[string]time.Time
+}
+
+func newDeprecationLog(interval time.Duration) *deprecationLog {
+	return &deprecationLog{interval: interval, logged: map[string]time.Time{}}
+}
+
+// allow checks if the key may be logged at now, recording it if so. When the log is full the keys
+// past the interval are evicted, and the new key is neither logged nor recorded if none was.
+func (l *deprecationLog) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if last, ok := l.logged[key]; ok && now.Sub(last) < l.interval {
+		return false
+	}
+	if _, ok := l.logged[key]; !ok && len(l.logged) >= maxDeprecationLogEntries {
+		for k, last := range l.logged {
+			if now.Sub(last) >= l.interval {
+				delete(l.logged, k)
+			}
+		}
+		if len(l.logged) >= maxDeprecationLogEntries {
+			return false
+		}
+	}
+	l.logged[key] = now
+	return true
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplication_Deprecated(t *testing.T) {
+	sunset := time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("expect the deprecation headers to be set", func(t *testing.T) {
+		a := newTestApplication()
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		a.Deprecated(homeHandler, sunset, "https://example.com/deprecations")(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if resp.Header.Get("Deprecation") != "true" {
+			t.Fatalf("expected Deprecation 'true', got '%s'", resp.Header.Get("Deprecation"))
+		}
+		if s := resp.Header.Get("Sunset"); s != "Thu, 01 Apr 2027 00:00:00 GMT" {
+			t.Fatalf("expected the sunset HTTP-date, got '%s'", s)
+		}
+		if link := resp.Header.Get("Link"); link != `<https://example.com/deprecations>; rel="deprecation"` {
+			t.Fatalf("expected Link to the deprecation docs, got '%s'", link)
+		}
+	})
+
+	t.Run("expect the legacy routes to link to their successor and the deprecation docs", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/users", nil)
+		w := httptest.NewRecorder()
+		newTestApplication().Routes().ServeHTTP(w, r)
+
+		links := w.Result().Header.Values("Link")
+		if len(links) != 2 || !strings.Contains(links[1], `rel="deprecation"`) {
+			t.Fatalf("expected the successor and deprecation links, got %v", links)
+		}
+		if links[0] != `<http://example.com/api/v1/users>; rel="successor-version"` {
+			t.Fatalf("expected the absolute URL of the successor, got %s", links[0])
+		}
+		if w.Result().Header.Get("Sunset") == "" {
+			t.Fatal("expected a Sunset header")
+		}
+	})
+
+	t.Run("expect the calls to be counted per route", func(t *testing.T) {
+		a := newTestApplication()
+		routes := a.Routes()
+		for i := 0; i < 2; i++ {
+			r := httptest.NewRequest("GET", "/users", nil)
+			routes.ServeHTTP(httptest.NewRecorder(), r)
+		}
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		routes.ServeHTTP(httptest.NewRecorder(), r)
+
+		fake := a.metrics.(*metrics.Fake)
+		if count := fake.Counter(deprecatedRequestsMetric, metrics.Labels{"route": "/users", "method": "GET"}); count != 2 {
+			t.Fatalf("expected 2 deprecated calls, got %v", count)
+		}
+		if count := fake.Counter(deprecatedRequestsMetric, metrics.Labels{"route": "/api/v1/users", "method": "GET"}); count != 0 {
+			t.Fatalf("expected versioned calls not to be counted, got %v", count)
+		}
+	})
+
+	t.Run("expect nested wrappers to flag the response once", func(t *testing.T) {
+		a := newTestApplication()
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		inner := a.Deprecated(homeHandler, sunset.AddDate(0, 3, 0), "https://example.com/inner")
+		a.Deprecated(inner, sunset, "https://example.com/outer")(w, r)
+
+		resp := w.Result()
+
+		if links := resp.Header.Values("Link"); len(links) != 1 {
+			t.Fatalf("expected a single deprecation link, got %v", links)
+		}
+		if s := resp.Header.Get("Sunset"); s != "Thu, 01 Apr 2027 00:00:00 GMT" {
+			t.Fatalf("expected the outer sunset, got '%s'", s)
+		}
+	})
+
+	t.Run("expect the authenticated caller to be known to the wrapper", func(t *testing.T) {
+		a := newTestApplication()
+		a.deprecationLog = newDeprecationLog(time.Hour)
+		r := httptest.NewRequest("GET", "/", nil)
+		a.Deprecated(a.JwtVerify(homeHandler), sunset, "https://example.com/deprecations")(httptest.NewRecorder(), r)
+
+		if a.deprecationLog.allow("unmatched user:1", time.Now()) {
+			t.Fatal("expected the call of user 1 to have been logged")
+		}
+	})
+}
+
+func TestDeprecationLog_allow(t *testing.T) {
+	now := time.Now()
+
+	t.Run("expect a caller to be logged once per interval", func(t *testing.T) {
+		l := newDeprecationLog(time.Hour)
+
+		if !l.allow("/users user:1", now) {
+			t.Fatal("expected the first call to be logged")
+		}
+		if l.allow("/users user:1", now.Add(59*time.Minute)) {
+			t.Fatal("expected a call within the hour not to be logged")
+		}
+		if !l.allow("/users user:2", now) {
+			t.Fatal("expected another caller to be logged")
+		}
+		if !l.allow("/users user:1", now.Add(time.Hour)) {
+			t.Fatal("expected a call after the hour to be logged")
+		}
+	})
+
+	t.Run("expect a full log to stay at its cap, evicting the callers past the interval", func(t *testing.T) {
+		l := newDeprecationLog(time.Hour)
+		for i := 0; i < maxDeprecationLogEntries; i++ {
+			l.allow("/users ip:"+strconv.Itoa(i), now)
+		}
+
+		if l.allow("/users ip:new", now.Add(time.Minute)) || len(l.logged) != maxDeprecationLogEntries {
+			t.Fatalf("expected the new caller to be left out of the full log, got %d entries", len(l.logged))
+		}
+		if !l.allow("/users ip:new", now.Add(time.Hour)) || len(l.logged) != 1 {
+			t.Fatalf("expected the expired callers to be evicted for the new one, got %d entries", len(l.logged))
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package app
+
+import (
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"context"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+const (
+	maxDevicePayloadBytes = 8 << 10
+	maxDeviceTokenLength  = 4096
+)
+
+// DevicesHandler holds handler dependencies
+type DevicesHandler struct {
+	devicesRepo repositories.DevicesRepositoryInterface
+	notifier    notify.Notifier
+	events      *eventDispatcher
+	workers     *workerGroup
+}
+
+// NewDevicesHandler returns an initialized devices handler with the required dependencies
+func NewDevicesHandler(devicesRepo repositories.DevicesRepositoryInterface, notifier notify.Notifier, events *eventDispatcher,
+	workers *workerGroup) *DevicesHandler {
+	return &DevicesHandler{
+		devicesRepo: devicesRepo,
+		notifier:    notifier,
+		events:      events,
+		workers:     workers,
+	}
+}
+
+// DevicePayload registers either the FCM token of a device or the Web Push subscription of a browser
+type DevicePayload struct {
+	Token        string                         `json:"token"`
+	Platform     string                         `json:"platform"`
+	Subscription *repositories.PushSubscription `json:"subscription"`
+}
+
+func (p *DevicePayload) validate() []string {
+	var errs []string
+
+	switch {
+	case p.Subscription != nil:
+		if p.Token != "" {
+			errs = append(errs, "token: can't be given along with a subscription")
+		}
+		if p.Platform != Web {
+			errs = append(errs, "subscription: only browsers subscribe with web push")
+		}
+		if len(p.Subscription.Endpoint) > maxDeviceTokenLength {
+			errs = append(errs, "subscription: the endpoint must be at most 4096 characters")
+		} else if err := p.webPushSubscription().Validate(); err != nil {
+			errs = append(errs, "subscription: "+err.Error())
+		}
+	case p.Token == "" || len(p.Token) > maxDeviceTokenLength:
+		errs = append(errs, "token: must be between 1 and 4096 characters")
+	}
+	if p.Platform != Web && p.Platform != IOS && p.Platform != Android {
+		errs = append(errs, "platform: must be one of web, ios, Android")
+	}
+
+	return errs
+}
+
+func (p *DevicePayload) webPushSubscription() notify.WebPushSubscription {
+	return notify.WebPushSubscription{
+		Endpoint: p.Subscription.Endpoint,
+		P256dh:   p.Subscription.Keys.P256dh,
+		Auth:     p.Subscription.Keys.Auth,
+	}
+}
+
+// Register registers the FCM token of the device the user is signed in on, or the Web Push
+// subscription of their browser under a token derived from its endpoint, answering 201 the first
+// time and 200 when registering it again, which only refreshes its last_seen_at. New FCM devices are
+// subscribed to the all-users topic, and every new device publishes eventDeviceRegistered.
+func (h *DevicesHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var payload DevicePayload
+	if err := decodeJSON(r, &payload, maxDevicePayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		respondError(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed, "invalid device", errs)
+		return
+	}
+
+	token := payload.Token
+	if payload.Subscription != nil {
+		token = notify.WebPushToken(payload.Subscription.Endpoint)
+	}
+	userID, _ := r.Context().Value("userID").(string)
+	device, created, err := h.devicesRepo.Upsert(r.Context(), &repositories.DeviceToken{
+		Token:        token,
+		UserID:       userID,
+		Platform:     payload.Platform,
+		Subscription: payload.Subscription,
+	})
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	if created && payload.Subscription == nil {
+		h.workers.Go(r.Context(), func(ctx context.Context) {
+			h.notifier.SubscribeToTopic(ctx, []string{device.Token}, allUsersTopic)
+		})
+	}
+	if created {
+		h.events.Publish(r.Context(), eventDeviceRegistered, userEvent{UserID: userID, Device: device})
+	}
+	respondJSON(w, r, device, status)
+}
+
+// Delete unregisters a device of the user, on sign out, unsubscribing it from the all-users topic
+func (h *DevicesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+	token := mux.Vars(r)["token"]
+
+	found, err := h.devicesRepo.Delete(r.Context(), userID, token)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	if !found {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "device not found", nil)
+		return
+	}
+	h.workers.Go(r.Context(), func(ctx context.Context) {
+		h.notifier.UnsubscribeFromTopic(ctx, []string{token}, allUsersTopic)
+	})
+
+	respondNoContent(w, http.StatusNoContent)
+}
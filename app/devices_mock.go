@@ -0,0 +1,121 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// mockDevicesRepository keeps the device tokens in memory, with the same upsert semantics as the database
+type mockDevicesRepository struct {
+	mu      sync.Mutex
+	devices map[string]*repos.DeviceToken
+}
+
+func newMockDevicesRepository() *mockDevicesRepository {
+	return &mockDevicesRepository{devices: map[string]*repos.DeviceToken{}}
+}
+
+func (r *mockDevicesRepository) Upsert(_ context.Context, device *repos.DeviceToken) (*repos.DeviceToken, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	stored, ok := r.devices[device.Token]
+	if !ok {
+		stored = &repos.DeviceToken{Token: device.Token, CreatedAt: now}
+		r.devices[device.Token] = stored
+	}
+	stored.UserID = device.UserID
+	stored.Platform = device.Platform
+	stored.Subscription = device.Subscription
+	stored.LastSeenAt = now
+
+	saved := *stored
+	return &saved, !ok, nil
+}
+
+func (r *mockDevicesRepository) ListByUser(_ context.Context, userID string) ([]*repos.DeviceToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	devices := []*repos.DeviceToken{}
+	for _, device := range r.devices {
+		if device.UserID == userID {
+			saved := *device
+			devices = append(devices, &saved)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].LastSeenAt.After(devices[j].LastSeenAt) })
+	return devices, nil
+}
+
+func (r *mockDevicesRepository) Delete(_ context.Context, userID string, token string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	device, ok := r.devices[token]
+	if !ok || device.UserID != userID {
+		return false, nil
+	}
+	delete(r.devices, token)
+	return true, nil
+}
+
+func (r *mockDevicesRepository) DeleteTokens(_ context.Context, tokens []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range tokens {
+		delete(r.devices, token)
+	}
+	return nil
+}
+
+func (r *mockDevicesRepository) ListTokensByUsers(_ context.Context, userIDs []string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := map[string]bool{}
+	for _, userID := range userIDs {
+		users[userID] = true
+	}
+	tokens := []string{}
+	for token, device := range r.devices {
+		if users[device.UserID] {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Strings(tokens)
+	return tokens, nil
+}
+
+func (r *mockDevicesRepository) ListOwnersByTokens(_ context.Context, tokens []string) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	owners := map[string]string{}
+	for _, token := range tokens {
+		if device, ok := r.devices[token]; ok {
+			owners[token] = device.UserID
+		}
+	}
+	return owners, nil
+}
+
+func (r *mockDevicesRepository) ListByTokens(_ context.Context, tokens []string) ([]*repos.DeviceToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	devices := []*repos.DeviceToken{}
+	for _, token := range tokens {
+		if device, ok := r.devices[token]; ok {
+			saved := *device
+			devices = append(devices, &saved)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].Token < devices[j].Token })
+	return devices, nil
+}
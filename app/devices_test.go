@@ -0,0 +1,178 @@
+package app
+
+import (
+	"appdoki-be/app/notify"
+	"appdoki-be/app/reporting"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDevicesHandler(t *testing.T) {
+	register := func(routes http.Handler, body string) *http.Response {
+		r := httptest.NewRequest("POST", "/api/v1/users/me/devices", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	t.Run("expect a new device to be registered with 201 and a known one refreshed with 200", func(t *testing.T) {
+		a := newTestApplication()
+		routes := a.Routes()
+
+		resp := register(routes, `{"token":"fcm-token","platform":"ios"}`)
+		assertStatusCode(t, resp, http.StatusCreated)
+		assertJSONContentType(t, resp)
+
+		var device repos.DeviceToken
+		if err := json.NewDecoder(resp.Body).Decode(&device); err != nil {
+			t.Fatal(err)
+		}
+		if device.Token != "fcm-token" || device.Platform != "ios" {
+			t.Fatalf("unexpected device %+v", device)
+		}
+
+		assertStatusCode(t, register(routes, `{"token":"fcm-token","platform":"ios"}`), http.StatusOK)
+
+		devices, _ := a.devicesRepository.ListByUser(context.Background(), "1")
+		if len(devices) != 1 {
+			t.Fatalf("expected the device to be registered once, got %d", len(devices))
+		}
+	})
+
+	t.Run("expect an invalid device to return 422", func(t *testing.T) {
+		resp := register(newTestApplication().Routes(), `{"token":"","platform":"windows"}`)
+
+		assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+		assertErrorCode(t, resp, ErrCodeValidationFailed)
+	})
+
+	t.Run("expect a browser to be registered with its web push subscription", func(t *testing.T) {
+		a := newTestApplication()
+		routes := a.Routes()
+
+		resp := register(routes, webPushDeviceBody("web"))
+		assertStatusCode(t, resp, http.StatusCreated)
+
+		var device repos.DeviceToken
+		if err := json.NewDecoder(resp.Body).Decode(&device); err != nil {
+			t.Fatal(err)
+		}
+		if device.Token != notify.WebPushToken("https://fcm.googleapis.com/fcm/send/abc") {
+			t.Fatalf("expected the token to be derived from the endpoint, got %s", device.Token)
+		}
+		devices, _ := a.devicesRepository.ListByUser(context.Background(), "1")
+		if len(devices) != 1 || devices[0].Subscription == nil || devices[0].Subscription.Keys.Auth != "AAECAwQFBgcICQoLDA0ODw" {
+			t.Fatalf("expected the subscription to be stored, got %+v", devices)
+		}
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if subscribers := a.notifier.(*notify.Fake).Subscribers(allUsersTopic); len(subscribers) != 0 {
+			t.Fatalf("expected the browser not to be subscribed to topics, got %v", subscribers)
+		}
+	})
+
+	t.Run("expect an invalid web push subscription to return 422", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"other platform": webPushDeviceBody("ios"),
+			"with a token":   `{"token":"fcm-token","platform":"web","subscription":{"endpoint":"https://push.example.com/abc","keys":{"p256dh":"","auth":""}}}`,
+			"invalid keys":   `{"platform":"web","subscription":{"endpoint":"https://push.example.com/abc","keys":{"p256dh":"abc","auth":"abc"}}}`,
+		} {
+			resp := register(newTestApplication().Routes(), body)
+
+			if resp.StatusCode != http.StatusUnprocessableEntity {
+				t.Fatalf("expected the subscription with %s to return 422, got %d", name, resp.StatusCode)
+			}
+		}
+	})
+
+	t.Run("expect a device to be unregistered, and only by its user", func(t *testing.T) {
+		a := newTestApplication()
+		a.devicesRepository.Upsert(context.Background(), &repos.DeviceToken{Token: "mine", UserID: "1", Platform: Web})
+		a.devicesRepository.Upsert(context.Background(), &repos.DeviceToken{Token: "theirs", UserID: "2", Platform: Web})
+		routes := a.Routes()
+
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, httptest.NewRequest("DELETE", "/api/v1/users/me/devices/mine", nil))
+		assertStatusCode(t, w.Result(), http.StatusNoContent)
+
+		w = httptest.NewRecorder()
+		routes.ServeHTTP(w, httptest.NewRequest("DELETE", "/api/v1/users/me/devices/theirs", nil))
+		assertStatusCode(t, w.Result(), http.StatusNotFound)
+
+		if devices, _ := a.devicesRepository.ListByUser(context.Background(), "2"); len(devices) != 1 {
+			t.Fatal("expected the device of the other user to be kept")
+		}
+	})
+}
+
+func TestDevicesHandler_topics(t *testing.T) {
+	t.Run("expect new devices to be subscribed to the all-users topic until unregistered", func(t *testing.T) {
+		a := newTestApplication()
+		routes := a.Routes()
+
+		r := httptest.NewRequest("POST", "/api/v1/users/me/devices", strings.NewReader(`{"token":"fcm-token","platform":"web"}`))
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+		assertStatusCode(t, w.Result(), http.StatusCreated)
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if subscribers := a.notifier.(*notify.Fake).Subscribers(allUsersTopic); len(subscribers) != 1 || subscribers[0] != "fcm-token" {
+			t.Fatalf("expected the device to be subscribed, got %v", subscribers)
+		}
+
+		a.workers = newWorkerGroup(reporting.Noop{})
+		w = httptest.NewRecorder()
+		routes = a.Routes()
+		routes.ServeHTTP(w, httptest.NewRequest("DELETE", "/api/v1/users/me/devices/fcm-token", nil))
+		assertStatusCode(t, w.Result(), http.StatusNoContent)
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if subscribers := a.notifier.(*notify.Fake).Subscribers(allUsersTopic); len(subscribers) != 0 {
+			t.Fatalf("expected the device to be unsubscribed, got %v", subscribers)
+		}
+	})
+}
+
+func TestDeviceTokens(t *testing.T) {
+	t.Run("expect the tokens of the user's devices to be resolved and forgotten", func(t *testing.T) {
+		repo := newMockDevicesRepository()
+		repo.Upsert(context.Background(), &repos.DeviceToken{Token: "a", UserID: "1", Platform: IOS})
+		repo.Upsert(context.Background(), &repos.DeviceToken{Token: "b", UserID: "1", Platform: Android})
+		repo.Upsert(context.Background(), &repos.DeviceToken{Token: "c", UserID: "2", Platform: Web})
+		tokens := deviceTokens{repo}
+
+		resolved, err := tokens.UserDevices(context.Background(), "1")
+		if err != nil || len(resolved) != 2 {
+			t.Fatalf("expected the 2 devices of the user, got %v (%v)", resolved, err)
+		}
+		for _, device := range resolved {
+			if (device.Token == "a" && device.Platform != IOS) || (device.Token == "b" && device.Platform != Android) {
+				t.Fatalf("expected the platform of the devices to be resolved, got %+v", resolved)
+			}
+		}
+
+		if err := tokens.Forget(context.Background(), []string{"a"}); err != nil {
+			t.Fatal(err)
+		}
+		if resolved, _ := tokens.UserDevices(context.Background(), "1"); len(resolved) != 1 || resolved[0].Token != "b" {
+			t.Fatalf("expected only b to be left, got %v", resolved)
+		}
+	})
+}
+
+// webPushDeviceBody returns the registration of a browser subscribed with Web Push, on the platform
+func webPushDeviceBody(platform string) string {
+	key := make([]byte, 65)
+	key[0] = 4
+	return `{"platform":"` + platform + `","subscription":{"endpoint":"https://fcm.googleapis.com/fcm/send/abc",` +
+		`"keys":{"p256dh":"` + base64.RawURLEncoding.EncodeToString(key) + `","auth":"AAECAwQFBgcICQoLDA0ODw"}}}`
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"context"
+	"fmt"
+	"time"
+)
+
+const digestJob = "digest"
+
+// digestCheckInterval is how often the digest schedule is checked for a run due
+const digestCheckInterval = time.Minute
+
+const digestsSentMetric = "digests_sent_total"
+
+// the frequencies of the digest
+const (
+	digestDaily  = "daily"
+	digestWeekly = "weekly"
+)
+
+// digestSchedule is when the digests are sent, at Hour (UTC) every day or every Monday
+type digestSchedule struct {
+	Frequency string
+	Hour      int
+}
+
+func (s digestSchedule) validate() error {
+	if s.Frequency != digestDaily && s.Frequency != digestWeekly {
+		return fmt.Errorf("unknown frequency %q, expected %q or %q", s.Frequency, digestDaily, digestWeekly)
+	}
+	if s.Hour < 0 || s.Hour > 23 {
+		return fmt.Errorf("hour %d out of the 0-23 range", s.Hour)
+	}
+	return nil
+}
+
+// period is the time between two runs
+func (s digestSchedule) period() time.Duration {
+	if s.Frequency == digestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// periodName names the period in the digest copy
+func (s digestSchedule) periodName() string {
+	if s.Frequency == digestWeekly {
+		return "week"
+	}
+	return "day"
+}
+
+// last returns the last run scheduled at or before now
+func (s digestSchedule) last(now time.Time) time.Time {
+	now = now.UTC()
+	last := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, 0, 0, 0, time.UTC)
+	if last.After(now) {
+		last = last.AddDate(0, 0, -1)
+	}
+	if s.Frequency == digestWeekly {
+		last = last.AddDate(0, 0, -((int(last.Weekday()) + 6) % 7))
+	}
+	return last
+}
+
+func (a *Application) digestSchedule() digestSchedule {
+	return digestSchedule{Frequency: a.conf.Digest.Frequency, Hour: a.conf.Digest.Hour}
+}
+
+// runDigests sends the digest of its last scheduled run every interval until ctx is cancelled. Every
+// run is claimed by a single instance, the others skipping it.
+func (a *Application) runDigests(ctx context.Context, interval time.Duration) {
+	schedule := a.digestSchedule()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := a.runDigest(ctx, schedule, schedule.last(time.Now())); err != nil {
+			logging.FromContext(ctx).Errorf("error sending the digests: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDigest sends the users the beers they received since the previous run, through the channel
+// they prefer, unless another instance claimed the run scheduled at scheduledAt
+func (a *Application) runDigest(ctx context.Context, schedule digestSchedule, scheduledAt time.Time) error {
+	claimed, err := a.jobRunsRepository.Claim(ctx, digestJob, scheduledAt)
+	if err != nil || !claimed {
+		return err
+	}
+
+	err = a.sendDigests(ctx, schedule, scheduledAt)
+	if finishErr := a.jobRunsRepository.Finish(ctx, digestJob, scheduledAt, err); finishErr != nil && err == nil {
+		err = finishErr
+	}
+	return err
+}
+
+func (a *Application) sendDigests(ctx context.Context, schedule digestSchedule, scheduledAt time.Time) error {
+	since := scheduledAt.Add(-schedule.period())
+	previous, err := a.jobRunsRepository.LastScheduledBefore(ctx, digestJob, scheduledAt)
+	if err != nil {
+		return err
+	}
+	if previous != nil {
+		since = *previous
+	}
+
+	received, err := a.beersRepository.ReceivedBetween(ctx, since, scheduledAt)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range received {
+		prefs, err := a.preferencesRepository.FindNotifications(ctx, r.UserID)
+		if err != nil {
+			return err
+		}
+		if !prefs.Digest {
+			continue
+		}
+
+		notifier := a.notifier
+		if prefs.DigestChannel == repositories.DigestChannelEmail && a.mailer != nil {
+			notifier = a.mailer
+		}
+		notifier.SendToUser(ctx, r.UserID, notify.Notification{
+			Event:   notify.EventDigest,
+			Payload: digestPayload{Period: schedule.periodName(), Beers: r.Beers, Givers: r.Givers},
+		})
+		a.metrics.IncCounter(digestsSentMetric, metrics.Labels{"channel": prefs.DigestChannel})
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDigestSchedule(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2021, 3, 10, 12, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		schedule digestSchedule
+		now      time.Time
+		expected time.Time
+	}{
+		{"daily after the hour", digestSchedule{Frequency: digestDaily, Hour: 9}, now, time.Date(2021, 3, 10, 9, 0, 0, 0, time.UTC)},
+		{"daily before the hour", digestSchedule{Frequency: digestDaily, Hour: 18}, now, time.Date(2021, 3, 9, 18, 0, 0, 0, time.UTC)},
+		{"weekly", digestSchedule{Frequency: digestWeekly, Hour: 9}, now, time.Date(2021, 3, 8, 9, 0, 0, 0, time.UTC)},
+		{"weekly on Monday before the hour", digestSchedule{Frequency: digestWeekly, Hour: 9}, time.Date(2021, 3, 8, 8, 0, 0, 0, time.UTC), time.Date(2021, 3, 1, 9, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		t.Run("expect the last run "+c.name, func(t *testing.T) {
+			if last := c.schedule.last(c.now); !last.Equal(c.expected) {
+				t.Fatalf("expected %v, got %v", c.expected, last)
+			}
+		})
+	}
+
+	t.Run("expect an unknown frequency to be invalid", func(t *testing.T) {
+		if err := (digestSchedule{Frequency: "hourly", Hour: 9}).validate(); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestApplication_runDigest(t *testing.T) {
+	schedule := digestSchedule{Frequency: digestDaily, Hour: 9}
+	scheduledAt := time.Date(2021, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	newDigestApplication := func() (*Application, *notify.Fake, *notify.Fake) {
+		a := newTestApplication()
+		a.beersRepository.(*mockBeersRepository).receivedBetweenImpl = func(ctx context.Context, from time.Time, to time.Time) ([]*repos.BeersReceived, error) {
+			return []*repos.BeersReceived{
+				{UserID: "1", Beers: 3, Givers: 2},
+				{UserID: "2", Beers: 1, Givers: 1},
+				{UserID: "3", Beers: 2, Givers: 1},
+			}, nil
+		}
+		ctx := context.Background()
+		a.preferencesRepository.SaveNotifications(ctx, "2", &repos.NotificationPreferences{Digest: true, DigestChannel: repos.DigestChannelEmail})
+		a.preferencesRepository.SaveNotifications(ctx, "3", &repos.NotificationPreferences{Digest: false, DigestChannel: repos.DigestChannelPush})
+
+		pushes, emails := notify.NewFake(), notify.NewFake()
+		a.notifier, a.mailer = pushes, emails
+		return a, pushes, emails
+	}
+
+	t.Run("expect the users opted in to get the digest through their channel", func(t *testing.T) {
+		a, pushes, emails := newDigestApplication()
+		if err := a.runDigest(context.Background(), schedule, scheduledAt); err != nil {
+			t.Fatal(err)
+		}
+
+		if sent := pushes.Sent(); len(sent) != 1 || sent[0].UserID != "1" || sent[0].Notification.Event != notify.EventDigest {
+			t.Fatalf("expected user 1 to be pushed the digest, got %+v", sent)
+		}
+		if payload := pushes.Sent()[0].Notification.Payload.(digestPayload); payload != (digestPayload{Period: "day", Beers: 3, Givers: 2}) {
+			t.Fatalf("unexpected payload %+v", payload)
+		}
+		if sent := emails.Sent(); len(sent) != 1 || sent[0].UserID != "2" {
+			t.Fatalf("expected user 2 to be emailed the digest, got %+v", sent)
+		}
+		if count := a.metrics.(*metrics.Fake).Counter(digestsSentMetric, metrics.Labels{"channel": repos.DigestChannelEmail}); count != 1 {
+			t.Fatalf("expected an email digest to be counted, got %v", count)
+		}
+	})
+
+	t.Run("expect a run to be sent once", func(t *testing.T) {
+		a, pushes, _ := newDigestApplication()
+		a.runDigest(context.Background(), schedule, scheduledAt)
+		a.runDigest(context.Background(), schedule, scheduledAt)
+
+		if sent := pushes.Sent(); len(sent) != 1 {
+			t.Fatalf("expected the run to be sent once, got %d", len(sent))
+		}
+	})
+
+	t.Run("expect the beers received since the previous run", func(t *testing.T) {
+		a, _, _ := newDigestApplication()
+		var from time.Time
+		a.beersRepository.(*mockBeersRepository).receivedBetweenImpl = func(ctx context.Context, f time.Time, to time.Time) ([]*repos.BeersReceived, error) {
+			from = f
+			return nil, nil
+		}
+		previous := scheduledAt.Add(-72 * time.Hour)
+		a.jobRunsRepository.Claim(context.Background(), digestJob, previous)
+
+		a.runDigest(context.Background(), schedule, scheduledAt)
+		if !from.Equal(previous) {
+			t.Fatalf("expected the beers since %v, got %v", previous, from)
+		}
+	})
+}
+
+func TestDigestTemplate(t *testing.T) {
+	title, body := renderNotification(t, notify.Notification{Event: notify.EventDigest, Payload: digestPayload{Period: "week", Beers: 1, Givers: 1}})
+	if title != "Your beers this week 🍻" || body != "You got 1 beer from 1 person!" {
+		t.Fatalf("unexpected copy %q, %q", title, body)
+	}
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"appdoki-be/api"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+// DocsRouter serves the OpenAPI spec and, when enabled, the Swagger UI rendering it
+func (a *Application) DocsRouter(router *mux.Router) {
+	a.mount(router,
+		routeDef{methods: []string{http.MethodGet}, path: "/api/openapi.yaml", access: publicAccess,
+			handler: http.HandlerFunc(specHandler)},
+	)
+
+	if !a.conf.AppConfig.DocsEnabled {
+		return
+	}
+
+	csp := contentSecurityPolicyMiddleware(a.conf.AppConfig.SecurityHeaders.ContentSecurityPolicy)
+	a.mount(router,
+		routeDef{methods: []string{http.MethodGet}, path: "/docs", access: publicAccess,
+			handler: http.RedirectHandler("/api/docs/", http.StatusMovedPermanently)},
+		routeDef{methods: []string{http.MethodGet}, path: "/api/docs", prefix: true, access: publicAccess,
+			handler: csp(http.StripPrefix("/api/docs", http.FileServer(http.FS(api.SwaggerUI()))))},
+	)
+}
+
+func specHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(api.Spec)
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"appdoki-be/api"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v2"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// undocumentedRoutes are the routes deliberately left out of the API contract
+var undocumentedRoutes = map[string]bool{
+	"GET /metrics":                       true,
+	"GET /docs":                          true,
+	"GET /api/docs":                      true,
+	"GET " + apiV1Prefix + "/auth/login": true,
+	"GET " + apiV1Prefix + "/auth/google/callback": true,
+}
+
+type openAPISpec struct {
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths map[string]map[string]interface{} `yaml:"paths"`
+}
+
+// specRoutes lists the "METHOD /path" documented in the spec, prefixed with their server's path
+func specRoutes(t *testing.T) []string {
+	var spec openAPISpec
+	if err := yaml.Unmarshal(api.Spec, &spec); err != nil {
+		t.Fatalf("failed to parse the spec: %v", err)
+	}
+
+	serverPath := func(servers interface{}) string {
+		list, _ := servers.([]interface{})
+		if len(list) == 0 {
+			return ""
+		}
+		server, _ := list[0].(map[interface{}]interface{})
+		u, err := url.Parse(server["url"].(string))
+		if err != nil {
+			t.Fatalf("invalid server url: %v", err)
+		}
+		return u.Path
+	}
+
+	defaultPath := ""
+	if len(spec.Servers) > 0 {
+		u, err := url.Parse(spec.Servers[0].URL)
+		if err != nil {
+			t.Fatalf("invalid server url: %v", err)
+		}
+		defaultPath = u.Path
+	}
+
+	var routes []string
+	for path, item := range spec.Paths {
+		prefix := defaultPath
+		if servers, ok := item["servers"]; ok {
+			prefix = serverPath(servers)
+		}
+		for method := range item {
+			if method == "servers" || method == "parameters" {
+				continue
+			}
+			routes = append(routes, strings.ToUpper(method)+" "+prefix+path)
+		}
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+// routerRoutes lists the "METHOD /path" registered in the router, legacy aliases excluded
+func routerRoutes(t *testing.T, router *mux.Router) []string {
+	registered := map[string]bool{}
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		for _, method := range methods {
+			registered[method+" "+path] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk the router: %v", err)
+	}
+
+	var routes []string
+	for route := range registered {
+		method, path := route[:strings.Index(route, " ")], route[strings.Index(route, " ")+1:]
+		if registered[method+" "+apiV1Prefix+path] {
+			// unversioned alias of a v1 route
+			continue
+		}
+		if !undocumentedRoutes[route] {
+			routes = append(routes, route)
+		}
+	}
+	sort.Strings(routes)
+	return routes
+}
+
+func TestSpec_MatchesRoutes(t *testing.T) {
+	a := newTestApplication()
+	a.conf.AppConfig.DocsEnabled = true
+
+	documented := map[string]bool{}
+	for _, route := range specRoutes(t) {
+		documented[route] = true
+	}
+	registered := map[string]bool{}
+	for _, route := range routerRoutes(t, a.router()) {
+		registered[route] = true
+	}
+
+	for route := range registered {
+		if !documented[route] {
+			t.Errorf("route '%s' is missing from api/openapi.yaml", route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			t.Errorf("documented route '%s' is not registered", route)
+		}
+	}
+}
+
+func TestApplication_DocsRouter(t *testing.T) {
+	t.Run("expect GET /api/openapi.yaml to return the spec", func(t *testing.T) {
+		routes := newTestApplication().Routes()
+
+		r := httptest.NewRequest("GET", "/api/openapi.yaml", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/yaml") {
+			t.Fatalf("expected 'application/yaml', got '%s'", contentType)
+		}
+	})
+
+	t.Run("expect GET /api/docs/ to return 404 when docs are disabled", func(t *testing.T) {
+		routes := newTestApplication().Routes()
+
+		r := httptest.NewRequest("GET", "/api/docs/", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusNotFound)
+	})
+
+	t.Run("expect GET /api/docs/ to return the Swagger UI when docs are enabled", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.AppConfig.DocsEnabled = true
+		routes := a.Routes()
+
+		r := httptest.NewRequest("GET", "/api/docs/", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/html") {
+			t.Fatalf("expected 'text/html', got '%s'", contentType)
+		}
+	})
+}
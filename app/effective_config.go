@@ -0,0 +1,11 @@
+package app
+
+import (
+	"net/http"
+)
+
+// GetConfig responds with the effective configuration the instance runs with, the secrets redacted,
+// along with the source of every value
+func (a *Application) GetConfig(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, r, a.conf.Settings(), http.StatusOK)
+}
@@ -0,0 +1,28 @@
+package app
+
+// Error codes of the error envelope. They are part of the API contract: clients
+// may rely on them, so existing codes must not be renamed or repurposed.
+const (
+	ErrCodeInternal             = "internal_error"
+	ErrCodeBadRequest           = "bad_request"
+	ErrCodeRequestTooLarge      = "request_too_large"
+	ErrCodeValidationFailed     = "validation_failed"
+	ErrCodeMissingToken         = "missing_token"
+	ErrCodeInvalidToken         = "invalid_token"
+	ErrCodeInvalidCode          = "invalid_code"
+	ErrCodeInvalidSignature     = "invalid_signature"
+	ErrCodeForbidden            = "forbidden"
+	ErrCodeNotFound             = "not_found"
+	ErrCodeConflict             = "conflict"
+	ErrCodeMethodNotAllowed     = "method_not_allowed"
+	ErrCodeRateLimited          = "rate_limited"
+	ErrCodeTimeout              = "timeout"
+	ErrCodeDeadlineExceeded     = "deadline_exceeded"
+	ErrCodeMaintenance          = "maintenance"
+	ErrCodeIdempotencyKeyReused = "idempotency_key_reused"
+	ErrCodeRequestInProgress    = "request_in_progress"
+	ErrCodeAuthUnavailable      = "auth_unavailable"
+	ErrCodeDatabaseUnavailable  = "database_unavailable"
+	ErrCodeRequestCanceled      = "request_canceled"
+	ErrCodeUserDeactivated      = "user_deactivated"
+)
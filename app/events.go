@@ -0,0 +1,144 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const eventStreamContentType = "text/event-stream"
+
+var (
+	// eventsHeartbeatInterval is how often a comment is sent on idle event streams,
+	// so proxies and load balancers don't close them
+	eventsHeartbeatInterval = 15 * time.Second
+	// eventsRetry is how long clients wait before reconnecting once a stream ends
+	eventsRetry = 3 * time.Second
+	// eventsPingInterval is how often GET /events sends a ping
+	eventsPingInterval = 30 * time.Second
+	// notificationsHeartbeatInterval is the heartbeat of the notification streams
+	notificationsHeartbeatInterval = 25 * time.Second
+)
+
+// Event is a server-sent event, its data being sent as JSON
+type Event struct {
+	ID   string
+	Name string
+	Data interface{}
+}
+
+// PingEvent is the data of the ping events
+type PingEvent struct {
+	Time time.Time `json:"time"`
+}
+
+// streamEvents sends the events to the client as a text/event-stream, each one flushed as it comes,
+// with a heartbeat comment while there's none. It returns once the channel is closed or the request
+// context is done, when the client disconnects or the stream times out: clients reconnect after
+// eventsRetry. The response must not be written before.
+func streamEvents(w http.ResponseWriter, r *http.Request, events <-chan Event) {
+	streamEventsWithHeartbeat(w, r, events, eventsHeartbeatInterval)
+}
+
+// streamEventsWithHeartbeat is streamEvents sending the heartbeat comment every interval
+func streamEventsWithHeartbeat(w http.ResponseWriter, r *http.Request, events <-chan Event, interval time.Duration) {
+	if _, ok := w.(http.Flusher); !ok {
+		logging.FromContext(r.Context()).Error("streamEvents: the response writer can't be flushed")
+		respondInternalError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", eventStreamContentType)
+	w.Header().Set("Cache-Control", noStoreCache)
+	// nginx buffers the responses it proxies otherwise
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", eventsRetry.Milliseconds())
+	flush(w)
+
+	heartbeat := time.NewTicker(interval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				logging.FromContext(r.Context()).WithError(err).Debug("streamEvents: could not write the event")
+				return
+			}
+			heartbeat.Reset(interval)
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		flush(w)
+	}
+}
+
+// writeEvent writes the event in the text/event-stream format
+func writeEvent(w http.ResponseWriter, event Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if event.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", event.ID)
+	}
+	if event.Name != "" {
+		fmt.Fprintf(&b, "event: %s\n", event.Name)
+	}
+	fmt.Fprintf(&b, "data: %s\n\n", data)
+
+	_, err = w.Write([]byte(b.String()))
+	return err
+}
+
+// isEventStream checks if the headers are those of an event stream
+func isEventStream(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == eventStreamContentType
+}
+
+// flush sends what was written so far to the client, when w supports it
+func flush(w http.ResponseWriter) {
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// GetEvents streams a ping event every eventsPingInterval, the first live updates endpoint
+func (a *Application) GetEvents(w http.ResponseWriter, r *http.Request) {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(eventsPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case now := <-ticker.C:
+				select {
+				case events <- Event{Name: "ping", Data: PingEvent{Time: now.UTC()}}:
+				case <-r.Context().Done():
+					return
+				}
+			}
+		}
+	}()
+
+	streamEvents(w, r, events)
+}
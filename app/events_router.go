@@ -0,0 +1,13 @@
+package app
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func (a *Application) EventsRouter(router *mux.Router) {
+	a.mount(router,
+		routeDef{methods: []string{http.MethodGet}, path: "/events", access: authenticatedAccess,
+			handler: a.RateLimit(readRateLimit, a.GetEvents)},
+	)
+}
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flushRecorder is a ResponseRecorder safe to read while the handler writes,
+// which reports what was sent on every flush
+type flushRecorder struct {
+	mu      sync.Mutex
+	rec     *httptest.ResponseRecorder
+	flushes chan string
+}
+
+func newFlushRecorder() *flushRecorder {
+	return &flushRecorder{rec: httptest.NewRecorder(), flushes: make(chan string, 100)}
+}
+
+func (f *flushRecorder) Header() http.Header {
+	return f.rec.Header()
+}
+
+func (f *flushRecorder) WriteHeader(code int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rec.WriteHeader(code)
+}
+
+func (f *flushRecorder) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rec.Write(b)
+}
+
+func (f *flushRecorder) Flush() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rec.Flush()
+	f.flushes <- f.rec.Body.String()
+}
+
+// nextFlush waits for the next flush, returning everything sent so far
+func (f *flushRecorder) nextFlush(t *testing.T) string {
+	select {
+	case body := <-f.flushes:
+		return body
+	case <-time.After(time.Second):
+		t.Fatal("expected the stream to be flushed")
+		return ""
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	t.Run("expect every event to be flushed as it comes", func(t *testing.T) {
+		events := make(chan Event)
+		w := newFlushRecorder()
+		r := httptest.NewRequest("GET", "/events", nil)
+		done := make(chan struct{})
+		go func() {
+			streamEvents(w, r, events)
+			close(done)
+		}()
+
+		if body := w.nextFlush(t); body != "retry: 3000\n\n" {
+			t.Fatalf("expected the retry delay first, got '%s'", body)
+		}
+		if w.rec.Code != http.StatusOK || w.Header().Get("Content-Type") != eventStreamContentType {
+			t.Fatalf("expected a 200 event stream, got %d '%s'", w.rec.Code, w.Header().Get("Content-Type"))
+		}
+
+		events <- Event{ID: "1", Name: "ping", Data: map[string]int{"n": 1}}
+		if body := w.nextFlush(t); !strings.HasSuffix(body, "id: 1\nevent: ping\ndata: {\"n\":1}\n\n") {
+			t.Fatalf("expected the first event, got '%s'", body)
+		}
+		events <- Event{Data: "second"}
+		if body := w.nextFlush(t); !strings.HasSuffix(body, "\n\ndata: \"second\"\n\n") {
+			t.Fatalf("expected the second event, got '%s'", body)
+		}
+
+		close(events)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("expected the stream to end with the channel")
+		}
+	})
+
+	t.Run("expect a heartbeat while there's no event", func(t *testing.T) {
+		defer func(interval time.Duration) { eventsHeartbeatInterval = interval }(eventsHeartbeatInterval)
+		eventsHeartbeatInterval = 10 * time.Millisecond
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		w := newFlushRecorder()
+		go streamEvents(w, httptest.NewRequest("GET", "/events", nil).WithContext(ctx), make(chan Event))
+
+		w.nextFlush(t)
+		if body := w.nextFlush(t); !strings.HasSuffix(body, ": heartbeat\n\n") {
+			t.Fatalf("expected a heartbeat, got '%s'", body)
+		}
+	})
+
+	t.Run("expect the stream to end when the client disconnects", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			streamEvents(newFlushRecorder(), httptest.NewRequest("GET", "/events", nil).WithContext(ctx), make(chan Event))
+			close(done)
+		}()
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("expected the stream to end")
+		}
+	})
+
+	t.Run("expect a writer that can't be flushed to get 500", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		streamEvents(struct{ http.ResponseWriter }{w}, httptest.NewRequest("GET", "/events", nil), make(chan Event))
+
+		assertStatusCode(t, w.Result(), http.StatusInternalServerError)
+	})
+}
+
+func TestServeWithTimeout_EventStream(t *testing.T) {
+	t.Run("expect event streams to bypass the buffer and get the stream timeout", func(t *testing.T) {
+		events := make(chan Event)
+		ended := make(chan error, 1)
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			streamEvents(w, r, events)
+			ended <- r.Context().Err()
+		})
+		w := newFlushRecorder()
+		done := make(chan struct{})
+		go func() {
+			serveWithTimeout(w, httptest.NewRequest("GET", "/events", nil), h, 20*time.Millisecond, 200*time.Millisecond)
+			close(done)
+		}()
+
+		w.nextFlush(t)
+		time.Sleep(50 * time.Millisecond)
+		events <- Event{Name: "late"}
+		if body := w.nextFlush(t); !strings.Contains(body, "event: late") {
+			t.Fatalf("expected the event sent after the request timeout, got '%s'", body)
+		}
+
+		select {
+		case err := <-ended:
+			if err != context.Canceled {
+				t.Fatalf("expected the stream context to be cancelled, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected the stream to end after the stream timeout")
+		}
+		<-done
+		if w.rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.rec.Code)
+		}
+	})
+}
+
+func TestApplication_GetEvents(t *testing.T) {
+	defer func(interval time.Duration) { eventsPingInterval = interval }(eventsPingInterval)
+	eventsPingInterval = 100 * time.Millisecond
+
+	a := newTestApplication()
+	a.conf.Server.RequestTimeout = 50 * time.Millisecond
+	a.conf.Server.StreamRequestTimeout = time.Second
+	srv := httptest.NewServer(a.Routes())
+	defer srv.Close()
+
+	t.Run("expect pings to be streamed through every middleware", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/api/v1/events")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if contentType := resp.Header.Get("Content-Type"); contentType != eventStreamContentType {
+			t.Fatalf("expected an event stream, got '%s'", contentType)
+		}
+
+		lines := make(chan string)
+		go func() {
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+			close(lines)
+		}()
+
+		timeout := time.After(time.Second)
+		for pings := 0; pings < 2; {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					t.Fatal("expected the stream to stay open")
+				}
+				if line == "event: ping" {
+					pings++
+				}
+			case <-timeout:
+				t.Fatalf("expected 2 pings, got %d", pings)
+			}
+		}
+	})
+}
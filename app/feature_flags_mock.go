@@ -0,0 +1,65 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// mockFeatureFlagsRepository keeps the feature flags in memory
+type mockFeatureFlagsRepository struct {
+	mu    sync.Mutex
+	flags map[string]*repos.FeatureFlag
+	err   error
+}
+
+func newMockFeatureFlagsRepository() *mockFeatureFlagsRepository {
+	return &mockFeatureFlagsRepository{flags: map[string]*repos.FeatureFlag{}}
+}
+
+func (r *mockFeatureFlagsRepository) Seed(_ context.Context, defaults map[string]bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+	for name, enabled := range defaults {
+		if _, ok := r.flags[name]; !ok {
+			r.flags[name] = &repos.FeatureFlag{Name: name, Enabled: enabled, RolloutPercent: 100, UpdatedAt: time.Now()}
+		}
+	}
+	return nil
+}
+
+func (r *mockFeatureFlagsRepository) List(_ context.Context) ([]*repos.FeatureFlag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	flags := []*repos.FeatureFlag{}
+	for _, flag := range r.flags {
+		listed := *flag
+		flags = append(flags, &listed)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags, nil
+}
+
+func (r *mockFeatureFlagsRepository) Set(_ context.Context, flag *repos.FeatureFlag) (*repos.FeatureFlag, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	saved := *flag
+	saved.UpdatedAt = time.Now()
+	r.flags[flag.Name] = &saved
+	updated := saved
+	return &updated, nil
+}
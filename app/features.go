@@ -0,0 +1,263 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/repositories"
+	"context"
+	"github.com/gorilla/mux"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const maxFeaturePayloadBytes = 1 << 10
+
+// featureFlags caches the state of the feature flags kept in the feature flags repository, shared by
+// every replica. The configured flags are the defaults the repository is seeded with, the state set
+// by the admins winning over them. The cache is refreshed every FEATURE_FLAGS_REFRESH_INTERVAL, and
+// right away on the replica a flag is changed on.
+type featureFlags struct {
+	repo     repositories.FeatureFlagsRepositoryInterface
+	defaults map[string]bool
+
+	mu    sync.RWMutex
+	flags map[string]featureState
+}
+
+// featureState is a flag on or off, for RolloutPercent of the users when on
+type featureState struct {
+	Enabled        bool
+	RolloutPercent int
+}
+
+func newFeatureFlags(defaults map[string]bool, repo repositories.FeatureFlagsRepositoryInterface) *featureFlags {
+	f := &featureFlags{repo: repo, defaults: defaults, flags: make(map[string]featureState, len(defaults))}
+	for name, enabled := range defaults {
+		f.flags[name] = featureState{Enabled: enabled, RolloutPercent: 100}
+	}
+	return f
+}
+
+// Refresh seeds the repository with the flags missing, then caches the state of every flag
+func (f *featureFlags) Refresh(ctx context.Context) error {
+	if err := f.repo.Seed(ctx, f.defaults); err != nil {
+		return err
+	}
+	stored, err := f.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	flags := make(map[string]featureState, len(f.defaults)+len(stored))
+	for name, enabled := range f.defaults {
+		flags[name] = featureState{Enabled: enabled, RolloutPercent: 100}
+	}
+	for _, flag := range stored {
+		flags[flag.Name] = featureState{Enabled: flag.Enabled, RolloutPercent: flag.RolloutPercent}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flags = flags
+	return nil
+}
+
+// Enabled checks if the flag is on for the user, anonymous when userID is empty. The users of a
+// partial rollout are picked by rolloutBucket, the anonymous ones being left out. Unknown flags are off.
+func (f *featureFlags) Enabled(name string, userID string) bool {
+	f.mu.RLock()
+	state, ok := f.flags[name]
+	f.mu.RUnlock()
+
+	switch {
+	case !ok || !state.Enabled:
+		return false
+	case state.RolloutPercent >= 100:
+		return true
+	}
+	return userID != "" && rolloutBucket(name, userID) < state.RolloutPercent
+}
+
+// Available checks if the flag is on for some users at least
+func (f *featureFlags) Available(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	state := f.flags[name]
+	return state.Enabled && state.RolloutPercent > 0
+}
+
+// Exists checks if the flag is defined
+func (f *featureFlags) Exists(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	_, ok := f.flags[name]
+	return ok
+}
+
+// Set changes the state of a defined flag in the repository and in the cache, returning nil when
+// the flag is unknown
+func (f *featureFlags) Set(ctx context.Context, flag *repositories.FeatureFlag) (*repositories.FeatureFlag, error) {
+	if !f.Exists(flag.Name) {
+		return nil, nil
+	}
+	updated, err := f.repo.Set(ctx, flag)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flags[updated.Name] = featureState{Enabled: updated.Enabled, RolloutPercent: updated.RolloutPercent}
+	return updated, nil
+}
+
+// All returns the state of every flag, sorted by name
+func (f *featureFlags) All() []FeatureFlag {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	flags := make([]FeatureFlag, 0, len(f.flags))
+	for name, state := range f.flags {
+		flags = append(flags, FeatureFlag{Name: name, Enabled: state.Enabled, RolloutPercent: state.RolloutPercent})
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return flags
+}
+
+// EnabledFor returns the names of the flags on for the user, sorted
+func (f *featureFlags) EnabledFor(userID string) []string {
+	var names []string
+	for _, flag := range f.All() {
+		if f.Enabled(flag.Name, userID) {
+			names = append(names, flag.Name)
+		}
+	}
+	return names
+}
+
+// rolloutBucket places the user in one of 100 buckets, the same on every replica and restart for
+// the rollouts to be stable, and to keep their users as they grow. The flag name spreads the users
+// of the rollouts of different flags.
+func rolloutBucket(name string, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + userID))
+	return int(h.Sum32() % 100)
+}
+
+// refreshFeatureFlags refreshes the feature flags every interval until ctx is done, for the
+// changes made on the other replicas to be picked up
+func (a *Application) refreshFeatureFlags(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := a.features.Refresh(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Errorf("error refreshing the feature flags, keeping their last state: %v", err)
+		}
+	}
+}
+
+// FeatureGate hides the route behind the named feature flag, answering 404 as if it
+// didn't exist while the flag is off. It runs before anything else on the route:
+// a.FeatureGate(name, a.JwtVerify(a.FeatureRollout(name, handler))).
+func (a *Application) FeatureGate(name string, next http.HandlerFunc) http.HandlerFunc {
+	if !a.features.Exists(name) {
+		a.logger.Warnf("feature flag %q is not defined, its routes stay off", name)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !a.features.Available(name) {
+			notFoundHandler(w, r)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// FeatureRollout hides the route behind the named feature flag from the users left out of its
+// rollout, answering 404 as well. It runs once the user is known, after JwtVerify.
+func (a *Application) FeatureRollout(name string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := r.Context().Value("userID").(string)
+		if !a.features.Enabled(name, userID) {
+			notFoundHandler(w, r)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+type FeatureFlag struct {
+	Name           string `json:"name"`
+	Enabled        bool   `json:"enabled"`
+	RolloutPercent int    `json:"rollout_percent"`
+}
+
+// FeatureFlagPayload turns a flag on or off, when on for RolloutPercent of the users, every one
+// when left out
+type FeatureFlagPayload struct {
+	Enabled        *bool `json:"enabled"`
+	RolloutPercent *int  `json:"rollout_percent"`
+}
+
+// GetFeatures responds with the state of every feature flag
+func (a *Application) GetFeatures(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, r, a.features.All(), http.StatusOK)
+}
+
+// SetFeature turns a feature flag on or off for every replica, or on for a share of the users
+func (a *Application) SetFeature(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if !a.features.Exists(name) {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "feature flag not found", nil)
+		return
+	}
+
+	var payload FeatureFlagPayload
+	if err := decodeJSON(r, &payload, maxFeaturePayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	if payload.Enabled == nil {
+		respondErrorWithParams(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed,
+			"field \"{field}\" is required", map[string]string{"field": "enabled"}, nil)
+		return
+	}
+	rolloutPercent := 100
+	if payload.RolloutPercent != nil {
+		rolloutPercent = *payload.RolloutPercent
+	}
+	if rolloutPercent < 0 || rolloutPercent > 100 {
+		respondErrorWithParams(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed,
+			"field \"{field}\" must be between {min} and {max}", map[string]string{"field": "rollout_percent", "min": "0", "max": "100"}, nil)
+		return
+	}
+
+	actorID, _ := r.Context().Value("userID").(string)
+	flag := &repositories.FeatureFlag{Name: name, Enabled: *payload.Enabled, RolloutPercent: rolloutPercent}
+	if actorID != "" {
+		flag.UpdatedBy = &actorID
+	}
+	updated, err := a.features.Set(r.Context(), flag)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	if updated == nil {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "feature flag not found", nil)
+		return
+	}
+	logging.FromContext(r.Context()).
+		WithField("user_id", actorID).
+		Warnf("feature flag %s set to %t for %d%% of the users", name, updated.Enabled, updated.RolloutPercent)
+
+	respondJSON(w, r, FeatureFlag{Name: updated.Name, Enabled: updated.Enabled, RolloutPercent: updated.RolloutPercent}, http.StatusOK)
+}
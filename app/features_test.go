@@ -0,0 +1,269 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestApplication_FeatureGate(t *testing.T) {
+	serve := func(routes http.Handler, method string, path string, body string) *http.Response {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	t.Run("expect a gated route to return 404 while its flag is off", func(t *testing.T) {
+		a := newTestApplication()
+
+		resp := serve(a.Routes(), "GET", "/api/v1/users/me", "")
+
+		assertStatusCode(t, resp, http.StatusNotFound)
+		assertErrorCode(t, resp, ErrCodeNotFound)
+		assertStatusCode(t, serve(a.Routes(), "POST", "/api/v1/auth/token", `{"code":"abc"}`), http.StatusNotFound)
+	})
+
+	t.Run("expect a gated route to be served while its flag is on", func(t *testing.T) {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			return generateRandomUserMockWithID(ID), nil
+		}
+		a.features.Set(context.Background(), &repos.FeatureFlag{Name: "users_me", Enabled: true, RolloutPercent: 100})
+
+		resp := serve(a.Routes(), "GET", "/api/v1/users/me", "")
+
+		assertStatusCode(t, resp, http.StatusOK)
+		var user repos.User
+		if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+			t.Fatal(err)
+		}
+		if user.ID != "1" {
+			t.Fatalf("expected the authenticated user '1', got '%s'", user.ID)
+		}
+	})
+
+	t.Run("expect admins to flip a flag at runtime", func(t *testing.T) {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		routes := a.Routes()
+
+		assertStatusCode(t, serve(routes, "GET", "/api/v1/users/me", ""), http.StatusNotFound)
+
+		resp := serve(routes, "PUT", "/admin/features/users_me", `{"enabled":true}`)
+		assertStatusCode(t, resp, http.StatusOK)
+		assertStatusCode(t, serve(routes, "GET", "/api/v1/users/me", ""), http.StatusOK)
+
+		resp = serve(routes, "GET", "/admin/features", "")
+		assertStatusCode(t, resp, http.StatusOK)
+		var flags []FeatureFlag
+		if err := json.NewDecoder(resp.Body).Decode(&flags); err != nil {
+			t.Fatal(err)
+		}
+		if len(flags) != 2 || flags[1] != (FeatureFlag{Name: "users_me", Enabled: true, RolloutPercent: 100}) {
+			t.Fatalf("expected users_me to be listed as enabled, got %+v", flags)
+		}
+
+		resp = serve(routes, "PUT", "/admin/features/users_me", `{"enabled":false}`)
+		assertStatusCode(t, resp, http.StatusOK)
+		assertStatusCode(t, serve(routes, "GET", "/api/v1/users/me", ""), http.StatusNotFound)
+
+		stored, _ := a.features.repo.List(context.Background())
+		if len(stored) != 1 || stored[0].Name != "users_me" || stored[0].Enabled || stored[0].UpdatedBy == nil || *stored[0].UpdatedBy != "1" {
+			t.Fatalf("expected the change of admin 1 to be stored, got %+v", stored)
+		}
+	})
+
+	t.Run("expect a partial rollout to serve the users of its buckets only", func(t *testing.T) {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			return generateRandomUserMockWithID(ID), nil
+		}
+		// user 1 of the test token falls in bucket 7 of users_me
+		a.features.Set(context.Background(), &repos.FeatureFlag{Name: "users_me", Enabled: true, RolloutPercent: rolloutBucket("users_me", "1")})
+		assertStatusCode(t, serve(a.Routes(), "GET", "/api/v1/users/me", ""), http.StatusNotFound)
+
+		a.features.Set(context.Background(), &repos.FeatureFlag{Name: "users_me", Enabled: true, RolloutPercent: rolloutBucket("users_me", "1") + 1})
+		assertStatusCode(t, serve(a.Routes(), "GET", "/api/v1/users/me", ""), http.StatusOK)
+	})
+
+	t.Run("expect PUT /admin/features/{name} to return 422 for a rollout out of range", func(t *testing.T) {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+
+		resp := serve(a.Routes(), "PUT", "/admin/features/users_me", `{"enabled":true,"rollout_percent":101}`)
+
+		assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+		assertErrorCode(t, resp, ErrCodeValidationFailed)
+		if a.features.Available("users_me") {
+			t.Fatal("expected the flag to stay off")
+		}
+	})
+
+	t.Run("expect PUT /admin/features/{name} to return 404 for an unknown flag", func(t *testing.T) {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+
+		resp := serve(a.Routes(), "PUT", "/admin/features/nope", `{"enabled":true}`)
+
+		assertStatusCode(t, resp, http.StatusNotFound)
+	})
+
+	t.Run("expect PUT /admin/features/{name} to return 403 for non admins", func(t *testing.T) {
+		a := newTestApplication()
+
+		resp := serve(a.Routes(), "PUT", "/admin/features/users_me", `{"enabled":true}`)
+
+		assertStatusCode(t, resp, http.StatusForbidden)
+		if a.features.Enabled("users_me", "1") {
+			t.Fatal("expected the flag to stay off")
+		}
+	})
+}
+
+func TestFeatureFlags_Refresh(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expect the configured flags to seed the repository, and to be used until the first refresh", func(t *testing.T) {
+		repo := newMockFeatureFlagsRepository()
+		f := newFeatureFlags(map[string]bool{"users_me": true, "auth_token": false}, repo)
+		if !f.Enabled("users_me", "") || f.Enabled("auth_token", "") {
+			t.Fatal("expected the configured defaults before the first refresh")
+		}
+
+		if err := f.Refresh(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		stored, _ := repo.List(ctx)
+		if len(stored) != 2 || !stored[1].Enabled || stored[1].RolloutPercent != 100 {
+			t.Fatalf("expected the defaults to be seeded, got %+v", stored)
+		}
+	})
+
+	t.Run("expect the stored state to win over the configured one", func(t *testing.T) {
+		repo := newMockFeatureFlagsRepository()
+		repo.Set(ctx, &repos.FeatureFlag{Name: "users_me", Enabled: false, RolloutPercent: 100})
+		f := newFeatureFlags(map[string]bool{"users_me": true}, repo)
+
+		if err := f.Refresh(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		if f.Enabled("users_me", "1") {
+			t.Fatal("expected the flag turned off by an admin to stay off")
+		}
+		if stored, _ := repo.List(ctx); stored[0].Enabled {
+			t.Fatal("expected the seeding not to override the stored state")
+		}
+	})
+
+	t.Run("expect the changes of the other replicas to be picked up by the refresh", func(t *testing.T) {
+		repo := newMockFeatureFlagsRepository()
+		f := newFeatureFlags(map[string]bool{"users_me": false}, repo)
+		other := newFeatureFlags(map[string]bool{"users_me": false}, repo)
+		f.Refresh(ctx)
+
+		other.Set(ctx, &repos.FeatureFlag{Name: "users_me", Enabled: true, RolloutPercent: 100})
+		if !other.Enabled("users_me", "1") {
+			t.Fatal("expected the change to apply at once on its replica")
+		}
+		if f.Enabled("users_me", "1") {
+			t.Fatal("expected the other replica to keep its cache until refreshed")
+		}
+
+		if err := f.Refresh(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if !f.Enabled("users_me", "1") {
+			t.Fatal("expected the change to be picked up")
+		}
+	})
+
+	t.Run("expect the last state to be kept when the repository fails", func(t *testing.T) {
+		repo := newMockFeatureFlagsRepository()
+		f := newFeatureFlags(map[string]bool{"users_me": true}, repo)
+		repo.err = errors.New("connection refused")
+
+		if err := f.Refresh(ctx); err == nil {
+			t.Fatal("expected the error")
+		}
+		if !f.Enabled("users_me", "1") {
+			t.Fatal("expected the flag to keep its state")
+		}
+	})
+}
+
+func TestRolloutBucket(t *testing.T) {
+	t.Run("expect the bucket of a user to be stable", func(t *testing.T) {
+		if rolloutBucket("users_me", "42") != rolloutBucket("users_me", "42") {
+			t.Fatal("expected the same bucket")
+		}
+	})
+
+	t.Run("expect a 10% rollout to enable the flag for about 10% of the users", func(t *testing.T) {
+		f := newFeatureFlags(map[string]bool{"users_me": true}, newMockFeatureFlagsRepository())
+		f.Set(context.Background(), &repos.FeatureFlag{Name: "users_me", Enabled: true, RolloutPercent: 10})
+
+		enabled := 0
+		for i := 0; i < 10000; i++ {
+			if f.Enabled("users_me", strconv.Itoa(i)) {
+				enabled++
+			}
+		}
+		if enabled < 900 || enabled > 1100 {
+			t.Fatalf("expected about 1000 users out of 10000, got %d", enabled)
+		}
+		if f.Enabled("users_me", "") || !f.Available("users_me") {
+			t.Fatal("expected the anonymous users to be left out of a partial rollout")
+		}
+	})
+
+	t.Run("expect the users of a rollout to keep the flag as it grows", func(t *testing.T) {
+		f := newFeatureFlags(map[string]bool{"users_me": true}, newMockFeatureFlagsRepository())
+		f.Set(context.Background(), &repos.FeatureFlag{Name: "users_me", Enabled: true, RolloutPercent: 10})
+		var users []string
+		for i := 0; i < 1000; i++ {
+			if f.Enabled("users_me", strconv.Itoa(i)) {
+				users = append(users, strconv.Itoa(i))
+			}
+		}
+
+		f.Set(context.Background(), &repos.FeatureFlag{Name: "users_me", Enabled: true, RolloutPercent: 50})
+		for _, user := range users {
+			if !f.Enabled("users_me", user) {
+				t.Fatalf("expected user %s to keep the flag", user)
+			}
+		}
+	})
+
+	t.Run("expect the rollouts of different flags to pick different users", func(t *testing.T) {
+		same := 0
+		for i := 0; i < 1000; i++ {
+			if (rolloutBucket("users_me", strconv.Itoa(i)) < 10) == (rolloutBucket("auth_token", strconv.Itoa(i)) < 10) {
+				same++
+			}
+		}
+		if same == 1000 {
+			t.Fatal("expected the flags to spread their users differently")
+		}
+	})
+}
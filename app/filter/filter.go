@@ -0,0 +1,213 @@
+// Package filter parses the filter expressions of the list endpoints, such as
+// ?filter=picture:null,created_at>2024-01-01, into conditions on a whitelist of fields.
+//
+// An expression is a comma separated list of conditions, all of which must match. A condition
+// is a field, an operator and a value: ":" (equals), "!:" (differs), ">", ">=", "<", "<=" and
+// "~" (contains, case insensitive). The null value matches the fields without a value.
+// Values can't contain commas.
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxConditions is the maximum amount of conditions of an expression
+const MaxConditions = 10
+
+// Op is a comparison operator
+type Op string
+
+const (
+	Eq       Op = ":"
+	NotEq    Op = "!:"
+	Gt       Op = ">"
+	Gte      Op = ">="
+	Lt       Op = "<"
+	Lte      Op = "<="
+	Contains Op = "~"
+)
+
+// ops are the operators, the longest first so they're matched greedily
+var ops = []Op{NotEq, Gte, Lte, Eq, Gt, Lt, Contains}
+
+// Type is the type of the values of a field
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Time
+)
+
+// timeLayouts are the accepted layouts of the time values
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02"}
+
+// Field describes a filterable field: the column it's stored in, the type of its values and the
+// operators allowed on it. Only Nullable fields can be compared to null.
+type Field struct {
+	Column   string
+	Type     Type
+	Ops      []Op
+	Nullable bool
+}
+
+func (f Field) allows(op Op) bool {
+	for _, allowed := range f.Ops {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields is the whitelist of the filterable fields of an entity, by name
+type Fields map[string]Field
+
+func (f Fields) names() string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Condition is a validated condition of a filter. A nil Value stands for null.
+type Condition struct {
+	Field  string
+	Column string
+	Op     Op
+	Value  interface{}
+}
+
+// Filter is the list of conditions a row must match
+type Filter []Condition
+
+// Error explains why a condition was rejected
+type Error struct {
+	Condition string
+	Message   string
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Condition, e.Message)
+}
+
+// Errors are the problems of every rejected condition of an expression
+type Errors []Error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Parse parses an expression into the conditions on the given fields. The problems of the
+// rejected conditions are returned as Errors, which list what's allowed.
+func Parse(expr string, fields Fields) (Filter, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+
+	terms := strings.Split(expr, ",")
+	if len(terms) > MaxConditions {
+		return nil, Errors{{Condition: expr, Message: fmt.Sprintf("at most %d conditions are allowed", MaxConditions)}}
+	}
+
+	var filter Filter
+	var errs Errors
+	for _, term := range terms {
+		cond, err := parseCondition(strings.TrimSpace(term), fields)
+		if err != nil {
+			errs = append(errs, *err)
+			continue
+		}
+		filter = append(filter, cond)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return filter, nil
+}
+
+func parseCondition(term string, fields Fields) (Condition, *Error) {
+	fail := func(format string, args ...interface{}) (Condition, *Error) {
+		return Condition{}, &Error{Condition: term, Message: fmt.Sprintf(format, args...)}
+	}
+
+	name, op, raw, ok := splitCondition(term)
+	if !ok {
+		return fail("must be a field, an operator (%s) and a value", joinOps(ops))
+	}
+
+	field, ok := fields[name]
+	if !ok {
+		return fail("unknown field %q, allowed fields are %s", name, fields.names())
+	}
+	if !field.allows(op) {
+		return fail("operator %q is not allowed on %s, allowed operators are %s", op, name, joinOps(field.Ops))
+	}
+
+	cond := Condition{Field: name, Column: field.Column, Op: op}
+	if raw == "null" {
+		if !field.Nullable || (op != Eq && op != NotEq) {
+			return fail("%s can't be compared to null", name)
+		}
+		return cond, nil
+	}
+
+	switch field.Type {
+	case Int:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return fail("%s must be an integer", name)
+		}
+		cond.Value = value
+	case Time:
+		value, ok := parseTime(raw)
+		if !ok {
+			return fail("%s must be a RFC 3339 timestamp or a YYYY-MM-DD date", name)
+		}
+		cond.Value = value
+	default:
+		cond.Value = raw
+	}
+	return cond, nil
+}
+
+// splitCondition splits a condition at its first operator
+func splitCondition(term string) (string, Op, string, bool) {
+	for i := range term {
+		for _, op := range ops {
+			if strings.HasPrefix(term[i:], string(op)) {
+				name, value := term[:i], term[i+len(op):]
+				return name, op, value, name != "" && value != ""
+			}
+		}
+	}
+	return "", "", "", false
+}
+
+func parseTime(raw string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if value, err := time.Parse(layout, raw); err == nil {
+			return value, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func joinOps(ops []Op) string {
+	strs := make([]string, len(ops))
+	for i, op := range ops {
+		strs[i] = string(op)
+	}
+	return strings.Join(strs, " ")
+}
@@ -0,0 +1,82 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFields = Fields{
+	"name":       {Column: "name", Type: String, Ops: []Op{Eq, Contains}},
+	"picture":    {Column: "picture", Type: String, Ops: []Op{Eq, NotEq}, Nullable: true},
+	"beers":      {Column: "beers", Type: Int, Ops: []Op{Gt, Lte}},
+	"created_at": {Column: "created_at", Type: Time, Ops: []Op{Gt, Gte, Lt, Lte}},
+}
+
+func TestParse(t *testing.T) {
+	t.Run("expect an empty expression to be no filter", func(t *testing.T) {
+		f, err := Parse("", testFields)
+		if err != nil || f != nil {
+			t.Fatalf("expected no filter, got %v, %v", f, err)
+		}
+	})
+
+	t.Run("expect conditions to be parsed with typed values", func(t *testing.T) {
+		f, err := Parse("picture:null,created_at>=2024-01-01,beers>3,name~jo", testFields)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := Filter{
+			{Field: "picture", Column: "picture", Op: Eq, Value: nil},
+			{Field: "created_at", Column: "created_at", Op: Gte, Value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Field: "beers", Column: "beers", Op: Gt, Value: 3},
+			{Field: "name", Column: "name", Op: Contains, Value: "jo"},
+		}
+		if len(f) != len(expected) {
+			t.Fatalf("expected %d conditions, got %d", len(expected), len(f))
+		}
+		for i := range expected {
+			if f[i] != expected[i] {
+				t.Fatalf("expected %+v, got %+v", expected[i], f[i])
+			}
+		}
+	})
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected string
+	}{
+		{"expect an unknown field to list the allowed fields", "email:a@b.c", "allowed fields are beers, created_at, name, picture"},
+		{"expect a disallowed operator to list the allowed operators", "name>a", `operator ">" is not allowed on name, allowed operators are : ~`},
+		{"expect null on a field that isn't nullable to be rejected", "name:null", "name can't be compared to null"},
+		{"expect a malformed value to be rejected", "created_at>yesterday", "must be a RFC 3339 timestamp"},
+		{"expect a condition without a value to be rejected", "name:", "must be a field, an operator"},
+		{"expect too many conditions to be rejected", strings.Repeat("name:a,", MaxConditions) + "name:a", "at most"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.expr, testFields)
+
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected Errors, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("expected '%s' in '%s'", tt.expected, err)
+			}
+		})
+	}
+
+	t.Run("expect every rejected condition to be reported", func(t *testing.T) {
+		_, err := Parse("nope:1,name>a,name:ok", testFields)
+
+		var errs Errors
+		if !errors.As(err, &errs) || len(errs) != 2 {
+			t.Fatalf("expected 2 errors, got %v", err)
+		}
+	})
+}
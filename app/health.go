@@ -0,0 +1,145 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/config"
+	"appdoki-be/migrations"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// healthCheck is a readiness check of a single dependency. When a critical
+// check fails the application is reported as not ready.
+type healthCheck struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+// HealthStatus is the status of the server or of a dependency, the errors of the failed checks
+// being logged rather than exposed, for the hosts and driver messages not to leak
+type HealthStatus struct {
+	Status string `json:"status"`
+}
+
+type ReadinessResponse struct {
+	Status string                  `json:"status"`
+	Checks map[string]HealthStatus `json:"checks"`
+}
+
+// HealthHandler holds handler dependencies
+type HealthHandler struct {
+	checks []healthCheck
+}
+
+// NewHealthHandler returns an initialized health handler with the given readiness checks
+func NewHealthHandler(checks ...healthCheck) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+	}
+}
+
+// Live responds as long as the server is able to handle requests
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, r, HealthStatus{Status: "ok"}, http.StatusOK)
+}
+
+// Ready runs every readiness check and responds with each dependency's status,
+// or 503 if any critical dependency is unavailable, the status being degraded
+// when only non critical ones are
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+	defer cancel()
+
+	res := ReadinessResponse{
+		Status: "ok",
+		Checks: make(map[string]HealthStatus, len(h.checks)),
+	}
+	statusCode := http.StatusOK
+
+	for _, c := range h.checks {
+		if err := c.check(ctx); err != nil {
+			logging.FromContext(r.Context()).WithError(err).Warnf("readiness check %s failed", c.name)
+			res.Checks[c.name] = HealthStatus{Status: "unavailable"}
+			if c.critical {
+				res.Status = "unavailable"
+				statusCode = http.StatusServiceUnavailable
+			} else if res.Status == "ok" {
+				res.Status = "degraded"
+			}
+			continue
+		}
+		res.Checks[c.name] = HealthStatus{Status: "ok"}
+	}
+
+	respondJSON(w, r, res, statusCode)
+}
+
+// databaseCheck pings the database
+func databaseCheck(db pinger) healthCheck {
+	return healthCheck{
+		name:     "database",
+		critical: true,
+		check: func(ctx context.Context) error {
+			if db == nil {
+				return errors.New("database not configured")
+			}
+			return db.PingContext(ctx)
+		},
+	}
+}
+
+// schemaCheck fails while the schema of the database is behind the last migration, or dirty, for the
+// instance not to be served by a schema it doesn't expect
+func schemaCheck(db *sql.DB, migrationsSource string) healthCheck {
+	return healthCheck{
+		name:     "schema",
+		critical: true,
+		check: func(ctx context.Context) error {
+			if db == nil {
+				return errors.New("database not configured")
+			}
+			return migrations.CheckSchema(ctx, db, migrationsSource)
+		},
+	}
+}
+
+// shutdownCheck fails as soon as the application starts shutting down,
+// so the load balancer stops routing traffic to it
+func shutdownCheck(isShuttingDown func() bool) healthCheck {
+	return healthCheck{
+		name:     "server",
+		critical: true,
+		check: func(_ context.Context) error {
+			if isShuttingDown() {
+				return errors.New("shutting down")
+			}
+			return nil
+		},
+	}
+}
+
+// oidcCheck verifies the OIDC provider discovery document was loaded, only critical when it's
+// discovered eagerly: discovered lazily, the routes which don't verify tokens are served meanwhile
+func oidcCheck(provider *config.OIDCProvider, critical bool) healthCheck {
+	return healthCheck{
+		name:     "oidc",
+		critical: critical,
+		check: func(_ context.Context) error {
+			if err := provider.Err(); err != nil {
+				return fmt.Errorf("OIDC provider discovery not loaded: %w", err)
+			}
+			return nil
+		},
+	}
+}
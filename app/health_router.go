@@ -0,0 +1,31 @@
+package app
+
+import (
+	"appdoki-be/config"
+	"database/sql"
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func (a *Application) HealthRouter(router *mux.Router) {
+	var db pinger
+	var sqlDB *sql.DB
+	if a.db != nil {
+		db, sqlDB = a.db, a.db.DB
+	}
+
+	healthHandler := NewHealthHandler(
+		shutdownCheck(a.isShuttingDown),
+		databaseCheck(db),
+		schemaCheck(sqlDB, a.conf.Database.MigrationsDir),
+		oidcCheck(a.conf.AppConfig.OIDCProvider, a.conf.AppConfig.OIDCDiscovery != config.OIDCDiscoveryLazy),
+		maintenanceCheck(a.maintenance, a.conf.Maintenance.FailReadiness),
+	)
+
+	a.mount(router,
+		routeDef{methods: []string{http.MethodGet}, path: "/healthz", access: publicAccess,
+			handler: http.HandlerFunc(healthHandler.Live)},
+		routeDef{methods: []string{http.MethodGet}, path: "/readyz", access: publicAccess,
+			handler: http.HandlerFunc(healthHandler.Ready)},
+	)
+}
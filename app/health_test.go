@@ -0,0 +1,138 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakePinger struct {
+	err error
+}
+
+func (p *fakePinger) PingContext(_ context.Context) error {
+	return p.err
+}
+
+func readinessResponse(t *testing.T, resp *http.Response) ReadinessResponse {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal("failed to read response body")
+	}
+
+	var res ReadinessResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		t.Fatal("failed to parse response body")
+	}
+	return res
+}
+
+func TestHealthHandler_Live(t *testing.T) {
+	t.Run("expect GET /healthz to return 200", func(t *testing.T) {
+		h := NewHealthHandler(databaseCheck(&fakePinger{err: errors.New("connection refused")}))
+		router := prepareRouter(http.MethodGet, "/healthz", h.Live)
+
+		r := httptest.NewRequest("GET", "/healthz", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+	})
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	t.Run("expect GET /readyz to return 200 when dependencies are up", func(t *testing.T) {
+		h := NewHealthHandler(databaseCheck(&fakePinger{}))
+		router := prepareRouter(http.MethodGet, "/readyz", h.Ready)
+
+		r := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+
+		res := readinessResponse(t, resp)
+		if res.Checks["database"].Status != "ok" {
+			t.Fatalf("expected database status 'ok', got '%s'", res.Checks["database"].Status)
+		}
+	})
+
+	t.Run("expect GET /readyz to return 503 when the database is down", func(t *testing.T) {
+		h := NewHealthHandler(
+			databaseCheck(&fakePinger{err: errors.New("connection refused")}),
+			oidcCheck(nil, true),
+		)
+		router := prepareRouter(http.MethodGet, "/readyz", h.Ready)
+
+		r := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusServiceUnavailable)
+		assertJSONContentType(t, resp)
+
+		res := readinessResponse(t, resp)
+		if res.Checks["database"].Status != "unavailable" {
+			t.Fatalf("expected database status 'unavailable', got '%s'", res.Checks["database"].Status)
+		}
+		if res.Checks["oidc"].Status != "unavailable" {
+			t.Fatalf("expected oidc status 'unavailable', got '%s'", res.Checks["oidc"].Status)
+		}
+	})
+
+	t.Run("expect the errors of the checks to be logged, not exposed", func(t *testing.T) {
+		h := NewHealthHandler(databaseCheck(&fakePinger{err: errors.New("dial tcp db.internal:5432: connection refused")}))
+		router := prepareRouter(http.MethodGet, "/readyz", h.Ready)
+		var buf bytes.Buffer
+		logger := log.New()
+		logger.SetOutput(&buf)
+
+		r := httptest.NewRequest("GET", "/readyz", nil)
+		r = r.WithContext(logging.WithLogger(r.Context(), logger))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusServiceUnavailable)
+		if body := w.Body.String(); strings.Contains(body, "db.internal") || strings.Contains(body, "refused") {
+			t.Fatalf("expected the error not to be exposed, got %s", body)
+		}
+		if !strings.Contains(buf.String(), "db.internal:5432: connection refused") {
+			t.Fatalf("expected the error to be logged, got %s", buf.String())
+		}
+	})
+
+	t.Run("expect GET /readyz to return 200 when only a non critical dependency is down", func(t *testing.T) {
+		h := NewHealthHandler(
+			databaseCheck(&fakePinger{}),
+			healthCheck{
+				name: "optional",
+				check: func(_ context.Context) error {
+					return errors.New("down")
+				},
+			},
+		)
+		router := prepareRouter(http.MethodGet, "/readyz", h.Ready)
+
+		r := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusOK)
+	})
+}
@@ -0,0 +1,119 @@
+// Package i18n translates the error messages of the API. The messages are written in English
+// in the code, their translations live in one embedded file per locale, by error code:
+//
+//	{"not_found": {"user not found": "utilizador não encontrado"}}
+//
+// Messages may hold {name} placeholders, filled in with parameters once translated.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fallback is the locale the messages are written in, used for the missing translations
+const Fallback = "en"
+
+//go:embed locales/*.json
+var files embed.FS
+
+// catalog holds the translations by locale, error code and English message
+var catalog = mustLoad()
+
+func mustLoad() map[string]map[string]map[string]string {
+	entries, err := files.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	c := map[string]map[string]map[string]string{Fallback: {}}
+	for _, entry := range entries {
+		data, err := files.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var messages map[string]map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Errorf("invalid locale file %s: %w", entry.Name(), err))
+		}
+		c[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	return c
+}
+
+// Supported checks if there are translations for the locale
+func Supported(locale string) bool {
+	_, ok := catalog[locale]
+	return ok
+}
+
+// Locales returns the supported locales, sorted
+func Locales() []string {
+	locales := make([]string, 0, len(catalog))
+	for locale := range catalog {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Match returns the supported locale the client prefers according to its Accept-Language header,
+// ex.: "pt-PT,pt;q=0.9,en;q=0.8". A region falls back to its language, def is used when nothing matches.
+func Match(acceptLanguage string, def string) string {
+	type preference struct {
+		tag string
+		q   float64
+	}
+
+	var prefs []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if value, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = value
+				}
+			}
+		}
+		if q > 0 {
+			prefs = append(prefs, preference{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	for _, pref := range prefs {
+		if pref.tag == "*" {
+			return def
+		}
+		if Supported(pref.tag) {
+			return pref.tag
+		}
+		if base := strings.SplitN(pref.tag, "-", 2)[0]; Supported(base) {
+			return base
+		}
+	}
+	return def
+}
+
+// Translate renders the message of the error code in the locale, filling in its placeholders.
+// The English message is rendered when there's no translation for it.
+func Translate(locale string, code string, message string, params map[string]string) string {
+	if translated, ok := catalog[locale][code][message]; ok {
+		message = translated
+	}
+	for name, value := range params {
+		message = strings.ReplaceAll(message, "{"+name+"}", value)
+	}
+	return message
+}
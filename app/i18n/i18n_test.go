@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		expected       string
+	}{
+		{"", "en"},
+		{"pt", "pt"},
+		{"pt-PT,pt;q=0.9,en;q=0.8", "pt"},
+		{"PT-br", "pt"},
+		{"en-US,en;q=0.9,pt;q=0.8", "en"},
+		{"fr-FR,fr;q=0.9,pt;q=0.5", "pt"},
+		{"en;q=0.5,pt;q=0.8", "pt"},
+		{"pt;q=0,en", "en"},
+		{"fr", "en"},
+		{"*", "en"},
+	}
+
+	for _, test := range tests {
+		t.Run("expect '"+test.acceptLanguage+"' to match "+test.expected, func(t *testing.T) {
+			if locale := Match(test.acceptLanguage, "en"); locale != test.expected {
+				t.Fatalf("expected '%s', got '%s'", test.expected, locale)
+			}
+		})
+	}
+
+	t.Run("expect the default when nothing is supported", func(t *testing.T) {
+		if locale := Match("fr", "pt"); locale != "pt" {
+			t.Fatalf("expected 'pt', got '%s'", locale)
+		}
+	})
+}
+
+func TestTranslate(t *testing.T) {
+	t.Run("expect the message to be translated with its parameters", func(t *testing.T) {
+		message := Translate("pt", "validation_failed", "field \"{field}\" must not hold more than {max} IDs",
+			map[string]string{"field": "ids", "max": "100"})
+
+		if message != "o campo \"ids\" não pode ter mais de 100 IDs" {
+			t.Fatalf("unexpected message '%s'", message)
+		}
+	})
+
+	t.Run("expect English when the translation is missing", func(t *testing.T) {
+		message := Translate("pt", "not_found", "beer not found", nil)
+
+		if message != "beer not found" {
+			t.Fatalf("expected the English message, got '%s'", message)
+		}
+	})
+
+	t.Run("expect English for an unsupported locale", func(t *testing.T) {
+		message := Translate("fr", "validation_failed", "field \"{field}\" is required", map[string]string{"field": "enabled"})
+
+		if message != "field \"enabled\" is required" {
+			t.Fatalf("expected the English message, got '%s'", message)
+		}
+	})
+}
+
+func TestCatalog(t *testing.T) {
+	placeholder := regexp.MustCompile(`\{\w+\}`)
+	placeholders := func(message string) string {
+		found := placeholder.FindAllString(message, -1)
+		sort.Strings(found)
+		return strings.Join(found, ",")
+	}
+
+	t.Run("expect the translations to keep the placeholders of their messages", func(t *testing.T) {
+		for locale, codes := range catalog {
+			for code, messages := range codes {
+				for message, translated := range messages {
+					if placeholders(message) != placeholders(translated) {
+						t.Errorf("%s/%s: '%s' doesn't have the placeholders of '%s'", locale, code, translated, message)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("expect English and Portuguese to be supported", func(t *testing.T) {
+		if locales := strings.Join(Locales(), ","); locales != "en,pt" {
+			t.Fatalf("expected 'en,pt', got '%s'", locales)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	repos "appdoki-be/app/repositories"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	idempotencyStoreTimeout  = 5 * time.Second
+)
+
+// Idempotent handles a request once per Idempotency-Key header and replays the stored response
+// when the request is retried with the same key. Keys are scoped by user, so it runs after
+// JwtVerify: a.JwtVerify(a.Idempotent(handler)). Requests without the header are handled as usual.
+func (a *Application) Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		userID, _ := r.Context().Value("userID").(string)
+		if key == "" || userID == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondError(w, http.StatusBadRequest, ErrCodeBadRequest, "Idempotency-Key must not exceed 255 characters", nil)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			respondRequestError(w, decodeError(err))
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		hash := requestHash(r, body)
+
+		record, err := a.idempotencyRepository.Reserve(r.Context(), userID, key, hash, a.conf.Idempotency.TTL)
+		if err != nil {
+			respondRepositoryError(w, err)
+			return
+		}
+		if record != nil {
+			replayIdempotentResponse(w, record, hash)
+			return
+		}
+
+		rec := &idempotencyRecorder{ResponseWriter: w}
+		handled := false
+		defer func() {
+			a.storeIdempotentResponse(r.Context(), userID, key, rec, handled)
+		}()
+
+		next(rec, r)
+		handled = true
+	}
+}
+
+// storeIdempotentResponse stores the response to replay for the key. Keys of the requests
+// which failed on the server side are released instead, so they can be retried.
+func (a *Application) storeIdempotentResponse(ctx context.Context, userID string, key string, rec *idempotencyRecorder, handled bool) {
+	ctx, cancel := context.WithTimeout(logging.Detach(ctx), idempotencyStoreTimeout)
+	defer cancel()
+
+	var err error
+	if !handled || rec.status >= http.StatusInternalServerError {
+		err = a.idempotencyRepository.Release(ctx, userID, key)
+	} else {
+		err = a.idempotencyRepository.Complete(ctx, userID, key, rec.Status(), rec.Header().Get("Content-Type"), rec.body.Bytes())
+	}
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("could not store the idempotent response")
+	}
+}
+
+// replayIdempotentResponse responds with the stored response of the request that used the key first,
+// or with a conflict if that request was a different one or is still being handled
+func replayIdempotentResponse(w http.ResponseWriter, record *repos.IdempotencyRecord, hash string) {
+	if record.RequestHash != hash {
+		respondError(w, http.StatusConflict, ErrCodeIdempotencyKeyReused,
+			"Idempotency-Key was already used for a different request", nil)
+		return
+	}
+	if record.Status == 0 {
+		respondError(w, http.StatusConflict, ErrCodeRequestInProgress,
+			"a request with this Idempotency-Key is still being handled", nil)
+		return
+	}
+
+	w.Header().Set(idempotentReplayedHeader, "true")
+	setHeader(w, "Content-Type", record.ContentType)
+	w.WriteHeader(record.Status)
+	w.Write(record.Body)
+}
+
+// requestHash identifies a request by its method, path and body
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyRecorder keeps a copy of the response written to the client
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *idempotencyRecorder) WriteHeader(code int) {
+	if rec.status == 0 {
+		rec.status = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *idempotencyRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+func (rec *idempotencyRecorder) Flush() {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	flush(rec.ResponseWriter)
+}
+
+func (rec *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Status returns the status of the response, 200 when the handler didn't set one
+func (rec *idempotencyRecorder) Status() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"sync"
+	"time"
+)
+
+// mockIdempotencyRepository keeps the idempotency keys in memory, with the
+// same reservation semantics as the database unique insert
+type mockIdempotencyRepository struct {
+	mu      sync.Mutex
+	records map[string]*repos.IdempotencyRecord
+}
+
+func newMockIdempotencyRepository() *mockIdempotencyRepository {
+	return &mockIdempotencyRepository{records: map[string]*repos.IdempotencyRecord{}}
+}
+
+func (r *mockIdempotencyRepository) Reserve(_ context.Context, userID string, key string, requestHash string, _ time.Duration) (*repos.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if record, ok := r.records[userID+":"+key]; ok {
+		stored := *record
+		return &stored, nil
+	}
+	r.records[userID+":"+key] = &repos.IdempotencyRecord{RequestHash: requestHash}
+	return nil, nil
+}
+
+func (r *mockIdempotencyRepository) Complete(_ context.Context, userID string, key string, status int, contentType string, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record := r.records[userID+":"+key]
+	record.Status, record.ContentType, record.Body = status, contentType, body
+	return nil
+}
+
+func (r *mockIdempotencyRepository) Release(_ context.Context, userID string, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.records, userID+":"+key)
+	return nil
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestApplication_Idempotent(t *testing.T) {
+	serve := func(h http.Handler, key string, body string) *http.Response {
+		r := httptest.NewRequest("POST", "/users/2/beers/3", strings.NewReader(body))
+		if key != "" {
+			r.Header.Set(idempotencyKeyHeader, key)
+		}
+		r = r.WithContext(context.WithValue(r.Context(), "userID", "1"))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	newHandler := func(status int) (http.Handler, *int32) {
+		var calls int32
+		a := newTestApplication()
+		return a.Idempotent(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			respondJSON(w, r, map[string]int32{"call": atomic.LoadInt32(&calls)}, status)
+		}), &calls
+	}
+
+	t.Run("expect a retried request to replay the stored response", func(t *testing.T) {
+		h, calls := newHandler(http.StatusCreated)
+
+		first := serve(h, "key-1", `{"beers":3}`)
+		retry := serve(h, "key-1", `{"beers":3}`)
+
+		assertStatusCode(t, first, http.StatusCreated)
+		assertStatusCode(t, retry, http.StatusCreated)
+		assertJSONContentType(t, retry)
+		if *calls != 1 {
+			t.Fatalf("expected the handler to be called once, got %d", *calls)
+		}
+		if retry.Header.Get(idempotentReplayedHeader) != "true" {
+			t.Fatalf("expected the %s header", idempotentReplayedHeader)
+		}
+	})
+
+	t.Run("expect requests without a key to be handled every time", func(t *testing.T) {
+		h, calls := newHandler(http.StatusCreated)
+
+		serve(h, "", `{}`)
+		serve(h, "", `{}`)
+
+		if *calls != 2 {
+			t.Fatalf("expected the handler to be called twice, got %d", *calls)
+		}
+	})
+
+	t.Run("expect a key reused with a different body to return 409", func(t *testing.T) {
+		h, calls := newHandler(http.StatusCreated)
+
+		serve(h, "key-1", `{"beers":3}`)
+		resp := serve(h, "key-1", `{"beers":4}`)
+
+		assertStatusCode(t, resp, http.StatusConflict)
+		assertErrorCode(t, resp, ErrCodeIdempotencyKeyReused)
+		if *calls != 1 {
+			t.Fatalf("expected the handler to be called once, got %d", *calls)
+		}
+	})
+
+	t.Run("expect a duplicate of an in-flight request not to be handled", func(t *testing.T) {
+		var calls int32
+		release := make(chan struct{})
+		started := make(chan struct{})
+		h := newTestApplication().Idempotent(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			close(started)
+			<-release
+			respondNoContent(w, http.StatusNoContent)
+		})
+
+		done := make(chan *http.Response)
+		go func() { done <- serve(h, "key-1", `{}`) }()
+		<-started
+
+		resp := serve(h, "key-1", `{}`)
+		close(release)
+
+		assertStatusCode(t, resp, http.StatusConflict)
+		assertErrorCode(t, resp, ErrCodeRequestInProgress)
+		assertStatusCode(t, <-done, http.StatusNoContent)
+		if calls != 1 {
+			t.Fatalf("expected the handler to be called once, got %d", calls)
+		}
+	})
+
+	t.Run("expect a server error to release the key", func(t *testing.T) {
+		h, calls := newHandler(http.StatusInternalServerError)
+
+		serve(h, "key-1", `{}`)
+		serve(h, "key-1", `{}`)
+
+		if *calls != 2 {
+			t.Fatalf("expected the handler to be called twice, got %d", *calls)
+		}
+	})
+
+	t.Run("expect a key longer than 255 characters to return 400", func(t *testing.T) {
+		h, _ := newHandler(http.StatusCreated)
+
+		resp := serve(h, strings.Repeat("k", 256), `{}`)
+
+		assertStatusCode(t, resp, http.StatusBadRequest)
+	})
+}
+
+func TestApplication_Routes_Idempotency(t *testing.T) {
+	a := newTestApplication()
+	var transfers int32
+	a.usersRepository.(*mockUsersRepository).addBeerTransferImpl = func(_ context.Context, _ string, _ string, _ int) (int, error) {
+		return int(atomic.AddInt32(&transfers, 1)), nil
+	}
+	routes := a.Routes()
+
+	t.Run("expect beers given twice with the same key to be given once", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			r := httptest.NewRequest("POST", "/api/v1/users/2/beers/3", nil)
+			r.Header.Set(idempotencyKeyHeader, "give-beers-1")
+			w := httptest.NewRecorder()
+			routes.ServeHTTP(w, r)
+
+			assertStatusCode(t, w.Result(), http.StatusNoContent)
+		}
+
+		if transfers != 1 {
+			t.Fatalf("expected 1 beer transfer, got %d", transfers)
+		}
+	})
+}
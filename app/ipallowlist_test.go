@@ -0,0 +1,97 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseNetworks(t *testing.T) {
+	t.Run("expect CIDR ranges and single IPs to be parsed", func(t *testing.T) {
+		networks, err := parseNetworks([]string{"10.8.0.0/16", " 192.0.2.7", "2001:db8::/32"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(networks) != 3 {
+			t.Fatalf("expected 3 networks, got %d", len(networks))
+		}
+		if networks[1].String() != "192.0.2.7/32" {
+			t.Fatalf("expected 192.0.2.7/32, got %s", networks[1])
+		}
+	})
+
+	t.Run("expect an invalid range to fail", func(t *testing.T) {
+		for _, value := range []string{"10.8.0.0/33", "office"} {
+			if _, err := parseNetworks([]string{value}); err == nil {
+				t.Fatalf("expected '%s' to fail", value)
+			}
+		}
+	})
+}
+
+func TestIPAllowlistMiddleware(t *testing.T) {
+	allowed, _ := parseNetworks([]string{"10.8.0.0/16"})
+	trustedProxies, _ := parseNetworks([]string{"172.16.0.1"})
+	h := ipAllowlistMiddleware(allowed, trustedProxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondNoContent(w, http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		expectedCode int
+	}{
+		{"expect a client within the allowed networks to pass", "10.8.1.2:5000", "", http.StatusNoContent},
+		{"expect a client outside the allowed networks to get 403", "192.0.2.1:5000", "", http.StatusForbidden},
+		{"expect the forwarded client of a trusted proxy to pass", "172.16.0.1:5000", "10.8.1.2", http.StatusNoContent},
+		{"expect the forwarded client of a trusted proxy to be checked", "172.16.0.1:5000", "192.0.2.1", http.StatusForbidden},
+		{"expect the right-most untrusted address to be the client", "172.16.0.1:5000", "10.8.1.2, 192.0.2.1", http.StatusForbidden},
+		{"expect X-Forwarded-For from an untrusted peer to be ignored", "192.0.2.1:5000", "10.8.1.2", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/admin/maintenance", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assertStatusCode(t, w.Result(), tt.expectedCode)
+		})
+	}
+}
+
+func TestApplication_AdminRouter_Allowlist(t *testing.T) {
+	a := newTestApplication()
+	a.adminNetworks, _ = parseNetworks([]string{"10.8.0.0/16"})
+	a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+		user := generateRandomUserMockWithID(ID)
+		user.Role = repos.RoleAdmin
+		return user, nil
+	}
+	routes := a.Routes()
+
+	t.Run("expect admins outside the allowed networks to get 403", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/admin/maintenance", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusForbidden)
+		assertErrorCode(t, w.Result(), ErrCodeForbidden)
+	})
+
+	t.Run("expect admins within the allowed networks to pass", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/admin/maintenance", nil)
+		r.RemoteAddr = "10.8.1.2:5000"
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusOK)
+	})
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// mockJobRunsRepository keeps the claimed runs in memory, by job and schedule
+type mockJobRunsRepository struct {
+	mu       sync.Mutex
+	runs     map[string]map[time.Time]bool
+	finished map[string]map[time.Time]error
+}
+
+func newMockJobRunsRepository() *mockJobRunsRepository {
+	return &mockJobRunsRepository{runs: map[string]map[time.Time]bool{}, finished: map[string]map[time.Time]error{}}
+}
+
+func (r *mockJobRunsRepository) Claim(_ context.Context, name string, scheduledAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.runs[name] == nil {
+		r.runs[name] = map[time.Time]bool{}
+	}
+	if r.runs[name][scheduledAt.UTC()] {
+		return false, nil
+	}
+	r.runs[name][scheduledAt.UTC()] = true
+	return true, nil
+}
+
+func (r *mockJobRunsRepository) Finish(_ context.Context, name string, scheduledAt time.Time, runErr error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.finished[name] == nil {
+		r.finished[name] = map[time.Time]error{}
+	}
+	r.finished[name][scheduledAt.UTC()] = runErr
+	return nil
+}
+
+func (r *mockJobRunsRepository) LastScheduledBefore(_ context.Context, name string, before time.Time) (*time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var last *time.Time
+	for scheduledAt := range r.runs[name] {
+		if scheduledAt.Before(before) && (last == nil || scheduledAt.After(*last)) {
+			at := scheduledAt
+			last = &at
+		}
+	}
+	return last, nil
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"appdoki-be/app/i18n"
+	"net/http"
+)
+
+const contentLanguageHeader = "Content-Language"
+
+// localeMiddleware resolves the locale the client prefers from its Accept-Language header,
+// def when none is supported, and announces it in the Content-Language response header.
+// respondError reads it back from there to translate the error messages.
+func localeMiddleware(def string) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(contentLanguageHeader, i18n.Match(r.Header.Get("Accept-Language"), def))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// responseLocale returns the locale resolved for the response, English when there's none
+func responseLocale(w http.ResponseWriter) string {
+	if locale := w.Header().Get(contentLanguageHeader); locale != "" {
+		return locale
+	}
+	return i18n.Fallback
+}
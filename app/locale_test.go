@@ -0,0 +1,139 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	serve := func(a *Application, method string, path string, body string, acceptLanguage string) (*http.Response, errorEnvelope) {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		if acceptLanguage != "" {
+			r.Header.Set("Accept-Language", acceptLanguage)
+		}
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+
+		resp := w.Result()
+		var envelope errorEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatal(err)
+		}
+		return resp, envelope
+	}
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		locale         string
+		message        string
+	}{
+		{"English by default", "", "en", "resource not found"},
+		{"Portuguese when preferred", "pt-PT,pt;q=0.9,en;q=0.8", "pt", "recurso não encontrado"},
+		{"English when preferred", "en-GB,pt;q=0.5", "en", "resource not found"},
+	}
+	for _, test := range tests {
+		t.Run("expect errors in "+test.name, func(t *testing.T) {
+			resp, envelope := serve(newTestApplication(), "GET", "/api/v1/nowhere", "", test.acceptLanguage)
+
+			assertStatusCode(t, resp, http.StatusNotFound)
+			if envelope.Error.Code != ErrCodeNotFound {
+				t.Fatalf("expected the code to stay '%s', got '%s'", ErrCodeNotFound, envelope.Error.Code)
+			}
+			if envelope.Error.Message != test.message {
+				t.Fatalf("expected '%s', got '%s'", test.message, envelope.Error.Message)
+			}
+			if locale := resp.Header.Get("Content-Language"); locale != test.locale {
+				t.Fatalf("expected Content-Language '%s', got '%s'", test.locale, locale)
+			}
+			if !strings.Contains(strings.Join(resp.Header.Values("Vary"), ","), "Accept-Language") {
+				t.Fatal("expected the error to vary on Accept-Language")
+			}
+		})
+	}
+
+	t.Run("expect the configured default locale for unsupported languages", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.I18n.DefaultLocale = "pt"
+
+		_, envelope := serve(a, "GET", "/api/v1/nowhere", "", "fr")
+
+		if envelope.Error.Message != "recurso não encontrado" {
+			t.Fatalf("expected the Portuguese message, got '%s'", envelope.Error.Message)
+		}
+	})
+
+	admin := func() *Application {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		return a
+	}
+	validations := []struct {
+		name    string
+		body    string
+		english string
+		pt      string
+	}{
+		{
+			"a field type",
+			`{"enabled":"yes"}`,
+			`field "enabled" must be of type bool`,
+			`o campo "enabled" deve ser do tipo bool`,
+		},
+		{
+			"a required field",
+			`{}`,
+			`field "enabled" is required`,
+			`o campo "enabled" é obrigatório`,
+		},
+		{
+			"an unknown field",
+			`{"enabled":true,"until":"tomorrow"}`,
+			`unknown field "until"`,
+			`campo desconhecido "until"`,
+		},
+	}
+	for _, v := range validations {
+		t.Run("expect the validation of "+v.name+" to be translated with its parameters", func(t *testing.T) {
+			resp, envelope := serve(admin(), "PUT", "/admin/features/users_me", v.body, "")
+			assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+			if envelope.Error.Code != ErrCodeValidationFailed || envelope.Error.Message != v.english {
+				t.Fatalf("expected '%s', got %+v", v.english, envelope.Error)
+			}
+
+			resp, envelope = serve(admin(), "PUT", "/admin/features/users_me", v.body, "pt")
+			assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+			if envelope.Error.Code != ErrCodeValidationFailed || envelope.Error.Message != v.pt {
+				t.Fatalf("expected '%s', got %+v", v.pt, envelope.Error)
+			}
+		})
+	}
+
+	t.Run("expect a limit to be translated with its value", func(t *testing.T) {
+		ids := make([]string, maxBulkUsers+1)
+		for i := range ids {
+			ids[i] = strconv.Quote(strconv.Itoa(i + 2))
+		}
+		body := `{"action":"role","ids":[` + strings.Join(ids, ",") + `],"params":{"role":"user"}}`
+
+		_, envelope := serve(admin(), "POST", "/admin/users/bulk", body, "")
+		if envelope.Error.Message != `field "ids" must not hold more than 100 IDs` {
+			t.Fatalf("unexpected message '%s'", envelope.Error.Message)
+		}
+
+		_, envelope = serve(admin(), "POST", "/admin/users/bulk", body, "pt")
+		if envelope.Error.Message != `o campo "ids" não pode ter mais de 100 IDs` {
+			t.Fatalf("unexpected message '%s'", envelope.Error.Message)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/repositories"
+	"context"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const maxLogLevelPayloadBytes = 1 << 10
+
+// logLevels are the levels the logger may be set to at runtime, in the order SIGHUP cycles through
+var logLevels = []log.Level{log.DebugLevel, log.InfoLevel, log.WarnLevel, log.ErrorLevel}
+
+// logLevel is the runtime level of the logger shared by every component, safe for concurrent use
+type logLevel struct {
+	logger *log.Logger
+}
+
+// newLogLevel sets the level of logger from its configured name, keeping the current one when
+// there is none
+func newLogLevel(logger *log.Logger, name string) *logLevel {
+	l := &logLevel{logger: logger}
+	if level, err := log.ParseLevel(name); err == nil {
+		l.Set(level)
+	}
+	return l
+}
+
+// Level returns the level the logger logs from
+func (l *logLevel) Level() log.Level {
+	return l.logger.GetLevel()
+}
+
+// Set changes the level of the logger, for every component at once
+func (l *logLevel) Set(level log.Level) {
+	l.logger.SetLevel(level)
+}
+
+// Cycle sets the logger to the next of logLevels, back to the first after the last one
+func (l *logLevel) Cycle() log.Level {
+	next := logLevels[0]
+	for i, level := range logLevels {
+		if level == l.Level() && i+1 < len(logLevels) {
+			next = logLevels[i+1]
+		}
+	}
+	l.Set(next)
+	return next
+}
+
+// cycleLogLevel cycles the log level on every signal received, SIGHUP, until ctx is done
+func (a *Application) cycleLogLevel(ctx context.Context, signals <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			level := a.logLevel.Cycle()
+			a.logger.Warnf("log level set to %s on SIGHUP", level)
+		}
+	}
+}
+
+type LogLevelPayload struct {
+	Level string `json:"level"`
+}
+
+type LogLevelStatus struct {
+	Level string `json:"level"`
+}
+
+// SetLogLevel changes the level of the logs, without a restart, until the next one
+func (a *Application) SetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var payload LogLevelPayload
+	if err := decodeJSON(r, &payload, maxLogLevelPayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	level, ok := parseLogLevel(payload.Level)
+	if !ok {
+		names := make([]string, 0, len(logLevels))
+		for _, level := range logLevels {
+			names = append(names, level.String())
+		}
+		respondErrorWithParams(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed,
+			"field \"{field}\" must be one of {values}", map[string]string{"field": "level", "values": strings.Join(names, ", ")}, nil)
+		return
+	}
+
+	previous := a.logLevel.Level()
+	a.logLevel.Set(level)
+	actorID, _ := r.Context().Value("userID").(string)
+	logging.FromContext(r.Context()).
+		WithField("user_id", actorID).
+		Warnf("log level set to %s", level)
+	a.auditLogLevel(r.Context(), actorID, previous, level)
+
+	respondJSON(w, r, LogLevelStatus{Level: level.String()}, http.StatusOK)
+}
+
+// parseLogLevel finds the level among logLevels by its name
+func parseLogLevel(name string) (log.Level, bool) {
+	level, err := log.ParseLevel(name)
+	if err != nil {
+		return 0, false
+	}
+	for _, allowed := range logLevels {
+		if level == allowed {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// auditLogLevel records the change of the log level, its failure being logged rather than blocking it
+func (a *Application) auditLogLevel(ctx context.Context, actorID string, from log.Level, to log.Level) {
+	entry := &repositories.AuditEntry{
+		ActorID:  actorID,
+		Action:   repositories.AuditLogLevelChanged,
+		TargetID: "logger",
+		Details:  map[string]interface{}{"from": from.String(), "to": to.String()},
+	}
+	auditCtx, cancel := context.WithTimeout(logging.Detach(ctx), auditRecordTimeout)
+	defer cancel()
+	if err := a.auditRepository.Record(auditCtx, []*repositories.AuditEntry{entry}); err != nil {
+		logging.FromContext(ctx).
+			WithError(err).
+			WithField("user_id", actorID).
+			Error("could not record the audit entry of a log level change")
+	}
+}
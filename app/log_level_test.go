@@ -0,0 +1,111 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	repos "appdoki-be/app/repositories"
+	"bytes"
+	"context"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestApplication_LogLevel(t *testing.T) {
+	// captureLogs sends the logs of the shared logger to a buffer, restoring it and the info level after the test
+	captureLogs := func(t *testing.T) *bytes.Buffer {
+		var buf bytes.Buffer
+		logger := log.StandardLogger()
+		out := logger.Out
+		logger.SetOutput(&buf)
+		t.Cleanup(func() {
+			logger.SetOutput(out)
+			logger.SetLevel(log.InfoLevel)
+		})
+		return &buf
+	}
+	newAdminApplication := func() *Application {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		return a
+	}
+	setLevel := func(a *Application, level string) *http.Response {
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, httptest.NewRequest("PUT", "/admin/log-level", strings.NewReader(`{"level":"`+level+`"}`)))
+		return w.Result()
+	}
+
+	t.Run("expect the debug lines to appear and disappear with the level set", func(t *testing.T) {
+		buf := captureLogs(t)
+		a := newAdminApplication()
+		ctx := context.Background()
+
+		assertStatusCode(t, setLevel(a, "debug"), http.StatusOK)
+		logging.FromContext(ctx).Debug("first debug line")
+		assertStatusCode(t, setLevel(a, "info"), http.StatusOK)
+		logging.FromContext(ctx).Debug("second debug line")
+
+		if !strings.Contains(buf.String(), "first debug line") {
+			t.Fatal("expected the debug line to be logged at the debug level")
+		}
+		if strings.Contains(buf.String(), "second debug line") {
+			t.Fatal("expected the debug line to be left out at the info level")
+		}
+		entries := a.auditRepository.(*mockAuditRepository).entries
+		if len(entries) != 2 || entries[0].Action != repos.AuditLogLevelChanged || entries[0].Details["to"] != "debug" {
+			t.Fatalf("expected the changes to be audited, got %+v", entries)
+		}
+	})
+
+	t.Run("expect an unknown level to be rejected", func(t *testing.T) {
+		captureLogs(t)
+		a := newAdminApplication()
+
+		assertStatusCode(t, setLevel(a, "verbose"), http.StatusUnprocessableEntity)
+		assertStatusCode(t, setLevel(a, "panic"), http.StatusUnprocessableEntity)
+
+		if a.logLevel.Level() != log.InfoLevel {
+			t.Fatalf("expected the level to be left unchanged, got %s", a.logLevel.Level())
+		}
+	})
+
+	t.Run("expect the level to be set by admins only", func(t *testing.T) {
+		captureLogs(t)
+
+		assertStatusCode(t, setLevel(newTestApplication(), "debug"), http.StatusForbidden)
+	})
+
+	t.Run("expect every SIGHUP to cycle through the levels", func(t *testing.T) {
+		captureLogs(t)
+		a := newTestApplication()
+		ctx, cancel := context.WithCancel(context.Background())
+		signals := make(chan os.Signal)
+		done := make(chan struct{})
+		go func() {
+			a.cycleLogLevel(ctx, signals)
+			close(done)
+		}()
+
+		signals <- syscall.SIGHUP
+		cancel()
+		<-done
+		if a.logLevel.Level() != log.WarnLevel {
+			t.Fatalf("expected the level after info, got %s", a.logLevel.Level())
+		}
+
+		var levels []log.Level
+		for i := 0; i < 3; i++ {
+			levels = append(levels, a.logLevel.Cycle())
+		}
+		if levels[0] != log.ErrorLevel || levels[1] != log.DebugLevel || levels[2] != log.InfoLevel {
+			t.Fatalf("expected to cycle back to debug after error, got %v", levels)
+		}
+	})
+}
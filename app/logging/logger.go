@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"appdoki-be/config"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// New returns the logger of the application, writing in the format and to the output of conf,
+// along with the function closing its output once the application is done
+func New(conf config.LoggingConfig) (*log.Logger, func() error, error) {
+	logger := log.New()
+	closeOutput := func() error { return nil }
+
+	switch conf.Output {
+	case "", "stdout":
+		logger.SetOutput(os.Stdout)
+	case "stderr":
+		logger.SetOutput(os.Stderr)
+	case "file":
+		file, err := openRotatingFile(conf.File, conf.FileMaxBytes, conf.FileMaxBackups)
+		if err != nil {
+			return nil, nil, err
+		}
+		logger.SetOutput(file)
+		closeOutput = file.Close
+	default:
+		return nil, nil, fmt.Errorf("unknown log output %q", conf.Output)
+	}
+
+	var formatter log.Formatter = &log.TextFormatter{FullTimestamp: true}
+	if conf.Format == "json" {
+		formatter = &log.JSONFormatter{TimestampFormat: time.RFC3339Nano}
+	}
+	if conf.DebugSampling > 1 {
+		formatter = &sampledFormatter{Formatter: formatter, every: uint64(conf.DebugSampling)}
+	}
+	logger.SetFormatter(formatter)
+	logger.SetReportCaller(conf.Caller)
+	if level, err := log.ParseLevel(conf.Level); err == nil {
+		logger.SetLevel(level)
+	}
+
+	return logger, closeOutput, nil
+}
+
+// sampledFormatter only formats one debug line in every, the first one, leaving the others out
+// for the high volume ones not to flood the output
+type sampledFormatter struct {
+	log.Formatter
+	every      uint64
+	debugLines uint64
+}
+
+func (f *sampledFormatter) Format(entry *log.Entry) ([]byte, error) {
+	if entry.Level >= log.DebugLevel && (atomic.AddUint64(&f.debugLines, 1)-1)%f.every != 0 {
+		return nil, nil
+	}
+	return f.Formatter.Format(entry)
+}
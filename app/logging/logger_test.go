@@ -0,0 +1,133 @@
+package logging
+
+import (
+	"appdoki-be/config"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestLogger returns a context carrying the JSON logger of conf, writing to a buffer the lines of
+// which the returned function parses
+func newTestLogger(t *testing.T, conf config.LoggingConfig) (context.Context, func() []map[string]interface{}) {
+	t.Helper()
+	conf.Format, conf.Output = "json", "stdout"
+	logger, _, err := New(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+
+	lines := func() []map[string]interface{} {
+		var lines []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var fields map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &fields); err != nil {
+				t.Fatalf("expected a JSON line, got %s: %v", line, err)
+			}
+			lines = append(lines, fields)
+		}
+		return lines
+	}
+	return WithLogger(context.Background(), logger), lines
+}
+
+func TestNew(t *testing.T) {
+	t.Run("expect the JSON lines to have the standard fields, the request ID when there is one", func(t *testing.T) {
+		ctx, lines := newTestLogger(t, config.LoggingConfig{Level: "info"})
+
+		FromContext(WithRequestID(ctx, "request-1")).Info("with a request")
+		FromContext(ctx).Warn("without a request")
+
+		logged := lines()
+		if len(logged) != 2 {
+			t.Fatalf("expected 2 lines, got %v", logged)
+		}
+		first := logged[0]
+		if _, err := time.Parse(time.RFC3339Nano, first["time"].(string)); err != nil {
+			t.Fatalf("expected an RFC 3339 time, got %v", first["time"])
+		}
+		if first["level"] != "info" || first["msg"] != "with a request" || first["request_id"] != "request-1" {
+			t.Fatalf("unexpected line %v", first)
+		}
+		if _, ok := logged[1]["request_id"]; ok || logged[1]["level"] != "warning" {
+			t.Fatalf("expected no request ID, got %v", logged[1])
+		}
+		if _, ok := first["file"]; ok {
+			t.Fatalf("expected no caller, got %v", first)
+		}
+	})
+
+	t.Run("expect the caller when configured", func(t *testing.T) {
+		ctx, lines := newTestLogger(t, config.LoggingConfig{Level: "info", Caller: true})
+
+		FromContext(ctx).Info("with the caller")
+
+		line := lines()[0]
+		if !strings.Contains(line["file"].(string), "logger_test.go") || !strings.HasSuffix(line["func"].(string), "TestNew.func2") {
+			t.Fatalf("expected the caller of the line, got %v", line)
+		}
+	})
+
+	t.Run("expect a single debug line in every sampling, the others kept", func(t *testing.T) {
+		ctx, lines := newTestLogger(t, config.LoggingConfig{Level: "debug", DebugSampling: 3})
+
+		for i := 0; i < 7; i++ {
+			FromContext(ctx).WithField("i", i).Debug("probe")
+			FromContext(ctx).Info("request")
+		}
+
+		var debug []float64
+		info := 0
+		for _, line := range lines() {
+			if line["level"] == "debug" {
+				debug = append(debug, line["i"].(float64))
+			} else {
+				info++
+			}
+		}
+		if len(debug) != 3 || debug[0] != 0 || debug[1] != 3 || debug[2] != 6 || info != 7 {
+			t.Fatalf("expected the debug lines 0, 3 and 6 along with the 7 info ones, got %v and %d", debug, info)
+		}
+	})
+
+	t.Run("expect the log file to be rotated once too long, the oldest rotations removed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "appdoki.log")
+		logger, closeLogs, err := New(config.LoggingConfig{
+			Level: "info", Format: "json", Output: "file", File: path, FileMaxBytes: 200, FileMaxBackups: 2,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < 20; i++ {
+			logger.WithField("i", i).Info("a line long enough to fill the file quickly")
+		}
+		if err := closeLogs(); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, name := range []string{path, path + ".1", path + ".2"} {
+			content, err := ioutil.ReadFile(name)
+			if err != nil || len(content) == 0 || len(content) > 200 {
+				t.Fatalf("expected %s to be written up to 200 bytes, got %d bytes, %v", name, len(content), err)
+			}
+		}
+		if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+			t.Fatalf("expected 2 rotations at most, got %v", err)
+		}
+		if content, _ := ioutil.ReadFile(path); !strings.Contains(string(content), `"i":19`) {
+			t.Fatalf("expected the last line in the current file, got %s", content)
+		}
+	})
+}
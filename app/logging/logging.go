@@ -0,0 +1,80 @@
+// Package logging holds the request scoped logging helpers shared by the
+// handlers, repositories and any outbound clients.
+package logging
+
+import (
+	"context"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header used to receive and propagate request IDs
+const RequestIDHeader = "X-Request-ID"
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	loggerKey
+)
+
+// NewRequestID generates a new random request ID
+func NewRequestID() string {
+	return uuid.New().String()
+}
+
+// WithRequestID returns a copy of ctx carrying the given request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID returns the request ID stored in ctx or an empty string if there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithLogger returns a copy of ctx carrying the logger of the application
+func WithLogger(ctx context.Context, logger *log.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// Logger returns the logger carried by ctx, the standard one outside of the application (ex.: in tests)
+func Logger(ctx context.Context) *log.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey).(*log.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	return log.StandardLogger()
+}
+
+// Detach returns a background context carrying the request scoped values of ctx,
+// to be used by work that outlives the request (ex.: notifications)
+func Detach(ctx context.Context) context.Context {
+	detached := WithLogger(WithRequestID(context.Background(), RequestID(ctx)), Logger(ctx))
+	return trace.ContextWithSpanContext(detached, trace.SpanContextFromContext(ctx))
+}
+
+// FromContext returns a log entry of the logger of ctx, annotated with the request scoped
+// values of ctx and the current trace, so log lines can be linked to their traces
+func FromContext(ctx context.Context) *log.Entry {
+	entry := log.NewEntry(Logger(ctx))
+	if requestID := RequestID(ctx); requestID != "" {
+		entry = entry.WithField("request_id", requestID)
+	}
+	if ctx == nil {
+		return entry
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		entry = entry.WithFields(log.Fields{
+			"trace_id": sc.TraceID().String(),
+			"span_id":  sc.SpanID().String(),
+		})
+	}
+	return entry
+}
@@ -0,0 +1,242 @@
+package logging
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxStatementLength truncates the statements logged
+const maxStatementLength = 500
+
+// queryLog logs every statement when all is set, else the ones taking at least threshold, never when 0
+type queryLog struct {
+	logger    *log.Logger
+	threshold time.Duration
+	all       bool
+}
+
+// LogQueries returns a connector of the connections of next logging the statements with their
+// arguments, the text and binary ones redacted, their duration and the rows they
+// affected or returned, along with the request ID of their context. The statements taking at least
+// threshold are logged as slow warnings, the other ones only when all is set. The logger of the
+// context is used, or else logger.
+func LogQueries(next driver.Connector, logger *log.Logger, threshold time.Duration, all bool) driver.Connector {
+	return &queryLogConnector{next: next, log: &queryLog{logger: logger, threshold: threshold, all: all}}
+}
+
+// observe logs the statement once it's done, rows being -1 when unknown
+func (l *queryLog) observe(ctx context.Context, query string, args []driver.NamedValue, duration time.Duration, rows int64, err error) {
+	slow := l.threshold > 0 && duration >= l.threshold
+	if !slow && !l.all {
+		return
+	}
+
+	if Logger(ctx) == log.StandardLogger() && l.logger != nil {
+		ctx = WithLogger(ctx, l.logger)
+	}
+	entry := FromContext(ctx).WithFields(log.Fields{
+		"statement":   statementText(query),
+		"args":        redactArgs(args),
+		"duration_ms": float64(duration) / float64(time.Millisecond),
+	})
+	if rows >= 0 {
+		entry = entry.WithField("rows", rows)
+	}
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	if slow {
+		entry.Warn("slow statement")
+		return
+	}
+	entry.Info("statement")
+}
+
+// statementText returns the statement on a single line, truncated
+func statementText(query string) string {
+	return truncate(strings.Join(strings.Fields(query), " "), maxStatementLength)
+}
+
+// redactArgs returns the argument values as logged: the text and binary ones replaced by their size,
+// for the emails, secrets and tokens written never to be logged, the numbers, booleans and times as they are
+func redactArgs(args []driver.NamedValue) []string {
+	values := make([]string, 0, len(args))
+	for _, arg := range args {
+		var value string
+		switch v := arg.Value.(type) {
+		case nil:
+			value = "NULL"
+		case []byte:
+			value = fmt.Sprintf("<%d bytes>", len(v))
+		case time.Time:
+			value = v.Format(time.RFC3339Nano)
+		case string:
+			value = fmt.Sprintf("<%d chars>", utf8.RuneCountInString(v))
+		default:
+			value = fmt.Sprint(v)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func truncate(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max]) + "…"
+	}
+	return s
+}
+
+type queryLogConnector struct {
+	next driver.Connector
+	log  *queryLog
+}
+
+func (c *queryLogConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.next.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &queryLogConn{Conn: conn, log: c.log}, nil
+}
+
+func (c *queryLogConnector) Driver() driver.Driver {
+	return c.next.Driver()
+}
+
+// queryLogConn logs the statements run directly on the connection, the optional interfaces of the
+// connection it wraps being forwarded to it, or else skipped for database/sql to fall back on
+type queryLogConn struct {
+	driver.Conn
+	log *queryLog
+}
+
+func (c *queryLogConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := execer.ExecContext(ctx, query, args)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	rows := int64(-1)
+	if err == nil {
+		if affected, err := res.RowsAffected(); err == nil {
+			rows = affected
+		}
+	}
+	c.log.observe(ctx, query, args, time.Since(start), rows, err)
+	return res, err
+}
+
+func (c *queryLogConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err == driver.ErrSkip {
+		return nil, err
+	}
+	if err != nil {
+		c.log.observe(ctx, query, args, time.Since(start), -1, err)
+		return nil, err
+	}
+	return &queryLogRows{Rows: rows, done: func(count int64, err error) {
+		c.log.observe(ctx, query, args, time.Since(start), count, err)
+	}}, nil
+}
+
+func (c *queryLogConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *queryLogConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *queryLogConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *queryLogConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *queryLogConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+// queryLogRows counts the rows read, the statement being logged once they're closed
+type queryLogRows struct {
+	driver.Rows
+	count  int64
+	err    error
+	done   func(count int64, err error)
+	closed bool
+}
+
+func (r *queryLogRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	switch {
+	case err == nil:
+		r.count++
+	case err != io.EOF:
+		r.err = err
+	}
+	return err
+}
+
+func (r *queryLogRows) Close() error {
+	err := r.Rows.Close()
+	if !r.closed {
+		r.closed = true
+		r.done(r.count, r.err)
+	}
+	return err
+}
+
+func (r *queryLogRows) HasNextResultSet() bool {
+	if sets, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return sets.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *queryLogRows) NextResultSet() error {
+	if sets, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return sets.NextResultSet()
+	}
+	return io.EOF
+}
+
+func (r *queryLogRows) ColumnTypeDatabaseTypeName(index int) string {
+	if types, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return types.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"appdoki-be/config"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeConnector connects to a database answering every query with rows rows, after delay
+type fakeConnector struct {
+	rows  int
+	delay time.Duration
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) { return &fakeConn{c}, nil }
+func (c *fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ c *fakeConnector }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (c *fakeConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	time.Sleep(c.c.delay)
+	return driver.RowsAffected(c.c.rows), nil
+}
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	time.Sleep(c.c.delay)
+	return &fakeRows{left: c.c.rows}, nil
+}
+
+type fakeRows struct{ left int }
+
+func (r *fakeRows) Columns() []string { return []string{"id"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.left == 0 {
+		return io.EOF
+	}
+	r.left--
+	dest[0] = int64(r.left)
+	return nil
+}
+
+func TestLogQueries(t *testing.T) {
+	openDB := func(t *testing.T, connector *fakeConnector, threshold time.Duration, all bool) (*sql.DB, context.Context, func() []map[string]interface{}) {
+		t.Helper()
+		ctx, lines := newTestLogger(t, config.LoggingConfig{Level: "info", DebugSampling: 1})
+		db := sql.OpenDB(LogQueries(connector, nil, threshold, all))
+		t.Cleanup(func() { db.Close() })
+		return db, WithRequestID(ctx, "req-1"), lines
+	}
+
+	t.Run("expect the statement, its duration, the rows affected and the request ID, the text values redacted", func(t *testing.T) {
+		db, ctx, lines := openDB(t, &fakeConnector{rows: 2}, 0, true)
+
+		_, err := db.ExecContext(ctx, "UPDATE users\n\t SET email = $1 WHERE email = $2 OR name = $3",
+			"ana@cloudoki.com", "Contact: rui.costa+beers@mail.cloudoki.pt", "Ana")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logged := lines()
+		if len(logged) != 1 {
+			t.Fatalf("expected a single line, got %v", logged)
+		}
+		line := logged[0]
+		if line["msg"] != "statement" || line["level"] != "info" || line["request_id"] != "req-1" || line["rows"] != float64(2) {
+			t.Fatalf("expected the statement with its rows and request ID, got %v", line)
+		}
+		if line["statement"] != "UPDATE users SET email = $1 WHERE email = $2 OR name = $3" {
+			t.Fatalf("expected the statement on a single line, got %v", line["statement"])
+		}
+		if _, ok := line["duration_ms"].(float64); !ok {
+			t.Fatalf("expected the duration, got %v", line)
+		}
+		args, _ := line["args"].([]interface{})
+		if len(args) != 3 || args[0] != "<16 chars>" || args[1] != "<41 chars>" || args[2] != "<3 chars>" {
+			t.Fatalf("expected the text values to be redacted, got %v", line["args"])
+		}
+	})
+
+	t.Run("expect a webhook secret written never to be logged, even slow", func(t *testing.T) {
+		const secret = "whsec_9f86d081884c7d659a2feaa0c55ad015"
+		db, ctx, lines := openDB(t, &fakeConnector{delay: 30 * time.Millisecond}, 20*time.Millisecond, false)
+
+		_, err := db.ExecContext(ctx, "INSERT INTO webhook_endpoints (url, secret, events, created_by) VALUES ($1, $2, $3, $4)",
+			"https://hooks.example.com/appdoki", secret, "{beer_given}", "1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		logged := lines()
+		if len(logged) != 1 || logged[0]["msg"] != "slow statement" {
+			t.Fatalf("expected the slow statement, got %v", logged)
+		}
+		for _, arg := range logged[0]["args"].([]interface{}) {
+			if strings.Contains(arg.(string), secret[:8]) || strings.Contains(arg.(string), secret[len(secret)-8:]) {
+				t.Fatalf("expected the secret to be redacted, got %v", logged[0]["args"])
+			}
+		}
+	})
+
+	t.Run("expect the rows returned once read, the numbers as they are and the binary values redacted", func(t *testing.T) {
+		db, ctx, lines := openDB(t, &fakeConnector{rows: 3}, 0, true)
+
+		rows, err := db.QueryContext(ctx, "SELECT id FROM users WHERE beers = $1 AND picture = $2 AND deleted_at IS $3",
+			int64(12), []byte("png"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for rows.Next() {
+		}
+		rows.Close()
+
+		logged := lines()
+		if len(logged) != 1 || logged[0]["rows"] != float64(3) {
+			t.Fatalf("expected the rows returned, got %v", logged)
+		}
+		args, _ := logged[0]["args"].([]interface{})
+		if len(args) != 3 || args[0] != "12" || args[1] != "<3 bytes>" || args[2] != "NULL" {
+			t.Fatalf("expected the values redacted, got %v", args)
+		}
+	})
+
+	t.Run("expect only the statements above the threshold when not logging all, as slow warnings", func(t *testing.T) {
+		db, ctx, lines := openDB(t, &fakeConnector{}, 20*time.Millisecond, false)
+		if _, err := db.ExecContext(ctx, "SELECT 1"); err != nil {
+			t.Fatal(err)
+		}
+		if logged := lines(); len(logged) != 0 {
+			t.Fatalf("expected the fast statement not to be logged, got %v", logged)
+		}
+
+		slowDB, slowCtx, slowLines := openDB(t, &fakeConnector{delay: 30 * time.Millisecond}, 20*time.Millisecond, false)
+		if _, err := slowDB.ExecContext(slowCtx, "SELECT pg_sleep(1)"); err != nil {
+			t.Fatal(err)
+		}
+		if logged := slowLines(); len(logged) != 1 || logged[0]["msg"] != "slow statement" || logged[0]["level"] != "warning" {
+			t.Fatalf("expected the slow statement as a warning, got %v", logged)
+		}
+	})
+}
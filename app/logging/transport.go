@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"net/http"
+	"time"
+)
+
+// Transport is an http.RoundTripper that forwards the request ID found in
+// the outgoing request's context to the called service
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	requestID := RequestID(req.Context())
+	if requestID == "" || req.Header.Get(RequestIDHeader) != "" {
+		return base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the original request
+	r := req.Clone(req.Context())
+	r.Header.Set(RequestIDHeader, requestID)
+
+	return base.RoundTrip(r)
+}
+
+// HTTPClients creates the clients of the calls to the outbound dependencies, sharing a transport
+// and its connections, ex.: trusting the CA of a proxy intercepting TLS
+type HTTPClients struct {
+	base http.RoundTripper
+}
+
+// NewHTTPClients returns the factory of the clients sending their requests through base,
+// http.DefaultTransport when nil
+func NewHTTPClients(base http.RoundTripper) *HTTPClients {
+	return &HTTPClients{base: base}
+}
+
+// New returns an http.Client propagating request IDs, giving up on the requests after timeout,
+// never when 0. A nil factory returns clients of http.DefaultTransport.
+func (c *HTTPClients) New(timeout time.Duration) *http.Client {
+	var base http.RoundTripper
+	if c != nil {
+		base = c.base
+	}
+	return &http.Client{
+		Transport: &Transport{Base: base},
+		Timeout:   timeout,
+	}
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const maxMaintenancePayloadBytes = 1 << 10
+
+// maintenanceMode is the runtime switch of the maintenance mode, safe for concurrent use
+type maintenanceMode struct {
+	enabled int32
+}
+
+func newMaintenanceMode(enabled bool) *maintenanceMode {
+	m := &maintenanceMode{}
+	m.Set(enabled)
+	return m
+}
+
+// Enabled checks if the API is in maintenance
+func (m *maintenanceMode) Enabled() bool {
+	return atomic.LoadInt32(&m.enabled) == 1
+}
+
+// Set turns the maintenance mode on or off
+func (m *maintenanceMode) Set(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&m.enabled, v)
+}
+
+// maintenanceMiddleware answers 503 to every request, except the health probes and the
+// operations routes, while the API is in maintenance
+func (a *Application) maintenanceMiddleware(next http.Handler) http.Handler {
+	retryAfter := strconv.Itoa(maxAgeSeconds(a.conf.Maintenance.RetryAfter))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.maintenance.Enabled() || isMaintenanceExempt(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Retry-After", retryAfter)
+		respondError(w, http.StatusServiceUnavailable, ErrCodeMaintenance,
+			"appdoki is down for maintenance, please try again in a few minutes", nil)
+	})
+}
+
+// isMaintenanceExempt checks if the path stays available during maintenance
+func isMaintenanceExempt(path string) bool {
+	return isProbePath(path) || path == "/metrics" ||
+		strings.HasPrefix(path, "/admin") || strings.HasPrefix(path, "/debug")
+}
+
+// maintenanceCheck fails while the API is in maintenance, only critical
+// when the load balancer should drain the instance
+func maintenanceCheck(m *maintenanceMode, critical bool) healthCheck {
+	return healthCheck{
+		name:     "maintenance",
+		critical: critical,
+		check: func(_ context.Context) error {
+			if m.Enabled() {
+				return errors.New("in maintenance")
+			}
+			return nil
+		},
+	}
+}
+
+type MaintenancePayload struct {
+	Enabled *bool `json:"enabled"`
+}
+
+type MaintenanceStatus struct {
+	Enabled bool `json:"enabled"`
+}
+
+// GetMaintenance responds with the maintenance mode state
+func (a *Application) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, r, MaintenanceStatus{Enabled: a.maintenance.Enabled()}, http.StatusOK)
+}
+
+// SetMaintenance turns the maintenance mode on or off
+func (a *Application) SetMaintenance(w http.ResponseWriter, r *http.Request) {
+	var payload MaintenancePayload
+	if err := decodeJSON(r, &payload, maxMaintenancePayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	if payload.Enabled == nil {
+		respondErrorWithParams(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed,
+			"field \"{field}\" is required", map[string]string{"field": "enabled"}, nil)
+		return
+	}
+
+	a.maintenance.Set(*payload.Enabled)
+	logging.FromContext(r.Context()).
+		WithField("user_id", r.Context().Value("userID")).
+		Warnf("maintenance mode set to %t", *payload.Enabled)
+
+	respondJSON(w, r, MaintenanceStatus{Enabled: *payload.Enabled}, http.StatusOK)
+}
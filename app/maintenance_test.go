@@ -0,0 +1,93 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplication_Maintenance(t *testing.T) {
+	newApplication := func(role string) *Application {
+		a := newTestApplication()
+		a.conf.Maintenance.RetryAfter = 2 * time.Minute
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = role
+			return user, nil
+		}
+		return a
+	}
+
+	serve := func(routes http.Handler, method string, path string, body string) *http.Response {
+		r := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+		return w.Result()
+	}
+
+	t.Run("expect API routes to return 503 during maintenance", func(t *testing.T) {
+		a := newApplication(repos.RoleUser)
+		a.maintenance.Set(true)
+
+		resp := serve(a.Routes(), "GET", "/api/v1/users", "")
+
+		assertStatusCode(t, resp, http.StatusServiceUnavailable)
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "120" {
+			t.Fatalf("expected Retry-After '120', got '%s'", retryAfter)
+		}
+		assertErrorCode(t, resp, ErrCodeMaintenance)
+	})
+
+	t.Run("expect health probes to stay available during maintenance", func(t *testing.T) {
+		a := newApplication(repos.RoleUser)
+		a.maintenance.Set(true)
+
+		assertStatusCode(t, serve(a.Routes(), "GET", "/healthz", ""), http.StatusOK)
+	})
+
+	t.Run("expect admins to flip the maintenance mode at runtime", func(t *testing.T) {
+		a := newApplication(repos.RoleAdmin)
+		routes := a.Routes()
+
+		resp := serve(routes, "POST", "/admin/maintenance", `{"enabled":true}`)
+		assertStatusCode(t, resp, http.StatusOK)
+		assertStatusCode(t, serve(routes, "GET", "/api/v1/users", ""), http.StatusServiceUnavailable)
+
+		resp = serve(routes, "POST", "/admin/maintenance", `{"enabled":false}`)
+		assertStatusCode(t, resp, http.StatusOK)
+		assertStatusCode(t, serve(routes, "GET", "/api/v1/users", ""), http.StatusOK)
+	})
+
+	t.Run("expect POST /admin/maintenance to return 422 without the enabled field", func(t *testing.T) {
+		resp := serve(newApplication(repos.RoleAdmin).Routes(), "POST", "/admin/maintenance", `{}`)
+
+		assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+		assertErrorCode(t, resp, ErrCodeValidationFailed)
+	})
+
+	t.Run("expect POST /admin/maintenance to return 403 for non admins", func(t *testing.T) {
+		a := newApplication(repos.RoleUser)
+
+		resp := serve(a.Routes(), "POST", "/admin/maintenance", `{"enabled":true}`)
+
+		assertStatusCode(t, resp, http.StatusForbidden)
+		assertErrorCode(t, resp, ErrCodeForbidden)
+		if a.maintenance.Enabled() {
+			t.Fatal("expected the maintenance mode to stay off")
+		}
+	})
+
+	t.Run("expect readiness to fail during maintenance when configured to", func(t *testing.T) {
+		m := newMaintenanceMode(true)
+		for critical, status := range map[bool]int{true: http.StatusServiceUnavailable, false: http.StatusOK} {
+			h := NewHealthHandler(maintenanceCheck(m, critical))
+			router := prepareRouter(http.MethodGet, "/readyz", h.Ready)
+
+			assertStatusCode(t, serve(router, "GET", "/readyz", ""), status)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	"crypto/subtle"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	httpRequestDurationMetric = "http_request_duration_seconds"
+	httpPanicsMetric          = "http_panics_total"
+	authFailuresMetric        = "auth_failures_total"
+	dbConnectionsMetric       = "db_connections"
+	dbConnectionsMaxMetric    = "db_connections_max_open"
+	dbWaitsMetric             = "db_connection_waits"
+	dbWaitDurationMetric      = "db_connection_wait_seconds"
+	dbClosedMetric            = "db_connections_closed"
+	dbTableRowsMetric         = "db_table_rows"
+	dbTableSizeMetric         = "db_table_size_bytes"
+	rateLimitedMetric         = "http_rate_limited_total"
+)
+
+// MetricsRouter mounts the metrics endpoint in the API router when it is not served
+// on its own listener, in which case it is protected by the configured bearer token
+func (a *Application) MetricsRouter(router *mux.Router) {
+	if a.conf.Metrics.Address != "" {
+		return
+	}
+
+	if a.conf.Metrics.Token == "" {
+		a.logger.Warn("metrics endpoint disabled: neither METRICS_ADDRESS nor METRICS_TOKEN are set")
+		return
+	}
+
+	a.mount(router,
+		routeDef{methods: []string{http.MethodGet}, path: "/metrics", access: scopeAccess(metricsScope),
+			handler: a.MetricsHandler()},
+	)
+}
+
+// MetricsHandler serves the collected metrics, refreshing the database pool gauges on every scrape
+func (a *Application) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.recordDBStats()
+		a.metricsHandler.ServeHTTP(w, r)
+	})
+}
+
+func (a *Application) recordDBStats() {
+	if a.db == nil {
+		return
+	}
+
+	stats := a.db.Stats()
+	a.metrics.SetGauge(dbConnectionsMetric, float64(stats.OpenConnections), metrics.Labels{"state": "open"})
+	a.metrics.SetGauge(dbConnectionsMetric, float64(stats.InUse), metrics.Labels{"state": "in_use"})
+	a.metrics.SetGauge(dbConnectionsMetric, float64(stats.Idle), metrics.Labels{"state": "idle"})
+	a.metrics.SetGauge(dbConnectionsMaxMetric, float64(stats.MaxOpenConnections), nil)
+	// the totals since the pool was opened, the callers having waited for a connection
+	a.metrics.SetGauge(dbWaitsMetric, float64(stats.WaitCount), nil)
+	a.metrics.SetGauge(dbWaitDurationMetric, stats.WaitDuration.Seconds(), nil)
+	a.metrics.SetGauge(dbClosedMetric, float64(stats.MaxIdleClosed), metrics.Labels{"reason": "max_idle"})
+	a.metrics.SetGauge(dbClosedMetric, float64(stats.MaxIdleTimeClosed), metrics.Labels{"reason": "max_idle_time"})
+	a.metrics.SetGauge(dbClosedMetric, float64(stats.MaxLifetimeClosed), metrics.Labels{"reason": "max_lifetime"})
+}
+
+// metricsTokenVerify only lets through requests bearing the given token
+func metricsTokenVerify(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !hasBearerToken(r, token) {
+			respondNoContent(w, http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hasBearerToken checks, in constant time, that the request bears the given token
+func hasBearerToken(r *http.Request, token string) bool {
+	const bearerHeaderPrefix = "Bearer "
+	tokenHeader := r.Header.Get("Authorization")
+	return strings.HasPrefix(tokenHeader, bearerHeaderPrefix) &&
+		subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(tokenHeader, bearerHeaderPrefix)), []byte(token)) == 1
+}
+
+// metricsMiddleware records the duration of every request labeled by route, method and status class.
+// Routes are identified by their template (ex.: /users/{id}) so labels don't grow with every user ID.
+// The duration is measured from the start recorded by responseTimeMiddleware, as in the response header.
+func metricsMiddleware(m metrics.Metrics, router *mux.Router) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := requestStart(r.Context())
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			m.ObserveHistogram(httpRequestDurationMetric, time.Since(start).Seconds(), metrics.Labels{
+				"route":  routeTemplate(router, r),
+				"method": r.Method,
+				"status": fmt.Sprintf("%dxx", rec.status/100),
+			})
+		})
+	}
+}
+
+// routeTemplate returns the path template of the route matching the request
+func routeTemplate(router *mux.Router, r *http.Request) string {
+	var match mux.RouteMatch
+	if !router.Match(r, &match) || match.Route == nil {
+		return "unmatched"
+	}
+
+	template, err := match.Route.GetPathTemplate()
+	if err != nil {
+		return "unmatched"
+	}
+	return template
+}
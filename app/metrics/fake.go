@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Fake is an in-memory Metrics implementation to be used in tests
+type Fake struct {
+	mu           sync.Mutex
+	counters     map[string]float64
+	gauges       map[string]float64
+	observations map[string][]float64
+}
+
+// NewFake returns an empty Fake sink
+func NewFake() *Fake {
+	return &Fake{
+		counters:     map[string]float64{},
+		gauges:       map[string]float64{},
+		observations: map[string][]float64{},
+	}
+}
+
+func (f *Fake) IncCounter(name string, labels Labels) {
+	f.AddCounter(name, 1, labels)
+}
+
+func (f *Fake) AddCounter(name string, value float64, labels Labels) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.counters[sampleKey(name, labels)] += value
+}
+
+func (f *Fake) ObserveHistogram(name string, value float64, labels Labels) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := sampleKey(name, labels)
+	f.observations[key] = append(f.observations[key], value)
+}
+
+func (f *Fake) SetGauge(name string, value float64, labels Labels) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.gauges[sampleKey(name, labels)] = value
+}
+
+// Counter returns the current value of a counter
+func (f *Fake) Counter(name string, labels Labels) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counters[sampleKey(name, labels)]
+}
+
+// Gauge returns the current value of a gauge
+func (f *Fake) Gauge(name string, labels Labels) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.gauges[sampleKey(name, labels)]
+}
+
+// Observations returns the values observed by a histogram
+func (f *Fake) Observations(name string, labels Labels) []float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]float64(nil), f.observations[sampleKey(name, labels)]...)
+}
+
+func sampleKey(name string, labels Labels) string {
+	parts := []string{name}
+	for _, n := range labelNames(labels) {
+		parts = append(parts, fmt.Sprintf("%s=%s", n, labels[n]))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,36 @@
+// Package metrics defines the sink application metrics are recorded into
+// and its implementations.
+package metrics
+
+import (
+	"sort"
+)
+
+// Labels are the dimensions of a metric sample
+type Labels map[string]string
+
+// Metrics is implemented by every metrics sink
+type Metrics interface {
+	IncCounter(name string, labels Labels)
+	AddCounter(name string, value float64, labels Labels)
+	ObserveHistogram(name string, value float64, labels Labels)
+	SetGauge(name string, value float64, labels Labels)
+}
+
+// Noop is a Metrics implementation that discards every sample
+type Noop struct{}
+
+func (Noop) IncCounter(string, Labels)                {}
+func (Noop) AddCounter(string, float64, Labels)       {}
+func (Noop) ObserveHistogram(string, float64, Labels) {}
+func (Noop) SetGauge(string, float64, Labels)         {}
+
+// labelNames returns the sorted label names of a sample
+func labelNames(labels Labels) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"sync"
+)
+
+// Prometheus implements Metrics by registering metric vectors on first use.
+// The label names of a metric are fixed by the first recorded sample.
+type Prometheus struct {
+	namespace  string
+	registry   *prometheus.Registry
+	mu         sync.Mutex
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+	gauges     map[string]*prometheus.GaugeVec
+	labels     map[string][]string
+}
+
+// NewPrometheus returns a Prometheus sink with its own registry,
+// already including the Go runtime and process collectors
+func NewPrometheus(namespace string) *Prometheus {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return &Prometheus{
+		namespace:  namespace,
+		registry:   registry,
+		counters:   map[string]*prometheus.CounterVec{},
+		histograms: map[string]*prometheus.HistogramVec{},
+		gauges:     map[string]*prometheus.GaugeVec{},
+		labels:     map[string][]string{},
+	}
+}
+
+// Handler returns the HTTP handler exposing the registered metrics
+func (p *Prometheus) Handler() http.Handler {
+	return promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{})
+}
+
+func (p *Prometheus) IncCounter(name string, labels Labels) {
+	p.AddCounter(name, 1, labels)
+}
+
+func (p *Prometheus) AddCounter(name string, value float64, labels Labels) {
+	p.mu.Lock()
+	vec, ok := p.counters[name]
+	if !ok {
+		vec = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: p.namespace,
+			Name:      name,
+			Help:      name,
+		}, p.register(name, labels))
+		p.registry.MustRegister(vec)
+		p.counters[name] = vec
+	}
+	p.mu.Unlock()
+
+	vec.With(p.values(name, labels)).Add(value)
+}
+
+func (p *Prometheus) ObserveHistogram(name string, value float64, labels Labels) {
+	p.mu.Lock()
+	vec, ok := p.histograms[name]
+	if !ok {
+		vec = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: p.namespace,
+			Name:      name,
+			Help:      name,
+			Buckets:   prometheus.DefBuckets,
+		}, p.register(name, labels))
+		p.registry.MustRegister(vec)
+		p.histograms[name] = vec
+	}
+	p.mu.Unlock()
+
+	vec.With(p.values(name, labels)).Observe(value)
+}
+
+func (p *Prometheus) SetGauge(name string, value float64, labels Labels) {
+	p.mu.Lock()
+	vec, ok := p.gauges[name]
+	if !ok {
+		vec = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: p.namespace,
+			Name:      name,
+			Help:      name,
+		}, p.register(name, labels))
+		p.registry.MustRegister(vec)
+		p.gauges[name] = vec
+	}
+	p.mu.Unlock()
+
+	vec.With(p.values(name, labels)).Set(value)
+}
+
+// register stores the label names of a new metric, must be called with the lock held
+func (p *Prometheus) register(name string, labels Labels) []string {
+	names := labelNames(labels)
+	p.labels[name] = names
+	return names
+}
+
+// values maps a sample's labels onto the metric's registered label names,
+// so a sample with missing or extra labels never panics
+func (p *Prometheus) values(name string, labels Labels) prometheus.Labels {
+	p.mu.Lock()
+	names := p.labels[name]
+	p.mu.Unlock()
+
+	values := make(prometheus.Labels, len(names))
+	for _, n := range names {
+		values[n] = labels[n]
+	}
+	return values
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	"appdoki-be/config"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	t.Run("expect requests to be recorded by route template", func(t *testing.T) {
+		m := metrics.NewFake()
+		router := prepareRouter(http.MethodGet, "/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			respondNoContent(w, http.StatusNotFound)
+		})
+		h := metricsMiddleware(m, router)(router)
+
+		r := httptest.NewRequest("GET", "/users/123", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		observations := m.Observations(httpRequestDurationMetric, metrics.Labels{
+			"route":  "/users/{id}",
+			"method": http.MethodGet,
+			"status": "4xx",
+		})
+		if len(observations) != 1 {
+			t.Fatalf("expected 1 observation, got %d", len(observations))
+		}
+	})
+
+	t.Run("expect unknown routes to be recorded as unmatched", func(t *testing.T) {
+		m := metrics.NewFake()
+		router := mux.NewRouter()
+		h := metricsMiddleware(m, router)(router)
+
+		r := httptest.NewRequest("GET", "/nope", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		observations := m.Observations(httpRequestDurationMetric, metrics.Labels{
+			"route":  "unmatched",
+			"method": http.MethodGet,
+			"status": "4xx",
+		})
+		if len(observations) != 1 {
+			t.Fatalf("expected 1 observation, got %d", len(observations))
+		}
+	})
+}
+
+func TestApplication_MetricsRouter(t *testing.T) {
+	prom := metrics.NewPrometheus("test")
+	a := &Application{
+		conf:           &config.Config{Metrics: config.MetricsConfig{Token: "secret"}},
+		metrics:        prom,
+		metricsHandler: prom.Handler(),
+	}
+	router := mux.NewRouter()
+	a.MetricsRouter(router)
+
+	t.Run("expect GET /metrics to return 401 without the token", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/metrics", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusUnauthorized)
+	})
+
+	t.Run("expect GET /metrics to return 200 with the token", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/metrics", nil)
+		r.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusOK)
+	})
+}
@@ -1,14 +1,34 @@
 package app
 
 import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/reporting"
+	"appdoki-be/app/repositories"
+	"appdoki-be/config"
 	"context"
+	"errors"
 	"fmt"
-	"github.com/coreos/go-oidc"
+	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
 	"net/http"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
+type ctxKey int
+
+const (
+	requestStartKey ctxKey = iota
+	callerKey
+)
+
+// responseTimeHeader carries the time the server took to handle the request, in milliseconds
+const responseTimeHeader = "X-Response-Time-ms"
+
 type middleware func(next http.Handler) http.Handler
 
 // middlewareChain takes an array of middleware functions and a final handler
@@ -31,25 +51,344 @@ func trimSuffixMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// securityHeadersMiddleware sets the configured security headers on every response,
+// handlers may still override them
+func securityHeadersMiddleware(conf config.SecurityHeadersConfig) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			setHeader(w, "X-Content-Type-Options", conf.ContentTypeOptions)
+			setHeader(w, "X-Frame-Options", conf.FrameOptions)
+			setHeader(w, "Referrer-Policy", conf.ReferrerPolicy)
+			if conf.HTTPS {
+				setHeader(w, "Strict-Transport-Security", conf.StrictTransportSecurity)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// contentSecurityPolicyMiddleware sets the Content-Security-Policy of the HTML serving routes
+func contentSecurityPolicyMiddleware(policy string) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			setHeader(w, "Content-Security-Policy", policy)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func setHeader(w http.ResponseWriter, key string, value string) {
+	if value != "" {
+		w.Header().Set(key, value)
+	}
+}
+
+// bodyLimitMiddleware caps the size of every request body, handlers may set tighter limits
+func bodyLimitMiddleware(maxBytes int64) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if maxBytes > 0 && r.Body != nil {
+				r.Body = limitBody(r.Body, maxBytes)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// loggerMiddleware carries the logger of the application in the context of the requests, for
+// logging.FromContext to log with it
+func loggerMiddleware(logger *log.Logger) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(logging.WithLogger(r.Context(), logger)))
+		})
+	}
+}
+
+// requestIDMiddleware makes sure every request is identified, either by the ID the client sent
+// in the X-Request-ID header or by a newly generated one, which is echoed back in the response
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(logging.RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = logging.NewRequestID()
+		}
+
+		w.Header().Set(logging.RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), requestID)))
+	})
+}
+
+// responseTimeMiddleware starts measuring the request, the measure metricsMiddleware records
+// too, and reports it in the response time header. The header is set just before the response
+// headers are written, so for handlers writing early it's the time to the first byte.
+func responseTimeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		tw := &responseTimeWriter{ResponseWriter: w, start: start}
+		next.ServeHTTP(tw, r.WithContext(context.WithValue(r.Context(), requestStartKey, start)))
+
+		// handlers that never write get their headers written by the server once they return
+		tw.setHeader()
+	})
+}
+
+// requestStart returns when responseTimeMiddleware started measuring the request, or now
+func requestStart(ctx context.Context) time.Time {
+	if start, ok := ctx.Value(requestStartKey).(time.Time); ok {
+		return start
+	}
+	return time.Now()
+}
+
+// responseTimeWriter sets the response time header before the response headers are written
+type responseTimeWriter struct {
+	http.ResponseWriter
+	start     time.Time
+	headerSet bool
+}
+
+func (tw *responseTimeWriter) setHeader() {
+	if tw.headerSet {
+		return
+	}
+	tw.headerSet = true
+	elapsed := float64(time.Since(tw.start)) / float64(time.Millisecond)
+	tw.Header().Set(responseTimeHeader, strconv.FormatFloat(elapsed, 'f', 2, 64))
+}
+
+func (tw *responseTimeWriter) WriteHeader(code int) {
+	tw.setHeader()
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *responseTimeWriter) Write(b []byte) (int, error) {
+	tw.setHeader()
+	return tw.ResponseWriter.Write(b)
+}
+
+func (tw *responseTimeWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+func (tw *responseTimeWriter) Flush() {
+	tw.setHeader()
+	flush(tw.ResponseWriter)
+}
+
+// isValidRequestID checks if a client provided request ID is safe to be logged and echoed back
+func isValidRequestID(requestID string) bool {
+	if len(requestID) == 0 || len(requestID) > 128 {
+		return false
+	}
+	for _, c := range requestID {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// recoveryMiddleware returns a middleware that catches panics raised by the handlers and the inner
+// middlewares, logs them to logger and reports them with their stack trace and responds with the default
+// internal error. It also lets respondError report the server errors of the request. When the response
+// was already partially written the connection is aborted instead, as the client would otherwise get a
+// truncated body.
+func recoveryMiddleware(logger *log.Logger, m metrics.Metrics, reporter reporting.ErrorReporter, router *mux.Router) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, _ := withCaller(r.Context())
+			r = r.WithContext(ctx)
+			rw := &reportingWriter{ResponseWriter: w, r: r, router: router, reporter: reporter}
+			rec := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+
+				logging.FromContext(logging.WithLogger(r.Context(), logger)).WithFields(log.Fields{
+					"req":   fmt.Sprintf("%s %s", r.Method, r.RequestURI),
+					"panic": err,
+					"stack": string(debug.Stack()),
+				}).Error("recovered from panic")
+				m.IncCounter(httpPanicsMetric, nil)
+				captureError(reporter, r.Context(), panicError(err), map[string]string{
+					"route":  routeTemplate(router, r),
+					"method": r.Method,
+					"panic":  "true",
+				})
+
+				if rec.wroteHeader {
+					panic(http.ErrAbortHandler)
+				}
+				respondInternalError(w)
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// panicError turns a recovered value into an error
+func panicError(recovered interface{}) error {
+	if err, ok := recovered.(error); ok {
+		return fmt.Errorf("panic: %w", err)
+	}
+	return fmt.Errorf("panic: %v", recovered)
+}
+
+// implicitMethodsMiddleware answers HEAD requests with the GET route of the path, discarding
+// the body, and OPTIONS requests with the methods allowed on the path. Routes registering
+// these methods explicitly take precedence.
+func implicitMethodsMiddleware(router *mux.Router, methods *routeMethods) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if (r.Method != http.MethodHead && r.Method != http.MethodOptions) || routeMatches(router, r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Method == http.MethodHead {
+				get := r.Clone(r.Context())
+				get.Method = http.MethodGet
+				next.ServeHTTP(&headResponseWriter{ResponseWriter: w}, get)
+				return
+			}
+
+			allowed := methods.allowed(r)
+			if len(allowed) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			respondNoContent(w, http.StatusNoContent)
+		})
+	}
+}
+
+// routeMatches checks if a route handles the request path and method
+func routeMatches(router *mux.Router, r *http.Request) bool {
+	var match mux.RouteMatch
+	return router.Match(r, &match) && match.MatchErr == nil
+}
+
+// headResponseWriter discards the body of the responses to HEAD requests
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *headResponseWriter) Flush() {
+	flush(w.ResponseWriter)
+}
+
+func (w *headResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 type statusRecorder struct {
 	http.ResponseWriter
-	status int
+	status      int
+	wroteHeader bool
 }
 
 func (rec *statusRecorder) WriteHeader(code int) {
+	if rec.wroteHeader {
+		return
+	}
 	rec.status = code
+	rec.wroteHeader = true
 	rec.ResponseWriter.WriteHeader(code)
 }
 
-func loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		rec := statusRecorder{w, http.StatusOK}
-		next.ServeHTTP(&rec, r)
-		log.WithFields(log.Fields{
-			"req":    fmt.Sprintf("%s %s", r.Method, r.RequestURI),
-			"status": rec.status,
-		}).Info("handled request")
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+func (rec *statusRecorder) Flush() {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	flush(rec.ResponseWriter)
+}
+
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// loggingMiddleware logs every request. Those taking longer than slowThreshold, but the event streams,
+// are logged with a WARN line detailing the route, user and duration, and kept in the slow log. The
+// requests panicking are logged too, with the 500 recoveryMiddleware answers them.
+func loggingMiddleware(router *mux.Router, slowThreshold time.Duration, slow *slowLog) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			// logged while a panic unwinds as well, without recovering it for the stack trace to be kept
+			defer func() {
+				if !completed && !rec.wroteHeader {
+					rec.status = http.StatusInternalServerError
+				}
+				logRequest(r, rec, router, slowThreshold, slow)
+			}()
+			next.ServeHTTP(rec, r)
+			completed = true
+		})
+	}
+}
+
+// logRequest logs the request answered with the status of rec, as slow when it took slowThreshold or more
+func logRequest(r *http.Request, rec *statusRecorder, router *mux.Router, slowThreshold time.Duration, slow *slowLog) {
+	entry := logging.FromContext(r.Context()).WithFields(log.Fields{
+		"req":    fmt.Sprintf("%s %s", r.Method, r.RequestURI),
+		"status": rec.status,
 	})
+
+	duration := time.Since(requestStart(r.Context()))
+	if slowThreshold > 0 && duration >= slowThreshold && !isEventStream(rec.Header()) {
+		event := SlowEvent{
+			Kind:       slowRequestEvent,
+			Name:       routeTemplate(router, r),
+			Method:     r.Method,
+			Status:     rec.status,
+			UserID:     contextUserID(r.Context()),
+			RequestID:  logging.RequestID(r.Context()),
+			DurationMs: durationMs(duration),
+			At:         time.Now(),
+		}
+		slow.Add(event)
+		entry.WithFields(log.Fields{
+			"route":       event.Name,
+			"user_id":     event.UserID,
+			"duration_ms": event.DurationMs,
+		}).Warn("slow request")
+		return
+	}
+
+	level := log.InfoLevel
+	if isProbePath(r.URL.Path) {
+		level = log.DebugLevel
+	}
+	entry.Log(level, "handled request")
+}
+
+// isProbePath checks if the path belongs to a health probe, which are too frequent to be logged as info
+func isProbePath(path string) bool {
+	return path == "/healthz" || path == "/readyz"
 }
 
 const (
@@ -61,6 +400,7 @@ const (
 func (a *Application) JwtVerify(next http.HandlerFunc) http.HandlerFunc {
 	if a.conf.AppConfig.TestMode {
 		return func(w http.ResponseWriter, r *http.Request) {
+			recordCaller(r.Context(), "1")
 			newReq := r.WithContext(context.WithValue(r.Context(), "userID", "1"))
 			next.ServeHTTP(w, newReq)
 		}
@@ -72,29 +412,137 @@ func (a *Application) JwtVerify(next http.HandlerFunc) http.HandlerFunc {
 		platform := parsePlatformHeader(r.Header.Get("platform"))
 
 		if !strings.HasPrefix(tokenHeader, bearerHeaderPrefix) {
-			respondNoContent(w, http.StatusUnauthorized)
+			a.metrics.IncCounter(authFailuresMetric, metrics.Labels{"reason": "missing_token"})
+			respondError(w, http.StatusUnauthorized, ErrCodeMissingToken, "missing bearer token", nil)
 			return
 		}
 
 		token := strings.TrimPrefix(tokenHeader, bearerHeaderPrefix)
 
-		verifier := a.conf.AppConfig.OIDCProvider.Verifier(&oidc.Config{
-			ClientID: a.conf.AppConfig.GetPlatformClientID(platform),
-		})
-
-		parsedToken, err := verifier.Verify(r.Context(), token)
+		userID, err := a.verifyToken(r.Context(), token, platform)
+		if errors.Is(err, config.ErrOIDCUnavailable) {
+			respondAuthUnavailable(w)
+			return
+		}
 		if err != nil {
-			log.Errorln(err)
-			respondNoContent(w, http.StatusUnauthorized)
+			logging.FromContext(r.Context()).Errorln(err)
+			a.metrics.IncCounter(authFailuresMetric, metrics.Labels{"reason": "invalid_token"})
+			respondError(w, http.StatusUnauthorized, ErrCodeInvalidToken, "invalid or expired token", nil)
+			return
+		}
+		if err := a.checkActive(r.Context(), userID); err != nil {
+			respondRepositoryError(w, err)
 			return
 		}
 
-		newReq := r.WithContext(context.WithValue(r.Context(), "userID", parsedToken.Subject))
+		recordCaller(r.Context(), userID)
+		newReq := r.WithContext(context.WithValue(r.Context(), "userID", userID))
 
 		next.ServeHTTP(w, newReq)
 	}
 }
 
+// JwtVerifyOptional authenticates the user like JwtVerify when the request bears a token,
+// letting it through anonymous, without "userID" in its context, when it doesn't or the token is invalid
+func (a *Application) JwtVerifyOptional(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const bearerHeaderPrefix = "Bearer "
+		tokenHeader := r.Header.Get("Authorization")
+		if !strings.HasPrefix(tokenHeader, bearerHeaderPrefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID := "1"
+		if !a.conf.AppConfig.TestMode {
+			var err error
+			platform := parsePlatformHeader(r.Header.Get("platform"))
+			userID, err = a.verifyToken(r.Context(), strings.TrimPrefix(tokenHeader, bearerHeaderPrefix), platform)
+			if err != nil {
+				logging.FromContext(r.Context()).WithError(err).Info("continuing anonymous, invalid token")
+				a.metrics.IncCounter(authFailuresMetric, metrics.Labels{"reason": "invalid_token"})
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err := a.checkActive(r.Context(), userID); errors.Is(err, repositories.ErrUserDeactivated) {
+				logging.FromContext(r.Context()).Info("continuing anonymous, deactivated user")
+				next.ServeHTTP(w, r)
+				return
+			} else if err != nil {
+				respondRepositoryError(w, err)
+				return
+			}
+		}
+
+		recordCaller(r.Context(), userID)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), "userID", userID)))
+	}
+}
+
+// verifyToken verifies the ID token issued for the platform's client, returning the user it was issued to
+func (a *Application) verifyToken(ctx context.Context, token, platform string) (string, error) {
+	parsedToken, err := a.conf.AppConfig.VerifyIDToken(ctx, token, platform)
+	if err != nil {
+		return "", err
+	}
+	return parsedToken.Subject, nil
+}
+
+// checkActive checks the user of a verified token wasn't deactivated, failing with
+// repositories.ErrUserDeactivated when it was, for the token to be refused until it expires
+func (a *Application) checkActive(ctx context.Context, userID string) error {
+	now := time.Now()
+	deactivated, ok := a.deactivations.get(userID, now)
+	if !ok {
+		var err error
+		if deactivated, err = a.usersRepository.IsDeactivated(ctx, userID); err != nil {
+			return err
+		}
+		a.deactivations.set(userID, deactivated, now)
+	}
+	if deactivated {
+		a.metrics.IncCounter(authFailuresMetric, metrics.Labels{"reason": "deactivated_user"})
+		return repositories.ErrUserDeactivated
+	}
+	return nil
+}
+
+// caller is filled in by JwtVerify, letting the middleware that ran before it know who called
+type caller struct {
+	mu     sync.Mutex
+	userID string
+}
+
+func (c *caller) set(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.userID = userID
+}
+
+// UserID returns the authenticated user, empty while JwtVerify hasn't run
+func (c *caller) UserID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.userID
+}
+
+// withCaller returns a copy of ctx JwtVerify records the caller into, along with the caller.
+// A caller already in ctx is shared, so every middleware learns the same caller.
+func withCaller(ctx context.Context) (context.Context, *caller) {
+	if c, ok := ctx.Value(callerKey).(*caller); ok {
+		return ctx, c
+	}
+	c := &caller{}
+	return context.WithValue(ctx, callerKey, c), c
+}
+
+// recordCaller records the authenticated user for the middleware that asked for it
+func recordCaller(ctx context.Context, userID string) {
+	if c, ok := ctx.Value(callerKey).(*caller); ok {
+		c.set(userID)
+	}
+}
+
 func parsePlatformHeader(platformHeader string) string {
 	if platformHeader != Web && platformHeader != IOS && platformHeader != Android {
 		platformHeader = Web
@@ -0,0 +1,393 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/reporting"
+	"appdoki-be/config"
+	"bytes"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	t.Run("expect a panic before writing to respond with 500 and a JSON error", func(t *testing.T) {
+		m := metrics.NewFake()
+		h := recoveryMiddleware(log.StandardLogger(), m, reporting.Noop{}, mux.NewRouter())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusInternalServerError)
+		assertJSONContentType(t, resp)
+
+		assertErrorCode(t, resp, ErrCodeInternal)
+
+		if m.Counter(httpPanicsMetric, nil) != 1 {
+			t.Fatal("expected the panics counter to be incremented")
+		}
+	})
+
+	t.Run("expect a panic in any middleware of the routes but the request ID to respond with 500", func(t *testing.T) {
+		a := newTestApplication()
+		router := a.router()
+		panicking := func(http.Handler) http.Handler {
+			return http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") })
+		}
+
+		middlewares := a.middlewares(router)
+		for i := 2; i <= len(middlewares); i++ {
+			chain := append(append(append([]middleware{}, middlewares[:i]...), panicking), middlewares[i:]...)
+			r := httptest.NewRequest("GET", "/api/v1/users", nil)
+			w := httptest.NewRecorder()
+			middlewareChain(chain, router).ServeHTTP(w, r)
+
+			resp := w.Result()
+			assertStatusCode(t, resp, http.StatusInternalServerError)
+			if resp.Header.Get(logging.RequestIDHeader) == "" {
+				t.Fatalf("expected the panic after the middleware %d to be answered with a request ID", i)
+			}
+		}
+	})
+
+	t.Run("expect a panic after writing headers to abort the response", func(t *testing.T) {
+		h := recoveryMiddleware(log.StandardLogger(), metrics.NewFake(), reporting.Noop{}, mux.NewRouter())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("partial"))
+			panic("boom")
+		}))
+
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+
+		defer func() {
+			if err := recover(); err != http.ErrAbortHandler {
+				t.Fatalf("expected http.ErrAbortHandler panic, got %v", err)
+			}
+			assertStatusCode(t, w.Result(), http.StatusOK)
+		}()
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var ctxRequestID string
+	h := requestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctxRequestID = logging.RequestID(r.Context())
+	}))
+
+	t.Run("expect a request ID to be generated when none is sent", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		requestID := w.Result().Header.Get(logging.RequestIDHeader)
+		if requestID == "" {
+			t.Fatal("expected a generated request ID")
+		}
+		if ctxRequestID != requestID {
+			t.Fatalf("expected context request ID '%s', got '%s'", requestID, ctxRequestID)
+		}
+	})
+
+	t.Run("expect the client request ID to be propagated", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(logging.RequestIDHeader, "client-request-id")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if requestID := w.Result().Header.Get(logging.RequestIDHeader); requestID != "client-request-id" {
+			t.Fatalf("expected 'client-request-id', got '%s'", requestID)
+		}
+		if ctxRequestID != "client-request-id" {
+			t.Fatalf("expected context request ID 'client-request-id', got '%s'", ctxRequestID)
+		}
+	})
+
+	t.Run("expect an invalid client request ID to be replaced", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(logging.RequestIDHeader, "bad id\n")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if requestID := w.Result().Header.Get(logging.RequestIDHeader); requestID == "bad id\n" || requestID == "" {
+			t.Fatalf("expected a generated request ID, got '%s'", requestID)
+		}
+	})
+}
+
+func TestLoggerMiddleware(t *testing.T) {
+	t.Run("expect the requests to be logged by the logger of the application, with their request ID", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, _, _ := logging.New(config.LoggingConfig{Level: "info", Format: "json"})
+		logger.SetOutput(&buf)
+		a := newTestApplication()
+		a.logger = logger
+		routes := a.Routes()
+		buf.Reset()
+
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		r.Header.Set(logging.RequestIDHeader, "request-1")
+		routes.ServeHTTP(httptest.NewRecorder(), r)
+
+		var line map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+			t.Fatalf("expected a JSON line, got %s", buf.String())
+		}
+		if line["msg"] != "handled request" || line["request_id"] != "request-1" || line["level"] != "info" || line["time"] == nil {
+			t.Fatalf("unexpected line %v", line)
+		}
+	})
+
+	t.Run("expect a panicking request to be logged with its 500, along with the panic", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, _, _ := logging.New(config.LoggingConfig{Level: "info", Format: "json"})
+		logger.SetOutput(&buf)
+		a := newTestApplication()
+		a.logger = logger
+		router := a.router()
+		panicking := func(http.Handler) http.Handler {
+			return http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") })
+		}
+		buf.Reset()
+
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		r.Header.Set(logging.RequestIDHeader, "request-1")
+		middlewareChain(append(a.middlewares(router), panicking), router).ServeHTTP(httptest.NewRecorder(), r)
+
+		var lines []map[string]interface{}
+		for _, raw := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+			var line map[string]interface{}
+			if err := json.Unmarshal(raw, &line); err != nil {
+				t.Fatalf("expected JSON lines, got %s", buf.String())
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) != 2 || lines[0]["msg"] != "handled request" || lines[0]["status"] != float64(http.StatusInternalServerError) ||
+			lines[1]["msg"] != "recovered from panic" || lines[1]["request_id"] != "request-1" {
+			t.Fatalf("expected the request with its 500 and the panic, got %v", lines)
+		}
+	})
+}
+
+func TestApplication_JwtVerify(t *testing.T) {
+	a := &Application{
+		conf:    &config.Config{},
+		metrics: metrics.NewFake(),
+	}
+	h := a.JwtVerify(func(w http.ResponseWriter, r *http.Request) {
+		respondNoContent(w, http.StatusNoContent)
+	})
+
+	t.Run("expect a request without a token to return 401 and a missing_token error", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/users", nil)
+		r.Header.Set(logging.RequestIDHeader, "request-id")
+		w := httptest.NewRecorder()
+		requestIDMiddleware(h).ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusUnauthorized)
+		assertJSONContentType(t, resp)
+
+		var envelope errorEnvelope
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatal("failed to parse error envelope")
+		}
+		if envelope.Error.Code != ErrCodeMissingToken {
+			t.Fatalf("expected error code '%s', got '%s'", ErrCodeMissingToken, envelope.Error.Code)
+		}
+		if envelope.Error.RequestID != "request-id" {
+			t.Fatalf("expected request ID 'request-id', got '%s'", envelope.Error.RequestID)
+		}
+	})
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	newApplication := func(https bool) *Application {
+		a := newTestApplication()
+		a.conf.AppConfig.DocsEnabled = true
+		a.conf.AppConfig.SecurityHeaders = config.SecurityHeadersConfig{
+			HTTPS:                   https,
+			ContentTypeOptions:      "nosniff",
+			FrameOptions:            "DENY",
+			ReferrerPolicy:          "no-referrer",
+			StrictTransportSecurity: "max-age=31536000",
+			ContentSecurityPolicy:   config.DefaultContentSecurityPolicy,
+		}
+		return a
+	}
+
+	assertHeader := func(t *testing.T, resp *http.Response, key string, expected string) {
+		if value := resp.Header.Get(key); value != expected {
+			t.Fatalf("expected %s '%s', got '%s'", key, expected, value)
+		}
+	}
+
+	t.Run("expect API routes to have the security headers but no CSP", func(t *testing.T) {
+		routes := newApplication(false).Routes()
+
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertHeader(t, resp, "X-Content-Type-Options", "nosniff")
+		assertHeader(t, resp, "X-Frame-Options", "DENY")
+		assertHeader(t, resp, "Referrer-Policy", "no-referrer")
+		assertHeader(t, resp, "Strict-Transport-Security", "")
+		assertHeader(t, resp, "Content-Security-Policy", "")
+	})
+
+	t.Run("expect HSTS only when serving https", func(t *testing.T) {
+		routes := newApplication(true).Routes()
+
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		assertHeader(t, w.Result(), "Strict-Transport-Security", "max-age=31536000")
+	})
+
+	t.Run("expect the docs route to have a CSP", func(t *testing.T) {
+		routes := newApplication(false).Routes()
+
+		r := httptest.NewRequest("GET", "/api/docs/", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertHeader(t, resp, "X-Frame-Options", "DENY")
+		assertHeader(t, resp, "Content-Security-Policy", config.DefaultContentSecurityPolicy)
+	})
+
+	t.Run("expect empty values to leave headers out", func(t *testing.T) {
+		a := newApplication(false)
+		a.conf.AppConfig.SecurityHeaders.FrameOptions = ""
+		routes := a.Routes()
+
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		assertHeader(t, w.Result(), "X-Frame-Options", "")
+	})
+}
+
+func TestImplicitMethodsMiddleware(t *testing.T) {
+	routes := newTestApplication().Routes()
+
+	t.Run("expect HEAD on a GET route to return its headers without a body", func(t *testing.T) {
+		r := httptest.NewRequest("HEAD", "/api/v1/users/1", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+		if w.Body.Len() != 0 {
+			t.Fatalf("expected an empty body, got '%s'", w.Body.String())
+		}
+	})
+
+	t.Run("expect HEAD on a POST route to return 405", func(t *testing.T) {
+		r := httptest.NewRequest("HEAD", "/api/v1/users/1/beers/2", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusMethodNotAllowed)
+	})
+
+	t.Run("expect OPTIONS to return 204 with the allowed methods", func(t *testing.T) {
+		r := httptest.NewRequest("OPTIONS", "/api/v1/users/1", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusNoContent)
+		if allow := resp.Header.Get("Allow"); allow != "GET, HEAD, OPTIONS" {
+			t.Fatalf("expected Allow 'GET, HEAD, OPTIONS', got '%s'", allow)
+		}
+	})
+
+	t.Run("expect OPTIONS on an unknown path to return 404", func(t *testing.T) {
+		r := httptest.NewRequest("OPTIONS", "/api/v1/nope", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusNotFound)
+	})
+}
+
+func TestResponseTimeMiddleware(t *testing.T) {
+	t.Run("expect the response time header on a handler writing early", func(t *testing.T) {
+		h := responseTimeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("streamed"))
+			w.Header().Set("X-Late", "ignored")
+		}))
+
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Result().Header.Get(responseTimeHeader) == "" {
+			t.Fatalf("expected the %s header", responseTimeHeader)
+		}
+	})
+
+	t.Run("expect the response time header on a handler that doesn't write", func(t *testing.T) {
+		h := responseTimeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Result().Header.Get(responseTimeHeader) == "" {
+			t.Fatalf("expected the %s header", responseTimeHeader)
+		}
+	})
+
+	t.Run("expect the metrics and the header to share the same start", func(t *testing.T) {
+		m := metrics.NewFake()
+		router := mux.NewRouter()
+		router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(20 * time.Millisecond)
+			respondNoContent(w, http.StatusNoContent)
+		})
+		h := responseTimeMiddleware(metricsMiddleware(m, router)(router))
+
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		headerMs, err := strconv.ParseFloat(w.Result().Header.Get(responseTimeHeader), 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		observed := m.Observations(httpRequestDurationMetric, metrics.Labels{"route": "/", "method": "GET", "status": "2xx"})
+		if len(observed) != 1 {
+			t.Fatalf("expected 1 observation, got %d", len(observed))
+		}
+		if diff := observed[0]*1000 - headerMs; diff < 0 || diff > 5 {
+			t.Fatalf("expected the header (%.2fms) to match the metric (%.2fms)", headerMs, observed[0]*1000)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"context"
+	"github.com/gorilla/mux"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// notificationsPruneInterval is how often the notifications past their retention are pruned
+const notificationsPruneInterval = time.Hour
+
+// NotificationsHandler holds handler dependencies
+type NotificationsHandler struct {
+	notificationsRepo repositories.NotificationsRepositoryInterface
+	streams           *notify.Streams
+	notifier          notify.Notifier
+	workers           *workerGroup
+}
+
+// NewNotificationsHandler returns an initialized notifications handler with the required dependencies
+func NewNotificationsHandler(notificationsRepo repositories.NotificationsRepositoryInterface, streams *notify.Streams,
+	notifier notify.Notifier, workers *workerGroup) *NotificationsHandler {
+	return &NotificationsHandler{
+		notificationsRepo: notificationsRepo,
+		streams:           streams,
+		notifier:          notifier,
+		workers:           workers,
+	}
+}
+
+// NotificationsPage is a page of the notifications of the user, along with how many are unread overall
+type NotificationsPage struct {
+	Notifications []*repositories.Notification `json:"notifications"`
+	UnreadCount   int                          `json:"unread_count"`
+}
+
+// Get gets a page of the notifications sent to the user, the last first, paged with the
+// creation time of the last one as before
+func (h *NotificationsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	params := newQueryParams(r)
+	options := &repositories.NotificationListOptions{
+		Limit:      params.IntInRange("limit", 20, 1, 100),
+		Before:     params.Time("before", time.Now()),
+		UnreadOnly: params.Bool("unread", false),
+	}
+	if err := params.Err(); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+
+	notifications, err := h.notificationsRepo.ListByUser(r.Context(), userID, options)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	unread, err := h.notificationsRepo.CountUnread(r.Context(), userID)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	respondJSON(w, r, NotificationsPage{Notifications: notifications, UnreadCount: unread}, http.StatusOK)
+}
+
+// MarkRead marks a notification of the user read
+func (h *NotificationsHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "notification not found", nil)
+		return
+	}
+
+	found, err := h.notificationsRepo.MarkRead(r.Context(), userID, id)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	if !found {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "notification not found", nil)
+		return
+	}
+	h.syncUnread(r.Context(), userID)
+
+	respondNoContent(w, http.StatusNoContent)
+}
+
+// MarkAllRead marks every notification of the user read
+func (h *NotificationsHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	read, err := h.notificationsRepo.MarkAllRead(r.Context(), userID)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	if read > 0 {
+		h.syncUnread(r.Context(), userID)
+	}
+
+	respondNoContent(w, http.StatusNoContent)
+}
+
+// Stream streams the notifications sent to the user as they're dispatched, as notification events,
+// until the client disconnects. Every client of the user gets its own stream.
+func (h *NotificationsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+	notifications, unsubscribe := h.streams.Subscribe(userID)
+	defer unsubscribe()
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case n, ok := <-notifications:
+				if !ok {
+					return
+				}
+				event := Event{Name: "notification", Data: n}
+				if n.ID != 0 {
+					event.ID = strconv.FormatInt(n.ID, 10)
+				}
+				select {
+				case events <- event:
+				case <-r.Context().Done():
+					return
+				}
+			}
+		}
+	}()
+
+	streamEventsWithHeartbeat(w, r, events, notificationsHeartbeatInterval)
+}
+
+// syncUnread sends a silent push to the devices of the user with their unread notifications, for
+// every device to show the same count. FCM badges the iOS app icon with the same count.
+func (h *NotificationsHandler) syncUnread(ctx context.Context, userID string) {
+	h.workers.Go(ctx, func(ctx context.Context) {
+		unread, err := h.notificationsRepo.CountUnread(ctx, userID)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error counting the unread notifications of user %s: %v", userID, err)
+			return
+		}
+		h.notifier.SendToUser(ctx, userID, notify.Notification{
+			Data:        map[string]string{"unread_count": strconv.Itoa(unread)},
+			Event:       eventNotificationsRead,
+			Silent:      true,
+			CollapseKey: eventNotificationsRead,
+		})
+	})
+}
+
+// pruneNotifications deletes the notifications past their retention every interval until ctx is
+// done, doing nothing when they're kept forever
+func (a *Application) pruneNotifications(ctx context.Context, interval time.Duration) {
+	retention := time.Duration(a.conf.Notifications.HistoryRetentionDays) * 24 * time.Hour
+	if retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		deleted, err := a.notificationsRepository.DeleteOlderThan(ctx, time.Now().Add(-retention))
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error pruning the notifications history: %v", err)
+		} else if deleted > 0 {
+			logging.FromContext(ctx).Infof("pruned %d notifications past their retention", deleted)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// mockNotificationsRepository keeps the notifications history in memory
+type mockNotificationsRepository struct {
+	mu            sync.Mutex
+	notifications []*repos.Notification
+	nextID        int64
+}
+
+func newMockNotificationsRepository() *mockNotificationsRepository {
+	return &mockNotificationsRepository{}
+}
+
+func (r *mockNotificationsRepository) Create(_ context.Context, notification *repos.Notification) (*repos.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	created := *notification
+	created.ID = r.nextID
+	created.Status = repos.NotificationPending
+	if created.CreatedAt.IsZero() {
+		created.CreatedAt = time.Now()
+	}
+	r.notifications = append(r.notifications, &created)
+	saved := created
+	return &saved, nil
+}
+
+func (r *mockNotificationsRepository) SetStatus(_ context.Context, ID int64, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, n := range r.notifications {
+		if n.ID == ID {
+			n.Status = status
+		}
+	}
+	return nil
+}
+
+func (r *mockNotificationsRepository) ListByUser(_ context.Context, userID string, options *repos.NotificationListOptions) ([]*repos.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	page := []*repos.Notification{}
+	for _, n := range r.notifications {
+		if n.UserID == userID && n.CreatedAt.Before(options.Before) && (!options.UnreadOnly || n.ReadAt == nil) {
+			listed := *n
+			page = append(page, &listed)
+		}
+	}
+	sort.Slice(page, func(i, j int) bool {
+		return page[i].CreatedAt.After(page[j].CreatedAt)
+	})
+	if len(page) > options.Limit {
+		page = page[:options.Limit]
+	}
+	return page, nil
+}
+
+func (r *mockNotificationsRepository) CountUnread(_ context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unread := 0
+	for _, n := range r.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			unread++
+		}
+	}
+	return unread, nil
+}
+
+func (r *mockNotificationsRepository) MarkRead(_ context.Context, userID string, ID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, n := range r.notifications {
+		if n.ID == ID && n.UserID == userID {
+			if n.ReadAt == nil {
+				now := time.Now()
+				n.ReadAt = &now
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *mockNotificationsRepository) MarkAllRead(_ context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var read int64
+	now := time.Now()
+	for _, n := range r.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			n.ReadAt = &now
+			read++
+		}
+	}
+	return read, nil
+}
+
+func (r *mockNotificationsRepository) DeleteOlderThan(_ context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.notifications[:0]
+	for _, n := range r.notifications {
+		if !n.CreatedAt.Before(before) {
+			kept = append(kept, n)
+		}
+	}
+	deleted := int64(len(r.notifications) - len(kept))
+	r.notifications = kept
+	return deleted, nil
+}
+
+func (r *mockNotificationsRepository) CountSince(_ context.Context, since time.Time) ([]repos.NotificationCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := []repos.NotificationCount{}
+	index := map[[2]string]int{}
+	for _, n := range r.notifications {
+		if n.CreatedAt.Before(since) {
+			continue
+		}
+		key := [2]string{n.Event, n.Status}
+		if i, ok := index[key]; ok {
+			counts[i].Count++
+			continue
+		}
+		index[key] = len(counts)
+		counts = append(counts, repos.NotificationCount{Event: n.Event, Status: n.Status, Count: 1})
+	}
+	return counts, nil
+}
@@ -0,0 +1,288 @@
+package app
+
+import (
+	"appdoki-be/app/notify"
+	repos "appdoki-be/app/repositories"
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotificationsHandler_Get(t *testing.T) {
+	a := newTestApplication()
+	repo := a.notificationsRepository.(*mockNotificationsRepository)
+	now := time.Now()
+	readAt := now.Add(-time.Minute)
+	for i, n := range []*repos.Notification{
+		{UserID: "1", Title: "oldest", CreatedAt: now.Add(-3 * time.Hour)},
+		{UserID: "1", Title: "read", CreatedAt: now.Add(-2 * time.Hour), ReadAt: &readAt},
+		{UserID: "1", Title: "latest", CreatedAt: now.Add(-time.Hour)},
+		{UserID: "2", Title: "someone else's", CreatedAt: now.Add(-time.Hour)},
+	} {
+		repo.Create(context.Background(), n)
+		repo.notifications[i].ReadAt = n.ReadAt
+	}
+	routes := a.Routes()
+
+	page := func(t *testing.T, query string) NotificationsPage {
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/users/me/notifications"+query, nil))
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+
+		var page NotificationsPage
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			t.Fatal(err)
+		}
+		return page
+	}
+	list := func(t *testing.T, query string) []*repos.Notification {
+		return page(t, query).Notifications
+	}
+
+	t.Run("expect the notifications of the user, the latest first", func(t *testing.T) {
+		notifications := list(t, "")
+
+		if len(notifications) != 3 || notifications[0].Title != "latest" || notifications[2].Title != "oldest" {
+			t.Fatalf("unexpected notifications %+v", notifications)
+		}
+		if notifications[0].Status != repos.NotificationPending {
+			t.Fatalf("expected the delivery status, got %q", notifications[0].Status)
+		}
+	})
+
+	t.Run("expect the pages to follow the creation time of the last notification", func(t *testing.T) {
+		first := list(t, "?limit=2")
+		next := list(t, "?limit=2&before="+first[1].CreatedAt.Format(time.RFC3339Nano))
+
+		if len(first) != 2 || len(next) != 1 || next[0].Title != "oldest" {
+			t.Fatalf("unexpected pages %+v then %+v", first, next)
+		}
+	})
+
+	t.Run("expect the unread filter to skip the read notifications", func(t *testing.T) {
+		for _, n := range list(t, "?unread=true") {
+			if n.Title == "read" {
+				t.Fatal("expected the read notification to be skipped")
+			}
+		}
+	})
+
+	t.Run("expect the unread notifications of the user to be counted over every page", func(t *testing.T) {
+		if unread := page(t, "?limit=1").UnreadCount; unread != 2 {
+			t.Fatalf("expected 2 unread notifications, got %d", unread)
+		}
+	})
+
+	t.Run("expect an invalid limit to return 400", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/users/me/notifications?limit=0", nil))
+
+		assertStatusCode(t, w.Result(), http.StatusBadRequest)
+	})
+}
+
+func TestNotificationsHandler_MarkRead(t *testing.T) {
+	newApplication := func() (*Application, *mockNotificationsRepository) {
+		a := newTestApplication()
+		repo := a.notificationsRepository.(*mockNotificationsRepository)
+		repo.Create(context.Background(), &repos.Notification{UserID: "1", Title: "mine"})
+		repo.Create(context.Background(), &repos.Notification{UserID: "1", Title: "mine too"})
+		repo.Create(context.Background(), &repos.Notification{UserID: "2", Title: "someone else's"})
+		return a, repo
+	}
+	post := func(a *Application, path string) *http.Response {
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/users/me/notifications"+path, nil))
+		return w.Result()
+	}
+
+	t.Run("expect a notification of the user to be read and their devices told the unread count", func(t *testing.T) {
+		a, repo := newApplication()
+
+		assertStatusCode(t, post(a, "/1/read"), http.StatusNoContent)
+
+		if unread, _ := repo.CountUnread(context.Background(), "1"); unread != 1 {
+			t.Fatalf("expected 1 unread notification left, got %d", unread)
+		}
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		sent := a.notifier.(*notify.Fake).Sent()
+		if len(sent) != 1 || !sent[0].Notification.Silent || sent[0].Notification.Data["unread_count"] != "1" {
+			t.Fatalf("expected a silent push with the unread count, got %+v", sent)
+		}
+	})
+
+	t.Run("expect the notifications of other users to be out of reach", func(t *testing.T) {
+		a, repo := newApplication()
+
+		resp := post(a, "/3/read")
+
+		assertStatusCode(t, resp, http.StatusNotFound)
+		assertErrorCode(t, resp, ErrCodeNotFound)
+		if unread, _ := repo.CountUnread(context.Background(), "2"); unread != 1 {
+			t.Fatal("expected the notification of user 2 to be left unread")
+		}
+		assertStatusCode(t, post(a, "/abc/read"), http.StatusNotFound)
+	})
+
+	t.Run("expect every notification of the user alone to be read", func(t *testing.T) {
+		a, repo := newApplication()
+
+		assertStatusCode(t, post(a, "/read-all"), http.StatusNoContent)
+
+		if unread, _ := repo.CountUnread(context.Background(), "1"); unread != 0 {
+			t.Fatalf("expected no unread notification left, got %d", unread)
+		}
+		if unread, _ := repo.CountUnread(context.Background(), "2"); unread != 1 {
+			t.Fatal("expected the notification of user 2 to be left unread")
+		}
+	})
+}
+
+// streamLines sends the lines of the stream as they come, closing the channel when it ends
+func streamLines(body io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+// nextLine waits for the next line of the stream starting with prefix, skipping the others
+func nextLine(t *testing.T, lines <-chan string, prefix string) string {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				t.Fatalf("expected a line starting with '%s' before the stream ended", prefix)
+			}
+			if strings.HasPrefix(line, prefix) {
+				return line
+			}
+		case <-timeout:
+			t.Fatalf("expected a line starting with '%s'", prefix)
+		}
+	}
+}
+
+func TestNotificationsHandler_Stream(t *testing.T) {
+	defer func(interval time.Duration) { notificationsHeartbeatInterval = interval }(notificationsHeartbeatInterval)
+	notificationsHeartbeatInterval = 50 * time.Millisecond
+
+	a := newTestApplication()
+	a.conf.Server.RequestTimeout = 50 * time.Millisecond
+	a.conf.Server.StreamRequestTimeout = 5 * time.Second
+	srv := httptest.NewServer(a.Routes())
+	defer srv.Close()
+	notifier := notify.WithStreams(notify.NewFake(), a.notificationStreams)
+
+	open := func(t *testing.T) (*http.Response, <-chan string) {
+		resp, err := http.Get(srv.URL + "/api/v1/users/me/notifications/stream")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertStatusCode(t, resp, http.StatusOK)
+		if contentType := resp.Header.Get("Content-Type"); contentType != eventStreamContentType {
+			t.Fatalf("expected an event stream, got '%s'", contentType)
+		}
+		lines := streamLines(resp.Body)
+		nextLine(t, lines, "retry:")
+		return resp, lines
+	}
+	waitForSubscribers := func(t *testing.T, count int) {
+		for deadline := time.Now().Add(time.Second); a.notificationStreams.Subscribers("1") != count; {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d streams, got %d", count, a.notificationStreams.Subscribers("1"))
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	t.Run("expect the notifications of the user to reach every tab", func(t *testing.T) {
+		first, firstLines := open(t)
+		defer first.Body.Close()
+		second, secondLines := open(t)
+		defer second.Body.Close()
+		waitForSubscribers(t, 2)
+
+		notifier.SendToUser(context.Background(), "2", notify.Notification{Title: "Not yours"})
+		notifier.SendToUser(context.Background(), "1", notify.Notification{Title: "Cheers!", Event: notify.EventBeerReceived})
+
+		for _, lines := range []<-chan string{firstLines, secondLines} {
+			nextLine(t, lines, "event: notification")
+			var got notify.StreamedNotification
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(nextLine(t, lines, "data: "), "data: ")), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Title != "Cheers!" || got.Event != notify.EventBeerReceived {
+				t.Fatalf("expected the notification of the user, got %+v", got)
+			}
+		}
+	})
+
+	t.Run("expect a heartbeat on idle streams", func(t *testing.T) {
+		resp, lines := open(t)
+		defer resp.Body.Close()
+
+		nextLine(t, lines, ": heartbeat")
+	})
+
+	t.Run("expect the subscription to be removed when the client disconnects", func(t *testing.T) {
+		waitForSubscribers(t, 0)
+		resp, _ := open(t)
+		waitForSubscribers(t, 1)
+
+		resp.Body.Close()
+		waitForSubscribers(t, 0)
+	})
+}
+
+func TestNotificationHistory(t *testing.T) {
+	t.Run("expect the notifications to users to be recorded with their delivery status", func(t *testing.T) {
+		repo := newMockNotificationsRepository()
+		history := notificationHistory{repo}
+
+		id, err := history.Record(context.Background(), "42", notify.Notification{Title: "Cheers!", Event: notify.EventBeerReceived})
+		if err != nil {
+			t.Fatal(err)
+		}
+		history.SetStatus(context.Background(), id, notify.DeliverySent)
+
+		n := repo.notifications[0]
+		if n.UserID != "42" || n.Event != notify.EventBeerReceived || n.Status != repos.NotificationSent {
+			t.Fatalf("unexpected notification %+v", n)
+		}
+	})
+}
+
+func TestApplication_pruneNotifications(t *testing.T) {
+	t.Run("expect the notifications past their retention to be pruned", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.Notifications.HistoryRetentionDays = 30
+		repo := a.notificationsRepository.(*mockNotificationsRepository)
+		repo.Create(context.Background(), &repos.Notification{UserID: "1", Title: "old", CreatedAt: time.Now().AddDate(0, 0, -31)})
+		repo.Create(context.Background(), &repos.Notification{UserID: "1", Title: "recent", CreatedAt: time.Now().AddDate(0, 0, -29)})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		a.pruneNotifications(ctx, time.Hour)
+
+		if len(repo.notifications) != 1 || repo.notifications[0].Title != "recent" {
+			t.Fatalf("expected the old notification alone to be pruned, got %+v", repo.notifications)
+		}
+	})
+}
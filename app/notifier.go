@@ -1,80 +1,388 @@
 package app
 
 import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/reporting"
+	"appdoki-be/app/repositories"
 	"context"
 	firebase "firebase.google.com/go/v4"
-	"firebase.google.com/go/v4/messaging"
 	log "github.com/sirupsen/logrus"
 )
 
 const beersTopic = "beers"
 const usersTopic = "users"
+const integrationsTopic = "integrations"
 
-type notifyService struct {
-	client           *messaging.Client
-	androidMsgConfig *messaging.AndroidConfig
-	apnsMsgConfig    *messaging.APNSConfig
-	dryRun           bool
+// allUsersTopic is the topic every registered device is subscribed to, for the announcements
+const allUsersTopic = "all-users"
+
+// the events of the notifications rendered from templates, besides the ones of notify
+const (
+	eventBeerGiven               = "beer_given"
+	eventGitHubStar              = "github_star"
+	eventGitHubPullRequestMerged = "github_pull_request_merged"
+	eventWelcome                 = "welcome"
+)
+
+// digestPayload is the payload of the digest event, the beers received over the period
+type digestPayload struct {
+	Period string
+	Beers  int
+	Givers int
+}
+
+// the events of the silent pushes: the profile of the user changed, their notifications were read
+const (
+	eventUserUpdated       = "user_updated"
+	eventNotificationsRead = "notifications_read"
+)
+
+// beerTransferPayload is the payload of the beer_given and beer_received events. Givers is how many
+// gave the beers received within a coalescing window, 0 outside of one, TransferID being the last
+// transfer of the window.
+type beerTransferPayload struct {
+	TransferID int
+	Giver      string
+	Receiver   string
+	Beers      int
+	Givers     int
 }
 
-type notifier interface {
-	notifyAll(topic string, notification *messaging.Notification, data map[string]string)
-	messageAll(topic string, content map[string]string)
+// notificationSamples are the sample payloads of the events with a template, checked against
+// the templates on startup
+var notificationSamples = map[string]interface{}{
+	eventBeerGiven:               beerTransferPayload{TransferID: 42, Giver: "Alice", Receiver: "Bob", Beers: 2},
+	notify.EventBeerReceived:     beerTransferPayload{TransferID: 42, Giver: "Alice", Receiver: "Bob", Beers: 5, Givers: 3},
+	eventGitHubStar:              &WebhookEvent{Actor: "octocat", Subject: "Cloudoki/appdoki-be"},
+	eventGitHubPullRequestMerged: &WebhookEvent{Actor: "octocat", Subject: "Add beer streaks"},
+	notify.EventDigest:           digestPayload{Period: "week", Beers: 5, Givers: 3},
+	eventWelcome:                 welcomePayload{Name: "Alice"},
 }
 
-func newNotifier(app *firebase.App, dryRun bool) (*notifyService, error) {
-	ctx := context.Background()
-	client, err := app.Messaging(ctx)
+const (
+	notificationsSuppressedMetric   = "notifications_suppressed_total"
+	notificationsCoalescedMetric    = "notifications_coalesced_total"
+	notificationsFailedMetric       = "notifications_failed_total"
+	notificationsDeadLetteredMetric = "notifications_dead_lettered_total"
+)
+
+// the reasons of the notifications suppressed
+const (
+	suppressedByPreference = "preference"
+	suppressedByCeiling    = "ceiling"
+)
+
+// newNotifier returns the notifier dispatching to the channels enabled by the configuration, FCM and
+// email by default, reaching users on the devices they registered unless they opted out of their
+// event. The sends are queued for the notifications workers, the failed ones retried in the
+// background workers and the ones given up on reported and kept as dead letters. The notifications
+// to users are kept in their history along with their FCM delivery, the ones beyond the hourly
+// ceiling of the user only kept there. The bursts of notifications of an event to a user are
+// coalesced into one, and the ones in the quiet hours of the user deferred until they end. The test
+// notifications are sent to FCM right away instead, by the push notifier, and the emails of the
+// events needing one rather than a push by the mailer. Every attempt to send through a channel is
+// counted and timed. The sends left in the queue or between retries on shutdown are kept as pending
+// dead letters, replayed on the next start.
+// Their copy and deep link are rendered from the templates of their event, which are validated first.
+func (a *Application) newNotifier(app *firebase.App, templates *notify.Templates) (*notify.Queue, error) {
+	history := notificationHistory{a.notificationsRepository}
+	pipeline, err := a.newDispatchPipeline(a.channelSenders(app, history), history)
 	if err != nil {
-		log.Errorf("error getting Messaging client: %v\n", err)
 		return nil, err
 	}
+	a.pushNotifier = pipeline.push
+	if pipeline.email != nil {
+		a.mailer = notify.WithTemplates(pipeline.email, templates, a.recipientLocales())
+	}
+	dispatcher := pipeline.dispatcher
+	failed := notificationFailed(a.metrics, a.errorReporter)
 
-	return &notifyService{
-		dryRun: dryRun,
-		client: client,
-		androidMsgConfig: &messaging.AndroidConfig{
-			Priority: "high",
-		},
-		apnsMsgConfig: &messaging.APNSConfig{
-			Payload: &messaging.APNSPayload{
-				Aps: &messaging.Aps{
-					ContentAvailable: true,
-				},
-			},
-		},
-	}, nil
-}
-
-func (n *notifyService) notifyAll(topic string, notification *messaging.Notification, data map[string]string) {
-	n.sendMessage(&messaging.Message{
-		Data:         data,
-		Notification: notification,
-		Topic:        topic,
-		Android:      n.androidMsgConfig,
-		APNS:         n.apnsMsgConfig,
-	})
+	conf := a.conf.Notifications
+	capped := notify.WithUserCeiling(notify.WithStreams(dispatcher, a.notificationStreams), conf.UserHourlyCeiling, a.rateLimiter, history, countSuppressed(a.metrics, suppressedByCeiling))
+	a.quietHours = notify.WithQuietHours(capped, quietHours{a.preferencesRepository}, deferredNotifications{a.deferredRepository}, history, countDeferred(a.metrics))
+	preferring := notify.WithPreferences(notify.WithHistory(a.quietHours, history), notificationPreferences{a.preferencesRepository}, countSuppressed(a.metrics, suppressedByPreference))
+	linked := notify.WithLinks(preferring, a.notificationLinks())
+	coalescing := notify.WithCoalescing(notify.WithTemplates(linked, templates, a.recipientLocales()), notify.CoalesceConfig{
+		Window: conf.CoalesceWindow,
+		Merge:  map[string]notify.MergeFunc{notify.EventBeerReceived: mergeBeersReceived},
+	}, notify.NewMemoryCoalesceStore(), a.workers.Go, countCoalesced(a.metrics))
+	return notify.NewQueue(coalescing, notify.QueueConfig{
+		Size:         a.conf.Notifications.QueueSize,
+		Workers:      a.conf.Notifications.QueueWorkers,
+		SyncWhenFull: a.conf.Notifications.QueueFullSync,
+	}, a.metrics, a.deadLettering(deadLetterQueue, queuedSender{coalescing}, failed)), nil
 }
 
-func (n *notifyService) messageAll(topic string, content map[string]string) {
-	n.sendMessage(&messaging.Message{
-		Data:    content,
-		Topic:   topic,
-		Android: n.androidMsgConfig,
-		APNS:    n.apnsMsgConfig,
-	})
+// loadNotificationTemplates loads the templates of the notifications copy, validated against the samples
+func loadNotificationTemplates(dir string) (*notify.Templates, error) {
+	templates, err := notify.LoadTemplates(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := templates.Validate(notificationSamples); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// notificationLinks returns the bases of the deep links, the web one being PUBLIC_BASE_URL unless
+// NOTIFICATIONS_WEB_LINK_BASE is set
+func (a *Application) notificationLinks() notify.Links {
+	links := notify.Links{AppBase: a.conf.Notifications.DeepLinkBase, WebBase: a.conf.Notifications.WebLinkBase}
+	if links.WebBase == "" && a.conf.AppConfig.PublicBaseURL != "" {
+		links.WebBase = a.absoluteURL(nil, "/", nil)
+	}
+	return links
+}
+
+func (a *Application) recipientLocales() notify.Locales {
+	return recipientLocales{devices: a.devicesRepository, prefs: a.preferencesRepository}
+}
+
+func (a *Application) retryPolicy() notify.RetryPolicy {
+	return notify.RetryPolicy{
+		MaxAttempts: a.conf.Notifications.RetryMaxAttempts,
+		BaseDelay:   a.conf.Notifications.RetryBaseDelay,
+		MaxDelay:    a.conf.Notifications.RetryMaxDelay,
+	}
+}
+
+// notificationFailed logs, counts and reports the sends given up on, the ones interrupted by the
+// shutdown being kept rather than reported
+func notificationFailed(m metrics.Metrics, reporter reporting.ErrorReporter) notify.FailureHandler {
+	return func(ctx context.Context, failure notify.Failure) {
+		reason := "permanent"
+		switch {
+		case failure.Interrupted:
+			reason = "interrupted"
+		case failure.Exhausted():
+			reason = "exhausted"
+		}
+		m.IncCounter(notificationsFailedMetric, metrics.Labels{"op": failure.Op, "reason": reason})
+
+		entry := logging.FromContext(ctx).WithFields(log.Fields{
+			"op":       failure.Op,
+			"target":   failure.Target,
+			"summary":  failure.Summary,
+			"attempts": failure.Attempts,
+			"reason":   reason,
+		}).WithError(failure.Err)
+		if failure.Interrupted {
+			// kept to be sent once the application starts again
+			entry.Warn("left a notification as the application stops")
+			return
+		}
+		entry.Error("gave up on a notification")
+		captureError(reporter, ctx, failure.Err, map[string]string{
+			"worker": "notifier",
+			"op":     failure.Op,
+			"reason": reason,
+		})
+	}
+}
+
+// countSuppressed counts the notifications skipped for the reason, by event
+func countSuppressed(m metrics.Metrics, reason string) notify.SuppressedHandler {
+	return func(_ context.Context, event string) {
+		m.IncCounter(notificationsSuppressedMetric, metrics.Labels{"event": event, "reason": reason})
+	}
+}
+
+// countCoalesced counts the notifications merged into another, by event
+func countCoalesced(m metrics.Metrics) notify.CoalescedHandler {
+	return func(_ context.Context, event string, count int) {
+		m.AddCounter(notificationsCoalescedMetric, float64(count), metrics.Labels{"event": event})
+	}
+}
+
+// mergeBeersReceived merges the beers received within a coalescing window into a notification of
+// their total, keeping the data of the last transfer
+func mergeBeersReceived(held []notify.Notification) notify.Notification {
+	merged := held[len(held)-1]
+	payload := beerTransferPayload{}
+	givers := map[string]bool{}
+	for _, n := range held {
+		transfer, ok := n.Payload.(beerTransferPayload)
+		if !ok {
+			return merged
+		}
+		payload.TransferID, payload.Giver, payload.Receiver = transfer.TransferID, transfer.Giver, transfer.Receiver
+		payload.Beers += transfer.Beers
+		givers[transfer.Giver] = true
+	}
+	payload.Givers = len(givers)
+
+	merged.Payload = payload
+	return merged
+}
+
+// deviceTokens resolves the tokens of the users for the notifier from the devices they registered
+type deviceTokens struct {
+	devices repositories.DevicesRepositoryInterface
+}
+
+func (t deviceTokens) UserDevices(ctx context.Context, userID string) ([]notify.Device, error) {
+	devices, err := t.devices.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make([]notify.Device, 0, len(devices))
+	for _, device := range devices {
+		resolved = append(resolved, notify.Device{Token: device.Token, Platform: device.Platform})
+	}
+	return resolved, nil
+}
+
+func (t deviceTokens) Forget(ctx context.Context, tokens []string) error {
+	return t.devices.DeleteTokens(ctx, tokens)
 }
 
-func (n *notifyService) sendMessage(message *messaging.Message) {
-	sendFunc := n.client.Send
-	if n.dryRun {
-		sendFunc = n.client.SendDryRun
+// notificationPreferences tells the notifier which events the users want to be notified of
+type notificationPreferences struct {
+	prefs repositories.PreferencesRepositoryInterface
+}
+
+func (p notificationPreferences) Wants(ctx context.Context, userID string, event string) (bool, error) {
+	prefs, err := p.prefs.FindNotifications(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	switch event {
+	case notify.EventBeerReceived:
+		return prefs.BeerReceived, nil
+	case notify.EventNewUser:
+		return prefs.NewUser, nil
+	case notify.EventDigest:
+		return prefs.Digest, nil
+	}
+	return true, nil
+}
+
+// recipientLocales resolves the locales of the recipients of the notifications from their preferences
+type recipientLocales struct {
+	devices repositories.DevicesRepositoryInterface
+	prefs   repositories.PreferencesRepositoryInterface
+}
+
+func (l recipientLocales) UserLocale(ctx context.Context, userID string) (string, error) {
+	prefs, err := l.prefs.FindNotifications(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return prefs.Locale, nil
+}
+
+func (l recipientLocales) TokenLocales(ctx context.Context, tokens []string) (map[string]string, error) {
+	owners, err := l.devices.ListOwnersByTokens(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(owners))
+	seen := map[string]bool{}
+	for _, userID := range owners {
+		if !seen[userID] {
+			seen[userID] = true
+			userIDs = append(userIDs, userID)
+		}
+	}
+	byUser, err := l.prefs.FindLocales(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	locales := make(map[string]string, len(owners))
+	for token, userID := range owners {
+		locales[token] = byUser[userID]
+	}
+	return locales, nil
+}
+
+// pushSubscriptions resolves the Web Push subscriptions of the users and devices from the devices
+// they registered
+type pushSubscriptions struct {
+	devices repositories.DevicesRepositoryInterface
+}
+
+func (s pushSubscriptions) UserSubscriptions(ctx context.Context, userID string) ([]notify.WebPushSubscription, error) {
+	devices, err := s.devices.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return webPushSubscriptions(devices), nil
+}
+
+func (s pushSubscriptions) TokenSubscriptions(ctx context.Context, tokens []string) ([]notify.WebPushSubscription, error) {
+	devices, err := s.devices.ListByTokens(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+	return webPushSubscriptions(devices), nil
+}
+
+func (s pushSubscriptions) Forget(ctx context.Context, tokens []string) error {
+	return s.devices.DeleteTokens(ctx, tokens)
+}
+
+// webPushSubscriptions returns the subscriptions of the devices subscribed with Web Push
+func webPushSubscriptions(devices []*repositories.DeviceToken) []notify.WebPushSubscription {
+	subscriptions := make([]notify.WebPushSubscription, 0, len(devices))
+	for _, device := range devices {
+		if device.Subscription == nil {
+			continue
+		}
+		subscriptions = append(subscriptions, notify.WebPushSubscription{
+			Token:    device.Token,
+			Endpoint: device.Subscription.Endpoint,
+			P256dh:   device.Subscription.Keys.P256dh,
+			Auth:     device.Subscription.Keys.Auth,
+		})
 	}
+	return subscriptions
+}
+
+// emailAddresses resolves the email addresses of the users for the mailer
+type emailAddresses struct {
+	users repositories.UsersRepositoryInterface
+}
+
+func (e emailAddresses) UserEmail(ctx context.Context, userID string) (string, error) {
+	user, err := e.users.FindByID(ctx, userID)
+	if err != nil || user == nil {
+		return "", err
+	}
+	return user.Email, nil
+}
 
-	response, err := sendFunc(context.Background(), message)
+// notificationHistory keeps the notifications sent to the users in the notifications repository
+type notificationHistory struct {
+	notifications repositories.NotificationsRepositoryInterface
+}
+
+func (h notificationHistory) Record(ctx context.Context, userID string, n notify.Notification) (int64, error) {
+	recorded, err := h.notifications.Create(ctx, &repositories.Notification{
+		UserID:   userID,
+		Event:    n.Event,
+		Title:    n.Title,
+		Body:     n.Body,
+		Data:     n.Data,
+		DeepLink: n.DeepLink,
+	})
 	if err != nil {
-		log.Errorf("error sending message to topic %s: %v\n", message.Topic, err)
+		return 0, err
 	}
+	return recorded.ID, nil
+}
+
+func (h notificationHistory) SetStatus(ctx context.Context, id int64, status string) error {
+	return h.notifications.SetStatus(ctx, id, status)
+}
 
-	log.Infof("successfully sent message with id %s", response)
+// Unread counts the unread notifications of the user, badging the app icon
+func (h notificationHistory) Unread(ctx context.Context, userID string) (int, error) {
+	return h.notifications.CountUnread(ctx, userID)
 }
@@ -0,0 +1,235 @@
+package app
+
+import (
+	"appdoki-be/app/notify"
+	"appdoki-be/config"
+	firebase "firebase.google.com/go/v4"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// noopChannel is the channel only logging the notifications, replacing every other in the noop mode
+const noopChannel = "noop"
+
+// the decorators of the sends of every channel
+const (
+	decoratorRetry   = "retry"
+	decoratorDedup   = "dedup"
+	decoratorMetrics = "metrics"
+)
+
+// notifierChannels are the channels the notifications can be dispatched to
+var notifierChannels = []string{deadLetterFCM, deadLetterWebPush, deadLetterSlack, deadLetterEmail, deadLetterWebhook, noopChannel}
+
+// notifierDecorators are the decorators the sends of the channels can be wrapped with
+var notifierDecorators = []string{decoratorRetry, decoratorDedup, decoratorMetrics}
+
+// routableEvents are the events of the notifications, which can be routed to channels of their own
+var routableEvents = []string{
+	eventBeerGiven, notify.EventBeerReceived, notify.EventNewUser, notify.EventDigest, eventGitHubStar,
+	eventGitHubPullRequestMerged, eventWelcome, eventUserUpdated, eventNotificationsRead,
+}
+
+// newSenderFunc returns the sender of an enabled channel
+type newSenderFunc func(channel string) (notify.Sender, error)
+
+// dispatchPipeline is the part of the notifier assembled from the configuration: the enabled
+// channels, their sends wrapped with the decorators, and the routes of the events to them
+type dispatchPipeline struct {
+	// dispatcher sends to the channels the event of the notifications is routed to, every enabled
+	// one but email by default
+	dispatcher *notify.Dispatcher
+	// push sends to FCM and Web Push right away, for the test notifications
+	push *notify.Dispatcher
+	// email is the email channel, nil when disabled
+	email notify.Notifier
+}
+
+// validateNotifierConfig checks the channels, routes and decorators of the notifier configuration,
+// telling what's wrong with them
+func validateNotifierConfig(conf *config.Config) error {
+	notifier := conf.Notifier
+	if notifier.Mode != "" && notifier.Mode != noopChannel {
+		return fmt.Errorf("invalid NOTIFIER %q, expected %s or nothing", notifier.Mode, noopChannel)
+	}
+
+	enabled := map[string]bool{}
+	for _, channel := range conf.NotifierChannels() {
+		if !containsString(notifierChannels, channel) {
+			return fmt.Errorf("unknown notifier channel %q, expected any of %s", channel, strings.Join(notifierChannels, ", "))
+		}
+		if enabled[channel] {
+			return fmt.Errorf("the notifier channel %q is enabled twice", channel)
+		}
+		enabled[channel] = true
+	}
+	if enabled[deadLetterWebPush] && conf.WebPush.VAPIDPrivateKey == "" {
+		return fmt.Errorf("the %s channel needs WEBPUSH_VAPID_PUBLIC_KEY and WEBPUSH_VAPID_PRIVATE_KEY", deadLetterWebPush)
+	}
+	if enabled[deadLetterSlack] && conf.Slack.WebhookURL == "" {
+		return fmt.Errorf("the %s channel needs SLACK_WEBHOOK_URL", deadLetterSlack)
+	}
+
+	if notifier.Mode != noopChannel {
+		events := make([]string, 0, len(notifier.Routes))
+		for event := range notifier.Routes {
+			events = append(events, event)
+		}
+		sort.Strings(events)
+		for _, event := range events {
+			if !containsString(routableEvents, event) {
+				return fmt.Errorf("unknown event %q routed, expected any of %s", event, strings.Join(routableEvents, ", "))
+			}
+			for _, channel := range notifier.Routes[event] {
+				if !containsString(notifierChannels, channel) {
+					return fmt.Errorf("event %q is routed to the unknown channel %q", event, channel)
+				}
+				if !enabled[channel] {
+					return fmt.Errorf("event %q is routed to the disabled channel %q", event, channel)
+				}
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, decorator := range notifier.Decorators {
+		if !containsString(notifierDecorators, decorator) {
+			return fmt.Errorf("unknown notifier decorator %q, expected any of %s", decorator, strings.Join(notifierDecorators, ", "))
+		}
+		if seen[decorator] {
+			return fmt.Errorf("the notifier decorator %q is listed twice", decorator)
+		}
+		seen[decorator] = true
+		if decorator == decoratorRetry && i > 0 {
+			return fmt.Errorf("the %s decorator must be listed first, the others wrapping every attempt", decoratorRetry)
+		}
+	}
+	if seen[decoratorDedup] && notifier.DedupWindow <= 0 {
+		return fmt.Errorf("the %s decorator needs a positive NOTIFIER_DEDUP_WINDOW", decoratorDedup)
+	}
+	return nil
+}
+
+// containsString tells if the value is one of the values
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// channelSenders returns the senders of the channels, set up from their configuration
+func (a *Application) channelSenders(app *firebase.App, history notificationHistory) newSenderFunc {
+	return func(channel string) (notify.Sender, error) {
+		switch channel {
+		case deadLetterFCM:
+			service, err := notify.NewFCM(app, notify.FCMConfig{
+				DryRun:           a.conf.AppConfig.TestMode,
+				AndroidChannelID: a.conf.Notifications.AndroidChannelID,
+			}, deviceTokens{a.devicesRepository}, history, a.metrics)
+			if err != nil {
+				return nil, err
+			}
+			return service, nil
+		case deadLetterWebPush:
+			service, err := notify.NewWebPush(notify.WebPushConfig{
+				VAPIDPublicKey:  a.conf.WebPush.VAPIDPublicKey,
+				VAPIDPrivateKey: a.conf.WebPush.VAPIDPrivateKey,
+				Subject:         a.conf.WebPush.Subject,
+				DryRun:          a.conf.AppConfig.TestMode,
+			}, pushSubscriptions{a.devicesRepository}, a.httpClients)
+			if err != nil {
+				return nil, err
+			}
+			return service, nil
+		case deadLetterSlack:
+			return notify.NewSlack(notify.SlackConfig{WebhookURL: a.conf.Slack.WebhookURL, Topics: a.conf.Slack.Topics}, a.httpClients), nil
+		case deadLetterEmail:
+			conf := a.conf.Email
+			return notify.NewEmail(notify.EmailConfig{
+				Host:     conf.Host,
+				Port:     conf.Port,
+				Username: conf.Username,
+				Password: conf.Password,
+				From:     conf.From,
+				DryRun:   conf.DryRun || conf.Host == "",
+			}, emailAddresses{a.usersRepository}), nil
+		case deadLetterWebhook:
+			return a.outboundWebhooks, nil
+		}
+		return notify.Noop{}, nil
+	}
+}
+
+// newDispatchPipeline assembles the channels enabled by the configuration, validated first, from
+// the senders of newSender
+func (a *Application) newDispatchPipeline(newSender newSenderFunc, history notify.History) (*dispatchPipeline, error) {
+	if err := validateNotifierConfig(a.conf); err != nil {
+		return nil, err
+	}
+
+	failed := notificationFailed(a.metrics, a.errorReporter)
+	pipeline := &dispatchPipeline{dispatcher: notify.NewDispatcher(), push: notify.NewDispatcher()}
+	channels := map[string]notify.Notifier{}
+	for _, channel := range a.conf.NotifierChannels() {
+		sender, err := newSender(channel)
+		if err != nil {
+			return nil, fmt.Errorf("could not set up the %s channel: %w", channel, err)
+		}
+
+		channels[channel] = a.decorateChannel(channel, sender, history, failed)
+		switch channel {
+		case deadLetterEmail:
+			pipeline.email = channels[channel]
+			continue
+		case deadLetterFCM, deadLetterWebPush, noopChannel:
+			pipeline.push.Register(notify.Synchronous(a.instrumentChannel(channel, sender)))
+		}
+		pipeline.dispatcher.Register(channels[channel])
+	}
+
+	if a.conf.Notifier.Mode != noopChannel {
+		for event, names := range a.conf.Notifier.Routes {
+			routed := make([]notify.Notifier, 0, len(names))
+			for _, name := range names {
+				routed = append(routed, channels[name])
+			}
+			pipeline.dispatcher.Route(event, routed...)
+		}
+	}
+	return pipeline, nil
+}
+
+// decorateChannel wraps the sender of the channel with the decorators, the first one outermost. The
+// sends are retried in the background with the retry decorator, the failed ones being dead lettered,
+// and only logged without it. The FCM sends track the delivery of the notifications in their history.
+func (a *Application) decorateChannel(channel string, sender notify.Sender, history notify.History, failed notify.FailureHandler) notify.Notifier {
+	decorators := a.conf.Notifier.Decorators
+	for i := len(decorators) - 1; i >= 0; i-- {
+		switch decorators[i] {
+		case decoratorDedup:
+			sender = notify.Deduplicate(sender, a.conf.Notifier.DedupWindow)
+		case decoratorMetrics:
+			sender = notify.Instrument(sender, channel, a.metrics)
+		}
+	}
+	if channel == deadLetterFCM {
+		sender = notify.TrackDelivery(sender, history)
+	}
+
+	if len(decorators) == 0 || decorators[0] != decoratorRetry {
+		return notify.Synchronous(sender)
+	}
+	return notify.WithRetry(sender, a.retryPolicy(), a.workers.Go, a.deadLettering(channel, sender, failed))
+}
+
+// instrumentChannel counts and times the sends of the channel, with the metrics decorator
+func (a *Application) instrumentChannel(channel string, sender notify.Sender) notify.Sender {
+	if containsString(a.conf.Notifier.Decorators, decoratorMetrics) {
+		return notify.Instrument(sender, channel, a.metrics)
+	}
+	return sender
+}
@@ -0,0 +1,229 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	"appdoki-be/config"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sendLog records the sends of the channels of a pipeline, as "<channel> <op> <event>"
+type sendLog struct {
+	mu    sync.Mutex
+	sends []string
+}
+
+func (l *sendLog) record(channel string, op string, n notify.Notification) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sends = append(l.sends, channel+" "+op+" "+n.Event)
+	return nil
+}
+
+func (l *sendLog) Sends() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.sends...)
+}
+
+// loggedSender is the sender of a channel recording its sends in the log
+type loggedSender struct {
+	channel string
+	log     *sendLog
+}
+
+func (s loggedSender) SendToUser(_ context.Context, _ string, n notify.Notification) error {
+	return s.log.record(s.channel, notify.OpSendToUser, n)
+}
+
+func (s loggedSender) SendToTopic(_ context.Context, _ string, n notify.Notification) error {
+	return s.log.record(s.channel, notify.OpSendToTopic, n)
+}
+
+func (s loggedSender) SendMulticast(_ context.Context, _ []string, n notify.Notification) error {
+	return s.log.record(s.channel, notify.OpSendMulticast, n)
+}
+
+func (s loggedSender) SubscribeToTopic(context.Context, []string, string) error {
+	return nil
+}
+
+func (s loggedSender) UnsubscribeFromTopic(context.Context, []string, string) error {
+	return nil
+}
+
+func TestValidateNotifierConfig(t *testing.T) {
+	newConfig := func(notifier config.NotifierConfig) *config.Config {
+		if notifier.Decorators == nil {
+			notifier.Decorators = []string{decoratorRetry, decoratorMetrics}
+		}
+		return &config.Config{Notifier: notifier}
+	}
+
+	for _, tc := range []struct {
+		name string
+		conf *config.Config
+		err  string
+	}{
+		{name: "the default channels", conf: newConfig(config.NotifierConfig{})},
+		{name: "the noop mode, ignoring the routes", conf: newConfig(config.NotifierConfig{
+			Mode: "noop", Routes: map[string][]string{"digest": {"email"}},
+		})},
+		{name: "an event routed to enabled channels", conf: newConfig(config.NotifierConfig{
+			Channels: []string{"fcm", "email"}, Routes: map[string][]string{"digest": {"fcm", "email"}},
+		})},
+		{name: "every decorator", conf: newConfig(config.NotifierConfig{
+			Decorators: []string{"retry", "dedup", "metrics"}, DedupWindow: time.Minute,
+		})},
+		{name: "no decorator", conf: newConfig(config.NotifierConfig{Decorators: []string{}})},
+		{name: "an unknown mode", conf: newConfig(config.NotifierConfig{Mode: "silent"}), err: `invalid NOTIFIER "silent"`},
+		{name: "an unknown channel", conf: newConfig(config.NotifierConfig{Channels: []string{"fcm", "sms"}}),
+			err: `unknown notifier channel "sms"`},
+		{name: "a channel enabled twice", conf: newConfig(config.NotifierConfig{Channels: []string{"fcm", "fcm"}}),
+			err: `"fcm" is enabled twice`},
+		{name: "webpush without its keys", conf: newConfig(config.NotifierConfig{Channels: []string{"webpush"}}),
+			err: "the webpush channel needs WEBPUSH_VAPID_PUBLIC_KEY"},
+		{name: "slack without its webhook", conf: newConfig(config.NotifierConfig{Channels: []string{"slack"}}),
+			err: "the slack channel needs SLACK_WEBHOOK_URL"},
+		{name: "an event routed to a disabled channel", conf: newConfig(config.NotifierConfig{
+			Channels: []string{"fcm"}, Routes: map[string][]string{"digest": {"email"}},
+		}), err: `event "digest" is routed to the disabled channel "email"`},
+		{name: "an event routed to an unknown channel", conf: newConfig(config.NotifierConfig{
+			Routes: map[string][]string{"digest": {"pigeon"}},
+		}), err: `event "digest" is routed to the unknown channel "pigeon"`},
+		{name: "an unknown event routed", conf: newConfig(config.NotifierConfig{
+			Routes: map[string][]string{"beer_spilled": {"fcm"}},
+		}), err: `unknown event "beer_spilled" routed`},
+		{name: "an unknown decorator", conf: newConfig(config.NotifierConfig{Decorators: []string{"retry", "cache"}}),
+			err: `unknown notifier decorator "cache"`},
+		{name: "a decorator listed twice", conf: newConfig(config.NotifierConfig{Decorators: []string{"metrics", "metrics"}}),
+			err: `"metrics" is listed twice`},
+		{name: "retry within another decorator", conf: newConfig(config.NotifierConfig{Decorators: []string{"metrics", "retry"}}),
+			err: "the retry decorator must be listed first"},
+		{name: "dedup without a window", conf: newConfig(config.NotifierConfig{Decorators: []string{"dedup"}}),
+			err: "the dedup decorator needs a positive NOTIFIER_DEDUP_WINDOW"},
+	} {
+		t.Run("expect "+tc.name+" to be checked", func(t *testing.T) {
+			err := validateNotifierConfig(tc.conf)
+			switch {
+			case tc.err == "" && err != nil:
+				t.Fatalf("expected the configuration to be valid, got %v", err)
+			case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+				t.Fatalf("expected the error '%s', got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestApplication_newDispatchPipeline(t *testing.T) {
+	newPipeline := func(t *testing.T, notifier config.NotifierConfig, configure func(c *config.Config)) (*Application, *dispatchPipeline, *sendLog) {
+		a := newTestApplication()
+		a.conf.Notifier = notifier
+		if configure != nil {
+			configure(a.conf)
+		}
+		log := &sendLog{}
+		pipeline, err := a.newDispatchPipeline(func(channel string) (notify.Sender, error) {
+			return loggedSender{channel: channel, log: log}, nil
+		}, notificationHistory{a.notificationsRepository})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a, pipeline, log
+	}
+
+	t.Run("expect the events to reach the channels they're routed to, every one but email otherwise", func(t *testing.T) {
+		_, pipeline, log := newPipeline(t, config.NotifierConfig{
+			Channels: []string{"fcm", "slack", "email"},
+			Routes:   map[string][]string{"digest": {"email"}, "github_star": {"slack"}},
+		}, func(c *config.Config) { c.Slack.WebhookURL = "https://hooks.slack.com/services/T/B/X" })
+
+		pipeline.dispatcher.SendToUser(context.Background(), "1", notify.Notification{Title: "Cheers!", Event: notify.EventBeerReceived})
+		pipeline.dispatcher.SendToUser(context.Background(), "1", notify.Notification{Title: "Your beers", Event: notify.EventDigest})
+		pipeline.dispatcher.SendToTopic(context.Background(), integrationsTopic, notify.Notification{Title: "Star", Event: eventGitHubStar})
+
+		expected := []string{
+			"fcm SendToUser beer_received", "slack SendToUser beer_received",
+			"email SendToUser digest",
+			"slack SendToTopic github_star",
+		}
+		if sends := log.Sends(); !reflect.DeepEqual(sends, expected) {
+			t.Fatalf("expected %v, got %v", expected, sends)
+		}
+		if pipeline.email == nil {
+			t.Fatal("expected the email channel")
+		}
+	})
+
+	t.Run("expect the noop mode to only log the notifications, without email", func(t *testing.T) {
+		a, pipeline, log := newPipeline(t, config.NotifierConfig{Mode: "noop"}, nil)
+
+		pipeline.dispatcher.SendToUser(context.Background(), "1", notify.Notification{Title: "Cheers!", Event: notify.EventBeerReceived})
+		pipeline.push.SendToUser(context.Background(), "1", notify.Notification{Title: "Test"})
+
+		if sends := log.Sends(); len(sends) != 2 || sends[0] != "noop SendToUser beer_received" || !strings.HasPrefix(sends[1], "noop ") {
+			t.Fatalf("expected the noop channel alone, got %v", sends)
+		}
+		if pipeline.email != nil || !reflect.DeepEqual(a.conf.NotifierChannels(), []string{"noop"}) {
+			t.Fatal("expected no email channel in the noop mode")
+		}
+	})
+
+	t.Run("expect the decorators to wrap the sends of every channel", func(t *testing.T) {
+		a, pipeline, log := newPipeline(t, config.NotifierConfig{
+			Channels:    []string{"fcm", "noop"},
+			Decorators:  []string{"dedup", "metrics"},
+			DedupWindow: time.Minute,
+		}, nil)
+
+		for i := 0; i < 2; i++ {
+			pipeline.dispatcher.SendToUser(context.Background(), "1", notify.Notification{Title: "Cheers!"})
+		}
+
+		if sends := log.Sends(); len(sends) != 2 {
+			t.Fatalf("expected the duplicate to be dropped on both channels, got %v", sends)
+		}
+		m := a.metrics.(*metrics.Fake)
+		for _, channel := range []string{"fcm", "noop"} {
+			if sent := m.Counter("notifications_sent_total", metrics.Labels{"channel": channel, "op": notify.OpSendToUser}); sent != 1 {
+				t.Fatalf("expected the send through %s to be counted once, got %v", channel, sent)
+			}
+		}
+		if len(a.replaySenders) != 0 {
+			t.Fatal("expected no dead letters without retries")
+		}
+	})
+
+	t.Run("expect the retried channels to dead letter their failures", func(t *testing.T) {
+		a, _, _ := newPipeline(t, config.NotifierConfig{Channels: []string{"fcm", "email"}, Decorators: []string{"retry"}}, nil)
+
+		if _, ok := a.replaySenders[deadLetterFCM]; !ok {
+			t.Fatal("expected the fcm dead letters to be replayable")
+		}
+		if _, ok := a.replaySenders[deadLetterEmail]; !ok {
+			t.Fatal("expected the email dead letters to be replayable")
+		}
+	})
+
+	t.Run("expect a misconfiguration or a sender failing to set up to fail", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.Notifier = config.NotifierConfig{Channels: []string{"fcm"}, Routes: map[string][]string{"digest": {"email"}}}
+		if _, err := a.newDispatchPipeline(nil, notificationHistory{a.notificationsRepository}); err == nil || !strings.Contains(err.Error(), "disabled channel") {
+			t.Fatalf("expected the route to the disabled channel to fail, got %v", err)
+		}
+
+		a.conf.Notifier = config.NotifierConfig{Channels: []string{"fcm"}}
+		_, err := a.newDispatchPipeline(func(string) (notify.Sender, error) {
+			return nil, errors.New("no credentials")
+		}, notificationHistory{a.notificationsRepository})
+		if err == nil || err.Error() != "could not set up the fcm channel: no credentials" {
+			t.Fatalf("expected the channel failing to set up to fail, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"appdoki-be/app/i18n"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/reporting"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNotificationFailed(t *testing.T) {
+	t.Run("expect the sends given up on to be counted by reason and reported", func(t *testing.T) {
+		m := metrics.NewFake()
+		reporter := reporting.NewFake()
+		handle := notificationFailed(m, reporter)
+
+		handle(context.Background(), notify.Failure{Op: "SendToTopic", Target: beersTopic, Attempts: 5, Err: notify.Retryable(errors.New("unavailable"))})
+		handle(context.Background(), notify.Failure{Op: "SendToTopic", Target: beersTopic, Attempts: 1, Err: errors.New("invalid argument")})
+
+		for _, reason := range []string{"exhausted", "permanent"} {
+			if count := m.Counter(notificationsFailedMetric, metrics.Labels{"op": "SendToTopic", "reason": reason}); count != 1 {
+				t.Fatalf("expected 1 %s failure, got %v", reason, count)
+			}
+		}
+		if captures := reporter.Captures(); len(captures) != 2 {
+			t.Fatalf("expected 2 reports, got %d", len(captures))
+		}
+	})
+}
+
+func TestEmailAddresses(t *testing.T) {
+	a := newTestApplication()
+	addresses := emailAddresses{a.usersRepository}
+
+	t.Run("expect the email of the user to be resolved", func(t *testing.T) {
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(ctx context.Context, ID string) (*repos.User, error) {
+			return &repos.User{ID: ID, Email: "beer@cloudoki.com"}, nil
+		}
+
+		if email, err := addresses.UserEmail(context.Background(), "42"); err != nil || email != "beer@cloudoki.com" {
+			t.Fatalf("expected the email of the user, got %q, %v", email, err)
+		}
+	})
+
+	t.Run("expect no email for unknown users", func(t *testing.T) {
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(ctx context.Context, ID string) (*repos.User, error) {
+			return nil, nil
+		}
+
+		if email, err := addresses.UserEmail(context.Background(), "42"); err != nil || email != "" {
+			t.Fatalf("expected no email, got %q, %v", email, err)
+		}
+	})
+}
+
+// renderNotification renders the copy of a notification from the embedded templates
+func renderNotification(t *testing.T, n notify.Notification) (string, string) {
+	t.Helper()
+	templates, err := notify.LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	title, body, err := templates.Render(i18n.Fallback, n.Event, n.Payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return title, body
+}
+
+func TestNotificationTemplates(t *testing.T) {
+	t.Run("expect the embedded templates to render the samples of their event", func(t *testing.T) {
+		templates, err := notify.LoadTemplates("")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := templates.Validate(notificationSamples); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("expect the copy of the beer transfers", func(t *testing.T) {
+		payload := beerTransferPayload{Giver: "Alice", Receiver: "Bob", Beers: 2}
+
+		if title, body := renderNotification(t, notify.Notification{Event: eventBeerGiven, Payload: payload}); title != "BeerTab event" || body != "Alice just rewarded Bob with 2 beers!" {
+			t.Errorf("unexpected beer_given copy %q %q", title, body)
+		}
+		if title, body := renderNotification(t, notify.Notification{Event: notify.EventBeerReceived, Payload: payload}); title != "Cheers!" || body != "Alice just rewarded you with 2 beers!" {
+			t.Errorf("unexpected beer_received copy %q %q", title, body)
+		}
+	})
+}
+
+func TestMergeBeersReceived(t *testing.T) {
+	held := []notify.Notification{
+		{Event: notify.EventBeerReceived, Data: map[string]string{"id": "1"}, Payload: beerTransferPayload{TransferID: 1, Giver: "Alice", Receiver: "Bob", Beers: 2}},
+		{Event: notify.EventBeerReceived, Data: map[string]string{"id": "2"}, Payload: beerTransferPayload{TransferID: 2, Giver: "Carol", Receiver: "Bob", Beers: 1}},
+		{Event: notify.EventBeerReceived, Data: map[string]string{"id": "3"}, Payload: beerTransferPayload{TransferID: 3, Giver: "Alice", Receiver: "Bob", Beers: 2}},
+	}
+
+	t.Run("expect the beers of the givers to be summed up", func(t *testing.T) {
+		merged := mergeBeersReceived(held)
+
+		if merged.Data["id"] != "3" || merged.Payload.(beerTransferPayload).TransferID != 3 {
+			t.Errorf("expected the data of the last transfer, got %v", merged.Data)
+		}
+		if title, body := renderNotification(t, merged); title != "Cheers!" || body != "You received 5 beers from 2 people!" {
+			t.Errorf("unexpected merged copy %q %q", title, body)
+		}
+	})
+
+	t.Run("expect the beers of a single giver to be credited to them", func(t *testing.T) {
+		merged := mergeBeersReceived([]notify.Notification{held[0], held[2]})
+
+		if _, body := renderNotification(t, merged); body != "Alice just rewarded you with 4 beers!" {
+			t.Errorf("unexpected merged copy %q", body)
+		}
+	})
+}
+
+func TestNotificationLinks(t *testing.T) {
+	templates, err := notify.LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := newTestApplication()
+	a.conf.Notifications.DeepLinkBase = "appdoki://"
+
+	t.Run("expect the notifications to link to the screen of their entity", func(t *testing.T) {
+		for _, c := range []struct {
+			n    notify.Notification
+			link string
+		}{
+			{notify.Notification{Event: notify.EventBeerReceived, Payload: beerTransferPayload{TransferID: 42, Giver: "Alice", Beers: 2}}, "appdoki://beers/42"},
+			{notify.Notification{Event: notify.EventBeerReceived, Payload: beerTransferPayload{TransferID: 42, Giver: "Alice", Beers: 5, Givers: 3}}, "appdoki://beers"},
+			{notify.Notification{Event: eventBeerGiven, Payload: beerTransferPayload{TransferID: 7, Giver: "Alice", Receiver: "Bob", Beers: 2}}, "appdoki://beers/7"},
+			{notify.Notification{Event: notify.EventDigest, Payload: digestPayload{Period: "week", Beers: 5, Givers: 3}}, "appdoki://beers"},
+			{notify.Notification{Data: map[string]string{"user": "{}"}, DeepLink: "users/2"}, "appdoki://users/2"},
+		} {
+			fake := notify.NewFake()
+			notify.WithTemplates(notify.WithLinks(fake, a.notificationLinks()), templates, nil).SendToUser(context.Background(), "1", c.n)
+
+			if link := fake.Sent()[0].Notification.DeepLink; link != c.link {
+				t.Fatalf("expected %s for %s, got %q", c.link, c.n.Event, link)
+			}
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/ratelimit"
+	"context"
+	"time"
+)
+
+// ceilingNotifier caps the notifications pushed to every user
+type ceilingNotifier struct {
+	next       Notifier
+	store      ratelimit.Store
+	limit      ratelimit.Limit
+	history    History
+	suppressed SuppressedHandler
+}
+
+// WithUserCeiling returns a Notifier sending through next up to perHour notifications to every user
+// an hour. The ones beyond are only kept in the history recorded by WithHistory, marked suppressed.
+// The silent notifications and the ones to topics or devices aren't capped, nor any when perHour is
+// 0, and the store failing doesn't block the send.
+func WithUserCeiling(next Notifier, perHour int, store ratelimit.Store, history History, suppressed SuppressedHandler) Notifier {
+	return &ceilingNotifier{
+		next:       next,
+		store:      store,
+		limit:      ratelimit.Limit{Rate: float64(perHour) / time.Hour.Seconds(), Burst: perHour},
+		history:    history,
+		suppressed: suppressed,
+	}
+}
+
+func (c *ceilingNotifier) SendToUser(ctx context.Context, userID string, n Notification) {
+	if n.Silent || !c.limit.Enabled() {
+		c.next.SendToUser(ctx, userID, n)
+		return
+	}
+
+	res, err := c.store.Take(ctx, "notifications:"+userID, c.limit)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error checking the notifications ceiling of user %s, sending anyway: %v", userID, err)
+	} else if !res.Allowed {
+		logging.FromContext(ctx).Debugf("user %s reached the notifications ceiling, not pushing %s", userID, n.summary())
+		if n.historyID != 0 {
+			if err := c.history.SetStatus(ctx, n.historyID, DeliverySuppressed); err != nil {
+				logging.FromContext(ctx).Errorf("error setting the delivery status of notification %d: %v", n.historyID, err)
+			}
+		}
+		if c.suppressed != nil {
+			c.suppressed(ctx, n.Event)
+		}
+		return
+	}
+
+	c.next.SendToUser(ctx, userID, n)
+}
+
+func (c *ceilingNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	c.next.SendToTopic(ctx, topic, n)
+}
+
+func (c *ceilingNotifier) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	c.next.SendMulticast(ctx, tokens, n)
+}
+
+func (c *ceilingNotifier) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	c.next.SubscribeToTopic(ctx, tokens, topic)
+}
+
+func (c *ceilingNotifier) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	c.next.UnsubscribeFromTopic(ctx, tokens, topic)
+}
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"appdoki-be/app/ratelimit"
+	"context"
+	"testing"
+)
+
+func TestWithUserCeiling(t *testing.T) {
+	t.Run("expect the notifications beyond the ceiling to only be kept in the history", func(t *testing.T) {
+		fake := NewFake()
+		history := &fakeHistory{}
+		var suppressed []string
+		n := WithHistory(WithUserCeiling(fake, 2, ratelimit.NewMemory(), history, func(_ context.Context, event string) {
+			suppressed = append(suppressed, event)
+		}), history)
+
+		for i := 0; i < 3; i++ {
+			n.SendToUser(context.Background(), "42", Notification{Title: "Cheers!", Event: EventBeerReceived})
+		}
+		n.SendToUser(context.Background(), "7", Notification{Title: "Cheers!", Event: EventBeerReceived})
+		n.SendToUser(context.Background(), "42", Notification{Event: "user_updated", Silent: true})
+
+		if sent := fake.Sent(); len(sent) != 4 || sent[2].UserID != "7" || !sent[3].Notification.Silent {
+			t.Fatalf("expected the third notification to user 42 not to be pushed, got %+v", sent)
+		}
+		if len(history.recorded) != 4 || len(history.statuses[3]) != 1 || history.statuses[3][0] != DeliverySuppressed {
+			t.Fatalf("expected the third notification to be kept suppressed, got %+v", history.statuses)
+		}
+		if len(suppressed) != 1 || suppressed[0] != EventBeerReceived {
+			t.Fatalf("expected the suppressed notification to be reported, got %v", suppressed)
+		}
+	})
+
+	t.Run("expect no ceiling when disabled", func(t *testing.T) {
+		fake := NewFake()
+		n := WithUserCeiling(fake, 0, ratelimit.NewMemory(), &fakeHistory{}, nil)
+
+		for i := 0; i < 20; i++ {
+			n.SendToUser(context.Background(), "42", Notification{Title: "Cheers!"})
+		}
+		if sent := fake.Sent(); len(sent) != 20 {
+			t.Fatalf("expected every notification to be pushed, got %d", len(sent))
+		}
+	})
+}
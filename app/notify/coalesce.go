@@ -0,0 +1,152 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+	"sync"
+	"time"
+)
+
+// CoalesceStore keeps the notifications of the open coalescing windows, by key
+type CoalesceStore interface {
+	// Hold adds the notification to the window of the key, telling if it opened the window
+	Hold(ctx context.Context, key string, n Notification) (bool, error)
+	// Release closes the window of the key, returning its notifications in the order they were held
+	Release(ctx context.Context, key string) ([]Notification, error)
+}
+
+// MemoryCoalesceStore is a CoalesceStore keeping the windows in memory, only suitable for a single instance
+type MemoryCoalesceStore struct {
+	mu      sync.Mutex
+	windows map[string][]Notification
+}
+
+// NewMemoryCoalesceStore returns an empty in-memory store
+func NewMemoryCoalesceStore() *MemoryCoalesceStore {
+	return &MemoryCoalesceStore{windows: map[string][]Notification{}}
+}
+
+func (m *MemoryCoalesceStore) Hold(_ context.Context, key string, n Notification) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	held, open := m.windows[key]
+	m.windows[key] = append(held, n)
+	return !open, nil
+}
+
+func (m *MemoryCoalesceStore) Release(_ context.Context, key string) ([]Notification, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	held := m.windows[key]
+	delete(m.windows, key)
+	return held, nil
+}
+
+// MergeFunc merges the notifications of a coalescing window, in the order they were sent, into the
+// one replacing them
+type MergeFunc func(held []Notification) Notification
+
+// CoalesceConfig collapses the notifications of an event to a user sent within Window of the first
+// one. Merge merges them by event, the last one of the window replacing the others for the events
+// without a MergeFunc.
+type CoalesceConfig struct {
+	Window time.Duration
+	Merge  map[string]MergeFunc
+}
+
+// CoalescedHandler is called with the event and the count of the notifications merged into another
+type CoalescedHandler func(ctx context.Context, event string, count int)
+
+// coalescingNotifier collapses the bursts of notifications of an event to a user
+type coalescingNotifier struct {
+	next       Notifier
+	conf       CoalesceConfig
+	store      CoalesceStore
+	background BackgroundFunc
+	coalesced  CoalescedHandler
+	sleep      func(ctx context.Context, d time.Duration) bool
+}
+
+// WithCoalescing returns a Notifier sending through next the first notification of an event to a
+// user right away, then the ones sent within the window merged into one when it closes, in the
+// background. The merged notification shares the collapse key of the first one, replacing it on the
+// devices. The notifications without an event, and the ones to topics or devices, aren't coalesced,
+// nor any when the window is 0.
+func WithCoalescing(next Notifier, conf CoalesceConfig, store CoalesceStore, background BackgroundFunc, coalesced CoalescedHandler) Notifier {
+	return &coalescingNotifier{
+		next:       next,
+		conf:       conf,
+		store:      store,
+		background: background,
+		coalesced:  coalesced,
+		sleep:      sleep,
+	}
+}
+
+func (c *coalescingNotifier) SendToUser(ctx context.Context, userID string, n Notification) {
+	if n.Event == "" || c.conf.Window <= 0 {
+		c.next.SendToUser(ctx, userID, n)
+		return
+	}
+	if n.CollapseKey == "" {
+		n.CollapseKey = n.Event
+	}
+
+	key := userID + "/" + n.Event
+	first, err := c.store.Hold(ctx, key, n)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error coalescing a notification to user %s, sending it: %v", userID, err)
+		c.next.SendToUser(ctx, userID, n)
+		return
+	}
+	if !first {
+		return
+	}
+
+	c.next.SendToUser(ctx, userID, n)
+	c.background(ctx, func(ctx context.Context) {
+		c.sleep(ctx, c.conf.Window)
+		c.flush(ctx, userID, n.Event, key)
+	})
+}
+
+// flush closes the window of the key, sending its notifications merged unless the first one,
+// sent already, was alone
+func (c *coalescingNotifier) flush(ctx context.Context, userID string, event string, key string) {
+	held, err := c.store.Release(ctx, key)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error releasing the coalesced %s notifications to user %s: %v", event, userID, err)
+		return
+	}
+	if len(held) < 2 {
+		return
+	}
+
+	merged := held[len(held)-1]
+	if merge, ok := c.conf.Merge[event]; ok {
+		merged = merge(held)
+	}
+	merged.CollapseKey = held[0].CollapseKey
+	if c.coalesced != nil {
+		c.coalesced(ctx, event, len(held)-1)
+	}
+	c.next.SendToUser(ctx, userID, merged)
+}
+
+func (c *coalescingNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	c.next.SendToTopic(ctx, topic, n)
+}
+
+func (c *coalescingNotifier) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	c.next.SendMulticast(ctx, tokens, n)
+}
+
+func (c *coalescingNotifier) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	c.next.SubscribeToTopic(ctx, tokens, topic)
+}
+
+func (c *coalescingNotifier) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	c.next.UnsubscribeFromTopic(ctx, tokens, topic)
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWithCoalescing(t *testing.T) {
+	// holds the window flushes for the test to close the windows
+	newCoalescing := func(fake *Fake, flushes *[]func(ctx context.Context), coalesced *int) Notifier {
+		c := WithCoalescing(fake, CoalesceConfig{
+			Window: time.Minute,
+			Merge: map[string]MergeFunc{EventBeerReceived: func(held []Notification) Notification {
+				return Notification{Title: "merged", Event: EventBeerReceived, Data: map[string]string{"count": strconv.Itoa(len(held))}}
+			}},
+		}, NewMemoryCoalesceStore(), func(ctx context.Context, f func(ctx context.Context)) {
+			*flushes = append(*flushes, f)
+		}, func(_ context.Context, _ string, count int) { *coalesced += count }).(*coalescingNotifier)
+		c.sleep = func(context.Context, time.Duration) bool { return true }
+		return c
+	}
+
+	t.Run("expect a burst to push the first notification then the merged ones", func(t *testing.T) {
+		fake := NewFake()
+		var flushes []func(ctx context.Context)
+		coalesced := 0
+		n := newCoalescing(fake, &flushes, &coalesced)
+
+		for i := 0; i < 3; i++ {
+			n.SendToUser(context.Background(), "42", Notification{Title: "Cheers!", Event: EventBeerReceived})
+		}
+		n.SendToUser(context.Background(), "7", Notification{Title: "Cheers!", Event: EventBeerReceived})
+		if sent := fake.Sent(); len(sent) != 2 || len(flushes) != 2 || sent[0].Notification.CollapseKey != EventBeerReceived {
+			t.Fatalf("expected the first notification of each user to be pushed, got %+v", sent)
+		}
+
+		for _, flush := range flushes {
+			flush(context.Background())
+		}
+		sent := fake.Sent()
+		if len(sent) != 3 || sent[2].UserID != "42" || sent[2].Notification.Title != "merged" || sent[2].Notification.Data["count"] != "3" {
+			t.Fatalf("expected the 3 notifications to user 42 to be merged, got %+v", sent)
+		}
+		if sent[2].Notification.CollapseKey != EventBeerReceived || coalesced != 2 {
+			t.Fatalf("expected the merged notification to replace the first one, got %+v and %d coalesced", sent[2], coalesced)
+		}
+	})
+
+	t.Run("expect the last notification to replace the others of the events without a merge", func(t *testing.T) {
+		fake := NewFake()
+		var flushes []func(ctx context.Context)
+		coalesced := 0
+		n := newCoalescing(fake, &flushes, &coalesced)
+
+		n.SendToUser(context.Background(), "42", Notification{Event: "user_updated", Silent: true, Data: map[string]string{"v": "1"}})
+		n.SendToUser(context.Background(), "42", Notification{Event: "user_updated", Silent: true, Data: map[string]string{"v": "2"}})
+		flushes[0](context.Background())
+
+		if sent := fake.Sent(); len(sent) != 2 || sent[1].Notification.Data["v"] != "2" {
+			t.Fatalf("expected the last notification to be pushed, got %+v", sent)
+		}
+	})
+
+	t.Run("expect the notifications without an event or to topics not to be coalesced", func(t *testing.T) {
+		fake := NewFake()
+		var flushes []func(ctx context.Context)
+		coalesced := 0
+		n := newCoalescing(fake, &flushes, &coalesced)
+
+		n.SendToUser(context.Background(), "42", Notification{Title: "Hi"})
+		n.SendToUser(context.Background(), "42", Notification{Title: "Hi"})
+		n.SendToTopic(context.Background(), "beers", Notification{Event: "beer_given"})
+		n.SendToTopic(context.Background(), "beers", Notification{Event: "beer_given"})
+
+		if sent := fake.Sent(); len(sent) != 4 || len(flushes) != 0 {
+			t.Fatalf("expected every notification to be pushed, got %+v", sent)
+		}
+	})
+
+	t.Run("expect a lone notification not to be pushed again", func(t *testing.T) {
+		fake := NewFake()
+		var flushes []func(ctx context.Context)
+		coalesced := 0
+		n := newCoalescing(fake, &flushes, &coalesced)
+
+		n.SendToUser(context.Background(), "42", Notification{Title: "Cheers!", Event: EventBeerReceived})
+		flushes[0](context.Background())
+		n.SendToUser(context.Background(), "42", Notification{Title: "Cheers!", Event: EventBeerReceived})
+
+		if sent := fake.Sent(); len(sent) != 2 || len(flushes) != 2 || coalesced != 0 {
+			t.Fatalf("expected a window per notification, got %+v", sent)
+		}
+	})
+}
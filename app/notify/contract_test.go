@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// The apps and the service worker of the web app hardcode the keys of the messages, these tests pin
+// them down: change them along with the clients only.
+
+func TestContract_FCMData(t *testing.T) {
+	fake := NewFake()
+	WithLinks(fake, Links{AppBase: "appdoki://", WebBase: "https://appdoki.cloudoki.com/"}).
+		SendToUser(context.Background(), "1", Notification{Title: "Cheers!", Data: map[string]string{"id": "42"}, DeepLink: "beers/42"})
+	n := fake.Sent()[0].Notification
+
+	message := newTestFCM(&fakeFCMClient{}, &fakeDeviceTokens{}).message("", n)
+
+	if !reflect.DeepEqual(message.Data, map[string]string{"id": "42", "deep_link": "appdoki://beers/42"}) {
+		t.Fatalf("expected the deep link in the deep_link data key, got %v", message.Data)
+	}
+	if message.Webpush == nil || message.Webpush.FCMOptions == nil || message.Webpush.FCMOptions.Link != "https://appdoki.cloudoki.com/beers/42" {
+		t.Fatalf("expected the web page as the webpush link, got %+v", message.Webpush)
+	}
+}
+
+func TestContract_WebPushMessage(t *testing.T) {
+	n := Notification{
+		Title:    "Cheers!",
+		Body:     "You received a beer",
+		DeepLink: "appdoki://beers/42",
+		Webpush:  &WebpushOptions{Link: "https://appdoki.cloudoki.com/beers/42", Icon: "https://appdoki.cloudoki.com/beer.png"},
+	}
+	encoded, err := json.Marshal(newWebPushMessage(n))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var message map[string]interface{}
+	json.Unmarshal(encoded, &message)
+	keys := make([]string, 0, len(message))
+	for key := range message {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"body", "click_action", "data", "icon", "title"}) {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+	if message["data"].(map[string]interface{})["deep_link"] != "appdoki://beers/42" {
+		t.Fatalf("expected the deep link in the deep_link data key, got %v", message["data"])
+	}
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// dedupSender drops the sends identical to one made within the window
+type dedupSender struct {
+	next   Sender
+	window time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	sent map[[sha256.Size]byte]time.Time
+}
+
+// Deduplicate returns a Sender sending through next, dropping the notifications identical to one
+// sent to the same user, topic or devices within the window, ex.: when a client retries a request
+// notifying someone. Only the successful sends count, so the retries of a failed one go through.
+// The sends are remembered in memory, by instance.
+func Deduplicate(next Sender, window time.Duration) Sender {
+	return &dedupSender{next: next, window: window, now: time.Now, sent: map[[sha256.Size]byte]time.Time{}}
+}
+
+func (d *dedupSender) SendToUser(ctx context.Context, userID string, n Notification) error {
+	return d.send(ctx, Job{Op: OpSendToUser, UserID: userID, Notification: &n}, func() error {
+		return d.next.SendToUser(ctx, userID, n)
+	})
+}
+
+func (d *dedupSender) SendToTopic(ctx context.Context, topic string, n Notification) error {
+	return d.send(ctx, Job{Op: OpSendToTopic, Topic: topic, Notification: &n}, func() error {
+		return d.next.SendToTopic(ctx, topic, n)
+	})
+}
+
+func (d *dedupSender) SendMulticast(ctx context.Context, tokens []string, n Notification) error {
+	return d.send(ctx, Job{Op: OpSendMulticast, Tokens: tokens, Notification: &n}, func() error {
+		return d.next.SendMulticast(ctx, tokens, n)
+	})
+}
+
+func (d *dedupSender) SubscribeToTopic(ctx context.Context, tokens []string, topic string) error {
+	return d.next.SubscribeToTopic(ctx, tokens, topic)
+}
+
+func (d *dedupSender) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) error {
+	return d.next.UnsubscribeFromTopic(ctx, tokens, topic)
+}
+
+// send sends the job unless it was within the window, remembering it once sent
+func (d *dedupSender) send(ctx context.Context, job Job, send func() error) error {
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return send()
+	}
+	key := sha256.Sum256(encoded)
+
+	now := d.now()
+	d.mu.Lock()
+	for k, at := range d.sent {
+		if now.Sub(at) >= d.window {
+			delete(d.sent, k)
+		}
+	}
+	_, duplicate := d.sent[key]
+	d.mu.Unlock()
+	if duplicate {
+		logging.FromContext(ctx).Debugf("dropping the duplicate %s of %s", job.Op, job.Notification.summary())
+		return nil
+	}
+
+	if err := send(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.sent[key] = now
+	d.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeduplicate(t *testing.T) {
+	newDedup := func(sender Sender) (*dedupSender, *time.Time) {
+		now := time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC)
+		d := Deduplicate(sender, time.Minute).(*dedupSender)
+		d.now = func() time.Time { return now }
+		return d, &now
+	}
+
+	t.Run("expect the same notification to be sent once within the window", func(t *testing.T) {
+		sender := &flakySender{}
+		d, now := newDedup(sender)
+
+		for i := 0; i < 2; i++ {
+			if err := d.SendToUser(context.Background(), "42", Notification{Title: "Cheers!"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		d.SendToUser(context.Background(), "7", Notification{Title: "Cheers!"})
+		d.SendToUser(context.Background(), "42", Notification{Title: "Cheers again!"})
+		d.SendToTopic(context.Background(), "42", Notification{Title: "Cheers!"})
+		if sender.attempts != 4 {
+			t.Fatalf("expected the duplicate alone to be dropped, got %d sends", sender.attempts)
+		}
+
+		*now = now.Add(time.Minute)
+		d.SendToUser(context.Background(), "42", Notification{Title: "Cheers!"})
+		if sender.attempts != 5 {
+			t.Fatalf("expected the notification to be sent again after the window, got %d sends", sender.attempts)
+		}
+	})
+
+	t.Run("expect a failed send not to drop its retry", func(t *testing.T) {
+		sender := &flakySender{failures: 1, err: Retryable(errors.New("unavailable"))}
+		d, _ := newDedup(sender)
+
+		if err := d.SendToUser(context.Background(), "42", Notification{Title: "Cheers!"}); err == nil {
+			t.Fatal("expected the first attempt to fail")
+		}
+		if err := d.SendToUser(context.Background(), "42", Notification{Title: "Cheers!"}); err != nil || sender.attempts != 2 {
+			t.Fatalf("expected the retry to be sent, got %v after %d attempts", err, sender.attempts)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+)
+
+// Dispatcher fans the notifications out to every channel registered (ex.: FCM and Slack), each
+// handling its failures on its own, or to the channels their event is routed to
+type Dispatcher struct {
+	channels []Notifier
+	routes   map[string][]Notifier
+}
+
+func NewDispatcher(channels ...Notifier) *Dispatcher {
+	return &Dispatcher{channels: channels, routes: map[string][]Notifier{}}
+}
+
+// Register adds a channel, before the dispatcher is used
+func (d *Dispatcher) Register(channel Notifier) {
+	d.channels = append(d.channels, channel)
+}
+
+// Route has the notifications of the event only sent to the channels, rather than to the ones
+// registered, before the dispatcher is used
+func (d *Dispatcher) Route(event string, channels ...Notifier) {
+	d.routes[event] = channels
+}
+
+// channelsOf returns the channels the notification is sent to
+func (d *Dispatcher) channelsOf(n Notification) []Notifier {
+	if channels, ok := d.routes[n.Event]; ok && n.Event != "" {
+		return channels
+	}
+	return d.channels
+}
+
+func (d *Dispatcher) SendToUser(ctx context.Context, userID string, n Notification) {
+	for _, channel := range d.channelsOf(n) {
+		channel.SendToUser(ctx, userID, n)
+	}
+}
+
+func (d *Dispatcher) SendToTopic(ctx context.Context, topic string, n Notification) {
+	for _, channel := range d.channelsOf(n) {
+		channel.SendToTopic(ctx, topic, n)
+	}
+}
+
+func (d *Dispatcher) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	for _, channel := range d.channelsOf(n) {
+		channel.SendMulticast(ctx, tokens, n)
+	}
+}
+
+func (d *Dispatcher) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	for _, channel := range d.channels {
+		channel.SubscribeToTopic(ctx, tokens, topic)
+	}
+}
+
+func (d *Dispatcher) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	for _, channel := range d.channels {
+		channel.UnsubscribeFromTopic(ctx, tokens, topic)
+	}
+}
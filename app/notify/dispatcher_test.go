@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDispatcher(t *testing.T) {
+	t.Run("expect the notifications to reach every channel registered", func(t *testing.T) {
+		push, chat := NewFake(), NewFake()
+		d := NewDispatcher(push)
+		d.Register(chat)
+
+		d.SendToTopic(context.Background(), "beers", Notification{Title: "Cheers"})
+		d.SendToUser(context.Background(), "42", Notification{Title: "Cheers"})
+		d.SubscribeToTopic(context.Background(), []string{"token"}, "all-users")
+
+		for _, channel := range []*Fake{push, chat} {
+			if sent := channel.Sent(); len(sent) != 2 || sent[0].Topic != "beers" || sent[1].UserID != "42" {
+				t.Fatalf("unexpected sends %+v", sent)
+			}
+			if subscribers := channel.Subscribers("all-users"); len(subscribers) != 1 {
+				t.Fatalf("expected the device to be subscribed, got %v", subscribers)
+			}
+		}
+	})
+}
+
+func TestDispatcher_Route(t *testing.T) {
+	t.Run("expect the notifications of a routed event to only reach its channels", func(t *testing.T) {
+		push, chat, email := NewFake(), NewFake(), NewFake()
+		d := NewDispatcher(push, chat)
+		d.Route(EventDigest, push, email)
+
+		d.SendToUser(context.Background(), "42", Notification{Title: "Your beers", Event: EventDigest})
+		d.SendToUser(context.Background(), "42", Notification{Title: "Cheers", Event: EventBeerReceived})
+
+		if sent := push.Sent(); len(sent) != 2 {
+			t.Fatalf("expected both notifications to be pushed, got %+v", sent)
+		}
+		if sent := chat.Sent(); len(sent) != 1 || sent[0].Notification.Event != EventBeerReceived {
+			t.Fatalf("expected the digest not to reach the unrouted channel, got %+v", sent)
+		}
+		if sent := email.Sent(); len(sent) != 1 || sent[0].Notification.Event != EventDigest {
+			t.Fatalf("expected the digest alone to be emailed, got %+v", sent)
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"path"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// defaultEmailTemplate renders the notifications of the events without a template of their own
+const defaultEmailTemplate = "default"
+
+//go:embed templates/email/*
+var emailTemplateFiles embed.FS
+
+// emailTemplates holds the HTML and text templates of the emails by event, each event having both
+var emailTemplates = mustLoadEmailTemplates()
+
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+func mustLoadEmailTemplates() map[string]emailTemplate {
+	entries, err := emailTemplateFiles.ReadDir("templates/email")
+	if err != nil {
+		panic(err)
+	}
+
+	templates := map[string]emailTemplate{}
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		name := strings.TrimSuffix(entry.Name(), ext)
+		file := path.Join("templates/email", entry.Name())
+		t := templates[name]
+		switch ext {
+		case ".html":
+			t.html = htmltemplate.Must(htmltemplate.ParseFS(emailTemplateFiles, file)).Option("missingkey=error")
+		case ".txt":
+			t.text = texttemplate.Must(texttemplate.ParseFS(emailTemplateFiles, file)).Option("missingkey=error")
+		default:
+			panic(fmt.Errorf("email template %s is neither .html nor .txt", entry.Name()))
+		}
+		templates[name] = t
+	}
+
+	for name, t := range templates {
+		if t.html == nil || t.text == nil {
+			panic(fmt.Errorf("email template %s needs both a .html and a .txt file", name))
+		}
+	}
+	if _, ok := templates[defaultEmailTemplate]; !ok {
+		panic("the default email template is missing")
+	}
+	return templates
+}
+
+// EmailAddresses resolves the email address of a user
+type EmailAddresses interface {
+	UserEmail(ctx context.Context, userID string) (string, error)
+}
+
+// EmailConfig contains the SMTP server the emails are sent through, from From. Username may be
+// empty for servers without authentication. In dry run mode the emails are only logged.
+type EmailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	DryRun   bool
+}
+
+// Email sends the notifications to users by email, rendered from the template of their event.
+// Emails have no topics nor devices, so the topic sends and subscriptions are ignored.
+type Email struct {
+	conf      EmailConfig
+	addresses EmailAddresses
+	sendMail  func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewEmail(conf EmailConfig, addresses EmailAddresses) *Email {
+	return &Email{conf: conf, addresses: addresses, sendMail: smtp.SendMail}
+}
+
+// SendToUser emails the notification to the user, the SMTP server's temporary errors being retryable
+func (e *Email) SendToUser(ctx context.Context, userID string, n Notification) error {
+	to, err := e.addresses.UserEmail(ctx, userID)
+	if err != nil {
+		return Retryable(fmt.Errorf("resolving the email address of the user: %w", err))
+	}
+	if to == "" {
+		logging.FromContext(ctx).Debugf("user %s has no email address", userID)
+		return nil
+	}
+
+	msg, err := e.message(to, n)
+	if err != nil {
+		return err
+	}
+	if e.conf.DryRun {
+		logging.FromContext(ctx).Infof("dry run, not emailing user %s:\n%s", userID, msg)
+		return nil
+	}
+
+	var auth smtp.Auth
+	if e.conf.Username != "" {
+		auth = smtp.PlainAuth("", e.conf.Username, e.conf.Password, e.conf.Host)
+	}
+	addr := net.JoinHostPort(e.conf.Host, strconv.Itoa(e.conf.Port))
+	if err := e.sendMail(addr, auth, e.conf.From, []string{to}, msg); err != nil {
+		err = fmt.Errorf("emailing the user: %w", err)
+		if smtpRetryable(err) {
+			return Retryable(err)
+		}
+		return err
+	}
+
+	logging.FromContext(ctx).Infof("emailed %s to user %s", n.summary(), userID)
+	return nil
+}
+
+func (e *Email) SendToTopic(ctx context.Context, topic string, n Notification) error {
+	logging.FromContext(ctx).Debugf("emails have no topics, ignoring %s to %s", n.summary(), topic)
+	return nil
+}
+
+func (e *Email) SendMulticast(ctx context.Context, tokens []string, n Notification) error {
+	logging.FromContext(ctx).Debugf("emails have no devices, ignoring %s to %s", n.summary(), devicesSummary(tokens))
+	return nil
+}
+
+func (e *Email) SubscribeToTopic(context.Context, []string, string) error {
+	return nil
+}
+
+func (e *Email) UnsubscribeFromTopic(context.Context, []string, string) error {
+	return nil
+}
+
+// message builds the email of the notification, with its HTML and text alternatives
+func (e *Email) message(to string, n Notification) ([]byte, error) {
+	html, text, err := renderEmail(n)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	parts := multipart.NewWriter(&buf)
+	header := []string{
+		"From: " + e.conf.From,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", n.Title),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + parts.Boundary(),
+	}
+	buf.WriteString(strings.Join(header, "\r\n") + "\r\n\r\n")
+
+	for _, alternative := range []struct {
+		contentType string
+		body        []byte
+	}{{"text/plain", text}, {"text/html", html}} {
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {alternative.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(part)
+		if _, err := qp.Write(alternative.body); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// renderEmail renders the HTML and text bodies of the notification from the template of its event
+func renderEmail(n Notification) ([]byte, []byte, error) {
+	name := n.Event
+	if _, ok := emailTemplates[name]; !ok {
+		name = defaultEmailTemplate
+	}
+	t := emailTemplates[name]
+
+	var html, text bytes.Buffer
+	if err := t.html.Execute(&html, n); err != nil {
+		return nil, nil, fmt.Errorf("rendering the %s email template: %w", name, err)
+	}
+	if err := t.text.Execute(&text, n); err != nil {
+		return nil, nil, fmt.Errorf("rendering the %s email template: %w", name, err)
+	}
+	return html.Bytes(), text.Bytes(), nil
+}
+
+// smtpRetryable tells if the SMTP server may accept the email later: it couldn't be
+// reached or answered with a transient (4xx) reply
+func smtpRetryable(err error) bool {
+	var reply *textproto.Error
+	if errors.As(err, &reply) {
+		return reply.Code >= 400 && reply.Code < 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
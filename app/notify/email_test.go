@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+type fakeEmailAddresses map[string]string
+
+func (a fakeEmailAddresses) UserEmail(_ context.Context, userID string) (string, error) {
+	return a[userID], nil
+}
+
+func TestEmailTemplates(t *testing.T) {
+	t.Run("expect every template to render", func(t *testing.T) {
+		for name := range emailTemplates {
+			for _, n := range []Notification{
+				{Event: name, Title: "Cheers", Body: "You got 2 beers", DeepLink: "appdoki://beers"},
+				{Event: name, Title: "Cheers"},
+			} {
+				html, text, err := renderEmail(n)
+				if err != nil {
+					t.Fatalf("expected the %s template to render, got %v", name, err)
+				}
+				if !strings.Contains(string(html), "Cheers") || !strings.Contains(string(text), "Cheers") {
+					t.Fatalf("expected the %s template to hold the title, got %s and %s", name, html, text)
+				}
+			}
+		}
+	})
+
+	t.Run("expect the web link rather than the deep link", func(t *testing.T) {
+		n := Notification{Title: "Cheers", DeepLink: "appdoki://beers/7", Webpush: &WebpushOptions{Link: "https://appdoki.dev/beers/7"}}
+
+		html, text, err := renderEmail(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(html), `href="https://appdoki.dev/beers/7"`) || !strings.Contains(string(text), "https://appdoki.dev/beers/7") {
+			t.Fatalf("expected the web link, got %s and %s", html, text)
+		}
+	})
+
+	t.Run("expect the HTML to be escaped", func(t *testing.T) {
+		html, _, err := renderEmail(Notification{Title: "<script>"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(html), "<script>") {
+			t.Fatalf("expected the title to be escaped, got %s", html)
+		}
+	})
+}
+
+func TestEmail_SendToUser(t *testing.T) {
+	type sent struct {
+		addr string
+		to   []string
+		msg  string
+	}
+	newTestEmail := func(conf EmailConfig, err error) (*Email, *[]sent) {
+		var mails []sent
+		e := NewEmail(conf, fakeEmailAddresses{"42": "jane@cloudoki.com"})
+		e.sendMail = func(addr string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+			mails = append(mails, sent{addr: addr, to: to, msg: string(msg)})
+			return err
+		}
+		return e, &mails
+	}
+	conf := EmailConfig{Host: "smtp.cloudoki.com", Port: 587, From: "AppDoki <appdoki@cloudoki.com>"}
+
+	t.Run("expect the notification to be emailed with its text and HTML alternatives", func(t *testing.T) {
+		e, mails := newTestEmail(conf, nil)
+
+		if err := e.SendToUser(context.Background(), "42", Notification{Title: "Cheers ☕", Body: "You got 2 beers"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(*mails) != 1 || (*mails)[0].addr != "smtp.cloudoki.com:587" || (*mails)[0].to[0] != "jane@cloudoki.com" {
+			t.Fatalf("unexpected emails %+v", *mails)
+		}
+		msg := (*mails)[0].msg
+		for _, expected := range []string{"Subject: =?utf-8?q?Cheers_=E2=98=95?=", "text/plain; charset=utf-8", "text/html; charset=utf-8", "You got 2 beers"} {
+			if !strings.Contains(msg, expected) {
+				t.Fatalf("expected the email to contain %q, got %s", expected, msg)
+			}
+		}
+	})
+
+	t.Run("expect nothing to be sent in dry run mode", func(t *testing.T) {
+		dryRun := conf
+		dryRun.DryRun = true
+		e, mails := newTestEmail(dryRun, nil)
+
+		if err := e.SendToUser(context.Background(), "42", Notification{Title: "Cheers"}); err != nil || len(*mails) != 0 {
+			t.Fatalf("expected no email, got %+v (%v)", *mails, err)
+		}
+	})
+
+	t.Run("expect the transient SMTP errors to be retryable", func(t *testing.T) {
+		e, _ := newTestEmail(conf, &textproto.Error{Code: 451, Msg: "try again later"})
+		if err := e.SendToUser(context.Background(), "42", Notification{Title: "Cheers"}); !IsRetryable(err) {
+			t.Fatalf("expected a retryable error, got %v", err)
+		}
+
+		e, _ = newTestEmail(conf, &textproto.Error{Code: 550, Msg: "no such user"})
+		if err := e.SendToUser(context.Background(), "42", Notification{Title: "Cheers"}); err == nil || IsRetryable(err) {
+			t.Fatalf("expected a permanent error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Sent is a notification recorded by the Fake notifier, sent either to a user, to a topic or to devices
+type Sent struct {
+	UserID       string
+	Topic        string
+	Tokens       []string
+	Notification Notification
+}
+
+// Fake is an in-memory Notifier implementation to be used in tests
+type Fake struct {
+	mu     sync.Mutex
+	sent   []Sent
+	topics map[string]map[string]bool
+}
+
+// NewFake returns a Fake notifier which sent nothing
+func NewFake() *Fake {
+	return &Fake{topics: map[string]map[string]bool{}}
+}
+
+func (f *Fake) SendToUser(_ context.Context, userID string, n Notification) {
+	f.record(Sent{UserID: userID, Notification: n})
+}
+
+func (f *Fake) SendToTopic(_ context.Context, topic string, n Notification) {
+	f.record(Sent{Topic: topic, Notification: n})
+}
+
+// SendMulticast records the notification, reporting it delivered to every device
+func (f *Fake) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	f.record(Sent{Tokens: tokens, Notification: n})
+
+	results := make([]TokenResult, 0, len(tokens))
+	for _, token := range tokens {
+		results = append(results, TokenResult{Token: token})
+	}
+	reportFrom(ctx).addResults(results)
+}
+
+func (f *Fake) SubscribeToTopic(_ context.Context, tokens []string, topic string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.topics[topic] == nil {
+		f.topics[topic] = map[string]bool{}
+	}
+	for _, token := range tokens {
+		f.topics[topic][token] = true
+	}
+}
+
+func (f *Fake) UnsubscribeFromTopic(_ context.Context, tokens []string, topic string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, token := range tokens {
+		delete(f.topics[topic], token)
+	}
+}
+
+func (f *Fake) record(sent Sent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, sent)
+}
+
+// Sent returns the notifications sent so far
+func (f *Fake) Sent() []Sent {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Sent(nil), f.sent...)
+}
+
+// Subscribers returns the sorted tokens subscribed to the topic
+func (f *Fake) Subscribers(topic string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tokens := []string{}
+	for token := range f.topics[topic] {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	return tokens
+}
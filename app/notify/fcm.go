@@ -0,0 +1,541 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/tracing"
+	"context"
+	firebase "firebase.google.com/go/v4"
+	"firebase.google.com/go/v4/messaging"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+const (
+	// maxMulticastTokens is the number of tokens FCM accepts in a multicast message
+	maxMulticastTokens = 500
+	// maxTopicTokens is the number of tokens FCM (un)subscribes to a topic at once
+	maxTopicTokens = 1000
+	// the devices failing with a transient error are retried, waiting twice as long every time
+	maxSendAttempts = 3
+	sendRetryDelay  = 500 * time.Millisecond
+)
+
+const (
+	multicastBatchesMetric  = "notifications_multicast_batches_total"
+	multicastTokensMetric   = "notifications_multicast_tokens_total"
+	multicastFailuresMetric = "notifications_multicast_failures_total"
+)
+
+// webPlatform is the platform of the devices registered by browsers
+const webPlatform = "web"
+
+// fcmClient is the part of the FCM client used to send, faked in tests
+type fcmClient interface {
+	Send(ctx context.Context, message *messaging.Message) (string, error)
+	SendDryRun(ctx context.Context, message *messaging.Message) (string, error)
+	SendMulticast(ctx context.Context, message *messaging.MulticastMessage) (*messaging.BatchResponse, error)
+	SendMulticastDryRun(ctx context.Context, message *messaging.MulticastMessage) (*messaging.BatchResponse, error)
+	SubscribeToTopic(ctx context.Context, tokens []string, topic string) (*messaging.TopicManagementResponse, error)
+	UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) (*messaging.TopicManagementResponse, error)
+}
+
+// sendErrorKind tells what to do about a failed send
+type sendErrorKind int
+
+const (
+	// reported, sending again would fail the same way
+	permanentError sendErrorKind = iota
+	// retried
+	transientError
+	// the token is no longer valid and gets forgotten
+	staleTokenError
+)
+
+// fcmErrorKind classifies the errors of FCM
+func fcmErrorKind(err error) sendErrorKind {
+	switch {
+	case messaging.IsUnregistered(err), messaging.IsSenderIDMismatch(err):
+		return staleTokenError
+	case messaging.IsUnavailable(err), messaging.IsInternal(err), messaging.IsQuotaExceeded(err):
+		return transientError
+	}
+	return permanentError
+}
+
+// TokenResult is the outcome of sending a notification to a device
+type TokenResult struct {
+	Token string
+	Err   error
+	// Stale tells the token will never work again
+	Stale bool
+}
+
+// FCMConfig contains the defaults of the notifications sent with FCM, only validated in DryRun mode:
+// the Android notifications without a channel are displayed by AndroidChannelID
+type FCMConfig struct {
+	DryRun           bool
+	AndroidChannelID string
+}
+
+// FCM sends the notifications with Firebase Cloud Messaging, only validating them in dry run mode
+// or with a context returned by WithDryRun
+type FCM struct {
+	client           fcmClient
+	dryRun           bool
+	androidChannelID string
+	tokens           DeviceTokens
+	badges           Badges
+	classify         func(err error) sendErrorKind
+	retryDelay       time.Duration
+	metrics          metrics.Metrics
+}
+
+// NewFCM returns an FCM sender recording the size and the failures of the multicast batches in m.
+// The notifications to users badge the app icon with their unread ones, counted by badges.
+func NewFCM(app *firebase.App, conf FCMConfig, tokens DeviceTokens, badges Badges, m metrics.Metrics) (*FCM, error) {
+	client, err := app.Messaging(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("getting the messaging client: %w", err)
+	}
+
+	return newFCM(client, conf, tokens, badges, m), nil
+}
+
+func newFCM(client fcmClient, conf FCMConfig, tokens DeviceTokens, badges Badges, m metrics.Metrics) *FCM {
+	return &FCM{
+		client:           client,
+		dryRun:           conf.DryRun,
+		androidChannelID: conf.AndroidChannelID,
+		tokens:           tokens,
+		badges:           badges,
+		classify:         fcmErrorKind,
+		retryDelay:       sendRetryDelay,
+		metrics:          m,
+	}
+}
+
+// SendToUser fans the notification out to the devices of the user able to receive it, but the ones
+// subscribed with Web Push, badging the app icon with their unread notifications. Its failure is
+// only retryable when no device was reached, so none gets the notification twice.
+func (f *FCM) SendToUser(ctx context.Context, userID string, n Notification) error {
+	devices, err := f.tokens.UserDevices(ctx, userID)
+	if err != nil {
+		return Retryable(fmt.Errorf("resolving the devices of the user: %w", err))
+	}
+	tokens := make([]string, 0, len(devices))
+	for _, device := range devices {
+		if receives(device.Platform, n) && !IsWebPushToken(device.Token) {
+			tokens = append(tokens, device.Token)
+		}
+	}
+	if len(tokens) == 0 {
+		logging.FromContext(ctx).Debugf("user %s has no device to notify", userID)
+		return nil
+	}
+
+	n = f.badged(ctx, userID, n)
+	results := f.multicast(ctx, tokens, n)
+	logging.FromContext(ctx).Infof("sent message to %d of %d devices of user %s", delivered(results), len(tokens), userID)
+	if err := deliveryError(results, f.classify); err != nil {
+		return fmt.Errorf("sending message to the devices of the user: %w", err)
+	}
+	return nil
+}
+
+// SendMulticast sends the notification to the devices, in batches of the most tokens FCM accepts,
+// but the ones subscribed with Web Push. Its failure is only retryable when no device was reached, so
+// none gets the notification twice.
+func (f *FCM) SendMulticast(ctx context.Context, tokens []string, n Notification) error {
+	tokens = fcmTokens(tokens)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	results := f.multicast(ctx, tokens, n)
+	logging.FromContext(ctx).Infof("sent message to %d of %d devices", delivered(results), len(tokens))
+	if err := deliveryError(results, f.classify); err != nil {
+		return fmt.Errorf("sending message to %s: %w", devicesSummary(tokens), err)
+	}
+	return nil
+}
+
+// multicast sends the notification to the devices, forgetting the stale tokens, and returns the
+// outcome for each of their tokens, in order, adding it to the report of the context. The failures
+// are counted by kind.
+func (f *FCM) multicast(ctx context.Context, tokens []string, n Notification) []TokenResult {
+	results := f.sendToDevices(ctx, tokens, n)
+	reportFrom(ctx).addResults(results)
+
+	var stale []string
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		reason := "permanent"
+		switch {
+		case result.Stale:
+			reason = "stale"
+			stale = append(stale, result.Token)
+		case f.classify(result.Err) == transientError:
+			reason = "transient"
+		}
+		f.metrics.IncCounter(multicastFailuresMetric, metrics.Labels{"reason": reason})
+	}
+
+	if len(stale) > 0 {
+		logging.FromContext(ctx).Infof("forgetting %d devices FCM no longer knows", len(stale))
+		if err := f.tokens.Forget(ctx, stale); err != nil {
+			logging.FromContext(ctx).Errorf("error forgetting %d devices: %v", len(stale), err)
+		}
+	}
+	return results
+}
+
+// receives tells if the devices of the platform can receive the notification: browsers display
+// every push, so they don't receive the silent ones
+func receives(platform string, n Notification) bool {
+	return !n.Silent || platform != webPlatform
+}
+
+// fcmTokens returns the tokens of FCM among the tokens, leaving out the Web Push ones
+func fcmTokens(tokens []string) []string {
+	filtered := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if !IsWebPushToken(token) {
+			filtered = append(filtered, token)
+		}
+	}
+	return filtered
+}
+
+// badged sets the badge of the notification to the unread notifications of the user, unless it
+// has one. The notification is sent without a badge when they fail to be counted.
+func (f *FCM) badged(ctx context.Context, userID string, n Notification) Notification {
+	if f.badges == nil || (n.APNS != nil && n.APNS.Badge != nil) {
+		return n
+	}
+
+	unread, err := f.badges.Unread(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error counting the unread notifications of user %s: %v", userID, err)
+		return n
+	}
+	options := APNSOptions{}
+	if n.APNS != nil {
+		options = *n.APNS
+	}
+	options.Badge = &unread
+	n.APNS = &options
+	return n
+}
+
+func delivered(results []TokenResult) int {
+	sent := 0
+	for _, result := range results {
+		if result.Err == nil {
+			sent++
+		}
+	}
+	return sent
+}
+
+// deliveryError returns the first failure of the delivery to devices other than stale ones,
+// retryable when no device was reached and every failure was transient per classify
+func deliveryError(results []TokenResult, classify func(err error) sendErrorKind) error {
+	transient := true
+	var failures []error
+	for _, result := range results {
+		if result.Err != nil && !result.Stale {
+			failures = append(failures, result.Err)
+			transient = transient && classify(result.Err) == transientError
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+	err := fmt.Errorf("%d of %d devices failed: %w", len(failures), len(results), failures[0])
+	if delivered(results) == 0 && transient {
+		return Retryable(err)
+	}
+	return err
+}
+
+// sendToDevices sends the notification to the devices, retrying the ones failing with a transient
+// error, and returns the outcome for each of their tokens, in order
+func (f *FCM) sendToDevices(ctx context.Context, tokens []string, n Notification) []TokenResult {
+	results := make([]TokenResult, len(tokens))
+	pending := make([]int, len(tokens))
+	for i, token := range tokens {
+		results[i].Token = token
+		pending[i] = i
+	}
+
+	delay := f.retryDelay
+	for attempt := 1; ; attempt++ {
+		f.sendMulticast(ctx, results, pending, n)
+
+		var retry []int
+		for _, i := range pending {
+			if results[i].Err != nil && f.classify(results[i].Err) == transientError {
+				retry = append(retry, i)
+			}
+		}
+		if len(retry) == 0 || attempt == maxSendAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return f.markStale(results)
+		case <-time.After(delay):
+		}
+		delay *= 2
+		pending = retry
+	}
+
+	return f.markStale(results)
+}
+
+// sendMulticast sends the notification to the pending devices, recording their outcome in results
+func (f *FCM) sendMulticast(ctx context.Context, results []TokenResult, pending []int, n Notification) {
+	sendFunc := f.client.SendMulticast
+	if f.dryRun || isDryRun(ctx) {
+		sendFunc = f.client.SendMulticastDryRun
+	}
+
+	for start := 0; start < len(pending); start += maxMulticastTokens {
+		end := start + maxMulticastTokens
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+		tokens := make([]string, len(batch))
+		for j, i := range batch {
+			tokens[j] = results[i].Token
+		}
+
+		f.metrics.IncCounter(multicastBatchesMetric, nil)
+		f.metrics.AddCounter(multicastTokensMetric, float64(len(tokens)), nil)
+		ctx, span := tracing.Start(ctx, "fcm.SendMulticast",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.Int("messaging.batch.message_count", len(tokens))),
+		)
+		response, err := sendFunc(ctx, f.multicastMessage(tokens, n))
+		tracing.End(span, err)
+
+		for j, i := range batch {
+			switch {
+			case err != nil:
+				results[i].Err = err
+			case j < len(response.Responses) && !response.Responses[j].Success:
+				results[i].Err = response.Responses[j].Error
+			default:
+				results[i].Err = nil
+			}
+		}
+	}
+}
+
+func (f *FCM) markStale(results []TokenResult) []TokenResult {
+	for i := range results {
+		results[i].Stale = results[i].Err != nil && f.classify(results[i].Err) == staleTokenError
+	}
+	return results
+}
+
+func (f *FCM) SendToTopic(ctx context.Context, topic string, n Notification) error {
+	return f.send(ctx, f.message(topic, n))
+}
+
+// SubscribeToTopic subscribes the devices to the topic, which dry run mode skips as it can't be validated only
+func (f *FCM) SubscribeToTopic(ctx context.Context, tokens []string, topic string) error {
+	return f.manageTopic(ctx, tokens, topic, true)
+}
+
+func (f *FCM) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) error {
+	return f.manageTopic(ctx, tokens, topic, false)
+}
+
+// manageTopic (un)subscribes the devices to the topic in chunks of the most tokens FCM accepts,
+// logging the tokens it refused. The failed chunks are all attempted again on retry, which FCM
+// doesn't mind.
+func (f *FCM) manageTopic(ctx context.Context, tokens []string, topic string, subscribe bool) error {
+	manage, spanName, action := f.client.SubscribeToTopic, "fcm.SubscribeToTopic", "subscribing devices to"
+	if !subscribe {
+		manage, spanName, action = f.client.UnsubscribeFromTopic, "fcm.UnsubscribeFromTopic", "unsubscribing devices from"
+	}
+
+	tokens = fcmTokens(tokens)
+	if len(tokens) == 0 {
+		return nil
+	}
+	if f.dryRun || isDryRun(ctx) {
+		logging.FromContext(ctx).Infof("dry run, not %s topic %s", action, topic)
+		return nil
+	}
+
+	done := 0
+	var failed error
+	for start := 0; start < len(tokens); start += maxTopicTokens {
+		end := start + maxTopicTokens
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+		chunk := tokens[start:end]
+
+		ctx, span := tracing.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("messaging.destination", topic),
+				attribute.Int("messaging.batch.message_count", len(chunk)),
+			),
+		)
+		response, err := manage(ctx, chunk, topic)
+		tracing.End(span, err)
+		if err != nil {
+			failed = f.classified(fmt.Errorf("%s topic %s: %w", action, topic, err), err)
+			continue
+		}
+
+		for _, failure := range response.Errors {
+			logging.FromContext(ctx).Warnf("error %s topic %s: %s", action, topic, failure.Reason)
+		}
+		done += response.SuccessCount
+	}
+	logging.FromContext(ctx).Infof("%s topic %s: %d of %d succeeded", action, topic, done, len(tokens))
+	return failed
+}
+
+// message builds the FCM message of the notification, a data message when it has nothing to display
+func (f *FCM) message(topic string, n Notification) *messaging.Message {
+	return &messaging.Message{
+		Data:         n.payload(),
+		Notification: displayed(n),
+		Topic:        topic,
+		Android:      f.androidConfig(n),
+		APNS:         apnsConfig(n),
+		Webpush:      webpushConfig(n),
+	}
+}
+
+// multicastMessage builds the FCM message of the notification to the devices with the tokens
+func (f *FCM) multicastMessage(tokens []string, n Notification) *messaging.MulticastMessage {
+	return &messaging.MulticastMessage{
+		Tokens:       tokens,
+		Data:         n.payload(),
+		Notification: displayed(n),
+		Android:      f.androidConfig(n),
+		APNS:         apnsConfig(n),
+		Webpush:      webpushConfig(n),
+	}
+}
+
+// displayed returns what's displayed of the notification, nil for data and silent messages
+func displayed(n Notification) *messaging.Notification {
+	if n.Silent || (n.Title == "" && n.Body == "") {
+		return nil
+	}
+	return &messaging.Notification{Title: n.Title, Body: n.Body}
+}
+
+// androidConfig delivers the notifications right away, but the silent ones which may wait for the
+// device to be awake. The displayed ones go to their channel, the default channel without one, and
+// replace the displayed notification with the same collapse key.
+func (f *FCM) androidConfig(n Notification) *messaging.AndroidConfig {
+	config := &messaging.AndroidConfig{Priority: "high", CollapseKey: n.CollapseKey}
+	if n.Silent {
+		config.Priority = "normal"
+		return config
+	}
+	if displayed(n) == nil {
+		return config
+	}
+
+	options := AndroidOptions{}
+	if n.Android != nil {
+		options = *n.Android
+	}
+	if options.ChannelID == "" {
+		options.ChannelID = f.androidChannelID
+	}
+	if options != (AndroidOptions{}) || n.CollapseKey != "" {
+		config.Notification = &messaging.AndroidNotification{
+			ChannelID: options.ChannelID,
+			Sound:     options.Sound,
+			Color:     options.Color,
+			Tag:       n.CollapseKey,
+		}
+	}
+	return config
+}
+
+// apnsConfig marks every notification content-available, for the apps to handle their data. Apple
+// requires the silent ones to be background pushes of low priority, without an alert nor a sound.
+func apnsConfig(n Notification) *messaging.APNSConfig {
+	headers := map[string]string{"apns-priority": "10"}
+	aps := &messaging.Aps{ContentAvailable: true}
+	if n.Silent {
+		headers = map[string]string{"apns-priority": "5", "apns-push-type": "background"}
+	} else if displayed(n) != nil {
+		aps.Sound = "default"
+	}
+	if n.CollapseKey != "" {
+		headers["apns-collapse-id"] = n.CollapseKey
+	}
+
+	if n.APNS != nil {
+		aps.Badge = n.APNS.Badge
+		if n.APNS.Sound != "" && !n.Silent {
+			aps.Sound = n.APNS.Sound
+		}
+	}
+	return &messaging.APNSConfig{Headers: headers, Payload: &messaging.APNSPayload{Aps: aps}}
+}
+
+// webpushConfig returns the page and icon of the notifications displayed in browsers, nil without any
+func webpushConfig(n Notification) *messaging.WebpushConfig {
+	if n.Webpush == nil {
+		return nil
+	}
+
+	config := &messaging.WebpushConfig{}
+	if n.Webpush.Link != "" {
+		config.FCMOptions = &messaging.WebpushFCMOptions{Link: n.Webpush.Link}
+	}
+	if n.Webpush.Icon != "" {
+		config.Notification = &messaging.WebpushNotification{Icon: n.Webpush.Icon}
+	}
+	return config
+}
+
+func (f *FCM) send(ctx context.Context, message *messaging.Message) error {
+	sendFunc := f.client.Send
+	if f.dryRun || isDryRun(ctx) {
+		sendFunc = f.client.SendDryRun
+	}
+
+	ctx, span := tracing.Start(ctx, "fcm.Send",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("messaging.destination", message.Topic)),
+	)
+	response, err := sendFunc(ctx, message)
+	tracing.End(span, err)
+	if err != nil {
+		return f.classified(fmt.Errorf("sending message to topic %s: %w", message.Topic, err), err)
+	}
+
+	logging.FromContext(ctx).Infof("successfully sent message with id %s", response)
+	return nil
+}
+
+// classified marks wrapped as Retryable when the FCM error cause is transient
+func (f *FCM) classified(wrapped error, cause error) error {
+	if f.classify(cause) == transientError {
+		return Retryable(wrapped)
+	}
+	return wrapped
+}
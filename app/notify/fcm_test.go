@@ -0,0 +1,419 @@
+package notify
+
+import (
+	"appdoki-be/app/metrics"
+	"context"
+	"errors"
+	"firebase.google.com/go/v4/messaging"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestFCM_message(t *testing.T) {
+	f := &FCM{}
+
+	t.Run("expect notifications to be displayed with their deep link in the data", func(t *testing.T) {
+		data := map[string]string{"beers": "2"}
+		message := f.message("beers", Notification{Title: "BeerTab event", Body: "cheers", Data: data, DeepLink: "appdoki://users/42"})
+
+		if message.Topic != "beers" || message.Notification == nil || message.Notification.Title != "BeerTab event" {
+			t.Fatalf("unexpected message %+v", message)
+		}
+		if message.Data["beers"] != "2" || message.Data[deepLinkKey] != "appdoki://users/42" {
+			t.Fatalf("unexpected data %v", message.Data)
+		}
+		if _, ok := data[deepLinkKey]; ok {
+			t.Fatal("expected the data of the notification to be left untouched")
+		}
+	})
+
+	t.Run("expect notifications without a title nor a body to be data messages", func(t *testing.T) {
+		message := f.message("users", Notification{Data: map[string]string{"user": "{}"}})
+
+		if message.Notification != nil {
+			t.Fatalf("expected a data message, got %+v", message.Notification)
+		}
+	})
+
+	t.Run("expect silent notifications to be low priority background pushes without anything displayed", func(t *testing.T) {
+		message := f.message("users", Notification{Title: "not displayed", Silent: true, CollapseKey: "user_updated", Data: map[string]string{"user_id": "42"}})
+
+		if message.Notification != nil || message.Data["user_id"] != "42" {
+			t.Fatalf("expected a data message, got %+v", message)
+		}
+		if message.Android.Priority != "normal" || message.Android.CollapseKey != "user_updated" {
+			t.Fatalf("unexpected android config %+v", message.Android)
+		}
+		headers := map[string]string{"apns-priority": "5", "apns-push-type": "background", "apns-collapse-id": "user_updated"}
+		if !reflect.DeepEqual(message.APNS.Headers, headers) || !message.APNS.Payload.Aps.ContentAvailable || message.APNS.Payload.Aps.Alert != nil {
+			t.Fatalf("unexpected apns config %+v %+v", message.APNS.Headers, message.APNS.Payload.Aps)
+		}
+	})
+
+	t.Run("expect displayed notifications to be high priority", func(t *testing.T) {
+		message := f.multicastMessage([]string{"token-1"}, Notification{Title: "Cheers"})
+
+		if message.Notification == nil || message.Android.Priority != "high" || message.Android.CollapseKey != "" {
+			t.Fatalf("unexpected message %+v %+v", message, message.Android)
+		}
+		if !reflect.DeepEqual(message.APNS.Headers, map[string]string{"apns-priority": "10"}) || !message.APNS.Payload.Aps.ContentAvailable {
+			t.Fatalf("unexpected apns config %+v", message.APNS)
+		}
+	})
+
+	t.Run("expect displayed notifications to replace the ones with the same collapse key", func(t *testing.T) {
+		f := &FCM{}
+		message := f.message("users", Notification{Title: "Cheers", CollapseKey: EventBeerReceived})
+
+		if message.Android.Notification == nil || message.Android.Notification.Tag != EventBeerReceived {
+			t.Fatalf("expected the collapse key to tag the notification, got %+v", message.Android.Notification)
+		}
+	})
+
+	t.Run("expect the platform options to be mapped on the config of each platform", func(t *testing.T) {
+		badge := 7
+		f := &FCM{androidChannelID: "general"}
+		message := f.message("beers", Notification{
+			Title:   "Cheers",
+			APNS:    &APNSOptions{Badge: &badge, Sound: "cheers.caf"},
+			Android: &AndroidOptions{Color: "#ffcc00"},
+			Webpush: &WebpushOptions{Link: "https://appdoki.cloudoki.com/beers", Icon: "/beer.png"},
+		})
+
+		if aps := message.APNS.Payload.Aps; aps.Badge == nil || *aps.Badge != 7 || aps.Sound != "cheers.caf" {
+			t.Fatalf("unexpected aps %+v", aps)
+		}
+		if android := message.Android.Notification; android == nil || android.ChannelID != "general" || android.Color != "#ffcc00" {
+			t.Fatalf("expected the default channel along with the color, got %+v", android)
+		}
+		if webpush := message.Webpush; webpush.FCMOptions.Link != "https://appdoki.cloudoki.com/beers" || webpush.Notification.Icon != "/beer.png" {
+			t.Fatalf("unexpected webpush config %+v", webpush)
+		}
+	})
+
+	t.Run("expect the displayed notifications to default to the default sound and channel", func(t *testing.T) {
+		f := &FCM{androidChannelID: "general"}
+
+		message := f.message("beers", Notification{Title: "Cheers", Android: &AndroidOptions{ChannelID: "beers"}})
+		if message.APNS.Payload.Aps.Sound != "default" || message.Android.Notification.ChannelID != "beers" || message.Webpush != nil {
+			t.Fatalf("unexpected message %+v %+v", message.APNS.Payload.Aps, message.Android.Notification)
+		}
+
+		data := f.message("users", Notification{Data: map[string]string{"user": "{}"}})
+		if data.APNS.Payload.Aps.Sound != "" || data.Android.Notification != nil {
+			t.Fatalf("expected data messages to make no sound, got %+v %+v", data.APNS.Payload.Aps, data.Android.Notification)
+		}
+	})
+
+	t.Run("expect the notifications to users to be sent to their devices", func(t *testing.T) {
+		message := f.multicastMessage([]string{"token-1", "token-2"}, Notification{Title: "Cheers", DeepLink: "appdoki://beers"})
+
+		if len(message.Tokens) != 2 || message.Notification == nil || message.Data[deepLinkKey] != "appdoki://beers" {
+			t.Fatalf("unexpected message %+v", message)
+		}
+	})
+}
+
+var (
+	errStaleToken = errors.New("unregistered")
+	errTransient  = errors.New("unavailable")
+	errPermanent  = errors.New("invalid argument")
+)
+
+// fakeFCMClient answers each token with the next of its outcomes, success once they're exhausted
+type fakeFCMClient struct {
+	mu         sync.Mutex
+	outcomes   map[string][]error
+	calls      [][]string
+	messages   []*messaging.MulticastMessage
+	topicCalls [][]string
+}
+
+func (c *fakeFCMClient) Send(context.Context, *messaging.Message) (string, error) {
+	return "message-id", nil
+}
+
+func (c *fakeFCMClient) SendDryRun(ctx context.Context, message *messaging.Message) (string, error) {
+	return c.Send(ctx, message)
+}
+
+func (c *fakeFCMClient) SendMulticast(_ context.Context, message *messaging.MulticastMessage) (*messaging.BatchResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = append(c.calls, message.Tokens)
+	c.messages = append(c.messages, message)
+	response := &messaging.BatchResponse{}
+	for _, token := range message.Tokens {
+		var err error
+		if outcomes := c.outcomes[token]; len(outcomes) > 0 {
+			err, c.outcomes[token] = outcomes[0], outcomes[1:]
+		}
+		if err != nil {
+			response.FailureCount++
+		} else {
+			response.SuccessCount++
+		}
+		response.Responses = append(response.Responses, &messaging.SendResponse{Success: err == nil, Error: err})
+	}
+	return response, nil
+}
+
+func (c *fakeFCMClient) SendMulticastDryRun(ctx context.Context, message *messaging.MulticastMessage) (*messaging.BatchResponse, error) {
+	return c.SendMulticast(ctx, message)
+}
+
+func (c *fakeFCMClient) SubscribeToTopic(_ context.Context, tokens []string, _ string) (*messaging.TopicManagementResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.topicCalls = append(c.topicCalls, tokens)
+	return &messaging.TopicManagementResponse{SuccessCount: len(tokens)}, nil
+}
+
+func (c *fakeFCMClient) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) (*messaging.TopicManagementResponse, error) {
+	return c.SubscribeToTopic(ctx, tokens, topic)
+}
+
+type fakeDeviceTokens struct {
+	tokens []string
+	// platforms are the platforms of the tokens, ios when missing
+	platforms map[string]string
+	forgotten []string
+}
+
+func (d *fakeDeviceTokens) UserDevices(context.Context, string) ([]Device, error) {
+	devices := make([]Device, 0, len(d.tokens))
+	for _, token := range d.tokens {
+		platform := d.platforms[token]
+		if platform == "" {
+			platform = "ios"
+		}
+		devices = append(devices, Device{Token: token, Platform: platform})
+	}
+	return devices, nil
+}
+
+// fakeBadges counts the same unread notifications for everyone
+type fakeBadges int
+
+func (b fakeBadges) Unread(context.Context, string) (int, error) {
+	return int(b), nil
+}
+
+func (d *fakeDeviceTokens) Forget(_ context.Context, tokens []string) error {
+	d.forgotten = append(d.forgotten, tokens...)
+	return nil
+}
+
+func newTestFCM(client fcmClient, tokens DeviceTokens) *FCM {
+	f := newFCM(client, FCMConfig{}, tokens, nil, metrics.NewFake())
+	f.retryDelay = 0
+	f.classify = func(err error) sendErrorKind {
+		switch {
+		case errors.Is(err, errStaleToken):
+			return staleTokenError
+		case errors.Is(err, errTransient):
+			return transientError
+		}
+		return permanentError
+	}
+	return f
+}
+
+func TestFCM_SendToUser(t *testing.T) {
+	t.Run("expect the stale tokens to be forgotten and the transient errors retried", func(t *testing.T) {
+		client := &fakeFCMClient{outcomes: map[string][]error{
+			"stale":     {errStaleToken},
+			"transient": {errTransient},
+			"invalid":   {errPermanent},
+		}}
+		tokens := &fakeDeviceTokens{tokens: []string{"ok", "stale", "transient", "invalid"}}
+		f := newTestFCM(client, tokens)
+
+		err := f.SendToUser(context.Background(), "42", Notification{Title: "Cheers"})
+
+		if !reflect.DeepEqual(tokens.forgotten, []string{"stale"}) {
+			t.Fatalf("expected the stale token to be forgotten, got %v", tokens.forgotten)
+		}
+		if len(client.calls) != 2 || !reflect.DeepEqual(client.calls[1], []string{"transient"}) {
+			t.Fatalf("expected the transient failure alone to be retried, got %v", client.calls)
+		}
+		if !errors.Is(err, errPermanent) || IsRetryable(err) {
+			t.Fatalf("expected the permanent failure to be returned, got %v", err)
+		}
+	})
+
+	t.Run("expect silent notifications to skip the browsers and the app icon to be badged", func(t *testing.T) {
+		client := &fakeFCMClient{}
+		f := newTestFCM(client, &fakeDeviceTokens{tokens: []string{"phone", "browser"}, platforms: map[string]string{"browser": "web"}})
+		f.badges = fakeBadges(3)
+
+		if err := f.SendToUser(context.Background(), "42", Notification{Silent: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.SendToUser(context.Background(), "42", Notification{Title: "Cheers"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(client.calls) != 2 || !reflect.DeepEqual(client.calls[0], []string{"phone"}) || len(client.calls[1]) != 2 {
+			t.Fatalf("expected the browser to only get the displayed notification, got %v", client.calls)
+		}
+		if badge := client.messages[1].APNS.Payload.Aps.Badge; badge == nil || *badge != 3 {
+			t.Fatalf("expected the unread notifications to badge the icon, got %v", badge)
+		}
+	})
+
+	t.Run("expect the failure to be retryable when no device was reached", func(t *testing.T) {
+		client := &fakeFCMClient{outcomes: map[string][]error{
+			"a": {errTransient, errTransient, errTransient},
+			"b": {errTransient, errTransient, errTransient},
+		}}
+		f := newTestFCM(client, &fakeDeviceTokens{tokens: []string{"a", "b"}})
+
+		if err := f.SendToUser(context.Background(), "42", Notification{Title: "Cheers"}); !IsRetryable(err) {
+			t.Fatalf("expected a retryable error, got %v", err)
+		}
+	})
+
+	t.Run("expect the transient errors to be given up on after the last attempt", func(t *testing.T) {
+		client := &fakeFCMClient{outcomes: map[string][]error{
+			"transient": {errTransient, errTransient, errTransient, errTransient},
+		}}
+		f := newTestFCM(client, &fakeDeviceTokens{})
+
+		results := f.sendToDevices(context.Background(), []string{"ok", "transient"}, Notification{})
+
+		if len(client.calls) != maxSendAttempts {
+			t.Fatalf("expected %d attempts, got %d", maxSendAttempts, len(client.calls))
+		}
+		if results[0].Token != "ok" || results[0].Err != nil {
+			t.Fatalf("expected the first device to be sent to, got %+v", results[0])
+		}
+		if results[1].Token != "transient" || !errors.Is(results[1].Err, errTransient) || results[1].Stale {
+			t.Fatalf("expected the second device to fail, got %+v", results[1])
+		}
+	})
+}
+
+func TestFCM_SendMulticast(t *testing.T) {
+	t.Run("expect the tokens to be sent to in batches of the most FCM accepts", func(t *testing.T) {
+		client := &fakeFCMClient{}
+		f := newTestFCM(client, &fakeDeviceTokens{})
+		tokens := make([]string, 2*maxMulticastTokens+1)
+		for i := range tokens {
+			tokens[i] = fmt.Sprintf("token-%d", i)
+		}
+
+		if err := f.SendMulticast(context.Background(), tokens, Notification{Title: "Welcome"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(client.calls) != 3 || len(client.calls[0]) != maxMulticastTokens || len(client.calls[1]) != maxMulticastTokens || len(client.calls[2]) != 1 {
+			t.Fatalf("expected batches of 500, 500 and 1, got %d batches", len(client.calls))
+		}
+		if client.calls[2][0] != tokens[len(tokens)-1] {
+			t.Fatalf("expected every token to be sent to, got %v last", client.calls[2])
+		}
+		m := f.metrics.(*metrics.Fake)
+		if m.Counter(multicastBatchesMetric, nil) != 3 || m.Counter(multicastTokensMetric, nil) != float64(len(tokens)) {
+			t.Fatalf("expected 3 batches of %d tokens to be recorded", len(tokens))
+		}
+	})
+
+	t.Run("expect the stale tokens to be forgotten and the failures counted by kind", func(t *testing.T) {
+		client := &fakeFCMClient{outcomes: map[string][]error{
+			"stale":   {errStaleToken},
+			"invalid": {errPermanent},
+		}}
+		tokens := &fakeDeviceTokens{}
+		f := newTestFCM(client, tokens)
+
+		err := f.SendMulticast(context.Background(), []string{"ok", "stale", "invalid"}, Notification{Title: "Welcome"})
+
+		if !reflect.DeepEqual(tokens.forgotten, []string{"stale"}) {
+			t.Fatalf("expected the stale token to be forgotten, got %v", tokens.forgotten)
+		}
+		if !errors.Is(err, errPermanent) || IsRetryable(err) {
+			t.Fatalf("expected the permanent failure to be returned, got %v", err)
+		}
+		m := f.metrics.(*metrics.Fake)
+		for _, reason := range []string{"stale", "permanent"} {
+			if count := m.Counter(multicastFailuresMetric, metrics.Labels{"reason": reason}); count != 1 {
+				t.Fatalf("expected 1 %s failure, got %v", reason, count)
+			}
+		}
+	})
+
+	t.Run("expect the web push tokens to be left to WebPush", func(t *testing.T) {
+		client := &fakeFCMClient{}
+		f := newTestFCM(client, &fakeDeviceTokens{})
+
+		if err := f.SendMulticast(context.Background(), []string{"fcm-token", WebPushToken("https://push.example.com/1")}, Notification{Title: "Welcome"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(client.calls) != 1 || !reflect.DeepEqual(client.calls[0], []string{"fcm-token"}) {
+			t.Fatalf("expected only the FCM token to be sent to, got %v", client.calls)
+		}
+	})
+
+	t.Run("expect the outcome for each token to be added to the report of the context", func(t *testing.T) {
+		client := &fakeFCMClient{outcomes: map[string][]error{"stale": {errStaleToken}}}
+		f := newTestFCM(client, &fakeDeviceTokens{})
+		ctx, report := WithReport(context.Background())
+
+		f.SendMulticast(ctx, []string{"ok", "stale"}, Notification{Title: "Welcome"})
+
+		expected := []TokenResult{{Token: "ok"}, {Token: "stale", Err: errStaleToken, Stale: true}}
+		if results := report.Results(); !reflect.DeepEqual(results, expected) {
+			t.Fatalf("expected the outcome of both tokens, got %+v", results)
+		}
+	})
+}
+
+func TestFCM_SubscribeToTopic(t *testing.T) {
+	t.Run("expect the tokens to be subscribed in chunks of the most FCM accepts", func(t *testing.T) {
+		client := &fakeFCMClient{}
+		f := newTestFCM(client, &fakeDeviceTokens{})
+		tokens := make([]string, 2*maxTopicTokens+1)
+		for i := range tokens {
+			tokens[i] = fmt.Sprintf("token-%d", i)
+		}
+
+		if err := f.SubscribeToTopic(context.Background(), tokens, "all-users"); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(client.topicCalls) != 3 || len(client.topicCalls[0]) != maxTopicTokens || len(client.topicCalls[2]) != 1 {
+			t.Fatalf("expected 3 chunks, got %d", len(client.topicCalls))
+		}
+		if client.topicCalls[2][0] != tokens[len(tokens)-1] {
+			t.Fatalf("expected every token to be subscribed, got %v last", client.topicCalls[2])
+		}
+	})
+
+	t.Run("expect nothing to be subscribed in dry run mode", func(t *testing.T) {
+		client := &fakeFCMClient{}
+		f := newFCM(client, FCMConfig{DryRun: true}, &fakeDeviceTokens{}, nil, metrics.Noop{})
+
+		f.SubscribeToTopic(context.Background(), []string{"token"}, "all-users")
+
+		if len(client.topicCalls) != 0 {
+			t.Fatalf("expected no call, got %v", client.topicCalls)
+		}
+	})
+
+	t.Run("expect nothing to be subscribed with a dry run context", func(t *testing.T) {
+		client := &fakeFCMClient{}
+		f := newFCM(client, FCMConfig{}, &fakeDeviceTokens{}, nil, metrics.Noop{})
+
+		f.SubscribeToTopic(WithDryRun(context.Background()), []string{"token"}, "all-users")
+
+		if len(client.topicCalls) != 0 {
+			t.Fatalf("expected no call, got %v", client.topicCalls)
+		}
+	})
+}
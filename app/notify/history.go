@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+)
+
+// Delivery statuses of the notifications kept in the history
+const (
+	DeliverySent   = "sent"
+	DeliveryFailed = "failed"
+	// DeliverySuppressed notifications were kept in the history without being pushed
+	DeliverySuppressed = "suppressed"
+	// DeliveryDeferred notifications are pushed once the quiet hours of their user end
+	DeliveryDeferred = "deferred"
+)
+
+// History keeps the notifications sent to the users, pending until the status of their delivery is set
+type History interface {
+	Record(ctx context.Context, userID string, n Notification) (int64, error)
+	SetStatus(ctx context.Context, id int64, status string) error
+}
+
+// historyNotifier records the notifications to users before sending them
+type historyNotifier struct {
+	next    Notifier
+	history History
+}
+
+// WithHistory returns a Notifier recording the notifications to users in history before sending
+// them through next, for TrackDelivery to set how their delivery went. The notifications failing
+// to be recorded are sent all the same, and the silent ones aren't recorded.
+func WithHistory(next Notifier, history History) Notifier {
+	return &historyNotifier{next: next, history: history}
+}
+
+func (h *historyNotifier) SendToUser(ctx context.Context, userID string, n Notification) {
+	if n.Silent {
+		h.next.SendToUser(ctx, userID, n)
+		return
+	}
+
+	id, err := h.history.Record(ctx, userID, n)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error recording a notification to user %s: %v", userID, err)
+	}
+	n.historyID = id
+
+	h.next.SendToUser(ctx, userID, n)
+}
+
+func (h *historyNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	h.next.SendToTopic(ctx, topic, n)
+}
+
+func (h *historyNotifier) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	h.next.SendMulticast(ctx, tokens, n)
+}
+
+func (h *historyNotifier) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	h.next.SubscribeToTopic(ctx, tokens, topic)
+}
+
+func (h *historyNotifier) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	h.next.UnsubscribeFromTopic(ctx, tokens, topic)
+}
+
+// deliveryTracker sets the delivery status of the notifications recorded by WithHistory
+type deliveryTracker struct {
+	Sender
+	history History
+}
+
+// TrackDelivery returns a Sender setting the delivery status of the notifications to users recorded
+// by WithHistory after every attempt to send them through next, so a retry succeeding marks the
+// failed notification sent
+func TrackDelivery(next Sender, history History) Sender {
+	return &deliveryTracker{Sender: next, history: history}
+}
+
+func (t *deliveryTracker) SendToUser(ctx context.Context, userID string, n Notification) error {
+	err := t.Sender.SendToUser(ctx, userID, n)
+	if n.historyID == 0 {
+		return err
+	}
+
+	status := DeliverySent
+	if err != nil {
+		status = DeliveryFailed
+	}
+	if err := t.history.SetStatus(ctx, n.historyID, status); err != nil {
+		logging.FromContext(ctx).Errorf("error setting the delivery status of notification %d: %v", n.historyID, err)
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeHistory records the notifications in memory with their delivery statuses
+type fakeHistory struct {
+	recorded []Notification
+	statuses map[int64][]string
+}
+
+func (h *fakeHistory) Record(_ context.Context, _ string, n Notification) (int64, error) {
+	h.recorded = append(h.recorded, n)
+	return int64(len(h.recorded)), nil
+}
+
+func (h *fakeHistory) SetStatus(_ context.Context, id int64, status string) error {
+	if h.statuses == nil {
+		h.statuses = map[int64][]string{}
+	}
+	h.statuses[id] = append(h.statuses[id], status)
+	return nil
+}
+
+func TestWithHistory(t *testing.T) {
+	t.Run("expect the delivery of the notifications to users to be tracked attempt after attempt", func(t *testing.T) {
+		history := &fakeHistory{}
+		sender := &flakySender{failures: 1, err: Retryable(errors.New("unavailable"))}
+		retrying := WithRetry(TrackDelivery(sender, history), RetryPolicy{MaxAttempts: 2},
+			func(ctx context.Context, f func(ctx context.Context)) { f(ctx) }, nil).(*retryNotifier)
+		retrying.sleep = func(context.Context, time.Duration) bool { return true }
+		n := WithHistory(retrying, history)
+
+		n.SendToUser(context.Background(), "42", Notification{Title: "Cheers!"})
+		n.SendToTopic(context.Background(), "beers", Notification{Title: "BeerTab event"})
+
+		if len(history.recorded) != 1 || history.recorded[0].Title != "Cheers!" {
+			t.Fatalf("expected the notification to the user alone to be recorded, got %+v", history.recorded)
+		}
+		if statuses := history.statuses[1]; len(statuses) != 2 || statuses[0] != DeliveryFailed || statuses[1] != DeliverySent {
+			t.Fatalf("expected the failed attempt then the delivery, got %v", statuses)
+		}
+	})
+	t.Run("expect silent notifications to be sent without being recorded", func(t *testing.T) {
+		history := &fakeHistory{}
+		fake := NewFake()
+
+		WithHistory(fake, history).SendToUser(context.Background(), "42", Notification{Silent: true, Event: "user_updated"})
+
+		if len(history.recorded) != 0 || len(fake.Sent()) != 1 {
+			t.Fatalf("expected the notification to be sent unrecorded, got %+v and %+v", history.recorded, fake.Sent())
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"appdoki-be/app/metrics"
+	"context"
+	"time"
+)
+
+const (
+	sendDurationMetric = "notifications_send_duration_seconds"
+	sentMetric         = "notifications_sent_total"
+	sendErrorsMetric   = "notifications_send_errors_total"
+)
+
+// instrumentedSender records the attempts to send through a push service
+type instrumentedSender struct {
+	next    Sender
+	channel string
+	metrics metrics.Metrics
+	now     func() time.Time
+}
+
+// Instrument returns a Sender recording how long every attempt to send through next takes, by
+// channel (ex.: fcm), op and outcome, counting the successful ones and the failed ones by error
+// class, transient when Retryable, permanent otherwise
+func Instrument(next Sender, channel string, m metrics.Metrics) Sender {
+	return &instrumentedSender{next: next, channel: channel, metrics: m, now: time.Now}
+}
+
+func (s *instrumentedSender) SendToUser(ctx context.Context, userID string, n Notification) error {
+	return s.observe(OpSendToUser, func() error { return s.next.SendToUser(ctx, userID, n) })
+}
+
+func (s *instrumentedSender) SendToTopic(ctx context.Context, topic string, n Notification) error {
+	return s.observe(OpSendToTopic, func() error { return s.next.SendToTopic(ctx, topic, n) })
+}
+
+func (s *instrumentedSender) SendMulticast(ctx context.Context, tokens []string, n Notification) error {
+	return s.observe(OpSendMulticast, func() error { return s.next.SendMulticast(ctx, tokens, n) })
+}
+
+func (s *instrumentedSender) SubscribeToTopic(ctx context.Context, tokens []string, topic string) error {
+	return s.observe(OpSubscribeToTopic, func() error { return s.next.SubscribeToTopic(ctx, tokens, topic) })
+}
+
+func (s *instrumentedSender) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) error {
+	return s.observe(OpUnsubscribeFromTopic, func() error { return s.next.UnsubscribeFromTopic(ctx, tokens, topic) })
+}
+
+func (s *instrumentedSender) observe(op string, send func() error) error {
+	start := s.now()
+	err := send()
+
+	outcome := "sent"
+	if err != nil {
+		outcome = "failed"
+		s.metrics.IncCounter(sendErrorsMetric, metrics.Labels{"channel": s.channel, "op": op, "class": errorClass(err)})
+	} else {
+		s.metrics.IncCounter(sentMetric, metrics.Labels{"channel": s.channel, "op": op})
+	}
+	s.metrics.ObserveHistogram(sendDurationMetric, s.now().Sub(start).Seconds(), metrics.Labels{
+		"channel": s.channel,
+		"op":      op,
+		"outcome": outcome,
+	})
+	return err
+}
+
+// errorClass classifies the error of a send for the metrics
+func errorClass(err error) string {
+	if IsRetryable(err) {
+		return "transient"
+	}
+	return "permanent"
+}
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"appdoki-be/app/metrics"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInstrument(t *testing.T) {
+	newInstrumented := func(next Sender) (*instrumentedSender, *metrics.Fake) {
+		m := metrics.NewFake()
+		s := Instrument(next, "fcm", m).(*instrumentedSender)
+		now := time.Now()
+		s.now = func() time.Time {
+			now = now.Add(250 * time.Millisecond)
+			return now
+		}
+		return s, m
+	}
+
+	t.Run("expect the sends to be counted and their latency observed", func(t *testing.T) {
+		s, m := newInstrumented(&flakySender{})
+
+		if err := s.SendToTopic(context.Background(), "beers", Notification{Title: "Cheers!"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if count := m.Counter(sentMetric, metrics.Labels{"channel": "fcm", "op": OpSendToTopic}); count != 1 {
+			t.Fatalf("expected 1 send, got %v", count)
+		}
+		observations := m.Observations(sendDurationMetric, metrics.Labels{"channel": "fcm", "op": OpSendToTopic, "outcome": "sent"})
+		if len(observations) != 1 || observations[0] != 0.25 {
+			t.Fatalf("expected the send to take 250ms, got %v", observations)
+		}
+	})
+
+	t.Run("expect the failed sends to be counted by error class", func(t *testing.T) {
+		for class, err := range map[string]error{
+			"transient": Retryable(errors.New("unavailable")),
+			"permanent": errors.New("invalid argument"),
+		} {
+			s, m := newInstrumented(&flakySender{failures: 1, err: err})
+
+			if s.SendToUser(context.Background(), "1", Notification{Title: "Cheers!"}) != err {
+				t.Fatal("expected the error to be returned as is")
+			}
+
+			if count := m.Counter(sendErrorsMetric, metrics.Labels{"channel": "fcm", "op": OpSendToUser, "class": class}); count != 1 {
+				t.Fatalf("expected 1 %s failure, got %v", class, count)
+			}
+			if count := m.Counter(sentMetric, metrics.Labels{"channel": "fcm", "op": OpSendToUser}); count != 0 {
+				t.Fatalf("expected the failure not to be counted sent, got %v", count)
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+)
+
+// The operations of the Sender, the Op of the jobs and failures
+const (
+	OpSendToUser           = "SendToUser"
+	OpSendToTopic          = "SendToTopic"
+	OpSendMulticast        = "SendMulticast"
+	OpSubscribeToTopic     = "SubscribeToTopic"
+	OpUnsubscribeFromTopic = "UnsubscribeFromTopic"
+)
+
+// Job is a call to a Sender, serialized in JSON to be replayed once given up on. Its notification
+// is the one sent, its title and body rendered already, along with its entry in the history. The
+// notifications left in the queue aren't rendered yet, so the payload of their event is kept too,
+// decoded from JSON as a map when replayed.
+type Job struct {
+	Op           string        `json:"op"`
+	UserID       string        `json:"user_id,omitempty"`
+	Topic        string        `json:"topic,omitempty"`
+	Tokens       []string      `json:"tokens,omitempty"`
+	Notification *Notification `json:"notification,omitempty"`
+	HistoryID    int64         `json:"history_id,omitempty"`
+	Payload      interface{}   `json:"payload,omitempty"`
+}
+
+func sendToUserJob(userID string, n Notification) Job {
+	return Job{Op: OpSendToUser, UserID: userID, Notification: &n, HistoryID: n.historyID, Payload: n.Payload}
+}
+
+func sendToTopicJob(topic string, n Notification) Job {
+	return Job{Op: OpSendToTopic, Topic: topic, Notification: &n, HistoryID: n.historyID, Payload: n.Payload}
+}
+
+func sendMulticastJob(tokens []string, n Notification) Job {
+	return Job{Op: OpSendMulticast, Tokens: tokens, Notification: &n, HistoryID: n.historyID, Payload: n.Payload}
+}
+
+// failure returns the Failure of the job, without its attempts nor error
+func (j Job) failure() Failure {
+	f := Failure{Op: j.Op, Target: j.Topic, Job: j}
+	switch j.Op {
+	case OpSendToUser:
+		f.Target = j.UserID
+	case OpSendMulticast:
+		f.Target = devicesSummary(j.Tokens)
+	}
+	if j.Notification != nil {
+		f.Summary = j.Notification.summary()
+	} else {
+		f.Summary = devicesSummary(j.Tokens)
+	}
+	return f
+}
+
+// Send calls the Sender with the job
+func (j Job) Send(ctx context.Context, s Sender) error {
+	var n Notification
+	if j.Notification != nil {
+		n = *j.Notification
+		n.historyID = j.HistoryID
+		if n.Payload == nil {
+			n.Payload = j.Payload
+		}
+	}
+
+	switch j.Op {
+	case OpSendToUser:
+		return s.SendToUser(ctx, j.UserID, n)
+	case OpSendToTopic:
+		return s.SendToTopic(ctx, j.Topic, n)
+	case OpSendMulticast:
+		return s.SendMulticast(ctx, j.Tokens, n)
+	case OpSubscribeToTopic:
+		return s.SubscribeToTopic(ctx, j.Tokens, j.Topic)
+	case OpUnsubscribeFromTopic:
+		return s.UnsubscribeFromTopic(ctx, j.Tokens, j.Topic)
+	}
+	return fmt.Errorf("unknown operation %q", j.Op)
+}
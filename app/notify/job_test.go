@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestJob(t *testing.T) {
+	t.Run("expect a send given up on to be replayed from its JSON, along with its delivery", func(t *testing.T) {
+		history := &fakeHistory{}
+		var failures []Failure
+		sender := &flakySender{failures: 1, err: Retryable(errors.New("unavailable"))}
+		retrying := WithRetry(TrackDelivery(sender, history), RetryPolicy{MaxAttempts: 1},
+			func(ctx context.Context, f func(ctx context.Context)) { f(ctx) },
+			func(_ context.Context, failure Failure) { failures = append(failures, failure) })
+		n := Notification{Title: "Cheers!", Data: map[string]string{"b": "2", "a": "1"}, Payload: struct{}{}}
+
+		WithHistory(retrying, history).SendToUser(context.Background(), "42", n)
+		if len(failures) != 1 || failures[0].Job.Op != OpSendToUser || failures[0].Job.HistoryID != 1 {
+			t.Fatalf("expected the send to user 42 to be given up on, got %+v", failures)
+		}
+
+		encoded, err := json.Marshal(failures[0].Job)
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, _ := json.Marshal(failures[0].Job)
+		if !bytes.Equal(encoded, again) {
+			t.Fatalf("expected the job to be serialized deterministically, got %s and %s", encoded, again)
+		}
+		var job Job
+		if err := json.Unmarshal(encoded, &job); err != nil {
+			t.Fatal(err)
+		}
+		if err := job.Send(context.Background(), TrackDelivery(sender, history)); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(history.recorded) != 1 || !reflect.DeepEqual(job.Notification.Data, n.Data) || job.UserID != "42" {
+			t.Fatalf("expected the notification to user 42 to be replayed, got %+v", job)
+		}
+		if statuses := history.statuses[1]; len(statuses) != 2 || statuses[1] != DeliverySent {
+			t.Fatalf("expected the replay to be tracked, got %v", statuses)
+		}
+	})
+
+	t.Run("expect the jobs of unknown operations to fail", func(t *testing.T) {
+		if err := (Job{Op: "Explode"}).Send(context.Background(), &flakySender{}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Links are the bases the deep links of the notifications are resolved against: the scheme of the
+// apps (ex.: appdoki://) and the URL of the web app, for the page browsers open on click
+type Links struct {
+	AppBase string
+	WebBase string
+}
+
+// absolute tells if the link has a scheme, rather than being a path to resolve
+func absolute(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && u.Scheme != ""
+}
+
+// linksNotifier resolves the deep links of the notifications
+type linksNotifier struct {
+	next  Notifier
+	links Links
+}
+
+// WithLinks returns a Notifier resolving the deep links of the notifications, paths to a screen of the
+// apps (ex.: beers/42), against the bases before sending them through next: the app one for the
+// deep_link data, the web one for the page browsers open on click unless the notification sets it.
+// The absolute links are sent as is, and browsers open the web app on click without a web base.
+func WithLinks(next Notifier, links Links) Notifier {
+	return &linksNotifier{next: next, links: links}
+}
+
+func (l *linksNotifier) SendToUser(ctx context.Context, userID string, n Notification) {
+	l.next.SendToUser(ctx, userID, l.resolve(n))
+}
+
+func (l *linksNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	l.next.SendToTopic(ctx, topic, l.resolve(n))
+}
+
+func (l *linksNotifier) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	l.next.SendMulticast(ctx, tokens, l.resolve(n))
+}
+
+func (l *linksNotifier) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	l.next.SubscribeToTopic(ctx, tokens, topic)
+}
+
+func (l *linksNotifier) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	l.next.UnsubscribeFromTopic(ctx, tokens, topic)
+}
+
+func (l *linksNotifier) resolve(n Notification) Notification {
+	if n.DeepLink == "" || absolute(n.DeepLink) {
+		return n
+	}
+
+	path := strings.TrimPrefix(n.DeepLink, "/")
+	if l.links.WebBase != "" && (n.Webpush == nil || n.Webpush.Link == "") {
+		options := WebpushOptions{}
+		if n.Webpush != nil {
+			options = *n.Webpush
+		}
+		options.Link = l.links.WebBase + path
+		n.Webpush = &options
+	}
+	n.DeepLink = l.links.AppBase + path
+	return n
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithLinks(t *testing.T) {
+	links := Links{AppBase: "appdoki://", WebBase: "https://appdoki.cloudoki.com/"}
+	sendThrough := func(links Links, n Notification) Notification {
+		fake := NewFake()
+		WithLinks(fake, links).SendToUser(context.Background(), "1", n)
+		return fake.Sent()[0].Notification
+	}
+
+	t.Run("expect a path to be resolved against the app and web bases", func(t *testing.T) {
+		n := sendThrough(links, Notification{Title: "Cheers!", DeepLink: "/beers/42"})
+
+		if n.DeepLink != "appdoki://beers/42" {
+			t.Fatalf("expected the app link, got %q", n.DeepLink)
+		}
+		if n.Webpush == nil || n.Webpush.Link != "https://appdoki.cloudoki.com/beers/42" {
+			t.Fatalf("expected the web link, got %+v", n.Webpush)
+		}
+	})
+
+	t.Run("expect the absolute links and the pages set to be kept", func(t *testing.T) {
+		n := sendThrough(links, Notification{
+			Title:    "Cheers!",
+			DeepLink: "beers/42",
+			Webpush:  &WebpushOptions{Link: "https://appdoki.cloudoki.com/home", Icon: "beer.png"},
+		})
+		if n.DeepLink != "appdoki://beers/42" || n.Webpush.Link != "https://appdoki.cloudoki.com/home" || n.Webpush.Icon != "beer.png" {
+			t.Fatalf("expected the page set to be kept, got %q and %+v", n.DeepLink, n.Webpush)
+		}
+
+		n = sendThrough(links, Notification{Title: "Cheers!", DeepLink: "https://github.com/Cloudoki/appdoki-be"})
+		if n.DeepLink != "https://github.com/Cloudoki/appdoki-be" || n.Webpush != nil {
+			t.Fatalf("expected the absolute link to be kept, got %q and %+v", n.DeepLink, n.Webpush)
+		}
+	})
+
+	t.Run("expect no web link without a web base", func(t *testing.T) {
+		n := sendThrough(Links{AppBase: "appdoki://"}, Notification{Title: "Cheers!", DeepLink: "beers"})
+
+		if n.DeepLink != "appdoki://beers" || n.Webpush != nil {
+			t.Fatalf("expected the app link alone, got %q and %+v", n.DeepLink, n.Webpush)
+		}
+	})
+}
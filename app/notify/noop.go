@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+)
+
+// Noop is a Sender only logging what it's sent, for local development without push credentials
+type Noop struct{}
+
+func (Noop) SendToUser(ctx context.Context, userID string, n Notification) error {
+	logging.FromContext(ctx).Infof("noop: not sending %s to user %s", n.summary(), userID)
+	return nil
+}
+
+func (Noop) SendToTopic(ctx context.Context, topic string, n Notification) error {
+	logging.FromContext(ctx).Infof("noop: not sending %s to topic %s", n.summary(), topic)
+	return nil
+}
+
+func (Noop) SendMulticast(ctx context.Context, tokens []string, n Notification) error {
+	logging.FromContext(ctx).Infof("noop: not sending %s to %s", n.summary(), devicesSummary(tokens))
+	return nil
+}
+
+func (Noop) SubscribeToTopic(ctx context.Context, tokens []string, topic string) error {
+	logging.FromContext(ctx).Debugf("noop: not subscribing %s to topic %s", devicesSummary(tokens), topic)
+	return nil
+}
+
+func (Noop) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) error {
+	logging.FromContext(ctx).Debugf("noop: not unsubscribing %s from topic %s", devicesSummary(tokens), topic)
+	return nil
+}
@@ -0,0 +1,157 @@
+// Package notify defines the push notifications sender of the apps and its implementations.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// deepLinkKey is the data key the deep link of a notification is sent in
+const deepLinkKey = "deep_link"
+
+// Notification is a push notification. Notifications without a title nor a body are sent as
+// data messages, handled by the apps without being displayed. Their JSON form is the one of the
+// dead letters, their title and body rendered already.
+type Notification struct {
+	Title string            `json:"title,omitempty"`
+	Body  string            `json:"body,omitempty"`
+	Data  map[string]string `json:"data,omitempty"`
+	// DeepLink is the screen of the apps opened by tapping the notification, a path resolved by
+	// WithLinks (ex.: beers/42) or an absolute link
+	DeepLink string `json:"deep_link,omitempty"`
+	// Event is the kind of event notified, which users can opt out of
+	Event string `json:"event,omitempty"`
+	// Silent notifications wake the apps up in the background without displaying anything, ex.: for
+	// them to refresh their cache. Their title and body aren't sent, nor kept in the history.
+	Silent bool `json:"silent,omitempty"`
+	// CollapseKey has the push services only keep the last of the pending notifications of a
+	// device with the same key
+	CollapseKey string `json:"collapse_key,omitempty"`
+	// Payload is the data of the event the title and body are rendered from, by WithTemplates
+	Payload interface{} `json:"-"`
+	// APNS, Android and Webpush customize the notification on each platform, the defaults of
+	// the push service applying when nil
+	APNS    *APNSOptions    `json:"apns,omitempty"`
+	Android *AndroidOptions `json:"android,omitempty"`
+	Webpush *WebpushOptions `json:"webpush,omitempty"`
+	// historyID is the notification in the history, set by WithHistory
+	historyID int64
+}
+
+// Link returns the link of the notification outside of the apps, ex.: in the emails: the page
+// browsers open on click, else the deep link
+func (n Notification) Link() string {
+	if n.Webpush != nil && n.Webpush.Link != "" {
+		return n.Webpush.Link
+	}
+	return n.DeepLink
+}
+
+// APNSOptions customize the notifications on iOS
+type APNSOptions struct {
+	// Badge is the count shown on the app icon, the unread notifications of the user when nil
+	Badge *int `json:"badge,omitempty"`
+	// Sound is played on delivery, the default sound when empty
+	Sound string `json:"sound,omitempty"`
+}
+
+// AndroidOptions customize the notifications on Android
+type AndroidOptions struct {
+	// ChannelID is the notification channel displaying the notification, the default channel of
+	// the push service when empty
+	ChannelID string `json:"channel_id,omitempty"`
+	Sound     string `json:"sound,omitempty"`
+	// Color is the color of the notification icon, in #rrggbb format
+	Color string `json:"color,omitempty"`
+}
+
+// WebpushOptions customize the notifications in browsers
+type WebpushOptions struct {
+	// Link is the page opened by clicking the notification
+	Link string `json:"link,omitempty"`
+	Icon string `json:"icon,omitempty"`
+}
+
+// Notifier is what the application sends the notifications through. Failures are handled
+// beneath it, by WithRetry, rather than returned.
+type Notifier interface {
+	// SendToUser sends the notification to every device the user registered
+	SendToUser(ctx context.Context, userID string, n Notification)
+	SendToTopic(ctx context.Context, topic string, n Notification)
+	// SendMulticast sends the notification to the devices with the tokens, in as few calls as the
+	// push service allows
+	SendMulticast(ctx context.Context, tokens []string, n Notification)
+	// SubscribeToTopic subscribes the devices with the tokens to the topic
+	SubscribeToTopic(ctx context.Context, tokens []string, topic string)
+	UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string)
+}
+
+// Sender is implemented by every push service, returning the error of a failed send, marked
+// Retryable when attempting it again may succeed. WithRetry turns a Sender into a Notifier.
+type Sender interface {
+	SendToUser(ctx context.Context, userID string, n Notification) error
+	SendToTopic(ctx context.Context, topic string, n Notification) error
+	SendMulticast(ctx context.Context, tokens []string, n Notification) error
+	SubscribeToTopic(ctx context.Context, tokens []string, topic string) error
+	UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) error
+}
+
+// Device is a device a user registered, by the registration token of the push service
+type Device struct {
+	Token    string
+	Platform string
+}
+
+// DeviceTokens resolves the devices of a user, forgetting the tokens the push service no longer knows
+type DeviceTokens interface {
+	UserDevices(ctx context.Context, userID string) ([]Device, error)
+	Forget(ctx context.Context, tokens []string) error
+}
+
+// Badges counts the unread notifications of a user, shown on the app icon
+type Badges interface {
+	Unread(ctx context.Context, userID string) (int, error)
+}
+
+// payload returns the data of the notification, its deep link included
+func (n Notification) payload() map[string]string {
+	if n.DeepLink == "" {
+		return n.Data
+	}
+
+	data := make(map[string]string, len(n.Data)+1)
+	for key, value := range n.Data {
+		data[key] = value
+	}
+	data[deepLinkKey] = n.DeepLink
+	return data
+}
+
+// summary describes the notification in the logs, without its data values which may be personal
+func (n Notification) summary() string {
+	keys := make([]string, 0, len(n.Data))
+	for key := range n.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	summary := fmt.Sprintf("%q", n.Title)
+	if n.Silent {
+		summary = "silent message"
+	} else if n.Title == "" && n.Body == "" {
+		summary = "data message"
+	}
+	if n.Event != "" {
+		summary += " (" + n.Event + ")"
+	}
+	if len(keys) > 0 {
+		summary += " with " + strings.Join(keys, ", ")
+	}
+	return summary
+}
+
+func devicesSummary(tokens []string) string {
+	return fmt.Sprintf("%d devices", len(tokens))
+}
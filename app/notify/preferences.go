@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+)
+
+// the events users can opt out of being notified of
+const (
+	EventBeerReceived = "beer_received"
+	EventNewUser      = "new_user"
+	EventDigest       = "digest"
+)
+
+// Preferences tells whether a user wants to be notified of an event
+type Preferences interface {
+	Wants(ctx context.Context, userID string, event string) (bool, error)
+}
+
+// SuppressedHandler is called with the event of every notification skipped by a preference
+type SuppressedHandler func(ctx context.Context, event string)
+
+// preferencesNotifier skips the notifications to the users who opted out of their event
+type preferencesNotifier struct {
+	next       Notifier
+	prefs      Preferences
+	suppressed SuppressedHandler
+}
+
+// WithPreferences returns a Notifier sending through next the notifications their recipient wants.
+// The notifications without an event, the silent ones, and the ones to topics or devices reach
+// everyone, and the preferences failing to load don't block the send.
+func WithPreferences(next Notifier, prefs Preferences, suppressed SuppressedHandler) Notifier {
+	return &preferencesNotifier{next: next, prefs: prefs, suppressed: suppressed}
+}
+
+func (p *preferencesNotifier) SendToUser(ctx context.Context, userID string, n Notification) {
+	if n.Event != "" && !n.Silent {
+		wants, err := p.prefs.Wants(ctx, userID, n.Event)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error loading the notification preferences of user %s, sending anyway: %v", userID, err)
+		} else if !wants {
+			logging.FromContext(ctx).Debugf("user %s opted out of %s notifications", userID, n.Event)
+			if p.suppressed != nil {
+				p.suppressed(ctx, n.Event)
+			}
+			return
+		}
+	}
+
+	p.next.SendToUser(ctx, userID, n)
+}
+
+func (p *preferencesNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	p.next.SendToTopic(ctx, topic, n)
+}
+
+func (p *preferencesNotifier) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	p.next.SendMulticast(ctx, tokens, n)
+}
+
+func (p *preferencesNotifier) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	p.next.SubscribeToTopic(ctx, tokens, topic)
+}
+
+func (p *preferencesNotifier) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	p.next.UnsubscribeFromTopic(ctx, tokens, topic)
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakePreferences map[string]bool
+
+func (p fakePreferences) Wants(_ context.Context, _ string, event string) (bool, error) {
+	if event == "broken" {
+		return false, errors.New("connection refused")
+	}
+	wants, ok := p[event]
+	return wants || !ok, nil
+}
+
+func TestWithPreferences(t *testing.T) {
+	send := func(prefs fakePreferences, n Notification) ([]Sent, []string) {
+		fake := NewFake()
+		var suppressed []string
+		notifier := WithPreferences(fake, prefs, func(_ context.Context, event string) {
+			suppressed = append(suppressed, event)
+		})
+		notifier.SendToUser(context.Background(), "42", n)
+		return fake.Sent(), suppressed
+	}
+
+	t.Run("expect the notifications of an event the user opted out of to be suppressed", func(t *testing.T) {
+		prefs := fakePreferences{EventBeerReceived: false, EventDigest: true}
+
+		sent, suppressed := send(prefs, Notification{Event: EventBeerReceived})
+		if len(sent) != 0 || len(suppressed) != 1 || suppressed[0] != EventBeerReceived {
+			t.Fatalf("expected the notification to be suppressed, got %+v and %v", sent, suppressed)
+		}
+
+		sent, suppressed = send(prefs, Notification{Event: EventDigest})
+		if len(sent) != 1 || len(suppressed) != 0 {
+			t.Fatalf("expected the digest to be sent, got %+v and %v", sent, suppressed)
+		}
+	})
+
+	t.Run("expect the notifications to be sent by default", func(t *testing.T) {
+		for _, n := range []Notification{{Event: EventNewUser}, {Title: "no event"}, {Event: "broken"}} {
+			if sent, _ := send(fakePreferences{}, n); len(sent) != 1 || sent[0].UserID != "42" {
+				t.Fatalf("expected %+v to be sent, got %+v", n, sent)
+			}
+		}
+	})
+
+	t.Run("expect the topic notifications to reach everyone", func(t *testing.T) {
+		fake := NewFake()
+		WithPreferences(fake, fakePreferences{EventBeerReceived: false}, nil).
+			SendToTopic(context.Background(), "beers", Notification{Event: EventBeerReceived})
+
+		if sent := fake.Sent(); len(sent) != 1 || sent[0].Topic != "beers" {
+			t.Fatalf("expected the topic notification to be sent, got %+v", sent)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	queueDepthMetric    = "notifications_queue_depth"
+	queueFullMetric     = "notifications_queue_full_total"
+	queueEnqueuedMetric = "notifications_enqueued_total"
+)
+
+// QueueConfig tells how many sends a Queue holds and how many workers process them.
+// When the queue is full, the sends are dropped unless SyncWhenFull, which makes the caller wait.
+type QueueConfig struct {
+	Size         int
+	Workers      int
+	SyncWhenFull bool
+}
+
+// errQueueClosed is the error of the sends left in the queue when it's closed
+var errQueueClosed = errors.New("the notifications queue was closed before the send")
+
+// job is a send waiting in the queue, with the detached context of its caller
+type job struct {
+	ctx  context.Context
+	call Job
+	send func(ctx context.Context)
+}
+
+// Queue is a Notifier handing the sends over to a pool of workers through a bounded queue,
+// so the callers never wait on the push service
+type Queue struct {
+	next       Notifier
+	conf       QueueConfig
+	metrics    metrics.Metrics
+	unfinished FailureHandler
+	jobs       chan job
+	mu         sync.RWMutex
+	closed     bool
+	wg         sync.WaitGroup
+	abandon    chan struct{}
+	abandoning sync.Once
+	left       int32
+}
+
+// NewQueue returns a Queue sending through next, its workers already started. The sends still
+// queued when Close gives up on draining it are handed to unfinished, interrupted, to be kept for
+// later.
+func NewQueue(next Notifier, conf QueueConfig, m metrics.Metrics, unfinished FailureHandler) *Queue {
+	if conf.Workers < 1 {
+		conf.Workers = 1
+	}
+	if conf.Size < 0 {
+		conf.Size = 0
+	}
+
+	q := &Queue{
+		next:       next,
+		conf:       conf,
+		metrics:    m,
+		unfinished: unfinished,
+		jobs:       make(chan job, conf.Size),
+		abandon:    make(chan struct{}),
+	}
+	q.wg.Add(conf.Workers)
+	for i := 0; i < conf.Workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+func (q *Queue) SendToUser(ctx context.Context, userID string, n Notification) {
+	q.enqueue(ctx, sendToUserJob(userID, n), func(ctx context.Context) {
+		q.next.SendToUser(ctx, userID, n)
+	})
+}
+
+func (q *Queue) SendToTopic(ctx context.Context, topic string, n Notification) {
+	q.enqueue(ctx, sendToTopicJob(topic, n), func(ctx context.Context) {
+		q.next.SendToTopic(ctx, topic, n)
+	})
+}
+
+func (q *Queue) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	q.enqueue(ctx, sendMulticastJob(tokens, n), func(ctx context.Context) {
+		q.next.SendMulticast(ctx, tokens, n)
+	})
+}
+
+func (q *Queue) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	q.enqueue(ctx, Job{Op: OpSubscribeToTopic, Topic: topic, Tokens: tokens}, func(ctx context.Context) {
+		q.next.SubscribeToTopic(ctx, tokens, topic)
+	})
+}
+
+func (q *Queue) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	q.enqueue(ctx, Job{Op: OpUnsubscribeFromTopic, Topic: topic, Tokens: tokens}, func(ctx context.Context) {
+		q.next.UnsubscribeFromTopic(ctx, tokens, topic)
+	})
+}
+
+// enqueue queues the send without blocking: when the queue is full it's dropped, or sent in
+// the caller's goroutine with SyncWhenFull, as it is once the queue is closed
+func (q *Queue) enqueue(ctx context.Context, call Job, send func(ctx context.Context)) {
+	op := call.Op
+	j := job{ctx: logging.Detach(ctx), call: call, send: send}
+
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		logging.FromContext(ctx).Warnf("notifications queue closed, calling %s synchronously", op)
+		q.run(j)
+		return
+	}
+
+	select {
+	case q.jobs <- j:
+		q.metrics.IncCounter(queueEnqueuedMetric, metrics.Labels{"op": op})
+		q.metrics.SetGauge(queueDepthMetric, float64(len(q.jobs)), nil)
+	default:
+		if q.conf.SyncWhenFull {
+			q.metrics.IncCounter(queueFullMetric, metrics.Labels{"op": op, "outcome": "sync"})
+			logging.FromContext(ctx).Warnf("notifications queue full, calling %s synchronously", op)
+			q.run(j)
+			return
+		}
+		q.metrics.IncCounter(queueFullMetric, metrics.Labels{"op": op, "outcome": "dropped"})
+		logging.FromContext(ctx).Warnf("notifications queue full, dropping %s", op)
+	}
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	for j := range q.jobs {
+		q.metrics.SetGauge(queueDepthMetric, float64(len(q.jobs)), nil)
+		select {
+		case <-q.abandon:
+			q.leave(j)
+		default:
+			q.run(j)
+		}
+	}
+}
+
+// leave hands the send over to the unfinished handler instead of sending it
+func (q *Queue) leave(j job) {
+	atomic.AddInt32(&q.left, 1)
+	logging.FromContext(j.ctx).Warnf("notifications queue closed, leaving %s for later", j.call.Op)
+	if q.unfinished == nil {
+		return
+	}
+
+	failure := j.call.failure()
+	failure.Err = Retryable(errQueueClosed)
+	failure.Interrupted = true
+	q.unfinished(j.ctx, failure)
+}
+
+// run sends the job, a panic only losing it
+func (q *Queue) run(j job) {
+	defer func() {
+		if err := recover(); err != nil {
+			logging.FromContext(j.ctx).Errorf("recovered from panic calling %s: %v", j.call.Op, err)
+		}
+	}()
+
+	j.send(j.ctx)
+}
+
+// Close stops queueing the sends, the next ones happening in their caller's goroutine, and waits
+// for the queued ones to be processed. If ctx expires first, the ones still queued are left to the
+// unfinished handler rather than sent, and ctx's error is returned.
+func (q *Queue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	// the workers only leave the sends they take from now on, the ones they're making finishing
+	q.abandoning.Do(func() { close(q.abandon) })
+	for j := range q.jobs {
+		q.leave(j)
+	}
+	return fmt.Errorf("%d notifications left in the queue: %w", atomic.LoadInt32(&q.left), ctx.Err())
+}
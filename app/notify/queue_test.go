@@ -0,0 +1,169 @@
+package notify
+
+import (
+	"appdoki-be/app/metrics"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// blockingNotifier holds the sends to the "slow" topic until released
+type blockingNotifier struct {
+	*Fake
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingNotifier() *blockingNotifier {
+	return &blockingNotifier{Fake: NewFake(), started: make(chan struct{}, 10), release: make(chan struct{})}
+}
+
+func (b *blockingNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	if topic == "slow" {
+		b.started <- struct{}{}
+		<-b.release
+	}
+	b.Fake.SendToTopic(ctx, topic, n)
+}
+
+func TestQueue(t *testing.T) {
+	t.Run("expect the sends to be processed in order", func(t *testing.T) {
+		fake := NewFake()
+		q := NewQueue(fake, QueueConfig{Size: 10, Workers: 1}, metrics.NewFake(), nil)
+
+		for i := 0; i < 5; i++ {
+			q.SendToTopic(context.Background(), fmt.Sprintf("topic-%d", i), Notification{})
+		}
+		if err := q.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		sent := fake.Sent()
+		if len(sent) != 5 {
+			t.Fatalf("expected 5 sends, got %d", len(sent))
+		}
+		for i, s := range sent {
+			if s.Topic != fmt.Sprintf("topic-%d", i) {
+				t.Fatalf("expected topic-%d, got %s", i, s.Topic)
+			}
+		}
+	})
+
+	t.Run("expect the sends to be dropped when the queue is full, without blocking", func(t *testing.T) {
+		next := newBlockingNotifier()
+		m := metrics.NewFake()
+		q := NewQueue(next, QueueConfig{Size: 1, Workers: 1}, m, nil)
+
+		q.SendToTopic(context.Background(), "slow", Notification{})
+		<-next.started
+		q.SendToTopic(context.Background(), "queued", Notification{})
+		q.SendToTopic(context.Background(), "dropped", Notification{})
+
+		if depth := m.Gauge(queueDepthMetric, nil); depth != 1 {
+			t.Fatalf("expected a depth of 1, got %v", depth)
+		}
+		close(next.release)
+		if err := q.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		if sent := next.Sent(); len(sent) != 2 || sent[0].Topic != "slow" || sent[1].Topic != "queued" {
+			t.Fatalf("expected the third send to be dropped, got %+v", sent)
+		}
+		if dropped := m.Counter(queueFullMetric, metrics.Labels{"op": "SendToTopic", "outcome": "dropped"}); dropped != 1 {
+			t.Fatalf("expected 1 dropped send, got %v", dropped)
+		}
+		if enqueued := m.Counter(queueEnqueuedMetric, metrics.Labels{"op": "SendToTopic"}); enqueued != 2 {
+			t.Fatalf("expected 2 enqueued sends, got %v", enqueued)
+		}
+	})
+
+	t.Run("expect the sends to happen synchronously when the queue is full if configured", func(t *testing.T) {
+		next := newBlockingNotifier()
+		m := metrics.NewFake()
+		q := NewQueue(next, QueueConfig{Size: 1, Workers: 1, SyncWhenFull: true}, m, nil)
+
+		q.SendToTopic(context.Background(), "slow", Notification{})
+		<-next.started
+		q.SendToTopic(context.Background(), "queued", Notification{})
+		q.SendToUser(context.Background(), "42", Notification{})
+
+		if sent := next.Sent(); len(sent) != 1 || sent[0].UserID != "42" {
+			t.Fatalf("expected the user send to happen right away, got %+v", sent)
+		}
+		if synced := m.Counter(queueFullMetric, metrics.Labels{"op": "SendToUser", "outcome": "sync"}); synced != 1 {
+			t.Fatalf("expected 1 synchronous send, got %v", synced)
+		}
+		close(next.release)
+		if err := q.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("expect the queue to be drained on close, within the deadline", func(t *testing.T) {
+		next := newBlockingNotifier()
+		q := NewQueue(next, QueueConfig{Size: 10, Workers: 2}, metrics.NewFake(), nil)
+
+		q.SendToTopic(context.Background(), "slow", Notification{})
+		for i := 0; i < 5; i++ {
+			q.SendToUser(context.Background(), fmt.Sprint(i), Notification{})
+		}
+		<-next.started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := q.Close(ctx); err == nil {
+			t.Fatal("expected the close to time out while a send is held")
+		}
+
+		close(next.release)
+		if err := q.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if sent := next.Sent(); len(sent) != 6 {
+			t.Fatalf("expected every queued send to be processed, got %d", len(sent))
+		}
+
+		q.SendToTopic(context.Background(), "late", Notification{})
+		if sent := next.Sent(); len(sent) != 7 || sent[6].Topic != "late" {
+			t.Fatalf("expected the sends after close to happen synchronously, got %+v", sent)
+		}
+	})
+
+	t.Run("expect the sends still queued past the deadline to be left to the unfinished handler", func(t *testing.T) {
+		next := newBlockingNotifier()
+		var left []Failure
+		q := NewQueue(next, QueueConfig{Size: 10, Workers: 1}, metrics.NewFake(), func(_ context.Context, failure Failure) {
+			left = append(left, failure)
+		})
+
+		q.SendToTopic(context.Background(), "slow", Notification{})
+		for i := 0; i < 3; i++ {
+			q.SendToUser(context.Background(), fmt.Sprint(i), Notification{Event: EventBeerReceived, Payload: i})
+		}
+		<-next.started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := q.Close(ctx); err == nil {
+			t.Fatal("expected the close to time out while a send is held")
+		}
+		close(next.release)
+
+		if len(left) != 3 {
+			t.Fatalf("expected the 3 queued sends to be left, got %+v", left)
+		}
+		for i, failure := range left {
+			if !failure.Interrupted || !failure.Exhausted() || failure.Job.UserID != fmt.Sprint(i) || failure.Job.Payload != i {
+				t.Fatalf("expected the send to user %d to be left for later, got %+v", i, failure)
+			}
+		}
+		if err := q.Close(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if sent := next.Sent(); len(sent) != 1 || sent[0].Topic != "slow" {
+			t.Fatalf("expected the held send alone to be made, got %+v", sent)
+		}
+	})
+}
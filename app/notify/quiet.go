@@ -0,0 +1,194 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	// the time zones of the users are loaded wherever the server runs, even without tzdata
+	_ "time/tzdata"
+)
+
+// QuietHours is the daily window, in the time zone of a user, their notifications are deferred
+// during. Start and End are minutes since midnight, the window crossing midnight when End is
+// before Start.
+type QuietHours struct {
+	Start    int
+	End      int
+	Location *time.Location
+}
+
+// ParseQuietHours parses quiet hours starting and ending at HH:MM clock times in the IANA time zone
+func ParseQuietHours(start string, end string, timezone string) (QuietHours, error) {
+	var q QuietHours
+	var err error
+	if q.Start, err = parseClock(start); err != nil {
+		return q, fmt.Errorf("start: %w", err)
+	}
+	if q.End, err = parseClock(end); err != nil {
+		return q, fmt.Errorf("end: %w", err)
+	}
+	if q.Start == q.End {
+		return q, errors.New("the window is empty")
+	}
+	if q.Location, err = time.LoadLocation(timezone); err != nil || timezone == "" || timezone == "Local" {
+		return q, fmt.Errorf("unknown time zone %q", timezone)
+	}
+	return q, nil
+}
+
+// parseClock returns the minutes since midnight of a HH:MM clock time
+func parseClock(clock string) (int, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a HH:MM time", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Until tells if now is within the quiet hours, and when they end. The windows are laid on the wall
+// clock of the time zone, so a window spanning a DST transition is an hour shorter or longer, and a
+// clock time skipped by the transition is taken as the one it normalizes to.
+func (q QuietHours) Until(now time.Time) (time.Time, bool) {
+	local := now.In(q.Location)
+	// the window starting yesterday may still be open, when crossing midnight
+	for _, days := range []int{0, -1} {
+		day := local.AddDate(0, 0, days)
+		start := q.at(day, q.Start)
+		end := q.at(day, q.End)
+		if q.End < q.Start {
+			end = q.at(day.AddDate(0, 0, 1), q.End)
+		}
+		if !now.Before(start) && now.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// at returns the time of the day at the minutes since midnight, on the wall clock of the time zone
+func (q QuietHours) at(day time.Time, minutes int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, minutes, 0, 0, q.Location)
+}
+
+// QuietHoursResolver resolves the quiet hours of the users, nil for the users without any
+type QuietHoursResolver interface {
+	QuietHours(ctx context.Context, userID string) (*QuietHours, error)
+}
+
+// DeferredStore keeps the sends deferred until a time
+type DeferredStore interface {
+	Defer(ctx context.Context, job Job, until time.Time) error
+	// TakeDue removes and returns the sends due at now, in the order they were deferred
+	TakeDue(ctx context.Context, now time.Time) ([]Job, error)
+}
+
+// DeferredHandler is called with the event of every notification deferred
+type DeferredHandler func(ctx context.Context, event string)
+
+// QuietHoursNotifier defers the notifications to the users in their quiet hours
+type QuietHoursNotifier struct {
+	next     Notifier
+	hours    QuietHoursResolver
+	store    DeferredStore
+	history  History
+	deferred DeferredHandler
+	now      func() time.Time
+}
+
+// WithQuietHours returns a Notifier sending through next the notifications to the users outside of
+// their quiet hours, the others being stored until the window ends and marked deferred in the
+// history recorded by WithHistory. Flush sends them once due. The silent notifications and the
+// ones to topics or devices aren't deferred, and the quiet hours or the store failing don't block
+// the send.
+func WithQuietHours(next Notifier, hours QuietHoursResolver, store DeferredStore, history History, deferred DeferredHandler) *QuietHoursNotifier {
+	return &QuietHoursNotifier{next: next, hours: hours, store: store, history: history, deferred: deferred, now: time.Now}
+}
+
+func (q *QuietHoursNotifier) SendToUser(ctx context.Context, userID string, n Notification) {
+	if n.Silent {
+		q.next.SendToUser(ctx, userID, n)
+		return
+	}
+
+	hours, err := q.hours.QuietHours(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error loading the quiet hours of user %s, sending anyway: %v", userID, err)
+	}
+	if hours == nil {
+		q.next.SendToUser(ctx, userID, n)
+		return
+	}
+	until, quiet := hours.Until(q.now())
+	if !quiet {
+		q.next.SendToUser(ctx, userID, n)
+		return
+	}
+
+	if err := q.store.Defer(ctx, sendToUserJob(userID, n), until); err != nil {
+		logging.FromContext(ctx).Errorf("error deferring a notification to user %s, sending it: %v", userID, err)
+		q.next.SendToUser(ctx, userID, n)
+		return
+	}
+	logging.FromContext(ctx).Debugf("user %s is in their quiet hours, deferring %s until %s", userID, n.summary(), until.Format(time.RFC3339))
+	q.setStatus(ctx, n.historyID, DeliveryDeferred)
+	if q.deferred != nil {
+		q.deferred(ctx, n.Event)
+	}
+}
+
+// Flush sends the notifications deferred until now. The ones to a user sharing a collapse key would
+// replace each other on the devices, so only the last one is pushed, the others kept in the history
+// as suppressed. It returns how many notifications were due.
+func (q *QuietHoursNotifier) Flush(ctx context.Context) (int, error) {
+	jobs, err := q.store.TakeDue(ctx, q.now())
+	if err != nil {
+		return 0, err
+	}
+
+	last := map[string]int{}
+	for i, job := range jobs {
+		if job.Notification != nil && job.Notification.CollapseKey != "" {
+			last[job.UserID+"/"+job.Notification.CollapseKey] = i
+		}
+	}
+	for i, job := range jobs {
+		if job.Op != OpSendToUser || job.Notification == nil {
+			continue
+		}
+		n := *job.Notification
+		n.historyID = job.HistoryID
+		if latest, ok := last[job.UserID+"/"+n.CollapseKey]; ok && latest != i {
+			q.setStatus(ctx, n.historyID, DeliverySuppressed)
+			continue
+		}
+		q.next.SendToUser(ctx, job.UserID, n)
+	}
+	return len(jobs), nil
+}
+
+func (q *QuietHoursNotifier) setStatus(ctx context.Context, historyID int64, status string) {
+	if historyID == 0 {
+		return
+	}
+	if err := q.history.SetStatus(ctx, historyID, status); err != nil {
+		logging.FromContext(ctx).Errorf("error setting the delivery status of notification %d: %v", historyID, err)
+	}
+}
+
+func (q *QuietHoursNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	q.next.SendToTopic(ctx, topic, n)
+}
+
+func (q *QuietHoursNotifier) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	q.next.SendMulticast(ctx, tokens, n)
+}
+
+func (q *QuietHoursNotifier) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	q.next.SubscribeToTopic(ctx, tokens, topic)
+}
+
+func (q *QuietHoursNotifier) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	q.next.UnsubscribeFromTopic(ctx, tokens, topic)
+}
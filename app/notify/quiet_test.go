@@ -0,0 +1,250 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// memoryDeferredStore keeps the deferred sends in memory
+type memoryDeferredStore struct {
+	deferred []deferredJob
+	err      error
+}
+
+type deferredJob struct {
+	job   Job
+	until time.Time
+}
+
+func (s *memoryDeferredStore) Defer(_ context.Context, job Job, until time.Time) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.deferred = append(s.deferred, deferredJob{job: job, until: until})
+	return nil
+}
+
+func (s *memoryDeferredStore) TakeDue(_ context.Context, now time.Time) ([]Job, error) {
+	var due []Job
+	var kept []deferredJob
+	for _, d := range s.deferred {
+		if d.until.After(now) {
+			kept = append(kept, d)
+		} else {
+			due = append(due, d.job)
+		}
+	}
+	s.deferred = kept
+	return due, nil
+}
+
+// fixedQuietHours gives every user the same quiet hours
+type fixedQuietHours struct {
+	hours *QuietHours
+}
+
+func (f fixedQuietHours) QuietHours(context.Context, string) (*QuietHours, error) {
+	return f.hours, nil
+}
+
+func mustQuietHours(t *testing.T, start string, end string, timezone string) QuietHours {
+	t.Helper()
+	q, err := ParseQuietHours(start, end, timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func TestParseQuietHours(t *testing.T) {
+	t.Run("expect invalid quiet hours to be rejected", func(t *testing.T) {
+		for _, tt := range [][3]string{
+			{"22:00", "22:00", "UTC"},
+			{"24:00", "07:00", "UTC"},
+			{"22:00", "7", "UTC"},
+			{"22:00", "07:00", "Mars/Olympus"},
+			{"22:00", "07:00", ""},
+			{"22:00", "07:00", "Local"},
+		} {
+			if _, err := ParseQuietHours(tt[0], tt[1], tt[2]); err == nil {
+				t.Fatalf("expected %v to be rejected", tt)
+			}
+		}
+	})
+}
+
+func TestQuietHours_Until(t *testing.T) {
+	t.Run("expect a window crossing midnight to span both days", func(t *testing.T) {
+		q := mustQuietHours(t, "22:00", "07:00", "UTC")
+
+		for _, tt := range []struct {
+			now   string
+			quiet bool
+			until string
+		}{
+			{"2026-06-10T21:59:00Z", false, ""},
+			{"2026-06-10T22:00:00Z", true, "2026-06-11T07:00:00Z"},
+			{"2026-06-10T23:30:00Z", true, "2026-06-11T07:00:00Z"},
+			{"2026-06-11T03:00:00Z", true, "2026-06-11T07:00:00Z"},
+			{"2026-06-11T07:00:00Z", false, ""},
+			{"2026-06-11T12:00:00Z", false, ""},
+		} {
+			now, _ := time.Parse(time.RFC3339, tt.now)
+			until, quiet := q.Until(now)
+			if quiet != tt.quiet || (quiet && until.UTC().Format(time.RFC3339) != tt.until) {
+				t.Fatalf("at %s expected %v until %s, got %v until %s", tt.now, tt.quiet, tt.until, quiet, until.UTC())
+			}
+		}
+	})
+
+	t.Run("expect a window within a day to be in the time zone of the user", func(t *testing.T) {
+		q := mustQuietHours(t, "13:00", "14:30", "America/New_York")
+
+		now, _ := time.Parse(time.RFC3339, "2026-01-15T18:15:00Z")
+		if until, quiet := q.Until(now); !quiet || !until.Equal(time.Date(2026, 1, 15, 19, 30, 0, 0, time.UTC)) {
+			t.Fatalf("expected quiet hours until 19:30Z, got %v until %s", quiet, until.UTC())
+		}
+		now, _ = time.Parse(time.RFC3339, "2026-01-15T13:15:00Z")
+		if _, quiet := q.Until(now); quiet {
+			t.Fatal("expected 13:15Z, 08:15 in New York, not to be quiet")
+		}
+	})
+
+	t.Run("expect the window to end on the wall clock when the clocks spring forward", func(t *testing.T) {
+		// Lisbon moves from WET (UTC) to WEST (UTC+1) at 01:00Z on 2026-03-29
+		q := mustQuietHours(t, "22:00", "07:00", "Europe/Lisbon")
+
+		now, _ := time.Parse(time.RFC3339, "2026-03-28T23:00:00Z")
+		until, quiet := q.Until(now)
+		if !quiet || !until.Equal(time.Date(2026, 3, 29, 6, 0, 0, 0, time.UTC)) {
+			t.Fatalf("expected quiet hours until 06:00Z, 07:00 WEST, got %v until %s", quiet, until.UTC())
+		}
+		now, _ = time.Parse(time.RFC3339, "2026-03-29T06:30:00Z")
+		if _, quiet := q.Until(now); quiet {
+			t.Fatal("expected 06:30Z, 07:30 WEST, not to be quiet")
+		}
+		now, _ = time.Parse(time.RFC3339, "2026-03-28T21:30:00Z")
+		if _, quiet := q.Until(now); quiet {
+			t.Fatal("expected 21:30Z, 21:30 WET, not to be quiet")
+		}
+	})
+
+	t.Run("expect the window to end on the wall clock when the clocks fall back", func(t *testing.T) {
+		// Lisbon moves from WEST (UTC+1) to WET (UTC) at 01:00Z on 2026-10-25
+		q := mustQuietHours(t, "22:00", "07:00", "Europe/Lisbon")
+
+		now, _ := time.Parse(time.RFC3339, "2026-10-24T21:00:00Z")
+		until, quiet := q.Until(now)
+		if !quiet || !until.Equal(time.Date(2026, 10, 25, 7, 0, 0, 0, time.UTC)) {
+			t.Fatalf("expected quiet hours until 07:00Z, 07:00 WET, got %v until %s", quiet, until.UTC())
+		}
+		now, _ = time.Parse(time.RFC3339, "2026-10-25T06:30:00Z")
+		if _, quiet := q.Until(now); !quiet {
+			t.Fatal("expected 06:30Z, 06:30 WET, to still be quiet")
+		}
+		now, _ = time.Parse(time.RFC3339, "2026-10-24T20:59:00Z")
+		if _, quiet := q.Until(now); quiet {
+			t.Fatal("expected 20:59Z, 21:59 WEST, not to be quiet")
+		}
+	})
+}
+
+func TestWithQuietHours(t *testing.T) {
+	night := time.Date(2026, 6, 10, 23, 0, 0, 0, time.UTC)
+	hours := mustQuietHours(t, "22:00", "07:00", "UTC")
+
+	t.Run("expect the notifications in the quiet hours to be deferred, and recorded right away", func(t *testing.T) {
+		fake := NewFake()
+		history := &fakeHistory{}
+		store := &memoryDeferredStore{}
+		var deferred []string
+		quiet := WithQuietHours(fake, fixedQuietHours{&hours}, store, history, func(_ context.Context, event string) {
+			deferred = append(deferred, event)
+		})
+		quiet.now = func() time.Time { return night }
+		n := WithHistory(quiet, history)
+
+		n.SendToUser(context.Background(), "42", Notification{Title: "Cheers!", Event: EventBeerReceived})
+		n.SendToUser(context.Background(), "42", Notification{Event: "user_updated", Silent: true})
+		n.SendToTopic(context.Background(), "beers", Notification{Title: "BeerTab event"})
+
+		if sent := fake.Sent(); len(sent) != 2 || !sent[0].Notification.Silent || sent[1].Topic != "beers" {
+			t.Fatalf("expected the silent and topic notifications alone to be pushed, got %+v", sent)
+		}
+		if len(history.recorded) != 1 || len(history.statuses[1]) != 1 || history.statuses[1][0] != DeliveryDeferred {
+			t.Fatalf("expected the notification to be recorded deferred, got %+v", history.statuses)
+		}
+		if len(store.deferred) != 1 || !store.deferred[0].until.Equal(time.Date(2026, 6, 11, 7, 0, 0, 0, time.UTC)) {
+			t.Fatalf("expected the notification to be deferred until 07:00, got %+v", store.deferred)
+		}
+		if len(deferred) != 1 || deferred[0] != EventBeerReceived {
+			t.Fatalf("expected the deferred notification to be reported, got %v", deferred)
+		}
+	})
+
+	t.Run("expect the users without quiet hours, or out of them, to be notified right away", func(t *testing.T) {
+		fake := NewFake()
+		store := &memoryDeferredStore{}
+		quiet := WithQuietHours(fake, fixedQuietHours{}, store, &fakeHistory{}, nil)
+		quiet.now = func() time.Time { return night }
+		quiet.SendToUser(context.Background(), "42", Notification{Title: "Cheers!"})
+
+		quiet = WithQuietHours(fake, fixedQuietHours{&hours}, store, &fakeHistory{}, nil)
+		quiet.now = func() time.Time { return night.Add(12 * time.Hour) }
+		quiet.SendToUser(context.Background(), "42", Notification{Title: "Cheers!"})
+
+		if sent := fake.Sent(); len(sent) != 2 || len(store.deferred) != 0 {
+			t.Fatalf("expected both notifications to be pushed, got %+v", sent)
+		}
+	})
+
+	t.Run("expect the store failing not to block the send", func(t *testing.T) {
+		fake := NewFake()
+		quiet := WithQuietHours(fake, fixedQuietHours{&hours}, &memoryDeferredStore{err: errors.New("unavailable")}, &fakeHistory{}, nil)
+		quiet.now = func() time.Time { return night }
+
+		quiet.SendToUser(context.Background(), "42", Notification{Title: "Cheers!"})
+		if sent := fake.Sent(); len(sent) != 1 {
+			t.Fatalf("expected the notification to be pushed, got %+v", sent)
+		}
+	})
+
+	t.Run("expect Flush to push the due notifications, the last of a collapse key alone", func(t *testing.T) {
+		fake := NewFake()
+		history := &fakeHistory{}
+		store := &memoryDeferredStore{}
+		quiet := WithQuietHours(fake, fixedQuietHours{&hours}, store, history, nil)
+		quiet.now = func() time.Time { return night }
+		n := WithHistory(quiet, history)
+
+		n.SendToUser(context.Background(), "42", Notification{Title: "1 beer", Event: EventBeerReceived, CollapseKey: EventBeerReceived})
+		n.SendToUser(context.Background(), "42", Notification{Title: "Welcome Ana", Event: EventNewUser})
+		n.SendToUser(context.Background(), "42", Notification{Title: "2 beers", Event: EventBeerReceived, CollapseKey: EventBeerReceived})
+		n.SendToUser(context.Background(), "7", Notification{Title: "1 beer", Event: EventBeerReceived, CollapseKey: EventBeerReceived})
+
+		if flushed, err := quiet.Flush(context.Background()); err != nil || flushed != 0 || len(fake.Sent()) != 0 {
+			t.Fatalf("expected nothing due in the quiet hours, got %d, %v", flushed, err)
+		}
+
+		quiet.now = func() time.Time { return night.Add(8 * time.Hour) }
+		flushed, err := quiet.Flush(context.Background())
+		if err != nil || flushed != 4 {
+			t.Fatalf("expected 4 notifications due, got %d, %v", flushed, err)
+		}
+		sent := fake.Sent()
+		if len(sent) != 3 || sent[0].Notification.Title != "Welcome Ana" || sent[1].Notification.Title != "2 beers" || sent[2].UserID != "7" {
+			t.Fatalf("expected the last beer_received of user 42 to replace the first, got %+v", sent)
+		}
+		if sent[1].Notification.historyID != 3 {
+			t.Fatalf("expected the history entry to be kept, got %d", sent[1].Notification.historyID)
+		}
+		if statuses := history.statuses[1]; len(statuses) != 2 || statuses[1] != DeliverySuppressed {
+			t.Fatalf("expected the replaced notification to be suppressed, got %v", statuses)
+		}
+		if len(store.deferred) != 0 {
+			t.Fatalf("expected the deferred notifications to be taken, got %+v", store.deferred)
+		}
+	})
+}
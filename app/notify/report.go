@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+	"sync"
+)
+
+type reportKey struct{}
+
+type dryRunKey struct{}
+
+// Report collects the outcome of the sends made with a context returned by WithReport, for them to
+// be inspected once done
+type Report struct {
+	mu      sync.Mutex
+	results []TokenResult
+	errs    []error
+}
+
+// WithReport returns a context collecting the outcome of the sends made with it in the report
+func WithReport(ctx context.Context) (context.Context, *Report) {
+	report := &Report{}
+	return context.WithValue(ctx, reportKey{}, report), report
+}
+
+// reportFrom returns the report of the context, nil when it has none
+func reportFrom(ctx context.Context) *Report {
+	report, _ := ctx.Value(reportKey{}).(*Report)
+	return report
+}
+
+// Results returns the outcome of the sends to devices, for each of their tokens
+func (r *Report) Results() []TokenResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]TokenResult(nil), r.results...)
+}
+
+// Errors returns the errors the sends failed with, and the ones rendering their copy
+func (r *Report) Errors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.errs...)
+}
+
+func (r *Report) addResults(results []TokenResult) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, results...)
+}
+
+func (r *Report) addError(err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+}
+
+// WithDryRun returns a context having the sends made with it only validated by the push service
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+func isDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// syncNotifier sends through a Sender in the caller's goroutine
+type syncNotifier struct {
+	next Sender
+}
+
+// Synchronous returns a Notifier sending through next in the caller's goroutine, without retrying.
+// The failures are logged and added to the report of the context.
+func Synchronous(next Sender) Notifier {
+	return &syncNotifier{next: next}
+}
+
+func (s *syncNotifier) SendToUser(ctx context.Context, userID string, n Notification) {
+	s.do(ctx, sendToUserJob(userID, n))
+}
+
+func (s *syncNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	s.do(ctx, sendToTopicJob(topic, n))
+}
+
+func (s *syncNotifier) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	s.do(ctx, sendMulticastJob(tokens, n))
+}
+
+func (s *syncNotifier) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	s.do(ctx, Job{Op: OpSubscribeToTopic, Topic: topic, Tokens: tokens})
+}
+
+func (s *syncNotifier) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	s.do(ctx, Job{Op: OpUnsubscribeFromTopic, Topic: topic, Tokens: tokens})
+}
+
+func (s *syncNotifier) do(ctx context.Context, job Job) {
+	if err := job.Send(ctx, s.next); err != nil {
+		logging.FromContext(ctx).Errorf("error in %s: %v", job.Op, err)
+		reportFrom(ctx).addError(err)
+	}
+}
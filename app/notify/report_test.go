@@ -0,0 +1,33 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSynchronous(t *testing.T) {
+	t.Run("expect the failures to be added to the report of the context", func(t *testing.T) {
+		sender := &flakySender{failures: 1, err: Retryable(errors.New("unavailable"))}
+		ctx, report := WithReport(context.Background())
+
+		Synchronous(sender).SendToTopic(ctx, "beers", Notification{Title: "Cheers"})
+
+		if sender.attempts != 1 {
+			t.Fatalf("expected a single attempt, got %d", sender.attempts)
+		}
+		if errs := report.Errors(); len(errs) != 1 || !IsRetryable(errs[0]) {
+			t.Fatalf("expected the failure to be reported, got %v", errs)
+		}
+	})
+
+	t.Run("expect the sends without a report not to fail", func(t *testing.T) {
+		sender := &flakySender{failures: 1, err: errors.New("invalid")}
+
+		Synchronous(sender).SendToUser(context.Background(), "42", Notification{Title: "Cheers"})
+
+		if sender.attempts != 1 {
+			t.Fatalf("expected a single attempt, got %d", sender.attempts)
+		}
+	})
+}
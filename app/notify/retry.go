@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy tells how many times a send is attempted, waiting between the attempts
+// a random delay up to BaseDelay, doubled at every attempt and capped by MaxDelay
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// backoff returns the delay before the attempt following the given one, with full jitter
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.MaxDelay
+	if shift := uint(attempt - 1); shift < 32 && p.BaseDelay<<shift > 0 && p.BaseDelay<<shift < ceiling {
+		ceiling = p.BaseDelay << shift
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// retryableError marks the errors of the sends which may succeed when attempted again
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// Retryable marks err as the error of a send which may succeed when attempted again
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{err: err}
+}
+
+// IsRetryable tells if err, or an error it wraps, was marked Retryable
+func IsRetryable(err error) bool {
+	var retryable *retryableError
+	return errors.As(err, &retryable)
+}
+
+// Failure describes a send given up on
+type Failure struct {
+	// Op is the Sender method called
+	Op string
+	// Target is the user, the topic or the number of devices of the send
+	Target string
+	// Summary describes the notification, for the logs
+	Summary  string
+	Attempts int
+	Err      error
+	// Job is the send, to be replayed later
+	Job Job
+	// Interrupted tells the send was left as the application stopped, rather than given up on
+	Interrupted bool
+}
+
+// Exhausted tells if the send was given up on with a retryable error, its attempts exhausted
+// or its context done, rather than with a permanent one
+func (f Failure) Exhausted() bool {
+	return IsRetryable(f.Err)
+}
+
+// FailureHandler is called with every send given up on
+type FailureHandler func(ctx context.Context, failure Failure)
+
+// BackgroundFunc runs f in the background with a context carrying the values of ctx,
+// cancelled when the application stops
+type BackgroundFunc func(ctx context.Context, f func(ctx context.Context))
+
+// retryNotifier attempts the sends of a Sender again while they fail with a retryable error
+type retryNotifier struct {
+	next       Sender
+	policy     RetryPolicy
+	background BackgroundFunc
+	onFailure  FailureHandler
+	sleep      func(ctx context.Context, d time.Duration) bool
+}
+
+// WithRetry returns a Notifier sending through next with the policy. The first attempt happens in
+// the caller's goroutine, the next ones in the background so the caller never waits on the backoff.
+func WithRetry(next Sender, policy RetryPolicy, background BackgroundFunc, onFailure FailureHandler) Notifier {
+	return &retryNotifier{
+		next:       next,
+		policy:     policy,
+		background: background,
+		onFailure:  onFailure,
+		sleep:      sleep,
+	}
+}
+
+func (r *retryNotifier) SendToUser(ctx context.Context, userID string, n Notification) {
+	r.do(ctx, Failure{Op: OpSendToUser, Target: userID, Summary: n.summary()}, sendToUserJob(userID, n))
+}
+
+func (r *retryNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	r.do(ctx, Failure{Op: OpSendToTopic, Target: topic, Summary: n.summary()}, sendToTopicJob(topic, n))
+}
+
+func (r *retryNotifier) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	r.do(ctx, Failure{Op: OpSendMulticast, Target: devicesSummary(tokens), Summary: n.summary()}, sendMulticastJob(tokens, n))
+}
+
+func (r *retryNotifier) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	r.do(ctx, Failure{Op: OpSubscribeToTopic, Target: topic, Summary: devicesSummary(tokens)}, Job{Op: OpSubscribeToTopic, Topic: topic, Tokens: tokens})
+}
+
+func (r *retryNotifier) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	r.do(ctx, Failure{Op: OpUnsubscribeFromTopic, Target: topic, Summary: devicesSummary(tokens)}, Job{Op: OpUnsubscribeFromTopic, Topic: topic, Tokens: tokens})
+}
+
+// do attempts the send, continuing in the background when it failed with a retryable error
+func (r *retryNotifier) do(ctx context.Context, failure Failure, job Job) {
+	failure.Job = job
+	send := func(ctx context.Context) error {
+		return job.Send(ctx, r.next)
+	}
+
+	err := send(ctx)
+	if err == nil {
+		return
+	}
+	if !IsRetryable(err) || r.policy.MaxAttempts <= 1 {
+		r.fail(ctx, failure, 1, err)
+		return
+	}
+
+	r.background(ctx, func(ctx context.Context) {
+		attempt := 1
+		for ; attempt < r.policy.MaxAttempts && IsRetryable(err); attempt++ {
+			if !r.sleep(ctx, r.policy.backoff(attempt)) {
+				failure.Interrupted = true
+				break
+			}
+			if err = send(ctx); err == nil {
+				return
+			}
+		}
+		r.fail(ctx, failure, attempt, err)
+	})
+}
+
+func (r *retryNotifier) fail(ctx context.Context, failure Failure, attempts int, err error) {
+	failure.Attempts = attempts
+	failure.Err = err
+	if r.onFailure != nil {
+		r.onFailure(ctx, failure)
+	}
+}
+
+// sleep waits for d, telling false when ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,126 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakySender fails its first sends with err, then succeeds
+type flakySender struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	attempts int
+}
+
+func (s *flakySender) attempt() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attempts++
+	if s.attempts <= s.failures {
+		return s.err
+	}
+	return nil
+}
+
+func (s *flakySender) SendToUser(context.Context, string, Notification) error  { return s.attempt() }
+func (s *flakySender) SendToTopic(context.Context, string, Notification) error { return s.attempt() }
+func (s *flakySender) SendMulticast(context.Context, []string, Notification) error {
+	return s.attempt()
+}
+func (s *flakySender) SubscribeToTopic(context.Context, []string, string) error {
+	return s.attempt()
+}
+func (s *flakySender) UnsubscribeFromTopic(context.Context, []string, string) error {
+	return s.attempt()
+}
+
+func TestWithRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	// runs the retries right away, recording the backoff
+	newRetryNotifier := func(sender Sender, failures *[]Failure, delays *[]time.Duration) *retryNotifier {
+		r := WithRetry(sender, policy, func(ctx context.Context, f func(ctx context.Context)) { f(ctx) },
+			func(_ context.Context, failure Failure) { *failures = append(*failures, failure) }).(*retryNotifier)
+		r.sleep = func(_ context.Context, d time.Duration) bool {
+			*delays = append(*delays, d)
+			return true
+		}
+		return r
+	}
+
+	t.Run("expect the retryable failures to be retried until the send succeeds", func(t *testing.T) {
+		sender := &flakySender{failures: 2, err: Retryable(errors.New("unavailable"))}
+		var failures []Failure
+		var delays []time.Duration
+
+		newRetryNotifier(sender, &failures, &delays).SendToTopic(context.Background(), "beers", Notification{Title: "Cheers"})
+
+		if sender.attempts != 3 || len(failures) != 0 {
+			t.Fatalf("expected 3 attempts and no failure, got %d and %+v", sender.attempts, failures)
+		}
+		if len(delays) != 2 || delays[0] > time.Second || delays[1] > 2*time.Second {
+			t.Fatalf("expected 2 jittered exponential delays, got %v", delays)
+		}
+	})
+
+	t.Run("expect the retries to be exhausted after the max attempts", func(t *testing.T) {
+		sender := &flakySender{failures: 10, err: Retryable(errors.New("unavailable"))}
+		var failures []Failure
+		var delays []time.Duration
+
+		newRetryNotifier(sender, &failures, &delays).SendToUser(context.Background(), "42", Notification{Title: "Cheers", Event: EventBeerReceived})
+
+		if sender.attempts != policy.MaxAttempts {
+			t.Fatalf("expected %d attempts, got %d", policy.MaxAttempts, sender.attempts)
+		}
+		if len(failures) != 1 || !failures[0].Exhausted() || failures[0].Attempts != policy.MaxAttempts {
+			t.Fatalf("expected an exhausted failure, got %+v", failures)
+		}
+		if failures[0].Op != "SendToUser" || failures[0].Target != "42" || failures[0].Summary != `"Cheers" (beer_received)` {
+			t.Fatalf("expected the send to be described, got %+v", failures[0])
+		}
+	})
+
+	t.Run("expect the permanent failures not to be retried", func(t *testing.T) {
+		sender := &flakySender{failures: 1, err: errors.New("invalid argument")}
+		var failures []Failure
+		var delays []time.Duration
+
+		newRetryNotifier(sender, &failures, &delays).SubscribeToTopic(context.Background(), []string{"token"}, "all-users")
+
+		if sender.attempts != 1 || len(failures) != 1 || failures[0].Exhausted() || failures[0].Attempts != 1 {
+			t.Fatalf("expected a single attempt failing for good, got %d and %+v", sender.attempts, failures)
+		}
+	})
+
+	t.Run("expect the retries to stop when the context is done", func(t *testing.T) {
+		sender := &flakySender{failures: 10, err: Retryable(errors.New("unavailable"))}
+		var failures []Failure
+		r := WithRetry(sender, policy, func(ctx context.Context, f func(ctx context.Context)) { f(ctx) },
+			func(_ context.Context, failure Failure) { failures = append(failures, failure) })
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		r.SendToTopic(ctx, "beers", Notification{})
+
+		if sender.attempts != 1 || len(failures) != 1 || !failures[0].Exhausted() || !failures[0].Interrupted {
+			t.Fatalf("expected to be interrupted after the first attempt, got %d and %+v", sender.attempts, failures)
+		}
+	})
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, ceiling := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 8: time.Second, 40: time.Second} {
+		for i := 0; i < 20; i++ {
+			if delay := policy.backoff(attempt); delay < 0 || delay > ceiling {
+				t.Fatalf("expected the delay after attempt %d to be up to %v, got %v", attempt, ceiling, delay)
+			}
+		}
+	}
+}
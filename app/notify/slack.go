@@ -0,0 +1,232 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/tracing"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// slackInterval is the least time between two posts to a webhook, Slack allowing one a second
+	slackInterval = time.Second
+	// maxSlackBlocks is the number of blocks Slack accepts in a message
+	maxSlackBlocks = 50
+	slackTimeout   = 10 * time.Second
+)
+
+// SlackConfig contains the incoming webhook the notifications to Topics are mirrored to
+type SlackConfig struct {
+	WebhookURL string
+	Topics     []string
+}
+
+// Slack mirrors the notifications sent to some topics to a channel, with an incoming webhook.
+// It posts at most once per slackInterval, the notifications sent meanwhile being coalesced
+// into the next post.
+type Slack struct {
+	webhookURL string
+	topics     map[string]bool
+	client     *http.Client
+	interval   time.Duration
+
+	mu sync.Mutex
+	// pending is the post waiting for its turn, which the notifications join
+	pending *slackPost
+	// next is when the next post may be made
+	next time.Time
+}
+
+// slackPost is a message to the webhook, shared by the notifications it coalesces
+type slackPost struct {
+	message slackMessage
+	done    chan struct{}
+	err     error
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackElement struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+	AltText  string `json:"alt_text,omitempty"`
+}
+
+// slackUser is a user in the data of a notification, ex.: the giver of beers
+type slackUser struct {
+	Name    string `json:"name"`
+	Picture string `json:"picture"`
+}
+
+func NewSlack(conf SlackConfig, clients *logging.HTTPClients) *Slack {
+	topics := make(map[string]bool, len(conf.Topics))
+	for _, topic := range conf.Topics {
+		topics[topic] = true
+	}
+
+	return &Slack{
+		webhookURL: conf.WebhookURL,
+		topics:     topics,
+		client:     clients.New(slackTimeout),
+		interval:   slackInterval,
+	}
+}
+
+// SendToUser is a no-op, the notifications to a user being personal
+func (s *Slack) SendToUser(context.Context, string, Notification) error {
+	return nil
+}
+
+// SendToTopic posts the notification to the channel when it mirrors the topic, waiting for the
+// turn of the post it joins. Every notification of a post fails with it.
+func (s *Slack) SendToTopic(ctx context.Context, topic string, n Notification) error {
+	if !s.topics[topic] {
+		return nil
+	}
+	blocks := slackBlocks(n)
+	if len(blocks) == 0 {
+		logging.FromContext(ctx).Debugf("not posting a data message of topic %s to slack", topic)
+		return nil
+	}
+
+	s.mu.Lock()
+	if post := s.pending; post != nil && len(post.message.Blocks)+len(blocks) <= maxSlackBlocks {
+		post.add(n, blocks)
+		s.mu.Unlock()
+		<-post.done
+		return post.err
+	}
+
+	post := &slackPost{done: make(chan struct{})}
+	post.add(n, blocks)
+	s.pending = post
+	now := time.Now()
+	if s.next.Before(now) {
+		s.next = now
+	}
+	wait := s.next.Sub(now)
+	s.next = s.next.Add(s.interval)
+	s.mu.Unlock()
+
+	// the post is made whatever happens to ctx, the notifications that joined it waiting on it
+	time.Sleep(wait)
+	s.mu.Lock()
+	if s.pending == post {
+		s.pending = nil
+	}
+	s.mu.Unlock()
+
+	post.err = s.post(ctx, post.message)
+	close(post.done)
+	return post.err
+}
+
+// SendMulticast is a no-op, the channel has no devices
+func (s *Slack) SendMulticast(context.Context, []string, Notification) error {
+	return nil
+}
+
+// SubscribeToTopic is a no-op, the channel has no devices
+func (s *Slack) SubscribeToTopic(context.Context, []string, string) error {
+	return nil
+}
+
+func (s *Slack) UnsubscribeFromTopic(context.Context, []string, string) error {
+	return nil
+}
+
+func (p *slackPost) add(n Notification, blocks []slackBlock) {
+	if len(p.message.Blocks) > 0 {
+		p.message.Text += "\n"
+	}
+	p.message.Text += strings.TrimSpace(n.Title + " " + n.Body)
+	p.message.Blocks = append(p.message.Blocks, blocks...)
+}
+
+func (s *Slack) post(ctx context.Context, message slackMessage) (err error) {
+	ctx, span := tracing.Start(ctx, "slack.Post",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("slack.blocks", len(message.Blocks))),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("encoding the slack message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building the slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Retryable(fmt.Errorf("posting to slack: %w", err))
+	}
+	defer resp.Body.Close()
+	reason, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return Retryable(fmt.Errorf("posting to slack: %d %s", resp.StatusCode, reason))
+	}
+	return fmt.Errorf("posting to slack: %d %s", resp.StatusCode, reason)
+}
+
+// slackBlocks formats the notification: its title and body, with the users of its data (ex.: the
+// giver and receiver of beers) by name and avatar. Data messages have nothing to post.
+func slackBlocks(n Notification) []slackBlock {
+	if n.Title == "" && n.Body == "" {
+		return nil
+	}
+
+	text := n.Body
+	if n.Title != "" {
+		text = "*" + n.Title + "*\n" + n.Body
+	}
+	blocks := []slackBlock{{Type: "section", Text: &slackText{Type: "mrkdwn", Text: strings.TrimSpace(text)}}}
+
+	var elements []slackElement
+	for _, key := range []string{"giver", "receiver", "user"} {
+		var user slackUser
+		if err := json.Unmarshal([]byte(n.Data[key]), &user); err != nil || user.Name == "" {
+			continue
+		}
+		if user.Picture != "" {
+			elements = append(elements, slackElement{Type: "image", ImageURL: user.Picture, AltText: user.Name})
+		}
+		elements = append(elements, slackElement{Type: "mrkdwn", Text: user.Name})
+	}
+	if len(elements) > 0 {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: elements})
+	}
+	return append(blocks, slackBlock{Type: "divider"})
+}
@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slackServer records the messages posted to the webhook, answering with status
+type slackServer struct {
+	mu       sync.Mutex
+	messages []slackMessage
+	times    []time.Time
+	status   int
+}
+
+func (s *slackServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var message slackMessage
+	json.NewDecoder(r.Body).Decode(&message)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, message)
+	s.times = append(s.times, time.Now())
+	if s.status != 0 {
+		w.WriteHeader(s.status)
+	}
+}
+
+func newTestSlack(t *testing.T, server *slackServer) *Slack {
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	return NewSlack(SlackConfig{WebhookURL: ts.URL, Topics: []string{"beers"}}, nil)
+}
+
+func TestSlack_SendToTopic(t *testing.T) {
+	beers := Notification{
+		Title: "BeerTab event",
+		Body:  "Ana just rewarded Rui with 2 beers!",
+		Data: map[string]string{
+			"giver":    `{"id":"1","name":"Ana","picture":"https://cdn/ana.png"}`,
+			"receiver": `{"id":"2","name":"Rui","picture":""}`,
+			"beers":    "2",
+		},
+	}
+
+	t.Run("expect the notification to be posted with its users by name and avatar", func(t *testing.T) {
+		server := &slackServer{}
+		s := newTestSlack(t, server)
+
+		if err := s.SendToTopic(context.Background(), "beers", beers); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(server.messages) != 1 {
+			t.Fatalf("expected 1 message, got %d", len(server.messages))
+		}
+		message := server.messages[0]
+		if message.Text != "BeerTab event Ana just rewarded Rui with 2 beers!" || len(message.Blocks) != 3 {
+			t.Fatalf("unexpected message %+v", message)
+		}
+		if section := message.Blocks[0]; section.Type != "section" || section.Text.Text != "*BeerTab event*\nAna just rewarded Rui with 2 beers!" {
+			t.Fatalf("unexpected section %+v", section)
+		}
+		elements := message.Blocks[1].Elements
+		if message.Blocks[1].Type != "context" || len(elements) != 3 {
+			t.Fatalf("expected the avatar and name of the giver and the name of the receiver, got %+v", elements)
+		}
+		if elements[0].Type != "image" || elements[0].ImageURL != "https://cdn/ana.png" || elements[1].Text != "Ana" || elements[2].Text != "Rui" {
+			t.Fatalf("unexpected elements %+v", elements)
+		}
+	})
+
+	t.Run("expect the other topics and the data messages not to be posted", func(t *testing.T) {
+		server := &slackServer{}
+		s := newTestSlack(t, server)
+
+		s.SendToTopic(context.Background(), "users", beers)
+		s.SendToTopic(context.Background(), "beers", Notification{Data: map[string]string{"beers": "2"}})
+		s.SendToUser(context.Background(), "2", beers)
+
+		if len(server.messages) != 0 {
+			t.Fatalf("expected no message, got %+v", server.messages)
+		}
+	})
+
+	t.Run("expect a post a second at most, the notifications sent meanwhile coalesced", func(t *testing.T) {
+		server := &slackServer{}
+		s := newTestSlack(t, server)
+		s.interval = 200 * time.Millisecond
+
+		s.SendToTopic(context.Background(), "beers", beers)
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := s.SendToTopic(context.Background(), "beers", beers); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(server.messages) != 2 {
+			t.Fatalf("expected 2 messages, got %d", len(server.messages))
+		}
+		if blocks := len(server.messages[1].Blocks); blocks != 9 {
+			t.Fatalf("expected the 3 notifications in the second message, got %d blocks", blocks)
+		}
+		if gap := server.times[1].Sub(server.times[0]); gap < s.interval {
+			t.Fatalf("expected the messages %v apart, got %v", s.interval, gap)
+		}
+	})
+
+	t.Run("expect the rate limited posts to be retryable but not the refused ones", func(t *testing.T) {
+		server := &slackServer{status: http.StatusTooManyRequests}
+		s := newTestSlack(t, server)
+
+		if err := s.SendToTopic(context.Background(), "beers", beers); !IsRetryable(err) {
+			t.Fatalf("expected a retryable error, got %v", err)
+		}
+
+		server.status = http.StatusNotFound
+		s.next = time.Time{}
+		if err := s.SendToTopic(context.Background(), "beers", beers); err == nil || IsRetryable(err) {
+			t.Fatalf("expected a permanent error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// streamBuffer is how many notifications a stream holds while its client is slow, the ones beyond
+// being dropped
+const streamBuffer = 16
+
+// StreamedNotification is a notification published to the live streams of its user, with its id
+// in the history when it's kept there
+type StreamedNotification struct {
+	ID int64 `json:"id,omitempty"`
+	Notification
+}
+
+// Streams fans the notifications out to the live streams of their users, in process: a user has a
+// stream per connected client
+type Streams struct {
+	mu   sync.Mutex
+	subs map[string]map[chan StreamedNotification]struct{}
+}
+
+// NewStreams returns streams without subscribers
+func NewStreams() *Streams {
+	return &Streams{subs: map[string]map[chan StreamedNotification]struct{}{}}
+}
+
+// Subscribe opens a stream of the notifications of the user, closed by calling unsubscribe
+func (s *Streams) Subscribe(userID string) (stream <-chan StreamedNotification, unsubscribe func()) {
+	ch := make(chan StreamedNotification, streamBuffer)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs[userID] == nil {
+		s.subs[userID] = map[chan StreamedNotification]struct{}{}
+	}
+	s.subs[userID][ch] = struct{}{}
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.subs[userID], ch)
+			if len(s.subs[userID]) == 0 {
+				delete(s.subs, userID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish sends the notification to every stream of the user, without waiting for the slow ones.
+// It tells how many streams got it.
+func (s *Streams) Publish(userID string, n StreamedNotification) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sent := 0
+	for ch := range s.subs[userID] {
+		select {
+		case ch <- n:
+			sent++
+		default:
+		}
+	}
+	return sent
+}
+
+// Subscribers returns how many streams the user has open
+func (s *Streams) Subscribers(userID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subs[userID])
+}
+
+// streamingNotifier publishes the notifications to users on their live streams
+type streamingNotifier struct {
+	next    Notifier
+	streams *Streams
+}
+
+// WithStreams returns a Notifier sending through next, publishing the notifications to users on
+// their live streams as well. The ones to topics or devices aren't published.
+func WithStreams(next Notifier, streams *Streams) Notifier {
+	return &streamingNotifier{next: next, streams: streams}
+}
+
+func (s *streamingNotifier) SendToUser(ctx context.Context, userID string, n Notification) {
+	s.streams.Publish(userID, StreamedNotification{ID: n.historyID, Notification: n})
+	s.next.SendToUser(ctx, userID, n)
+}
+
+func (s *streamingNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	s.next.SendToTopic(ctx, topic, n)
+}
+
+func (s *streamingNotifier) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	s.next.SendMulticast(ctx, tokens, n)
+}
+
+func (s *streamingNotifier) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	s.next.SubscribeToTopic(ctx, tokens, topic)
+}
+
+func (s *streamingNotifier) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	s.next.UnsubscribeFromTopic(ctx, tokens, topic)
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithStreams(t *testing.T) {
+	t.Run("expect the notifications to users to reach every stream of theirs", func(t *testing.T) {
+		fake := NewFake()
+		streams := NewStreams()
+		history := &fakeHistory{}
+		n := WithHistory(WithStreams(fake, streams), history)
+
+		first, unsubscribeFirst := streams.Subscribe("42")
+		defer unsubscribeFirst()
+		second, unsubscribeSecond := streams.Subscribe("42")
+		defer unsubscribeSecond()
+		other, unsubscribeOther := streams.Subscribe("7")
+		defer unsubscribeOther()
+
+		n.SendToUser(context.Background(), "42", Notification{Title: "Cheers!", Event: EventBeerReceived})
+		n.SendToTopic(context.Background(), "beers", Notification{Title: "Cheers!"})
+
+		for _, stream := range []<-chan StreamedNotification{first, second} {
+			select {
+			case got := <-stream:
+				if got.ID != 1 || got.Title != "Cheers!" {
+					t.Fatalf("expected the notification with its history id, got %+v", got)
+				}
+			default:
+				t.Fatal("expected the notification on every stream of the user")
+			}
+		}
+		if len(other) != 0 {
+			t.Fatal("expected no notification on the stream of another user")
+		}
+		if sent := fake.Sent(); len(sent) != 2 {
+			t.Fatalf("expected the notifications to be sent through, got %+v", sent)
+		}
+	})
+
+	t.Run("expect the streams to be removed once unsubscribed", func(t *testing.T) {
+		streams := NewStreams()
+		stream, unsubscribe := streams.Subscribe("42")
+
+		unsubscribe()
+		unsubscribe()
+		if _, open := <-stream; open {
+			t.Fatal("expected the stream to be closed")
+		}
+		if streams.Subscribers("42") != 0 || streams.Publish("42", StreamedNotification{}) != 0 {
+			t.Fatal("expected no stream left")
+		}
+	})
+
+	t.Run("expect slow streams not to block the send", func(t *testing.T) {
+		streams := NewStreams()
+		_, unsubscribe := streams.Subscribe("42")
+		defer unsubscribe()
+
+		n := WithStreams(NewFake(), streams)
+		for i := 0; i < streamBuffer+5; i++ {
+			n.SendToUser(context.Background(), "42", Notification{Title: "Cheers!"})
+		}
+		if streams.Publish("42", StreamedNotification{}) != 0 {
+			t.Fatal("expected the full stream to be skipped")
+		}
+	})
+}
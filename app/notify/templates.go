@@ -0,0 +1,261 @@
+package notify
+
+import (
+	"appdoki-be/app/i18n"
+	"appdoki-be/app/logging"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const pushTemplatesDir = "templates/push"
+
+//go:embed templates/push/*.tmpl templates/push/*/*.tmpl
+var pushTemplateFiles embed.FS
+
+// Templates renders the title and body of the notifications from the template of their event,
+// templates/push/<event>.tmpl defining a "title" and a "body" template executed with the payload,
+// and optionally a "link" one, the deep link of the notification. Those are in English,
+// templates/push/<locale>/<event>.tmpl being their variants in other locales.
+type Templates struct {
+	byLocale map[string]map[string]*template.Template
+}
+
+// LoadTemplates loads the embedded templates, overridden by the ones of dir when it's not empty
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{byLocale: map[string]map[string]*template.Template{i18n.Fallback: {}}}
+
+	embedded, err := fs.Sub(pushTemplateFiles, pushTemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.load(embedded); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := t.load(os.DirFS(dir)); err != nil {
+			return nil, fmt.Errorf("loading the templates of %s: %w", dir, err)
+		}
+	}
+	return t, nil
+}
+
+func (t *Templates) load(files fs.FS) error {
+	names, err := fs.Glob(files, "*.tmpl")
+	if err != nil {
+		return err
+	}
+	variants, err := fs.Glob(files, "*/*.tmpl")
+	if err != nil {
+		return err
+	}
+
+	for _, name := range append(names, variants...) {
+		locale, file := path.Split(name)
+		locale = strings.TrimSuffix(locale, "/")
+		if locale == "" {
+			locale = i18n.Fallback
+		}
+		tmpl, err := template.New(file).Option("missingkey=error").ParseFS(files, name)
+		if err != nil {
+			return err
+		}
+		for _, part := range []string{"title", "body"} {
+			if tmpl.Lookup(part) == nil {
+				return fmt.Errorf("template %s doesn't define a %q template", name, part)
+			}
+		}
+		if t.byLocale[locale] == nil {
+			t.byLocale[locale] = map[string]*template.Template{}
+		}
+		t.byLocale[locale][strings.TrimSuffix(file, path.Ext(file))] = tmpl
+	}
+	return nil
+}
+
+// Render returns the title and body of the notification of the event in the locale, in English
+// when the event has no variant in the locale
+func (t *Templates) Render(locale string, event string, payload interface{}) (title string, body string, err error) {
+	tmpl, ok := t.byLocale[locale][event]
+	if !ok {
+		tmpl, ok = t.byLocale[i18n.Fallback][event]
+	}
+	if !ok {
+		return "", "", fmt.Errorf("no template for event %q", event)
+	}
+
+	if title, err = execute(tmpl, "title", payload); err != nil {
+		return "", "", err
+	}
+	if body, err = execute(tmpl, "body", payload); err != nil {
+		return "", "", err
+	}
+	return title, body, nil
+}
+
+// Link returns the deep link of the notification of the event, rendered from the "link" template of
+// its English template, empty when it has none: the links don't vary with the locale
+func (t *Templates) Link(event string, payload interface{}) (string, error) {
+	tmpl, ok := t.byLocale[i18n.Fallback][event]
+	if !ok || tmpl.Lookup("link") == nil {
+		return "", nil
+	}
+	return execute(tmpl, "link", payload)
+}
+
+func execute(tmpl *template.Template, name string, payload interface{}) (string, error) {
+	var b bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&b, name, payload); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Validate renders every template with the sample payload of its event, in every locale, failing
+// on the templates of unknown events, on the events without an English template and on the
+// templates failing to execute
+func (t *Templates) Validate(samples map[string]interface{}) error {
+	var problems []string
+	for locale, byEvent := range t.byLocale {
+		for event := range byEvent {
+			if _, ok := samples[event]; !ok {
+				problems = append(problems, fmt.Sprintf("%s template of unknown event %q", locale, event))
+			}
+		}
+	}
+	for event, sample := range samples {
+		if _, ok := t.byLocale[i18n.Fallback][event]; !ok {
+			problems = append(problems, fmt.Sprintf("no template for event %q", event))
+			continue
+		}
+		for locale := range t.byLocale {
+			if _, _, err := t.Render(locale, event, sample); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", locale, err))
+			}
+		}
+		if _, err := t.Link(event, sample); err != nil {
+			problems = append(problems, fmt.Sprintf("link: %v", err))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid notification templates: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Locales resolves the locales the recipients of the notifications prefer, by user or by the
+// tokens of their devices. The recipients missing get the English copy.
+type Locales interface {
+	UserLocale(ctx context.Context, userID string) (string, error)
+	TokenLocales(ctx context.Context, tokens []string) (map[string]string, error)
+}
+
+// templatesNotifier renders the notifications carrying a payload before sending them
+type templatesNotifier struct {
+	next      Notifier
+	templates *Templates
+	locales   Locales
+}
+
+// WithTemplates returns a Notifier rendering the title and body of the notifications carrying a
+// Payload from the template of their event before sending them through next, in the locale of
+// their recipients when locales isn't nil and in English otherwise. Topics get the English copy.
+// The notifications failing to render are sent without a title nor a body, as data messages.
+func WithTemplates(next Notifier, templates *Templates, locales Locales) Notifier {
+	return &templatesNotifier{next: next, templates: templates, locales: locales}
+}
+
+func (t *templatesNotifier) SendToUser(ctx context.Context, userID string, n Notification) {
+	locale := i18n.Fallback
+	if rendered(n) && t.locales != nil {
+		userLocale, err := t.locales.UserLocale(ctx, userID)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error resolving the locale of user %s, rendering in English: %v", userID, err)
+		} else if userLocale != "" {
+			locale = userLocale
+		}
+	}
+	t.next.SendToUser(ctx, userID, t.render(ctx, locale, n))
+}
+
+func (t *templatesNotifier) SendToTopic(ctx context.Context, topic string, n Notification) {
+	t.next.SendToTopic(ctx, topic, t.render(ctx, i18n.Fallback, n))
+}
+
+// SendMulticast groups the devices by the locale of their users, sending each group its copy
+func (t *templatesNotifier) SendMulticast(ctx context.Context, tokens []string, n Notification) {
+	if !rendered(n) || t.locales == nil {
+		t.next.SendMulticast(ctx, tokens, t.render(ctx, i18n.Fallback, n))
+		return
+	}
+
+	byToken, err := t.locales.TokenLocales(ctx, tokens)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("error resolving the locales of %s, rendering in English: %v", devicesSummary(tokens), err)
+	}
+	groups := map[string][]string{}
+	for _, token := range tokens {
+		locale := byToken[token]
+		if locale == "" {
+			locale = i18n.Fallback
+		}
+		groups[locale] = append(groups[locale], token)
+	}
+
+	locales := make([]string, 0, len(groups))
+	for locale := range groups {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		t.next.SendMulticast(ctx, groups[locale], t.render(ctx, locale, n))
+	}
+}
+
+func (t *templatesNotifier) SubscribeToTopic(ctx context.Context, tokens []string, topic string) {
+	t.next.SubscribeToTopic(ctx, tokens, topic)
+}
+
+func (t *templatesNotifier) UnsubscribeFromTopic(ctx context.Context, tokens []string, topic string) {
+	t.next.UnsubscribeFromTopic(ctx, tokens, topic)
+}
+
+// rendered tells if the notification is rendered from a template: it carries a payload and no copy of its own
+func rendered(n Notification) bool {
+	return n.Payload != nil && n.Title == "" && n.Body == ""
+}
+
+// render sets the title and body of a notification rendered from a template, in the locale
+func (t *templatesNotifier) render(ctx context.Context, locale string, n Notification) Notification {
+	if !rendered(n) {
+		return n
+	}
+
+	title, body, err := t.templates.Render(locale, n.Event, n.Payload)
+	if err != nil {
+		// the templates are validated on startup, only a payload of the wrong shape gets here
+		logging.FromContext(ctx).Errorf("error rendering the %s notification, sending it as a data message: %v", n.Event, err)
+		reportFrom(ctx).addError(fmt.Errorf("rendering the %s notification: %w", n.Event, err))
+		return n
+	}
+	n.Title, n.Body = title, body
+
+	if n.DeepLink == "" {
+		link, err := t.templates.Link(n.Event, n.Payload)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error rendering the link of the %s notification, sending it without: %v", n.Event, err)
+			reportFrom(ctx).addError(fmt.Errorf("rendering the link of the %s notification: %w", n.Event, err))
+		}
+		n.DeepLink = link
+	}
+	return n
+}
@@ -0,0 +1,259 @@
+package notify
+
+import (
+	"appdoki-be/app/i18n"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type starPayload struct {
+	Actor   string
+	Subject string
+}
+
+// writeTemplate writes a template to an override directory
+func writeTemplate(t *testing.T, dir string, name string, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	t.Run("expect the templates of the directory to override the embedded ones", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "templates")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		writeTemplate(t, dir, "github_star.tmpl", `{{define "title"}}Starred{{end}}{{define "body"}} {{.Subject}} by {{.Actor}} {{end}}`)
+
+		templates, err := LoadTemplates(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		title, body, err := templates.Render(i18n.Fallback, "github_star", starPayload{Actor: "octocat", Subject: "appdoki-be"})
+		if err != nil || title != "Starred" || body != "appdoki-be by octocat" {
+			t.Fatalf("expected the overridden copy, got %q %q %v", title, body, err)
+		}
+		if _, _, err := templates.Render(i18n.Fallback, EventBeerReceived, map[string]interface{}{"Giver": "Alice", "Givers": 1, "Beers": 2}); err != nil {
+			t.Fatalf("expected the embedded templates to be kept, got %v", err)
+		}
+	})
+
+	t.Run("expect the variants of a locale to be picked, falling back to English", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "templates")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		if err := os.Mkdir(filepath.Join(dir, "fr"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeTemplate(t, dir, "fr/github_star.tmpl", `{{define "title"}}Nouvelle étoile{{end}}{{define "body"}}{{.Actor}}{{end}}`)
+
+		templates, err := LoadTemplates(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if title, _, _ := templates.Render("fr", "github_star", starPayload{Actor: "octocat"}); title != "Nouvelle étoile" {
+			t.Fatalf("expected the fr variant, got %q", title)
+		}
+		if title, _, _ := templates.Render("pt", "github_star", starPayload{Actor: "octocat"}); title != "Nova estrela ⭐" {
+			t.Fatalf("expected the embedded pt variant, got %q", title)
+		}
+		if title, _, _ := templates.Render("fr", "github_pull_request_merged", starPayload{Actor: "octocat"}); title != "Pull request merged 🍻" {
+			t.Fatalf("expected the English template without a fr variant, got %q", title)
+		}
+	})
+
+	t.Run("expect the templates without a title or a body to be rejected", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "templates")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		writeTemplate(t, dir, "github_star.tmpl", `{{define "title"}}Starred{{end}}`)
+
+		if _, err := LoadTemplates(dir); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestTemplates_Validate(t *testing.T) {
+	templates, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := func() map[string]interface{} {
+		return map[string]interface{}{
+			"beer_given":                 map[string]interface{}{"TransferID": 42, "Giver": "Alice", "Receiver": "Bob", "Beers": 2},
+			EventBeerReceived:            map[string]interface{}{"TransferID": 42, "Giver": "Alice", "Givers": 1, "Beers": 2},
+			"github_star":                starPayload{Actor: "octocat", Subject: "appdoki-be"},
+			"github_pull_request_merged": starPayload{Actor: "octocat", Subject: "Add webhooks"},
+			EventDigest:                  map[string]interface{}{"Period": "day", "Beers": 2, "Givers": 1},
+			"welcome":                    map[string]interface{}{"Name": "Alice"},
+		}
+	}
+
+	t.Run("expect the templates rendering their samples to be valid", func(t *testing.T) {
+		if err := templates.Validate(samples()); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("expect the templates of unknown events to be invalid", func(t *testing.T) {
+		s := samples()
+		delete(s, "github_star")
+
+		if err := templates.Validate(s); err == nil || !strings.Contains(err.Error(), `unknown event "github_star"`) {
+			t.Fatalf("expected the unknown event to be reported, got %v", err)
+		}
+	})
+
+	t.Run("expect the events without a template to be invalid", func(t *testing.T) {
+		s := samples()
+		s["new_badge"] = starPayload{}
+
+		if err := templates.Validate(s); err == nil || !strings.Contains(err.Error(), `no template for event "new_badge"`) {
+			t.Fatalf("expected the missing template to be reported, got %v", err)
+		}
+	})
+
+	t.Run("expect the templates failing to execute to be invalid", func(t *testing.T) {
+		s := samples()
+		s[EventBeerReceived] = map[string]interface{}{"Giver": "Alice", "Givers": 1}
+
+		if err := templates.Validate(s); err == nil || !strings.Contains(err.Error(), "Beers") {
+			t.Fatalf("expected the execution error to be reported, got %v", err)
+		}
+	})
+
+	t.Run("expect the link templates failing to execute to be invalid", func(t *testing.T) {
+		s := samples()
+		s["beer_given"] = map[string]interface{}{"Giver": "Alice", "Receiver": "Bob", "Beers": 2}
+
+		if err := templates.Validate(s); err == nil || !strings.Contains(err.Error(), "TransferID") {
+			t.Fatalf("expected the link error to be reported, got %v", err)
+		}
+	})
+}
+
+func TestTemplates_Link(t *testing.T) {
+	templates, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("expect the link of the event to be rendered", func(t *testing.T) {
+		link, err := templates.Link(EventBeerReceived, map[string]interface{}{"TransferID": 42, "Givers": 0})
+
+		if err != nil || link != "beers/42" {
+			t.Fatalf("expected beers/42, got %q, %v", link, err)
+		}
+	})
+
+	t.Run("expect no link for the events without a link template", func(t *testing.T) {
+		if link, err := templates.Link("github_star", starPayload{Actor: "octocat"}); err != nil || link != "" {
+			t.Fatalf("expected no link, got %q, %v", link, err)
+		}
+	})
+}
+
+// fakeLocales resolves the locales of the users and of their devices from maps
+type fakeLocales struct {
+	users  map[string]string
+	tokens map[string]string
+}
+
+func (l fakeLocales) UserLocale(_ context.Context, userID string) (string, error) {
+	return l.users[userID], nil
+}
+
+func (l fakeLocales) TokenLocales(_ context.Context, _ []string) (map[string]string, error) {
+	return l.tokens, nil
+}
+
+func TestWithTemplates(t *testing.T) {
+	templates, err := LoadTemplates("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := starPayload{Actor: "octocat", Subject: "appdoki-be"}
+
+	t.Run("expect the notifications carrying a payload to be rendered", func(t *testing.T) {
+		fake := NewFake()
+		notifier := WithTemplates(fake, templates, nil)
+
+		notifier.SendToTopic(context.Background(), "integrations", Notification{Event: "github_star", Payload: payload})
+		notifier.SendToUser(context.Background(), "42", Notification{Event: "github_star", Payload: payload})
+		notifier.SendMulticast(context.Background(), []string{"t1"}, Notification{Event: "github_star", Payload: payload})
+
+		for _, sent := range fake.Sent() {
+			if sent.Notification.Title != "New star ⭐" || sent.Notification.Body != "octocat starred appdoki-be" {
+				t.Fatalf("expected the notification to be rendered, got %+v", sent)
+			}
+		}
+	})
+
+	t.Run("expect the notifications with a copy of their own or failing to render to be sent as is", func(t *testing.T) {
+		fake := NewFake()
+		notifier := WithTemplates(fake, templates, nil)
+
+		notifier.SendToTopic(context.Background(), "integrations", Notification{Title: "Custom", Event: "github_star", Payload: payload})
+		notifier.SendToTopic(context.Background(), "integrations", Notification{Event: "github_star", Payload: struct{}{}})
+		notifier.SendToTopic(context.Background(), "integrations", Notification{Title: "No payload"})
+
+		sent := fake.Sent()
+		if len(sent) != 3 || sent[0].Notification.Title != "Custom" || sent[0].Notification.Body != "" {
+			t.Fatalf("expected the copy to be kept, got %+v", sent)
+		}
+		if sent[1].Notification.Title != "" || sent[2].Notification.Title != "No payload" {
+			t.Fatalf("expected the notifications to be sent as is, got %+v", sent)
+		}
+	})
+	t.Run("expect the notifications to be rendered in the locale of their recipients", func(t *testing.T) {
+		fake := NewFake()
+		notifier := WithTemplates(fake, templates, fakeLocales{
+			users:  map[string]string{"42": "pt"},
+			tokens: map[string]string{"t1": "pt", "t2": "en", "t3": "pt", "t4": "fr"},
+		})
+
+		notifier.SendToUser(context.Background(), "42", Notification{Event: "github_star", Payload: payload})
+		notifier.SendToUser(context.Background(), "7", Notification{Event: "github_star", Payload: payload})
+		notifier.SendToTopic(context.Background(), "integrations", Notification{Event: "github_star", Payload: payload})
+
+		sent := fake.Sent()
+		if sent[0].Notification.Title != "Nova estrela ⭐" || sent[1].Notification.Title != "New star ⭐" || sent[2].Notification.Title != "New star ⭐" {
+			t.Fatalf("expected the pt copy for user 42 alone, got %+v", sent)
+		}
+	})
+
+	t.Run("expect the multicasts to be grouped by locale, the unknown ones getting English", func(t *testing.T) {
+		fake := NewFake()
+		notifier := WithTemplates(fake, templates, fakeLocales{tokens: map[string]string{"t1": "pt", "t2": "en", "t3": "pt", "t4": "fr"}})
+
+		notifier.SendMulticast(context.Background(), []string{"t1", "t2", "t3", "t4", "t5"}, Notification{Event: "github_star", Payload: payload})
+
+		sent := fake.Sent()
+		if len(sent) != 3 {
+			t.Fatalf("expected a multicast per locale, got %+v", sent)
+		}
+		expected := []struct {
+			tokens string
+			title  string
+		}{{"t2 t5", "New star ⭐"}, {"t4", "New star ⭐"}, {"t1 t3", "Nova estrela ⭐"}}
+		for i, e := range expected {
+			if strings.Join(sent[i].Tokens, " ") != e.tokens || sent[i].Notification.Title != e.title {
+				t.Fatalf("expected %s to get %q, got %+v", e.tokens, e.title, sent[i])
+			}
+		}
+	})
+}
@@ -0,0 +1,261 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/tracing"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WebhookPayloadVersion is the version of the schema of the payloads posted to the webhook
+// endpoints, bumped on every breaking change
+const WebhookPayloadVersion = 1
+
+// the headers of the posts to the webhook endpoints. The signature is the HMAC-SHA256 of
+// "<timestamp>.<body>" with the secret of the endpoint, hex encoded after "sha256=".
+const (
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookIDHeader        = "X-Webhook-Id"
+	webhookEventHeader     = "X-Webhook-Event"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// WebhookPayload is the JSON posted to the endpoints subscribed to the event of a notification. ID
+// identifies the event, the same for every endpoint and redelivery.
+type WebhookPayload struct {
+	Version    int               `json:"version"`
+	ID         string            `json:"id"`
+	Event      string            `json:"event"`
+	OccurredAt time.Time         `json:"occurred_at"`
+	UserID     string            `json:"user_id,omitempty"`
+	Topic      string            `json:"topic,omitempty"`
+	Title      string            `json:"title,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	DeepLink   string            `json:"deep_link,omitempty"`
+	Link       string            `json:"link,omitempty"`
+	Data       map[string]string `json:"data,omitempty"`
+}
+
+// WebhookEndpoint is an endpoint the events it subscribed to are posted to
+type WebhookEndpoint struct {
+	ID     int64
+	URL    string
+	Secret string
+}
+
+// WebhookAttempt is a post of an event to an endpoint, along with its outcome
+type WebhookAttempt struct {
+	EndpointID int64
+	EventID    string
+	Event      string
+	Payload    []byte
+	Attempt    int
+	// StatusCode is the status the endpoint responded with, 0 when it couldn't be reached
+	StatusCode int
+	Err        error
+}
+
+// WebhookEndpoints resolves the endpoints subscribed to the events and keeps track of the
+// deliveries to them
+type WebhookEndpoints interface {
+	// Subscribed returns the enabled endpoints subscribed to the event
+	Subscribed(ctx context.Context, event string) ([]WebhookEndpoint, error)
+	// Attempted records a post to an endpoint
+	Attempted(ctx context.Context, attempt WebhookAttempt) error
+	// Delivered records the delivery of an event to the endpoint, succeeded or given up on
+	Delivered(ctx context.Context, endpointID int64, ok bool) error
+}
+
+// Webhooks posts the events of the notifications to the endpoints subscribed to them. Every
+// endpoint is delivered to in the background, the failed posts being attempted again with the
+// policy.
+type Webhooks struct {
+	endpoints  WebhookEndpoints
+	client     *http.Client
+	policy     RetryPolicy
+	background BackgroundFunc
+	now        func() time.Time
+	sleep      func(ctx context.Context, d time.Duration) bool
+}
+
+func NewWebhooks(policy RetryPolicy, endpoints WebhookEndpoints, background BackgroundFunc, clients *logging.HTTPClients) *Webhooks {
+	client := clients.New(webhookTimeout)
+	// the redirects are failures, not to turn the posts into gets
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Webhooks{
+		endpoints:  endpoints,
+		client:     client,
+		policy:     policy,
+		background: background,
+		now:        time.Now,
+		sleep:      sleep,
+	}
+}
+
+func (w *Webhooks) SendToUser(ctx context.Context, userID string, n Notification) error {
+	return w.publish(ctx, WebhookPayload{UserID: userID}, n)
+}
+
+func (w *Webhooks) SendToTopic(ctx context.Context, topic string, n Notification) error {
+	return w.publish(ctx, WebhookPayload{Topic: topic}, n)
+}
+
+func (w *Webhooks) SendMulticast(ctx context.Context, _ []string, n Notification) error {
+	return w.publish(ctx, WebhookPayload{}, n)
+}
+
+// SubscribeToTopic is a no-op, the endpoints subscribing to events rather than topics
+func (w *Webhooks) SubscribeToTopic(context.Context, []string, string) error {
+	return nil
+}
+
+// UnsubscribeFromTopic is a no-op, the endpoints subscribing to events rather than topics
+func (w *Webhooks) UnsubscribeFromTopic(context.Context, []string, string) error {
+	return nil
+}
+
+// publish queues the delivery of the event of the notification to every endpoint subscribed to it.
+// The notifications without an event have nothing to deliver.
+func (w *Webhooks) publish(ctx context.Context, payload WebhookPayload, n Notification) error {
+	if n.Event == "" {
+		return nil
+	}
+	endpoints, err := w.endpoints.Subscribed(ctx, n.Event)
+	if err != nil {
+		return Retryable(fmt.Errorf("resolving the webhook endpoints: %w", err))
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	payload.Version = WebhookPayloadVersion
+	payload.ID = uuid.New().String()
+	payload.Event = n.Event
+	payload.OccurredAt = w.now().UTC()
+	payload.Title = n.Title
+	payload.Body = n.Body
+	payload.DeepLink = n.DeepLink
+	if n.Webpush != nil {
+		payload.Link = n.Webpush.Link
+	}
+	payload.Data = n.Data
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encoding the webhook payload: %w", err)
+	}
+
+	for _, endpoint := range endpoints {
+		endpoint := endpoint
+		w.background(ctx, func(ctx context.Context) {
+			w.deliver(ctx, endpoint, payload.ID, n.Event, body)
+		})
+	}
+	return nil
+}
+
+// deliver posts the event to the endpoint, attempting it again while it fails with a retryable
+// error. The delivery left as the application stops is neither succeeded nor given up on.
+func (w *Webhooks) deliver(ctx context.Context, endpoint WebhookEndpoint, eventID string, event string, body []byte) {
+	for attempt := 1; ; attempt++ {
+		err := w.send(ctx, endpoint, eventID, event, body, attempt)
+		if err == nil || !IsRetryable(err) || attempt >= w.policy.MaxAttempts {
+			w.delivered(ctx, endpoint.ID, err == nil)
+			return
+		}
+		if !w.sleep(ctx, w.policy.backoff(attempt)) {
+			return
+		}
+	}
+}
+
+// Redeliver posts the event to the endpoint once more, ex.: once the endpoint is fixed, recording
+// the delivery
+func (w *Webhooks) Redeliver(ctx context.Context, endpoint WebhookEndpoint, eventID string, event string, body []byte, attempt int) error {
+	err := w.send(ctx, endpoint, eventID, event, body, attempt)
+	w.delivered(ctx, endpoint.ID, err == nil)
+	return err
+}
+
+func (w *Webhooks) delivered(ctx context.Context, endpointID int64, ok bool) {
+	if err := w.endpoints.Delivered(ctx, endpointID, ok); err != nil {
+		logging.FromContext(ctx).Errorf("error recording the delivery to webhook endpoint %d: %v", endpointID, err)
+	}
+}
+
+// send posts the event to the endpoint, signed with its secret, and records the attempt. It fails
+// with a Retryable error when the endpoint can't be reached, is rate limited or fails itself.
+func (w *Webhooks) send(ctx context.Context, endpoint WebhookEndpoint, eventID string, event string, body []byte, attempt int) error {
+	status, err := w.post(ctx, endpoint, eventID, event, body)
+	recorded := w.endpoints.Attempted(ctx, WebhookAttempt{
+		EndpointID: endpoint.ID,
+		EventID:    eventID,
+		Event:      event,
+		Payload:    body,
+		Attempt:    attempt,
+		StatusCode: status,
+		Err:        err,
+	})
+	if recorded != nil {
+		logging.FromContext(ctx).Errorf("error recording a post to webhook endpoint %d: %v", endpoint.ID, recorded)
+	}
+	return err
+}
+
+func (w *Webhooks) post(ctx context.Context, endpoint WebhookEndpoint, eventID string, event string, body []byte) (status int, err error) {
+	ctx, span := tracing.Start(ctx, "webhook.Post",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int64("webhook.endpoint", endpoint.ID), attribute.String("webhook.event", event)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("building the webhook request: %w", err)
+	}
+	timestamp := strconv.FormatInt(w.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook([]byte(endpoint.Secret), timestamp, body))
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookIDHeader, eventID)
+	req.Header.Set(webhookEventHeader, event)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, Retryable(fmt.Errorf("posting to the webhook: %w", err))
+	}
+	defer resp.Body.Close()
+	reason, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.StatusCode, nil
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return resp.StatusCode, Retryable(fmt.Errorf("posting to the webhook: %d %s", resp.StatusCode, reason))
+	}
+	return resp.StatusCode, fmt.Errorf("posting to the webhook: %d %s", resp.StatusCode, reason)
+}
+
+// signWebhook returns the hex encoded HMAC-SHA256 of "<timestamp>.<body>" with the secret
+func signWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,163 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeWebhookEndpoints subscribes its endpoints to every event, recording the attempts and deliveries
+type fakeWebhookEndpoints struct {
+	mu        sync.Mutex
+	endpoints []WebhookEndpoint
+	attempts  []WebhookAttempt
+	delivered map[int64][]bool
+}
+
+func (e *fakeWebhookEndpoints) Subscribed(context.Context, string) ([]WebhookEndpoint, error) {
+	return e.endpoints, nil
+}
+
+func (e *fakeWebhookEndpoints) Attempted(_ context.Context, attempt WebhookAttempt) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.attempts = append(e.attempts, attempt)
+	return nil
+}
+
+func (e *fakeWebhookEndpoints) Delivered(_ context.Context, endpointID int64, ok bool) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.delivered == nil {
+		e.delivered = map[int64][]bool{}
+	}
+	e.delivered[endpointID] = append(e.delivered[endpointID], ok)
+	return nil
+}
+
+// webhookServer records the posts, answering with the statuses in turn, then 200
+type webhookServer struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	statuses []int
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+	s.bodies = append(s.bodies, body)
+	if len(s.statuses) > 0 {
+		w.WriteHeader(s.statuses[0])
+		s.statuses = s.statuses[1:]
+	}
+}
+
+func newTestWebhooks(endpoints *fakeWebhookEndpoints) *Webhooks {
+	w := NewWebhooks(RetryPolicy{MaxAttempts: 3}, endpoints, func(ctx context.Context, f func(ctx context.Context)) {
+		f(ctx)
+	}, nil)
+	w.now = func() time.Time { return time.Unix(1700000000, 0) }
+	w.sleep = func(context.Context, time.Duration) bool { return true }
+	return w
+}
+
+func TestWebhooks_SendToTopic(t *testing.T) {
+	n := Notification{Title: "Cheers!", Body: "Ana gave Rui 2 beers", Event: "beer_given", Data: map[string]string{"beers": "2"}}
+
+	t.Run("expect the event to be posted to every endpoint, versioned and signed with its secret", func(t *testing.T) {
+		first, second := &webhookServer{}, &webhookServer{}
+		firstTS, secondTS := httptest.NewServer(first), httptest.NewServer(second)
+		defer firstTS.Close()
+		defer secondTS.Close()
+		endpoints := &fakeWebhookEndpoints{endpoints: []WebhookEndpoint{
+			{ID: 1, URL: firstTS.URL, Secret: "first-secret"},
+			{ID: 2, URL: secondTS.URL, Secret: "second-secret"},
+		}}
+
+		if err := newTestWebhooks(endpoints).SendToTopic(context.Background(), "beers", n); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(first.requests) != 1 || len(second.requests) != 1 {
+			t.Fatalf("expected a post to each endpoint, got %d and %d", len(first.requests), len(second.requests))
+		}
+		r, body := first.requests[0], first.bodies[0]
+		if r.Header.Get("X-Webhook-Signature") != "sha256="+signWebhook([]byte("first-secret"), "1700000000", body) {
+			t.Fatalf("expected the post to be signed with the secret of the endpoint, got %s", r.Header.Get("X-Webhook-Signature"))
+		}
+		if r.Header.Get("X-Webhook-Timestamp") != "1700000000" || r.Header.Get("X-Webhook-Event") != "beer_given" {
+			t.Fatalf("unexpected headers %v", r.Header)
+		}
+
+		var payload WebhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload.Version != WebhookPayloadVersion || payload.Event != "beer_given" || payload.Topic != "beers" ||
+			payload.Title != "Cheers!" || payload.Data["beers"] != "2" {
+			t.Fatalf("unexpected payload %+v", payload)
+		}
+		if payload.ID == "" || r.Header.Get("X-Webhook-Id") != payload.ID || second.requests[0].Header.Get("X-Webhook-Id") != payload.ID {
+			t.Fatal("expected the event to be identified the same for every endpoint")
+		}
+		if len(endpoints.delivered[1]) != 1 || !endpoints.delivered[1][0] {
+			t.Fatalf("expected the delivery to succeed, got %v", endpoints.delivered)
+		}
+	})
+
+	t.Run("expect the failed posts to be attempted again and recorded, up to the policy", func(t *testing.T) {
+		server := &webhookServer{statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable}}
+		ts := httptest.NewServer(server)
+		defer ts.Close()
+		endpoints := &fakeWebhookEndpoints{endpoints: []WebhookEndpoint{{ID: 1, URL: ts.URL, Secret: "secret"}}}
+
+		newTestWebhooks(endpoints).SendToTopic(context.Background(), "beers", n)
+
+		if len(endpoints.attempts) != 3 {
+			t.Fatalf("expected 3 attempts, got %d", len(endpoints.attempts))
+		}
+		for i, attempt := range endpoints.attempts {
+			if attempt.Attempt != i+1 || attempt.StatusCode != http.StatusServiceUnavailable || attempt.Err == nil {
+				t.Fatalf("unexpected attempt %+v", attempt)
+			}
+		}
+		if len(endpoints.delivered[1]) != 1 || endpoints.delivered[1][0] {
+			t.Fatalf("expected the delivery to be given up on, got %v", endpoints.delivered)
+		}
+	})
+
+	t.Run("expect a client error not to be attempted again", func(t *testing.T) {
+		server := &webhookServer{statuses: []int{http.StatusGone}}
+		ts := httptest.NewServer(server)
+		defer ts.Close()
+		endpoints := &fakeWebhookEndpoints{endpoints: []WebhookEndpoint{{ID: 1, URL: ts.URL, Secret: "secret"}}}
+
+		newTestWebhooks(endpoints).SendToTopic(context.Background(), "beers", n)
+
+		if len(endpoints.attempts) != 1 || endpoints.attempts[0].StatusCode != http.StatusGone {
+			t.Fatalf("expected a single attempt, got %+v", endpoints.attempts)
+		}
+	})
+
+	t.Run("expect the notifications without an event to be ignored", func(t *testing.T) {
+		server := &webhookServer{}
+		ts := httptest.NewServer(server)
+		defer ts.Close()
+		endpoints := &fakeWebhookEndpoints{endpoints: []WebhookEndpoint{{ID: 1, URL: ts.URL, Secret: "secret"}}}
+
+		newTestWebhooks(endpoints).SendToTopic(context.Background(), "users", Notification{Data: map[string]string{"user": "{}"}})
+
+		if len(server.requests) != 0 {
+			t.Fatalf("expected no post, got %d", len(server.requests))
+		}
+	})
+}
@@ -0,0 +1,464 @@
+package notify
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/tracing"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// webPushTokenPrefix marks the tokens of the devices subscribed with Web Push, derived from their endpoint
+	webPushTokenPrefix = "webpush:"
+	// webPushRecordSize is the size of the single record the messages are encrypted in, the most the
+	// push services accept
+	webPushRecordSize = 4096
+	// webPushHeaderSize is the size of the header of the encrypted messages: salt, record size and key
+	webPushHeaderSize = 16 + 4 + 1 + 65
+	// webPushTTL is how long the push services keep the messages for the browsers offline
+	webPushTTL     = 24 * time.Hour
+	webPushTimeout = 10 * time.Second
+	// vapidExpiry is how long the VAPID tokens are valid, the push services refusing more than a day
+	vapidExpiry = 12 * time.Hour
+)
+
+// webPushTopicPattern matches the Topic header values the push services accept
+var webPushTopicPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// WebPushSubscription is the PushSubscription of a browser: the endpoint of its push service and the
+// keys its messages are encrypted with, base64url encoded
+type WebPushSubscription struct {
+	Token    string
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Validate checks the endpoint is an https URL and the keys are a P-256 public key and a 16 bytes secret
+func (s WebPushSubscription) Validate() error {
+	if u, err := url.Parse(s.Endpoint); err != nil || u.Scheme != "https" || u.Host == "" {
+		return errors.New("the endpoint must be an https URL")
+	}
+	if key, err := decodeBase64URL(s.P256dh); err != nil || len(key) != 65 {
+		return errors.New("the p256dh key must be a base64url encoded P-256 public key")
+	}
+	if auth, err := decodeBase64URL(s.Auth); err != nil || len(auth) != 16 {
+		return errors.New("the auth secret must be 16 bytes, base64url encoded")
+	}
+	return nil
+}
+
+// WebPushToken returns the token of the device subscribed with Web Push to the endpoint
+func WebPushToken(endpoint string) string {
+	sum := sha256.Sum256([]byte(endpoint))
+	return webPushTokenPrefix + hex.EncodeToString(sum[:])
+}
+
+// IsWebPushToken tells if the token is the one of a device subscribed with Web Push, rather than FCM
+func IsWebPushToken(token string) bool {
+	return strings.HasPrefix(token, webPushTokenPrefix)
+}
+
+// Subscriptions resolves the Web Push subscriptions of the users and devices
+type Subscriptions interface {
+	UserSubscriptions(ctx context.Context, userID string) ([]WebPushSubscription, error)
+	TokenSubscriptions(ctx context.Context, tokens []string) ([]WebPushSubscription, error)
+	// Forget removes the subscriptions the push services no longer know
+	Forget(ctx context.Context, tokens []string) error
+}
+
+// WebPushConfig contains the VAPID key pair identifying the application to the push services,
+// base64url encoded, and the Subject they can contact its operators at, a mailto: or https: URL.
+// The messages are only encrypted in DryRun mode.
+type WebPushConfig struct {
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	Subject         string
+	DryRun          bool
+}
+
+// WebPush sends the notifications to the browsers subscribed with the Web Push protocol, their
+// messages encrypted per RFC 8291 and the application identified per RFC 8292 (VAPID)
+type WebPush struct {
+	key           *ecdsa.PrivateKey
+	publicKey     string
+	subject       string
+	dryRun        bool
+	subscriptions Subscriptions
+	client        *http.Client
+	now           func() time.Time
+}
+
+// webPushMessage is what the service worker of the web app receives
+type webPushMessage struct {
+	Title string            `json:"title,omitempty"`
+	Body  string            `json:"body,omitempty"`
+	Data  map[string]string `json:"data,omitempty"`
+	Link  string            `json:"click_action,omitempty"`
+	Icon  string            `json:"icon,omitempty"`
+}
+
+func newWebPushMessage(n Notification) webPushMessage {
+	message := webPushMessage{Title: n.Title, Body: n.Body, Data: n.payload()}
+	if n.Webpush != nil {
+		message.Link, message.Icon = n.Webpush.Link, n.Webpush.Icon
+	}
+	return message
+}
+
+// webPushStatusError is the refusal of a push service
+type webPushStatusError struct {
+	status int
+	reason string
+}
+
+func (e *webPushStatusError) Error() string {
+	return fmt.Sprintf("push service answered %d %s", e.status, e.reason)
+}
+
+func NewWebPush(conf WebPushConfig, subscriptions Subscriptions, clients *logging.HTTPClients) (*WebPush, error) {
+	key, err := parseVAPIDKeys(conf.VAPIDPublicKey, conf.VAPIDPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebPush{
+		key:           key,
+		publicKey:     strings.TrimRight(conf.VAPIDPublicKey, "="),
+		subject:       conf.Subject,
+		dryRun:        conf.DryRun,
+		subscriptions: subscriptions,
+		client:        clients.New(webPushTimeout),
+		now:           time.Now,
+	}, nil
+}
+
+// parseVAPIDKeys returns the VAPID private key, checking the public key is its own
+func parseVAPIDKeys(public string, private string) (*ecdsa.PrivateKey, error) {
+	d, err := decodeBase64URL(private)
+	if err != nil || len(d) != 32 {
+		return nil, errors.New("the VAPID private key must be a base64url encoded P-256 key")
+	}
+
+	key, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, errors.New("the VAPID private key must be a base64url encoded P-256 key")
+	}
+	point := key.PublicKey().Bytes()
+	if base64.RawURLEncoding.EncodeToString(point) != strings.TrimRight(public, "=") {
+		return nil, errors.New("the VAPID public key isn't the one of the private key")
+	}
+
+	// the point is uncompressed, 0x04 followed by its coordinates
+	return &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(point[1:33]),
+			Y:     new(big.Int).SetBytes(point[33:]),
+		},
+		D: new(big.Int).SetBytes(d),
+	}, nil
+}
+
+// SendToUser pushes the notification to the browsers of the user subscribed with Web Push. Its
+// failure is only retryable when no browser was reached, so none gets the notification twice.
+func (w *WebPush) SendToUser(ctx context.Context, userID string, n Notification) error {
+	if n.Silent {
+		return nil
+	}
+	subscriptions, err := w.subscriptions.UserSubscriptions(ctx, userID)
+	if err != nil {
+		return Retryable(fmt.Errorf("resolving the web push subscriptions of the user: %w", err))
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	results := w.push(ctx, subscriptions, n)
+	logging.FromContext(ctx).Infof("pushed message to %d of %d browsers of user %s", delivered(results), len(subscriptions), userID)
+	if err := deliveryError(results, webPushErrorKind); err != nil {
+		return fmt.Errorf("pushing message to the browsers of the user: %w", err)
+	}
+	return nil
+}
+
+// SendToTopic is a no-op, the topics being FCM's
+func (w *WebPush) SendToTopic(context.Context, string, Notification) error {
+	return nil
+}
+
+// SendMulticast pushes the notification to the devices subscribed with Web Push among the tokens
+func (w *WebPush) SendMulticast(ctx context.Context, tokens []string, n Notification) error {
+	var webTokens []string
+	for _, token := range tokens {
+		if IsWebPushToken(token) {
+			webTokens = append(webTokens, token)
+		}
+	}
+	if len(webTokens) == 0 || n.Silent {
+		return nil
+	}
+
+	subscriptions, err := w.subscriptions.TokenSubscriptions(ctx, webTokens)
+	if err != nil {
+		return Retryable(fmt.Errorf("resolving the web push subscriptions of %s: %w", devicesSummary(webTokens), err))
+	}
+	results := w.push(ctx, subscriptions, n)
+	logging.FromContext(ctx).Infof("pushed message to %d of %d browsers", delivered(results), len(subscriptions))
+	if err := deliveryError(results, webPushErrorKind); err != nil {
+		return fmt.Errorf("pushing message to %s: %w", devicesSummary(webTokens), err)
+	}
+	return nil
+}
+
+// SubscribeToTopic is a no-op, the topics being FCM's
+func (w *WebPush) SubscribeToTopic(context.Context, []string, string) error {
+	return nil
+}
+
+func (w *WebPush) UnsubscribeFromTopic(context.Context, []string, string) error {
+	return nil
+}
+
+// push sends the notification to the subscriptions, forgetting the ones the push services no longer
+// know, and returns the outcome for each of them, in order, adding it to the report of the context
+func (w *WebPush) push(ctx context.Context, subscriptions []WebPushSubscription, n Notification) []TokenResult {
+	payload, err := json.Marshal(newWebPushMessage(n))
+
+	results := make([]TokenResult, len(subscriptions))
+	var stale []string
+	for i, subscription := range subscriptions {
+		results[i].Token = subscription.Token
+		if err != nil {
+			results[i].Err = fmt.Errorf("encoding the message: %w", err)
+			continue
+		}
+		results[i].Err = w.pushTo(ctx, subscription, payload, n)
+		if results[i].Err != nil && webPushErrorKind(results[i].Err) == staleTokenError {
+			results[i].Stale = true
+			stale = append(stale, subscription.Token)
+		}
+	}
+
+	if len(stale) > 0 {
+		logging.FromContext(ctx).Infof("forgetting %d browsers the push services no longer know", len(stale))
+		if err := w.subscriptions.Forget(ctx, stale); err != nil {
+			logging.FromContext(ctx).Errorf("error forgetting %d browsers: %v", len(stale), err)
+		}
+	}
+	reportFrom(ctx).addResults(results)
+	return results
+}
+
+// pushTo sends the encrypted payload to the push service of the subscription, which only validates
+// it in dry run mode
+func (w *WebPush) pushTo(ctx context.Context, subscription WebPushSubscription, payload []byte, n Notification) (err error) {
+	body, err := encryptWebPush(subscription, payload)
+	if err != nil {
+		return fmt.Errorf("encrypting the message: %w", err)
+	}
+	if w.dryRun || isDryRun(ctx) {
+		logging.FromContext(ctx).Debugf("dry run, not pushing message to %s", subscription.Token)
+		return nil
+	}
+	authorization, err := w.vapidAuthorization(subscription.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := tracing.Start(ctx, "webpush.Send", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() { tracing.End(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building the web push request: %w", err)
+	}
+	span.SetAttributes(attribute.String("messaging.destination", req.URL.Host))
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(webPushTTL.Seconds())))
+	req.Header.Set("Urgency", "high")
+	if n.CollapseKey != "" {
+		req.Header.Set("Topic", webPushTopic(n.CollapseKey))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return Retryable(fmt.Errorf("pushing to %s: %w", req.URL.Host, err))
+	}
+	defer resp.Body.Close()
+	reason, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+
+	statusErr := &webPushStatusError{status: resp.StatusCode, reason: strings.TrimSpace(string(reason))}
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return Retryable(statusErr)
+	}
+	return statusErr
+}
+
+// webPushErrorKind classifies the errors pushing a message: the push services answer 404 or 410 for
+// the subscriptions expired or cancelled
+func webPushErrorKind(err error) sendErrorKind {
+	var statusErr *webPushStatusError
+	switch {
+	case IsRetryable(err):
+		return transientError
+	case errors.As(err, &statusErr) && (statusErr.status == http.StatusNotFound || statusErr.status == http.StatusGone):
+		return staleTokenError
+	}
+	return permanentError
+}
+
+// webPushTopic returns the Topic header replacing the pending messages with the same collapse key,
+// hashed when it has characters or a length the push services don't accept
+func webPushTopic(collapseKey string) string {
+	if webPushTopicPattern.MatchString(collapseKey) {
+		return collapseKey
+	}
+	sum := sha256.Sum256([]byte(collapseKey))
+	return base64.RawURLEncoding.EncodeToString(sum[:])[:32]
+}
+
+// vapidAuthorization returns the Authorization header identifying the application to the push service
+// of the endpoint, a JWT signed with the VAPID key
+func (w *WebPush) vapidAuthorization(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("parsing the endpoint: %w", err)
+	}
+
+	claims := map[string]interface{}{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": w.now().Add(vapidExpiry).Unix(),
+	}
+	if w.subject != "" {
+		claims["sub"] = w.subject
+	}
+	encodedClaims, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("encoding the VAPID claims: %w", err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(encodedClaims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, w.key, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("signing the VAPID token: %w", err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	return fmt.Sprintf("vapid t=%s.%s, k=%s", unsigned, base64.RawURLEncoding.EncodeToString(signature), w.publicKey), nil
+}
+
+// encryptWebPush encrypts the payload for the subscription per RFC 8291, in a single aes128gcm record
+func encryptWebPush(subscription WebPushSubscription, payload []byte) ([]byte, error) {
+	uaPublic, err := decodeBase64URL(subscription.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("decoding the p256dh key: %w", err)
+	}
+	authSecret, err := decodeBase64URL(subscription.Auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, errors.New("the auth secret must be 16 bytes")
+	}
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, errors.New("the p256dh key isn't a P-256 public key")
+	}
+	if webPushHeaderSize+len(payload)+1+16 > webPushRecordSize {
+		return nil, fmt.Errorf("the message of %d bytes is too large", len(payload))
+	}
+
+	asKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return sealWebPush(uaKey, authSecret, asKey, salt, payload)
+}
+
+// sealWebPush encrypts the payload for the key and auth secret of the browser with the ephemeral key
+// and salt given
+func sealWebPush(uaKey *ecdh.PublicKey, authSecret []byte, asKey *ecdh.PrivateKey, salt []byte, payload []byte) ([]byte, error) {
+	// the shared secret of the ephemeral key pair and the key of the browser
+	ecdhSecret, err := asKey.ECDH(uaKey)
+	if err != nil {
+		return nil, err
+	}
+	uaPublic, asPublic := uaKey.Bytes(), asKey.PublicKey().Bytes()
+
+	keyInfo := append(append([]byte("WebPush: info\x00"), uaPublic...), asPublic...)
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ecdhSecret, authSecret, keyInfo), ikm); err != nil {
+		return nil, err
+	}
+
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek := make([]byte, 16)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: aes128gcm\x00")), cek); err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 12)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: nonce\x00")), nonce); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, webPushHeaderSize)
+	header = append(header, salt...)
+	header = append(header, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(header[16:20], webPushRecordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	// the last record is delimited by a 2
+	plaintext := append(append([]byte{}, payload...), 2)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// decodeBase64URL decodes base64url, padded or not as the browsers encode their keys either way
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
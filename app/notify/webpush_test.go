@@ -0,0 +1,334 @@
+package notify
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeSubscriptions struct {
+	subscriptions []WebPushSubscription
+	forgotten     []string
+}
+
+func (f *fakeSubscriptions) UserSubscriptions(context.Context, string) ([]WebPushSubscription, error) {
+	return f.subscriptions, nil
+}
+
+func (f *fakeSubscriptions) TokenSubscriptions(_ context.Context, tokens []string) ([]WebPushSubscription, error) {
+	var subscriptions []WebPushSubscription
+	for _, s := range f.subscriptions {
+		for _, token := range tokens {
+			if s.Token == token {
+				subscriptions = append(subscriptions, s)
+			}
+		}
+	}
+	return subscriptions, nil
+}
+
+func (f *fakeSubscriptions) Forget(_ context.Context, tokens []string) error {
+	f.forgotten = append(f.forgotten, tokens...)
+	return nil
+}
+
+// testBrowser is the key pair and auth secret of a browser, decrypting the messages pushed to it
+type testBrowser struct {
+	key  *ecdh.PrivateKey
+	auth []byte
+}
+
+func newTestBrowser(t *testing.T) *testBrowser {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	rand.Read(auth)
+	return &testBrowser{key: key, auth: auth}
+}
+
+func (b *testBrowser) subscription(endpoint string) WebPushSubscription {
+	return WebPushSubscription{
+		Token:    WebPushToken(endpoint),
+		Endpoint: endpoint,
+		P256dh:   base64.RawURLEncoding.EncodeToString(b.key.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(b.auth),
+	}
+}
+
+// decrypt decrypts the message as the browser does, per RFC 8291
+func (b *testBrowser) decrypt(t *testing.T, body []byte) []byte {
+	t.Helper()
+	salt, recordSize, asPublic := body[:16], binary.BigEndian.Uint32(body[16:20]), body[21:21+int(body[20])]
+	if recordSize != webPushRecordSize {
+		t.Fatalf("unexpected record size %d", recordSize)
+	}
+	asKey, err := ecdh.P256().NewPublicKey(asPublic)
+	if err != nil {
+		t.Fatalf("expected the key of the server, got %v", err)
+	}
+	ecdhSecret, _ := b.key.ECDH(asKey)
+
+	uaPublic := b.key.PublicKey().Bytes()
+	keyInfo := append(append([]byte("WebPush: info\x00"), uaPublic...), asPublic...)
+	ikm := make([]byte, 32)
+	io.ReadFull(hkdf.New(sha256.New, ecdhSecret, b.auth, keyInfo), ikm)
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek, nonce := make([]byte, 16), make([]byte, 12)
+	io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: aes128gcm\x00")), cek)
+	io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: nonce\x00")), nonce)
+
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plaintext, err := gcm.Open(nil, nonce, body[21+len(asPublic):], nil)
+	if err != nil {
+		t.Fatalf("expected the message to decrypt, got %v", err)
+	}
+	if plaintext[len(plaintext)-1] != 2 {
+		t.Fatal("expected the message to be a single, last record")
+	}
+	return plaintext[:len(plaintext)-1]
+}
+
+func newTestVAPIDKeys(t *testing.T) (string, string) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()), base64.RawURLEncoding.EncodeToString(key.Bytes())
+}
+
+func newTestWebPush(t *testing.T, subscriptions Subscriptions) *WebPush {
+	public, private := newTestVAPIDKeys(t)
+	w, err := NewWebPush(WebPushConfig{VAPIDPublicKey: public, VAPIDPrivateKey: private, Subject: "mailto:test@cloudoki.com"}, subscriptions, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+// verifyVAPID checks the Authorization header is a VAPID token for the audience signed with the public key
+func verifyVAPID(t *testing.T, authorization string, audience string, publicKey string) {
+	t.Helper()
+	var token, key string
+	for _, part := range strings.Split(strings.TrimPrefix(authorization, "vapid "), ", ") {
+		if strings.HasPrefix(part, "t=") {
+			token = strings.TrimPrefix(part, "t=")
+		} else if strings.HasPrefix(part, "k=") {
+			key = strings.TrimPrefix(part, "k=")
+		}
+	}
+	if key != publicKey {
+		t.Fatalf("expected the public key to be sent, got %q", key)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a JWT, got %q", token)
+	}
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims struct {
+		Aud string `json:"aud"`
+		Sub string `json:"sub"`
+	}
+	json.Unmarshal(claimsJSON, &claims)
+	if claims.Aud != audience || claims.Sub != "mailto:test@cloudoki.com" {
+		t.Fatalf("unexpected claims %s", claimsJSON)
+	}
+
+	rawKey, _ := base64.RawURLEncoding.DecodeString(publicKey)
+	x, y := new(big.Int).SetBytes(rawKey[1:33]), new(big.Int).SetBytes(rawKey[33:])
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+	if !ecdsa.Verify(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, digest[:], r, s) {
+		t.Fatal("expected the VAPID token to be signed with the key")
+	}
+}
+
+func TestSealWebPush(t *testing.T) {
+	t.Run("expect the message of RFC 8291 to be encrypted as in its example", func(t *testing.T) {
+		decode := func(s string) []byte {
+			b, err := decodeBase64URL(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return b
+		}
+		uaKey, err := ecdh.P256().NewPublicKey(decode("BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		asKey, err := ecdh.P256().NewPrivateKey(decode("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := sealWebPush(uaKey, decode("BTBZMqHH6r4Tts7J_aSIgg"), asKey, decode("DGv6ra1nlYgDCS1FRnbzlw"),
+			[]byte("When I grow up, I want to be a watermelon"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6Tlz" +
+			"AC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+		if encoded := base64.RawURLEncoding.EncodeToString(body); encoded != expected {
+			t.Fatalf("expected %s, got %s", expected, encoded)
+		}
+	})
+}
+
+func TestNewWebPush(t *testing.T) {
+	t.Run("expect a public key not matching the private key to be refused", func(t *testing.T) {
+		_, private := newTestVAPIDKeys(t)
+		public, _ := newTestVAPIDKeys(t)
+
+		if _, err := NewWebPush(WebPushConfig{VAPIDPublicKey: public, VAPIDPrivateKey: private}, &fakeSubscriptions{}, nil); err == nil {
+			t.Fatal("expected the mismatched keys to be refused")
+		}
+	})
+}
+
+func TestWebPush_SendToUser(t *testing.T) {
+	t.Run("expect the message to be encrypted for the browser and signed with the VAPID key", func(t *testing.T) {
+		var req *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = r
+			body, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+		browser := newTestBrowser(t)
+		w := newTestWebPush(t, &fakeSubscriptions{subscriptions: []WebPushSubscription{browser.subscription(server.URL + "/push/1")}})
+
+		err := w.SendToUser(context.Background(), "1", Notification{
+			Title: "Cheers!", Body: "You received a beer", DeepLink: "appdoki://beers", CollapseKey: "beer_received",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if req == nil || req.URL.Path != "/push/1" {
+			t.Fatal("expected the message to be pushed to the endpoint")
+		}
+		if req.Header.Get("Content-Encoding") != "aes128gcm" || req.Header.Get("TTL") == "" || req.Header.Get("Topic") != "beer_received" {
+			t.Fatalf("unexpected headers %v", req.Header)
+		}
+		verifyVAPID(t, req.Header.Get("Authorization"), server.URL, w.publicKey)
+
+		var message webPushMessage
+		if err := json.Unmarshal(browser.decrypt(t, body), &message); err != nil {
+			t.Fatal(err)
+		}
+		if message.Title != "Cheers!" || message.Body != "You received a beer" || message.Data["deep_link"] != "appdoki://beers" {
+			t.Fatalf("unexpected message %+v", message)
+		}
+	})
+
+	t.Run("expect the subscriptions the push service no longer knows to be forgotten", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/gone" {
+				w.WriteHeader(http.StatusGone)
+			}
+		}))
+		defer server.Close()
+		gone, ok := newTestBrowser(t).subscription(server.URL+"/gone"), newTestBrowser(t).subscription(server.URL+"/ok")
+		subscriptions := &fakeSubscriptions{subscriptions: []WebPushSubscription{gone, ok}}
+		w := newTestWebPush(t, subscriptions)
+		ctx, report := WithReport(context.Background())
+
+		if err := w.SendToUser(ctx, "1", Notification{Title: "Welcome"}); err != nil {
+			t.Fatalf("expected the gone subscription not to fail the send, got %v", err)
+		}
+		if !reflect.DeepEqual(subscriptions.forgotten, []string{gone.Token}) {
+			t.Fatalf("expected the gone subscription to be forgotten, got %v", subscriptions.forgotten)
+		}
+		if results := report.Results(); len(results) != 2 || !results[0].Stale || results[1].Err != nil {
+			t.Fatalf("expected the outcome of both browsers, got %+v", results)
+		}
+	})
+
+	t.Run("expect the push service failing to be retried", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+		subscriptions := &fakeSubscriptions{subscriptions: []WebPushSubscription{newTestBrowser(t).subscription(server.URL)}}
+		w := newTestWebPush(t, subscriptions)
+
+		if err := w.SendToUser(context.Background(), "1", Notification{Title: "Welcome"}); !IsRetryable(err) {
+			t.Fatalf("expected a retryable failure, got %v", err)
+		}
+		if len(subscriptions.forgotten) != 0 {
+			t.Fatalf("expected the subscription to be kept, got %v forgotten", subscriptions.forgotten)
+		}
+	})
+
+	t.Run("expect silent notifications not to be pushed to browsers", func(t *testing.T) {
+		pushed := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { pushed = true }))
+		defer server.Close()
+		w := newTestWebPush(t, &fakeSubscriptions{subscriptions: []WebPushSubscription{newTestBrowser(t).subscription(server.URL)}})
+
+		w.SendToUser(context.Background(), "1", Notification{Silent: true, Data: map[string]string{"type": "user_updated"}})
+
+		if pushed {
+			t.Fatal("expected nothing to be pushed")
+		}
+	})
+}
+
+func TestWebPush_SendMulticast(t *testing.T) {
+	t.Run("expect only the web push tokens to be pushed to", func(t *testing.T) {
+		var paths []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+		}))
+		defer server.Close()
+		browser := newTestBrowser(t).subscription(server.URL + "/browser")
+		w := newTestWebPush(t, &fakeSubscriptions{subscriptions: []WebPushSubscription{browser}})
+
+		if err := w.SendMulticast(context.Background(), []string{"fcm-token", browser.Token}, Notification{Title: "Welcome"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(paths, []string{"/browser"}) {
+			t.Fatalf("expected the browser to be pushed to, got %v", paths)
+		}
+	})
+}
+
+func TestWebPushSubscription_Validate(t *testing.T) {
+	valid := newTestBrowser(t).subscription("https://fcm.googleapis.com/fcm/send/abc")
+	insecure, shortAuth := valid, valid
+	insecure.Endpoint = "http://push.example.com/abc"
+	shortAuth.Auth = base64.RawURLEncoding.EncodeToString([]byte("short"))
+
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected the subscription to be valid, got %v", err)
+	}
+	for name, s := range map[string]WebPushSubscription{"insecure endpoint": insecure, "short auth": shortAuth} {
+		if s.Validate() == nil {
+			t.Fatalf("expected the %s to be refused", name)
+		}
+	}
+}
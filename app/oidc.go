@@ -0,0 +1,30 @@
+package app
+
+import (
+	"appdoki-be/config"
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// oidcRetryAfter is the Retry-After of the routes answering 503 while the OIDC provider isn't discovered
+var oidcRetryAfter = strconv.Itoa(int(config.OIDCDiscoveryMaxDelay.Seconds()))
+
+// discoverOIDC discovers the OIDC provider in the background, retrying with backoff until it succeeds
+// or ctx is done. Meanwhile the routes which verify tokens answer 503, the others being served.
+func (a *Application) discoverOIDC(ctx context.Context, provider *config.OIDCProvider) {
+	err := provider.DiscoverWithRetry(ctx, config.OIDCDiscoveryBaseDelay, config.OIDCDiscoveryMaxDelay, func(attempt int, err error) {
+		a.logger.WithError(err).Warnf("error discovering the Google OIDC provider (attempt %d), the sign-in is unavailable, retrying", attempt)
+	})
+	if err == nil {
+		a.logger.Info("Google OIDC provider discovered, the sign-in is available")
+	}
+}
+
+// respondAuthUnavailable answers 503 while the tokens can't be verified, for the clients to retry
+// rather than sign the user out
+func respondAuthUnavailable(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", oidcRetryAfter)
+	respondError(w, http.StatusServiceUnavailable, ErrCodeAuthUnavailable,
+		"the sign-in is temporarily unavailable, please try again in a minute", nil)
+}
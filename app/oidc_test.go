@@ -0,0 +1,222 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	repos "appdoki-be/app/repositories"
+	"appdoki-be/app/testsupport"
+	"appdoki-be/config"
+	"context"
+	"encoding/json"
+	"golang.org/x/oauth2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestApplication_OIDCUnavailable(t *testing.T) {
+	// newApp returns an application verifying the tokens, its OIDC provider left undiscovered
+	newApp := func() *Application {
+		a := newTestApplication()
+		a.conf.AppConfig.TestMode = false
+		a.conf.AppConfig.OIDCDiscovery = config.OIDCDiscoveryLazy
+		a.conf.AppConfig.OIDCProvider = config.NewOIDCProvider("http://127.0.0.1:0", false, http.DefaultClient)
+		return a
+	}
+
+	t.Run("expect the authenticated routes to return 503 until the provider is discovered", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		r.Header.Set("Authorization", "Bearer token")
+		w := httptest.NewRecorder()
+		newApp().Routes().ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusServiceUnavailable)
+		assertErrorCode(t, resp, ErrCodeAuthUnavailable)
+		if resp.Header.Get("Retry-After") != "60" {
+			t.Fatalf("expected to be retried in a minute, got '%s'", resp.Header.Get("Retry-After"))
+		}
+	})
+
+	t.Run("expect the sign-in to return 503 without exchanging the code", func(t *testing.T) {
+		a := newApp()
+		r := httptest.NewRequest("GET", "/auth/google/callback?code=abc", nil)
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusServiceUnavailable)
+	})
+
+	t.Run("expect the routes which don't need it to be served", func(t *testing.T) {
+		resp, body := getBootstrap(t, newApp(), "token")
+
+		assertStatusCode(t, resp, http.StatusOK)
+		if string(body["user"]) != "null" {
+			t.Fatalf("expected the caller to be anonymous, got %s", body["user"])
+		}
+	})
+
+	t.Run("expect the readiness to be degraded, rather than unavailable, in lazy mode", func(t *testing.T) {
+		a := newApp()
+		h := NewHealthHandler(
+			databaseCheck(&fakePinger{}),
+			oidcCheck(a.conf.AppConfig.OIDCProvider, false),
+		)
+		r := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+		h.Ready(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		res := readinessResponse(t, resp)
+		if res.Status != "degraded" || res.Checks["oidc"].Status != "unavailable" {
+			t.Fatalf("expected the oidc check to degrade the readiness, got %+v", res)
+		}
+	})
+}
+
+func TestApplication_OIDCIssuer(t *testing.T) {
+	// newApp returns an application verifying the tokens of a fake provider of a non Google issuer,
+	// like a local Keycloak, issued to a client of its own
+	newApp := func(t *testing.T) (*Application, *testsupport.OIDCProvider) {
+		fake := testsupport.NewOIDCProvider(t)
+		a := newTestApplication()
+		a.conf.AppConfig.TestMode = false
+		a.conf.AppConfig.WebClientID = "google-web-client"
+		a.conf.AppConfig.OIDCIssuerURL = fake.Issuer
+		a.conf.AppConfig.OIDCAudiences = []string{"appdoki-local"}
+		a.conf.AppConfig.GoogleOauth = oauth2.Config{ClientID: "appdoki-local", RedirectURL: "http://localhost:4000/auth/google/callback"}
+		a.conf.AppConfig.OIDCProvider = config.NewOIDCProvider(fake.Issuer, false, http.DefaultClient)
+		if err := a.conf.AppConfig.OIDCProvider.Discover(); err != nil {
+			t.Fatal(err)
+		}
+		return a, fake
+	}
+
+	t.Run("expect the bearer tokens of the issuer to be verified for the configured audiences", func(t *testing.T) {
+		a, fake := newApp(t)
+		routes := a.Routes()
+		get := func(token string) *http.Response {
+			r := httptest.NewRequest("GET", "/api/v1/users", nil)
+			r.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			routes.ServeHTTP(w, r)
+			return w.Result()
+		}
+
+		assertStatusCode(t, get(fake.IDToken(t, "42", "appdoki-local", nil)), http.StatusOK)
+		assertStatusCode(t, get(fake.IDToken(t, "42", "google-web-client", nil)), http.StatusUnauthorized)
+	})
+
+	t.Run("expect the tokens of a deactivated user to be refused, and the user not to sign in again", func(t *testing.T) {
+		a, fake := newApp(t)
+		a.features.Set(context.Background(), &repos.FeatureFlag{Name: "auth_token", Enabled: true, RolloutPercent: 100})
+		users := a.usersRepository.(*mockUsersRepository)
+		users.isDeactivatedImpl = func(_ context.Context, ID string) (bool, error) {
+			return ID == "42", nil
+		}
+		users.findOrCreateUserImpl = func(context.Context, *repos.User) (*repos.User, bool, error) {
+			return nil, false, repos.ErrUserDeactivated
+		}
+		routes := a.Routes()
+
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		r.Header.Set("Authorization", "Bearer "+fake.IDToken(t, "42", "appdoki-local", nil))
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusForbidden)
+		assertErrorCode(t, resp, ErrCodeUserDeactivated)
+		if failures := a.metrics.(*metrics.Fake).Counter(authFailuresMetric, metrics.Labels{"reason": "deactivated_user"}); failures != 1 {
+			t.Fatalf("expected the failure to be counted, got %v", failures)
+		}
+
+		r = httptest.NewRequest("POST", "/api/v1/auth/token", strings.NewReader(`{"code":"`+fake.Code(fake.IDToken(t, "42", "appdoki-local", nil))+`"}`))
+		w = httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+		assertStatusCode(t, w.Result(), http.StatusForbidden)
+
+		r = httptest.NewRequest("GET", "/api/v1/users", nil)
+		r.Header.Set("Authorization", "Bearer "+fake.IDToken(t, "43", "appdoki-local", nil))
+		w = httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+		assertStatusCode(t, w.Result(), http.StatusOK)
+	})
+
+	t.Run("expect a user deactivated through the admin bulk endpoint to be refused on their next request", func(t *testing.T) {
+		a, fake := newApp(t)
+		a.deactivations = newDeactivations(time.Hour)
+		users := a.usersRepository.(*mockUsersRepository)
+		var mu sync.Mutex
+		deactivated := map[string]bool{}
+		users.isDeactivatedImpl = func(_ context.Context, ID string) (bool, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return deactivated[ID], nil
+		}
+		users.deactivateManyImpl = func(_ context.Context, IDs []string) ([]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, ID := range IDs {
+				deactivated[ID] = true
+			}
+			return IDs, nil
+		}
+		users.findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			if ID == "1" {
+				user.Role = repos.RoleAdmin
+			}
+			return user, nil
+		}
+		routes := a.Routes()
+		serve := func(method string, path string, body string, userID string) *http.Response {
+			r := httptest.NewRequest(method, path, strings.NewReader(body))
+			r.Header.Set("Authorization", "Bearer "+fake.IDToken(t, userID, "appdoki-local", nil))
+			w := httptest.NewRecorder()
+			routes.ServeHTTP(w, r)
+			return w.Result()
+		}
+
+		assertStatusCode(t, serve("GET", "/api/v1/users", "", "42"), http.StatusOK)
+		assertStatusCode(t, serve("POST", "/admin/users/bulk", `{"action":"deactivate","ids":["42"],"confirm":true}`, "1"), http.StatusOK)
+
+		resp := serve("GET", "/api/v1/users", "", "42")
+		assertStatusCode(t, resp, http.StatusForbidden)
+		assertErrorCode(t, resp, ErrCodeUserDeactivated)
+	})
+
+	t.Run("expect the sign-in to exchange the code with the issuer and verify its token alike", func(t *testing.T) {
+		a, fake := newApp(t)
+		a.features.Set(context.Background(), &repos.FeatureFlag{Name: "auth_token", Enabled: true, RolloutPercent: 100})
+		var created *repos.User
+		a.usersRepository.(*mockUsersRepository).findOrCreateUserImpl = func(_ context.Context, user *repos.User) (*repos.User, bool, error) {
+			created = user
+			return user, true, nil
+		}
+		idToken := fake.IDToken(t, "42", "appdoki-local", map[string]interface{}{"email": "ana@cloudoki.com", "name": "Ana"})
+
+		r := httptest.NewRequest("POST", "/api/v1/auth/token", strings.NewReader(`{"code":"`+fake.Code(idToken)+`"}`))
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusOK)
+		var res TokenResponse
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Token != idToken || created == nil || created.ID != "42" || created.Email != "ana@cloudoki.com" {
+			t.Fatalf("expected user 42 to sign in, got %+v", created)
+		}
+
+		r = httptest.NewRequest("POST", "/api/v1/auth/token", strings.NewReader(`{"code":"`+fake.Code(fake.IDToken(t, "43", "another-app", nil))+`"}`))
+		w = httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+		assertStatusCode(t, w.Result(), http.StatusUnauthorized)
+	})
+}
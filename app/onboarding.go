@@ -0,0 +1,79 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+)
+
+// welcomeTrigger is the pending trigger of the welcome notification, fired by the first device of the user
+const welcomeTrigger = "welcome"
+
+// welcomePayload is the payload of the welcome event
+type welcomePayload struct {
+	Name string
+}
+
+// subscribeOnboarding subscribes the onboarding steps enabled to the internal events: the users
+// signing in for the first time are announced to the users topic right away, and welcomed once
+// their first device is registered
+func (a *Application) subscribeOnboarding() {
+	conf := a.conf.Onboarding
+	if conf.AnnounceEnabled {
+		a.events.Subscribe(eventUserCreated, a.announceUser)
+	}
+	if conf.WelcomeEnabled {
+		a.events.Subscribe(eventUserCreated, a.holdWelcome)
+		a.events.Subscribe(eventDeviceRegistered, a.sendWelcome)
+	}
+}
+
+// announceUser sends the new user to the users topic
+func (a *Application) announceUser(ctx context.Context, event userEvent) {
+	user := event.User
+	a.workers.Go(ctx, func(ctx context.Context) {
+		userJSON, _ := json.Marshal(user)
+		a.notifier.SendToTopic(ctx, usersTopic, notify.Notification{
+			Data:     map[string]string{"user": string(userJSON)},
+			DeepLink: "users/" + user.ID,
+		})
+	})
+}
+
+// holdWelcome keeps the welcome of the new user pending until they register a device, in the
+// database for it to survive the restarts in between
+func (a *Application) holdWelcome(ctx context.Context, event userEvent) {
+	if err := a.triggersRepository.Create(ctx, event.UserID, welcomeTrigger); err != nil {
+		logging.FromContext(ctx).Errorf("error holding the welcome of user %s: %v", event.UserID, err)
+	}
+}
+
+// sendWelcome welcomes the user registering a device when their welcome is pending, which it only
+// is until their first device
+func (a *Application) sendWelcome(ctx context.Context, event userEvent) {
+	a.workers.Go(ctx, func(ctx context.Context) {
+		pending, err := a.triggersRepository.Take(ctx, event.UserID, welcomeTrigger)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error taking the welcome of user %s: %v", event.UserID, err)
+			return
+		}
+		if !pending {
+			return
+		}
+
+		user, err := a.usersRepository.FindByID(repositories.ReadFresh(ctx), event.UserID)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error finding user %s to welcome: %v", event.UserID, err)
+			return
+		}
+		if user == nil {
+			return
+		}
+		a.notifier.SendToUser(ctx, event.UserID, notify.Notification{
+			Event:   eventWelcome,
+			Payload: welcomePayload{Name: user.Name},
+		})
+	})
+}
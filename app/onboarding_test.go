@@ -0,0 +1,90 @@
+package app
+
+import (
+	"appdoki-be/app/notify"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOnboarding(t *testing.T) {
+	register := func(a *Application, token string) {
+		r := httptest.NewRequest("POST", "/api/v1/users/me/devices", strings.NewReader(`{"token":"`+token+`","platform":"ios"}`))
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+		assertStatusCode(t, w.Result(), http.StatusCreated)
+	}
+	onboard := func(a *Application, announce bool, welcome bool) *notify.Fake {
+		a.conf.Onboarding.AnnounceEnabled = announce
+		a.conf.Onboarding.WelcomeEnabled = welcome
+		a.subscribeOnboarding()
+		return a.notifier.(*notify.Fake)
+	}
+	stop := func(t *testing.T, a *Application) {
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	created := userEvent{UserID: "1", User: &repos.User{ID: "1", Name: "Alice"}}
+
+	t.Run("expect a new user to be announced, then welcomed on their first device only", func(t *testing.T) {
+		a := newTestApplication()
+		fake := onboard(a, true, true)
+
+		a.events.Publish(context.Background(), eventUserCreated, created)
+		register(a, "first-token")
+		register(a, "second-token")
+		stop(t, a)
+
+		var announced, welcomed []notify.Sent
+		for _, sent := range fake.Sent() {
+			switch {
+			case sent.Topic == usersTopic:
+				announced = append(announced, sent)
+			case sent.UserID == "1":
+				welcomed = append(welcomed, sent)
+			}
+		}
+		if len(announced) != 1 || announced[0].Notification.DeepLink != "users/1" {
+			t.Fatalf("expected the user to be announced once, got %+v", announced)
+		}
+		if len(welcomed) != 1 || welcomed[0].Notification.Event != eventWelcome {
+			t.Fatalf("expected the user to be welcomed once, got %+v", welcomed)
+		}
+	})
+
+	t.Run("expect a welcome held before a restart to be sent after it", func(t *testing.T) {
+		before := newTestApplication()
+		onboard(before, false, true)
+		before.events.Publish(context.Background(), eventUserCreated, created)
+
+		after := newTestApplication()
+		after.triggersRepository = before.triggersRepository
+		fake := onboard(after, false, true)
+		register(after, "fcm-token")
+		stop(t, after)
+
+		if sent := fake.Sent(); len(sent) != 1 || sent[0].UserID != "1" || sent[0].Notification.Event != eventWelcome {
+			t.Fatalf("expected the held welcome to be sent, got %+v", sent)
+		}
+	})
+
+	t.Run("expect the disabled steps to be skipped", func(t *testing.T) {
+		a := newTestApplication()
+		fake := onboard(a, false, false)
+
+		a.events.Publish(context.Background(), eventUserCreated, created)
+		register(a, "fcm-token")
+		stop(t, a)
+
+		if sent := fake.Sent(); len(sent) != 0 {
+			t.Fatalf("expected no onboarding notification, got %+v", sent)
+		}
+		if pending, _ := a.triggersRepository.Take(context.Background(), "1", welcomeTrigger); pending {
+			t.Fatal("expected no welcome to be held")
+		}
+	})
+}
@@ -0,0 +1,266 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"context"
+	"fmt"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxWebhookEndpointPayloadBytes = 4 << 10
+	// minWebhookSecretLength is the shortest secret the endpoints may be signed with
+	minWebhookSecretLength = 16
+)
+
+// WebhookEndpointPayload registers an endpoint the events subscribed to are posted to, signed with
+// the secret
+type WebhookEndpointPayload struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+func (p *WebhookEndpointPayload) validate() []string {
+	var errs []string
+
+	if u, err := url.Parse(p.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		errs = append(errs, "url: must be an absolute http or https URL")
+	}
+	if len(p.Secret) < minWebhookSecretLength {
+		errs = append(errs, fmt.Sprintf("secret: must be at least %d characters long", minWebhookSecretLength))
+	}
+	if len(p.Events) == 0 {
+		errs = append(errs, "events: at least one event is required")
+	}
+	seen := map[string]bool{}
+	for _, event := range p.Events {
+		if !containsString(routableEvents, event) {
+			errs = append(errs, fmt.Sprintf("events: unknown event %q, expected any of %s", event, strings.Join(routableEvents, ", ")))
+		} else if seen[event] {
+			errs = append(errs, fmt.Sprintf("events: %q is listed twice", event))
+		}
+		seen[event] = true
+	}
+
+	return errs
+}
+
+// newOutboundWebhooks returns the sender posting the events to the endpoints registered, the
+// failed posts being attempted again with the retry policy of the notifications
+func (a *Application) newOutboundWebhooks() *notify.Webhooks {
+	return notify.NewWebhooks(a.retryPolicy(), webhookEndpoints{a.webhooksRepository, a.conf.Webhooks.MaxFailures}, a.workers.Go, a.httpClients)
+}
+
+// webhookEndpoints keeps the endpoints and their deliveries in the webhooks repository, disabling
+// the endpoints once maxFailures deliveries in a row failed
+type webhookEndpoints struct {
+	webhooks    repositories.WebhooksRepositoryInterface
+	maxFailures int
+}
+
+func (e webhookEndpoints) Subscribed(ctx context.Context, event string) ([]notify.WebhookEndpoint, error) {
+	endpoints, err := e.webhooks.ListSubscribed(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribed := make([]notify.WebhookEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		subscribed = append(subscribed, notify.WebhookEndpoint{ID: endpoint.ID, URL: endpoint.URL, Secret: endpoint.Secret})
+	}
+	return subscribed, nil
+}
+
+func (e webhookEndpoints) Attempted(ctx context.Context, attempt notify.WebhookAttempt) error {
+	delivery := &repositories.WebhookDelivery{
+		EndpointID: attempt.EndpointID,
+		EventID:    attempt.EventID,
+		Event:      attempt.Event,
+		Payload:    attempt.Payload,
+		Attempt:    attempt.Attempt,
+		Status:     repositories.WebhookDelivered,
+	}
+	if attempt.StatusCode != 0 {
+		delivery.ResponseCode = &attempt.StatusCode
+	}
+	if attempt.Err != nil {
+		delivery.Status = repositories.WebhookFailed
+		delivery.Error = attempt.Err.Error()
+	}
+	_, err := e.webhooks.CreateDelivery(ctx, delivery)
+	return err
+}
+
+func (e webhookEndpoints) Delivered(ctx context.Context, endpointID int64, ok bool) error {
+	disabled, err := e.webhooks.RecordOutcome(ctx, endpointID, ok, e.maxFailures)
+	if disabled {
+		logging.FromContext(ctx).Warnf("disabled webhook endpoint %d after %d failed deliveries in a row", endpointID, e.maxFailures)
+	}
+	return err
+}
+
+// CreateWebhookEndpoint registers an endpoint, enabled right away
+func (a *Application) CreateWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	var payload WebhookEndpointPayload
+	if err := decodeJSON(r, &payload, maxWebhookEndpointPayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		respondError(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed, "invalid webhook endpoint", errs)
+		return
+	}
+
+	actorID, _ := r.Context().Value("userID").(string)
+	endpoint, err := a.webhooksRepository.Create(r.Context(), &repositories.WebhookEndpoint{
+		URL:       payload.URL,
+		Secret:    payload.Secret,
+		Events:    pq.StringArray(payload.Events),
+		CreatedBy: &actorID,
+	})
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	a.auditWebhookEndpoint(r.Context(), actorID, repositories.AuditWebhookCreated, endpoint)
+	respondJSON(w, r, endpoint, http.StatusCreated)
+}
+
+// GetWebhookEndpoints lists the endpoints, the oldest first
+func (a *Application) GetWebhookEndpoints(w http.ResponseWriter, r *http.Request) {
+	endpoints, err := a.webhooksRepository.List(r.Context())
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	respondJSON(w, r, endpoints, http.StatusOK)
+}
+
+// DeleteWebhookEndpoint removes an endpoint along with its deliveries
+func (a *Application) DeleteWebhookEndpoint(w http.ResponseWriter, r *http.Request) {
+	endpoint, ok := a.findWebhookEndpoint(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := a.webhooksRepository.Delete(r.Context(), endpoint.ID)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	if !deleted {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "webhook endpoint not found", nil)
+		return
+	}
+
+	actorID, _ := r.Context().Value("userID").(string)
+	a.auditWebhookEndpoint(r.Context(), actorID, repositories.AuditWebhookDeleted, endpoint)
+	respondNoContent(w, http.StatusNoContent)
+}
+
+// GetWebhookDeliveries lists the latest attempts to post the events to an endpoint, for debugging
+func (a *Application) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	params := newQueryParams(r)
+	limit := params.IntInRange("limit", 20, 1, 100)
+	if err := params.Err(); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	endpoint, ok := a.findWebhookEndpoint(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := a.webhooksRepository.ListDeliveries(r.Context(), endpoint.ID, limit)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	respondJSON(w, r, deliveries, http.StatusOK)
+}
+
+// RedeliverWebhook queues posting the event of a delivery to its endpoint once more, with the same
+// payload. The endpoint is redelivered to even when disabled, and enabled again once it succeeds.
+func (a *Application) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	endpoint, ok := a.findWebhookEndpoint(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseInt(mux.Vars(r)["delivery"], 10, 64)
+	if err != nil {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "webhook delivery not found", nil)
+		return
+	}
+	delivery, err := a.webhooksRepository.FindDelivery(r.Context(), endpoint.ID, deliveryID)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	if delivery == nil {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "webhook delivery not found", nil)
+		return
+	}
+
+	target := notify.WebhookEndpoint{ID: endpoint.ID, URL: endpoint.URL, Secret: endpoint.Secret}
+	a.workers.Go(r.Context(), func(ctx context.Context) {
+		err := a.outboundWebhooks.Redeliver(ctx, target, delivery.EventID, delivery.Event, delivery.Payload, delivery.Attempt+1)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Warnf("redelivering webhook delivery %d failed", delivery.ID)
+		}
+	})
+
+	respondNoContent(w, http.StatusAccepted)
+}
+
+// findWebhookEndpoint finds the endpoint of the request, responding with the error when it can't
+func (a *Application) findWebhookEndpoint(w http.ResponseWriter, r *http.Request) (*repositories.WebhookEndpoint, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "webhook endpoint not found", nil)
+		return nil, false
+	}
+
+	endpoint, err := a.webhooksRepository.Find(r.Context(), id)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return nil, false
+	}
+	if endpoint == nil {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "webhook endpoint not found", nil)
+		return nil, false
+	}
+	return endpoint, true
+}
+
+// auditWebhookEndpoint records the registration or removal of the endpoint, its failure being
+// logged rather than blocking it
+func (a *Application) auditWebhookEndpoint(ctx context.Context, actorID string, action string, endpoint *repositories.WebhookEndpoint) {
+	entry := &repositories.AuditEntry{
+		ActorID:  actorID,
+		Action:   action,
+		TargetID: strconv.FormatInt(endpoint.ID, 10),
+		Details: map[string]interface{}{
+			"url":    endpoint.URL,
+			"events": []string(endpoint.Events),
+		},
+	}
+	auditCtx, cancel := context.WithTimeout(logging.Detach(ctx), auditRecordTimeout)
+	defer cancel()
+	if err := a.auditRepository.Record(auditCtx, []*repositories.AuditEntry{entry}); err != nil {
+		logging.FromContext(ctx).
+			WithError(err).
+			WithField("user_id", actorID).
+			Error("could not record the audit entry of a webhook endpoint")
+	}
+}
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"appdoki-be/app/notify"
+	"appdoki-be/app/reporting"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestApplication_OutboundWebhooks(t *testing.T) {
+	newAdminApplication := func() *Application {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		a.conf.Notifications.RetryMaxAttempts = 1
+		a.conf.Webhooks.MaxFailures = 2
+		a.outboundWebhooks = a.newOutboundWebhooks()
+		return a
+	}
+	serve := func(a *Application, method string, path string, body string) *http.Response {
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w.Result()
+	}
+	register := func(t *testing.T, a *Application, url string) *repos.WebhookEndpoint {
+		t.Helper()
+		resp := serve(a, "POST", "/admin/webhooks", `{"url":"`+url+`","secret":"0123456789abcdef","events":["beer_given"]}`)
+		assertStatusCode(t, resp, http.StatusCreated)
+		var endpoint repos.WebhookEndpoint
+		if err := json.NewDecoder(resp.Body).Decode(&endpoint); err != nil {
+			t.Fatal(err)
+		}
+		return &endpoint
+	}
+	// drain waits for the deliveries in the background, the workers starting over for the next ones
+	drain := func(t *testing.T, a *Application) {
+		t.Helper()
+		if err := a.workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		a.workers = newWorkerGroup(reporting.Noop{})
+		a.outboundWebhooks = a.newOutboundWebhooks()
+	}
+	beerGiven := notify.Notification{Title: "Cheers!", Event: eventBeerGiven}
+
+	t.Run("expect an endpoint to be registered and listed, without its secret", func(t *testing.T) {
+		a := newAdminApplication()
+
+		endpoint := register(t, a, "https://example.com/appdoki")
+
+		resp := serve(a, "GET", "/admin/webhooks", "")
+		assertStatusCode(t, resp, http.StatusOK)
+		body, _ := ioutil.ReadAll(resp.Body)
+		if !strings.Contains(string(body), `"url":"https://example.com/appdoki"`) || strings.Contains(string(body), "0123456789abcdef") {
+			t.Fatalf("expected the endpoint listed without its secret, got %s", body)
+		}
+		if !endpoint.Enabled || len(a.auditRepository.(*mockAuditRepository).entries) != 1 {
+			t.Fatal("expected the endpoint to be enabled and its registration audited")
+		}
+	})
+
+	t.Run("expect an invalid endpoint to be rejected", func(t *testing.T) {
+		resp := serve(newAdminApplication(), "POST", "/admin/webhooks", `{"url":"ftp://example.com","secret":"short","events":["beer_spilled"]}`)
+
+		assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+		body, _ := ioutil.ReadAll(resp.Body)
+		for _, field := range []string{"url:", "secret:", "events:"} {
+			if !strings.Contains(string(body), field) {
+				t.Fatalf("expected the %s error, got %s", field, body)
+			}
+		}
+	})
+
+	t.Run("expect an endpoint failing in a row to be disabled, then enabled again by a redelivery", func(t *testing.T) {
+		var mu sync.Mutex
+		status := http.StatusInternalServerError
+		var signed error
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			mu.Lock()
+			defer mu.Unlock()
+			signed = defaultWebhookSignature.verify(r, body, []byte("0123456789abcdef"), time.Now(), time.Minute)
+			w.WriteHeader(status)
+		}))
+		defer ts.Close()
+		a := newAdminApplication()
+		endpoint := register(t, a, ts.URL)
+
+		for i := 0; i < 3; i++ {
+			a.outboundWebhooks.SendToTopic(context.Background(), beersTopic, beerGiven)
+			drain(t, a)
+		}
+
+		resp := serve(a, "GET", fmt.Sprintf("/admin/webhooks/%d/deliveries", endpoint.ID), "")
+		assertStatusCode(t, resp, http.StatusOK)
+		var deliveries []*repos.WebhookDelivery
+		if err := json.NewDecoder(resp.Body).Decode(&deliveries); err != nil {
+			t.Fatal(err)
+		}
+		if len(deliveries) != 2 || deliveries[0].Status != repos.WebhookFailed || *deliveries[0].ResponseCode != http.StatusInternalServerError {
+			t.Fatalf("expected the 2 failed deliveries before the endpoint was disabled, got %+v", deliveries)
+		}
+		if found, _ := a.webhooksRepository.Find(context.Background(), endpoint.ID); found.Enabled {
+			t.Fatal("expected the endpoint to be disabled")
+		}
+		if signed != nil {
+			t.Fatalf("expected the posts to be signed like the inbound webhooks, got %v", signed)
+		}
+
+		mu.Lock()
+		status = http.StatusOK
+		mu.Unlock()
+		resp = serve(a, "POST", fmt.Sprintf("/admin/webhooks/%d/deliveries/%d/redeliver", endpoint.ID, deliveries[0].ID), "")
+		assertStatusCode(t, resp, http.StatusAccepted)
+		drain(t, a)
+
+		redelivered, _ := a.webhooksRepository.ListDeliveries(context.Background(), endpoint.ID, 1)
+		if redelivered[0].Status != repos.WebhookDelivered || redelivered[0].EventID != deliveries[0].EventID {
+			t.Fatalf("expected the event to be redelivered, got %+v", redelivered[0])
+		}
+		if found, _ := a.webhooksRepository.Find(context.Background(), endpoint.ID); !found.Enabled || found.ConsecutiveFailures != 0 {
+			t.Fatalf("expected the endpoint to be enabled again, got %+v", found)
+		}
+	})
+
+	t.Run("expect an unknown endpoint or delivery to be not found", func(t *testing.T) {
+		a := newAdminApplication()
+		endpoint := register(t, a, "https://example.com/appdoki")
+
+		assertStatusCode(t, serve(a, "GET", "/admin/webhooks/42/deliveries", ""), http.StatusNotFound)
+		assertStatusCode(t, serve(a, "POST", fmt.Sprintf("/admin/webhooks/%d/deliveries/42/redeliver", endpoint.ID), ""), http.StatusNotFound)
+		assertStatusCode(t, serve(a, "DELETE", fmt.Sprintf("/admin/webhooks/%d", endpoint.ID), ""), http.StatusNoContent)
+		assertStatusCode(t, serve(a, "DELETE", fmt.Sprintf("/admin/webhooks/%d", endpoint.ID), ""), http.StatusNotFound)
+	})
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"appdoki-be/app/filter"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queryDateLayouts are the accepted layouts of the time query parameters
+var queryDateLayouts = []string{time.RFC3339Nano, "2006-01-02"}
+
+// fieldError describes the problem of a single request field
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// queryParams reads typed query parameters, falling back to a default when they're
+// absent, and collects the problems of every invalid one so they're reported at once:
+//
+//	params := newQueryParams(r)
+//	limit := params.IntInRange("limit", 20, 1, 100)
+//	if err := params.Err(); err != nil {
+//		respondRequestError(w, err)
+//		return
+//	}
+type queryParams struct {
+	values url.Values
+	errs   []fieldError
+}
+
+func newQueryParams(r *http.Request) *queryParams {
+	return &queryParams{values: r.URL.Query()}
+}
+
+// IntInRange reads an integer between min and max, inclusive
+func (p *queryParams) IntInRange(name string, def int, min int, max int) int {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		p.fail(name, "must be an integer")
+		return def
+	}
+	if value < min || value > max {
+		p.fail(name, fmt.Sprintf("must be between %d and %d", min, max))
+		return def
+	}
+	return value
+}
+
+// Bool reads a boolean, either 1, t, true, 0, f or false
+func (p *queryParams) Bool(name string, def bool) bool {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.fail(name, "must be a boolean")
+		return def
+	}
+	return value
+}
+
+// Time reads a RFC 3339 timestamp or a date (2006-01-02, as UTC)
+func (p *queryParams) Time(name string, def time.Time) time.Time {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return def
+	}
+
+	for _, layout := range queryDateLayouts {
+		if value, err := time.Parse(layout, raw); err == nil {
+			return value
+		}
+	}
+	p.fail(name, "must be a RFC 3339 timestamp or a YYYY-MM-DD date")
+	return def
+}
+
+// OneOf reads a value among the allowed ones
+func (p *queryParams) OneOf(name string, def string, allowed ...string) string {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return def
+	}
+
+	for _, value := range allowed {
+		if raw == value {
+			return value
+		}
+	}
+	p.fail(name, "must be one of "+strings.Join(allowed, ", "))
+	return def
+}
+
+// Filter reads a filter expression on the given fields, each rejected condition being reported
+func (p *queryParams) Filter(name string, fields filter.Fields) filter.Filter {
+	f, err := filter.Parse(p.values.Get(name), fields)
+	if err != nil {
+		var errs filter.Errors
+		if !errors.As(err, &errs) {
+			p.fail(name, err.Error())
+			return nil
+		}
+		for _, e := range errs {
+			p.fail(name, e.Error())
+		}
+		return nil
+	}
+	return f
+}
+
+// Err returns the problems of the invalid parameters as a *requestError, or nil
+func (p *queryParams) Err() error {
+	if len(p.errs) == 0 {
+		return nil
+	}
+
+	return &requestError{
+		status:  http.StatusBadRequest,
+		code:    ErrCodeValidationFailed,
+		message: "invalid query parameters",
+		details: p.errs,
+	}
+}
+
+func (p *queryParams) fail(name string, message string) {
+	p.errs = append(p.errs, fieldError{Field: name, Message: message})
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestQueryParams(t *testing.T) {
+	params := func(query string) *queryParams {
+		return newQueryParams(httptest.NewRequest("GET", "/?"+query, nil))
+	}
+
+	t.Run("expect defaults for absent params", func(t *testing.T) {
+		p := params("")
+		def := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+		if v := p.IntInRange("limit", 20, 1, 100); v != 20 {
+			t.Fatalf("expected 20, got %d", v)
+		}
+		if v := p.Bool("active", true); !v {
+			t.Fatal("expected true")
+		}
+		if v := p.Time("since", def); !v.Equal(def) {
+			t.Fatalf("expected %s, got %s", def, v)
+		}
+		if v := p.OneOf("op", "lt", "lt", "gt"); v != "lt" {
+			t.Fatalf("expected 'lt', got '%s'", v)
+		}
+		if err := p.Err(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("expect valid params to be parsed", func(t *testing.T) {
+		p := params("limit=50&active=false&op=gt")
+
+		if v := p.IntInRange("limit", 20, 1, 100); v != 50 {
+			t.Fatalf("expected 50, got %d", v)
+		}
+		if v := p.Bool("active", true); v {
+			t.Fatal("expected false")
+		}
+		if v := p.OneOf("op", "lt", "lt", "gt"); v != "gt" {
+			t.Fatalf("expected 'gt', got '%s'", v)
+		}
+		if err := p.Err(); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("expect dates to be parsed regardless of the locale", func(t *testing.T) {
+		expected := map[string]time.Time{
+			"2021-03-04":                time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC),
+			"2021-03-04T05:06:07Z":      time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+			"2021-03-04T05:06:07.5Z":    time.Date(2021, 3, 4, 5, 6, 7, 5e8, time.UTC),
+			"2021-03-04T06:06:07+01:00": time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+		}
+		for raw, want := range expected {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.URL.RawQuery = "since=" + url.QueryEscape(raw)
+			p := newQueryParams(r)
+			if v := p.Time("since", time.Time{}); !v.Equal(want) {
+				t.Fatalf("expected %s for '%s', got %s", want, raw, v)
+			}
+			if err := p.Err(); err != nil {
+				t.Fatalf("expected '%s' to be valid, got %v", raw, err)
+			}
+		}
+
+		for _, raw := range []string{"04/03/2021", "4 March 2021", "2021-3-4", "mar 4, 2021"} {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.URL.RawQuery = "since=" + url.QueryEscape(raw)
+			p := newQueryParams(r)
+			p.Time("since", time.Time{})
+			if p.Err() == nil {
+				t.Fatalf("expected '%s' to be invalid", raw)
+			}
+		}
+	})
+
+	t.Run("expect every invalid param to be reported in one 400", func(t *testing.T) {
+		p := params("limit=500&active=maybe&since=yesterday&op=eq")
+		p.IntInRange("limit", 20, 1, 100)
+		p.Bool("active", false)
+		p.Time("since", time.Time{})
+		p.OneOf("op", "lt", "lt", "gt")
+
+		w := httptest.NewRecorder()
+		respondRequestError(w, p.Err())
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusBadRequest)
+
+		var envelope struct {
+			Error struct {
+				Code    string       `json:"code"`
+				Details []fieldError `json:"details"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatalf("failed to parse the error envelope: %v", err)
+		}
+		if envelope.Error.Code != ErrCodeValidationFailed {
+			t.Fatalf("expected code '%s', got '%s'", ErrCodeValidationFailed, envelope.Error.Code)
+		}
+
+		fields := []string{"limit", "active", "since", "op"}
+		if len(envelope.Error.Details) != len(fields) {
+			t.Fatalf("expected %d field errors, got %d", len(fields), len(envelope.Error.Details))
+		}
+		for i, field := range fields {
+			if envelope.Error.Details[i].Field != field {
+				t.Fatalf("expected error %d on '%s', got '%s'", i, field, envelope.Error.Details[i].Field)
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"appdoki-be/app/i18n"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const maxPreferencesPayloadBytes = 1 << 10
+
+// PreferencesHandler holds handler dependencies
+type PreferencesHandler struct {
+	preferencesRepo repositories.PreferencesRepositoryInterface
+}
+
+// NewPreferencesHandler returns an initialized preferences handler with the required dependencies
+func NewPreferencesHandler(preferencesRepo repositories.PreferencesRepositoryInterface) *PreferencesHandler {
+	return &PreferencesHandler{
+		preferencesRepo: preferencesRepo,
+	}
+}
+
+// NotificationPreferencesPayload changes the preferences given, the others being kept. The quiet
+// hours are cleared by setting their start and end to empty strings.
+type NotificationPreferencesPayload struct {
+	BeerReceived    *bool   `json:"beer_received"`
+	NewUser         *bool   `json:"new_user"`
+	Digest          *bool   `json:"digest"`
+	DigestChannel   *string `json:"digest_channel"`
+	Locale          *string `json:"locale"`
+	QuietHoursStart *string `json:"quiet_hours_start"`
+	QuietHoursEnd   *string `json:"quiet_hours_end"`
+	Timezone        *string `json:"timezone"`
+}
+
+func (p *NotificationPreferencesPayload) validate() []string {
+	var errs []string
+	if p.DigestChannel != nil && *p.DigestChannel != repositories.DigestChannelPush && *p.DigestChannel != repositories.DigestChannelEmail {
+		errs = append(errs, "digest_channel: must be one of push, email")
+	}
+	if p.Locale != nil && !i18n.Supported(*p.Locale) {
+		errs = append(errs, "locale: must be one of "+strings.Join(i18n.Locales(), ", "))
+	}
+	if p.QuietHoursStart != nil && !validClock(*p.QuietHoursStart) {
+		errs = append(errs, "quiet_hours_start: must be a HH:MM time or empty")
+	}
+	if p.QuietHoursEnd != nil && !validClock(*p.QuietHoursEnd) {
+		errs = append(errs, "quiet_hours_end: must be a HH:MM time or empty")
+	}
+	if p.Timezone != nil && !validTimezone(*p.Timezone) {
+		errs = append(errs, "timezone: must be an IANA time zone")
+	}
+	return errs
+}
+
+// validateQuietHours checks the quiet hours of the preferences the payload was applied to, their
+// start and end being set together
+func validateQuietHours(prefs *repositories.NotificationPreferences) []string {
+	if (prefs.QuietHoursStart == "") != (prefs.QuietHoursEnd == "") {
+		return []string{"quiet_hours: start and end must be set together"}
+	}
+	if prefs.QuietHoursStart == "" {
+		return nil
+	}
+	if _, err := notify.ParseQuietHours(prefs.QuietHoursStart, prefs.QuietHoursEnd, prefs.Timezone); err != nil {
+		return []string{"quiet_hours: " + err.Error()}
+	}
+	return nil
+}
+
+func validClock(clock string) bool {
+	if clock == "" {
+		return true
+	}
+	_, err := time.Parse("15:04", clock)
+	return err == nil
+}
+
+func validTimezone(timezone string) bool {
+	if timezone == "" || timezone == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(timezone)
+	return err == nil
+}
+
+func (p *NotificationPreferencesPayload) apply(prefs *repositories.NotificationPreferences) {
+	if p.BeerReceived != nil {
+		prefs.BeerReceived = *p.BeerReceived
+	}
+	if p.NewUser != nil {
+		prefs.NewUser = *p.NewUser
+	}
+	if p.Digest != nil {
+		prefs.Digest = *p.Digest
+	}
+	if p.DigestChannel != nil {
+		prefs.DigestChannel = *p.DigestChannel
+	}
+	if p.Locale != nil {
+		prefs.Locale = *p.Locale
+	}
+	if p.QuietHoursStart != nil {
+		prefs.QuietHoursStart = *p.QuietHoursStart
+	}
+	if p.QuietHoursEnd != nil {
+		prefs.QuietHoursEnd = *p.QuietHoursEnd
+	}
+	if p.Timezone != nil {
+		prefs.Timezone = *p.Timezone
+	}
+}
+
+// GetNotifications responds with the events the user wants to be notified of
+func (h *PreferencesHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+
+	prefs, err := h.preferencesRepo.FindNotifications(r.Context(), userID)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	respondJSON(w, r, prefs, http.StatusOK)
+}
+
+// UpdateNotifications sets the events the user wants to be notified of
+func (h *PreferencesHandler) UpdateNotifications(w http.ResponseWriter, r *http.Request) {
+	var payload NotificationPreferencesPayload
+	if err := decodeJSON(r, &payload, maxPreferencesPayloadBytes); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+	if errs := payload.validate(); len(errs) > 0 {
+		respondError(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed, "invalid preferences", errs)
+		return
+	}
+
+	userID, _ := r.Context().Value("userID").(string)
+	prefs, err := h.preferencesRepo.FindNotifications(r.Context(), userID)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+	payload.apply(prefs)
+	if errs := validateQuietHours(prefs); len(errs) > 0 {
+		respondError(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed, "invalid preferences", errs)
+		return
+	}
+
+	saved, err := h.preferencesRepo.SaveNotifications(r.Context(), userID, prefs)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	respondJSON(w, r, saved, http.StatusOK)
+}
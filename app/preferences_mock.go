@@ -0,0 +1,50 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"sync"
+)
+
+// mockPreferencesRepository keeps the preferences in memory, the users without any having the defaults
+type mockPreferencesRepository struct {
+	mu            sync.Mutex
+	notifications map[string]repos.NotificationPreferences
+}
+
+func newMockPreferencesRepository() *mockPreferencesRepository {
+	return &mockPreferencesRepository{notifications: map[string]repos.NotificationPreferences{}}
+}
+
+func (r *mockPreferencesRepository) FindNotifications(_ context.Context, userID string) (*repos.NotificationPreferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prefs, ok := r.notifications[userID]
+	if !ok {
+		return repos.DefaultNotificationPreferences(), nil
+	}
+	return &prefs, nil
+}
+
+func (r *mockPreferencesRepository) SaveNotifications(_ context.Context, userID string, prefs *repos.NotificationPreferences) (*repos.NotificationPreferences, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notifications[userID] = *prefs
+	saved := *prefs
+	return &saved, nil
+}
+
+func (r *mockPreferencesRepository) FindLocales(_ context.Context, userIDs []string) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	locales := map[string]string{}
+	for _, userID := range userIDs {
+		if prefs, ok := r.notifications[userID]; ok {
+			locales[userID] = prefs.Locale
+		}
+	}
+	return locales, nil
+}
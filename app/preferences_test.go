@@ -0,0 +1,164 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPreferencesHandler_notifications(t *testing.T) {
+	t.Run("expect every notification to be on by default", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newTestApplication().Routes().ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/users/me/preferences/notifications", nil))
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+
+		var prefs repos.NotificationPreferences
+		if err := json.NewDecoder(resp.Body).Decode(&prefs); err != nil {
+			t.Fatal(err)
+		}
+		if prefs != *repos.DefaultNotificationPreferences() {
+			t.Fatalf("expected the defaults, got %+v", prefs)
+		}
+	})
+
+	t.Run("expect PUT to change the events given only", func(t *testing.T) {
+		a := newTestApplication()
+		r := httptest.NewRequest("PUT", "/api/v1/users/me/preferences/notifications", strings.NewReader(`{"beer_received":false}`))
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusOK)
+
+		prefs, _ := a.preferencesRepository.FindNotifications(context.Background(), "1")
+		if prefs.BeerReceived || !prefs.NewUser || !prefs.Digest {
+			t.Fatalf("expected beer_received alone to be off, got %+v", prefs)
+		}
+	})
+
+	t.Run("expect an invalid payload to return 422", func(t *testing.T) {
+		r := httptest.NewRequest("PUT", "/api/v1/users/me/preferences/notifications", strings.NewReader(`{"digest":"no"}`))
+		w := httptest.NewRecorder()
+		newTestApplication().Routes().ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusUnprocessableEntity)
+	})
+
+	t.Run("expect an unsupported locale to return 422", func(t *testing.T) {
+		r := httptest.NewRequest("PUT", "/api/v1/users/me/preferences/notifications", strings.NewReader(`{"locale":"xx"}`))
+		w := httptest.NewRecorder()
+		newTestApplication().Routes().ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusUnprocessableEntity)
+	})
+
+	t.Run("expect an unknown digest channel to return 422", func(t *testing.T) {
+		r := httptest.NewRequest("PUT", "/api/v1/users/me/preferences/notifications", strings.NewReader(`{"digest_channel":"sms"}`))
+		w := httptest.NewRecorder()
+		newTestApplication().Routes().ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusUnprocessableEntity)
+	})
+
+	t.Run("expect PUT to set and clear the quiet hours", func(t *testing.T) {
+		a := newTestApplication()
+		for _, body := range []string{
+			`{"quiet_hours_start":"22:00","quiet_hours_end":"07:00","timezone":"Europe/Lisbon"}`,
+			`{"quiet_hours_start":"","quiet_hours_end":""}`,
+		} {
+			w := httptest.NewRecorder()
+			a.Routes().ServeHTTP(w, httptest.NewRequest("PUT", "/api/v1/users/me/preferences/notifications", strings.NewReader(body)))
+			assertStatusCode(t, w.Result(), http.StatusOK)
+
+			prefs, _ := a.preferencesRepository.FindNotifications(context.Background(), "1")
+			if hours, err := (quietHours{a.preferencesRepository}).QuietHours(context.Background(), "1"); err != nil ||
+				(hours == nil) != (prefs.QuietHoursStart == "") || prefs.Timezone != "Europe/Lisbon" {
+				t.Fatalf("unexpected quiet hours %+v, %+v, %v after %s", prefs, hours, err, body)
+			}
+		}
+	})
+
+	t.Run("expect invalid quiet hours to return 422", func(t *testing.T) {
+		for _, body := range []string{
+			`{"quiet_hours_start":"10pm","quiet_hours_end":"07:00"}`,
+			`{"quiet_hours_start":"22:00"}`,
+			`{"quiet_hours_start":"22:00","quiet_hours_end":"22:00"}`,
+			`{"timezone":"Europe/Atlantis"}`,
+		} {
+			w := httptest.NewRecorder()
+			newTestApplication().Routes().ServeHTTP(w, httptest.NewRequest("PUT", "/api/v1/users/me/preferences/notifications", strings.NewReader(body)))
+			assertStatusCode(t, w.Result(), http.StatusUnprocessableEntity)
+		}
+	})
+}
+
+func TestDeferredNotifications(t *testing.T) {
+	t.Run("expect the deferred sends to be taken once due", func(t *testing.T) {
+		store := deferredNotifications{newMockDeferredNotificationsRepository()}
+		now := time.Now()
+		job := notify.Job{Op: notify.OpSendToUser, UserID: "42", Notification: &notify.Notification{Title: "Cheers!"}, HistoryID: 3}
+		if err := store.Defer(context.Background(), job, now.Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+
+		if due, err := store.TakeDue(context.Background(), now); err != nil || len(due) != 0 {
+			t.Fatalf("expected nothing due yet, got %+v, %v", due, err)
+		}
+		due, err := store.TakeDue(context.Background(), now.Add(time.Hour))
+		if err != nil || len(due) != 1 || due[0].UserID != "42" || due[0].HistoryID != 3 || due[0].Notification.Title != "Cheers!" {
+			t.Fatalf("expected the send to be due, got %+v, %v", due, err)
+		}
+		if due, _ := store.TakeDue(context.Background(), now.Add(time.Hour)); len(due) != 0 {
+			t.Fatalf("expected the send to be taken once, got %+v", due)
+		}
+	})
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	t.Run("expect the users to be notified of the events they didn't opt out of, counting the others", func(t *testing.T) {
+		prefs := newMockPreferencesRepository()
+		prefs.SaveNotifications(context.Background(), "42", &repos.NotificationPreferences{NewUser: true, Digest: true})
+		fake := notify.NewFake()
+		m := metrics.NewFake()
+		notifier := notify.WithPreferences(fake, notificationPreferences{prefs}, countSuppressed(m, suppressedByPreference))
+
+		notifier.SendToUser(context.Background(), "42", notify.Notification{Event: notify.EventBeerReceived})
+		notifier.SendToUser(context.Background(), "42", notify.Notification{Event: notify.EventNewUser})
+		notifier.SendToUser(context.Background(), "7", notify.Notification{Event: notify.EventBeerReceived})
+
+		sent := fake.Sent()
+		if len(sent) != 2 || sent[0].Notification.Event != notify.EventNewUser || sent[1].UserID != "7" {
+			t.Fatalf("unexpected notifications %+v", sent)
+		}
+		if count := m.Counter(notificationsSuppressedMetric, metrics.Labels{"event": notify.EventBeerReceived, "reason": suppressedByPreference}); count != 1 {
+			t.Fatalf("expected 1 suppressed notification, got %v", count)
+		}
+	})
+}
+
+func TestRecipientLocales(t *testing.T) {
+	t.Run("expect the devices to get the locale of their users, if they set one", func(t *testing.T) {
+		a := newTestApplication()
+		for _, device := range []*repos.DeviceToken{{Token: "a", UserID: "2"}, {Token: "b", UserID: "2"}, {Token: "c", UserID: "3"}} {
+			a.devicesRepository.Upsert(context.Background(), device)
+		}
+		a.preferencesRepository.SaveNotifications(context.Background(), "2", &repos.NotificationPreferences{Locale: "pt"})
+
+		locales, err := a.recipientLocales().TokenLocales(context.Background(), []string{"a", "b", "c", "d"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(locales) != 3 || locales["a"] != "pt" || locales["b"] != "pt" || locales["c"] != "" {
+			t.Fatalf("expected the devices of user 2 in pt, got %v", locales)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// absoluteURL returns the URL of path, along with the query, on PUBLIC_BASE_URL. Without it, the
+// URL is built from the request, the X-Forwarded-Proto and X-Forwarded-Host headers set by the
+// proxies being only honored with TRUST_PROXY_HEADERS, and is left as the path without a request
+// either, ex.: for the links sent in the background.
+func (a *Application) absoluteURL(r *http.Request, path string, query url.Values) string {
+	base := a.conf.AppConfig.PublicBaseURL
+	if base == "" && r != nil {
+		base = requestBaseURL(r, a.conf.AppConfig.TrustProxyHeaders)
+	}
+
+	link := strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+// requestBaseURL returns the scheme and host the request was sent to, the ones the client sent to
+// the proxy when trustProxyHeaders
+func requestBaseURL(r *http.Request, trustProxyHeaders bool) string {
+	scheme, host := "http", r.Host
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if trustProxyHeaders {
+		if proto := firstHeaderValue(r, "X-Forwarded-Proto"); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwarded := firstHeaderValue(r, "X-Forwarded-Host"); forwarded != "" {
+			host = forwarded
+		}
+	}
+	return scheme + "://" + host
+}
+
+// firstHeaderValue returns the first of the comma separated values of the header, the one set by
+// the proxy closest to the client
+func firstHeaderValue(r *http.Request, name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(r.Header.Get(name), ",")[0]))
+}
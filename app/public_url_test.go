@@ -0,0 +1,66 @@
+package app
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestApplication_absoluteURL(t *testing.T) {
+	newApplication := func(base string, trustProxyHeaders bool) *Application {
+		a := newTestApplication()
+		a.conf.AppConfig.PublicBaseURL = base
+		a.conf.AppConfig.TrustProxyHeaders = trustProxyHeaders
+		return a
+	}
+
+	t.Run("expect the base and the path to be joined by a single slash", func(t *testing.T) {
+		for _, base := range []string{"https://api.appdoki.dev", "https://api.appdoki.dev/"} {
+			a := newApplication(base, false)
+			for _, path := range []string{"/api/v1/users", "api/v1/users"} {
+				if link := a.absoluteURL(nil, path, nil); link != "https://api.appdoki.dev/api/v1/users" {
+					t.Fatalf("expected %q and %q to be joined, got %s", base, path, link)
+				}
+			}
+		}
+		if link := newApplication("https://appdoki.dev/api/", false).absoluteURL(nil, "/users", url.Values{"page": {"2"}}); link != "https://appdoki.dev/api/users?page=2" {
+			t.Fatalf("expected the path of the base and the query to be kept, got %s", link)
+		}
+	})
+
+	t.Run("expect the public base URL to win over the request", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://10.0.0.7:4000/users", nil)
+		r.Header.Set("X-Forwarded-Host", "evil.example.com")
+
+		if link := newApplication("https://api.appdoki.dev", true).absoluteURL(r, "/api/v1/users", nil); link != "https://api.appdoki.dev/api/v1/users" {
+			t.Fatalf("expected the public base URL, got %s", link)
+		}
+	})
+
+	t.Run("expect the proxy headers to be honored only when trusted", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://10.0.0.7:4000/users", nil)
+		r.Header.Set("X-Forwarded-Proto", "https")
+		r.Header.Set("X-Forwarded-Host", "api.appdoki.dev, 10.0.0.7:4000")
+
+		if link := newApplication("", false).absoluteURL(r, "/api/v1/users", nil); link != "http://10.0.0.7:4000/api/v1/users" {
+			t.Fatalf("expected the host of the request, got %s", link)
+		}
+		if link := newApplication("", true).absoluteURL(r, "/api/v1/users", nil); link != "https://api.appdoki.dev/api/v1/users" {
+			t.Fatalf("expected the host the client sent to the proxy, got %s", link)
+		}
+
+		r.TLS = &tls.ConnectionState{}
+		r.Header.Del("X-Forwarded-Proto")
+		r.Header.Del("X-Forwarded-Host")
+		if link := newApplication("", true).absoluteURL(r, "/api/v1/users", nil); link != "https://10.0.0.7:4000/api/v1/users" {
+			t.Fatalf("expected the scheme of the connection without the headers, got %s", link)
+		}
+	})
+
+	t.Run("expect the path alone without a base nor a request", func(t *testing.T) {
+		if link := newApplication("", true).absoluteURL(nil, "beers/42", nil); link != "/beers/42" {
+			t.Fatalf("expected the path, got %s", link)
+		}
+	})
+}
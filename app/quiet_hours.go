@@ -0,0 +1,110 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// deferredFlushInterval is how often the notifications deferred past the quiet hours of their users
+// are sent once due
+const deferredFlushInterval = time.Minute
+
+// deferredBatchSize is how many deferred notifications are taken from the repository at once
+const deferredBatchSize = 500
+
+const notificationsDeferredMetric = "notifications_deferred_total"
+
+// flushDeferredNotifications sends the notifications whose quiet hours ended every interval until
+// ctx is done
+func (a *Application) flushDeferredNotifications(ctx context.Context, interval time.Duration) {
+	if a.quietHours == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		flushed, err := a.quietHours.Flush(ctx)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("error sending the deferred notifications: %v", err)
+		} else if flushed > 0 {
+			logging.FromContext(ctx).Infof("sent %d notifications deferred past quiet hours", flushed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// countDeferred counts the notifications deferred past the quiet hours of their user, by event
+func countDeferred(m metrics.Metrics) notify.DeferredHandler {
+	return func(_ context.Context, event string) {
+		m.IncCounter(notificationsDeferredMetric, metrics.Labels{"event": event})
+	}
+}
+
+// quietHours resolves the quiet hours of the users from their preferences
+type quietHours struct {
+	prefs repositories.PreferencesRepositoryInterface
+}
+
+func (q quietHours) QuietHours(ctx context.Context, userID string) (*notify.QuietHours, error) {
+	prefs, err := q.prefs.FindNotifications(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs.QuietHoursStart == "" {
+		return nil, nil
+	}
+
+	hours, err := notify.ParseQuietHours(prefs.QuietHoursStart, prefs.QuietHoursEnd, prefs.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	return &hours, nil
+}
+
+// deferredNotifications keeps the notifications deferred past the quiet hours in the deferred
+// notifications repository
+type deferredNotifications struct {
+	deferred repositories.DeferredNotificationsRepositoryInterface
+}
+
+func (d deferredNotifications) Defer(ctx context.Context, job notify.Job, until time.Time) error {
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return d.deferred.Create(ctx, &repositories.DeferredNotification{UserID: job.UserID, Job: encoded, SendAfter: until})
+}
+
+// TakeDue takes the due notifications a batch at a time, until a batch isn't full. The ones which
+// can't be decoded are logged and dropped.
+func (d deferredNotifications) TakeDue(ctx context.Context, now time.Time) ([]notify.Job, error) {
+	jobs := []notify.Job{}
+	for {
+		due, err := d.deferred.TakeDue(ctx, now, deferredBatchSize)
+		if err != nil {
+			return jobs, err
+		}
+		for _, deferred := range due {
+			var job notify.Job
+			if err := json.Unmarshal(deferred.Job, &job); err != nil {
+				logging.FromContext(ctx).Errorf("error decoding deferred notification %d, dropping it: %v", deferred.ID, err)
+				continue
+			}
+			jobs = append(jobs, job)
+		}
+		if len(due) < deferredBatchSize {
+			return jobs, nil
+		}
+	}
+}
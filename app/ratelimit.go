@@ -0,0 +1,70 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/ratelimit"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// rate limit route groups
+const (
+	readRateLimit  = "read"
+	writeRateLimit = "write"
+)
+
+// RateLimit limits the requests per authenticated user, or per client IP on unauthenticated
+// routes, according to the limit configured for the route group. It runs after JwtVerify,
+// so the user is already known: a.JwtVerify(a.RateLimit(group, handler)).
+func (a *Application) RateLimit(group string, next http.HandlerFunc) http.HandlerFunc {
+	limit := a.rateLimit(group)
+	if !limit.Enabled() {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := a.rateLimiter.Take(r.Context(), group+":"+a.rateLimitKey(r), limit)
+		if err != nil {
+			// fail open, the API should not go down with the rate limiter store
+			logging.FromContext(r.Context()).WithError(err).Error("rate limiter unavailable")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+
+		if !res.Allowed {
+			a.metrics.IncCounter(rateLimitedMetric, metrics.Labels{"group": group})
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+			respondError(w, http.StatusTooManyRequests, ErrCodeRateLimited, "rate limit exceeded", nil)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+// rateLimit returns the configured limit of a route group
+func (a *Application) rateLimit(group string) ratelimit.Limit {
+	conf := a.conf.RateLimit
+	if group == writeRateLimit {
+		return ratelimit.Limit{Rate: conf.WriteRate, Burst: conf.WriteBurst}
+	}
+	return ratelimit.Limit{Rate: conf.ReadRate, Burst: conf.ReadBurst}
+}
+
+// rateLimitKey identifies the client of a request by its user ID or, when unauthenticated, by its IP,
+// the one forwarded by the trusted proxies rather than theirs
+func (a *Application) rateLimitKey(r *http.Request) string {
+	if userID, ok := r.Context().Value("userID").(string); ok && userID != "" {
+		return "user:" + userID
+	}
+
+	if ip := clientIP(r, a.trustedProxies); ip != nil {
+		return "ip:" + ip.String()
+	}
+	return "ip:" + r.RemoteAddr
+}
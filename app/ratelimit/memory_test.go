@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestMemory(now *time.Time) *Memory {
+	m := NewMemory()
+	m.now = func() time.Time { return *now }
+	m.lastSweep = *now
+	return m
+}
+
+func TestMemory_Take(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 2}
+
+	t.Run("expect requests over the burst to be rejected until tokens refill", func(t *testing.T) {
+		now := time.Now()
+		m := newTestMemory(&now)
+
+		for i, remaining := range []int{1, 0} {
+			res, _ := m.Take(context.Background(), "user", limit)
+			if !res.Allowed || res.Remaining != remaining {
+				t.Fatalf("expected request %d to be allowed with %d remaining, got %+v", i, remaining, res)
+			}
+		}
+
+		res, _ := m.Take(context.Background(), "user", limit)
+		if res.Allowed {
+			t.Fatal("expected request over the burst to be rejected")
+		}
+		if res.RetryAfter != time.Second {
+			t.Fatalf("expected retry after 1s, got %s", res.RetryAfter)
+		}
+
+		now = now.Add(time.Second)
+		if res, _ := m.Take(context.Background(), "user", limit); !res.Allowed {
+			t.Fatal("expected request to be allowed after a token refilled")
+		}
+	})
+
+	t.Run("expect buckets to be independent", func(t *testing.T) {
+		now := time.Now()
+		m := newTestMemory(&now)
+
+		m.Take(context.Background(), "user1", Limit{Rate: 1, Burst: 1})
+		if res, _ := m.Take(context.Background(), "user2", Limit{Rate: 1, Burst: 1}); !res.Allowed {
+			t.Fatal("expected another key to have its own bucket")
+		}
+	})
+
+	t.Run("expect refilled buckets to be evicted", func(t *testing.T) {
+		now := time.Now()
+		m := newTestMemory(&now)
+
+		m.Take(context.Background(), "user1", limit)
+		m.Take(context.Background(), "user2", Limit{Rate: 0.001, Burst: 2})
+
+		now = now.Add(sweepInterval)
+		m.Take(context.Background(), "user3", limit)
+
+		if m.Len() != 2 {
+			t.Fatalf("expected 2 buckets after the sweep, got %d", m.Len())
+		}
+	})
+}
@@ -0,0 +1,32 @@
+// Package ratelimit defines the token bucket store rate limits are enforced with
+// and its implementations.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit allows Rate requests per second on average, with bursts of up to Burst requests
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled checks if the limit should be enforced
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store is implemented by every token bucket store
+type Store interface {
+	// Take takes a token from the bucket identified by key, creating it with the given limit if needed
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
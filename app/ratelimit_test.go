@@ -0,0 +1,111 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/ratelimit"
+	"appdoki-be/config"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApplication_RateLimit(t *testing.T) {
+	a := &Application{
+		conf: &config.Config{RateLimit: config.RateLimitConfig{
+			ReadRate:   1,
+			ReadBurst:  2,
+			WriteRate:  1,
+			WriteBurst: 1,
+		}},
+		metrics:     metrics.NewFake(),
+		rateLimiter: ratelimit.NewMemory(),
+	}
+	ok := func(w http.ResponseWriter, r *http.Request) {
+		respondNoContent(w, http.StatusNoContent)
+	}
+	router := prepareRouter(http.MethodGet, "/limited", a.RateLimit(writeRateLimit, ok))
+
+	newRequest := func(userID string) *http.Request {
+		r := httptest.NewRequest("GET", "/limited", nil)
+		if userID != "" {
+			r = r.WithContext(context.WithValue(r.Context(), "userID", userID))
+		}
+		return r
+	}
+
+	t.Run("expect requests over the limit to return 429", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest("1"))
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusNoContent)
+		if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "0" {
+			t.Fatalf("expected X-RateLimit-Remaining '0', got '%s'", remaining)
+		}
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest("1"))
+
+		resp = w.Result()
+		assertStatusCode(t, resp, http.StatusTooManyRequests)
+		assertJSONContentType(t, resp)
+		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "1" {
+			t.Fatalf("expected Retry-After '1', got '%s'", retryAfter)
+		}
+	})
+
+	t.Run("expect users to be limited independently", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest("2"))
+
+		assertStatusCode(t, w.Result(), http.StatusNoContent)
+	})
+
+	t.Run("expect unauthenticated requests to be limited by IP", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest(""))
+		assertStatusCode(t, w.Result(), http.StatusNoContent)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest(""))
+		assertStatusCode(t, w.Result(), http.StatusTooManyRequests)
+	})
+
+	t.Run("expect the clients behind a trusted proxy to be limited by their forwarded IP", func(t *testing.T) {
+		proxied := &Application{conf: a.conf, metrics: metrics.NewFake(), rateLimiter: ratelimit.NewMemory()}
+		trustedProxies, err := parseNetworks([]string{"10.0.0.0/8"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		proxied.trustedProxies = trustedProxies
+		proxiedRouter := prepareRouter(http.MethodGet, "/limited", proxied.RateLimit(writeRateLimit, ok))
+		forwardedFor := func(client string) int {
+			r := newRequest("")
+			r.RemoteAddr = "10.0.0.2:41000"
+			r.Header.Set("X-Forwarded-For", client)
+			w := httptest.NewRecorder()
+			proxiedRouter.ServeHTTP(w, r)
+			return w.Code
+		}
+
+		if status := forwardedFor("203.0.113.7"); status != http.StatusNoContent {
+			t.Fatalf("expected the first client to go through, got %d", status)
+		}
+		if status := forwardedFor("198.51.100.4"); status != http.StatusNoContent {
+			t.Fatalf("expected the second client to have its own bucket, got %d", status)
+		}
+		if status := forwardedFor("203.0.113.7"); status != http.StatusTooManyRequests {
+			t.Fatalf("expected the first client to be limited, got %d", status)
+		}
+	})
+
+	t.Run("expect read routes to have their own limit", func(t *testing.T) {
+		readRouter := prepareRouter(http.MethodGet, "/limited", a.RateLimit(readRateLimit, ok))
+
+		w := httptest.NewRecorder()
+		readRouter.ServeHTTP(w, newRequest("1"))
+
+		assertStatusCode(t, w.Result(), http.StatusNoContent)
+	})
+}
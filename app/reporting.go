@@ -0,0 +1,118 @@
+package app
+
+import (
+	"appdoki-be/app/buildinfo"
+	"appdoki-be/app/logging"
+	"appdoki-be/app/reporting"
+	"appdoki-be/config"
+	"context"
+	"fmt"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+)
+
+// newErrorReporter returns the Sentry reporter of the configured DSN, a no-op one when none is set
+func newErrorReporter(conf config.ErrorReportingConfig, clients *logging.HTTPClients) (reporting.ErrorReporter, error) {
+	if conf.DSN == "" {
+		return reporting.Noop{}, nil
+	}
+	return reporting.NewSentry(conf.DSN, conf.Environment, buildinfo.Version, clients)
+}
+
+// unreportedErrorCodes are the server errors responded on purpose, which aren't failures
+var unreportedErrorCodes = map[string]bool{
+	ErrCodeMaintenance:      true,
+	ErrCodeDeadlineExceeded: true,
+}
+
+// captureError reports err with the request scoped values of ctx. Users are only ever
+// identified by their ID, never by their email or name.
+func captureError(reporter reporting.ErrorReporter, ctx context.Context, err error, tags map[string]string) {
+	if reporter == nil {
+		return
+	}
+
+	reporter.CaptureException(ctx, err, reporting.Event{
+		RequestID: logging.RequestID(ctx),
+		UserID:    contextUserID(ctx),
+		Tags:      tags,
+	})
+}
+
+// contextUserID returns the authenticated user of ctx, either set by JwtVerify
+// or recorded for the middleware that ran before it
+func contextUserID(ctx context.Context) string {
+	if userID, ok := ctx.Value("userID").(string); ok {
+		return userID
+	}
+	if c, ok := ctx.Value(callerKey).(*caller); ok {
+		return c.UserID()
+	}
+	return ""
+}
+
+// reportingWriter carries the request a response answers, so respondError can report the server
+// errors along with it. Middleware wrapping the response writer further implement Unwrap.
+type reportingWriter struct {
+	http.ResponseWriter
+	r        *http.Request
+	router   *mux.Router
+	reporter reporting.ErrorReporter
+}
+
+func (rw *reportingWriter) Flush() {
+	flush(rw.ResponseWriter)
+}
+
+// unwrapper is implemented by the response writers wrapping another one
+type unwrapper interface {
+	Unwrap() http.ResponseWriter
+}
+
+// responseLogger returns the logger of the request w responds to, found through the writers
+// wrapping it, the standard one annotated with the request ID when there is none
+func responseLogger(w http.ResponseWriter) *log.Entry {
+	for {
+		switch ww := w.(type) {
+		case *reportingWriter:
+			return logging.FromContext(ww.r.Context())
+		case unwrapper:
+			w = ww.Unwrap()
+		default:
+			return logging.FromContext(context.Background()).WithField("request_id", w.Header().Get(logging.RequestIDHeader))
+		}
+	}
+}
+
+// reportResponseError reports the server error responded with w, when it answers a request
+// which went through the recovery middleware. Errors answering a request past its client deadline
+// aren't reported, they're the cancellation of its work and get replaced by deadline_exceeded.
+func reportResponseError(w http.ResponseWriter, statusCode int, code string, message string) {
+	if statusCode < http.StatusInternalServerError || unreportedErrorCodes[code] {
+		return
+	}
+
+	for {
+		switch ww := w.(type) {
+		case *reportingWriter:
+			captureError(ww.reporter, ww.r.Context(), fmt.Errorf("responded %d %s: %s", statusCode, code, message), map[string]string{
+				"route":  routeTemplate(ww.router, ww.r),
+				"method": ww.r.Method,
+				"status": strconv.Itoa(statusCode),
+				"code":   code,
+			})
+			return
+		case *deadlineWriter:
+			if ww.ctx.Err() == context.DeadlineExceeded {
+				return
+			}
+			w = ww.Unwrap()
+		case unwrapper:
+			w = ww.Unwrap()
+		default:
+			return
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package reporting
+
+import (
+	"context"
+	"sync"
+)
+
+// Capture is an error captured by the Fake reporter
+type Capture struct {
+	Err   error
+	Event Event
+}
+
+// Fake is an in-memory ErrorReporter implementation to be used in tests
+type Fake struct {
+	mu       sync.Mutex
+	captures []Capture
+}
+
+// NewFake returns a Fake reporter without captures
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) CaptureException(_ context.Context, err error, event Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.captures = append(f.captures, Capture{Err: err, Event: event})
+}
+
+func (f *Fake) Flush(context.Context) error {
+	return nil
+}
+
+// Captures returns the errors captured so far
+func (f *Fake) Captures() []Capture {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Capture(nil), f.captures...)
+}
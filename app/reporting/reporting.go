@@ -0,0 +1,28 @@
+// Package reporting defines the error tracker panics and server errors are reported to
+// and its implementations.
+package reporting
+
+import (
+	"context"
+)
+
+// Event holds what is known of the circumstances of a reported error. Users are identified
+// by their ID only, their email or name must never be reported.
+type Event struct {
+	RequestID string
+	UserID    string
+	Tags      map[string]string
+}
+
+// ErrorReporter is implemented by every error tracker
+type ErrorReporter interface {
+	CaptureException(ctx context.Context, err error, event Event)
+	// Flush waits for the reports being sent, or for ctx to expire
+	Flush(ctx context.Context) error
+}
+
+// Noop is an ErrorReporter implementation that discards every error
+type Noop struct{}
+
+func (Noop) CaptureException(context.Context, error, Event) {}
+func (Noop) Flush(context.Context) error                    { return nil }
@@ -0,0 +1,271 @@
+package reporting
+
+import (
+	"appdoki-be/app/logging"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sentryTimeout    = 10 * time.Second
+	maxSentryPending = 100
+	maxStackFrames   = 64
+)
+
+// Sentry implements ErrorReporter by posting the errors to the envelope endpoint of a Sentry
+// project, an envelope of a single event each. Reports are sent in the background, those exceeding
+// the pending limit are dropped.
+type Sentry struct {
+	client      *http.Client
+	envelopeURL string
+	dsn         string
+	auth        string
+	environment string
+	release     string
+	pending     chan struct{}
+	wg          sync.WaitGroup
+}
+
+// NewSentry returns a Sentry reporter for the project of the DSN
+// (ex.: https://public@o0.ingest.sentry.io/123)
+func NewSentry(dsn string, environment string, release string, clients *logging.HTTPClients) (*Sentry, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Sentry DSN: %w", err)
+	}
+	projectID := path.Base(u.Path)
+	if u.User == nil || u.User.Username() == "" || u.Host == "" || projectID == "." || projectID == "/" {
+		return nil, fmt.Errorf("invalid Sentry DSN: expected <scheme>://<key>@<host>/<project>")
+	}
+
+	return &Sentry{
+		client:      clients.New(sentryTimeout),
+		envelopeURL: fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, strings.TrimSuffix(path.Dir(u.Path), "/"), projectID),
+		dsn:         dsn,
+		auth:        fmt.Sprintf("Sentry sentry_version=7, sentry_client=appdoki-be/%s, sentry_key=%s", release, u.User.Username()),
+		environment: environment,
+		release:     release,
+		pending:     make(chan struct{}, maxSentryPending),
+	}, nil
+}
+
+// sentryEnvelopeHeader heads the envelope, the header of its event item and the event following
+type sentryEnvelopeHeader struct {
+	EventID string `json:"event_id"`
+	SentAt  string `json:"sent_at"`
+	DSN     string `json:"dsn"`
+}
+
+type sentryItemHeader struct {
+	Type   string `json:"type"`
+	Length int    `json:"length"`
+}
+
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Exception   sentryExceptions  `json:"exception"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	User        *sentryUser       `json:"user,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string           `json:"type"`
+	Value      string           `json:"value"`
+	Stacktrace sentryStacktrace `json:"stacktrace"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	Module   string `json:"module"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// sentryUser only ever carries the user ID
+type sentryUser struct {
+	ID string `json:"id"`
+}
+
+func (s *Sentry) CaptureException(ctx context.Context, err error, event Event) {
+	payload := s.newEvent(err, event, stackFrames(2))
+	logger := logging.FromContext(ctx).WithField("request_id", event.RequestID)
+
+	select {
+	case s.pending <- struct{}{}:
+	default:
+		logger.Warn("dropped the error report, too many are being sent")
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.pending }()
+
+		if err := s.send(payload); err != nil {
+			logger.WithError(err).Warn("could not report the error to Sentry")
+		}
+	}()
+}
+
+// Flush waits for the reports being sent, or for ctx to expire
+func (s *Sentry) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Sentry) newEvent(err error, event Event, frames []sentryFrame) *sentryEvent {
+	payload := &sentryEvent{
+		EventID:     strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Level:       "error",
+		Platform:    "go",
+		Environment: s.environment,
+		Release:     s.release,
+		Exception: sentryExceptions{Values: []sentryException{{
+			Type:       fmt.Sprintf("%T", err),
+			Value:      err.Error(),
+			Stacktrace: sentryStacktrace{Frames: frames},
+		}}},
+		Tags: map[string]string{},
+	}
+	for name, value := range event.Tags {
+		payload.Tags[name] = value
+	}
+	if event.RequestID != "" {
+		payload.Tags["request_id"] = event.RequestID
+	}
+	if event.UserID != "" {
+		payload.User = &sentryUser{ID: event.UserID}
+	}
+	return payload
+}
+
+// envelope returns the envelope of the event: its header, the header of the event item and the
+// event, each on a line of its own
+func (s *Sentry) envelope(payload *sentryEvent) ([]byte, error) {
+	event, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	header, err := json.Marshal(sentryEnvelopeHeader{
+		EventID: payload.EventID,
+		SentAt:  time.Now().UTC().Format(time.RFC3339),
+		DSN:     s.dsn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	item, err := json.Marshal(sentryItemHeader{Type: "event", Length: len(event)})
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	for _, line := range [][]byte{header, item, event} {
+		body.Write(line)
+		body.WriteByte('\n')
+	}
+	return body.Bytes(), nil
+}
+
+func (s *Sentry) send(payload *sentryEvent) error {
+	body, err := s.envelope(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.envelopeURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", s.auth)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// stackFrames returns the frames of the calling goroutine, skipping the given number of
+// callers, oldest first as Sentry expects them
+func stackFrames(skip int) []sentryFrame {
+	pcs := make([]uintptr, maxStackFrames)
+	n := runtime.Callers(skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []sentryFrame
+	for {
+		frame, more := frames.Next()
+		module, function := splitFunctionName(frame.Function)
+		stack = append(stack, sentryFrame{
+			Function: function,
+			Module:   module,
+			Filename: path.Base(frame.File),
+			AbsPath:  frame.File,
+			Lineno:   frame.Line,
+			InApp:    strings.HasPrefix(module, "appdoki-be"),
+		})
+		if !more {
+			break
+		}
+	}
+
+	for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+		stack[i], stack[j] = stack[j], stack[i]
+	}
+	return stack
+}
+
+// splitFunctionName splits appdoki-be/app.(*Application).Routes into its package and function
+func splitFunctionName(name string) (string, string) {
+	lastSlash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[lastSlash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	dot += lastSlash + 1
+	return name[:dot], name[dot+1:]
+}
@@ -0,0 +1,101 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewSentry(t *testing.T) {
+	t.Run("expect the envelope endpoint to be derived from the DSN", func(t *testing.T) {
+		s, err := NewSentry("https://public@sentry.example.com/prefix/42", "production", "1.0.0", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.envelopeURL != "https://sentry.example.com/prefix/api/42/envelope/" {
+			t.Fatalf("unexpected envelope URL '%s'", s.envelopeURL)
+		}
+		if !strings.Contains(s.auth, "sentry_key=public") {
+			t.Fatalf("expected the auth header to carry the key, got '%s'", s.auth)
+		}
+	})
+
+	t.Run("expect a DSN without key or project to fail", func(t *testing.T) {
+		for _, dsn := range []string{"https://sentry.example.com/42", "https://public@sentry.example.com", "::"} {
+			if _, err := NewSentry(dsn, "", "", nil); err == nil {
+				t.Fatalf("expected %q to fail", dsn)
+			}
+		}
+	})
+}
+
+func TestSentry_CaptureException(t *testing.T) {
+	received := make(chan map[string]interface{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/42/envelope/" || !strings.HasPrefix(r.Header.Get("X-Sentry-Auth"), "Sentry ") ||
+			r.Header.Get("Content-Type") != "application/x-sentry-envelope" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+		if len(lines) != 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var header, item, event map[string]interface{}
+		_ = json.Unmarshal([]byte(lines[0]), &header)
+		_ = json.Unmarshal([]byte(lines[1]), &item)
+		_ = json.Unmarshal([]byte(lines[2]), &event)
+		if header["event_id"] != event["event_id"] || item["type"] != "event" || item["length"] != float64(len(lines[2])) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- event
+	}))
+	defer srv.Close()
+
+	s, err := NewSentry(strings.Replace(srv.URL, "://", "://public@", 1)+"/42", "test", "1.0.0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("expect the error to be posted with its tags and the user ID only", func(t *testing.T) {
+		s.CaptureException(context.Background(), errors.New("boom"), Event{
+			RequestID: "req-1",
+			UserID:    "user-1",
+			Tags:      map[string]string{"route": "/users"},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.Flush(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		var event map[string]interface{}
+		select {
+		case event = <-received:
+		default:
+			t.Fatal("expected the envelope of the event to be received")
+		}
+		tags := event["tags"].(map[string]interface{})
+		if tags["route"] != "/users" || tags["request_id"] != "req-1" {
+			t.Fatalf("unexpected tags %v", tags)
+		}
+		user := event["user"].(map[string]interface{})
+		if len(user) != 1 || user["id"] != "user-1" {
+			t.Fatalf("expected the user to be identified by ID only, got %v", user)
+		}
+		values := event["exception"].(map[string]interface{})["values"].([]interface{})
+		if values[0].(map[string]interface{})["value"] != "boom" {
+			t.Fatalf("unexpected exception %v", values)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"appdoki-be/app/filter"
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/reporting"
+	repos "appdoki-be/app/repositories"
+	"context"
+	"errors"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecoveryMiddleware_reporting(t *testing.T) {
+	t.Run("expect a panic to be reported with the route and the authenticated user", func(t *testing.T) {
+		a := newTestApplication()
+		reporter := reporting.NewFake()
+		router := mux.NewRouter()
+		router.Path("/users/{id}").HandlerFunc(a.JwtVerify(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}))
+		h := recoveryMiddleware(log.StandardLogger(), metrics.NewFake(), reporter, router)(router)
+
+		r := httptest.NewRequest("GET", "/users/42", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusInternalServerError)
+
+		captures := reporter.Captures()
+		if len(captures) != 1 {
+			t.Fatalf("expected 1 capture, got %d", len(captures))
+		}
+		capture := captures[0]
+		if capture.Err.Error() != "panic: boom" {
+			t.Fatalf("unexpected error '%v'", capture.Err)
+		}
+		if capture.Event.UserID != "1" {
+			t.Fatalf("expected the user ID to be reported, got '%s'", capture.Event.UserID)
+		}
+		if capture.Event.Tags["route"] != "/users/{id}" || capture.Event.Tags["panic"] != "true" {
+			t.Fatalf("unexpected tags %v", capture.Event.Tags)
+		}
+	})
+}
+
+func TestRespondError_reporting(t *testing.T) {
+	t.Run("expect a server error to be reported with the request scoped values", func(t *testing.T) {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).getAllImpl = func(_ context.Context, _ filter.Filter) ([]*repos.User, error) {
+			return nil, errors.New("connection refused")
+		}
+
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		r.Header.Set("X-Request-ID", "request-1")
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusInternalServerError)
+
+		captures := a.errorReporter.(*reporting.Fake).Captures()
+		if len(captures) != 1 {
+			t.Fatalf("expected 1 capture, got %d", len(captures))
+		}
+		event := captures[0].Event
+		if event.RequestID != "request-1" || event.UserID != "1" {
+			t.Fatalf("expected the request and user IDs, got %+v", event)
+		}
+		expected := map[string]string{"route": "/api/v1/users", "method": "GET", "status": "500", "code": ErrCodeInternal}
+		for name, value := range expected {
+			if event.Tags[name] != value {
+				t.Fatalf("expected tag %s '%s', got '%s'", name, value, event.Tags[name])
+			}
+		}
+	})
+
+	t.Run("expect client errors and maintenance not to be reported", func(t *testing.T) {
+		a := newTestApplication()
+		routes := a.Routes()
+
+		r := httptest.NewRequest("GET", "/api/v1/nope", nil)
+		routes.ServeHTTP(httptest.NewRecorder(), r)
+
+		a.maintenance.Set(true)
+		r = httptest.NewRequest("GET", "/api/v1/users", nil)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusServiceUnavailable)
+		if captures := a.errorReporter.(*reporting.Fake).Captures(); len(captures) != 0 {
+			t.Fatalf("expected no capture, got %v", captures)
+		}
+	})
+
+	t.Run("expect errors responded outside of a request not to be reported", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		respondInternalError(w)
+
+		assertStatusCode(t, w.Result(), http.StatusInternalServerError)
+	})
+}
+
+func TestWorkerGroup_reporting(t *testing.T) {
+	t.Run("expect a panicking worker to be reported with the user who started it", func(t *testing.T) {
+		reporter := reporting.NewFake()
+		g := newWorkerGroup(reporter)
+
+		ctx := context.WithValue(context.Background(), "userID", "42")
+		g.Go(ctx, func(ctx context.Context) {
+			panic(errors.New("boom"))
+		})
+
+		stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := g.Stop(stopCtx); err != nil {
+			t.Fatal(err)
+		}
+
+		captures := reporter.Captures()
+		if len(captures) != 1 {
+			t.Fatalf("expected 1 capture, got %d", len(captures))
+		}
+		if captures[0].Event.UserID != "42" || captures[0].Event.Tags["worker"] != "true" {
+			t.Fatalf("unexpected capture %+v", captures[0].Event)
+		}
+		if !strings.Contains(captures[0].Err.Error(), "boom") {
+			t.Fatalf("unexpected error '%v'", captures[0].Err)
+		}
+	})
+}
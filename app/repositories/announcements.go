@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"time"
+)
+
+// Announcement audiences
+const (
+	AudienceAll   = "all"
+	AudienceRole  = "role"
+	AudienceUsers = "users"
+)
+
+// Announcement statuses
+const (
+	AnnouncementSending = "sending"
+	AnnouncementSent    = "sent"
+	AnnouncementFailed  = "failed"
+)
+
+// Announcement is a push sent by an admin to all the users, the ones of a role or a list of users
+type Announcement struct {
+	ID       int64          `json:"id" db:"id"`
+	Title    string         `json:"title" db:"title"`
+	Body     string         `json:"body" db:"body"`
+	DeepLink string         `json:"deep_link,omitempty" db:"deep_link"`
+	Audience string         `json:"audience" db:"audience"`
+	Role     string         `json:"role,omitempty" db:"role"`
+	UserIDs  pq.StringArray `json:"user_ids,omitempty" db:"user_ids"`
+	// Channel is how it was sent: to a topic or multicast to the devices of its audience
+	Channel string `json:"channel" db:"channel"`
+	Status  string `json:"status" db:"status"`
+	// Targeted is how many devices it was sent to, Delivered how many FCM and Web Push accepted it
+	// for, unknown for the topic sends
+	Targeted  int        `json:"targeted" db:"targeted"`
+	Delivered *int       `json:"delivered" db:"delivered"`
+	CreatedBy *string    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	SentAt    *time.Time `json:"sent_at" db:"sent_at"`
+}
+
+// AnnouncementsRepositoryInterface defines the set of announcements related methods available
+type AnnouncementsRepositoryInterface interface {
+	Create(ctx context.Context, announcement *Announcement) (*Announcement, error)
+	Finish(ctx context.Context, ID int64, status string, delivered *int) error
+	List(ctx context.Context, limit int) ([]*Announcement, error)
+}
+
+// AnnouncementsRepository implements AnnouncementsRepositoryInterface
+type AnnouncementsRepository struct {
+	db *sqlx.DB
+}
+
+// NewAnnouncementsRepository returns a configured AnnouncementsRepository object
+func NewAnnouncementsRepository(db *sqlx.DB) *AnnouncementsRepository {
+	return &AnnouncementsRepository{db: db}
+}
+
+const announcementColumns = `id, title, body, deep_link, audience, role, user_ids, channel, status, targeted, delivered,
+	created_by, created_at, sent_at`
+
+// Create keeps the announcement, being sent
+func (r *AnnouncementsRepository) Create(ctx context.Context, announcement *Announcement) (*Announcement, error) {
+	stmt := `INSERT INTO announcements (title, body, deep_link, audience, role, user_ids, channel, targeted, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING ` + announcementColumns
+
+	userIDs := announcement.UserIDs
+	if userIDs == nil {
+		userIDs = pq.StringArray{}
+	}
+	created := &Announcement{}
+	err := r.db.GetContext(ctx, created, stmt, announcement.Title, announcement.Body, announcement.DeepLink,
+		announcement.Audience, announcement.Role, userIDs, announcement.Channel, announcement.Targeted, announcement.CreatedBy)
+	if err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return created, nil
+}
+
+// Finish sets the outcome of the send of the announcement
+func (r *AnnouncementsRepository) Finish(ctx context.Context, ID int64, status string, delivered *int) error {
+	stmt := "UPDATE announcements SET status = $2, delivered = $3, sent_at = now() WHERE id = $1"
+	if _, err := r.db.ExecContext(ctx, stmt, ID, status, delivered); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
+
+// List returns up to limit announcements, the latest first
+func (r *AnnouncementsRepository) List(ctx context.Context, limit int) ([]*Announcement, error) {
+	stmt := "SELECT " + announcementColumns + " FROM announcements ORDER BY id DESC LIMIT $1"
+
+	announcements := []*Announcement{}
+	if err := r.db.SelectContext(ctx, &announcements, stmt, limit); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return announcements, nil
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+)
+
+// Audited actions
+const (
+	AuditUserDeactivated  = "user.deactivated"
+	AuditUserDeleted      = "user.deleted"
+	AuditUserRoleChanged  = "user.role_changed"
+	AuditNotificationTest = "notification.test"
+	AuditAnnouncement     = "announcement.sent"
+	AuditWebhookCreated   = "webhook.created"
+	AuditWebhookDeleted   = "webhook.deleted"
+	AuditLogLevelChanged  = "log_level.changed"
+)
+
+// AuditEntry records an action taken by a user on a resource
+type AuditEntry struct {
+	ActorID  string                 `db:"actor_id"`
+	Action   string                 `db:"action"`
+	TargetID string                 `db:"target_id"`
+	Details  map[string]interface{} `db:"-"`
+}
+
+// AuditRepositoryInterface defines the set of audit log related methods available
+type AuditRepositoryInterface interface {
+	Record(ctx context.Context, entries []*AuditEntry) error
+}
+
+// AuditRepository implements AuditRepositoryInterface
+type AuditRepository struct {
+	db  queryer
+	uow *UnitOfWork
+}
+
+// NewAuditRepository returns a configured AuditRepository object
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{db: db, uow: NewUnitOfWork(db)}
+}
+
+// WithUnitOfWork returns a copy of the repository running its transactions in uow
+func (r *AuditRepository) WithUnitOfWork(uow *UnitOfWork) *AuditRepository {
+	return &AuditRepository{db: r.db, uow: uow}
+}
+
+// Record appends the entries to the audit log, all of them or none
+func (r *AuditRepository) Record(ctx context.Context, entries []*AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	return r.uow.DoIdempotent(ctx, func(repos Repositories) error {
+		stmt := "INSERT INTO audit_log (actor_id, action, target_id, details) VALUES ($1, $2, $3, $4)"
+		for _, entry := range entries {
+			var details *string
+			if len(entry.Details) > 0 {
+				encoded, err := json.Marshal(entry.Details)
+				if err != nil {
+					return err
+				}
+				value := string(encoded)
+				details = &value
+			}
+			if _, err := repos.Audit.db.ExecContext(ctx, stmt, entry.ActorID, entry.Action, entry.TargetID, details); err != nil {
+				return parseError(ctx, err)
+			}
+		}
+		return nil
+	})
+}
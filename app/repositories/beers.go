@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/jmoiron/sqlx"
 	"strconv"
+	"time"
 )
 
 type BeerTransferFeedItem struct {
@@ -43,20 +44,36 @@ func (o *BeerFeedPaginationOptions) SetLtOperator() {
 	o.op = "<"
 }
 
-// BeersRepositoryInterface defines the set of User related methods available
+// BeersRepositoryInterface defines the set of User related methods available.
+// Collections are returned as empty slices, never nil, when nothing is found.
 type BeersRepositoryInterface interface {
 	GetBeerTransfer(ctx context.Context, id int) (*BeerTransferFeedItem, error)
 	GetBeerTransfers(ctx context.Context, options *BeerFeedPaginationOptions) ([]BeerTransferFeedItem, error)
+	LastModified(ctx context.Context) (time.Time, error)
+	ReceivedBetween(ctx context.Context, from time.Time, to time.Time) ([]*BeersReceived, error)
+}
+
+// BeersReceived sums up the beers a user received over a period, from how many givers
+type BeersReceived struct {
+	UserID string `db:"user_id"`
+	Beers  int    `db:"beers"`
+	Givers int    `db:"givers"`
 }
 
 // BeersRepository implements UsersRepositoryInterface
 type BeersRepository struct {
-	db *sqlx.DB
+	db dbPair
 }
 
 // NewBeersRepository returns a configured BeersRepository object
 func NewBeersRepository(db *sqlx.DB) *BeersRepository {
-	return &BeersRepository{db: db}
+	return &BeersRepository{db: newDBPair(db)}
+}
+
+// WithReplica returns a copy of the repository reading from the replica, unless the context reads
+// fresh. A nil replica reads from the primary.
+func (r *BeersRepository) WithReplica(replica *sqlx.DB) *BeersRepository {
+	return &BeersRepository{db: r.db.withReplica(replica)}
 }
 
 const baseBeerTransferQuery = `
@@ -78,7 +95,7 @@ const baseBeerTransferQuery = `
 
 func (r *BeersRepository) GetBeerTransfer(ctx context.Context, id int) (*BeerTransferFeedItem, error) {
 	query := baseBeerTransferQuery + " WHERE btf.id = $1;"
-	row := r.db.QueryRowxContext(ctx, query, id)
+	row := r.db.reader(ctx).QueryRowxContext(ctx, query, id)
 
 	var t BeerTransferFeedItem
 	err := row.Scan(
@@ -95,12 +112,33 @@ func (r *BeersRepository) GetBeerTransfer(ctx context.Context, id int) (*BeerTra
 		&t.ID)
 
 	if err != nil {
-		return nil, parseError(err)
+		return nil, parseError(ctx, err)
 	}
 
 	return &t, nil
 }
 
+// LastModified returns when the feed last changed, which includes the users giving and receiving beers
+func (r *BeersRepository) LastModified(ctx context.Context) (time.Time, error) {
+	return collectionsLastModified(ctx, r.db.reader(ctx), "beer_transfers", "users")
+}
+
+// ReceivedBetween sums up the beers each active user received from the given time until to, excluded
+func (r *BeersRepository) ReceivedBetween(ctx context.Context, from time.Time, to time.Time) ([]*BeersReceived, error) {
+	stmt := `SELECT btf.taker_id AS user_id, sum(btf.beers) AS beers, count(DISTINCT btf.giver_id) AS givers
+		FROM beer_transfers btf
+		JOIN users receiver ON receiver.id = btf.taker_id
+		WHERE btf.given_at >= $1 AND btf.given_at < $2 AND receiver.deactivated_at IS NULL
+		GROUP BY btf.taker_id ORDER BY btf.taker_id`
+
+	received := []*BeersReceived{}
+	if err := r.db.reader(ctx).SelectContext(ctx, &received, stmt, from.UTC(), to.UTC()); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return received, nil
+}
+
+// GetBeerTransfers fetches a page of the beer transfers feed, returns an empty slice if no transfer matches
 func (r *BeersRepository) GetBeerTransfers(ctx context.Context, options *BeerFeedPaginationOptions) ([]BeerTransferFeedItem, error) {
 	var whereClause string
 	var limitClause string
@@ -115,15 +153,15 @@ func (r *BeersRepository) GetBeerTransfers(ctx context.Context, options *BeerFee
 	var rows *sqlx.Rows
 	var err error
 	if len(options.GivenAt) > 0 {
-		rows, err = r.db.QueryxContext(ctx, query, options.GivenAt)
+		rows, err = r.db.reader(ctx).QueryxContext(ctx, query, options.GivenAt)
 	} else {
-		rows, err = r.db.QueryxContext(ctx, query)
+		rows, err = r.db.reader(ctx).QueryxContext(ctx, query)
 	}
 	if err != nil {
-		return nil, parseError(err)
+		return nil, parseError(ctx, err)
 	}
 
-	var beerFeed []BeerTransferFeedItem
+	beerFeed := []BeerTransferFeedItem{}
 	for rows.Next() {
 		var t BeerTransferFeedItem
 
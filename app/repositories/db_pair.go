@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+)
+
+type readFreshKey struct{}
+
+// ReadFresh returns a copy of ctx whose reads go to the primary database rather than its replica,
+// for the reads which must see a write just made (ex.: reading back a row just inserted), the
+// replica lagging behind the primary
+func ReadFresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readFreshKey{}, true)
+}
+
+// readsFresh checks if the reads of ctx must go to the primary database
+func readsFresh(ctx context.Context) bool {
+	fresh, _ := ctx.Value(readFreshKey{}).(bool)
+	return fresh
+}
+
+// dbPair routes the statements of a repository: the writes, and the reads which must see them, to
+// the primary database, the other reads to its read replica when there is one. Bound to the
+// transaction of a unit of work, every statement runs in it.
+type dbPair struct {
+	primary queryer
+	replica queryer
+}
+
+// newDBPair returns the pair of the primary database, without a replica
+func newDBPair(primary queryer) dbPair {
+	return dbPair{primary: primary}
+}
+
+// withReplica returns a copy of the pair reading from replica, the primary when nil
+func (p dbPair) withReplica(replica *sqlx.DB) dbPair {
+	if replica == nil {
+		return dbPair{primary: p.primary}
+	}
+	return dbPair{primary: p.primary, replica: replica}
+}
+
+// reader returns where the reads of ctx go: the replica, unless there is none or ctx reads fresh
+func (p dbPair) reader(ctx context.Context) queryer {
+	if p.replica == nil || readsFresh(ctx) {
+		return p.primary
+	}
+	return p.replica
+}
+
+// writer returns where the writes go, the primary
+func (p dbPair) writer() queryer {
+	return p.primary
+}
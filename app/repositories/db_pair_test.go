@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"appdoki-be/app/filter"
+	"appdoki-be/app/testsupport"
+	"context"
+	"github.com/jmoiron/sqlx"
+	"testing"
+)
+
+func TestDBPair(t *testing.T) {
+	ctx := context.Background()
+	ana := &User{ID: "1", Name: "Ana", Email: "ana@cloudoki.com"}
+	rui := &User{ID: "2", Name: "Rui", Email: "rui@cloudoki.com"}
+
+	// newRepositories returns the repositories of a primary and a replica, two distinct schemas,
+	// Ana being in the primary only and Rui in the replica only, for the reads to tell them apart
+	newRepositories := func(t *testing.T) (*UsersRepository, *BeersRepository) {
+		t.Helper()
+		primary, replica := testsupport.NewDB(t), testsupport.NewDB(t)
+		if _, _, err := NewUsersRepository(primary).FindOrCreateUser(ctx, ana); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := NewUsersRepository(replica).FindOrCreateUser(ctx, rui); err != nil {
+			t.Fatal(err)
+		}
+		return NewUsersRepository(primary).WithReplica(replica), NewBeersRepository(primary).WithReplica(replica)
+	}
+
+	t.Run("expect the reads to go to the replica when there is one, but the fresh ones", func(t *testing.T) {
+		primary, replica := &sqlx.DB{}, &sqlx.DB{}
+
+		if pair := newDBPair(primary).withReplica(nil); pair.reader(ctx) != queryer(primary) || pair.writer() != queryer(primary) {
+			t.Fatalf("expected the primary only, got %+v", pair)
+		}
+		pair := newDBPair(primary).withReplica(replica)
+		if pair.reader(ctx) != queryer(replica) || pair.reader(ReadFresh(ctx)) != queryer(primary) || pair.writer() != queryer(primary) {
+			t.Fatalf("expected the reads to go to the replica but the fresh ones, got %+v", pair)
+		}
+	})
+
+	t.Run("expect the reads to go to the replica, the ones reading fresh to the primary", func(t *testing.T) {
+		users, _ := newRepositories(t)
+
+		all, err := users.GetAll(ctx, filter.Filter{})
+		if err != nil || len(all) != 1 || all[0].ID != "2" {
+			t.Fatalf("expected the users of the replica, got %+v, %v", all, err)
+		}
+		if user, err := users.FindByID(ctx, "1"); user != nil || err != nil {
+			t.Fatalf("expected the user of the primary not to be read, got %+v, %v", user, err)
+		}
+		if user, err := users.FindByID(ReadFresh(ctx), "1"); user == nil || err != nil {
+			t.Fatalf("expected the fresh read to find the user of the primary, got %+v, %v", user, err)
+		}
+	})
+
+	t.Run("expect the writes and FindOrCreateUser to go to the primary", func(t *testing.T) {
+		users, beers := newRepositories(t)
+
+		if _, created, err := users.FindOrCreateUser(ctx, ana); err != nil || created {
+			t.Fatalf("expected the user of the primary to be found, got %v, %v", created, err)
+		}
+		if _, created, err := users.FindOrCreateUser(ctx, rui); err != nil || !created {
+			t.Fatalf("expected the user to be created in the primary, got %v, %v", created, err)
+		}
+		id, err := users.AddBeerTransfer(ctx, "1", "2", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if transfer, err := beers.GetBeerTransfer(ctx, id); err == nil {
+			t.Fatalf("expected the transfer not to be in the replica, got %+v", transfer)
+		}
+		if transfer, err := beers.GetBeerTransfer(ReadFresh(ctx), id); err != nil || transfer.Beers != 3 {
+			t.Fatalf("expected the fresh read to find the transfer, got %+v, %v", transfer, err)
+		}
+	})
+
+	t.Run("expect the reads of a unit of work to go to its transaction, on the primary", func(t *testing.T) {
+		users, _ := newRepositories(t)
+
+		err := users.uow.Do(ctx, func(repos Repositories) error {
+			user, err := repos.Users.FindByID(ctx, "1")
+			if user == nil || err != nil {
+				t.Fatalf("expected the user of the primary, got %+v, %v", user, err)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
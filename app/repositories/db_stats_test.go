@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"appdoki-be/app/testsupport"
+	"context"
+	"testing"
+)
+
+func TestDBStatsRepository_TableStats(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expect the estimates of the tables of the schema, the unknown ones omitted", func(t *testing.T) {
+		db := testsupport.NewDB(t)
+		users := NewUsersRepository(db)
+		for _, user := range []*User{{ID: "1", Name: "Ana", Email: "ana@cloudoki.com"}, {ID: "2", Name: "Rui", Email: "rui@cloudoki.com"}} {
+			if _, _, err := users.FindOrCreateUser(ctx, user); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := db.ExecContext(ctx, "ANALYZE users"); err != nil {
+			t.Fatal(err)
+		}
+
+		stats, err := NewDBStatsRepository(db).TableStats(ctx, []string{"users", "beer_transfers", "missing"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(stats) != 2 || stats[0].Name != "beer_transfers" || stats[1].Name != "users" {
+			t.Fatalf("expected the known tables sorted by name, got %+v", stats)
+		}
+		if stats[0].Rows != 0 || stats[1].Rows != 2 || stats[1].SizeBytes <= 0 {
+			t.Fatalf("expected the rows and size of the tables, got %+v", stats)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// DeadLetter is a notification send given up on once its attempts were exhausted, kept to be replayed
+type DeadLetter struct {
+	ID int64 `json:"id" db:"id"`
+	// Channel is the notifier which gave up on the send: fcm, webpush, slack, email or webhook, or queue for
+	// the sends left in the queue
+	Channel string `json:"channel" db:"channel"`
+	Op      string `json:"op" db:"op"`
+	Target  string `json:"target" db:"target"`
+	// Job is the send serialized
+	Job       json.RawMessage `json:"job" db:"job"`
+	Error     string          `json:"error" db:"error"`
+	Attempts  int             `json:"attempts" db:"attempts"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+	// Pending dead letters were left as the application stopped rather than given up on, replayed
+	// once it starts again
+	Pending bool `json:"pending" db:"pending"`
+}
+
+// DeadLettersRepositoryInterface defines the set of notification dead letters related methods available
+type DeadLettersRepositoryInterface interface {
+	Create(ctx context.Context, letter *DeadLetter) (*DeadLetter, error)
+	List(ctx context.Context, limit int) ([]*DeadLetter, error)
+	ListPending(ctx context.Context, limit int) ([]*DeadLetter, error)
+	Find(ctx context.Context, ID int64) (*DeadLetter, error)
+	RecordAttempt(ctx context.Context, ID int64, errMessage string) error
+	Delete(ctx context.Context, ID int64) error
+}
+
+// DeadLettersRepository implements DeadLettersRepositoryInterface
+type DeadLettersRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeadLettersRepository returns a configured DeadLettersRepository object
+func NewDeadLettersRepository(db *sqlx.DB) *DeadLettersRepository {
+	return &DeadLettersRepository{db: db}
+}
+
+const deadLetterColumns = "id, channel, op, target, job, error, attempts, pending, created_at, updated_at"
+
+// Create keeps the send given up on
+func (r *DeadLettersRepository) Create(ctx context.Context, letter *DeadLetter) (*DeadLetter, error) {
+	stmt := `INSERT INTO notification_dead_letters (channel, op, target, job, error, attempts, pending)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING ` + deadLetterColumns
+
+	created := &DeadLetter{}
+	err := r.db.GetContext(ctx, created, stmt, letter.Channel, letter.Op, letter.Target, []byte(letter.Job),
+		letter.Error, letter.Attempts, letter.Pending)
+	if err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return created, nil
+}
+
+// List returns up to limit dead letters, the oldest first
+func (r *DeadLettersRepository) List(ctx context.Context, limit int) ([]*DeadLetter, error) {
+	stmt := "SELECT " + deadLetterColumns + " FROM notification_dead_letters ORDER BY id LIMIT $1"
+
+	letters := []*DeadLetter{}
+	if err := r.db.SelectContext(ctx, &letters, stmt, limit); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return letters, nil
+}
+
+// ListPending returns up to limit pending dead letters, the oldest first
+func (r *DeadLettersRepository) ListPending(ctx context.Context, limit int) ([]*DeadLetter, error) {
+	stmt := "SELECT " + deadLetterColumns + " FROM notification_dead_letters WHERE pending ORDER BY id LIMIT $1"
+
+	letters := []*DeadLetter{}
+	if err := r.db.SelectContext(ctx, &letters, stmt, limit); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return letters, nil
+}
+
+// Find finds a dead letter by ID, returns nil if not found
+func (r *DeadLettersRepository) Find(ctx context.Context, ID int64) (*DeadLetter, error) {
+	letter := &DeadLetter{}
+	stmt := "SELECT " + deadLetterColumns + " FROM notification_dead_letters WHERE id = $1"
+	if err := r.db.GetContext(ctx, letter, stmt, ID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, parseError(ctx, err)
+	}
+	return letter, nil
+}
+
+// RecordAttempt counts a failed replay of the dead letter, along with its error. It's no longer
+// pending, to be replayed by the admins only.
+func (r *DeadLettersRepository) RecordAttempt(ctx context.Context, ID int64, errMessage string) error {
+	stmt := `UPDATE notification_dead_letters SET attempts = attempts + 1, error = $2, pending = false, updated_at = now()
+		WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, stmt, ID, errMessage); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
+
+// Delete removes the dead letter, once replayed
+func (r *DeadLettersRepository) Delete(ctx context.Context, ID int64) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM notification_dead_letters WHERE id = $1", ID); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"sort"
+	"time"
+)
+
+// DeferredNotification is a notification send to a user deferred until the end of their quiet hours
+type DeferredNotification struct {
+	ID     int64  `db:"id"`
+	UserID string `db:"user_id"`
+	// Job is the send serialized
+	Job       json.RawMessage `db:"job"`
+	SendAfter time.Time       `db:"send_after"`
+	CreatedAt time.Time       `db:"created_at"`
+}
+
+// DeferredNotificationsRepositoryInterface defines the set of deferred notifications related methods available
+type DeferredNotificationsRepositoryInterface interface {
+	Create(ctx context.Context, deferred *DeferredNotification) error
+	TakeDue(ctx context.Context, now time.Time, limit int) ([]*DeferredNotification, error)
+}
+
+// DeferredNotificationsRepository implements DeferredNotificationsRepositoryInterface
+type DeferredNotificationsRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeferredNotificationsRepository returns a configured DeferredNotificationsRepository object
+func NewDeferredNotificationsRepository(db *sqlx.DB) *DeferredNotificationsRepository {
+	return &DeferredNotificationsRepository{db: db}
+}
+
+// Create keeps the deferred send until its SendAfter time
+func (r *DeferredNotificationsRepository) Create(ctx context.Context, deferred *DeferredNotification) error {
+	stmt := "INSERT INTO deferred_notifications (user_id, job, send_after) VALUES ($1, $2, $3)"
+	if _, err := r.db.ExecContext(ctx, stmt, deferred.UserID, []byte(deferred.Job), deferred.SendAfter); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
+
+// TakeDue removes and returns up to limit sends due at now, the oldest first. The rows locked by
+// another instance taking them are skipped, so every send is taken once.
+func (r *DeferredNotificationsRepository) TakeDue(ctx context.Context, now time.Time, limit int) ([]*DeferredNotification, error) {
+	stmt := `DELETE FROM deferred_notifications WHERE id IN (
+			SELECT id FROM deferred_notifications WHERE send_after <= $1 ORDER BY id LIMIT $2 FOR UPDATE SKIP LOCKED
+		) RETURNING id, user_id, job, send_after, created_at`
+
+	due := []*DeferredNotification{}
+	if err := r.db.SelectContext(ctx, &due, stmt, now, limit); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	return due, nil
+}
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"time"
+)
+
+// DeviceToken is the FCM registration token of a device a user signed in on, or the token of the
+// Web Push Subscription of a browser
+type DeviceToken struct {
+	Token        string            `json:"token" db:"token"`
+	UserID       string            `json:"-" db:"user_id"`
+	Platform     string            `json:"platform" db:"platform"`
+	Subscription *PushSubscription `json:"-" db:"-"`
+	CreatedAt    time.Time         `json:"created_at" db:"created_at"`
+	LastSeenAt   time.Time         `json:"last_seen_at" db:"last_seen_at"`
+}
+
+// PushSubscription is the PushSubscription of a browser subscribed with Web Push
+type PushSubscription struct {
+	Endpoint string               `json:"endpoint"`
+	Keys     PushSubscriptionKeys `json:"keys"`
+}
+
+// PushSubscriptionKeys are the keys the messages to a browser are encrypted with, base64url encoded
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+// deviceRow is a device as stored, its subscription encoded in JSON
+type deviceRow struct {
+	DeviceToken
+	RawSubscription []byte `db:"subscription"`
+}
+
+func (row *deviceRow) decode() (*DeviceToken, error) {
+	device := row.DeviceToken
+	if len(row.RawSubscription) > 0 {
+		if err := json.Unmarshal(row.RawSubscription, &device.Subscription); err != nil {
+			return nil, err
+		}
+	}
+	return &device, nil
+}
+
+func decodeDevices(rows []*deviceRow) ([]*DeviceToken, error) {
+	devices := make([]*DeviceToken, 0, len(rows))
+	for _, row := range rows {
+		device, err := row.decode()
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// DevicesRepositoryInterface defines the set of device token related methods available.
+// A token belongs to a single user, the last one to register it.
+type DevicesRepositoryInterface interface {
+	Upsert(ctx context.Context, device *DeviceToken) (*DeviceToken, bool, error)
+	ListByUser(ctx context.Context, userID string) ([]*DeviceToken, error)
+	Delete(ctx context.Context, userID string, token string) (bool, error)
+	DeleteTokens(ctx context.Context, tokens []string) error
+	ListTokensByUsers(ctx context.Context, userIDs []string) ([]string, error)
+	ListOwnersByTokens(ctx context.Context, tokens []string) (map[string]string, error)
+	ListByTokens(ctx context.Context, tokens []string) ([]*DeviceToken, error)
+}
+
+// DevicesRepository implements DevicesRepositoryInterface
+type DevicesRepository struct {
+	db queryer
+}
+
+// NewDevicesRepository returns a configured DevicesRepository object
+func NewDevicesRepository(db *sqlx.DB) *DevicesRepository {
+	return &DevicesRepository{db: db}
+}
+
+// Upsert registers the token for the user, telling if it was created. Registering a known token
+// again updates its last_seen_at and subscription, handing it over to the user when it was
+// registered by another one.
+func (r *DevicesRepository) Upsert(ctx context.Context, device *DeviceToken) (*DeviceToken, bool, error) {
+	var subscription []byte
+	if device.Subscription != nil {
+		encoded, err := json.Marshal(device.Subscription)
+		if err != nil {
+			return nil, false, err
+		}
+		subscription = encoded
+	}
+
+	stmt := `INSERT INTO device_tokens (token, user_id, platform, subscription) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (token) DO UPDATE SET user_id = EXCLUDED.user_id, platform = EXCLUDED.platform,
+			subscription = EXCLUDED.subscription, last_seen_at = now()
+		RETURNING token, user_id, platform, subscription, created_at, last_seen_at, (xmax = 0) AS created`
+
+	var row struct {
+		deviceRow
+		Created bool `db:"created"`
+	}
+	if err := r.db.GetContext(ctx, &row, stmt, device.Token, device.UserID, device.Platform, subscription); err != nil {
+		return nil, false, parseError(ctx, err)
+	}
+
+	saved, err := row.decode()
+	if err != nil {
+		return nil, false, err
+	}
+	return saved, row.Created, nil
+}
+
+// ListByUser returns the devices of the user, the last seen first
+func (r *DevicesRepository) ListByUser(ctx context.Context, userID string) ([]*DeviceToken, error) {
+	rows := []*deviceRow{}
+	stmt := `SELECT token, user_id, platform, subscription, created_at, last_seen_at FROM device_tokens
+		WHERE user_id = $1 ORDER BY last_seen_at DESC`
+	if err := r.db.SelectContext(ctx, &rows, stmt, userID); err != nil {
+		return nil, parseError(ctx, err)
+	}
+
+	return decodeDevices(rows)
+}
+
+// ListByTokens returns the devices with the tokens
+func (r *DevicesRepository) ListByTokens(ctx context.Context, tokens []string) ([]*DeviceToken, error) {
+	rows := []*deviceRow{}
+	stmt := `SELECT token, user_id, platform, subscription, created_at, last_seen_at FROM device_tokens
+		WHERE token = ANY($1) ORDER BY token`
+	if err := r.db.SelectContext(ctx, &rows, stmt, pq.Array(tokens)); err != nil {
+		return nil, parseError(ctx, err)
+	}
+
+	return decodeDevices(rows)
+}
+
+// Delete removes the token of the user, telling if it was found
+func (r *DevicesRepository) Delete(ctx context.Context, userID string, token string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM device_tokens WHERE user_id = $1 AND token = $2", userID, token)
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+	return rows > 0, nil
+}
+
+// DeleteTokens removes the tokens, whichever users registered them
+func (r *DevicesRepository) DeleteTokens(ctx context.Context, tokens []string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM device_tokens WHERE token = ANY($1)", pq.Array(tokens)); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
+
+// ListTokensByUsers returns the tokens of the devices of the users
+func (r *DevicesRepository) ListTokensByUsers(ctx context.Context, userIDs []string) ([]string, error) {
+	tokens := []string{}
+	stmt := "SELECT token FROM device_tokens WHERE user_id = ANY($1) ORDER BY token"
+	if err := r.db.SelectContext(ctx, &tokens, stmt, pq.Array(userIDs)); err != nil {
+		return nil, parseError(ctx, err)
+	}
+
+	return tokens, nil
+}
+
+// ListOwnersByTokens returns the users the devices with the tokens belong to, by token
+func (r *DevicesRepository) ListOwnersByTokens(ctx context.Context, tokens []string) (map[string]string, error) {
+	var devices []*DeviceToken
+	stmt := "SELECT token, user_id FROM device_tokens WHERE token = ANY($1)"
+	if err := r.db.SelectContext(ctx, &devices, stmt, pq.Array(tokens)); err != nil {
+		return nil, parseError(ctx, err)
+	}
+
+	owners := make(map[string]string, len(devices))
+	for _, device := range devices {
+		owners[device.Token] = device.UserID
+	}
+	return owners, nil
+}
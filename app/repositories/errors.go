@@ -1,44 +1,146 @@
 package repositories
 
 import (
+	"appdoki-be/app/logging"
+	"context"
+	"database/sql/driver"
+	"errors"
 	"fmt"
 	"github.com/lib/pq"
-	log "github.com/sirupsen/logrus"
+	"io"
+	"net"
 	"regexp"
+	"syscall"
 )
 
-const PQUniqueViolation = "23505"
+const (
+	PQUniqueViolation      = "23505"
+	PQForeignKeyViolation  = "23503"
+	PQCheckViolation       = "23514"
+	PQSerializationFailure = "40001"
+	PQDeadlockDetected     = "40P01"
+	PQQueryCanceled        = "57014"
+	PQConnectionException  = "08"
+	PQAdminShutdown        = "57P01"
+	PQCannotConnectNow     = "57P03"
+	PQTooManyConnections   = "53300"
+)
+
+// The kinds of the database errors returned by parseError, for errors.Is
+var (
+	ErrDuplicate      = errors.New("the record already exists")
+	ErrForeignKey     = errors.New("the record references a missing one, or is still referenced")
+	ErrCheckViolation = errors.New("the record breaks a check constraint")
+	ErrSerialization  = errors.New("the transaction conflicts with a concurrent one")
+	ErrDeadlock       = errors.New("the transaction deadlocked with a concurrent one")
+	ErrConnection     = errors.New("the database can't be reached")
+	ErrTimeout        = errors.New("the statement ran longer than the statement_timeout")
+)
 
+// DatabaseError is a database error of a known kind, one of the Err variables, along with the
+// constraint and table involved when there's one. It wraps the original error, the *pq.Error
+// remaining available to errors.As.
+type DatabaseError struct {
+	Kind       error
+	Constraint string
+	Table      string
+	Err        error
+}
+
+func (e *DatabaseError) Error() string {
+	if e.Constraint != "" {
+		return fmt.Sprintf("%v (%s): %v", e.Kind, e.Constraint, e.Err)
+	}
+	return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+}
+
+// Is reports whether the error is of the target kind
+func (e *DatabaseError) Is(target error) bool {
+	return e.Kind == target
+}
+
+func (e *DatabaseError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError is a duplicated or still referenced record, its message telling which one. It
+// wraps the DatabaseError of the violation.
 type ConflictError struct {
 	Message string
+	Err     error
 }
 
 func (e *ConflictError) Error() string {
 	return e.Message
 }
 
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
 // parseError take an error and passes it through the respective database error parser
 // or returns the error itself if there is no matching parser.
-// If there is a match, the resulting error may be a custom one.
-func parseError(e error) error {
-	log.Error("database error: ", e)
+// If there is a match, the resulting error is a DatabaseError, wrapped in a ConflictError for the
+// unique and foreign key violations. ctx being canceled or timed out, the error is the one of ctx.
+func parseError(ctx context.Context, e error) error {
+	if ctx.Err() != nil {
+		logging.FromContext(ctx).Debug("database statement canceled: ", e)
+		return contextError(ctx, e)
+	}
+	logging.FromContext(ctx).Error("database error: ", e)
 
-	pqErr, ok := e.(*pq.Error)
-	if !ok {
+	var pqErr *pq.Error
+	if !errors.As(e, &pqErr) {
+		if isConnectionError(e) {
+			return &DatabaseError{Kind: ErrConnection, Err: e}
+		}
 		return e
 	}
 
-	switch pqErr.Code {
-	case PQUniqueViolation:
+	dbErr := &DatabaseError{Constraint: pqErr.Constraint, Table: pqErr.Table, Err: e}
+	switch {
+	case pqErr.Code == PQUniqueViolation:
+		dbErr.Kind = ErrDuplicate
 		column, value := extractColumnValue(pqErr.Detail)
 		msg := fmt.Sprintf("[%s] already exists with this value (%s)", column, value)
 
 		return &ConflictError{
 			Message: msg,
+			Err:     dbErr,
 		}
+	case pqErr.Code == PQForeignKeyViolation:
+		dbErr.Kind = ErrForeignKey
+		return &ConflictError{
+			Message: fmt.Sprintf("still referenced by [%s]", pqErr.Table),
+			Err:     dbErr,
+		}
+	case pqErr.Code == PQCheckViolation:
+		dbErr.Kind = ErrCheckViolation
+	case pqErr.Code == PQSerializationFailure:
+		dbErr.Kind = ErrSerialization
+	case pqErr.Code == PQDeadlockDetected:
+		dbErr.Kind = ErrDeadlock
+	case pqErr.Code == PQQueryCanceled:
+		dbErr.Kind = ErrTimeout
+	case pqErr.Code.Class() == PQConnectionException, pqErr.Code == PQAdminShutdown,
+		pqErr.Code == PQCannotConnectNow, pqErr.Code == PQTooManyConnections:
+		dbErr.Kind = ErrConnection
 	default:
 		return e
 	}
+	return dbErr
+}
+
+// isConnectionError reports whether the error comes from the connection to the database rather
+// than from a statement: a connection the driver gave up on, refused or closed by the server, or a
+// network failure. The cancelled and timed out contexts aren't, the request having given up itself.
+func isConnectionError(e error) bool {
+	if errors.Is(e, context.Canceled) || errors.Is(e, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(e, driver.ErrBadConn) || errors.Is(e, io.EOF) || errors.Is(e, io.ErrUnexpectedEOF) ||
+		errors.Is(e, syscall.ECONNREFUSED) || errors.Is(e, syscall.ECONNRESET) || errors.As(e, &netErr)
 }
 
 // extractColumnValue takes a string in the form of a sql error detail
@@ -62,3 +164,14 @@ func extractStringSubmatch(regex *regexp.Regexp, str string) string {
 	}
 	return results[1]
 }
+
+// contextError returns the error of ctx wrapping e, ctx being canceled or timed out while the statement
+// failing with e ran: the driver reports the statement canceled, or the transaction rolled back, rather
+// than why it was
+func contextError(ctx context.Context, e error) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil || errors.Is(e, ctxErr) {
+		return e
+	}
+	return fmt.Errorf("%w: %v", ctxErr, e)
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"net"
+	"syscall"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	ctx := context.Background()
+	duplicate := &pq.Error{Code: PQUniqueViolation, Constraint: "users_email_key", Table: "users", Detail: "Key (email)=(ana@cloudoki.com) already exists."}
+
+	tests := []struct {
+		name       string
+		err        error
+		kind       error
+		conflict   bool
+		constraint string
+	}{
+		{name: "unique violation", err: duplicate, kind: ErrDuplicate, conflict: true, constraint: "users_email_key"},
+		{name: "wrapped unique violation", err: fmt.Errorf("inserting: %w", duplicate), kind: ErrDuplicate, conflict: true, constraint: "users_email_key"},
+		{name: "foreign key violation", err: &pq.Error{Code: PQForeignKeyViolation, Constraint: "beer_transfers_giver_id_fkey", Table: "beer_transfers"}, kind: ErrForeignKey, conflict: true, constraint: "beer_transfers_giver_id_fkey"},
+		{name: "check violation", err: &pq.Error{Code: PQCheckViolation, Constraint: "beers_positive"}, kind: ErrCheckViolation, constraint: "beers_positive"},
+		{name: "serialization failure", err: &pq.Error{Code: PQSerializationFailure}, kind: ErrSerialization},
+		{name: "deadlock", err: &pq.Error{Code: PQDeadlockDetected}, kind: ErrDeadlock},
+		{name: "statement timeout", err: &pq.Error{Code: PQQueryCanceled}, kind: ErrTimeout},
+		{name: "connection exception", err: &pq.Error{Code: "08006"}, kind: ErrConnection},
+		{name: "server shutting down", err: &pq.Error{Code: PQAdminShutdown}, kind: ErrConnection},
+		{name: "too many connections", err: &pq.Error{Code: PQTooManyConnections}, kind: ErrConnection},
+		{name: "bad connection", err: driver.ErrBadConn, kind: ErrConnection},
+		{name: "refused connection", err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, kind: ErrConnection},
+		{name: "other database error", err: &pq.Error{Code: "42P01"}},
+		{name: "no rows", err: sql.ErrNoRows},
+		{name: "cancelled request", err: context.Canceled},
+	}
+	kinds := []error{ErrDuplicate, ErrForeignKey, ErrCheckViolation, ErrSerialization, ErrDeadlock, ErrConnection, ErrTimeout}
+
+	for _, test := range tests {
+		t.Run("expect a "+test.name+" to be parsed", func(t *testing.T) {
+			err := parseError(ctx, test.err)
+
+			for _, kind := range kinds {
+				if errors.Is(err, kind) != (kind == test.kind) {
+					t.Fatalf("expected errors.Is(%v) to be %v, got %v", kind, kind == test.kind, err)
+				}
+			}
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected the original error to be wrapped, got %v", err)
+			}
+			var conflict *ConflictError
+			if errors.As(err, &conflict) != test.conflict {
+				t.Fatalf("expected a ConflictError to be %v, got %v", test.conflict, err)
+			}
+			var dbErr *DatabaseError
+			if errors.As(err, &dbErr) != (test.kind != nil) {
+				t.Fatalf("expected a DatabaseError to be %v, got %v", test.kind != nil, err)
+			}
+			if dbErr != nil && dbErr.Constraint != test.constraint {
+				t.Fatalf("expected the constraint %q, got %q", test.constraint, dbErr.Constraint)
+			}
+		})
+	}
+
+	t.Run("expect a statement stopped by its context to fail with the error of the context", func(t *testing.T) {
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		if err := parseError(canceled, &pq.Error{Code: PQQueryCanceled, Message: "canceling statement due to user request"}); !errors.Is(err, context.Canceled) || errors.Is(err, ErrTimeout) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+
+		timedOut, cancel := context.WithTimeout(ctx, 0)
+		defer cancel()
+		if err := parseError(timedOut, sql.ErrTxDone); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("expect the original pq error to remain available", func(t *testing.T) {
+		var pqErr *pq.Error
+		if err := parseError(ctx, duplicate); !errors.As(err, &pqErr) || pqErr.Table != "users" {
+			t.Fatalf("expected the pq error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"time"
+)
+
+// FeatureFlag is the state of a feature flag, shared by every replica: off, or on for
+// RolloutPercent of the users
+type FeatureFlag struct {
+	Name           string    `json:"name" db:"name"`
+	Enabled        bool      `json:"enabled" db:"enabled"`
+	RolloutPercent int       `json:"rollout_percent" db:"rollout_percent"`
+	UpdatedBy      *string   `json:"updated_by" db:"updated_by"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// FeatureFlagsRepositoryInterface defines the set of feature flags related methods available
+type FeatureFlagsRepositoryInterface interface {
+	Seed(ctx context.Context, defaults map[string]bool) error
+	List(ctx context.Context) ([]*FeatureFlag, error)
+	Set(ctx context.Context, flag *FeatureFlag) (*FeatureFlag, error)
+}
+
+// FeatureFlagsRepository implements FeatureFlagsRepositoryInterface
+type FeatureFlagsRepository struct {
+	db *sqlx.DB
+}
+
+// NewFeatureFlagsRepository returns a configured FeatureFlagsRepository object
+func NewFeatureFlagsRepository(db *sqlx.DB) *FeatureFlagsRepository {
+	return &FeatureFlagsRepository{db: db}
+}
+
+const featureFlagColumns = "name, enabled, rollout_percent, updated_by, updated_at"
+
+// Seed creates the flags missing with their default state, for every user, the existing ones
+// keeping theirs
+func (r *FeatureFlagsRepository) Seed(ctx context.Context, defaults map[string]bool) error {
+	names := make(pq.StringArray, 0, len(defaults))
+	states := make(pq.BoolArray, 0, len(defaults))
+	for name, enabled := range defaults {
+		names = append(names, name)
+		states = append(states, enabled)
+	}
+
+	stmt := `INSERT INTO feature_flags (name, enabled)
+		SELECT * FROM unnest($1::text[], $2::boolean[])
+		ON CONFLICT (name) DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, stmt, names, states); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
+
+// List returns every flag, sorted by name
+func (r *FeatureFlagsRepository) List(ctx context.Context) ([]*FeatureFlag, error) {
+	stmt := "SELECT " + featureFlagColumns + " FROM feature_flags ORDER BY name"
+
+	flags := []*FeatureFlag{}
+	if err := r.db.SelectContext(ctx, &flags, stmt); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return flags, nil
+}
+
+// Set changes the state of the flag, creating it when it wasn't seeded yet
+func (r *FeatureFlagsRepository) Set(ctx context.Context, flag *FeatureFlag) (*FeatureFlag, error) {
+	stmt := `INSERT INTO feature_flags (name, enabled, rollout_percent, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, now())
+		ON CONFLICT (name) DO UPDATE SET enabled = EXCLUDED.enabled, rollout_percent = EXCLUDED.rollout_percent,
+			updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+		RETURNING ` + featureFlagColumns
+
+	updated := &FeatureFlag{}
+	if err := r.db.GetContext(ctx, updated, stmt, flag.Name, flag.Enabled, flag.RolloutPercent, flag.UpdatedBy); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return updated, nil
+}
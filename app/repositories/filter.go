@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"appdoki-be/app/filter"
+	"fmt"
+	"strings"
+)
+
+// UserFilterFields are the fields the users can be filtered by, their role being left out as it's
+// told only to the admins
+var UserFilterFields = filter.Fields{
+	"name":       {Column: "name", Type: filter.String, Ops: []filter.Op{filter.Eq, filter.NotEq, filter.Contains}},
+	"email":      {Column: "email", Type: filter.String, Ops: []filter.Op{filter.Eq, filter.NotEq, filter.Contains}},
+	"picture":    {Column: "picture", Type: filter.String, Ops: []filter.Op{filter.Eq, filter.NotEq}, Nullable: true},
+	"created_at": {Column: "created_at", Type: filter.Time, Ops: []filter.Op{filter.Gt, filter.Gte, filter.Lt, filter.Lte}},
+}
+
+// UserRoleFilterFields are the UserFilterFields along with the role, for the filters of the admins
+var UserRoleFilterFields = func() filter.Fields {
+	fields := filter.Fields{
+		"role": {Column: "role", Type: filter.String, Ops: []filter.Op{filter.Eq, filter.NotEq}},
+	}
+	for name, field := range UserFilterFields {
+		fields[name] = field
+	}
+	return fields
+}()
+
+// filterOperators are the SQL operators of the filter comparisons
+var filterOperators = map[filter.Op]string{
+	filter.Eq:    "=",
+	filter.NotEq: "<>",
+	filter.Gt:    ">",
+	filter.Gte:   ">=",
+	filter.Lt:    "<",
+	filter.Lte:   "<=",
+}
+
+// filterClause translates a filter into the conditions of a WHERE clause, its values being
+// bound to the placeholders from $firstArg. Columns come from the whitelist, never from the client.
+// Null matches the missing values, empty strings included.
+func filterClause(f filter.Filter, firstArg int) (string, []interface{}) {
+	conditions := make([]string, 0, len(f))
+	args := make([]interface{}, 0, len(f))
+
+	for _, cond := range f {
+		switch {
+		case cond.Value == nil && cond.Op == filter.Eq:
+			conditions = append(conditions, fmt.Sprintf("(%s IS NULL OR %s::text = '')", cond.Column, cond.Column))
+		case cond.Value == nil:
+			conditions = append(conditions, fmt.Sprintf("(%s IS NOT NULL AND %s::text <> '')", cond.Column, cond.Column))
+		case cond.Op == filter.Contains:
+			args = append(args, escapeLike(fmt.Sprint(cond.Value)))
+			conditions = append(conditions, fmt.Sprintf("%s ILIKE '%%' || $%d || '%%'", cond.Column, firstArg+len(args)-1))
+		default:
+			args = append(args, cond.Value)
+			conditions = append(conditions, fmt.Sprintf("%s %s $%d", cond.Column, filterOperators[cond.Op], firstArg+len(args)-1))
+		}
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"appdoki-be/app/filter"
+	"reflect"
+	"testing"
+)
+
+func TestFilterClause(t *testing.T) {
+	t.Run("expect the conditions to be bound to placeholders", func(t *testing.T) {
+		f, err := filter.Parse("picture:null,role!:admin,name~50%_off", UserRoleFilterFields)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		where, args := filterClause(f, 2)
+
+		expectedWhere := "(picture IS NULL OR picture::text = '') AND role <> $2 AND name ILIKE '%' || $3 || '%'"
+		if where != expectedWhere {
+			t.Fatalf("expected '%s', got '%s'", expectedWhere, where)
+		}
+		if expectedArgs := []interface{}{"admin", `50\%\_off`}; !reflect.DeepEqual(args, expectedArgs) {
+			t.Fatalf("expected %v, got %v", expectedArgs, args)
+		}
+	})
+
+	t.Run("expect the role to be left out of the public fields", func(t *testing.T) {
+		if _, err := filter.Parse("role:admin", UserFilterFields); err == nil {
+			t.Fatal("expected the role not to be filterable")
+		}
+	})
+
+	t.Run("expect no filter to be no condition", func(t *testing.T) {
+		if where, args := filterClause(nil, 1); where != "" || len(args) != 0 {
+			t.Fatalf("expected no condition, got '%s' %v", where, args)
+		}
+	})
+}
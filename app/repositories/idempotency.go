@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// IdempotencyRecord is the stored outcome of a request made with an idempotency key.
+// Status is 0 while the request is still being handled.
+type IdempotencyRecord struct {
+	RequestHash string `db:"request_hash"`
+	Status      int    `db:"status"`
+	ContentType string `db:"content_type"`
+	Body        []byte `db:"body"`
+}
+
+// IdempotencyRepositoryInterface defines the set of idempotency key related methods available
+type IdempotencyRepositoryInterface interface {
+	Reserve(ctx context.Context, userID string, key string, requestHash string, ttl time.Duration) (*IdempotencyRecord, error)
+	Complete(ctx context.Context, userID string, key string, status int, contentType string, body []byte) error
+	Release(ctx context.Context, userID string, key string) error
+}
+
+// IdempotencyRepository implements IdempotencyRepositoryInterface
+type IdempotencyRepository struct {
+	db *sqlx.DB
+}
+
+// NewIdempotencyRepository returns a configured IdempotencyRepository object
+func NewIdempotencyRepository(db *sqlx.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve claims the key for a request, returns nil when claimed or the record of the request
+// that claimed it first. The insert is the gate: of concurrent requests with the same key only
+// one gets the row, expired keys being claimable again.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, userID string, key string, requestHash string, ttl time.Duration) (*IdempotencyRecord, error) {
+	const reserveStmt = `
+		INSERT INTO idempotency_keys (user_id, key, request_hash, expires_at)
+		VALUES ($1, $2, $3, now() + $4 * interval '1 millisecond')
+		ON CONFLICT (user_id, key) DO UPDATE
+			SET request_hash = EXCLUDED.request_hash,
+				status = NULL,
+				content_type = NULL,
+				body = NULL,
+				created_at = now(),
+				expires_at = EXCLUDED.expires_at
+			WHERE idempotency_keys.expires_at < now()
+		RETURNING key;`
+
+	var reserved string
+	err := r.db.QueryRowxContext(ctx, reserveStmt, userID, key, requestHash, ttl.Milliseconds()).Scan(&reserved)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, parseError(ctx, err)
+	}
+
+	record := &IdempotencyRecord{}
+	err = r.db.GetContext(ctx, record, `
+		SELECT request_hash, COALESCE(status, 0) AS status, COALESCE(content_type, '') AS content_type, body
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2;`, userID, key)
+	if err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return record, nil
+}
+
+// Complete stores the response of the request that reserved the key
+func (r *IdempotencyRepository) Complete(ctx context.Context, userID string, key string, status int, contentType string, body []byte) error {
+	const stmt = `UPDATE idempotency_keys SET status = $3, content_type = $4, body = $5 WHERE user_id = $1 AND key = $2;`
+	if _, err := r.db.ExecContext(ctx, stmt, userID, key, status, contentType, body); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
+
+// Release frees the key, so the request can be retried
+func (r *IdempotencyRepository) Release(ctx context.Context, userID string, key string) error {
+	const stmt = `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2;`
+	if _, err := r.db.ExecContext(ctx, stmt, userID, key); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// JobRunsRepositoryInterface defines the set of scheduled job runs related methods available. Every
+// scheduled run of a job is claimed by a single instance, even across restarts.
+type JobRunsRepositoryInterface interface {
+	Claim(ctx context.Context, name string, scheduledAt time.Time) (bool, error)
+	Finish(ctx context.Context, name string, scheduledAt time.Time, runErr error) error
+	LastScheduledBefore(ctx context.Context, name string, before time.Time) (*time.Time, error)
+}
+
+// JobRunsRepository implements JobRunsRepositoryInterface
+type JobRunsRepository struct {
+	db *sqlx.DB
+}
+
+// NewJobRunsRepository returns a configured JobRunsRepository object
+func NewJobRunsRepository(db *sqlx.DB) *JobRunsRepository {
+	return &JobRunsRepository{db: db}
+}
+
+// Claim records the run of the job scheduled at the given time, telling if it was claimed. It
+// wasn't when another instance claimed it first, or this one before a restart.
+func (r *JobRunsRepository) Claim(ctx context.Context, name string, scheduledAt time.Time) (bool, error) {
+	stmt := "INSERT INTO job_runs (name, scheduled_at) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+	res, err := r.db.ExecContext(ctx, stmt, name, scheduledAt)
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+	return rows > 0, nil
+}
+
+// Finish records the end of the run, along with the error it failed with
+func (r *JobRunsRepository) Finish(ctx context.Context, name string, scheduledAt time.Time, runErr error) error {
+	message := ""
+	if runErr != nil {
+		message = runErr.Error()
+	}
+
+	stmt := "UPDATE job_runs SET finished_at = now(), error = $3 WHERE name = $1 AND scheduled_at = $2"
+	if _, err := r.db.ExecContext(ctx, stmt, name, scheduledAt, message); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
+
+// LastScheduledBefore returns when the last run of the job before the given time was scheduled,
+// nil when it never ran
+func (r *JobRunsRepository) LastScheduledBefore(ctx context.Context, name string, before time.Time) (*time.Time, error) {
+	var last sql.NullTime
+	stmt := "SELECT max(scheduled_at) FROM job_runs WHERE name = $1 AND scheduled_at < $2"
+	if err := r.db.GetContext(ctx, &last, stmt, name, before); err != nil {
+		return nil, parseError(ctx, err)
+	}
+
+	if !last.Valid {
+		return nil, nil
+	}
+	return &last.Time, nil
+}
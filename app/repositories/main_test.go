@@ -0,0 +1,11 @@
+package repositories
+
+import (
+	"appdoki-be/app/testsupport"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testsupport.Main(m))
+}
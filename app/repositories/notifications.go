@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// Delivery statuses of the notifications
+const (
+	NotificationPending = "pending"
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed"
+	// NotificationSuppressed notifications were only kept in the history, the user getting too many
+	NotificationSuppressed = "suppressed"
+	// NotificationDeferred notifications are pushed once the quiet hours of the user end
+	NotificationDeferred = "deferred"
+)
+
+// Notification is a notification sent to a user, kept in their history
+type Notification struct {
+	ID        int64             `json:"id" db:"id"`
+	UserID    string            `json:"-" db:"user_id"`
+	Event     string            `json:"event" db:"event"`
+	Title     string            `json:"title" db:"title"`
+	Body      string            `json:"body" db:"body"`
+	Data      map[string]string `json:"data" db:"-"`
+	DeepLink  string            `json:"deep_link" db:"deep_link"`
+	Status    string            `json:"status" db:"status"`
+	ReadAt    *time.Time        `json:"read_at" db:"read_at"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// NotificationCount is how many notifications of an event have a delivery status
+type NotificationCount struct {
+	Event  string `db:"event"`
+	Status string `db:"status"`
+	Count  int    `db:"count"`
+}
+
+// NotificationListOptions pages the history of a user: up to Limit notifications created before
+// Before, the unread ones only with UnreadOnly
+type NotificationListOptions struct {
+	Limit      int
+	Before     time.Time
+	UnreadOnly bool
+}
+
+// NotificationsRepositoryInterface defines the set of notification history related methods available
+type NotificationsRepositoryInterface interface {
+	Create(ctx context.Context, notification *Notification) (*Notification, error)
+	SetStatus(ctx context.Context, ID int64, status string) error
+	ListByUser(ctx context.Context, userID string, options *NotificationListOptions) ([]*Notification, error)
+	CountUnread(ctx context.Context, userID string) (int, error)
+	MarkRead(ctx context.Context, userID string, ID int64) (bool, error)
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+	CountSince(ctx context.Context, since time.Time) ([]NotificationCount, error)
+}
+
+// NotificationsRepository implements NotificationsRepositoryInterface
+type NotificationsRepository struct {
+	db *sqlx.DB
+}
+
+// NewNotificationsRepository returns a configured NotificationsRepository object
+func NewNotificationsRepository(db *sqlx.DB) *NotificationsRepository {
+	return &NotificationsRepository{db: db}
+}
+
+// notificationRow is a notification as stored, its data encoded in JSON
+type notificationRow struct {
+	Notification
+	RawData []byte `db:"data"`
+}
+
+func (row *notificationRow) decode() (*Notification, error) {
+	n := row.Notification
+	if len(row.RawData) > 0 {
+		if err := json.Unmarshal(row.RawData, &n.Data); err != nil {
+			return nil, err
+		}
+	}
+	return &n, nil
+}
+
+// Create records the notification, pending until its delivery status is set
+func (r *NotificationsRepository) Create(ctx context.Context, notification *Notification) (*Notification, error) {
+	var data []byte
+	if len(notification.Data) > 0 {
+		encoded, err := json.Marshal(notification.Data)
+		if err != nil {
+			return nil, err
+		}
+		data = encoded
+	}
+
+	stmt := `INSERT INTO notifications (user_id, event, title, body, data, deep_link, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, user_id, event, title, body, data, deep_link, status, read_at, created_at`
+
+	var row notificationRow
+	err := r.db.GetContext(ctx, &row, stmt, notification.UserID, notification.Event, notification.Title,
+		notification.Body, data, notification.DeepLink, NotificationPending)
+	if err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return row.decode()
+}
+
+// SetStatus sets the delivery status of the notification
+func (r *NotificationsRepository) SetStatus(ctx context.Context, ID int64, status string) error {
+	stmt := "UPDATE notifications SET status = $2, updated_at = now() WHERE id = $1"
+	if _, err := r.db.ExecContext(ctx, stmt, ID, status); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
+
+// ListByUser returns a page of the history of the user, the last notification first
+func (r *NotificationsRepository) ListByUser(ctx context.Context, userID string, options *NotificationListOptions) ([]*Notification, error) {
+	stmt := `SELECT id, user_id, event, title, body, data, deep_link, status, read_at, created_at FROM notifications
+		WHERE user_id = $1 AND created_at < $2 AND (NOT $3 OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC LIMIT $4`
+
+	var rows []notificationRow
+	if err := r.db.SelectContext(ctx, &rows, stmt, userID, options.Before, options.UnreadOnly, options.Limit); err != nil {
+		return nil, parseError(ctx, err)
+	}
+
+	notifications := make([]*Notification, 0, len(rows))
+	for i := range rows {
+		n, err := rows[i].decode()
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+// CountUnread returns how many notifications of the user are unread
+func (r *NotificationsRepository) CountUnread(ctx context.Context, userID string) (int, error) {
+	var unread int
+	stmt := "SELECT count(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL"
+	if err := r.db.GetContext(ctx, &unread, stmt, userID); err != nil {
+		return 0, parseError(ctx, err)
+	}
+	return unread, nil
+}
+
+// MarkRead marks the notification of the user read, telling if it was found. A notification
+// read already keeps the time it was first read.
+func (r *NotificationsRepository) MarkRead(ctx context.Context, userID string, ID int64) (bool, error) {
+	stmt := "UPDATE notifications SET read_at = COALESCE(read_at, now()), updated_at = now() WHERE id = $1 AND user_id = $2"
+	res, err := r.db.ExecContext(ctx, stmt, ID, userID)
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+	return rows > 0, nil
+}
+
+// MarkAllRead marks every unread notification of the user read, telling how many there were
+func (r *NotificationsRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	stmt := "UPDATE notifications SET read_at = now(), updated_at = now() WHERE user_id = $1 AND read_at IS NULL"
+	res, err := r.db.ExecContext(ctx, stmt, userID)
+	if err != nil {
+		return 0, parseError(ctx, err)
+	}
+	return res.RowsAffected()
+}
+
+// CountSince counts the notifications created since the given time by event and delivery status
+func (r *NotificationsRepository) CountSince(ctx context.Context, since time.Time) ([]NotificationCount, error) {
+	stmt := `SELECT event, status, count(*) AS count FROM notifications WHERE created_at >= $1
+		GROUP BY event, status ORDER BY event, status`
+
+	counts := []NotificationCount{}
+	if err := r.db.SelectContext(ctx, &counts, stmt, since); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return counts, nil
+}
+
+// DeleteOlderThan prunes the notifications created before the given time, telling how many there were
+func (r *NotificationsRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM notifications WHERE created_at < $1", before)
+	if err != nil {
+		return 0, parseError(ctx, err)
+	}
+	return res.RowsAffected()
+}
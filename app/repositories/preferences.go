@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"appdoki-be/app/i18n"
+	"context"
+	"database/sql"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// The channels the digest is sent through
+const (
+	DigestChannelPush  = "push"
+	DigestChannelEmail = "email"
+)
+
+// NotificationPreferences tells which events a user wants to be notified of, how they want the
+// digest, the locale of their notifications and their quiet hours
+type NotificationPreferences struct {
+	BeerReceived  bool   `json:"beer_received" db:"notify_beer_received"`
+	NewUser       bool   `json:"new_user" db:"notify_new_user"`
+	Digest        bool   `json:"digest" db:"notify_digest"`
+	DigestChannel string `json:"digest_channel" db:"digest_channel"`
+	Locale        string `json:"locale" db:"locale"`
+	// QuietHoursStart and QuietHoursEnd are HH:MM clock times in Timezone, both empty without quiet hours
+	QuietHoursStart string `json:"quiet_hours_start" db:"quiet_hours_start"`
+	QuietHoursEnd   string `json:"quiet_hours_end" db:"quiet_hours_end"`
+	Timezone        string `json:"timezone" db:"timezone"`
+}
+
+// DefaultNotificationPreferences are the preferences of the users who never set theirs, notified of everything in English
+func DefaultNotificationPreferences() *NotificationPreferences {
+	return &NotificationPreferences{BeerReceived: true, NewUser: true, Digest: true, DigestChannel: DigestChannelPush, Locale: i18n.Fallback, Timezone: "UTC"}
+}
+
+// PreferencesRepositoryInterface defines the set of user preferences related methods available
+type PreferencesRepositoryInterface interface {
+	FindNotifications(ctx context.Context, userID string) (*NotificationPreferences, error)
+	SaveNotifications(ctx context.Context, userID string, prefs *NotificationPreferences) (*NotificationPreferences, error)
+	FindLocales(ctx context.Context, userIDs []string) (map[string]string, error)
+}
+
+// PreferencesRepository implements PreferencesRepositoryInterface
+type PreferencesRepository struct {
+	db *sqlx.DB
+}
+
+// NewPreferencesRepository returns a configured PreferencesRepository object
+func NewPreferencesRepository(db *sqlx.DB) *PreferencesRepository {
+	return &PreferencesRepository{db: db}
+}
+
+// FindNotifications returns the notification preferences of the user, the defaults when they never set them
+func (r *PreferencesRepository) FindNotifications(ctx context.Context, userID string) (*NotificationPreferences, error) {
+	prefs := &NotificationPreferences{}
+	stmt := `SELECT notify_beer_received, notify_new_user, notify_digest, digest_channel, locale,
+			quiet_hours_start, quiet_hours_end, timezone
+		FROM user_preferences WHERE user_id = $1`
+	if err := r.db.GetContext(ctx, prefs, stmt, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return DefaultNotificationPreferences(), nil
+		}
+		return nil, parseError(ctx, err)
+	}
+
+	return prefs, nil
+}
+
+// SaveNotifications stores the notification preferences of the user
+func (r *PreferencesRepository) SaveNotifications(ctx context.Context, userID string, prefs *NotificationPreferences) (*NotificationPreferences, error) {
+	stmt := `INSERT INTO user_preferences (user_id, notify_beer_received, notify_new_user, notify_digest, digest_channel, locale,
+			quiet_hours_start, quiet_hours_end, timezone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE SET notify_beer_received = EXCLUDED.notify_beer_received,
+			notify_new_user = EXCLUDED.notify_new_user, notify_digest = EXCLUDED.notify_digest,
+			digest_channel = EXCLUDED.digest_channel, locale = EXCLUDED.locale,
+			quiet_hours_start = EXCLUDED.quiet_hours_start, quiet_hours_end = EXCLUDED.quiet_hours_end,
+			timezone = EXCLUDED.timezone, updated_at = now()
+		RETURNING notify_beer_received, notify_new_user, notify_digest, digest_channel, locale,
+			quiet_hours_start, quiet_hours_end, timezone`
+
+	saved := &NotificationPreferences{}
+	err := r.db.GetContext(ctx, saved, stmt, userID, prefs.BeerReceived, prefs.NewUser, prefs.Digest, prefs.DigestChannel, prefs.Locale,
+		prefs.QuietHoursStart, prefs.QuietHoursEnd, prefs.Timezone)
+	if err != nil {
+		return nil, parseError(ctx, err)
+	}
+
+	return saved, nil
+}
+
+// FindLocales returns the locales of the users who set their preferences, by user
+func (r *PreferencesRepository) FindLocales(ctx context.Context, userIDs []string) (map[string]string, error) {
+	var rows []struct {
+		UserID string `db:"user_id"`
+		Locale string `db:"locale"`
+	}
+	stmt := "SELECT user_id, locale FROM user_preferences WHERE user_id = ANY($1)"
+	if err := r.db.SelectContext(ctx, &rows, stmt, pq.Array(userIDs)); err != nil {
+		return nil, parseError(ctx, err)
+	}
+
+	locales := make(map[string]string, len(rows))
+	for _, row := range rows {
+		locales[row.UserID] = row.Locale
+	}
+	return locales, nil
+}
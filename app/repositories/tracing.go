@@ -0,0 +1,647 @@
+package repositories
+
+import (
+	"appdoki-be/app/filter"
+	"appdoki-be/app/tracing"
+	"context"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"time"
+)
+
+// startSpan starts a client span for a repository method
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
+}
+
+// QueryObserver is told how long each repository call took, ex.: to log the slow ones.
+// Calls are named after the repository and its method (ex.: UsersRepository.GetAll).
+type QueryObserver func(ctx context.Context, name string, duration time.Duration, err error)
+
+// startCall starts the span of a repository call, the returned func ends it
+// and tells observe, when not nil, how long the call took
+func startCall(ctx context.Context, name string, observe QueryObserver) (context.Context, func(err error)) {
+	start := time.Now()
+	ctx, span := startSpan(ctx, name)
+	return ctx, func(err error) {
+		tracing.End(span, err)
+		if observe != nil {
+			observe(ctx, name, time.Since(start), err)
+		}
+	}
+}
+
+// TracedUsersRepository decorates a UsersRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedUsersRepository struct {
+	next    UsersRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedUsersRepository returns a TracedUsersRepository wrapping next
+func NewTracedUsersRepository(next UsersRepositoryInterface, observe QueryObserver) *TracedUsersRepository {
+	return &TracedUsersRepository{next: next, observe: observe}
+}
+
+func (r *TracedUsersRepository) GetAll(ctx context.Context, f filter.Filter) (users []*User, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.GetAll", r.observe)
+	defer func() { end(err) }()
+	return r.next.GetAll(ctx, f)
+}
+
+func (r *TracedUsersRepository) LastModified(ctx context.Context) (lastModified time.Time, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.LastModified", r.observe)
+	defer func() { end(err) }()
+	return r.next.LastModified(ctx)
+}
+
+func (r *TracedUsersRepository) FindByID(ctx context.Context, ID string) (user *User, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.FindByID", r.observe)
+	defer func() { end(err) }()
+	return r.next.FindByID(ctx, ID)
+}
+
+func (r *TracedUsersRepository) FindByEmail(ctx context.Context, email string) (user *User, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.FindByEmail", r.observe)
+	defer func() { end(err) }()
+	return r.next.FindByEmail(ctx, email)
+}
+
+func (r *TracedUsersRepository) IsDeactivated(ctx context.Context, ID string) (deactivated bool, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.IsDeactivated", r.observe)
+	defer func() { end(err) }()
+	return r.next.IsDeactivated(ctx, ID)
+}
+
+func (r *TracedUsersRepository) FindOrCreateUser(ctx context.Context, userData *User) (user *User, created bool, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.FindOrCreateUser", r.observe)
+	defer func() { end(err) }()
+	return r.next.FindOrCreateUser(ctx, userData)
+}
+
+func (r *TracedUsersRepository) Create(ctx context.Context, user *User) (created *User, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.Create", r.observe)
+	defer func() { end(err) }()
+	return r.next.Create(ctx, user)
+}
+
+func (r *TracedUsersRepository) Update(ctx context.Context, user *User) (updated *User, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.Update", r.observe)
+	defer func() { end(err) }()
+	return r.next.Update(ctx, user)
+}
+
+func (r *TracedUsersRepository) Delete(ctx context.Context, ID string) (deleted bool, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.Delete", r.observe)
+	defer func() { end(err) }()
+	return r.next.Delete(ctx, ID)
+}
+
+func (r *TracedUsersRepository) DeactivateMany(ctx context.Context, IDs []string) (found []string, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.DeactivateMany", r.observe)
+	defer func() { end(err) }()
+	return r.next.DeactivateMany(ctx, IDs)
+}
+
+func (r *TracedUsersRepository) SetRoleMany(ctx context.Context, IDs []string, role string) (found []string, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.SetRoleMany", r.observe)
+	defer func() { end(err) }()
+	return r.next.SetRoleMany(ctx, IDs, role)
+}
+
+func (r *TracedUsersRepository) AddBeerTransfer(ctx context.Context, giverID string, takerID string, beers int) (id int, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.AddBeerTransfer", r.observe)
+	defer func() { end(err) }()
+	return r.next.AddBeerTransfer(ctx, giverID, takerID, beers)
+}
+
+func (r *TracedUsersRepository) GetBeerTransfersSummary(ctx context.Context, userID string) (beerLog *UserBeerLog, err error) {
+	ctx, end := startCall(ctx, "UsersRepository.GetBeerTransfersSummary", r.observe)
+	defer func() { end(err) }()
+	return r.next.GetBeerTransfersSummary(ctx, userID)
+}
+
+// TracedBeersRepository decorates a BeersRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedBeersRepository struct {
+	next    BeersRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedBeersRepository returns a TracedBeersRepository wrapping next
+func NewTracedBeersRepository(next BeersRepositoryInterface, observe QueryObserver) *TracedBeersRepository {
+	return &TracedBeersRepository{next: next, observe: observe}
+}
+
+func (r *TracedBeersRepository) GetBeerTransfer(ctx context.Context, id int) (transfer *BeerTransferFeedItem, err error) {
+	ctx, end := startCall(ctx, "BeersRepository.GetBeerTransfer", r.observe)
+	defer func() { end(err) }()
+	return r.next.GetBeerTransfer(ctx, id)
+}
+
+func (r *TracedBeersRepository) GetBeerTransfers(ctx context.Context, options *BeerFeedPaginationOptions) (feed []BeerTransferFeedItem, err error) {
+	ctx, end := startCall(ctx, "BeersRepository.GetBeerTransfers", r.observe)
+	defer func() { end(err) }()
+	return r.next.GetBeerTransfers(ctx, options)
+}
+
+func (r *TracedBeersRepository) LastModified(ctx context.Context) (lastModified time.Time, err error) {
+	ctx, end := startCall(ctx, "BeersRepository.LastModified", r.observe)
+	defer func() { end(err) }()
+	return r.next.LastModified(ctx)
+}
+
+func (r *TracedBeersRepository) ReceivedBetween(ctx context.Context, from time.Time, to time.Time) (received []*BeersReceived, err error) {
+	ctx, end := startCall(ctx, "BeersRepository.ReceivedBetween", r.observe)
+	defer func() { end(err) }()
+	return r.next.ReceivedBetween(ctx, from, to)
+}
+
+// TracedIdempotencyRepository decorates an IdempotencyRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedIdempotencyRepository struct {
+	next    IdempotencyRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedIdempotencyRepository returns a TracedIdempotencyRepository wrapping next
+func NewTracedIdempotencyRepository(next IdempotencyRepositoryInterface, observe QueryObserver) *TracedIdempotencyRepository {
+	return &TracedIdempotencyRepository{next: next, observe: observe}
+}
+
+func (r *TracedIdempotencyRepository) Reserve(ctx context.Context, userID string, key string, requestHash string, ttl time.Duration) (record *IdempotencyRecord, err error) {
+	ctx, end := startCall(ctx, "IdempotencyRepository.Reserve", r.observe)
+	defer func() { end(err) }()
+	return r.next.Reserve(ctx, userID, key, requestHash, ttl)
+}
+
+func (r *TracedIdempotencyRepository) Complete(ctx context.Context, userID string, key string, status int, contentType string, body []byte) (err error) {
+	ctx, end := startCall(ctx, "IdempotencyRepository.Complete", r.observe)
+	defer func() { end(err) }()
+	return r.next.Complete(ctx, userID, key, status, contentType, body)
+}
+
+func (r *TracedIdempotencyRepository) Release(ctx context.Context, userID string, key string) (err error) {
+	ctx, end := startCall(ctx, "IdempotencyRepository.Release", r.observe)
+	defer func() { end(err) }()
+	return r.next.Release(ctx, userID, key)
+}
+
+// TracedAuditRepository decorates an AuditRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedAuditRepository struct {
+	next    AuditRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedAuditRepository returns a TracedAuditRepository wrapping next
+func NewTracedAuditRepository(next AuditRepositoryInterface, observe QueryObserver) *TracedAuditRepository {
+	return &TracedAuditRepository{next: next, observe: observe}
+}
+
+func (r *TracedAuditRepository) Record(ctx context.Context, entries []*AuditEntry) (err error) {
+	ctx, end := startCall(ctx, "AuditRepository.Record", r.observe)
+	defer func() { end(err) }()
+	return r.next.Record(ctx, entries)
+}
+
+// TracedDevicesRepository decorates a DevicesRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedDevicesRepository struct {
+	next    DevicesRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedDevicesRepository returns a TracedDevicesRepository wrapping next
+func NewTracedDevicesRepository(next DevicesRepositoryInterface, observe QueryObserver) *TracedDevicesRepository {
+	return &TracedDevicesRepository{next: next, observe: observe}
+}
+
+func (r *TracedDevicesRepository) Upsert(ctx context.Context, device *DeviceToken) (saved *DeviceToken, created bool, err error) {
+	ctx, end := startCall(ctx, "DevicesRepository.Upsert", r.observe)
+	defer func() { end(err) }()
+	return r.next.Upsert(ctx, device)
+}
+
+func (r *TracedDevicesRepository) ListByUser(ctx context.Context, userID string) (devices []*DeviceToken, err error) {
+	ctx, end := startCall(ctx, "DevicesRepository.ListByUser", r.observe)
+	defer func() { end(err) }()
+	return r.next.ListByUser(ctx, userID)
+}
+
+func (r *TracedDevicesRepository) Delete(ctx context.Context, userID string, token string) (found bool, err error) {
+	ctx, end := startCall(ctx, "DevicesRepository.Delete", r.observe)
+	defer func() { end(err) }()
+	return r.next.Delete(ctx, userID, token)
+}
+
+func (r *TracedDevicesRepository) DeleteTokens(ctx context.Context, tokens []string) (err error) {
+	ctx, end := startCall(ctx, "DevicesRepository.DeleteTokens", r.observe)
+	defer func() { end(err) }()
+	return r.next.DeleteTokens(ctx, tokens)
+}
+
+func (r *TracedDevicesRepository) ListTokensByUsers(ctx context.Context, userIDs []string) (tokens []string, err error) {
+	ctx, end := startCall(ctx, "DevicesRepository.ListTokensByUsers", r.observe)
+	defer func() { end(err) }()
+	return r.next.ListTokensByUsers(ctx, userIDs)
+}
+
+func (r *TracedDevicesRepository) ListOwnersByTokens(ctx context.Context, tokens []string) (owners map[string]string, err error) {
+	ctx, end := startCall(ctx, "DevicesRepository.ListOwnersByTokens", r.observe)
+	defer func() { end(err) }()
+	return r.next.ListOwnersByTokens(ctx, tokens)
+}
+
+func (r *TracedDevicesRepository) ListByTokens(ctx context.Context, tokens []string) (devices []*DeviceToken, err error) {
+	ctx, end := startCall(ctx, "DevicesRepository.ListByTokens", r.observe)
+	defer func() { end(err) }()
+	return r.next.ListByTokens(ctx, tokens)
+}
+
+// TracedPreferencesRepository decorates a PreferencesRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedPreferencesRepository struct {
+	next    PreferencesRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedPreferencesRepository returns a TracedPreferencesRepository wrapping next
+func NewTracedPreferencesRepository(next PreferencesRepositoryInterface, observe QueryObserver) *TracedPreferencesRepository {
+	return &TracedPreferencesRepository{next: next, observe: observe}
+}
+
+func (r *TracedPreferencesRepository) FindNotifications(ctx context.Context, userID string) (prefs *NotificationPreferences, err error) {
+	ctx, end := startCall(ctx, "PreferencesRepository.FindNotifications", r.observe)
+	defer func() { end(err) }()
+	return r.next.FindNotifications(ctx, userID)
+}
+
+func (r *TracedPreferencesRepository) SaveNotifications(ctx context.Context, userID string, prefs *NotificationPreferences) (saved *NotificationPreferences, err error) {
+	ctx, end := startCall(ctx, "PreferencesRepository.SaveNotifications", r.observe)
+	defer func() { end(err) }()
+	return r.next.SaveNotifications(ctx, userID, prefs)
+}
+
+func (r *TracedPreferencesRepository) FindLocales(ctx context.Context, userIDs []string) (locales map[string]string, err error) {
+	ctx, end := startCall(ctx, "PreferencesRepository.FindLocales", r.observe)
+	defer func() { end(err) }()
+	return r.next.FindLocales(ctx, userIDs)
+}
+
+// TracedNotificationsRepository decorates a NotificationsRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedNotificationsRepository struct {
+	next    NotificationsRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedNotificationsRepository returns a TracedNotificationsRepository wrapping next
+func NewTracedNotificationsRepository(next NotificationsRepositoryInterface, observe QueryObserver) *TracedNotificationsRepository {
+	return &TracedNotificationsRepository{next: next, observe: observe}
+}
+
+func (r *TracedNotificationsRepository) Create(ctx context.Context, notification *Notification) (created *Notification, err error) {
+	ctx, end := startCall(ctx, "NotificationsRepository.Create", r.observe)
+	defer func() { end(err) }()
+	return r.next.Create(ctx, notification)
+}
+
+func (r *TracedNotificationsRepository) SetStatus(ctx context.Context, ID int64, status string) (err error) {
+	ctx, end := startCall(ctx, "NotificationsRepository.SetStatus", r.observe)
+	defer func() { end(err) }()
+	return r.next.SetStatus(ctx, ID, status)
+}
+
+func (r *TracedNotificationsRepository) ListByUser(ctx context.Context, userID string, options *NotificationListOptions) (notifications []*Notification, err error) {
+	ctx, end := startCall(ctx, "NotificationsRepository.ListByUser", r.observe)
+	defer func() { end(err) }()
+	return r.next.ListByUser(ctx, userID, options)
+}
+
+func (r *TracedNotificationsRepository) CountUnread(ctx context.Context, userID string) (unread int, err error) {
+	ctx, end := startCall(ctx, "NotificationsRepository.CountUnread", r.observe)
+	defer func() { end(err) }()
+	return r.next.CountUnread(ctx, userID)
+}
+
+func (r *TracedNotificationsRepository) MarkRead(ctx context.Context, userID string, ID int64) (found bool, err error) {
+	ctx, end := startCall(ctx, "NotificationsRepository.MarkRead", r.observe)
+	defer func() { end(err) }()
+	return r.next.MarkRead(ctx, userID, ID)
+}
+
+func (r *TracedNotificationsRepository) MarkAllRead(ctx context.Context, userID string) (read int64, err error) {
+	ctx, end := startCall(ctx, "NotificationsRepository.MarkAllRead", r.observe)
+	defer func() { end(err) }()
+	return r.next.MarkAllRead(ctx, userID)
+}
+
+func (r *TracedNotificationsRepository) DeleteOlderThan(ctx context.Context, before time.Time) (deleted int64, err error) {
+	ctx, end := startCall(ctx, "NotificationsRepository.DeleteOlderThan", r.observe)
+	defer func() { end(err) }()
+	return r.next.DeleteOlderThan(ctx, before)
+}
+
+func (r *TracedNotificationsRepository) CountSince(ctx context.Context, since time.Time) (counts []NotificationCount, err error) {
+	ctx, end := startCall(ctx, "NotificationsRepository.CountSince", r.observe)
+	defer func() { end(err) }()
+	return r.next.CountSince(ctx, since)
+}
+
+// TracedJobRunsRepository decorates a JobRunsRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedJobRunsRepository struct {
+	next    JobRunsRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedJobRunsRepository returns a TracedJobRunsRepository wrapping next
+func NewTracedJobRunsRepository(next JobRunsRepositoryInterface, observe QueryObserver) *TracedJobRunsRepository {
+	return &TracedJobRunsRepository{next: next, observe: observe}
+}
+
+func (r *TracedJobRunsRepository) Claim(ctx context.Context, name string, scheduledAt time.Time) (claimed bool, err error) {
+	ctx, end := startCall(ctx, "JobRunsRepository.Claim", r.observe)
+	defer func() { end(err) }()
+	return r.next.Claim(ctx, name, scheduledAt)
+}
+
+func (r *TracedJobRunsRepository) Finish(ctx context.Context, name string, scheduledAt time.Time, runErr error) (err error) {
+	ctx, end := startCall(ctx, "JobRunsRepository.Finish", r.observe)
+	defer func() { end(err) }()
+	return r.next.Finish(ctx, name, scheduledAt, runErr)
+}
+
+func (r *TracedJobRunsRepository) LastScheduledBefore(ctx context.Context, name string, before time.Time) (last *time.Time, err error) {
+	ctx, end := startCall(ctx, "JobRunsRepository.LastScheduledBefore", r.observe)
+	defer func() { end(err) }()
+	return r.next.LastScheduledBefore(ctx, name, before)
+}
+
+// TracedDeadLettersRepository decorates a DeadLettersRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedDeadLettersRepository struct {
+	next    DeadLettersRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedDeadLettersRepository returns a TracedDeadLettersRepository wrapping next
+func NewTracedDeadLettersRepository(next DeadLettersRepositoryInterface, observe QueryObserver) *TracedDeadLettersRepository {
+	return &TracedDeadLettersRepository{next: next, observe: observe}
+}
+
+func (r *TracedDeadLettersRepository) Create(ctx context.Context, letter *DeadLetter) (created *DeadLetter, err error) {
+	ctx, end := startCall(ctx, "DeadLettersRepository.Create", r.observe)
+	defer func() { end(err) }()
+	return r.next.Create(ctx, letter)
+}
+
+func (r *TracedDeadLettersRepository) List(ctx context.Context, limit int) (letters []*DeadLetter, err error) {
+	ctx, end := startCall(ctx, "DeadLettersRepository.List", r.observe)
+	defer func() { end(err) }()
+	return r.next.List(ctx, limit)
+}
+
+func (r *TracedDeadLettersRepository) ListPending(ctx context.Context, limit int) (letters []*DeadLetter, err error) {
+	ctx, end := startCall(ctx, "DeadLettersRepository.ListPending", r.observe)
+	defer func() { end(err) }()
+	return r.next.ListPending(ctx, limit)
+}
+
+func (r *TracedDeadLettersRepository) Find(ctx context.Context, ID int64) (letter *DeadLetter, err error) {
+	ctx, end := startCall(ctx, "DeadLettersRepository.Find", r.observe)
+	defer func() { end(err) }()
+	return r.next.Find(ctx, ID)
+}
+
+func (r *TracedDeadLettersRepository) RecordAttempt(ctx context.Context, ID int64, errMessage string) (err error) {
+	ctx, end := startCall(ctx, "DeadLettersRepository.RecordAttempt", r.observe)
+	defer func() { end(err) }()
+	return r.next.RecordAttempt(ctx, ID, errMessage)
+}
+
+func (r *TracedDeadLettersRepository) Delete(ctx context.Context, ID int64) (err error) {
+	ctx, end := startCall(ctx, "DeadLettersRepository.Delete", r.observe)
+	defer func() { end(err) }()
+	return r.next.Delete(ctx, ID)
+}
+
+// TracedDeferredNotificationsRepository decorates a DeferredNotificationsRepositoryInterface with a
+// span per method, telling observe how long each call took
+type TracedDeferredNotificationsRepository struct {
+	next    DeferredNotificationsRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedDeferredNotificationsRepository returns a TracedDeferredNotificationsRepository wrapping next
+func NewTracedDeferredNotificationsRepository(next DeferredNotificationsRepositoryInterface, observe QueryObserver) *TracedDeferredNotificationsRepository {
+	return &TracedDeferredNotificationsRepository{next: next, observe: observe}
+}
+
+func (r *TracedDeferredNotificationsRepository) Create(ctx context.Context, deferred *DeferredNotification) (err error) {
+	ctx, end := startCall(ctx, "DeferredNotificationsRepository.Create", r.observe)
+	defer func() { end(err) }()
+	return r.next.Create(ctx, deferred)
+}
+
+func (r *TracedDeferredNotificationsRepository) TakeDue(ctx context.Context, now time.Time, limit int) (due []*DeferredNotification, err error) {
+	ctx, end := startCall(ctx, "DeferredNotificationsRepository.TakeDue", r.observe)
+	defer func() { end(err) }()
+	return r.next.TakeDue(ctx, now, limit)
+}
+
+// TracedAnnouncementsRepository decorates an AnnouncementsRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedAnnouncementsRepository struct {
+	next    AnnouncementsRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedAnnouncementsRepository returns a TracedAnnouncementsRepository wrapping next
+func NewTracedAnnouncementsRepository(next AnnouncementsRepositoryInterface, observe QueryObserver) *TracedAnnouncementsRepository {
+	return &TracedAnnouncementsRepository{next: next, observe: observe}
+}
+
+func (r *TracedAnnouncementsRepository) Create(ctx context.Context, announcement *Announcement) (created *Announcement, err error) {
+	ctx, end := startCall(ctx, "AnnouncementsRepository.Create", r.observe)
+	defer func() { end(err) }()
+	return r.next.Create(ctx, announcement)
+}
+
+func (r *TracedAnnouncementsRepository) Finish(ctx context.Context, ID int64, status string, delivered *int) (err error) {
+	ctx, end := startCall(ctx, "AnnouncementsRepository.Finish", r.observe)
+	defer func() { end(err) }()
+	return r.next.Finish(ctx, ID, status, delivered)
+}
+
+func (r *TracedAnnouncementsRepository) List(ctx context.Context, limit int) (announcements []*Announcement, err error) {
+	ctx, end := startCall(ctx, "AnnouncementsRepository.List", r.observe)
+	defer func() { end(err) }()
+	return r.next.List(ctx, limit)
+}
+
+// TracedTriggersRepository decorates a TriggersRepositoryInterface with a span per method, telling
+// observe how long each call took
+type TracedTriggersRepository struct {
+	next    TriggersRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedTriggersRepository returns a TracedTriggersRepository wrapping next
+func NewTracedTriggersRepository(next TriggersRepositoryInterface, observe QueryObserver) *TracedTriggersRepository {
+	return &TracedTriggersRepository{next: next, observe: observe}
+}
+
+func (r *TracedTriggersRepository) Create(ctx context.Context, userID string, name string) (err error) {
+	ctx, end := startCall(ctx, "TriggersRepository.Create", r.observe)
+	defer func() { end(err) }()
+	return r.next.Create(ctx, userID, name)
+}
+
+func (r *TracedTriggersRepository) Take(ctx context.Context, userID string, name string) (taken bool, err error) {
+	ctx, end := startCall(ctx, "TriggersRepository.Take", r.observe)
+	defer func() { end(err) }()
+	return r.next.Take(ctx, userID, name)
+}
+
+// TracedWebhooksRepository decorates a WebhooksRepositoryInterface with a span per method, telling
+// observe how long each call took
+type TracedWebhooksRepository struct {
+	next    WebhooksRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedWebhooksRepository returns a TracedWebhooksRepository wrapping next
+func NewTracedWebhooksRepository(next WebhooksRepositoryInterface, observe QueryObserver) *TracedWebhooksRepository {
+	return &TracedWebhooksRepository{next: next, observe: observe}
+}
+
+func (r *TracedWebhooksRepository) Create(ctx context.Context, endpoint *WebhookEndpoint) (created *WebhookEndpoint, err error) {
+	ctx, end := startCall(ctx, "WebhooksRepository.Create", r.observe)
+	defer func() { end(err) }()
+	return r.next.Create(ctx, endpoint)
+}
+
+func (r *TracedWebhooksRepository) List(ctx context.Context) (endpoints []*WebhookEndpoint, err error) {
+	ctx, end := startCall(ctx, "WebhooksRepository.List", r.observe)
+	defer func() { end(err) }()
+	return r.next.List(ctx)
+}
+
+func (r *TracedWebhooksRepository) ListSubscribed(ctx context.Context, event string) (endpoints []*WebhookEndpoint, err error) {
+	ctx, end := startCall(ctx, "WebhooksRepository.ListSubscribed", r.observe)
+	defer func() { end(err) }()
+	return r.next.ListSubscribed(ctx, event)
+}
+
+func (r *TracedWebhooksRepository) Find(ctx context.Context, ID int64) (endpoint *WebhookEndpoint, err error) {
+	ctx, end := startCall(ctx, "WebhooksRepository.Find", r.observe)
+	defer func() { end(err) }()
+	return r.next.Find(ctx, ID)
+}
+
+func (r *TracedWebhooksRepository) Delete(ctx context.Context, ID int64) (deleted bool, err error) {
+	ctx, end := startCall(ctx, "WebhooksRepository.Delete", r.observe)
+	defer func() { end(err) }()
+	return r.next.Delete(ctx, ID)
+}
+
+func (r *TracedWebhooksRepository) RecordOutcome(ctx context.Context, ID int64, delivered bool, maxFailures int) (disabled bool, err error) {
+	ctx, end := startCall(ctx, "WebhooksRepository.RecordOutcome", r.observe)
+	defer func() { end(err) }()
+	return r.next.RecordOutcome(ctx, ID, delivered, maxFailures)
+}
+
+func (r *TracedWebhooksRepository) CreateDelivery(ctx context.Context, delivery *WebhookDelivery) (created *WebhookDelivery, err error) {
+	ctx, end := startCall(ctx, "WebhooksRepository.CreateDelivery", r.observe)
+	defer func() { end(err) }()
+	return r.next.CreateDelivery(ctx, delivery)
+}
+
+func (r *TracedWebhooksRepository) ListDeliveries(ctx context.Context, endpointID int64, limit int) (deliveries []*WebhookDelivery, err error) {
+	ctx, end := startCall(ctx, "WebhooksRepository.ListDeliveries", r.observe)
+	defer func() { end(err) }()
+	return r.next.ListDeliveries(ctx, endpointID, limit)
+}
+
+func (r *TracedWebhooksRepository) FindDelivery(ctx context.Context, endpointID int64, ID int64) (delivery *WebhookDelivery, err error) {
+	ctx, end := startCall(ctx, "WebhooksRepository.FindDelivery", r.observe)
+	defer func() { end(err) }()
+	return r.next.FindDelivery(ctx, endpointID, ID)
+}
+
+// TracedWebhookReceiptsRepository decorates a WebhookReceiptsRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedWebhookReceiptsRepository struct {
+	next    WebhookReceiptsRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedWebhookReceiptsRepository returns a TracedWebhookReceiptsRepository wrapping next
+func NewTracedWebhookReceiptsRepository(next WebhookReceiptsRepositoryInterface, observe QueryObserver) *TracedWebhookReceiptsRepository {
+	return &TracedWebhookReceiptsRepository{next: next, observe: observe}
+}
+
+func (r *TracedWebhookReceiptsRepository) Claim(ctx context.Context, source string, deliveryID string, ttl time.Duration) (claimed bool, err error) {
+	ctx, end := startCall(ctx, "WebhookReceiptsRepository.Claim", r.observe)
+	defer func() { end(err) }()
+	return r.next.Claim(ctx, source, deliveryID, ttl)
+}
+
+func (r *TracedWebhookReceiptsRepository) Release(ctx context.Context, source string, deliveryID string) (err error) {
+	ctx, end := startCall(ctx, "WebhookReceiptsRepository.Release", r.observe)
+	defer func() { end(err) }()
+	return r.next.Release(ctx, source, deliveryID)
+}
+
+// TracedFeatureFlagsRepository decorates a FeatureFlagsRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedFeatureFlagsRepository struct {
+	next    FeatureFlagsRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedFeatureFlagsRepository returns a TracedFeatureFlagsRepository wrapping next
+func NewTracedFeatureFlagsRepository(next FeatureFlagsRepositoryInterface, observe QueryObserver) *TracedFeatureFlagsRepository {
+	return &TracedFeatureFlagsRepository{next: next, observe: observe}
+}
+
+func (r *TracedFeatureFlagsRepository) Seed(ctx context.Context, defaults map[string]bool) (err error) {
+	ctx, end := startCall(ctx, "FeatureFlagsRepository.Seed", r.observe)
+	defer func() { end(err) }()
+	return r.next.Seed(ctx, defaults)
+}
+
+func (r *TracedFeatureFlagsRepository) List(ctx context.Context) (flags []*FeatureFlag, err error) {
+	ctx, end := startCall(ctx, "FeatureFlagsRepository.List", r.observe)
+	defer func() { end(err) }()
+	return r.next.List(ctx)
+}
+
+func (r *TracedFeatureFlagsRepository) Set(ctx context.Context, flag *FeatureFlag) (updated *FeatureFlag, err error) {
+	ctx, end := startCall(ctx, "FeatureFlagsRepository.Set", r.observe)
+	defer func() { end(err) }()
+	return r.next.Set(ctx, flag)
+}
+
+// TracedDBStatsRepository decorates a DBStatsRepositoryInterface with a span per method,
+// telling observe how long each call took
+type TracedDBStatsRepository struct {
+	next    DBStatsRepositoryInterface
+	observe QueryObserver
+}
+
+// NewTracedDBStatsRepository returns a TracedDBStatsRepository wrapping next
+func NewTracedDBStatsRepository(next DBStatsRepositoryInterface, observe QueryObserver) *TracedDBStatsRepository {
+	return &TracedDBStatsRepository{next: next, observe: observe}
+}
+
+func (r *TracedDBStatsRepository) TableStats(ctx context.Context, tables []string) (stats []TableStats, err error) {
+	ctx, end := startCall(ctx, "DBStatsRepository.TableStats", r.observe)
+	defer func() { end(err) }()
+	return r.next.TableStats(ctx, tables)
+}
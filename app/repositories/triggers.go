@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+)
+
+// TriggersRepositoryInterface defines the set of pending triggers related methods available. A pending
+// trigger waits for an event of its user to fire, ex.: the welcome notification for their first device.
+type TriggersRepositoryInterface interface {
+	Create(ctx context.Context, userID string, name string) error
+	Take(ctx context.Context, userID string, name string) (bool, error)
+}
+
+// TriggersRepository implements TriggersRepositoryInterface
+type TriggersRepository struct {
+	db *sqlx.DB
+}
+
+// NewTriggersRepository returns a configured TriggersRepository object
+func NewTriggersRepository(db *sqlx.DB) *TriggersRepository {
+	return &TriggersRepository{db: db}
+}
+
+// Create keeps the trigger pending for the user, once however many times it's created
+func (r *TriggersRepository) Create(ctx context.Context, userID string, name string) error {
+	stmt := "INSERT INTO pending_triggers (user_id, name) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+	if _, err := r.db.ExecContext(ctx, stmt, userID, name); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
+
+// Take removes the trigger of the user, telling if it was pending. Only one of the concurrent calls
+// takes it.
+func (r *TriggersRepository) Take(ctx context.Context, userID string, name string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM pending_triggers WHERE user_id = $1 AND name = $2", userID, name)
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+	return rows > 0, nil
+}
@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/tracing"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"math/rand"
+	"time"
+)
+
+// DefaultTransactionRetries is how many times an idempotent unit of work is run again after its
+// transaction failed to serialize, or deadlocked, with a concurrent one
+const DefaultTransactionRetries = 3
+
+const (
+	// transactionRetryBaseDelay and transactionRetryMaxDelay bound the random delay before running
+	// a unit of work again, doubled at every retry
+	transactionRetryBaseDelay = 10 * time.Millisecond
+	transactionRetryMaxDelay  = 250 * time.Millisecond
+
+	transactionRetriesMetric = "db_transaction_retries_total"
+)
+
+// queryer runs the statements of a repository: the database, or the transaction of a unit of work
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+	QueryRowxContext(ctx context.Context, query string, args ...interface{}) *sqlx.Row
+}
+
+// Repositories are the repositories of a unit of work, bound to its transaction
+type Repositories struct {
+	Users   *UsersRepository
+	Audit   *AuditRepository
+	Beers   *BeersRepository
+	Devices *DevicesRepository
+}
+
+// UnitOfWork runs the statements of several repositories in a single transaction.
+// The unit of work of repositories already bound to a transaction joins it, for the methods of a
+// repository running their own unit of work to be called from another one.
+type UnitOfWork struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+	// Timeout bounds every run of the transaction, none when 0
+	Timeout time.Duration
+	// StatementTimeout is the statement_timeout of the transaction, PostgreSQL canceling its
+	// statements running longer with ErrTimeout, the one of the session when 0
+	StatementTimeout time.Duration
+	// MaxRetries is how many times DoIdempotent runs the transaction again when it fails to
+	// serialize or deadlocks
+	MaxRetries int
+	// Metrics counts the retries in db_transaction_retries_total, by reason
+	Metrics metrics.Metrics
+}
+
+// NewUnitOfWork returns a UnitOfWork of the database, retrying DefaultTransactionRetries times
+func NewUnitOfWork(db *sqlx.DB) *UnitOfWork {
+	return &UnitOfWork{db: db, MaxRetries: DefaultTransactionRetries, Metrics: metrics.Noop{}}
+}
+
+// newRepositories returns the repositories bound to the transaction
+func newRepositories(tx *sqlx.Tx) Repositories {
+	uow := &UnitOfWork{tx: tx}
+	return Repositories{
+		Users:   &UsersRepository{db: newDBPair(tx), uow: uow},
+		Audit:   &AuditRepository{db: tx, uow: uow},
+		Beers:   &BeersRepository{db: newDBPair(tx)},
+		Devices: &DevicesRepository{db: tx},
+	}
+}
+
+// Do runs fn in a transaction, committed when fn returns nil and rolled back when it returns an
+// error or panics
+func (u *UnitOfWork) Do(ctx context.Context, fn func(r Repositories) error) (err error) {
+	if u.tx != nil {
+		return fn(newRepositories(u.tx))
+	}
+
+	ctx, span := startSpan(ctx, "UnitOfWork.Do")
+	defer func() { tracing.End(span, err) }()
+	return u.run(ctx, fn)
+}
+
+// DoIdempotent is similar to Do but, the transaction failing to serialize or deadlocking with a
+// concurrent one, runs fn again in a new one up to MaxRetries times, after a random delay. fn must
+// be safe to run several times: no effect outside of the database, nor state kept across runs.
+// Joining the transaction of bound repositories, it leaves the retries to the enclosing unit of work.
+func (u *UnitOfWork) DoIdempotent(ctx context.Context, fn func(r Repositories) error) (err error) {
+	if u.tx != nil {
+		return fn(newRepositories(u.tx))
+	}
+
+	ctx, span := startSpan(ctx, "UnitOfWork.DoIdempotent")
+	defer func() { tracing.End(span, err) }()
+
+	for attempt := 1; ; attempt++ {
+		err = u.run(ctx, fn)
+		reason := retryReason(err)
+		if reason == "" || attempt > u.MaxRetries {
+			return err
+		}
+		u.Metrics.IncCounter(transactionRetriesMetric, metrics.Labels{"reason": reason})
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(retryBackoff(attempt)):
+		}
+	}
+}
+
+// retryReason returns why a transaction failing with err is worth running again, empty when it isn't
+func retryReason(err error) string {
+	switch {
+	case errors.Is(err, ErrSerialization):
+		return "serialization"
+	case errors.Is(err, ErrDeadlock):
+		return "deadlock"
+	}
+	return ""
+}
+
+// retryBackoff returns the delay before the retry following the given attempt, with full jitter
+func retryBackoff(attempt int) time.Duration {
+	ceiling := transactionRetryMaxDelay
+	if shift := uint(attempt - 1); shift < 16 && transactionRetryBaseDelay<<shift < ceiling {
+		ceiling = transactionRetryBaseDelay << shift
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// run runs fn once in a transaction of its own
+func (u *UnitOfWork) run(ctx context.Context, fn func(r Repositories) error) (err error) {
+	if u.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, u.Timeout)
+		defer cancel()
+	}
+
+	tx, err := u.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return parseError(ctx, err)
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			tx.Rollback()
+			panic(recovered)
+		}
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+				err = fmt.Errorf("%w (rolling back: %v)", err, rollbackErr)
+			}
+		}
+	}()
+
+	if u.StatementTimeout > 0 {
+		// SET can't take parameters, the timeout is formatted in milliseconds
+		stmt := fmt.Sprintf("SET LOCAL statement_timeout = %d", u.StatementTimeout.Milliseconds())
+		if _, err = tx.ExecContext(ctx, stmt); err != nil {
+			return parseError(ctx, err)
+		}
+	}
+
+	// database/sql rolls the transaction back as soon as ctx is done, the statement running being
+	// canceled, and the error is the one of ctx for the caller to tell the cancellation apart
+	if err = fn(newRepositories(tx)); err != nil {
+		return contextError(ctx, err)
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package repositories
+
+import (
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/testsupport"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	ana := &User{ID: "1", Name: "Ana", Email: "ana@cloudoki.com"}
+	rui := &User{ID: "2", Name: "Rui", Email: "rui@cloudoki.com"}
+	errAbort := errors.New("abort")
+
+	// writeAll creates the users, a beer transfer between them and an audit entry
+	writeAll := func(repos Repositories) error {
+		for _, user := range []*User{ana, rui} {
+			if _, _, err := repos.Users.FindOrCreateUser(ctx, user); err != nil {
+				return err
+			}
+		}
+		if _, err := repos.Users.AddBeerTransfer(ctx, "1", "2", 3); err != nil {
+			return err
+		}
+		return repos.Audit.Record(ctx, []*AuditEntry{{ActorID: "1", Action: AuditUserRoleChanged, TargetID: "2"}})
+	}
+	// countAll counts the users, beer transfers and audit entries
+	countAll := func(t *testing.T, uow *UnitOfWork) (counts [3]int) {
+		t.Helper()
+		for i, table := range []string{"users", "beer_transfers", "audit_log"} {
+			if err := uow.db.GetContext(ctx, &counts[i], "SELECT COUNT(*) FROM "+table); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return counts
+	}
+
+	t.Run("expect the writes of every repository to be committed together", func(t *testing.T) {
+		uow := NewUnitOfWork(testsupport.NewDB(t))
+
+		if err := uow.Do(ctx, writeAll); err != nil {
+			t.Fatal(err)
+		}
+
+		if counts := countAll(t, uow); counts != [3]int{2, 1, 1} {
+			t.Fatalf("expected every write to be committed, got %v", counts)
+		}
+	})
+
+	t.Run("expect the partial writes to be rolled back on error", func(t *testing.T) {
+		uow := NewUnitOfWork(testsupport.NewDB(t))
+
+		err := uow.Do(ctx, func(repos Repositories) error {
+			if err := writeAll(repos); err != nil {
+				return err
+			}
+			return errAbort
+		})
+
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("expected the error of the callback, got %v", err)
+		}
+		if counts := countAll(t, uow); counts != [3]int{} {
+			t.Fatalf("expected every write to be rolled back, got %v", counts)
+		}
+	})
+
+	t.Run("expect the partial writes to be rolled back on panic, the panic going on", func(t *testing.T) {
+		uow := NewUnitOfWork(testsupport.NewDB(t))
+
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != "boom" {
+					t.Fatalf("expected the panic to go on, got %v", recovered)
+				}
+			}()
+			uow.Do(ctx, func(repos Repositories) error {
+				if err := writeAll(repos); err != nil {
+					return err
+				}
+				panic("boom")
+			})
+		}()
+
+		if counts := countAll(t, uow); counts != [3]int{} {
+			t.Fatalf("expected every write to be rolled back, got %v", counts)
+		}
+	})
+
+	t.Run("expect a failing statement to roll back the ones before it", func(t *testing.T) {
+		uow := NewUnitOfWork(testsupport.NewDB(t))
+
+		err := uow.Do(ctx, func(repos Repositories) error {
+			if _, _, err := repos.Users.FindOrCreateUser(ctx, ana); err != nil {
+				return err
+			}
+			_, err := repos.Users.AddBeerTransfer(ctx, "1", "42", 3)
+			return err
+		})
+
+		if !errors.Is(err, ErrForeignKey) {
+			t.Fatalf("expected a foreign key violation, got %v", err)
+		}
+		if counts := countAll(t, uow); counts != [3]int{} {
+			t.Fatalf("expected the user to be rolled back, got %v", counts)
+		}
+	})
+
+	t.Run("expect a serialization failure to run an idempotent unit of work again, up to MaxRetries times", func(t *testing.T) {
+		uow := NewUnitOfWork(testsupport.NewDB(t))
+		fake := metrics.NewFake()
+		uow.Metrics = fake
+		serialization := &DatabaseError{Kind: ErrSerialization, Err: errors.New("could not serialize access")}
+
+		attempts := 0
+		err := uow.DoIdempotent(ctx, func(repos Repositories) error {
+			attempts++
+			if err := writeAll(repos); err != nil || attempts < 3 {
+				return serialization
+			}
+			return nil
+		})
+		if err != nil || attempts != 3 {
+			t.Fatalf("expected the third attempt to commit, got %d attempts, %v", attempts, err)
+		}
+		if counts := countAll(t, uow); counts != [3]int{2, 1, 1} {
+			t.Fatalf("expected the writes of the last attempt only, got %v", counts)
+		}
+		if retries := fake.Counter(transactionRetriesMetric, metrics.Labels{"reason": "serialization"}); retries != 2 {
+			t.Fatalf("expected 2 retries to be counted, got %v", retries)
+		}
+
+		attempts = 0
+		err = uow.DoIdempotent(ctx, func(repos Repositories) error {
+			attempts++
+			return serialization
+		})
+		if !errors.Is(err, ErrSerialization) || attempts != DefaultTransactionRetries+1 {
+			t.Fatalf("expected the failure after %d attempts, got %d, %v", DefaultTransactionRetries+1, attempts, err)
+		}
+	})
+
+	t.Run("expect a unit of work not marked idempotent to run once", func(t *testing.T) {
+		uow := NewUnitOfWork(testsupport.NewDB(t))
+
+		attempts := 0
+		err := uow.Do(ctx, func(repos Repositories) error {
+			attempts++
+			return &DatabaseError{Kind: ErrDeadlock, Err: errors.New("deadlock detected")}
+		})
+
+		if !errors.Is(err, ErrDeadlock) || attempts != 1 {
+			t.Fatalf("expected a single attempt, got %d, %v", attempts, err)
+		}
+	})
+
+	t.Run("expect two transactions deadlocking on the same rows to both succeed, the aborted one being retried", func(t *testing.T) {
+		uow := NewUnitOfWork(testsupport.NewDB(t))
+		fake := metrics.NewFake()
+		uow.Metrics = fake
+		if err := uow.Do(ctx, writeAll); err != nil {
+			t.Fatal(err)
+		}
+
+		// both first attempts lock their first row before either locks its second one, the other way round
+		var locked sync.WaitGroup
+		locked.Add(2)
+		rename := func(first string, second string, name string) error {
+			attempts := 0
+			return uow.DoIdempotent(ctx, func(repos Repositories) error {
+				attempts++
+				for i, id := range []string{first, second} {
+					if _, err := repos.Users.db.writer().ExecContext(ctx, "UPDATE users SET name = $1 WHERE id = $2", name, id); err != nil {
+						return parseError(ctx, err)
+					}
+					if i == 0 && attempts == 1 {
+						locked.Done()
+						locked.Wait()
+					}
+				}
+				return nil
+			})
+		}
+
+		errs := make(chan error, 2)
+		go func() { errs <- rename("1", "2", "Ana") }()
+		go func() { errs <- rename("2", "1", "Rui") }()
+		for i := 0; i < 2; i++ {
+			if err := <-errs; err != nil {
+				t.Fatalf("expected both transactions to succeed, got %v", err)
+			}
+		}
+
+		if retries := fake.Counter(transactionRetriesMetric, metrics.Labels{"reason": "deadlock"}); retries != 1 {
+			t.Fatalf("expected the deadlock to be retried once, got %v", retries)
+		}
+		var names []string
+		if err := uow.db.SelectContext(ctx, &names, "SELECT DISTINCT name FROM users"); err != nil || len(names) != 1 {
+			t.Fatalf("expected both users renamed by the same transaction, got %v, %v", names, err)
+		}
+	})
+
+	t.Run("expect the delay before a retry to be random, doubled up to its ceiling", func(t *testing.T) {
+		for attempt, ceiling := range map[int]time.Duration{1: 10 * time.Millisecond, 3: 40 * time.Millisecond, 10: transactionRetryMaxDelay} {
+			for i := 0; i < 100; i++ {
+				if delay := retryBackoff(attempt); delay < 0 || delay > ceiling {
+					t.Fatalf("expected the delay after attempt %d to be up to %v, got %v", attempt, ceiling, delay)
+				}
+			}
+		}
+	})
+
+	t.Run("expect the cancellation of the context to stop the statement and roll the transaction back at once", func(t *testing.T) {
+		uow := NewUnitOfWork(testsupport.NewDB(t))
+		canceled, cancel := context.WithCancel(ctx)
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		start := time.Now()
+		err := uow.Do(canceled, func(repos Repositories) error {
+			if _, _, err := repos.Users.FindOrCreateUser(canceled, ana); err != nil {
+				return err
+			}
+			_, err := repos.Users.db.writer().ExecContext(canceled, "SELECT pg_sleep(10)")
+			return parseError(canceled, err)
+		})
+
+		if !errors.Is(err, context.Canceled) || time.Since(start) > 2*time.Second {
+			t.Fatalf("expected context.Canceled promptly, got %v after %s", err, time.Since(start))
+		}
+		if counts := countAll(t, uow); counts != [3]int{} {
+			t.Fatalf("expected the user to be rolled back, got %v", counts)
+		}
+	})
+
+	t.Run("expect a statement running longer than the statement_timeout to be canceled by PostgreSQL", func(t *testing.T) {
+		uow := NewUnitOfWork(testsupport.NewDB(t))
+		uow.StatementTimeout = 50 * time.Millisecond
+
+		start := time.Now()
+		err := uow.Do(ctx, func(repos Repositories) error {
+			_, err := repos.Users.db.writer().ExecContext(ctx, "SELECT pg_sleep(5)")
+			return parseError(ctx, err)
+		})
+
+		if !errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 2*time.Second {
+			t.Fatalf("expected ErrTimeout promptly, got %v after %s", err, time.Since(start))
+		}
+		if err := uow.Do(ctx, func(repos Repositories) error {
+			_, err := repos.Users.db.writer().ExecContext(ctx, "SELECT pg_sleep(0.01)")
+			return err
+		}); err != nil {
+			t.Fatalf("expected the statements within the timeout to run, got %v", err)
+		}
+	})
+
+	t.Run("expect the timeout to bound the transaction", func(t *testing.T) {
+		uow := NewUnitOfWork(testsupport.NewDB(t))
+		uow.Timeout = 50 * time.Millisecond
+
+		err := uow.Do(ctx, func(repos Repositories) error {
+			_, err := repos.Users.db.writer().ExecContext(ctx, "SELECT pg_sleep(1)")
+			return err
+		})
+
+		if err == nil {
+			t.Fatal("expected the transaction to time out")
+		}
+	})
+}
@@ -1,18 +1,33 @@
 package repositories
 
 import (
+	"appdoki-be/app/filter"
 	"context"
 	"database/sql"
 	"errors"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"time"
 )
 
-// User model
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// ErrUserDeactivated is returned for the users deactivated by an admin, which can't sign in nor give beers
+var ErrUserDeactivated = errors.New("the user is deactivated")
+
+// User model. Its columns are all NOT NULL, defaulting to empty for the optional ones: the users
+// without a picture have an empty one, never null, in the queries and in JSON alike. The role is
+// left out of its JSON, the users being listed to everyone, and told only to the user itself.
 type User struct {
 	ID      string `json:"id" db:"id"`
 	Name    string `json:"name" db:"name"`
 	Email   string `json:"email" db:"email"`
 	Picture string `json:"picture" db:"picture"`
+	Role    string `json:"-" db:"role"`
 }
 
 type UserBeerLog struct {
@@ -20,119 +35,180 @@ type UserBeerLog struct {
 	Received int `json:"received" db:"received"`
 }
 
-// UsersRepositoryInterface defines the set of User related methods available
+// UsersRepositoryInterface defines the set of User related methods available.
+// Collections are returned as empty slices, never nil, when nothing is found.
+// Deactivated users are left out of GetAll, FindByID and FindByEmail, FindOrCreateUser and
+// AddBeerTransfer failing with ErrUserDeactivated for them.
 type UsersRepositoryInterface interface {
-	GetAll(ctx context.Context) ([]*User, error)
+	GetAll(ctx context.Context, f filter.Filter) ([]*User, error)
+	LastModified(ctx context.Context) (time.Time, error)
 	FindByID(ctx context.Context, ID string) (*User, error)
 	FindByEmail(ctx context.Context, email string) (*User, error)
+	IsDeactivated(ctx context.Context, ID string) (bool, error)
 	FindOrCreateUser(ctx context.Context, userData *User) (*User, bool, error)
 	Create(ctx context.Context, user *User) (*User, error)
 	Update(ctx context.Context, user *User) (*User, error)
 	Delete(ctx context.Context, ID string) (bool, error)
+	DeactivateMany(ctx context.Context, IDs []string) ([]string, error)
+	SetRoleMany(ctx context.Context, IDs []string, role string) ([]string, error)
 	AddBeerTransfer(ctx context.Context, giverID string, takerID string, beers int) (int, error)
 	GetBeerTransfersSummary(ctx context.Context, userID string) (*UserBeerLog, error)
 }
 
 // UsersRepository implements UsersRepositoryInterface
 type UsersRepository struct {
-	db *sqlx.DB
+	db  dbPair
+	uow *UnitOfWork
 }
 
 // NewUsersRepository returns a configured UsersRepository object
 func NewUsersRepository(db *sqlx.DB) *UsersRepository {
-	return &UsersRepository{db: db}
+	return &UsersRepository{db: newDBPair(db), uow: NewUnitOfWork(db)}
+}
+
+// WithUnitOfWork returns a copy of the repository running its transactions in uow
+func (r *UsersRepository) WithUnitOfWork(uow *UnitOfWork) *UsersRepository {
+	return &UsersRepository{db: r.db, uow: uow}
 }
 
-// GetAll fetches all users, returns an empty slice if no user exists
-func (r *UsersRepository) GetAll(ctx context.Context) ([]*User, error) {
+// WithReplica returns a copy of the repository reading from the replica, unless the context reads
+// fresh, FindOrCreateUser and the writes going to the primary. A nil replica reads from the primary.
+func (r *UsersRepository) WithReplica(replica *sqlx.DB) *UsersRepository {
+	return &UsersRepository{db: r.db.withReplica(replica), uow: r.uow}
+}
+
+// GetAll fetches the active users matching the filter, returns an empty slice if no user matches
+func (r *UsersRepository) GetAll(ctx context.Context, f filter.Filter) ([]*User, error) {
+	query := "SELECT id, name, email, picture, role FROM users WHERE deactivated_at IS NULL"
+	where, args := filterClause(f, 1)
+	if where != "" {
+		query += " AND " + where
+	}
+
 	users := []*User{}
-	err := r.db.SelectContext(ctx, &users, "SELECT id, name, email, picture FROM users")
+	err := r.db.reader(ctx).SelectContext(ctx, &users, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, parseError(ctx, err)
 	}
 
 	return users, nil
 }
 
-// FindByID finds a user by ID, returns nil if not found
+// LastModified returns when any user last changed
+func (r *UsersRepository) LastModified(ctx context.Context) (time.Time, error) {
+	return collectionsLastModified(ctx, r.db.reader(ctx), "users")
+}
+
+// FindByID finds an active user by ID, returns nil if not found
 func (r *UsersRepository) FindByID(ctx context.Context, ID string) (*User, error) {
 	user := &User{}
-	err := r.db.GetContext(ctx, user, "SELECT id, name, email, picture FROM users WHERE id = $1", ID)
+	stmt := "SELECT id, name, email, picture, role FROM users WHERE id = $1 AND deactivated_at IS NULL"
+	err := r.db.reader(ctx).GetContext(ctx, user, stmt, ID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
-		return nil, err
+		return nil, parseError(ctx, err)
 	}
 	return user, nil
 }
 
-// FindByEmail finds a user by email, returns nil if not found
+// FindByEmail finds an active user by email, returns nil if not found
 func (r *UsersRepository) FindByEmail(ctx context.Context, email string) (*User, error) {
 	user := &User{}
-	stmt := "SELECT id, name, email, picture FROM users WHERE email = $1"
-	err := r.db.GetContext(ctx, user, stmt, email)
+	stmt := "SELECT id, name, email, picture, role FROM users WHERE email = $1 AND deactivated_at IS NULL"
+	err := r.db.reader(ctx).GetContext(ctx, user, stmt, email)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
-		return nil, err
+		return nil, parseError(ctx, err)
 	}
 	return user, nil
 }
 
+// IsDeactivated checks if the user was deactivated, the users not found yet, ex.: signing in for
+// the first time, not being deactivated. It reads from the primary database, for a deactivation to
+// be seen at once, the replica lagging behind.
+func (r *UsersRepository) IsDeactivated(ctx context.Context, ID string) (bool, error) {
+	var deactivated bool
+	stmt := "SELECT deactivated_at IS NOT NULL FROM users WHERE id = $1"
+	err := r.db.reader(ReadFresh(ctx)).GetContext(ctx, &deactivated, stmt, ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, parseError(ctx, err)
+	}
+	return deactivated, nil
+}
+
 // FindOrCreateUser finds a user by ID and creates it if not found
 // returns a boolean indicating if the user was created
-// TODO deal with passing txn around
+// It runs in a unit of work, the one of the repository when bound to a transaction.
+// A deactivated user isn't returned, ErrUserDeactivated is.
 func (r *UsersRepository) FindOrCreateUser(ctx context.Context, userData *User) (*User, bool, error) {
-	tx, err := r.db.BeginTxx(ctx, nil)
+	var user *User
+	var created bool
+	err := r.uow.DoIdempotent(ctx, func(repos Repositories) error {
+		var err error
+		user, created, err = repos.Users.findOrCreateUser(ctx, userData)
+		return err
+	})
 	if err != nil {
 		return nil, false, err
 	}
-	defer tx.Rollback()
+	return user, created, nil
+}
 
-	user := &User{}
-	selectStmt := "SELECT id, name, email, picture FROM users WHERE id = $1"
-	err = tx.GetContext(ctx, user, selectStmt, userData.ID)
+// findOrCreateUser is FindOrCreateUser, the repository being bound to the transaction
+func (r *UsersRepository) findOrCreateUser(ctx context.Context, userData *User) (*User, bool, error) {
+	var found struct {
+		User
+		Deactivated bool `db:"deactivated"`
+	}
+	selectStmt := "SELECT id, name, email, picture, role, deactivated_at IS NOT NULL AS deactivated FROM users WHERE id = $1"
+	err := r.db.writer().GetContext(ctx, &found, selectStmt, userData.ID)
 	if err == nil {
-		return user, false, nil
+		if found.Deactivated {
+			return nil, false, ErrUserDeactivated
+		}
+		return &found.User, false, nil
 	}
 	if err != sql.ErrNoRows {
-		return nil, false, parseError(err)
+		return nil, false, parseError(ctx, err)
 	}
 
-	insertStmt := "INSERT INTO users (id, name, email, picture) VALUES ($1, $2, $3, $4)"
-	res, err := tx.ExecContext(ctx, insertStmt, userData.ID, userData.Name, userData.Email, userData.Picture)
+	// a concurrent call creating the same user first, the insert waits for it and does nothing
+	insertStmt := "INSERT INTO users (id, name, email, picture) VALUES ($1, $2, $3, $4) ON CONFLICT (id) DO NOTHING"
+	res, err := r.db.writer().ExecContext(ctx, insertStmt, userData.ID, userData.Name, userData.Email, userData.Picture)
 	if err != nil {
-		return nil, false, parseError(err)
+		return nil, false, parseError(ctx, err)
 	}
-
-	if rows, err := res.RowsAffected(); err != nil {
-		if rows == 0 {
-			return nil, false, errors.New("could not create user")
-		}
-		return nil, false, parseError(err)
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return nil, false, parseError(ctx, err)
 	}
 
-	err = tx.GetContext(ctx, user, selectStmt, userData.ID)
+	err = r.db.writer().GetContext(ctx, &found, selectStmt, userData.ID)
 	if err != nil {
-		return nil, false, parseError(err)
+		return nil, false, parseError(ctx, err)
 	}
-
-	if err := tx.Commit(); err != nil {
-		return nil, false, parseError(err)
+	// the user a concurrent call created may have been deactivated since
+	if found.Deactivated {
+		return nil, false, ErrUserDeactivated
 	}
 
-	return user, true, nil
+	return &found.User, rows > 0, nil
 }
 
-// Create creates a new user, returning the full model
+// Create creates a new user of the given ID, returning the full model
 func (r *UsersRepository) Create(ctx context.Context, user *User) (*User, error) {
-	stmt := "INSERT INTO users (name, email) VALUES ($1, $2) RETURNING id"
-	row := r.db.QueryRowxContext(ctx, stmt, user.Name, user.Email)
-	err := row.Scan(&user.ID)
+	stmt := "INSERT INTO users (id, name, email, picture) VALUES ($1, $2, $3, $4) RETURNING role"
+	row := r.db.writer().QueryRowxContext(ctx, stmt, user.ID, user.Name, user.Email, user.Picture)
+	err := row.Scan(&user.Role)
 	if err != nil {
-		return nil, parseError(err)
+		return nil, parseError(ctx, err)
 	}
 	return user, nil
 }
@@ -140,9 +216,9 @@ func (r *UsersRepository) Create(ctx context.Context, user *User) (*User, error)
 // Update updates a user, returning the updated model or nil if no rows were affected
 func (r *UsersRepository) Update(ctx context.Context, user *User) (*User, error) {
 	stmt := "UPDATE users SET name = $1, email = $2 WHERE id = $3"
-	res, err := r.db.ExecContext(ctx, stmt, user.Name, user.Email, user.ID)
+	res, err := r.db.writer().ExecContext(ctx, stmt, user.Name, user.Email, user.ID)
 	if err != nil {
-		return nil, parseError(err)
+		return nil, parseError(ctx, err)
 	}
 
 	rows, err := res.RowsAffected()
@@ -155,12 +231,13 @@ func (r *UsersRepository) Update(ctx context.Context, user *User) (*User, error)
 	return user, nil
 }
 
-// Delete deletes a user, only returns error if action fails
+// Delete deletes a user, only returns error if action fails.
+// Users still referenced by other records can't be deleted, a ConflictError is returned.
 func (r *UsersRepository) Delete(ctx context.Context, ID string) (bool, error) {
 	stmt := "DELETE FROM users WHERE id = $1 RETURNING id"
-	res, err := r.db.ExecContext(ctx, stmt, ID)
+	res, err := r.db.writer().ExecContext(ctx, stmt, ID)
 	if err != nil {
-		return false, err
+		return false, parseError(ctx, err)
 	}
 	rows, err := res.RowsAffected()
 	if err != nil {
@@ -169,13 +246,43 @@ func (r *UsersRepository) Delete(ctx context.Context, ID string) (bool, error) {
 	return rows > 0, nil
 }
 
-// AddBeerTransfer adds a beer transference record between two users
+// DeactivateMany deactivates the users in a single statement, returns the IDs of those found.
+// Users already deactivated keep their first deactivation time.
+func (r *UsersRepository) DeactivateMany(ctx context.Context, IDs []string) ([]string, error) {
+	stmt := "UPDATE users SET deactivated_at = COALESCE(deactivated_at, now()) WHERE id = ANY($1) RETURNING id"
+	found := []string{}
+	err := r.db.writer().SelectContext(ctx, &found, stmt, pq.Array(IDs))
+	if err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return found, nil
+}
+
+// SetRoleMany sets the role of the users in a single statement, returns the IDs of those found
+func (r *UsersRepository) SetRoleMany(ctx context.Context, IDs []string, role string) ([]string, error) {
+	stmt := "UPDATE users SET role = $1 WHERE id = ANY($2) RETURNING id"
+	found := []string{}
+	err := r.db.writer().SelectContext(ctx, &found, stmt, role, pq.Array(IDs))
+	if err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return found, nil
+}
+
+// AddBeerTransfer adds a beer transference record between two users, failing with ErrUserDeactivated
+// when either of them is deactivated
 func (r *UsersRepository) AddBeerTransfer(ctx context.Context, giverID string, takerID string, beers int) (int, error) {
-	stmt := "INSERT INTO beer_transfers (giver_id, taker_id, beers) VALUES ($1, $2, $3) RETURNING id"
+	stmt := `INSERT INTO beer_transfers (giver_id, taker_id, beers)
+		SELECT $1::text, $2::text, $3::int
+		WHERE NOT EXISTS (SELECT 1 FROM users WHERE id IN ($1, $2) AND deactivated_at IS NOT NULL)
+		RETURNING id`
 	var newID int
-	err := r.db.GetContext(ctx, &newID, stmt, giverID, takerID, beers)
+	err := r.db.writer().GetContext(ctx, &newID, stmt, giverID, takerID, beers)
+	if err == sql.ErrNoRows {
+		return 0, ErrUserDeactivated
+	}
 	if err != nil {
-		return 0, parseError(err)
+		return 0, parseError(ctx, err)
 	}
 
 	return newID, nil
@@ -186,15 +293,15 @@ func (r *UsersRepository) GetBeerTransfersSummary(ctx context.Context, userID st
 	beerLog := &UserBeerLog{}
 
 	giverQuery := "SELECT COALESCE(SUM(beers), 0) AS given FROM beer_transfers WHERE giver_id = $1"
-	err := r.db.GetContext(ctx, beerLog, giverQuery, userID)
+	err := r.db.reader(ctx).GetContext(ctx, beerLog, giverQuery, userID)
 	if err != nil {
-		return nil, parseError(err)
+		return nil, parseError(ctx, err)
 	}
 
 	receivedQuery := "SELECT COALESCE(SUM(beers), 0) AS received FROM beer_transfers WHERE taker_id = $1"
-	err = r.db.GetContext(ctx, beerLog, receivedQuery, userID)
+	err = r.db.reader(ctx).GetContext(ctx, beerLog, receivedQuery, userID)
 	if err != nil {
-		return nil, parseError(err)
+		return nil, parseError(ctx, err)
 	}
 
 	return beerLog, nil
@@ -0,0 +1,277 @@
+package repositories
+
+import (
+	"appdoki-be/app/filter"
+	"appdoki-be/app/testsupport"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUsersRepository(t *testing.T) {
+	ctx := context.Background()
+	newRepository := func(t *testing.T) *UsersRepository {
+		t.Helper()
+		return NewUsersRepository(testsupport.NewDB(t))
+	}
+	seed := func(t *testing.T, r *UsersRepository, users ...*User) {
+		t.Helper()
+		for _, user := range users {
+			if _, created, err := r.FindOrCreateUser(ctx, user); err != nil || !created {
+				t.Fatalf("seeding %s: created %v, %v", user.ID, created, err)
+			}
+		}
+	}
+	ana := &User{ID: "1", Name: "Ana", Email: "ana@cloudoki.com", Picture: "https://example.com/ana.png"}
+	rui := &User{ID: "2", Name: "Rui", Email: "rui@cloudoki.com"}
+
+	t.Run("expect a user to be created once, then found", func(t *testing.T) {
+		r := newRepository(t)
+
+		user, created, err := r.FindOrCreateUser(ctx, ana)
+		if err != nil || !created || user.Role != RoleUser {
+			t.Fatalf("expected the user to be created with the user role, got %+v, %v, %v", user, created, err)
+		}
+		if _, created, _ := r.FindOrCreateUser(ctx, ana); created {
+			t.Fatal("expected the user to be found the second time")
+		}
+
+		found, err := r.FindByEmail(ctx, "ana@cloudoki.com")
+		if err != nil || found == nil || found.ID != "1" || found.Picture != ana.Picture {
+			t.Fatalf("expected the user to be found by email, got %+v, %v", found, err)
+		}
+		if missing, err := r.FindByID(ctx, "42"); missing != nil || err != nil {
+			t.Fatalf("expected nil for a missing user, got %+v, %v", missing, err)
+		}
+	})
+
+	t.Run("expect the users created with and without a picture to be read alike, an empty picture for none", func(t *testing.T) {
+		r := newRepository(t)
+		seed(t, r, ana)
+		created, err := r.Create(ctx, &User{ID: "2", Name: "Rui", Email: "rui@cloudoki.com"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		users, err := r.GetAll(ctx, filter.Filter{})
+		if err != nil || len(users) != 2 {
+			t.Fatalf("expected both users, got %+v, %v", users, err)
+		}
+		byID, err := r.FindByID(ctx, created.ID)
+		if err != nil || byID == nil || byID.Picture != "" {
+			t.Fatalf("expected an empty picture, got %+v, %v", byID, err)
+		}
+		byEmail, err := r.FindByEmail(ctx, "ana@cloudoki.com")
+		if err != nil || byEmail == nil || byEmail.Picture != ana.Picture {
+			t.Fatalf("expected the picture, got %+v, %v", byEmail, err)
+		}
+		found, _, err := r.FindOrCreateUser(ctx, &User{ID: created.ID})
+		if err != nil || found.Picture != "" {
+			t.Fatalf("expected an empty picture, got %+v, %v", found, err)
+		}
+		if body, _ := json.Marshal(byID); !strings.Contains(string(body), `"picture":""`) {
+			t.Fatalf("expected an empty picture in JSON, got %s", body)
+		}
+	})
+
+	t.Run("expect a query waiting on a lock to stop once its context is canceled", func(t *testing.T) {
+		r := newRepository(t)
+		seed(t, r, ana)
+		// a transaction holding the users, until the test is done
+		lock, err := r.uow.db.BeginTxx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer lock.Rollback()
+		if _, err := lock.ExecContext(ctx, "LOCK TABLE users IN ACCESS EXCLUSIVE MODE"); err != nil {
+			t.Fatal(err)
+		}
+
+		canceled, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if _, err := r.FindByID(canceled, "1"); !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 2*time.Second {
+			t.Fatalf("expected context.DeadlineExceeded promptly, got %v after %s", err, time.Since(start))
+		}
+		canceled, cancel = context.WithCancel(ctx)
+		time.AfterFunc(100*time.Millisecond, cancel)
+		if _, _, err := r.FindOrCreateUser(canceled, rui); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	})
+
+	t.Run("expect the deactivated users to be left out", func(t *testing.T) {
+		r := newRepository(t)
+		seed(t, r, ana, rui)
+
+		deactivated, err := r.DeactivateMany(ctx, []string{"2", "42"})
+		if err != nil || len(deactivated) != 1 || deactivated[0] != "2" {
+			t.Fatalf("expected the found user only to be deactivated, got %v, %v", deactivated, err)
+		}
+
+		users, err := r.GetAll(ctx, filter.Filter{})
+		if err != nil || len(users) != 1 || users[0].ID != "1" {
+			t.Fatalf("expected the active users only, got %+v, %v", users, err)
+		}
+		if found, _ := r.FindByID(ctx, "2"); found != nil {
+			t.Fatalf("expected the deactivated user not to be found, got %+v", found)
+		}
+		if found, _ := r.FindByEmail(ctx, rui.Email); found != nil {
+			t.Fatalf("expected the deactivated user not to be found by email, got %+v", found)
+		}
+		if deactivated, err := r.IsDeactivated(ctx, "2"); err != nil || !deactivated {
+			t.Fatalf("expected the user to be deactivated, got %v, %v", deactivated, err)
+		}
+		if deactivated, err := r.IsDeactivated(ctx, "42"); err != nil || deactivated {
+			t.Fatalf("expected a user yet to sign in not to be deactivated, got %v, %v", deactivated, err)
+		}
+	})
+
+	t.Run("expect the deactivated users not to sign in again, nor give or receive beers", func(t *testing.T) {
+		r := newRepository(t)
+		seed(t, r, ana, rui)
+		if _, err := r.DeactivateMany(ctx, []string{"2"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if user, _, err := r.FindOrCreateUser(ctx, rui); !errors.Is(err, ErrUserDeactivated) {
+			t.Fatalf("expected ErrUserDeactivated, got %+v, %v", user, err)
+		}
+		if _, err := r.AddBeerTransfer(ctx, "2", "1", 3); !errors.Is(err, ErrUserDeactivated) {
+			t.Fatalf("expected the deactivated giver to be refused, got %v", err)
+		}
+		if _, err := r.AddBeerTransfer(ctx, "1", "2", 3); !errors.Is(err, ErrUserDeactivated) {
+			t.Fatalf("expected the deactivated taker to be refused, got %v", err)
+		}
+		if summary, err := r.GetBeerTransfersSummary(ctx, "1"); err != nil || summary.Given != 0 || summary.Received != 0 {
+			t.Fatalf("expected no transfer, got %+v, %v", summary, err)
+		}
+	})
+
+	t.Run("expect a user to be updated and given a role", func(t *testing.T) {
+		r := newRepository(t)
+		seed(t, r, ana)
+
+		updated, err := r.Update(ctx, &User{ID: "1", Name: "Ana Maria", Email: "ana.maria@cloudoki.com"})
+		if err != nil || updated == nil {
+			t.Fatalf("expected the user to be updated, got %v", err)
+		}
+		if missing, err := r.Update(ctx, &User{ID: "42", Name: "Nobody", Email: "nobody@cloudoki.com"}); missing != nil || err != nil {
+			t.Fatalf("expected nil for a missing user, got %+v, %v", missing, err)
+		}
+		if _, err := r.SetRoleMany(ctx, []string{"1"}, RoleAdmin); err != nil {
+			t.Fatal(err)
+		}
+
+		found, _ := r.FindByID(ctx, "1")
+		if found.Name != "Ana Maria" || found.Email != "ana.maria@cloudoki.com" || found.Role != RoleAdmin {
+			t.Fatalf("unexpected user %+v", found)
+		}
+	})
+
+	t.Run("expect a duplicated email to conflict", func(t *testing.T) {
+		r := newRepository(t)
+		seed(t, r, ana, rui)
+
+		_, err := r.Update(ctx, &User{ID: "2", Name: "Rui", Email: "ana@cloudoki.com"})
+
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("expected a ConflictError, got %v", err)
+		}
+		if _, err := r.Create(ctx, &User{ID: "3", Name: "Ana", Email: "ana@cloudoki.com"}); !errors.Is(err, ErrDuplicate) {
+			t.Fatalf("expected the created user to be a duplicate, got %v", err)
+		}
+		if _, _, err := r.FindOrCreateUser(ctx, &User{ID: "3", Name: "Ana", Email: "ana@cloudoki.com"}); !errors.Is(err, ErrDuplicate) {
+			t.Fatalf("expected the found or created user to be a duplicate, got %v", err)
+		}
+	})
+
+	t.Run("expect concurrent calls of FindOrCreateUser to create the user once, all of them finding it", func(t *testing.T) {
+		r := newRepository(t)
+
+		const calls = 8
+		var ready sync.WaitGroup
+		ready.Add(calls)
+		createdCount := make(chan bool, calls)
+		errs := make(chan error, calls)
+		for i := 0; i < calls; i++ {
+			go func() {
+				ready.Done()
+				ready.Wait()
+				user, created, err := r.FindOrCreateUser(ctx, ana)
+				if err == nil && user.ID != "1" {
+					err = errors.New("found another user " + user.ID)
+				}
+				createdCount <- created
+				errs <- err
+			}()
+		}
+
+		created := 0
+		for i := 0; i < calls; i++ {
+			if err := <-errs; err != nil {
+				t.Fatalf("expected every call to find the user, got %v", err)
+			}
+			if <-createdCount {
+				created++
+			}
+		}
+		if created != 1 {
+			t.Fatalf("expected the user to be created once, got %d", created)
+		}
+	})
+
+	t.Run("expect a user created with its ID to get the default role, then to be deleted once", func(t *testing.T) {
+		r := newRepository(t)
+		before, err := r.LastModified(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		created, err := r.Create(ctx, &User{ID: "3", Name: "Eva", Email: "eva@cloudoki.com"})
+		if err != nil || created.Role != RoleUser {
+			t.Fatalf("expected the user with the user role, got %+v, %v", created, err)
+		}
+		if after, err := r.LastModified(ctx); err != nil || !after.After(before) {
+			t.Fatalf("expected the users to be modified after %v, got %v, %v", before, after, err)
+		}
+
+		if deleted, err := r.Delete(ctx, "3"); !deleted || err != nil {
+			t.Fatalf("expected the user to be deleted, got %v, %v", deleted, err)
+		}
+		if deleted, err := r.Delete(ctx, "3"); deleted || err != nil {
+			t.Fatalf("expected nothing deleted the second time, got %v, %v", deleted, err)
+		}
+		if found, err := r.SetRoleMany(ctx, []string{"3"}, RoleAdmin); err != nil || len(found) != 0 {
+			t.Fatalf("expected the deleted user not to be found, got %v, %v", found, err)
+		}
+	})
+
+	t.Run("expect the beer transfers to be summed up, and their users not to be deleted", func(t *testing.T) {
+		r := newRepository(t)
+		seed(t, r, ana, rui)
+
+		for _, beers := range []int{2, 3} {
+			if _, err := r.AddBeerTransfer(ctx, "1", "2", beers); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		summary, err := r.GetBeerTransfersSummary(ctx, "2")
+		if err != nil || summary.Given != 0 || summary.Received != 5 {
+			t.Fatalf("expected 5 beers received, got %+v, %v", summary, err)
+		}
+		var conflict *ConflictError
+		if _, err := r.Delete(ctx, "1"); !errors.As(err, &conflict) {
+			t.Fatalf("expected a ConflictError, got %v", err)
+		}
+		if deleted, err := r.Delete(ctx, "42"); deleted || err != nil {
+			t.Fatalf("expected nothing deleted, got %v, %v", deleted, err)
+		}
+	})
+}
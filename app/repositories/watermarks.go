@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"github.com/lib/pq"
+	"time"
+)
+
+// collectionsLastModified returns when any row of the collections (table names) last changed, as
+// recorded by the triggers of the collection_watermarks table. The zero time is returned when unknown.
+func collectionsLastModified(ctx context.Context, db queryer, collections ...string) (time.Time, error) {
+	var lastModified sql.NullTime
+	err := db.QueryRowxContext(ctx,
+		"SELECT max(modified_at) FROM collection_watermarks WHERE collection = ANY($1);",
+		pq.Array(collections)).Scan(&lastModified)
+	if err != nil {
+		return time.Time{}, parseError(ctx, err)
+	}
+
+	return lastModified.Time, nil
+}
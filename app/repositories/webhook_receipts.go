@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// WebhookReceiptsRepositoryInterface defines the set of inbound webhook deliveries related methods
+// available. Every delivery of a source is claimed once until it expires, even across restarts.
+type WebhookReceiptsRepositoryInterface interface {
+	Claim(ctx context.Context, source string, deliveryID string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, source string, deliveryID string) error
+}
+
+// WebhookReceiptsRepository implements WebhookReceiptsRepositoryInterface
+type WebhookReceiptsRepository struct {
+	db *sqlx.DB
+}
+
+// NewWebhookReceiptsRepository returns a configured WebhookReceiptsRepository object
+func NewWebhookReceiptsRepository(db *sqlx.DB) *WebhookReceiptsRepository {
+	return &WebhookReceiptsRepository{db: db}
+}
+
+// Claim records the delivery for ttl, telling if it was claimed. It wasn't when it was received
+// before and hasn't expired yet, the insert being the gate for concurrent deliveries. The expired
+// receipts of the other deliveries are deleted along.
+func (r *WebhookReceiptsRepository) Claim(ctx context.Context, source string, deliveryID string, ttl time.Duration) (bool, error) {
+	const stmt = `
+		WITH expired AS (
+			DELETE FROM webhook_receipts
+			WHERE expires_at < now() AND NOT (source = $1 AND delivery_id = $2)
+		)
+		INSERT INTO webhook_receipts (source, delivery_id, expires_at)
+		VALUES ($1, $2, now() + $3 * interval '1 millisecond')
+		ON CONFLICT (source, delivery_id) DO UPDATE
+			SET received_at = now(), expires_at = EXCLUDED.expires_at
+			WHERE webhook_receipts.expires_at < now()`
+	res, err := r.db.ExecContext(ctx, stmt, source, deliveryID, ttl.Milliseconds())
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+	return rows > 0, nil
+}
+
+// Release forgets the delivery, so it's processed again when retried
+func (r *WebhookReceiptsRepository) Release(ctx context.Context, source string, deliveryID string) error {
+	const stmt = `DELETE FROM webhook_receipts WHERE source = $1 AND delivery_id = $2`
+	if _, err := r.db.ExecContext(ctx, stmt, source, deliveryID); err != nil {
+		return parseError(ctx, err)
+	}
+	return nil
+}
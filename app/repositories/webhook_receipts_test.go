@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"appdoki-be/app/testsupport"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWebhookReceiptsRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expect a delivery to be claimed once until it expires or is released", func(t *testing.T) {
+		r := NewWebhookReceiptsRepository(testsupport.NewDB(t))
+
+		if claimed, err := r.Claim(ctx, "github", "d-1", time.Hour); err != nil || !claimed {
+			t.Fatalf("expected the delivery to be claimed, got %v, %v", claimed, err)
+		}
+		if claimed, err := r.Claim(ctx, "github", "d-1", time.Hour); err != nil || claimed {
+			t.Fatalf("expected the delivery to be claimed once, got %v, %v", claimed, err)
+		}
+		if claimed, err := r.Claim(ctx, "stripe", "d-1", time.Hour); err != nil || !claimed {
+			t.Fatalf("expected the deliveries of other sources to be claimed apart, got %v, %v", claimed, err)
+		}
+
+		if err := r.Release(ctx, "github", "d-1"); err != nil {
+			t.Fatal(err)
+		}
+		if claimed, err := r.Claim(ctx, "github", "d-1", -time.Second); err != nil || !claimed {
+			t.Fatalf("expected the released delivery to be claimed again, got %v, %v", claimed, err)
+		}
+		if claimed, err := r.Claim(ctx, "github", "d-1", time.Hour); err != nil || !claimed {
+			t.Fatalf("expected the expired delivery to be claimed again, got %v, %v", claimed, err)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"time"
+)
+
+// Webhook delivery statuses
+const (
+	WebhookDelivered = "delivered"
+	WebhookFailed    = "failed"
+)
+
+// WebhookEndpoint is an endpoint registered by an admin, the events it subscribed to being posted to
+// it signed with its secret. It's disabled after too many failed deliveries in a row.
+type WebhookEndpoint struct {
+	ID                  int64          `json:"id" db:"id"`
+	URL                 string         `json:"url" db:"url"`
+	Secret              string         `json:"-" db:"secret"`
+	Events              pq.StringArray `json:"events" db:"events"`
+	Enabled             bool           `json:"enabled" db:"enabled"`
+	ConsecutiveFailures int            `json:"consecutive_failures" db:"consecutive_failures"`
+	DisabledAt          *time.Time     `json:"disabled_at" db:"disabled_at"`
+	CreatedBy           *string        `json:"created_by" db:"created_by"`
+	CreatedAt           time.Time      `json:"created_at" db:"created_at"`
+}
+
+// WebhookDelivery is an attempt to post an event to an endpoint
+type WebhookDelivery struct {
+	ID         int64  `json:"id" db:"id"`
+	EndpointID int64  `json:"endpoint_id" db:"endpoint_id"`
+	EventID    string `json:"event_id" db:"event_id"`
+	Event      string `json:"event" db:"event"`
+	// Payload is the JSON posted
+	Payload json.RawMessage `json:"payload" db:"payload"`
+	Attempt int             `json:"attempt" db:"attempt"`
+	Status  string          `json:"status" db:"status"`
+	// ResponseCode is the status the endpoint responded with, nil when it couldn't be reached
+	ResponseCode *int      `json:"response_code" db:"response_code"`
+	Error        string    `json:"error,omitempty" db:"error"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// WebhooksRepositoryInterface defines the set of outbound webhooks related methods available
+type WebhooksRepositoryInterface interface {
+	Create(ctx context.Context, endpoint *WebhookEndpoint) (*WebhookEndpoint, error)
+	List(ctx context.Context) ([]*WebhookEndpoint, error)
+	ListSubscribed(ctx context.Context, event string) ([]*WebhookEndpoint, error)
+	Find(ctx context.Context, ID int64) (*WebhookEndpoint, error)
+	Delete(ctx context.Context, ID int64) (bool, error)
+	RecordOutcome(ctx context.Context, ID int64, delivered bool, maxFailures int) (bool, error)
+	CreateDelivery(ctx context.Context, delivery *WebhookDelivery) (*WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]*WebhookDelivery, error)
+	FindDelivery(ctx context.Context, endpointID int64, ID int64) (*WebhookDelivery, error)
+}
+
+// WebhooksRepository implements WebhooksRepositoryInterface
+type WebhooksRepository struct {
+	db *sqlx.DB
+}
+
+// NewWebhooksRepository returns a configured WebhooksRepository object
+func NewWebhooksRepository(db *sqlx.DB) *WebhooksRepository {
+	return &WebhooksRepository{db: db}
+}
+
+const (
+	webhookEndpointColumns = "id, url, secret, events, enabled, consecutive_failures, disabled_at, created_by, created_at"
+	webhookDeliveryColumns = "id, endpoint_id, event_id, event, payload, attempt, status, response_code, error, created_at"
+)
+
+// Create registers the endpoint, enabled
+func (r *WebhooksRepository) Create(ctx context.Context, endpoint *WebhookEndpoint) (*WebhookEndpoint, error) {
+	stmt := `INSERT INTO webhook_endpoints (url, secret, events, created_by)
+		VALUES ($1, $2, $3, $4) RETURNING ` + webhookEndpointColumns
+
+	created := &WebhookEndpoint{}
+	err := r.db.GetContext(ctx, created, stmt, endpoint.URL, endpoint.Secret, endpoint.Events, endpoint.CreatedBy)
+	if err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return created, nil
+}
+
+// List returns every endpoint, the oldest first
+func (r *WebhooksRepository) List(ctx context.Context) ([]*WebhookEndpoint, error) {
+	stmt := "SELECT " + webhookEndpointColumns + " FROM webhook_endpoints ORDER BY id"
+
+	endpoints := []*WebhookEndpoint{}
+	if err := r.db.SelectContext(ctx, &endpoints, stmt); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return endpoints, nil
+}
+
+// ListSubscribed returns the enabled endpoints subscribed to the event
+func (r *WebhooksRepository) ListSubscribed(ctx context.Context, event string) ([]*WebhookEndpoint, error) {
+	stmt := "SELECT " + webhookEndpointColumns + " FROM webhook_endpoints WHERE enabled AND $1 = ANY(events) ORDER BY id"
+
+	endpoints := []*WebhookEndpoint{}
+	if err := r.db.SelectContext(ctx, &endpoints, stmt, event); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return endpoints, nil
+}
+
+// Find finds an endpoint by ID, returns nil if not found
+func (r *WebhooksRepository) Find(ctx context.Context, ID int64) (*WebhookEndpoint, error) {
+	endpoint := &WebhookEndpoint{}
+	stmt := "SELECT " + webhookEndpointColumns + " FROM webhook_endpoints WHERE id = $1"
+	if err := r.db.GetContext(ctx, endpoint, stmt, ID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, parseError(ctx, err)
+	}
+	return endpoint, nil
+}
+
+// Delete removes the endpoint along with its deliveries, telling if it existed
+func (r *WebhooksRepository) Delete(ctx context.Context, ID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM webhook_endpoints WHERE id = $1", ID)
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, parseError(ctx, err)
+	}
+	return count > 0, nil
+}
+
+// RecordOutcome counts the failed delivery to the endpoint, disabling it once it reaches maxFailures
+// in a row, or resets its failures and enables it again after a successful one. It tells if this
+// disabled the endpoint. The endpoints are never disabled with no maxFailures.
+func (r *WebhooksRepository) RecordOutcome(ctx context.Context, ID int64, delivered bool, maxFailures int) (bool, error) {
+	// the expressions of the SET clause see the row before the update
+	stmt := `UPDATE webhook_endpoints SET
+			consecutive_failures = CASE WHEN $2 THEN 0 ELSE consecutive_failures + 1 END,
+			enabled = $2 OR (enabled AND ($3 <= 0 OR consecutive_failures + 1 < $3)),
+			disabled_at = CASE
+				WHEN $2 THEN NULL
+				WHEN enabled AND $3 > 0 AND consecutive_failures + 1 >= $3 THEN now()
+				ELSE disabled_at
+			END
+		WHERE id = $1
+		RETURNING disabled_at IS NOT NULL AND disabled_at = now()`
+
+	var disabled bool
+	if err := r.db.GetContext(ctx, &disabled, stmt, ID, delivered, maxFailures); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, parseError(ctx, err)
+	}
+	return disabled, nil
+}
+
+// CreateDelivery records an attempt to post an event to an endpoint
+func (r *WebhooksRepository) CreateDelivery(ctx context.Context, delivery *WebhookDelivery) (*WebhookDelivery, error) {
+	stmt := `INSERT INTO webhook_deliveries (endpoint_id, event_id, event, payload, attempt, status, response_code, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + webhookDeliveryColumns
+
+	created := &WebhookDelivery{}
+	err := r.db.GetContext(ctx, created, stmt, delivery.EndpointID, delivery.EventID, delivery.Event, []byte(delivery.Payload),
+		delivery.Attempt, delivery.Status, delivery.ResponseCode, delivery.Error)
+	if err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return created, nil
+}
+
+// ListDeliveries returns up to limit deliveries to the endpoint, the latest first
+func (r *WebhooksRepository) ListDeliveries(ctx context.Context, endpointID int64, limit int) ([]*WebhookDelivery, error) {
+	stmt := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE endpoint_id = $1 ORDER BY id DESC LIMIT $2"
+
+	deliveries := []*WebhookDelivery{}
+	if err := r.db.SelectContext(ctx, &deliveries, stmt, endpointID, limit); err != nil {
+		return nil, parseError(ctx, err)
+	}
+	return deliveries, nil
+}
+
+// FindDelivery finds a delivery to the endpoint by ID, returns nil if not found
+func (r *WebhooksRepository) FindDelivery(ctx context.Context, endpointID int64, ID int64) (*WebhookDelivery, error) {
+	delivery := &WebhookDelivery{}
+	stmt := "SELECT " + webhookDeliveryColumns + " FROM webhook_deliveries WHERE endpoint_id = $1 AND id = $2"
+	if err := r.db.GetContext(ctx, delivery, stmt, endpointID, ID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, parseError(ctx, err)
+	}
+	return delivery, nil
+}
@@ -0,0 +1,158 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// requestError describes why a request could not be read, mapped to its response
+type requestError struct {
+	status  int
+	code    string
+	message string
+	params  map[string]string
+	details interface{}
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+// decodeJSON decodes the request body into dst, rejecting bodies larger than maxBytes,
+// unknown fields and any data after the first JSON value. Failures are returned as
+// *requestError, to be responded with respondRequestError.
+func decodeJSON(r *http.Request, dst interface{}, maxBytes int64) error {
+	if maxBytes > 0 {
+		r.Body = limitBody(r.Body, maxBytes)
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		if errors.Is(err, errBodyTooLarge) {
+			return decodeError(err)
+		}
+		return &requestError{
+			status:  http.StatusBadRequest,
+			code:    ErrCodeBadRequest,
+			message: "request body must contain a single JSON value",
+		}
+	}
+
+	return nil
+}
+
+// decodeError maps a JSON decoding error to the request error explaining it
+func decodeError(err error) *requestError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, errBodyTooLarge):
+		return &requestError{
+			status:  http.StatusRequestEntityTooLarge,
+			code:    ErrCodeRequestTooLarge,
+			message: "request body is too large",
+		}
+	case errors.Is(err, io.EOF):
+		return &requestError{
+			status:  http.StatusBadRequest,
+			code:    ErrCodeBadRequest,
+			message: "request body must not be empty",
+		}
+	case errors.As(err, &syntaxErr):
+		return &requestError{
+			status:  http.StatusBadRequest,
+			code:    ErrCodeBadRequest,
+			message: "malformed JSON at position {position}",
+			params:  map[string]string{"position": strconv.FormatInt(syntaxErr.Offset, 10)},
+		}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &requestError{
+			status:  http.StatusBadRequest,
+			code:    ErrCodeBadRequest,
+			message: "malformed JSON",
+		}
+	case errors.As(err, &typeErr):
+		return &requestError{
+			status:  http.StatusUnprocessableEntity,
+			code:    ErrCodeValidationFailed,
+			message: "field \"{field}\" must be of type {type}",
+			params:  map[string]string{"field": typeErr.Field, "type": typeErr.Type.String()},
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return &requestError{
+			status:  http.StatusUnprocessableEntity,
+			code:    ErrCodeValidationFailed,
+			message: "unknown field {field}",
+			params:  map[string]string{"field": strings.TrimPrefix(err.Error(), "json: unknown field ")},
+		}
+	}
+
+	return &requestError{
+		status:  http.StatusBadRequest,
+		code:    ErrCodeBadRequest,
+		message: "invalid request body",
+	}
+}
+
+// errBodyTooLarge is returned by the bodies of limitBody read past their limit
+var errBodyTooLarge = errors.New("request body too large")
+
+// limitBody caps body at maxBytes, reading past it failing with errBodyTooLarge
+func limitBody(body io.ReadCloser, maxBytes int64) io.ReadCloser {
+	return &limitedBody{ReadCloser: body, remaining: maxBytes}
+}
+
+// limitedBody is the body returned by limitBody, it reads one byte past the limit to tell a body
+// ending right at it from a larger one
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	err       error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.remaining {
+		b.remaining -= int64(n)
+		b.err = err
+		return n, err
+	}
+
+	n = int(b.remaining)
+	b.remaining = 0
+	b.err = errBodyTooLarge
+	return n, b.err
+}
+
+// respondRequestError is an helper that responds with the error envelope of a request error,
+// or with an internal error for any other error
+func respondRequestError(w http.ResponseWriter, err error) {
+	var reqErr *requestError
+	if !errors.As(err, &reqErr) {
+		respondInternalError(w)
+		return
+	}
+
+	respondErrorWithParams(w, reqErr.status, reqErr.code, reqErr.message, reqErr.params, reqErr.details)
+}
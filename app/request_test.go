@@ -0,0 +1,99 @@
+package app
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type payload struct {
+		Name  string `json:"name"`
+		Beers int    `json:"beers"`
+	}
+
+	decode := func(body string, maxBytes int64) (*payload, *http.Response) {
+		var p payload
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		if err := decodeJSON(r, &p, maxBytes); err != nil {
+			respondRequestError(w, err)
+			return nil, w.Result()
+		}
+		return &p, nil
+	}
+
+	t.Run("expect a valid body to be decoded", func(t *testing.T) {
+		p, resp := decode(`{"name":"john","beers":2}`+"\n", 1024)
+		if resp != nil {
+			t.Fatalf("expected no error, got %d", resp.StatusCode)
+		}
+		if p.Name != "john" || p.Beers != 2 {
+			t.Fatalf("unexpected payload %+v", p)
+		}
+	})
+
+	t.Run("expect a body over the limit to return 413", func(t *testing.T) {
+		_, resp := decode(`{"name":"`+strings.Repeat("a", 100)+`"}`, 16)
+
+		assertStatusCode(t, resp, http.StatusRequestEntityTooLarge)
+		assertErrorCode(t, resp, ErrCodeRequestTooLarge)
+	})
+
+	t.Run("expect a body ending right at the limit to be decoded", func(t *testing.T) {
+		body := `{"name":"john"}`
+		p, resp := decode(body, int64(len(body)))
+		if resp != nil {
+			t.Fatalf("expected no error, got %d", resp.StatusCode)
+		}
+		if p.Name != "john" {
+			t.Fatalf("unexpected payload %+v", p)
+		}
+	})
+
+	t.Run("expect a raw body read past the limit to return 413", func(t *testing.T) {
+		_, err := ioutil.ReadAll(limitBody(ioutil.NopCloser(strings.NewReader(strings.Repeat("a", 100))), 16))
+		w := httptest.NewRecorder()
+		respondRequestError(w, decodeError(err))
+
+		assertStatusCode(t, w.Result(), http.StatusRequestEntityTooLarge)
+		assertErrorCode(t, w.Result(), ErrCodeRequestTooLarge)
+	})
+
+	t.Run("expect malformed JSON to return 400", func(t *testing.T) {
+		_, resp := decode(`{"name":`, 1024)
+
+		assertStatusCode(t, resp, http.StatusBadRequest)
+		assertErrorCode(t, resp, ErrCodeBadRequest)
+	})
+
+	t.Run("expect an empty body to return 400", func(t *testing.T) {
+		_, resp := decode(``, 1024)
+
+		assertStatusCode(t, resp, http.StatusBadRequest)
+		assertErrorCode(t, resp, ErrCodeBadRequest)
+	})
+
+	t.Run("expect trailing data to return 400", func(t *testing.T) {
+		_, resp := decode(`{"name":"john"}{"name":"doe"}`, 1024)
+
+		assertStatusCode(t, resp, http.StatusBadRequest)
+		assertErrorCode(t, resp, ErrCodeBadRequest)
+	})
+
+	t.Run("expect an unknown field to return 422", func(t *testing.T) {
+		_, resp := decode(`{"name":"john","admin":true}`, 1024)
+
+		assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+		assertErrorCode(t, resp, ErrCodeValidationFailed)
+	})
+
+	t.Run("expect a field of the wrong type to return 422", func(t *testing.T) {
+		_, resp := decode(`{"beers":"two"}`, 1024)
+
+		assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+		assertErrorCode(t, resp, ErrCodeValidationFailed)
+	})
+}
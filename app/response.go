@@ -1,40 +1,211 @@
 package app
 
 import (
+	"appdoki-be/app/i18n"
+	"appdoki-be/app/logging"
+	"appdoki-be/app/repositories"
+	"context"
 	"encoding/json"
-	log "github.com/sirupsen/logrus"
+	"errors"
+	"mime"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 )
 
-type internalError struct {
-	Message string
+// errorEnvelope wraps every error response
+type errorEnvelope struct {
+	Error errorBody `json:"error"`
 }
 
-type appError struct {
-	Errors []string
+type errorBody struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
 }
 
+const (
+	defaultJSONIndent = 2
+	maxJSONIndent     = 8
+	// databaseRetryAfter is the Retry-After, in seconds, of the responses failing on an unreachable database
+	databaseRetryAfter = "5"
+	// statusClientClosedRequest is the status of the requests the client went away from, nginx's
+	statusClientClosedRequest = 499
+)
+
 // respondJSON is an helper that takes care of the
-// HTTP response part of a request handler
-func respondJSON(w http.ResponseWriter, data interface{}, statusCode int) {
+// HTTP response part of a request handler.
+// The payload is encoded before anything is written, so encoding
+// failures still get a proper error response
+func respondJSON(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
+	writeJSON(w, emptyCollections(data), statusCode, jsonIndent(r))
+}
+
+// respondJSONStream is similar to respondJSON but encodes large payloads
+// straight to the response, without an intermediate buffer
+func respondJSONStream(w http.ResponseWriter, r *http.Request, data interface{}, statusCode int) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(statusCode)
-	err := json.NewEncoder(w).Encode(data)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", jsonIndent(r))
+	if err := enc.Encode(emptyCollections(data)); err != nil {
+		logging.FromContext(r.Context()).
+			Errorln("respondJSONStream: encoding failed after headers were sent:", err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, data interface{}, statusCode int, indent string) {
+	var body []byte
+	var err error
+	if indent != "" {
+		body, err = json.MarshalIndent(data, "", indent)
+	} else {
+		body, err = json.Marshal(data)
+	}
 	if err != nil {
-		log.Errorln("respondJSON", err)
+		responseLogger(w).Errorln("respondJSON", err)
+		if _, ok := data.(*errorEnvelope); !ok {
+			respondInternalError(w)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(append(body, '\n')); err != nil {
+		responseLogger(w).Debugln("respondJSON", err)
+	}
+}
+
+// emptyCollections replaces a nil slice payload, or a nil slice in the data field of
+// a payload struct, by an empty one so clients get [] instead of null
+func emptyCollections(data interface{}) interface{} {
+	v := reflect.ValueOf(data)
+	switch {
+	case v.Kind() == reflect.Slice && v.IsNil():
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	case v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct:
+		if copied, ok := withEmptyDataField(v.Elem()); ok {
+			return copied.Addr().Interface()
+		}
+	case v.Kind() == reflect.Struct:
+		if copied, ok := withEmptyDataField(v); ok {
+			return copied.Interface()
+		}
+	}
+	return data
+}
+
+// withEmptyDataField returns a copy of the struct v with an empty slice in its data field, when nil
+func withEmptyDataField(v reflect.Value) (reflect.Value, bool) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.PkgPath != "" || strings.Split(field.Tag.Get("json"), ",")[0] != "data" {
+			continue
+		}
+
+		value := v.Field(i)
+		if value.Kind() != reflect.Slice || !value.IsNil() {
+			return v, false
+		}
+
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		copied.Field(i).Set(reflect.MakeSlice(value.Type(), 0, 0))
+		return copied, true
+	}
+	return v, false
+}
+
+// jsonIndent returns the indentation asked for by the client, either with the
+// pretty query param or an indent parameter on the accepted JSON media type
+func jsonIndent(r *http.Request) string {
+	if r == nil {
+		return ""
 	}
+
+	if pretty, err := strconv.ParseBool(r.URL.Query().Get("pretty")); err == nil && pretty {
+		return strings.Repeat(" ", defaultJSONIndent)
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(accept)
+		if err != nil || mediaType != "application/json" {
+			continue
+		}
+		if n, err := strconv.Atoi(params["indent"]); err == nil && n > 0 {
+			if n > maxJSONIndent {
+				n = maxJSONIndent
+			}
+			return strings.Repeat(" ", n)
+		}
+	}
+
+	return ""
+}
+
+// respondError is an helper that responds with the error envelope,
+// identified by one of the ErrCode constants. Errors are never cached,
+// server errors are reported to the error tracker.
+// The message is written in English and translated to the locale of the response.
+func respondError(w http.ResponseWriter, statusCode int, code string, message string, details interface{}) {
+	respondErrorWithParams(w, statusCode, code, message, nil, details)
+}
+
+// respondErrorWithParams is similar to respondError but fills in the {name}
+// placeholders of the message once translated
+func respondErrorWithParams(w http.ResponseWriter, statusCode int, code string, message string, params map[string]string, details interface{}) {
+	reportResponseError(w, statusCode, code, message)
+
+	w.Header().Set("Cache-Control", noStoreCache)
+	w.Header().Add("Vary", "Accept-Language")
+	writeJSON(w, &errorEnvelope{
+		Error: errorBody{
+			Code:      code,
+			Message:   i18n.Translate(responseLocale(w), code, message, params),
+			Details:   details,
+			RequestID: w.Header().Get(logging.RequestIDHeader),
+		},
+	}, statusCode, "")
 }
 
 // respondInternalError is an helper similar to respondError but responds
 // with a default internal error code and payload
 func respondInternalError(w http.ResponseWriter) {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(http.StatusInternalServerError)
-	err := json.NewEncoder(w).Encode(&internalError{
-		Message: "Oops! Something went wrong on our side.",
-	})
-	if err != nil {
-		log.Errorln("respondInternalError", err)
+	respondError(w, http.StatusInternalServerError, ErrCodeInternal, "Oops! Something went wrong on our side.", nil)
+}
+
+// respondRepositoryError is an helper similar to respondInternalError for the errors of the
+// repositories: the duplicated or still referenced records and the concurrent transactions conflict,
+// the records breaking a check constraint fail the validation, and the database being unreachable
+// answers 503 for the clients to retry. The statements stopped by the cancellation of the request
+// answer 499, for the logs and metrics as the client is gone, and by its timeout or the
+// statement_timeout 504. The deactivated users are forbidden. Any other error is an internal one.
+func respondRepositoryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		respondError(w, statusClientClosedRequest, ErrCodeRequestCanceled, "the request was canceled by the client", nil)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, repositories.ErrTimeout):
+		respondError(w, http.StatusGatewayTimeout, ErrCodeTimeout, "the database took too long to answer", nil)
+	case errors.Is(err, repositories.ErrUserDeactivated):
+		respondError(w, http.StatusForbidden, ErrCodeUserDeactivated, "the user is deactivated", nil)
+	case errors.Is(err, repositories.ErrDuplicate):
+		respondError(w, http.StatusConflict, ErrCodeConflict, "the record already exists", nil)
+	case errors.Is(err, repositories.ErrForeignKey):
+		respondError(w, http.StatusConflict, ErrCodeConflict, "the record references a missing one, or is still referenced", nil)
+	case errors.Is(err, repositories.ErrSerialization), errors.Is(err, repositories.ErrDeadlock):
+		respondError(w, http.StatusConflict, ErrCodeConflict, "the request conflicted with a concurrent one, please retry it", nil)
+	case errors.Is(err, repositories.ErrCheckViolation):
+		respondError(w, http.StatusUnprocessableEntity, ErrCodeValidationFailed, "the record breaks a constraint", nil)
+	case errors.Is(err, repositories.ErrConnection):
+		w.Header().Set("Retry-After", databaseRetryAfter)
+		respondError(w, http.StatusServiceUnavailable, ErrCodeDatabaseUnavailable,
+			"the database is temporarily unavailable, please try again in a moment", nil)
+	default:
+		respondInternalError(w)
 	}
 }
 
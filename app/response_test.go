@@ -0,0 +1,130 @@
+package app
+
+import (
+	"appdoki-be/app/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRespondJSON(t *testing.T) {
+	payload := map[string]string{"status": "ok"}
+
+	tests := []struct {
+		name   string
+		url    string
+		accept string
+		body   string
+	}{
+		{"expect compact JSON by default", "/", "", "{\"status\":\"ok\"}\n"},
+		{"expect indented JSON with ?pretty=1", "/?pretty=1", "", "{\n  \"status\": \"ok\"\n}\n"},
+		{"expect indented JSON with an indent media type parameter", "/", "application/json;indent=4", "{\n    \"status\": \"ok\"\n}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, respond := range map[string]func(http.ResponseWriter, *http.Request, interface{}, int){
+				"buffered": respondJSON,
+				"streamed": respondJSONStream,
+			} {
+				r := httptest.NewRequest("GET", tt.url, nil)
+				if tt.accept != "" {
+					r.Header.Set("Accept", tt.accept)
+				}
+				w := httptest.NewRecorder()
+				respond(w, r, payload, http.StatusCreated)
+
+				resp := w.Result()
+				assertStatusCode(t, resp, http.StatusCreated)
+				assertJSONContentType(t, resp)
+
+				body, _ := ioutil.ReadAll(resp.Body)
+				if string(body) != tt.body {
+					t.Fatalf("%s: expected body %q, got %q", name, tt.body, body)
+				}
+			}
+		})
+	}
+
+	t.Run("expect 500 when the payload can't be encoded", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		respondJSON(w, r, map[string]interface{}{"ch": make(chan int)}, http.StatusOK)
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusInternalServerError)
+		assertErrorCode(t, resp, ErrCodeInternal)
+	})
+}
+
+func TestRespondJSON_EmptyCollections(t *testing.T) {
+	type page struct {
+		Data  []string `json:"data"`
+		Total int      `json:"total"`
+	}
+
+	tests := []struct {
+		name string
+		data interface{}
+		body string
+	}{
+		{"expect a nil slice to be an empty array", []string(nil), "[]\n"},
+		{"expect a nil data field to be an empty array", page{Total: 0}, "{\"data\":[],\"total\":0}\n"},
+		{"expect a nil data field behind a pointer to be an empty array", &page{}, "{\"data\":[],\"total\":0}\n"},
+		{"expect populated collections to be left alone", page{Data: []string{"a"}, Total: 1}, "{\"data\":[\"a\"],\"total\":1}\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, respond := range map[string]func(http.ResponseWriter, *http.Request, interface{}, int){
+				"buffered": respondJSON,
+				"streamed": respondJSONStream,
+			} {
+				w := httptest.NewRecorder()
+				respond(w, httptest.NewRequest("GET", "/", nil), tt.data, http.StatusOK)
+
+				if body := w.Body.String(); body != tt.body {
+					t.Fatalf("%s: expected body %q, got %q", name, tt.body, body)
+				}
+			}
+		})
+	}
+}
+
+func TestRespondRepositoryError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"expect 409 for a duplicated record", &repositories.DatabaseError{Kind: repositories.ErrDuplicate}, http.StatusConflict, ErrCodeConflict},
+		{"expect 409 for a conflict wrapping a foreign key violation", &repositories.ConflictError{Err: &repositories.DatabaseError{Kind: repositories.ErrForeignKey}}, http.StatusConflict, ErrCodeConflict},
+		{"expect 409 for a serialization failure", &repositories.DatabaseError{Kind: repositories.ErrSerialization}, http.StatusConflict, ErrCodeConflict},
+		{"expect 409 for a deadlock", &repositories.DatabaseError{Kind: repositories.ErrDeadlock}, http.StatusConflict, ErrCodeConflict},
+		{"expect 422 for a check violation", &repositories.DatabaseError{Kind: repositories.ErrCheckViolation}, http.StatusUnprocessableEntity, ErrCodeValidationFailed},
+		{"expect 503 for an unreachable database", fmt.Errorf("listing: %w", &repositories.DatabaseError{Kind: repositories.ErrConnection}), http.StatusServiceUnavailable, ErrCodeDatabaseUnavailable},
+		{"expect 499 for a statement canceled with the request", fmt.Errorf("%w: pq: canceling statement due to user request", context.Canceled), statusClientClosedRequest, ErrCodeRequestCanceled},
+		{"expect 504 for a statement timing out", fmt.Errorf("finding the user: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, ErrCodeTimeout},
+		{"expect 504 for a statement canceled by the statement_timeout", &repositories.DatabaseError{Kind: repositories.ErrTimeout}, http.StatusGatewayTimeout, ErrCodeTimeout},
+		{"expect 500 for any other error", errors.New("boom"), http.StatusInternalServerError, ErrCodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			respondRepositoryError(w, tt.err)
+
+			resp := w.Result()
+			assertStatusCode(t, resp, tt.status)
+			assertErrorCode(t, resp, tt.code)
+			if retry := resp.Header.Get("Retry-After"); (retry != "") != (tt.status == http.StatusServiceUnavailable) {
+				t.Fatalf("unexpected Retry-After %q", retry)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"appdoki-be/app/repositories"
+	"net/http"
+)
+
+// RequireRole only lets through the authenticated users having the given role.
+// It runs after JwtVerify: a.JwtVerify(a.RequireRole(role, handler)).
+func (a *Application) RequireRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := r.Context().Value("userID").(string)
+		if userID == "" {
+			respondError(w, http.StatusForbidden, ErrCodeForbidden, "forbidden", nil)
+			return
+		}
+
+		// the role just changed must be enforced at once, read from the primary
+		user, err := a.usersRepository.FindByID(repositories.ReadFresh(r.Context()), userID)
+		if err != nil {
+			respondRepositoryError(w, err)
+			return
+		}
+		if user == nil || user.Role != role {
+			respondError(w, http.StatusForbidden, ErrCodeForbidden, "forbidden", nil)
+			return
+		}
+
+		next(w, r)
+	}
+}
@@ -0,0 +1,233 @@
+package app
+
+import (
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// the scopes of the static bearer tokens guarding the operations endpoints
+const (
+	metricsScope = "metrics"
+	debugScope   = "debug"
+)
+
+// access is what a route requires from the caller: nothing, a signed in user,
+// a user with a role or the bearer token of a scope. Optional routes serve anonymous
+// callers too, knowing the user when there's one. Public routes say so, the zero value
+// being no declaration at all.
+type access struct {
+	public        bool
+	authenticated bool
+	optional      bool
+	role          string
+	scope         string
+}
+
+var (
+	publicAccess        = access{public: true}
+	authenticatedAccess = access{authenticated: true}
+	optionalAccess      = access{optional: true}
+)
+
+func roleAccess(role string) access {
+	return access{authenticated: true, role: role}
+}
+
+func scopeAccess(scope string) access {
+	return access{scope: scope}
+}
+
+// routeDef declares a route: the handler is given without its authorization,
+// which a.mount applies from the declared access
+type routeDef struct {
+	methods []string
+	path    string
+	// matches the paths starting with path
+	prefix    bool
+	access    access
+	feature   string
+	streaming bool
+	handler   http.Handler
+}
+
+// mount registers the declared routes, wrapping each handler in the chain its declaration asks for.
+// The feature flag comes first so a dark route answers 404 to everyone, then the access, then the
+// rollout of the flag, once the user is known. It panics on a route declared without its access,
+// for it not to be served to anyone by omission.
+func (a *Application) mount(router *mux.Router, defs ...routeDef) {
+	for _, def := range defs {
+		if def.access == (access{}) {
+			panic(fmt.Sprintf("route %s %s is declared without its access", strings.Join(def.methods, ","), def.path))
+		}
+		h := def.handler
+		if def.feature != "" {
+			h = a.FeatureRollout(def.feature, h.ServeHTTP)
+		}
+		h = a.authorize(def.access, h)
+		if def.feature != "" {
+			h = a.FeatureGate(def.feature, h.ServeHTTP)
+		}
+		if def.streaming {
+			h = streamingHandler(h.ServeHTTP)
+		}
+
+		route := router.Methods(def.methods...)
+		if def.prefix {
+			route = route.PathPrefix(def.path)
+		} else {
+			route = route.Path(def.path)
+		}
+		route.Handler(h)
+		a.routeRegistry.add(route, def)
+	}
+}
+
+// authorize wraps the handler in the middlewares checking the access
+func (a *Application) authorize(access access, next http.Handler) http.Handler {
+	switch {
+	case access.scope != "":
+		return a.scopeVerify(access.scope)(next)
+	case access.role != "":
+		return a.JwtVerify(a.RequireRole(access.role, next.ServeHTTP))
+	case access.authenticated:
+		return a.JwtVerify(next.ServeHTTP)
+	case access.optional:
+		return a.JwtVerifyOptional(next.ServeHTTP)
+	}
+	return next
+}
+
+// scopeVerify returns the middleware checking the bearer token of the scope
+func (a *Application) scopeVerify(scope string) middleware {
+	switch scope {
+	case metricsScope:
+		return func(next http.Handler) http.Handler {
+			return metricsTokenVerify(a.conf.Metrics.Token, next)
+		}
+	case debugScope:
+		return debugTokenVerify(a.conf.Debug.Token)
+	}
+	panic(fmt.Sprintf("route scope %q has no token", scope))
+}
+
+// routeRegistry records the declaration of every route mounted, so their protections can be
+// listed and the routes registered without one caught
+type routeRegistry struct {
+	mu     sync.Mutex
+	routes map[*mux.Route]routeDef
+	router *mux.Router
+}
+
+func newRouteRegistry() *routeRegistry {
+	return &routeRegistry{routes: map[*mux.Route]routeDef{}}
+}
+
+func (reg *routeRegistry) add(route *mux.Route, def routeDef) {
+	if reg == nil {
+		return
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.routes[route] = def
+}
+
+// reset forgets the routes of the previous router, to be called before building a new one
+func (reg *routeRegistry) reset(router *mux.Router) {
+	if reg == nil {
+		return
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.routes = map[*mux.Route]routeDef{}
+	reg.router = router
+}
+
+// Undeclared lists the routes of the router registered without a.mount
+func (reg *routeRegistry) Undeclared() []string {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	undeclared := []string{}
+	if reg.router == nil {
+		return undeclared
+	}
+
+	_ = reg.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil
+		}
+		if _, ok := reg.routes[route]; ok {
+			return nil
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			path = "?"
+		}
+		methods, _ := route.GetMethods()
+		undeclared = append(undeclared, strings.TrimSpace(strings.Join(methods, ",")+" "+path))
+		return nil
+	})
+	return undeclared
+}
+
+type RouteInfo struct {
+	Methods       []string `json:"methods"`
+	Path          string   `json:"path"`
+	Authenticated bool     `json:"authenticated"`
+	Role          string   `json:"role,omitempty"`
+	Scope         string   `json:"scope,omitempty"`
+	FeatureFlag   string   `json:"feature_flag,omitempty"`
+}
+
+// Routes walks the router, describing each route sorted by path
+func (reg *routeRegistry) Routes() []RouteInfo {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	routes := []RouteInfo{}
+	if reg.router == nil {
+		return routes
+	}
+
+	_ = reg.router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		if route.GetHandler() == nil {
+			return nil
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{}
+		}
+
+		def := reg.routes[route]
+		routes = append(routes, RouteInfo{
+			Methods:       methods,
+			Path:          path,
+			Authenticated: def.access.authenticated || def.access.scope != "",
+			Role:          def.access.role,
+			Scope:         def.access.scope,
+			FeatureFlag:   def.feature,
+		})
+		return nil
+	})
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return strings.Join(routes[i].Methods, ",") < strings.Join(routes[j].Methods, ",")
+	})
+	return routes
+}
+
+// GetRoutes responds with the routes of the API and their protections, sorted by path
+func (a *Application) GetRoutes(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, r, a.routeRegistry.Routes(), http.StatusOK)
+}
@@ -0,0 +1,148 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestApplication_GetRoutes(t *testing.T) {
+	a := newTestApplication()
+	a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+		user := generateRandomUserMockWithID(ID)
+		user.Role = repos.RoleAdmin
+		return user, nil
+	}
+	// building the routes again must not list them twice
+	a.Routes()
+	routes := a.Routes()
+
+	r := httptest.NewRequest("GET", "/admin/routes", nil)
+	w := httptest.NewRecorder()
+	routes.ServeHTTP(w, r)
+
+	resp := w.Result()
+
+	assertStatusCode(t, resp, http.StatusOK)
+	assertJSONContentType(t, resp)
+
+	var infos []RouteInfo
+	if err := json.NewDecoder(resp.Body).Decode(&infos); err != nil {
+		t.Fatal(err)
+	}
+	byRoute := map[string]RouteInfo{}
+	for _, info := range infos {
+		key := info.Methods[0] + " " + info.Path
+		if _, ok := byRoute[key]; ok {
+			t.Fatalf("expected %s to be listed once", key)
+		}
+		byRoute[key] = info
+	}
+
+	t.Run("expect the routes to be sorted by path", func(t *testing.T) {
+		if !sort.SliceIsSorted(infos, func(i, j int) bool { return infos[i].Path < infos[j].Path }) {
+			t.Fatal("expected the routes to be sorted by path")
+		}
+	})
+
+	t.Run("expect the authenticated routes to be flagged", func(t *testing.T) {
+		for _, key := range []string{"GET /api/v1/users/{id}", "GET /users/{id}", "GET /api/v1/events"} {
+			if !byRoute[key].Authenticated {
+				t.Fatalf("expected %s to be authenticated, got %+v", key, byRoute[key])
+			}
+		}
+		for _, key := range []string{"GET /healthz", "GET /", "POST /webhooks/{source}", "GET /api/v1/auth/login"} {
+			if info, ok := byRoute[key]; !ok || info.Authenticated {
+				t.Fatalf("expected %s to be listed as public, got %+v", key, info)
+			}
+		}
+	})
+
+	t.Run("expect the roles and feature flags to be listed", func(t *testing.T) {
+		if info := byRoute["GET /admin/routes"]; !info.Authenticated || info.Role != repos.RoleAdmin {
+			t.Fatalf("expected the admin role to be required, got %+v", info)
+		}
+		if info := byRoute["GET /api/v1/users/me"]; info.FeatureFlag != "users_me" || !info.Authenticated {
+			t.Fatalf("expected the users_me feature flag, got %+v", info)
+		}
+		if info := byRoute["POST /api/v1/auth/token"]; info.FeatureFlag != "auth_token" || info.Authenticated {
+			t.Fatalf("expected the auth_token feature flag, got %+v", info)
+		}
+	})
+}
+
+func TestApplication_mount(t *testing.T) {
+	t.Run("expect the declared access to be applied", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.Debug.Token = "secret"
+		router := mux.NewRouter()
+		a.routeRegistry.reset(router)
+		a.mount(router,
+			routeDef{methods: []string{http.MethodGet}, path: "/vars", access: scopeAccess(debugScope),
+				handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { respondNoContent(w, http.StatusOK) })},
+		)
+
+		r := httptest.NewRequest("GET", "/vars", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assertStatusCode(t, w.Result(), http.StatusForbidden)
+
+		r = httptest.NewRequest("GET", "/vars", nil)
+		r.Header.Set("Authorization", "Bearer secret")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assertStatusCode(t, w.Result(), http.StatusOK)
+	})
+
+	t.Run("expect a route declared without its access to panic", func(t *testing.T) {
+		a := newTestApplication()
+		router := mux.NewRouter()
+		a.routeRegistry.reset(router)
+		defer func() {
+			if recovered := recover(); recovered == nil || !strings.Contains(fmt.Sprint(recovered), "GET /forgotten") {
+				t.Fatalf("expected the route to be refused, got %v", recovered)
+			}
+		}()
+
+		a.mount(router, routeDef{methods: []string{http.MethodGet}, path: "/forgotten", handler: http.NotFoundHandler()})
+	})
+
+	t.Run("expect the routes registered without a declaration to be caught", func(t *testing.T) {
+		a := newTestApplication()
+		router := mux.NewRouter()
+		a.routeRegistry.reset(router)
+		a.mount(router,
+			routeDef{methods: []string{http.MethodGet}, path: "/declared", access: publicAccess, handler: http.NotFoundHandler()},
+		)
+		router.Methods(http.MethodPost).Path("/undeclared").Handler(http.NotFoundHandler())
+
+		undeclared := a.routeRegistry.Undeclared()
+		if len(undeclared) != 1 || undeclared[0] != "POST /undeclared" {
+			t.Fatalf("expected POST /undeclared to be caught, got %v", undeclared)
+		}
+	})
+
+	t.Run("expect every route of the application to be declared", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.Debug.Enabled = true
+		a.conf.Debug.Token = "secret"
+		a.conf.Metrics.Token = "secret"
+		a.Routes()
+
+		if undeclared := a.routeRegistry.Undeclared(); len(undeclared) != 0 {
+			t.Fatalf("expected no undeclared route, got %v", undeclared)
+		}
+		for _, info := range a.routeRegistry.Routes() {
+			if info.Path == "/metrics" && (info.Scope != metricsScope || !info.Authenticated) {
+				t.Fatalf("expected the metrics scope to be required, got %+v", info)
+			}
+		}
+	})
+}
@@ -0,0 +1,200 @@
+// Package seed fills a development database with a deterministic data set, through the repositories:
+// users with their roles, pictures and devices, and the beers they gave each other. The data set is
+// the fixture of the integration tests as well.
+package seed
+
+import (
+	"appdoki-be/app/repositories"
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+// User is a user of the data set along with the tokens of its devices
+type User struct {
+	repositories.User
+	Devices []repositories.DeviceToken
+}
+
+// Users are the users of the data set, user 1, the caller of the test mode, being an admin
+var Users = []User{
+	seedUser("1", "Ana Silva", repositories.RoleAdmin, "ios"),
+	seedUser("2", "Bruno Costa", repositories.RoleUser, "Android"),
+	seedUser("3", "Carla Mendes", repositories.RoleUser, "ios", "Android"),
+	seedUser("4", "Diogo Ferreira", repositories.RoleAdmin),
+	seedUser("5", "Eva Santos", repositories.RoleUser, "Android"),
+}
+
+// Transfer is a transfer of beers of the data set
+type Transfer struct {
+	GiverID string
+	TakerID string
+	Beers   int
+}
+
+// Transfers are the beers the users of the data set gave each other, every user giving to every
+// other one, from 1 to 5 beers
+var Transfers = seedTransfers()
+
+func seedUser(id string, name string, role string, platforms ...string) User {
+	user := User{User: repositories.User{
+		ID:      id,
+		Name:    name,
+		Email:   fmt.Sprintf("user%s@appdoki.dev", id),
+		Picture: fmt.Sprintf("https://i.pravatar.cc/150?u=appdoki-%s", id),
+		Role:    role,
+	}}
+	for _, platform := range platforms {
+		user.Devices = append(user.Devices, repositories.DeviceToken{
+			Token:    fmt.Sprintf("seed-%s-%s", id, platform),
+			UserID:   id,
+			Platform: platform,
+		})
+	}
+	return user
+}
+
+func seedTransfers() []Transfer {
+	var transfers []Transfer
+	for i, giver := range Users {
+		for j, taker := range Users {
+			if i != j {
+				transfers = append(transfers, Transfer{GiverID: giver.ID, TakerID: taker.ID, Beers: (i+2*j)%5 + 1})
+			}
+		}
+	}
+	return transfers
+}
+
+// Seeder seeds the data set into a database
+type Seeder struct {
+	db      *sqlx.DB
+	users   repositories.UsersRepositoryInterface
+	devices repositories.DevicesRepositoryInterface
+}
+
+// NewSeeder returns a Seeder of the database
+func NewSeeder(db *sqlx.DB) *Seeder {
+	return &Seeder{
+		db:      db,
+		users:   repositories.NewUsersRepository(db),
+		devices: repositories.NewDevicesRepository(db),
+	}
+}
+
+// Result is what a run of the seed created, and the users of the data set
+type Result struct {
+	Users        []*repositories.User
+	CreatedUsers int
+	Devices      int
+	Transfers    int
+}
+
+// Run seeds the whole data set, after wiping the tables when wipe is set. Running it again creates
+// nothing new.
+func (s *Seeder) Run(ctx context.Context, wipe bool) (*Result, error) {
+	if wipe {
+		if err := s.Wipe(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &Result{}
+	var err error
+	if res.Users, res.CreatedUsers, err = s.SeedUsers(ctx); err != nil {
+		return nil, err
+	}
+	if res.Devices, err = s.SeedDevices(ctx); err != nil {
+		return nil, err
+	}
+	if res.Transfers, err = s.SeedTransfers(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Wipe deletes the users along with their beers, devices, preferences and notifications, the
+// announcements, webhooks and feature flags they edited being kept. The repositories delete one
+// record at a time, hence the statements of its own.
+func (s *Seeder) Wipe(ctx context.Context) error {
+	for _, query := range []string{
+		`TRUNCATE beer_transfers, idempotency_keys RESTART IDENTITY`,
+		`DELETE FROM users`,
+	} {
+		if _, err := s.db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("error wiping the users: %w", err)
+		}
+	}
+	return nil
+}
+
+// SeedUsers creates the users of the data set which don't exist yet, and sets their roles, returning
+// them and how many were created
+func (s *Seeder) SeedUsers(ctx context.Context) ([]*repositories.User, int, error) {
+	users := make([]*repositories.User, 0, len(Users))
+	created := 0
+	byRole := map[string][]string{}
+	for _, seed := range Users {
+		user := seed.User
+		saved, isNew, err := s.users.FindOrCreateUser(ctx, &user)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error seeding user %s: %w", seed.ID, err)
+		}
+		if isNew {
+			created++
+		}
+		saved.Role = seed.Role
+		users = append(users, saved)
+		byRole[seed.Role] = append(byRole[seed.Role], seed.ID)
+	}
+
+	for role, IDs := range byRole {
+		if _, err := s.users.SetRoleMany(ctx, IDs, role); err != nil {
+			return nil, 0, fmt.Errorf("error setting the %s role: %w", role, err)
+		}
+	}
+	return users, created, nil
+}
+
+// SeedDevices registers the devices of the users of the data set, returning how many were created
+func (s *Seeder) SeedDevices(ctx context.Context) (int, error) {
+	created := 0
+	for _, seed := range Users {
+		for _, device := range seed.Devices {
+			device := device
+			_, isNew, err := s.devices.Upsert(ctx, &device)
+			if err != nil {
+				return 0, fmt.Errorf("error seeding device %s: %w", device.Token, err)
+			}
+			if isNew {
+				created++
+			}
+		}
+	}
+	return created, nil
+}
+
+// SeedTransfers gives the beers of the data set, the ones of a user being skipped once it gave some,
+// returning how many transfers were created
+func (s *Seeder) SeedTransfers(ctx context.Context) (int, error) {
+	gave := map[string]bool{}
+	for _, seed := range Users {
+		summary, err := s.users.GetBeerTransfersSummary(ctx, seed.ID)
+		if err != nil {
+			return 0, fmt.Errorf("error reading the beers of user %s: %w", seed.ID, err)
+		}
+		gave[seed.ID] = summary != nil && summary.Given > 0
+	}
+
+	created := 0
+	for _, transfer := range Transfers {
+		if gave[transfer.GiverID] {
+			continue
+		}
+		if _, err := s.users.AddBeerTransfer(ctx, transfer.GiverID, transfer.TakerID, transfer.Beers); err != nil {
+			return 0, fmt.Errorf("error seeding the beers of user %s: %w", transfer.GiverID, err)
+		}
+		created++
+	}
+	return created, nil
+}
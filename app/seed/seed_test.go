@@ -0,0 +1,68 @@
+package seed
+
+import (
+	"appdoki-be/app/repositories"
+	"appdoki-be/app/testsupport"
+	"context"
+	"testing"
+)
+
+func TestSeeder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expect the data set to give beers from every user to every other one", func(t *testing.T) {
+		if len(Transfers) != len(Users)*(len(Users)-1) {
+			t.Fatalf("expected %d transfers, got %d", len(Users)*(len(Users)-1), len(Transfers))
+		}
+		for _, transfer := range Transfers {
+			if transfer.GiverID == transfer.TakerID || transfer.Beers < 1 || transfer.Beers > 5 {
+				t.Fatalf("unexpected transfer %+v", transfer)
+			}
+		}
+		if Users[0].ID != "1" || Users[0].Role != repositories.RoleAdmin {
+			t.Fatalf("expected the caller of the test mode to be an admin, got %+v", Users[0])
+		}
+	})
+
+	t.Run("expect the data set to be seeded once, running again creating nothing", func(t *testing.T) {
+		db := testsupport.NewDB(t)
+		s := NewSeeder(db)
+
+		res, err := s.Run(ctx, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.CreatedUsers != len(Users) || res.Devices != 5 || res.Transfers != len(Transfers) {
+			t.Fatalf("expected the whole data set to be created, got %+v", res)
+		}
+
+		again, err := s.Run(ctx, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again.CreatedUsers != 0 || again.Devices != 0 || again.Transfers != 0 || len(again.Users) != len(Users) {
+			t.Fatalf("expected nothing to be created again, got %+v", again)
+		}
+
+		admin, err := repositories.NewUsersRepository(db).FindByID(ctx, "4")
+		if err != nil || admin == nil || admin.Role != repositories.RoleAdmin {
+			t.Fatalf("expected the role of the data set, got %+v, %v", admin, err)
+		}
+	})
+
+	t.Run("expect the wipe to seed the data set from scratch", func(t *testing.T) {
+		db := testsupport.NewDB(t)
+		s := NewSeeder(db)
+		if _, err := s.Run(ctx, false); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := s.Run(ctx, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.CreatedUsers != len(Users) || res.Transfers != len(Transfers) {
+			t.Fatalf("expected the data set to be created again, got %+v", res)
+		}
+	})
+}
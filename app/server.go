@@ -0,0 +1,193 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+	log "github.com/sirupsen/logrus"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Run serves the API, and the metrics endpoint when it has its own address, until
+// ctx is cancelled. The application is then gracefully shut down.
+// In TLS mode the API is served over HTTPS and HTTP/2, with the redirects from HTTP on
+// their own listener when the redirect address is set. SIGHUP cycles the log level.
+func (a *Application) Run(ctx context.Context) error {
+	ctx = logging.WithLogger(ctx, a.logger)
+	conf := a.conf.Server
+
+	api := a.newServer(conf.Address, a.Routes())
+	servers := []*http.Server{api}
+	if conf.TLSEnabled() {
+		tlsConf, redirect, err := tlsConfig(&conf)
+		if err != nil {
+			return err
+		}
+		api.TLSConfig = tlsConf
+		if conf.HTTPRedirectAddress != "" {
+			servers = append(servers, a.newServer(conf.HTTPRedirectAddress, redirect))
+		}
+	}
+	if a.conf.Metrics.Address != "" {
+		servers = append(servers, a.newServer(a.conf.Metrics.Address, a.MetricsHandler()))
+	}
+
+	a.logger.WithFields(log.Fields{
+		"read_header_timeout":    conf.ReadHeaderTimeout,
+		"read_timeout":           conf.ReadTimeout,
+		"write_timeout":          conf.WriteTimeout,
+		"idle_timeout":           conf.IdleTimeout,
+		"max_header_bytes":       conf.MaxHeaderBytes,
+		"request_timeout":        conf.RequestTimeout,
+		"stream_request_timeout": conf.StreamRequestTimeout,
+		"tls":                    conf.TLSEnabled(),
+	}).Info("Server limits")
+	if conf.WriteTimeout > 0 && conf.WriteTimeout <= conf.StreamRequestTimeout {
+		a.logger.Warn("the write timeout doesn't exceed the stream request timeout, streamed responses may be cut short")
+	}
+
+	listeners := make([]net.Listener, 0, len(servers))
+	for _, srv := range servers {
+		l, err := net.Listen("tcp", srv.Addr)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, l)
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	go a.cycleLogLevel(ctx, hup)
+
+	if err := a.features.Refresh(ctx); err != nil {
+		a.logger.Errorf("error loading the feature flags, starting with their configured defaults: %v", err)
+	}
+	go a.refreshFeatureFlags(ctx, a.conf.Features.RefreshInterval)
+	if provider := a.conf.AppConfig.OIDCProvider; provider != nil && provider.Err() != nil {
+		go a.discoverOIDC(ctx, provider)
+	}
+	go a.replayPendingDeadLetters(ctx)
+	go a.pruneNotifications(ctx, notificationsPruneInterval)
+	go a.flushDeferredNotifications(ctx, deferredFlushInterval)
+	if a.conf.Metrics.DBStatsInterval > 0 {
+		go a.collectDBStats(ctx, a.conf.Metrics.DBStatsInterval)
+	}
+	if a.conf.Digest.Enabled {
+		go a.runDigests(ctx, digestCheckInterval)
+	}
+	return a.serve(ctx, servers, listeners)
+}
+
+// newServer returns a server of handler on addr with the configured timeouts and limits
+func (a *Application) newServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: a.conf.Server.ReadHeaderTimeout,
+		ReadTimeout:       a.conf.Server.ReadTimeout,
+		WriteTimeout:      a.conf.Server.WriteTimeout,
+		IdleTimeout:       a.conf.Server.IdleTimeout,
+		MaxHeaderBytes:    a.conf.Server.MaxHeaderBytes,
+	}
+}
+
+// serve serves each server on its listener until ctx is cancelled or one of them fails
+func (a *Application) serve(ctx context.Context, servers []*http.Server, listeners []net.Listener) error {
+	errs := make(chan error, len(servers))
+	for i := range servers {
+		srv, l := servers[i], listeners[i]
+		// read before serving, net/http setting a TLSConfig of its own on the servers without one
+		useTLS := srv.TLSConfig != nil
+		go func() {
+			var err error
+			if useTLS {
+				err = srv.ServeTLS(l, "", "")
+			} else {
+				err = srv.Serve(l)
+			}
+			if err != nil && err != http.ErrServerClosed {
+				errs <- err
+			}
+		}()
+		a.logger.Infof("Server started on %s (TLS: %t)", l.Addr(), useTLS)
+	}
+
+	var serveErr error
+	select {
+	case <-ctx.Done():
+		a.logger.Info("Server stopping")
+	case serveErr = <-errs:
+		a.logger.Errorf("Server failed: %+v", serveErr)
+	}
+
+	if err := a.shutdown(servers); err != nil && serveErr == nil {
+		serveErr = err
+	}
+	return serveErr
+}
+
+// shutdown flips readiness, lets in-flight requests, queued notifications and background workers
+// finish within the grace period, sends the pending error reports and releases the database connections
+func (a *Application) shutdown(servers []*http.Server) error {
+	atomic.StoreInt32(&a.shuttingDown, 1)
+	time.Sleep(a.conf.Server.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(logging.WithLogger(context.Background(), a.logger), a.conf.Server.ShutdownGracePeriod)
+	defer cancel()
+
+	var shutdownErr error
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctx); err != nil {
+			a.logger.Errorf("Server shutdown failed: %+v", err)
+			shutdownErr = err
+		}
+	}
+
+	if a.notificationQueue != nil {
+		if err := a.notificationQueue.Close(ctx); err != nil {
+			a.logger.Errorf("Notifications queue was not drained: %+v", err)
+			shutdownErr = err
+		}
+	}
+
+	if err := a.workers.Stop(ctx); err != nil {
+		a.logger.Errorf("Background workers did not finish: %+v", err)
+		shutdownErr = err
+	}
+
+	if a.errorReporter != nil {
+		if err := a.errorReporter.Flush(ctx); err != nil {
+			a.logger.Errorf("Error reports were not all sent: %+v", err)
+			shutdownErr = err
+		}
+	}
+
+	if a.db != nil {
+		if err := a.db.Close(); err != nil {
+			a.logger.Errorf("Database close failed: %+v", err)
+			shutdownErr = err
+		}
+	}
+	if a.replica != nil {
+		if err := a.replica.Close(); err != nil {
+			a.logger.Errorf("Read replica close failed: %+v", err)
+			shutdownErr = err
+		}
+	}
+
+	return shutdownErr
+}
+
+// isShuttingDown checks if the application received the signal to shut down
+func (a *Application) isShuttingDown() bool {
+	return atomic.LoadInt32(&a.shuttingDown) == 1
+}
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"appdoki-be/app/metrics"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/reporting"
+	"appdoki-be/config"
+	"context"
+	"errors"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestApplication_serve(t *testing.T) {
+	t.Run("expect an in-flight request to complete during shutdown", func(t *testing.T) {
+		a := &Application{
+			conf:    &config.Config{Server: config.ServerConfig{ShutdownGracePeriod: 5 * time.Second}},
+			workers: newWorkerGroup(reporting.Noop{}),
+			logger:  log.StandardLogger(),
+		}
+
+		started := make(chan struct{})
+		srv := &http.Server{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				time.Sleep(200 * time.Millisecond)
+				w.Write([]byte("done"))
+			}),
+		}
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		served := make(chan error, 1)
+		go func() {
+			served <- a.serve(ctx, []*http.Server{srv}, []net.Listener{l})
+		}()
+
+		type result struct {
+			resp *http.Response
+			err  error
+		}
+		responses := make(chan result, 1)
+		go func() {
+			resp, err := http.Get("http://" + l.Addr().String())
+			responses <- result{resp, err}
+		}()
+
+		<-started
+		cancel()
+
+		res := <-responses
+		if res.err != nil {
+			t.Fatalf("expected the in-flight request to complete, got %v", res.err)
+		}
+		assertStatusCode(t, res.resp, http.StatusOK)
+
+		body, err := ioutil.ReadAll(res.resp.Body)
+		if err != nil || string(body) != "done" {
+			t.Fatalf("expected body 'done', got '%s'", body)
+		}
+
+		if err := <-served; err != nil {
+			t.Fatalf("expected a graceful shutdown, got %v", err)
+		}
+		if !a.isShuttingDown() {
+			t.Fatal("expected the application to be shutting down")
+		}
+	})
+
+	t.Run("expect background workers to be drained during shutdown", func(t *testing.T) {
+		a := &Application{
+			conf:    &config.Config{Server: config.ServerConfig{ShutdownGracePeriod: 5 * time.Second}},
+			workers: newWorkerGroup(reporting.Noop{}),
+			logger:  log.StandardLogger(),
+		}
+
+		finished := false
+		a.workers.Go(context.Background(), func(_ context.Context) {
+			time.Sleep(100 * time.Millisecond)
+			finished = true
+		})
+
+		if err := a.shutdown(nil); err != nil {
+			t.Fatalf("expected a graceful shutdown, got %v", err)
+		}
+		if !finished {
+			t.Fatal("expected the background worker to finish")
+		}
+	})
+
+	t.Run("expect queued notifications to be sent during shutdown", func(t *testing.T) {
+		fake := notify.NewFake()
+		a := &Application{
+			conf:              &config.Config{Server: config.ServerConfig{ShutdownGracePeriod: 5 * time.Second}},
+			workers:           newWorkerGroup(reporting.Noop{}),
+			notificationQueue: notify.NewQueue(fake, notify.QueueConfig{Size: 10, Workers: 1}, metrics.NewFake(), nil),
+			logger:            log.StandardLogger(),
+		}
+
+		for i := 0; i < 3; i++ {
+			a.notificationQueue.SendToTopic(context.Background(), beersTopic, notify.Notification{})
+		}
+
+		if err := a.shutdown(nil); err != nil {
+			t.Fatalf("expected a graceful shutdown, got %v", err)
+		}
+		if sent := fake.Sent(); len(sent) != 3 {
+			t.Fatalf("expected the 3 queued notifications to be sent, got %d", len(sent))
+		}
+	})
+
+	t.Run("expect the notifications left on shutdown to be kept, then replayed on start", func(t *testing.T) {
+		held := heldNotifier{Fake: notify.NewFake(), release: make(chan struct{})}
+		defer close(held.release)
+		a := newTestApplication()
+		a.conf.Server.ShutdownGracePeriod = 50 * time.Millisecond
+		failed := notificationFailed(a.metrics, a.errorReporter)
+		a.notificationQueue = notify.NewQueue(held, notify.QueueConfig{Size: 10, Workers: 1}, a.metrics,
+			a.deadLettering(deadLetterQueue, queuedSender{held}, failed))
+		sender := &failingSender{err: notify.Retryable(errors.New("unavailable"))}
+		retrying := notify.WithRetry(sender, notify.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour},
+			a.workers.Go, a.deadLettering(deadLetterFCM, sender, failed))
+
+		ctx := context.Background()
+		a.notificationQueue.SendToTopic(ctx, beersTopic, notify.Notification{Title: "Held"})
+		for _, userID := range []string{"2", "3"} {
+			a.notificationQueue.SendToUser(ctx, userID, notify.Notification{
+				Event:   notify.EventBeerReceived,
+				Payload: beerTransferPayload{TransferID: 7, Giver: "Alice", Receiver: "Bob", Beers: 2},
+			})
+		}
+		retrying.SendToUser(ctx, "4", notify.Notification{Title: "Cheers!"})
+
+		if err := a.shutdown(nil); err == nil {
+			t.Fatal("expected the queue not to be drained while a send is held")
+		}
+		pending, _ := a.deadLettersRepository.ListPending(ctx, 10)
+		if len(pending) != 3 || pending[0].Channel != deadLetterQueue || pending[1].Channel != deadLetterQueue || pending[2].Channel != deadLetterFCM {
+			t.Fatalf("expected the 2 queued sends and the retry to be kept pending, got %+v", pending)
+		}
+
+		sender.err = nil
+		a.replayPendingDeadLetters(ctx)
+
+		sent := held.Sent()
+		if len(sent) != 2 || sent[0].UserID != "2" || sent[1].UserID != "3" {
+			t.Fatalf("expected the queued sends to be replayed, got %+v", sent)
+		}
+		if payload, ok := sent[0].Notification.Payload.(map[string]interface{}); !ok || payload["TransferID"] != 7 || payload["Giver"] != "Alice" {
+			t.Fatalf("expected the payload to be replayed for the templates, got %#v", sent[0].Notification.Payload)
+		}
+		if sender.sends != 2 {
+			t.Fatalf("expected the retry to be replayed, got %d sends", sender.sends)
+		}
+		if letters, _ := a.deadLettersRepository.List(ctx, 10); len(letters) != 0 {
+			t.Fatalf("expected the replayed dead letters to be removed, got %+v", letters)
+		}
+		if count := a.metrics.(*metrics.Fake).Counter(notificationsFailedMetric, metrics.Labels{"op": notify.OpSendToUser, "reason": "interrupted"}); count != 3 {
+			t.Fatalf("expected 3 interrupted sends, got %v", count)
+		}
+	})
+}
+
+// heldNotifier holds the sends to topics until released
+type heldNotifier struct {
+	*notify.Fake
+	release chan struct{}
+}
+
+func (h heldNotifier) SendToTopic(ctx context.Context, topic string, n notify.Notification) {
+	<-h.release
+	h.Fake.SendToTopic(ctx, topic, n)
+}
+
+func TestShutdownCheck(t *testing.T) {
+	t.Run("expect GET /readyz to return 503 once shutting down", func(t *testing.T) {
+		a := &Application{}
+		h := NewHealthHandler(shutdownCheck(a.isShuttingDown))
+		router := prepareRouter(http.MethodGet, "/readyz", h.Ready)
+
+		r := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assertStatusCode(t, w.Result(), http.StatusOK)
+
+		a.shuttingDown = 1
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assertStatusCode(t, w.Result(), http.StatusServiceUnavailable)
+	})
+}
+
+func TestApplication_newServer(t *testing.T) {
+	a := &Application{conf: &config.Config{Server: config.ServerConfig{
+		ReadHeaderTimeout: time.Second,
+		ReadTimeout:       2 * time.Second,
+		WriteTimeout:      3 * time.Second,
+		IdleTimeout:       4 * time.Second,
+		MaxHeaderBytes:    1024,
+	}}}
+
+	srv := a.newServer("localhost:0", http.NotFoundHandler())
+
+	if srv.ReadHeaderTimeout != time.Second || srv.ReadTimeout != 2*time.Second ||
+		srv.WriteTimeout != 3*time.Second || srv.IdleTimeout != 4*time.Second {
+		t.Fatal("expected the server to use the configured timeouts")
+	}
+	if srv.MaxHeaderBytes != 1024 {
+		t.Fatalf("expected MaxHeaderBytes 1024, got %d", srv.MaxHeaderBytes)
+	}
+}
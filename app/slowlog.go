@@ -0,0 +1,113 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/repositories"
+	"context"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	slowRequestEvent = "request"
+	slowQueryEvent   = "query"
+)
+
+// SlowEvent is a request or a database call which took longer than its threshold
+type SlowEvent struct {
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"`
+	Method     string    `json:"method,omitempty"`
+	Status     int       `json:"status,omitempty"`
+	UserID     string    `json:"user_id,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	DurationMs float64   `json:"duration_ms"`
+	At         time.Time `json:"at"`
+}
+
+// slowLog keeps the last slow events in a ring buffer, so they can be inspected without log access
+type slowLog struct {
+	mu     sync.Mutex
+	events []SlowEvent
+	next   int
+	full   bool
+}
+
+func newSlowLog(size int) *slowLog {
+	if size < 0 {
+		size = 0
+	}
+	return &slowLog{events: make([]SlowEvent, size)}
+}
+
+// Add records the event, overwriting the oldest one once the buffer is full
+func (l *slowLog) Add(event SlowEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.events) == 0 {
+		return
+	}
+	l.events[l.next] = event
+	l.next = (l.next + 1) % len(l.events)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Events returns the recorded events, the latest first
+func (l *slowLog) Events() []SlowEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.events)
+	}
+	events := make([]SlowEvent, 0, count)
+	for i := 1; i <= count; i++ {
+		events = append(events, l.events[(l.next-i+len(l.events))%len(l.events)])
+	}
+	return events
+}
+
+// slowQueryObserver logs the repository calls which took longer than threshold,
+// nil when the threshold is zero
+func slowQueryObserver(threshold time.Duration, slow *slowLog) repositories.QueryObserver {
+	if threshold <= 0 {
+		return nil
+	}
+
+	return func(ctx context.Context, name string, duration time.Duration, err error) {
+		if duration < threshold {
+			return
+		}
+
+		event := SlowEvent{
+			Kind:       slowQueryEvent,
+			Name:       name,
+			UserID:     contextUserID(ctx),
+			RequestID:  logging.RequestID(ctx),
+			DurationMs: durationMs(duration),
+			At:         time.Now(),
+		}
+		slow.Add(event)
+		logging.FromContext(ctx).WithFields(log.Fields{
+			"query":       name,
+			"user_id":     event.UserID,
+			"duration_ms": event.DurationMs,
+			"failed":      err != nil,
+		}).Warn("slow query")
+	}
+}
+
+// GetSlowEvents responds with the last slow requests and database calls, the latest first
+func (a *Application) GetSlowEvents(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, r, a.slowLog.Events(), http.StatusOK)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSlowLog(t *testing.T) {
+	t.Run("expect the latest events first, the oldest being overwritten", func(t *testing.T) {
+		l := newSlowLog(2)
+		for _, name := range []string{"a", "b", "c"} {
+			l.Add(SlowEvent{Name: name})
+		}
+
+		events := l.Events()
+		if len(events) != 2 || events[0].Name != "c" || events[1].Name != "b" {
+			t.Fatalf("expected c and b, got %+v", events)
+		}
+	})
+
+	t.Run("expect an empty buffer to keep nothing", func(t *testing.T) {
+		l := newSlowLog(0)
+		l.Add(SlowEvent{Name: "a"})
+
+		if events := l.Events(); len(events) != 0 {
+			t.Fatalf("expected no events, got %+v", events)
+		}
+	})
+}
+
+func TestSlowQueryObserver(t *testing.T) {
+	t.Run("expect the calls above the threshold to be recorded", func(t *testing.T) {
+		l := newSlowLog(10)
+		observe := slowQueryObserver(200*time.Millisecond, l)
+		ctx := context.WithValue(context.Background(), "userID", "42")
+
+		observe(ctx, "UsersRepository.GetAll", 100*time.Millisecond, nil)
+		observe(ctx, "UsersRepository.FindByID", 300*time.Millisecond, nil)
+
+		events := l.Events()
+		if len(events) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(events))
+		}
+		if events[0].Kind != slowQueryEvent || events[0].Name != "UsersRepository.FindByID" || events[0].UserID != "42" {
+			t.Fatalf("unexpected event %+v", events[0])
+		}
+		if events[0].DurationMs != 300 {
+			t.Fatalf("expected 300ms, got %v", events[0].DurationMs)
+		}
+	})
+
+	t.Run("expect a zero threshold to disable it", func(t *testing.T) {
+		if slowQueryObserver(0, newSlowLog(10)) != nil {
+			t.Fatal("expected no observer")
+		}
+	})
+}
+
+func TestLoggingMiddleware_slowRequests(t *testing.T) {
+	t.Run("expect the requests above the threshold to be recorded with their route and user", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.SlowLog.RequestThreshold = time.Nanosecond
+
+		r := httptest.NewRequest("GET", "/api/v1/users/42", nil)
+		a.Routes().ServeHTTP(httptest.NewRecorder(), r)
+
+		events := a.slowLog.Events()
+		if len(events) != 1 {
+			t.Fatalf("expected 1 event, got %d", len(events))
+		}
+		event := events[0]
+		if event.Kind != slowRequestEvent || event.Name != "/api/v1/users/{id}" || event.Method != "GET" || event.UserID != "1" {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	})
+
+	t.Run("expect a zero threshold to disable it", func(t *testing.T) {
+		a := newTestApplication()
+
+		r := httptest.NewRequest("GET", "/api/v1/users", nil)
+		a.Routes().ServeHTTP(httptest.NewRecorder(), r)
+
+		if events := a.slowLog.Events(); len(events) != 0 {
+			t.Fatalf("expected no events, got %+v", events)
+		}
+	})
+}
+
+func TestApplication_GetSlowEvents(t *testing.T) {
+	t.Run("expect admins to get the slow events", func(t *testing.T) {
+		a := newTestApplication()
+		a.usersRepository.(*mockUsersRepository).findByIDImpl = func(_ context.Context, ID string) (*repos.User, error) {
+			user := generateRandomUserMockWithID(ID)
+			user.Role = repos.RoleAdmin
+			return user, nil
+		}
+		a.slowLog.Add(SlowEvent{Kind: slowQueryEvent, Name: "UsersRepository.GetAll", DurationMs: 250})
+
+		r := httptest.NewRequest("GET", "/admin/debug/slow", nil)
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusOK)
+		assertJSONContentType(t, resp)
+
+		var events []SlowEvent
+		if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 1 || events[0].Name != "UsersRepository.GetAll" {
+			t.Fatalf("unexpected events %+v", events)
+		}
+	})
+
+	t.Run("expect other users to be forbidden", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/admin/debug/slow", nil)
+		w := httptest.NewRecorder()
+		newTestApplication().Routes().ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusForbidden)
+	})
+}
@@ -1,7 +1,9 @@
 package app
 
 import (
+	"encoding/json"
 	"github.com/gorilla/mux"
+	"io/ioutil"
 	"net/http"
 	"testing"
 )
@@ -25,3 +27,18 @@ func assertStatusCode(t *testing.T, r *http.Response, status int) {
 		t.Fatalf("expected %d response, got %d", status, r.StatusCode)
 	}
 }
+
+func assertErrorCode(t *testing.T, r *http.Response, code string) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal("failed to read response body")
+	}
+
+	var envelope errorEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		t.Fatal("failed to parse error envelope")
+	}
+	if envelope.Error.Code != code {
+		t.Fatalf("expected error code '%s', got '%s'", code, envelope.Error.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package testsupport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	// ContainerEnv is the variable which, set to true, runs the database tests of a package against a
+	// throwaway PostgreSQL container rather than the database of TEST_DATABASE_URL
+	ContainerEnv = "TEST_POSTGRES_CONTAINER"
+	// ContainerImageEnv is the variable holding the image of the container, DefaultContainerImage when unset
+	ContainerImageEnv = "TEST_POSTGRES_IMAGE"
+	// DefaultContainerImage is the image of the container, the PostgreSQL of docker-compose
+	DefaultContainerImage = "postgres:13"
+
+	// containerStartTimeout bounds the wait for PostgreSQL to accept connections in the container
+	containerStartTimeout = time.Minute
+)
+
+// Main runs the tests of a package, returning the exit code of m.Run. With TEST_POSTGRES_CONTAINER
+// set to true and no TEST_DATABASE_URL, it starts a PostgreSQL container with docker first, for
+// NewDB to create the schemas of the tests in it, and removes it once they're done. The packages
+// testing against PostgreSQL call it from their TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(testsupport.Main(m))
+//	}
+func Main(m *testing.M) int {
+	if os.Getenv(ContainerEnv) != "true" || os.Getenv(DatabaseURLEnv) != "" {
+		return m.Run()
+	}
+
+	image := os.Getenv(ContainerImageEnv)
+	if image == "" {
+		image = DefaultContainerImage
+	}
+	databaseURL, stop, err := startPostgres(image)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "starting the PostgreSQL container (%s): %v\n", image, err)
+		return 1
+	}
+	defer stop()
+
+	os.Setenv(DatabaseURLEnv, databaseURL)
+	defer os.Unsetenv(DatabaseURLEnv)
+	return m.Run()
+}
+
+// startPostgres starts a container of the PostgreSQL image, published on a random port of the
+// loopback, and waits for it to accept connections. stop removes the container.
+func startPostgres(image string) (databaseURL string, stop func(), err error) {
+	id, err := docker("run", "--detach", "--rm", "--publish", "127.0.0.1::5432",
+		"--env", "POSTGRES_USER=appdoki", "--env", "POSTGRES_PASSWORD=appdoki", "--env", "POSTGRES_DB=appdoki", image)
+	if err != nil {
+		return "", nil, err
+	}
+	stop = func() {
+		if _, err := docker("rm", "--force", id); err != nil {
+			fmt.Fprintf(os.Stderr, "removing the PostgreSQL container %s: %v\n", id, err)
+		}
+	}
+
+	address, err := docker("port", id, "5432/tcp")
+	if err != nil {
+		stop()
+		return "", nil, err
+	}
+	// the address may be listed for IPv4 and IPv6, one per line
+	address = strings.SplitN(address, "\n", 2)[0]
+	databaseURL = fmt.Sprintf("postgres://appdoki:appdoki@%s/appdoki?sslmode=disable", address)
+
+	if err := waitForPostgres(databaseURL, containerStartTimeout); err != nil {
+		stop()
+		return "", nil, err
+	}
+	return databaseURL, stop, nil
+}
+
+// waitForPostgres connects to the database until it answers, for at most timeout
+func waitForPostgres(databaseURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for {
+		db, err := sqlx.ConnectContext(ctx, "postgres", databaseURL)
+		if err == nil {
+			return db.Close()
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("PostgreSQL didn't accept connections within %s: %w", timeout, err)
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// docker runs the docker command with args, returning its trimmed output
+func docker(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,95 @@
+// Package testsupport provides the helpers shared by the tests running against real services, or
+// against fakes of them
+package testsupport
+
+import (
+	"appdoki-be/migrations"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"net/url"
+	"os"
+	"testing"
+)
+
+// DatabaseURLEnv is the variable holding the URL of the PostgreSQL database the tests run against
+const DatabaseURLEnv = "TEST_DATABASE_URL"
+
+// NewDB returns a connection to a schema of its own to the test, created in the database of
+// TEST_DATABASE_URL and migrated, then dropped along with its data once the test is done. The test
+// is skipped without TEST_DATABASE_URL or in -short mode, for go test ./... to run without PostgreSQL.
+func NewDB(t testing.TB) *sqlx.DB {
+	t.Helper()
+	databaseURL := os.Getenv(DatabaseURLEnv)
+	if databaseURL == "" || testing.Short() {
+		t.Skipf("skipping the database test, set %s and leave out -short to run it", DatabaseURLEnv)
+	}
+
+	admin, err := sqlx.Connect("postgres", databaseURL)
+	if err != nil {
+		t.Fatalf("connecting to %s: %v", DatabaseURLEnv, err)
+	}
+	schema := "test_" + randomSuffix(t)
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		admin.Close()
+		t.Fatalf("creating the schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() {
+		defer admin.Close()
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Errorf("dropping the schema %s: %v", schema, err)
+		}
+	})
+
+	schemaURL, err := withSearchPath(databaseURL, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlx.Connect("postgres", schemaURL)
+	if err != nil {
+		t.Fatalf("connecting to the schema %s: %v", schema, err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrateUp(db); err != nil {
+		t.Fatalf("migrating the schema %s: %v", schema, err)
+	}
+	return db
+}
+
+// withSearchPath returns the database URL with the schema first in the search path of its connections
+func withSearchPath(databaseURL string, schema string) (string, error) {
+	u, err := url.Parse(databaseURL)
+	if err != nil || u.Scheme == "" {
+		return "", fmt.Errorf("%s must be a postgres:// URL", DatabaseURLEnv)
+	}
+
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// migrateUp runs the migrations embedded in the binary
+func migrateUp(db *sqlx.DB) error {
+	m, err := migrations.New(db.DB, "")
+	if err != nil {
+		return err
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+	return nil
+}
+
+func randomSuffix(t testing.TB) string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
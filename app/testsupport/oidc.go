@@ -0,0 +1,179 @@
+package testsupport
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// oidcKeyID is the ID of the key the fake provider signs its tokens with
+const oidcKeyID = "test-key"
+
+// OIDCProvider is a fake OIDC provider with an issuer of its own, like a local Keycloak realm: it
+// serves its discovery document and keys, issues the ID tokens the tests ask for, and exchanges the
+// codes of the sign-in for them
+type OIDCProvider struct {
+	// Issuer is the URL of the provider, and the issuer of its tokens
+	Issuer string
+
+	key               *rsa.PrivateKey
+	discoveryFailures int32
+	discoveryRequests int32
+
+	mu            sync.Mutex
+	codes         map[string]string
+	issued        int
+	proxiedIssuer string
+}
+
+// NewOIDCProvider starts a fake OIDC provider, stopped once the test is done
+func NewOIDCProvider(t testing.TB) *OIDCProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &OIDCProvider{key: key, codes: map[string]string{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/realms/appdoki/.well-known/openid-configuration", p.serveDiscovery)
+	mux.HandleFunc("/realms/appdoki/protocol/openid-connect/certs", p.serveKeys)
+	mux.HandleFunc("/realms/appdoki/protocol/openid-connect/token", p.serveToken)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	p.Issuer = srv.URL + "/realms/appdoki"
+	return p
+}
+
+// FailDiscovery answers 503 to the next n requests of the discovery document
+func (p *OIDCProvider) FailDiscovery(n int) {
+	atomic.StoreInt32(&p.discoveryFailures, int32(n))
+}
+
+// DiscoveryRequests returns how many times the discovery document was requested
+func (p *OIDCProvider) DiscoveryRequests() int {
+	return int(atomic.LoadInt32(&p.discoveryRequests))
+}
+
+// ProxiedAs makes the discovery document and the tokens tell issuer rather than the URL of the
+// provider, as when the provider is reached through a proxy
+func (p *OIDCProvider) ProxiedAs(issuer string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.proxiedIssuer = issuer
+}
+
+// IDToken returns an ID token of the subject issued to audience, valid for an hour, with the claims
+// given along
+func (p *OIDCProvider) IDToken(t testing.TB, subject string, audience string, claims map[string]interface{}) string {
+	t.Helper()
+	payload := map[string]interface{}{
+		"iss": p.tokenIssuer(),
+		"sub": subject,
+		"aud": audience,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range claims {
+		payload[name] = value
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": oidcKeyID, "typ": "JWT"})
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// Code returns an authorization code the token endpoint exchanges for the ID token
+func (p *OIDCProvider) Code(idToken string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.issued++
+	code := fmt.Sprintf("code-%d", p.issued)
+	p.codes[code] = idToken
+	return code
+}
+
+func (p *OIDCProvider) tokenIssuer() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.proxiedIssuer != "" {
+		return p.proxiedIssuer
+	}
+	return p.Issuer
+}
+
+func (p *OIDCProvider) serveDiscovery(w http.ResponseWriter, _ *http.Request) {
+	atomic.AddInt32(&p.discoveryRequests, 1)
+	if atomic.AddInt32(&p.discoveryFailures, -1) >= 0 {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"issuer":                 p.tokenIssuer(),
+		"authorization_endpoint": p.Issuer + "/protocol/openid-connect/auth",
+		"token_endpoint":         p.Issuer + "/protocol/openid-connect/token",
+		"jwks_uri":               p.Issuer + "/protocol/openid-connect/certs",
+	})
+}
+
+func (p *OIDCProvider) serveKeys(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": oidcKeyID,
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (p *OIDCProvider) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p.mu.Lock()
+	idToken, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"github.com/gorilla/mux"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// streamingHandler marks the routes streaming their response, which get the longer
+// StreamRequestTimeout and aren't buffered: router.Handler(streamingHandler(handler))
+type streamingHandler http.HandlerFunc
+
+func (h streamingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h(w, r)
+}
+
+// timeoutMiddleware bounds the time the API routes take to respond. Regular routes are
+// buffered and answer 503 when the RequestTimeout expires, while streaming routes only
+// get their context cancelled after the StreamRequestTimeout. Responses turning out to be
+// event streams are detected by their content type and treated as streaming routes.
+func (a *Application) timeoutMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if route := mux.CurrentRoute(r); route != nil {
+			if _, ok := route.GetHandler().(streamingHandler); ok {
+				if a.conf.Server.StreamRequestTimeout <= 0 {
+					next.ServeHTTP(w, r)
+					return
+				}
+				ctx, cancel := context.WithTimeout(r.Context(), a.conf.Server.StreamRequestTimeout)
+				defer cancel()
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+		}
+
+		if a.conf.Server.RequestTimeout <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		serveWithTimeout(w, r, next, a.conf.Server.RequestTimeout, a.conf.Server.StreamRequestTimeout)
+	})
+}
+
+// serveWithTimeout buffers the response of next, which is discarded in favor of an error
+// response when it isn't ready within timeout. Event streams are sent as they're written
+// instead, their context being cancelled after streamTimeout, if any.
+func serveWithTimeout(w http.ResponseWriter, r *http.Request, next http.Handler, timeout time.Duration, streamTimeout time.Duration) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	timer := time.AfterFunc(timeout, cancel)
+	defer timer.Stop()
+
+	tw := &timeoutWriter{w: w, header: w.Header().Clone(), streamStarted: make(chan struct{})}
+	done := make(chan struct{})
+	panics := make(chan interface{}, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panics <- p
+			}
+		}()
+		next.ServeHTTP(tw, r.WithContext(ctx))
+		close(done)
+	}()
+
+	// wait lets the streaming handler finish, as it writes to w until then
+	wait := func() {
+		select {
+		case p := <-panics:
+			panic(p)
+		case <-done:
+		}
+	}
+
+	select {
+	case p := <-panics:
+		// re-panic in the request goroutine so the recovery middleware handles it
+		panic(p)
+	case <-done:
+		tw.mu.Lock()
+		defer tw.mu.Unlock()
+
+		if tw.streaming {
+			return
+		}
+		tw.copyHeader()
+		if !tw.wroteHeader {
+			tw.status = http.StatusOK
+		}
+		w.WriteHeader(tw.status)
+		w.Write(tw.body.Bytes())
+	case <-tw.streamStarted:
+		if timer.Stop() && streamTimeout > 0 {
+			defer time.AfterFunc(streamTimeout, cancel).Stop()
+		}
+		wait()
+	case <-ctx.Done():
+		tw.mu.Lock()
+		if tw.streaming {
+			tw.mu.Unlock()
+			wait()
+			return
+		}
+		defer tw.mu.Unlock()
+
+		tw.timedOut = true
+		respondError(w, http.StatusServiceUnavailable, ErrCodeTimeout, "the request took too long to complete", nil)
+	}
+}
+
+// timeoutWriter buffers a response until it's known whether it completed in time,
+// unless it's an event stream which is passed through to w as soon as its headers are written
+type timeoutWriter struct {
+	mu            sync.Mutex
+	w             http.ResponseWriter
+	header        http.Header
+	body          bytes.Buffer
+	status        int
+	wroteHeader   bool
+	timedOut      bool
+	streaming     bool
+	streamStarted chan struct{}
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.writeHeader(code)
+}
+
+func (tw *timeoutWriter) writeHeader(code int) {
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.status = code
+	tw.wroteHeader = true
+
+	if isEventStream(tw.header) {
+		tw.copyHeader()
+		tw.w.WriteHeader(code)
+		tw.streaming = true
+		close(tw.streamStarted)
+	}
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.writeHeader(http.StatusOK)
+	if tw.streaming {
+		return tw.w.Write(b)
+	}
+	return tw.body.Write(b)
+}
+
+// Flush sends what was written so far of an event stream, buffered responses aren't flushed
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.timedOut {
+		return
+	}
+	tw.writeHeader(http.StatusOK)
+	if tw.streaming {
+		flush(tw.w)
+	}
+}
+
+// Unwrap returns the writer the response is copied to, which must not be written to directly
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.w
+}
+
+// copyHeader replaces the headers of w by the ones written by the handler
+func (tw *timeoutWriter) copyHeader() {
+	dst := tw.w.Header()
+	for key := range dst {
+		if _, ok := tw.header[key]; !ok {
+			dst.Del(key)
+		}
+	}
+	for key, values := range tw.header {
+		dst[key] = values
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/config"
+	"github.com/gorilla/mux"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestApplication_timeoutMiddleware(t *testing.T) {
+	a := &Application{conf: &config.Config{Server: config.ServerConfig{
+		RequestTimeout:       50 * time.Millisecond,
+		StreamRequestTimeout: time.Second,
+	}}}
+
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+		respondNoContent(w, http.StatusNoContent)
+	}
+
+	router := mux.NewRouter()
+	router.Use(a.timeoutMiddleware)
+	router.Methods(http.MethodGet).Path("/slow").HandlerFunc(slow)
+	router.Methods(http.MethodGet).Path("/stream").Handler(streamingHandler(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Error("expected the streaming request to have a deadline")
+		}
+		time.Sleep(100 * time.Millisecond)
+		respondJSONStream(w, r, []string{"a", "b"}, http.StatusOK)
+	}))
+	router.Methods(http.MethodGet).Path("/fast").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Custom", "yes")
+		respondJSON(w, r, "created", http.StatusCreated)
+	})
+	router.Methods(http.MethodGet).Path("/panic").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	t.Run("expect slow requests to return 503", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/slow", nil)
+		w := httptest.NewRecorder()
+		w.Header().Set(logging.RequestIDHeader, "req-1")
+		router.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusServiceUnavailable)
+		assertJSONContentType(t, resp)
+		assertErrorCode(t, resp, ErrCodeTimeout)
+	})
+
+	t.Run("expect responses in time to be passed through", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/fast", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusCreated)
+		assertJSONContentType(t, resp)
+		if resp.Header.Get("X-Custom") != "yes" {
+			t.Fatal("expected the handler headers to be kept")
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if string(body) != "\"created\"\n" {
+			t.Fatalf("expected the handler body, got '%s'", body)
+		}
+	})
+
+	t.Run("expect streaming requests to get the longer timeout", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/stream", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusOK)
+	})
+
+	t.Run("expect panics to reach the request goroutine", func(t *testing.T) {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Fatalf("expected panic 'boom', got %v", p)
+			}
+		}()
+
+		r := httptest.NewRequest("GET", "/panic", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+	})
+}
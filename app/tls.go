@@ -0,0 +1,67 @@
+package app
+
+import (
+	"appdoki-be/config"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/acme/autocert"
+	"net"
+	"net/http"
+)
+
+// tlsConfig returns the TLS configuration of the API server, serving either the configured
+// certificate or the ones obtained from Let's Encrypt for the allowed domains, along with the
+// handler of the HTTP listener. The certificate files are loaded right away so that a missing
+// or unreadable file fails the start-up instead of the first handshake.
+func tlsConfig(conf *config.ServerConfig) (*tls.Config, http.Handler, error) {
+	redirect := httpsRedirectHandler(conf.Address)
+
+	if conf.TLSCertFile != "" || conf.TLSKeyFile != "" {
+		if conf.TLSCertFile == "" || conf.TLSKeyFile == "" {
+			return nil, nil, errors.New("both TLS_CERT_FILE and TLS_KEY_FILE must be set")
+		}
+		if len(conf.AutocertDomains) > 0 {
+			return nil, nil, errors.New("TLS_AUTOCERT_DOMAINS can't be set along with the certificate files")
+		}
+
+		cert, err := tls.LoadX509KeyPair(conf.TLSCertFile, conf.TLSKeyFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading the TLS certificate %s: %w", conf.TLSCertFile, err)
+		}
+		return &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+			NextProtos:   []string{"h2", "http/1.1"},
+		}, redirect, nil
+	}
+
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(conf.AutocertDomains...),
+		Cache:      autocert.DirCache(conf.AutocertCacheDir),
+	}
+	tlsConf := m.TLSConfig()
+	tlsConf.MinVersion = tls.VersionTLS12
+
+	// the HTTP listener also answers the ACME http-01 challenges
+	return tlsConf, m.HTTPHandler(redirect), nil
+}
+
+// httpsRedirectHandler permanently redirects the requests to the same URL on the HTTPS address
+func httpsRedirectHandler(httpsAddress string) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddress)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
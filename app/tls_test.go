@@ -0,0 +1,202 @@
+package app
+
+import (
+	"appdoki-be/app/reporting"
+	"appdoki-be/config"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCertificate writes a self-signed certificate for localhost and its key in dir
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestTLSConfig(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+
+	t.Run("expect the certificate files to be loaded with HTTP/2 enabled", func(t *testing.T) {
+		conf, _, err := tlsConfig(&config.ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conf.Certificates) != 1 {
+			t.Fatalf("expected 1 certificate, got %d", len(conf.Certificates))
+		}
+		if conf.NextProtos[0] != "h2" {
+			t.Fatalf("expected h2 to be negotiated first, got %v", conf.NextProtos)
+		}
+	})
+
+	t.Run("expect a missing certificate file to fail", func(t *testing.T) {
+		_, _, err := tlsConfig(&config.ServerConfig{TLSCertFile: "missing.pem", TLSKeyFile: keyFile})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("expect a certificate without its key to fail", func(t *testing.T) {
+		_, _, err := tlsConfig(&config.ServerConfig{TLSCertFile: certFile})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("expect autocert to answer the HTTP listener", func(t *testing.T) {
+		conf, redirect, err := tlsConfig(&config.ServerConfig{
+			Address:          ":443",
+			AutocertDomains:  []string{"appdokiapi.cloudoki.com"},
+			AutocertCacheDir: t.TempDir(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if conf.GetCertificate == nil {
+			t.Fatal("expected the certificates to be obtained on the fly")
+		}
+
+		r := httptest.NewRequest("GET", "http://appdokiapi.cloudoki.com/api/v1/users", nil)
+		w := httptest.NewRecorder()
+		redirect.ServeHTTP(w, r)
+
+		if location := w.Result().Header.Get("Location"); location != "https://appdokiapi.cloudoki.com/api/v1/users" {
+			t.Fatalf("expected a redirect to HTTPS, got '%s'", location)
+		}
+	})
+}
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	t.Run("expect a redirect to the HTTPS port, keeping the path and query", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://localhost:8080/api/v1/beers?limit=5", nil)
+		w := httptest.NewRecorder()
+		httpsRedirectHandler("localhost:8443").ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusPermanentRedirect)
+		if location := resp.Header.Get("Location"); location != "https://localhost:8443/api/v1/beers?limit=5" {
+			t.Fatalf("expected the HTTPS URL, got '%s'", location)
+		}
+	})
+
+	t.Run("expect the default HTTPS port to be left out", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://localhost/", nil)
+		w := httptest.NewRecorder()
+		httpsRedirectHandler(":443").ServeHTTP(w, r)
+
+		if location := w.Result().Header.Get("Location"); location != "https://localhost/" {
+			t.Fatalf("expected the HTTPS URL, got '%s'", location)
+		}
+	})
+}
+
+func TestApplication_serve_TLS(t *testing.T) {
+	certFile, keyFile := writeTestCertificate(t, t.TempDir())
+	conf := &config.Config{Server: config.ServerConfig{
+		TLSCertFile: certFile, TLSKeyFile: keyFile, ShutdownGracePeriod: time.Second,
+	}}
+	a := &Application{conf: conf, workers: newWorkerGroup(reporting.Noop{}), logger: log.StandardLogger()}
+
+	tlsConf, _, err := tlsConfig(&conf.Server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := a.newServer("", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	srv.TLSConfig = tlsConf
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- a.serve(ctx, []*http.Server{srv}, []net.Listener{l})
+	}()
+	defer func() {
+		cancel()
+		<-served
+	}()
+
+	t.Run("expect the API to be served over HTTP/2", func(t *testing.T) {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			ForceAttemptHTTP2: true,
+		}}
+		resp, err := client.Get("https://" + l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		body, _ := ioutil.ReadAll(resp.Body)
+		if string(body) != "HTTP/2.0" {
+			t.Fatalf("expected HTTP/2.0, got '%s'", body)
+		}
+	})
+}
+
+func TestApplication_securityHeaders(t *testing.T) {
+	t.Run("expect HSTS to be on in TLS mode", func(t *testing.T) {
+		a := &Application{conf: &config.Config{Server: config.ServerConfig{TLSCertFile: "cert.pem", TLSKeyFile: "key.pem"}}}
+
+		if !a.securityHeaders().HTTPS {
+			t.Fatal("expected HTTPS to be set")
+		}
+	})
+
+	t.Run("expect HSTS to follow the config otherwise", func(t *testing.T) {
+		a := &Application{conf: &config.Config{}}
+
+		if a.securityHeaders().HTTPS {
+			t.Fatal("expected HTTPS not to be set")
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// useTestTracer installs a global tracer provider exporting the spans synchronously to the returned
+// exporter, along with the W3C propagator, until the test ends
+func useTestTracer(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+	return exporter
+}
+
+// findSpan returns the span named name, failing the test when there's none
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+	t.Fatalf("expected a %s span, got %d others", name, len(spans))
+	return tracetest.SpanStub{}
+}
+
+// spanAttributes returns the attributes of the span by key
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	usersRepo := repos.NewTracedUsersRepository(getDefaultMockUsersRepository(), nil)
+	router := prepareRouter(http.MethodGet, "/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := usersRepo.FindByID(r.Context(), "1"); err != nil {
+			t.Fatal(err)
+		}
+		respondNoContent(w, http.StatusNoContent)
+	})
+	router.HandleFunc("/failing", func(w http.ResponseWriter, r *http.Request) {
+		respondInternalError(w)
+	})
+	h := tracingMiddleware(router)(router)
+
+	t.Run("expect a server span named after the route template", func(t *testing.T) {
+		exporter := useTestTracer(t)
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+
+		span := findSpan(t, exporter.GetSpans(), "GET /users/{id}")
+		if span.SpanKind != trace.SpanKindServer {
+			t.Fatalf("expected a server span, got %s", span.SpanKind)
+		}
+		if span.Status.Code != codes.Unset {
+			t.Fatalf("expected the status to be unset, got %s", span.Status.Code)
+		}
+		attrs := spanAttributes(span)
+		expected := map[attribute.Key]string{
+			"http.method": "GET",
+			"http.route":  "/users/{id}",
+			"http.target": "/users/1",
+		}
+		for key, value := range expected {
+			if attrs[key].AsString() != value {
+				t.Fatalf("expected %s to be %s, got %q", key, value, attrs[key].Emit())
+			}
+		}
+		if status := attrs["http.status_code"].AsInt64(); status != http.StatusNoContent {
+			t.Fatalf("expected the status code 204, got %d", status)
+		}
+	})
+
+	t.Run("expect the repository span to be a child of the request span", func(t *testing.T) {
+		exporter := useTestTracer(t)
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+
+		spans := exporter.GetSpans()
+		server := findSpan(t, spans, "GET /users/{id}")
+		child := findSpan(t, spans, "UsersRepository.FindByID")
+		if child.Parent.SpanID() != server.SpanContext.SpanID() || child.SpanContext.TraceID() != server.SpanContext.TraceID() {
+			t.Fatalf("expected the repository span under %s, got parent %s", server.SpanContext.SpanID(), child.Parent.SpanID())
+		}
+		if child.SpanKind != trace.SpanKindClient {
+			t.Fatalf("expected a client span, got %s", child.SpanKind)
+		}
+	})
+
+	t.Run("expect a server error to set the error status", func(t *testing.T) {
+		exporter := useTestTracer(t)
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/failing", nil))
+
+		span := findSpan(t, exporter.GetSpans(), "GET /failing")
+		if span.Status.Code != codes.Error {
+			t.Fatalf("expected the error status, got %s", span.Status.Code)
+		}
+	})
+
+	t.Run("expect the trace of the caller to be continued", func(t *testing.T) {
+		exporter := useTestTracer(t)
+
+		r := httptest.NewRequest("GET", "/users/1", nil)
+		r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		h.ServeHTTP(httptest.NewRecorder(), r)
+
+		span := findSpan(t, exporter.GetSpans(), "GET /users/{id}")
+		if traceID := span.SpanContext.TraceID().String(); traceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Fatalf("expected the trace of the caller, got %s", traceID)
+		}
+		if parent := span.Parent.SpanID().String(); parent != "00f067aa0ba902b7" || !span.Parent.IsRemote() {
+			t.Fatalf("expected the remote parent 00f067aa0ba902b7, got %s", parent)
+		}
+	})
+}
+
+func TestTracedUsersRepository(t *testing.T) {
+	t.Run("expect a failing call to be recorded on its span and told to the observer", func(t *testing.T) {
+		exporter := useTestTracer(t)
+		mock := getDefaultMockUsersRepository()
+		mock.findByIDImpl = func(context.Context, string) (*repos.User, error) {
+			return nil, errors.New("connection refused")
+		}
+		var observed []string
+		usersRepo := repos.NewTracedUsersRepository(mock, func(_ context.Context, name string, _ time.Duration, err error) {
+			if err != nil {
+				observed = append(observed, name)
+			}
+		})
+
+		if _, err := usersRepo.FindByID(context.Background(), "1"); err == nil {
+			t.Fatal("expected the error of the repository")
+		}
+
+		span := findSpan(t, exporter.GetSpans(), "UsersRepository.FindByID")
+		if span.Status.Code != codes.Error || span.Status.Description != "connection refused" {
+			t.Fatalf("expected the error status, got %+v", span.Status)
+		}
+		if attrs := spanAttributes(span); attrs["db.system"].AsString() != "postgresql" {
+			t.Fatalf("expected a postgresql span, got %q", attrs["db.system"].Emit())
+		}
+		if len(observed) != 1 || observed[0] != "UsersRepository.FindByID" {
+			t.Fatalf("expected the failed call to be observed, got %v", observed)
+		}
+	})
+}
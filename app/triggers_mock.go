@@ -0,0 +1,31 @@
+package app
+
+import (
+	"context"
+	"sync"
+)
+
+// mockTriggersRepository keeps the pending triggers in memory
+type mockTriggersRepository struct {
+	mu      sync.Mutex
+	pending map[string]bool
+}
+
+func newMockTriggersRepository() *mockTriggersRepository {
+	return &mockTriggersRepository{pending: map[string]bool{}}
+}
+
+func (r *mockTriggersRepository) Create(_ context.Context, userID string, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending[userID+"/"+name] = true
+	return nil
+}
+
+func (r *mockTriggersRepository) Take(_ context.Context, userID string, name string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	taken := r.pending[userID+"/"+name]
+	delete(r.pending, userID+"/"+name)
+	return taken, nil
+}
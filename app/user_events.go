@@ -0,0 +1,55 @@
+package app
+
+import (
+	"appdoki-be/app/repositories"
+	"context"
+	"sync"
+)
+
+// the internal events of the lifecycle of the users
+const (
+	// eventUserCreated is published when a user signs in for the first time
+	eventUserCreated = "user.created"
+	// eventDeviceRegistered is published when a user registers a new device
+	eventDeviceRegistered = "device.registered"
+)
+
+// userEvent is the payload of the internal events, the user being set for eventUserCreated and the
+// device for eventDeviceRegistered
+type userEvent struct {
+	UserID string
+	User   *repositories.User
+	Device *repositories.DeviceToken
+}
+
+// userEventHandler handles an internal event, in the goroutine publishing it: the slow work goes to
+// the background workers
+type userEventHandler func(ctx context.Context, event userEvent)
+
+// eventDispatcher dispatches the internal events to their subscribers, in process
+type eventDispatcher struct {
+	mu          sync.RWMutex
+	subscribers map[string][]userEventHandler
+}
+
+func newEventDispatcher() *eventDispatcher {
+	return &eventDispatcher{subscribers: map[string][]userEventHandler{}}
+}
+
+// Subscribe has the handler called with the events of the name, after the subscribers before it
+func (d *eventDispatcher) Subscribe(name string, handler userEventHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscribers[name] = append(d.subscribers[name], handler)
+}
+
+// Publish calls the subscribers of the event, in the order they subscribed
+func (d *eventDispatcher) Publish(ctx context.Context, name string, event userEvent) {
+	d.mu.RLock()
+	handlers := d.subscribers[name]
+	d.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+}
@@ -1,12 +1,12 @@
 package app
 
 import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/notify"
 	"appdoki-be/app/repositories"
 	"context"
-	"firebase.google.com/go/v4/messaging"
 	"fmt"
 	"github.com/gorilla/mux"
-	log "github.com/sirupsen/logrus"
 	"net/http"
 	"strconv"
 )
@@ -30,34 +30,63 @@ func (p *CreateUserPayload) validate() []string {
 	return errs
 }
 
+// userWithRole is the representation of a user along with its role, served to the user itself only
+type userWithRole struct {
+	*repositories.User
+	Role string `json:"role"`
+}
+
+func newUserWithRole(user *repositories.User) *userWithRole {
+	if user == nil {
+		return nil
+	}
+	return &userWithRole{User: user, Role: user.Role}
+}
+
 // UsersHandler holds handler dependencies
 type UsersHandler struct {
 	userRepo  repositories.UsersRepositoryInterface
 	beersRepo repositories.BeersRepositoryInterface
-	notifier  notifier
+	notifier  notify.Notifier
+	workers   *workerGroup
 }
 
 // NewUsersHandler returns an initialized users handler with the required dependencies
 func NewUsersHandler(
 	userRepo repositories.UsersRepositoryInterface,
 	beersRepo repositories.BeersRepositoryInterface,
-	notifierSrv notifier) *UsersHandler {
+	notifierSrv notify.Notifier,
+	workers *workerGroup) *UsersHandler {
 	return &UsersHandler{
 		userRepo:  userRepo,
 		beersRepo: beersRepo,
 		notifier:  notifierSrv,
+		workers:   workers,
 	}
 }
 
-// Get gets all users
+// Get gets all users, or those matching the filter parameter
 func (h *UsersHandler) Get(w http.ResponseWriter, r *http.Request) {
-	users, err := h.userRepo.GetAll(r.Context())
+	params := newQueryParams(r)
+	f := params.Filter("filter", repositories.UserFilterFields)
+	if err := params.Err(); err != nil {
+		respondRequestError(w, err)
+		return
+	}
+
+	// when the watermark can't be read the list is served unconditionally
+	lastModified, _ := h.userRepo.LastModified(r.Context())
+	if notModified(w, r, lastModified) {
+		return
+	}
+
+	users, err := h.userRepo.GetAll(r.Context(), f)
 	if err != nil {
-		respondInternalError(w)
+		respondRepositoryError(w, err)
 		return
 	}
 
-	respondJSON(w, users, http.StatusOK)
+	respondJSONStream(w, r, users, http.StatusOK)
 }
 
 // GetByID tries to get a user by ID
@@ -65,25 +94,41 @@ func (h *UsersHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	uid, ok := vars["id"]
 	if !ok {
-		log.Error("could not read id param in UsersHandler.GetByID")
+		logging.FromContext(r.Context()).Error("could not read id param in UsersHandler.GetByID")
 		respondInternalError(w)
 		return
 	}
 
 	user, err := h.userRepo.FindByID(r.Context(), uid)
 	if err != nil {
-		respondInternalError(w)
+		respondRepositoryError(w, err)
 		return
 	}
 
 	if user == nil {
-		respondJSON(w, &appError{
-			Errors: []string{"user not found"},
-		}, http.StatusNotFound)
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "user not found", nil)
 		return
 	}
 
-	respondJSON(w, user, http.StatusOK)
+	respondJSON(w, r, user, http.StatusOK)
+}
+
+// GetMe gets the authenticated user
+func (h *UsersHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	uid, _ := r.Context().Value("userID").(string)
+
+	user, err := h.userRepo.FindByID(r.Context(), uid)
+	if err != nil {
+		respondRepositoryError(w, err)
+		return
+	}
+
+	if user == nil {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "user not found", nil)
+		return
+	}
+
+	respondJSON(w, r, newUserWithRole(user), http.StatusOK)
 }
 
 // GiveBeers creates a beer transaction between two users
@@ -93,74 +138,71 @@ func (h *UsersHandler) GiveBeers(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	takerUserId, ok := vars["id"]
 	if !ok {
-		log.Error("could not read id param in UsersHandler.GiveBeers")
+		logging.FromContext(r.Context()).Error("could not read id param in UsersHandler.GiveBeers")
 		respondInternalError(w)
 		return
 	}
 
 	if userID == takerUserId {
-		respondJSON(w, &appError{
-			Errors: []string{"oi, cheeky bastard, give beers to others"},
-		}, http.StatusForbidden)
+		respondError(w, http.StatusForbidden, ErrCodeForbidden, "oi, cheeky bastard, give beers to others", nil)
 		return
 	}
 
 	beersParam, ok := vars["beers"]
 	if !ok {
-		log.Error("could not read beers param in UsersHandler.GiveBeers")
+		logging.FromContext(r.Context()).Error("could not read beers param in UsersHandler.GiveBeers")
 		respondInternalError(w)
 		return
 	}
 
 	beers, err := strconv.Atoi(beersParam)
 	if err != nil {
-		respondJSON(w, &appError{
-			Errors: []string{"invalid beers param: number expected"},
-		}, http.StatusBadRequest)
+		respondError(w, http.StatusBadRequest, ErrCodeValidationFailed, "invalid beers param: number expected", nil)
 		return
 	}
 
 	if beers <= 0 {
-		respondJSON(w, &appError{
-			Errors: []string{"invalid amount of beers: don't be a cheap bastard!"},
-		}, http.StatusBadRequest)
+		respondError(w, http.StatusBadRequest, ErrCodeValidationFailed, "invalid amount of beers: don't be a cheap bastard!", nil)
 		return
 	}
 
 	user, err := h.userRepo.FindByID(r.Context(), takerUserId)
 	if err != nil {
-		respondInternalError(w)
+		respondRepositoryError(w, err)
 		return
 	}
 
 	if user == nil {
-		respondJSON(w, &appError{
-			Errors: []string{"user not found"},
-		}, http.StatusNotFound)
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "user not found", nil)
 		return
 	}
 
 	transferID, err := h.userRepo.AddBeerTransfer(r.Context(), userID, takerUserId, beers)
 	if err != nil {
-		respondInternalError(w)
+		respondRepositoryError(w, err)
 		return
 	}
 
-	go func() {
-		backgroundCtx := context.Background()
-		transfer, err := h.beersRepo.GetBeerTransfer(backgroundCtx, transferID)
+	h.workers.Go(r.Context(), func(ctx context.Context) {
+		// the transfer was just written, the replica may not have it yet
+		transfer, err := h.beersRepo.GetBeerTransfer(repositories.ReadFresh(ctx), transferID)
 		if err != nil {
-			log.Error("failed to get beer transfer")
+			logging.FromContext(ctx).Error("failed to get beer transfer")
 			return
 		}
 
-		notification := &messaging.Notification{
-			Title: "BeerTab event",
-			Body:  fmt.Sprintf("%s just rewarded %s with %d beers!", transfer.Giver.Name, transfer.Receiver.Name, beers),
-		}
-
-		h.notifier.notifyAll(beersTopic, notification, transfer.ToStringMap())
-	}()
+		payload := beerTransferPayload{TransferID: transferID, Giver: transfer.Giver.Name, Receiver: transfer.Receiver.Name, Beers: beers}
+		h.notifier.SendToTopic(ctx, beersTopic, notify.Notification{
+			Data:    transfer.ToStringMap(),
+			Event:   eventBeerGiven,
+			Payload: payload,
+		})
+		h.notifier.SendToUser(ctx, takerUserId, notify.Notification{
+			Data:    transfer.ToStringMap(),
+			Event:   notify.EventBeerReceived,
+			Payload: payload,
+		})
+	})
 
 	respondNoContent(w, http.StatusNoContent)
 }
@@ -170,28 +212,27 @@ func (h *UsersHandler) BeersSummary(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID, ok := vars["id"]
 	if !ok {
-		log.Error("could not read id param in UsersHandler.BeersSummary")
+		logging.FromContext(r.Context()).Error("could not read id param in UsersHandler.BeersSummary")
 		respondInternalError(w)
 		return
 	}
 
 	user, err := h.userRepo.FindByID(r.Context(), userID)
 	if err != nil {
-		respondInternalError(w)
+		respondRepositoryError(w, err)
 		return
 	}
 
 	if user == nil {
-		respondJSON(w, &appError{
-			Errors: []string{"user not found"},
-		}, http.StatusNotFound)
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "user not found", nil)
 		return
 	}
 
 	beerLog, err := h.userRepo.GetBeerTransfersSummary(r.Context(), userID)
 	if err != nil {
-		respondInternalError(w)
+		respondRepositoryError(w, err)
+		return
 	}
 
-	respondJSON(w, beerLog, http.StatusOK)
+	respondJSON(w, r, beerLog, http.StatusOK)
 }
@@ -1,26 +1,36 @@
 package app
 
 import (
+	"appdoki-be/app/filter"
 	repos "appdoki-be/app/repositories"
 	"context"
 	"github.com/brianvoe/gofakeit/v5"
 	"strconv"
+	"time"
 )
 
 type mockUsersRepository struct {
-	getAllImpl             func(ctx context.Context) ([]*repos.User, error)
+	getAllImpl             func(ctx context.Context, f filter.Filter) ([]*repos.User, error)
+	lastModifiedImpl       func(ctx context.Context) (time.Time, error)
 	findByIDImpl           func(ctx context.Context, ID string) (*repos.User, error)
 	findByEmailImpl        func(ctx context.Context, email string) (*repos.User, error)
+	isDeactivatedImpl      func(ctx context.Context, ID string) (bool, error)
 	findOrCreateUserImpl   func(ctx context.Context, userData *repos.User) (*repos.User, bool, error)
 	createImpl             func(ctx context.Context, user *repos.User) (*repos.User, error)
 	updateImpl             func(ctx context.Context, user *repos.User) (*repos.User, error)
 	deleteImpl             func(ctx context.Context, ID string) (bool, error)
+	deactivateManyImpl     func(ctx context.Context, IDs []string) ([]string, error)
+	setRoleManyImpl        func(ctx context.Context, IDs []string, role string) ([]string, error)
 	addBeerTransferImpl    func(ctx context.Context, giverID string, takerID string, beers int) (int, error)
 	getBeerTransferLogImpl func(ctx context.Context, userID string) (*repos.UserBeerLog, error)
 }
 
-func (r *mockUsersRepository) GetAll(ctx context.Context) ([]*repos.User, error) {
-	return r.getAllImpl(ctx)
+func (r *mockUsersRepository) GetAll(ctx context.Context, f filter.Filter) ([]*repos.User, error) {
+	return r.getAllImpl(ctx, f)
+}
+
+func (r *mockUsersRepository) LastModified(ctx context.Context) (time.Time, error) {
+	return r.lastModifiedImpl(ctx)
 }
 
 func (r *mockUsersRepository) FindByID(ctx context.Context, ID string) (*repos.User, error) {
@@ -31,6 +41,10 @@ func (r *mockUsersRepository) FindByEmail(ctx context.Context, email string) (*r
 	return r.findByEmailImpl(ctx, email)
 }
 
+func (r *mockUsersRepository) IsDeactivated(ctx context.Context, ID string) (bool, error) {
+	return r.isDeactivatedImpl(ctx, ID)
+}
+
 func (r *mockUsersRepository) Create(ctx context.Context, user *repos.User) (*repos.User, error) {
 	return r.createImpl(ctx, user)
 }
@@ -47,6 +61,14 @@ func (r *mockUsersRepository) Delete(ctx context.Context, ID string) (bool, erro
 	return r.deleteImpl(ctx, ID)
 }
 
+func (r *mockUsersRepository) DeactivateMany(ctx context.Context, IDs []string) ([]string, error) {
+	return r.deactivateManyImpl(ctx, IDs)
+}
+
+func (r *mockUsersRepository) SetRoleMany(ctx context.Context, IDs []string, role string) ([]string, error) {
+	return r.setRoleManyImpl(ctx, IDs, role)
+}
+
 func (r *mockUsersRepository) AddBeerTransfer(ctx context.Context, giverID string, takerID string, beers int) (int, error) {
 	return r.addBeerTransferImpl(ctx, giverID, takerID, beers)
 }
@@ -57,15 +79,21 @@ func (r *mockUsersRepository) GetBeerTransfersSummary(ctx context.Context, userI
 
 func getDefaultMockUsersRepository() *mockUsersRepository {
 	return &mockUsersRepository{
-		getAllImpl: func(_ context.Context) ([]*repos.User, error) {
+		getAllImpl: func(_ context.Context, _ filter.Filter) ([]*repos.User, error) {
 			return []*repos.User{generateRandomUserMock()}, nil
 		},
+		lastModifiedImpl: func(ctx context.Context) (time.Time, error) {
+			return time.Time{}, nil
+		},
 		findByIDImpl: func(ctx context.Context, ID string) (*repos.User, error) {
 			return generateRandomUserMock(), nil
 		},
 		findByEmailImpl: func(ctx context.Context, email string) (*repos.User, error) {
 			return generateRandomUserMock(), nil
 		},
+		isDeactivatedImpl: func(ctx context.Context, ID string) (bool, error) {
+			return false, nil
+		},
 		findOrCreateUserImpl: func(ctx context.Context, user *repos.User) (*repos.User, bool, error) {
 			return generateRandomUserMock(), true, nil
 		},
@@ -78,6 +106,12 @@ func getDefaultMockUsersRepository() *mockUsersRepository {
 		deleteImpl: func(ctx context.Context, ID string) (bool, error) {
 			return true, nil
 		},
+		deactivateManyImpl: func(ctx context.Context, IDs []string) ([]string, error) {
+			return IDs, nil
+		},
+		setRoleManyImpl: func(ctx context.Context, IDs []string, role string) ([]string, error) {
+			return IDs, nil
+		},
 		addBeerTransferImpl: func(ctx context.Context, giverID string, takerID string, beers int) (int, error) {
 			return 1, nil
 		},
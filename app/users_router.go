@@ -6,25 +6,38 @@ import (
 )
 
 func (a *Application) UsersRouter(router *mux.Router) {
-	usersHandler := NewUsersHandler(a.usersRepository, a.beersRepository, a.notifier)
+	usersHandler := NewUsersHandler(a.usersRepository, a.beersRepository, a.notifier, a.workers)
+	devicesHandler := NewDevicesHandler(a.devicesRepository, a.notifier, a.events, a.workers)
+	preferencesHandler := NewPreferencesHandler(a.preferencesRepository)
+	notificationsHandler := NewNotificationsHandler(a.notificationsRepository, a.notificationStreams, a.notifier, a.workers)
 
-	router.
-		Methods(http.MethodGet).
-		Path("/users").
-		HandlerFunc(a.JwtVerify(usersHandler.Get))
-
-	router.
-		Methods(http.MethodGet).
-		Path("/users/{id}").
-		HandlerFunc(a.JwtVerify(usersHandler.GetByID))
-
-	router.
-		Methods(http.MethodGet).
-		Path("/users/{id}/beers").
-		HandlerFunc(a.JwtVerify(usersHandler.BeersSummary))
-
-	router.
-		Methods(http.MethodPost).
-		Path("/users/{id}/beers/{beers}").
-		HandlerFunc(a.JwtVerify(usersHandler.GiveBeers))
+	a.mount(router,
+		routeDef{methods: []string{http.MethodGet}, path: "/users", access: authenticatedAccess, streaming: true,
+			handler: a.RateLimit(readRateLimit, a.CacheControl(privateCache, usersHandler.Get))},
+		// registered before /users/{id} to take precedence
+		routeDef{methods: []string{http.MethodGet}, path: "/users/me", access: authenticatedAccess, feature: "users_me",
+			handler: a.RateLimit(readRateLimit, a.CacheControl(privateCache, usersHandler.GetMe))},
+		routeDef{methods: []string{http.MethodPost}, path: "/users/me/devices", access: authenticatedAccess,
+			handler: a.RateLimit(writeRateLimit, a.CacheControl(noStoreCache, devicesHandler.Register))},
+		routeDef{methods: []string{http.MethodDelete}, path: "/users/me/devices/{token}", access: authenticatedAccess,
+			handler: a.RateLimit(writeRateLimit, a.CacheControl(noStoreCache, devicesHandler.Delete))},
+		routeDef{methods: []string{http.MethodGet}, path: "/users/me/notifications", access: authenticatedAccess,
+			handler: a.RateLimit(readRateLimit, a.CacheControl(noStoreCache, notificationsHandler.Get))},
+		routeDef{methods: []string{http.MethodGet}, path: "/users/me/notifications/stream", access: authenticatedAccess,
+			handler: a.RateLimit(readRateLimit, notificationsHandler.Stream)},
+		routeDef{methods: []string{http.MethodPost}, path: "/users/me/notifications/read-all", access: authenticatedAccess,
+			handler: a.RateLimit(writeRateLimit, a.CacheControl(noStoreCache, notificationsHandler.MarkAllRead))},
+		routeDef{methods: []string{http.MethodPost}, path: "/users/me/notifications/{id}/read", access: authenticatedAccess,
+			handler: a.RateLimit(writeRateLimit, a.CacheControl(noStoreCache, notificationsHandler.MarkRead))},
+		routeDef{methods: []string{http.MethodGet}, path: "/users/me/preferences/notifications", access: authenticatedAccess,
+			handler: a.RateLimit(readRateLimit, a.CacheControl(noStoreCache, preferencesHandler.GetNotifications))},
+		routeDef{methods: []string{http.MethodPut}, path: "/users/me/preferences/notifications", access: authenticatedAccess,
+			handler: a.RateLimit(writeRateLimit, a.CacheControl(noStoreCache, preferencesHandler.UpdateNotifications))},
+		routeDef{methods: []string{http.MethodGet}, path: "/users/{id}", access: authenticatedAccess,
+			handler: a.RateLimit(readRateLimit, a.CacheControl(privateCache, usersHandler.GetByID))},
+		routeDef{methods: []string{http.MethodGet}, path: "/users/{id}/beers", access: authenticatedAccess,
+			handler: a.RateLimit(readRateLimit, a.CacheControl(privateCache, usersHandler.BeersSummary))},
+		routeDef{methods: []string{http.MethodPost}, path: "/users/{id}/beers/{beers}", access: authenticatedAccess,
+			handler: a.RateLimit(writeRateLimit, a.CacheControl(noStoreCache, a.Idempotent(usersHandler.GiveBeers)))},
+	)
 }
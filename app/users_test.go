@@ -1,30 +1,25 @@
 package app
 
 import (
+	"appdoki-be/app/filter"
+	"appdoki-be/app/notify"
+	"appdoki-be/app/reporting"
 	repos "appdoki-be/app/repositories"
 	"context"
 	"encoding/json"
-	"firebase.google.com/go/v4/messaging"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
-type mockNotifier struct{}
-
-func (n *mockNotifier) notifyAll(_ string, _ *messaging.Notification, _ map[string]string) {}
-func (n *mockNotifier) messageAll(_ string, _ map[string]string)                           {}
-
-func getMockNotifier() *mockNotifier {
-	return &mockNotifier{}
-}
-
 func TestUsersHandler_Get(t *testing.T) {
 	defaultHandler := NewUsersHandler(
 		getDefaultMockUsersRepository(),
 		getDefaultMockBeersRepository(),
-		getMockNotifier())
+		notify.NewFake(),
+		newWorkerGroup(reporting.Noop{}))
 
 	t.Run("expect GET /users to return 200 and a list of users", func(t *testing.T) {
 		r := httptest.NewRequest("GET", "/users", nil)
@@ -51,10 +46,10 @@ func TestUsersHandler_Get(t *testing.T) {
 
 	t.Run("expect GET /users to return 200 and an empty list of users ", func(t *testing.T) {
 		mock := getDefaultMockUsersRepository()
-		mock.getAllImpl = func(_ context.Context) ([]*repos.User, error) {
+		mock.getAllImpl = func(_ context.Context, _ filter.Filter) ([]*repos.User, error) {
 			return []*repos.User{}, nil
 		}
-		uh := NewUsersHandler(mock, getDefaultMockBeersRepository(), getMockNotifier())
+		uh := NewUsersHandler(mock, getDefaultMockBeersRepository(), notify.NewFake(), newWorkerGroup(reporting.Noop{}))
 
 		r := httptest.NewRequest("GET", "/users", nil)
 		w := httptest.NewRecorder()
@@ -88,7 +83,7 @@ func TestUsersHandler_GetByID(t *testing.T) {
 		mock.findByIDImpl = func(ctx context.Context, ID string) (*repos.User, error) {
 			return generateRandomUserMockWithID("1"), nil
 		}
-		uh := NewUsersHandler(mock, getDefaultMockBeersRepository(), getMockNotifier())
+		uh := NewUsersHandler(mock, getDefaultMockBeersRepository(), notify.NewFake(), newWorkerGroup(reporting.Noop{}))
 
 		r := httptest.NewRequest("GET", "/users/1", nil)
 		w := httptest.NewRecorder()
@@ -117,7 +112,7 @@ func TestUsersHandler_GetByID(t *testing.T) {
 		mock.findByIDImpl = func(ctx context.Context, ID string) (*repos.User, error) {
 			return nil, nil
 		}
-		uh := NewUsersHandler(mock, getDefaultMockBeersRepository(), getMockNotifier())
+		uh := NewUsersHandler(mock, getDefaultMockBeersRepository(), notify.NewFake(), newWorkerGroup(reporting.Noop{}))
 
 		r := httptest.NewRequest("GET", "/users/1", nil)
 		w := httptest.NewRecorder()
@@ -127,6 +122,8 @@ func TestUsersHandler_GetByID(t *testing.T) {
 		resp := w.Result()
 
 		assertStatusCode(t, resp, http.StatusNotFound)
+		assertJSONContentType(t, resp)
+		assertErrorCode(t, resp, ErrCodeNotFound)
 	})
 }
 
@@ -134,7 +131,8 @@ func TestUsersHandler_GiveBeers(t *testing.T) {
 	defaultHandler := NewUsersHandler(
 		getDefaultMockUsersRepository(),
 		getDefaultMockBeersRepository(),
-		getMockNotifier())
+		notify.NewFake(),
+		newWorkerGroup(reporting.Noop{}))
 
 	t.Run("expect POST /users/{id}/beers/{beers} to return 403 when a user gives beers to self", func(t *testing.T) {
 		r := httptest.NewRequest("POST", "/users/1/beers/10", nil)
@@ -145,6 +143,7 @@ func TestUsersHandler_GiveBeers(t *testing.T) {
 		resp := w.Result()
 
 		assertStatusCode(t, resp, http.StatusForbidden)
+		assertErrorCode(t, resp, ErrCodeForbidden)
 	})
 
 	t.Run("expect POST /users/{id}/beers/{beers} to return 404 when beers param invalid", func(t *testing.T) {
@@ -156,6 +155,7 @@ func TestUsersHandler_GiveBeers(t *testing.T) {
 		resp := w.Result()
 
 		assertStatusCode(t, resp, http.StatusBadRequest)
+		assertErrorCode(t, resp, ErrCodeValidationFailed)
 	})
 
 	t.Run("expect POST /users/{id}/beers/{beers} to return 404 when beers param negative", func(t *testing.T) {
@@ -167,6 +167,29 @@ func TestUsersHandler_GiveBeers(t *testing.T) {
 		resp := w.Result()
 
 		assertStatusCode(t, resp, http.StatusBadRequest)
+		assertErrorCode(t, resp, ErrCodeValidationFailed)
+	})
+
+	t.Run("expect POST /users/{id}/beers/{beers} to return 403 when the giver is deactivated", func(t *testing.T) {
+		urMock := getDefaultMockUsersRepository()
+		urMock.addBeerTransferImpl = func(ctx context.Context, giverID string, takerID string, beers int) (int, error) {
+			return 0, repos.ErrUserDeactivated
+		}
+		notifier := notify.NewFake()
+		uh := NewUsersHandler(urMock, getDefaultMockBeersRepository(), notifier, newWorkerGroup(reporting.Noop{}))
+
+		r := httptest.NewRequest("POST", "/users/999/beers/10", nil)
+		r = r.WithContext(context.WithValue(r.Context(), "userID", "1"))
+		w := httptest.NewRecorder()
+		router := prepareRouter(http.MethodPost, "/users/{id}/beers/{beers}", uh.GiveBeers)
+		router.ServeHTTP(w, r)
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusForbidden)
+		assertErrorCode(t, resp, ErrCodeUserDeactivated)
+		if sent := notifier.Sent(); len(sent) != 0 {
+			t.Fatalf("expected nothing to be notified, got %+v", sent)
+		}
 	})
 
 	t.Run("expect POST /users/{id}/beers/{beers} to return 200", func(t *testing.T) {
@@ -178,7 +201,9 @@ func TestUsersHandler_GiveBeers(t *testing.T) {
 		brMock.getBeerTransferImpl = func(ctx context.Context, id int) (*repos.BeerTransferFeedItem, error) {
 			return generateRandomBeerTransferMock(), nil
 		}
-		uh := NewUsersHandler(urMock, brMock, getMockNotifier())
+		notifier := notify.NewFake()
+		workers := newWorkerGroup(reporting.Noop{})
+		uh := NewUsersHandler(urMock, brMock, notifier, workers)
 
 		r := httptest.NewRequest("POST", "/users/999/beers/10", nil)
 		r = r.WithContext(context.WithValue(r.Context(), "userID", "1"))
@@ -188,6 +213,20 @@ func TestUsersHandler_GiveBeers(t *testing.T) {
 		resp := w.Result()
 
 		assertStatusCode(t, resp, http.StatusNoContent)
+
+		if err := workers.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		sent := notifier.Sent()
+		if len(sent) != 2 || sent[0].Topic != beersTopic || sent[0].Notification.Event != eventBeerGiven {
+			t.Fatalf("expected the transfer to be notified on the beers topic, got %+v", sent)
+		}
+		if len(sent[0].Notification.Data) == 0 {
+			t.Fatal("expected the transfer to be sent along")
+		}
+		if sent[1].UserID != "999" || sent[1].Notification.Event != notify.EventBeerReceived {
+			t.Fatalf("expected the receiver to be notified, got %+v", sent[1])
+		}
 	})
 }
 
@@ -195,7 +234,8 @@ func TestUsersHandler_BeersSummary(t *testing.T) {
 	defaultHandler := NewUsersHandler(
 		getDefaultMockUsersRepository(),
 		getDefaultMockBeersRepository(),
-		getMockNotifier())
+		notify.NewFake(),
+		newWorkerGroup(reporting.Noop{}))
 
 	t.Run("expect GET /users/{id}/beers to return 200", func(t *testing.T) {
 		r := httptest.NewRequest("GET", "/users/1/beers", nil)
@@ -220,3 +260,103 @@ func TestUsersHandler_BeersSummary(t *testing.T) {
 		}
 	})
 }
+
+func TestUsersHandler_Role(t *testing.T) {
+	admin := &repos.User{ID: "1", Name: "Ana", Email: "ana@example.com", Role: repos.RoleAdmin}
+	mock := getDefaultMockUsersRepository()
+	mock.getAllImpl = func(_ context.Context, _ filter.Filter) ([]*repos.User, error) {
+		return []*repos.User{admin}, nil
+	}
+	mock.findByIDImpl = func(_ context.Context, _ string) (*repos.User, error) {
+		return admin, nil
+	}
+	uh := NewUsersHandler(mock, getDefaultMockBeersRepository(), notify.NewFake(), newWorkerGroup(reporting.Noop{}))
+
+	t.Run("expect the role to be left out of the listed users", func(t *testing.T) {
+		for _, path := range []string{"/users", "/users/1"} {
+			w := httptest.NewRecorder()
+			router := prepareRouter(http.MethodGet, "/users", uh.Get)
+			router.HandleFunc("/users/{id}", uh.GetByID).Methods(http.MethodGet)
+			router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+			assertStatusCode(t, w.Result(), http.StatusOK)
+			if strings.Contains(w.Body.String(), "role") {
+				t.Fatalf("expected no role in GET %s, got %s", path, w.Body.String())
+			}
+		}
+	})
+
+	t.Run("expect GET /users/me to tell the role", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/users/me", nil)
+		r = r.WithContext(context.WithValue(r.Context(), "userID", "1"))
+		w := httptest.NewRecorder()
+		prepareRouter(http.MethodGet, "/users/me", uh.GetMe).ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusOK)
+		var user struct {
+			ID   string `json:"id"`
+			Role string `json:"role"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&user); err != nil {
+			t.Fatal(err)
+		}
+		if user.ID != "1" || user.Role != repos.RoleAdmin {
+			t.Fatalf("expected user 1 to be an admin, got %+v", user)
+		}
+	})
+
+	t.Run("expect the role not to be filterable", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		prepareRouter(http.MethodGet, "/users", uh.Get).ServeHTTP(w, httptest.NewRequest("GET", "/users?filter=role:admin", nil))
+
+		assertStatusCode(t, w.Result(), http.StatusBadRequest)
+	})
+}
+
+func TestUsersHandler_Get_Filter(t *testing.T) {
+	t.Run("expect the filter to be passed to the repository", func(t *testing.T) {
+		var received filter.Filter
+		mock := getDefaultMockUsersRepository()
+		mock.getAllImpl = func(_ context.Context, f filter.Filter) ([]*repos.User, error) {
+			received = f
+			return []*repos.User{}, nil
+		}
+		uh := NewUsersHandler(mock, getDefaultMockBeersRepository(), notify.NewFake(), newWorkerGroup(reporting.Noop{}))
+
+		r := httptest.NewRequest("GET", "/users?filter=picture:null", nil)
+		w := httptest.NewRecorder()
+		prepareRouter(http.MethodGet, "/users", uh.Get).ServeHTTP(w, r)
+
+		assertStatusCode(t, w.Result(), http.StatusOK)
+		if len(received) != 1 || received[0].Field != "picture" || received[0].Value != nil {
+			t.Fatalf("expected the picture:null condition, got %+v", received)
+		}
+	})
+
+	t.Run("expect an unknown filter field to return 400 listing the allowed fields", func(t *testing.T) {
+		uh := NewUsersHandler(getDefaultMockUsersRepository(), getDefaultMockBeersRepository(), notify.NewFake(), newWorkerGroup(reporting.Noop{}))
+
+		r := httptest.NewRequest("GET", "/users?filter=password:1234", nil)
+		w := httptest.NewRecorder()
+		prepareRouter(http.MethodGet, "/users", uh.Get).ServeHTTP(w, r)
+
+		resp := w.Result()
+
+		assertStatusCode(t, resp, http.StatusBadRequest)
+		var envelope struct {
+			Error struct {
+				Code    string       `json:"code"`
+				Details []fieldError `json:"details"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+			t.Fatal(err)
+		}
+		if envelope.Error.Code != ErrCodeValidationFailed || len(envelope.Error.Details) != 1 {
+			t.Fatalf("expected one validation problem, got %+v", envelope.Error)
+		}
+		if detail := envelope.Error.Details[0]; detail.Field != "filter" || !strings.Contains(detail.Message, "allowed fields are created_at, email, name, picture") {
+			t.Fatalf("expected the allowed fields to be listed, got %+v", detail)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// mockWebhookReceiptsRepository keeps the claimed deliveries in memory until they expire, with
+// the same claim semantics as the database insert
+type mockWebhookReceiptsRepository struct {
+	mu      sync.Mutex
+	expires map[string]time.Time
+	// ttls are the ttls the deliveries were last claimed for
+	ttls map[string]time.Duration
+}
+
+func newMockWebhookReceiptsRepository() *mockWebhookReceiptsRepository {
+	return &mockWebhookReceiptsRepository{expires: map[string]time.Time{}, ttls: map[string]time.Duration{}}
+}
+
+func (r *mockWebhookReceiptsRepository) Claim(_ context.Context, source string, deliveryID string, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if expires, ok := r.expires[source+":"+deliveryID]; ok && now.Before(expires) {
+		return false, nil
+	}
+	r.expires[source+":"+deliveryID] = now.Add(ttl)
+	r.ttls[source+":"+deliveryID] = ttl
+	return true, nil
+}
+
+func (r *mockWebhookReceiptsRepository) Release(_ context.Context, source string, deliveryID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.expires, source+":"+deliveryID)
+	return nil
+}
+
+func (r *mockWebhookReceiptsRepository) ttl(source string, deliveryID string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ttls[source+":"+deliveryID]
+}
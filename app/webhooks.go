@@ -0,0 +1,209 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidSignature = errors.New("invalid signature")
+	errStaleDelivery    = errors.New("delivery timestamp is outside of the tolerance window")
+)
+
+// WebhookEvent is a verified delivery of a webhook source, parsed by its processor
+type WebhookEvent struct {
+	Source     string
+	DeliveryID string
+	Type       string
+	Action     string
+	Actor      string
+	Subject    string
+	URL        string
+	Payload    json.RawMessage
+	ReceivedAt time.Time
+}
+
+// webhookProcessor turns the deliveries of a source into beers or notifications
+type webhookProcessor interface {
+	// Parse reads a delivery into an event, nil when it's of no interest
+	Parse(r *http.Request, body []byte) (*WebhookEvent, error)
+	Process(ctx context.Context, event *WebhookEvent) error
+}
+
+// webhookSignature describes how a source signs its deliveries: an HMAC-SHA256 of the body,
+// hex encoded after Prefix in Header. Sources sending a TimestampHeader (unix seconds) sign
+// "<timestamp>.<body>" instead, and their deliveries are only accepted within the tolerance.
+// Deliveries already received are recognized by their DeliveryHeader.
+type webhookSignature struct {
+	Header          string
+	Prefix          string
+	TimestampHeader string
+	DeliveryHeader  string
+}
+
+// defaultWebhookSignature is the signature scheme of the sources that let us choose one
+var defaultWebhookSignature = webhookSignature{
+	Header:          "X-Webhook-Signature",
+	Prefix:          "sha256=",
+	TimestampHeader: "X-Webhook-Timestamp",
+	DeliveryHeader:  "X-Webhook-Id",
+}
+
+// verify checks the signature of the delivery with the secret, and its timestamp when signed
+func (s webhookSignature) verify(r *http.Request, body []byte, secret []byte, now time.Time, tolerance time.Duration) error {
+	header := r.Header.Get(s.Header)
+	if !strings.HasPrefix(header, s.Prefix) {
+		return errInvalidSignature
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(header, s.Prefix))
+	if err != nil {
+		return errInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	timestamp := r.Header.Get(s.TimestampHeader)
+	if s.TimestampHeader != "" {
+		mac.Write([]byte(timestamp + "."))
+	}
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return errInvalidSignature
+	}
+
+	if s.TimestampHeader != "" {
+		return checkWebhookTimestamp(timestamp, now, tolerance)
+	}
+	return nil
+}
+
+// retention is how long the deliveries are remembered to refuse their replays. Those with a signed
+// timestamp are refused as stale past twice the tolerance of their reception, the others may be
+// replayed at any time and are remembered for the retention of the unsigned ones.
+func (s webhookSignature) retention(tolerance time.Duration, unsigned time.Duration) time.Duration {
+	if s.TimestampHeader != "" {
+		return 2 * tolerance
+	}
+	return unsigned
+}
+
+// checkWebhookTimestamp checks the timestamp is within the tolerance of now, either way
+func checkWebhookTimestamp(timestamp string, now time.Time, tolerance time.Duration) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errStaleDelivery
+	}
+	drift := now.Sub(time.Unix(seconds, 0))
+	if drift > tolerance || drift < -tolerance {
+		return errStaleDelivery
+	}
+	return nil
+}
+
+// webhookSource is a source registered to post webhooks
+type webhookSource struct {
+	signature webhookSignature
+	secret    []byte
+	processor webhookProcessor
+}
+
+// registerWebhook accepts the deliveries of the source at POST /webhooks/{source}, handled by
+// the processor. Sources without a secret configured in WEBHOOK_SECRET_<SOURCE> stay off.
+func (a *Application) registerWebhook(source string, signature webhookSignature, processor webhookProcessor) {
+	secret := a.conf.Webhooks.Secrets[source]
+	if secret == "" {
+		a.logger.Infof("webhooks from %s are off, WEBHOOK_SECRET_%s is not set", source, strings.ToUpper(source))
+		return
+	}
+
+	if a.webhooks == nil {
+		a.webhooks = map[string]*webhookSource{}
+	}
+	a.webhooks[source] = &webhookSource{signature: signature, secret: []byte(secret), processor: processor}
+}
+
+// ReceiveWebhook verifies a delivery of a registered source and hands it to its processor, once:
+// the delivery is claimed before being processed, and released for its retry when it fails.
+// Failed events are logged in full so they can be replayed.
+func (a *Application) ReceiveWebhook(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["source"]
+	source, ok := a.webhooks[name]
+	if !ok {
+		notFoundHandler(w, r)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		respondRequestError(w, decodeError(err))
+		return
+	}
+
+	now := time.Now()
+	if err := source.signature.verify(r, body, source.secret, now, a.conf.Webhooks.Tolerance); err != nil {
+		logging.FromContext(r.Context()).WithError(err).Warnf("rejected a webhook delivery from %s", name)
+		respondError(w, http.StatusUnauthorized, ErrCodeInvalidSignature, "invalid webhook signature", nil)
+		return
+	}
+
+	deliveryID := r.Header.Get(source.signature.DeliveryHeader)
+	if deliveryID != "" {
+		retention := source.signature.retention(a.conf.Webhooks.Tolerance, a.conf.Webhooks.DeliveryRetention)
+		claimed, err := a.receiptsRepository.Claim(r.Context(), name, deliveryID, retention)
+		if err != nil {
+			respondRepositoryError(w, err)
+			return
+		}
+		if !claimed {
+			respondNoContent(w, http.StatusNoContent)
+			return
+		}
+	}
+
+	event, err := source.processor.Parse(r, body)
+	if err != nil {
+		logging.FromContext(r.Context()).WithError(err).Warnf("could not parse a webhook delivery from %s", name)
+		a.releaseWebhookDelivery(r.Context(), name, deliveryID)
+		respondError(w, http.StatusBadRequest, ErrCodeBadRequest, "invalid webhook payload", nil)
+		return
+	}
+	if event == nil {
+		respondNoContent(w, http.StatusNoContent)
+		return
+	}
+	event.Source, event.DeliveryID, event.Payload, event.ReceivedAt = name, deliveryID, body, now
+
+	if err := source.processor.Process(r.Context(), event); err != nil {
+		logging.FromContext(r.Context()).WithError(err).WithFields(log.Fields{
+			"webhook_source":      event.Source,
+			"webhook_delivery_id": event.DeliveryID,
+			"webhook_event":       event.Type,
+			"webhook_payload":     string(event.Payload),
+		}).Error("could not process a webhook event")
+		a.releaseWebhookDelivery(r.Context(), name, deliveryID)
+		respondInternalError(w)
+		return
+	}
+	respondNoContent(w, http.StatusNoContent)
+}
+
+// releaseWebhookDelivery forgets the delivery that failed, for its retry to be processed
+func (a *Application) releaseWebhookDelivery(ctx context.Context, source string, deliveryID string) {
+	if deliveryID == "" {
+		return
+	}
+	if err := a.receiptsRepository.Release(ctx, source, deliveryID); err != nil {
+		logging.FromContext(ctx).Errorf("error releasing the webhook delivery %s of %s: %v", deliveryID, source, err)
+	}
+}
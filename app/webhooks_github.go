@@ -0,0 +1,110 @@
+package app
+
+import (
+	"appdoki-be/app/notify"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// githubWebhookSignature is the signature scheme of GitHub, which doesn't sign a timestamp:
+// replays are only recognized by their delivery ID
+var githubWebhookSignature = webhookSignature{
+	Header:         "X-Hub-Signature-256",
+	Prefix:         "sha256=",
+	DeliveryHeader: "X-GitHub-Delivery",
+}
+
+// GitHub events turned into notifications
+const (
+	githubStarEvent        = "star"
+	githubPullRequestEvent = "pull_request"
+)
+
+type githubPayload struct {
+	Action     string `json:"action"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+	PullRequest *struct {
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+		Merged  bool   `json:"merged"`
+		User    struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+}
+
+// githubProcessor notifies everyone of the new stars of our repositories and of the merged pull requests
+type githubProcessor struct {
+	notifier notify.Notifier
+}
+
+func newGitHubProcessor(notifierSrv notify.Notifier) *githubProcessor {
+	return &githubProcessor{notifier: notifierSrv}
+}
+
+func (p *githubProcessor) Parse(r *http.Request, body []byte) (*WebhookEvent, error) {
+	eventType := r.Header.Get("X-GitHub-Event")
+	if eventType != githubStarEvent && eventType != githubPullRequestEvent {
+		return nil, nil
+	}
+
+	var payload githubPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	event := &WebhookEvent{
+		Type:    eventType,
+		Action:  payload.Action,
+		Actor:   payload.Sender.Login,
+		Subject: payload.Repository.FullName,
+		URL:     payload.Repository.HTMLURL,
+	}
+	switch eventType {
+	case githubStarEvent:
+		if payload.Action != "created" {
+			return nil, nil
+		}
+	case githubPullRequestEvent:
+		if payload.PullRequest == nil {
+			return nil, fmt.Errorf("pull_request event without a pull request")
+		}
+		if payload.Action != "closed" || !payload.PullRequest.Merged {
+			return nil, nil
+		}
+		event.Action = "merged"
+		event.Actor = payload.PullRequest.User.Login
+		event.Subject = payload.PullRequest.Title
+		event.URL = payload.PullRequest.HTMLURL
+	}
+	return event, nil
+}
+
+func (p *githubProcessor) Process(ctx context.Context, event *WebhookEvent) error {
+	notification := notify.Notification{Payload: event}
+	switch event.Type {
+	case githubStarEvent:
+		notification.Event = eventGitHubStar
+	case githubPullRequestEvent:
+		notification.Event = eventGitHubPullRequestMerged
+	default:
+		return fmt.Errorf("unsupported GitHub event %q", event.Type)
+	}
+
+	notification.Data = map[string]string{
+		"source": event.Source,
+		"type":   event.Type,
+		"actor":  event.Actor,
+		"url":    event.URL,
+	}
+	p.notifier.SendToTopic(ctx, integrationsTopic, notification)
+	return nil
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	repos "appdoki-be/app/repositories"
+	"context"
+	"sync"
+	"time"
+)
+
+// mockWebhooksRepository keeps the webhook endpoints and their deliveries in memory
+type mockWebhooksRepository struct {
+	mu         sync.Mutex
+	endpoints  []*repos.WebhookEndpoint
+	deliveries []*repos.WebhookDelivery
+	nextID     int64
+}
+
+func newMockWebhooksRepository() *mockWebhooksRepository {
+	return &mockWebhooksRepository{}
+}
+
+func (r *mockWebhooksRepository) Create(_ context.Context, endpoint *repos.WebhookEndpoint) (*repos.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	created := *endpoint
+	created.ID = r.nextID
+	created.Enabled = true
+	created.CreatedAt = time.Now()
+	r.endpoints = append(r.endpoints, &created)
+	saved := created
+	return &saved, nil
+}
+
+func (r *mockWebhooksRepository) List(_ context.Context) ([]*repos.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoints := []*repos.WebhookEndpoint{}
+	for _, endpoint := range r.endpoints {
+		saved := *endpoint
+		endpoints = append(endpoints, &saved)
+	}
+	return endpoints, nil
+}
+
+func (r *mockWebhooksRepository) ListSubscribed(_ context.Context, event string) ([]*repos.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoints := []*repos.WebhookEndpoint{}
+	for _, endpoint := range r.endpoints {
+		if endpoint.Enabled && containsString(endpoint.Events, event) {
+			saved := *endpoint
+			endpoints = append(endpoints, &saved)
+		}
+	}
+	return endpoints, nil
+}
+
+func (r *mockWebhooksRepository) Find(_ context.Context, ID int64) (*repos.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, endpoint := range r.endpoints {
+		if endpoint.ID == ID {
+			saved := *endpoint
+			return &saved, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *mockWebhooksRepository) Delete(_ context.Context, ID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, endpoint := range r.endpoints {
+		if endpoint.ID == ID {
+			r.endpoints = append(r.endpoints[:i], r.endpoints[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *mockWebhooksRepository) RecordOutcome(_ context.Context, ID int64, delivered bool, maxFailures int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, endpoint := range r.endpoints {
+		if endpoint.ID != ID {
+			continue
+		}
+		if delivered {
+			endpoint.ConsecutiveFailures = 0
+			endpoint.Enabled = true
+			endpoint.DisabledAt = nil
+			return false, nil
+		}
+		endpoint.ConsecutiveFailures++
+		if endpoint.Enabled && maxFailures > 0 && endpoint.ConsecutiveFailures >= maxFailures {
+			now := time.Now()
+			endpoint.Enabled = false
+			endpoint.DisabledAt = &now
+			return true, nil
+		}
+		return false, nil
+	}
+	return false, nil
+}
+
+func (r *mockWebhooksRepository) CreateDelivery(_ context.Context, delivery *repos.WebhookDelivery) (*repos.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	created := *delivery
+	created.ID = r.nextID
+	created.CreatedAt = time.Now()
+	r.deliveries = append(r.deliveries, &created)
+	saved := created
+	return &saved, nil
+}
+
+func (r *mockWebhooksRepository) ListDeliveries(_ context.Context, endpointID int64, limit int) ([]*repos.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries := []*repos.WebhookDelivery{}
+	for i := len(r.deliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if r.deliveries[i].EndpointID == endpointID {
+			saved := *r.deliveries[i]
+			deliveries = append(deliveries, &saved)
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *mockWebhooksRepository) FindDelivery(_ context.Context, endpointID int64, ID int64) (*repos.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.EndpointID == endpointID && delivery.ID == ID {
+			saved := *delivery
+			return &saved, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,14 @@
+package app
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func (a *Application) WebhooksRouter(router *mux.Router) {
+	// public, the deliveries are authenticated by their signature
+	a.mount(router,
+		routeDef{methods: []string{http.MethodPost}, path: "/webhooks/{source}", access: publicAccess,
+			handler: http.HandlerFunc(a.ReceiveWebhook)},
+	)
+}
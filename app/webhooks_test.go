@@ -0,0 +1,302 @@
+package app
+
+import (
+	"appdoki-be/app/notify"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingProcessor parses every delivery but fails to process them
+type failingProcessor struct{}
+
+func (failingProcessor) Parse(_ *http.Request, _ []byte) (*WebhookEvent, error) {
+	return &WebhookEvent{Type: "test"}, nil
+}
+
+func (failingProcessor) Process(_ context.Context, _ *WebhookEvent) error {
+	return errors.New("downstream is down")
+}
+
+// countingProcessor parses every delivery and counts those processed, slowly for the concurrent
+// deliveries to overlap
+type countingProcessor struct {
+	processed int32
+}
+
+func (p *countingProcessor) Parse(_ *http.Request, _ []byte) (*WebhookEvent, error) {
+	return &WebhookEvent{Type: "test"}, nil
+}
+
+func (p *countingProcessor) Process(_ context.Context, _ *WebhookEvent) error {
+	atomic.AddInt32(&p.processed, 1)
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+func signWebhook(secret string, message string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookSignature_verify(t *testing.T) {
+	const githubSecret = "It's a Secret to Everybody"
+	const githubVector = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+	now := time.Unix(1654077600, 0)
+
+	github := func(signature string) *http.Request {
+		r := httptest.NewRequest("POST", "/webhooks/github", nil)
+		r.Header.Set("X-Hub-Signature-256", signature)
+		return r
+	}
+
+	t.Run("expect the GitHub known vector to be valid", func(t *testing.T) {
+		err := githubWebhookSignature.verify(github(githubVector), []byte("Hello, World!"), []byte(githubSecret), now, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("expect invalid GitHub signatures to be rejected", func(t *testing.T) {
+		cases := map[string]struct {
+			signature string
+			body      string
+			secret    string
+		}{
+			"tampered body":   {githubVector, "Hello, World?", githubSecret},
+			"other secret":    {githubVector, "Hello, World!", "It's a Secret to Nobody"},
+			"missing prefix":  {strings.TrimPrefix(githubVector, "sha256="), "Hello, World!", githubSecret},
+			"not hex":         {"sha256=not-hex", "Hello, World!", githubSecret},
+			"missing":         {"", "Hello, World!", githubSecret},
+			"sha1 prefix":     {"sha1=01dc10d0c83e72ed246219cdd91669667fe2ca59", "Hello, World!", githubSecret},
+			"truncated value": {githubVector[:40], "Hello, World!", githubSecret},
+		}
+		for name, c := range cases {
+			err := githubWebhookSignature.verify(github(c.signature), []byte(c.body), []byte(c.secret), now, time.Minute)
+			if err != errInvalidSignature {
+				t.Errorf("%s: expected an invalid signature, got %v", name, err)
+			}
+		}
+	})
+
+	const secret = "appdoki-secret"
+	const body = `{"event":"ping"}`
+	const vector = "sha256=e9618318a4f48ac7ead0c8b7d701b6e10a4bb07f79db92a243fa99e61d3825bf"
+	timestamped := func(timestamp string, signature string) *http.Request {
+		r := httptest.NewRequest("POST", "/webhooks/tool", nil)
+		r.Header.Set("X-Webhook-Timestamp", timestamp)
+		r.Header.Set("X-Webhook-Signature", signature)
+		return r
+	}
+
+	t.Run("expect the timestamped known vector to be valid within the tolerance", func(t *testing.T) {
+		for _, at := range []time.Time{now, now.Add(4 * time.Minute), now.Add(-4 * time.Minute)} {
+			err := defaultWebhookSignature.verify(timestamped("1654077600", vector), []byte(body), []byte(secret), at, 5*time.Minute)
+			if err != nil {
+				t.Fatalf("at %s: %v", at, err)
+			}
+		}
+	})
+
+	t.Run("expect deliveries outside of the tolerance to be rejected as replays", func(t *testing.T) {
+		for _, at := range []time.Time{now.Add(6 * time.Minute), now.Add(-6 * time.Minute)} {
+			err := defaultWebhookSignature.verify(timestamped("1654077600", vector), []byte(body), []byte(secret), at, 5*time.Minute)
+			if err != errStaleDelivery {
+				t.Fatalf("at %s: expected a stale delivery, got %v", at, err)
+			}
+		}
+	})
+
+	t.Run("expect a changed timestamp to invalidate the signature", func(t *testing.T) {
+		err := defaultWebhookSignature.verify(timestamped("1654077660", vector), []byte(body), []byte(secret), now, 5*time.Minute)
+		if err != errInvalidSignature {
+			t.Fatalf("expected an invalid signature, got %v", err)
+		}
+	})
+}
+
+func TestApplication_ReceiveWebhook(t *testing.T) {
+	const secret = "github-secret"
+	newWebhooksApplication := func() (*Application, *notify.Fake) {
+		a := newTestApplication()
+		a.conf.Webhooks.Secrets = map[string]string{"github": secret, "flaky": "flaky-secret"}
+		a.conf.Webhooks.Tolerance = 5 * time.Minute
+		a.conf.Webhooks.DeliveryRetention = 72 * time.Hour
+		n := notify.NewFake()
+		a.registerWebhook("github", githubWebhookSignature, newGitHubProcessor(n))
+		a.registerWebhook("flaky", defaultWebhookSignature, failingProcessor{})
+		a.registerWebhook("unconfigured", defaultWebhookSignature, failingProcessor{})
+		return a, n
+	}
+	deliver := func(routes http.Handler, event string, delivery string, body string, signature string) *http.Response {
+		r := httptest.NewRequest("POST", "/webhooks/github", strings.NewReader(body))
+		r.Header.Set("X-GitHub-Event", event)
+		r.Header.Set("X-GitHub-Delivery", delivery)
+		r.Header.Set("X-Hub-Signature-256", signature)
+		w := httptest.NewRecorder()
+		routes.ServeHTTP(w, r)
+		return w.Result()
+	}
+	star := `{"action":"created","repository":{"full_name":"Cloudoki/appdoki-be","html_url":"https://github.com/Cloudoki/appdoki-be"},"sender":{"login":"octocat"}}`
+	merged := `{"action":"closed","pull_request":{"title":"Add webhooks","html_url":"https://github.com/Cloudoki/appdoki-be/pull/1","merged":true,"user":{"login":"hubot"}},"repository":{"full_name":"Cloudoki/appdoki-be"},"sender":{"login":"octocat"}}`
+
+	t.Run("expect a new star to be notified once", func(t *testing.T) {
+		a, n := newWebhooksApplication()
+		routes := a.Routes()
+
+		assertStatusCode(t, deliver(routes, "star", "d-1", star, signWebhook(secret, star)), http.StatusNoContent)
+		assertStatusCode(t, deliver(routes, "star", "d-1", star, signWebhook(secret, star)), http.StatusNoContent)
+
+		sent := n.Sent()
+		if len(sent) != 1 || sent[0].Notification.Event != eventGitHubStar {
+			t.Fatalf("expected a single star notification, got %+v", sent)
+		}
+		if _, body := renderNotification(t, sent[0].Notification); body != "octocat starred Cloudoki/appdoki-be" {
+			t.Errorf("unexpected star notification %q", body)
+		}
+	})
+
+	t.Run("expect a merged pull request to be notified", func(t *testing.T) {
+		a, n := newWebhooksApplication()
+
+		resp := deliver(a.Routes(), "pull_request", "d-2", merged, signWebhook(secret, merged))
+
+		assertStatusCode(t, resp, http.StatusNoContent)
+		sent := n.Sent()
+		if len(sent) != 1 || sent[0].Topic != integrationsTopic || sent[0].Notification.Event != eventGitHubPullRequestMerged {
+			t.Fatalf("expected a merge notification, got %+v", sent)
+		}
+		if _, body := renderNotification(t, sent[0].Notification); body != `hubot got "Add webhooks" merged` {
+			t.Errorf("unexpected merge notification %q", body)
+		}
+	})
+
+	t.Run("expect the events of no interest to be acknowledged without notification", func(t *testing.T) {
+		a, n := newWebhooksApplication()
+		routes := a.Routes()
+		unmerged := strings.Replace(merged, `"merged":true`, `"merged":false`, 1)
+		unstar := strings.Replace(star, `"created"`, `"deleted"`, 1)
+
+		assertStatusCode(t, deliver(routes, "ping", "d-3", `{"zen":"Keep it logically awesome."}`,
+			signWebhook(secret, `{"zen":"Keep it logically awesome."}`)), http.StatusNoContent)
+		assertStatusCode(t, deliver(routes, "pull_request", "d-4", unmerged, signWebhook(secret, unmerged)), http.StatusNoContent)
+		assertStatusCode(t, deliver(routes, "star", "d-5", unstar, signWebhook(secret, unstar)), http.StatusNoContent)
+		if sent := n.Sent(); len(sent) != 0 {
+			t.Fatalf("expected no notification, got %+v", sent)
+		}
+	})
+
+	t.Run("expect an invalid signature to return 401", func(t *testing.T) {
+		a, n := newWebhooksApplication()
+
+		resp := deliver(a.Routes(), "star", "d-6", star, signWebhook("other-secret", star))
+
+		assertStatusCode(t, resp, http.StatusUnauthorized)
+		assertErrorCode(t, resp, ErrCodeInvalidSignature)
+		if len(n.Sent()) != 0 {
+			t.Fatal("expected no notification")
+		}
+	})
+
+	t.Run("expect a malformed payload to return 400", func(t *testing.T) {
+		a, _ := newWebhooksApplication()
+
+		resp := deliver(a.Routes(), "star", "d-7", `{"action":`, signWebhook(secret, `{"action":`))
+
+		assertStatusCode(t, resp, http.StatusBadRequest)
+		assertErrorCode(t, resp, ErrCodeBadRequest)
+	})
+
+	t.Run("expect unknown or unconfigured sources to return 404", func(t *testing.T) {
+		a, _ := newWebhooksApplication()
+
+		for _, source := range []string{"gitlab", "unconfigured"} {
+			r := httptest.NewRequest("POST", "/webhooks/"+source, strings.NewReader("{}"))
+			w := httptest.NewRecorder()
+			a.Routes().ServeHTTP(w, r)
+
+			assertStatusCode(t, w.Result(), http.StatusNotFound)
+		}
+	})
+
+	t.Run("expect processing errors to return 500 and the delivery to be processed again when retried", func(t *testing.T) {
+		a, _ := newWebhooksApplication()
+		routes := a.Routes()
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+		for i := 0; i < 2; i++ {
+			r := httptest.NewRequest("POST", "/webhooks/flaky", strings.NewReader("{}"))
+			r.Header.Set("X-Webhook-Id", "d-8")
+			r.Header.Set("X-Webhook-Timestamp", timestamp)
+			r.Header.Set("X-Webhook-Signature", signWebhook("flaky-secret", timestamp+".{}"))
+			w := httptest.NewRecorder()
+			routes.ServeHTTP(w, r)
+
+			assertStatusCode(t, w.Result(), http.StatusInternalServerError)
+		}
+	})
+
+	t.Run("expect concurrent deliveries of an id to be processed once", func(t *testing.T) {
+		a, _ := newWebhooksApplication()
+		a.conf.Webhooks.Secrets["counted"] = "counted-secret"
+		processor := &countingProcessor{}
+		a.registerWebhook("counted", defaultWebhookSignature, processor)
+		routes := a.Routes()
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r := httptest.NewRequest("POST", "/webhooks/counted", strings.NewReader("{}"))
+				r.Header.Set("X-Webhook-Id", "d-9")
+				r.Header.Set("X-Webhook-Timestamp", timestamp)
+				r.Header.Set("X-Webhook-Signature", signWebhook("counted-secret", timestamp+".{}"))
+				w := httptest.NewRecorder()
+				routes.ServeHTTP(w, r)
+				if w.Code != http.StatusNoContent {
+					t.Errorf("expected 204, got %d", w.Code)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if processed := atomic.LoadInt32(&processor.processed); processed != 1 {
+			t.Fatalf("expected the delivery to be processed once, got %d", processed)
+		}
+		if ttl := a.receiptsRepository.(*mockWebhookReceiptsRepository).ttl("counted", "d-9"); ttl != 10*time.Minute {
+			t.Fatalf("expected a timestamped delivery to be remembered twice the tolerance, got %s", ttl)
+		}
+	})
+
+	t.Run("expect the GitHub deliveries to be remembered for the retention, across restarts", func(t *testing.T) {
+		a, n := newWebhooksApplication()
+		receipts := a.receiptsRepository.(*mockWebhookReceiptsRepository)
+
+		assertStatusCode(t, deliver(a.Routes(), "star", "d-10", star, signWebhook(secret, star)), http.StatusNoContent)
+		if ttl := receipts.ttl("github", "d-10"); ttl != 72*time.Hour {
+			t.Fatalf("expected the delivery to be remembered for WEBHOOK_DELIVERY_RETENTION, got %s", ttl)
+		}
+
+		restarted, _ := newWebhooksApplication()
+		restarted.receiptsRepository = receipts
+		restarted.registerWebhook("github", githubWebhookSignature, newGitHubProcessor(n))
+		assertStatusCode(t, deliver(restarted.Routes(), "star", "d-10", star, signWebhook(secret, star)), http.StatusNoContent)
+
+		if sent := n.Sent(); len(sent) != 1 {
+			t.Fatalf("expected the replay to be refused after the restart, got %+v", sent)
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"net/http"
+)
+
+// VAPIDKey is the public VAPID key the browsers subscribe to Web Push with
+type VAPIDKey struct {
+	PublicKey string `json:"public_key"`
+}
+
+// GetVAPIDKey returns the public VAPID key for the web app to subscribe the browser with, 404 when
+// Web Push isn't configured
+func (a *Application) GetVAPIDKey(w http.ResponseWriter, r *http.Request) {
+	if a.conf.WebPush.VAPIDPrivateKey == "" {
+		respondError(w, http.StatusNotFound, ErrCodeNotFound, "web push isn't configured", nil)
+		return
+	}
+
+	respondJSON(w, r, VAPIDKey{PublicKey: a.conf.WebPush.VAPIDPublicKey}, http.StatusOK)
+}
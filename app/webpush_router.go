@@ -0,0 +1,13 @@
+package app
+
+import (
+	"github.com/gorilla/mux"
+	"net/http"
+)
+
+func (a *Application) WebPushRouter(router *mux.Router) {
+	a.mount(router,
+		routeDef{methods: []string{http.MethodGet}, path: "/notifications/vapid-key", access: publicAccess,
+			handler: a.RateLimit(readRateLimit, a.CacheControl(privateCache, a.GetVAPIDKey))},
+	)
+}
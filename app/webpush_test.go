@@ -0,0 +1,36 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApplication_GetVAPIDKey(t *testing.T) {
+	t.Run("expect the public VAPID key to be returned", func(t *testing.T) {
+		a := newTestApplication()
+		a.conf.WebPush.VAPIDPublicKey, a.conf.WebPush.VAPIDPrivateKey = "public", "private"
+
+		w := httptest.NewRecorder()
+		a.Routes().ServeHTTP(w, httptest.NewRequest("GET", "/notifications/vapid-key", nil))
+
+		resp := w.Result()
+		assertStatusCode(t, resp, http.StatusOK)
+		var key VAPIDKey
+		if err := json.NewDecoder(resp.Body).Decode(&key); err != nil {
+			t.Fatal(err)
+		}
+		if key.PublicKey != "public" {
+			t.Fatalf("expected the public key, got %q", key.PublicKey)
+		}
+	})
+
+	t.Run("expect 404 when web push isn't configured", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newTestApplication().Routes().ServeHTTP(w, httptest.NewRequest("GET", "/notifications/vapid-key", nil))
+
+		assertStatusCode(t, w.Result(), http.StatusNotFound)
+		assertErrorCode(t, w.Result(), ErrCodeNotFound)
+	})
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"appdoki-be/app/logging"
+	"appdoki-be/app/reporting"
+	"context"
+	log "github.com/sirupsen/logrus"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// workersCancelGrace is how long the workers cancelled on shutdown are given to wrap up, ex.: to
+// keep the notification retries they interrupted as dead letters
+const workersCancelGrace = 5 * time.Second
+
+// workerGroup tracks the background goroutines spawned by the application,
+// so they can be drained and cancelled on shutdown
+type workerGroup struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	reporter reporting.ErrorReporter
+
+	// mu orders the goroutines added with Stop waiting for them, none being added once stopped
+	mu      sync.Mutex
+	stopped bool
+}
+
+func newWorkerGroup(reporter reporting.ErrorReporter) *workerGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workerGroup{
+		ctx:      ctx,
+		cancel:   cancel,
+		reporter: reporter,
+	}
+}
+
+// Go runs f in a new goroutine. The context passed to f carries the request scoped
+// values of ctx, the authenticated user included, and is cancelled when the group is stopped.
+// Panics of f are reported instead of crashing the server. Once the group is stopping, f is
+// refused and never runs.
+func (g *workerGroup) Go(ctx context.Context, f func(ctx context.Context)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stopped {
+		logging.FromContext(ctx).Warn("the background workers are stopping, refusing a new one")
+		return
+	}
+
+	detached := logging.Detach(ctx)
+	if userID := contextUserID(ctx); userID != "" {
+		detached = context.WithValue(detached, "userID", userID)
+	}
+	workerCtx, cancel := context.WithCancel(detached)
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer cancel()
+		defer g.recover(workerCtx)
+
+		go func() {
+			select {
+			case <-g.ctx.Done():
+				cancel()
+			case <-workerCtx.Done():
+			}
+		}()
+
+		f(workerCtx)
+	}()
+}
+
+// recover reports the panic of a worker, it must be deferred by the worker goroutine
+func (g *workerGroup) recover(ctx context.Context) {
+	err := recover()
+	if err == nil {
+		return
+	}
+
+	logging.FromContext(ctx).WithFields(log.Fields{
+		"panic": err,
+		"stack": string(debug.Stack()),
+	}).Error("recovered from panic in a background worker")
+	captureError(g.reporter, ctx, panicError(err), map[string]string{
+		"worker": "true",
+		"panic":  "true",
+	})
+}
+
+// Stop waits for the running goroutines to finish, refusing the new ones from then on. If ctx
+// expires first, they are cancelled, given workersCancelGrace to return, and ctx's error is returned.
+func (g *workerGroup) Stop(ctx context.Context) error {
+	g.mu.Lock()
+	g.stopped = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		g.cancel()
+		return nil
+	case <-ctx.Done():
+		g.cancel()
+		select {
+		case <-done:
+		case <-time.After(workersCancelGrace):
+		}
+		return ctx.Err()
+	}
+}
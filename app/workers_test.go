@@ -0,0 +1,55 @@
+package app
+
+import (
+	"appdoki-be/app/reporting"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkerGroup_Stop(t *testing.T) {
+	t.Run("expect the workers given after Stop to be refused", func(t *testing.T) {
+		g := newWorkerGroup(reporting.Noop{})
+		if err := g.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		ran := make(chan struct{})
+		g.Go(context.Background(), func(context.Context) { close(ran) })
+
+		select {
+		case <-ran:
+			t.Fatal("expected the worker to be refused")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("expect the workers given while stopping to be either waited for or refused", func(t *testing.T) {
+		g := newWorkerGroup(reporting.Noop{})
+		var started, finished int32
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				g.Go(context.Background(), func(context.Context) {
+					atomic.AddInt32(&started, 1)
+					time.Sleep(time.Millisecond)
+					atomic.AddInt32(&finished, 1)
+				})
+			}()
+		}
+		if err := g.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		stoppedWith := atomic.LoadInt32(&finished)
+		wg.Wait()
+
+		if started, finished := atomic.LoadInt32(&started), atomic.LoadInt32(&finished); started != stoppedWith || finished != stoppedWith {
+			t.Fatalf("expected the %d workers accepted to be done when Stop returned, got %d started and %d finished", stoppedWith, started, finished)
+		}
+	})
+}
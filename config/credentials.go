@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/oauth2/google"
+	"io/ioutil"
+	"strings"
+)
+
+// The modes the FCM credentials are found with, in the order of precedence
+const (
+	FCMCredentialsJSON = "json"
+	FCMCredentialsFile = "file"
+	FCMCredentialsADC  = "adc"
+)
+
+// fcmScopes are the scopes of the FCM credentials
+var fcmScopes = []string{
+	"https://www.googleapis.com/auth/cloud-platform",
+	"https://www.googleapis.com/auth/firebase.messaging",
+}
+
+// FCMCredentialsMode returns how the FCM credentials are found: from the service account key of
+// GOOGLE_SERVICE_ACCOUNT_JSON, else from the file of GOOGLE_SERVICE_ACCOUNT_KEY, else from the
+// Application Default Credentials, ex.: of the service account of the instance when running on GCP
+func (a *AppConfig) FCMCredentialsMode() string {
+	switch {
+	case a.GoogleServiceAccountJSON != "":
+		return FCMCredentialsJSON
+	case a.GoogleServiceAccountKeyPath != "":
+		return FCMCredentialsFile
+	}
+	return FCMCredentialsADC
+}
+
+// FCMCredentials returns the credentials FCM is called with, found in the FCMCredentialsMode. The
+// Application Default Credentials may query the metadata server of GCP.
+func (a *AppConfig) FCMCredentials(ctx context.Context) (*google.Credentials, error) {
+	switch a.FCMCredentialsMode() {
+	case FCMCredentialsJSON:
+		key, err := decodeServiceAccountJSON(a.GoogleServiceAccountJSON)
+		if err != nil {
+			return nil, fmt.Errorf("GOOGLE_SERVICE_ACCOUNT_JSON: %w", err)
+		}
+		creds, err := google.CredentialsFromJSON(ctx, key, fcmScopes...)
+		if err != nil {
+			return nil, fmt.Errorf("GOOGLE_SERVICE_ACCOUNT_JSON: %w", err)
+		}
+		return creds, nil
+	case FCMCredentialsFile:
+		key, err := ioutil.ReadFile(a.GoogleServiceAccountKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("GOOGLE_SERVICE_ACCOUNT_KEY: %w", err)
+		}
+		creds, err := google.CredentialsFromJSON(ctx, key, fcmScopes...)
+		if err != nil {
+			return nil, fmt.Errorf("GOOGLE_SERVICE_ACCOUNT_KEY: %s: %w", a.GoogleServiceAccountKeyPath, err)
+		}
+		return creds, nil
+	}
+	return google.FindDefaultCredentials(ctx, fcmScopes...)
+}
+
+// decodeServiceAccountJSON returns the service account key, given as JSON or base64 encoded JSON
+func decodeServiceAccountJSON(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "{") {
+		return []byte(value), nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("neither JSON nor base64 encoded JSON")
+	}
+	return key, nil
+}
+
+// validateFCMCredentials checks the service account key of the json and file modes, the
+// Application Default Credentials being only found once starting, on GCP
+func (c *Config) validateFCMCredentials(p *problems) {
+	if !c.UsesNotifierChannel("fcm") {
+		return
+	}
+
+	mode := c.AppConfig.FCMCredentialsMode()
+	if mode == FCMCredentialsADC {
+		return
+	}
+	if _, err := c.AppConfig.FCMCredentials(context.Background()); err != nil {
+		p.add("FCM credentials (%s mode): %v", mode, err)
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAppConfig_FCMCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expect the JSON of the variable, plain or base64, over the file", func(t *testing.T) {
+		path := writeConfigFile(t, "key.json", strings.Replace(serviceAccountJSON, `"appdoki"`, `"appdoki-file"`, 1))
+		for _, value := range []string{serviceAccountJSON, base64.StdEncoding.EncodeToString([]byte(serviceAccountJSON))} {
+			conf := AppConfig{GoogleServiceAccountJSON: value, GoogleServiceAccountKeyPath: path}
+
+			creds, err := conf.FCMCredentials(ctx)
+
+			if conf.FCMCredentialsMode() != FCMCredentialsJSON || err != nil || creds.ProjectID != "appdoki" {
+				t.Fatalf("expected the credentials of the variable, got %v, %v", creds, err)
+			}
+		}
+	})
+
+	t.Run("expect the file without the JSON, then the Application Default Credentials", func(t *testing.T) {
+		conf := AppConfig{GoogleServiceAccountKeyPath: writeConfigFile(t, "key.json", serviceAccountJSON)}
+
+		creds, err := conf.FCMCredentials(ctx)
+
+		if conf.FCMCredentialsMode() != FCMCredentialsFile || err != nil || creds.ProjectID != "appdoki" {
+			t.Fatalf("expected the credentials of the file, got %v, %v", creds, err)
+		}
+		if mode := (&AppConfig{}).FCMCredentialsMode(); mode != FCMCredentialsADC {
+			t.Fatalf("expected the Application Default Credentials, got %s", mode)
+		}
+	})
+
+	t.Run("expect the invalid credentials to fail the validation, with their mode", func(t *testing.T) {
+		for _, tc := range []struct {
+			mode    string
+			json    string
+			keyPath string
+		}{
+			{"json", "not a key", ""},
+			{"json", `{"type": "unknown"}`, ""},
+			{"file", "", filepath.Join(t.TempDir(), "missing.json")},
+		} {
+			conf := validConfig()
+			conf.AppConfig.GoogleServiceAccountJSON = tc.json
+			conf.AppConfig.GoogleServiceAccountKeyPath = tc.keyPath
+
+			problems := problemsOf(t, conf.Validate())
+
+			if len(problems) != 1 || !strings.HasPrefix(problems[0], "FCM credentials ("+tc.mode+" mode)") {
+				t.Fatalf("expected the %s credentials to be reported, got %v", tc.mode, problems)
+			}
+		}
+	})
+
+	t.Run("expect the variable to be read, the noop notifier needing no credentials", func(t *testing.T) {
+		setEnv(t, "GOOGLE_SERVICE_ACCOUNT_JSON", "not a key")
+		setEnv(t, "NOTIFIER", "noop")
+
+		conf, _, _ := load("")
+
+		if conf.AppConfig.FCMCredentialsMode() != FCMCredentialsJSON {
+			t.Fatalf("expected the JSON of the variable, got %s", conf.AppConfig.FCMCredentialsMode())
+		}
+		for _, problem := range problemsOf(t, conf.Validate()) {
+			if strings.HasPrefix(problem, "FCM credentials") {
+				t.Fatalf("expected no FCM credentials problem in the noop mode, got %s", problem)
+			}
+		}
+	})
+}
@@ -0,0 +1,385 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// requiredUnless prefixes the variable of the boolean setting which makes a required one optional
+// when set, ex.: required:"unless:TEST_MODE"
+const requiredUnless = "unless:"
+
+// durationType is the type of the settings given as durations, ex.: 30s
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a setting of the configuration, as the tags of its struct field declare it:
+//
+//	env       the variable overriding the setting, ex.: env:"DB_URI". The maps take every variable
+//	          starting with the part before the placeholder, by the rest of its name in lower case,
+//	          ex.: env:"WEBHOOK_SECRET_<SOURCE>", the maps of flags only their declared keys.
+//	default   the value when neither the profile, the file nor the variable set it, in the format of
+//	          the variable: 15s for the durations, a,b,c for the lists
+//	required  true when the setting can't be left empty, or unless:<VARIABLE> when it may be along
+//	          with the boolean setting of that variable
+//	secret    true when the setting is redacted by Settings, its variable then being read from the
+//	          file of <NAME>_FILE instead when set
+//	desc      what the setting is for, listed by HelpConfig
+type field struct {
+	// key is the path of its YAML keys, ex.: server.address
+	key      string
+	env      string
+	def      string
+	required string
+	secret   bool
+	desc     string
+	value    reflect.Value
+}
+
+// prefix returns the prefix of the variables of a map, ex.: WEBHOOK_SECRET_, empty for the other settings
+func (f field) prefix() string {
+	if i := strings.Index(f.env, "<"); i >= 0 {
+		return f.env[:i]
+	}
+	return ""
+}
+
+// walkFields calls fn with every field of the configuration v points at, the nested structs being
+// walked rather than passed, and the fields tagged yaml:"-" and the unexported ones left out
+func walkFields(v reflect.Value, prefix string, fn func(f field)) {
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name := sf.Tag.Get("yaml")
+		if sf.PkgPath != "" || name == "" || name == "-" {
+			continue
+		}
+		if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+			walkFields(v.Field(i), joinKey(prefix, name), fn)
+			continue
+		}
+		fn(field{
+			key:      joinKey(prefix, name),
+			env:      sf.Tag.Get("env"),
+			def:      sf.Tag.Get("default"),
+			required: sf.Tag.Get("required"),
+			secret:   sf.Tag.Get("secret") == "true",
+			desc:     sf.Tag.Get("desc"),
+			value:    v.Field(i),
+		})
+	}
+}
+
+// defaultConfig returns the configuration used when neither the profile, the file nor the
+// environment set it, from the default tags and the DefaultFeatureFlags
+func defaultConfig() *Config {
+	c := &Config{}
+	walkFields(reflect.ValueOf(c).Elem(), "", func(f field) {
+		if f.def == "" {
+			return
+		}
+		if err := setValue(f.value, f.def); err != nil {
+			panic(fmt.Sprintf("config: default of %s: %v", f.key, err))
+		}
+	})
+
+	c.Features.Flags = make(map[string]bool, len(DefaultFeatureFlags))
+	for name, enabled := range DefaultFeatureFlags {
+		c.Features.Flags[name] = enabled
+	}
+	return c
+}
+
+// overrideFromEnv sets the settings from their environment variables, when set, returning the
+// variables set to values which couldn't be parsed and the secret files which couldn't be read, the
+// settings keeping their value
+func (c *Config) overrideFromEnv() []string {
+	var invalid []string
+	walkFields(reflect.ValueOf(c).Elem(), "", func(f field) {
+		switch {
+		case f.env == "":
+		case f.prefix() != "":
+			invalid = append(invalid, overrideMapFromEnv(f)...)
+		default:
+			value, ok, err := lookupEnv(f.env, f.secret)
+			if err != nil {
+				invalid = append(invalid, err.Error())
+			}
+			if !ok || (value == "" && f.value.Kind() != reflect.String) {
+				return
+			}
+			if err := setValue(f.value, value); err != nil {
+				invalid = append(invalid, fmt.Sprintf("%s must be %s, got %q", f.env, expectedValue(f.value.Type()), value))
+			}
+		}
+	})
+	return invalid
+}
+
+// lookupEnv returns the value of the variable, of the file its <NAME>_FILE variable points at for a
+// secret, the way the orchestrators mount them. The file wins when both are set, its content trimmed.
+func lookupEnv(name string, secret bool) (string, bool, error) {
+	if path := os.Getenv(name + "_FILE"); secret && path != "" {
+		return readSecretFile(name+"_FILE", path)
+	}
+	value, ok := os.LookupEnv(name)
+	return value, ok, nil
+}
+
+func readSecretFile(name string, path string) (string, bool, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("%s: can't read %s: %v", name, path, err)
+	}
+	return strings.TrimSpace(string(content)), true, nil
+}
+
+// overrideMapFromEnv sets the entries of the map from the non empty variables starting with its
+// prefix, by the rest of their name in lower case (ex.: WEBHOOK_SECRET_GITHUB is read as github).
+// The secrets ending with _FILE are read from their file, which wins, and the flags only take their
+// declared keys.
+func overrideMapFromEnv(f field) []string {
+	var invalid []string
+	prefix := f.prefix()
+	set := func(env string, key string, value string) {
+		entry := reflect.New(f.value.Type().Elem()).Elem()
+		if err := setValue(entry, value); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s must be %s, got %q", env, expectedValue(entry.Type()), value))
+			return
+		}
+		if f.value.IsNil() {
+			f.value.Set(reflect.MakeMap(f.value.Type()))
+		}
+		f.value.SetMapIndex(reflect.ValueOf(key), entry)
+	}
+
+	if f.value.Type().Elem().Kind() == reflect.Bool {
+		for _, key := range f.value.MapKeys() {
+			env := prefix + strings.ToUpper(key.String())
+			if value := os.Getenv(env); value != "" {
+				set(env, key.String(), value)
+			}
+		}
+		return invalid
+	}
+
+	files := map[string]string{}
+	for _, pair := range os.Environ() {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], prefix) || parts[1] == "" {
+			continue
+		}
+		key := strings.ToLower(strings.TrimPrefix(parts[0], prefix))
+		if f.secret && strings.HasSuffix(key, "_file") {
+			files[strings.TrimSuffix(key, "_file")] = parts[1]
+			continue
+		}
+		set(parts[0], key, parts[1])
+	}
+	for key, path := range files {
+		env := prefix + strings.ToUpper(key) + "_FILE"
+		if value, _, err := readSecretFile(env, path); err != nil {
+			invalid = append(invalid, err.Error())
+		} else {
+			set(env, key, value)
+		}
+	}
+	return invalid
+}
+
+// setValue parses the text into v, in the format of the variables
+func setValue(v reflect.Value, text string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		v.Set(reflect.ValueOf(strings.Split(text, ",")))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// expectedValue describes the values of the type, for the variables which couldn't be parsed
+func expectedValue(t reflect.Type) string {
+	if t == durationType {
+		return "a duration, ex.: 30s"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int64:
+		return "an integer"
+	case reflect.Float64:
+		return "a number"
+	}
+	return "a " + typeName(t)
+}
+
+// typeName names the type of a setting for HelpConfig
+func typeName(t reflect.Type) string {
+	if t == durationType {
+		return "duration"
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64:
+		return "integer"
+	case reflect.Float64:
+		return "number"
+	case reflect.Slice:
+		return "list"
+	case reflect.Map:
+		return "map of " + typeName(t.Elem())
+	}
+	return t.Kind().String()
+}
+
+// checkRequired reports the required settings left empty
+func (c *Config) checkRequired(p *problems) {
+	bools := map[string]bool{}
+	walkFields(reflect.ValueOf(c).Elem(), "", func(f field) {
+		if f.value.Kind() == reflect.Bool {
+			bools[f.env] = f.value.Bool()
+		}
+	})
+
+	walkFields(reflect.ValueOf(c).Elem(), "", func(f field) {
+		switch {
+		case f.required == "":
+			return
+		case strings.HasPrefix(f.required, requiredUnless):
+			if bools[strings.TrimPrefix(f.required, requiredUnless)] {
+				return
+			}
+		case f.required != "true":
+			return
+		}
+		if f.value.IsZero() || (f.value.Kind() == reflect.Slice && f.value.Len() == 0) {
+			p.add("%s is required", f.env)
+		}
+	})
+}
+
+// HelpConfig writes every setting to w, for the operators: its variable, YAML key, type, default,
+// the defaults of the profiles differing from it, whether it is required or secret, and what it is for
+func HelpConfig(w io.Writer) error {
+	defaults := defaultConfig()
+	profiled := map[string]*Config{}
+	for env := range profiles {
+		profiled[env] = defaultConfig()
+		profiled[env].applyProfile(env)
+	}
+	envs := make([]string, 0, len(profiles))
+	for env := range profiles {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+
+	var err error
+	walkFields(reflect.ValueOf(defaults).Elem(), "", func(f field) {
+		if err != nil || f.env == "" {
+			return
+		}
+
+		details := []string{typeName(f.value.Type())}
+		if value := f.def; value != "" || f.value.Kind() == reflect.Map {
+			if value == "" {
+				value = formatValue(f.value)
+			}
+			details = append(details, "default: "+value)
+		}
+		for _, env := range envs {
+			if value := formatValue(settingField(profiled[env], f.key)); value != formatValue(f.value) {
+				if value == "" {
+					value = `""`
+				}
+				details = append(details, fmt.Sprintf("%s profile: %s", env, value))
+			}
+		}
+		switch {
+		case f.required == "true":
+			details = append(details, "required")
+		case strings.HasPrefix(f.required, requiredUnless):
+			details = append(details, "required unless "+strings.TrimPrefix(f.required, requiredUnless))
+		}
+		if f.secret {
+			details = append(details, "secret, may be read from the file of "+f.env+"_FILE")
+		}
+
+		_, err = fmt.Fprintf(w, "%s (%s)\n    %s\n    %s\n\n", f.env, f.key, f.desc, strings.Join(details, ", "))
+	})
+	return err
+}
+
+// settingField returns the value of the setting of c at the key
+func settingField(c *Config, key string) reflect.Value {
+	var value reflect.Value
+	walkFields(reflect.ValueOf(c).Elem(), "", func(f field) {
+		if f.key == key {
+			value = f.value
+		}
+	})
+	return value
+}
+
+// formatValue formats the value in the format of the variables, empty when unset
+func formatValue(v reflect.Value) string {
+	if v.Type() == durationType {
+		if v.Int() == 0 {
+			return ""
+		}
+		return time.Duration(v.Int()).String()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = v.Index(i).String()
+		}
+		return strings.Join(values, ",")
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, fmt.Sprintf("%s=%v", key, v.MapIndex(key)))
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ",")
+	}
+	if v.IsZero() && v.Kind() != reflect.Bool {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfig_Loader(t *testing.T) {
+	t.Run("expect every setting with a variable to be described, and every variable to be unique", func(t *testing.T) {
+		seen := map[string]string{}
+		walkFields(reflect.ValueOf(&Config{}).Elem(), "", func(f field) {
+			if f.env == "" || f.desc == "" {
+				t.Fatalf("expected %s to declare its variable and description", f.key)
+			}
+			if other, ok := seen[f.env]; ok {
+				t.Fatalf("expected %s to be the variable of a single setting, got %s and %s", f.env, other, f.key)
+			}
+			seen[f.env] = f.key
+		})
+	})
+
+	t.Run("expect the default tags to be parsed by the type of their setting", func(t *testing.T) {
+		conf := defaultConfig()
+
+		if conf.Server.Address != "localhost:4000" || conf.Server.ShutdownGracePeriod != 15*time.Second || conf.Server.MaxBodyBytes != 1<<20 {
+			t.Fatalf("expected the defaults of the server, got %+v", conf.Server)
+		}
+		if conf.Tracing.SampleRatio != 1 || !conf.Onboarding.WelcomeEnabled || conf.Cache.ImmutableMaxAge != 365*24*time.Hour {
+			t.Fatalf("expected the numbers, booleans and durations, got %+v %+v %+v", conf.Tracing, conf.Onboarding, conf.Cache)
+		}
+		if !reflect.DeepEqual(conf.AppConfig.OAuthScopes, []string{"openid", "profile", "email"}) || len(conf.CORS.AllowedHeaders) != 6 {
+			t.Fatalf("expected the lists to be split on commas, got %v and %v", conf.AppConfig.OAuthScopes, conf.CORS.AllowedHeaders)
+		}
+		if conf.AppConfig.SecurityHeaders.ContentSecurityPolicy != DefaultContentSecurityPolicy || !strings.Contains(DefaultContentSecurityPolicy, "frame-ancestors 'none'") {
+			t.Fatalf("unexpected content security policy %q", conf.AppConfig.SecurityHeaders.ContentSecurityPolicy)
+		}
+		if conf.Env != DefaultEnv || conf.AppConfig.OIDCDiscovery != OIDCDiscoveryEager || conf.AppConfig.OIDCIssuerURL != GoogleIssuer {
+			t.Fatalf("expected the defaults to match their constants, got %q %q %q", conf.Env, conf.AppConfig.OIDCDiscovery, conf.AppConfig.OIDCIssuerURL)
+		}
+		if len(conf.Features.Flags) != len(DefaultFeatureFlags) {
+			t.Fatalf("expected the default feature flags, got %v", conf.Features.Flags)
+		}
+	})
+
+	t.Run("expect the variables to be parsed by the type of their setting", func(t *testing.T) {
+		setEnv(t, "MAX_BODY_BYTES", "2048")
+		setEnv(t, "OTEL_TRACES_SAMPLER_RATIO", "0.25")
+		setEnv(t, "NOTIFIER_DEDUP_WINDOW", "90s")
+		setEnv(t, "ONBOARDING_WELCOME_ENABLED", "false")
+		setEnv(t, "TRUSTED_PROXIES", "10.0.0.0/8,192.168.0.1")
+		setEnv(t, "NOTIFIER_ROUTE_BEER_GIVEN", "fcm,slack")
+
+		conf := defaultConfig()
+		invalid := conf.overrideFromEnv()
+
+		if len(invalid) != 0 {
+			t.Fatalf("unexpected invalid values %v", invalid)
+		}
+		if conf.Server.MaxBodyBytes != 2048 || conf.Tracing.SampleRatio != 0.25 || conf.Notifier.DedupWindow != 90*time.Second || conf.Onboarding.WelcomeEnabled {
+			t.Fatalf("unexpected settings %+v %+v %+v %+v", conf.Server, conf.Tracing, conf.Notifier, conf.Onboarding)
+		}
+		if len(conf.Server.TrustedProxies) != 2 || len(conf.Notifier.Routes["beer_given"]) != 2 {
+			t.Fatalf("unexpected lists %v and %v", conf.Server.TrustedProxies, conf.Notifier.Routes)
+		}
+	})
+
+	t.Run("expect the variables which can't be parsed to be collected, the setting keeping its value", func(t *testing.T) {
+		setEnv(t, "SERVER_READ_TIMEOUT", "soon")
+		setEnv(t, "SLOW_LOG_SIZE", "many")
+		setEnv(t, "FEATURE_USERS_ME", "maybe")
+		setEnv(t, "DEBUG_ENDPOINTS_ENABLED", "")
+
+		conf := defaultConfig()
+		invalid := conf.overrideFromEnv()
+
+		if conf.Server.ReadTimeout != 15*time.Second || conf.SlowLog.Size != 100 || conf.Features.Flags["users_me"] {
+			t.Fatalf("expected the defaults, got %v, %d and %v", conf.Server.ReadTimeout, conf.SlowLog.Size, conf.Features.Flags)
+		}
+		expected := []string{
+			`SERVER_READ_TIMEOUT must be a duration, ex.: 30s, got "soon"`,
+			`SLOW_LOG_SIZE must be an integer, got "many"`,
+			`FEATURE_USERS_ME must be true or false, got "maybe"`,
+		}
+		if len(invalid) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, invalid)
+		}
+		for _, problem := range expected {
+			if !strings.Contains(strings.Join(invalid, "\n"), problem) {
+				t.Fatalf("expected the problem '%s' in %v", problem, invalid)
+			}
+		}
+	})
+
+	t.Run("expect the maps of flags to only take their declared keys", func(t *testing.T) {
+		setEnv(t, "FEATURE_USERS_ME", "true")
+		setEnv(t, "FEATURE_UNKNOWN", "true")
+
+		conf := defaultConfig()
+		conf.overrideFromEnv()
+
+		if !conf.Features.Flags["users_me"] || len(conf.Features.Flags) != len(DefaultFeatureFlags) {
+			t.Fatalf("unexpected flags %v", conf.Features.Flags)
+		}
+	})
+
+	t.Run("expect the required settings to be reported when empty, unless their condition is set", func(t *testing.T) {
+		conf := validConfig()
+		conf.Database.URI = ""
+		conf.Server.Address = ""
+		conf.AppConfig.WebClientID = ""
+
+		var p problems
+		conf.checkRequired(&p)
+
+		if !reflect.DeepEqual([]string(p), []string{"ADDRESS is required", "GOOGLE_OIDC_WEB_CLIENT_ID is required", "DB_URI is required"}) {
+			t.Fatalf("unexpected problems %v", p)
+		}
+
+		conf.AppConfig.TestMode = true
+		p = nil
+		conf.checkRequired(&p)
+		if len(p) != 2 {
+			t.Fatalf("expected the client ID to be optional in test mode, got %v", p)
+		}
+	})
+
+	t.Run("expect the help to list every setting with its type, default, profiles and requirement", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := HelpConfig(&buf); err != nil {
+			t.Fatal(err)
+		}
+		help := buf.String()
+
+		for _, expected := range []string{
+			"ADDRESS (server.address)\n    The host:port address the API listens on\n    string, default: localhost:4000, required\n",
+			"SERVER_READ_TIMEOUT (server.read_timeout)\n    How long a whole request may take to be read\n    duration, default: 15s\n",
+			"DB_URI (database.uri)\n",
+			"string, required, secret, may be read from the file of DB_URI_FILE\n",
+			"string, required unless TEST_MODE\n",
+			"LOG_FORMAT (logging.format)\n    The format of the logs: console or json\n    string, default: console, prod profile: json, staging profile: json\n",
+			"FEATURE_<NAME> (features.flags)\n",
+			"map of bool, default: auth_token=false,users_me=false\n",
+		} {
+			if !strings.Contains(help, expected) {
+				t.Fatalf("expected %q in the help, got %s", expected, help)
+			}
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/coreos/go-oidc"
+	"golang.org/x/oauth2"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The modes the OIDC provider is discovered in
+const (
+	// OIDCDiscoveryEager discovers the provider while loading the configuration, failing the startup
+	// when it can't
+	OIDCDiscoveryEager = "eager"
+	// OIDCDiscoveryLazy discovers the provider in the background once started, retrying until it
+	// succeeds, the routes which need it answering 503 meanwhile
+	OIDCDiscoveryLazy = "lazy"
+)
+
+// GoogleIssuer is the issuer of the ID tokens of Google, its discovery document being under it
+const GoogleIssuer = "https://accounts.google.com"
+
+// The delays between the attempts of the lazy discovery, doubled at every attempt
+const (
+	OIDCDiscoveryBaseDelay = time.Second
+	OIDCDiscoveryMaxDelay  = time.Minute
+)
+
+// oidcRequestTimeout bounds the calls to the provider, for its discovery document and its keys
+const oidcRequestTimeout = 10 * time.Second
+
+// ErrOIDCUnavailable is returned while the OIDC provider isn't discovered yet
+var ErrOIDCUnavailable = errors.New("the OIDC provider isn't discovered yet")
+
+// OIDCProvider is the OIDC provider of an issuer, discovered once, safe for concurrent use. Until
+// then the tokens can't be verified. When skipIssuerCheck, for the providers behind a proxy, the
+// issuer of the discovery document and of the tokens may differ from the URL it's discovered at.
+type OIDCProvider struct {
+	issuer          string
+	skipIssuerCheck bool
+	client          *http.Client
+
+	mu         sync.RWMutex
+	discovered *oidcDiscovery
+	err        error
+}
+
+// oidcDiscovery is what the discovery document of the provider tells: the issuer of its tokens,
+// the endpoints of the OAuth flow, and the keys its tokens are signed with
+type oidcDiscovery struct {
+	issuer   string
+	endpoint oauth2.Endpoint
+	keySet   oidc.KeySet
+}
+
+// NewOIDCProvider returns the undiscovered provider of issuer, its discovery document and keys
+// being fetched with client, which bounds each call with its timeout
+func NewOIDCProvider(issuer string, skipIssuerCheck bool, client *http.Client) *OIDCProvider {
+	return &OIDCProvider{issuer: strings.TrimSuffix(issuer, "/"), skipIssuerCheck: skipIssuerCheck, client: client, err: ErrOIDCUnavailable}
+}
+
+// Discover fetches the discovery document of the issuer once, the provider staying undiscovered
+// when it fails. The key set keeps the context it's created with to fetch the keys as they rotate,
+// hence a background one rather than the one of the caller.
+func (p *OIDCProvider) Discover() error {
+	discovered, err := p.discover(oidc.ClientContext(context.Background(), p.client))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		p.err = err
+		return err
+	}
+	p.discovered, p.err = discovered, nil
+	return nil
+}
+
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: discovery document of %s: %s", p.issuer, resp.Status)
+	}
+
+	var document struct {
+		Issuer   string `json:"issuer"`
+		AuthURL  string `json:"authorization_endpoint"`
+		TokenURL string `json:"token_endpoint"`
+		JWKSURL  string `json:"jwks_uri"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("oidc: discovery document of %s: %w", p.issuer, err)
+	}
+	if document.JWKSURL == "" {
+		return nil, fmt.Errorf("oidc: discovery document of %s has no jwks_uri", p.issuer)
+	}
+	if !p.skipIssuerCheck && document.Issuer != p.issuer {
+		return nil, fmt.Errorf("oidc: issuer %q of the discovery document doesn't match %q", document.Issuer, p.issuer)
+	}
+
+	return &oidcDiscovery{
+		issuer:   document.Issuer,
+		endpoint: oauth2.Endpoint{AuthURL: document.AuthURL, TokenURL: document.TokenURL},
+		keySet:   oidc.NewRemoteKeySet(ctx, document.JWKSURL),
+	}, nil
+}
+
+// DiscoverWithRetry attempts the discovery until it succeeds or ctx is done, waiting between the
+// attempts a random delay up to baseDelay, doubled at every attempt and capped by maxDelay.
+// onError is called with every failed attempt.
+func (p *OIDCProvider) DiscoverWithRetry(ctx context.Context, baseDelay time.Duration, maxDelay time.Duration, onError func(attempt int, err error)) error {
+	for attempt := 1; ; attempt++ {
+		err := p.Discover()
+		if err == nil {
+			return nil
+		}
+		onError(attempt, err)
+
+		timer := time.NewTimer(discoveryBackoff(attempt, baseDelay, maxDelay))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// discoveryBackoff returns the delay before the attempt following the given one, with full jitter
+// for the replicas not to retry in step, the connections to the database backing off alike
+func discoveryBackoff(attempt int, baseDelay time.Duration, maxDelay time.Duration) time.Duration {
+	ceiling := maxDelay
+	if shift := uint(attempt - 1); shift < 32 && baseDelay<<shift > 0 && baseDelay<<shift < ceiling {
+		ceiling = baseDelay << shift
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// VerifyIDToken verifies the ID token with the OIDC provider, issued to one of the OIDCAudiences, or
+// else to the client of the platform (web, ios or android). The bearer tokens and the tokens of the
+// sign-in are verified alike.
+func (c *AppConfig) VerifyIDToken(ctx context.Context, rawIDToken string, platform string) (*oidc.IDToken, error) {
+	audiences := c.OIDCAudiences
+	if len(audiences) == 0 {
+		audiences = []string{c.GetPlatformClientID(platform)}
+	}
+	return c.OIDCProvider.Verify(ctx, rawIDToken, audiences)
+}
+
+// OAuthConfig returns GoogleOauth on the endpoints of the discovered provider, its configured ones
+// until then
+func (c *AppConfig) OAuthConfig() *oauth2.Config {
+	conf := c.GoogleOauth
+	if endpoint, ok := c.OIDCProvider.Endpoint(); ok {
+		conf.Endpoint = endpoint
+	}
+	return &conf
+}
+
+// Endpoint returns the endpoints of the OAuth flow of the provider, false until it's discovered
+func (p *OIDCProvider) Endpoint() (oauth2.Endpoint, bool) {
+	if p == nil {
+		return oauth2.Endpoint{}, false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.discovered == nil {
+		return oauth2.Endpoint{}, false
+	}
+	return p.discovered.endpoint, true
+}
+
+// Err returns why the provider isn't discovered, nil once it is
+func (p *OIDCProvider) Err() error {
+	if p == nil {
+		return ErrOIDCUnavailable
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.err
+}
+
+// Verify verifies the signature, expiry and issuer of the ID token, and that it was issued to one of
+// audiences, returning ErrOIDCUnavailable until the provider is discovered
+func (p *OIDCProvider) Verify(ctx context.Context, rawIDToken string, audiences []string) (*oidc.IDToken, error) {
+	if p == nil {
+		return nil, ErrOIDCUnavailable
+	}
+	p.mu.RLock()
+	discovered := p.discovered
+	p.mu.RUnlock()
+	if discovered == nil {
+		return nil, ErrOIDCUnavailable
+	}
+
+	// go-oidc checks a single client ID, the audiences being checked below
+	verifier := oidc.NewVerifier(discovered.issuer, discovered.keySet, &oidc.Config{
+		SkipClientIDCheck: true,
+		SkipIssuerCheck:   p.skipIssuerCheck,
+	})
+	token, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	for _, audience := range token.Audience {
+		for _, expected := range audiences {
+			if audience == expected {
+				return token, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("oidc: expected an audience among %v, got %v", audiences, token.Audience)
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"appdoki-be/app/testsupport"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOIDCProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expect the discovery to be retried until it succeeds", func(t *testing.T) {
+		fake := testsupport.NewOIDCProvider(t)
+		fake.FailDiscovery(2)
+		provider := NewOIDCProvider(fake.Issuer, false, http.DefaultClient)
+		if _, err := provider.Verify(ctx, fake.IDToken(t, "1", "web", nil), []string{"web"}); !errors.Is(err, ErrOIDCUnavailable) {
+			t.Fatalf("expected the tokens not to be verified before the discovery, got %v", err)
+		}
+
+		var attempts []int
+		err := provider.DiscoverWithRetry(ctx, time.Millisecond, 5*time.Millisecond, func(attempt int, err error) {
+			attempts = append(attempts, attempt)
+			if provider.Err() == nil {
+				t.Fatal("expected the provider to be undiscovered after a failure")
+			}
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(attempts) != 2 || attempts[1] != 2 || fake.DiscoveryRequests() != 3 {
+			t.Fatalf("expected 2 failed attempts before the third succeeded, got %v and %d requests", attempts, fake.DiscoveryRequests())
+		}
+		if endpoint, ok := provider.Endpoint(); !ok || endpoint.TokenURL != fake.Issuer+"/protocol/openid-connect/token" {
+			t.Fatalf("expected the provider to be discovered, got %v", provider.Err())
+		}
+	})
+
+	t.Run("expect the retries to stop once the context is done", func(t *testing.T) {
+		fake := testsupport.NewOIDCProvider(t)
+		fake.FailDiscovery(1000)
+		provider := NewOIDCProvider(fake.Issuer, false, http.DefaultClient)
+		ctx, cancel := context.WithCancel(ctx)
+
+		err := provider.DiscoverWithRetry(ctx, time.Millisecond, time.Millisecond, func(attempt int, _ error) {
+			if attempt == 3 {
+				cancel()
+			}
+		})
+
+		if _, ok := provider.Endpoint(); !errors.Is(err, context.Canceled) || ok {
+			t.Fatalf("expected the discovery to be canceled, got %v", err)
+		}
+	})
+
+	t.Run("expect the delays to double up to the max delay", func(t *testing.T) {
+		for attempt, ceiling := range map[int]time.Duration{1: time.Second, 3: 4 * time.Second, 10: time.Minute, 100: time.Minute} {
+			for i := 0; i < 100; i++ {
+				if delay := discoveryBackoff(attempt, time.Second, time.Minute); delay < 0 || delay > ceiling {
+					t.Fatalf("expected the delay of attempt %d up to %s, got %s", attempt, ceiling, delay)
+				}
+			}
+		}
+	})
+
+	t.Run("expect the tokens of a non Google issuer to be verified for the expected audiences", func(t *testing.T) {
+		fake := testsupport.NewOIDCProvider(t)
+		provider := NewOIDCProvider(fake.Issuer, false, http.DefaultClient)
+		if err := provider.Discover(); err != nil {
+			t.Fatal(err)
+		}
+
+		token, err := provider.Verify(ctx, fake.IDToken(t, "42", "appdoki-mobile", nil), []string{"appdoki-web", "appdoki-mobile"})
+		if err != nil || token.Subject != "42" {
+			t.Fatalf("expected the token of user 42 to be verified, got %v", err)
+		}
+		if _, err := provider.Verify(ctx, fake.IDToken(t, "42", "another-app", nil), []string{"appdoki-web"}); err == nil {
+			t.Fatal("expected the token of another audience to be refused")
+		}
+		if _, err := NewOIDCProvider(GoogleIssuer, false, http.DefaultClient).Verify(ctx, "token", nil); !errors.Is(err, ErrOIDCUnavailable) {
+			t.Fatalf("expected the undiscovered provider to be unavailable, got %v", err)
+		}
+	})
+
+	t.Run("expect the issuer to be checked unless skipped, for a proxied provider", func(t *testing.T) {
+		fake := testsupport.NewOIDCProvider(t)
+		fake.ProxiedAs("https://sso.appdoki.dev/realms/appdoki")
+
+		if err := NewOIDCProvider(fake.Issuer, false, http.DefaultClient).Discover(); err == nil {
+			t.Fatal("expected the issuer of the discovery document to be checked")
+		}
+
+		provider := NewOIDCProvider(fake.Issuer, true, http.DefaultClient)
+		if err := provider.Discover(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := provider.Verify(ctx, fake.IDToken(t, "42", "appdoki-web", nil), []string{"appdoki-web"}); err != nil {
+			t.Fatalf("expected the token of the proxied issuer to be verified, got %v", err)
+		}
+	})
+
+	t.Run("expect the nil provider to be unavailable", func(t *testing.T) {
+		var provider *OIDCProvider
+		if _, err := provider.Verify(ctx, "token", nil); !errors.Is(err, ErrOIDCUnavailable) || provider.Err() == nil {
+			t.Fatalf("expected the provider to be unavailable, got %v", err)
+		}
+	})
+}
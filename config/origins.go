@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// subdomainLabel is what the * of an origin pattern stands for, a single label of the host
+const subdomainLabel = `[a-z0-9]([a-z0-9-]*[a-z0-9])?`
+
+// OriginMatcher tells whether the browsers of an origin may call the API, from the allowed origins:
+// exact ones, patterns matching a subdomain like https://*.appdoki.dev, or * for any
+type OriginMatcher struct {
+	any      bool
+	exact    map[string]bool
+	patterns []*regexp.Regexp
+}
+
+// NewOriginMatcher compiles the allowed origins, failing on the first malformed one
+func NewOriginMatcher(origins []string) (*OriginMatcher, error) {
+	m := &OriginMatcher{exact: map[string]bool{}}
+	for _, origin := range origins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			m.any = true
+			continue
+		}
+
+		pattern, err := compileOrigin(origin)
+		if err != nil {
+			return nil, err
+		}
+		if pattern == nil {
+			m.exact[strings.ToLower(origin)] = true
+		} else {
+			m.patterns = append(m.patterns, pattern)
+		}
+	}
+	return m, nil
+}
+
+// Empty tells whether no origin is allowed
+func (m *OriginMatcher) Empty() bool {
+	return !m.any && len(m.exact) == 0 && len(m.patterns) == 0
+}
+
+// Allow returns the Access-Control-Allow-Origin value of origin: * when any is allowed, the origin
+// when it is allowed, and empty otherwise
+func (m *OriginMatcher) Allow(origin string) string {
+	if origin == "" {
+		return ""
+	}
+	if m.any {
+		return "*"
+	}
+
+	lower := strings.ToLower(origin)
+	if m.exact[lower] {
+		return origin
+	}
+	for _, pattern := range m.patterns {
+		if pattern.MatchString(lower) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// compileOrigin checks origin is a scheme and a host, with an optional port, returning the matcher
+// of its subdomains when it starts with *. The pattern is anchored on the whole origin, for
+// https://*.appdoki.dev not to match https://evil-appdoki.dev nor https://pr-1.appdoki.dev:8443.
+func compileOrigin(origin string) (*regexp.Regexp, error) {
+	invalid := fmt.Errorf("%q isn't an origin, ex.: https://appdoki.cloudoki.com or https://*.appdoki.dev", origin)
+
+	scheme, host := origin, ""
+	if i := strings.Index(origin, "://"); i > 0 {
+		scheme, host = origin[:i], origin[i+len("://"):]
+	}
+	wildcard := strings.HasPrefix(host, "*.")
+	if strings.Contains(strings.TrimPrefix(host, "*."), "*") {
+		return nil, invalid
+	}
+
+	u, err := url.Parse(scheme + "://" + strings.Replace(host, "*", "wildcard", 1))
+	if err != nil || u.Scheme == "" || u.Hostname() == "" || (u.Path != "" && u.Path != "/") ||
+		u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return nil, invalid
+	}
+	if !wildcard {
+		return nil, nil
+	}
+
+	suffix := strings.TrimSuffix(strings.ToLower(host[len("*"):]), "/")
+	if strings.Count(strings.Split(suffix, ":")[0], ".") < 2 {
+		return nil, fmt.Errorf("%q would match the subdomains of a top-level domain", origin)
+	}
+	return regexp.MustCompile("^" + regexp.QuoteMeta(strings.ToLower(scheme)+"://") + subdomainLabel + regexp.QuoteMeta(suffix) + "$"), nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestOriginMatcher(t *testing.T) {
+	m, err := NewOriginMatcher([]string{"https://appdoki.cloudoki.com", "https://*.appdoki.dev", " http://*.preview.appdoki.dev:8080 "})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		origin string
+		allow  string
+	}{
+		{"an exact origin", "https://APPDOKI.cloudoki.com", "https://APPDOKI.cloudoki.com"},
+		{"a subdomain", "https://pr-123.appdoki.dev", "https://pr-123.appdoki.dev"},
+		{"a subdomain with its port", "http://pr-1.preview.appdoki.dev:8080", "http://pr-1.preview.appdoki.dev:8080"},
+		{"no port mismatch", "https://pr-123.appdoki.dev:8443", ""},
+		{"no other port", "http://pr-1.preview.appdoki.dev:9090", ""},
+		{"no other scheme", "http://pr-123.appdoki.dev", ""},
+		{"no domain ending the same", "https://evil-appdoki.dev", ""},
+		{"no domain continuing it", "https://pr-123.appdoki.dev.evil.com", ""},
+		{"no domain itself", "https://appdoki.dev", ""},
+		{"no nested subdomain", "https://a.b.appdoki.dev", ""},
+		{"no empty origin", "", ""},
+	} {
+		t.Run("expect "+tc.name, func(t *testing.T) {
+			if allow := m.Allow(tc.origin); allow != tc.allow {
+				t.Fatalf("expected '%s' for %s, got '%s'", tc.allow, tc.origin, allow)
+			}
+		})
+	}
+
+	t.Run("expect * to allow any origin", func(t *testing.T) {
+		m, _ := NewOriginMatcher([]string{"https://*.appdoki.dev", "*"})
+
+		if allow := m.Allow("https://evil.com"); allow != "*" {
+			t.Fatalf("expected *, got '%s'", allow)
+		}
+	})
+
+	t.Run("expect the malformed patterns to be rejected", func(t *testing.T) {
+		for _, origin := range []string{
+			"*.appdoki.dev", "https://pr-*.appdoki.dev", "https://*.*.appdoki.dev", "https://app.*.dev",
+			"https://*.dev", "https://*.appdoki.dev/path", "https://*.appdoki.dev?q=1", "appdoki.dev",
+		} {
+			if _, err := NewOriginMatcher([]string{origin}); err == nil {
+				t.Fatalf("expected %s to be rejected", origin)
+			}
+		}
+
+		conf := CORSConfig{AllowedOrigins: []string{"https://*.appdoki.dev", "https://pr-*.appdoki.dev", "*"}}
+		if problems := problemsOf(t, conf.Validate()); len(problems) != 1 {
+			t.Fatalf("expected the malformed pattern to be reported, got %v", problems)
+		}
+	})
+}
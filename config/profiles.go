@@ -0,0 +1,103 @@
+package config
+
+import (
+	"gopkg.in/yaml.v2"
+	"os"
+	"strings"
+)
+
+// The environments, each with a profile of defaults
+const (
+	EnvDev     = "dev"
+	EnvStaging = "staging"
+	EnvProd    = "prod"
+)
+
+// DefaultEnv is the environment of the deployments which don't set APP_ENV, the strictest one
+const DefaultEnv = EnvProd
+
+// profiles set the defaults of each environment, which the file and the environment variables
+// override: the format of the logs, Swagger UI, the Secure flag of the cookies, the origins allowed
+// to call the API, the pprof endpoints and the logs of the SQL statements
+var profiles = map[string]func(c *Config){
+	EnvDev: func(c *Config) {
+		c.Logging.Format = "console"
+		c.Logging.Queries = QueryLogAll
+		c.AppConfig.DocsEnabled = true
+		c.AppConfig.CookieSecure = false
+		c.CORS.AllowedOrigins = []string{"*"}
+		c.Debug.Enabled = true
+	},
+	EnvStaging: func(c *Config) {
+		c.Logging.Format = "json"
+		c.Logging.Queries = QueryLogOff
+		c.AppConfig.DocsEnabled = true
+		c.AppConfig.CookieSecure = true
+		c.CORS.AllowedOrigins = nil
+		c.Debug.Enabled = true
+	},
+	EnvProd: func(c *Config) {
+		c.Logging.Format = "json"
+		c.Logging.Queries = QueryLogOff
+		c.AppConfig.DocsEnabled = false
+		c.AppConfig.CookieSecure = true
+		c.CORS.AllowedOrigins = nil
+		c.Debug.Enabled = false
+	},
+}
+
+// applyProfile sets the defaults of the environment, an unknown one being left to Validate
+func (c *Config) applyProfile(env string) {
+	c.Env = env
+	if profile, ok := profiles[env]; ok {
+		profile(c)
+	}
+}
+
+// profileEnv returns the environment of APP_ENV, or else of the env key of the file
+func profileEnv(file []byte) string {
+	var probe struct {
+		Env string `yaml:"env"`
+	}
+	yaml.Unmarshal(file, &probe)
+	if probe.Env == "" {
+		probe.Env = DefaultEnv
+	}
+
+	if env, ok := os.LookupEnv("APP_ENV"); ok {
+		return env
+	}
+	return probe.Env
+}
+
+// validateProfile checks the environment is known, and refuses the insecure combinations in prod
+func (c *Config) validateProfile(p *problems) {
+	if _, ok := profiles[c.Env]; !ok {
+		p.add("APP_ENV must be dev, staging or prod, got %q", c.Env)
+		return
+	}
+	if c.Env != EnvProd {
+		return
+	}
+
+	if c.CORS.AllowCredentials {
+		for _, origin := range c.CORS.AllowedOrigins {
+			if strings.TrimSpace(origin) == "*" {
+				p.add("APP_ENV=prod: CORS_ALLOWED_ORIGINS can't allow any origin along with CORS_ALLOW_CREDENTIALS")
+				break
+			}
+		}
+	}
+	if !c.AppConfig.CookieSecure {
+		p.add("APP_ENV=prod: COOKIE_SECURE can't be disabled")
+	}
+	if !c.AppConfig.TestMode && strings.HasPrefix(c.AppConfig.GoogleOauth.RedirectURL, "http://") {
+		p.add("APP_ENV=prod: GOOGLE_OAUTH_REDIRECT_URL must be https, the cookies being sent over https only")
+	}
+	if c.OutboundTLS.InsecureSkipVerify {
+		p.add("APP_ENV=prod: OUTBOUND_TLS_INSECURE_SKIP_VERIFY can't be set, trust the CA of the proxy with OUTBOUND_TLS_CA_FILE")
+	}
+	if len(c.Admin.AllowedNetworks) == 0 {
+		p.add("APP_ENV=prod: ADMIN_ALLOWED_NETWORKS is required, 0.0.0.0/0,::/0 letting any network through")
+	}
+}
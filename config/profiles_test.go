@@ -0,0 +1,90 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Run("expect each environment to have its defaults", func(t *testing.T) {
+		for _, tc := range []struct {
+			env          string
+			format       string
+			docs         bool
+			cookieSecure bool
+			anyOrigin    bool
+			debug        bool
+		}{
+			{EnvDev, "console", true, false, true, true},
+			{EnvStaging, "json", true, true, false, true},
+			{EnvProd, "json", false, true, false, false},
+		} {
+			setEnv(t, "APP_ENV", tc.env)
+
+			conf, _, _ := load("")
+
+			c := conf.AppConfig
+			anyOrigin := len(conf.CORS.AllowedOrigins) == 1 && conf.CORS.AllowedOrigins[0] == "*"
+			if conf.Env != tc.env || conf.Logging.Format != tc.format || c.DocsEnabled != tc.docs ||
+				c.CookieSecure != tc.cookieSecure || anyOrigin != tc.anyOrigin || conf.Debug.Enabled != tc.debug {
+				t.Fatalf("unexpected defaults of %s: %+v", tc.env, conf)
+			}
+		}
+	})
+
+	t.Run("expect prod without APP_ENV, and the explicit settings to override the profile", func(t *testing.T) {
+		setEnv(t, "DOCS_ENABLED", "true")
+
+		conf, _, _ := load("")
+
+		if conf.Env != EnvProd || !conf.AppConfig.DocsEnabled || conf.Logging.Format != "json" {
+			t.Fatalf("expected the prod defaults but the docs, got %+v", conf)
+		}
+	})
+
+	t.Run("expect the file to select the environment, its settings overriding the profile", func(t *testing.T) {
+		path := writeConfigFile(t, "appdoki.yaml", "env: dev\ndebug:\n  enabled: false\n")
+
+		conf, _, _ := load(path)
+
+		if conf.Env != EnvDev || conf.Debug.Enabled || conf.CORS.AllowedOrigins[0] != "*" {
+			t.Fatalf("expected the dev defaults but pprof, got %+v", conf)
+		}
+	})
+
+	t.Run("expect an unknown environment to be invalid", func(t *testing.T) {
+		conf := validConfig()
+		conf.Env = "production"
+
+		problems := problemsOf(t, conf.Validate())
+
+		if len(problems) != 1 || !strings.HasPrefix(problems[0], "APP_ENV must be") {
+			t.Fatalf("expected the environment to be reported, got %v", problems)
+		}
+	})
+
+	t.Run("expect prod to refuse the insecure combinations", func(t *testing.T) {
+		conf := validConfig()
+		conf.CORS = CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+		conf.AppConfig.CookieSecure = false
+		conf.AppConfig.GoogleOauth.RedirectURL = "http://appdokiapi.cloudoki.com/auth/callback"
+		conf.OutboundTLS.InsecureSkipVerify = true
+		conf.Admin.AllowedNetworks = nil
+
+		problems := problemsOf(t, conf.Validate())
+
+		if len(problems) != 5 {
+			t.Fatalf("expected the 5 insecure settings to be reported, got %v", problems)
+		}
+		for _, problem := range problems {
+			if !strings.HasPrefix(problem, "APP_ENV=prod:") {
+				t.Fatalf("unexpected problem %s", problem)
+			}
+		}
+
+		conf.Env = EnvDev
+		if problems := problemsOf(t, conf.Validate()); len(problems) != 0 {
+			t.Fatalf("expected the dev environment to allow them, got %v", problems)
+		}
+	})
+}
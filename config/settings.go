@@ -0,0 +1,146 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// The sources of the settings, the last one to set the value winning
+const (
+	SourceDefault = "default"
+	SourceProfile = "profile"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// redactedVisible is how many of the last characters of a secret are shown, as long as the secret is
+// at least twice as long
+const redactedVisible = 4
+
+// Setting is a setting of the effective configuration, its key being the path of its YAML keys (ex.:
+// server.address), its Value redacted when tagged secret, and its Source the default, the profile of
+// the environment, the file or the environment variables
+type Setting struct {
+	Key    string      `json:"key"`
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+// Settings returns the effective configuration, every field tagged secret:"true" being redacted to
+// its last characters. The entries of the maps are settings of their own, ex.: features.flags.users_me.
+func (c *Config) Settings() []Setting {
+	var settings []Setting
+	walkSettings(reflect.ValueOf(c).Elem(), "", false, func(key string, value reflect.Value, secret bool) {
+		source := c.sources[key]
+		if source == "" {
+			source = SourceDefault
+		}
+		settings = append(settings, Setting{Key: key, Value: settingValue(value, secret), Source: source})
+	})
+	return settings
+}
+
+// PrintConfig writes the effective configuration of the file at path and of the environment
+// variables to w, as JSON, without discovering the OIDC provider
+func PrintConfig(w io.Writer, path string) error {
+	conf, _, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(conf.Settings())
+}
+
+// walkSettings calls fn with every setting of v, the fields tagged yaml:"-" and the unexported ones
+// being left out. The settings within a field tagged secret are secret as well.
+func walkSettings(v reflect.Value, prefix string, secret bool, fn func(key string, value reflect.Value, secret bool)) {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		fn(prefix, v, secret)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name := field.Tag.Get("yaml")
+			if field.PkgPath != "" || name == "" || name == "-" {
+				continue
+			}
+			walkSettings(v.Field(i), joinKey(prefix, name), secret || field.Tag.Get("secret") == "true", fn)
+		}
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkSettings(v.MapIndex(reflect.ValueOf(key)), joinKey(prefix, key), secret, fn)
+		}
+	default:
+		fn(prefix, v, secret)
+	}
+}
+
+func joinKey(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// settingValue returns the value as printed: the durations as text (ex.: 15s) and the secrets redacted
+func settingValue(value reflect.Value, secret bool) interface{} {
+	if secret {
+		return redact(fmt.Sprint(value.Interface()))
+	}
+	if d, ok := value.Interface().(time.Duration); ok {
+		return d.String()
+	}
+	return value.Interface()
+}
+
+// redact hides the secret but for its last redactedVisible characters, entirely when it is too
+// short for them not to give most of it away. An empty secret is left empty, to tell it is unset.
+func redact(secret string) string {
+	runes := []rune(secret)
+	switch {
+	case len(runes) == 0:
+		return ""
+	case len(runes) < 2*redactedVisible:
+		return "****"
+	}
+	return "****" + string(runes[len(runes)-redactedVisible:])
+}
+
+// settingsSnapshot returns the printed value of every setting, for the sources to be found by
+// comparing the snapshots taken after each step of the loading
+func (c *Config) settingsSnapshot() map[string]string {
+	snapshot := map[string]string{}
+	walkSettings(reflect.ValueOf(c).Elem(), "", false, func(key string, value reflect.Value, _ bool) {
+		snapshot[key] = fmt.Sprint(value.Interface())
+	})
+	return snapshot
+}
+
+// trackSources records source as the source of the settings changed since the previous snapshot,
+// returning the current one
+func (c *Config) trackSources(previous map[string]string, source string) map[string]string {
+	current := c.settingsSnapshot()
+	if c.sources == nil {
+		c.sources = map[string]string{}
+	}
+	for key, value := range current {
+		if before, ok := previous[key]; !ok || before != value {
+			c.sources[key] = source
+		}
+	}
+	return current
+}
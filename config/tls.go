@@ -0,0 +1,69 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// TLSConfig returns the TLS configuration of the outbound calls, nil when none of the settings is
+// set for the defaults to apply. The CAs of CAFile are trusted along with the ones of the system.
+func (c *OutboundTLSConfig) TLSConfig() (*tls.Config, error) {
+	if c.CAFile == "" && c.CertFile == "" && c.KeyFile == "" && !c.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	conf := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify}
+	if c.CAFile != "" {
+		bundle, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("OUTBOUND_TLS_CA_FILE: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("OUTBOUND_TLS_CA_FILE: %s: no PEM encoded certificate found", c.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		if c.CertFile == "" || c.KeyFile == "" {
+			return nil, errors.New("both OUTBOUND_TLS_CERT_FILE and OUTBOUND_TLS_KEY_FILE must be set")
+		}
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("OUTBOUND_TLS_CERT_FILE and OUTBOUND_TLS_KEY_FILE: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	return conf, nil
+}
+
+// Transport returns the transport of the outbound calls, the one of http.DefaultTransport along
+// with the TLS configuration, for their clients to share its connections
+func (c *OutboundTLSConfig) Transport() (*http.Transport, error) {
+	conf, err := c.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if conf != nil {
+		transport.TLSClientConfig = conf
+	}
+	return transport, nil
+}
+
+// Validate checks the CA bundle and the client certificate can be loaded
+func (c *OutboundTLSConfig) Validate() error {
+	var p problems
+	if _, err := c.TLSConfig(); err != nil {
+		p.add("%v", err)
+	}
+	return p.err()
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutboundTLSConfig(t *testing.T) {
+	// writeCA writes the certificate the TLS test server presents, as the CA bundle of a proxy would be
+	writeCA := func(t *testing.T, srv *httptest.Server) string {
+		return writeConfigFile(t, "ca.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})))
+	}
+	get := func(t *testing.T, conf OutboundTLSConfig, url string) error {
+		transport, err := conf.Transport()
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := (&http.Client{Transport: transport}).Get(url)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	t.Run("expect the CA bundle to be trusted along with the system CAs", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		defer srv.Close()
+
+		if err := get(t, OutboundTLSConfig{}, srv.URL); err == nil || !strings.Contains(err.Error(), "certificate") {
+			t.Fatalf("expected the certificate of an unknown CA to be refused, got %v", err)
+		}
+		if err := get(t, OutboundTLSConfig{CAFile: writeCA(t, srv)}, srv.URL); err != nil {
+			t.Fatalf("expected the certificate of the CA of the bundle to be trusted, got %v", err)
+		}
+	})
+
+	t.Run("expect the client certificate to be presented", func(t *testing.T) {
+		var presented []string
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			for _, cert := range r.TLS.PeerCertificates {
+				presented = append(presented, cert.Subject.CommonName)
+			}
+		}))
+		srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+		srv.StartTLS()
+		defer srv.Close()
+		certFile, keyFile := writeClientCertificate(t, "appdoki-be")
+
+		if err := get(t, OutboundTLSConfig{CAFile: writeCA(t, srv), CertFile: certFile, KeyFile: keyFile}, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+		if len(presented) != 1 || presented[0] != "appdoki-be" {
+			t.Fatalf("expected the client certificate, got %v", presented)
+		}
+	})
+
+	t.Run("expect the certificates not to be verified when insecure", func(t *testing.T) {
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		defer srv.Close()
+
+		if err := get(t, OutboundTLSConfig{InsecureSkipVerify: true}, srv.URL); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("expect the defaults without any setting", func(t *testing.T) {
+		if conf, err := (&OutboundTLSConfig{}).TLSConfig(); conf != nil || err != nil {
+			t.Fatalf("expected no TLS configuration, got %+v, %v", conf, err)
+		}
+	})
+
+	t.Run("expect the unusable bundles and certificates to be reported", func(t *testing.T) {
+		conf := validConfig()
+		conf.OutboundTLS = OutboundTLSConfig{CAFile: writeConfigFile(t, "ca.pem", "not a certificate")}
+		other := validConfig()
+		other.OutboundTLS = OutboundTLSConfig{CertFile: "client.pem"}
+
+		if problems := problemsOf(t, conf.Validate()); len(problems) != 1 || !strings.Contains(problems[0], "no PEM encoded certificate found") {
+			t.Fatalf("expected the bundle to be reported, got %v", problems)
+		}
+		if problems := problemsOf(t, other.Validate()); len(problems) != 1 || problems[0] != "both OUTBOUND_TLS_CERT_FILE and OUTBOUND_TLS_KEY_FILE must be set" {
+			t.Fatalf("expected the missing key to be reported, got %v", problems)
+		}
+	})
+}
+
+// writeClientCertificate writes a self-signed client certificate of the common name and its key,
+// returning their paths
+func writeClientCertificate(t *testing.T, commonName string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := writeConfigFile(t, "client.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	keyFile := writeConfigFile(t, "client-key.pem", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})))
+	return certFile, keyFile
+}
@@ -0,0 +1,381 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ValidationError lists every problem found in the configuration, for them to be fixed at once
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// problems collects the problems of a configuration, as the environment variables to fix
+type problems []string
+
+func (p *problems) add(format string, args ...interface{}) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+// merge adds the problems of err, a ValidationError
+func (p *problems) merge(err error) {
+	var invalid *ValidationError
+	if errors.As(err, &invalid) {
+		*p = append(*p, invalid.Problems...)
+	}
+}
+
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p}
+}
+
+// Validate checks the whole configuration, returning a ValidationError with every problem found:
+// the variables which couldn't be parsed, the required ones missing, and the values out of range
+// or contradicting each other. The optional features are only checked when configured.
+func (c *Config) Validate() error {
+	var p problems
+	p = append(p, c.invalid...)
+	c.checkRequired(&p)
+	c.validateProfile(&p)
+	c.validateFCMCredentials(&p)
+	for _, err := range []error{
+		c.Server.Validate(), c.AppConfig.Validate(), c.Database.Validate(), c.Metrics.Validate(),
+		c.Tracing.Validate(), c.RateLimit.Validate(), c.CORS.Validate(), c.Features.Validate(), c.Admin.Validate(),
+		c.Webhooks.Validate(), c.Notifications.Validate(), c.Email.Validate(), c.Slack.Validate(),
+		c.Digest.Validate(), c.WebPush.Validate(), c.Logging.Validate(), c.OutboundTLS.Validate(),
+	} {
+		p.merge(err)
+	}
+	return p.err()
+}
+
+// Validate checks the addresses, the timeouts and the TLS settings of the server
+func (c *ServerConfig) Validate() error {
+	var p problems
+	checkAddress(&p, "ADDRESS", c.Address, false)
+	checkAddress(&p, "HTTP_REDIRECT_ADDRESS", c.HTTPRedirectAddress, false)
+	if c.MaxBodyBytes <= 0 {
+		p.add("MAX_BODY_BYTES must be positive")
+	}
+	if c.RequestTimeout <= 0 || c.StreamRequestTimeout <= 0 {
+		p.add("REQUEST_TIMEOUT and STREAM_REQUEST_TIMEOUT must be positive")
+	}
+	if c.WriteTimeout > 0 && c.WriteTimeout <= c.StreamRequestTimeout {
+		p.add("SERVER_WRITE_TIMEOUT must exceed STREAM_REQUEST_TIMEOUT for the streamed responses to get through")
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		p.add("both TLS_CERT_FILE and TLS_KEY_FILE must be set")
+	}
+	if c.TLSCertFile != "" && len(c.AutocertDomains) > 0 {
+		p.add("TLS_AUTOCERT_DOMAINS can't be set along with the certificate files")
+	}
+	if c.HTTPRedirectAddress != "" && !c.TLSEnabled() {
+		p.add("HTTP_REDIRECT_ADDRESS needs TLS, with TLS_CERT_FILE and TLS_KEY_FILE or TLS_AUTOCERT_DOMAINS")
+	}
+	checkNetworks(&p, "TRUSTED_PROXIES", c.TrustedProxies)
+	return p.err()
+}
+
+// Validate checks the Google sign-in settings, unused in test mode
+func (c *AppConfig) Validate() error {
+	var p problems
+	if !c.TestMode {
+		checkURL(&p, "GOOGLE_OAUTH_REDIRECT_URL", c.GoogleOauth.RedirectURL, false)
+
+		scopes := map[string]bool{}
+		for _, scope := range c.GoogleOauth.Scopes {
+			scopes[scope] = true
+		}
+		var missing []string
+		for _, required := range RequiredOAuthScopes {
+			if !scopes[required] {
+				missing = append(missing, required)
+			}
+		}
+		if len(missing) > 0 {
+			p.add("GOOGLE_OAUTH_SCOPES must include %s, the sign-in relying on their claims", strings.Join(missing, ", "))
+		}
+	}
+	for name := range c.OAuthAuthParams {
+		if reservedOAuthParams[name] {
+			p.add("GOOGLE_OAUTH_AUTH_PARAM_%s can't be set, the sign-in sets it", strings.ToUpper(name))
+		}
+	}
+	if c.OIDCDiscovery != OIDCDiscoveryEager && c.OIDCDiscovery != OIDCDiscoveryLazy {
+		p.add("GOOGLE_OIDC_DISCOVERY must be %s or %s, got %q", OIDCDiscoveryEager, OIDCDiscoveryLazy, c.OIDCDiscovery)
+	}
+	checkURL(&p, "OIDC_ISSUER_URL", c.OIDCIssuerURL, false)
+	checkURL(&p, "GOOGLE_OIDC_REVOKE_URL", c.RevokeEndpoint, false)
+	checkURL(&p, "PUBLIC_BASE_URL", c.PublicBaseURL, false)
+	if u, err := url.Parse(c.PublicBaseURL); err == nil && (u.RawQuery != "" || u.Fragment != "") {
+		p.add("PUBLIC_BASE_URL can't have a query nor a fragment, got %q", c.PublicBaseURL)
+	}
+	return p.err()
+}
+
+// reservedOAuthParams are the parameters of the URL of the consent page set by the sign-in itself
+var reservedOAuthParams = map[string]bool{
+	"client_id": true, "redirect_uri": true, "response_type": true, "scope": true, "state": true,
+}
+
+// Validate checks the pool of the database makes sense, and the replica isn't the primary
+func (c *DatabaseConfig) Validate() error {
+	var p problems
+	if c.ReplicaURI != "" && c.ReplicaURI == c.URI {
+		p.add("DB_REPLICA_URI can't be DB_URI, leave it unset to read from the primary")
+	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 {
+		p.add("DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS can't be negative, 0 open connections being unlimited")
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		p.add("DB_MAX_IDLE_CONNS (%d) can't exceed DB_MAX_OPEN_CONNS (%d)", c.MaxIdleConns, c.MaxOpenConns)
+	}
+	if c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 {
+		p.add("DB_CONN_MAX_LIFETIME and DB_CONN_MAX_IDLE_TIME can't be negative, 0 being unlimited")
+	}
+	if c.ConnectTimeout <= 0 {
+		p.add("DB_CONNECT_TIMEOUT must be positive, got %s", c.ConnectTimeout)
+	}
+	if c.StatementTimeout < 0 {
+		p.add("DB_STATEMENT_TIMEOUT can't be negative, 0 being unlimited")
+	}
+	return p.err()
+}
+
+// Validate checks the address of the metrics endpoint, when served apart, and the interval of the
+// database gauges
+func (c *MetricsConfig) Validate() error {
+	var p problems
+	checkAddress(&p, "METRICS_ADDRESS", c.Address, false)
+	if c.DBStatsInterval < 0 {
+		p.add("METRICS_DB_STATS_INTERVAL can't be negative, 0 turning the database gauges off")
+	}
+	return p.err()
+}
+
+// Validate checks the sampling ratio of the traces
+func (c *TracingConfig) Validate() error {
+	var p problems
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		p.add("OTEL_TRACES_SAMPLER_RATIO must be between 0 and 1, got %v", c.SampleRatio)
+	}
+	return p.err()
+}
+
+// Validate checks the rates and bursts aren't negative
+func (c *RateLimitConfig) Validate() error {
+	var p problems
+	if c.ReadRate < 0 || c.ReadBurst < 0 || c.WriteRate < 0 || c.WriteBurst < 0 {
+		p.add("RATE_LIMIT_READ_RATE, RATE_LIMIT_READ_BURST, RATE_LIMIT_WRITE_RATE and RATE_LIMIT_WRITE_BURST can't be negative")
+	}
+	return p.err()
+}
+
+// Validate checks the allowed origins are origins, subdomain patterns, or *
+func (c *CORSConfig) Validate() error {
+	var p problems
+	for _, origin := range c.AllowedOrigins {
+		origin = strings.TrimSpace(origin)
+		if origin == "*" {
+			continue
+		}
+		if _, err := compileOrigin(origin); err != nil {
+			p.add("CORS_ALLOWED_ORIGINS: %v", err)
+		}
+	}
+	return p.err()
+}
+
+// Validate checks the feature flags are refreshed at a positive interval
+func (c *FeaturesConfig) Validate() error {
+	var p problems
+	if c.RefreshInterval <= 0 {
+		p.add("FEATURE_FLAGS_REFRESH_INTERVAL must be positive, got %s", c.RefreshInterval)
+	}
+	return p.err()
+}
+
+// Validate checks the allowed networks are CIDR ranges or IPs
+func (c *AdminConfig) Validate() error {
+	var p problems
+	checkNetworks(&p, "ADMIN_ALLOWED_NETWORKS", c.AllowedNetworks)
+	return p.err()
+}
+
+// Validate checks the tolerance and retention of the inbound webhooks and the failures of the outbound ones
+func (c *WebhooksConfig) Validate() error {
+	var p problems
+	if c.Tolerance <= 0 {
+		p.add("WEBHOOK_TOLERANCE must be positive")
+	}
+	if c.DeliveryRetention <= 0 {
+		p.add("WEBHOOK_DELIVERY_RETENTION must be positive")
+	}
+	if c.MaxFailures < 0 {
+		p.add("WEBHOOK_MAX_FAILURES can't be negative")
+	}
+	return p.err()
+}
+
+// Validate checks the retry policy, the queue and the links of the notifications
+func (c *NotificationsConfig) Validate() error {
+	var p problems
+	if c.RetryMaxAttempts < 1 {
+		p.add("NOTIFICATIONS_RETRY_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.RetryBaseDelay > c.RetryMaxDelay {
+		p.add("NOTIFICATIONS_RETRY_BASE_DELAY can't exceed NOTIFICATIONS_RETRY_MAX_DELAY")
+	}
+	if c.QueueSize < 1 || c.QueueWorkers < 1 {
+		p.add("NOTIFICATIONS_QUEUE_SIZE and NOTIFICATIONS_QUEUE_WORKERS must be at least 1")
+	}
+	if c.HistoryRetentionDays < 0 || c.UserHourlyCeiling < 0 || c.CoalesceWindow < 0 {
+		p.add("NOTIFICATIONS_HISTORY_RETENTION_DAYS, NOTIFICATIONS_USER_HOURLY_CEILING and NOTIFICATIONS_COALESCE_WINDOW can't be negative")
+	}
+	checkURL(&p, "NOTIFICATIONS_WEB_LINK_BASE", c.WebLinkBase, false)
+	return p.err()
+}
+
+// Validate checks the SMTP server, only when one is set
+func (c *EmailConfig) Validate() error {
+	var p problems
+	if c.Host == "" {
+		return nil
+	}
+	if c.Port < 1 || c.Port > 65535 {
+		p.add("SMTP_PORT must be a port, got %d", c.Port)
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		p.add("SMTP_FROM must be an email address, ex.: AppDoki <appdoki@cloudoki.com>")
+	}
+	return p.err()
+}
+
+// Validate checks the incoming webhook, only when one is set
+func (c *SlackConfig) Validate() error {
+	var p problems
+	checkURL(&p, "SLACK_WEBHOOK_URL", c.WebhookURL, false)
+	return p.err()
+}
+
+// Validate checks the schedule of the digest, only when enabled
+func (c *DigestConfig) Validate() error {
+	var p problems
+	if !c.Enabled {
+		return nil
+	}
+	if c.Frequency != "daily" && c.Frequency != "weekly" {
+		p.add("DIGEST_FREQUENCY must be daily or weekly, got %q", c.Frequency)
+	}
+	if c.Hour < 0 || c.Hour > 23 {
+		p.add("DIGEST_HOUR must be between 0 and 23, got %d", c.Hour)
+	}
+	return p.err()
+}
+
+// Validate checks the VAPID keys come in pairs, along with a subject, only when set
+func (c *WebPushConfig) Validate() error {
+	var p problems
+	if c.VAPIDPublicKey == "" && c.VAPIDPrivateKey == "" {
+		return nil
+	}
+	if c.VAPIDPublicKey == "" || c.VAPIDPrivateKey == "" {
+		p.add("both WEBPUSH_VAPID_PUBLIC_KEY and WEBPUSH_VAPID_PRIVATE_KEY must be set")
+	}
+	if !strings.HasPrefix(c.Subject, "mailto:") && !strings.HasPrefix(c.Subject, "https://") {
+		p.add("WEBPUSH_SUBJECT must be a mailto: or https: URL")
+	}
+	return p.err()
+}
+
+// Validate checks the level, the format and the output of the logs
+func (c *LoggingConfig) Validate() error {
+	var p problems
+	switch c.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		p.add("LOG_LEVEL must be debug, info, warn or error, got %q", c.Level)
+	}
+	if c.Format != "console" && c.Format != "json" {
+		p.add("LOG_FORMAT must be console or json, got %q", c.Format)
+	}
+	switch c.Output {
+	case "stdout", "stderr":
+	case "file":
+		if c.File == "" {
+			p.add("LOG_FILE is required by LOG_OUTPUT=file")
+		}
+		if c.FileMaxBytes <= 0 || c.FileMaxBackups < 0 {
+			p.add("LOG_FILE_MAX_BYTES must be positive and LOG_FILE_MAX_BACKUPS can't be negative")
+		}
+	default:
+		p.add("LOG_OUTPUT must be stdout, stderr or file, got %q", c.Output)
+	}
+	if c.DebugSampling < 1 {
+		p.add("LOG_DEBUG_SAMPLING must be 1, keeping every debug line, or more")
+	}
+	switch c.Queries {
+	case QueryLogOff, QueryLogSlow, QueryLogAll:
+	default:
+		p.add("LOG_QUERIES must be off, slow or all, got %q", c.Queries)
+	}
+	return p.err()
+}
+
+// checkAddress checks the value is a host:port address, the host being optional
+func checkAddress(p *problems, name string, value string, required bool) {
+	if value == "" {
+		if required {
+			p.add("%s is required", name)
+		}
+		return
+	}
+	_, port, err := net.SplitHostPort(value)
+	if err == nil {
+		var n int
+		if n, err = strconv.Atoi(port); err == nil && (n < 0 || n > 65535) {
+			err = errors.New("port out of range")
+		}
+	}
+	if err != nil {
+		p.add("%s must be a host:port address, got %q", name, value)
+	}
+}
+
+// checkURL checks the value is an absolute http or https URL
+func checkURL(p *problems, name string, value string, required bool) {
+	if value == "" {
+		if required {
+			p.add("%s is required", name)
+		}
+		return
+	}
+	if u, err := url.Parse(value); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		p.add("%s must be an absolute http or https URL, got %q", name, value)
+	}
+}
+
+// checkNetworks checks every value is a CIDR range or an IP
+func checkNetworks(p *problems, name string, values []string) {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(value); err != nil && net.ParseIP(value) == nil {
+			p.add("%s: %q is neither a CIDR range nor an IP", name, value)
+		}
+	}
+}
@@ -2,74 +2,172 @@ package main
 
 import (
 	"appdoki-be/app"
+	"appdoki-be/app/logging"
+	"appdoki-be/app/tracing"
 	"appdoki-be/config"
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	firebase "firebase.google.com/go/v4"
+	"flag"
+	"fmt"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
 	"google.golang.org/api/option"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 )
 
 func main() {
-	conf := config.NewConfig()
-	firebaseApp := prepareFirebaseApp(conf.AppConfig.GoogleServiceAccountKeyPath)
-	db := prepareDatabase(&conf.Database)
-	application := app.NewApplication(conf, db, firebaseApp)
-
-	srv := &http.Server{
-		Addr:         conf.Server.Address,
-		Handler:      application.Routes(),
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 10 * time.Second,
-		IdleTimeout:  15 * time.Second,
+	if err := run(); err != nil {
+		log.Fatalf("Server exited with error: %+v", err)
 	}
+	log.Info("Server exited gracefully")
+}
 
-	done := make(chan os.Signal, 1)
-	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+// run starts the application and blocks until it is shut down by SIGINT or SIGTERM
+func run() error {
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"),
+		"YAML or JSON configuration file, its values overridden by the environment variables")
+	printConfig := flag.Bool("print-config", false,
+		"print the effective configuration, the secrets redacted, along with the source of every value, and exit")
+	helpConfig := flag.Bool("help-config", false,
+		"list every setting with its variable, type, default and description, and exit")
+	flag.Parse()
+	if *printConfig {
+		return config.PrintConfig(os.Stdout, *configFile)
+	}
+	if *helpConfig {
+		return config.HelpConfig(os.Stdout)
+	}
+	if flag.Arg(0) == "migrate" {
+		return runMigrateCommand(flag.Args()[1:], *configFile, os.Stdout)
+	}
+	if flag.Arg(0) == "seed" {
+		return runSeedCommand(flag.Args()[1:], *configFile, os.Stdout)
+	}
 
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Listen: %s\n", err)
-		}
-	}()
-	log.Infof("Server started on %s", srv.Addr)
+	conf, warnings, err := config.NewConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	if err := conf.Validate(); err != nil {
+		return err
+	}
 
-	<-done
-	log.Info("Server stopped")
+	// the logger of every component, the standard one only logging the exit of the process
+	logger, closeLogs, err := logging.New(conf.Logging)
+	if err != nil {
+		return err
+	}
+	defer closeLogs()
+	for _, warning := range warnings {
+		logger.Warnf("ignoring configuration: %s", warning)
+	}
+	logger.Infof("configured for the %s environment", conf.Env)
+	if conf.OutboundTLS.InsecureSkipVerify {
+		logger.Warn("OUTBOUND_TLS_INSECURE_SKIP_VERIFY is set: the certificates of Google, FCM, Slack, Sentry and the " +
+			"webhook endpoints are NOT verified, anyone on the network can intercept the outbound calls")
+	}
+	transport, err := conf.OutboundTLS.Transport()
+	if err != nil {
+		return err
+	}
+	httpClients := logging.NewHTTPClients(transport)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownTracing, err := tracing.Setup(context.Background(), &conf.Tracing)
+	if err != nil {
+		return fmt.Errorf("error initializing tracing: %w", err)
+	}
 	defer func() {
-		cancel()
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Errorf("error shutting down tracing: %+v", err)
+		}
 	}()
 
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("Server shutdown failed: %+v", err)
+	// the notifier needs no credentials without FCM, ex.: in the noop mode
+	var firebaseApp *firebase.App
+	if conf.UsesNotifierChannel("fcm") {
+		firebaseApp = prepareFirebaseApp(&conf.AppConfig, httpClients, logger)
 	}
-	log.Info("Server exited gracefully")
+	db := prepareDatabase(conf, logger)
+	var replica *sqlx.DB
+	if conf.Database.ReplicaURI != "" {
+		replica = openDatabase(conf, conf.Database.ReplicaURI, "read replica", logger)
+	}
+	application := app.NewApplication(conf, logger, db, replica, firebaseApp, httpClients)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return application.Run(ctx)
 }
 
-func prepareDatabase(conf *config.DatabaseConfig) *sqlx.DB {
-	db, err := sqlx.Connect("postgres", conf.URI)
+// prepareDatabase connects to the database, then migrates it on a connection of its own, the long
+// migrations not being canceled by DB_STATEMENT_TIMEOUT
+func prepareDatabase(c *config.Config, logger *log.Logger) *sqlx.DB {
+	db := openDatabase(c, c.Database.URI, "database", logger)
+
+	migrationsDB, err := sql.Open("postgres", c.Database.URI)
 	if err != nil {
-		log.Fatalln(err)
+		logger.Fatalln(err)
 	}
+	defer migrationsDB.Close()
+	runMigrations(migrationsDB, &c.Database, logger)
 
-	runMigrations(db.DB, conf)
+	return db
+}
+
+// openDatabase connects to the database of uri, attempting it again for up to DB_CONNECT_TIMEOUT for
+// the database starting along with the API. The API isn't served, so isn't ready, meanwhile. The
+// statements are logged as set by LOG_QUERIES, and canceled after DB_STATEMENT_TIMEOUT.
+func openDatabase(c *config.Config, uri string, name string, logger *log.Logger) *sqlx.DB {
+	conf := &c.Database
+	sessionURI, err := conf.SessionURI(uri)
+	if err != nil {
+		logger.Fatalf("invalid URI of the %s: %v", name, err)
+	}
+	var connector driver.Connector
+	connector, err = pq.NewConnector(sessionURI)
+	if err != nil {
+		logger.Fatalf("invalid URI of the %s: %v", name, err)
+	}
+	if c.Logging.Queries != config.QueryLogOff {
+		connector = logging.LogQueries(connector, logger, c.SlowLog.QueryThreshold, c.Logging.Queries == config.QueryLogAll)
+		logger.Infof("logging the SQL statements of the %s: %s", name, c.Logging.Queries)
+	}
+	db := sqlx.NewDb(sql.OpenDB(connector), "postgres")
+	conf.ConfigurePool(db.DB)
+	logger.Infof("%s pool: %s", name, conf.PoolSummary())
 
+	err = conf.WaitForConnection(context.Background(), db, config.DatabaseConnectBaseDelay, config.DatabaseConnectMaxDelay, func(attempt int, err error) {
+		logger.WithError(err).Warnf("error connecting to the %s (attempt %d), retrying for up to %s", name, attempt, conf.ConnectTimeout)
+	})
+	if err != nil {
+		logger.Fatalf("%s: %v", name, err)
+	}
 	return db
 }
 
-func prepareFirebaseApp(keyPath string) *firebase.App {
-	opt := option.WithCredentialsFile(keyPath)
-	firebaseApp, err := firebase.NewApp(context.Background(), nil, opt)
+// prepareFirebaseApp initializes the Firebase app with an HTTP client of httpClients that propagates
+// request IDs to the Firebase services, the tokens being obtained through it as well
+func prepareFirebaseApp(conf *config.AppConfig, httpClients *logging.HTTPClients, logger *log.Logger) *firebase.App {
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClients.New(0))
+
+	mode := conf.FCMCredentialsMode()
+	creds, err := conf.FCMCredentials(ctx)
+	if err != nil {
+		logger.Fatalf("error finding the FCM credentials (%s mode): %+v", mode, err)
+	}
+	logger.Infof("FCM credentials found in the %s mode", mode)
+
+	opt := option.WithHTTPClient(oauth2.NewClient(ctx, creds.TokenSource))
+	firebaseApp, err := firebase.NewApp(ctx, &firebase.Config{ProjectID: creds.ProjectID}, opt)
 	if err != nil {
-		log.Fatalf("error initializing app: %+v", err)
+		logger.Fatalf("error initializing app: %+v", err)
 	}
 
 	return firebaseApp
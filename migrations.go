@@ -2,41 +2,106 @@ package main
 
 import (
 	"appdoki-be/config"
+	"appdoki-be/migrations"
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jmoiron/sqlx"
 	log "github.com/sirupsen/logrus"
+	"io"
 )
 
+// migrateUsage is how the migrate command is run
+const migrateUsage = "usage: appdoki migrate up|down|version"
+
 type migrationsLogger struct {
+	logger  *log.Logger
 	verbose bool
 }
 
 func (ml *migrationsLogger) Printf(format string, v ...interface{}) {
-	log.Debugf(format, v...)
+	ml.logger.Debugf(format, v...)
 }
 
 func (ml *migrationsLogger) Verbose() bool {
 	return ml.verbose
 }
 
-func runMigrations(db *sql.DB, conf *config.DatabaseConfig) {
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+// runMigrations migrates the schema up when DB_AUTO_MIGRATE is set, then checks it's at the version of
+// the last migration, the readiness failing until it is
+func runMigrations(db *sql.DB, conf *config.DatabaseConfig, logger *log.Logger) {
+	if conf.AutoMigrate {
+		m, err := migrations.New(db, conf.MigrationsDir)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		m.Log = &migrationsLogger{
+			logger:  logger,
+			verbose: conf.MigrationsLogVerbose,
+		}
+		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+			logger.Fatal(err)
+		}
+	}
+
+	if err := migrations.CheckSchema(context.Background(), db, conf.MigrationsDir); err != nil {
+		logger.Errorf("%v: the readiness fails until the schema is migrated", err)
+	}
+}
+
+// runMigrateCommand runs the migrations of the migrate command against the database of the
+// configuration, without serving the API: up to the last one, down by the last one, or the version
+// of the schema
+func runMigrateCommand(args []string, configFile string, w io.Writer) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+	conf, _, err := config.Load(configFile)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	if conf.Database.URI == "" {
+		return errors.New("DB_URI is required")
 	}
+	db, err := sqlx.Connect("postgres", conf.Database.URI)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
 
-	m, err := migrate.NewWithDatabaseInstance(conf.MigrationsDir, "postgres", driver)
+	m, err := migrations.New(db.DB, conf.Database.MigrationsDir)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
+	m.Log = &migrationsLogger{logger: log.StandardLogger(), verbose: conf.Database.MigrationsLogVerbose}
 
-	m.Log = &migrationsLogger{
-		verbose: conf.MigrationsLogVerbose,
+	switch args[0] {
+	case "up":
+		err = m.Up()
+	case "down":
+		err = m.Steps(-1)
+	case "version":
+	default:
+		return errors.New(migrateUsage)
+	}
+	if err != nil && err != migrate.ErrNoChange {
+		return err
 	}
 
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		log.Fatal(err)
+	latest, err := migrations.Latest(conf.Database.MigrationsDir)
+	if err != nil {
+		return err
+	}
+	version, dirty, err := migrations.Version(context.Background(), db.DB)
+	if err != nil {
+		return err
+	}
+	state := ""
+	if dirty {
+		state = " (dirty)"
 	}
+	_, err = fmt.Fprintf(w, "schema version %d%s, last migration %d\n", version, state, latest)
+	return err
 }
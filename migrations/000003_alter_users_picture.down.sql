@@ -1 +1,2 @@
-ALTER TABLE users ALTER COLUMN picture SET NULL;
\ No newline at end of file
+ALTER TABLE users ALTER COLUMN picture DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN picture DROP NOT NULL;
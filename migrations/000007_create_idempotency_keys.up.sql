@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id       TEXT NOT NULL REFERENCES users(id),
+    key           VARCHAR(255) NOT NULL,
+    request_hash  CHAR(64) NOT NULL,
+    status        INT NULL,
+    content_type  VARCHAR(255) NULL,
+    body          BYTEA NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT now(),
+    expires_at    TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
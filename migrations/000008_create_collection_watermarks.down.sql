@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS beer_transfers_watermark ON beer_transfers;
+DROP TRIGGER IF EXISTS users_watermark ON users;
+DROP FUNCTION IF EXISTS touch_collection_watermark();
+DROP TABLE IF EXISTS collection_watermarks;
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS collection_watermarks (
+    collection  VARCHAR(64) PRIMARY KEY,
+    modified_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO collection_watermarks (collection) VALUES
+    ('users'),
+    ('beer_transfers')
+ON CONFLICT DO NOTHING;
+
+CREATE OR REPLACE FUNCTION touch_collection_watermark() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE collection_watermarks SET modified_at = clock_timestamp() WHERE collection = TG_TABLE_NAME;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS users_watermark ON users;
+CREATE TRIGGER users_watermark
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON users
+    FOR EACH STATEMENT EXECUTE PROCEDURE touch_collection_watermark();
+
+DROP TRIGGER IF EXISTS beer_transfers_watermark ON beer_transfers;
+CREATE TRIGGER beer_transfers_watermark
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON beer_transfers
+    FOR EACH STATEMENT EXECUTE PROCEDURE touch_collection_watermark();
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    id          SERIAL PRIMARY KEY,
+    actor_id    TEXT NOT NULL,
+    action      VARCHAR(64) NOT NULL,
+    target_id   TEXT NOT NULL,
+    details     JSONB NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_target_id_idx ON audit_log (target_id);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS device_tokens (
+    token         TEXT PRIMARY KEY,
+    user_id       TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform      VARCHAR(16) NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_seen_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS device_tokens_user_id_idx ON device_tokens (user_id);
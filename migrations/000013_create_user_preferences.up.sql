@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_preferences (
+    user_id               TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    notify_beer_received  BOOLEAN NOT NULL DEFAULT TRUE,
+    notify_new_user       BOOLEAN NOT NULL DEFAULT TRUE,
+    notify_digest         BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS notifications_user_id_unread_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS notifications_user_id_unread_idx ON notifications (user_id) WHERE read_at IS NULL;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS job_runs (
+    name          VARCHAR(64) NOT NULL,
+    scheduled_at  TIMESTAMPTZ NOT NULL,
+    started_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at   TIMESTAMPTZ,
+    error         TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (name, scheduled_at)
+);
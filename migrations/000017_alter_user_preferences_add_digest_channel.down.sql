@@ -0,0 +1 @@
+ALTER TABLE user_preferences DROP COLUMN IF EXISTS digest_channel;
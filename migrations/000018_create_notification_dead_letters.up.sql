@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS notification_dead_letters (
+    id          BIGSERIAL PRIMARY KEY,
+    channel     VARCHAR(16) NOT NULL,
+    op          VARCHAR(32) NOT NULL,
+    target      TEXT NOT NULL DEFAULT '',
+    job         JSONB NOT NULL,
+    error       TEXT NOT NULL DEFAULT '',
+    attempts    INT NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1 @@
+ALTER TABLE user_preferences ADD COLUMN IF NOT EXISTS locale VARCHAR(8) NOT NULL DEFAULT 'en';
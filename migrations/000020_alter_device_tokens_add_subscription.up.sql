@@ -0,0 +1 @@
+ALTER TABLE device_tokens ADD COLUMN IF NOT EXISTS subscription JSONB;
@@ -0,0 +1,3 @@
+ALTER TABLE user_preferences DROP COLUMN IF EXISTS timezone;
+ALTER TABLE user_preferences DROP COLUMN IF EXISTS quiet_hours_end;
+ALTER TABLE user_preferences DROP COLUMN IF EXISTS quiet_hours_start;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS deferred_notifications (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    job         JSONB NOT NULL,
+    send_after  TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS deferred_notifications_send_after_idx ON deferred_notifications (send_after);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS announcements (
+    id          BIGSERIAL PRIMARY KEY,
+    title       TEXT NOT NULL,
+    body        TEXT NOT NULL DEFAULT '',
+    deep_link   TEXT NOT NULL DEFAULT '',
+    audience    VARCHAR(16) NOT NULL,
+    role        VARCHAR(16) NOT NULL DEFAULT '',
+    user_ids    TEXT[] NOT NULL DEFAULT '{}',
+    channel     VARCHAR(16) NOT NULL,
+    status      VARCHAR(16) NOT NULL DEFAULT 'sending',
+    targeted    INT NOT NULL DEFAULT 0,
+    delivered   INT,
+    created_by  TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at     TIMESTAMPTZ
+);
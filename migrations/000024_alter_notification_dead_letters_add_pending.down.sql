@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS notification_dead_letters_pending_idx;
+
+ALTER TABLE notification_dead_letters DROP COLUMN IF EXISTS pending;
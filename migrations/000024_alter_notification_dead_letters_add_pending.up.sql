@@ -0,0 +1,3 @@
+ALTER TABLE notification_dead_letters ADD COLUMN IF NOT EXISTS pending BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS notification_dead_letters_pending_idx ON notification_dead_letters (id) WHERE pending;
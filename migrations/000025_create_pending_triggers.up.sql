@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS pending_triggers (
+    user_id     TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, name)
+);
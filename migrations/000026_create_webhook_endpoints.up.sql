@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id                    BIGSERIAL PRIMARY KEY,
+    url                   TEXT NOT NULL,
+    secret                TEXT NOT NULL,
+    events                TEXT[] NOT NULL,
+    enabled               BOOLEAN NOT NULL DEFAULT true,
+    consecutive_failures  INT NOT NULL DEFAULT 0,
+    disabled_at           TIMESTAMPTZ,
+    created_by            TEXT REFERENCES users(id) ON DELETE SET NULL,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id             BIGSERIAL PRIMARY KEY,
+    endpoint_id    BIGINT NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    event_id       TEXT NOT NULL,
+    event          VARCHAR(64) NOT NULL,
+    payload        JSONB NOT NULL,
+    attempt        INT NOT NULL,
+    status         VARCHAR(16) NOT NULL,
+    response_code  INT,
+    error          TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_endpoint_id_idx ON webhook_deliveries (endpoint_id, id DESC);
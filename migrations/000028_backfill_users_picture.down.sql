@@ -0,0 +1,2 @@
+-- The pictures backfilled can't be told apart from the empty ones, and the default and NOT NULL
+-- constraint are those of 000003, so there's nothing to undo.
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS webhook_receipts (
+    source       VARCHAR(64) NOT NULL,
+    delivery_id  VARCHAR(255) NOT NULL,
+    received_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at   TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (source, delivery_id)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_receipts_expires_at_idx ON webhook_receipts (expires_at);
@@ -0,0 +1,112 @@
+// Package migrations embeds the SQL migrations of the database schema in the binary, and runs them
+// with golang-migrate
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/httpfs"
+	"net/http"
+	"os"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// ErrSchemaNotMigrated is wrapped by the errors of CheckSchema
+var ErrSchemaNotMigrated = errors.New("the database schema isn't migrated")
+
+// Source returns the migrations of sourceURL (ex.: file://migrations), the ones embedded in the
+// binary when empty
+func Source(sourceURL string) (source.Driver, error) {
+	if sourceURL == "" {
+		return httpfs.New(http.FS(files), "/")
+	}
+	return source.Open(sourceURL)
+}
+
+// New returns the migrations of sourceURL, the embedded ones when empty, run against db
+func New(db *sql.DB, sourceURL string) (*migrate.Migrate, error) {
+	src, err := Source(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewWithInstance("migrations", src, "postgres", driver)
+}
+
+// Latest returns the version of the last migration of sourceURL, the embedded ones when empty
+func Latest(sourceURL string) (uint, error) {
+	src, err := Source(sourceURL)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		version = next
+	}
+}
+
+// Version returns the version of the schema of db, and whether the migration to it failed half
+// way, 0 when no migration ran yet. Unlike the one of golang-migrate, it never creates the table of
+// the versions.
+func Version(ctx context.Context, db *sql.DB) (uint, bool, error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil || !exists {
+		return 0, false, err
+	}
+
+	var version int64
+	var dirty bool
+	err := db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	return uint(version), dirty, err
+}
+
+// CheckSchema checks the schema of db is at the version of the last migration of sourceURL, the
+// embedded ones when empty, for the API not to be served by a schema it doesn't expect
+func CheckSchema(ctx context.Context, db *sql.DB, sourceURL string) error {
+	expected, err := Latest(sourceURL)
+	if err != nil {
+		return fmt.Errorf("error reading the migrations: %w", err)
+	}
+	actual, dirty, err := Version(ctx, db)
+	if err != nil {
+		return fmt.Errorf("error reading the version of the database schema: %w", err)
+	}
+	return checkVersion(actual, dirty, expected)
+}
+
+func checkVersion(actual uint, dirty bool, expected uint) error {
+	switch {
+	case dirty:
+		return fmt.Errorf("%w: the migration to version %d failed half way, fix the schema and force its version", ErrSchemaNotMigrated, actual)
+	case actual < expected:
+		return fmt.Errorf("%w: expected version %d, got %d, run the migrations with `appdoki migrate up`", ErrSchemaNotMigrated, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// The tests are in a package of their own, testsupport migrating its databases with this one
+package migrations_test
+
+import (
+	"appdoki-be/app/testsupport"
+	"appdoki-be/migrations"
+	"context"
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("expect the last embedded migration to be the last one of the directory", func(t *testing.T) {
+		files, err := ioutil.ReadDir(".")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var last uint64
+		for _, file := range files {
+			if strings.HasSuffix(file.Name(), ".up.sql") {
+				version, err := strconv.ParseUint(strings.SplitN(file.Name(), "_", 2)[0], 10, 64)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if version > last {
+					last = version
+				}
+			}
+		}
+
+		latest, err := migrations.Latest("")
+		if err != nil || uint64(latest) != last {
+			t.Fatalf("expected the last migration to be %d, got %d (%v)", last, latest, err)
+		}
+		if latest, err := migrations.Latest("file://."); err != nil || uint64(latest) != last {
+			t.Fatalf("expected the directory to be read alike, got %d (%v)", latest, err)
+		}
+	})
+
+	t.Run("expect every migration to have its rollback", func(t *testing.T) {
+		files, err := ioutil.ReadDir(".")
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := map[string]bool{}
+		for _, file := range files {
+			names[file.Name()] = true
+		}
+		for name := range names {
+			if strings.HasSuffix(name, ".up.sql") && !names[strings.TrimSuffix(name, ".up.sql")+".down.sql"] {
+				t.Errorf("expected %s to have a .down.sql", name)
+			}
+		}
+	})
+
+	t.Run("expect the migrated schema to be at the last version", func(t *testing.T) {
+		db := testsupport.NewDB(t)
+
+		if err := migrations.CheckSchema(ctx, db.DB, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("expect every migration to be rolled back and applied again", func(t *testing.T) {
+		db := testsupport.NewDB(t)
+		m, err := migrations.New(db.DB, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := m.Down(); err != nil {
+			t.Fatal(err)
+		}
+		if version, _, err := migrations.Version(ctx, db.DB); err != nil || version != 0 {
+			t.Fatalf("expected every migration to be rolled back, got version %d (%v)", version, err)
+		}
+		if err := m.Up(); err != nil {
+			t.Fatal(err)
+		}
+		if err := migrations.CheckSchema(ctx, db.DB, ""); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("expect the NULL pictures to be backfilled empty, the column required again", func(t *testing.T) {
+		db := testsupport.NewDB(t)
+		m, err := migrations.New(db.DB, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Migrate(27); err != nil {
+			t.Fatal(err)
+		}
+		// the rows written while the pictures weren't required, and the ones written with a picture
+		for _, stmt := range []string{
+			`ALTER TABLE users ALTER COLUMN picture DROP NOT NULL`,
+			`INSERT INTO users (id, name, email, picture) VALUES ('1', 'Ana', 'ana@cloudoki.com', NULL)`,
+			`INSERT INTO users (id, name, email, picture) VALUES ('2', 'Rui', 'rui@cloudoki.com', 'https://example.com/rui.png')`,
+		} {
+			if _, err := db.Exec(stmt); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := m.Up(); err != nil {
+			t.Fatal(err)
+		}
+
+		var pictures []string
+		if err := db.Select(&pictures, `SELECT picture FROM users ORDER BY id`); err != nil {
+			t.Fatal(err)
+		}
+		if len(pictures) != 2 || pictures[0] != "" || pictures[1] != "https://example.com/rui.png" {
+			t.Fatalf("expected the NULL picture only to be backfilled, got %q", pictures)
+		}
+		if _, err := db.Exec(`INSERT INTO users (id, name, email, picture) VALUES ('3', 'Eva', 'eva@cloudoki.com', NULL)`); err == nil {
+			t.Fatal("expected a NULL picture to be rejected")
+		}
+	})
+
+	t.Run("expect a schema behind the migrations to be reported with both versions", func(t *testing.T) {
+		db := testsupport.NewDB(t)
+		m, err := migrations.New(db.DB, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		latest, _ := migrations.Latest("")
+		if err := m.Steps(-1); err != nil {
+			t.Fatal(err)
+		}
+		actual, _, _ := migrations.Version(ctx, db.DB)
+
+		err = migrations.CheckSchema(ctx, db.DB, "")
+
+		expected := "expected version " + strconv.Itoa(int(latest)) + ", got " + strconv.Itoa(int(actual))
+		if !errors.Is(err, migrations.ErrSchemaNotMigrated) || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected %q, got %v", expected, err)
+		}
+	})
+
+	t.Run("expect a dirty schema to be reported", func(t *testing.T) {
+		db := testsupport.NewDB(t)
+		if _, err := db.Exec(`UPDATE schema_migrations SET dirty = true`); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := migrations.CheckSchema(ctx, db.DB, ""); !errors.Is(err, migrations.ErrSchemaNotMigrated) || !strings.Contains(err.Error(), "failed half way") {
+			t.Fatalf("expected the dirty schema to be reported, got %v", err)
+		}
+	})
+}
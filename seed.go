@@ -0,0 +1,71 @@
+package main
+
+import (
+	"appdoki-be/app/seed"
+	"appdoki-be/config"
+	"appdoki-be/migrations"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"io"
+	"strings"
+)
+
+// runSeedCommand fills the database of the configuration with the data set of the seed package,
+// wiping its users first with --wipe, then prints the users to sign in as. It refuses any profile
+// but dev, not to fill a real database with fake users.
+func runSeedCommand(args []string, configFile string, w io.Writer) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.SetOutput(w)
+	wipe := flags.Bool("wipe", false, "delete the users, their beers, devices and notifications first")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return errors.New("usage: appdoki seed [--wipe]")
+	}
+
+	conf, _, err := config.Load(configFile)
+	if err != nil {
+		return err
+	}
+	if conf.Env != config.EnvDev {
+		return fmt.Errorf("the seed only runs with APP_ENV=%s, got %s", config.EnvDev, conf.Env)
+	}
+	if conf.Database.URI == "" {
+		return errors.New("DB_URI is required")
+	}
+	db, err := sqlx.Connect("postgres", conf.Database.URI)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := migrations.CheckSchema(ctx, db.DB, conf.Database.MigrationsDir); err != nil {
+		return err
+	}
+	res, err := seed.NewSeeder(db).Run(ctx, *wipe)
+	if err != nil {
+		return err
+	}
+	return printSeed(w, res)
+}
+
+func printSeed(w io.Writer, res *seed.Result) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "created %d users, %d devices and %d beer transfers\n\n", res.CreatedUsers, res.Devices, res.Transfers)
+	fmt.Fprintf(&b, "%-4s %-16s %-22s %-6s %s\n", "ID", "NAME", "EMAIL", "ROLE", "DEVICES")
+	for i, user := range res.Users {
+		var devices []string
+		for _, device := range seed.Users[i].Devices {
+			devices = append(devices, device.Platform+":"+device.Token)
+		}
+		fmt.Fprintf(&b, "%-4s %-16s %-22s %-6s %s\n", user.ID, user.Name, user.Email, user.Role, strings.Join(devices, ", "))
+	}
+	fmt.Fprintf(&b, "\nwith TEST_MODE=true, every request is authenticated as user %s\n", seed.Users[0].ID)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
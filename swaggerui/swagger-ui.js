@@ -1,9 +0,0 @@
-!function(e,t){"object"==typeof exports&&"object"==typeof module?module.exports=t():"function"==typeof define&&define.amd?define([],t):"object"==typeof exports?exports.SwaggerUICore=t():e.SwaggerUICore=t()}(window,(function(){return function(e){var t={};function n(r){if(t[r])return t[r].exports;var o=t[r]={i:r,l:!1,exports:{}};return e[r].call(o.exports,o,o.exports,n),o.l=!0,o.exports}return n.m=e,n.c=t,n.d=function(e,t,r){n.o(e,t)||Object.defineProperty(e,t,{enumerable:!0,get:r})},n.r=function(e){"undefined"!=typeof Symbol&&Symbol.toStringTag&&Object.defineProperty(e,Symbol.toStringTag,{value:"Module"}),Object.defineProperty(e,"__esModule",{value:!0})},n.t=function(e,t){if(1&t&&(e=n(e)),8&t)return e;if(4&t&&"object"==typeof e&&e&&e.__esModule)return e;var r=Object.create(null);if(n.r(r),Object.defineProperty(r,"default",{enumerable:!0,value:e}),2&t&&"string"!=typeof e)for(var o in e)n.d(r,o,function(t){return e[t]}.bind(null,o));return r},n.n=function(e){var t=e&&e.__esModule?function(){return e.default}:function(){return e};return n.d(t,"a",t),t},n.o=function(e,t){return Object.prototype.hasOwnProperty.call(e,t)},n.p="/dist",n(n.s=385)}([function(e,t){e.exports=require("react")},function(e,t){e.exports=require("immutable")},function(e,t,n){var r=n(90);e.exports=function(e,t,n){return t in e?r(e,t,{value:n,enumerable:!0,configurable:!0,writable:!0}):e[t]=n,e}},function(e,t,n){var r=n(90);function o(e,t){for(var n=0;n<t.length;n++){var o=t[n];o.enumerable=o.enumerable||!1,o.configurable=!0,"value"in o&&(o.writable=!0),r(e,o.key,o)}}e.exports=function(e,t,n){return t&&o(e.prototype,t),n&&o(e,n),e}},function(e,t){e.exports=function(e,t){if(!(e instanceof t))throw new TypeError("Cannot call a class as a function")}},function(e,t,n){"use strict";(function(e){n.d(t,"t",(function(){return V})),n.d(t,"A",(function(){return z})),n.d(t,"i",(function(){return F})),n.d(t,"w",(function(){return J})),n.d(t,"r",(function(){return W})),n.d(t,"u",(function(){return H})),n.d(t,"s",(function(){return $})),n.d(t,"p",(function(){return Y})),n.d(t,"v",(function(){return K})),n.d(t,"y",(function(){return G})),n.d(t,"z",(function(){return Z})),n.d(t,"K",(function(){return X})),n.d(t,"f",(function(){return Q})),n.d(t,"n",(function(){return ee})),n.d(t,"h",(function(){return te})),n.d(t,"E",(function(){return ne})),n.d(t,"L",(function(){return me})),n.d(t,"o",(function(){return ve})),n.d(t,"D",(function(){return ge})),n.d(t,"a",(function(){return ye})),n.d(t,"I",(function(){return be})),n.d(t,"b",(function(){return Ee})),n.d(t,"H",(function(){return xe})),n.d(t,"G",(function(){return Se})),n.d(t,"F",(function(){return we})),n.d(t,"k",(function(){return je})),n.d(t,"d",(function(){return _e})),n.d(t,"g",(function(){return Oe})),n.d(t,"m",(function(){return Ce})),n.d(t,"l",(function(){return Ae})),n.d(t,"e",(function(){return ke})),n.d(t,"J",(function(){return Pe})),n.d(t,"x",(function(){return Ie})),n.d(t,"B",(function(){return Te})),n.d(t,"C",(function(){return Ne})),n.d(t,"j",(function(){return Re})),n.d(t,"c",(function(){return Me})),n.d(t,"q",(function(){return qe}));var r=n(22),o=n.n(r),a=(n(12),n(16)),i=n.n(a),c=n(11),s=n.n(c),u=n(31),l=n.n(u),p=n(10),f=n.n(p),d=n(15),h=n.n(d),m=n(1),v=n.n(m),g=n(352),y=n(353),b=n.n(y),E=n(204),x=n.n(E),S=n(205),w=n.n(S),j=n(144),_=n.n(j),O=(n(354),n(74)),C=n.n(O),A=n(46),k=n.n(A),P=n(93),I=n(17),T=n.n(I),N=n(356),R=n.n(N),M=n(95),D=n(357),q=n.n(D),L=n(358),B=n.n(L),U=function(e){return v.a.Iterable.isIterable(e)};function V(e){try{var t=JSON.parse(e);if(t&&"object"===h()(t))return t}catch(e){}return!1}function z(e){return H(e)?U(e)?e.toJS():e:{}}function F(e){if(U(e))return e;if(e instanceof T.a.File)return e;if(!H(e))return e;if(f()(e))return v.a.Seq(e).map(F).toList();if(k()(e.entries)){var t=function(e){if(!k()(e.entries))return e;var t,n={},r={},o=l()(e.entries());try{for(o.s();!(t=o.n()).done;){var a=t.value;if(n[a[0]]||r[a[0]]&&r[a[0]].containsMultiple){if(!r[a[0]]){r[a[0]]={containsMultiple:!0,length:1};var i="".concat(a[0]).concat("_**[]").concat(r[a[0]].length);n[i]=n[a[0]],delete n[a[0]]}r[a[0]].length+=1;var c="".concat(a[0]).concat("_**[]").concat(r[a[0]].length);n[c]=a[1]}else n[a[0]]=a[1]}}catch(e){o.e(e)}finally{o.f()}return n}(e);return v.a.OrderedMap(t).map(F)}return v.a.OrderedMap(e).map(F)}function J(e){return f()(e)?e:[e]}function W(e){return"function"==typeof e}function H(e){return!!e&&"object"===h()(e)}function $(e){return"function"==typeof e}function Y(e){return f()(e)}var K=w.a;function G(e,t){return s()(e).reduce((function(n,r){return n[r]=t(e[r],r),n}),{})}function Z(e,t){return s()(e).reduce((function(n,r){var o=t(e[r],r);return o&&"object"===h()(o)&&i()(n,o),n}),{})}function X(e){return function(t){t.dispatch,t.getState;return function(t){return function(n){return"function"==typeof n?n(e()):t(n)}}}}function Q(e){var t=e.keySeq();return t.contains("default")?"default":t.filter((function(e){return"2"===(e+"")[0]})).sort().first()}function ee(e,t){if(!v.a.Iterable.isIterable(e))return v.a.List();var n=e.getIn(f()(t)?t:[t]);return v.a.List.isList(n)?n:v.a.List()}function te(e){var t;if([/filename\*=[^']+'\w*'"([^"]+)";?/i,/filename\*=[^']+'\w*'([^;]+);?/i,/filename="([^;]*);?"/i,/filename=([^;]*);?/i].some((function(n){return null!==(t=n.exec(e))})),null!==t&&t.length>1)try{return decodeURIComponent(t[1])}catch(e){console.error(e)}return null}function ne(e){return t=e.replace(/\.[^./]*$/,""),x()(b()(t));var t}var re=function(e,t){if(e>t)return"Value must be less than ".concat(t)},oe=function(e,t){if(e<t)return"Value must be greater than ".concat(t)},ae=function(e){if(!/^-?\d+(\.?\d+)?$/.test(e))return"Value must be a number"},ie=function(e){if(!/^-?\d+$/.test(e))return"Value must be an integer"},ce=function(e){if(e&&!(e instanceof T.a.File))return"Value must be a file"},se=function(e){if("true"!==e&&"false"!==e&&!0!==e&&!1!==e)return"Value must be a boolean"},ue=function(e){if(e&&"string"!=typeof e)return"Value must be a string"},le=function(e){if(isNaN(Date.parse(e)))return"Value must be a DateTime"},pe=function(e){if(e=e.toString().toLowerCase(),!/^[{(]?[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}[)}]?$/.test(e))return"Value must be a Guid"},fe=function(e,t){if(e.length>t)return"Value must be no longer than ".concat(t," character").concat(1!==t?"s":"")},de=function(e,t){if(e.length<t)return"Value must be at least ".concat(t," character").concat(1!==t?"s":"")},he=function(e,t){if(!new RegExp(t).test(e))return"Value must follow pattern "+t},me=function(e,t){var n=arguments.length>2&&void 0!==arguments[2]?arguments[2]:{},r=n.isOAS3,o=void 0!==r&&r,a=n.bypassRequiredCheck,i=void 0!==a&&a,c=[],s=e.get("required"),u=Object(M.a)(e,{isOAS3:o}),l=u.schema,p=u.parameterContentMediaType;if(!l)return c;var d=l.get("required"),m=l.get("maximum"),g=l.get("minimum"),y=l.get("type"),b=l.get("format"),E=l.get("maxLength"),x=l.get("minLength"),S=l.get("pattern");if(y&&(s||d||t)){var w="string"===y&&t,j="array"===y&&f()(t)&&t.length,_="array"===y&&v.a.List.isList(t)&&t.count(),O="array"===y&&"string"==typeof t&&t,C="file"===y&&t instanceof T.a.File,A="boolean"===y&&(t||!1===t),k="number"===y&&(t||0===t),P="integer"===y&&(t||0===t),I="object"===y&&"object"===h()(t)&&null!==t,N="object"===y&&"string"==typeof t&&t,R=[w,j,_,O,C,A,k,P,I,N],D=R.some((function(e){return!!e}));if((s||d)&&!D&&!i)return c.push("Required field is not provided"),c;if("object"===y&&"string"==typeof t&&(null===p||"application/json"===p))try{JSON.parse(t)}catch(e){return c.push("Parameter string value must be valid JSON"),c}if(S){var q=he(t,S);q&&c.push(q)}if(E||0===E){var L=fe(t,E);L&&c.push(L)}if(x){var B=de(t,x);B&&c.push(B)}if(m||0===m){var U=re(t,m);U&&c.push(U)}if(g||0===g){var V=oe(t,g);V&&c.push(V)}if("string"===y){var z;if(!(z="date-time"===b?le(t):"uuid"===b?pe(t):ue(t)))return c;c.push(z)}else if("boolean"===y){var F=se(t);if(!F)return c;c.push(F)}else if("number"===y){var J=ae(t);if(!J)return c;c.push(J)}else if("integer"===y){var W=ie(t);if(!W)return c;c.push(W)}else if("array"===y){var H;if(!_||!t.count())return c;H=l.getIn(["items","type"]),t.forEach((function(e,t){var n;"number"===H?n=ae(e):"integer"===H?n=ie(e):"string"===H&&(n=ue(e)),n&&c.push({index:t,error:n})}))}else if("file"===y){var $=ce(t);if(!$)return c;c.push($)}}return c},ve=function(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:"",n=arguments.length>2&&void 0!==arguments[2]?arguments[2]:{};if(/xml/.test(t)){if(!e.xml||!e.xml.name){if(e.xml=e.xml||{},!e.$$ref)return e.type||e.items||e.properties||e.additionalProperties?'<?xml version="1.0" encoding="UTF-8"?>\n\x3c!-- XML example cannot be generated; root element name is undefined --\x3e':null;var r=e.$$ref.match(/\S*\/(\S+)$/);e.xml.name=r[1]}return Object(P.memoizedCreateXMLExample)(e,n)}var a=Object(P.memoizedSampleFromSchema)(e,n);return"object"===h()(a)?o()(a,null,2):a},ge=function(){var e={},t=T.a.location.search;if(!t)return{};if(""!=t){var n=t.substr(1).split("&");for(var r in n)n.hasOwnProperty(r)&&(r=n[r].split("="),e[decodeURIComponent(r[0])]=r[1]&&decodeURIComponent(r[1])||"")}return e},ye=function(t){return(t instanceof e?t:new e(t.toString(),"utf-8")).toString("base64")},be={operationsSorter:{alpha:function(e,t){return e.get("path").localeCompare(t.get("path"))},method:function(e,t){return e.get("method").localeCompare(t.get("method"))}},tagsSorter:{alpha:function(e,t){return e.localeCompare(t)}}},Ee=function(e){var t=[];for(var n in e){var r=e[n];void 0!==r&&""!==r&&t.push([n,"=",encodeURIComponent(r).replace(/%20/g,"+")].join(""))}return t.join("&")},xe=function(e,t,n){return!!_()(n,(function(n){return C()(e[n],t[n])}))};function Se(e){return"string"!=typeof e||""===e?"":Object(g.sanitizeUrl)(e)}function we(e){return!(!e||e.indexOf("localhost")>=0||e.indexOf("127.0.0.1")>=0||"none"===e)}function je(e){if(!v.a.OrderedMap.isOrderedMap(e))return null;if(!e.size)return null;var t=e.find((function(e,t){return t.startsWith("2")&&s()(e.get("content")||{}).length>0})),n=e.get("default")||v.a.OrderedMap(),r=(n.get("content")||v.a.OrderedMap()).keySeq().toJS().length?n:null;return t||r}var _e=function(e){return"string"==typeof e||e instanceof String?e.trim().replace(/\s/g,"%20"):""},Oe=function(e){return R()(_e(e).replace(/%20/g,"_"))},Ce=function(e){return e.filter((function(e,t){return/^x-/.test(t)}))},Ae=function(e){return e.filter((function(e,t){return/^pattern|maxLength|minLength|maximum|minimum/.test(t)}))};function ke(e,t){var n=arguments.length>2&&void 0!==arguments[2]?arguments[2]:function(){return!0};if("object"!==h()(e)||f()(e)||null===e||!t)return e;var r=i()({},e);return s()(r).forEach((function(e){e===t&&n(r[e],e)?delete r[e]:r[e]=ke(r[e],t,n)})),r}function Pe(e){if("string"==typeof e)return e;if(e&&e.toJS&&(e=e.toJS()),"object"===h()(e)&&null!==e)try{return o()(e,null,2)}catch(t){return String(e)}return null==e?"":e.toString()}function Ie(e){return"number"==typeof e?e.toString():e}function Te(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{},n=t.returnAll,r=void 0!==n&&n,o=t.allowHashes,a=void 0===o||o;if(!v.a.Map.isMap(e))throw new Error("paramToIdentifier: received a non-Im.Map parameter as input");var i=e.get("name"),c=e.get("in"),s=[];return e&&e.hashCode&&c&&i&&a&&s.push("".concat(c,".").concat(i,".hash-").concat(e.hashCode())),c&&i&&s.push("".concat(c,".").concat(i)),s.push(i),r?s:s[0]||""}function Ne(e,t){return Te(e,{returnAll:!0}).map((function(e){return t[e]})).filter((function(e){return void 0!==e}))[0]}function Re(){return De(q()(32).toString("base64"))}function Me(e){return De(B()("sha256").update(e).digest("base64"))}function De(e){return e.replace(/\+/g,"-").replace(/\//g,"_").replace(/=/g,"")}var qe=function(e){return!e||!(!U(e)||!e.isEmpty())}}).call(this,n(470).Buffer)},function(e,t,n){var r=n(145),o=n(592);e.exports=function(e,t){if("function"!=typeof t&&null!==t)throw new TypeError("Super expression must either be null or a function");e.prototype=r(t&&t.prototype,{constructor:{value:e,writable:!0,configurable:!0}}),t&&o(e,t)}},function(e,t,n){var r=n(339),o=n(142),a=n(602),i=n(603);e.exports=function(e){var t=a();return function(){var n,a=o(e);if(t){var c=o(this).constructor;n=r(a,arguments,c)}else n=a.apply(this,arguments);return i(this,n)}}},function(e,t){e.exports=function(e){if(void 0===e)throw new ReferenceError("this hasn't been initialised - super() hasn't been called");return e}},function(e,t){e.exports=require("prop-types")},function(e,t,n){e.exports=n(464)},function(e,t,n){e.exports=n(450)},function(e,t,n){var r=n(293),o=n(474),a=n(128),i=n(301);e.exports=function(e,t){return r(e)||o(e,t)||a(e,t)||i()}},function(e,t,n){var r=n(567),o=n(326),a=n(128),i=n(568);e.exports=function(e){return r(e)||o(e)||a(e)||i()}},function(e,t){e.exports=require("reselect")},function(e,t,n){var r=n(286),o=n(124);function a(t){return e.exports=a="function"==typeof o&&"symbol"==typeof r?function(e){return typeof e}:function(e){return e&&"function"==typeof o&&e.constructor===o&&e!==o.prototype?"symbol":typeof e},a(t)}e.exports=a},function(e,t,n){e.exports=n(468)},function(e,t){e.exports=function(){var e={location:{},history:{},open:function(){},close:function(){},File:function(){}};if("undefined"==typeof window)return e;try{e=window;for(var t=0,n=["File","Blob","FormData"];t<n.length;t++){var r=n[t];r in window&&(e[r]=window[r])}}catch(e){console.error(e)}return e}()},function(e,t,n){var r=n(90),o=n(569),a=n(572),i=n(327),c=n(328),s=n(11),u=n(2);function l(e,t){var n=s(e);if(c){var r=c(e);t&&(r=r.filter((function(t){return i(e,t).enumerable}))),n.push.apply(n,r)}return n}e.exports=function(e){for(var t=1;t<arguments.length;t++){var n=null!=arguments[t]?arguments[t]:{};t%2?l(Object(n),!0).forEach((function(t){u(e,t,n[t])})):a?o(e,a(n)):l(Object(n)).forEach((function(t){r(e,t,i(n,t))}))}return e}},function(e,t,n){var r=n(16);function o(){return e.exports=o=r||function(e){for(var t=1;t<arguments.length;t++){var n=arguments[t];for(var r in n)Object.prototype.hasOwnProperty.call(n,r)&&(e[r]=n[r])}return e},o.apply(this,arguments)}e.exports=o},function(e,t){e.exports=require("react-immutable-proptypes")},function(e,t){var n=e.exports={version:"2.6.11"};"number"==typeof __e&&(__e=n)},function(e,t,n){e.exports=n(449)},function(e,t,n){"use strict";n.r(t),n.d(t,"isOAS3",(function(){return c})),n.d(t,"isSwagger2",(function(){return s})),n.d(t,"OAS3ComponentWrapFactory",(function(){return u}));var r=n(19),o=n.n(r),a=n(0),i=n.n(a);function c(e){var t=e.get("openapi");return"string"==typeof t&&(t.startsWith("3.0.")&&t.length>4)}function s(e){var t=e.get("swagger");return"string"==typeof t&&t.startsWith("2.0")}function u(e){return function(t,n){return function(r){return n&&n.specSelectors&&n.specSelectors.specJson?c(n.specSelectors.specJson())?i.a.createElement(e,o()({},r,n,{Ori:t})):i.a.createElement(t,r):(console.warn("OAS3 wrapper: couldn't get spec"),null)}}}},function(e,t,n){var r=n(28),o=n(21),a=n(53),i=n(62),c=n(51),s=function(e,t,n){var u,l,p,f=e&s.F,d=e&s.G,h=e&s.S,m=e&s.P,v=e&s.B,g=e&s.W,y=d?o:o[t]||(o[t]={}),b=y.prototype,E=d?r:h?r[t]:(r[t]||{}).prototype;for(u in d&&(n=t),n)(l=!f&&E&&void 0!==E[u])&&c(y,u)||(p=l?E[u]:n[u],y[u]=d&&"function"!=typeof E[u]?n[u]:v&&l?a(p,r):g&&E[u]==p?function(e){var t=function(t,n,r){if(this instanceof e){switch(arguments.length){case 0:return new e;case 1:return new e(t);case 2:return new e(t,n)}return new e(t,n,r)}return e.apply(this,arguments)};return t.prototype=e.prototype,t}(p):m&&"function"==typeof p?a(Function.call,p):p,m&&((y.virtual||(y.virtual={}))[u]=p,e&s.R&&b&&!b[u]&&i(b,u,p)))};s.F=1,s.G=2,s.S=4,s.P=8,s.B=16,s.W=32,s.U=64,s.R=128,e.exports=s},function(e,t,n){var r=n(138);e.exports=function(e,t,n){var o=null==e?void 0:r(e,t);return void 0===o?n:o}},function(e,t,n){"use strict";n.r(t),n.d(t,"UPDATE_SPEC",(function(){return B})),n.d(t,"UPDATE_URL",(function(){return U})),n.d(t,"UPDATE_JSON",(function(){return V})),n.d(t,"UPDATE_PARAM",(function(){return z})),n.d(t,"UPDATE_EMPTY_PARAM_INCLUSION",(function(){return F})),n.d(t,"VALIDATE_PARAMS",(function(){return J})),n.d(t,"SET_RESPONSE",(function(){return W})),n.d(t,"SET_REQUEST",(function(){return H})),n.d(t,"SET_MUTATED_REQUEST",(function(){return $})),n.d(t,"LOG_REQUEST",(function(){return Y})),n.d(t,"CLEAR_RESPONSE",(function(){return K})),n.d(t,"CLEAR_REQUEST",(function(){return G})),n.d(t,"CLEAR_VALIDATE_PARAMS",(function(){return Z})),n.d(t,"UPDATE_OPERATION_META_VALUE",(function(){return X})),n.d(t,"UPDATE_RESOLVED",(function(){return Q})),n.d(t,"UPDATE_RESOLVED_SUBTREE",(function(){return ee})),n.d(t,"SET_SCHEME",(function(){return te})),n.d(t,"updateSpec",(function(){return ne})),n.d(t,"updateResolved",(function(){return re})),n.d(t,"updateUrl",(function(){return oe})),n.d(t,"updateJsonSpec",(function(){return ae})),n.d(t,"parseToJson",(function(){return ie})),n.d(t,"resolveSpec",(function(){return se})),n.d(t,"requestResolvedSubtree",(function(){return pe})),n.d(t,"changeParam",(function(){return fe})),n.d(t,"changeParamByIdentity",(function(){return de})),n.d(t,"updateResolvedSubtree",(function(){return he})),n.d(t,"invalidateResolvedSubtreeCache",(function(){return me})),n.d(t,"validateParams",(function(){return ve})),n.d(t,"updateEmptyParamInclusion",(function(){return ge})),n.d(t,"clearValidateParams",(function(){return ye})),n.d(t,"changeConsumesValue",(function(){return be})),n.d(t,"changeProducesValue",(function(){return Ee})),n.d(t,"setResponse",(function(){return xe})),n.d(t,"setRequest",(function(){return Se})),n.d(t,"setMutatedRequest",(function(){return we})),n.d(t,"logRequest",(function(){return je})),n.d(t,"executeRequest",(function(){return _e})),n.d(t,"execute",(function(){return Oe})),n.d(t,"clearResponse",(function(){return Ce})),n.d(t,"clearRequest",(function(){return Ae})),n.d(t,"setScheme",(function(){return ke}));var r=n(18),o=n.n(r),a=n(34),i=n.n(a),c=n(264),s=n.n(c),u=n(16),l=n.n(u),p=n(11),f=n.n(p),d=n(47),h=n.n(d),m=n(27),v=n.n(m),g=n(57),y=n.n(g),b=n(90),E=n.n(b),x=n(10),S=n.n(x),w=n(15),j=n.n(w),_=n(64),O=n.n(_),C=n(1),A=n(75),k=n.n(A),P=n(92),I=n.n(P),T=n(360),N=n.n(T),R=n(361),M=n.n(R),D=n(265),q=n.n(D),L=n(5),B="spec_update_spec",U="spec_update_url",V="spec_update_json",z="spec_update_param",F="spec_update_empty_param_inclusion",J="spec_validate_param",W="spec_set_response",H="spec_set_request",$="spec_set_mutated_request",Y="spec_log_request",K="spec_clear_response",G="spec_clear_request",Z="spec_clear_validate_param",X="spec_update_operation_meta_value",Q="spec_update_resolved",ee="spec_update_resolved_subtree",te="set_scheme";function ne(e){var t,n=(t=e,N()(t)?t:"").replace(/\t/g,"  ");if("string"==typeof e)return{type:B,payload:n}}function re(e){return{type:Q,payload:e}}function oe(e){return{type:U,payload:e}}function ae(e){return{type:V,payload:e}}var ie=function(e){return function(t){var n=t.specActions,r=t.specSelectors,o=t.errActions,a=r.specStr,i=null;try{e=e||a(),o.clear({source:"parser"}),i=O.a.safeLoad(e)}catch(e){return console.error(e),o.newSpecErr({source:"parser",level:"error",message:e.reason,line:e.mark&&e.mark.line?e.mark.line+1:void 0})}return i&&"object"===j()(i)?n.updateJsonSpec(i):{}}},ce=!1,se=function(e,t){return function(n){var r=n.specActions,o=n.specSelectors,a=n.errActions,i=n.fn,c=i.fetch,s=i.resolve,u=i.AST,l=void 0===u?{}:u,p=n.getConfigs;ce||(console.warn("specActions.resolveSpec is deprecated since v3.10.0 and will be removed in v4.0.0; use requestResolvedSubtree instead!"),ce=!0);var f=p(),d=f.modelPropertyMacro,h=f.parameterMacro,m=f.requestInterceptor,v=f.responseInterceptor;void 0===e&&(e=o.specJson()),void 0===t&&(t=o.url());var g=l.getLineNumberForPath?l.getLineNumberForPath:function(){},y=o.specStr();return s({fetch:c,spec:e,baseDoc:t,modelPropertyMacro:d,parameterMacro:h,requestInterceptor:m,responseInterceptor:v}).then((function(e){var t=e.spec,n=e.errors;if(a.clear({type:"thrown"}),S()(n)&&n.length>0){var o=n.map((function(e){return console.error(e),e.line=e.fullPath?g(y,e.fullPath):null,e.path=e.fullPath?e.fullPath.join("."):null,e.level="error",e.type="thrown",e.source="resolver",E()(e,"message",{enumerable:!0,value:e.message}),e}));a.newThrownErrBatch(o)}return r.updateResolved(t)}))}},ue=[],le=M()(y()(v.a.mark((function e(){var t,n,r,o,a,i,c,s,u,l,p,f,d,m,g,b,x;return v.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:if(t=ue.system){e.next=4;break}return console.error("debResolveSubtrees: don't have a system to operate on, aborting."),e.abrupt("return");case 4:if(n=t.errActions,r=t.errSelectors,o=t.fn,a=o.resolveSubtree,i=o.AST,c=void 0===i?{}:i,s=t.specSelectors,u=t.specActions,a){e.next=8;break}return console.error("Error: Swagger-Client did not provide a `resolveSubtree` method, doing nothing."),e.abrupt("return");case 8:return l=c.getLineNumberForPath?c.getLineNumberForPath:function(){},p=s.specStr(),f=t.getConfigs(),d=f.modelPropertyMacro,m=f.parameterMacro,g=f.requestInterceptor,b=f.responseInterceptor,e.prev=11,e.next=14,ue.reduce(function(){var e=y()(v.a.mark((function e(t,o){var i,c,u,f,h,y,x;return v.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:return e.next=2,t;case 2:return i=e.sent,c=i.resultMap,u=i.specWithCurrentSubtrees,e.next=7,a(u,o,{baseDoc:s.url(),modelPropertyMacro:d,parameterMacro:m,requestInterceptor:g,responseInterceptor:b});case 7:return f=e.sent,h=f.errors,y=f.spec,r.allErrors().size&&n.clearBy((function(e){return"thrown"!==e.get("type")||"resolver"!==e.get("source")||!e.get("fullPath").every((function(e,t){return e===o[t]||void 0===o[t]}))})),S()(h)&&h.length>0&&(x=h.map((function(e){return e.line=e.fullPath?l(p,e.fullPath):null,e.path=e.fullPath?e.fullPath.join("."):null,e.level="error",e.type="thrown",e.source="resolver",E()(e,"message",{enumerable:!0,value:e.message}),e})),n.newThrownErrBatch(x)),q()(c,o,y),q()(u,o,y),e.abrupt("return",{resultMap:c,specWithCurrentSubtrees:u});case 15:case"end":return e.stop()}}),e)})));return function(t,n){return e.apply(this,arguments)}}(),h.a.resolve({resultMap:(s.specResolvedSubtree([])||Object(C.Map)()).toJS(),specWithCurrentSubtrees:s.specJson().toJS()}));case 14:x=e.sent,delete ue.system,ue=[],e.next=22;break;case 19:e.prev=19,e.t0=e.catch(11),console.error(e.t0);case 22:u.updateResolvedSubtree([],x.resultMap);case 23:case"end":return e.stop()}}),e,null,[[11,19]])}))),35),pe=function(e){return function(t){ue.map((function(e){return e.join("@@")})).indexOf(e.join("@@"))>-1||(ue.push(e),ue.system=t,le())}};function fe(e,t,n,r,o){return{type:z,payload:{path:e,value:r,paramName:t,paramIn:n,isXml:o}}}function de(e,t,n,r){return{type:z,payload:{path:e,param:t,value:n,isXml:r}}}var he=function(e,t){return{type:ee,payload:{path:e,value:t}}},me=function(){return{type:ee,payload:{path:[],value:Object(C.Map)()}}},ve=function(e,t){return{type:J,payload:{pathMethod:e,isOAS3:t}}},ge=function(e,t,n,r){return{type:F,payload:{pathMethod:e,paramName:t,paramIn:n,includeEmptyValue:r}}};function ye(e){return{type:Z,payload:{pathMethod:e}}}function be(e,t){return{type:X,payload:{path:e,value:t,key:"consumes_value"}}}function Ee(e,t){return{type:X,payload:{path:e,value:t,key:"produces_value"}}}var xe=function(e,t,n){return{payload:{path:e,method:t,res:n},type:W}},Se=function(e,t,n){return{payload:{path:e,method:t,req:n},type:H}},we=function(e,t,n){return{payload:{path:e,method:t,req:n},type:$}},je=function(e){return{payload:e,type:Y}},_e=function(e){return function(t){var n=t.fn,r=t.specActions,o=t.specSelectors,a=t.getConfigs,i=t.oas3Selectors,c=e.pathName,u=e.method,p=e.operation,d=a(),h=d.requestInterceptor,m=d.responseInterceptor,v=p.toJS();if(p&&p.get("parameters")&&p.get("parameters").filter((function(e){return e&&!0===e.get("allowEmptyValue")})).forEach((function(t){if(o.parameterInclusionSettingFor([c,u],t.get("name"),t.get("in"))){e.parameters=e.parameters||{};var n=Object(L.C)(t,e.parameters);(!n||n&&0===n.size)&&(e.parameters[t.get("name")]="")}})),e.contextUrl=k()(o.url()).toString(),v&&v.operationId?e.operationId=v.operationId:v&&c&&u&&(e.operationId=n.opId(v,c,u)),o.isOAS3()){var g="".concat(c,":").concat(u);e.server=i.selectedServer(g)||i.selectedServer();var y=i.serverVariables({server:e.server,namespace:g}).toJS(),b=i.serverVariables({server:e.server}).toJS();e.serverVariables=f()(y).length?y:b,e.requestContentType=i.requestContentType(c,u),e.responseContentType=i.responseContentType(c,u)||"*/*";var E=i.requestBodyValue(c,u),x=i.requestBodyInclusionSetting(c,u);Object(L.t)(E)?e.requestBody=JSON.parse(E):E&&E.toJS?e.requestBody=E.map((function(e){return C.Map.isMap(e)?e.get("value"):e})).filter((function(e,t){return!Object(L.q)(e)||x.get(t)})).toJS():e.requestBody=E}var S=l()({},e);S=n.buildRequest(S),r.setRequest(e.pathName,e.method,S);e.requestInterceptor=function(t){var n=h.apply(this,[t]),o=l()({},n);return r.setMutatedRequest(e.pathName,e.method,o),n},e.responseInterceptor=m;var w=s()();return n.execute(e).then((function(t){t.duration=s()()-w,r.setResponse(e.pathName,e.method,t)})).catch((function(t){console.error(t),r.setResponse(e.pathName,e.method,{error:!0,err:I()(t)})}))}},Oe=function(){var e=arguments.length>0&&void 0!==arguments[0]?arguments[0]:{},t=e.path,n=e.method,r=i()(e,["path","method"]);return function(e){var a=e.fn.fetch,i=e.specSelectors,c=e.specActions,s=i.specJsonWithResolvedSubtrees().toJS(),u=i.operationScheme(t,n),l=i.contentTypeValues([t,n]).toJS(),p=l.requestContentType,f=l.responseContentType,d=/xml/i.test(p),h=i.parameterValues([t,n],d).toJS();return c.executeRequest(o()(o()({},r),{},{fetch:a,spec:s,pathName:t,method:n,parameters:h,requestContentType:p,scheme:u,responseContentType:f}))}};function Ce(e,t){return{type:K,payload:{path:e,method:t}}}function Ae(e,t){return{type:G,payload:{path:e,method:t}}}function ke(e,t,n){return{type:te,payload:{scheme:e,path:t,method:n}}}},function(e,t,n){e.exports=n(588)},function(e,t){var n=e.exports="undefined"!=typeof window&&window.Math==Math?window:"undefined"!=typeof self&&self.Math==Math?self:Function("return this")();"number"==typeof __g&&(__g=n)},function(e,t,n){var r=n(157)("wks"),o=n(159),a=n(37).Symbol,i="function"==typeof a;(e.exports=function(e){return r[e]||(r[e]=i&&a[e]||(i?a:o)("Symbol."+e))}).store=r},function(e,t){e.exports=function(e){return"object"==typeof e?null!==e:"function"==typeof e}},function(e,t,n){var r=n(294),o=n(10),a=n(286),i=n(124),c=n(128);e.exports=function(e,t){var n;if(void 0===i||null==e[a]){if(o(e)||(n=c(e))||t&&e&&"number"==typeof e.length){n&&(e=n);var s=0,u=function(){};return{s:u,n:function(){return s>=e.length?{done:!0}:{done:!1,value:e[s++]}},e:function(e){throw e},f:u}}throw new TypeError("Invalid attempt to iterate non-iterable instance.\nIn order to be iterable, non-array objects must have a [Symbol.iterator]() method.")}var l,p=!0,f=!1;return{s:function(){n=r(e)},n:function(){var e=n.next();return p=e.done,e},e:function(e){f=!0,l=e},f:function(){try{p||null==n.return||n.return()}finally{if(f)throw l}}}}},function(e,t,n){var r=n(174)("wks"),o=n(123),a=n(28).Symbol,i="function"==typeof a;(e.exports=function(e){return r[e]||(r[e]=i&&a[e]||(i?a:o)("Symbol."+e))}).store=r},function(e,t){var n=Array.isArray;e.exports=n},function(e,t,n){var r=n(328),o=n(579);e.exports=function(e,t){if(null==e)return{};var n,a,i=o(e,t);if(r){var c=r(e);for(a=0;a<c.length;a++)n=c[a],t.indexOf(n)>=0||Object.prototype.propertyIsEnumerable.call(e,n)&&(i[n]=e[n])}return i}},function(e,t,n){var r=n(37),o=n(59),a=n(68),i=n(77),c=n(117),s=function(e,t,n){var u,l,p,f,d=e&s.F,h=e&s.G,m=e&s.S,v=e&s.P,g=e&s.B,y=h?r:m?r[t]||(r[t]={}):(r[t]||{}).prototype,b=h?o:o[t]||(o[t]={}),E=b.prototype||(b.prototype={});for(u in h&&(n=t),n)p=((l=!d&&y&&void 0!==y[u])?y:n)[u],f=g&&l?c(p,r):v&&"function"==typeof p?c(Function.call,p):p,y&&i(y,u,p,e&s.U),b[u]!=p&&a(b,u,f),v&&E[u]!=p&&(E[u]=p)};r.core=o,s.F=1,s.G=2,s.S=4,s.P=8,s.B=16,s.W=32,s.U=64,s.R=128,e.exports=s},function(e,t,n){var r=n(30);e.exports=function(e){if(!r(e))throw TypeError(e+" is not an object!");return e}},function(e,t){var n=e.exports="undefined"!=typeof window&&window.Math==Math?window:"undefined"!=typeof self&&self.Math==Math?self:Function("return this")();"number"==typeof __g&&(__g=n)},function(e,t,n){var r=n(35),o=n(79),a=n(60),i=/"/g,c=function(e,t,n,r){var o=String(a(e)),c="<"+t;return""!==n&&(c+=" "+n+'="'+String(r).replace(i,"&quot;")+'"'),c+">"+o+"</"+t+">"};e.exports=function(e,t){var n={};n[e]=t(c),r(r.P+r.F*o((function(){var t=""[e]('"');return t!==t.toLowerCase()||t.split('"').length>3})),"String",n)}},function(e,t,n){e.exports=!n(63)((function(){return 7!=Object.defineProperty({},"a",{get:function(){return 7}}).a}))},function(e,t){e.exports=function(e){var t=typeof e;return null!=e&&("object"==t||"function"==t)}},function(e,t,n){"use strict";n.d(t,"b",(function(){return d})),n.d(t,"e",(function(){return h})),n.d(t,"c",(function(){return v})),n.d(t,"a",(function(){return g})),n.d(t,"d",(function(){return y}));var r=n(31),o=n.n(r),a=n(15),i=n.n(a),c=n(40),s=n.n(c),u=n(269),l=n.n(u),p=function(e){return String.prototype.toLowerCase.call(e)},f=function(e){return e.replace(/[^\w]/gi,"_")};function d(e){var t=e.openapi;return!!t&&l()(t,"3")}function h(e,t){var n=arguments.length>2&&void 0!==arguments[2]?arguments[2]:"",r=arguments.length>3&&void 0!==arguments[3]?arguments[3]:{},o=r.v2OperationIdCompatibilityMode;if(!e||"object"!==i()(e))return null;var a=(e.operationId||"").replace(/\s/g,"");return a.length?f(e.operationId):m(t,n,{v2OperationIdCompatibilityMode:o})}function m(e,t){var n=arguments.length>2&&void 0!==arguments[2]?arguments[2]:{},r=n.v2OperationIdCompatibilityMode;if(r){var o="".concat(t.toLowerCase(),"_").concat(e).replace(/[\s!@#$%^&*()_+=[{\]};:<>|./?,\\'""-]/g,"_");return(o=o||"".concat(e.substring(1),"_").concat(t)).replace(/((_){2,})/g,"_").replace(/^(_)*/g,"").replace(/([_])*$/g,"")}return"".concat(p(t)).concat(f(e))}function v(e,t){return"".concat(p(t),"-").concat(e)}function g(e,t){return e&&e.paths?function(e,t){return function(e,t,n){if(!e||"object"!==i()(e)||!e.paths||"object"!==i()(e.paths))return null;var r=e.paths;for(var o in r)for(var a in r[o])if("PARAMETERS"!==a.toUpperCase()){var c=r[o][a];if(c&&"object"===i()(c)){var s={spec:e,pathName:o,method:a.toUpperCase(),operation:c},u=t(s);if(n&&u)return s}}return}(e,t,!0)||null}(e,(function(e){var n=e.pathName,r=e.method,o=e.operation;if(!o||"object"!==i()(o))return!1;var a=o.operationId;return[h(o,n,r),v(n,r),a].some((function(e){return e&&e===t}))})):null}function y(e){var t=e.spec,n=t.paths,r={};if(!n||t.$$normalized)return e;for(var a in n){var i=n[a];if(s()(i)){var c=i.parameters,u=function(e){var n=i[e];if(!s()(n))return"continue";var u=h(n,a,e);if(u){r[u]?r[u].push(n):r[u]=[n];var l=r[u];if(l.length>1)l.forEach((function(e,t){e.__originalOperationId=e.__originalOperationId||e.operationId,e.operationId="".concat(u).concat(t+1)}));else if(void 0!==n.operationId){var p=l[0];p.__originalOperationId=p.__originalOperationId||n.operationId,p.operationId=u}}if("parameters"!==e){var f=[],d={};for(var m in t)"produces"!==m&&"consumes"!==m&&"security"!==m||(d[m]=t[m],f.push(d));if(c&&(d.parameters=c,f.push(d)),f.length){var v,g=o()(f);try{for(g.s();!(v=g.n()).done;){var y=v.value;for(var b in y)if(n[b]){if("parameters"===b){var E,x=o()(y[b]);try{var S=function(){var e=E.value;n[b].some((function(t){return t.name&&t.name===e.name||t.$ref&&t.$ref===e.$ref||t.$$ref&&t.$$ref===e.$$ref||t===e}))||n[b].push(e)};for(x.s();!(E=x.n()).done;)S()}catch(e){x.e(e)}finally{x.f()}}}else n[b]=y[b]}}catch(e){g.e(e)}finally{g.f()}}}};for(var l in i)u(l)}}return t.$$normalized=!0,e}},function(e,t,n){"use strict";n.r(t),n.d(t,"NEW_THROWN_ERR",(function(){return a})),n.d(t,"NEW_THROWN_ERR_BATCH",(function(){return i})),n.d(t,"NEW_SPEC_ERR",(function(){return c})),n.d(t,"NEW_SPEC_ERR_BATCH",(function(){return s})),n.d(t,"NEW_AUTH_ERR",(function(){return u})),n.d(t,"CLEAR",(function(){return l})),n.d(t,"CLEAR_BY",(function(){return p})),n.d(t,"newThrownErr",(function(){return f})),n.d(t,"newThrownErrBatch",(function(){return d})),n.d(t,"newSpecErr",(function(){return h})),n.d(t,"newSpecErrBatch",(function(){return m})),n.d(t,"newAuthErr",(function(){return v})),n.d(t,"clear",(function(){return g})),n.d(t,"clearBy",(function(){return y}));var r=n(92),o=n.n(r),a="err_new_thrown_err",i="err_new_thrown_err_batch",c="err_new_spec_err",s="err_new_spec_err_batch",u="err_new_auth_err",l="err_clear",p="err_clear_by";function f(e){return{type:a,payload:o()(e)}}function d(e){return{type:i,payload:e}}function h(e){return{type:c,payload:e}}function m(e){return{type:s,payload:e}}function v(e){return{type:u,payload:e}}function g(){var e=arguments.length>0&&void 0!==arguments[0]?arguments[0]:{};return{type:l,payload:e}}function y(){var e=arguments.length>0&&void 0!==arguments[0]?arguments[0]:function(){return!0};return{type:p,payload:e}}},function(e,t,n){"use strict";n.r(t),n.d(t,"UPDATE_SELECTED_SERVER",(function(){return r})),n.d(t,"UPDATE_REQUEST_BODY_VALUE",(function(){return o})),n.d(t,"UPDATE_REQUEST_BODY_INCLUSION",(function(){return a})),n.d(t,"UPDATE_ACTIVE_EXAMPLES_MEMBER",(function(){return i})),n.d(t,"UPDATE_REQUEST_CONTENT_TYPE",(function(){return c})),n.d(t,"UPDATE_RESPONSE_CONTENT_TYPE",(function(){return s})),n.d(t,"UPDATE_SERVER_VARIABLE_VALUE",(function(){return u})),n.d(t,"SET_REQUEST_BODY_VALIDATE_ERROR",(function(){return l})),n.d(t,"CLEAR_REQUEST_BODY_VALIDATE_ERROR",(function(){return p})),n.d(t,"setSelectedServer",(function(){return f})),n.d(t,"setRequestBodyValue",(function(){return d})),n.d(t,"setRequestBodyInclusion",(function(){return h})),n.d(t,"setActiveExamplesMember",(function(){return m})),n.d(t,"setRequestContentType",(function(){return v})),n.d(t,"setResponseContentType",(function(){return g})),n.d(t,"setServerVariableValue",(function(){return y})),n.d(t,"setRequestBodyValidateError",(function(){return b})),n.d(t,"clearRequestBodyValidateError",(function(){return E})),n.d(t,"initRequestBodyValidateError",(function(){return x}));var r="oas3_set_servers",o="oas3_set_request_body_value",a="oas3_set_request_body_inclusion",i="oas3_set_active_examples_member",c="oas3_set_request_content_type",s="oas3_set_response_content_type",u="oas3_set_server_variable_value",l="oas3_set_request_body_validate_error",p="oas3_clear_request_body_validate_error";function f(e,t){return{type:r,payload:{selectedServerUrl:e,namespace:t}}}function d(e){var t=e.value,n=e.pathMethod;return{type:o,payload:{value:t,pathMethod:n}}}function h(e){var t=e.value,n=e.pathMethod,r=e.name;return{type:a,payload:{value:t,pathMethod:n,name:r}}}function m(e){var t=e.name,n=e.pathMethod,r=e.contextType,o=e.contextName;return{type:i,payload:{name:t,pathMethod:n,contextType:r,contextName:o}}}function v(e){var t=e.value,n=e.pathMethod;return{type:c,payload:{value:t,pathMethod:n}}}function g(e){var t=e.value,n=e.path,r=e.method;return{type:s,payload:{value:t,path:n,method:r}}}function y(e){var t=e.server,n=e.namespace,r=e.key,o=e.val;return{type:u,payload:{server:t,namespace:n,key:r,val:o}}}var b=function(e){var t=e.path,n=e.method,r=e.validationErrors;return{type:l,payload:{path:t,method:n,validationErrors:r}}},E=function(e){var t=e.path,n=e.method;return{type:p,payload:{path:t,method:n}}},x=function(e){var t=e.pathMethod;return{type:p,payload:{path:t[0],method:t[1]}}}},function(e,t,n){var r=n(78);e.exports=function(e){if(!r(e))throw TypeError(e+" is not an object!");return e}},function(e,t){e.exports=require("classnames")},function(e,t,n){var r=n(71),o=n(40);e.exports=function(e){if(!o(e))return!1;var t=r(e);return"[object Function]"==t||"[object GeneratorFunction]"==t||"[object AsyncFunction]"==t||"[object Proxy]"==t}},function(e,t,n){e.exports=n(582)},function(e,t,n){var r=n(36),o=n(285),a=n(178),i=Object.defineProperty;t.f=n(39)?Object.defineProperty:function(e,t,n){if(r(e),t=a(t,!0),r(n),o)try{return i(e,t,n)}catch(e){}if("get"in n||"set"in n)throw TypeError("Accessors not supported!");return"value"in n&&(e[t]=n.value),e}},function(e,t,n){var r=n(303),o="object"==typeof self&&self&&self.Object===Object&&self,a=r||o||Function("return this")();e.exports=a},function(e,t){e.exports=require("deep-extend")},function(e,t){var n={}.hasOwnProperty;e.exports=function(e,t){return n.call(e,t)}},function(e,t,n){var r=n(171),o=n(170);e.exports=function(e){return r(o(e))}},function(e,t,n){var r=n(70);e.exports=function(e,t,n){if(r(e),void 0===t)return e;switch(n){case 1:return function(n){return e.call(t,n)};case 2:return function(n,r){return e.call(t,n,r)};case 3:return function(n,r,o){return e.call(t,n,r,o)}}return function(){return e.apply(t,arguments)}}},function(e,t){e.exports=function(e){return null!=e&&"object"==typeof e}},function(e,t,n){var r=n(302);e.exports=function(e){return null==e?"":r(e)}},function(e,t,n){"use strict";n.r(t),n.d(t,"lastError",(function(){return h})),n.d(t,"url",(function(){return m})),n.d(t,"specStr",(function(){return v})),n.d(t,"specSource",(function(){return g})),n.d(t,"specJson",(function(){return y})),n.d(t,"specResolved",(function(){return b})),n.d(t,"specResolvedSubtree",(function(){return E})),n.d(t,"specJsonWithResolvedSubtrees",(function(){return S})),n.d(t,"spec",(function(){return w})),n.d(t,"isOAS3",(function(){return j})),n.d(t,"info",(function(){return _})),n.d(t,"externalDocs",(function(){return O})),n.d(t,"version",(function(){return C})),n.d(t,"semver",(function(){return A})),n.d(t,"paths",(function(){return k})),n.d(t,"operations",(function(){return P})),n.d(t,"consumes",(function(){return I})),n.d(t,"produces",(function(){return T})),n.d(t,"security",(function(){return N})),n.d(t,"securityDefinitions",(function(){return R})),n.d(t,"findDefinition",(function(){return M})),n.d(t,"definitions",(function(){return D})),n.d(t,"basePath",(function(){return q})),n.d(t,"host",(function(){return L})),n.d(t,"schemes",(function(){return B})),n.d(t,"operationsWithRootInherited",(function(){return U})),n.d(t,"tags",(function(){return V})),n.d(t,"tagDetails",(function(){return z})),n.d(t,"operationsWithTags",(function(){return F})),n.d(t,"taggedOperations",(function(){return J})),n.d(t,"responses",(function(){return W})),n.d(t,"requests",(function(){return H})),n.d(t,"mutatedRequests",(function(){return $})),n.d(t,"responseFor",(function(){return Y})),n.d(t,"requestFor",(function(){return K})),n.d(t,"mutatedRequestFor",(function(){return G})),n.d(t,"allowTryItOutFor",(function(){return Z})),n.d(t,"parameterWithMetaByIdentity",(function(){return X})),n.d(t,"parameterInclusionSettingFor",(function(){return Q})),n.d(t,"parameterWithMeta",(function(){return ee})),n.d(t,"operationWithMeta",(function(){return te})),n.d(t,"getParameter",(function(){return ne})),n.d(t,"hasHost",(function(){return re})),n.d(t,"parameterValues",(function(){return oe})),n.d(t,"parametersIncludeIn",(function(){return ae})),n.d(t,"parametersIncludeType",(function(){return ie})),n.d(t,"contentTypeValues",(function(){return ce})),n.d(t,"currentProducesFor",(function(){return se})),n.d(t,"producesOptionsFor",(function(){return ue})),n.d(t,"consumesOptionsFor",(function(){return le})),n.d(t,"operationScheme",(function(){return pe})),n.d(t,"canExecuteScheme",(function(){return fe})),n.d(t,"validateBeforeExecute",(function(){return de})),n.d(t,"getOAS3RequiredRequestBodyContentType",(function(){return he}));var r=n(10),o=n.n(r),a=n(12),i=n.n(a),c=n(13),s=n.n(c),u=n(14),l=n(5),p=n(1),f=["get","put","post","delete","options","head","patch","trace"],d=function(e){return e||Object(p.Map)()},h=Object(u.createSelector)(d,(function(e){return e.get("lastError")})),m=Object(u.createSelector)(d,(function(e){return e.get("url")})),v=Object(u.createSelector)(d,(function(e){return e.get("spec")||""})),g=Object(u.createSelector)(d,(function(e){return e.get("specSource")||"not-editor"})),y=Object(u.createSelector)(d,(function(e){return e.get("json",Object(p.Map)())})),b=Object(u.createSelector)(d,(function(e){return e.get("resolved",Object(p.Map)())})),E=function(e,t){return e.getIn(["resolvedSubtrees"].concat(s()(t)),void 0)},x=function e(t,n){return p.Map.isMap(t)&&p.Map.isMap(n)?n.get("$$ref")?n:Object(p.OrderedMap)().mergeWith(e,t,n):n},S=Object(u.createSelector)(d,(function(e){return Object(p.OrderedMap)().mergeWith(x,e.get("json"),e.get("resolvedSubtrees"))})),w=function(e){return y(e)},j=Object(u.createSelector)(w,(function(){return!1})),_=Object(u.createSelector)(w,(function(e){return me(e&&e.get("info"))})),O=Object(u.createSelector)(w,(function(e){return me(e&&e.get("externalDocs"))})),C=Object(u.createSelector)(_,(function(e){return e&&e.get("version")})),A=Object(u.createSelector)(C,(function(e){return/v?([0-9]*)\.([0-9]*)\.([0-9]*)/i.exec(e).slice(1)})),k=Object(u.createSelector)(S,(function(e){return e.get("paths")})),P=Object(u.createSelector)(k,(function(e){if(!e||e.size<1)return Object(p.List)();var t=Object(p.List)();return e&&e.forEach?(e.forEach((function(e,n){if(!e||!e.forEach)return{};e.forEach((function(e,r){f.indexOf(r)<0||(t=t.push(Object(p.fromJS)({path:n,method:r,operation:e,id:"".concat(r,"-").concat(n)})))}))})),t):Object(p.List)()})),I=Object(u.createSelector)(w,(function(e){return Object(p.Set)(e.get("consumes"))})),T=Object(u.createSelector)(w,(function(e){return Object(p.Set)(e.get("produces"))})),N=Object(u.createSelector)(w,(function(e){return e.get("security",Object(p.List)())})),R=Object(u.createSelector)(w,(function(e){return e.get("securityDefinitions")})),M=function(e,t){var n=e.getIn(["resolvedSubtrees","definitions",t],null),r=e.getIn(["json","definitions",t],null);return n||r||null},D=Object(u.createSelector)(w,(function(e){var t=e.get("definitions");return p.Map.isMap(t)?t:Object(p.Map)()})),q=Object(u.createSelector)(w,(function(e){return e.get("basePath")})),L=Object(u.createSelector)(w,(function(e){return e.get("host")})),B=Object(u.createSelector)(w,(function(e){return e.get("schemes",Object(p.Map)())})),U=Object(u.createSelector)(P,I,T,(function(e,t,n){return e.map((function(e){return e.update("operation",(function(e){if(e){if(!p.Map.isMap(e))return;return e.withMutations((function(e){return e.get("consumes")||e.update("consumes",(function(e){return Object(p.Set)(e).merge(t)})),e.get("produces")||e.update("produces",(function(e){return Object(p.Set)(e).merge(n)})),e}))}return Object(p.Map)()}))}))})),V=Object(u.createSelector)(w,(function(e){var t=e.get("tags",Object(p.List)());return p.List.isList(t)?t.filter((function(e){return p.Map.isMap(e)})):Object(p.List)()})),z=function(e,t){return(V(e)||Object(p.List)()).filter(p.Map.isMap).find((function(e){return e.get("name")===t}),Object(p.Map)())},F=Object(u.createSelector)(U,V,(function(e,t){return e.reduce((function(e,t){var n=Object(p.Set)(t.getIn(["operation","tags"]));return n.count()<1?e.update("default",Object(p.List)(),(function(e){return e.push(t)})):n.reduce((function(e,n){return e.update(n,Object(p.List)(),(function(e){return e.push(t)}))}),e)}),t.reduce((function(e,t){return e.set(t.get("name"),Object(p.List)())}),Object(p.OrderedMap)()))})),J=function(e){return function(t){var n=(0,t.getConfigs)(),r=n.tagsSorter,o=n.operationsSorter;return F(e).sortBy((function(e,t){return t}),(function(e,t){var n="function"==typeof r?r:l.I.tagsSorter[r];return n?n(e,t):null})).map((function(t,n){var r="function"==typeof o?o:l.I.operationsSorter[o],a=r?t.sort(r):t;return Object(p.Map)({tagDetails:z(e,n),operations:a})}))}},W=Object(u.createSelector)(d,(function(e){return e.get("responses",Object(p.Map)())})),H=Object(u.createSelector)(d,(function(e){return e.get("requests",Object(p.Map)())})),$=Object(u.createSelector)(d,(function(e){return e.get("mutatedRequests",Object(p.Map)())})),Y=function(e,t,n){return W(e).getIn([t,n],null)},K=function(e,t,n){return H(e).getIn([t,n],null)},G=function(e,t,n){return $(e).getIn([t,n],null)},Z=function(){return!0},X=function(e,t,n){var r=S(e).getIn(["paths"].concat(s()(t),["parameters"]),Object(p.OrderedMap)()),o=e.getIn(["meta","paths"].concat(s()(t),["parameters"]),Object(p.OrderedMap)());return r.map((function(e){var t=o.get("".concat(n.get("in"),".").concat(n.get("name"))),r=o.get("".concat(n.get("in"),".").concat(n.get("name"),".hash-").concat(n.hashCode()));return Object(p.OrderedMap)().merge(e,t,r)})).find((function(e){return e.get("in")===n.get("in")&&e.get("name")===n.get("name")}),Object(p.OrderedMap)())},Q=function(e,t,n,r){var o="".concat(r,".").concat(n);return e.getIn(["meta","paths"].concat(s()(t),["parameter_inclusions",o]),!1)},ee=function(e,t,n,r){var o=S(e).getIn(["paths"].concat(s()(t),["parameters"]),Object(p.OrderedMap)()).find((function(e){return e.get("in")===r&&e.get("name")===n}),Object(p.OrderedMap)());return X(e,t,o)},te=function(e,t,n){var r=S(e).getIn(["paths",t,n],Object(p.OrderedMap)()),o=e.getIn(["meta","paths",t,n],Object(p.OrderedMap)()),a=r.get("parameters",Object(p.List)()).map((function(r){return X(e,[t,n],r)}));return Object(p.OrderedMap)().merge(r,o).set("parameters",a)};function ne(e,t,n,r){return t=t||[],e.getIn(["meta","paths"].concat(s()(t),["parameters"]),Object(p.fromJS)([])).find((function(e){return p.Map.isMap(e)&&e.get("name")===n&&e.get("in")===r}))||Object(p.Map)()}var re=Object(u.createSelector)(w,(function(e){var t=e.get("host");return"string"==typeof t&&t.length>0&&"/"!==t[0]}));function oe(e,t,n){return t=t||[],te.apply(void 0,[e].concat(s()(t))).get("parameters",Object(p.List)()).reduce((function(e,t){var r=n&&"body"===t.get("in")?t.get("value_xml"):t.get("value");return e.set(Object(l.B)(t,{allowHashes:!1}),r)}),Object(p.fromJS)({}))}function ae(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:"";if(p.List.isList(e))return e.some((function(e){return p.Map.isMap(e)&&e.get("in")===t}))}function ie(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:"";if(p.List.isList(e))return e.some((function(e){return p.Map.isMap(e)&&e.get("type")===t}))}function ce(e,t){t=t||[];var n=S(e).getIn(["paths"].concat(s()(t)),Object(p.fromJS)({})),r=e.getIn(["meta","paths"].concat(s()(t)),Object(p.fromJS)({})),o=se(e,t),a=n.get("parameters")||new p.List,i=r.get("consumes_value")?r.get("consumes_value"):ie(a,"file")?"multipart/form-data":ie(a,"formData")?"application/x-www-form-urlencoded":void 0;return Object(p.fromJS)({requestContentType:i,responseContentType:o})}function se(e,t){t=t||[];var n=S(e).getIn(["paths"].concat(s()(t)),null);if(null!==n){var r=e.getIn(["meta","paths"].concat(s()(t),["produces_value"]),null),o=n.getIn(["produces",0],null);return r||o||"application/json"}}function ue(e,t){t=t||[];var n=S(e),r=n.getIn(["paths"].concat(s()(t)),null);if(null!==r){var o=t,a=i()(o,1)[0],c=r.get("produces",null),u=n.getIn(["paths",a,"produces"],null),l=n.getIn(["produces"],null);return c||u||l}}function le(e,t){t=t||[];var n=S(e),r=n.getIn(["paths"].concat(s()(t)),null);if(null!==r){var o=t,a=i()(o,1)[0],c=r.get("consumes",null),u=n.getIn(["paths",a,"consumes"],null),l=n.getIn(["consumes"],null);return c||u||l}}var pe=function(e,t,n){var r=e.get("url").match(/^([a-z][a-z0-9+\-.]*):/),a=o()(r)?r[1]:null;return e.getIn(["scheme",t,n])||e.getIn(["scheme","_defaultScheme"])||a||""},fe=function(e,t,n){return["http","https"].indexOf(pe(e,t,n))>-1},de=function(e,t){t=t||[];var n=e.getIn(["meta","paths"].concat(s()(t),["parameters"]),Object(p.fromJS)([])),r=!0;return n.forEach((function(e){var t=e.get("errors");t&&t.count()&&(r=!1)})),r},he=function(e,t){var n={requestBody:!1,requestContentType:{}},r=e.getIn(["resolvedSubtrees","paths"].concat(s()(t),["requestBody"]),Object(p.fromJS)([]));return r.size<1||(r.getIn(["required"])&&(n.requestBody=r.getIn(["required"])),r.getIn(["content"]).entrySeq().forEach((function(e){var t=e[0];if(e[1].getIn(["schema","required"])){var r=e[1].getIn(["schema","required"]).toJS();n.requestContentType[t]=r}}))),n};function me(e){return p.Map.isMap(e)?e:new p.Map}},function(e,t,n){var r=n(47);function o(e,t,n,o,a,i,c){try{var s=e[i](c),u=s.value}catch(e){return void n(e)}s.done?t(u):r.resolve(u).then(o,a)}e.exports=function(e){return function(){var t=this,n=arguments;return new r((function(r,a){var i=e.apply(t,n);function c(e){o(i,r,a,c,s,"next",e)}function s(e){o(i,r,a,c,s,"throw",e)}c(void 0)}))}}},function(e,t,n){"use strict";n.r(t),n.d(t,"SHOW_AUTH_POPUP",(function(){return h})),n.d(t,"AUTHORIZE",(function(){return m})),n.d(t,"LOGOUT",(function(){return v})),n.d(t,"PRE_AUTHORIZE_OAUTH2",(function(){return g})),n.d(t,"AUTHORIZE_OAUTH2",(function(){return y})),n.d(t,"VALIDATE",(function(){return b})),n.d(t,"CONFIGURE_AUTH",(function(){return E})),n.d(t,"showDefinitions",(function(){return x})),n.d(t,"authorize",(function(){return S})),n.d(t,"logout",(function(){return w})),n.d(t,"preAuthorizeImplicit",(function(){return j})),n.d(t,"authorizeOauth2",(function(){return _})),n.d(t,"authorizePassword",(function(){return O})),n.d(t,"authorizeApplication",(function(){return C})),n.d(t,"authorizeAccessCodeWithFormParams",(function(){return A})),n.d(t,"authorizeAccessCodeWithBasicAuthentication",(function(){return k})),n.d(t,"authorizeRequest",(function(){return P})),n.d(t,"configureAuth",(function(){return I}));var r=n(15),o=n.n(r),a=n(16),i=n.n(a),c=n(22),s=n.n(c),u=n(75),l=n.n(u),p=n(17),f=n.n(p),d=n(5),h="show_popup",m="authorize",v="logout",g="pre_authorize_oauth2",y="authorize_oauth2",b="validate",E="configure_auth";function x(e){return{type:h,payload:e}}function S(e){return{type:m,payload:e}}function w(e){return{type:v,payload:e}}var j=function(e){return function(t){var n=t.authActions,r=t.errActions,o=e.auth,a=e.token,i=e.isValid,c=o.schema,u=o.name,l=c.get("flow");delete f.a.swaggerUIRedirectOauth2,"accessCode"===l||i||r.newAuthErr({authId:u,source:"auth",level:"warning",message:"Authorization may be unsafe, passed state was changed in server Passed state wasn't returned from auth server"}),a.error?r.newAuthErr({authId:u,source:"auth",level:"error",message:s()(a)}):n.authorizeOauth2({auth:o,token:a})}};function _(e){return{type:y,payload:e}}var O=function(e){return function(t){var n=t.authActions,r=e.schema,o=e.name,a=e.username,c=e.password,s=e.passwordType,u=e.clientId,l=e.clientSecret,p={grant_type:"password",scope:e.scopes.join(" "),username:a,password:c},f={};switch(s){case"request-body":!function(e,t,n){t&&i()(e,{client_id:t});n&&i()(e,{client_secret:n})}(p,u,l);break;case"basic":f.Authorization="Basic "+Object(d.a)(u+":"+l);break;default:console.warn("Warning: invalid passwordType ".concat(s," was passed, not including client id and secret"))}return n.authorizeRequest({body:Object(d.b)(p),url:r.get("tokenUrl"),name:o,headers:f,query:{},auth:e})}};var C=function(e){return function(t){var n=t.authActions,r=e.schema,o=e.scopes,a=e.name,i=e.clientId,c=e.clientSecret,s={Authorization:"Basic "+Object(d.a)(i+":"+c)},u={grant_type:"client_credentials",scope:o.join(" ")};return n.authorizeRequest({body:Object(d.b)(u),name:a,url:r.get("tokenUrl"),auth:e,headers:s})}},A=function(e){var t=e.auth,n=e.redirectUrl;return function(e){var r=e.authActions,o=t.schema,a=t.name,i=t.clientId,c=t.clientSecret,s=t.codeVerifier,u={grant_type:"authorization_code",code:t.code,client_id:i,client_secret:c,redirect_uri:n,code_verifier:s};return r.authorizeRequest({body:Object(d.b)(u),name:a,url:o.get("tokenUrl"),auth:t})}},k=function(e){var t=e.auth,n=e.redirectUrl;return function(e){var r=e.authActions,o=t.schema,a=t.name,i=t.clientId,c=t.clientSecret,s={Authorization:"Basic "+Object(d.a)(i+":"+c)},u={grant_type:"authorization_code",code:t.code,client_id:i,redirect_uri:n};return r.authorizeRequest({body:Object(d.b)(u),name:a,url:o.get("tokenUrl"),auth:t,headers:s})}},P=function(e){return function(t){var n,r=t.fn,a=t.getConfigs,c=t.authActions,u=t.errActions,p=t.oas3Selectors,f=t.specSelectors,d=t.authSelectors,h=e.body,m=e.query,v=void 0===m?{}:m,g=e.headers,y=void 0===g?{}:g,b=e.name,E=e.url,x=e.auth,S=(d.getConfigs()||{}).additionalQueryStringParams;if(f.isOAS3()){var w=p.selectedServer();n=l()(E,p.serverEffectiveValue({server:w}),!0)}else n=l()(E,f.url(),!0);"object"===o()(S)&&(n.query=i()({},n.query,S));var j=n.toString(),_=i()({Accept:"application/json, text/plain, */*","Content-Type":"application/x-www-form-urlencoded","X-Requested-With":"XMLHttpRequest"},y);r.fetch({url:j,method:"post",headers:_,query:v,body:h,requestInterceptor:a().requestInterceptor,responseInterceptor:a().responseInterceptor}).then((function(e){var t=JSON.parse(e.data),n=t&&(t.error||""),r=t&&(t.parseError||"");e.ok?n||r?u.newAuthErr({authId:b,level:"error",source:"auth",message:s()(t)}):c.authorizeOauth2({auth:x,token:t}):u.newAuthErr({authId:b,level:"error",source:"auth",message:e.statusText})})).catch((function(e){var t=new Error(e).message;if(e.response&&e.response.data){var n=e.response.data;try{var r="string"==typeof n?JSON.parse(n):n;r.error&&(t+=", error: ".concat(r.error)),r.error_description&&(t+=", description: ".concat(r.error_description))}catch(e){}}u.newAuthErr({authId:b,level:"error",source:"auth",message:t})}))}};function I(e){return{type:E,payload:e}}},function(e,t){var n=e.exports={version:"2.6.11"};"number"==typeof __e&&(__e=n)},function(e,t){e.exports=function(e){if(null==e)throw TypeError("Can't call method on  "+e);return e}},function(e,t,n){var r=n(99),o=Math.min;e.exports=function(e){return e>0?o(r(e),9007199254740991):0}},function(e,t,n){var r=n(48),o=n(103);e.exports=n(39)?function(e,t,n){return r.f(e,t,o(1,n))}:function(e,t,n){return e[t]=n,e}},function(e,t){e.exports=function(e){try{return!!e()}catch(e){return!0}}},function(e,t){e.exports=require("js-yaml")},function(e,t,n){"use strict";n.r(t),n.d(t,"UPDATE_LAYOUT",(function(){return o})),n.d(t,"UPDATE_FILTER",(function(){return a})),n.d(t,"UPDATE_MODE",(function(){return i})),n.d(t,"SHOW",(function(){return c})),n.d(t,"updateLayout",(function(){return s})),n.d(t,"updateFilter",(function(){return u})),n.d(t,"show",(function(){return l})),n.d(t,"changeMode",(function(){return p}));var r=n(5),o="layout_update_layout",a="layout_update_filter",i="layout_update_mode",c="layout_show";function s(e){return{type:o,payload:e}}function u(e){return{type:a,payload:e}}function l(e){var t=!(arguments.length>1&&void 0!==arguments[1])||arguments[1];return e=Object(r.w)(e),{type:c,payload:{thing:e,shown:t}}}function p(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:"";return e=Object(r.w)(e),{type:i,payload:{thing:e,mode:t}}}},function(e,t){e.exports=require("url")},function(e,t){e.exports=require("react-syntax-highlighter")},function(e,t,n){var r=n(115),o=n(272);e.exports=n(98)?function(e,t,n){return r.f(e,t,o(1,n))}:function(e,t,n){return e[t]=n,e}},function(e,t,n){var r=n(170);e.exports=function(e){return Object(r(e))}},function(e,t){e.exports=function(e){if("function"!=typeof e)throw TypeError(e+" is not a function!");return e}},function(e,t,n){var r=n(84),o=n(482),a=n(483),i=r?r.toStringTag:void 0;e.exports=function(e){return null==e?void 0===e?"[object Undefined]":"[object Null]":i&&i in Object(e)?o(e):a(e)}},function(e,t,n){var r=n(500),o=n(503);e.exports=function(e,t){var n=o(e,t);return r(n)?n:void 0}},function(e,t,n){var r=n(316),o=n(540),a=n(85);e.exports=function(e){return a(e)?r(e):o(e)}},function(e,t){e.exports=function(e,t){return e===t||e!=e&&t!=t}},function(e,t){e.exports=require("url-parse")},function(e,t,n){"use strict";n.r(t),n.d(t,"default",(function(){return i}));var r=n(359),o=n.n(r),a=[n(208),n(209)];function i(e,t){var n={jsSpec:t.specSelectors.specJson().toJS()};return o()(a,(function(e,t){try{return t.transform(e,n).filter((function(e){return!!e}))}catch(t){return console.error("Transformer error:",t),e}}),e).filter((function(e){return!!e})).map((function(e){return!e.get("line")&&e.get("path"),e}))}},function(e,t,n){var r=n(37),o=n(68),a=n(116),i=n(159)("src"),c=n(391),s=(""+c).split("toString");n(59).inspectSource=function(e){return c.call(e)},(e.exports=function(e,t,n,c){var u="function"==typeof n;u&&(a(n,"name")||o(n,"name",t)),e[t]!==n&&(u&&(a(n,i)||o(n,i,e[t]?""+e[t]:s.join(String(t)))),e===r?e[t]=n:c?e[t]?e[t]=n:o(e,t,n):(delete e[t],o(e,t,n)))})(Function.prototype,"toString",(function(){return"function"==typeof this&&this[i]||c.call(this)}))},function(e,t){e.exports=function(e){return"object"==typeof e?null!==e:"function"==typeof e}},function(e,t){e.exports=function(e){try{return!!e()}catch(e){return!0}}},function(e,t,n){var r=n(284),o=n(175);e.exports=Object.keys||function(e){return r(e,o)}},function(e,t,n){"use strict";var r=n(455)(!0);n(179)(String,"String",(function(e){this._t=String(e),this._i=0}),(function(){var e,t=this._t,n=this._i;return n>=t.length?{value:void 0,done:!0}:(e=r(t,n),this._i+=e.length,{value:e,done:!1})}))},function(e,t){e.exports={}},function(e,t,n){n(457);for(var r=n(28),o=n(62),a=n(82),i=n(32)("toStringTag"),c="CSSRuleList,CSSStyleDeclaration,CSSValueList,ClientRectList,DOMRectList,DOMStringList,DOMTokenList,DataTransferItemList,FileList,HTMLAllCollection,HTMLCollection,HTMLFormElement,HTMLSelectElement,MediaList,MimeTypeArray,NamedNodeMap,NodeList,PaintRequestList,Plugin,PluginArray,SVGLengthList,SVGNumberList,SVGPathSegList,SVGPointList,SVGStringList,SVGTransformList,SourceBufferList,StyleSheetList,TextTrackCueList,TextTrackList,TouchList".split(","),s=0;s<c.length;s++){var u=c[s],l=r[u],p=l&&l.prototype;p&&!p[i]&&o(p,i,u),a[u]=a.Array}},function(e,t,n){var r=n(49).Symbol;e.exports=r},function(e,t,n){var r=n(46),o=n(195);e.exports=function(e){return null!=e&&o(e.length)&&!r(e)}},function(e,t,n){var r=n(33),o=n(198),a=n(548),i=n(55);e.exports=function(e,t){return r(e)?e:o(e,t)?[e]:a(i(e))}},function(e,t,n){var r=n(129);e.exports=function(e){if("string"==typeof e||r(e))return e;var t=e+"";return"0"==t&&1/e==-1/0?"-0":t}},function(e,t,n){var r=n(53),o=n(296),a=n(297),i=n(36),c=n(122),s=n(186),u={},l={};(t=e.exports=function(e,t,n,p,f){var d,h,m,v,g=f?function(){return e}:s(e),y=r(n,p,t?2:1),b=0;if("function"!=typeof g)throw TypeError(e+" is not iterable!");if(a(g)){for(d=c(e.length);d>b;b++)if((v=t?y(i(h=e[b])[0],h[1]):y(e[b]))===u||v===l)return v}else for(m=g.call(e);!(h=m.next()).done;)if((v=o(m,y,h.value,t))===u||v===l)return v}).BREAK=u,t.RETURN=l},function(e,t,n){var r=n(141),o=n(336);e.exports=function(e,t,n,a){var i=!n;n||(n={});for(var c=-1,s=t.length;++c<s;){var u=t[c],l=a?a(n[u],e[u],u,n,e):void 0;void 0===l&&(l=e[u]),i?o(n,u,l):r(n,u,l)}return n}},function(e,t,n){e.exports=n(466)},function(e,t,n){e.exports=n(479)},function(e,t){e.exports=require("serialize-error")},function(e,t,n){"use strict";n.r(t),n.d(t,"sampleFromSchema",(function(){return h})),n.d(t,"inferSchema",(function(){return m})),n.d(t,"sampleXmlFromSchema",(function(){return v})),n.d(t,"createXMLExample",(function(){return g})),n.d(t,"memoizedCreateXMLExample",(function(){return y})),n.d(t,"memoizedSampleFromSchema",(function(){return b}));var r=n(10),o=n.n(r),a=n(5),i=n(355),c=n.n(i),s=n(263),u=n.n(s),l=n(143),p=n.n(l),f={string:function(){return"string"},string_email:function(){return"user@example.com"},"string_date-time":function(){return(new Date).toISOString()},string_date:function(){return(new Date).toISOString().substring(0,10)},string_uuid:function(){return"3fa85f64-5717-4562-b3fc-2c963f66afa6"},string_hostname:function(){return"example.com"},string_ipv4:function(){return"198.51.100.42"},string_ipv6:function(){return"2001:0db8:5b96:0000:0000:426f:8e17:642a"},number:function(){return 0},number_float:function(){return 0},integer:function(){return 0},boolean:function(e){return"boolean"!=typeof e.default||e.default}},d=function(e){var t=e=Object(a.A)(e),n=t.type,r=t.format,o=f["".concat(n,"_").concat(r)]||f[n];return Object(a.s)(o)?o(e):"Unknown Type: "+e.type},h=function e(t){var n=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{},r=Object(a.A)(t),i=r.type,c=r.example,s=r.properties,u=r.additionalProperties,l=r.items,p=n.includeReadOnly,f=n.includeWriteOnly;if(void 0!==c)return Object(a.e)(c,"$$ref",(function(e){return"string"==typeof e&&e.indexOf("#")>-1}));if(!i)if(s)i="object";else{if(!l)return;i="array"}if("object"===i){var h=Object(a.A)(s),m={};for(var v in h)h[v]&&h[v].deprecated||h[v]&&h[v].readOnly&&!p||h[v]&&h[v].writeOnly&&!f||(m[v]=e(h[v],n));if(!0===u)m.additionalProp1={};else if(u)for(var g=Object(a.A)(u),y=e(g,n),b=1;b<4;b++)m["additionalProp"+b]=y;return m}return"array"===i?o()(l.anyOf)?l.anyOf.map((function(t){return e(t,n)})):o()(l.oneOf)?l.oneOf.map((function(t){return e(t,n)})):[e(l,n)]:t.enum?t.default?t.default:Object(a.w)(t.enum)[0]:"file"!==i?d(t):void 0},m=function(e){return e.schema&&(e=e.schema),e.properties&&(e.type="object"),e},v=function e(t){var n,r,i=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{},c=p()({},Object(a.A)(t)),s=c.type,u=c.properties,l=c.additionalProperties,f=c.items,h=c.example,m=i.includeReadOnly,v=i.includeWriteOnly,g=c.default,y={},b={},E=t.xml,x=E.name,S=E.prefix,w=E.namespace,j=c.enum;if(!s)if(u||l)s="object";else{if(!f)return;s="array"}if(n=(S?S+":":"")+(x=x||"notagname"),w){var _=S?"xmlns:"+S:"xmlns";b[_]=w}if("array"===s&&f){if(f.xml=f.xml||E||{},f.xml.name=f.xml.name||E.name,E.wrapped)return y[n]=[],o()(h)?h.forEach((function(t){f.example=t,y[n].push(e(f,i))})):o()(g)?g.forEach((function(t){f.default=t,y[n].push(e(f,i))})):y[n]=[e(f,i)],b&&y[n].push({_attr:b}),y;var O=[];return o()(h)?(h.forEach((function(t){f.example=t,O.push(e(f,i))})),O):o()(g)?(g.forEach((function(t){f.default=t,O.push(e(f,i))})),O):e(f,i)}if("object"===s){var C=Object(a.A)(u);for(var A in y[n]=[],h=h||{},C)if(C.hasOwnProperty(A)&&(!C[A].readOnly||m)&&(!C[A].writeOnly||v))if(C[A].xml=C[A].xml||{},C[A].xml.attribute){var k=o()(C[A].enum)&&C[A].enum[0],P=C[A].example,I=C[A].default;b[C[A].xml.name||A]=void 0!==P&&P||void 0!==h[A]&&h[A]||void 0!==I&&I||k||d(C[A])}else{C[A].xml.name=C[A].xml.name||A,void 0===C[A].example&&void 0!==h[A]&&(C[A].example=h[A]);var T=e(C[A]);o()(T)?y[n]=y[n].concat(T):y[n].push(T)}return!0===l?y[n].push({additionalProp:"Anything can be here"}):l&&y[n].push({additionalProp:d(l)}),b&&y[n].push({_attr:b}),y}return r=void 0!==h?h:void 0!==g?g:o()(j)?j[0]:d(t),y[n]=b?[{_attr:b},r]:r,y};function g(e,t){var n=v(e,t);if(n)return c()(n,{declaration:!0,indent:"\t"})}var y=u()(g),b=u()(h)},function(e,t,n){"use strict";n.r(t),n.d(t,"UPDATE_CONFIGS",(function(){return a})),n.d(t,"TOGGLE_CONFIGS",(function(){return i})),n.d(t,"update",(function(){return c})),n.d(t,"toggle",(function(){return s})),n.d(t,"loaded",(function(){return u}));var r=n(2),o=n.n(r),a="configs_update",i="configs_toggle";function c(e,t){return{type:a,payload:o()({},e,t)}}function s(e){return{type:i,payload:e}}var u=function(){return function(){}}},function(e,t,n){"use strict";n.d(t,"a",(function(){return i}));var r=n(1),o=n.n(r),a=o.a.Set.of("type","format","items","default","maximum","exclusiveMaximum","minimum","exclusiveMinimum","maxLength","minLength","pattern","maxItems","minItems","uniqueItems","enum","multipleOf");function i(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{},n=t.isOAS3;if(!o.a.Map.isMap(e))return{schema:o.a.Map(),parameterContentMediaType:null};if(!n)return"body"===e.get("in")?{schema:e.get("schema",o.a.Map()),parameterContentMediaType:null}:{schema:e.filter((function(e,t){return a.includes(t)})),parameterContentMediaType:null};if(e.get("content")){var r=e.get("content",o.a.Map({})).keySeq(),i=r.first();return{schema:e.getIn(["content",i,"schema"],o.a.Map()),parameterContentMediaType:i}}return{schema:e.get("schema",o.a.Map()),parameterContentMediaType:null}}},function(e,t){e.exports=require("fast-json-patch")},function(e,t){var n={}.toString;e.exports=function(e){return n.call(e).slice(8,-1)}},function(e,t,n){e.exports=!n(79)((function(){return 7!=Object.defineProperty({},"a",{get:function(){return 7}}).a}))},function(e,t){var n=Math.ceil,r=Math.floor;e.exports=function(e){return isNaN(e=+e)?0:(e>0?r:n)(e)}},function(e,t){e.exports={}},function(e,t){var n={}.toString;e.exports=function(e){return n.call(e).slice(8,-1)}},function(e,t){e.exports=!0},function(e,t){e.exports=function(e,t){return{enumerable:!(1&e),configurable:!(2&e),writable:!(4&e),value:t}}},function(e,t,n){var r=n(36),o=n(287),a=n(175),i=n(173)("IE_PROTO"),c=function(){},s=function(){var e,t=n(177)("iframe"),r=a.length;for(t.style.display="none",n(288).appendChild(t),t.src="javascript:",(e=t.contentWindow.document).open(),e.write("<script>document.F=Object<\/script>"),e.close(),s=e.F;r--;)delete s.prototype[a[r]];return s()};e.exports=Object.create||function(e,t){var n;return null!==e?(c.prototype=r(e),n=new c,c.prototype=null,n[i]=e):n=s(),void 0===t?n:o(n,t)}},function(e,t,n){var r=n(48).f,o=n(51),a=n(32)("toStringTag");e.exports=function(e,t,n){e&&!o(e=n?e:e.prototype,a)&&r(e,a,{configurable:!0,value:t})}},function(e,t,n){var r=n(123)("meta"),o=n(30),a=n(51),i=n(48).f,c=0,s=Object.isExtensible||function(){return!0},u=!n(63)((function(){return s(Object.preventExtensions({}))})),l=function(e){i(e,r,{value:{i:"O"+ ++c,w:{}}})},p=e.exports={KEY:r,NEED:!1,fastKey:function(e,t){if(!o(e))return"symbol"==typeof e?e:("string"==typeof e?"S":"P")+e;if(!a(e,r)){if(!s(e))return"F";if(!t)return"E";l(e)}return e[r].i},getWeak:function(e,t){if(!a(e,r)){if(!s(e))return!0;if(!t)return!1;l(e)}return e[r].w},onFreeze:function(e){return u&&p.NEED&&s(e)&&!a(e,r)&&l(e),e}}},function(e,t){t.f={}.propertyIsEnumerable},function(e,t,n){var r=n(107),o=n(103),a=n(52),i=n(178),c=n(51),s=n(285),u=Object.getOwnPropertyDescriptor;t.f=n(39)?u:function(e,t){if(e=a(e),t=i(t,!0),s)try{return u(e,t)}catch(e){}if(c(e,t))return o(!r.f.call(e,t),e[t])}},function(e,t,n){var r=n(30);e.exports=function(e,t){if(!r(e)||e._t!==t)throw TypeError("Incompatible receiver, "+t+" required!");return e}},function(e,t,n){"use strict";n.r(t),n.d(t,"parseYamlConfig",(function(){return a}));var r=n(64),o=n.n(r),a=function(e,t){try{return o.a.safeLoad(e)}catch(e){return t&&t.errActions.newThrownErr(new Error(e)),{}}}},function(e,t,n){"use strict";n.r(t),n.d(t,"makeMappedContainer",(function(){return A})),n.d(t,"render",(function(){return k})),n.d(t,"getComponent",(function(){return T}));var r=n(15),o=n.n(r),a=n(11),i=n.n(a),c=n(16),s=n.n(c),u=n(19),l=n.n(u),p=n(4),f=n.n(p),d=n(3),h=n.n(d),m=n(6),v=n.n(m),g=n(7),y=n.n(g),b=n(0),E=n.n(b),x=n(362),S=n.n(x),w=n(266),j=n(363),_=n.n(j),O=function(e,t,n){var r=function(e,t){return function(n){v()(o,n);var r=y()(o);function o(){return f()(this,o),r.apply(this,arguments)}return h()(o,[{key:"render",value:function(){return E.a.createElement(t,l()({},e(),this.props,this.context))}}]),o}(b.Component)}(e,t),o=Object(w.connect)((function(n,r){var o=s()({},r,e());return(t.prototype.mapStateToProps||function(e){return{state:e}})(n,o)}))(r);return n?function(e,t){return function(n){v()(o,n);var r=y()(o);function o(){return f()(this,o),r.apply(this,arguments)}return h()(o,[{key:"render",value:function(){return E.a.createElement(w.Provider,{store:e},E.a.createElement(t,l()({},this.props,this.context)))}}]),o}(b.Component)}(n,o):o},C=function(e,t,n,r){for(var o in t){var a=t[o];"function"==typeof a&&a(n[o],r[o],e())}},A=function(e,t,n,r,o,a){return function(t){v()(c,t);var r=y()(c);function c(t,n){var o;return f()(this,c),o=r.call(this,t,n),C(e,a,t,{}),o}return h()(c,[{key:"componentWillReceiveProps",value:function(t){C(e,a,t,this.props)}},{key:"render",value:function(){var e=_()(this.props,a?i()(a):[]),t=n(o,"root");return E.a.createElement(t,e)}}]),c}(b.Component)},k=function(e,t,n,r,o){var a=n(e,t,r,"App","root");S.a.render(E.a.createElement(a,null),o)},P=function(e){var t=e.name;return E.a.createElement("div",{className:"fallback"},"😱 ",E.a.createElement("i",null,"Could not render ","t"===t?"this component":t,", see the console."))},I=function(e){var t=function(e){return!(e.prototype&&e.prototype.isReactComponent)}(e)?function(e){return function(t){v()(r,t);var n=y()(r);function r(){return f()(this,r),n.apply(this,arguments)}return h()(r,[{key:"render",value:function(){return e(this.props)}}]),r}(b.Component)}(e):e,n=t.prototype.render;return t.prototype.render=function(){try{for(var e=arguments.length,r=new Array(e),o=0;o<e;o++)r[o]=arguments[o];return n.apply(this,r)}catch(e){return console.error(e),E.a.createElement(P,{error:e,name:t.name})}},t},T=function(e,t,n,r,a){var i=arguments.length>5&&void 0!==arguments[5]?arguments[5]:{};if("string"!=typeof r)throw new TypeError("Need a string, to fetch a component. Was given a "+o()(r));var c=n(r);return c?a?"root"===a?O(e,c,t()):O(e,I(c)):I(c):(i.failSilently||e().log.warn("Could not find component:",r),null)}},function(e,t,n){"use strict";n.r(t),n.d(t,"setHash",(function(){return r}));var r=function(e){return e?history.pushState(null,null,"#".concat(e)):window.location.hash=""}},function(e,t){e.exports=require("redux")},function(e,t,n){var r=n(97),o=n(29)("toStringTag"),a="Arguments"==r(function(){return arguments}());e.exports=function(e){var t,n,i;return void 0===e?"Undefined":null===e?"Null":"string"==typeof(n=function(e,t){try{return e[t]}catch(e){}}(t=Object(e),o))?n:a?r(t):"Object"==(i=r(t))&&"function"==typeof t.callee?"Arguments":i}},function(e,t,n){var r=n(44),o=n(389),a=n(390),i=Object.defineProperty;t.f=n(98)?Object.defineProperty:function(e,t,n){if(r(e),t=a(t,!0),r(n),o)try{return i(e,t,n)}catch(e){}if("get"in n||"set"in n)throw TypeError("Accessors not supported!");return"value"in n&&(e[t]=n.value),e}},function(e,t){var n={}.hasOwnProperty;e.exports=function(e,t){return n.call(e,t)}},function(e,t,n){var r=n(118);e.exports=function(e,t,n){if(r(e),void 0===t)return e;switch(n){case 1:return function(n){return e.call(t,n)};case 2:return function(n,r){return e.call(t,n,r)};case 3:return function(n,r,o){return e.call(t,n,r,o)}}return function(){return e.apply(t,arguments)}}},function(e,t){e.exports=function(e){if("function"!=typeof e)throw TypeError(e+" is not a function!");return e}},function(e,t,n){var r=n(396),o=n(60);e.exports=function(e){return r(o(e))}},function(e,t,n){"use strict";var r=n(114),o=RegExp.prototype.exec;e.exports=function(e,t){var n=e.exec;if("function"==typeof n){var a=n.call(e,t);if("object"!=typeof a)throw new TypeError("RegExp exec method returned something other than an Object or null");return a}if("RegExp"!==r(e))throw new TypeError("RegExp#exec called on incompatible receiver");return o.call(e,t)}},function(e,t,n){"use strict";n(443);var r=n(77),o=n(68),a=n(79),i=n(60),c=n(29),s=n(169),u=c("species"),l=!a((function(){var e=/./;return e.exec=function(){var e=[];return e.groups={a:"7"},e},"7"!=="".replace(e,"$<a>")})),p=function(){var e=/(?:)/,t=e.exec;e.exec=function(){return t.apply(this,arguments)};var n="ab".split(e);return 2===n.length&&"a"===n[0]&&"b"===n[1]}();e.exports=function(e,t,n){var f=c(e),d=!a((function(){var t={};return t[f]=function(){return 7},7!=""[e](t)})),h=d?!a((function(){var t=!1,n=/a/;return n.exec=function(){return t=!0,null},"split"===e&&(n.constructor={},n.constructor[u]=function(){return n}),n[f](""),!t})):void 0;if(!d||!h||"replace"===e&&!l||"split"===e&&!p){var m=/./[f],v=n(i,f,""[e],(function(e,t,n,r,o){return t.exec===s?d&&!o?{done:!0,value:m.call(t,n,r)}:{done:!0,value:e.call(n,t,r)}:{done:!1}})),g=v[0],y=v[1];r(String.prototype,e,g),o(RegExp.prototype,f,2==t?function(e,t){return y.call(e,this,t)}:function(e){return y.call(e,this)})}}},function(e,t,n){var r=n(172),o=Math.min;e.exports=function(e){return e>0?o(r(e),9007199254740991):0}},function(e,t){var n=0,r=Math.random();e.exports=function(e){return"Symbol(".concat(void 0===e?"":e,")_",(++n+r).toString(36))}},function(e,t,n){e.exports=n(459)},function(e,t){t.f=Object.getOwnPropertySymbols},function(e,t){},function(e,t,n){var r=n(101),o=n(32)("toStringTag"),a="Arguments"==r(function(){return arguments}());e.exports=function(e){var t,n,i;return void 0===e?"Undefined":null===e?"Null":"string"==typeof(n=function(e,t){try{return e[t]}catch(e){}}(t=Object(e),o))?n:a?r(t):"Object"==(i=r(t))&&"function"==typeof t.callee?"Arguments":i}},function(e,t,n){var r=n(91),o=n(300);e.exports=function(e,t){if(e){if("string"==typeof e)return o(e,t);var n=Object.prototype.toString.call(e).slice(8,-1);return"Object"===n&&e.constructor&&(n=e.constructor.name),"Map"===n||"Set"===n?r(e):"Arguments"===n||/^(?:Ui|I)nt(?:8|16|32)(?:Clamped)?Array$/.test(n)?o(e,t):void 0}}},function(e,t,n){var r=n(71),o=n(54);e.exports=function(e){return"symbol"==typeof e||o(e)&&"[object Symbol]"==r(e)}},function(e,t,n){var r=n(72)(Object,"create");e.exports=r},function(e,t,n){var r=n(508),o=n(509),a=n(510),i=n(511),c=n(512);function s(e){var t=-1,n=null==e?0:e.length;for(this.clear();++t<n;){var r=e[t];this.set(r[0],r[1])}}s.prototype.clear=r,s.prototype.delete=o,s.prototype.get=a,s.prototype.has=i,s.prototype.set=c,e.exports=s},function(e,t,n){var r=n(74);e.exports=function(e,t){for(var n=e.length;n--;)if(r(e[n][0],t))return n;return-1}},function(e,t,n){var r=n(514);e.exports=function(e,t){var n=e.__data__;return r(t)?n["string"==typeof t?"string":"hash"]:n.map}},function(e,t,n){var r=n(519),o=n(547),a=n(199),i=n(33),c=n(552);e.exports=function(e){return"function"==typeof e?e:null==e?a:"object"==typeof e?i(e)?o(e[0],e[1]):r(e):c(e)}},function(e,t){var n=/^(?:0|[1-9]\d*)$/;e.exports=function(e,t){var r=typeof e;return!!(t=null==t?9007199254740991:t)&&("number"==r||"symbol"!=r&&n.test(e))&&e>-1&&e%1==0&&e<t}},function(e,t){var n=Object.prototype;e.exports=function(e){var t=e&&e.constructor;return e===("function"==typeof t&&t.prototype||n)}},function(e,t,n){var r=n(542),o=n(188),a=n(543),i=n(544),c=n(545),s=n(71),u=n(308),l=u(r),p=u(o),f=u(a),d=u(i),h=u(c),m=s;(r&&"[object DataView]"!=m(new r(new ArrayBuffer(1)))||o&&"[object Map]"!=m(new o)||a&&"[object Promise]"!=m(a.resolve())||i&&"[object Set]"!=m(new i)||c&&"[object WeakMap]"!=m(new c))&&(m=function(e){var t=s(e),n="[object Object]"==t?e.constructor:void 0,r=n?u(n):"";if(r)switch(r){case l:return"[object DataView]";case p:return"[object Map]";case f:return"[object Promise]";case d:return"[object Set]";case h:return"[object WeakMap]"}return t}),e.exports=m},function(e,t,n){var r=n(86),o=n(87);e.exports=function(e,t){for(var n=0,a=(t=r(t,e)).length;null!=e&&n<a;)e=e[o(t[n++])];return n&&n==a?e:void 0}},function(e,t){e.exports=function(e,t,n,r){if(!(e instanceof t)||void 0!==r&&r in e)throw TypeError(n+": incorrect invocation!");return e}},function(e,t,n){var r=n(62);e.exports=function(e,t,n){for(var o in t)n&&e[o]?e[o]=t[o]:r(e,o,t[o]);return e}},function(e,t,n){var r=n(336),o=n(74),a=Object.prototype.hasOwnProperty;e.exports=function(e,t,n){var i=e[t];a.call(e,t)&&o(i,n)&&(void 0!==n||t in e)||r(e,t,n)}},function(e,t,n){var r=n(599),o=n(338);function a(t){return e.exports=a=o?r:function(e){return e.__proto__||r(e)},a(t)}e.exports=a},function(e,t,n){"use strict";var r="function"==typeof Symbol&&"symbol"==typeof Symbol.iterator?function(e){return typeof e}:function(e){return e&&"function"==typeof Symbol&&e.constructor===Symbol&&e!==Symbol.prototype?"symbol":typeof e};function o(e){return null===e?"null":void 0===e?"undefined":"object"===(void 0===e?"undefined":r(e))?Array.isArray(e)?"array":"object":void 0===e?"undefined":r(e)}function a(e){return"object"===o(e)?c(e):"array"===o(e)?i(e):e}function i(e){return e.map(a)}function c(e){var t={};for(var n in e)e.hasOwnProperty(n)&&(t[n]=a(e[n]));return t}function s(e){for(var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:[],n=arguments.length>2&&void 0!==arguments[2]?arguments[2]:{},r={arrayBehaviour:n.arrayBehaviour||"replace"},a=t.map((function(e){return e||{}})),u=e||{},l=0;l<a.length;l++)for(var p=a[l],f=Object.keys(p),d=0;d<f.length;d++){var h=f[d],m=p[h],v=o(m),g=o(u[h]);if("object"===v)if("undefined"!==g){var y="object"===g?u[h]:{};u[h]=s({},[y,c(m)],r)}else u[h]=c(m);else if("array"===v)if("array"===g){var b=i(m);u[h]="merge"===r.arrayBehaviour?u[h].concat(b):b}else u[h]=i(m);else u[h]=m}return u}e.exports=function(e){for(var t=arguments.length,n=Array(t>1?t-1:0),r=1;r<t;r++)n[r-1]=arguments[r];return s(e,n)},e.exports.noMutate=function(){for(var e=arguments.length,t=Array(e),n=0;n<e;n++)t[n]=arguments[n];return s({},t)},e.exports.withOptions=function(e,t,n){return s(e,t,n)}},function(e,t,n){var r=n(518)(n(555));e.exports=r},function(e,t,n){e.exports=n(590)},function(e,t,n){var r=n(640),o=n(344)((function(e,t){return null==e?{}:r(e,t)}));e.exports=o},function(e,t){e.exports=require("buffer")},function(e,t,n){var r=n(141),o=n(89),a=n(654),i=n(85),c=n(136),s=n(73),u=Object.prototype.hasOwnProperty,l=a((function(e,t){if(c(t)||i(t))o(t,s(t),e);else for(var n in t)u.call(t,n)&&r(e,n,t[n])}));e.exports=l},function(e,t){e.exports=require("btoa")},function(e,t,n){"use strict";n.d(t,"a",(function(){return O}));var r=n(11),o=n.n(r),a=n(15),i=n.n(a),c=n(4),s=n.n(c),u=n(3),l=n.n(u),p=n(8),f=n.n(p),d=n(6),h=n.n(d),m=n(7),v=n.n(m),g=n(2),y=n.n(g),b=n(0),E=n.n(b),x=n(75),S=n.n(x),w=(n(9),n(5)),j=n(17),_=n.n(j),O=function(e){h()(n,e);var t=v()(n);function n(e,r){var o;s()(this,n),o=t.call(this,e,r),y()(f()(o),"getDefinitionUrl",(function(){var e=o.props.specSelectors;return new S.a(e.url(),_.a.location).toString()}));var a=(0,e.getConfigs)().validatorUrl;return o.state={url:o.getDefinitionUrl(),validatorUrl:void 0===a?"https://validator.swagger.io/validator":a},o}return l()(n,[{key:"componentWillReceiveProps",value:function(e){var t=(0,e.getConfigs)().validatorUrl;this.setState({url:this.getDefinitionUrl(),validatorUrl:void 0===t?"https://validator.swagger.io/validator":t})}},{key:"render",value:function(){var e=(0,this.props.getConfigs)().spec,t=Object(w.G)(this.state.validatorUrl);return"object"===i()(e)&&o()(e).length?null:this.state.url&&Object(w.F)(this.state.validatorUrl)&&Object(w.F)(this.state.url)?E.a.createElement("span",{className:"float-right"},E.a.createElement("a",{target:"_blank",rel:"noopener noreferrer",href:"".concat(t,"/debug?url=").concat(encodeURIComponent(this.state.url))},E.a.createElement(C,{src:"".concat(t,"?url=").concat(encodeURIComponent(this.state.url)),alt:"Online validator badge"}))):null}}]),n}(E.a.Component),C=function(e){h()(n,e);var t=v()(n);function n(e){var r;return s()(this,n),(r=t.call(this,e)).state={loaded:!1,error:!1},r}return l()(n,[{key:"componentDidMount",value:function(){var e=this,t=new Image;t.onload=function(){e.setState({loaded:!0})},t.onerror=function(){e.setState({error:!0})},t.src=this.props.src}},{key:"componentWillReceiveProps",value:function(e){var t=this;if(e.src!==this.props.src){var n=new Image;n.onload=function(){t.setState({loaded:!0})},n.onerror=function(){t.setState({error:!0})},n.src=e.src}}},{key:"render",value:function(){return this.state.error?E.a.createElement("img",{alt:"Error"}):this.state.loaded?E.a.createElement("img",{src:this.props.src,alt:this.props.alt}):null}}]),n}(E.a.Component)},function(e,t){e.exports=require("react-copy-to-clipboard")},function(e,t,n){"use strict";n.d(t,"a",(function(){return _}));var r=n(19),o=n.n(r),a=n(4),i=n.n(a),c=n(3),s=n.n(c),u=n(8),l=n.n(u),p=n(6),f=n.n(p),d=n(7),h=n.n(d),m=n(2),v=n.n(m),g=n(0),y=n.n(g),b=n(383),E=n.n(b),x=n(20),S=n.n(x),w=n(9),j=n.n(w),_=function(e){f()(r,e);var t=h()(r);function r(){var e;i()(this,r);for(var n=arguments.length,o=new Array(n),a=0;a<n;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(l()(e),"getModelName",(function(e){return-1!==e.indexOf("#/definitions/")?e.replace(/^.*#\/definitions\//,""):-1!==e.indexOf("#/components/schemas/")?e.replace(/^.*#\/components\/schemas\//,""):void 0})),v()(l()(e),"getRefSchema",(function(t){return e.props.specSelectors.findDefinition(t)})),e}return s()(r,[{key:"render",value:function(){var e=this.props,t=e.getComponent,r=e.getConfigs,a=e.specSelectors,i=e.schema,c=e.required,s=e.name,u=e.isRef,l=e.specPath,p=e.displayName,f=e.includeReadOnly,d=e.includeWriteOnly,h=t("ObjectModel"),m=t("ArrayModel"),v=t("PrimitiveModel"),g="object",b=i&&i.get("$$ref");if(!s&&b&&(s=this.getModelName(b)),!i&&b&&(i=this.getRefSchema(s)),!i)return y.a.createElement("span",{className:"model model-title"},y.a.createElement("span",{className:"model-title__text"},p||s),y.a.createElement("img",{src:n(350),height:"20px",width:"20px"}));var E=a.isOAS3()&&i.get("deprecated");switch(u=void 0!==u?u:!!b,g=i&&i.get("type")||g){case"object":return y.a.createElement(h,o()({className:"object"},this.props,{specPath:l,getConfigs:r,schema:i,name:s,deprecated:E,isRef:u,includeReadOnly:f,includeWriteOnly:d}));case"array":return y.a.createElement(m,o()({className:"array"},this.props,{getConfigs:r,schema:i,name:s,deprecated:E,required:c,includeReadOnly:f,includeWriteOnly:d}));case"string":case"number":case"integer":case"boolean":default:return y.a.createElement(v,o()({},this.props,{getComponent:t,getConfigs:r,schema:i,name:s,deprecated:E,required:c}))}}}]),r}(E.a);v()(_,"propTypes",{schema:S.a.map.isRequired,getComponent:j.a.func.isRequired,getConfigs:j.a.func.isRequired,specSelectors:j.a.object.isRequired,name:j.a.string,displayName:j.a.string,isRef:j.a.bool,required:j.a.bool,expandDepth:j.a.number,depth:j.a.number,specPath:S.a.list.isRequired,includeReadOnly:j.a.bool,includeWriteOnly:j.a.bool})},function(e,t){e.exports=require("remarkable")},function(e,t,n){"use strict";n.d(t,"b",(function(){return y}));var r=n(0),o=n.n(r),a=(n(9),n(153)),i=n(384),c=n.n(i),s=/www|@|\:\/\//;function u(e){return/^<\/a\s*>/i.test(e)}function l(){var e=[],t=new c.a({stripPrefix:!1,url:!0,email:!0,replaceFn:function(t){switch(t.getType()){case"url":e.push({text:t.matchedText,url:t.getUrl()});break;case"email":e.push({text:t.matchedText,url:"mailto:"+t.getEmail().replace(/^mailto:/i,"")})}return!1}});return{links:e,autolinker:t}}function p(e){var t,n,r,o,a,i,c,p,f,d,h,m,v,g,y=e.tokens,b=null;for(n=0,r=y.length;n<r;n++)if("inline"===y[n].type)for(h=0,t=(o=y[n].children).length-1;t>=0;t--)if("link_close"!==(a=o[t]).type){if("htmltag"===a.type&&(g=a.content,/^<a[>\s]/i.test(g)&&h>0&&h--,u(a.content)&&h++),!(h>0)&&"text"===a.type&&s.test(a.content)){if(b||(m=(b=l()).links,v=b.autolinker),i=a.content,m.length=0,v.link(i),!m.length)continue;for(c=[],d=a.level,p=0;p<m.length;p++)e.inline.validateLink(m[p].url)&&((f=i.indexOf(m[p].text))&&c.push({type:"text",content:i.slice(0,f),level:d}),c.push({type:"link_open",href:m[p].url,title:"",level:d++}),c.push({type:"text",content:m[p].text,level:d}),c.push({type:"link_close",level:--d}),i=i.slice(f+m[p].text.length));i.length&&c.push({type:"text",content:i,level:d}),y[n].children=o=[].concat(o.slice(0,t),c,o.slice(t+1))}}else for(t--;o[t].level!==a.level&&"link_open"!==o[t].type;)t--}function f(e){e.core.ruler.push("linkify",p)}var d=n(156),h=n.n(d),m=n(45),v=n.n(m);function g(e){var t=e.source,n=e.className,r=void 0===n?"":n,i=e.getConfigs;if("string"!=typeof t)return null;var c=new a.Remarkable({html:!0,typographer:!0,breaks:!0,linkTarget:"_blank"}).use(f);c.core.ruler.disable(["replacements","smartquotes"]);var s=i().useUnsafeMarkdown,u=c.render(t),l=y(u,{useUnsafeMarkdown:s});return t&&u&&l?o.a.createElement("div",{className:v()(r,"markdown"),dangerouslySetInnerHTML:{__html:l}}):null}h.a.addHook&&h.a.addHook("beforeSanitizeElements",(function(e){return e.href&&e.setAttribute("rel","noopener noreferrer"),e})),g.defaultProps={getConfigs:function(){return{useUnsafeMarkdown:!1}}};t.a=g;function y(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{},n=t.useUnsafeMarkdown,r=void 0!==n&&n,o=r,a=r?[]:["style","class"];return r&&!y.hasWarnedAboutDeprecation&&(console.warn("useUnsafeMarkdown display configuration parameter is deprecated since >3.26.0 and will be removed in v4.0.0."),y.hasWarnedAboutDeprecation=!0),h.a.sanitize(e,{ADD_ATTR:["target"],FORBID_TAGS:["style"],ALLOW_DATA_ATTR:o,FORBID_ATTR:a})}y.hasWarnedAboutDeprecation=!1},function(e,t){e.exports=require("qs")},function(e,t){e.exports=require("dompurify")},function(e,t,n){var r=n(59),o=n(37),a=o["__core-js_shared__"]||(o["__core-js_shared__"]={});(e.exports=function(e,t){return a[e]||(a[e]=void 0!==t?t:{})})("versions",[]).push({version:r.version,mode:n(158)?"pure":"global",copyright:"© 2019 Denis Pushkarev (zloirock.ru)"})},function(e,t){e.exports=!1},function(e,t){var n=0,r=Math.random();e.exports=function(e){return"Symbol(".concat(void 0===e?"":e,")_",(++n+r).toString(36))}},function(e,t,n){var r=n(78),o=n(37).document,a=r(o)&&r(o.createElement);e.exports=function(e){return a?o.createElement(e):{}}},function(e,t,n){var r=n(99),o=n(60);e.exports=function(e){return function(t,n){var a,i,c=String(o(t)),s=r(n),u=c.length;return s<0||s>=u?e?"":void 0:(a=c.charCodeAt(s))<55296||a>56319||s+1===u||(i=c.charCodeAt(s+1))<56320||i>57343?e?c.charAt(s):a:e?c.slice(s,s+2):i-56320+(a-55296<<10)+65536}}},function(e,t,n){var r=n(157)("keys"),o=n(159);e.exports=function(e){return r[e]||(r[e]=o(e))}},function(e,t,n){var r=n(115).f,o=n(116),a=n(29)("toStringTag");e.exports=function(e,t,n){e&&!o(e=n?e:e.prototype,a)&&r(e,a,{configurable:!0,value:t})}},function(e,t,n){var r=n(44),o=n(118),a=n(29)("species");e.exports=function(e,t){var n,i=r(e).constructor;return void 0===i||null==(n=r(i)[a])?t:o(n)}},function(e,t,n){"use strict";var r=n(118);function o(e){var t,n;this.promise=new e((function(e,r){if(void 0!==t||void 0!==n)throw TypeError("Bad Promise constructor");t=e,n=r})),this.resolve=r(t),this.reject=r(n)}e.exports.f=function(e){return new o(e)}},function(e,t,n){var r=n(283),o=n(60);e.exports=function(e,t,n){if(r(t))throw TypeError("String#"+n+" doesn't accept regex!");return String(o(e))}},function(e,t,n){var r=n(29)("match");e.exports=function(e){var t=/./;try{"/./"[e](t)}catch(n){try{return t[r]=!1,!"/./"[e](t)}catch(e){}}return!0}},function(e,t,n){"use strict";var r=n(161)(!0);e.exports=function(e,t,n){return t+(n?r(e,t).length:1)}},function(e,t,n){"use strict";var r,o,a=n(444),i=RegExp.prototype.exec,c=String.prototype.replace,s=i,u=(r=/a/,o=/b*/g,i.call(r,"a"),i.call(o,"a"),0!==r.lastIndex||0!==o.lastIndex),l=void 0!==/()??/.exec("")[1];(u||l)&&(s=function(e){var t,n,r,o,s=this;return l&&(n=new RegExp("^"+s.source+"$(?!\\s)",a.call(s))),u&&(t=s.lastIndex),r=i.call(s,e),u&&r&&(s.lastIndex=s.global?r.index+r[0].length:t),l&&r&&r.length>1&&c.call(r[0],n,(function(){for(o=1;o<arguments.length-2;o++)void 0===arguments[o]&&(r[o]=void 0)})),r}),e.exports=s},function(e,t){e.exports=function(e){if(null==e)throw TypeError("Can't call method on  "+e);return e}},function(e,t,n){var r=n(101);e.exports=Object("z").propertyIsEnumerable(0)?Object:function(e){return"String"==r(e)?e.split(""):Object(e)}},function(e,t){var n=Math.ceil,r=Math.floor;e.exports=function(e){return isNaN(e=+e)?0:(e>0?r:n)(e)}},function(e,t,n){var r=n(174)("keys"),o=n(123);e.exports=function(e){return r[e]||(r[e]=o(e))}},function(e,t,n){var r=n(21),o=n(28),a=o["__core-js_shared__"]||(o["__core-js_shared__"]={});(e.exports=function(e,t){return a[e]||(a[e]=void 0!==t?t:{})})("versions",[]).push({version:r.version,mode:n(102)?"pure":"global",copyright:"© 2019 Denis Pushkarev (zloirock.ru)"})},function(e,t){e.exports="constructor,hasOwnProperty,isPrototypeOf,propertyIsEnumerable,toLocaleString,toString,valueOf".split(",")},function(e,t,n){var r=n(24),o=n(21),a=n(63);e.exports=function(e,t){var n=(o.Object||{})[e]||Object[e],i={};i[e]=t(n),r(r.S+r.F*a((function(){n(1)})),"Object",i)}},function(e,t,n){var r=n(30),o=n(28).document,a=r(o)&&r(o.createElement);e.exports=function(e){return a?o.createElement(e):{}}},function(e,t,n){var r=n(30);e.exports=function(e,t){if(!r(e))return e;var n,o;if(t&&"function"==typeof(n=e.toString)&&!r(o=n.call(e)))return o;if("function"==typeof(n=e.valueOf)&&!r(o=n.call(e)))return o;if(!t&&"function"==typeof(n=e.toString)&&!r(o=n.call(e)))return o;throw TypeError("Can't convert object to primitive value")}},function(e,t,n){"use strict";var r=n(102),o=n(24),a=n(180),i=n(62),c=n(82),s=n(456),u=n(105),l=n(181),p=n(32)("iterator"),f=!([].keys&&"next"in[].keys()),d=function(){return this};e.exports=function(e,t,n,h,m,v,g){s(n,t,h);var y,b,E,x=function(e){if(!f&&e in _)return _[e];switch(e){case"keys":case"values":return function(){return new n(this,e)}}return function(){return new n(this,e)}},S=t+" Iterator",w="values"==m,j=!1,_=e.prototype,O=_[p]||_["@@iterator"]||m&&_[m],C=O||x(m),A=m?w?x("entries"):C:void 0,k="Array"==t&&_.entries||O;if(k&&(E=l(k.call(new e)))!==Object.prototype&&E.next&&(u(E,S,!0),r||"function"==typeof E[p]||i(E,p,d)),w&&O&&"values"!==O.name&&(j=!0,C=function(){return O.call(this)}),r&&!g||!f&&!j&&_[p]||i(_,p,C),c[t]=C,c[S]=d,m)if(y={values:w?C:x("values"),keys:v?C:x("keys"),entries:A},g)for(b in y)b in _||a(_,b,y[b]);else o(o.P+o.F*(f||j),t,y);return y}},function(e,t,n){e.exports=n(62)},function(e,t,n){var r=n(51),o=n(69),a=n(173)("IE_PROTO"),i=Object.prototype;e.exports=Object.getPrototypeOf||function(e){return e=o(e),r(e,a)?e[a]:"function"==typeof e.constructor&&e instanceof e.constructor?e.constructor.prototype:e instanceof Object?i:null}},function(e,t,n){t.f=n(32)},function(e,t,n){var r=n(28),o=n(21),a=n(102),i=n(182),c=n(48).f;e.exports=function(e){var t=o.Symbol||(o.Symbol=a?{}:r.Symbol||{});"_"==e.charAt(0)||e in t||c(t,e,{value:i.f(e)})}},function(e,t,n){var r=n(101);e.exports=Array.isArray||function(e){return"Array"==r(e)}},function(e,t,n){var r=n(284),o=n(175).concat("length","prototype");t.f=Object.getOwnPropertyNames||function(e){return r(e,o)}},function(e,t,n){var r=n(127),o=n(32)("iterator"),a=n(82);e.exports=n(21).getIteratorMethod=function(e){if(null!=e)return e[o]||e["@@iterator"]||a[r(e)]}},function(e,t,n){var r=n(497),o=n(513),a=n(515),i=n(516),c=n(517);function s(e){var t=-1,n=null==e?0:e.length;for(this.clear();++t<n;){var r=e[t];this.set(r[0],r[1])}}s.prototype.clear=r,s.prototype.delete=o,s.prototype.get=a,s.prototype.has=i,s.prototype.set=c,e.exports=s},function(e,t,n){var r=n(72)(n(49),"Map");e.exports=r},function(e,t,n){var r=n(131),o=n(521),a=n(522),i=n(523),c=n(524),s=n(525);function u(e){var t=this.__data__=new r(e);this.size=t.size}u.prototype.clear=o,u.prototype.delete=a,u.prototype.get=i,u.prototype.has=c,u.prototype.set=s,e.exports=u},function(e,t){e.exports=function(e,t){for(var n=-1,r=t.length,o=e.length;++n<r;)e[o+n]=t[n];return e}},function(e,t,n){var r=n(535),o=n(315),a=Object.prototype.propertyIsEnumerable,i=Object.getOwnPropertySymbols,c=i?function(e){return null==e?[]:(e=Object(e),r(i(e),(function(t){return a.call(e,t)})))}:o;e.exports=c},function(e,t,n){var r=n(537),o=n(54),a=Object.prototype,i=a.hasOwnProperty,c=a.propertyIsEnumerable,s=r(function(){return arguments}())?r:function(e){return o(e)&&i.call(e,"callee")&&!c.call(e,"callee")};e.exports=s},function(e,t,n){(function(e){var r=n(49),o=n(538),a=t&&!t.nodeType&&t,i=a&&"object"==typeof e&&e&&!e.nodeType&&e,c=i&&i.exports===a?r.Buffer:void 0,s=(c?c.isBuffer:void 0)||o;e.exports=s}).call(this,n(194)(e))},function(e,t){e.exports=function(e){return e.webpackPolyfill||(e.deprecate=function(){},e.paths=[],e.children||(e.children=[]),Object.defineProperty(e,"loaded",{enumerable:!0,get:function(){return e.l}}),Object.defineProperty(e,"id",{enumerable:!0,get:function(){return e.i}}),e.webpackPolyfill=1),e}},function(e,t){e.exports=function(e){return"number"==typeof e&&e>-1&&e%1==0&&e<=9007199254740991}},function(e,t){e.exports=function(e){return function(t){return e(t)}}},function(e,t,n){(function(e){var r=n(303),o=t&&!t.nodeType&&t,a=o&&"object"==typeof e&&e&&!e.nodeType&&e,i=a&&a.exports===o&&r.process,c=function(){try{var e=a&&a.require&&a.require("util").types;return e||i&&i.binding&&i.binding("util")}catch(e){}}();e.exports=c}).call(this,n(194)(e))},function(e,t,n){var r=n(33),o=n(129),a=/\.|\[(?:[^[\]]*|(["'])(?:(?!\1)[^\\]|\\.)*?\1)\]/,i=/^\w*$/;e.exports=function(e,t){if(r(e))return!1;var n=typeof e;return!("number"!=n&&"symbol"!=n&&"boolean"!=n&&null!=e&&!o(e))||(i.test(e)||!a.test(e)||null!=t&&e in Object(t))}},function(e,t){e.exports=function(e){return e}},function(e,t,n){"use strict";var r=n(70);function o(e){var t,n;this.promise=new e((function(e,r){if(void 0!==t||void 0!==n)throw TypeError("Bad Promise constructor");t=e,n=r})),this.resolve=r(t),this.reject=r(n)}e.exports.f=function(e){return new o(e)}},function(e,t,n){var r=n(318)(Object.getPrototypeOf,Object);e.exports=r},function(e,t,n){var r=n(312);e.exports=function(e){var t=new e.constructor(e.byteLength);return new r(t).set(new r(e)),t}},function(e,t,n){var r=n(53),o=n(171),a=n(69),i=n(122),c=n(648);e.exports=function(e,t){var n=1==e,s=2==e,u=3==e,l=4==e,p=6==e,f=5==e||p,d=t||c;return function(t,c,h){for(var m,v,g=a(t),y=o(g),b=r(c,h,3),E=i(y.length),x=0,S=n?d(t,E):s?d(t,0):void 0;E>x;x++)if((f||x in y)&&(v=b(m=y[x],x,g),e))if(n)S[x]=v;else if(v)switch(e){case 3:return!0;case 5:return m;case 6:return x;case 2:S.push(m)}else if(l)return!1;return p?-1:u||l?l:S}}},function(e,t,n){var r=n(484)("toUpperCase");e.exports=r},function(e,t,n){var r=n(187);function o(e,t){if("function"!=typeof e||null!=t&&"function"!=typeof t)throw new TypeError("Expected a function");var n=function(){var r=arguments,o=t?t.apply(this,r):r[0],a=n.cache;if(a.has(o))return a.get(o);var i=e.apply(this,r);return n.cache=a.set(o,i)||a,i};return n.cache=new(o.Cache||r),n}o.Cache=r,e.exports=o},function(e,t,n){"use strict";n.r(t);var r=n(207),o=n(42),a=n(210);t.default=function(e){return{statePlugins:{err:{reducers:Object(r.default)(e),actions:o,selectors:a}}}}},function(e,t,n){"use strict";n.r(t);var r=n(2),o=n.n(r),a=n(16),i=n.n(a),c=n(42),s=n(1),u=n(76),l={line:0,level:"error",message:"Unknown error"};t.default=function(e){var t;return t={},o()(t,c.NEW_THROWN_ERR,(function(t,n){var r=n.payload,o=i()(l,r,{type:"thrown"});return t.update("errors",(function(e){return(e||Object(s.List)()).push(Object(s.fromJS)(o))})).update("errors",(function(t){return Object(u.default)(t,e.getSystem())}))})),o()(t,c.NEW_THROWN_ERR_BATCH,(function(t,n){var r=n.payload;return r=r.map((function(e){return Object(s.fromJS)(i()(l,e,{type:"thrown"}))})),t.update("errors",(function(e){return(e||Object(s.List)()).concat(Object(s.fromJS)(r))})).update("errors",(function(t){return Object(u.default)(t,e.getSystem())}))})),o()(t,c.NEW_SPEC_ERR,(function(t,n){var r=n.payload,o=Object(s.fromJS)(r);return o=o.set("type","spec"),t.update("errors",(function(e){return(e||Object(s.List)()).push(Object(s.fromJS)(o)).sortBy((function(e){return e.get("line")}))})).update("errors",(function(t){return Object(u.default)(t,e.getSystem())}))})),o()(t,c.NEW_SPEC_ERR_BATCH,(function(t,n){var r=n.payload;return r=r.map((function(e){return Object(s.fromJS)(i()(l,e,{type:"spec"}))})),t.update("errors",(function(e){return(e||Object(s.List)()).concat(Object(s.fromJS)(r))})).update("errors",(function(t){return Object(u.default)(t,e.getSystem())}))})),o()(t,c.NEW_AUTH_ERR,(function(t,n){var r=n.payload,o=Object(s.fromJS)(i()({},r));return o=o.set("type","auth"),t.update("errors",(function(e){return(e||Object(s.List)()).push(Object(s.fromJS)(o))})).update("errors",(function(t){return Object(u.default)(t,e.getSystem())}))})),o()(t,c.CLEAR,(function(e,t){var n=t.payload;if(!n||!e.get("errors"))return e;var r=e.get("errors").filter((function(e){return e.keySeq().every((function(t){var r=e.get(t),o=n[t];return!o||r!==o}))}));return e.merge({errors:r})})),o()(t,c.CLEAR_BY,(function(e,t){var n=t.payload;if(!n||"function"!=typeof n)return e;var r=e.get("errors").filter((function(e){return n(e)}));return e.merge({errors:r})})),t}},function(e,t,n){"use strict";function r(e){return e.map((function(e){var t=e.get("message").indexOf("is not of a type(s)");if(t>-1){var n=e.get("message").slice(t+"is not of a type(s)".length).split(",");return e.set("message",e.get("message").slice(0,t)+function(e){return e.reduce((function(e,t,n,r){return n===r.length-1&&r.length>1?e+"or "+t:r[n+1]&&r.length>2?e+t+", ":r[n+1]?e+t+" ":e+t}),"should be a")}(n))}return e}))}n.r(t),n.d(t,"transform",(function(){return r}))},function(e,t,n){"use strict";n.r(t),n.d(t,"transform",(function(){return r}));n(25),n(1);function r(e,t){t.jsSpec;return e}},function(e,t,n){"use strict";n.r(t),n.d(t,"allErrors",(function(){return a})),n.d(t,"lastError",(function(){return i}));var r=n(1),o=n(14),a=Object(o.createSelector)((function(e){return e}),(function(e){return e.get("errors",Object(r.List)())})),i=Object(o.createSelector)(a,(function(e){return e.last()}))},function(e,t,n){"use strict";n.r(t);var r=n(212),o=n(65),a=n(213);t.default=function(){return{statePlugins:{layout:{reducers:r.default,actions:o,selectors:a}}}}},function(e,t,n){"use strict";n.r(t);var r,o=n(2),a=n.n(o),i=n(1),c=n(65);t.default=(r={},a()(r,c.UPDATE_LAYOUT,(function(e,t){return e.set("layout",t.payload)})),a()(r,c.UPDATE_FILTER,(function(e,t){return e.set("filter",t.payload)})),a()(r,c.SHOW,(function(e,t){var n=t.payload.shown,r=Object(i.fromJS)(t.payload.thing);return e.update("shown",Object(i.fromJS)({}),(function(e){return e.set(r,n)}))})),a()(r,c.UPDATE_MODE,(function(e,t){var n=t.payload.thing,r=t.payload.mode;return e.setIn(["modes"].concat(n),(r||"")+"")})),r)},function(e,t,n){"use strict";n.r(t),n.d(t,"current",(function(){return s})),n.d(t,"currentFilter",(function(){return u})),n.d(t,"isShown",(function(){return l})),n.d(t,"whatMode",(function(){return p})),n.d(t,"showSummary",(function(){return f}));var r=n(13),o=n.n(r),a=n(14),i=n(5),c=n(1),s=function(e){return e.get("layout")},u=function(e){return e.get("filter")},l=function(e,t,n){return t=Object(i.w)(t),e.get("shown",Object(c.fromJS)({})).get(Object(c.fromJS)(t),n)},p=function(e,t){var n=arguments.length>2&&void 0!==arguments[2]?arguments[2]:"";return t=Object(i.w)(t),e.getIn(["modes"].concat(o()(t)),n)},f=Object(a.createSelector)((function(e){return e}),(function(e){return!l(e,"editor")}))},function(e,t,n){"use strict";n.r(t);var r=n(215),o=n(26),a=n(56),i=n(216);t.default=function(){return{statePlugins:{spec:{wrapActions:i,reducers:r.default,actions:o,selectors:a}}}}},function(e,t,n){"use strict";n.r(t);var r,o=n(2),a=n.n(o),i=n(16),c=n.n(i),s=n(13),u=n.n(s),l=n(1),p=n(5),f=n(17),d=n.n(f),h=n(56),m=n(26);t.default=(r={},a()(r,m.UPDATE_SPEC,(function(e,t){return"string"==typeof t.payload?e.set("spec",t.payload):e})),a()(r,m.UPDATE_URL,(function(e,t){return e.set("url",t.payload+"")})),a()(r,m.UPDATE_JSON,(function(e,t){return e.set("json",Object(p.i)(t.payload))})),a()(r,m.UPDATE_RESOLVED,(function(e,t){return e.setIn(["resolved"],Object(p.i)(t.payload))})),a()(r,m.UPDATE_RESOLVED_SUBTREE,(function(e,t){var n=t.payload,r=n.value,o=n.path;return e.setIn(["resolvedSubtrees"].concat(u()(o)),Object(p.i)(r))})),a()(r,m.UPDATE_PARAM,(function(e,t){var n=t.payload,r=n.path,o=n.paramName,a=n.paramIn,i=n.param,c=n.value,s=n.isXml,l=i?Object(p.B)(i):"".concat(a,".").concat(o),f=s?"value_xml":"value";return e.setIn(["meta","paths"].concat(u()(r),["parameters",l,f]),c)})),a()(r,m.UPDATE_EMPTY_PARAM_INCLUSION,(function(e,t){var n=t.payload,r=n.pathMethod,o=n.paramName,a=n.paramIn,i=n.includeEmptyValue;if(!o||!a)return console.warn("Warning: UPDATE_EMPTY_PARAM_INCLUSION could not generate a paramKey."),e;var c="".concat(a,".").concat(o);return e.setIn(["meta","paths"].concat(u()(r),["parameter_inclusions",c]),i)})),a()(r,m.VALIDATE_PARAMS,(function(e,t){var n=t.payload,r=n.pathMethod,o=n.isOAS3,a=Object(h.specJsonWithResolvedSubtrees)(e).getIn(["paths"].concat(u()(r))),i=Object(h.parameterValues)(e,r).toJS();return e.updateIn(["meta","paths"].concat(u()(r),["parameters"]),Object(l.fromJS)({}),(function(t){return a.get("parameters",Object(l.List)()).reduce((function(t,n){var a=Object(p.C)(n,i),c=Object(h.parameterInclusionSettingFor)(e,r,n.get("name"),n.get("in")),s=Object(p.L)(n,a,{bypassRequiredCheck:c,isOAS3:o});return t.setIn([Object(p.B)(n),"errors"],Object(l.fromJS)(s))}),t)}))})),a()(r,m.CLEAR_VALIDATE_PARAMS,(function(e,t){var n=t.payload.pathMethod;return e.updateIn(["meta","paths"].concat(u()(n),["parameters"]),Object(l.fromJS)([]),(function(e){return e.map((function(e){return e.set("errors",Object(l.fromJS)([]))}))}))})),a()(r,m.SET_RESPONSE,(function(e,t){var n,r=t.payload,o=r.res,a=r.path,i=r.method;(n=o.error?c()({error:!0,name:o.err.name,message:o.err.message,statusCode:o.err.statusCode},o.err.response):o).headers=n.headers||{};var s=e.setIn(["responses",a,i],Object(p.i)(n));return d.a.Blob&&o.data instanceof d.a.Blob&&(s=s.setIn(["responses",a,i,"text"],o.data)),s})),a()(r,m.SET_REQUEST,(function(e,t){var n=t.payload,r=n.req,o=n.path,a=n.method;return e.setIn(["requests",o,a],Object(p.i)(r))})),a()(r,m.SET_MUTATED_REQUEST,(function(e,t){var n=t.payload,r=n.req,o=n.path,a=n.method;return e.setIn(["mutatedRequests",o,a],Object(p.i)(r))})),a()(r,m.UPDATE_OPERATION_META_VALUE,(function(e,t){var n=t.payload,r=n.path,o=n.value,a=n.key,i=["paths"].concat(u()(r)),c=["meta","paths"].concat(u()(r));return e.getIn(["json"].concat(u()(i)))||e.getIn(["resolved"].concat(u()(i)))||e.getIn(["resolvedSubtrees"].concat(u()(i)))?e.setIn([].concat(u()(c),[a]),Object(l.fromJS)(o)):e})),a()(r,m.CLEAR_RESPONSE,(function(e,t){var n=t.payload,r=n.path,o=n.method;return e.deleteIn(["responses",r,o])})),a()(r,m.CLEAR_REQUEST,(function(e,t){var n=t.payload,r=n.path,o=n.method;return e.deleteIn(["requests",r,o])})),a()(r,m.SET_SCHEME,(function(e,t){var n=t.payload,r=n.scheme,o=n.path,a=n.method;return o&&a?e.setIn(["scheme",o,a],r):o||a?void 0:e.setIn(["scheme","_defaultScheme"],r)})),r)},function(e,t,n){"use strict";n.r(t),n.d(t,"updateSpec",(function(){return c})),n.d(t,"updateJsonSpec",(function(){return s})),n.d(t,"executeRequest",(function(){return u})),n.d(t,"validateParams",(function(){return l}));var r=n(11),o=n.n(r),a=n(25),i=n.n(a),c=function(e,t){var n=t.specActions;return function(){e.apply(void 0,arguments),n.parseToJson.apply(n,arguments)}},s=function(e,t){var n=t.specActions;return function(){for(var t=arguments.length,r=new Array(t),a=0;a<t;a++)r[a]=arguments[a];e.apply(void 0,r),n.invalidateResolvedSubtreeCache();var c=r[0],s=i()(c,["paths"])||{},u=o()(s);u.forEach((function(e){i()(s,[e]).$ref&&n.requestResolvedSubtree(["paths",e])})),n.requestResolvedSubtree(["components","securitySchemes"])}},u=function(e,t){var n=t.specActions;return function(t){return n.logRequest(t),e(t)}},l=function(e,t){var n=t.specSelectors;return function(t){return e(t,n.isOAS3())}}},function(e,t,n){"use strict";n.r(t);var r=n(111),o=n(5);t.default=function(e){var t=e.getComponents,n=e.getStore,a=e.getSystem,i=r.getComponent,c=r.render,s=r.makeMappedContainer,u=Object(o.v)(i.bind(null,a,n,t));return{rootInjects:{getComponent:u,makeMappedContainer:Object(o.v)(s.bind(null,a,n,u,t)),render:c.bind(null,a,n,i,t)}}}},function(e,t,n){var r=n(71),o=n(201),a=n(54),i=Function.prototype,c=Object.prototype,s=i.toString,u=c.hasOwnProperty,l=s.call(Object);e.exports=function(e){if(!a(e)||"[object Object]"!=r(e))return!1;var t=o(e);if(null===t)return!0;var n=u.call(t,"constructor")&&t.constructor;return"function"==typeof n&&n instanceof n&&s.call(n)==l}},function(e,t,n){"use strict";n.r(t);var r=n(93);t.default=function(){return{fn:r}}},function(e,t,n){"use strict";n.r(t),t.default=function(e){var t=e.configs,n={debug:0,info:1,log:2,warn:3,error:4},r=function(e){return n[e]||-1},o=t.logLevel,a=r(o);function i(e){for(var t,n=arguments.length,o=new Array(n>1?n-1:0),i=1;i<n;i++)o[i-1]=arguments[i];r(e)>=a&&(t=console)[e].apply(t,o)}return i.warn=i.bind(null,"warn"),i.error=i.bind(null,"error"),i.info=i.bind(null,"info"),i.debug=i.bind(null,"debug"),{rootInjects:{log:i}}}},function(e,t,n){"use strict";n.r(t),n.d(t,"loaded",(function(){return r}));var r=function(e,t){return function(){e.apply(void 0,arguments);var n=t.getConfigs().withCredentials;void 0!==n&&(t.fn.fetch.withCredentials="string"==typeof n?"true"===n:!!n)}}},function(e,t,n){"use strict";n.r(t),n.d(t,"preauthorizeBasic",(function(){return u})),n.d(t,"preauthorizeApiKey",(function(){return l}));var r=n(2),o=n.n(r),a=n(223),i=n(58),c=n(224),s=n(225);function u(e,t,n,r){var a=e.authActions.authorize,i=e.specSelectors,c=i.specJson,s=(0,i.isOAS3)()?["components","securitySchemes"]:["securityDefinitions"],u=c().getIn([].concat(s,[t]));return u?a(o()({},t,{value:{username:n,password:r},schema:u.toJS()})):null}function l(e,t,n){var r=e.authActions.authorize,a=e.specSelectors,i=a.specJson,c=(0,a.isOAS3)()?["components","securitySchemes"]:["securityDefinitions"],s=i().getIn([].concat(c,[t]));return s?r(o()({},t,{value:n,schema:s.toJS()})):null}t.default=function(){return{afterLoad:function(e){this.rootInjects=this.rootInjects||{},this.rootInjects.initOAuth=e.authActions.configureAuth,this.rootInjects.preauthorizeApiKey=l.bind(null,e),this.rootInjects.preauthorizeBasic=u.bind(null,e)},statePlugins:{auth:{reducers:a.default,actions:i,selectors:c},spec:{wrapActions:s}}}}},function(e,t,n){"use strict";n.r(t);var r,o=n(2),a=n.n(o),i=n(16),c=n.n(i),s=n(12),u=n.n(s),l=n(1),p=n(5),f=n(58);t.default=(r={},a()(r,f.SHOW_AUTH_POPUP,(function(e,t){var n=t.payload;return e.set("showDefinitions",n)})),a()(r,f.AUTHORIZE,(function(e,t){var n=t.payload,r=Object(l.fromJS)(n),o=e.get("authorized")||Object(l.Map)();return r.entrySeq().forEach((function(t){var n=u()(t,2),r=n[0],a=n[1];if(!Object(p.s)(a.getIn))return e.set("authorized",o);var i=a.getIn(["schema","type"]);if("apiKey"===i||"http"===i)o=o.set(r,a);else if("basic"===i){var c=a.getIn(["value","username"]),s=a.getIn(["value","password"]);o=(o=o.setIn([r,"value"],{username:c,header:"Basic "+Object(p.a)(c+":"+s)})).setIn([r,"schema"],a.get("schema"))}})),e.set("authorized",o)})),a()(r,f.AUTHORIZE_OAUTH2,(function(e,t){var n,r=t.payload,o=r.auth,a=r.token;return o.token=c()({},a),n=Object(l.fromJS)(o),e.setIn(["authorized",n.get("name")],n)})),a()(r,f.LOGOUT,(function(e,t){var n=t.payload,r=e.get("authorized").withMutations((function(e){n.forEach((function(t){e.delete(t)}))}));return e.set("authorized",r)})),a()(r,f.CONFIGURE_AUTH,(function(e,t){var n=t.payload;return e.set("configs",n)})),r)},function(e,t,n){"use strict";n.r(t),n.d(t,"shownDefinitions",(function(){return l})),n.d(t,"definitionsToAuthorize",(function(){return p})),n.d(t,"getDefinitionsByNames",(function(){return f})),n.d(t,"definitionsForRequirements",(function(){return d})),n.d(t,"authorized",(function(){return h})),n.d(t,"isAuthorized",(function(){return m})),n.d(t,"getConfigs",(function(){return v}));var r=n(11),o=n.n(r),a=n(12),i=n.n(a),c=n(14),s=n(1),u=function(e){return e},l=Object(c.createSelector)(u,(function(e){return e.get("showDefinitions")})),p=Object(c.createSelector)(u,(function(){return function(e){var t=e.specSelectors.securityDefinitions()||Object(s.Map)({}),n=Object(s.List)();return t.entrySeq().forEach((function(e){var t=i()(e,2),r=t[0],o=t[1],a=Object(s.Map)();a=a.set(r,o),n=n.push(a)})),n}})),f=function(e,t){return function(e){var n=e.specSelectors;console.warn("WARNING: getDefinitionsByNames is deprecated and will be removed in the next major version.");var r=n.securityDefinitions(),o=Object(s.List)();return t.valueSeq().forEach((function(e){var t=Object(s.Map)();e.entrySeq().forEach((function(e){var n,o=i()(e,2),a=o[0],c=o[1],s=r.get(a);"oauth2"===s.get("type")&&c.size&&((n=s.get("scopes")).keySeq().forEach((function(e){c.contains(e)||(n=n.delete(e))})),s=s.set("allowedScopes",n)),t=t.set(a,s)})),o=o.push(t)})),o}},d=function(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:Object(s.List)();return function(e){return(e.authSelectors.definitionsToAuthorize()||Object(s.List)()).filter((function(e){return t.some((function(t){return t.get(e.keySeq().first())}))}))}},h=Object(c.createSelector)(u,(function(e){return e.get("authorized")||Object(s.Map)()})),m=function(e,t){return function(e){var n=e.authSelectors.authorized();return s.List.isList(t)?!!t.toJS().filter((function(e){return-1===o()(e).map((function(e){return!!n.get(e)})).indexOf(!1)})).length:null}},v=Object(c.createSelector)(u,(function(e){return e.get("configs")}))},function(e,t,n){"use strict";n.r(t),n.d(t,"execute",(function(){return a}));var r=n(18),o=n.n(r),a=function(e,t){var n=t.authSelectors,r=t.specSelectors;return function(t){var a=t.path,i=t.method,c=t.operation,s=t.extras,u={authorized:n.authorized()&&n.authorized().toJS(),definitions:r.securityDefinitions()&&r.securityDefinitions().toJS(),specSecurity:r.security()&&r.security().toJS()};return e(o()({path:a,method:i,operation:c,securities:u},s))}}},function(e,t,n){"use strict";n.r(t);var r=n(5);t.default=function(){return{fn:{shallowEqualKeys:r.H}}}},function(e,t,n){"use strict";n.r(t),n.d(t,"default",(function(){return p}));var r=n(22),o=n.n(r),a=n(16),i=n.n(a),c=n(14),s=n(1),u=n(17),l=n.n(u);function p(e){var t=e.fn;return{statePlugins:{spec:{actions:{download:function(e){return function(n){var r=n.errActions,o=n.specSelectors,a=n.specActions,c=n.getConfigs,s=t.fetch,u=c();function p(t){if(t instanceof Error||t.status>=400)return a.updateLoadingStatus("failed"),r.newThrownErr(i()(new Error((t.message||t.statusText)+" "+e),{source:"fetch"})),void(!t.status&&t instanceof Error&&function(){try{var t;if("URL"in l.a?t=new URL(e):(t=document.createElement("a")).href=e,"https:"!==t.protocol&&"https:"===l.a.location.protocol){var n=i()(new Error("Possible mixed-content issue? The page was loaded over https:// but a ".concat(t.protocol,"// URL was specified. Check that you are not attempting to load mixed content.")),{source:"fetch"});return void r.newThrownErr(n)}if(t.origin!==l.a.location.origin){var o=i()(new Error("Possible cross-origin (CORS) issue? The URL origin (".concat(t.origin,") does not match the page (").concat(l.a.location.origin,"). Check the server returns the correct 'Access-Control-Allow-*' headers.")),{source:"fetch"});r.newThrownErr(o)}}catch(e){return}}());a.updateLoadingStatus("success"),a.updateSpec(t.text),o.url()!==e&&a.updateUrl(e)}e=e||o.url(),a.updateLoadingStatus("loading"),r.clear({source:"fetch"}),s({url:e,loadSpec:!0,requestInterceptor:u.requestInterceptor||function(e){return e},responseInterceptor:u.responseInterceptor||function(e){return e},credentials:"same-origin",headers:{Accept:"application/json,*/*"}}).then(p,p)}},updateLoadingStatus:function(e){var t=[null,"loading","failed","success","failedConfig"];return-1===t.indexOf(e)&&console.error("Error: ".concat(e," is not one of ").concat(o()(t))),{type:"spec_update_loading_status",payload:e}}},reducers:{spec_update_loading_status:function(e,t){return"string"==typeof t.payload?e.set("loadingStatus",t.payload):e}},selectors:{loadingStatus:Object(c.createSelector)((function(e){return e||Object(s.Map)()}),(function(e){return e.get("loadingStatus")||null}))}}}}}},function(e,t,n){"use strict";n.r(t),n.d(t,"downloadConfig",(function(){return o})),n.d(t,"getConfigByUrl",(function(){return a}));var r=n(110),o=function(e){return function(t){return(0,t.fn.fetch)(e)}},a=function(e,t){return function(n){var o=n.specActions;if(e)return o.downloadConfig(e).then(a,a);function a(n){n instanceof Error||n.status>=400?(o.updateLoadingStatus("failedConfig"),o.updateLoadingStatus("failedConfig"),o.updateUrl(""),console.error(n.statusText+" "+e.url),t(null)):t(Object(r.parseYamlConfig)(n.text))}}}},function(e,t,n){"use strict";n.r(t),n.d(t,"get",(function(){return a}));var r=n(10),o=n.n(r),a=function(e,t){return e.getIn(o()(t)?t:[t])}},function(e,t,n){"use strict";n.r(t);var r,o=n(2),a=n.n(o),i=n(1),c=n(94);t.default=(r={},a()(r,c.UPDATE_CONFIGS,(function(e,t){return e.merge(Object(i.fromJS)(t.payload))})),a()(r,c.TOGGLE_CONFIGS,(function(e,t){var n=t.payload,r=e.get(n);return e.set(n,!r)})),r)},function(e,t,n){"use strict";n.r(t);var r=n(232),o=n(233),a=n(234);t.default=function(){return[r.default,{statePlugins:{configs:{wrapActions:{loaded:function(e,t){return function(){e.apply(void 0,arguments);var n=decodeURIComponent(window.location.hash);t.layoutActions.parseDeepLinkHash(n)}}}}},wrapComponents:{operation:o.default,OperationTag:a.default}}]}},function(e,t,n){"use strict";n.r(t),n.d(t,"show",(function(){return v})),n.d(t,"scrollTo",(function(){return g})),n.d(t,"parseDeepLinkHash",(function(){return y})),n.d(t,"readyToScroll",(function(){return b})),n.d(t,"scrollToElement",(function(){return E})),n.d(t,"clearScrollTo",(function(){return x}));var r,o=n(2),a=n.n(o),i=n(12),c=n.n(i),s=n(10),u=n.n(s),l=n(112),p=n(372),f=n.n(p),d=n(5),h=n(1),m=n.n(h),v=function(e,t){var n=t.getConfigs,r=t.layoutSelectors;return function(){for(var t=arguments.length,o=new Array(t),a=0;a<t;a++)o[a]=arguments[a];if(e.apply(void 0,o),n().deepLinking)try{var i=o[0],s=o[1];i=u()(i)?i:[i];var p=r.urlHashArrayFromIsShownKey(i);if(!p.length)return;var f=c()(p,2),h=f[0],m=f[1];if(!s)return Object(l.setHash)("/");2===p.length?Object(l.setHash)(Object(d.d)("/".concat(encodeURIComponent(h),"/").concat(encodeURIComponent(m)))):1===p.length&&Object(l.setHash)(Object(d.d)("/".concat(encodeURIComponent(h))))}catch(e){console.error(e)}}},g=function(e){return{type:"layout_scroll_to",payload:u()(e)?e:[e]}},y=function(e){return function(t){var n=t.layoutActions,r=t.layoutSelectors;if((0,t.getConfigs)().deepLinking&&e){var o=e.slice(1);"!"===o[0]&&(o=o.slice(1)),"/"===o[0]&&(o=o.slice(1));var a=o.split("/").map((function(e){return e||""})),i=r.isShownKeyFromUrlHashArray(a),s=c()(i,3),u=s[0],l=s[1],p=void 0===l?"":l,f=s[2],d=void 0===f?"":f;if("operations"===u){var h=r.isShownKeyFromUrlHashArray([p]);p.indexOf("_")>-1&&(console.warn("Warning: escaping deep link whitespace with `_` will be unsupported in v4.0, use `%20` instead."),n.show(h.map((function(e){return e.replace(/_/g," ")})),!0)),n.show(h,!0)}(p.indexOf("_")>-1||d.indexOf("_")>-1)&&(console.warn("Warning: escaping deep link whitespace with `_` will be unsupported in v4.0, use `%20` instead."),n.show(i.map((function(e){return e.replace(/_/g," ")})),!0)),n.show(i,!0),n.scrollTo(i)}}},b=function(e,t){return function(n){var r=n.layoutSelectors.getScrollToKey();m.a.is(r,Object(h.fromJS)(e))&&(n.layoutActions.scrollToElement(t),n.layoutActions.clearScrollTo())}},E=function(e,t){return function(n){try{t=t||n.fn.getScrollParent(e),f.a.createScroller(t).to(e)}catch(e){console.error(e)}}},x=function(){return{type:"layout_clear_scroll"}};t.default={fn:{getScrollParent:function(e,t){var n=document.documentElement,r=getComputedStyle(e),o="absolute"===r.position,a=t?/(auto|scroll|hidden)/:/(auto|scroll)/;if("fixed"===r.position)return n;for(var i=e;i=i.parentElement;)if(r=getComputedStyle(i),(!o||"static"!==r.position)&&a.test(r.overflow+r.overflowY+r.overflowX))return i;return n}},statePlugins:{layout:{actions:{scrollToElement:E,scrollTo:g,clearScrollTo:x,readyToScroll:b,parseDeepLinkHash:y},selectors:{getScrollToKey:function(e){return e.get("scrollToKey")},isShownKeyFromUrlHashArray:function(e,t){var n=c()(t,2),r=n[0],o=n[1];return o?["operations",r,o]:r?["operations-tag",r]:[]},urlHashArrayFromIsShownKey:function(e,t){var n=c()(t,3),r=n[0],o=n[1],a=n[2];return"operations"==r?[o,a]:"operations-tag"==r?[o]:[]}},reducers:(r={},a()(r,"layout_scroll_to",(function(e,t){return e.set("scrollToKey",m.a.fromJS(t.payload))})),a()(r,"layout_clear_scroll",(function(e){return e.delete("scrollToKey")})),r),wrapActions:{show:v}}}}},function(e,t,n){"use strict";n.r(t);var r=n(4),o=n.n(r),a=n(3),i=n.n(a),c=n(8),s=n.n(c),u=n(6),l=n.n(u),p=n(7),f=n.n(p),d=n(2),h=n.n(d),m=n(0),v=n.n(m);n(20);t.default=function(e,t){return function(n){l()(a,n);var r=f()(a);function a(){var e;o()(this,a);for(var n=arguments.length,i=new Array(n),c=0;c<n;c++)i[c]=arguments[c];return e=r.call.apply(r,[this].concat(i)),h()(s()(e),"onLoad",(function(n){var r=e.props.operation,o=r.toObject(),a=o.tag,i=o.operationId,c=r.toObject().isShownKey;c=c||["operations",a,i],t.layoutActions.readyToScroll(c,n)})),e}return i()(a,[{key:"render",value:function(){return v.a.createElement("span",{ref:this.onLoad},v.a.createElement(e,this.props))}}]),a}(v.a.Component)}},function(e,t,n){"use strict";n.r(t);var r=n(4),o=n.n(r),a=n(3),i=n.n(a),c=n(8),s=n.n(c),u=n(6),l=n.n(u),p=n(7),f=n.n(p),d=n(2),h=n.n(d),m=n(0),v=n.n(m);n(9);t.default=function(e,t){return function(n){l()(a,n);var r=f()(a);function a(){var e;o()(this,a);for(var n=arguments.length,i=new Array(n),c=0;c<n;c++)i[c]=arguments[c];return e=r.call.apply(r,[this].concat(i)),h()(s()(e),"onLoad",(function(n){var r=["operations-tag",e.props.tag];t.layoutActions.readyToScroll(r,n)})),e}return i()(a,[{key:"render",value:function(){return v.a.createElement("span",{ref:this.onLoad},v.a.createElement(e,this.props))}}]),a}(v.a.Component)}},function(e,t,n){"use strict";n.r(t);var r=n(236);t.default=function(){return{fn:{opsFilter:r.default}}}},function(e,t,n){"use strict";n.r(t),t.default=function(e,t){return e.filter((function(e,n){return-1!==n.indexOf(t)}))}},function(e,t,n){"use strict";n.r(t);var r=!1;t.default=function(){return{statePlugins:{spec:{wrapActions:{updateSpec:function(e){return function(){return r=!0,e.apply(void 0,arguments)}},updateJsonSpec:function(e,t){return function(){var n=t.getConfigs().onComplete;return r&&"function"==typeof n&&(setTimeout(n,0),r=!1),e.apply(void 0,arguments)}}}}}}}},function(e,t,n){"use strict";n.r(t);var r=n(239),o=n(240),a=n(241),i=n(242),c=n(251),s=n(43),u=n(258),l=n(259);t.default=function(){return{components:i.default,wrapComponents:c.default,statePlugins:{spec:{wrapSelectors:r,selectors:a},auth:{wrapSelectors:o},oas3:{actions:s,reducers:l.default,selectors:u}}}}},function(e,t,n){"use strict";n.r(t),n.d(t,"definitions",(function(){return d})),n.d(t,"hasHost",(function(){return h})),n.d(t,"securityDefinitions",(function(){return m})),n.d(t,"host",(function(){return v})),n.d(t,"basePath",(function(){return g})),n.d(t,"consumes",(function(){return y})),n.d(t,"produces",(function(){return b})),n.d(t,"schemes",(function(){return E})),n.d(t,"servers",(function(){return x})),n.d(t,"isOAS3",(function(){return S})),n.d(t,"isSwagger2",(function(){return w}));var r=n(14),o=n(56),a=n(1),i=n(23);function c(e){return function(t,n){return function(){var r=n.getSystem().specSelectors.specJson();return Object(i.isOAS3)(r)?e.apply(void 0,arguments):t.apply(void 0,arguments)}}}var s=function(e){return e||Object(a.Map)()},u=c(Object(r.createSelector)((function(){return null}))),l=Object(r.createSelector)(s,(function(e){return e.get("json",Object(a.Map)())})),p=Object(r.createSelector)(s,(function(e){return e.get("resolved",Object(a.Map)())})),f=function(e){var t=p(e);return t.count()<1&&(t=l(e)),t},d=c(Object(r.createSelector)(f,(function(e){var t=e.getIn(["components","schemas"]);return a.Map.isMap(t)?t:Object(a.Map)()}))),h=c((function(e){return f(e).hasIn(["servers",0])})),m=c(Object(r.createSelector)(o.specJsonWithResolvedSubtrees,(function(e){return e.getIn(["components","securitySchemes"])||null}))),v=u,g=u,y=u,b=u,E=u,x=c(Object(r.createSelector)(f,(function(e){return e.getIn(["servers"])||Object(a.Map)()}))),S=function(e,t){return function(){var e=t.getSystem().specSelectors.specJson();return Object(i.isOAS3)(a.Map.isMap(e)?e:Object(a.Map)())}},w=function(e,t){return function(){var e=t.getSystem().specSelectors.specJson();return Object(i.isSwagger2)(a.Map.isMap(e)?e:Object(a.Map)())}}},function(e,t,n){"use strict";n.r(t),n.d(t,"definitionsToAuthorize",(function(){return p}));var r=n(2),o=n.n(r),a=n(12),i=n.n(a),c=n(14),s=n(1),u=n(23);var l,p=(l=Object(c.createSelector)((function(e){return e}),(function(e){return e.specSelectors.securityDefinitions()}),(function(e,t){var n=Object(s.List)();return t?(t.entrySeq().forEach((function(e){var t=i()(e,2),r=t[0],a=t[1],c=a.get("type");"oauth2"===c&&a.get("flows").entrySeq().forEach((function(e){var t=i()(e,2),c=t[0],u=t[1],l=Object(s.fromJS)({flow:c,authorizationUrl:u.get("authorizationUrl"),tokenUrl:u.get("tokenUrl"),scopes:u.get("scopes"),type:a.get("type")});n=n.push(new s.Map(o()({},r,l.filter((function(e){return void 0!==e})))))})),"http"!==c&&"apiKey"!==c||(n=n.push(new s.Map(o()({},r,a))))})),n):n})),function(e,t){return function(n){for(var r=t.getSystem().specSelectors.specJson(),o=arguments.length,a=new Array(o>1?o-1:0),i=1;i<o;i++)a[i-1]=arguments[i];return Object(u.isOAS3)(r)?l.apply(void 0,[t].concat(a)):e.apply(void 0,a)}})},function(e,t,n){"use strict";n.r(t),n.d(t,"servers",(function(){return l})),n.d(t,"isSwagger2",(function(){return p}));var r=n(14),o=n(1),a=n(23);var i,c=function(e){return e||Object(o.Map)()},s=Object(r.createSelector)(c,(function(e){return e.get("json",Object(o.Map)())})),u=Object(r.createSelector)(c,(function(e){return e.get("resolved",Object(o.Map)())})),l=(i=Object(r.createSelector)((function(e){var t=u(e);return t.count()<1&&(t=s(e)),t}),(function(e){return e.getIn(["servers"])||Object(o.Map)()})),function(){return function(e){var t=e.getSystem().specSelectors.specJson();if(Object(a.isOAS3)(t)){for(var n=arguments.length,r=new Array(n>1?n-1:0),o=1;o<n;o++)r[o-1]=arguments[o];return i.apply(void 0,r)}return null}}),p=function(e,t){return function(){var e=t.getSystem().specSelectors.specJson();return Object(a.isSwagger2)(e)}}},function(e,t,n){"use strict";n.r(t);var r=n(243),o=n(244),a=n(245),i=n(246),c=n(247),s=n(248),u=n(249),l=n(250);t.default={Callbacks:r.default,HttpAuth:u.default,RequestBody:o.default,Servers:i.default,ServersContainer:c.default,RequestBodyEditor:s.default,OperationServers:l.default,operationLink:a.default}},function(e,t,n){"use strict";n.r(t);var r=n(19),o=n.n(r),a=n(12),i=n.n(a),c=n(0),s=n.n(c),u=(n(9),n(20),n(1));t.default=function(e){var t=e.callbacks,n=e.getComponent,r=e.specPath,a=n("OperationContainer",!0);if(!t)return s.a.createElement("span",null,"No callbacks");var c=t.entrySeq().map((function(t){var n=i()(t,2),c=n[0],l=n[1];return s.a.createElement("div",{key:c},s.a.createElement("h2",null,c),l.entrySeq().map((function(t){var n=i()(t,2),l=n[0],p=n[1];return"$$ref"===l?null:s.a.createElement("div",{key:l},p.entrySeq().map((function(t){var n=i()(t,2),p=n[0],f=n[1];if("$$ref"===p)return null;var d=Object(u.fromJS)({operation:f});return s.a.createElement(a,o()({},e,{op:d,key:p,tag:"",method:p,path:l,specPath:r.push(c,l,p),allowTryItOut:!1}))})))})))}));return s.a.createElement("div",null,c)}},function(e,t,n){"use strict";n.r(t);var r=n(12),o=n.n(r),a=n(0),i=n.n(a),c=(n(9),n(20),n(1)),s=n(5);function u(e,t,n){var r=e.getIn(["content",t]),o=r.get("schema").toJS(),a=void 0!==r.get("example")?Object(s.J)(r.get("example")):null,i=r.getIn(["examples",n,"value"]);return r.get("examples")?Object(s.J)(i)||"":Object(s.J)(a||Object(s.o)(o,t,{includeWriteOnly:!0})||"")}t.default=function(e){var t=e.requestBody,n=e.requestBodyValue,r=e.requestBodyInclusionSetting,a=e.requestBodyErrors,l=e.getComponent,p=e.getConfigs,f=e.specSelectors,d=e.fn,h=e.contentType,m=e.isExecute,v=e.specPath,g=e.onChange,y=e.onChangeIncludeEmpty,b=e.activeExamplesKey,E=e.updateActiveExamplesKey,x=function(e){var t={key:e,shouldDispatchInit:!1,defaultValue:!0};return"no value"===r.get(e,"no value")&&(t.shouldDispatchInit=!0),t},S=l("Markdown",!0),w=l("modelExample"),j=l("RequestBodyEditor"),_=l("highlightCode"),O=l("ExamplesSelectValueRetainer"),C=l("Example"),A=l("ParameterIncludeEmpty"),k=p().showCommonExtensions,P=t&&t.get("description")||null,I=t&&t.get("content")||new c.OrderedMap;h=h||I.keySeq().first()||"";var T=I.get(h,Object(c.OrderedMap)()),N=T.get("schema",Object(c.OrderedMap)()),R=T.get("examples",null);if(a=c.List.isList(a)?a:Object(c.List)(),!T.size)return null;var M="object"===T.getIn(["schema","type"]);if("application/octet-stream"===h||0===h.indexOf("image/")||0===h.indexOf("audio/")||0===h.indexOf("video/")){var D=l("Input");return m?i.a.createElement(D,{type:"file",onChange:function(e){g(e.target.files[0])}}):i.a.createElement("i",null,"Example values are not available for ",i.a.createElement("code",null,"application/octet-stream")," media types.")}if(M&&("application/x-www-form-urlencoded"===h||0===h.indexOf("multipart/"))&&N.get("properties",Object(c.OrderedMap)()).size>0){var q=l("JsonSchemaForm"),L=l("ParameterExt"),B=N.get("properties",Object(c.OrderedMap)());return n=c.Map.isMap(n)?n:Object(c.OrderedMap)(),i.a.createElement("div",{className:"table-container"},P&&i.a.createElement(S,{source:P}),i.a.createElement("table",null,i.a.createElement("tbody",null,B.map((function(e,t){var u=k?Object(s.l)(e):null,p=N.get("required",Object(c.List)()).includes(t),f=e.get("type"),h=e.get("format"),v=e.get("description"),b=n.getIn([t,"value"]),E=n.getIn([t,"errors"])||a,w=e.get("default")||e.get("example")||"";""===w&&"object"===f&&(w=Object(s.o)(e,!1,{includeWriteOnly:!0})),"string"!=typeof w&&"object"===f&&(w=Object(s.J)(w));var j="string"===f&&("binary"===h||"base64"===h);return i.a.createElement("tr",{key:t,className:"parameters","data-property-name":t},i.a.createElement("td",{className:"parameters-col_name"},i.a.createElement("div",{className:p?"parameter__name required":"parameter__name"},t,p?i.a.createElement("span",null," *"):null),i.a.createElement("div",{className:"parameter__type"},f,h&&i.a.createElement("span",{className:"prop-format"},"($",h,")"),k&&u.size?u.entrySeq().map((function(e){var t=o()(e,2),n=t[0],r=t[1];return i.a.createElement(L,{key:"".concat(n,"-").concat(r),xKey:n,xVal:r})})):null),i.a.createElement("div",{className:"parameter__deprecated"},e.get("deprecated")?"deprecated":null)),i.a.createElement("td",{className:"parameters-col_description"},i.a.createElement(S,{source:v}),m?i.a.createElement("div",null,i.a.createElement(q,{fn:d,dispatchInitialValue:!j,schema:e,description:t,getComponent:l,value:void 0===b?w:b,required:p,errors:E,onChange:function(e){g(e,[t])}}),p?null:i.a.createElement(A,{onChange:function(e){return y(t,e)},isIncluded:r.get(t)||!1,isIncludedOptions:x(t),isDisabled:!Object(s.q)(b)})):null))})))))}return i.a.createElement("div",null,P&&i.a.createElement(S,{source:P}),R?i.a.createElement(O,{examples:R,currentKey:b,currentUserInputValue:n,onSelect:function(e){E(e)},updateValue:g,defaultToFirstExample:!0,getComponent:l}):null,m?i.a.createElement("div",null,i.a.createElement(j,{value:n,errors:a,defaultValue:u(t,h,b),onChange:g,getComponent:l})):i.a.createElement(w,{getComponent:l,getConfigs:p,specSelectors:f,expandDepth:1,isExecute:m,schema:T.get("schema"),specPath:v.push("content",h),example:i.a.createElement(_,{className:"body-param__example",value:Object(s.J)(n)||u(t,h,b)}),includeWriteOnly:!0}),R?i.a.createElement(C,{example:R.get(b),getComponent:l}):null)}},function(e,t,n){"use strict";n.r(t);var r=n(22),o=n.n(r),a=n(4),i=n.n(a),c=n(3),s=n.n(c),u=n(6),l=n.n(u),p=n(7),f=n.n(p),d=n(0),h=n.n(d),m=(n(9),n(20),function(e){l()(n,e);var t=f()(n);function n(){return i()(this,n),t.apply(this,arguments)}return s()(n,[{key:"render",value:function(){var e=this.props,t=e.link,n=e.name,r=(0,e.getComponent)("Markdown",!0),a=t.get("operationId")||t.get("operationRef"),i=t.get("parameters")&&t.get("parameters").toJS(),c=t.get("description");return h.a.createElement("div",{className:"operation-link"},h.a.createElement("div",{className:"description"},h.a.createElement("b",null,h.a.createElement("code",null,n)),c?h.a.createElement(r,{source:c}):null),h.a.createElement("pre",null,"Operation `",a,"`",h.a.createElement("br",null),h.a.createElement("br",null),"Parameters ",function(e,t){if("string"!=typeof t)return"";return t.split("\n").map((function(t,n){return n>0?Array(e+1).join(" ")+t:t})).join("\n")}(0,o()(i,null,2))||"{}",h.a.createElement("br",null)))}}]),n}(d.Component));t.default=m},function(e,t,n){"use strict";n.r(t),n.d(t,"default",(function(){return y}));var r=n(4),o=n.n(r),a=n(3),i=n.n(a),c=n(8),s=n.n(c),u=n(6),l=n.n(u),p=n(7),f=n.n(p),d=n(2),h=n.n(d),m=n(0),v=n.n(m),g=n(1),y=(n(9),n(20),function(e){l()(n,e);var t=f()(n);function n(){var e;o()(this,n);for(var r=arguments.length,a=new Array(r),i=0;i<r;i++)a[i]=arguments[i];return e=t.call.apply(t,[this].concat(a)),h()(s()(e),"onServerChange",(function(t){e.setServer(t.target.value)})),h()(s()(e),"onServerVariableValueChange",(function(t){var n=e.props,r=n.setServerVariableValue,o=n.currentServer,a=t.target.getAttribute("data-variable"),i=t.target.value;"function"==typeof r&&r({server:o,key:a,val:i})})),h()(s()(e),"setServer",(function(t){(0,e.props.setSelectedServer)(t)})),e}return i()(n,[{key:"componentDidMount",value:function(){var e=this.props,t=e.servers;e.currentServer||this.setServer(t.first().get("url"))}},{key:"componentWillReceiveProps",value:function(e){var t=this.props,n=t.servers,r=t.setServerVariableValue,o=t.getServerVariable;if(this.props.currentServer!==e.currentServer){var a=n.find((function(t){return t.get("url")===e.currentServer}));if(!a)return this.setServer(n.first().get("url"));(a.get("variables")||Object(g.OrderedMap)()).map((function(t,n){o(e.currentServer,n)||r({server:e.currentServer,key:n,val:t.get("default")||""})}))}}},{key:"render",value:function(){var e=this,t=this.props,n=t.servers,r=t.currentServer,o=t.getServerVariable,a=t.getEffectiveServerValue,i=(n.find((function(e){return e.get("url")===r}))||Object(g.OrderedMap)()).get("variables")||Object(g.OrderedMap)(),c=0!==i.size;return v.a.createElement("div",{className:"servers"},v.a.createElement("label",{htmlFor:"servers"},v.a.createElement("select",{onChange:this.onServerChange},n.valueSeq().map((function(e){return v.a.createElement("option",{value:e.get("url"),key:e.get("url")},e.get("url"),e.get("description")&&" - ".concat(e.get("description")))})).toArray())),c?v.a.createElement("div",null,v.a.createElement("div",{className:"computed-url"},"Computed URL:",v.a.createElement("code",null,a(r))),v.a.createElement("h4",null,"Server variables"),v.a.createElement("table",null,v.a.createElement("tbody",null,i.map((function(t,n){return v.a.createElement("tr",{key:n},v.a.createElement("td",null,n),v.a.createElement("td",null,t.get("enum")?v.a.createElement("select",{"data-variable":n,onChange:e.onServerVariableValueChange},t.get("enum").map((function(e){return v.a.createElement("option",{selected:e===o(r,n),key:e,value:e},e)}))):v.a.createElement("input",{type:"text",value:o(r,n)||"",onChange:e.onServerVariableValueChange,"data-variable":n})))}))))):null)}}]),n}(v.a.Component))},function(e,t,n){"use strict";n.r(t),n.d(t,"default",(function(){return d}));var r=n(4),o=n.n(r),a=n(3),i=n.n(a),c=n(6),s=n.n(c),u=n(7),l=n.n(u),p=n(0),f=n.n(p),d=(n(9),function(e){s()(n,e);var t=l()(n);function n(){return o()(this,n),t.apply(this,arguments)}return i()(n,[{key:"render",value:function(){var e=this.props,t=e.specSelectors,n=e.oas3Selectors,r=e.oas3Actions,o=e.getComponent,a=t.servers(),i=o("Servers");return a&&a.size?f.a.createElement("div",null,f.a.createElement("span",{className:"servers-title"},"Servers"),f.a.createElement(i,{servers:a,currentServer:n.selectedServer(),setSelectedServer:r.setSelectedServer,setServerVariableValue:r.setServerVariableValue,getServerVariable:n.serverVariableValue,getEffectiveServerValue:n.serverEffectiveValue})):null}}]),n}(f.a.Component))},function(e,t,n){"use strict";n.r(t),n.d(t,"default",(function(){return x}));var r=n(4),o=n.n(r),a=n(3),i=n.n(a),c=n(8),s=n.n(c),u=n(6),l=n.n(u),p=n(7),f=n.n(p),d=n(2),h=n.n(d),m=n(0),v=n.n(m),g=(n(9),n(45)),y=n.n(g),b=n(5),E=Function.prototype,x=function(e){l()(n,e);var t=f()(n);function n(e,r){var a;return o()(this,n),a=t.call(this,e,r),h()(s()(a),"applyDefaultValue",(function(e){var t=e||a.props,n=t.onChange,r=t.defaultValue;return a.setState({value:r}),n(r)})),h()(s()(a),"onChange",(function(e){a.props.onChange(Object(b.J)(e))})),h()(s()(a),"onDomChange",(function(e){var t=e.target.value;a.setState({value:t},(function(){return a.onChange(t)}))})),a.state={value:Object(b.J)(e.value)||e.defaultValue},e.onChange(e.value),a}return i()(n,[{key:"componentWillReceiveProps",value:function(e){this.props.value!==e.value&&e.value!==this.state.value&&this.setState({value:Object(b.J)(e.value)}),!e.value&&e.defaultValue&&this.state.value&&this.applyDefaultValue(e)}},{key:"render",value:function(){var e=this.props,t=e.getComponent,n=e.errors,r=this.state.value,o=n.size>0,a=t("TextArea");return v.a.createElement("div",{className:"body-param"},v.a.createElement(a,{className:y()("body-param__text",{invalid:o}),title:n.size?n.join(", "):"",value:r,onChange:this.onDomChange}))}}]),n}(m.PureComponent);h()(x,"defaultProps",{onChange:E})},function(e,t,n){"use strict";n.r(t),n.d(t,"default",(function(){return b}));var r=n(16),o=n.n(r),a=n(4),i=n.n(a),c=n(3),s=n.n(c),u=n(8),l=n.n(u),p=n(6),f=n.n(p),d=n(7),h=n.n(d),m=n(2),v=n.n(m),g=n(0),y=n.n(g),b=(n(9),function(e){f()(n,e);var t=h()(n);function n(e,r){var a;i()(this,n),a=t.call(this,e,r),v()(l()(a),"onChange",(function(e){var t=a.props.onChange,n=e.target,r=n.value,i=n.name,c=o()({},a.state.value);i?c[i]=r:c=r,a.setState({value:c},(function(){return t(a.state)}))}));var c=a.props,s=c.name,u=c.schema,p=a.getValue();return a.state={name:s,schema:u,value:p},a}return s()(n,[{key:"getValue",value:function(){var e=this.props,t=e.name,n=e.authorized;return n&&n.getIn([t,"value"])}},{key:"render",value:function(){var e=this.props,t=e.schema,n=e.getComponent,r=e.errSelectors,o=e.name,a=n("Input"),i=n("Row"),c=n("Col"),s=n("authError"),u=n("Markdown",!0),l=n("JumpToPath",!0),p=(t.get("scheme")||"").toLowerCase(),f=this.getValue(),d=r.allErrors().filter((function(e){return e.get("authId")===o}));if("basic"===p){var h=f?f.get("username"):null;return y.a.createElement("div",null,y.a.createElement("h4",null,y.a.createElement("code",null,o||t.get("name")),"  (http, Basic)",y.a.createElement(l,{path:["securityDefinitions",o]})),h&&y.a.createElement("h6",null,"Authorized"),y.a.createElement(i,null,y.a.createElement(u,{source:t.get("description")})),y.a.createElement(i,null,y.a.createElement("label",null,"Username:"),h?y.a.createElement("code",null," ",h," "):y.a.createElement(c,null,y.a.createElement(a,{type:"text",required:"required",name:"username",onChange:this.onChange}))),y.a.createElement(i,null,y.a.createElement("label",null,"Password:"),h?y.a.createElement("code",null," ****** "):y.a.createElement(c,null,y.a.createElement(a,{autoComplete:"new-password",name:"password",type:"password",onChange:this.onChange}))),d.valueSeq().map((function(e,t){return y.a.createElement(s,{error:e,key:t})})))}return"bearer"===p?y.a.createElement("div",null,y.a.createElement("h4",null,y.a.createElement("code",null,o||t.get("name")),"  (http, Bearer)",y.a.createElement(l,{path:["securityDefinitions",o]})),f&&y.a.createElement("h6",null,"Authorized"),y.a.createElement(i,null,y.a.createElement(u,{source:t.get("description")})),y.a.createElement(i,null,y.a.createElement("label",null,"Value:"),f?y.a.createElement("code",null," ****** "):y.a.createElement(c,null,y.a.createElement(a,{type:"text",onChange:this.onChange}))),d.valueSeq().map((function(e,t){return y.a.createElement(s,{error:e,key:t})}))):y.a.createElement("div",null,y.a.createElement("em",null,y.a.createElement("b",null,o)," HTTP authentication: unsupported scheme ","'".concat(p,"'")))}}]),n}(y.a.Component))},function(e,t,n){"use strict";n.r(t),n.d(t,"default",(function(){return b}));var r=n(18),o=n.n(r),a=n(4),i=n.n(a),c=n(3),s=n.n(c),u=n(8),l=n.n(u),p=n(6),f=n.n(p),d=n(7),h=n.n(d),m=n(2),v=n.n(m),g=n(0),y=n.n(g),b=(n(9),n(20),function(e){f()(n,e);var t=h()(n);function n(){var e;i()(this,n);for(var r=arguments.length,a=new Array(r),c=0;c<r;c++)a[c]=arguments[c];return e=t.call.apply(t,[this].concat(a)),v()(l()(e),"setSelectedServer",(function(t){var n=e.props,r=n.path,o=n.method;return e.forceUpdate(),e.props.setSelectedServer(t,"".concat(r,":").concat(o))})),v()(l()(e),"setServerVariableValue",(function(t){var n=e.props,r=n.path,a=n.method;return e.forceUpdate(),e.props.setServerVariableValue(o()(o()({},t),{},{namespace:"".concat(r,":").concat(a)}))})),v()(l()(e),"getSelectedServer",(function(){var t=e.props,n=t.path,r=t.method;return e.props.getSelectedServer("".concat(n,":").concat(r))})),v()(l()(e),"getServerVariable",(function(t,n){var r=e.props,o=r.path,a=r.method;return e.props.getServerVariable({namespace:"".concat(o,":").concat(a),server:t},n)})),v()(l()(e),"getEffectiveServerValue",(function(t){var n=e.props,r=n.path,o=n.method;return e.props.getEffectiveServerValue({server:t,namespace:"".concat(r,":").concat(o)})})),e}return s()(n,[{key:"render",value:function(){var e=this.props,t=e.operationServers,n=e.pathServers,r=e.getComponent;if(!t&&!n)return null;var o=r("Servers"),a=t||n,i=t?"operation":"path";return y.a.createElement("div",{className:"opblock-section operation-servers"},y.a.createElement("div",{className:"opblock-section-header"},y.a.createElement("div",{className:"tab-header"},y.a.createElement("h4",{className:"opblock-title"},"Servers"))),y.a.createElement("div",{className:"opblock-description-wrapper"},y.a.createElement("h4",{className:"message"},"These ",i,"-level options override the global server options."),y.a.createElement(o,{servers:a,currentServer:this.getSelectedServer(),setSelectedServer:this.setSelectedServer,setServerVariableValue:this.setServerVariableValue,getServerVariable:this.getServerVariable,getEffectiveServerValue:this.getEffectiveServerValue})))}}]),n}(y.a.Component))},function(e,t,n){"use strict";n.r(t);var r=n(252),o=n(253),a=n(254),i=n(255),c=n(256),s=n(257);t.default={Markdown:r.default,AuthItem:o.default,JsonSchema_string:s.default,VersionStamp:a.default,model:c.default,onlineValidatorBadge:i.default}},function(e,t,n){"use strict";n.r(t),n.d(t,"Markdown",(function(){return p}));var r=n(0),o=n.n(r),a=(n(9),n(45)),i=n.n(a),c=n(153),s=n(23),u=n(154),l=new c.Remarkable("commonmark");l.block.ruler.enable(["table"]),l.set({linkTarget:"_blank"});var p=function(e){var t=e.source,n=e.className,r=void 0===n?"":n,a=e.getConfigs;if("string"!=typeof t)return null;if(t){var c,s=a().useUnsafeMarkdown,p=l.render(t),f=Object(u.b)(p,{useUnsafeMarkdown:s});return"string"==typeof f&&(c=f.trim()),o.a.createElement("div",{dangerouslySetInnerHTML:{__html:c},className:i()(r,"renderedMarkdown")})}return null};p.defaultProps={getConfigs:function(){return{useUnsafeMarkdown:!1}}},t.default=Object(s.OAS3ComponentWrapFactory)(p)},function(e,t,n){"use strict";n.r(t);var r=n(34),o=n.n(r),a=n(0),i=n.n(a),c=n(23);t.default=Object(c.OAS3ComponentWrapFactory)((function(e){var t=e.Ori,n=o()(e,["Ori"]),r=n.schema,a=n.getComponent,c=n.errSelectors,s=n.authorized,u=n.onAuthChange,l=n.name,p=a("HttpAuth");return"http"===r.get("type")?i.a.createElement(p,{key:l,schema:r,name:l,errSelectors:c,authorized:s,getComponent:a,onChange:u}):i.a.createElement(t,n)}))},function(e,t,n){"use strict";n.r(t);var r=n(0),o=n.n(r),a=n(23);t.default=Object(a.OAS3ComponentWrapFactory)((function(e){var t=e.Ori;return o.a.createElement("span",null,o.a.createElement(t,e),o.a.createElement("small",{className:"version-stamp"},o.a.createElement("pre",{className:"version"},"OAS3")))}))},function(e,t,n){"use strict";n.r(t);var r=n(23),o=n(150);t.default=Object(r.OAS3ComponentWrapFactory)(o.a)},function(e,t,n){"use strict";n.r(t);var r=n(19),o=n.n(r),a=n(4),i=n.n(a),c=n(3),s=n.n(c),u=n(6),l=n.n(u),p=n(7),f=n.n(p),d=n(0),h=n.n(d),m=(n(9),n(23)),v=n(152),g=function(e){l()(n,e);var t=f()(n);function n(){return i()(this,n),t.apply(this,arguments)}return s()(n,[{key:"render",value:function(){var e=this.props,t=e.getConfigs,n=["model-box"],r=null;return!0===e.schema.get("deprecated")&&(n.push("deprecated"),r=h.a.createElement("span",{className:"model-deprecated-warning"},"Deprecated:")),h.a.createElement("div",{className:n.join(" ")},r,h.a.createElement(v.a,o()({},this.props,{getConfigs:t,depth:1,expandDepth:this.props.expandDepth||0})))}}]),n}(d.Component);t.default=Object(m.OAS3ComponentWrapFactory)(g)},function(e,t,n){"use strict";n.r(t);var r=n(34),o=n.n(r),a=n(0),i=n.n(a),c=n(23);t.default=Object(c.OAS3ComponentWrapFactory)((function(e){var t=e.Ori,n=o()(e,["Ori"]),r=n.schema,a=n.getComponent,c=n.errors,s=n.onChange,u=r&&r.get?r.get("format"):null,l=r&&r.get?r.get("type"):null,p=a("Input");return l&&"string"===l&&u&&("binary"===u||"base64"===u)?i.a.createElement(p,{type:"file",className:c.length?"invalid":"",title:c.length?c:"",onChange:function(e){s(e.target.files[0])},disabled:t.isDisabled}):i.a.createElement(t,n)}))},function(e,t,n){"use strict";n.r(t),n.d(t,"selectedServer",(function(){return p})),n.d(t,"requestBodyValue",(function(){return f})),n.d(t,"requestBodyInclusionSetting",(function(){return d})),n.d(t,"requestBodyErrors",(function(){return h})),n.d(t,"activeExamplesMember",(function(){return m})),n.d(t,"requestContentType",(function(){return v})),n.d(t,"responseContentType",(function(){return g})),n.d(t,"serverVariableValue",(function(){return y})),n.d(t,"serverVariables",(function(){return b})),n.d(t,"serverEffectiveValue",(function(){return E})),n.d(t,"validateBeforeExecute",(function(){return x})),n.d(t,"validateShallowRequired",(function(){return S}));var r=n(11),o=n.n(r),a=n(13),i=n.n(a),c=n(1),s=n(23);function u(e){return function(){for(var t=arguments.length,n=new Array(t),r=0;r<t;r++)n[r]=arguments[r];return function(t){var r=t.getSystem().specSelectors.specJson();return Object(s.isOAS3)(r)?e.apply(void 0,n):null}}}var l,p=u((function(e,t){var n=t?[t,"selectedServer"]:["selectedServer"];return e.getIn(n)||""})),f=u((function(e,t,n){return e.getIn(["requestData",t,n,"bodyValue"])||null})),d=u((function(e,t,n){return e.getIn(["requestData",t,n,"bodyInclusion"])||Object(c.Map)()})),h=u((function(e,t,n){return e.getIn(["requestData",t,n,"errors"])||null})),m=u((function(e,t,n,r,o){return e.getIn(["examples",t,n,r,o,"activeExample"])||null})),v=u((function(e,t,n){return e.getIn(["requestData",t,n,"requestContentType"])||null})),g=u((function(e,t,n){return e.getIn(["requestData",t,n,"responseContentType"])||null})),y=u((function(e,t,n){var r;if("string"!=typeof t){var o=t.server,a=t.namespace;r=a?[a,"serverVariableValues",o,n]:["serverVariableValues",o,n]}else{r=["serverVariableValues",t,n]}return e.getIn(r)||null})),b=u((function(e,t){var n;if("string"!=typeof t){var r=t.server,o=t.namespace;n=o?[o,"serverVariableValues",r]:["serverVariableValues",r]}else{n=["serverVariableValues",t]}return e.getIn(n)||Object(c.OrderedMap)()})),E=u((function(e,t){var n,r;if("string"!=typeof t){var o=t.server,a=t.namespace;r=o,n=a?e.getIn([a,"serverVariableValues",r]):e.getIn(["serverVariableValues",r])}else r=t,n=e.getIn(["serverVariableValues",r]);n=n||Object(c.OrderedMap)();var i=r;return n.map((function(e,t){i=i.replace(new RegExp("{".concat(t,"}"),"g"),e)})),i})),x=(l=function(e,t){return function(e,t){return t=t||[],!!e.getIn(["requestData"].concat(i()(t),["bodyValue"]))}(e,t)},function(){for(var e=arguments.length,t=new Array(e),n=0;n<e;n++)t[n]=arguments[n];return function(e){var n=e.getSystem().specSelectors.specJson(),r=[].concat(t)[1]||[];return!n.getIn(["paths"].concat(i()(r),["requestBody","required"]))||l.apply(void 0,t)}}),S=function(e,t){var n=t.oas3RequiredRequestBodyContentType,r=t.oas3RequestBodyValue,a=[];if(!c.Map.isMap(r))return a;var i=[];return o()(n.requestContentType).forEach((function(e){n.requestContentType[e].forEach((function(e){i.indexOf(e)<0&&i.push(e)}))})),i.forEach((function(e){r.getIn([e,"value"])||a.push(e)})),a}},function(e,t,n){"use strict";n.r(t);var r,o=n(2),a=n.n(o),i=n(271),c=n.n(i),s=n(12),u=n.n(s),l=n(1),p=n(43);t.default=(r={},a()(r,p.UPDATE_SELECTED_SERVER,(function(e,t){var n=t.payload,r=n.selectedServerUrl,o=n.namespace,a=o?[o,"selectedServer"]:["selectedServer"];return e.setIn(a,r)})),a()(r,p.UPDATE_REQUEST_BODY_VALUE,(function(e,t){var n=t.payload,r=n.value,o=n.pathMethod,a=u()(o,2),i=a[0],s=a[1];if(!l.Map.isMap(r))return e.setIn(["requestData",i,s,"bodyValue"],r);var p,f=e.getIn(["requestData",i,s,"bodyValue"])||Object(l.Map)();l.Map.isMap(f)||(f=Object(l.Map)());var d=r.keys();return c()(d).slice(0).forEach((function(e){var t=r.getIn([e]);f.has(e)&&l.Map.isMap(t)||(p=f.setIn([e,"value"],t))})),e.setIn(["requestData",i,s,"bodyValue"],p)})),a()(r,p.UPDATE_REQUEST_BODY_INCLUSION,(function(e,t){var n=t.payload,r=n.value,o=n.pathMethod,a=n.name,i=u()(o,2),c=i[0],s=i[1];return e.setIn(["requestData",c,s,"bodyInclusion",a],r)})),a()(r,p.UPDATE_ACTIVE_EXAMPLES_MEMBER,(function(e,t){var n=t.payload,r=n.name,o=n.pathMethod,a=n.contextType,i=n.contextName,c=u()(o,2),s=c[0],l=c[1];return e.setIn(["examples",s,l,a,i,"activeExample"],r)})),a()(r,p.UPDATE_REQUEST_CONTENT_TYPE,(function(e,t){var n=t.payload,r=n.value,o=n.pathMethod,a=u()(o,2),i=a[0],c=a[1];return e.setIn(["requestData",i,c,"requestContentType"],r)})),a()(r,p.UPDATE_RESPONSE_CONTENT_TYPE,(function(e,t){var n=t.payload,r=n.value,o=n.path,a=n.method;return e.setIn(["requestData",o,a,"responseContentType"],r)})),a()(r,p.UPDATE_SERVER_VARIABLE_VALUE,(function(e,t){var n=t.payload,r=n.server,o=n.namespace,a=n.key,i=n.val,c=o?[o,"serverVariableValues",r,a]:["serverVariableValues",r,a];return e.setIn(c,i)})),a()(r,p.SET_REQUEST_BODY_VALIDATE_ERROR,(function(e,t){var n=t.payload,r=n.path,o=n.method,a=n.validationErrors,i=[];if(i.push("Required field is not provided"),a.missingBodyValue)return e.setIn(["requestData",r,o,"errors"],Object(l.fromJS)(i));if(a.missingRequiredKeys&&a.missingRequiredKeys.length>0){var c=a.missingRequiredKeys;return e.updateIn(["requestData",r,o,"bodyValue"],Object(l.fromJS)({}),(function(e){return c.reduce((function(e,t){return e.setIn([t,"errors"],Object(l.fromJS)(i))}),e)}))}return console.warn("unexpected result: SET_REQUEST_BODY_VALIDATE_ERROR"),e})),a()(r,p.CLEAR_REQUEST_BODY_VALIDATE_ERROR,(function(e,t){var n=t.payload,r=n.path,o=n.method,a=e.getIn(["requestData",r,o,"bodyValue"]);if(!l.Map.isMap(a))return e.setIn(["requestData",r,o,"errors"],Object(l.fromJS)([]));var i=a.keys(),s=c()(i).slice(0);return s?e.updateIn(["requestData",r,o,"bodyValue"],Object(l.fromJS)({}),(function(e){return s.reduce((function(e,t){return e.setIn([t,"errors"],Object(l.fromJS)([]))}),e)})):e})),r)},function(e,t,n){"use strict";n.r(t);var r=n(5),o=n(664),a={};o.keys().forEach((function(e){if("./index.js"!==e){var t=o(e);a[Object(r.E)(e)]=t.default?t.default:t}})),t.default=a},function(e,t,n){"use strict";n.r(t);var r={};n.r(r),n.d(r,"path",(function(){return tn})),n.d(r,"query",(function(){return nn})),n.d(r,"header",(function(){return on})),n.d(r,"cookie",(function(){return an}));var o=n(27),a=n.n(o),i=n(57),c=n.n(i),s=n(31),u=n.n(s),l=n(364),p=n.n(l),f=n(11),d=n.n(f),h=n(22),m=n.n(h),v=n(10),g=n.n(v),y=n(15),b=n.n(y),E=n(12),x=n.n(E),S=n(91),w=n.n(S),j=(n(639),n(155)),_=n.n(j),O=n(64),C=n.n(O),A=n(146),k=n.n(A),P=n(46),I=n.n(P),T=n(147),N=n(4),R=n.n(N),M=n(3),D=n.n(M),q=n(365),L=n.n(q),B=n(142),U=n.n(B),V=n(6),z=n.n(V),F=n(7),J=n.n(F),W=n(366),H=function(e){var t=function(e,t){return{name:e,value:t}};return I()(e.prototype.set)||I()(e.prototype.get)||I()(e.prototype.getAll)||I()(e.prototype.has)?e:function(e){z()(r,e);var n=J()(r);function r(e){var t;return R()(this,r),(t=n.call(this,e)).entryList=[],t}return D()(r,[{key:"append",value:function(e,n,o){return this.entryList.push(t(e,n)),L()(U()(r.prototype),"append",this).call(this,e,n,o)}},{key:"set",value:function(e,n){var r=t(e,n);this.entryList=this.entryList.filter((function(t){return t.name!==e})),this.entryList.push(r)}},{key:"get",value:function(e){var t=this.entryList.find((function(t){return t.name===e}));return void 0===t?null:t}},{key:"getAll",value:function(e){return this.entryList.filter((function(t){return t.name===e})).map((function(e){return e.value}))}},{key:"has",value:function(e){return this.entryList.some((function(t){return t.name===e}))}}]),r}(e)}(n.n(W).a),$=n(13),Y=n.n($),K=n(147).Buffer,G=function(e){return":/?#[]@!$&'()*+,;=".indexOf(e)>-1},Z=function(e){return/^[a-z0-9\-._~]+$/i.test(e)};function X(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{},n=t.escape,r=arguments.length>2?arguments[2]:void 0;return"number"==typeof e&&(e=e.toString()),"string"==typeof e&&e.length&&n?r?JSON.parse(e):Y()(e).map((function(e){return Z(e)||G(e)&&"unsafe"===n?e:(K.from(e).toJSON().data||[]).map((function(e){return"0".concat(e.toString(16).toUpperCase()).slice(-2)})).map((function(e){return"%".concat(e)})).join("")})).join(""):e}function Q(e){var t=e.value;return g()(t)?function(e){var t=e.key,n=e.value,r=e.style,o=e.explode,a=e.escape,i=function(e){return X(e,{escape:a})};if("simple"===r)return n.map((function(e){return i(e)})).join(",");if("label"===r)return".".concat(n.map((function(e){return i(e)})).join("."));if("matrix"===r)return n.map((function(e){return i(e)})).reduce((function(e,n){return!e||o?"".concat(e||"",";").concat(t,"=").concat(n):"".concat(e,",").concat(n)}),"");if("form"===r){var c=o?"&".concat(t,"="):",";return n.map((function(e){return i(e)})).join(c)}if("spaceDelimited"===r){var s=o?"".concat(t,"="):"";return n.map((function(e){return i(e)})).join(" ".concat(s))}if("pipeDelimited"===r){var u=o?"".concat(t,"="):"";return n.map((function(e){return i(e)})).join("|".concat(u))}return}(e):"object"===b()(t)?function(e){var t=e.key,n=e.value,r=e.style,o=e.explode,a=e.escape,i=function(e){return X(e,{escape:a})},c=d()(n);if("simple"===r)return c.reduce((function(e,t){var r=i(n[t]),a=o?"=":",",c=e?"".concat(e,","):"";return"".concat(c).concat(t).concat(a).concat(r)}),"");if("label"===r)return c.reduce((function(e,t){var r=i(n[t]),a=o?"=":".",c=e?"".concat(e,"."):".";return"".concat(c).concat(t).concat(a).concat(r)}),"");if("matrix"===r&&o)return c.reduce((function(e,t){var r=i(n[t]),o=e?"".concat(e,";"):";";return"".concat(o).concat(t,"=").concat(r)}),"");if("matrix"===r)return c.reduce((function(e,r){var o=i(n[r]),a=e?"".concat(e,","):";".concat(t,"=");return"".concat(a).concat(r,",").concat(o)}),"");if("form"===r)return c.reduce((function(e,t){var r=i(n[t]),a=e?"".concat(e).concat(o?"&":","):"",c=o?"=":",";return"".concat(a).concat(t).concat(c).concat(r)}),"");return}(e):function(e){var t=e.key,n=e.value,r=e.style,o=e.escape,a=function(e){return X(e,{escape:o})};if("simple"===r)return a(n);if("label"===r)return".".concat(a(n));if("matrix"===r)return";".concat(t,"=").concat(a(n));if("form"===r)return a(n);if("deepObject"===r)return a(n);return}(e)}var ee={serializeRes:ae,mergeInQueryOrForm:ve};function te(e){return ne.apply(this,arguments)}function ne(){return(ne=c()(a.a.mark((function e(t){var n,r,o,i,c,s=arguments;return a.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:if(n=s.length>1&&void 0!==s[1]?s[1]:{},"object"===b()(t)&&(t=(n=t).url),n.headers=n.headers||{},ee.mergeInQueryOrForm(n),n.headers&&d()(n.headers).forEach((function(e){var t=n.headers[e];"string"==typeof t&&(n.headers[e]=t.replace(/\n+/g," "))})),!n.requestInterceptor){e.next=12;break}return e.next=8,n.requestInterceptor(n);case 8:if(e.t0=e.sent,e.t0){e.next=11;break}e.t0=n;case 11:n=e.t0;case 12:return r=n.headers["content-type"]||n.headers["Content-Type"],/multipart\/form-data/i.test(r)&&(delete n.headers["content-type"],delete n.headers["Content-Type"]),e.prev=14,e.next=17,(n.userFetch||fetch)(n.url,n);case 17:return o=e.sent,e.next=20,ee.serializeRes(o,t,n);case 20:if(o=e.sent,!n.responseInterceptor){e.next=28;break}return e.next=24,n.responseInterceptor(o);case 24:if(e.t1=e.sent,e.t1){e.next=27;break}e.t1=o;case 27:o=e.t1;case 28:e.next=39;break;case 30:if(e.prev=30,e.t2=e.catch(14),o){e.next=34;break}throw e.t2;case 34:throw(i=new Error(o.statusText)).status=o.status,i.statusCode=o.status,i.responseError=e.t2,i;case 39:if(o.ok){e.next=45;break}throw(c=new Error(o.statusText)).status=o.status,c.statusCode=o.status,c.response=o,c;case 45:return e.abrupt("return",o);case 46:case"end":return e.stop()}}),e,null,[[14,30]])})))).apply(this,arguments)}var re=function(){var e=arguments.length>0&&void 0!==arguments[0]?arguments[0]:"";return/(json|xml|yaml|text)\b/.test(e)};function oe(e,t){return t&&(0===t.indexOf("application/json")||t.indexOf("+json")>0)?JSON.parse(e):C.a.safeLoad(e)}function ae(e,t){var n=arguments.length>2&&void 0!==arguments[2]?arguments[2]:{},r=n.loadSpec,o=void 0!==r&&r,a={ok:e.ok,url:e.url||t,status:e.status,statusText:e.statusText,headers:ce(e.headers)},i=a.headers["content-type"],c=o||re(i),s=c?e.text:e.blob||e.buffer;return s.call(e).then((function(e){if(a.text=e,a.data=e,c)try{var t=oe(e,i);a.body=t,a.obj=t}catch(e){a.parseError=e}return a}))}function ie(e){return e.includes(", ")?e.split(", "):e}function ce(){var e=arguments.length>0&&void 0!==arguments[0]?arguments[0]:{};return I()(e.entries)?w()(e.entries()).reduce((function(e,t){var n=x()(t,2),r=n[0],o=n[1];return e[r]=ie(o),e}),{}):{}}function se(e,t){return t||"undefined"==typeof navigator||(t=navigator),t&&"ReactNative"===t.product?!(!e||"object"!==b()(e)||"string"!=typeof e.uri):"undefined"!=typeof File&&e instanceof File||("undefined"!=typeof Blob&&e instanceof Blob||(void 0!==T.Buffer&&e instanceof T.Buffer||null!==e&&"object"===b()(e)&&"function"==typeof e.pipe))}function ue(e,t){return g()(e)&&e.some((function(e){return se(e,t)}))}var le={form:",",spaceDelimited:"%20",pipeDelimited:"|"},pe={csv:",",ssv:"%20",tsv:"%09",pipes:"|"};function fe(e,t){var n=arguments.length>2&&void 0!==arguments[2]&&arguments[2],r=t.collectionFormat,o=t.allowEmptyValue,a=t.serializationOption,i=t.encoding,c="object"!==b()(t)||g()(t)?t:t.value,s=n?function(e){return e.toString()}:function(e){return encodeURIComponent(e)},u=s(e);if(void 0===c&&o)return[[u,""]];if(se(c)||ue(c))return[[u,c]];if(a)return de(e,c,n,a);if(i){if([b()(i.style),b()(i.explode),b()(i.allowReserved)].some((function(e){return"undefined"!==e})))return de(e,c,n,k()(i,["style","explode","allowReserved"]));if(i.contentType){if("application/json"===i.contentType){var l="string"==typeof c?c:m()(c);return[[u,s(l)]]}return[[u,s(c.toString())]]}return"object"!==b()(c)?[[u,s(c)]]:g()(c)&&c.every((function(e){return"object"!==b()(e)}))?[[u,c.map(s).join(",")]]:[[u,s(m()(c))]]}return"object"!==b()(c)?[[u,s(c)]]:g()(c)?"multi"===r?[[u,c.map(s)]]:[[u,c.map(s).join(pe[r||"csv"])]]:[[u,""]]}function de(e,t,n,r){var o=r.style||"form",a=void 0===r.explode?"form"===o:r.explode,i=!n&&(r&&r.allowReserved?"unsafe":"reserved"),c=function(e){return X(e,{escape:i})},s=n?function(e){return e}:function(e){return X(e,{escape:i})};return"object"!==b()(t)?[[s(e),c(t)]]:g()(t)?a?[[s(e),t.map(c)]]:[[s(e),t.map(c).join(le[o])]]:"deepObject"===o?d()(t).map((function(n){return[s("".concat(e,"[").concat(n,"]")),c(t[n])]})):a?d()(t).map((function(e){return[s(e),c(t[e])]})):[[s(e),d()(t).map((function(e){return["".concat(s(e),",").concat(c(t[e]))]})).join(",")]]}function he(e){return p()(e).reduce((function(e,t){var n,r=x()(t,2),o=r[0],a=r[1],i=u()(fe(o,a,!0));try{for(i.s();!(n=i.n()).done;){var c=x()(n.value,2),s=c[0],l=c[1];if(g()(l)){var p,f=u()(l);try{for(f.s();!(p=f.n()).done;){var d=p.value;e.append(s,d)}}catch(e){f.e(e)}finally{f.f()}}else e.append(s,l)}}catch(e){i.e(e)}finally{i.f()}return e}),new H)}function me(e){var t=d()(e).reduce((function(t,n){var r,o=u()(fe(n,e[n]));try{for(o.s();!(r=o.n()).done;){var a=x()(r.value,2),i=a[0],c=a[1];t[i]=c}}catch(e){o.e(e)}finally{o.f()}return t}),{});return _.a.stringify(t,{encode:!1,indices:!1})||""}function ve(){var e=arguments.length>0&&void 0!==arguments[0]?arguments[0]:{},t=e.url,n=void 0===t?"":t,r=e.query,o=e.form,a=function(){for(var e=arguments.length,t=new Array(e),n=0;n<e;n++)t[n]=arguments[n];var r=t.filter((function(e){return e})).join("&");return r?"?".concat(r):""};if(o){var i=d()(o).some((function(e){var t=o[e].value;return se(t)||ue(t)})),c=e.headers["content-type"]||e.headers["Content-Type"];i||/multipart\/form-data/i.test(c)?e.body=he(e.form):e.body=me(o),delete e.form}if(r){var s=n.split("?"),u=x()(s,2),l=u[0],p=u[1],f="";if(p){var h=_.a.parse(p),m=d()(r);m.forEach((function(e){return delete h[e]})),f=_.a.stringify(h,{encode:!0})}var v=a(f,me(r));e.url=l+v,delete e.query}return e}var ge=n(18),ye=n.n(ge),be=n(47),Ee=n.n(be),xe=n(145),Se=n.n(xe),we=n(16),je=n.n(we),_e=n(144),Oe=n.n(_e),Ce=n(267),Ae=n.n(Ce),ke=n(2),Pe=n.n(ke),Ie=n(96),Te=n(50),Ne=n.n(Te),Re=n(367),Me=n.n(Re),De={add:function(e,t){return{op:"add",path:e,value:t}},replace:Le,remove:function(e){return{op:"remove",path:e}},merge:function(e,t){return{type:"mutation",op:"merge",path:e,value:t}},mergeDeep:function(e,t){return{type:"mutation",op:"mergeDeep",path:e,value:t}},context:function(e,t){return{type:"context",path:e,value:t}},getIn:function(e,t){return t.reduce((function(e,t){return void 0!==t&&e?e[t]:e}),e)},applyPatch:function(e,t,n){if(n=n||{},"merge"===(t=ye()(ye()({},t),{},{path:t.path&&qe(t.path)})).op){var r=Ze(e,t.path);je()(r,t.value),Ie.applyPatch(e,[Le(t.path,r)])}else if("mergeDeep"===t.op){var o=Ze(e,t.path);for(var a in t.value){var i=t.value[a],c=g()(i);if(c){var s=o[a]||[];o[a]=s.concat(i)}else if(We(i)&&!c){var u=ye()({},o[a]);for(var l in i){if(Object.prototype.hasOwnProperty.call(u,l)){u=Ne()(Me()(u),i);break}je()(u,Pe()({},l,i[l]))}o[a]=u}else o[a]=i}}else if("add"===t.op&&""===t.path&&We(t.value)){var p=d()(t.value).reduce((function(e,n){return e.push({op:"add",path:"/".concat(qe(n)),value:t.value[n]}),e}),[]);Ie.applyPatch(e,p)}else if("replace"===t.op&&""===t.path){var f=t.value;n.allowMetaPatches&&t.meta&&Ke(t)&&(g()(t.value)||We(t.value))&&(f=ye()(ye()({},f),t.meta)),e=f}else if(Ie.applyPatch(e,[t]),n.allowMetaPatches&&t.meta&&Ke(t)&&(g()(t.value)||We(t.value))){var h=Ze(e,t.path),m=ye()(ye()({},h),t.meta);Ie.applyPatch(e,[Le(t.path,m)])}return e},parentPathMatch:function(e,t){if(!g()(t))return!1;for(var n=0,r=t.length;n<r;n+=1)if(t[n]!==e[n])return!1;return!0},flatten:Fe,fullyNormalizeArray:function(e){return Je(Fe(ze(e)))},normalizeArray:ze,isPromise:function(e){return We(e)&&He(e.then)},forEachNew:function(e,t){try{return Be(e,Ve,t)}catch(e){return e}},forEachNewPrimitive:function(e,t){try{return Be(e,Ue,t)}catch(e){return e}},isJsonPatch:$e,isContextPatch:function(e){return Ge(e)&&"context"===e.type},isPatch:Ge,isMutation:Ye,isAdditiveMutation:Ke,isGenerator:function(e){return"[object GeneratorFunction]"===Object.prototype.toString.call(e)},isFunction:He,isObject:We,isError:function(e){return e instanceof Error}};function qe(e){return g()(e)?e.length<1?"":"/".concat(e.map((function(e){return(e+"").replace(/~/g,"~0").replace(/\//g,"~1")})).join("/")):e}function Le(e,t,n){return{op:"replace",path:e,value:t,meta:n}}function Be(e,t,n){return Je(Fe(e.filter(Ke).map((function(e){return t(e.value,n,e.path)}))||[]))}function Ue(e,t,n){return n=n||[],g()(e)?e.map((function(e,r){return Ue(e,t,n.concat(r))})):We(e)?d()(e).map((function(r){return Ue(e[r],t,n.concat(r))})):t(e,n[n.length-1],n)}function Ve(e,t,n){var r=[];if((n=n||[]).length>0){var o=t(e,n[n.length-1],n);o&&(r=r.concat(o))}if(g()(e)){var a=e.map((function(e,r){return Ve(e,t,n.concat(r))}));a&&(r=r.concat(a))}else if(We(e)){var i=d()(e).map((function(r){return Ve(e[r],t,n.concat(r))}));i&&(r=r.concat(i))}return r=Fe(r)}function ze(e){return g()(e)?e:[e]}function Fe(e){var t;return(t=[]).concat.apply(t,Y()(e.map((function(e){return g()(e)?Fe(e):e}))))}function Je(e){return e.filter((function(e){return void 0!==e}))}function We(e){return e&&"object"===b()(e)}function He(e){return e&&"function"==typeof e}function $e(e){if(Ge(e)){var t=e.op;return"add"===t||"remove"===t||"replace"===t}return!1}function Ye(e){return $e(e)||Ge(e)&&"mutation"===e.type}function Ke(e){return Ye(e)&&("add"===e.op||"replace"===e.op||"merge"===e.op||"mergeDeep"===e.op)}function Ge(e){return e&&"object"===b()(e)}function Ze(e,t){try{return Ie.getValueByPointer(e,t)}catch(e){return console.error(e),{}}}var Xe=n(368),Qe=n.n(Xe),et=n(369),tt=n(268),nt=n.n(tt),rt=n(66),ot=n.n(rt);function at(e,t){function n(){Error.captureStackTrace?Error.captureStackTrace(this,this.constructor):this.stack=(new Error).stack;for(var e=arguments.length,n=new Array(e),r=0;r<e;r++)n[r]=arguments[r];this.message=n[0],t&&t.apply(this,n)}return n.prototype=new Error,n.prototype.name=e,n.prototype.constructor=n,n}var it=n(370),ct=n.n(it),st=["properties"],ut=["properties"],lt=["definitions","parameters","responses","securityDefinitions","components/schemas","components/responses","components/parameters","components/securitySchemes"],pt=["schema/example","items/example"];function ft(e){var t=e[e.length-1],n=e[e.length-2],r=e.join("/");return st.indexOf(t)>-1&&-1===ut.indexOf(n)||lt.indexOf(r)>-1||pt.some((function(e){return r.indexOf(e)>-1}))}function dt(e,t){var n=e.split("#"),r=x()(n,2),o=r[0],a=r[1],i=ot.a.resolve(o||"",t||"");return a?"".concat(i,"#").concat(a):i}var ht=new RegExp("^([a-z]+://|//)","i"),mt=at("JSONRefError",(function(e,t,n){this.originalError=n,je()(this,t||{})})),vt={},gt=new Qe.a,yt=[function(e){return"paths"===e[0]&&"responses"===e[3]&&"content"===e[5]&&"example"===e[7]},function(e){return"paths"===e[0]&&"requestBody"===e[3]&&"content"===e[4]&&"example"===e[6]}],bt={key:"$ref",plugin:function(e,t,n,r){var o=r.getInstance(),a=n.slice(0,-1);if(!ft(a)&&(i=a,!yt.some((function(e){return e(i)})))){var i,c=r.getContext(n).baseDoc;if("string"!=typeof e)return new mt("$ref: must be a string (JSON-Ref)",{$ref:e,baseDoc:c,fullPath:n});var s,u,l,p=jt(e),f=p[0],h=p[1]||"";try{s=c||f?St(f,c):null}catch(t){return wt(t,{pointer:h,$ref:e,basePath:s,fullPath:n})}if(function(e,t,n,r){var o=gt.get(r);o||(o={},gt.set(r,o));var a=function(e){if(0===e.length)return"";return"/".concat(e.map(Pt).join("/"))}(n),i="".concat(t||"<specmap-base>","#").concat(e),c=a.replace(/allOf\/\d+\/?/g,""),s=r.contextTree.get([]).baseDoc;if(t==s&&It(c,e))return!0;var u="";if(n.some((function(e){return u="".concat(u,"/").concat(Pt(e)),o[u]&&o[u].some((function(e){return It(e,i)||It(i,e)}))})))return!0;return void(o[c]=(o[c]||[]).concat(i))}(h,s,a,r)&&!o.useCircularStructures){var m=dt(e,s);return e===m?null:De.replace(n,m)}if(null==s?(l=At(h),void 0===(u=r.get(l))&&(u=new mt("Could not resolve reference: ".concat(e),{pointer:h,$ref:e,baseDoc:c,fullPath:n}))):u=null!=(u=_t(s,h)).__value?u.__value:u.catch((function(t){throw wt(t,{pointer:h,$ref:e,baseDoc:c,fullPath:n})})),u instanceof Error)return[De.remove(n),u];var v=dt(e,s),g=De.replace(a,u,{$$ref:v});if(s&&s!==c)return[g,De.context(a,{baseDoc:s})];try{if(!function(e,t){var n=[e];return t.path.reduce((function(e,t){return n.push(e[t]),e[t]}),e),function e(t){return De.isObject(t)&&(n.indexOf(t)>=0||d()(t).some((function(n){return e(t[n])})))}(t.value)}(r.state,g)||o.useCircularStructures)return g}catch(e){return null}}}},Et=je()(bt,{docCache:vt,absoluteify:St,clearCache:function(e){void 0!==e?delete vt[e]:d()(vt).forEach((function(e){delete vt[e]}))},JSONRefError:mt,wrapError:wt,getDoc:Ot,split:jt,extractFromDoc:_t,fetchJSON:function(e){return Object(et.fetch)(e,{headers:{Accept:"application/json, application/yaml"},loadSpec:!0}).then((function(e){return e.text()})).then((function(e){return C.a.safeLoad(e)}))},extract:Ct,jsonPointerToArray:At,unescapeJsonPointerToken:kt}),xt=Et;function St(e,t){if(!ht.test(e)){if(!t)throw new mt("Tried to resolve a relative URL, without having a basePath. path: '".concat(e,"' basePath: '").concat(t,"'"));return ot.a.resolve(t,e)}return e}function wt(e,t){var n;return n=e&&e.response&&e.response.body?"".concat(e.response.body.code," ").concat(e.response.body.message):e.message,new mt("Could not resolve reference: ".concat(n),t,e)}function jt(e){return(e+"").split("#")}function _t(e,t){var n=vt[e];if(n&&!De.isPromise(n))try{var r=Ct(t,n);return je()(Ee.a.resolve(r),{__value:r})}catch(e){return Ee.a.reject(e)}return Ot(e).then((function(e){return Ct(t,e)}))}function Ot(e){var t=vt[e];return t?De.isPromise(t)?t:Ee.a.resolve(t):(vt[e]=Et.fetchJSON(e).then((function(t){return vt[e]=t,t})),vt[e])}function Ct(e,t){var n=At(e);if(n.length<1)return t;var r=De.getIn(t,n);if(void 0===r)throw new mt("Could not resolve pointer: ".concat(e," does not exist in document"),{pointer:e});return r}function At(e){if("string"!=typeof e)throw new TypeError("Expected a string, got a ".concat(b()(e)));return"/"===e[0]&&(e=e.substr(1)),""===e?[]:e.split("/").map(kt)}function kt(e){return"string"!=typeof e?e:nt.a.unescape(e.replace(/~1/g,"/").replace(/~0/g,"~"))}function Pt(e){return nt.a.escape(e.replace(/~/g,"~0").replace(/\//g,"~1"))}function It(e,t){if(!(n=t)||"/"===n||"#"===n)return!0;var n,r=e.charAt(t.length),o=t.slice(-1);return 0===e.indexOf(t)&&(!r||"/"===r||"#"===r)&&"#"!==o}var Tt={key:"allOf",plugin:function(e,t,n,r,o){if(!o.meta||!o.meta.$$ref){var a=n.slice(0,-1);if(!ft(a)){if(!g()(e)){var i=new TypeError("allOf must be an array");return i.fullPath=n,i}var c=!1,s=o.value;a.forEach((function(e){s&&(s=s[e])})),delete(s=ye()({},s)).allOf;var u=[];return u.push(r.replace(a,{})),e.forEach((function(e,t){if(!r.isObject(e)){if(c)return null;c=!0;var o=new TypeError("Elements in allOf must be objects");return o.fullPath=n,u.push(o)}u.push(r.mergeDeep(a,e));var i=function(e,t){var n=arguments.length>2&&void 0!==arguments[2]?arguments[2]:{},r=n.specmap,o=n.getBaseUrlForNodePath,a=void 0===o?function(e){return r.getContext([].concat(Y()(t),Y()(e))).baseDoc}:o,i=n.targetKeys,c=void 0===i?["$ref","$$ref"]:i,s=[];return ct()(e).forEach((function(){if(c.indexOf(this.key)>-1){var e=this.path,n=t.concat(this.path),o=dt(this.node,a(e));s.push(r.replace(n,o))}})),s}(e,n.slice(0,-1),{getBaseUrlForNodePath:function(e){return r.getContext([].concat(Y()(n),[t],Y()(e))).baseDoc},specmap:r});u.push.apply(u,Y()(i))})),u.push(r.mergeDeep(a,s)),s.$$ref||u.push(r.remove([].concat(a,"$$ref"))),u}}}},Nt={key:"parameters",plugin:function(e,t,n,r){if(g()(e)&&e.length){var o=je()([],e),a=n.slice(0,-1),i=ye()({},De.getIn(r.spec,a));return e.forEach((function(e,t){try{o[t].default=r.parameterMacro(i,e)}catch(e){var a=new Error(e);return a.fullPath=n,a}})),De.replace(n,o)}return De.replace(n,e)}},Rt={key:"properties",plugin:function(e,t,n,r){var o=ye()({},e);for(var a in e)try{o[a].default=r.modelPropertyMacro(o[a])}catch(e){var i=new Error(e);return i.fullPath=n,i}return De.replace(n,o)}},Mt=function(){function e(t){R()(this,e),this.root=Dt(t||{})}return D()(e,[{key:"set",value:function(e,t){var n=this.getParent(e,!0);if(n){var r=e[e.length-1],o=n.children;o[r]?qt(o[r],t,n):o[r]=Dt(t,n)}else qt(this.root,t,null)}},{key:"get",value:function(e){if((e=e||[]).length<1)return this.root.value;for(var t,n,r=this.root,o=0;o<e.length&&(n=e[o],(t=r.children)[n]);o+=1)r=t[n];return r&&r.protoValue}},{key:"getParent",value:function(e,t){return!e||e.length<1?null:e.length<2?this.root:e.slice(0,-1).reduce((function(e,n){if(!e)return e;var r=e.children;return!r[n]&&t&&(r[n]=Dt(null,e)),r[n]}),this.root)}}]),e}();function Dt(e,t){return qt({children:{}},e,t)}function qt(e,t,n){return e.value=t||{},e.protoValue=n?ye()(ye()({},n.protoValue),e.value):e.value,d()(e.children).forEach((function(t){var n=e.children[t];e.children[t]=qt(n,n.value,e)})),e}var Lt=function(){function e(t){var n=this;R()(this,e),je()(this,{spec:"",debugLevel:"info",plugins:[],pluginHistory:{},errors:[],mutations:[],promisedPatches:[],state:{},patches:[],context:{},contextTree:new Mt,showDebug:!1,allPatches:[],pluginProp:"specMap",libMethods:je()(Se()(this),De,{getInstance:function(){return n}}),allowMetaPatches:!1},t),this.get=this._get.bind(this),this.getContext=this._getContext.bind(this),this.hasRun=this._hasRun.bind(this),this.wrappedPlugins=this.plugins.map(this.wrapPlugin.bind(this)).filter(De.isFunction),this.patches.push(De.add([],this.spec)),this.patches.push(De.context([],this.context)),this.updatePatches(this.patches)}return D()(e,null,[{key:"getPluginName",value:function(e){return e.pluginName}},{key:"getPatchesOfType",value:function(e,t){return e.filter(t)}}]),D()(e,[{key:"debug",value:function(e){if(this.debugLevel===e){for(var t,n=arguments.length,r=new Array(n>1?n-1:0),o=1;o<n;o++)r[o-1]=arguments[o];(t=console).log.apply(t,r)}}},{key:"verbose",value:function(e){if("verbose"===this.debugLevel){for(var t,n=arguments.length,r=new Array(n>1?n-1:0),o=1;o<n;o++)r[o-1]=arguments[o];(t=console).log.apply(t,["[".concat(e,"]   ")].concat(r))}}},{key:"wrapPlugin",value:function(e,t){var n,r,o,i=this.pathDiscriminator,c=null;return e[this.pluginProp]?(c=e,n=e[this.pluginProp]):De.isFunction(e)?n=e:De.isObject(e)&&(r=e,o=function(e,t){return!g()(e)||e.every((function(e,n){return e===t[n]}))},n=a.a.mark((function e(t,n){var c,s,l,p,f,h;return a.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:h=function(e,t,u){var l,p,f,m,v,g,y,b,E,x,S,w,j;return a.a.wrap((function(a){for(;;)switch(a.prev=a.next){case 0:if(De.isObject(e)){a.next=6;break}if(r.key!==t[t.length-1]){a.next=4;break}return a.next=4,r.plugin(e,r.key,t,n);case 4:a.next=30;break;case 6:l=t.length-1,p=t[l],f=t.indexOf("properties"),m="properties"===p&&l===f,v=n.allowMetaPatches&&s[e.$$ref],g=0,y=d()(e);case 12:if(!(g<y.length)){a.next=30;break}if(b=y[g],E=e[b],x=t.concat(b),S=De.isObject(E),w=e.$$ref,v){a.next=22;break}if(!S){a.next=22;break}return n.allowMetaPatches&&w&&(s[w]=!0),a.delegateYield(h(E,x,u),"t0",22);case 22:if(m||b!==r.key){a.next=27;break}if(j=o(i,t),i&&!j){a.next=27;break}return a.next=27,r.plugin(E,b,x,n,u);case 27:g++,a.next=12;break;case 30:case"end":return a.stop()}}),c)},c=a.a.mark(h),s={},l=u()(t.filter(De.isAdditiveMutation)),e.prev=4,l.s();case 6:if((p=l.n()).done){e.next=11;break}return f=p.value,e.delegateYield(h(f.value,f.path,f),"t0",9);case 9:e.next=6;break;case 11:e.next=16;break;case 13:e.prev=13,e.t1=e.catch(4),l.e(e.t1);case 16:return e.prev=16,l.f(),e.finish(16);case 19:case"end":return e.stop()}}),e,null,[[4,13,16,19]])}))),je()(n.bind(c),{pluginName:e.name||t,isGenerator:De.isGenerator(n)})}},{key:"nextPlugin",value:function(){var e=this;return Oe()(this.wrappedPlugins,(function(t){return e.getMutationsForPlugin(t).length>0}))}},{key:"nextPromisedPatch",value:function(){if(this.promisedPatches.length>0)return Ee.a.race(this.promisedPatches.map((function(e){return e.value})))}},{key:"getPluginHistory",value:function(e){var t=this.constructor.getPluginName(e);return this.pluginHistory[t]||[]}},{key:"getPluginRunCount",value:function(e){return this.getPluginHistory(e).length}},{key:"getPluginHistoryTip",value:function(e){var t=this.getPluginHistory(e);return t&&t[t.length-1]||{}}},{key:"getPluginMutationIndex",value:function(e){var t=this.getPluginHistoryTip(e).mutationIndex;return"number"!=typeof t?-1:t}},{key:"updatePluginHistory",value:function(e,t){var n=this.constructor.getPluginName(e);this.pluginHistory[n]=this.pluginHistory[n]||[],this.pluginHistory[n].push(t)}},{key:"updatePatches",value:function(e){var t=this;De.normalizeArray(e).forEach((function(e){if(e instanceof Error)t.errors.push(e);else try{if(!De.isObject(e))return void t.debug("updatePatches","Got a non-object patch",e);if(t.showDebug&&t.allPatches.push(e),De.isPromise(e.value))return t.promisedPatches.push(e),void t.promisedPatchThen(e);if(De.isContextPatch(e))return void t.setContext(e.path,e.value);if(De.isMutation(e))return void t.updateMutations(e)}catch(e){console.error(e),t.errors.push(e)}}))}},{key:"updateMutations",value:function(e){"object"===b()(e.value)&&!g()(e.value)&&this.allowMetaPatches&&(e.value=ye()({},e.value));var t=De.applyPatch(this.state,e,{allowMetaPatches:this.allowMetaPatches});t&&(this.mutations.push(e),this.state=t)}},{key:"removePromisedPatch",value:function(e){var t=this.promisedPatches.indexOf(e);t<0?this.debug("Tried to remove a promisedPatch that isn't there!"):this.promisedPatches.splice(t,1)}},{key:"promisedPatchThen",value:function(e){var t=this;return e.value=e.value.then((function(n){var r=ye()(ye()({},e),{},{value:n});t.removePromisedPatch(e),t.updatePatches(r)})).catch((function(n){t.removePromisedPatch(e),t.updatePatches(n)})),e.value}},{key:"getMutations",value:function(e,t){return e=e||0,"number"!=typeof t&&(t=this.mutations.length),this.mutations.slice(e,t)}},{key:"getCurrentMutations",value:function(){return this.getMutationsForPlugin(this.getCurrentPlugin())}},{key:"getMutationsForPlugin",value:function(e){var t=this.getPluginMutationIndex(e);return this.getMutations(t+1)}},{key:"getCurrentPlugin",value:function(){return this.currentPlugin}},{key:"getLib",value:function(){return this.libMethods}},{key:"_get",value:function(e){return De.getIn(this.state,e)}},{key:"_getContext",value:function(e){return this.contextTree.get(e)}},{key:"setContext",value:function(e,t){return this.contextTree.set(e,t)}},{key:"_hasRun",value:function(e){return this.getPluginRunCount(this.getCurrentPlugin())>(e||0)}},{key:"dispatch",value:function(){var e=this,t=this,n=this.nextPlugin();if(!n){var r=this.nextPromisedPatch();if(r)return r.then((function(){return e.dispatch()})).catch((function(){return e.dispatch()}));var o={spec:this.state,errors:this.errors};return this.showDebug&&(o.patches=this.allPatches),Ee.a.resolve(o)}if(t.pluginCount=t.pluginCount||{},t.pluginCount[n]=(t.pluginCount[n]||0)+1,t.pluginCount[n]>100)return Ee.a.resolve({spec:t.state,errors:t.errors.concat(new Error("We've reached a hard limit of ".concat(100," plugin runs")))});if(n!==this.currentPlugin&&this.promisedPatches.length){var a=this.promisedPatches.map((function(e){return e.value}));return Ee.a.all(a.map((function(e){return e.then(Ae.a,Ae.a)}))).then((function(){return e.dispatch()}))}return function(){t.currentPlugin=n;var e=t.getCurrentMutations(),r=t.mutations.length-1;try{if(n.isGenerator){var o,a=u()(n(e,t.getLib()));try{for(a.s();!(o=a.n()).done;){i(o.value)}}catch(e){a.e(e)}finally{a.f()}}else{i(n(e,t.getLib()))}}catch(e){console.error(e),i([je()(Se()(e),{plugin:n})])}finally{t.updatePluginHistory(n,{mutationIndex:r})}return t.dispatch()}();function i(e){e&&(e=De.fullyNormalizeArray(e),t.updatePatches(e,n))}}}]),e}();var Bt={refs:xt,allOf:Tt,parameters:Nt,properties:Rt},Ut=n(41);function Vt(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{},n=t.requestInterceptor,r=t.responseInterceptor,o=e.withCredentials?"include":"same-origin";return function(t){return e({url:t,loadSpec:!0,requestInterceptor:n,responseInterceptor:r,headers:{Accept:"application/json, application/yaml"},credentials:o}).then((function(e){return e.body}))}}function zt(e){var t=e.fetch,n=e.spec,r=e.url,o=e.mode,i=e.allowMetaPatches,s=void 0===i||i,u=e.pathDiscriminator,l=e.modelPropertyMacro,p=e.parameterMacro,f=e.requestInterceptor,d=e.responseInterceptor,h=e.skipNormalization,m=e.useCircularStructures,v=e.http,g=e.baseDoc;return g=g||r,v=t||v||te,n?y(n):Vt(v,{requestInterceptor:f,responseInterceptor:d})(g).then(y);function y(e){g&&(Bt.refs.docCache[g]=e),Bt.refs.fetchJSON=Vt(v,{requestInterceptor:f,responseInterceptor:d});var t,n=[Bt.refs];return"function"==typeof p&&n.push(Bt.parameters),"function"==typeof l&&n.push(Bt.properties),"strict"!==o&&n.push(Bt.allOf),(t={spec:e,context:{baseDoc:g},plugins:n,allowMetaPatches:s,pathDiscriminator:u,parameterMacro:p,modelPropertyMacro:l,useCircularStructures:m},new Lt(t).dispatch()).then(h?function(){var e=c()(a.a.mark((function e(t){return a.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:return e.abrupt("return",t);case 1:case"end":return e.stop()}}),e)})));return function(t){return e.apply(this,arguments)}}():Ut.d)}}var Ft=n(34),Jt=n.n(Ft),Wt=n(25),Ht=n.n(Wt),$t=n(218),Yt=n.n($t),Kt=n(33),Gt=n.n(Kt),Zt=n(371),Xt=n.n(Zt),Qt={body:function(e){var t=e.req,n=e.value;t.body=n},header:function(e){var t=e.req,n=e.parameter,r=e.value;t.headers=t.headers||{},void 0!==r&&(t.headers[n.name]=r)},query:function(e){var t=e.req,n=e.value,r=e.parameter;t.query=t.query||{},!1===n&&"boolean"===r.type&&(n="false");0===n&&["number","integer"].indexOf(r.type)>-1&&(n="0");if(n)t.query[r.name]={collectionFormat:r.collectionFormat,value:n};else if(r.allowEmptyValue&&void 0!==n){var o=r.name;t.query[o]=t.query[o]||{},t.query[o].allowEmptyValue=!0}},path:function(e){var t=e.req,n=e.value,r=e.parameter;t.url=t.url.split("{".concat(r.name,"}")).join(encodeURIComponent(n))},formData:function(e){var t=e.req,n=e.value,r=e.parameter;(n||r.allowEmptyValue)&&(t.form=t.form||{},t.form[r.name]={value:n,allowEmptyValue:r.allowEmptyValue,collectionFormat:r.collectionFormat})}};function en(e,t){return t.includes("application/json")?"string"==typeof e?e:m()(e):e.toString()}function tn(e){var t=e.req,n=e.value,r=e.parameter,o=r.name,a=r.style,i=r.explode,c=r.content;if(c){var s=d()(c)[0];t.url=t.url.split("{".concat(o,"}")).join(X(en(n,s),{escape:!0}))}else{var u=Q({key:r.name,value:n,style:a||"simple",explode:i||!1,escape:!0});t.url=t.url.split("{".concat(o,"}")).join(u)}}function nn(e){var t=e.req,n=e.value,r=e.parameter;if(t.query=t.query||{},r.content){var o=d()(r.content)[0];t.query[r.name]=en(n,o)}else if(!1===n&&(n="false"),0===n&&(n="0"),n)t.query[r.name]={value:n,serializationOption:k()(r,["style","explode","allowReserved"])};else if(r.allowEmptyValue&&void 0!==n){var a=r.name;t.query[a]=t.query[a]||{},t.query[a].allowEmptyValue=!0}}var rn=["accept","authorization","content-type"];function on(e){var t=e.req,n=e.parameter,r=e.value;if(t.headers=t.headers||{},!(rn.indexOf(n.name.toLowerCase())>-1))if(n.content){var o=d()(n.content)[0];t.headers[n.name]=en(r,o)}else void 0!==r&&(t.headers[n.name]=Q({key:n.name,value:r,style:n.style||"simple",explode:void 0!==n.explode&&n.explode,escape:!1}))}function an(e){var t=e.req,n=e.parameter,r=e.value;t.headers=t.headers||{};var o=b()(r);if(n.content){var a=d()(n.content)[0];t.headers.Cookie="".concat(n.name,"=").concat(en(r,a))}else if("undefined"!==o){var i="object"===o&&!g()(r)&&n.explode?"":"".concat(n.name,"=");t.headers.Cookie=i+Q({key:n.name,value:r,escape:!1,style:n.style||"form",explode:void 0!==n.explode&&n.explode})}}var cn=n(148),sn=n.n(cn),un=n(149),ln=n.n(un);function pn(e,t){var n=e.operation,r=e.requestBody,o=e.securities,a=e.spec,i=e.attachContentTypeForEmptyPayload,c=e.requestContentType;t=function(e){var t=e.request,n=e.securities,r=void 0===n?{}:n,o=e.operation,a=void 0===o?{}:o,i=e.spec,c=sn()({},t),s=r.authorized,u=void 0===s?{}:s,l=a.security||i.security||[],p=u&&!!d()(u).length,f=Ht()(i,["components","securitySchemes"])||{};if(c.headers=c.headers||{},c.query=c.query||{},!d()(r).length||!p||!l||g()(a.security)&&!a.security.length)return t;return l.forEach((function(e){d()(e).forEach((function(e){var t=u[e],n=f[e];if(t){var r=t.value||t,o=n.type;if(t)if("apiKey"===o)"query"===n.in&&(c.query[n.name]=r),"header"===n.in&&(c.headers[n.name]=r),"cookie"===n.in&&(c.cookies[n.name]=r);else if("http"===o){if(/^basic$/i.test(n.scheme)){var a=r.username||"",i=r.password||"",s=ln()("".concat(a,":").concat(i));c.headers.Authorization="Basic ".concat(s)}/^bearer$/i.test(n.scheme)&&(c.headers.Authorization="Bearer ".concat(r))}else if("oauth2"===o){var l=t.token||{},p=l[n["x-tokenName"]||"access_token"],d=l.token_type;d&&"bearer"!==d.toLowerCase()||(d="Bearer"),c.headers.Authorization="".concat(d," ").concat(p)}}}))})),c}({request:t,securities:o,operation:n,spec:a});var s=n.requestBody||{},u=d()(s.content||{}),l=c&&u.indexOf(c)>-1;if(r||i){if(c&&l)t.headers["Content-Type"]=c;else if(!c){var p=u[0];p&&(t.headers["Content-Type"]=p,c=p)}}else c&&l&&(t.headers["Content-Type"]=c);if(r)if(c){if(u.indexOf(c)>-1)if("application/x-www-form-urlencoded"===c||"multipart/form-data"===c)if("object"===b()(r)){var f=(s.content[c]||{}).encoding||{};t.form={},d()(r).forEach((function(e){t.form[e]={value:r[e],encoding:f[e]||{}}}))}else t.form=r;else t.body=r}else t.body=r;return t}function fn(e,t){var n=e.spec,r=e.operation,o=e.securities,a=e.requestContentType,i=e.attachContentTypeForEmptyPayload;if((t=function(e){var t=e.request,n=e.securities,r=void 0===n?{}:n,o=e.operation,a=void 0===o?{}:o,i=e.spec,c=sn()({},t),s=r.authorized,u=void 0===s?{}:s,l=r.specSecurity,p=void 0===l?[]:l,f=a.security||p,h=u&&!!d()(u).length,m=i.securityDefinitions;if(c.headers=c.headers||{},c.query=c.query||{},!d()(r).length||!h||!f||g()(a.security)&&!a.security.length)return t;return f.forEach((function(e){d()(e).forEach((function(e){var t=u[e];if(t){var n=t.token,r=t.value||t,o=m[e],a=o.type,i=o["x-tokenName"]||"access_token",s=n&&n[i],l=n&&n.token_type;if(t)if("apiKey"===a){var p="query"===o.in?"query":"headers";c[p]=c[p]||{},c[p][o.name]=r}else if("basic"===a)if(r.header)c.headers.authorization=r.header;else{var f=r.username||"",d=r.password||"";r.base64=ln()("".concat(f,":").concat(d)),c.headers.authorization="Basic ".concat(r.base64)}else"oauth2"===a&&s&&(l=l&&"bearer"!==l.toLowerCase()?l:"Bearer",c.headers.authorization="".concat(l," ").concat(s))}}))})),c}({request:t,securities:o,operation:r,spec:n})).body||t.form||i)if(a)t.headers["Content-Type"]=a;else if(g()(r.consumes)){var c=x()(r.consumes,1);t.headers["Content-Type"]=c[0]}else if(g()(n.consumes)){var s=x()(n.consumes,1);t.headers["Content-Type"]=s[0]}else r.parameters&&r.parameters.filter((function(e){return"file"===e.type})).length?t.headers["Content-Type"]="multipart/form-data":r.parameters&&r.parameters.filter((function(e){return"formData"===e.in})).length&&(t.headers["Content-Type"]="application/x-www-form-urlencoded");else if(a){var u=r.parameters&&r.parameters.filter((function(e){return"body"===e.in})).length>0,l=r.parameters&&r.parameters.filter((function(e){return"formData"===e.in})).length>0;(u||l)&&(t.headers["Content-Type"]=a)}return t}var dn=function(e){return g()(e)?e:[]},hn=at("OperationNotFoundError",(function(e,t,n){this.originalError=n,je()(this,t||{})})),mn={buildRequest:gn};function vn(e){var t=e.http,n=e.fetch,r=e.spec,o=e.operationId,a=e.pathName,i=e.method,c=e.parameters,s=e.securities,u=Jt()(e,["http","fetch","spec","operationId","pathName","method","parameters","securities"]),l=t||n||te;a&&i&&!o&&(o=Object(Ut.c)(a,i));var p=mn.buildRequest(ye()({spec:r,operationId:o,parameters:c,securities:s,http:l},u));return p.body&&(Yt()(p.body)||Gt()(p.body))&&(p.body=m()(p.body)),l(p)}function gn(e){var t=e.spec,n=e.operationId,o=e.responseContentType,a=e.scheme,i=e.requestInterceptor,c=e.responseInterceptor,s=e.contextUrl,u=e.userFetch,l=e.server,p=e.serverVariables,f=e.http,h=e.parameters,m=e.parameterBuilders,v=Object(Ut.b)(t);m||(m=v?r:Qt);var y={url:"",credentials:f&&f.withCredentials?"include":"same-origin",headers:{},cookies:{}};i&&(y.requestInterceptor=i),c&&(y.responseInterceptor=c),u&&(y.userFetch=u);var b=Object(Ut.a)(t,n);if(!b)throw new hn("Operation ".concat(n," not found"));var E,S=b.operation,w=void 0===S?{}:S,j=b.method,_=b.pathName;if(y.url+=(E={spec:t,scheme:a,contextUrl:s,server:l,serverVariables:p,pathName:_,method:j},Object(Ut.b)(E.spec)?function(e){var t=e.spec,n=e.pathName,r=e.method,o=e.server,a=e.contextUrl,i=e.serverVariables,c=void 0===i?{}:i,s=Ht()(t,["paths",n,(r||"").toLowerCase(),"servers"])||Ht()(t,["paths",n,"servers"])||Ht()(t,["servers"]),u="",l=null;if(o&&s&&s.length){var p=s.map((function(e){return e.url}));p.indexOf(o)>-1&&(u=o,l=s[p.indexOf(o)])}if(!u&&s&&s.length){u=s[0].url;var f=x()(s,1);l=f[0]}return u.indexOf("{")>-1&&function(e){for(var t,n=[],r=/{([^}]+)}/g;t=r.exec(e);)n.push(t[1]);return n}(u).forEach((function(e){if(l.variables&&l.variables[e]){var t=l.variables[e],n=c[e]||t.default,r=new RegExp("{".concat(e,"}"),"g");u=u.replace(r,n)}})),function(){var e,t=arguments.length>0&&void 0!==arguments[0]?arguments[0]:"",n=arguments.length>1&&void 0!==arguments[1]?arguments[1]:"",r=ot.a.parse(t),o=ot.a.parse(n),a=yn(r.protocol)||yn(o.protocol)||"",i=r.host||o.host,c=r.pathname||"";return"/"===(e=a&&i?"".concat(a,"://").concat(i+c):c)[e.length-1]?e.slice(0,-1):e}(u,a)}(E):function(e){var t,n=e.spec,r=e.scheme,o=e.contextUrl,a=void 0===o?"":o,i=ot.a.parse(a),c=g()(n.schemes)?n.schemes[0]:null,s=r||c||yn(i.protocol)||"http",u=n.host||i.host||"",l=n.basePath||"";return"/"===(t=s&&u?"".concat(s,"://").concat(u+l):l)[t.length-1]?t.slice(0,-1):t}(E)),!n)return delete y.cookies,y;y.url+=_,y.method="".concat(j).toUpperCase(),h=h||{};var O=t.paths[_]||{};o&&(y.headers.accept=o);var C=function(e){var t={};e.forEach((function(e){t[e.in]||(t[e.in]={}),t[e.in][e.name]=e}));var n=[];return d()(t).forEach((function(e){d()(t[e]).forEach((function(r){n.push(t[e][r])}))})),n}([].concat(dn(w.parameters)).concat(dn(O.parameters)));C.forEach((function(e){var n,r=m[e.in];if("body"===e.in&&e.schema&&e.schema.properties&&(n=h),void 0===(n=e&&e.name&&h[e.name])?n=e&&e.name&&h["".concat(e.in,".").concat(e.name)]:function(e,t){return t.filter((function(t){return t.name===e}))}(e.name,C).length>1&&console.warn("Parameter '".concat(e.name,"' is ambiguous because the defined spec has more than one parameter with the name: '").concat(e.name,"' and the passed-in parameter values did not define an 'in' value.")),null!==n){if(void 0!==e.default&&void 0===n&&(n=e.default),void 0===n&&e.required&&!e.allowEmptyValue)throw new Error("Required parameter ".concat(e.name," is not provided"));if(v&&e.schema&&"object"===e.schema.type&&"string"==typeof n)try{n=JSON.parse(n)}catch(e){throw new Error("Could not parse object parameter value string as JSON")}r&&r({req:y,parameter:e,value:n,operation:w,spec:t})}}));var A=ye()(ye()({},e),{},{operation:w});if((y=v?pn(A,y):fn(A,y)).cookies&&d()(y.cookies).length){var k=d()(y.cookies).reduce((function(e,t){var n=y.cookies[t];return e+(e?"&":"")+Xt.a.serialize(t,n)}),"");y.headers.Cookie=k}return y.cookies&&delete y.cookies,ve(y),y}var yn=function(e){return e?e.replace(/\W/g,""):null};function bn(e,t){return En.apply(this,arguments)}function En(){return(En=c()(a.a.mark((function e(t,n){var r,o,i,c,s,u,l,p,f,d,h,m,v=arguments;return a.a.wrap((function(e){for(;;)switch(e.prev=e.next){case 0:return r=v.length>2&&void 0!==v[2]?v[2]:{},o=r.returnEntireTree,i=r.baseDoc,c=r.requestInterceptor,s=r.responseInterceptor,u=r.parameterMacro,l=r.modelPropertyMacro,p=r.useCircularStructures,f={pathDiscriminator:n,baseDoc:i,requestInterceptor:c,responseInterceptor:s,parameterMacro:u,modelPropertyMacro:l,useCircularStructures:p},d=Object(Ut.d)({spec:t}),h=d.spec,e.next=6,zt(ye()(ye()({},f),{},{spec:h,allowMetaPatches:!0,skipNormalization:!0}));case 6:return m=e.sent,!o&&g()(n)&&n.length&&(m.spec=Ht()(m.spec,n)||null),e.abrupt("return",m);case 9:case"end":return e.stop()}}),e)})))).apply(this,arguments)}var xn=n(221);t.default=function(e){var t,n,r,o=e.configs,a=e.getConfigs;return{fn:{fetch:(t=te,n=o.preFetch,r=o.postFetch,r=r||function(e){return e},n=n||function(e){return e},function(e){return"string"==typeof e&&(e={url:e}),ee.mergeInQueryOrForm(e),e=n(e),r(t(e))}),buildRequest:gn,execute:vn,resolve:zt,resolveSubtree:function(e,t,n){if(void 0===n){var r=a();n={modelPropertyMacro:r.modelPropertyMacro,parameterMacro:r.parameterMacro,requestInterceptor:r.requestInterceptor,responseInterceptor:r.responseInterceptor}}for(var o=arguments.length,i=new Array(o>3?o-3:0),c=3;c<o;c++)i[c-3]=arguments[c];return bn.apply(void 0,[e,t,n].concat(i))},serializeRes:ae,opId:Ut.e},statePlugins:{configs:{wrapActions:xn}}}}},function(e,t,n){"use strict";n.r(t),n.d(t,"default",(function(){return u}));var r=n(110),o=n(94),a=n(228),i=n(229),c=n(230),s={getLocalConfig:function(){return Object(r.parseYamlConfig)('---\nurl: "https://petstore.swagger.io/v2/swagger.json"\ndom_id: "#swagger-ui"\nvalidatorUrl: "https://validator.swagger.io/validator"\n')}};function u(){return{statePlugins:{spec:{actions:a,selectors:s},configs:{reducers:c.default,actions:o,selectors:i}}}}},function(e,t){e.exports=require("memoizee")},function(e,t,n){e.exports=n(580)},function(e,t,n){var r=n(335);e.exports=function(e,t,n){return null==e?e:r(e,t,n)}},function(e,t){e.exports=require("react-redux")},function(e,t){e.exports=function(){}},function(e,t){e.exports=require("querystring-browser")},function(e,t,n){var r=n(653),o=n(302),a=n(322),i=n(55);e.exports=function(e,t,n){return e=i(e),n=null==n?0:r(a(n),0,e.length),t=o(t),e.slice(n,n+t.length)==t}},function(e,t){e.exports=require("react-debounce-input")},function(e,t,n){var r=n(293),o=n(326),a=n(128),i=n(301);e.exports=function(e){return r(e)||o(e)||a(e)||i()}},function(e,t){e.exports=function(e,t){return{enumerable:!(1&e),configurable:!(2&e),writable:!(4&e),value:t}}},function(e,t,n){"use strict";var r=n(161)(!0);n(274)(String,"String",(function(e){this._t=String(e),this._i=0}),(function(){var e,t=this._t,n=this._i;return n>=t.length?{value:void 0,done:!0}:(e=r(t,n),this._i+=e.length,{value:e,done:!1})}))},function(e,t,n){"use strict";var r=n(158),o=n(35),a=n(77),i=n(68),c=n(100),s=n(392),u=n(163),l=n(398),p=n(29)("iterator"),f=!([].keys&&"next"in[].keys()),d=function(){return this};e.exports=function(e,t,n,h,m,v,g){s(n,t,h);var y,b,E,x=function(e){if(!f&&e in _)return _[e];switch(e){case"keys":case"values":return function(){return new n(this,e)}}return function(){return new n(this,e)}},S=t+" Iterator",w="values"==m,j=!1,_=e.prototype,O=_[p]||_["@@iterator"]||m&&_[m],C=O||x(m),A=m?w?x("entries"):C:void 0,k="Array"==t&&_.entries||O;if(k&&(E=l(k.call(new e)))!==Object.prototype&&E.next&&(u(E,S,!0),r||"function"==typeof E[p]||i(E,p,d)),w&&O&&"values"!==O.name&&(j=!0,C=function(){return O.call(this)}),r&&!g||!f&&!j&&_[p]||i(_,p,C),c[t]=C,c[S]=d,m)if(y={values:w?C:x("values"),keys:v?C:x("keys"),entries:A},g)for(b in y)b in _||a(_,b,y[b]);else o(o.P+o.F*(f||j),t,y);return y}},function(e,t,n){var r=n(395),o=n(277);e.exports=Object.keys||function(e){return r(e,o)}},function(e,t,n){var r=n(99),o=Math.max,a=Math.min;e.exports=function(e,t){return(e=r(e))<0?o(e+t,0):a(e,t)}},function(e,t){e.exports="constructor,hasOwnProperty,isPrototypeOf,propertyIsEnumerable,toLocaleString,toString,valueOf".split(",")},function(e,t,n){var r=n(37).document;e.exports=r&&r.documentElement},function(e,t,n){var r=n(60);e.exports=function(e){return Object(r(e))}},function(e,t,n){var r,o,a,i=n(117),c=n(409),s=n(278),u=n(160),l=n(37),p=l.process,f=l.setImmediate,d=l.clearImmediate,h=l.MessageChannel,m=l.Dispatch,v=0,g={},y=function(){var e=+this;if(g.hasOwnProperty(e)){var t=g[e];delete g[e],t()}},b=function(e){y.call(e.data)};f&&d||(f=function(e){for(var t=[],n=1;arguments.length>n;)t.push(arguments[n++]);return g[++v]=function(){c("function"==typeof e?e:Function(e),t)},r(v),v},d=function(e){delete g[e]},"process"==n(97)(p)?r=function(e){p.nextTick(i(y,e,1))}:m&&m.now?r=function(e){m.now(i(y,e,1))}:h?(a=(o=new h).port2,o.port1.onmessage=b,r=i(a.postMessage,a,1)):l.addEventListener&&"function"==typeof postMessage&&!l.importScripts?(r=function(e){l.postMessage(e+"","*")},l.addEventListener("message",b,!1)):r="onreadystatechange"in u("script")?function(e){s.appendChild(u("script")).onreadystatechange=function(){s.removeChild(this),y.call(e)}}:function(e){setTimeout(i(y,e,1),0)}),e.exports={set:f,clear:d}},function(e,t){e.exports=function(e){try{return{e:!1,v:e()}}catch(e){return{e:!0,v:e}}}},function(e,t,n){var r=n(44),o=n(78),a=n(165);e.exports=function(e,t){if(r(e),o(t)&&t.constructor===e)return t;var n=a.f(e);return(0,n.resolve)(t),n.promise}},function(e,t,n){var r=n(78),o=n(97),a=n(29)("match");e.exports=function(e){var t;return r(e)&&(void 0!==(t=e[a])?!!t:"RegExp"==o(e))}},function(e,t,n){var r=n(51),o=n(52),a=n(452)(!1),i=n(173)("IE_PROTO");e.exports=function(e,t){var n,c=o(e),s=0,u=[];for(n in c)n!=i&&r(c,n)&&u.push(n);for(;t.length>s;)r(c,n=t[s++])&&(~a(u,n)||u.push(n));return u}},function(e,t,n){e.exports=!n(39)&&!n(63)((function(){return 7!=Object.defineProperty(n(177)("div"),"a",{get:function(){return 7}}).a}))},function(e,t,n){e.exports=n(454)},function(e,t,n){var r=n(48),o=n(36),a=n(80);e.exports=n(39)?Object.defineProperties:function(e,t){o(e);for(var n,i=a(t),c=i.length,s=0;c>s;)r.f(e,n=i[s++],t[n]);return e}},function(e,t,n){var r=n(28).document;e.exports=r&&r.documentElement},function(e,t){e.exports=function(e,t){return{value:t,done:!!e}}},function(e,t,n){"use strict";var r=n(28),o=n(51),a=n(39),i=n(24),c=n(180),s=n(106).KEY,u=n(63),l=n(174),p=n(105),f=n(123),d=n(32),h=n(182),m=n(183),v=n(460),g=n(184),y=n(36),b=n(30),E=n(69),x=n(52),S=n(178),w=n(103),j=n(104),_=n(461),O=n(108),C=n(125),A=n(48),k=n(80),P=O.f,I=A.f,T=_.f,N=r.Symbol,R=r.JSON,M=R&&R.stringify,D=d("_hidden"),q=d("toPrimitive"),L={}.propertyIsEnumerable,B=l("symbol-registry"),U=l("symbols"),V=l("op-symbols"),z=Object.prototype,F="function"==typeof N&&!!C.f,J=r.QObject,W=!J||!J.prototype||!J.prototype.findChild,H=a&&u((function(){return 7!=j(I({},"a",{get:function(){return I(this,"a",{value:7}).a}})).a}))?function(e,t,n){var r=P(z,t);r&&delete z[t],I(e,t,n),r&&e!==z&&I(z,t,r)}:I,$=function(e){var t=U[e]=j(N.prototype);return t._k=e,t},Y=F&&"symbol"==typeof N.iterator?function(e){return"symbol"==typeof e}:function(e){return e instanceof N},K=function(e,t,n){return e===z&&K(V,t,n),y(e),t=S(t,!0),y(n),o(U,t)?(n.enumerable?(o(e,D)&&e[D][t]&&(e[D][t]=!1),n=j(n,{enumerable:w(0,!1)})):(o(e,D)||I(e,D,w(1,{})),e[D][t]=!0),H(e,t,n)):I(e,t,n)},G=function(e,t){y(e);for(var n,r=v(t=x(t)),o=0,a=r.length;a>o;)K(e,n=r[o++],t[n]);return e},Z=function(e){var t=L.call(this,e=S(e,!0));return!(this===z&&o(U,e)&&!o(V,e))&&(!(t||!o(this,e)||!o(U,e)||o(this,D)&&this[D][e])||t)},X=function(e,t){if(e=x(e),t=S(t,!0),e!==z||!o(U,t)||o(V,t)){var n=P(e,t);return!n||!o(U,t)||o(e,D)&&e[D][t]||(n.enumerable=!0),n}},Q=function(e){for(var t,n=T(x(e)),r=[],a=0;n.length>a;)o(U,t=n[a++])||t==D||t==s||r.push(t);return r},ee=function(e){for(var t,n=e===z,r=T(n?V:x(e)),a=[],i=0;r.length>i;)!o(U,t=r[i++])||n&&!o(z,t)||a.push(U[t]);return a};F||(c((N=function(){if(this instanceof N)throw TypeError("Symbol is not a constructor!");var e=f(arguments.length>0?arguments[0]:void 0),t=function(n){this===z&&t.call(V,n),o(this,D)&&o(this[D],e)&&(this[D][e]=!1),H(this,e,w(1,n))};return a&&W&&H(z,e,{configurable:!0,set:t}),$(e)}).prototype,"toString",(function(){return this._k})),O.f=X,A.f=K,n(185).f=_.f=Q,n(107).f=Z,C.f=ee,a&&!n(102)&&c(z,"propertyIsEnumerable",Z,!0),h.f=function(e){return $(d(e))}),i(i.G+i.W+i.F*!F,{Symbol:N});for(var te="hasInstance,isConcatSpreadable,iterator,match,replace,search,species,split,toPrimitive,toStringTag,unscopables".split(","),ne=0;te.length>ne;)d(te[ne++]);for(var re=k(d.store),oe=0;re.length>oe;)m(re[oe++]);i(i.S+i.F*!F,"Symbol",{for:function(e){return o(B,e+="")?B[e]:B[e]=N(e)},keyFor:function(e){if(!Y(e))throw TypeError(e+" is not a symbol!");for(var t in B)if(B[t]===e)return t},useSetter:function(){W=!0},useSimple:function(){W=!1}}),i(i.S+i.F*!F,"Object",{create:function(e,t){return void 0===t?j(e):G(j(e),t)},defineProperty:K,defineProperties:G,getOwnPropertyDescriptor:X,getOwnPropertyNames:Q,getOwnPropertySymbols:ee});var ae=u((function(){C.f(1)}));i(i.S+i.F*ae,"Object",{getOwnPropertySymbols:function(e){return C.f(E(e))}}),R&&i(i.S+i.F*(!F||u((function(){var e=N();return"[null]"!=M([e])||"{}"!=M({a:e})||"{}"!=M(Object(e))}))),"JSON",{stringify:function(e){for(var t,n,r=[e],o=1;arguments.length>o;)r.push(arguments[o++]);if(n=t=r[1],(b(t)||void 0!==e)&&!Y(e))return g(t)||(t=function(e,t){if("function"==typeof n&&(t=n.call(this,e,t)),!Y(t))return t}),r[1]=t,M.apply(R,r)}}),N.prototype[q]||n(62)(N.prototype,q,N.prototype.valueOf),p(N,"Symbol"),p(Math,"Math",!0),p(r.JSON,"JSON",!0)},function(e,t,n){"use strict";var r=n(39),o=n(80),a=n(125),i=n(107),c=n(69),s=n(171),u=Object.assign;e.exports=!u||n(63)((function(){var e={},t={},n=Symbol(),r="abcdefghijklmnopqrst";return e[n]=7,r.split("").forEach((function(e){t[e]=e})),7!=u({},e)[n]||Object.keys(u({},t)).join("")!=r}))?function(e,t){for(var n=c(e),u=arguments.length,l=1,p=a.f,f=i.f;u>l;)for(var d,h=s(arguments[l++]),m=p?o(h).concat(p(h)):o(h),v=m.length,g=0;v>g;)d=m[g++],r&&!f.call(h,d)||(n[d]=h[d]);return n}:u},function(e,t){var n;n=function(){return this}();try{n=n||new Function("return this")()}catch(e){"object"==typeof window&&(n=window)}e.exports=n},function(e,t,n){var r=n(10);e.exports=function(e){if(r(e))return e}},function(e,t,n){e.exports=n(475)},function(e,t,n){e.exports=n(477)},function(e,t,n){var r=n(36);e.exports=function(e,t,n,o){try{return o?t(r(n)[0],n[1]):t(n)}catch(t){var a=e.return;throw void 0!==a&&r(a.call(e)),t}}},function(e,t,n){var r=n(82),o=n(32)("iterator"),a=Array.prototype;e.exports=function(e){return void 0!==e&&(r.Array===e||a[o]===e)}},function(e,t,n){"use strict";var r=n(48),o=n(103);e.exports=function(e,t,n){t in e?r.f(e,t,o(0,n)):e[t]=n}},function(e,t,n){var r=n(32)("iterator"),o=!1;try{var a=[7][r]();a.return=function(){o=!0},Array.from(a,(function(){throw 2}))}catch(e){}e.exports=function(e,t){if(!t&&!o)return!1;var n=!1;try{var a=[7],i=a[r]();i.next=function(){return{done:n=!0}},a[r]=function(){return i},e(a)}catch(e){}return n}},function(e,t){e.exports=function(e,t){(null==t||t>e.length)&&(t=e.length);for(var n=0,r=new Array(t);n<t;n++)r[n]=e[n];return r}},function(e,t){e.exports=function(){throw new TypeError("Invalid attempt to destructure non-iterable instance.\nIn order to be iterable, non-array objects must have a [Symbol.iterator]() method.")}},function(e,t,n){var r=n(84),o=n(304),a=n(33),i=n(129),c=r?r.prototype:void 0,s=c?c.toString:void 0;e.exports=function e(t){if("string"==typeof t)return t;if(a(t))return o(t,e)+"";if(i(t))return s?s.call(t):"";var n=t+"";return"0"==n&&1/t==-1/0?"-0":n}},function(e,t,n){(function(t){var n="object"==typeof t&&t&&t.Object===Object&&t;e.exports=n}).call(this,n(292))},function(e,t){e.exports=function(e,t){for(var n=-1,r=null==e?0:e.length,o=Array(r);++n<r;)o[n]=t(e[n],n,e);return o}},function(e,t){e.exports=function(e,t,n){var r=-1,o=e.length;t<0&&(t=-t>o?0:o+t),(n=n>o?o:n)<0&&(n+=o),o=t>n?0:n-t>>>0,t>>>=0;for(var a=Array(o);++r<o;)a[r]=e[r+t];return a}},function(e,t){var n=RegExp("[\\u200d\\ud800-\\udfff\\u0300-\\u036f\\ufe20-\\ufe2f\\u20d0-\\u20ff\\ufe0e\\ufe0f]");e.exports=function(e){return n.test(e)}},function(e,t){e.exports=function(e,t,n,r){var o=-1,a=null==e?0:e.length;for(r&&a&&(n=e[++o]);++o<a;)n=t(n,e[o],o,e);return n}},function(e,t){var n=Function.prototype.toString;e.exports=function(e){if(null!=e){try{return n.call(e)}catch(e){}try{return e+""}catch(e){}}return""}},function(e,t,n){var r=n(526),o=n(54);e.exports=function e(t,n,a,i,c){return t===n||(null==t||null==n||!o(t)&&!o(n)?t!=t&&n!=n:r(t,n,a,i,e,c))}},function(e,t,n){var r=n(527),o=n(311),a=n(530);e.exports=function(e,t,n,i,c,s){var u=1&n,l=e.length,p=t.length;if(l!=p&&!(u&&p>l))return!1;var f=s.get(e);if(f&&s.get(t))return f==t;var d=-1,h=!0,m=2&n?new r:void 0;for(s.set(e,t),s.set(t,e);++d<l;){var v=e[d],g=t[d];if(i)var y=u?i(g,v,d,t,e,s):i(v,g,d,e,t,s);if(void 0!==y){if(y)continue;h=!1;break}if(m){if(!o(t,(function(e,t){if(!a(m,t)&&(v===e||c(v,e,n,i,s)))return m.push(t)}))){h=!1;break}}else if(v!==g&&!c(v,g,n,i,s)){h=!1;break}}return s.delete(e),s.delete(t),h}},function(e,t){e.exports=function(e,t){for(var n=-1,r=null==e?0:e.length;++n<r;)if(t(e[n],n,e))return!0;return!1}},function(e,t,n){var r=n(49).Uint8Array;e.exports=r},function(e,t,n){var r=n(314),o=n(191),a=n(73);e.exports=function(e){return r(e,a,o)}},function(e,t,n){var r=n(190),o=n(33);e.exports=function(e,t,n){var a=t(e);return o(e)?a:r(a,n(e))}},function(e,t){e.exports=function(){return[]}},function(e,t,n){var r=n(536),o=n(192),a=n(33),i=n(193),c=n(135),s=n(317),u=Object.prototype.hasOwnProperty;e.exports=function(e,t){var n=a(e),l=!n&&o(e),p=!n&&!l&&i(e),f=!n&&!l&&!p&&s(e),d=n||l||p||f,h=d?r(e.length,String):[],m=h.length;for(var v in e)!t&&!u.call(e,v)||d&&("length"==v||p&&("offset"==v||"parent"==v)||f&&("buffer"==v||"byteLength"==v||"byteOffset"==v)||c(v,m))||h.push(v);return h}},function(e,t,n){var r=n(539),o=n(196),a=n(197),i=a&&a.isTypedArray,c=i?o(i):r;e.exports=c},function(e,t){e.exports=function(e,t){return function(n){return e(t(n))}}},function(e,t,n){var r=n(40);e.exports=function(e){return e==e&&!r(e)}},function(e,t){e.exports=function(e,t){return function(n){return null!=n&&(n[e]===t&&(void 0!==t||e in Object(n)))}}},function(e,t,n){var r=n(550),o=n(551);e.exports=function(e,t){return null!=e&&o(e,t,r)}},function(e,t,n){var r=n(557);e.exports=function(e){var t=r(e),n=t%1;return t==t?n?t-n:t:0}},function(e,t,n){var r=n(40),o=n(129),a=/^\s+|\s+$/g,i=/^[-+]0x[0-9a-f]+$/i,c=/^0b[01]+$/i,s=/^0o[0-7]+$/i,u=parseInt;e.exports=function(e){if("number"==typeof e)return e;if(o(e))return NaN;if(r(e)){var t="function"==typeof e.valueOf?e.valueOf():e;e=r(t)?t+"":t}if("string"!=typeof e)return 0===e?e:+e;e=e.replace(a,"");var n=c.test(e);return n||s.test(e)?u(e.slice(2),n?2:8):i.test(e)?NaN:+e}},function(e,t,n){var r=n(559),o=n(562)(r);e.exports=o},function(e,t,n){var r=n(74),o=n(85),a=n(135),i=n(40);e.exports=function(e,t,n){if(!i(n))return!1;var c=typeof t;return!!("number"==c?o(n)&&a(t,n.length):"string"==c&&t in n)&&r(n[t],e)}},function(e,t,n){var r=n(91),o=n(295),a=n(124);e.exports=function(e){if(void 0!==a&&o(Object(e)))return r(e)}},function(e,t,n){e.exports=n(576)},function(e,t,n){e.exports=n(578)},function(e,t,n){var r=n(36),o=n(70),a=n(32)("species");e.exports=function(e,t){var n,i=r(e).constructor;return void 0===i||null==(n=r(i)[a])?t:o(n)}},function(e,t,n){var r,o,a,i=n(53),c=n(331),s=n(288),u=n(177),l=n(28),p=l.process,f=l.setImmediate,d=l.clearImmediate,h=l.MessageChannel,m=l.Dispatch,v=0,g={},y=function(){var e=+this;if(g.hasOwnProperty(e)){var t=g[e];delete g[e],t()}},b=function(e){y.call(e.data)};f&&d||(f=function(e){for(var t=[],n=1;arguments.length>n;)t.push(arguments[n++]);return g[++v]=function(){c("function"==typeof e?e:Function(e),t)},r(v),v},d=function(e){delete g[e]},"process"==n(101)(p)?r=function(e){p.nextTick(i(y,e,1))}:m&&m.now?r=function(e){m.now(i(y,e,1))}:h?(a=(o=new h).port2,o.port1.onmessage=b,r=i(a.postMessage,a,1)):l.addEventListener&&"function"==typeof postMessage&&!l.importScripts?(r=function(e){l.postMessage(e+"","*")},l.addEventListener("message",b,!1)):r="onreadystatechange"in u("script")?function(e){s.appendChild(u("script")).onreadystatechange=function(){s.removeChild(this),y.call(e)}}:function(e){setTimeout(i(y,e,1),0)}),e.exports={set:f,clear:d}},function(e,t){e.exports=function(e,t,n){var r=void 0===n;switch(t.length){case 0:return r?e():e.call(n);case 1:return r?e(t[0]):e.call(n,t[0]);case 2:return r?e(t[0],t[1]):e.call(n,t[0],t[1]);case 3:return r?e(t[0],t[1],t[2]):e.call(n,t[0],t[1],t[2]);case 4:return r?e(t[0],t[1],t[2],t[3]):e.call(n,t[0],t[1],t[2],t[3])}return e.apply(n,t)}},function(e,t){e.exports=function(e){try{return{e:!1,v:e()}}catch(e){return{e:!0,v:e}}}},function(e,t,n){var r=n(36),o=n(30),a=n(200);e.exports=function(e,t){if(r(e),o(t)&&t.constructor===e)return t;var n=a.f(e);return(0,n.resolve)(t),n.promise}},function(e,t,n){"use strict";var r=n(28),o=n(21),a=n(48),i=n(39),c=n(32)("species");e.exports=function(e){var t="function"==typeof o[e]?o[e]:r[e];i&&t&&!t[c]&&a.f(t,c,{configurable:!0,get:function(){return this}})}},function(e,t,n){var r=n(141),o=n(86),a=n(135),i=n(40),c=n(87);e.exports=function(e,t,n,s){if(!i(e))return e;for(var u=-1,l=(t=o(t,e)).length,p=l-1,f=e;null!=f&&++u<l;){var d=c(t[u]),h=n;if(u!=p){var m=f[d];void 0===(h=s?s(m,d,f):void 0)&&(h=i(m)?m:a(t[u+1])?[]:{})}r(f,d,h),f=f[d]}return e}},function(e,t,n){var r=n(337);e.exports=function(e,t,n){"__proto__"==t&&r?r(e,t,{configurable:!0,enumerable:!0,value:n,writable:!0}):e[t]=n}},function(e,t,n){var r=n(72),o=function(){try{var e=r(Object,"defineProperty");return e({},"",{}),e}catch(e){}}();e.exports=o},function(e,t,n){e.exports=n(593)},function(e,t,n){e.exports=n(596)},function(e,t,n){var r=n(189),o=n(604),a=n(141),i=n(605),c=n(606),s=n(609),u=n(610),l=n(611),p=n(612),f=n(313),d=n(343),h=n(137),m=n(613),v=n(614),g=n(619),y=n(33),b=n(193),E=n(621),x=n(40),S=n(623),w=n(73),j={};j["[object Arguments]"]=j["[object Array]"]=j["[object ArrayBuffer]"]=j["[object DataView]"]=j["[object Boolean]"]=j["[object Date]"]=j["[object Float32Array]"]=j["[object Float64Array]"]=j["[object Int8Array]"]=j["[object Int16Array]"]=j["[object Int32Array]"]=j["[object Map]"]=j["[object Number]"]=j["[object Object]"]=j["[object RegExp]"]=j["[object Set]"]=j["[object String]"]=j["[object Symbol]"]=j["[object Uint8Array]"]=j["[object Uint8ClampedArray]"]=j["[object Uint16Array]"]=j["[object Uint32Array]"]=!0,j["[object Error]"]=j["[object Function]"]=j["[object WeakMap]"]=!1,e.exports=function e(t,n,_,O,C,A){var k,P=1&n,I=2&n,T=4&n;if(_&&(k=C?_(t,O,C,A):_(t)),void 0!==k)return k;if(!x(t))return t;var N=y(t);if(N){if(k=m(t),!P)return u(t,k)}else{var R=h(t),M="[object Function]"==R||"[object GeneratorFunction]"==R;if(b(t))return s(t,P);if("[object Object]"==R||"[object Arguments]"==R||M&&!C){if(k=I||M?{}:g(t),!P)return I?p(t,c(k,t)):l(t,i(k,t))}else{if(!j[R])return C?t:{};k=v(t,R,P)}}A||(A=new r);var D=A.get(t);if(D)return D;A.set(t,k),S(t)?t.forEach((function(r){k.add(e(r,n,_,r,t,A))})):E(t)&&t.forEach((function(r,o){k.set(o,e(r,n,_,o,t,A))}));var q=T?I?d:f:I?keysIn:w,L=N?void 0:q(t);return o(L||t,(function(r,o){L&&(r=t[o=r]),a(k,o,e(r,n,_,o,t,A))})),k}},function(e,t,n){var r=n(316),o=n(607),a=n(85);e.exports=function(e){return a(e)?r(e,!0):o(e)}},function(e,t,n){var r=n(190),o=n(201),a=n(191),i=n(315),c=Object.getOwnPropertySymbols?function(e){for(var t=[];e;)r(t,a(e)),e=o(e);return t}:i;e.exports=c},function(e,t,n){var r=n(314),o=n(342),a=n(341);e.exports=function(e){return r(e,a,o)}},function(e,t,n){var r=n(629),o=n(345),a=n(346);e.exports=function(e){return a(o(e,void 0,r),e+"")}},function(e,t,n){var r=n(632),o=Math.max;e.exports=function(e,t,n){return t=o(void 0===t?e.length-1:t,0),function(){for(var a=arguments,i=-1,c=o(a.length-t,0),s=Array(c);++i<c;)s[i]=a[t+i];i=-1;for(var u=Array(t+1);++i<t;)u[i]=a[i];return u[t]=n(s),r(e,this,u)}}},function(e,t,n){var r=n(633),o=n(635)(r);e.exports=o},function(e,t,n){"use strict";var r=n(28),o=n(24),a=n(106),i=n(63),c=n(62),s=n(140),u=n(88),l=n(139),p=n(30),f=n(105),d=n(48).f,h=n(203)(0),m=n(39);e.exports=function(e,t,n,v,g,y){var b=r[e],E=b,x=g?"set":"add",S=E&&E.prototype,w={};return m&&"function"==typeof E&&(y||S.forEach&&!i((function(){(new E).entries().next()})))?(E=t((function(t,n){l(t,E,e,"_c"),t._c=new b,null!=n&&u(n,g,t[x],t)})),h("add,clear,delete,forEach,get,has,set,keys,values,entries,toJSON".split(","),(function(e){var t="add"==e||"set"==e;!(e in S)||y&&"clear"==e||c(E.prototype,e,(function(n,r){if(l(this,E,e),!t&&y&&!p(n))return"get"==e&&void 0;var o=this._c[e](0===n?0:n,r);return t?this:o}))})),y||d(E.prototype,"size",{get:function(){return this._c.size}})):(E=v.getConstructor(t,e,g,x),s(E.prototype,n),a.NEED=!0),f(E,e),w[e]=E,o(o.G+o.W+o.F,w),y||v.setStrong(E,e,g),E}},function(e,t,n){"use strict";var r=n(24);e.exports=function(e){r(r.S,e,{of:function(){for(var e=arguments.length,t=new Array(e);e--;)t[e]=arguments[e];return new this(t)}})}},function(e,t,n){"use strict";var r=n(24),o=n(70),a=n(53),i=n(88);e.exports=function(e){r(r.S,e,{from:function(e){var t,n,r,c,s=arguments[1];return o(this),(t=void 0!==s)&&o(s),null==e?new this:(n=[],t?(r=0,c=a(s,arguments[2],2),i(e,!1,(function(e){n.push(c(e,r++))}))):i(e,!1,n.push,n),new this(n))}})}},function(e,t){e.exports="data:image/svg+xml;base64,PHN2ZyB3aWR0aD0iMjAwcHgiICBoZWlnaHQ9IjIwMHB4IiAgeG1sbnM9Imh0dHA6Ly93d3cudzMub3JnLzIwMDAvc3ZnIiB2aWV3Qm94PSIwIDAgMTAwIDEwMCIgcHJlc2VydmVBc3BlY3RSYXRpbz0ieE1pZFlNaWQiIGNsYXNzPSJsZHMtcm9sbGluZyIgc3R5bGU9ImJhY2tncm91bmQtaW1hZ2U6IG5vbmU7IGJhY2tncm91bmQtcG9zaXRpb246IGluaXRpYWwgaW5pdGlhbDsgYmFja2dyb3VuZC1yZXBlYXQ6IGluaXRpYWwgaW5pdGlhbDsiPjxjaXJjbGUgY3g9IjUwIiBjeT0iNTAiIGZpbGw9Im5vbmUiIG5nLWF0dHItc3Ryb2tlPSJ7e2NvbmZpZy5jb2xvcn19IiBuZy1hdHRyLXN0cm9rZS13aWR0aD0ie3tjb25maWcud2lkdGh9fSIgbmctYXR0ci1yPSJ7e2NvbmZpZy5yYWRpdXN9fSIgbmctYXR0ci1zdHJva2UtZGFzaGFycmF5PSJ7e2NvbmZpZy5kYXNoYXJyYXl9fSIgc3Ryb2tlPSIjNTU1NTU1IiBzdHJva2Utd2lkdGg9IjEwIiByPSIzNSIgc3Ryb2tlLWRhc2hhcnJheT0iMTY0LjkzMzYxNDMxMzQ2NDE1IDU2Ljk3Nzg3MTQzNzgyMTM4Ij48YW5pbWF0ZVRyYW5zZm9ybSBhdHRyaWJ1dGVOYW1lPSJ0cmFuc2Zvcm0iIHR5cGU9InJvdGF0ZSIgY2FsY01vZGU9ImxpbmVhciIgdmFsdWVzPSIwIDUwIDUwOzM2MCA1MCA1MCIga2V5VGltZXM9IjA7MSIgZHVyPSIxcyIgYmVnaW49IjBzIiByZXBlYXRDb3VudD0iaW5kZWZpbml0ZSI+PC9hbmltYXRlVHJhbnNmb3JtPjwvY2lyY2xlPjwvc3ZnPgo="},function(e,t){e.exports=require("redux-immutable")},function(e,t,n){"use strict";var r=/^(%20|\s)*(javascript|data)/im,o=/[^\x20-\x7E]/gim,a=/^([^:]+):/gm,i=[".","/"];e.exports={sanitizeUrl:function(e){var t,n,c;return e?function(e){return i.indexOf(e[0])>-1}(c=e.replace(o,"").trim())?c:(n=c.match(a))?(t=n[0],r.test(t)?"about:blank":c):c:"about:blank"}}},function(e,t,n){var r=n(481),o=n(489)((function(e,t,n){return t=t.toLowerCase(),e+(n?r(t):t)}));e.exports=o},function(e,t,n){var r=n(311),o=n(134),a=n(558),i=n(33),c=n(325);e.exports=function(e,t,n){var s=i(e)?r:a;return n&&c(e,t,n)&&(t=void 0),s(e,o(t,3))}},function(e,t,n){(function(t){var r=n(564),o=n(565).Stream;function a(e,t,n){n=n||0;var o,i,c=(o=t,new Array(n||0).join(o||"")),s=e;if("object"==typeof e&&((s=e[i=Object.keys(e)[0]])&&s._elem))return s._elem.name=i,s._elem.icount=n,s._elem.indent=t,s._elem.indents=c,s._elem.interrupt=s,s._elem;var u,l=[],p=[];function f(e){Object.keys(e).forEach((function(t){l.push(function(e,t){return e+'="'+r(t)+'"'}(t,e[t]))}))}switch(typeof s){case"object":if(null===s)break;s._attr&&f(s._attr),s._cdata&&p.push(("<![CDATA["+s._cdata).replace(/\]\]>/g,"]]]]><![CDATA[>")+"]]>"),s.forEach&&(u=!1,p.push(""),s.forEach((function(e){"object"==typeof e?"_attr"==Object.keys(e)[0]?f(e._attr):p.push(a(e,t,n+1)):(p.pop(),u=!0,p.push(r(e)))})),u||p.push(""));break;default:p.push(r(s))}return{name:i,interrupt:!1,attributes:l,content:p,icount:n,indents:c,indent:t}}function i(e,t,n){if("object"!=typeof t)return e(!1,t);var r=t.interrupt?1:t.content.length;function o(){for(;t.content.length;){var o=t.content.shift();if(void 0!==o){if(a(o))return;i(e,o)}}e(!1,(r>1?t.indents:"")+(t.name?"</"+t.name+">":"")+(t.indent&&!n?"\n":"")),n&&n()}function a(t){return!!t.interrupt&&(t.interrupt.append=e,t.interrupt.end=o,t.interrupt=!1,e(!0),!0)}if(e(!1,t.indents+(t.name?"<"+t.name:"")+(t.attributes.length?" "+t.attributes.join(" "):"")+(r?t.name?">":"":t.name?"/>":"")+(t.indent&&r>1?"\n":"")),!r)return e(!1,t.indent?"\n":"");a(t)||o()}e.exports=function(e,n){"object"!=typeof n&&(n={indent:n});var r,c,s=n.stream?new o:null,u="",l=!1,p=n.indent?!0===n.indent?"    ":n.indent:"",f=!0;function d(e){f?t.nextTick(e):e()}function h(e,t){if(void 0!==t&&(u+=t),e&&!l&&(s=s||new o,l=!0),e&&l){var n=u;d((function(){s.emit("data",n)})),u=""}}function m(e,t){i(h,a(e,p,p?1:0),t)}function v(){if(s){var e=u;d((function(){s.emit("data",e),s.emit("end"),s.readable=!1,s.emit("close")}))}}return d((function(){f=!1})),n.declaration&&(r=n.declaration,c={version:"1.0",encoding:r.encoding||"UTF-8"},r.standalone&&(c.standalone=r.standalone),m({"?xml":{_attr:c}}),u=u.replace("/>","?>")),e&&e.forEach?e.forEach((function(t,n){var r;n+1===e.length&&(r=v),m(t,r)})):m(e,v),s?(s.readable=!0,s):u},e.exports.element=e.exports.Element=function(){var e=Array.prototype.slice.call(arguments),t={_elem:a(e),push:function(e){if(!this.append)throw new Error("not assigned to a parent!");var t=this,n=this._elem.indent;i(this.append,a(e,n,this._elem.icount+(n?1:0)),(function(){t.append(!0)}))},close:function(e){void 0!==e&&this.push(e),this.end&&this.end()}};return t}}).call(this,n(563))},function(e,t){e.exports=require("css.escape")},function(e,t){e.exports=require("randombytes")},function(e,t){e.exports=require("sha.js")},function(e,t,n){var r=n(307),o=n(324),a=n(134),i=n(566),c=n(33);e.exports=function(e,t,n){var s=c(e)?r:i,u=arguments.length<3;return s(e,a(t,4),n,u,o)}},function(e,t,n){var r=n(71),o=n(33),a=n(54);e.exports=function(e){return"string"==typeof e||!o(e)&&a(e)&&"[object String]"==r(e)}},function(e,t,n){var r=n(40),o=n(589),a=n(323),i=Math.max,c=Math.min;e.exports=function(e,t,n){var s,u,l,p,f,d,h=0,m=!1,v=!1,g=!0;if("function"!=typeof e)throw new TypeError("Expected a function");function y(t){var n=s,r=u;return s=u=void 0,h=t,p=e.apply(r,n)}function b(e){return h=e,f=setTimeout(x,t),m?y(e):p}function E(e){var n=e-d;return void 0===d||n>=t||n<0||v&&e-h>=l}function x(){var e=o();if(E(e))return S(e);f=setTimeout(x,function(e){var n=t-(e-d);return v?c(n,l-(e-h)):n}(e))}function S(e){return f=void 0,g&&s?y(e):(s=u=void 0,p)}function w(){var e=o(),n=E(e);if(s=arguments,u=this,d=e,n){if(void 0===f)return b(d);if(v)return clearTimeout(f),f=setTimeout(x,t),y(d)}return void 0===f&&(f=setTimeout(x,t)),p}return t=a(t)||0,r(n)&&(m=!!n.leading,l=(v="maxWait"in n)?i(a(n.maxWait)||0,t):l,g="trailing"in n?!!n.trailing:g),w.cancel=function(){void 0!==f&&clearTimeout(f),h=0,s=d=u=f=void 0},w.flush=function(){return void 0===f?p:S(o())},w}},function(e,t){e.exports=require("react-dom")},function(e,t,n){var r=n(304),o=n(340),a=n(625),i=n(86),c=n(89),s=n(628),u=n(344),l=n(343),p=u((function(e,t){var n={};if(null==e)return n;var u=!1;t=r(t,(function(t){return t=i(t,e),u||(u=t.length>1),t})),c(e,l(e),n),u&&(n=o(n,7,s));for(var p=t.length;p--;)a(n,t[p]);return n}));e.exports=p},function(e,t,n){e.exports=n(636)},function(e,t,n){var r=n(327),o=n(642),a=n(645);function i(t,n,c){return"undefined"!=typeof Reflect&&o?e.exports=i=o:e.exports=i=function(e,t,n){var o=a(e,t);if(o){var i=r(o,t);return i.get?i.get.call(n):i.value}},i(t,n,c||t)}e.exports=i},function(e,t){e.exports=require("isomorphic-form-data")},function(e,t,n){var r=n(340);e.exports=function(e){return r(e,5)}},function(e,t,n){e.exports=n(646)},function(e,t){e.exports=require("cross-fetch")},function(e,t){e.exports=require("traverse")},function(e,t){e.exports=require("cookie")},function(e,t){e.exports=require("zenscroll")},function(e,t,n){e.exports=n(656)},function(e,t){e.exports=function(e){var t={literal:"true false null"},n=[e.QUOTE_STRING_MODE,e.C_NUMBER_MODE],r={end:",",endsWithParent:!0,excludeEnd:!0,contains:n,keywords:t},o={begin:"{",end:"}",contains:[{className:"attr",begin:/"/,end:/"/,contains:[e.BACKSLASH_ESCAPE],illegal:"\\n"},e.inherit(r,{begin:/:/})],illegal:"\\S"},a={begin:"\\[",end:"\\]",contains:[e.inherit(r)],illegal:"\\S"};return n.splice(n.length,0,o,a),{contains:n,keywords:t,illegal:"\\S"}}},function(e,t){e.exports=function(e){var t="[A-Za-z$_][0-9A-Za-z$_]*",n={keyword:"in of if for while finally var new function do return void else break catch instanceof with throw case default try this switch continue typeof delete let yield const export super debugger as async await static import from as",literal:"true false null undefined NaN Infinity",built_in:"eval isFinite isNaN parseFloat parseInt decodeURI decodeURIComponent encodeURI encodeURIComponent escape unescape Object Function Boolean Error EvalError InternalError RangeError ReferenceError StopIteration SyntaxError TypeError URIError Number Math Date String RegExp Array Float32Array Float64Array Int16Array Int32Array Int8Array Uint16Array Uint32Array Uint8Array Uint8ClampedArray ArrayBuffer DataView JSON Intl arguments require module console window document Symbol Set Map WeakSet WeakMap Proxy Reflect Promise"},r={className:"number",variants:[{begin:"\\b(0[bB][01]+)"},{begin:"\\b(0[oO][0-7]+)"},{begin:e.C_NUMBER_RE}],relevance:0},o={className:"subst",begin:"\\$\\{",end:"\\}",keywords:n,contains:[]},a={begin:"html`",end:"",starts:{end:"`",returnEnd:!1,contains:[e.BACKSLASH_ESCAPE,o],subLanguage:"xml"}},i={begin:"css`",end:"",starts:{end:"`",returnEnd:!1,contains:[e.BACKSLASH_ESCAPE,o],subLanguage:"css"}},c={className:"string",begin:"`",end:"`",contains:[e.BACKSLASH_ESCAPE,o]};o.contains=[e.APOS_STRING_MODE,e.QUOTE_STRING_MODE,a,i,c,r,e.REGEXP_MODE];var s=o.contains.concat([e.C_BLOCK_COMMENT_MODE,e.C_LINE_COMMENT_MODE]);return{aliases:["js","jsx"],keywords:n,contains:[{className:"meta",relevance:10,begin:/^\s*['"]use (strict|asm)['"]/},{className:"meta",begin:/^#!/,end:/$/},e.APOS_STRING_MODE,e.QUOTE_STRING_MODE,a,i,c,e.C_LINE_COMMENT_MODE,e.C_BLOCK_COMMENT_MODE,r,{begin:/[{,]\s*/,relevance:0,contains:[{begin:t+"\\s*:",returnBegin:!0,relevance:0,contains:[{className:"attr",begin:t,relevance:0}]}]},{begin:"("+e.RE_STARTERS_RE+"|\\b(case|return|throw)\\b)\\s*",keywords:"return throw case",contains:[e.C_LINE_COMMENT_MODE,e.C_BLOCK_COMMENT_MODE,e.REGEXP_MODE,{className:"function",begin:"(\\(.*?\\)|"+t+")\\s*=>",returnBegin:!0,end:"\\s*=>",contains:[{className:"params",variants:[{begin:t},{begin:/\(\s*\)/},{begin:/\(/,end:/\)/,excludeBegin:!0,excludeEnd:!0,keywords:n,contains:s}]}]},{className:"",begin:/\s/,end:/\s*/,skip:!0},{begin:/</,end:/(\/[A-Za-z0-9\\._:-]+|[A-Za-z0-9\\._:-]+\/)>/,subLanguage:"xml",contains:[{begin:/<[A-Za-z0-9\\._:-]+\s*\/>/,skip:!0},{begin:/<[A-Za-z0-9\\._:-]+/,end:/(\/[A-Za-z0-9\\._:-]+|[A-Za-z0-9\\._:-]+\/)>/,skip:!0,contains:[{begin:/<[A-Za-z0-9\\._:-]+\s*\/>/,skip:!0},"self"]}]}],relevance:0},{className:"function",beginKeywords:"function",end:/\{/,excludeEnd:!0,contains:[e.inherit(e.TITLE_MODE,{begin:t}),{className:"params",begin:/\(/,end:/\)/,excludeBegin:!0,excludeEnd:!0,contains:s}],illegal:/\[|%/},{begin:/\$[(.]/},e.METHOD_GUARD,{className:"class",beginKeywords:"class",end:/[{;=]/,excludeEnd:!0,illegal:/[:"\[\]]/,contains:[{beginKeywords:"extends"},e.UNDERSCORE_TITLE_MODE]},{beginKeywords:"constructor get set",end:/\{/,excludeEnd:!0}],illegal:/#(?!!)/}}},function(e,t){e.exports=function(e){var t={endsWithParent:!0,illegal:/</,relevance:0,contains:[{className:"attr",begin:"[A-Za-z0-9\\._:-]+",relevance:0},{begin:/=\s*/,relevance:0,contains:[{className:"string",endsParent:!0,variants:[{begin:/"/,end:/"/},{begin:/'/,end:/'/},{begin:/[^\s"'=<>`]+/}]}]}]};return{aliases:["html","xhtml","rss","atom","xjb","xsd","xsl","plist","wsf"],case_insensitive:!0,contains:[{className:"meta",begin:"<!DOCTYPE",end:">",relevance:10,contains:[{begin:"\\[",end:"\\]"}]},e.COMMENT("\x3c!--","--\x3e",{relevance:10}),{begin:"<\\!\\[CDATA\\[",end:"\\]\\]>",relevance:10},{className:"meta",begin:/<\?xml/,end:/\?>/,relevance:10},{begin:/<\?(php)?/,end:/\?>/,subLanguage:"php",contains:[{begin:"/\\*",end:"\\*/",skip:!0},{begin:'b"',end:'"',skip:!0},{begin:"b'",end:"'",skip:!0},e.inherit(e.APOS_STRING_MODE,{illegal:null,className:null,contains:null,skip:!0}),e.inherit(e.QUOTE_STRING_MODE,{illegal:null,className:null,contains:null,skip:!0})]},{className:"tag",begin:"<style(?=\\s|>|$)",end:">",keywords:{name:"style"},contains:[t],starts:{end:"</style>",returnEnd:!0,subLanguage:["css","xml"]}},{className:"tag",begin:"<script(?=\\s|>|$)",end:">",keywords:{name:"script"},contains:[t],starts:{end:"<\/script>",returnEnd:!0,subLanguage:["actionscript","javascript","handlebars","xml","vbscript"]}},{className:"tag",begin:"</?",end:"/?>",contains:[{className:"name",begin:/[^\/><\s]+/,relevance:0},t]}]}}},function(e,t){e.exports=function(e){var t="[a-zA-Z_][\\w\\-]*",n={className:"attr",variants:[{begin:"^[ \\-]*"+t+":"},{begin:'^[ \\-]*"'+t+'":'},{begin:"^[ \\-]*'"+t+"':"}]},r={className:"string",relevance:0,variants:[{begin:/'/,end:/'/},{begin:/"/,end:/"/},{begin:/\S+/}],contains:[e.BACKSLASH_ESCAPE,{className:"template-variable",variants:[{begin:"{{",end:"}}"},{begin:"%{",end:"}"}]}]};return{case_insensitive:!0,aliases:["yml","YAML","yaml"],contains:[n,{className:"meta",begin:"^---s*$",relevance:10},{className:"string",begin:"[\\|>] *$",returnEnd:!0,contains:r.contains,end:n.variants[0].begin},{begin:"<%[%=-]?",end:"[%-]?%>",subLanguage:"ruby",excludeBegin:!0,excludeEnd:!0,relevance:0},{className:"type",begin:"!"+e.UNDERSCORE_IDENT_RE},{className:"type",begin:"!!"+e.UNDERSCORE_IDENT_RE},{className:"meta",begin:"&"+e.UNDERSCORE_IDENT_RE+"$"},{className:"meta",begin:"\\*"+e.UNDERSCORE_IDENT_RE+"$"},{className:"bullet",begin:"^ *-",relevance:0},e.HASH_COMMENT_MODE,{beginKeywords:"true false yes no null",keywords:{literal:"true false yes no null"}},e.C_NUMBER_MODE,r]}}},function(e,t){e.exports=function(e){return{aliases:["https"],illegal:"\\S",contains:[{begin:"^HTTP/[0-9\\.]+",end:"$",contains:[{className:"number",begin:"\\b\\d{3}\\b"}]},{begin:"^[A-Z]+ (.*?) HTTP/[0-9\\.]+$",returnBegin:!0,end:"$",contains:[{className:"string",begin:" ",end:" ",excludeBegin:!0,excludeEnd:!0},{begin:"HTTP/[0-9\\.]+"},{className:"keyword",begin:"[A-Z]+"}]},{className:"attribute",begin:"^\\w",end:": ",excludeEnd:!0,illegal:"\\n|\\s|=",starts:{end:"$",relevance:0}},{begin:"\\n\\n",starts:{subLanguage:[],endsWithParent:!0}}]}}},function(e,t){e.exports=function(e){var t={className:"variable",variants:[{begin:/\$[\w\d#@][\w\d_]*/},{begin:/\$\{(.*?)}/}]},n={className:"string",begin:/"/,end:/"/,contains:[e.BACKSLASH_ESCAPE,t,{className:"variable",begin:/\$\(/,end:/\)/,contains:[e.BACKSLASH_ESCAPE]}]};return{aliases:["sh","zsh"],lexemes:/\b-?[a-z\._]+\b/,keywords:{keyword:"if then else elif fi for while in do done case esac function",literal:"true false",built_in:"break cd continue eval exec exit export getopts hash pwd readonly return shift test times trap umask unset alias bind builtin caller command declare echo enable help let local logout mapfile printf read readarray source type typeset ulimit unalias set shopt autoload bg bindkey bye cap chdir clone comparguments compcall compctl compdescribe compfiles compgroups compquote comptags comptry compvalues dirs disable disown echotc echoti emulate fc fg float functions getcap getln history integer jobs kill limit log noglob popd print pushd pushln rehash sched setcap setopt stat suspend ttyctl unfunction unhash unlimit unsetopt vared wait whence where which zcompile zformat zftp zle zmodload zparseopts zprof zpty zregexparse zsocket zstyle ztcp",_:"-ne -eq -lt -gt -f -d -e -s -l -a"},contains:[{className:"meta",begin:/^#![^\n]+sh\s*$/,relevance:10},{className:"function",begin:/\w[\w\d_]*\s*\(\s*\)\s*\{/,returnBegin:!0,contains:[e.inherit(e.TITLE_MODE,{begin:/\w[\w\d_]*/})],relevance:0},e.HASH_COMMENT_MODE,n,{className:"",begin:/\\"/},{className:"string",begin:/'/,end:/'/},t]}}},function(e,t){e.exports=require("js-file-download")},function(e,t){e.exports=require("xml-but-prettier")},function(e,t,n){var r=n(55);e.exports=function(e){return r(e).toLowerCase()}},function(e,t){e.exports=require("react-immutable-pure-component")},function(e,t){e.exports=require("autolinker")},function(e,t,n){n(386),e.exports=n(665)},function(e,t,n){"use strict";n.r(t);var r=n(17);void 0===n.n(r).a.Promise&&n(387),String.prototype.startsWith||n(417)},function(e,t,n){n(388),n(273),n(399),n(403),n(415),n(416),e.exports=n(59).Promise},function(e,t,n){"use strict";var r=n(114),o={};o[n(29)("toStringTag")]="z",o+""!="[object z]"&&n(77)(Object.prototype,"toString",(function(){return"[object "+r(this)+"]"}),!0)},function(e,t,n){e.exports=!n(98)&&!n(79)((function(){return 7!=Object.defineProperty(n(160)("div"),"a",{get:function(){return 7}}).a}))},function(e,t,n){var r=n(78);e.exports=function(e,t){if(!r(e))return e;var n,o;if(t&&"function"==typeof(n=e.toString)&&!r(o=n.call(e)))return o;if("function"==typeof(n=e.valueOf)&&!r(o=n.call(e)))return o;if(!t&&"function"==typeof(n=e.toString)&&!r(o=n.call(e)))return o;throw TypeError("Can't convert object to primitive value")}},function(e,t,n){e.exports=n(157)("native-function-to-string",Function.toString)},function(e,t,n){"use strict";var r=n(393),o=n(272),a=n(163),i={};n(68)(i,n(29)("iterator"),(function(){return this})),e.exports=function(e,t,n){e.prototype=r(i,{next:o(1,n)}),a(e,t+" Iterator")}},function(e,t,n){var r=n(44),o=n(394),a=n(277),i=n(162)("IE_PROTO"),c=function(){},s=function(){var e,t=n(160)("iframe"),r=a.length;for(t.style.display="none",n(278).appendChild(t),t.src="javascript:",(e=t.contentWindow.document).open(),e.write("<script>document.F=Object<\/script>"),e.close(),s=e.F;r--;)delete s.prototype[a[r]];return s()};e.exports=Object.create||function(e,t){var n;return null!==e?(c.prototype=r(e),n=new c,c.prototype=null,n[i]=e):n=s(),void 0===t?n:o(n,t)}},function(e,t,n){var r=n(115),o=n(44),a=n(275);e.exports=n(98)?Object.defineProperties:function(e,t){o(e);for(var n,i=a(t),c=i.length,s=0;c>s;)r.f(e,n=i[s++],t[n]);return e}},function(e,t,n){var r=n(116),o=n(119),a=n(397)(!1),i=n(162)("IE_PROTO");e.exports=function(e,t){var n,c=o(e),s=0,u=[];for(n in c)n!=i&&r(c,n)&&u.push(n);for(;t.length>s;)r(c,n=t[s++])&&(~a(u,n)||u.push(n));return u}},function(e,t,n){var r=n(97);e.exports=Object("z").propertyIsEnumerable(0)?Object:function(e){return"String"==r(e)?e.split(""):Object(e)}},function(e,t,n){var r=n(119),o=n(61),a=n(276);e.exports=function(e){return function(t,n,i){var c,s=r(t),u=o(s.length),l=a(i,u);if(e&&n!=n){for(;u>l;)if((c=s[l++])!=c)return!0}else for(;u>l;l++)if((e||l in s)&&s[l]===n)return e||l||0;return!e&&-1}}},function(e,t,n){var r=n(116),o=n(279),a=n(162)("IE_PROTO"),i=Object.prototype;e.exports=Object.getPrototypeOf||function(e){return e=o(e),r(e,a)?e[a]:"function"==typeof e.constructor&&e instanceof e.constructor?e.constructor.prototype:e instanceof Object?i:null}},function(e,t,n){for(var r=n(400),o=n(275),a=n(77),i=n(37),c=n(68),s=n(100),u=n(29),l=u("iterator"),p=u("toStringTag"),f=s.Array,d={CSSRuleList:!0,CSSStyleDeclaration:!1,CSSValueList:!1,ClientRectList:!1,DOMRectList:!1,DOMStringList:!1,DOMTokenList:!0,DataTransferItemList:!1,FileList:!1,HTMLAllCollection:!1,HTMLCollection:!1,HTMLFormElement:!1,HTMLSelectElement:!1,MediaList:!0,MimeTypeArray:!1,NamedNodeMap:!1,NodeList:!0,PaintRequestList:!1,Plugin:!1,PluginArray:!1,SVGLengthList:!1,SVGNumberList:!1,SVGPathSegList:!1,SVGPointList:!1,SVGStringList:!1,SVGTransformList:!1,SourceBufferList:!1,StyleSheetList:!0,TextTrackCueList:!1,TextTrackList:!1,TouchList:!1},h=o(d),m=0;m<h.length;m++){var v,g=h[m],y=d[g],b=i[g],E=b&&b.prototype;if(E&&(E[l]||c(E,l,f),E[p]||c(E,p,g),s[g]=f,y))for(v in r)E[v]||a(E,v,r[v],!0)}},function(e,t,n){"use strict";var r=n(401),o=n(402),a=n(100),i=n(119);e.exports=n(274)(Array,"Array",(function(e,t){this._t=i(e),this._i=0,this._k=t}),(function(){var e=this._t,t=this._k,n=this._i++;return!e||n>=e.length?(this._t=void 0,o(1)):o(0,"keys"==t?n:"values"==t?e[n]:[n,e[n]])}),"values"),a.Arguments=a.Array,r("keys"),r("values"),r("entries")},function(e,t,n){var r=n(29)("unscopables"),o=Array.prototype;null==o[r]&&n(68)(o,r,{}),e.exports=function(e){o[r][e]=!0}},function(e,t){e.exports=function(e,t){return{value:t,done:!!e}}},function(e,t,n){"use strict";var r,o,a,i,c=n(158),s=n(37),u=n(117),l=n(114),p=n(35),f=n(78),d=n(118),h=n(404),m=n(405),v=n(164),g=n(280).set,y=n(410)(),b=n(165),E=n(281),x=n(411),S=n(282),w=s.TypeError,j=s.process,_=j&&j.versions,O=_&&_.v8||"",C=s.Promise,A="process"==l(j),k=function(){},P=o=b.f,I=!!function(){try{var e=C.resolve(1),t=(e.constructor={})[n(29)("species")]=function(e){e(k,k)};return(A||"function"==typeof PromiseRejectionEvent)&&e.then(k)instanceof t&&0!==O.indexOf("6.6")&&-1===x.indexOf("Chrome/66")}catch(e){}}(),T=function(e){var t;return!(!f(e)||"function"!=typeof(t=e.then))&&t},N=function(e,t){if(!e._n){e._n=!0;var n=e._c;y((function(){for(var r=e._v,o=1==e._s,a=0,i=function(t){var n,a,i,c=o?t.ok:t.fail,s=t.resolve,u=t.reject,l=t.domain;try{c?(o||(2==e._h&&D(e),e._h=1),!0===c?n=r:(l&&l.enter(),n=c(r),l&&(l.exit(),i=!0)),n===t.promise?u(w("Promise-chain cycle")):(a=T(n))?a.call(n,s,u):s(n)):u(r)}catch(e){l&&!i&&l.exit(),u(e)}};n.length>a;)i(n[a++]);e._c=[],e._n=!1,t&&!e._h&&R(e)}))}},R=function(e){g.call(s,(function(){var t,n,r,o=e._v,a=M(e);if(a&&(t=E((function(){A?j.emit("unhandledRejection",o,e):(n=s.onunhandledrejection)?n({promise:e,reason:o}):(r=s.console)&&r.error&&r.error("Unhandled promise rejection",o)})),e._h=A||M(e)?2:1),e._a=void 0,a&&t.e)throw t.v}))},M=function(e){return 1!==e._h&&0===(e._a||e._c).length},D=function(e){g.call(s,(function(){var t;A?j.emit("rejectionHandled",e):(t=s.onrejectionhandled)&&t({promise:e,reason:e._v})}))},q=function(e){var t=this;t._d||(t._d=!0,(t=t._w||t)._v=e,t._s=2,t._a||(t._a=t._c.slice()),N(t,!0))},L=function(e){var t,n=this;if(!n._d){n._d=!0,n=n._w||n;try{if(n===e)throw w("Promise can't be resolved itself");(t=T(e))?y((function(){var r={_w:n,_d:!1};try{t.call(e,u(L,r,1),u(q,r,1))}catch(e){q.call(r,e)}})):(n._v=e,n._s=1,N(n,!1))}catch(e){q.call({_w:n,_d:!1},e)}}};I||(C=function(e){h(this,C,"Promise","_h"),d(e),r.call(this);try{e(u(L,this,1),u(q,this,1))}catch(e){q.call(this,e)}},(r=function(e){this._c=[],this._a=void 0,this._s=0,this._d=!1,this._v=void 0,this._h=0,this._n=!1}).prototype=n(412)(C.prototype,{then:function(e,t){var n=P(v(this,C));return n.ok="function"!=typeof e||e,n.fail="function"==typeof t&&t,n.domain=A?j.domain:void 0,this._c.push(n),this._a&&this._a.push(n),this._s&&N(this,!1),n.promise},catch:function(e){return this.then(void 0,e)}}),a=function(){var e=new r;this.promise=e,this.resolve=u(L,e,1),this.reject=u(q,e,1)},b.f=P=function(e){return e===C||e===i?new a(e):o(e)}),p(p.G+p.W+p.F*!I,{Promise:C}),n(163)(C,"Promise"),n(413)("Promise"),i=n(59).Promise,p(p.S+p.F*!I,"Promise",{reject:function(e){var t=P(this);return(0,t.reject)(e),t.promise}}),p(p.S+p.F*(c||!I),"Promise",{resolve:function(e){return S(c&&this===i?C:this,e)}}),p(p.S+p.F*!(I&&n(414)((function(e){C.all(e).catch(k)}))),"Promise",{all:function(e){var t=this,n=P(t),r=n.resolve,o=n.reject,a=E((function(){var n=[],a=0,i=1;m(e,!1,(function(e){var c=a++,s=!1;n.push(void 0),i++,t.resolve(e).then((function(e){s||(s=!0,n[c]=e,--i||r(n))}),o)})),--i||r(n)}));return a.e&&o(a.v),n.promise},race:function(e){var t=this,n=P(t),r=n.reject,o=E((function(){m(e,!1,(function(e){t.resolve(e).then(n.resolve,r)}))}));return o.e&&r(o.v),n.promise}})},function(e,t){e.exports=function(e,t,n,r){if(!(e instanceof t)||void 0!==r&&r in e)throw TypeError(n+": incorrect invocation!");return e}},function(e,t,n){var r=n(117),o=n(406),a=n(407),i=n(44),c=n(61),s=n(408),u={},l={};(t=e.exports=function(e,t,n,p,f){var d,h,m,v,g=f?function(){return e}:s(e),y=r(n,p,t?2:1),b=0;if("function"!=typeof g)throw TypeError(e+" is not iterable!");if(a(g)){for(d=c(e.length);d>b;b++)if((v=t?y(i(h=e[b])[0],h[1]):y(e[b]))===u||v===l)return v}else for(m=g.call(e);!(h=m.next()).done;)if((v=o(m,y,h.value,t))===u||v===l)return v}).BREAK=u,t.RETURN=l},function(e,t,n){var r=n(44);e.exports=function(e,t,n,o){try{return o?t(r(n)[0],n[1]):t(n)}catch(t){var a=e.return;throw void 0!==a&&r(a.call(e)),t}}},function(e,t,n){var r=n(100),o=n(29)("iterator"),a=Array.prototype;e.exports=function(e){return void 0!==e&&(r.Array===e||a[o]===e)}},function(e,t,n){var r=n(114),o=n(29)("iterator"),a=n(100);e.exports=n(59).getIteratorMethod=function(e){if(null!=e)return e[o]||e["@@iterator"]||a[r(e)]}},function(e,t){e.exports=function(e,t,n){var r=void 0===n;switch(t.length){case 0:return r?e():e.call(n);case 1:return r?e(t[0]):e.call(n,t[0]);case 2:return r?e(t[0],t[1]):e.call(n,t[0],t[1]);case 3:return r?e(t[0],t[1],t[2]):e.call(n,t[0],t[1],t[2]);case 4:return r?e(t[0],t[1],t[2],t[3]):e.call(n,t[0],t[1],t[2],t[3])}return e.apply(n,t)}},function(e,t,n){var r=n(37),o=n(280).set,a=r.MutationObserver||r.WebKitMutationObserver,i=r.process,c=r.Promise,s="process"==n(97)(i);e.exports=function(){var e,t,n,u=function(){var r,o;for(s&&(r=i.domain)&&r.exit();e;){o=e.fn,e=e.next;try{o()}catch(r){throw e?n():t=void 0,r}}t=void 0,r&&r.enter()};if(s)n=function(){i.nextTick(u)};else if(!a||r.navigator&&r.navigator.standalone)if(c&&c.resolve){var l=c.resolve(void 0);n=function(){l.then(u)}}else n=function(){o.call(r,u)};else{var p=!0,f=document.createTextNode("");new a(u).observe(f,{characterData:!0}),n=function(){f.data=p=!p}}return function(r){var o={fn:r,next:void 0};t&&(t.next=o),e||(e=o,n()),t=o}}},function(e,t,n){var r=n(37).navigator;e.exports=r&&r.userAgent||""},function(e,t,n){var r=n(77);e.exports=function(e,t,n){for(var o in t)r(e,o,t[o],n);return e}},function(e,t,n){"use strict";var r=n(37),o=n(115),a=n(98),i=n(29)("species");e.exports=function(e){var t=r[e];a&&t&&!t[i]&&o.f(t,i,{configurable:!0,get:function(){return this}})}},function(e,t,n){var r=n(29)("iterator"),o=!1;try{var a=[7][r]();a.return=function(){o=!0},Array.from(a,(function(){throw 2}))}catch(e){}e.exports=function(e,t){if(!t&&!o)return!1;var n=!1;try{var a=[7],i=a[r]();i.next=function(){return{done:n=!0}},a[r]=function(){return i},e(a)}catch(e){}return n}},function(e,t,n){"use strict";var r=n(35),o=n(59),a=n(37),i=n(164),c=n(282);r(r.P+r.R,"Promise",{finally:function(e){var t=i(this,o.Promise||a.Promise),n="function"==typeof e;return this.then(n?function(n){return c(t,e()).then((function(){return n}))}:e,n?function(n){return c(t,e()).then((function(){throw n}))}:e)}})},function(e,t,n){"use strict";var r=n(35),o=n(165),a=n(281);r(r.S,"Promise",{try:function(e){var t=o.f(this),n=a(e);return(n.e?t.reject:t.resolve)(n.v),t.promise}})},function(e,t,n){n(418),n(419),n(420),n(273),n(423),n(424),n(425),n(426),n(428),n(429),n(430),n(431),n(432),n(433),n(434),n(435),n(436),n(437),n(438),n(439),n(440),n(441),n(442),n(445),n(446),n(448),e.exports=n(59).String},function(e,t,n){var r=n(35),o=n(276),a=String.fromCharCode,i=String.fromCodePoint;r(r.S+r.F*(!!i&&1!=i.length),"String",{fromCodePoint:function(e){for(var t,n=[],r=arguments.length,i=0;r>i;){if(t=+arguments[i++],o(t,1114111)!==t)throw RangeError(t+" is not a valid code point");n.push(t<65536?a(t):a(55296+((t-=65536)>>10),t%1024+56320))}return n.join("")}})},function(e,t,n){var r=n(35),o=n(119),a=n(61);r(r.S,"String",{raw:function(e){for(var t=o(e.raw),n=a(t.length),r=arguments.length,i=[],c=0;n>c;)i.push(String(t[c++])),c<r&&i.push(String(arguments[c]));return i.join("")}})},function(e,t,n){"use strict";n(421)("trim",(function(e){return function(){return e(this,3)}}))},function(e,t,n){var r=n(35),o=n(60),a=n(79),i=n(422),c="["+i+"]",s=RegExp("^"+c+c+"*"),u=RegExp(c+c+"*$"),l=function(e,t,n){var o={},c=a((function(){return!!i[e]()||"​"!="​"[e]()})),s=o[e]=c?t(p):i[e];n&&(o[n]=s),r(r.P+r.F*c,"String",o)},p=l.trim=function(e,t){return e=String(o(e)),1&t&&(e=e.replace(s,"")),2&t&&(e=e.replace(u,"")),e};e.exports=l},function(e,t){e.exports="\t\n\v\f\r   ᠎             　\u2028\u2029\ufeff"},function(e,t,n){"use strict";var r=n(35),o=n(161)(!1);r(r.P,"String",{codePointAt:function(e){return o(this,e)}})},function(e,t,n){"use strict";var r=n(35),o=n(61),a=n(166),i="".endsWith;r(r.P+r.F*n(167)("endsWith"),"String",{endsWith:function(e){var t=a(this,e,"endsWith"),n=arguments.length>1?arguments[1]:void 0,r=o(t.length),c=void 0===n?r:Math.min(o(n),r),s=String(e);return i?i.call(t,s,c):t.slice(c-s.length,c)===s}})},function(e,t,n){"use strict";var r=n(35),o=n(166);r(r.P+r.F*n(167)("includes"),"String",{includes:function(e){return!!~o(this,e,"includes").indexOf(e,arguments.length>1?arguments[1]:void 0)}})},function(e,t,n){var r=n(35);r(r.P,"String",{repeat:n(427)})},function(e,t,n){"use strict";var r=n(99),o=n(60);e.exports=function(e){var t=String(o(this)),n="",a=r(e);if(a<0||a==1/0)throw RangeError("Count can't be negative");for(;a>0;(a>>>=1)&&(t+=t))1&a&&(n+=t);return n}},function(e,t,n){"use strict";var r=n(35),o=n(61),a=n(166),i="".startsWith;r(r.P+r.F*n(167)("startsWith"),"String",{startsWith:function(e){var t=a(this,e,"startsWith"),n=o(Math.min(arguments.length>1?arguments[1]:void 0,t.length)),r=String(e);return i?i.call(t,r,n):t.slice(n,n+r.length)===r}})},function(e,t,n){"use strict";n(38)("anchor",(function(e){return function(t){return e(this,"a","name",t)}}))},function(e,t,n){"use strict";n(38)("big",(function(e){return function(){return e(this,"big","","")}}))},function(e,t,n){"use strict";n(38)("blink",(function(e){return function(){return e(this,"blink","","")}}))},function(e,t,n){"use strict";n(38)("bold",(function(e){return function(){return e(this,"b","","")}}))},function(e,t,n){"use strict";n(38)("fixed",(function(e){return function(){return e(this,"tt","","")}}))},function(e,t,n){"use strict";n(38)("fontcolor",(function(e){return function(t){return e(this,"font","color",t)}}))},function(e,t,n){"use strict";n(38)("fontsize",(function(e){return function(t){return e(this,"font","size",t)}}))},function(e,t,n){"use strict";n(38)("italics",(function(e){return function(){return e(this,"i","","")}}))},function(e,t,n){"use strict";n(38)("link",(function(e){return function(t){return e(this,"a","href",t)}}))},function(e,t,n){"use strict";n(38)("small",(function(e){return function(){return e(this,"small","","")}}))},function(e,t,n){"use strict";n(38)("strike",(function(e){return function(){return e(this,"strike","","")}}))},function(e,t,n){"use strict";n(38)("sub",(function(e){return function(){return e(this,"sub","","")}}))},function(e,t,n){"use strict";n(38)("sup",(function(e){return function(){return e(this,"sup","","")}}))},function(e,t,n){"use strict";var r=n(44),o=n(61),a=n(168),i=n(120);n(121)("match",1,(function(e,t,n,c){return[function(n){var r=e(this),o=null==n?void 0:n[t];return void 0!==o?o.call(n,r):new RegExp(n)[t](String(r))},function(e){var t=c(n,e,this);if(t.done)return t.value;var s=r(e),u=String(this);if(!s.global)return i(s,u);var l=s.unicode;s.lastIndex=0;for(var p,f=[],d=0;null!==(p=i(s,u));){var h=String(p[0]);f[d]=h,""===h&&(s.lastIndex=a(u,o(s.lastIndex),l)),d++}return 0===d?null:f}]}))},function(e,t,n){"use strict";var r=n(169);n(35)({target:"RegExp",proto:!0,forced:r!==/./.exec},{exec:r})},function(e,t,n){"use strict";var r=n(44);e.exports=function(){var e=r(this),t="";return e.global&&(t+="g"),e.ignoreCase&&(t+="i"),e.multiline&&(t+="m"),e.unicode&&(t+="u"),e.sticky&&(t+="y"),t}},function(e,t,n){"use strict";var r=n(44),o=n(279),a=n(61),i=n(99),c=n(168),s=n(120),u=Math.max,l=Math.min,p=Math.floor,f=/\$([$&`']|\d\d?|<[^>]*>)/g,d=/\$([$&`']|\d\d?)/g;n(121)("replace",2,(function(e,t,n,h){return[function(r,o){var a=e(this),i=null==r?void 0:r[t];return void 0!==i?i.call(r,a,o):n.call(String(a),r,o)},function(e,t){var o=h(n,e,this,t);if(o.done)return o.value;var p=r(e),f=String(this),d="function"==typeof t;d||(t=String(t));var v=p.global;if(v){var g=p.unicode;p.lastIndex=0}for(var y=[];;){var b=s(p,f);if(null===b)break;if(y.push(b),!v)break;""===String(b[0])&&(p.lastIndex=c(f,a(p.lastIndex),g))}for(var E,x="",S=0,w=0;w<y.length;w++){b=y[w];for(var j=String(b[0]),_=u(l(i(b.index),f.length),0),O=[],C=1;C<b.length;C++)O.push(void 0===(E=b[C])?E:String(E));var A=b.groups;if(d){var k=[j].concat(O,_,f);void 0!==A&&k.push(A);var P=String(t.apply(void 0,k))}else P=m(j,f,_,O,A,t);_>=S&&(x+=f.slice(S,_)+P,S=_+j.length)}return x+f.slice(S)}];function m(e,t,r,a,i,c){var s=r+e.length,u=a.length,l=d;return void 0!==i&&(i=o(i),l=f),n.call(c,l,(function(n,o){var c;switch(o.charAt(0)){case"$":return"$";case"&":return e;case"`":return t.slice(0,r);case"'":return t.slice(s);case"<":c=i[o.slice(1,-1)];break;default:var l=+o;if(0===l)return n;if(l>u){var f=p(l/10);return 0===f?n:f<=u?void 0===a[f-1]?o.charAt(1):a[f-1]+o.charAt(1):n}c=a[l-1]}return void 0===c?"":c}))}}))},function(e,t,n){"use strict";var r=n(44),o=n(447),a=n(120);n(121)("search",1,(function(e,t,n,i){return[function(n){var r=e(this),o=null==n?void 0:n[t];return void 0!==o?o.call(n,r):new RegExp(n)[t](String(r))},function(e){var t=i(n,e,this);if(t.done)return t.value;var c=r(e),s=String(this),u=c.lastIndex;o(u,0)||(c.lastIndex=0);var l=a(c,s);return o(c.lastIndex,u)||(c.lastIndex=u),null===l?-1:l.index}]}))},function(e,t){e.exports=Object.is||function(e,t){return e===t?0!==e||1/e==1/t:e!=e&&t!=t}},function(e,t,n){"use strict";var r=n(283),o=n(44),a=n(164),i=n(168),c=n(61),s=n(120),u=n(169),l=n(79),p=Math.min,f=[].push,d="length",h=!l((function(){RegExp(4294967295,"y")}));n(121)("split",2,(function(e,t,n,l){var m;return m="c"=="abbc".split(/(b)*/)[1]||4!="test".split(/(?:)/,-1)[d]||2!="ab".split(/(?:ab)*/)[d]||4!=".".split(/(.?)(.?)/)[d]||".".split(/()()/)[d]>1||"".split(/.?/)[d]?function(e,t){var o=String(this);if(void 0===e&&0===t)return[];if(!r(e))return n.call(o,e,t);for(var a,i,c,s=[],l=(e.ignoreCase?"i":"")+(e.multiline?"m":"")+(e.unicode?"u":"")+(e.sticky?"y":""),p=0,h=void 0===t?4294967295:t>>>0,m=new RegExp(e.source,l+"g");(a=u.call(m,o))&&!((i=m.lastIndex)>p&&(s.push(o.slice(p,a.index)),a[d]>1&&a.index<o[d]&&f.apply(s,a.slice(1)),c=a[0][d],p=i,s[d]>=h));)m.lastIndex===a.index&&m.lastIndex++;return p===o[d]?!c&&m.test("")||s.push(""):s.push(o.slice(p)),s[d]>h?s.slice(0,h):s}:"0".split(void 0,0)[d]?function(e,t){return void 0===e&&0===t?[]:n.call(this,e,t)}:n,[function(n,r){var o=e(this),a=null==n?void 0:n[t];return void 0!==a?a.call(n,o,r):m.call(String(o),n,r)},function(e,t){var r=l(m,e,this,t,m!==n);if(r.done)return r.value;var u=o(e),f=String(this),d=a(u,RegExp),v=u.unicode,g=(u.ignoreCase?"i":"")+(u.multiline?"m":"")+(u.unicode?"u":"")+(h?"y":"g"),y=new d(h?u:"^(?:"+u.source+")",g),b=void 0===t?4294967295:t>>>0;if(0===b)return[];if(0===f.length)return null===s(y,f)?[f]:[];for(var E=0,x=0,S=[];x<f.length;){y.lastIndex=h?x:0;var w,j=s(y,h?f:f.slice(x));if(null===j||(w=p(c(y.lastIndex+(h?0:x)),f.length))===E)x=i(f,x,v);else{if(S.push(f.slice(E,x)),S.length===b)return S;for(var _=1;_<=j.length-1;_++)if(S.push(j[_]),S.length===b)return S;x=E=w}}return S.push(f.slice(E)),S}]}))},function(e,t,n){var r=n(21),o=r.JSON||(r.JSON={stringify:JSON.stringify});e.exports=function(e){return o.stringify.apply(o,arguments)}},function(e,t,n){n(451),e.exports=n(21).Object.keys},function(e,t,n){var r=n(69),o=n(80);n(176)("keys",(function(){return function(e){return o(r(e))}}))},function(e,t,n){var r=n(52),o=n(122),a=n(453);e.exports=function(e){return function(t,n,i){var c,s=r(t),u=o(s.length),l=a(i,u);if(e&&n!=n){for(;u>l;)if((c=s[l++])!=c)return!0}else for(;u>l;l++)if((e||l in s)&&s[l]===n)return e||l||0;return!e&&-1}}},function(e,t,n){var r=n(172),o=Math.max,a=Math.min;e.exports=function(e,t){return(e=r(e))<0?o(e+t,0):a(e,t)}},function(e,t,n){n(81),n(83),e.exports=n(182).f("iterator")},function(e,t,n){var r=n(172),o=n(170);e.exports=function(e){return function(t,n){var a,i,c=String(o(t)),s=r(n),u=c.length;return s<0||s>=u?e?"":void 0:(a=c.charCodeAt(s))<55296||a>56319||s+1===u||(i=c.charCodeAt(s+1))<56320||i>57343?e?c.charAt(s):a:e?c.slice(s,s+2):i-56320+(a-55296<<10)+65536}}},function(e,t,n){"use strict";var r=n(104),o=n(103),a=n(105),i={};n(62)(i,n(32)("iterator"),(function(){return this})),e.exports=function(e,t,n){e.prototype=r(i,{next:o(1,n)}),a(e,t+" Iterator")}},function(e,t,n){"use strict";var r=n(458),o=n(289),a=n(82),i=n(52);e.exports=n(179)(Array,"Array",(function(e,t){this._t=i(e),this._i=0,this._k=t}),(function(){var e=this._t,t=this._k,n=this._i++;return!e||n>=e.length?(this._t=void 0,o(1)):o(0,"keys"==t?n:"values"==t?e[n]:[n,e[n]])}),"values"),a.Arguments=a.Array,r("keys"),r("values"),r("entries")},function(e,t){e.exports=function(){}},function(e,t,n){n(290),n(126),n(462),n(463),e.exports=n(21).Symbol},function(e,t,n){var r=n(80),o=n(125),a=n(107);e.exports=function(e){var t=r(e),n=o.f;if(n)for(var i,c=n(e),s=a.f,u=0;c.length>u;)s.call(e,i=c[u++])&&t.push(i);return t}},function(e,t,n){var r=n(52),o=n(185).f,a={}.toString,i="object"==typeof window&&window&&Object.getOwnPropertyNames?Object.getOwnPropertyNames(window):[];e.exports.f=function(e){return i&&"[object Window]"==a.call(e)?function(e){try{return o(e)}catch(e){return i.slice()}}(e):o(r(e))}},function(e,t,n){n(183)("asyncIterator")},function(e,t,n){n(183)("observable")},function(e,t,n){n(465),e.exports=n(21).Array.isArray},function(e,t,n){var r=n(24);r(r.S,"Array",{isArray:n(184)})},function(e,t,n){n(467);var r=n(21).Object;e.exports=function(e,t,n){return r.defineProperty(e,t,n)}},function(e,t,n){var r=n(24);r(r.S+r.F*!n(39),"Object",{defineProperty:n(48).f})},function(e,t,n){n(469),e.exports=n(21).Object.assign},function(e,t,n){var r=n(24);r(r.S+r.F,"Object",{assign:n(291)})},function(e,t,n){"use strict";(function(e){
-/*!
- * The buffer module from node.js, for the browser.
- *
- * @author   Feross Aboukhadijeh <http://feross.org>
- * @license  MIT
- */
-var r=n(471),o=n(472),a=n(473);function i(){return s.TYPED_ARRAY_SUPPORT?2147483647:1073741823}function c(e,t){if(i()<t)throw new RangeError("Invalid typed array length");return s.TYPED_ARRAY_SUPPORT?(e=new Uint8Array(t)).__proto__=s.prototype:(null===e&&(e=new s(t)),e.length=t),e}function s(e,t,n){if(!(s.TYPED_ARRAY_SUPPORT||this instanceof s))return new s(e,t,n);if("number"==typeof e){if("string"==typeof t)throw new Error("If encoding is specified then the first argument must be a string");return p(this,e)}return u(this,e,t,n)}function u(e,t,n,r){if("number"==typeof t)throw new TypeError('"value" argument must not be a number');return"undefined"!=typeof ArrayBuffer&&t instanceof ArrayBuffer?function(e,t,n,r){if(t.byteLength,n<0||t.byteLength<n)throw new RangeError("'offset' is out of bounds");if(t.byteLength<n+(r||0))throw new RangeError("'length' is out of bounds");t=void 0===n&&void 0===r?new Uint8Array(t):void 0===r?new Uint8Array(t,n):new Uint8Array(t,n,r);s.TYPED_ARRAY_SUPPORT?(e=t).__proto__=s.prototype:e=f(e,t);return e}(e,t,n,r):"string"==typeof t?function(e,t,n){"string"==typeof n&&""!==n||(n="utf8");if(!s.isEncoding(n))throw new TypeError('"encoding" must be a valid string encoding');var r=0|h(t,n),o=(e=c(e,r)).write(t,n);o!==r&&(e=e.slice(0,o));return e}(e,t,n):function(e,t){if(s.isBuffer(t)){var n=0|d(t.length);return 0===(e=c(e,n)).length||t.copy(e,0,0,n),e}if(t){if("undefined"!=typeof ArrayBuffer&&t.buffer instanceof ArrayBuffer||"length"in t)return"number"!=typeof t.length||(r=t.length)!=r?c(e,0):f(e,t);if("Buffer"===t.type&&a(t.data))return f(e,t.data)}var r;throw new TypeError("First argument must be a string, Buffer, ArrayBuffer, Array, or array-like object.")}(e,t)}function l(e){if("number"!=typeof e)throw new TypeError('"size" argument must be a number');if(e<0)throw new RangeError('"size" argument must not be negative')}function p(e,t){if(l(t),e=c(e,t<0?0:0|d(t)),!s.TYPED_ARRAY_SUPPORT)for(var n=0;n<t;++n)e[n]=0;return e}function f(e,t){var n=t.length<0?0:0|d(t.length);e=c(e,n);for(var r=0;r<n;r+=1)e[r]=255&t[r];return e}function d(e){if(e>=i())throw new RangeError("Attempt to allocate Buffer larger than maximum size: 0x"+i().toString(16)+" bytes");return 0|e}function h(e,t){if(s.isBuffer(e))return e.length;if("undefined"!=typeof ArrayBuffer&&"function"==typeof ArrayBuffer.isView&&(ArrayBuffer.isView(e)||e instanceof ArrayBuffer))return e.byteLength;"string"!=typeof e&&(e=""+e);var n=e.length;if(0===n)return 0;for(var r=!1;;)switch(t){case"ascii":case"latin1":case"binary":return n;case"utf8":case"utf-8":case void 0:return U(e).length;case"ucs2":case"ucs-2":case"utf16le":case"utf-16le":return 2*n;case"hex":return n>>>1;case"base64":return V(e).length;default:if(r)return U(e).length;t=(""+t).toLowerCase(),r=!0}}function m(e,t,n){var r=!1;if((void 0===t||t<0)&&(t=0),t>this.length)return"";if((void 0===n||n>this.length)&&(n=this.length),n<=0)return"";if((n>>>=0)<=(t>>>=0))return"";for(e||(e="utf8");;)switch(e){case"hex":return k(this,t,n);case"utf8":case"utf-8":return O(this,t,n);case"ascii":return C(this,t,n);case"latin1":case"binary":return A(this,t,n);case"base64":return _(this,t,n);case"ucs2":case"ucs-2":case"utf16le":case"utf-16le":return P(this,t,n);default:if(r)throw new TypeError("Unknown encoding: "+e);e=(e+"").toLowerCase(),r=!0}}function v(e,t,n){var r=e[t];e[t]=e[n],e[n]=r}function g(e,t,n,r,o){if(0===e.length)return-1;if("string"==typeof n?(r=n,n=0):n>2147483647?n=2147483647:n<-2147483648&&(n=-2147483648),n=+n,isNaN(n)&&(n=o?0:e.length-1),n<0&&(n=e.length+n),n>=e.length){if(o)return-1;n=e.length-1}else if(n<0){if(!o)return-1;n=0}if("string"==typeof t&&(t=s.from(t,r)),s.isBuffer(t))return 0===t.length?-1:y(e,t,n,r,o);if("number"==typeof t)return t&=255,s.TYPED_ARRAY_SUPPORT&&"function"==typeof Uint8Array.prototype.indexOf?o?Uint8Array.prototype.indexOf.call(e,t,n):Uint8Array.prototype.lastIndexOf.call(e,t,n):y(e,[t],n,r,o);throw new TypeError("val must be string, number or Buffer")}function y(e,t,n,r,o){var a,i=1,c=e.length,s=t.length;if(void 0!==r&&("ucs2"===(r=String(r).toLowerCase())||"ucs-2"===r||"utf16le"===r||"utf-16le"===r)){if(e.length<2||t.length<2)return-1;i=2,c/=2,s/=2,n/=2}function u(e,t){return 1===i?e[t]:e.readUInt16BE(t*i)}if(o){var l=-1;for(a=n;a<c;a++)if(u(e,a)===u(t,-1===l?0:a-l)){if(-1===l&&(l=a),a-l+1===s)return l*i}else-1!==l&&(a-=a-l),l=-1}else for(n+s>c&&(n=c-s),a=n;a>=0;a--){for(var p=!0,f=0;f<s;f++)if(u(e,a+f)!==u(t,f)){p=!1;break}if(p)return a}return-1}function b(e,t,n,r){n=Number(n)||0;var o=e.length-n;r?(r=Number(r))>o&&(r=o):r=o;var a=t.length;if(a%2!=0)throw new TypeError("Invalid hex string");r>a/2&&(r=a/2);for(var i=0;i<r;++i){var c=parseInt(t.substr(2*i,2),16);if(isNaN(c))return i;e[n+i]=c}return i}function E(e,t,n,r){return z(U(t,e.length-n),e,n,r)}function x(e,t,n,r){return z(function(e){for(var t=[],n=0;n<e.length;++n)t.push(255&e.charCodeAt(n));return t}(t),e,n,r)}function S(e,t,n,r){return x(e,t,n,r)}function w(e,t,n,r){return z(V(t),e,n,r)}function j(e,t,n,r){return z(function(e,t){for(var n,r,o,a=[],i=0;i<e.length&&!((t-=2)<0);++i)n=e.charCodeAt(i),r=n>>8,o=n%256,a.push(o),a.push(r);return a}(t,e.length-n),e,n,r)}function _(e,t,n){return 0===t&&n===e.length?r.fromByteArray(e):r.fromByteArray(e.slice(t,n))}function O(e,t,n){n=Math.min(e.length,n);for(var r=[],o=t;o<n;){var a,i,c,s,u=e[o],l=null,p=u>239?4:u>223?3:u>191?2:1;if(o+p<=n)switch(p){case 1:u<128&&(l=u);break;case 2:128==(192&(a=e[o+1]))&&(s=(31&u)<<6|63&a)>127&&(l=s);break;case 3:a=e[o+1],i=e[o+2],128==(192&a)&&128==(192&i)&&(s=(15&u)<<12|(63&a)<<6|63&i)>2047&&(s<55296||s>57343)&&(l=s);break;case 4:a=e[o+1],i=e[o+2],c=e[o+3],128==(192&a)&&128==(192&i)&&128==(192&c)&&(s=(15&u)<<18|(63&a)<<12|(63&i)<<6|63&c)>65535&&s<1114112&&(l=s)}null===l?(l=65533,p=1):l>65535&&(l-=65536,r.push(l>>>10&1023|55296),l=56320|1023&l),r.push(l),o+=p}return function(e){var t=e.length;if(t<=4096)return String.fromCharCode.apply(String,e);var n="",r=0;for(;r<t;)n+=String.fromCharCode.apply(String,e.slice(r,r+=4096));return n}(r)}t.Buffer=s,t.SlowBuffer=function(e){+e!=e&&(e=0);return s.alloc(+e)},t.INSPECT_MAX_BYTES=50,s.TYPED_ARRAY_SUPPORT=void 0!==e.TYPED_ARRAY_SUPPORT?e.TYPED_ARRAY_SUPPORT:function(){try{var e=new Uint8Array(1);return e.__proto__={__proto__:Uint8Array.prototype,foo:function(){return 42}},42===e.foo()&&"function"==typeof e.subarray&&0===e.subarray(1,1).byteLength}catch(e){return!1}}(),t.kMaxLength=i(),s.poolSize=8192,s._augment=function(e){return e.__proto__=s.prototype,e},s.from=function(e,t,n){return u(null,e,t,n)},s.TYPED_ARRAY_SUPPORT&&(s.prototype.__proto__=Uint8Array.prototype,s.__proto__=Uint8Array,"undefined"!=typeof Symbol&&Symbol.species&&s[Symbol.species]===s&&Object.defineProperty(s,Symbol.species,{value:null,configurable:!0})),s.alloc=function(e,t,n){return function(e,t,n,r){return l(t),t<=0?c(e,t):void 0!==n?"string"==typeof r?c(e,t).fill(n,r):c(e,t).fill(n):c(e,t)}(null,e,t,n)},s.allocUnsafe=function(e){return p(null,e)},s.allocUnsafeSlow=function(e){return p(null,e)},s.isBuffer=function(e){return!(null==e||!e._isBuffer)},s.compare=function(e,t){if(!s.isBuffer(e)||!s.isBuffer(t))throw new TypeError("Arguments must be Buffers");if(e===t)return 0;for(var n=e.length,r=t.length,o=0,a=Math.min(n,r);o<a;++o)if(e[o]!==t[o]){n=e[o],r=t[o];break}return n<r?-1:r<n?1:0},s.isEncoding=function(e){switch(String(e).toLowerCase()){case"hex":case"utf8":case"utf-8":case"ascii":case"latin1":case"binary":case"base64":case"ucs2":case"ucs-2":case"utf16le":case"utf-16le":return!0;default:return!1}},s.concat=function(e,t){if(!a(e))throw new TypeError('"list" argument must be an Array of Buffers');if(0===e.length)return s.alloc(0);var n;if(void 0===t)for(t=0,n=0;n<e.length;++n)t+=e[n].length;var r=s.allocUnsafe(t),o=0;for(n=0;n<e.length;++n){var i=e[n];if(!s.isBuffer(i))throw new TypeError('"list" argument must be an Array of Buffers');i.copy(r,o),o+=i.length}return r},s.byteLength=h,s.prototype._isBuffer=!0,s.prototype.swap16=function(){var e=this.length;if(e%2!=0)throw new RangeError("Buffer size must be a multiple of 16-bits");for(var t=0;t<e;t+=2)v(this,t,t+1);return this},s.prototype.swap32=function(){var e=this.length;if(e%4!=0)throw new RangeError("Buffer size must be a multiple of 32-bits");for(var t=0;t<e;t+=4)v(this,t,t+3),v(this,t+1,t+2);return this},s.prototype.swap64=function(){var e=this.length;if(e%8!=0)throw new RangeError("Buffer size must be a multiple of 64-bits");for(var t=0;t<e;t+=8)v(this,t,t+7),v(this,t+1,t+6),v(this,t+2,t+5),v(this,t+3,t+4);return this},s.prototype.toString=function(){var e=0|this.length;return 0===e?"":0===arguments.length?O(this,0,e):m.apply(this,arguments)},s.prototype.equals=function(e){if(!s.isBuffer(e))throw new TypeError("Argument must be a Buffer");return this===e||0===s.compare(this,e)},s.prototype.inspect=function(){var e="",n=t.INSPECT_MAX_BYTES;return this.length>0&&(e=this.toString("hex",0,n).match(/.{2}/g).join(" "),this.length>n&&(e+=" ... ")),"<Buffer "+e+">"},s.prototype.compare=function(e,t,n,r,o){if(!s.isBuffer(e))throw new TypeError("Argument must be a Buffer");if(void 0===t&&(t=0),void 0===n&&(n=e?e.length:0),void 0===r&&(r=0),void 0===o&&(o=this.length),t<0||n>e.length||r<0||o>this.length)throw new RangeError("out of range index");if(r>=o&&t>=n)return 0;if(r>=o)return-1;if(t>=n)return 1;if(this===e)return 0;for(var a=(o>>>=0)-(r>>>=0),i=(n>>>=0)-(t>>>=0),c=Math.min(a,i),u=this.slice(r,o),l=e.slice(t,n),p=0;p<c;++p)if(u[p]!==l[p]){a=u[p],i=l[p];break}return a<i?-1:i<a?1:0},s.prototype.includes=function(e,t,n){return-1!==this.indexOf(e,t,n)},s.prototype.indexOf=function(e,t,n){return g(this,e,t,n,!0)},s.prototype.lastIndexOf=function(e,t,n){return g(this,e,t,n,!1)},s.prototype.write=function(e,t,n,r){if(void 0===t)r="utf8",n=this.length,t=0;else if(void 0===n&&"string"==typeof t)r=t,n=this.length,t=0;else{if(!isFinite(t))throw new Error("Buffer.write(string, encoding, offset[, length]) is no longer supported");t|=0,isFinite(n)?(n|=0,void 0===r&&(r="utf8")):(r=n,n=void 0)}var o=this.length-t;if((void 0===n||n>o)&&(n=o),e.length>0&&(n<0||t<0)||t>this.length)throw new RangeError("Attempt to write outside buffer bounds");r||(r="utf8");for(var a=!1;;)switch(r){case"hex":return b(this,e,t,n);case"utf8":case"utf-8":return E(this,e,t,n);case"ascii":return x(this,e,t,n);case"latin1":case"binary":return S(this,e,t,n);case"base64":return w(this,e,t,n);case"ucs2":case"ucs-2":case"utf16le":case"utf-16le":return j(this,e,t,n);default:if(a)throw new TypeError("Unknown encoding: "+r);r=(""+r).toLowerCase(),a=!0}},s.prototype.toJSON=function(){return{type:"Buffer",data:Array.prototype.slice.call(this._arr||this,0)}};function C(e,t,n){var r="";n=Math.min(e.length,n);for(var o=t;o<n;++o)r+=String.fromCharCode(127&e[o]);return r}function A(e,t,n){var r="";n=Math.min(e.length,n);for(var o=t;o<n;++o)r+=String.fromCharCode(e[o]);return r}function k(e,t,n){var r=e.length;(!t||t<0)&&(t=0),(!n||n<0||n>r)&&(n=r);for(var o="",a=t;a<n;++a)o+=B(e[a]);return o}function P(e,t,n){for(var r=e.slice(t,n),o="",a=0;a<r.length;a+=2)o+=String.fromCharCode(r[a]+256*r[a+1]);return o}function I(e,t,n){if(e%1!=0||e<0)throw new RangeError("offset is not uint");if(e+t>n)throw new RangeError("Trying to access beyond buffer length")}function T(e,t,n,r,o,a){if(!s.isBuffer(e))throw new TypeError('"buffer" argument must be a Buffer instance');if(t>o||t<a)throw new RangeError('"value" argument is out of bounds');if(n+r>e.length)throw new RangeError("Index out of range")}function N(e,t,n,r){t<0&&(t=65535+t+1);for(var o=0,a=Math.min(e.length-n,2);o<a;++o)e[n+o]=(t&255<<8*(r?o:1-o))>>>8*(r?o:1-o)}function R(e,t,n,r){t<0&&(t=4294967295+t+1);for(var o=0,a=Math.min(e.length-n,4);o<a;++o)e[n+o]=t>>>8*(r?o:3-o)&255}function M(e,t,n,r,o,a){if(n+r>e.length)throw new RangeError("Index out of range");if(n<0)throw new RangeError("Index out of range")}function D(e,t,n,r,a){return a||M(e,0,n,4),o.write(e,t,n,r,23,4),n+4}function q(e,t,n,r,a){return a||M(e,0,n,8),o.write(e,t,n,r,52,8),n+8}s.prototype.slice=function(e,t){var n,r=this.length;if((e=~~e)<0?(e+=r)<0&&(e=0):e>r&&(e=r),(t=void 0===t?r:~~t)<0?(t+=r)<0&&(t=0):t>r&&(t=r),t<e&&(t=e),s.TYPED_ARRAY_SUPPORT)(n=this.subarray(e,t)).__proto__=s.prototype;else{var o=t-e;n=new s(o,void 0);for(var a=0;a<o;++a)n[a]=this[a+e]}return n},s.prototype.readUIntLE=function(e,t,n){e|=0,t|=0,n||I(e,t,this.length);for(var r=this[e],o=1,a=0;++a<t&&(o*=256);)r+=this[e+a]*o;return r},s.prototype.readUIntBE=function(e,t,n){e|=0,t|=0,n||I(e,t,this.length);for(var r=this[e+--t],o=1;t>0&&(o*=256);)r+=this[e+--t]*o;return r},s.prototype.readUInt8=function(e,t){return t||I(e,1,this.length),this[e]},s.prototype.readUInt16LE=function(e,t){return t||I(e,2,this.length),this[e]|this[e+1]<<8},s.prototype.readUInt16BE=function(e,t){return t||I(e,2,this.length),this[e]<<8|this[e+1]},s.prototype.readUInt32LE=function(e,t){return t||I(e,4,this.length),(this[e]|this[e+1]<<8|this[e+2]<<16)+16777216*this[e+3]},s.prototype.readUInt32BE=function(e,t){return t||I(e,4,this.length),16777216*this[e]+(this[e+1]<<16|this[e+2]<<8|this[e+3])},s.prototype.readIntLE=function(e,t,n){e|=0,t|=0,n||I(e,t,this.length);for(var r=this[e],o=1,a=0;++a<t&&(o*=256);)r+=this[e+a]*o;return r>=(o*=128)&&(r-=Math.pow(2,8*t)),r},s.prototype.readIntBE=function(e,t,n){e|=0,t|=0,n||I(e,t,this.length);for(var r=t,o=1,a=this[e+--r];r>0&&(o*=256);)a+=this[e+--r]*o;return a>=(o*=128)&&(a-=Math.pow(2,8*t)),a},s.prototype.readInt8=function(e,t){return t||I(e,1,this.length),128&this[e]?-1*(255-this[e]+1):this[e]},s.prototype.readInt16LE=function(e,t){t||I(e,2,this.length);var n=this[e]|this[e+1]<<8;return 32768&n?4294901760|n:n},s.prototype.readInt16BE=function(e,t){t||I(e,2,this.length);var n=this[e+1]|this[e]<<8;return 32768&n?4294901760|n:n},s.prototype.readInt32LE=function(e,t){return t||I(e,4,this.length),this[e]|this[e+1]<<8|this[e+2]<<16|this[e+3]<<24},s.prototype.readInt32BE=function(e,t){return t||I(e,4,this.length),this[e]<<24|this[e+1]<<16|this[e+2]<<8|this[e+3]},s.prototype.readFloatLE=function(e,t){return t||I(e,4,this.length),o.read(this,e,!0,23,4)},s.prototype.readFloatBE=function(e,t){return t||I(e,4,this.length),o.read(this,e,!1,23,4)},s.prototype.readDoubleLE=function(e,t){return t||I(e,8,this.length),o.read(this,e,!0,52,8)},s.prototype.readDoubleBE=function(e,t){return t||I(e,8,this.length),o.read(this,e,!1,52,8)},s.prototype.writeUIntLE=function(e,t,n,r){(e=+e,t|=0,n|=0,r)||T(this,e,t,n,Math.pow(2,8*n)-1,0);var o=1,a=0;for(this[t]=255&e;++a<n&&(o*=256);)this[t+a]=e/o&255;return t+n},s.prototype.writeUIntBE=function(e,t,n,r){(e=+e,t|=0,n|=0,r)||T(this,e,t,n,Math.pow(2,8*n)-1,0);var o=n-1,a=1;for(this[t+o]=255&e;--o>=0&&(a*=256);)this[t+o]=e/a&255;return t+n},s.prototype.writeUInt8=function(e,t,n){return e=+e,t|=0,n||T(this,e,t,1,255,0),s.TYPED_ARRAY_SUPPORT||(e=Math.floor(e)),this[t]=255&e,t+1},s.prototype.writeUInt16LE=function(e,t,n){return e=+e,t|=0,n||T(this,e,t,2,65535,0),s.TYPED_ARRAY_SUPPORT?(this[t]=255&e,this[t+1]=e>>>8):N(this,e,t,!0),t+2},s.prototype.writeUInt16BE=function(e,t,n){return e=+e,t|=0,n||T(this,e,t,2,65535,0),s.TYPED_ARRAY_SUPPORT?(this[t]=e>>>8,this[t+1]=255&e):N(this,e,t,!1),t+2},s.prototype.writeUInt32LE=function(e,t,n){return e=+e,t|=0,n||T(this,e,t,4,4294967295,0),s.TYPED_ARRAY_SUPPORT?(this[t+3]=e>>>24,this[t+2]=e>>>16,this[t+1]=e>>>8,this[t]=255&e):R(this,e,t,!0),t+4},s.prototype.writeUInt32BE=function(e,t,n){return e=+e,t|=0,n||T(this,e,t,4,4294967295,0),s.TYPED_ARRAY_SUPPORT?(this[t]=e>>>24,this[t+1]=e>>>16,this[t+2]=e>>>8,this[t+3]=255&e):R(this,e,t,!1),t+4},s.prototype.writeIntLE=function(e,t,n,r){if(e=+e,t|=0,!r){var o=Math.pow(2,8*n-1);T(this,e,t,n,o-1,-o)}var a=0,i=1,c=0;for(this[t]=255&e;++a<n&&(i*=256);)e<0&&0===c&&0!==this[t+a-1]&&(c=1),this[t+a]=(e/i>>0)-c&255;return t+n},s.prototype.writeIntBE=function(e,t,n,r){if(e=+e,t|=0,!r){var o=Math.pow(2,8*n-1);T(this,e,t,n,o-1,-o)}var a=n-1,i=1,c=0;for(this[t+a]=255&e;--a>=0&&(i*=256);)e<0&&0===c&&0!==this[t+a+1]&&(c=1),this[t+a]=(e/i>>0)-c&255;return t+n},s.prototype.writeInt8=function(e,t,n){return e=+e,t|=0,n||T(this,e,t,1,127,-128),s.TYPED_ARRAY_SUPPORT||(e=Math.floor(e)),e<0&&(e=255+e+1),this[t]=255&e,t+1},s.prototype.writeInt16LE=function(e,t,n){return e=+e,t|=0,n||T(this,e,t,2,32767,-32768),s.TYPED_ARRAY_SUPPORT?(this[t]=255&e,this[t+1]=e>>>8):N(this,e,t,!0),t+2},s.prototype.writeInt16BE=function(e,t,n){return e=+e,t|=0,n||T(this,e,t,2,32767,-32768),s.TYPED_ARRAY_SUPPORT?(this[t]=e>>>8,this[t+1]=255&e):N(this,e,t,!1),t+2},s.prototype.writeInt32LE=function(e,t,n){return e=+e,t|=0,n||T(this,e,t,4,2147483647,-2147483648),s.TYPED_ARRAY_SUPPORT?(this[t]=255&e,this[t+1]=e>>>8,this[t+2]=e>>>16,this[t+3]=e>>>24):R(this,e,t,!0),t+4},s.prototype.writeInt32BE=function(e,t,n){return e=+e,t|=0,n||T(this,e,t,4,2147483647,-2147483648),e<0&&(e=4294967295+e+1),s.TYPED_ARRAY_SUPPORT?(this[t]=e>>>24,this[t+1]=e>>>16,this[t+2]=e>>>8,this[t+3]=255&e):R(this,e,t,!1),t+4},s.prototype.writeFloatLE=function(e,t,n){return D(this,e,t,!0,n)},s.prototype.writeFloatBE=function(e,t,n){return D(this,e,t,!1,n)},s.prototype.writeDoubleLE=function(e,t,n){return q(this,e,t,!0,n)},s.prototype.writeDoubleBE=function(e,t,n){return q(this,e,t,!1,n)},s.prototype.copy=function(e,t,n,r){if(n||(n=0),r||0===r||(r=this.length),t>=e.length&&(t=e.length),t||(t=0),r>0&&r<n&&(r=n),r===n)return 0;if(0===e.length||0===this.length)return 0;if(t<0)throw new RangeError("targetStart out of bounds");if(n<0||n>=this.length)throw new RangeError("sourceStart out of bounds");if(r<0)throw new RangeError("sourceEnd out of bounds");r>this.length&&(r=this.length),e.length-t<r-n&&(r=e.length-t+n);var o,a=r-n;if(this===e&&n<t&&t<r)for(o=a-1;o>=0;--o)e[o+t]=this[o+n];else if(a<1e3||!s.TYPED_ARRAY_SUPPORT)for(o=0;o<a;++o)e[o+t]=this[o+n];else Uint8Array.prototype.set.call(e,this.subarray(n,n+a),t);return a},s.prototype.fill=function(e,t,n,r){if("string"==typeof e){if("string"==typeof t?(r=t,t=0,n=this.length):"string"==typeof n&&(r=n,n=this.length),1===e.length){var o=e.charCodeAt(0);o<256&&(e=o)}if(void 0!==r&&"string"!=typeof r)throw new TypeError("encoding must be a string");if("string"==typeof r&&!s.isEncoding(r))throw new TypeError("Unknown encoding: "+r)}else"number"==typeof e&&(e&=255);if(t<0||this.length<t||this.length<n)throw new RangeError("Out of range index");if(n<=t)return this;var a;if(t>>>=0,n=void 0===n?this.length:n>>>0,e||(e=0),"number"==typeof e)for(a=t;a<n;++a)this[a]=e;else{var i=s.isBuffer(e)?e:U(new s(e,r).toString()),c=i.length;for(a=0;a<n-t;++a)this[a+t]=i[a%c]}return this};var L=/[^+\/0-9A-Za-z-_]/g;function B(e){return e<16?"0"+e.toString(16):e.toString(16)}function U(e,t){var n;t=t||1/0;for(var r=e.length,o=null,a=[],i=0;i<r;++i){if((n=e.charCodeAt(i))>55295&&n<57344){if(!o){if(n>56319){(t-=3)>-1&&a.push(239,191,189);continue}if(i+1===r){(t-=3)>-1&&a.push(239,191,189);continue}o=n;continue}if(n<56320){(t-=3)>-1&&a.push(239,191,189),o=n;continue}n=65536+(o-55296<<10|n-56320)}else o&&(t-=3)>-1&&a.push(239,191,189);if(o=null,n<128){if((t-=1)<0)break;a.push(n)}else if(n<2048){if((t-=2)<0)break;a.push(n>>6|192,63&n|128)}else if(n<65536){if((t-=3)<0)break;a.push(n>>12|224,n>>6&63|128,63&n|128)}else{if(!(n<1114112))throw new Error("Invalid code point");if((t-=4)<0)break;a.push(n>>18|240,n>>12&63|128,n>>6&63|128,63&n|128)}}return a}function V(e){return r.toByteArray(function(e){if((e=function(e){return e.trim?e.trim():e.replace(/^\s+|\s+$/g,"")}(e).replace(L,"")).length<2)return"";for(;e.length%4!=0;)e+="=";return e}(e))}function z(e,t,n,r){for(var o=0;o<r&&!(o+n>=t.length||o>=e.length);++o)t[o+n]=e[o];return o}}).call(this,n(292))},function(e,t){e.exports=require("base64-js")},function(e,t){e.exports=require("ieee754")},function(e,t){e.exports=require("isarray")},function(e,t,n){var r=n(294),o=n(295),a=n(124);e.exports=function(e,t){if(void 0!==a&&o(Object(e))){var n=[],i=!0,c=!1,s=void 0;try{for(var u,l=r(e);!(i=(u=l.next()).done)&&(n.push(u.value),!t||n.length!==t);i=!0);}catch(e){c=!0,s=e}finally{try{i||null==l.return||l.return()}finally{if(c)throw s}}return n}}},function(e,t,n){n(83),n(81),e.exports=n(476)},function(e,t,n){var r=n(36),o=n(186);e.exports=n(21).getIterator=function(e){var t=o(e);if("function"!=typeof t)throw TypeError(e+" is not iterable!");return r(t.call(e))}},function(e,t,n){n(83),n(81),e.exports=n(478)},function(e,t,n){var r=n(127),o=n(32)("iterator"),a=n(82);e.exports=n(21).isIterable=function(e){var t=Object(e);return void 0!==t[o]||"@@iterator"in t||a.hasOwnProperty(r(t))}},function(e,t,n){n(81),n(480),e.exports=n(21).Array.from},function(e,t,n){"use strict";var r=n(53),o=n(24),a=n(69),i=n(296),c=n(297),s=n(122),u=n(298),l=n(186);o(o.S+o.F*!n(299)((function(e){Array.from(e)})),"Array",{from:function(e){var t,n,o,p,f=a(e),d="function"==typeof this?this:Array,h=arguments.length,m=h>1?arguments[1]:void 0,v=void 0!==m,g=0,y=l(f);if(v&&(m=r(m,h>2?arguments[2]:void 0,2)),null==y||d==Array&&c(y))for(n=new d(t=s(f.length));t>g;g++)u(n,g,v?m(f[g],g):f[g]);else for(p=y.call(f),n=new d;!(o=p.next()).done;g++)u(n,g,v?i(p,m,[o.value,g],!0):o.value);return n.length=g,n}})},function(e,t,n){var r=n(55),o=n(204);e.exports=function(e){return o(r(e).toLowerCase())}},function(e,t,n){var r=n(84),o=Object.prototype,a=o.hasOwnProperty,i=o.toString,c=r?r.toStringTag:void 0;e.exports=function(e){var t=a.call(e,c),n=e[c];try{e[c]=void 0;var r=!0}catch(e){}var o=i.call(e);return r&&(t?e[c]=n:delete e[c]),o}},function(e,t){var n=Object.prototype.toString;e.exports=function(e){return n.call(e)}},function(e,t,n){var r=n(485),o=n(306),a=n(486),i=n(55);e.exports=function(e){return function(t){t=i(t);var n=o(t)?a(t):void 0,c=n?n[0]:t.charAt(0),s=n?r(n,1).join(""):t.slice(1);return c[e]()+s}}},function(e,t,n){var r=n(305);e.exports=function(e,t,n){var o=e.length;return n=void 0===n?o:n,!t&&n>=o?e:r(e,t,n)}},function(e,t,n){var r=n(487),o=n(306),a=n(488);e.exports=function(e){return o(e)?a(e):r(e)}},function(e,t){e.exports=function(e){return e.split("")}},function(e,t){var n="[\\ud800-\\udfff]",r="[\\u0300-\\u036f\\ufe20-\\ufe2f\\u20d0-\\u20ff]",o="\\ud83c[\\udffb-\\udfff]",a="[^\\ud800-\\udfff]",i="(?:\\ud83c[\\udde6-\\uddff]){2}",c="[\\ud800-\\udbff][\\udc00-\\udfff]",s="(?:"+r+"|"+o+")"+"?",u="[\\ufe0e\\ufe0f]?"+s+("(?:\\u200d(?:"+[a,i,c].join("|")+")[\\ufe0e\\ufe0f]?"+s+")*"),l="(?:"+[a+r+"?",r,i,c,n].join("|")+")",p=RegExp(o+"(?="+o+")|"+l+u,"g");e.exports=function(e){return e.match(p)||[]}},function(e,t,n){var r=n(307),o=n(490),a=n(493),i=RegExp("['’]","g");e.exports=function(e){return function(t){return r(a(o(t).replace(i,"")),e,"")}}},function(e,t,n){var r=n(491),o=n(55),a=/[\xc0-\xd6\xd8-\xf6\xf8-\xff\u0100-\u017f]/g,i=RegExp("[\\u0300-\\u036f\\ufe20-\\ufe2f\\u20d0-\\u20ff]","g");e.exports=function(e){return(e=o(e))&&e.replace(a,r).replace(i,"")}},function(e,t,n){var r=n(492)({"À":"A","Á":"A","Â":"A","Ã":"A","Ä":"A","Å":"A","à":"a","á":"a","â":"a","ã":"a","ä":"a","å":"a","Ç":"C","ç":"c","Ð":"D","ð":"d","È":"E","É":"E","Ê":"E","Ë":"E","è":"e","é":"e","ê":"e","ë":"e","Ì":"I","Í":"I","Î":"I","Ï":"I","ì":"i","í":"i","î":"i","ï":"i","Ñ":"N","ñ":"n","Ò":"O","Ó":"O","Ô":"O","Õ":"O","Ö":"O","Ø":"O","ò":"o","ó":"o","ô":"o","õ":"o","ö":"o","ø":"o","Ù":"U","Ú":"U","Û":"U","Ü":"U","ù":"u","ú":"u","û":"u","ü":"u","Ý":"Y","ý":"y","ÿ":"y","Æ":"Ae","æ":"ae","Þ":"Th","þ":"th","ß":"ss","Ā":"A","Ă":"A","Ą":"A","ā":"a","ă":"a","ą":"a","Ć":"C","Ĉ":"C","Ċ":"C","Č":"C","ć":"c","ĉ":"c","ċ":"c","č":"c","Ď":"D","Đ":"D","ď":"d","đ":"d","Ē":"E","Ĕ":"E","Ė":"E","Ę":"E","Ě":"E","ē":"e","ĕ":"e","ė":"e","ę":"e","ě":"e","Ĝ":"G","Ğ":"G","Ġ":"G","Ģ":"G","ĝ":"g","ğ":"g","ġ":"g","ģ":"g","Ĥ":"H","Ħ":"H","ĥ":"h","ħ":"h","Ĩ":"I","Ī":"I","Ĭ":"I","Į":"I","İ":"I","ĩ":"i","ī":"i","ĭ":"i","į":"i","ı":"i","Ĵ":"J","ĵ":"j","Ķ":"K","ķ":"k","ĸ":"k","Ĺ":"L","Ļ":"L","Ľ":"L","Ŀ":"L","Ł":"L","ĺ":"l","ļ":"l","ľ":"l","ŀ":"l","ł":"l","Ń":"N","Ņ":"N","Ň":"N","Ŋ":"N","ń":"n","ņ":"n","ň":"n","ŋ":"n","Ō":"O","Ŏ":"O","Ő":"O","ō":"o","ŏ":"o","ő":"o","Ŕ":"R","Ŗ":"R","Ř":"R","ŕ":"r","ŗ":"r","ř":"r","Ś":"S","Ŝ":"S","Ş":"S","Š":"S","ś":"s","ŝ":"s","ş":"s","š":"s","Ţ":"T","Ť":"T","Ŧ":"T","ţ":"t","ť":"t","ŧ":"t","Ũ":"U","Ū":"U","Ŭ":"U","Ů":"U","Ű":"U","Ų":"U","ũ":"u","ū":"u","ŭ":"u","ů":"u","ű":"u","ų":"u","Ŵ":"W","ŵ":"w","Ŷ":"Y","ŷ":"y","Ÿ":"Y","Ź":"Z","Ż":"Z","Ž":"Z","ź":"z","ż":"z","ž":"z","Ĳ":"IJ","ĳ":"ij","Œ":"Oe","œ":"oe","ŉ":"'n","ſ":"s"});e.exports=r},function(e,t){e.exports=function(e){return function(t){return null==e?void 0:e[t]}}},function(e,t,n){var r=n(494),o=n(495),a=n(55),i=n(496);e.exports=function(e,t,n){return e=a(e),void 0===(t=n?void 0:t)?o(e)?i(e):r(e):e.match(t)||[]}},function(e,t){var n=/[^\x00-\x2f\x3a-\x40\x5b-\x60\x7b-\x7f]+/g;e.exports=function(e){return e.match(n)||[]}},function(e,t){var n=/[a-z][A-Z]|[A-Z]{2}[a-z]|[0-9][a-zA-Z]|[a-zA-Z][0-9]|[^a-zA-Z0-9 ]/;e.exports=function(e){return n.test(e)}},function(e,t){var n="\\xac\\xb1\\xd7\\xf7\\x00-\\x2f\\x3a-\\x40\\x5b-\\x60\\x7b-\\xbf\\u2000-\\u206f \\t\\x0b\\f\\xa0\\ufeff\\n\\r\\u2028\\u2029\\u1680\\u180e\\u2000\\u2001\\u2002\\u2003\\u2004\\u2005\\u2006\\u2007\\u2008\\u2009\\u200a\\u202f\\u205f\\u3000",r="["+n+"]",o="\\d+",a="[\\u2700-\\u27bf]",i="[a-z\\xdf-\\xf6\\xf8-\\xff]",c="[^\\ud800-\\udfff"+n+o+"\\u2700-\\u27bfa-z\\xdf-\\xf6\\xf8-\\xffA-Z\\xc0-\\xd6\\xd8-\\xde]",s="(?:\\ud83c[\\udde6-\\uddff]){2}",u="[\\ud800-\\udbff][\\udc00-\\udfff]",l="[A-Z\\xc0-\\xd6\\xd8-\\xde]",p="(?:"+i+"|"+c+")",f="(?:"+l+"|"+c+")",d="(?:[\\u0300-\\u036f\\ufe20-\\ufe2f\\u20d0-\\u20ff]|\\ud83c[\\udffb-\\udfff])?",h="[\\ufe0e\\ufe0f]?"+d+("(?:\\u200d(?:"+["[^\\ud800-\\udfff]",s,u].join("|")+")[\\ufe0e\\ufe0f]?"+d+")*"),m="(?:"+[a,s,u].join("|")+")"+h,v=RegExp([l+"?"+i+"+(?:['’](?:d|ll|m|re|s|t|ve))?(?="+[r,l,"$"].join("|")+")",f+"+(?:['’](?:D|LL|M|RE|S|T|VE))?(?="+[r,l+p,"$"].join("|")+")",l+"?"+p+"+(?:['’](?:d|ll|m|re|s|t|ve))?",l+"+(?:['’](?:D|LL|M|RE|S|T|VE))?","\\d*(?:1ST|2ND|3RD|(?![123])\\dTH)(?=\\b|[a-z_])","\\d*(?:1st|2nd|3rd|(?![123])\\dth)(?=\\b|[A-Z_])",o,m].join("|"),"g");e.exports=function(e){return e.match(v)||[]}},function(e,t,n){var r=n(498),o=n(131),a=n(188);e.exports=function(){this.size=0,this.__data__={hash:new r,map:new(a||o),string:new r}}},function(e,t,n){var r=n(499),o=n(504),a=n(505),i=n(506),c=n(507);function s(e){var t=-1,n=null==e?0:e.length;for(this.clear();++t<n;){var r=e[t];this.set(r[0],r[1])}}s.prototype.clear=r,s.prototype.delete=o,s.prototype.get=a,s.prototype.has=i,s.prototype.set=c,e.exports=s},function(e,t,n){var r=n(130);e.exports=function(){this.__data__=r?r(null):{},this.size=0}},function(e,t,n){var r=n(46),o=n(501),a=n(40),i=n(308),c=/^\[object .+?Constructor\]$/,s=Function.prototype,u=Object.prototype,l=s.toString,p=u.hasOwnProperty,f=RegExp("^"+l.call(p).replace(/[\\^$.*+?()[\]{}|]/g,"\\$&").replace(/hasOwnProperty|(function).*?(?=\\\()| for .+?(?=\\\])/g,"$1.*?")+"$");e.exports=function(e){return!(!a(e)||o(e))&&(r(e)?f:c).test(i(e))}},function(e,t,n){var r,o=n(502),a=(r=/[^.]+$/.exec(o&&o.keys&&o.keys.IE_PROTO||""))?"Symbol(src)_1."+r:"";e.exports=function(e){return!!a&&a in e}},function(e,t,n){var r=n(49)["__core-js_shared__"];e.exports=r},function(e,t){e.exports=function(e,t){return null==e?void 0:e[t]}},function(e,t){e.exports=function(e){var t=this.has(e)&&delete this.__data__[e];return this.size-=t?1:0,t}},function(e,t,n){var r=n(130),o=Object.prototype.hasOwnProperty;e.exports=function(e){var t=this.__data__;if(r){var n=t[e];return"__lodash_hash_undefined__"===n?void 0:n}return o.call(t,e)?t[e]:void 0}},function(e,t,n){var r=n(130),o=Object.prototype.hasOwnProperty;e.exports=function(e){var t=this.__data__;return r?void 0!==t[e]:o.call(t,e)}},function(e,t,n){var r=n(130);e.exports=function(e,t){var n=this.__data__;return this.size+=this.has(e)?0:1,n[e]=r&&void 0===t?"__lodash_hash_undefined__":t,this}},function(e,t){e.exports=function(){this.__data__=[],this.size=0}},function(e,t,n){var r=n(132),o=Array.prototype.splice;e.exports=function(e){var t=this.__data__,n=r(t,e);return!(n<0)&&(n==t.length-1?t.pop():o.call(t,n,1),--this.size,!0)}},function(e,t,n){var r=n(132);e.exports=function(e){var t=this.__data__,n=r(t,e);return n<0?void 0:t[n][1]}},function(e,t,n){var r=n(132);e.exports=function(e){return r(this.__data__,e)>-1}},function(e,t,n){var r=n(132);e.exports=function(e,t){var n=this.__data__,o=r(n,e);return o<0?(++this.size,n.push([e,t])):n[o][1]=t,this}},function(e,t,n){var r=n(133);e.exports=function(e){var t=r(this,e).delete(e);return this.size-=t?1:0,t}},function(e,t){e.exports=function(e){var t=typeof e;return"string"==t||"number"==t||"symbol"==t||"boolean"==t?"__proto__"!==e:null===e}},function(e,t,n){var r=n(133);e.exports=function(e){return r(this,e).get(e)}},function(e,t,n){var r=n(133);e.exports=function(e){return r(this,e).has(e)}},function(e,t,n){var r=n(133);e.exports=function(e,t){var n=r(this,e),o=n.size;return n.set(e,t),this.size+=n.size==o?0:1,this}},function(e,t,n){var r=n(134),o=n(85),a=n(73);e.exports=function(e){return function(t,n,i){var c=Object(t);if(!o(t)){var s=r(n,3);t=a(t),n=function(e){return s(c[e],e,c)}}var u=e(t,n,i);return u>-1?c[s?t[u]:u]:void 0}}},function(e,t,n){var r=n(520),o=n(546),a=n(320);e.exports=function(e){var t=o(e);return 1==t.length&&t[0][2]?a(t[0][0],t[0][1]):function(n){return n===e||r(n,e,t)}}},function(e,t,n){var r=n(189),o=n(309);e.exports=function(e,t,n,a){var i=n.length,c=i,s=!a;if(null==e)return!c;for(e=Object(e);i--;){var u=n[i];if(s&&u[2]?u[1]!==e[u[0]]:!(u[0]in e))return!1}for(;++i<c;){var l=(u=n[i])[0],p=e[l],f=u[1];if(s&&u[2]){if(void 0===p&&!(l in e))return!1}else{var d=new r;if(a)var h=a(p,f,l,e,t,d);if(!(void 0===h?o(f,p,3,a,d):h))return!1}}return!0}},function(e,t,n){var r=n(131);e.exports=function(){this.__data__=new r,this.size=0}},function(e,t){e.exports=function(e){var t=this.__data__,n=t.delete(e);return this.size=t.size,n}},function(e,t){e.exports=function(e){return this.__data__.get(e)}},function(e,t){e.exports=function(e){return this.__data__.has(e)}},function(e,t,n){var r=n(131),o=n(188),a=n(187);e.exports=function(e,t){var n=this.__data__;if(n instanceof r){var i=n.__data__;if(!o||i.length<199)return i.push([e,t]),this.size=++n.size,this;n=this.__data__=new a(i)}return n.set(e,t),this.size=n.size,this}},function(e,t,n){var r=n(189),o=n(310),a=n(531),i=n(534),c=n(137),s=n(33),u=n(193),l=n(317),p="[object Object]",f=Object.prototype.hasOwnProperty;e.exports=function(e,t,n,d,h,m){var v=s(e),g=s(t),y=v?"[object Array]":c(e),b=g?"[object Array]":c(t),E=(y="[object Arguments]"==y?p:y)==p,x=(b="[object Arguments]"==b?p:b)==p,S=y==b;if(S&&u(e)){if(!u(t))return!1;v=!0,E=!1}if(S&&!E)return m||(m=new r),v||l(e)?o(e,t,n,d,h,m):a(e,t,y,n,d,h,m);if(!(1&n)){var w=E&&f.call(e,"__wrapped__"),j=x&&f.call(t,"__wrapped__");if(w||j){var _=w?e.value():e,O=j?t.value():t;return m||(m=new r),h(_,O,n,d,m)}}return!!S&&(m||(m=new r),i(e,t,n,d,h,m))}},function(e,t,n){var r=n(187),o=n(528),a=n(529);function i(e){var t=-1,n=null==e?0:e.length;for(this.__data__=new r;++t<n;)this.add(e[t])}i.prototype.add=i.prototype.push=o,i.prototype.has=a,e.exports=i},function(e,t){e.exports=function(e){return this.__data__.set(e,"__lodash_hash_undefined__"),this}},function(e,t){e.exports=function(e){return this.__data__.has(e)}},function(e,t){e.exports=function(e,t){return e.has(t)}},function(e,t,n){var r=n(84),o=n(312),a=n(74),i=n(310),c=n(532),s=n(533),u=r?r.prototype:void 0,l=u?u.valueOf:void 0;e.exports=function(e,t,n,r,u,p,f){switch(n){case"[object DataView]":if(e.byteLength!=t.byteLength||e.byteOffset!=t.byteOffset)return!1;e=e.buffer,t=t.buffer;case"[object ArrayBuffer]":return!(e.byteLength!=t.byteLength||!p(new o(e),new o(t)));case"[object Boolean]":case"[object Date]":case"[object Number]":return a(+e,+t);case"[object Error]":return e.name==t.name&&e.message==t.message;case"[object RegExp]":case"[object String]":return e==t+"";case"[object Map]":var d=c;case"[object Set]":var h=1&r;if(d||(d=s),e.size!=t.size&&!h)return!1;var m=f.get(e);if(m)return m==t;r|=2,f.set(e,t);var v=i(d(e),d(t),r,u,p,f);return f.delete(e),v;case"[object Symbol]":if(l)return l.call(e)==l.call(t)}return!1}},function(e,t){e.exports=function(e){var t=-1,n=Array(e.size);return e.forEach((function(e,r){n[++t]=[r,e]})),n}},function(e,t){e.exports=function(e){var t=-1,n=Array(e.size);return e.forEach((function(e){n[++t]=e})),n}},function(e,t,n){var r=n(313),o=Object.prototype.hasOwnProperty;e.exports=function(e,t,n,a,i,c){var s=1&n,u=r(e),l=u.length;if(l!=r(t).length&&!s)return!1;for(var p=l;p--;){var f=u[p];if(!(s?f in t:o.call(t,f)))return!1}var d=c.get(e);if(d&&c.get(t))return d==t;var h=!0;c.set(e,t),c.set(t,e);for(var m=s;++p<l;){var v=e[f=u[p]],g=t[f];if(a)var y=s?a(g,v,f,t,e,c):a(v,g,f,e,t,c);if(!(void 0===y?v===g||i(v,g,n,a,c):y)){h=!1;break}m||(m="constructor"==f)}if(h&&!m){var b=e.constructor,E=t.constructor;b==E||!("constructor"in e)||!("constructor"in t)||"function"==typeof b&&b instanceof b&&"function"==typeof E&&E instanceof E||(h=!1)}return c.delete(e),c.delete(t),h}},function(e,t){e.exports=function(e,t){for(var n=-1,r=null==e?0:e.length,o=0,a=[];++n<r;){var i=e[n];t(i,n,e)&&(a[o++]=i)}return a}},function(e,t){e.exports=function(e,t){for(var n=-1,r=Array(e);++n<e;)r[n]=t(n);return r}},function(e,t,n){var r=n(71),o=n(54);e.exports=function(e){return o(e)&&"[object Arguments]"==r(e)}},function(e,t){e.exports=function(){return!1}},function(e,t,n){var r=n(71),o=n(195),a=n(54),i={};i["[object Float32Array]"]=i["[object Float64Array]"]=i["[object Int8Array]"]=i["[object Int16Array]"]=i["[object Int32Array]"]=i["[object Uint8Array]"]=i["[object Uint8ClampedArray]"]=i["[object Uint16Array]"]=i["[object Uint32Array]"]=!0,i["[object Arguments]"]=i["[object Array]"]=i["[object ArrayBuffer]"]=i["[object Boolean]"]=i["[object DataView]"]=i["[object Date]"]=i["[object Error]"]=i["[object Function]"]=i["[object Map]"]=i["[object Number]"]=i["[object Object]"]=i["[object RegExp]"]=i["[object Set]"]=i["[object String]"]=i["[object WeakMap]"]=!1,e.exports=function(e){return a(e)&&o(e.length)&&!!i[r(e)]}},function(e,t,n){var r=n(136),o=n(541),a=Object.prototype.hasOwnProperty;e.exports=function(e){if(!r(e))return o(e);var t=[];for(var n in Object(e))a.call(e,n)&&"constructor"!=n&&t.push(n);return t}},function(e,t,n){var r=n(318)(Object.keys,Object);e.exports=r},function(e,t,n){var r=n(72)(n(49),"DataView");e.exports=r},function(e,t,n){var r=n(72)(n(49),"Promise");e.exports=r},function(e,t,n){var r=n(72)(n(49),"Set");e.exports=r},function(e,t,n){var r=n(72)(n(49),"WeakMap");e.exports=r},function(e,t,n){var r=n(319),o=n(73);e.exports=function(e){for(var t=o(e),n=t.length;n--;){var a=t[n],i=e[a];t[n]=[a,i,r(i)]}return t}},function(e,t,n){var r=n(309),o=n(25),a=n(321),i=n(198),c=n(319),s=n(320),u=n(87);e.exports=function(e,t){return i(e)&&c(t)?s(u(e),t):function(n){var i=o(n,e);return void 0===i&&i===t?a(n,e):r(t,i,3)}}},function(e,t,n){var r=n(549),o=/[^.[\]]+|\[(?:(-?\d+(?:\.\d+)?)|(["'])((?:(?!\2)[^\\]|\\.)*?)\2)\]|(?=(?:\.|\[\])(?:\.|\[\]|$))/g,a=/\\(\\)?/g,i=r((function(e){var t=[];return 46===e.charCodeAt(0)&&t.push(""),e.replace(o,(function(e,n,r,o){t.push(r?o.replace(a,"$1"):n||e)})),t}));e.exports=i},function(e,t,n){var r=n(205);e.exports=function(e){var t=r(e,(function(e){return 500===n.size&&n.clear(),e})),n=t.cache;return t}},function(e,t){e.exports=function(e,t){return null!=e&&t in Object(e)}},function(e,t,n){var r=n(86),o=n(192),a=n(33),i=n(135),c=n(195),s=n(87);e.exports=function(e,t,n){for(var u=-1,l=(t=r(t,e)).length,p=!1;++u<l;){var f=s(t[u]);if(!(p=null!=e&&n(e,f)))break;e=e[f]}return p||++u!=l?p:!!(l=null==e?0:e.length)&&c(l)&&i(f,l)&&(a(e)||o(e))}},function(e,t,n){var r=n(553),o=n(554),a=n(198),i=n(87);e.exports=function(e){return a(e)?r(i(e)):o(e)}},function(e,t){e.exports=function(e){return function(t){return null==t?void 0:t[e]}}},function(e,t,n){var r=n(138);e.exports=function(e){return function(t){return r(t,e)}}},function(e,t,n){var r=n(556),o=n(134),a=n(322),i=Math.max;e.exports=function(e,t,n){var c=null==e?0:e.length;if(!c)return-1;var s=null==n?0:a(n);return s<0&&(s=i(c+s,0)),r(e,o(t,3),s)}},function(e,t){e.exports=function(e,t,n,r){for(var o=e.length,a=n+(r?1:-1);r?a--:++a<o;)if(t(e[a],a,e))return a;return-1}},function(e,t,n){var r=n(323);e.exports=function(e){return e?(e=r(e))===1/0||e===-1/0?17976931348623157e292*(e<0?-1:1):e==e?e:0:0===e?e:0}},function(e,t,n){var r=n(324);e.exports=function(e,t){var n;return r(e,(function(e,r,o){return!(n=t(e,r,o))})),!!n}},function(e,t,n){var r=n(560),o=n(73);e.exports=function(e,t){return e&&r(e,t,o)}},function(e,t,n){var r=n(561)();e.exports=r},function(e,t){e.exports=function(e){return function(t,n,r){for(var o=-1,a=Object(t),i=r(t),c=i.length;c--;){var s=i[e?c:++o];if(!1===n(a[s],s,a))break}return t}}},function(e,t,n){var r=n(85);e.exports=function(e,t){return function(n,o){if(null==n)return n;if(!r(n))return e(n,o);for(var a=n.length,i=t?a:-1,c=Object(n);(t?i--:++i<a)&&!1!==o(c[i],i,c););return n}}},function(e,t){var n,r,o=e.exports={};function a(){throw new Error("setTimeout has not been defined")}function i(){throw new Error("clearTimeout has not been defined")}function c(e){if(n===setTimeout)return setTimeout(e,0);if((n===a||!n)&&setTimeout)return n=setTimeout,setTimeout(e,0);try{return n(e,0)}catch(t){try{return n.call(null,e,0)}catch(t){return n.call(this,e,0)}}}!function(){try{n="function"==typeof setTimeout?setTimeout:a}catch(e){n=a}try{r="function"==typeof clearTimeout?clearTimeout:i}catch(e){r=i}}();var s,u=[],l=!1,p=-1;function f(){l&&s&&(l=!1,s.length?u=s.concat(u):p=-1,u.length&&d())}function d(){if(!l){var e=c(f);l=!0;for(var t=u.length;t;){for(s=u,u=[];++p<t;)s&&s[p].run();p=-1,t=u.length}s=null,l=!1,function(e){if(r===clearTimeout)return clearTimeout(e);if((r===i||!r)&&clearTimeout)return r=clearTimeout,clearTimeout(e);try{r(e)}catch(t){try{return r.call(null,e)}catch(t){return r.call(this,e)}}}(e)}}function h(e,t){this.fun=e,this.array=t}function m(){}o.nextTick=function(e){var t=new Array(arguments.length-1);if(arguments.length>1)for(var n=1;n<arguments.length;n++)t[n-1]=arguments[n];u.push(new h(e,t)),1!==u.length||l||c(d)},h.prototype.run=function(){this.fun.apply(null,this.array)},o.title="browser",o.browser=!0,o.env={},o.argv=[],o.version="",o.versions={},o.on=m,o.addListener=m,o.once=m,o.off=m,o.removeListener=m,o.removeAllListeners=m,o.emit=m,o.prependListener=m,o.prependOnceListener=m,o.listeners=function(e){return[]},o.binding=function(e){throw new Error("process.binding is not supported")},o.cwd=function(){return"/"},o.chdir=function(e){throw new Error("process.chdir is not supported")},o.umask=function(){return 0}},function(e,t){var n={"&":"&amp;",'"':"&quot;","'":"&apos;","<":"&lt;",">":"&gt;"};e.exports=function(e){return e&&e.replace?e.replace(/([&"<>'])/g,(function(e,t){return n[t]})):e}},function(e,t){e.exports=require("stream")},function(e,t){e.exports=function(e,t,n,r,o){return o(e,(function(e,o,a){n=r?(r=!1,e):t(n,e,o,a)})),n}},function(e,t,n){var r=n(10),o=n(300);e.exports=function(e){if(r(e))return o(e)}},function(e,t){e.exports=function(){throw new TypeError("Invalid attempt to spread non-iterable instance.\nIn order to be iterable, non-array objects must have a [Symbol.iterator]() method.")}},function(e,t,n){e.exports=n(570)},function(e,t,n){n(571);var r=n(21).Object;e.exports=function(e,t){return r.defineProperties(e,t)}},function(e,t,n){var r=n(24);r(r.S+r.F*!n(39),"Object",{defineProperties:n(287)})},function(e,t,n){e.exports=n(573)},function(e,t,n){n(574),e.exports=n(21).Object.getOwnPropertyDescriptors},function(e,t,n){var r=n(24),o=n(575),a=n(52),i=n(108),c=n(298);r(r.S,"Object",{getOwnPropertyDescriptors:function(e){for(var t,n,r=a(e),s=i.f,u=o(r),l={},p=0;u.length>p;)void 0!==(n=s(r,t=u[p++]))&&c(l,t,n);return l}})},function(e,t,n){var r=n(185),o=n(125),a=n(36),i=n(28).Reflect;e.exports=i&&i.ownKeys||function(e){var t=r.f(a(e)),n=o.f;return n?t.concat(n(e)):t}},function(e,t,n){n(577);var r=n(21).Object;e.exports=function(e,t){return r.getOwnPropertyDescriptor(e,t)}},function(e,t,n){var r=n(52),o=n(108).f;n(176)("getOwnPropertyDescriptor",(function(){return function(e,t){return o(r(e),t)}}))},function(e,t,n){n(290),e.exports=n(21).Object.getOwnPropertySymbols},function(e,t,n){var r=n(11);e.exports=function(e,t){if(null==e)return{};var n,o,a={},i=r(e);for(o=0;o<i.length;o++)n=i[o],t.indexOf(n)>=0||(a[n]=e[n]);return a}},function(e,t,n){n(581),e.exports=n(21).Date.now},function(e,t,n){var r=n(24);r(r.S,"Date",{now:function(){return(new Date).getTime()}})},function(e,t,n){n(126),n(81),n(83),n(583),n(586),n(587),e.exports=n(21).Promise},function(e,t,n){"use strict";var r,o,a,i,c=n(102),s=n(28),u=n(53),l=n(127),p=n(24),f=n(30),d=n(70),h=n(139),m=n(88),v=n(329),g=n(330).set,y=n(584)(),b=n(200),E=n(332),x=n(585),S=n(333),w=s.TypeError,j=s.process,_=j&&j.versions,O=_&&_.v8||"",C=s.Promise,A="process"==l(j),k=function(){},P=o=b.f,I=!!function(){try{var e=C.resolve(1),t=(e.constructor={})[n(32)("species")]=function(e){e(k,k)};return(A||"function"==typeof PromiseRejectionEvent)&&e.then(k)instanceof t&&0!==O.indexOf("6.6")&&-1===x.indexOf("Chrome/66")}catch(e){}}(),T=function(e){var t;return!(!f(e)||"function"!=typeof(t=e.then))&&t},N=function(e,t){if(!e._n){e._n=!0;var n=e._c;y((function(){for(var r=e._v,o=1==e._s,a=0,i=function(t){var n,a,i,c=o?t.ok:t.fail,s=t.resolve,u=t.reject,l=t.domain;try{c?(o||(2==e._h&&D(e),e._h=1),!0===c?n=r:(l&&l.enter(),n=c(r),l&&(l.exit(),i=!0)),n===t.promise?u(w("Promise-chain cycle")):(a=T(n))?a.call(n,s,u):s(n)):u(r)}catch(e){l&&!i&&l.exit(),u(e)}};n.length>a;)i(n[a++]);e._c=[],e._n=!1,t&&!e._h&&R(e)}))}},R=function(e){g.call(s,(function(){var t,n,r,o=e._v,a=M(e);if(a&&(t=E((function(){A?j.emit("unhandledRejection",o,e):(n=s.onunhandledrejection)?n({promise:e,reason:o}):(r=s.console)&&r.error&&r.error("Unhandled promise rejection",o)})),e._h=A||M(e)?2:1),e._a=void 0,a&&t.e)throw t.v}))},M=function(e){return 1!==e._h&&0===(e._a||e._c).length},D=function(e){g.call(s,(function(){var t;A?j.emit("rejectionHandled",e):(t=s.onrejectionhandled)&&t({promise:e,reason:e._v})}))},q=function(e){var t=this;t._d||(t._d=!0,(t=t._w||t)._v=e,t._s=2,t._a||(t._a=t._c.slice()),N(t,!0))},L=function(e){var t,n=this;if(!n._d){n._d=!0,n=n._w||n;try{if(n===e)throw w("Promise can't be resolved itself");(t=T(e))?y((function(){var r={_w:n,_d:!1};try{t.call(e,u(L,r,1),u(q,r,1))}catch(e){q.call(r,e)}})):(n._v=e,n._s=1,N(n,!1))}catch(e){q.call({_w:n,_d:!1},e)}}};I||(C=function(e){h(this,C,"Promise","_h"),d(e),r.call(this);try{e(u(L,this,1),u(q,this,1))}catch(e){q.call(this,e)}},(r=function(e){this._c=[],this._a=void 0,this._s=0,this._d=!1,this._v=void 0,this._h=0,this._n=!1}).prototype=n(140)(C.prototype,{then:function(e,t){var n=P(v(this,C));return n.ok="function"!=typeof e||e,n.fail="function"==typeof t&&t,n.domain=A?j.domain:void 0,this._c.push(n),this._a&&this._a.push(n),this._s&&N(this,!1),n.promise},catch:function(e){return this.then(void 0,e)}}),a=function(){var e=new r;this.promise=e,this.resolve=u(L,e,1),this.reject=u(q,e,1)},b.f=P=function(e){return e===C||e===i?new a(e):o(e)}),p(p.G+p.W+p.F*!I,{Promise:C}),n(105)(C,"Promise"),n(334)("Promise"),i=n(21).Promise,p(p.S+p.F*!I,"Promise",{reject:function(e){var t=P(this);return(0,t.reject)(e),t.promise}}),p(p.S+p.F*(c||!I),"Promise",{resolve:function(e){return S(c&&this===i?C:this,e)}}),p(p.S+p.F*!(I&&n(299)((function(e){C.all(e).catch(k)}))),"Promise",{all:function(e){var t=this,n=P(t),r=n.resolve,o=n.reject,a=E((function(){var n=[],a=0,i=1;m(e,!1,(function(e){var c=a++,s=!1;n.push(void 0),i++,t.resolve(e).then((function(e){s||(s=!0,n[c]=e,--i||r(n))}),o)})),--i||r(n)}));return a.e&&o(a.v),n.promise},race:function(e){var t=this,n=P(t),r=n.reject,o=E((function(){m(e,!1,(function(e){t.resolve(e).then(n.resolve,r)}))}));return o.e&&r(o.v),n.promise}})},function(e,t,n){var r=n(28),o=n(330).set,a=r.MutationObserver||r.WebKitMutationObserver,i=r.process,c=r.Promise,s="process"==n(101)(i);e.exports=function(){var e,t,n,u=function(){var r,o;for(s&&(r=i.domain)&&r.exit();e;){o=e.fn,e=e.next;try{o()}catch(r){throw e?n():t=void 0,r}}t=void 0,r&&r.enter()};if(s)n=function(){i.nextTick(u)};else if(!a||r.navigator&&r.navigator.standalone)if(c&&c.resolve){var l=c.resolve(void 0);n=function(){l.then(u)}}else n=function(){o.call(r,u)};else{var p=!0,f=document.createTextNode("");new a(u).observe(f,{characterData:!0}),n=function(){f.data=p=!p}}return function(r){var o={fn:r,next:void 0};t&&(t.next=o),e||(e=o,n()),t=o}}},function(e,t,n){var r=n(28).navigator;e.exports=r&&r.userAgent||""},function(e,t,n){"use strict";var r=n(24),o=n(21),a=n(28),i=n(329),c=n(333);r(r.P+r.R,"Promise",{finally:function(e){var t=i(this,o.Promise||a.Promise),n="function"==typeof e;return this.then(n?function(n){return c(t,e()).then((function(){return n}))}:e,n?function(n){return c(t,e()).then((function(){throw n}))}:e)}})},function(e,t,n){"use strict";var r=n(24),o=n(200),a=n(332);r(r.S,"Promise",{try:function(e){var t=o.f(this),n=a(e);return(n.e?t.reject:t.resolve)(n.v),t.promise}})},function(e,t){e.exports=require("regenerator-runtime")},function(e,t,n){var r=n(49);e.exports=function(){return r.Date.now()}},function(e,t,n){n(591);var r=n(21).Object;e.exports=function(e,t){return r.create(e,t)}},function(e,t,n){var r=n(24);r(r.S,"Object",{create:n(104)})},function(e,t,n){var r=n(338);function o(t,n){return e.exports=o=r||function(e,t){return e.__proto__=t,e},o(t,n)}e.exports=o},function(e,t,n){n(594),e.exports=n(21).Object.setPrototypeOf},function(e,t,n){var r=n(24);r(r.S,"Object",{setPrototypeOf:n(595).set})},function(e,t,n){var r=n(30),o=n(36),a=function(e,t){if(o(e),!r(t)&&null!==t)throw TypeError(t+": can't set as prototype!")};e.exports={set:Object.setPrototypeOf||("__proto__"in{}?function(e,t,r){try{(r=n(53)(Function.call,n(108).f(Object.prototype,"__proto__").set,2))(e,[]),t=!(e instanceof Array)}catch(e){t=!0}return function(e,n){return a(e,n),t?e.__proto__=n:r(e,n),e}}({},!1):void 0),check:a}},function(e,t,n){n(597),e.exports=n(21).Reflect.construct},function(e,t,n){var r=n(24),o=n(104),a=n(70),i=n(36),c=n(30),s=n(63),u=n(598),l=(n(28).Reflect||{}).construct,p=s((function(){function e(){}return!(l((function(){}),[],e)instanceof e)})),f=!s((function(){l((function(){}))}));r(r.S+r.F*(p||f),"Reflect",{construct:function(e,t){a(e),i(t);var n=arguments.length<3?e:a(arguments[2]);if(f&&!p)return l(e,t,n);if(e==n){switch(t.length){case 0:return new e;case 1:return new e(t[0]);case 2:return new e(t[0],t[1]);case 3:return new e(t[0],t[1],t[2]);case 4:return new e(t[0],t[1],t[2],t[3])}var r=[null];return r.push.apply(r,t),new(u.apply(e,r))}var s=n.prototype,d=o(c(s)?s:Object.prototype),h=Function.apply.call(e,d,t);return c(h)?h:d}})},function(e,t,n){"use strict";var r=n(70),o=n(30),a=n(331),i=[].slice,c={},s=function(e,t,n){if(!(t in c)){for(var r=[],o=0;o<t;o++)r[o]="a["+o+"]";c[t]=Function("F,a","return new F("+r.join(",")+")")}return c[t](e,n)};e.exports=Function.bind||function(e){var t=r(this),n=i.call(arguments,1),c=function(){var r=n.concat(i.call(arguments));return this instanceof c?s(t,r.length,r):a(t,r,e)};return o(t.prototype)&&(c.prototype=t.prototype),c}},function(e,t,n){e.exports=n(600)},function(e,t,n){n(601),e.exports=n(21).Object.getPrototypeOf},function(e,t,n){var r=n(69),o=n(181);n(176)("getPrototypeOf",(function(){return function(e){return o(r(e))}}))},function(e,t,n){var r=n(339);e.exports=function(){if("undefined"==typeof Reflect||!r)return!1;if(r.sham)return!1;if("function"==typeof Proxy)return!0;try{return Date.prototype.toString.call(r(Date,[],(function(){}))),!0}catch(e){return!1}}},function(e,t,n){var r=n(15),o=n(8);e.exports=function(e,t){return!t||"object"!==r(t)&&"function"!=typeof t?o(e):t}},function(e,t){e.exports=function(e,t){for(var n=-1,r=null==e?0:e.length;++n<r&&!1!==t(e[n],n,e););return e}},function(e,t,n){var r=n(89),o=n(73);e.exports=function(e,t){return e&&r(t,o(t),e)}},function(e,t,n){var r=n(89),o=n(341);e.exports=function(e,t){return e&&r(t,o(t),e)}},function(e,t,n){var r=n(40),o=n(136),a=n(608),i=Object.prototype.hasOwnProperty;e.exports=function(e){if(!r(e))return a(e);var t=o(e),n=[];for(var c in e)("constructor"!=c||!t&&i.call(e,c))&&n.push(c);return n}},function(e,t){e.exports=function(e){var t=[];if(null!=e)for(var n in Object(e))t.push(n);return t}},function(e,t,n){(function(e){var r=n(49),o=t&&!t.nodeType&&t,a=o&&"object"==typeof e&&e&&!e.nodeType&&e,i=a&&a.exports===o?r.Buffer:void 0,c=i?i.allocUnsafe:void 0;e.exports=function(e,t){if(t)return e.slice();var n=e.length,r=c?c(n):new e.constructor(n);return e.copy(r),r}}).call(this,n(194)(e))},function(e,t){e.exports=function(e,t){var n=-1,r=e.length;for(t||(t=Array(r));++n<r;)t[n]=e[n];return t}},function(e,t,n){var r=n(89),o=n(191);e.exports=function(e,t){return r(e,o(e),t)}},function(e,t,n){var r=n(89),o=n(342);e.exports=function(e,t){return r(e,o(e),t)}},function(e,t){var n=Object.prototype.hasOwnProperty;e.exports=function(e){var t=e.length,r=new e.constructor(t);return t&&"string"==typeof e[0]&&n.call(e,"index")&&(r.index=e.index,r.input=e.input),r}},function(e,t,n){var r=n(202),o=n(615),a=n(616),i=n(617),c=n(618);e.exports=function(e,t,n){var s=e.constructor;switch(t){case"[object ArrayBuffer]":return r(e);case"[object Boolean]":case"[object Date]":return new s(+e);case"[object DataView]":return o(e,n);case"[object Float32Array]":case"[object Float64Array]":case"[object Int8Array]":case"[object Int16Array]":case"[object Int32Array]":case"[object Uint8Array]":case"[object Uint8ClampedArray]":case"[object Uint16Array]":case"[object Uint32Array]":return c(e,n);case"[object Map]":return new s;case"[object Number]":case"[object String]":return new s(e);case"[object RegExp]":return a(e);case"[object Set]":return new s;case"[object Symbol]":return i(e)}}},function(e,t,n){var r=n(202);e.exports=function(e,t){var n=t?r(e.buffer):e.buffer;return new e.constructor(n,e.byteOffset,e.byteLength)}},function(e,t){var n=/\w*$/;e.exports=function(e){var t=new e.constructor(e.source,n.exec(e));return t.lastIndex=e.lastIndex,t}},function(e,t,n){var r=n(84),o=r?r.prototype:void 0,a=o?o.valueOf:void 0;e.exports=function(e){return a?Object(a.call(e)):{}}},function(e,t,n){var r=n(202);e.exports=function(e,t){var n=t?r(e.buffer):e.buffer;return new e.constructor(n,e.byteOffset,e.length)}},function(e,t,n){var r=n(620),o=n(201),a=n(136);e.exports=function(e){return"function"!=typeof e.constructor||a(e)?{}:r(o(e))}},function(e,t,n){var r=n(40),o=Object.create,a=function(){function e(){}return function(t){if(!r(t))return{};if(o)return o(t);e.prototype=t;var n=new e;return e.prototype=void 0,n}}();e.exports=a},function(e,t,n){var r=n(622),o=n(196),a=n(197),i=a&&a.isMap,c=i?o(i):r;e.exports=c},function(e,t,n){var r=n(137),o=n(54);e.exports=function(e){return o(e)&&"[object Map]"==r(e)}},function(e,t,n){var r=n(624),o=n(196),a=n(197),i=a&&a.isSet,c=i?o(i):r;e.exports=c},function(e,t,n){var r=n(137),o=n(54);e.exports=function(e){return o(e)&&"[object Set]"==r(e)}},function(e,t,n){var r=n(86),o=n(626),a=n(627),i=n(87);e.exports=function(e,t){return t=r(t,e),null==(e=a(e,t))||delete e[i(o(t))]}},function(e,t){e.exports=function(e){var t=null==e?0:e.length;return t?e[t-1]:void 0}},function(e,t,n){var r=n(138),o=n(305);e.exports=function(e,t){return t.length<2?e:r(e,o(t,0,-1))}},function(e,t,n){var r=n(218);e.exports=function(e){return r(e)?void 0:e}},function(e,t,n){var r=n(630);e.exports=function(e){return(null==e?0:e.length)?r(e,1):[]}},function(e,t,n){var r=n(190),o=n(631);e.exports=function e(t,n,a,i,c){var s=-1,u=t.length;for(a||(a=o),c||(c=[]);++s<u;){var l=t[s];n>0&&a(l)?n>1?e(l,n-1,a,i,c):r(c,l):i||(c[c.length]=l)}return c}},function(e,t,n){var r=n(84),o=n(192),a=n(33),i=r?r.isConcatSpreadable:void 0;e.exports=function(e){return a(e)||o(e)||!!(i&&e&&e[i])}},function(e,t){e.exports=function(e,t,n){switch(n.length){case 0:return e.call(t);case 1:return e.call(t,n[0]);case 2:return e.call(t,n[0],n[1]);case 3:return e.call(t,n[0],n[1],n[2])}return e.apply(t,n)}},function(e,t,n){var r=n(634),o=n(337),a=n(199),i=o?function(e,t){return o(e,"toString",{configurable:!0,enumerable:!1,value:r(t),writable:!0})}:a;e.exports=i},function(e,t){e.exports=function(e){return function(){return e}}},function(e,t){var n=Date.now;e.exports=function(e){var t=0,r=0;return function(){var o=n(),a=16-(o-r);if(r=o,a>0){if(++t>=800)return arguments[0]}else t=0;return e.apply(void 0,arguments)}}},function(e,t,n){n(637),e.exports=n(21).Object.entries},function(e,t,n){var r=n(24),o=n(638)(!0);r(r.S,"Object",{entries:function(e){return o(e)}})},function(e,t,n){var r=n(39),o=n(80),a=n(52),i=n(107).f;e.exports=function(e){return function(t){for(var n,c=a(t),s=o(c),u=s.length,l=0,p=[];u>l;)n=s[l++],r&&!i.call(c,n)||p.push(e?[n,c[n]]:c[n]);return p}}},function(e,t){!function(e){!function(t){var n="URLSearchParams"in e,r="Symbol"in e&&"iterator"in Symbol,o="FileReader"in e&&"Blob"in e&&function(){try{return new Blob,!0}catch(e){return!1}}(),a="FormData"in e,i="ArrayBuffer"in e;if(i)var c=["[object Int8Array]","[object Uint8Array]","[object Uint8ClampedArray]","[object Int16Array]","[object Uint16Array]","[object Int32Array]","[object Uint32Array]","[object Float32Array]","[object Float64Array]"],s=ArrayBuffer.isView||function(e){return e&&c.indexOf(Object.prototype.toString.call(e))>-1};function u(e){if("string"!=typeof e&&(e=String(e)),/[^a-z0-9\-#$%&'*+.^_`|~]/i.test(e))throw new TypeError("Invalid character in header field name");return e.toLowerCase()}function l(e){return"string"!=typeof e&&(e=String(e)),e}function p(e){var t={next:function(){var t=e.shift();return{done:void 0===t,value:t}}};return r&&(t[Symbol.iterator]=function(){return t}),t}function f(e){this.map={},e instanceof f?e.forEach((function(e,t){this.append(t,e)}),this):Array.isArray(e)?e.forEach((function(e){this.append(e[0],e[1])}),this):e&&Object.getOwnPropertyNames(e).forEach((function(t){this.append(t,e[t])}),this)}function d(e){if(e.bodyUsed)return Promise.reject(new TypeError("Already read"));e.bodyUsed=!0}function h(e){return new Promise((function(t,n){e.onload=function(){t(e.result)},e.onerror=function(){n(e.error)}}))}function m(e){var t=new FileReader,n=h(t);return t.readAsArrayBuffer(e),n}function v(e){if(e.slice)return e.slice(0);var t=new Uint8Array(e.byteLength);return t.set(new Uint8Array(e)),t.buffer}function g(){return this.bodyUsed=!1,this._initBody=function(e){var t;this._bodyInit=e,e?"string"==typeof e?this._bodyText=e:o&&Blob.prototype.isPrototypeOf(e)?this._bodyBlob=e:a&&FormData.prototype.isPrototypeOf(e)?this._bodyFormData=e:n&&URLSearchParams.prototype.isPrototypeOf(e)?this._bodyText=e.toString():i&&o&&((t=e)&&DataView.prototype.isPrototypeOf(t))?(this._bodyArrayBuffer=v(e.buffer),this._bodyInit=new Blob([this._bodyArrayBuffer])):i&&(ArrayBuffer.prototype.isPrototypeOf(e)||s(e))?this._bodyArrayBuffer=v(e):this._bodyText=e=Object.prototype.toString.call(e):this._bodyText="",this.headers.get("content-type")||("string"==typeof e?this.headers.set("content-type","text/plain;charset=UTF-8"):this._bodyBlob&&this._bodyBlob.type?this.headers.set("content-type",this._bodyBlob.type):n&&URLSearchParams.prototype.isPrototypeOf(e)&&this.headers.set("content-type","application/x-www-form-urlencoded;charset=UTF-8"))},o&&(this.blob=function(){var e=d(this);if(e)return e;if(this._bodyBlob)return Promise.resolve(this._bodyBlob);if(this._bodyArrayBuffer)return Promise.resolve(new Blob([this._bodyArrayBuffer]));if(this._bodyFormData)throw new Error("could not read FormData body as blob");return Promise.resolve(new Blob([this._bodyText]))},this.arrayBuffer=function(){return this._bodyArrayBuffer?d(this)||Promise.resolve(this._bodyArrayBuffer):this.blob().then(m)}),this.text=function(){var e,t,n,r=d(this);if(r)return r;if(this._bodyBlob)return e=this._bodyBlob,t=new FileReader,n=h(t),t.readAsText(e),n;if(this._bodyArrayBuffer)return Promise.resolve(function(e){for(var t=new Uint8Array(e),n=new Array(t.length),r=0;r<t.length;r++)n[r]=String.fromCharCode(t[r]);return n.join("")}(this._bodyArrayBuffer));if(this._bodyFormData)throw new Error("could not read FormData body as text");return Promise.resolve(this._bodyText)},a&&(this.formData=function(){return this.text().then(E)}),this.json=function(){return this.text().then(JSON.parse)},this}f.prototype.append=function(e,t){e=u(e),t=l(t);var n=this.map[e];this.map[e]=n?n+", "+t:t},f.prototype.delete=function(e){delete this.map[u(e)]},f.prototype.get=function(e){return e=u(e),this.has(e)?this.map[e]:null},f.prototype.has=function(e){return this.map.hasOwnProperty(u(e))},f.prototype.set=function(e,t){this.map[u(e)]=l(t)},f.prototype.forEach=function(e,t){for(var n in this.map)this.map.hasOwnProperty(n)&&e.call(t,this.map[n],n,this)},f.prototype.keys=function(){var e=[];return this.forEach((function(t,n){e.push(n)})),p(e)},f.prototype.values=function(){var e=[];return this.forEach((function(t){e.push(t)})),p(e)},f.prototype.entries=function(){var e=[];return this.forEach((function(t,n){e.push([n,t])})),p(e)},r&&(f.prototype[Symbol.iterator]=f.prototype.entries);var y=["DELETE","GET","HEAD","OPTIONS","POST","PUT"];function b(e,t){var n,r,o=(t=t||{}).body;if(e instanceof b){if(e.bodyUsed)throw new TypeError("Already read");this.url=e.url,this.credentials=e.credentials,t.headers||(this.headers=new f(e.headers)),this.method=e.method,this.mode=e.mode,this.signal=e.signal,o||null==e._bodyInit||(o=e._bodyInit,e.bodyUsed=!0)}else this.url=String(e);if(this.credentials=t.credentials||this.credentials||"same-origin",!t.headers&&this.headers||(this.headers=new f(t.headers)),this.method=(n=t.method||this.method||"GET",r=n.toUpperCase(),y.indexOf(r)>-1?r:n),this.mode=t.mode||this.mode||null,this.signal=t.signal||this.signal,this.referrer=null,("GET"===this.method||"HEAD"===this.method)&&o)throw new TypeError("Body not allowed for GET or HEAD requests");this._initBody(o)}function E(e){var t=new FormData;return e.trim().split("&").forEach((function(e){if(e){var n=e.split("="),r=n.shift().replace(/\+/g," "),o=n.join("=").replace(/\+/g," ");t.append(decodeURIComponent(r),decodeURIComponent(o))}})),t}function x(e,t){t||(t={}),this.type="default",this.status=void 0===t.status?200:t.status,this.ok=this.status>=200&&this.status<300,this.statusText="statusText"in t?t.statusText:"OK",this.headers=new f(t.headers),this.url=t.url||"",this._initBody(e)}b.prototype.clone=function(){return new b(this,{body:this._bodyInit})},g.call(b.prototype),g.call(x.prototype),x.prototype.clone=function(){return new x(this._bodyInit,{status:this.status,statusText:this.statusText,headers:new f(this.headers),url:this.url})},x.error=function(){var e=new x(null,{status:0,statusText:""});return e.type="error",e};var S=[301,302,303,307,308];x.redirect=function(e,t){if(-1===S.indexOf(t))throw new RangeError("Invalid status code");return new x(null,{status:t,headers:{location:e}})},t.DOMException=e.DOMException;try{new t.DOMException}catch(e){t.DOMException=function(e,t){this.message=e,this.name=t;var n=Error(e);this.stack=n.stack},t.DOMException.prototype=Object.create(Error.prototype),t.DOMException.prototype.constructor=t.DOMException}function w(e,n){return new Promise((function(r,a){var i=new b(e,n);if(i.signal&&i.signal.aborted)return a(new t.DOMException("Aborted","AbortError"));var c=new XMLHttpRequest;function s(){c.abort()}c.onload=function(){var e,t,n={status:c.status,statusText:c.statusText,headers:(e=c.getAllResponseHeaders()||"",t=new f,e.replace(/\r?\n[\t ]+/g," ").split(/\r?\n/).forEach((function(e){var n=e.split(":"),r=n.shift().trim();if(r){var o=n.join(":").trim();t.append(r,o)}})),t)};n.url="responseURL"in c?c.responseURL:n.headers.get("X-Request-URL");var o="response"in c?c.response:c.responseText;r(new x(o,n))},c.onerror=function(){a(new TypeError("Network request failed"))},c.ontimeout=function(){a(new TypeError("Network request failed"))},c.onabort=function(){a(new t.DOMException("Aborted","AbortError"))},c.open(i.method,i.url,!0),"include"===i.credentials?c.withCredentials=!0:"omit"===i.credentials&&(c.withCredentials=!1),"responseType"in c&&o&&(c.responseType="blob"),i.headers.forEach((function(e,t){c.setRequestHeader(t,e)})),i.signal&&(i.signal.addEventListener("abort",s),c.onreadystatechange=function(){4===c.readyState&&i.signal.removeEventListener("abort",s)}),c.send(void 0===i._bodyInit?null:i._bodyInit)}))}w.polyfill=!0,e.fetch||(e.fetch=w,e.Headers=f,e.Request=b,e.Response=x),t.Headers=f,t.Request=b,t.Response=x,t.fetch=w}({})}("undefined"!=typeof self?self:this)},function(e,t,n){var r=n(641),o=n(321);e.exports=function(e,t){return r(e,t,(function(t,n){return o(e,n)}))}},function(e,t,n){var r=n(138),o=n(335),a=n(86);e.exports=function(e,t,n){for(var i=-1,c=t.length,s={};++i<c;){var u=t[i],l=r(e,u);n(l,u)&&o(s,a(u,e),l)}return s}},function(e,t,n){e.exports=n(643)},function(e,t,n){n(644),e.exports=n(21).Reflect.get},function(e,t,n){var r=n(108),o=n(181),a=n(51),i=n(24),c=n(30),s=n(36);i(i.S,"Reflect",{get:function e(t,n){var i,u,l=arguments.length<3?t:arguments[2];return s(t)===l?t[n]:(i=r.f(t,n))?a(i,"value")?i.value:void 0!==i.get?i.get.call(l):void 0:c(u=o(t))?e(u,n,l):void 0}})},function(e,t,n){var r=n(142);e.exports=function(e,t){for(;!Object.prototype.hasOwnProperty.call(e,t)&&null!==(e=r(e)););return e}},function(e,t,n){n(126),n(83),n(647),n(651),n(652),e.exports=n(21).WeakMap},function(e,t,n){"use strict";var r,o=n(28),a=n(203)(0),i=n(180),c=n(106),s=n(291),u=n(650),l=n(30),p=n(109),f=n(109),d=!o.ActiveXObject&&"ActiveXObject"in o,h=c.getWeak,m=Object.isExtensible,v=u.ufstore,g=function(e){return function(){return e(this,arguments.length>0?arguments[0]:void 0)}},y={get:function(e){if(l(e)){var t=h(e);return!0===t?v(p(this,"WeakMap")).get(e):t?t[this._i]:void 0}},set:function(e,t){return u.def(p(this,"WeakMap"),e,t)}},b=e.exports=n(347)("WeakMap",g,y,u,!0,!0);f&&d&&(s((r=u.getConstructor(g,"WeakMap")).prototype,y),c.NEED=!0,a(["delete","has","get","set"],(function(e){var t=b.prototype,n=t[e];i(t,e,(function(t,o){if(l(t)&&!m(t)){this._f||(this._f=new r);var a=this._f[e](t,o);return"set"==e?this:a}return n.call(this,t,o)}))})))},function(e,t,n){var r=n(649);e.exports=function(e,t){return new(r(e))(t)}},function(e,t,n){var r=n(30),o=n(184),a=n(32)("species");e.exports=function(e){var t;return o(e)&&("function"!=typeof(t=e.constructor)||t!==Array&&!o(t.prototype)||(t=void 0),r(t)&&null===(t=t[a])&&(t=void 0)),void 0===t?Array:t}},function(e,t,n){"use strict";var r=n(140),o=n(106).getWeak,a=n(36),i=n(30),c=n(139),s=n(88),u=n(203),l=n(51),p=n(109),f=u(5),d=u(6),h=0,m=function(e){return e._l||(e._l=new v)},v=function(){this.a=[]},g=function(e,t){return f(e.a,(function(e){return e[0]===t}))};v.prototype={get:function(e){var t=g(this,e);if(t)return t[1]},has:function(e){return!!g(this,e)},set:function(e,t){var n=g(this,e);n?n[1]=t:this.a.push([e,t])},delete:function(e){var t=d(this.a,(function(t){return t[0]===e}));return~t&&this.a.splice(t,1),!!~t}},e.exports={getConstructor:function(e,t,n,a){var u=e((function(e,r){c(e,u,t,"_i"),e._t=t,e._i=h++,e._l=void 0,null!=r&&s(r,n,e[a],e)}));return r(u.prototype,{delete:function(e){if(!i(e))return!1;var n=o(e);return!0===n?m(p(this,t)).delete(e):n&&l(n,this._i)&&delete n[this._i]},has:function(e){if(!i(e))return!1;var n=o(e);return!0===n?m(p(this,t)).has(e):n&&l(n,this._i)}}),u},def:function(e,t,n){var r=o(a(t),!0);return!0===r?m(e).set(t,n):r[e._i]=n,e},ufstore:m}},function(e,t,n){n(348)("WeakMap")},function(e,t,n){n(349)("WeakMap")},function(e,t){e.exports=function(e,t,n){return e==e&&(void 0!==n&&(e=e<=n?e:n),void 0!==t&&(e=e>=t?e:t)),e}},function(e,t,n){var r=n(655),o=n(325);e.exports=function(e){return r((function(t,n){var r=-1,a=n.length,i=a>1?n[a-1]:void 0,c=a>2?n[2]:void 0;for(i=e.length>3&&"function"==typeof i?(a--,i):void 0,c&&o(n[0],n[1],c)&&(i=a<3?void 0:i,a=1),t=Object(t);++r<a;){var s=n[r];s&&e(t,s,r,i)}return t}))}},function(e,t,n){var r=n(199),o=n(345),a=n(346);e.exports=function(e,t){return a(o(e,t,r),e+"")}},function(e,t,n){n(126),n(81),n(83),n(657),n(659),n(662),n(663),e.exports=n(21).Map},function(e,t,n){"use strict";var r=n(658),o=n(109);e.exports=n(347)("Map",(function(e){return function(){return e(this,arguments.length>0?arguments[0]:void 0)}}),{get:function(e){var t=r.getEntry(o(this,"Map"),e);return t&&t.v},set:function(e,t){return r.def(o(this,"Map"),0===e?0:e,t)}},r,!0)},function(e,t,n){"use strict";var r=n(48).f,o=n(104),a=n(140),i=n(53),c=n(139),s=n(88),u=n(179),l=n(289),p=n(334),f=n(39),d=n(106).fastKey,h=n(109),m=f?"_s":"size",v=function(e,t){var n,r=d(t);if("F"!==r)return e._i[r];for(n=e._f;n;n=n.n)if(n.k==t)return n};e.exports={getConstructor:function(e,t,n,u){var l=e((function(e,r){c(e,l,t,"_i"),e._t=t,e._i=o(null),e._f=void 0,e._l=void 0,e[m]=0,null!=r&&s(r,n,e[u],e)}));return a(l.prototype,{clear:function(){for(var e=h(this,t),n=e._i,r=e._f;r;r=r.n)r.r=!0,r.p&&(r.p=r.p.n=void 0),delete n[r.i];e._f=e._l=void 0,e[m]=0},delete:function(e){var n=h(this,t),r=v(n,e);if(r){var o=r.n,a=r.p;delete n._i[r.i],r.r=!0,a&&(a.n=o),o&&(o.p=a),n._f==r&&(n._f=o),n._l==r&&(n._l=a),n[m]--}return!!r},forEach:function(e){h(this,t);for(var n,r=i(e,arguments.length>1?arguments[1]:void 0,3);n=n?n.n:this._f;)for(r(n.v,n.k,this);n&&n.r;)n=n.p},has:function(e){return!!v(h(this,t),e)}}),f&&r(l.prototype,"size",{get:function(){return h(this,t)[m]}}),l},def:function(e,t,n){var r,o,a=v(e,t);return a?a.v=n:(e._l=a={i:o=d(t,!0),k:t,v:n,p:r=e._l,n:void 0,r:!1},e._f||(e._f=a),r&&(r.n=a),e[m]++,"F"!==o&&(e._i[o]=a)),e},getEntry:v,setStrong:function(e,t,n){u(e,t,(function(e,n){this._t=h(e,t),this._k=n,this._l=void 0}),(function(){for(var e=this._k,t=this._l;t&&t.r;)t=t.p;return this._t&&(this._l=t=t?t.n:this._t._f)?l(0,"keys"==e?t.k:"values"==e?t.v:[t.k,t.v]):(this._t=void 0,l(1))}),n?"entries":"values",!n,!0),p(t)}}},function(e,t,n){var r=n(24);r(r.P+r.R,"Map",{toJSON:n(660)("Map")})},function(e,t,n){var r=n(127),o=n(661);e.exports=function(e){return function(){if(r(this)!=e)throw TypeError(e+"#toJSON isn't generic");return o(this)}}},function(e,t,n){var r=n(88);e.exports=function(e,t){var n=[];return r(e,!1,n.push,n,t),n}},function(e,t,n){n(348)("Map")},function(e,t,n){n(349)("Map")},function(e,t,n){var r={"./all.js":260,"./auth/actions.js":58,"./auth/index.js":222,"./auth/reducers.js":223,"./auth/selectors.js":224,"./auth/spec-wrap-actions.js":225,"./configs/actions.js":94,"./configs/helpers.js":110,"./configs/index.js":262,"./configs/reducers.js":230,"./configs/selectors.js":229,"./configs/spec-actions.js":228,"./deep-linking/helpers.js":112,"./deep-linking/index.js":231,"./deep-linking/layout.js":232,"./deep-linking/operation-tag-wrapper.jsx":234,"./deep-linking/operation-wrapper.jsx":233,"./download-url.js":227,"./err/actions.js":42,"./err/error-transformers/hook.js":76,"./err/error-transformers/transformers/not-of-type.js":208,"./err/error-transformers/transformers/parameter-oneof.js":209,"./err/index.js":206,"./err/reducers.js":207,"./err/selectors.js":210,"./filter/index.js":235,"./filter/opsFilter.js":236,"./layout/actions.js":65,"./layout/index.js":211,"./layout/reducers.js":212,"./layout/selectors.js":213,"./logs/index.js":220,"./oas3/actions.js":43,"./oas3/auth-extensions/wrap-selectors.js":240,"./oas3/components/callbacks.jsx":243,"./oas3/components/http-auth.jsx":249,"./oas3/components/index.js":242,"./oas3/components/operation-link.jsx":245,"./oas3/components/operation-servers.jsx":250,"./oas3/components/request-body-editor.jsx":248,"./oas3/components/request-body.jsx":244,"./oas3/components/servers-container.jsx":247,"./oas3/components/servers.jsx":246,"./oas3/helpers.jsx":23,"./oas3/index.js":238,"./oas3/reducers.js":259,"./oas3/selectors.js":258,"./oas3/spec-extensions/selectors.js":241,"./oas3/spec-extensions/wrap-selectors.js":239,"./oas3/wrap-components/auth-item.jsx":253,"./oas3/wrap-components/index.js":251,"./oas3/wrap-components/json-schema-string.jsx":257,"./oas3/wrap-components/markdown.jsx":252,"./oas3/wrap-components/model.jsx":256,"./oas3/wrap-components/online-validator-badge.js":255,"./oas3/wrap-components/version-stamp.jsx":254,"./on-complete/index.js":237,"./samples/fn.js":93,"./samples/index.js":219,"./spec/actions.js":26,"./spec/index.js":214,"./spec/reducers.js":215,"./spec/selectors.js":56,"./spec/wrap-actions.js":216,"./swagger-js/configs-wrap-actions.js":221,"./swagger-js/index.js":261,"./util/index.js":226,"./view/index.js":217,"./view/root-injects.jsx":111};function o(e){var t=a(e);return n(t)}function a(e){if(!n.o(r,e)){var t=new Error("Cannot find module '"+e+"'");throw t.code="MODULE_NOT_FOUND",t}return r[e]}o.keys=function(){return Object.keys(r)},o.resolve=a,e.exports=o,o.id=664},function(e,t,n){"use strict";n.r(t),n.d(t,"default",(function(){return ur}));var r={};n.r(r),n.d(r,"Container",(function(){return Yt})),n.d(r,"Col",(function(){return Gt})),n.d(r,"Row",(function(){return Zt})),n.d(r,"Button",(function(){return Xt})),n.d(r,"TextArea",(function(){return Qt})),n.d(r,"Input",(function(){return en})),n.d(r,"Select",(function(){return tn})),n.d(r,"Link",(function(){return nn})),n.d(r,"Collapse",(function(){return on}));var o={};n.r(o),n.d(o,"JsonSchemaForm",(function(){return Hn})),n.d(o,"JsonSchema_string",(function(){return $n})),n.d(o,"JsonSchema_array",(function(){return Yn})),n.d(o,"JsonSchemaArrayItemText",(function(){return Kn})),n.d(o,"JsonSchemaArrayItemFile",(function(){return Gn})),n.d(o,"JsonSchema_boolean",(function(){return Zn})),n.d(o,"JsonSchema_object",(function(){return Xn}));var a=n(22),i=n.n(a),c=n(11),s=n.n(c),u=n(15),l=n.n(u),p=n(50),f=n.n(p),d=n(10),h=n.n(d),m=n(2),v=n.n(m),g=n(16),y=n.n(g),b=n(4),E=n.n(b),x=n(3),S=n.n(x),w=n(0),j=n.n(w),_=n(113),O=n(1),C=n.n(O),A=n(351),k=n(92),P=n.n(k),I=n(143),T=n.n(I),N=n(42),R=n(17),M=n.n(R),D=n(5),q=function(e){return e};var L=function(){function e(){var t=arguments.length>0&&void 0!==arguments[0]?arguments[0]:{};E()(this,e),f()(this,{state:{},plugins:[],system:{configs:{},fn:{},components:{},rootInjects:{},statePlugins:{}},boundSystem:{},toolbox:{}},t),this.getSystem=this._getSystem.bind(this),this.store=F(q,Object(O.fromJS)(this.state),this.getSystem),this.buildSystem(!1),this.register(this.plugins)}return S()(e,[{key:"getStore",value:function(){return this.store}},{key:"register",value:function(e){var t=!(arguments.length>1&&void 0!==arguments[1])||arguments[1],n=B(e,this.getSystem());V(this.system,n),t&&this.buildSystem();var r=U.call(this.system,e,this.getSystem());r&&this.buildSystem()}},{key:"buildSystem",value:function(){var e=!(arguments.length>0&&void 0!==arguments[0])||arguments[0],t=this.getStore().dispatch,n=this.getStore().getState;this.boundSystem=y()({},this.getRootInjects(),this.getWrappedAndBoundActions(t),this.getWrappedAndBoundSelectors(n,this.getSystem),this.getStateThunks(n),this.getFn(),this.getConfigs()),e&&this.rebuildReducer()}},{key:"_getSystem",value:function(){return this.boundSystem}},{key:"getRootInjects",value:function(){return y()({getSystem:this.getSystem,getStore:this.getStore.bind(this),getComponents:this.getComponents.bind(this),getState:this.getStore().getState,getConfigs:this._getConfigs.bind(this),Im:C.a,React:j.a},this.system.rootInjects||{})}},{key:"_getConfigs",value:function(){return this.system.configs}},{key:"getConfigs",value:function(){return{configs:this.system.configs}}},{key:"setConfigs",value:function(e){this.system.configs=e}},{key:"rebuildReducer",value:function(){var e,t,n;this.store.replaceReducer((n=this.system.statePlugins,e=Object(D.y)(n,(function(e){return e.reducers})),t=s()(e).reduce((function(t,n){var r;return t[n]=(r=e[n],function(){var e=arguments.length>0&&void 0!==arguments[0]?arguments[0]:new O.Map,t=arguments.length>1?arguments[1]:void 0;if(!r)return e;var n=r[t.type];if(n){var o=z(n)(e,t);return null===o?e:o}return e}),t}),{}),s()(t).length?Object(A.combineReducers)(t):q))}},{key:"getType",value:function(e){var t=e[0].toUpperCase()+e.slice(1);return Object(D.z)(this.system.statePlugins,(function(n,r){var o=n[e];if(o)return v()({},r+t,o)}))}},{key:"getSelectors",value:function(){return this.getType("selectors")}},{key:"getActions",value:function(){var e=this.getType("actions");return Object(D.y)(e,(function(e){return Object(D.z)(e,(function(e,t){if(Object(D.r)(e))return v()({},t,e)}))}))}},{key:"getWrappedAndBoundActions",value:function(e){var t=this,n=this.getBoundActions(e);return Object(D.y)(n,(function(e,n){var r=t.system.statePlugins[n.slice(0,-7)].wrapActions;return r?Object(D.y)(e,(function(e,n){var o=r[n];return o?(h()(o)||(o=[o]),o.reduce((function(e,n){var r=function(){return n(e,t.getSystem()).apply(void 0,arguments)};if(!Object(D.r)(r))throw new TypeError("wrapActions needs to return a function that returns a new function (ie the wrapped action)");return z(r)}),e||Function.prototype)):e})):e}))}},{key:"getWrappedAndBoundSelectors",value:function(e,t){var n=this,r=this.getBoundSelectors(e,t);return Object(D.y)(r,(function(t,r){var o=[r.slice(0,-9)],a=n.system.statePlugins[o].wrapSelectors;return a?Object(D.y)(t,(function(t,r){var i=a[r];return i?(h()(i)||(i=[i]),i.reduce((function(t,r){var a=function(){for(var a=arguments.length,i=new Array(a),c=0;c<a;c++)i[c]=arguments[c];return r(t,n.getSystem()).apply(void 0,[e().getIn(o)].concat(i))};if(!Object(D.r)(a))throw new TypeError("wrapSelector needs to return a function that returns a new function (ie the wrapped action)");return a}),t||Function.prototype)):t})):t}))}},{key:"getStates",value:function(e){return s()(this.system.statePlugins).reduce((function(t,n){return t[n]=e.get(n),t}),{})}},{key:"getStateThunks",value:function(e){return s()(this.system.statePlugins).reduce((function(t,n){return t[n]=function(){return e().get(n)},t}),{})}},{key:"getFn",value:function(){return{fn:this.system.fn}}},{key:"getComponents",value:function(e){var t=this,n=this.system.components[e];return h()(n)?n.reduce((function(e,n){return n(e,t.getSystem())})):void 0!==e?this.system.components[e]:this.system.components}},{key:"getBoundSelectors",value:function(e,t){return Object(D.y)(this.getSelectors(),(function(n,r){var o=[r.slice(0,-9)],a=function(){return e().getIn(o)};return Object(D.y)(n,(function(e){return function(){for(var n=arguments.length,r=new Array(n),o=0;o<n;o++)r[o]=arguments[o];var i=z(e).apply(null,[a()].concat(r));return"function"==typeof i&&(i=z(i)(t())),i}}))}))}},{key:"getBoundActions",value:function(e){e=e||this.getStore().dispatch;var t=this.getActions();return Object(D.y)(t,(function(t){return Object(_.bindActionCreators)(function e(t){return"function"!=typeof t?Object(D.y)(t,(function(t){return e(t)})):function(){var e=null;try{e=t.apply(void 0,arguments)}catch(t){e={type:N.NEW_THROWN_ERR,error:!0,payload:P()(t)}}finally{return e}}}(t),e)}))}},{key:"getMapStateToProps",value:function(){var e=this;return function(){return y()({},e.getSystem())}}},{key:"getMapDispatchToProps",value:function(e){var t=this;return function(n){return f()({},t.getWrappedAndBoundActions(n),t.getFn(),e)}}}]),e}();function B(e,t){return Object(D.u)(e)&&!Object(D.p)(e)?T()({},e):Object(D.s)(e)?B(e(t),t):Object(D.p)(e)?e.map((function(e){return B(e,t)})).reduce(V,{}):{}}function U(e,t){var n=this,r=arguments.length>2&&void 0!==arguments[2]?arguments[2]:{},o=r.hasLoaded,a=o;return Object(D.u)(e)&&!Object(D.p)(e)&&"function"==typeof e.afterLoad&&(a=!0,z(e.afterLoad).call(this,t)),Object(D.s)(e)?U.call(this,e(t),t,{hasLoaded:a}):Object(D.p)(e)?e.map((function(e){return U.call(n,e,t,{hasLoaded:a})})):a}function V(){var e=arguments.length>0&&void 0!==arguments[0]?arguments[0]:{},t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{};if(!Object(D.u)(e))return{};if(!Object(D.u)(t))return e;t.wrapComponents&&(Object(D.y)(t.wrapComponents,(function(n,r){var o=e.components&&e.components[r];o&&h()(o)?(e.components[r]=o.concat([n]),delete t.wrapComponents[r]):o&&(e.components[r]=[o,n],delete t.wrapComponents[r])})),s()(t.wrapComponents).length||delete t.wrapComponents);var n=e.statePlugins;if(Object(D.u)(n))for(var r in n){var o=n[r];if(Object(D.u)(o)&&Object(D.u)(o.wrapActions)){var a=o.wrapActions;for(var i in a){var c=a[i];h()(c)||(c=[c],a[i]=c),t&&t.statePlugins&&t.statePlugins[r]&&t.statePlugins[r].wrapActions&&t.statePlugins[r].wrapActions[i]&&(t.statePlugins[r].wrapActions[i]=a[i].concat(t.statePlugins[r].wrapActions[i]))}}}return f()(e,t)}function z(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{},n=t.logErrors,r=void 0===n||n;return"function"!=typeof e?e:function(){try{for(var t=arguments.length,n=new Array(t),o=0;o<t;o++)n[o]=arguments[o];return e.call.apply(e,[this].concat(n))}catch(e){return r&&console.error(e),null}}}function F(e,t,n){return function(e,t,n){var r=[Object(D.K)(n)],o=M.a.__REDUX_DEVTOOLS_EXTENSION_COMPOSE__||_.compose;return Object(_.createStore)(e,t,o(_.applyMiddleware.apply(void 0,r)))}(e,t,n)}var J=n(206),W=n(211),H=n(214),$=n(217),Y=n(219),K=n(220),G=n(261),Z=n(222),X=n(226),Q=n(227),ee=n(262),te=n(231),ne=n(235),re=n(237),oe=n(8),ae=n.n(oe),ie=n(6),ce=n.n(ie),se=n(7),ue=n.n(se),le=(n(9),n(20),n(41)),pe=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;return E()(this,n),o=t.call(this,e,r),v()(ae()(o),"toggleShown",(function(){var e=o.props,t=e.layoutActions,n=e.tag,r=e.operationId,a=e.isShown,i=o.getResolvedSubtree();a||void 0!==i||o.requestResolvedSubtree(),t.show(["operations",n,r],!a)})),v()(ae()(o),"onCancelClick",(function(){o.setState({tryItOutEnabled:!o.state.tryItOutEnabled})})),v()(ae()(o),"onTryoutClick",(function(){o.setState({tryItOutEnabled:!o.state.tryItOutEnabled})})),v()(ae()(o),"onExecute",(function(){o.setState({executeInProgress:!0})})),v()(ae()(o),"getResolvedSubtree",(function(){var e=o.props,t=e.specSelectors,n=e.path,r=e.method,a=e.specPath;return a?t.specResolvedSubtree(a.toJS()):t.specResolvedSubtree(["paths",n,r])})),v()(ae()(o),"requestResolvedSubtree",(function(){var e=o.props,t=e.specActions,n=e.path,r=e.method,a=e.specPath;return a?t.requestResolvedSubtree(a.toJS()):t.requestResolvedSubtree(["paths",n,r])})),o.state={tryItOutEnabled:!1,executeInProgress:!1},o}return S()(n,[{key:"mapStateToProps",value:function(e,t){var n=t.op,r=t.layoutSelectors,o=(0,t.getConfigs)(),a=o.docExpansion,i=o.deepLinking,c=o.displayOperationId,s=o.displayRequestDuration,u=o.supportedSubmitMethods,l=r.showSummary(),p=n.getIn(["operation","__originalOperationId"])||n.getIn(["operation","operationId"])||Object(le.e)(n.get("operation"),t.path,t.method)||n.get("id"),f=["operations",t.tag,p],d=i&&"false"!==i,h=u.indexOf(t.method)>=0&&(void 0===t.allowTryItOut?t.specSelectors.allowTryItOutFor(t.path,t.method):t.allowTryItOut),m=n.getIn(["operation","security"])||t.specSelectors.security();return{operationId:p,isDeepLinkingEnabled:d,showSummary:l,displayOperationId:c,displayRequestDuration:s,allowTryItOut:h,security:m,isAuthorized:t.authSelectors.isAuthorized(m),isShown:r.isShown(f,"full"===a),jumpToKey:"paths.".concat(t.path,".").concat(t.method),response:t.specSelectors.responseFor(t.path,t.method),request:t.specSelectors.requestFor(t.path,t.method)}}},{key:"componentDidMount",value:function(){var e=this.props.isShown,t=this.getResolvedSubtree();e&&void 0===t&&this.requestResolvedSubtree()}},{key:"componentWillReceiveProps",value:function(e){var t=e.response,n=e.isShown,r=this.getResolvedSubtree();t!==this.props.response&&this.setState({executeInProgress:!1}),n&&void 0===r&&this.requestResolvedSubtree()}},{key:"render",value:function(){var e=this.props,t=e.op,n=e.tag,r=e.path,o=e.method,a=e.security,i=e.isAuthorized,c=e.operationId,s=e.showSummary,u=e.isShown,l=e.jumpToKey,p=e.allowTryItOut,f=e.response,d=e.request,h=e.displayOperationId,m=e.displayRequestDuration,v=e.isDeepLinkingEnabled,g=e.specPath,y=e.specSelectors,b=e.specActions,E=e.getComponent,x=e.getConfigs,S=e.layoutSelectors,w=e.layoutActions,_=e.authActions,C=e.authSelectors,A=e.oas3Actions,k=e.oas3Selectors,P=e.fn,I=E("operation"),T=this.getResolvedSubtree()||Object(O.Map)(),N=Object(O.fromJS)({op:T,tag:n,path:r,summary:t.getIn(["operation","summary"])||"",deprecated:T.get("deprecated")||t.getIn(["operation","deprecated"])||!1,method:o,security:a,isAuthorized:i,operationId:c,originalOperationId:T.getIn(["operation","__originalOperationId"]),showSummary:s,isShown:u,jumpToKey:l,allowTryItOut:p,request:d,displayOperationId:h,displayRequestDuration:m,isDeepLinkingEnabled:v,executeInProgress:this.state.executeInProgress,tryItOutEnabled:this.state.tryItOutEnabled});return j.a.createElement(I,{operation:N,response:f,request:d,isShown:u,toggleShown:this.toggleShown,onTryoutClick:this.onTryoutClick,onCancelClick:this.onCancelClick,onExecute:this.onExecute,specPath:g,specActions:b,specSelectors:y,oas3Actions:A,oas3Selectors:k,layoutActions:w,layoutSelectors:S,authActions:_,authSelectors:C,getComponent:E,getConfigs:x,fn:P})}}]),n}(w.PureComponent);v()(pe,"defaultProps",{showSummary:!0,response:null,allowTryItOut:!0,displayOperationId:!1,displayRequestDuration:!1});var fe=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"getLayout",value:function(){var e=this.props,t=e.getComponent,n=e.layoutSelectors.current(),r=t(n,!0);return r||function(){return j.a.createElement("h1",null,' No layout defined for "',n,'" ')}}},{key:"render",value:function(){var e=this.getLayout();return j.a.createElement(e,null)}}]),n}(j.a.Component);fe.defaultProps={};var de=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"close",(function(){e.props.authActions.showDefinitions(!1)})),e}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.authSelectors,n=e.authActions,r=e.getComponent,o=e.errSelectors,a=e.specSelectors,i=e.fn.AST,c=void 0===i?{}:i,s=t.shownDefinitions(),u=r("auths");return j.a.createElement("div",{className:"dialog-ux"},j.a.createElement("div",{className:"backdrop-ux"}),j.a.createElement("div",{className:"modal-ux"},j.a.createElement("div",{className:"modal-dialog-ux"},j.a.createElement("div",{className:"modal-ux-inner"},j.a.createElement("div",{className:"modal-ux-header"},j.a.createElement("h3",null,"Available authorizations"),j.a.createElement("button",{type:"button",className:"close-modal",onClick:this.close},j.a.createElement("svg",{width:"20",height:"20"},j.a.createElement("use",{href:"#close",xlinkHref:"#close"})))),j.a.createElement("div",{className:"modal-ux-content"},s.valueSeq().map((function(e,i){return j.a.createElement(u,{key:i,AST:c,definitions:e,getComponent:r,errSelectors:o,authSelectors:t,authActions:n,specSelectors:a})})))))))}}]),n}(j.a.Component),he=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.isAuthorized,n=e.showPopup,r=e.onClick,o=(0,e.getComponent)("authorizationPopup",!0);return j.a.createElement("div",{className:"auth-wrapper"},j.a.createElement("button",{className:t?"btn authorize locked":"btn authorize unlocked",onClick:r},j.a.createElement("span",null,"Authorize"),j.a.createElement("svg",{width:"20",height:"20"},j.a.createElement("use",{href:t?"#locked":"#unlocked",xlinkHref:t?"#locked":"#unlocked"}))),n&&j.a.createElement(o,null))}}]),n}(j.a.Component),me=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.authActions,n=e.authSelectors,r=e.specSelectors,o=e.getComponent,a=r.securityDefinitions(),i=n.definitionsToAuthorize(),c=o("authorizeBtn");return a?j.a.createElement(c,{onClick:function(){return t.showDefinitions(i)},isAuthorized:!!n.authorized().size,showPopup:!!n.shownDefinitions(),getComponent:o}):null}}]),n}(j.a.Component),ve=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onClick",(function(t){t.stopPropagation();var n=e.props.onClick;n&&n()})),e}return S()(n,[{key:"render",value:function(){var e=this.props.isAuthorized;return j.a.createElement("button",{className:e?"authorization__btn locked":"authorization__btn unlocked","aria-label":e?"authorization button locked":"authorization button unlocked",onClick:this.onClick},j.a.createElement("svg",{width:"20",height:"20"},j.a.createElement("use",{href:e?"#locked":"#unlocked",xlinkHref:e?"#locked":"#unlocked"})))}}]),n}(j.a.Component),ge=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;return E()(this,n),o=t.call(this,e,r),v()(ae()(o),"onAuthChange",(function(e){var t=e.name;o.setState(v()({},t,e))})),v()(ae()(o),"submitAuth",(function(e){e.preventDefault(),o.props.authActions.authorize(o.state)})),v()(ae()(o),"logoutClick",(function(e){e.preventDefault();var t=o.props,n=t.authActions,r=t.definitions.map((function(e,t){return t})).toArray();o.setState(r.reduce((function(e,t){return e[t]="",e}),{})),n.logout(r)})),v()(ae()(o),"close",(function(e){e.preventDefault(),o.props.authActions.showDefinitions(!1)})),o.state={},o}return S()(n,[{key:"render",value:function(){var e=this,t=this.props,n=t.definitions,r=t.getComponent,o=t.authSelectors,a=t.errSelectors,i=r("AuthItem"),c=r("oauth2",!0),s=r("Button"),u=o.authorized(),l=n.filter((function(e,t){return!!u.get(t)})),p=n.filter((function(e){return"oauth2"!==e.get("type")})),f=n.filter((function(e){return"oauth2"===e.get("type")}));return j.a.createElement("div",{className:"auth-container"},!!p.size&&j.a.createElement("form",{onSubmit:this.submitAuth},p.map((function(t,n){return j.a.createElement(i,{key:n,schema:t,name:n,getComponent:r,onAuthChange:e.onAuthChange,authorized:u,errSelectors:a})})).toArray(),j.a.createElement("div",{className:"auth-btn-wrapper"},p.size===l.size?j.a.createElement(s,{className:"btn modal-btn auth",onClick:this.logoutClick},"Logout"):j.a.createElement(s,{type:"submit",className:"btn modal-btn auth authorize"},"Authorize"),j.a.createElement(s,{className:"btn modal-btn auth btn-done",onClick:this.close},"Close"))),f&&f.size?j.a.createElement("div",null,j.a.createElement("div",{className:"scope-def"},j.a.createElement("p",null,"Scopes are used to grant an application different levels of access to data on behalf of the end user. Each API may declare one or more scopes."),j.a.createElement("p",null,"API requires the following scopes. Select which ones you want to grant to Swagger UI.")),n.filter((function(e){return"oauth2"===e.get("type")})).map((function(e,t){return j.a.createElement("div",{key:t},j.a.createElement(c,{authorized:u,schema:e,name:t}))})).toArray()):null)}}]),n}(j.a.Component),ye=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e,t=this.props,n=t.schema,r=t.name,o=t.getComponent,a=t.onAuthChange,i=t.authorized,c=t.errSelectors,s=o("apiKeyAuth"),u=o("basicAuth"),l=n.get("type");switch(l){case"apiKey":e=j.a.createElement(s,{key:r,schema:n,name:r,errSelectors:c,authorized:i,getComponent:o,onChange:a});break;case"basic":e=j.a.createElement(u,{key:r,schema:n,name:r,errSelectors:c,authorized:i,getComponent:o,onChange:a});break;default:e=j.a.createElement("div",{key:r},"Unknown security definition type ",l)}return j.a.createElement("div",{key:"".concat(r,"-jump")},e)}}]),n}(j.a.Component),be=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props.error,t=e.get("level"),n=e.get("message"),r=e.get("source");return j.a.createElement("div",{className:"errors"},j.a.createElement("b",null,r," ",t),j.a.createElement("span",null,n))}}]),n}(j.a.Component),Ee=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;E()(this,n),o=t.call(this,e,r),v()(ae()(o),"onChange",(function(e){var t=o.props.onChange,n=e.target.value,r=y()({},o.state,{value:n});o.setState(r),t(r)}));var a=o.props,i=a.name,c=a.schema,s=o.getValue();return o.state={name:i,schema:c,value:s},o}return S()(n,[{key:"getValue",value:function(){var e=this.props,t=e.name,n=e.authorized;return n&&n.getIn([t,"value"])}},{key:"render",value:function(){var e=this.props,t=e.schema,n=e.getComponent,r=e.errSelectors,o=e.name,a=n("Input"),i=n("Row"),c=n("Col"),s=n("authError"),u=n("Markdown",!0),l=n("JumpToPath",!0),p=this.getValue(),f=r.allErrors().filter((function(e){return e.get("authId")===o}));return j.a.createElement("div",null,j.a.createElement("h4",null,j.a.createElement("code",null,o||t.get("name")),"  (apiKey)",j.a.createElement(l,{path:["securityDefinitions",o]})),p&&j.a.createElement("h6",null,"Authorized"),j.a.createElement(i,null,j.a.createElement(u,{source:t.get("description")})),j.a.createElement(i,null,j.a.createElement("p",null,"Name: ",j.a.createElement("code",null,t.get("name")))),j.a.createElement(i,null,j.a.createElement("p",null,"In: ",j.a.createElement("code",null,t.get("in")))),j.a.createElement(i,null,j.a.createElement("label",null,"Value:"),p?j.a.createElement("code",null," ****** "):j.a.createElement(c,null,j.a.createElement(a,{type:"text",onChange:this.onChange}))),f.valueSeq().map((function(e,t){return j.a.createElement(s,{error:e,key:t})})))}}]),n}(j.a.Component),xe=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;E()(this,n),o=t.call(this,e,r),v()(ae()(o),"onChange",(function(e){var t=o.props.onChange,n=e.target,r=n.value,a=n.name,i=o.state.value;i[a]=r,o.setState({value:i}),t(o.state)}));var a=o.props,i=a.schema,c=a.name,s=o.getValue().username;return o.state={name:c,schema:i,value:s?{username:s}:{}},o}return S()(n,[{key:"getValue",value:function(){var e=this.props,t=e.authorized,n=e.name;return t&&t.getIn([n,"value"])||{}}},{key:"render",value:function(){var e=this.props,t=e.schema,n=e.getComponent,r=e.name,o=e.errSelectors,a=n("Input"),i=n("Row"),c=n("Col"),s=n("authError"),u=n("JumpToPath",!0),l=n("Markdown",!0),p=this.getValue().username,f=o.allErrors().filter((function(e){return e.get("authId")===r}));return j.a.createElement("div",null,j.a.createElement("h4",null,"Basic authorization",j.a.createElement(u,{path:["securityDefinitions",r]})),p&&j.a.createElement("h6",null,"Authorized"),j.a.createElement(i,null,j.a.createElement(l,{source:t.get("description")})),j.a.createElement(i,null,j.a.createElement("label",null,"Username:"),p?j.a.createElement("code",null," ",p," "):j.a.createElement(c,null,j.a.createElement(a,{type:"text",required:"required",name:"username",onChange:this.onChange}))),j.a.createElement(i,null,j.a.createElement("label",null,"Password:"),p?j.a.createElement("code",null," ****** "):j.a.createElement(c,null,j.a.createElement(a,{autoComplete:"new-password",name:"password",type:"password",onChange:this.onChange}))),f.valueSeq().map((function(e,t){return j.a.createElement(s,{error:e,key:t})})))}}]),n}(j.a.Component);function Se(e){var t=e.example,n=e.showValue,r=e.getComponent,o=r("Markdown",!0),a=r("highlightCode");return t?j.a.createElement("div",{className:"example"},t.get("description")?j.a.createElement("section",{className:"example__section"},j.a.createElement("div",{className:"example__section-header"},"Example Description"),j.a.createElement("p",null,j.a.createElement(o,{source:t.get("description")}))):null,n&&t.has("value")?j.a.createElement("section",{className:"example__section"},j.a.createElement("div",{className:"example__section-header"},"Example Value"),j.a.createElement(a,{value:Object(D.J)(t.get("value"))})):null):null}var we=n(373),je=n.n(we),_e=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"_onSelect",(function(t){var n=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{},r=n.isSyntheticChange,o=void 0!==r&&r;"function"==typeof e.props.onSelect&&e.props.onSelect(t,{isSyntheticChange:o})})),v()(ae()(e),"_onDomSelect",(function(t){if("function"==typeof e.props.onSelect){var n=t.target.selectedOptions[0].getAttribute("value");e._onSelect(n,{isSyntheticChange:!1})}})),v()(ae()(e),"getCurrentExample",(function(){var t=e.props,n=t.examples,r=t.currentExampleKey,o=n.get(r),a=n.keySeq().first(),i=n.get(a);return o||i||je()({})})),e}return S()(n,[{key:"componentDidMount",value:function(){var e=this.props,t=e.onSelect,n=e.examples;if("function"==typeof t){var r=n.first(),o=n.keyOf(r);this._onSelect(o,{isSyntheticChange:!0})}}},{key:"componentWillReceiveProps",value:function(e){var t=e.currentExampleKey,n=e.examples;if(n!==this.props.examples&&!n.has(t)){var r=n.first(),o=n.keyOf(r);this._onSelect(o,{isSyntheticChange:!0})}}},{key:"render",value:function(){var e=this.props,t=e.examples,n=e.currentExampleKey,r=e.isValueModified,o=e.isModifiedValueAvailable,a=e.showLabels;return j.a.createElement("div",{className:"examples-select"},a?j.a.createElement("span",{className:"examples-select__section-label"},"Examples: "):null,j.a.createElement("select",{onChange:this._onDomSelect,value:o&&r?"__MODIFIED__VALUE__":n||""},o?j.a.createElement("option",{value:"__MODIFIED__VALUE__"},"[Modified value]"):null,t.map((function(e,t){return j.a.createElement("option",{key:t,value:t},e.get("summary")||t)})).valueSeq()))}}]),n}(j.a.PureComponent);v()(_e,"defaultProps",{examples:C.a.Map({}),onSelect:function(){for(var e,t=arguments.length,n=new Array(t),r=0;r<t;r++)n[r]=arguments[r];return(e=console).log.apply(e,["DEBUG: ExamplesSelect was not given an onSelect callback"].concat(n))},currentExampleKey:null,showLabels:!0});var Oe=function(e){return O.List.isList(e)?e:Object(D.J)(e)},Ce=function(e){ce()(n,e);var t=ue()(n);function n(e){var r;E()(this,n),r=t.call(this,e),v()(ae()(r),"_getStateForCurrentNamespace",(function(){var e=r.props.currentNamespace;return(r.state[e]||Object(O.Map)()).toObject()})),v()(ae()(r),"_setStateForCurrentNamespace",(function(e){var t=r.props.currentNamespace;return r._setStateForNamespace(t,e)})),v()(ae()(r),"_setStateForNamespace",(function(e,t){var n=(r.state[e]||Object(O.Map)()).mergeDeep(t);return r.setState(v()({},e,n))})),v()(ae()(r),"_isCurrentUserInputSameAsExampleValue",(function(){var e=r.props.currentUserInputValue;return r._getCurrentExampleValue()===e})),v()(ae()(r),"_getValueForExample",(function(e,t){var n=(t||r.props).examples;return Oe((n||Object(O.Map)({})).getIn([e,"value"]))})),v()(ae()(r),"_getCurrentExampleValue",(function(e){var t=(e||r.props).currentKey;return r._getValueForExample(t,e||r.props)})),v()(ae()(r),"_onExamplesSelect",(function(e){var t=arguments.length>1&&void 0!==arguments[1]?arguments[1]:{},n=t.isSyntheticChange,o=r.props,a=o.onSelect,i=o.updateValue,c=o.currentUserInputValue,s=r._getStateForCurrentNamespace(),u=s.lastUserEditedValue,l=r._getValueForExample(e);if("__MODIFIED__VALUE__"===e)return i(Oe(u)),r._setStateForCurrentNamespace({isModifiedValueSelected:!0});if("function"==typeof a){for(var p=arguments.length,f=new Array(p>2?p-2:0),d=2;d<p;d++)f[d-2]=arguments[d];a.apply(void 0,[e,{isSyntheticChange:n}].concat(f))}r._setStateForCurrentNamespace({lastDownstreamValue:l,isModifiedValueSelected:n&&!!c&&c!==l}),n||"function"==typeof i&&i(Oe(l))}));var o=r._getCurrentExampleValue();return r.state=v()({},e.currentNamespace,Object(O.Map)({lastUserEditedValue:r.props.currentUserInputValue,lastDownstreamValue:o,isModifiedValueSelected:r.props.currentUserInputValue!==o})),r}return S()(n,[{key:"componentWillReceiveProps",value:function(e){var t=e.currentUserInputValue,n=e.examples,r=e.onSelect,o=this._getStateForCurrentNamespace(),a=o.lastUserEditedValue,i=o.lastDownstreamValue,c=this._getValueForExample(e.currentKey,e),s=n.find((function(e){return e.get("value")===t||Object(D.J)(e.get("value"))===t}));s?r(n.keyOf(s),{isSyntheticChange:!0}):t!==this.props.currentUserInputValue&&t!==a&&t!==i&&this._setStateForNamespace(e.currentNamespace,{lastUserEditedValue:e.currentUserInputValue,isModifiedValueSelected:t!==c})}},{key:"render",value:function(){var e=this.props,t=e.currentUserInputValue,n=e.examples,r=e.currentKey,o=e.getComponent,a=this._getStateForCurrentNamespace(),i=a.lastDownstreamValue,c=a.lastUserEditedValue,s=a.isModifiedValueSelected,u=o("ExamplesSelect");return j.a.createElement(u,{examples:n,currentExampleKey:r,onSelect:this._onExamplesSelect,isModifiedValueAvailable:!!c&&c!==i,isValueModified:void 0!==t&&s&&t!==this._getCurrentExampleValue()})}}]),n}(j.a.PureComponent);v()(Ce,"defaultProps",{examples:Object(O.Map)({}),currentNamespace:"__DEFAULT__NAMESPACE__",onSelect:function(){for(var e,t=arguments.length,n=new Array(t),r=0;r<t;r++)n[r]=arguments[r];return(e=console).log.apply(e,["ExamplesSelectValueRetainer: no `onSelect` function was provided"].concat(n))},updateValue:function(){for(var e,t=arguments.length,n=new Array(t),r=0;r<t;r++)n[r]=arguments[r];return(e=console).log.apply(e,["ExamplesSelectValueRetainer: no `updateValue` function was provided"].concat(n))}});var Ae=n(91),ke=n.n(Ae);var Pe=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;E()(this,n),o=t.call(this,e,r),v()(ae()(o),"close",(function(e){e.preventDefault(),o.props.authActions.showDefinitions(!1)})),v()(ae()(o),"authorize",(function(){var e=o.props,t=e.authActions,n=e.errActions,r=e.getConfigs,a=e.authSelectors,i=r(),c=a.getConfigs();n.clear({authId:name,type:"auth",source:"auth"}),function(e){var t=e.auth,n=e.authActions,r=e.errActions,o=e.configs,a=e.authConfigs,i=void 0===a?{}:a,c=t.schema,s=t.scopes,u=t.name,l=t.clientId,p=c.get("flow"),f=[];switch(p){case"password":return void n.authorizePassword(t);case"application":return void n.authorizeApplication(t);case"accessCode":f.push("response_type=code");break;case"implicit":f.push("response_type=token");break;case"clientCredentials":return void n.authorizeApplication(t);case"authorizationCode":f.push("response_type=code")}"string"==typeof l&&f.push("client_id="+encodeURIComponent(l));var d=o.oauth2RedirectUrl;if(void 0!==d){if(f.push("redirect_uri="+encodeURIComponent(d)),h()(s)&&0<s.length){var m=i.scopeSeparator||" ";f.push("scope="+encodeURIComponent(s.join(m)))}var v=Object(D.a)(new Date);if(f.push("state="+encodeURIComponent(v)),void 0!==i.realm&&f.push("realm="+encodeURIComponent(i.realm)),"authorizationCode"===p&&i.usePkceWithAuthorizationCodeGrant){var g=Object(D.j)(),y=Object(D.c)(g);f.push("code_challenge="+y),f.push("code_challenge_method=S256"),t.codeVerifier=g}var b=i.additionalQueryStringParams;for(var E in b)void 0!==b[E]&&f.push([E,b[E]].map(encodeURIComponent).join("="));var x,S=c.get("authorizationUrl"),w=[Object(D.G)(S),f.join("&")].join(-1===S.indexOf("?")?"?":"&");x="implicit"===p?n.preAuthorizeImplicit:i.useBasicAuthenticationWithAccessCodeGrant?n.authorizeAccessCodeWithBasicAuthentication:n.authorizeAccessCodeWithFormParams,M.a.swaggerUIRedirectOauth2={auth:t,state:v,redirectUrl:d,callback:x,errCb:r.newAuthErr},M.a.open(w)}else r.newAuthErr({authId:u,source:"validation",level:"error",message:"oauth2RedirectUrl configuration is not passed. Oauth2 authorization cannot be performed."})}({auth:o.state,authActions:t,errActions:n,configs:i,authConfigs:c})})),v()(ae()(o),"onScopeChange",(function(e){var t=e.target,n=t.checked,r=t.dataset.value;if(n&&-1===o.state.scopes.indexOf(r)){var a=o.state.scopes.concat([r]);o.setState({scopes:a})}else!n&&o.state.scopes.indexOf(r)>-1&&o.setState({scopes:o.state.scopes.filter((function(e){return e!==r}))})})),v()(ae()(o),"onInputChange",(function(e){var t=e.target,n=t.dataset.name,r=t.value,a=v()({},n,r);o.setState(a)})),v()(ae()(o),"selectScopes",(function(e){e.target.dataset.all?o.setState({scopes:ke()((o.props.schema.get("allowedScopes")||o.props.schema.get("scopes")).keys())}):o.setState({scopes:[]})})),v()(ae()(o),"logout",(function(e){e.preventDefault();var t=o.props,n=t.authActions,r=t.errActions,a=t.name;r.clear({authId:a,type:"auth",source:"auth"}),n.logout([a])}));var a=o.props,i=a.name,c=a.schema,s=a.authorized,u=a.authSelectors,l=s&&s.get(i),p=u.getConfigs()||{},f=l&&l.get("username")||"",d=l&&l.get("clientId")||p.clientId||"",m=l&&l.get("clientSecret")||p.clientSecret||"",g=l&&l.get("passwordType")||"basic",y=l&&l.get("scopes")||p.scopes||[];return"string"==typeof y&&(y=y.split(p.scopeSeparator||" ")),o.state={appName:p.appName,name:i,schema:c,scopes:y,clientId:d,clientSecret:m,username:f,password:"",passwordType:g},o}return S()(n,[{key:"render",value:function(){var e=this,t=this.props,n=t.schema,r=t.getComponent,o=t.authSelectors,a=t.errSelectors,i=t.name,c=t.specSelectors,s=r("Input"),u=r("Row"),l=r("Col"),p=r("Button"),f=r("authError"),d=r("JumpToPath",!0),h=r("Markdown",!0),m=r("InitializedInput"),v=c.isOAS3,g=v()?"authorizationCode":"accessCode",y=v()?"clientCredentials":"application",b=n.get("flow"),E=n.get("allowedScopes")||n.get("scopes"),x=!!o.authorized().get(i),S=a.allErrors().filter((function(e){return e.get("authId")===i})),w=!S.filter((function(e){return"validation"===e.get("source")})).size,_=n.get("description");return j.a.createElement("div",null,j.a.createElement("h4",null,i," (OAuth2, ",n.get("flow"),") ",j.a.createElement(d,{path:["securityDefinitions",i]})),this.state.appName?j.a.createElement("h5",null,"Application: ",this.state.appName," "):null,_&&j.a.createElement(h,{source:n.get("description")}),x&&j.a.createElement("h6",null,"Authorized"),("implicit"===b||b===g)&&j.a.createElement("p",null,"Authorization URL: ",j.a.createElement("code",null,n.get("authorizationUrl"))),("password"===b||b===g||b===y)&&j.a.createElement("p",null,"Token URL:",j.a.createElement("code",null," ",n.get("tokenUrl"))),j.a.createElement("p",{className:"flow"},"Flow: ",j.a.createElement("code",null,n.get("flow"))),"password"!==b?null:j.a.createElement(u,null,j.a.createElement(u,null,j.a.createElement("label",{htmlFor:"oauth_username"},"username:"),x?j.a.createElement("code",null," ",this.state.username," "):j.a.createElement(l,{tablet:10,desktop:10},j.a.createElement("input",{id:"oauth_username",type:"text","data-name":"username",onChange:this.onInputChange}))),j.a.createElement(u,null,j.a.createElement("label",{htmlFor:"oauth_password"},"password:"),x?j.a.createElement("code",null," ****** "):j.a.createElement(l,{tablet:10,desktop:10},j.a.createElement("input",{id:"oauth_password",type:"password","data-name":"password",onChange:this.onInputChange}))),j.a.createElement(u,null,j.a.createElement("label",{htmlFor:"password_type"},"Client credentials location:"),x?j.a.createElement("code",null," ",this.state.passwordType," "):j.a.createElement(l,{tablet:10,desktop:10},j.a.createElement("select",{id:"password_type","data-name":"passwordType",onChange:this.onInputChange},j.a.createElement("option",{value:"basic"},"Authorization header"),j.a.createElement("option",{value:"request-body"},"Request body"))))),(b===y||"implicit"===b||b===g||"password"===b)&&(!x||x&&this.state.clientId)&&j.a.createElement(u,null,j.a.createElement("label",{htmlFor:"client_id"},"client_id:"),x?j.a.createElement("code",null," ****** "):j.a.createElement(l,{tablet:10,desktop:10},j.a.createElement(m,{id:"client_id",type:"text",required:"password"===b,initialValue:this.state.clientId,"data-name":"clientId",onChange:this.onInputChange}))),(b===y||b===g||"password"===b)&&j.a.createElement(u,null,j.a.createElement("label",{htmlFor:"client_secret"},"client_secret:"),x?j.a.createElement("code",null," ****** "):j.a.createElement(l,{tablet:10,desktop:10},j.a.createElement(m,{id:"client_secret",initialValue:this.state.clientSecret,type:"password","data-name":"clientSecret",onChange:this.onInputChange}))),!x&&E&&E.size?j.a.createElement("div",{className:"scopes"},j.a.createElement("h2",null,"Scopes:",j.a.createElement("a",{onClick:this.selectScopes,"data-all":!0},"select all"),j.a.createElement("a",{onClick:this.selectScopes},"select none")),E.map((function(t,n){return j.a.createElement(u,{key:n},j.a.createElement("div",{className:"checkbox"},j.a.createElement(s,{"data-value":n,id:"".concat(n,"-").concat(b,"-checkbox-").concat(e.state.name),disabled:x,checked:e.state.scopes.includes(n),type:"checkbox",onChange:e.onScopeChange}),j.a.createElement("label",{htmlFor:"".concat(n,"-").concat(b,"-checkbox-").concat(e.state.name)},j.a.createElement("span",{className:"item"}),j.a.createElement("div",{className:"text"},j.a.createElement("p",{className:"name"},n),j.a.createElement("p",{className:"description"},t)))))})).toArray()):null,S.valueSeq().map((function(e,t){return j.a.createElement(f,{error:e,key:t})})),j.a.createElement("div",{className:"auth-btn-wrapper"},w&&(x?j.a.createElement(p,{className:"btn modal-btn auth authorize",onClick:this.logout},"Logout"):j.a.createElement(p,{className:"btn modal-btn auth authorize",onClick:this.authorize},"Authorize")),j.a.createElement(p,{className:"btn modal-btn auth btn-done",onClick:this.close},"Close")))}}]),n}(j.a.Component),Ie=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onClick",(function(){var t=e.props,n=t.specActions,r=t.path,o=t.method;n.clearResponse(r,o),n.clearRequest(r,o)})),e}return S()(n,[{key:"render",value:function(){return j.a.createElement("button",{className:"btn btn-clear opblock-control__btn",onClick:this.onClick},"Clear")}}]),n}(w.Component),Te=function(e){var t=e.headers;return j.a.createElement("div",null,j.a.createElement("h5",null,"Response headers"),j.a.createElement("pre",{className:"microlight"},t))},Ne=function(e){var t=e.duration;return j.a.createElement("div",null,j.a.createElement("h5",null,"Request duration"),j.a.createElement("pre",{className:"microlight"},t," ms"))},Re=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"shouldComponentUpdate",value:function(e){return this.props.response!==e.response||this.props.path!==e.path||this.props.method!==e.method||this.props.displayRequestDuration!==e.displayRequestDuration}},{key:"render",value:function(){var e=this.props,t=e.response,n=e.getComponent,r=e.getConfigs,o=e.displayRequestDuration,a=e.specSelectors,i=e.path,c=e.method,u=r().showMutatedRequest?a.mutatedRequestFor(i,c):a.requestFor(i,c),l=t.get("status"),p=u.get("url"),f=t.get("headers").toJS(),d=t.get("notDocumented"),h=t.get("error"),m=t.get("text"),v=t.get("duration"),g=s()(f),y=f["content-type"]||f["Content-Type"],b=n("curl"),E=n("responseBody"),x=g.map((function(e){return j.a.createElement("span",{className:"headerline",key:e}," ",e,": ",f[e]," ")})),S=0!==x.length;return j.a.createElement("div",null,u&&j.a.createElement(b,{request:u,getConfigs:r}),p&&j.a.createElement("div",null,j.a.createElement("h4",null,"Request URL"),j.a.createElement("div",{className:"request-url"},j.a.createElement("pre",{className:"microlight"},p))),j.a.createElement("h4",null,"Server response"),j.a.createElement("table",{className:"responses-table live-responses-table"},j.a.createElement("thead",null,j.a.createElement("tr",{className:"responses-header"},j.a.createElement("td",{className:"col_header response-col_status"},"Code"),j.a.createElement("td",{className:"col_header response-col_description"},"Details"))),j.a.createElement("tbody",null,j.a.createElement("tr",{className:"response"},j.a.createElement("td",{className:"response-col_status"},l,d?j.a.createElement("div",{className:"response-undocumented"},j.a.createElement("i",null," Undocumented ")):null),j.a.createElement("td",{className:"response-col_description"},h?j.a.createElement("span",null,"".concat(t.get("name"),": ").concat(t.get("message"))):null,m?j.a.createElement(E,{content:m,contentType:y,url:p,headers:f,getConfigs:r,getComponent:n}):null,S?j.a.createElement(Te,{headers:x}):null,o&&v?j.a.createElement(Ne,{duration:v}):null)))))}}]),n}(j.a.Component),Me=n(150),De=["get","put","post","delete","options","head","patch"],qe=De.concat(["trace"]),Le=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.specSelectors,n=e.getComponent,r=e.layoutSelectors,o=e.layoutActions,a=e.getConfigs,i=e.fn,c=t.taggedOperations(),s=n("OperationContainer",!0),u=n("OperationTag"),l=a().maxDisplayedTags,p=r.currentFilter();return p&&!0!==p&&(c=i.opsFilter(c,p)),l&&!isNaN(l)&&l>=0&&(c=c.slice(0,l)),j.a.createElement("div",null,c.map((function(e,i){var c=e.get("operations");return j.a.createElement(u,{key:"operation-"+i,tagObj:e,tag:i,layoutSelectors:r,layoutActions:o,getConfigs:a,getComponent:n},c.map((function(e){var n=e.get("path"),r=e.get("method"),o=C.a.List(["paths",n,r]);return-1===(t.isOAS3()?qe:De).indexOf(r)?null:j.a.createElement(s,{key:"".concat(n,"-").concat(r),specPath:o,op:e,path:n,method:r,tag:i})})).toArray())})).toArray(),c.size<1?j.a.createElement("h3",null," No operations defined in spec! "):null)}}]),n}(j.a.Component),Be=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.tagObj,n=e.tag,r=e.children,o=e.layoutSelectors,a=e.layoutActions,i=e.getConfigs,c=e.getComponent,s=i(),u=s.docExpansion,l=s.deepLinking,p=l&&"false"!==l,f=c("Collapse"),d=c("Markdown",!0),h=c("DeepLink"),m=c("Link"),v=t.getIn(["tagDetails","description"],null),g=t.getIn(["tagDetails","externalDocs","description"]),y=t.getIn(["tagDetails","externalDocs","url"]),b=["operations-tag",n],E=o.isShown(b,"full"===u||"list"===u);return j.a.createElement("div",{className:E?"opblock-tag-section is-open":"opblock-tag-section"},j.a.createElement("h4",{onClick:function(){return a.show(b,!E)},className:v?"opblock-tag":"opblock-tag no-desc",id:b.map((function(e){return Object(D.g)(e)})).join("-"),"data-tag":n,"data-is-open":E},j.a.createElement(h,{enabled:p,isShown:E,path:Object(D.d)(n),text:n}),v?j.a.createElement("small",null,j.a.createElement(d,{source:v})):j.a.createElement("small",null),j.a.createElement("div",null,g?j.a.createElement("small",null,g,y?": ":null,y?j.a.createElement(m,{href:Object(D.G)(y),onClick:function(e){return e.stopPropagation()},target:"_blank"},y):null):null),j.a.createElement("button",{className:"expand-operation",title:E?"Collapse operation":"Expand operation",onClick:function(){return a.show(b,!E)}},j.a.createElement("svg",{className:"arrow",width:"20",height:"20"},j.a.createElement("use",{href:E?"#large-arrow-down":"#large-arrow",xlinkHref:E?"#large-arrow-down":"#large-arrow"})))),j.a.createElement(f,{isOpened:E},r))}}]),n}(j.a.Component);v()(Be,"defaultProps",{tagObj:C.a.fromJS({}),tag:""});var Ue=function(e){ce()(r,e);var t=ue()(r);function r(){return E()(this,r),t.apply(this,arguments)}return S()(r,[{key:"render",value:function(){var e=this.props,t=e.specPath,r=e.response,o=e.request,a=e.toggleShown,i=e.onTryoutClick,c=e.onCancelClick,s=e.onExecute,u=e.fn,l=e.getComponent,p=e.getConfigs,f=e.specActions,d=e.specSelectors,h=e.authActions,m=e.authSelectors,v=e.oas3Actions,g=e.oas3Selectors,y=this.props.operation,b=y.toJS(),E=b.deprecated,x=b.isShown,S=b.path,w=b.method,_=b.op,O=b.tag,C=b.operationId,A=b.allowTryItOut,k=b.displayRequestDuration,P=b.tryItOutEnabled,I=b.executeInProgress,T=_.description,N=_.externalDocs,R=_.schemes,M=y.getIn(["op"]),q=M.get("responses"),L=Object(D.n)(M,["parameters"]),B=d.operationScheme(S,w),U=["operations",O,C],V=Object(D.m)(M),z=l("responses"),F=l("parameters"),J=l("execute"),W=l("clear"),H=l("Collapse"),$=l("Markdown",!0),Y=l("schemes"),K=l("OperationServers"),G=l("OperationExt"),Z=l("OperationSummary"),X=l("Link"),Q=p().showExtensions;if(q&&r&&r.size>0){var ee=!q.get(String(r.get("status")))&&!q.get("default");r=r.set("notDocumented",ee)}var te=[S,w];return j.a.createElement("div",{className:E?"opblock opblock-deprecated":x?"opblock opblock-".concat(w," is-open"):"opblock opblock-".concat(w),id:Object(D.g)(U.join("-"))},j.a.createElement(Z,{operationProps:y,toggleShown:a,getComponent:l,authActions:h,authSelectors:m,specPath:t}),j.a.createElement(H,{isOpened:x},j.a.createElement("div",{className:"opblock-body"},M&&M.size||null===M?null:j.a.createElement("img",{height:"32px",width:"32px",src:n(350),className:"opblock-loading-animation"}),E&&j.a.createElement("h4",{className:"opblock-title_normal"}," Warning: Deprecated"),T&&j.a.createElement("div",{className:"opblock-description-wrapper"},j.a.createElement("div",{className:"opblock-description"},j.a.createElement($,{source:T}))),N&&N.url?j.a.createElement("div",{className:"opblock-external-docs-wrapper"},j.a.createElement("h4",{className:"opblock-title_normal"},"Find more details"),j.a.createElement("div",{className:"opblock-external-docs"},j.a.createElement("span",{className:"opblock-external-docs__description"},j.a.createElement($,{source:N.description})),j.a.createElement(X,{target:"_blank",className:"opblock-external-docs__link",href:Object(D.G)(N.url)},N.url))):null,M&&M.size?j.a.createElement(F,{parameters:L,specPath:t.push("parameters"),operation:M,onChangeKey:te,onTryoutClick:i,onCancelClick:c,tryItOutEnabled:P,allowTryItOut:A,fn:u,getComponent:l,specActions:f,specSelectors:d,pathMethod:[S,w],getConfigs:p,oas3Actions:v,oas3Selectors:g}):null,P?j.a.createElement(K,{getComponent:l,path:S,method:w,operationServers:M.get("servers"),pathServers:d.paths().getIn([S,"servers"]),getSelectedServer:g.selectedServer,setSelectedServer:v.setSelectedServer,setServerVariableValue:v.setServerVariableValue,getServerVariable:g.serverVariableValue,getEffectiveServerValue:g.serverEffectiveValue}):null,P&&A&&R&&R.size?j.a.createElement("div",{className:"opblock-schemes"},j.a.createElement(Y,{schemes:R,path:S,method:w,specActions:f,currentScheme:B})):null,j.a.createElement("div",{className:P&&r&&A?"btn-group":"execute-wrapper"},P&&A?j.a.createElement(J,{operation:M,specActions:f,specSelectors:d,oas3Selectors:g,oas3Actions:v,path:S,method:w,onExecute:s}):null,P&&r&&A?j.a.createElement(W,{specActions:f,path:S,method:w}):null),I?j.a.createElement("div",{className:"loading-container"},j.a.createElement("div",{className:"loading"})):null,q?j.a.createElement(z,{responses:q,request:o,tryItOutResponse:r,getComponent:l,getConfigs:p,specSelectors:d,oas3Actions:v,oas3Selectors:g,specActions:f,produces:d.producesOptionsFor([S,w]),producesValue:d.currentProducesFor([S,w]),specPath:t.push("responses"),path:S,method:w,displayRequestDuration:k,fn:u}):null,Q&&V.size?j.a.createElement(G,{extensions:V,getComponent:l}):null)))}}]),r}(w.PureComponent);v()(Ue,"defaultProps",{operation:null,response:null,request:null,specPath:Object(O.List)(),summary:""});var Ve=n(55),ze=n.n(Ve),Fe=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.toggleShown,n=e.getComponent,r=e.authActions,o=e.authSelectors,a=e.operationProps,i=e.specPath,c=a.toJS(),s=c.summary,u=c.isAuthorized,l=c.method,p=c.op,f=c.showSummary,d=c.operationId,h=c.originalOperationId,m=c.displayOperationId,v=p.summary,g=a.get("security"),y=n("authorizeOperationBtn"),b=n("OperationSummaryMethod"),E=n("OperationSummaryPath"),x=n("JumpToPath",!0);return j.a.createElement("div",{className:"opblock-summary opblock-summary-".concat(l),onClick:t},j.a.createElement(b,{method:l}),j.a.createElement(E,{getComponent:n,operationProps:a,specPath:i}),f?j.a.createElement("div",{className:"opblock-summary-description"},ze()(v||s)):null,m&&(h||d)?j.a.createElement("span",{className:"opblock-summary-operation-id"},h||d):null,g&&g.count()?j.a.createElement(y,{isAuthorized:u,onClick:function(){var e=o.definitionsForRequirements(g);r.showDefinitions(e)}}):null,j.a.createElement(x,{path:i}))}}]),n}(w.PureComponent);v()(Fe,"defaultProps",{operationProps:null,specPath:Object(O.List)(),summary:""});var Je=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props.method;return j.a.createElement("span",{className:"opblock-summary-method"},e.toUpperCase())}}]),n}(w.PureComponent);v()(Je,"defaultProps",{operationProps:null});var We=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onCopyCapture",(function(t){t.clipboardData.setData("text/plain",e.props.operationProps.get("path")),t.preventDefault()})),e}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.getComponent,n=e.operationProps.toJS(),r=n.deprecated,o=n.isShown,a=n.path,i=n.tag,c=n.operationId,s=n.isDeepLinkingEnabled,u=t("DeepLink");return j.a.createElement("span",{className:r?"opblock-summary-path__deprecated":"opblock-summary-path",onCopyCapture:this.onCopyCapture,"data-path":a},j.a.createElement(u,{enabled:s,isShown:o,path:Object(D.d)("".concat(i,"/").concat(c)),text:a.replace(/\//g,"​/")}))}}]),n}(w.PureComponent),He=n(12),$e=n.n(He),Ye=function(e){var t=e.extensions,n=(0,e.getComponent)("OperationExtRow");return j.a.createElement("div",{className:"opblock-section"},j.a.createElement("div",{className:"opblock-section-header"},j.a.createElement("h4",null,"Extensions")),j.a.createElement("div",{className:"table-container"},j.a.createElement("table",null,j.a.createElement("thead",null,j.a.createElement("tr",null,j.a.createElement("td",{className:"col_header"},"Field"),j.a.createElement("td",{className:"col_header"},"Value"))),j.a.createElement("tbody",null,t.entrySeq().map((function(e){var t=$e()(e,2),r=t[0],o=t[1];return j.a.createElement(n,{key:"".concat(r,"-").concat(o),xKey:r,xVal:o})}))))))},Ke=function(e){var t=e.xKey,n=e.xVal,r=n?n.toJS?n.toJS():n:null;return j.a.createElement("tr",null,j.a.createElement("td",null,t),j.a.createElement("td",null,i()(r)))},Ge=n(67),Ze=n(375),Xe=n.n(Ze).a,Qe=n(374),et=n.n(Qe).a,tt=n(376),nt=n.n(tt).a,rt=n(379),ot=n.n(rt).a,at=n(377),it=n.n(at).a,ct=n(378),st=n.n(ct).a,ut={hljs:{display:"block",overflowX:"auto",padding:"0.5em",background:"#333",color:"white"},"hljs-name":{fontWeight:"bold"},"hljs-strong":{fontWeight:"bold"},"hljs-code":{fontStyle:"italic",color:"#888"},"hljs-emphasis":{fontStyle:"italic"},"hljs-tag":{color:"#62c8f3"},"hljs-variable":{color:"#ade5fc"},"hljs-template-variable":{color:"#ade5fc"},"hljs-selector-id":{color:"#ade5fc"},"hljs-selector-class":{color:"#ade5fc"},"hljs-string":{color:"#a2fca2"},"hljs-bullet":{color:"#d36363"},"hljs-type":{color:"#ffa"},"hljs-title":{color:"#ffa"},"hljs-section":{color:"#ffa"},"hljs-attribute":{color:"#ffa"},"hljs-quote":{color:"#ffa"},"hljs-built_in":{color:"#ffa"},"hljs-builtin-name":{color:"#ffa"},"hljs-number":{color:"#d36363"},"hljs-symbol":{color:"#d36363"},"hljs-keyword":{color:"#fcc28c"},"hljs-selector-tag":{color:"#fcc28c"},"hljs-literal":{color:"#fcc28c"},"hljs-comment":{color:"#888"},"hljs-deletion":{color:"#333",backgroundColor:"#fc9b9b"},"hljs-regexp":{color:"#c6b4f0"},"hljs-link":{color:"#c6b4f0"},"hljs-meta":{color:"#fc9b9b"},"hljs-addition":{backgroundColor:"#a2fca2",color:"#333"}};Ge.Light.registerLanguage("json",et),Ge.Light.registerLanguage("js",Xe),Ge.Light.registerLanguage("xml",nt),Ge.Light.registerLanguage("yaml",it),Ge.Light.registerLanguage("http",st),Ge.Light.registerLanguage("bash",ot);var lt={agate:ut,arta:{hljs:{display:"block",overflowX:"auto",padding:"0.5em",background:"#222",color:"#aaa"},"hljs-subst":{color:"#aaa"},"hljs-section":{color:"#fff",fontWeight:"bold"},"hljs-comment":{color:"#444"},"hljs-quote":{color:"#444"},"hljs-meta":{color:"#444"},"hljs-string":{color:"#ffcc33"},"hljs-symbol":{color:"#ffcc33"},"hljs-bullet":{color:"#ffcc33"},"hljs-regexp":{color:"#ffcc33"},"hljs-number":{color:"#00cc66"},"hljs-addition":{color:"#00cc66"},"hljs-built_in":{color:"#32aaee"},"hljs-builtin-name":{color:"#32aaee"},"hljs-literal":{color:"#32aaee"},"hljs-type":{color:"#32aaee"},"hljs-template-variable":{color:"#32aaee"},"hljs-attribute":{color:"#32aaee"},"hljs-link":{color:"#32aaee"},"hljs-keyword":{color:"#6644aa"},"hljs-selector-tag":{color:"#6644aa"},"hljs-name":{color:"#6644aa"},"hljs-selector-id":{color:"#6644aa"},"hljs-selector-class":{color:"#6644aa"},"hljs-title":{color:"#bb1166"},"hljs-variable":{color:"#bb1166"},"hljs-deletion":{color:"#bb1166"},"hljs-template-tag":{color:"#bb1166"},"hljs-doctag":{fontWeight:"bold"},"hljs-strong":{fontWeight:"bold"},"hljs-emphasis":{fontStyle:"italic"}},monokai:{hljs:{display:"block",overflowX:"auto",padding:"0.5em",background:"#272822",color:"#ddd"},"hljs-tag":{color:"#f92672"},"hljs-keyword":{color:"#f92672",fontWeight:"bold"},"hljs-selector-tag":{color:"#f92672",fontWeight:"bold"},"hljs-literal":{color:"#f92672",fontWeight:"bold"},"hljs-strong":{color:"#f92672"},"hljs-name":{color:"#f92672"},"hljs-code":{color:"#66d9ef"},"hljs-class .hljs-title":{color:"white"},"hljs-attribute":{color:"#bf79db"},"hljs-symbol":{color:"#bf79db"},"hljs-regexp":{color:"#bf79db"},"hljs-link":{color:"#bf79db"},"hljs-string":{color:"#a6e22e"},"hljs-bullet":{color:"#a6e22e"},"hljs-subst":{color:"#a6e22e"},"hljs-title":{color:"#a6e22e",fontWeight:"bold"},"hljs-section":{color:"#a6e22e",fontWeight:"bold"},"hljs-emphasis":{color:"#a6e22e"},"hljs-type":{color:"#a6e22e",fontWeight:"bold"},"hljs-built_in":{color:"#a6e22e"},"hljs-builtin-name":{color:"#a6e22e"},"hljs-selector-attr":{color:"#a6e22e"},"hljs-selector-pseudo":{color:"#a6e22e"},"hljs-addition":{color:"#a6e22e"},"hljs-variable":{color:"#a6e22e"},"hljs-template-tag":{color:"#a6e22e"},"hljs-template-variable":{color:"#a6e22e"},"hljs-comment":{color:"#75715e"},"hljs-quote":{color:"#75715e"},"hljs-deletion":{color:"#75715e"},"hljs-meta":{color:"#75715e"},"hljs-doctag":{fontWeight:"bold"},"hljs-selector-id":{fontWeight:"bold"}},nord:{hljs:{display:"block",overflowX:"auto",padding:"0.5em",background:"#2E3440",color:"#D8DEE9"},"hljs-subst":{color:"#D8DEE9"},"hljs-selector-tag":{color:"#81A1C1"},"hljs-selector-id":{color:"#8FBCBB",fontWeight:"bold"},"hljs-selector-class":{color:"#8FBCBB"},"hljs-selector-attr":{color:"#8FBCBB"},"hljs-selector-pseudo":{color:"#88C0D0"},"hljs-addition":{backgroundColor:"rgba(163, 190, 140, 0.5)"},"hljs-deletion":{backgroundColor:"rgba(191, 97, 106, 0.5)"},"hljs-built_in":{color:"#8FBCBB"},"hljs-type":{color:"#8FBCBB"},"hljs-class":{color:"#8FBCBB"},"hljs-function":{color:"#88C0D0"},"hljs-function > .hljs-title":{color:"#88C0D0"},"hljs-keyword":{color:"#81A1C1"},"hljs-literal":{color:"#81A1C1"},"hljs-symbol":{color:"#81A1C1"},"hljs-number":{color:"#B48EAD"},"hljs-regexp":{color:"#EBCB8B"},"hljs-string":{color:"#A3BE8C"},"hljs-title":{color:"#8FBCBB"},"hljs-params":{color:"#D8DEE9"},"hljs-bullet":{color:"#81A1C1"},"hljs-code":{color:"#8FBCBB"},"hljs-emphasis":{fontStyle:"italic"},"hljs-formula":{color:"#8FBCBB"},"hljs-strong":{fontWeight:"bold"},"hljs-link:hover":{textDecoration:"underline"},"hljs-quote":{color:"#4C566A"},"hljs-comment":{color:"#4C566A"},"hljs-doctag":{color:"#8FBCBB"},"hljs-meta":{color:"#5E81AC"},"hljs-meta-keyword":{color:"#5E81AC"},"hljs-meta-string":{color:"#A3BE8C"},"hljs-attr":{color:"#8FBCBB"},"hljs-attribute":{color:"#D8DEE9"},"hljs-builtin-name":{color:"#81A1C1"},"hljs-name":{color:"#81A1C1"},"hljs-section":{color:"#88C0D0"},"hljs-tag":{color:"#81A1C1"},"hljs-variable":{color:"#D8DEE9"},"hljs-template-variable":{color:"#D8DEE9"},"hljs-template-tag":{color:"#5E81AC"},"abnf .hljs-attribute":{color:"#88C0D0"},"abnf .hljs-symbol":{color:"#EBCB8B"},"apache .hljs-attribute":{color:"#88C0D0"},"apache .hljs-section":{color:"#81A1C1"},"arduino .hljs-built_in":{color:"#88C0D0"},"aspectj .hljs-meta":{color:"#D08770"},"aspectj > .hljs-title":{color:"#88C0D0"},"bnf .hljs-attribute":{color:"#8FBCBB"},"clojure .hljs-name":{color:"#88C0D0"},"clojure .hljs-symbol":{color:"#EBCB8B"},"coq .hljs-built_in":{color:"#88C0D0"},"cpp .hljs-meta-string":{color:"#8FBCBB"},"css .hljs-built_in":{color:"#88C0D0"},"css .hljs-keyword":{color:"#D08770"},"diff .hljs-meta":{color:"#8FBCBB"},"ebnf .hljs-attribute":{color:"#8FBCBB"},"glsl .hljs-built_in":{color:"#88C0D0"},"groovy .hljs-meta:not(:first-child)":{color:"#D08770"},"haxe .hljs-meta":{color:"#D08770"},"java .hljs-meta":{color:"#D08770"},"ldif .hljs-attribute":{color:"#8FBCBB"},"lisp .hljs-name":{color:"#88C0D0"},"lua .hljs-built_in":{color:"#88C0D0"},"moonscript .hljs-built_in":{color:"#88C0D0"},"nginx .hljs-attribute":{color:"#88C0D0"},"nginx .hljs-section":{color:"#5E81AC"},"pf .hljs-built_in":{color:"#88C0D0"},"processing .hljs-built_in":{color:"#88C0D0"},"scss .hljs-keyword":{color:"#81A1C1"},"stylus .hljs-keyword":{color:"#81A1C1"},"swift .hljs-meta":{color:"#D08770"},"vim .hljs-built_in":{color:"#88C0D0",fontStyle:"italic"},"yaml .hljs-meta":{color:"#D08770"}},obsidian:{hljs:{display:"block",overflowX:"auto",padding:"0.5em",background:"#282b2e",color:"#e0e2e4"},"hljs-keyword":{color:"#93c763",fontWeight:"bold"},"hljs-selector-tag":{color:"#93c763",fontWeight:"bold"},"hljs-literal":{color:"#93c763",fontWeight:"bold"},"hljs-selector-id":{color:"#93c763"},"hljs-number":{color:"#ffcd22"},"hljs-attribute":{color:"#668bb0"},"hljs-code":{color:"white"},"hljs-class .hljs-title":{color:"white"},"hljs-section":{color:"white",fontWeight:"bold"},"hljs-regexp":{color:"#d39745"},"hljs-link":{color:"#d39745"},"hljs-meta":{color:"#557182"},"hljs-tag":{color:"#8cbbad"},"hljs-name":{color:"#8cbbad",fontWeight:"bold"},"hljs-bullet":{color:"#8cbbad"},"hljs-subst":{color:"#8cbbad"},"hljs-emphasis":{color:"#8cbbad"},"hljs-type":{color:"#8cbbad",fontWeight:"bold"},"hljs-built_in":{color:"#8cbbad"},"hljs-selector-attr":{color:"#8cbbad"},"hljs-selector-pseudo":{color:"#8cbbad"},"hljs-addition":{color:"#8cbbad"},"hljs-variable":{color:"#8cbbad"},"hljs-template-tag":{color:"#8cbbad"},"hljs-template-variable":{color:"#8cbbad"},"hljs-string":{color:"#ec7600"},"hljs-symbol":{color:"#ec7600"},"hljs-comment":{color:"#818e96"},"hljs-quote":{color:"#818e96"},"hljs-deletion":{color:"#818e96"},"hljs-selector-class":{color:"#A082BD"},"hljs-doctag":{fontWeight:"bold"},"hljs-title":{fontWeight:"bold"},"hljs-strong":{fontWeight:"bold"}},"tomorrow-night":{"hljs-comment":{color:"#969896"},"hljs-quote":{color:"#969896"},"hljs-variable":{color:"#cc6666"},"hljs-template-variable":{color:"#cc6666"},"hljs-tag":{color:"#cc6666"},"hljs-name":{color:"#cc6666"},"hljs-selector-id":{color:"#cc6666"},"hljs-selector-class":{color:"#cc6666"},"hljs-regexp":{color:"#cc6666"},"hljs-deletion":{color:"#cc6666"},"hljs-number":{color:"#de935f"},"hljs-built_in":{color:"#de935f"},"hljs-builtin-name":{color:"#de935f"},"hljs-literal":{color:"#de935f"},"hljs-type":{color:"#de935f"},"hljs-params":{color:"#de935f"},"hljs-meta":{color:"#de935f"},"hljs-link":{color:"#de935f"},"hljs-attribute":{color:"#f0c674"},"hljs-string":{color:"#b5bd68"},"hljs-symbol":{color:"#b5bd68"},"hljs-bullet":{color:"#b5bd68"},"hljs-addition":{color:"#b5bd68"},"hljs-title":{color:"#81a2be"},"hljs-section":{color:"#81a2be"},"hljs-keyword":{color:"#b294bb"},"hljs-selector-tag":{color:"#b294bb"},hljs:{display:"block",overflowX:"auto",background:"#1d1f21",color:"#c5c8c6",padding:"0.5em"},"hljs-emphasis":{fontStyle:"italic"},"hljs-strong":{fontWeight:"bold"}}},pt=s()(lt),ft=function(e){return pt.includes(e)?lt[e]:(console.warn("Request style '".concat(e,"' is not available, returning default instead")),ut)},dt=n(25),ht=n.n(dt),mt=n(380),vt=n.n(mt),gt=n(151),yt=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"downloadText",(function(){vt()(e.props.value,e.props.fileName||"response.txt")})),v()(ae()(e),"preventYScrollingBeyondElement",(function(e){var t=e.target,n=e.nativeEvent.deltaY,r=t.scrollHeight,o=t.offsetHeight,a=t.scrollTop;r>o&&(0===a&&n<0||o+a>=r&&n>0)&&e.preventDefault()})),e}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.value,n=e.className,r=e.downloadable,o=e.getConfigs,a=e.canCopy,i=o?o():{syntaxHighlight:{activated:!0,theme:"agate"}};n=n||"";var c=ht()(i,"syntaxHighlight.activated")?j.a.createElement(Ge.Light,{className:n+" microlight",onWheel:this.preventYScrollingBeyondElement,style:ft(ht()(i,"syntaxHighlight.theme"))},t):j.a.createElement("pre",{onWheel:this.preventYScrollingBeyondElement,className:n+" microlight"},t);return j.a.createElement("div",{className:"highlight-code"},r?j.a.createElement("div",{className:"download-contents",onClick:this.downloadText},"Download"):null,a?j.a.createElement("div",{className:"copy-to-clipboard"},j.a.createElement(gt.CopyToClipboard,{text:t},j.a.createElement("button",null))):null,c)}}]),n}(w.Component),bt=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onChangeProducesWrapper",(function(t){return e.props.specActions.changeProducesValue([e.props.path,e.props.method],t)})),v()(ae()(e),"onResponseContentTypeChange",(function(t){var n=t.controlsAcceptHeader,r=t.value,o=e.props,a=o.oas3Actions,i=o.path,c=o.method;n&&a.setResponseContentType({value:r,path:i,method:c})})),e}return S()(n,[{key:"render",value:function(){var e=this,t=this.props,r=t.responses,o=t.tryItOutResponse,a=t.getComponent,i=t.getConfigs,c=t.specSelectors,s=t.fn,u=t.producesValue,l=t.displayRequestDuration,p=t.specPath,f=t.path,d=t.method,h=t.oas3Selectors,m=t.oas3Actions,v=Object(D.f)(r),g=a("contentType"),y=a("liveResponse"),b=a("response"),E=this.props.produces&&this.props.produces.size?this.props.produces:n.defaultProps.produces,x=c.isOAS3()?Object(D.k)(r):null;return j.a.createElement("div",{className:"responses-wrapper"},j.a.createElement("div",{className:"opblock-section-header"},j.a.createElement("h4",null,"Responses"),c.isOAS3()?null:j.a.createElement("label",null,j.a.createElement("span",null,"Response content type"),j.a.createElement(g,{value:u,onChange:this.onChangeProducesWrapper,contentTypes:E,className:"execute-content-type"}))),j.a.createElement("div",{className:"responses-inner"},o?j.a.createElement("div",null,j.a.createElement(y,{response:o,getComponent:a,getConfigs:i,specSelectors:c,path:this.props.path,method:this.props.method,displayRequestDuration:l}),j.a.createElement("h4",null,"Responses")):null,j.a.createElement("table",{className:"responses-table"},j.a.createElement("thead",null,j.a.createElement("tr",{className:"responses-header"},j.a.createElement("td",{className:"col_header response-col_status"},"Code"),j.a.createElement("td",{className:"col_header response-col_description"},"Description"),c.isOAS3()?j.a.createElement("td",{className:"col col_header response-col_links"},"Links"):null)),j.a.createElement("tbody",null,r.entrySeq().map((function(t){var n=$e()(t,2),r=n[0],l=n[1],g=o&&o.get("status")==r?"response_current":"";return j.a.createElement(b,{key:r,path:f,method:d,specPath:p.push(r),isDefault:v===r,fn:s,className:g,code:r,response:l,specSelectors:c,controlsAcceptHeader:l===x,onContentTypeChange:e.onResponseContentTypeChange,contentType:u,getConfigs:i,activeExamplesKey:h.activeExamplesMember(f,d,"responses",r),oas3Actions:m,getComponent:a})})).toArray()))))}}]),n}(j.a.Component);v()(bt,"defaultProps",{tryItOutResponse:null,produces:Object(O.fromJS)(["application/json"]),displayRequestDuration:!1});var Et=n(45),xt=n.n(Et),St=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;return E()(this,n),o=t.call(this,e,r),v()(ae()(o),"_onContentTypeChange",(function(e){var t=o.props,n=t.onContentTypeChange,r=t.controlsAcceptHeader;o.setState({responseContentType:e}),n({value:e,controlsAcceptHeader:r})})),v()(ae()(o),"getTargetExamplesKey",(function(){var e=o.props,t=e.response,n=e.contentType,r=e.activeExamplesKey,a=o.state.responseContentType||n,i=t.getIn(["content",a],Object(O.Map)({})).get("examples",null).keySeq().first();return r||i})),o.state={responseContentType:""},o}return S()(n,[{key:"render",value:function(){var e,t,n,r=this.props,o=r.path,a=r.method,i=r.code,c=r.response,s=r.className,u=r.specPath,l=r.fn,p=r.getComponent,f=r.getConfigs,d=r.specSelectors,h=r.contentType,m=r.controlsAcceptHeader,v=r.oas3Actions,g=l.inferSchema,y=d.isOAS3(),b=c.get("headers"),E=c.get("links"),x=p("headers"),S=p("highlightCode"),w=p("modelExample"),_=p("Markdown",!0),C=p("operationLink"),A=p("contentType"),k=p("ExamplesSelect"),P=p("Example"),I=this.state.responseContentType||h,T=c.getIn(["content",I],Object(O.Map)({})),N=T.get("examples",null);if(y){var R=T.get("schema");t=R?g(R.toJS()):null,n=R?Object(O.List)(["content",this.state.responseContentType,"schema"]):u}else t=c.get("schema"),n=c.has("schema")?u.push("schema"):u;if(y){var M=T.get("schema",Object(O.Map)({}));if(N){var q=this.getTargetExamplesKey(),L=N.get(q,Object(O.Map)({}));e=Object(D.J)(L.get("value"))}else e=void 0!==T.get("example")?Object(D.J)(T.get("example")):Object(D.o)(M.toJS(),this.state.responseContentType,{includeReadOnly:!0})}else e=c.getIn(["examples",I])?c.getIn(["examples",I]):t?Object(D.o)(t.toJS(),I,{includeReadOnly:!0,includeWriteOnly:!0}):null;var B=function(e,t,n){return null!=e?j.a.createElement("div",null,j.a.createElement(t,{className:"example",getConfigs:n,value:Object(D.J)(e)})):null}(e,S,f);return j.a.createElement("tr",{className:"response "+(s||""),"data-code":i},j.a.createElement("td",{className:"response-col_status"},i),j.a.createElement("td",{className:"response-col_description"},j.a.createElement("div",{className:"response-col_description__inner"},j.a.createElement(_,{source:c.get("description")})),y&&c.get("content")?j.a.createElement("section",{className:"response-controls"},j.a.createElement("div",{className:xt()("response-control-media-type",{"response-control-media-type--accept-controller":m})},j.a.createElement("small",{className:"response-control-media-type__title"},"Media type"),j.a.createElement(A,{value:this.state.responseContentType,contentTypes:c.get("content")?c.get("content").keySeq():Object(O.Seq)(),onChange:this._onContentTypeChange}),m?j.a.createElement("small",{className:"response-control-media-type__accept-message"},"Controls ",j.a.createElement("code",null,"Accept")," header."):null),N?j.a.createElement("div",{className:"response-control-examples"},j.a.createElement("small",{className:"response-control-examples__title"},"Examples"),j.a.createElement(k,{examples:N,currentExampleKey:this.getTargetExamplesKey(),onSelect:function(e){return v.setActiveExamplesMember({name:e,pathMethod:[o,a],contextType:"responses",contextName:i})},showLabels:!1})):null):null,B||t?j.a.createElement(w,{specPath:n,getComponent:p,getConfigs:f,specSelectors:d,schema:Object(D.i)(t),example:B,includeReadOnly:!0}):null,y&&N?j.a.createElement(P,{example:N.get(this.getTargetExamplesKey(),Object(O.Map)({})),getComponent:p,omitValue:!0}):null,b?j.a.createElement(x,{headers:b,getComponent:p}):null),y?j.a.createElement("td",{className:"response-col_links"},E?E.toSeq().map((function(e,t){return j.a.createElement(C,{key:t,name:t,link:e,getComponent:p})})):j.a.createElement("i",null,"No links")):null)}}]),n}(j.a.Component);v()(St,"defaultProps",{response:Object(O.fromJS)({}),onContentTypeChange:function(){}});var wt=n(381),jt=n.n(wt),_t=n(382),Ot=n.n(_t),Ct=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"state",{parsedContent:null}),v()(ae()(e),"updateParsedContent",(function(t){var n=e.props.content;if(t!==n)if(n&&n instanceof Blob){var r=new FileReader;r.onload=function(){e.setState({parsedContent:r.result})},r.readAsText(n)}else e.setState({parsedContent:n.toString()})})),e}return S()(n,[{key:"componentDidMount",value:function(){this.updateParsedContent(null)}},{key:"componentDidUpdate",value:function(e){this.updateParsedContent(e.content)}},{key:"render",value:function(){var e,t,n=this.props,r=n.content,o=n.contentType,a=n.url,c=n.headers,s=void 0===c?{}:c,u=n.getConfigs,l=n.getComponent,p=this.state.parsedContent,f=l("highlightCode"),d="response_"+(new Date).getTime();if(a=a||"",/^application\/octet-stream/i.test(o)||s["Content-Disposition"]&&/attachment/i.test(s["Content-Disposition"])||s["content-disposition"]&&/attachment/i.test(s["content-disposition"])||s["Content-Description"]&&/File Transfer/i.test(s["Content-Description"])||s["content-description"]&&/File Transfer/i.test(s["content-description"]))if("Blob"in window){var h=o||"text/html",m=r instanceof Blob?r:new Blob([r],{type:h}),v=window.URL.createObjectURL(m),g=[h,a.substr(a.lastIndexOf("/")+1),v].join(":"),y=s["content-disposition"]||s["Content-Disposition"];if(void 0!==y){var b=Object(D.h)(y);null!==b&&(g=b)}t=M.a.navigator&&M.a.navigator.msSaveOrOpenBlob?j.a.createElement("div",null,j.a.createElement("a",{href:v,onClick:function(){return M.a.navigator.msSaveOrOpenBlob(m,g)}},"Download file")):j.a.createElement("div",null,j.a.createElement("a",{href:v,download:g},"Download file"))}else t=j.a.createElement("pre",{className:"microlight"},"Download headers detected but your browser does not support downloading binary via XHR (Blob).");else if(/json/i.test(o)){try{e=i()(JSON.parse(r),null,"  ")}catch(t){e="can't parse JSON.  Raw result:\n\n"+r}t=j.a.createElement(f,{downloadable:!0,fileName:"".concat(d,".json"),value:e,getConfigs:u,canCopy:!0})}else/xml/i.test(o)?(e=jt()(r,{textNodesOnSameLine:!0,indentor:"  "}),t=j.a.createElement(f,{downloadable:!0,fileName:"".concat(d,".xml"),value:e,getConfigs:u,canCopy:!0})):t="text/html"===Ot()(o)||/text\/plain/.test(o)?j.a.createElement(f,{downloadable:!0,fileName:"".concat(d,".html"),value:r,getConfigs:u,canCopy:!0}):/^image\//i.test(o)?o.includes("svg")?j.a.createElement("div",null," ",r," "):j.a.createElement("img",{className:"full-width",src:window.URL.createObjectURL(r)}):/^audio\//i.test(o)?j.a.createElement("pre",{className:"microlight"},j.a.createElement("audio",{controls:!0},j.a.createElement("source",{src:a,type:o}))):"string"==typeof r?j.a.createElement(f,{downloadable:!0,fileName:"".concat(d,".txt"),value:r,getConfigs:u,canCopy:!0}):r.size>0?p?j.a.createElement("div",null,j.a.createElement("p",{className:"i"},"Unrecognized response type; displaying content as text."),j.a.createElement(f,{downloadable:!0,fileName:"".concat(d,".txt"),value:p,getConfigs:u,canCopy:!0})):j.a.createElement("p",{className:"i"},"Unrecognized response type; unable to display."):null;return t?j.a.createElement("div",null,j.a.createElement("h5",null,"Response body"),t):null}}]),n}(j.a.PureComponent),At=n(13),kt=n.n(At),Pt=function(e){ce()(n,e);var t=ue()(n);function n(e){var r;return E()(this,n),r=t.call(this,e),v()(ae()(r),"onChange",(function(e,t,n){var o=r.props;(0,o.specActions.changeParamByIdentity)(o.onChangeKey,e,t,n)})),v()(ae()(r),"onChangeConsumesWrapper",(function(e){var t=r.props;(0,t.specActions.changeConsumesValue)(t.onChangeKey,e)})),v()(ae()(r),"toggleTab",(function(e){return"parameters"===e?r.setState({parametersVisible:!0,callbackVisible:!1}):"callbacks"===e?r.setState({callbackVisible:!0,parametersVisible:!1}):void 0})),r.state={callbackVisible:!1,parametersVisible:!0},r}return S()(n,[{key:"render",value:function(){var e=this,t=this.props,n=t.onTryoutClick,r=t.onCancelClick,o=t.parameters,a=t.allowTryItOut,i=t.tryItOutEnabled,c=t.specPath,s=t.fn,u=t.getComponent,l=t.getConfigs,p=t.specSelectors,f=t.specActions,d=t.pathMethod,h=t.oas3Actions,m=t.oas3Selectors,v=t.operation,g=u("parameterRow"),y=u("TryItOutButton"),b=u("contentType"),E=u("Callbacks",!0),x=u("RequestBody",!0),S=i&&a,w=p.isOAS3(),_=v.get("requestBody");return j.a.createElement("div",{className:"opblock-section"},j.a.createElement("div",{className:"opblock-section-header"},w?j.a.createElement("div",{className:"tab-header"},j.a.createElement("div",{onClick:function(){return e.toggleTab("parameters")},className:"tab-item ".concat(this.state.parametersVisible&&"active")},j.a.createElement("h4",{className:"opblock-title"},j.a.createElement("span",null,"Parameters"))),v.get("callbacks")?j.a.createElement("div",{onClick:function(){return e.toggleTab("callbacks")},className:"tab-item ".concat(this.state.callbackVisible&&"active")},j.a.createElement("h4",{className:"opblock-title"},j.a.createElement("span",null,"Callbacks"))):null):j.a.createElement("div",{className:"tab-header"},j.a.createElement("h4",{className:"opblock-title"},"Parameters")),a?j.a.createElement(y,{enabled:i,onCancelClick:r,onTryoutClick:n}):null),this.state.parametersVisible?j.a.createElement("div",{className:"parameters-container"},o.count()?j.a.createElement("div",{className:"table-container"},j.a.createElement("table",{className:"parameters"},j.a.createElement("thead",null,j.a.createElement("tr",null,j.a.createElement("th",{className:"col_header parameters-col_name"},"Name"),j.a.createElement("th",{className:"col_header parameters-col_description"},"Description"))),j.a.createElement("tbody",null,function(e,t){return e.valueSeq().filter(C.a.Map.isMap).map(t)}(o,(function(t,n){return j.a.createElement(g,{fn:s,specPath:c.push(n.toString()),getComponent:u,getConfigs:l,rawParam:t,param:p.parameterWithMetaByIdentity(d,t),key:"".concat(t.get("in"),".").concat(t.get("name")),onChange:e.onChange,onChangeConsumes:e.onChangeConsumesWrapper,specSelectors:p,specActions:f,oas3Actions:h,oas3Selectors:m,pathMethod:d,isExecute:S})})).toArray()))):j.a.createElement("div",{className:"opblock-description-wrapper"},j.a.createElement("p",null,"No parameters"))):null,this.state.callbackVisible?j.a.createElement("div",{className:"callbacks-container opblock-description-wrapper"},j.a.createElement(E,{callbacks:Object(O.Map)(v.get("callbacks")),specPath:c.slice(0,-1).push("callbacks")})):null,w&&_&&this.state.parametersVisible&&j.a.createElement("div",{className:"opblock-section opblock-section-request-body"},j.a.createElement("div",{className:"opblock-section-header"},j.a.createElement("h4",{className:"opblock-title parameter__name ".concat(_.get("required")&&"required")},"Request body"),j.a.createElement("label",null,j.a.createElement(b,{value:m.requestContentType.apply(m,kt()(d)),contentTypes:_.get("content",Object(O.List)()).keySeq(),onChange:function(e){h.setRequestContentType({value:e,pathMethod:d}),h.initRequestBodyValidateError({pathMethod:d})},className:"body-param-content-type"}))),j.a.createElement("div",{className:"opblock-description-wrapper"},j.a.createElement(x,{specPath:c.slice(0,-1).push("requestBody"),requestBody:_,requestBodyValue:m.requestBodyValue.apply(m,kt()(d)),requestBodyInclusionSetting:m.requestBodyInclusionSetting.apply(m,kt()(d)),requestBodyErrors:m.requestBodyErrors.apply(m,kt()(d)),isExecute:S,activeExamplesKey:m.activeExamplesMember.apply(m,kt()(d).concat(["requestBody","requestBody"])),updateActiveExamplesKey:function(t){e.props.oas3Actions.setActiveExamplesMember({name:t,pathMethod:e.props.pathMethod,contextType:"requestBody",contextName:"requestBody"})},onChange:function(e,t){if(t){var n=m.requestBodyValue.apply(m,kt()(d)),r=O.Map.isMap(n)?n:Object(O.Map)();return h.setRequestBodyValue({pathMethod:d,value:r.setIn(t,e)})}h.setRequestBodyValue({value:e,pathMethod:d})},onChangeIncludeEmpty:function(e,t){h.setRequestBodyInclusion({pathMethod:d,value:t,name:e})},contentType:m.requestContentType.apply(m,kt()(d))}))))}}]),n}(w.Component);v()(Pt,"defaultProps",{onTryoutClick:Function.prototype,onCancelClick:Function.prototype,tryItOutEnabled:!1,allowTryItOut:!0,onChangeKey:[],specPath:[]});var It=function(e){var t=e.xKey,n=e.xVal;return j.a.createElement("div",{className:"parameter__extension"},t,": ",String(n))},Tt={onChange:function(){},isIncludedOptions:{}},Nt=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onCheckboxChange",(function(t){(0,e.props.onChange)(t.target.checked)})),e}return S()(n,[{key:"componentDidMount",value:function(){var e=this.props,t=e.isIncludedOptions,n=e.onChange,r=t.shouldDispatchInit,o=t.defaultValue;r&&n(o)}},{key:"render",value:function(){var e=this.props,t=e.isIncluded,n=e.isDisabled;return j.a.createElement("div",null,j.a.createElement("label",{className:xt()("parameter__empty_value_toggle",{disabled:n})},j.a.createElement("input",{type:"checkbox",disabled:n,checked:!n&&t,onChange:this.onCheckboxChange}),"Send empty value"))}}]),n}(w.Component);v()(Nt,"defaultProps",Tt);var Rt=n(95),Mt=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;return E()(this,n),o=t.call(this,e,r),v()(ae()(o),"onChangeWrapper",(function(e){var t=arguments.length>1&&void 0!==arguments[1]&&arguments[1],n=o.props,r=n.onChange,a=n.rawParam;return r(a,""===e||e&&0===e.size?null:e,t)})),v()(ae()(o),"_onExampleSelect",(function(e){o.props.oas3Actions.setActiveExamplesMember({name:e,pathMethod:o.props.pathMethod,contextType:"parameters",contextName:o.getParamKey()})})),v()(ae()(o),"onChangeIncludeEmpty",(function(e){var t=o.props,n=t.specActions,r=t.param,a=t.pathMethod,i=r.get("name"),c=r.get("in");return n.updateEmptyParamInclusion(a,i,c,e)})),v()(ae()(o),"setDefaultValue",(function(){var e=o.props,t=e.specSelectors,n=e.pathMethod,r=e.rawParam,a=e.oas3Selectors,i=t.parameterWithMetaByIdentity(n,r)||Object(O.Map)(),c=Object(Rt.a)(i,{isOAS3:t.isOAS3()}).schema,s=i.get("content",Object(O.Map)()).keySeq().first(),u=c?Object(D.o)(c.toJS(),s,{includeWriteOnly:!0}):null;if(i&&void 0===i.get("value")&&"body"!==i.get("in")){var l;if(t.isSwagger2())l=i.get("x-example")||i.getIn(["schema","example"])||c&&c.getIn(["default"]);else if(t.isOAS3()){var p=a.activeExamplesMember.apply(a,kt()(n).concat(["parameters",o.getParamKey()]));l=i.getIn(["examples",p,"value"])||i.getIn(["content",s,"example"])||i.get("example")||c&&c.get("example")||c&&c.get("default")||i.get("default")}void 0===l||O.List.isList(l)||(l=Object(D.J)(l)),void 0!==l?o.onChangeWrapper(l):c&&"object"===c.get("type")&&u&&!i.get("examples")&&o.onChangeWrapper(O.List.isList(u)?u:Object(D.J)(u))}})),o.setDefaultValue(),o}return S()(n,[{key:"componentWillReceiveProps",value:function(e){var t,n=e.specSelectors,r=e.pathMethod,o=e.rawParam,a=n.isOAS3(),i=n.parameterWithMetaByIdentity(r,o)||new O.Map;if(i=i.isEmpty()?o:i,a){var c=Object(Rt.a)(i,{isOAS3:a}).schema;t=c?c.get("enum"):void 0}else t=i?i.get("enum"):void 0;var s,u=i?i.get("value"):void 0;void 0!==u?s=u:o.get("required")&&t&&t.size&&(s=t.first()),void 0!==s&&s!==u&&this.onChangeWrapper(Object(D.x)(s)),this.setDefaultValue()}},{key:"getParamKey",value:function(){var e=this.props.param;return e?"".concat(e.get("name"),"-").concat(e.get("in")):null}},{key:"render",value:function(){var e=this.props,t=e.param,n=e.rawParam,r=e.getComponent,o=e.getConfigs,a=e.isExecute,i=e.fn,c=e.onChangeConsumes,s=e.specSelectors,u=e.pathMethod,l=e.specPath,p=e.oas3Selectors,f=s.isOAS3(),d=o(),h=d.showExtensions,m=d.showCommonExtensions;if(t||(t=n),!n)return null;var v,g,y,b,E=r("JsonSchemaForm"),x=r("ParamBody"),S=t.get("in"),w="body"!==S?null:j.a.createElement(x,{getComponent:r,getConfigs:o,fn:i,param:t,consumes:s.consumesOptionsFor(u),consumesValue:s.contentTypeValues(u).get("requestContentType"),onChange:this.onChangeWrapper,onChangeConsumes:c,isExecute:a,specSelectors:s,pathMethod:u}),_=r("modelExample"),C=r("Markdown",!0),A=r("ParameterExt"),k=r("ParameterIncludeEmpty"),P=r("ExamplesSelectValueRetainer"),I=r("Example"),T=Object(Rt.a)(t,{isOAS3:f}).schema,N=s.parameterWithMetaByIdentity(u,n)||Object(O.Map)(),R=T?T.get("format"):null,q=T?T.get("type"):null,L=T?T.getIn(["items","type"]):null,B="formData"===S,U="FormData"in M.a,V=t.get("required"),z=N?N.get("value"):"",F=m?Object(D.l)(T):null,J=h?Object(D.m)(t):null,W=!1;return void 0!==t&&T&&(v=T.get("items")),void 0!==v?(g=v.get("enum"),y=v.get("default")):T&&(g=T.get("enum")),g&&g.size&&g.size>0&&(W=!0),void 0!==t&&(T&&(y=T.get("default")),void 0===y&&(y=t.get("default")),void 0===(b=t.get("example"))&&(b=t.get("x-example"))),j.a.createElement("tr",{"data-param-name":t.get("name"),"data-param-in":t.get("in")},j.a.createElement("td",{className:"parameters-col_name"},j.a.createElement("div",{className:V?"parameter__name required":"parameter__name"},t.get("name"),V?j.a.createElement("span",null," *"):null),j.a.createElement("div",{className:"parameter__type"},q,L&&"[".concat(L,"]"),R&&j.a.createElement("span",{className:"prop-format"},"($",R,")")),j.a.createElement("div",{className:"parameter__deprecated"},f&&t.get("deprecated")?"deprecated":null),j.a.createElement("div",{className:"parameter__in"},"(",t.get("in"),")"),m&&F.size?F.entrySeq().map((function(e){var t=$e()(e,2),n=t[0],r=t[1];return j.a.createElement(A,{key:"".concat(n,"-").concat(r),xKey:n,xVal:r})})):null,h&&J.size?J.map((function(e,t){return j.a.createElement(A,{key:"".concat(t,"-").concat(e),xKey:t,xVal:e})})):null),j.a.createElement("td",{className:"parameters-col_description"},t.get("description")?j.a.createElement(C,{source:t.get("description")}):null,!w&&a||!W?null:j.a.createElement(C,{className:"parameter__enum",source:"<i>Available values</i> : "+g.map((function(e){return e})).toArray().join(", ")}),!w&&a||void 0===y?null:j.a.createElement(C,{className:"parameter__default",source:"<i>Default value</i> : "+y}),!w&&a||void 0===b?null:j.a.createElement(C,{source:"<i>Example</i> : "+b}),B&&!U&&j.a.createElement("div",null,"Error: your browser does not support FormData"),f&&t.get("examples")?j.a.createElement("section",{className:"parameter-controls"},j.a.createElement(P,{examples:t.get("examples"),onSelect:this._onExampleSelect,updateValue:this.onChangeWrapper,getComponent:r,defaultToFirstExample:!0,currentKey:p.activeExamplesMember.apply(p,kt()(u).concat(["parameters",this.getParamKey()])),currentUserInputValue:z})):null,w?null:j.a.createElement(E,{fn:i,getComponent:r,value:z,required:V,disabled:!a,description:t.get("description")?"".concat(t.get("name")," - ").concat(t.get("description")):"".concat(t.get("name")),onChange:this.onChangeWrapper,errors:N.get("errors"),schema:T}),w&&T?j.a.createElement(_,{getComponent:r,specPath:l.push("schema"),getConfigs:o,isExecute:a,specSelectors:s,schema:T,example:w,includeWriteOnly:!0}):null,!w&&a&&t.get("allowEmptyValue")?j.a.createElement(k,{onChange:this.onChangeIncludeEmpty,isIncluded:s.parameterInclusionSettingFor(u,t.get("name"),t.get("in")),isDisabled:!Object(D.q)(z)}):null,f&&t.get("examples")?j.a.createElement(I,{example:t.getIn(["examples",p.activeExamplesMember.apply(p,kt()(u).concat(["parameters",this.getParamKey()]))]),getComponent:r}):null))}}]),n}(w.Component),Dt=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"handleValidateParameters",(function(){var t=e.props,n=t.specSelectors,r=t.specActions,o=t.path,a=t.method;return r.validateParams([o,a]),n.validateBeforeExecute([o,a])})),v()(ae()(e),"handleValidateRequestBody",(function(){var t=e.props,n=t.path,r=t.method,o=t.specSelectors,a=t.oas3Selectors,i=t.oas3Actions,c={missingBodyValue:!1,missingRequiredKeys:[]};i.clearRequestBodyValidateError({path:n,method:r});var s=o.getOAS3RequiredRequestBodyContentType([n,r]),u=a.requestBodyValue(n,r);if(!a.validateBeforeExecute([n,r]))return c.missingBodyValue=!0,i.setRequestBodyValidateError({path:n,method:r,validationErrors:c}),!1;if(!s)return!0;var l=a.validateShallowRequired({oas3RequiredRequestBodyContentType:s,oas3RequestBodyValue:u});return!l||l.length<1||(l.forEach((function(e){c.missingRequiredKeys.push(e)})),i.setRequestBodyValidateError({path:n,method:r,validationErrors:c}),!1)})),v()(ae()(e),"handleValidationResultPass",(function(){var t=e.props,n=t.specActions,r=t.operation,o=t.path,a=t.method;e.props.onExecute&&e.props.onExecute(),n.execute({operation:r,path:o,method:a})})),v()(ae()(e),"handleValidationResultFail",(function(){var t=e.props,n=t.specActions,r=t.path,o=t.method;n.clearValidateParams([r,o]),setTimeout((function(){n.validateParams([r,o])}),40)})),v()(ae()(e),"handleValidationResult",(function(t){t?e.handleValidationResultPass():e.handleValidationResultFail()})),v()(ae()(e),"onClick",(function(){var t=e.handleValidateParameters(),n=e.handleValidateRequestBody(),r=t&&n;e.handleValidationResult(r)})),v()(ae()(e),"onChangeProducesWrapper",(function(t){return e.props.specActions.changeProducesValue([e.props.path,e.props.method],t)})),e}return S()(n,[{key:"render",value:function(){return j.a.createElement("button",{className:"btn execute opblock-control__btn",onClick:this.onClick},"Execute")}}]),n}(w.Component),qt=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.headers,n=e.getComponent,r=n("Property"),o=n("Markdown",!0);return t&&t.size?j.a.createElement("div",{className:"headers-wrapper"},j.a.createElement("h4",{className:"headers__title"},"Headers:"),j.a.createElement("table",{className:"headers"},j.a.createElement("thead",null,j.a.createElement("tr",{className:"header-row"},j.a.createElement("th",{className:"header-col"},"Name"),j.a.createElement("th",{className:"header-col"},"Description"),j.a.createElement("th",{className:"header-col"},"Type"))),j.a.createElement("tbody",null,t.entrySeq().map((function(e){var t=$e()(e,2),n=t[0],a=t[1];if(!C.a.Map.isMap(a))return null;var i=a.get("description"),c=a.getIn(["schema"])?a.getIn(["schema","type"]):a.getIn(["type"]),s=a.getIn(["schema","example"]);return j.a.createElement("tr",{key:n},j.a.createElement("td",{className:"header-col"},n),j.a.createElement("td",{className:"header-col"},i?j.a.createElement(o,{source:i}):null),j.a.createElement("td",{className:"header-col"},c," ",s?j.a.createElement(r,{propKey:"Example",propVal:s,propClass:"header-example"}):null))})).toArray()))):null}}]),n}(j.a.Component),Lt=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.editorActions,n=e.errSelectors,r=e.layoutSelectors,o=e.layoutActions,a=(0,e.getComponent)("Collapse");if(t&&t.jumpToLine)var i=t.jumpToLine;var c=n.allErrors().filter((function(e){return"thrown"===e.get("type")||"error"===e.get("level")}));if(!c||c.count()<1)return null;var s=r.isShown(["errorPane"],!0),u=c.sortBy((function(e){return e.get("line")}));return j.a.createElement("pre",{className:"errors-wrapper"},j.a.createElement("hgroup",{className:"error"},j.a.createElement("h4",{className:"errors__title"},"Errors"),j.a.createElement("button",{className:"btn errors__clear-btn",onClick:function(){return o.show(["errorPane"],!s)}},s?"Hide":"Show")),j.a.createElement(a,{isOpened:s,animated:!0},j.a.createElement("div",{className:"errors"},u.map((function(e,t){var n=e.get("type");return"thrown"===n||"auth"===n?j.a.createElement(Bt,{key:t,error:e.get("error")||e,jumpToLine:i}):"spec"===n?j.a.createElement(Ut,{key:t,error:e,jumpToLine:i}):void 0})))))}}]),n}(j.a.Component),Bt=function(e){var t=e.error,n=e.jumpToLine;if(!t)return null;var r=t.get("line");return j.a.createElement("div",{className:"error-wrapper"},t?j.a.createElement("div",null,j.a.createElement("h4",null,t.get("source")&&t.get("level")?Vt(t.get("source"))+" "+t.get("level"):"",t.get("path")?j.a.createElement("small",null," at ",t.get("path")):null),j.a.createElement("span",{className:"message thrown"},t.get("message")),j.a.createElement("div",{className:"error-line"},r&&n?j.a.createElement("a",{onClick:n.bind(null,r)},"Jump to line ",r):null)):null)},Ut=function(e){var t=e.error,n=e.jumpToLine,r=null;return t.get("path")?r=O.List.isList(t.get("path"))?j.a.createElement("small",null,"at ",t.get("path").join(".")):j.a.createElement("small",null,"at ",t.get("path")):t.get("line")&&!n&&(r=j.a.createElement("small",null,"on line ",t.get("line"))),j.a.createElement("div",{className:"error-wrapper"},t?j.a.createElement("div",null,j.a.createElement("h4",null,Vt(t.get("source"))+" "+t.get("level")," ",r),j.a.createElement("span",{className:"message"},t.get("message")),j.a.createElement("div",{className:"error-line"},n?j.a.createElement("a",{onClick:n.bind(null,t.get("line"))},"Jump to line ",t.get("line")):null)):null)};function Vt(e){return(e||"").split(" ").map((function(e){return e[0].toUpperCase()+e.slice(1)})).join(" ")}Bt.defaultProps={jumpToLine:null};var zt=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onChangeWrapper",(function(t){return e.props.onChange(t.target.value)})),e}return S()(n,[{key:"componentDidMount",value:function(){this.props.contentTypes&&this.props.onChange(this.props.contentTypes.first())}},{key:"componentWillReceiveProps",value:function(e){e.contentTypes&&e.contentTypes.size&&(e.contentTypes.includes(e.value)||e.onChange(e.contentTypes.first()))}},{key:"render",value:function(){var e=this.props,t=e.contentTypes,n=e.className,r=e.value;return t&&t.size?j.a.createElement("div",{className:"content-type-wrapper "+(n||"")},j.a.createElement("select",{className:"content-type",value:r||"",onChange:this.onChangeWrapper},t.map((function(e){return j.a.createElement("option",{key:e,value:e},e)})).toArray())):null}}]),n}(j.a.Component);v()(zt,"defaultProps",{onChange:function(){},value:null,contentTypes:Object(O.fromJS)(["application/json"])});var Ft=n(19),Jt=n.n(Ft),Wt=n(34),Ht=n.n(Wt);function $t(){for(var e=arguments.length,t=new Array(e),n=0;n<e;n++)t[n]=arguments[n];return t.filter((function(e){return!!e})).join(" ").trim()}var Yt=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.fullscreen,n=e.full,r=Ht()(e,["fullscreen","full"]);if(t)return j.a.createElement("section",r);var o="swagger-container"+(n?"-full":"");return j.a.createElement("section",Jt()({},r,{className:$t(r.className,o)}))}}]),n}(j.a.Component),Kt={mobile:"",tablet:"-tablet",desktop:"-desktop",large:"-hd"},Gt=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.hide,n=e.keepContents,r=(e.mobile,e.tablet,e.desktop,e.large,Ht()(e,["hide","keepContents","mobile","tablet","desktop","large"]));if(t&&!n)return j.a.createElement("span",null);var o=[];for(var a in Kt)if(Kt.hasOwnProperty(a)){var i=Kt[a];if(a in this.props){var c=this.props[a];if(c<1){o.push("none"+i);continue}o.push("block"+i),o.push("col-"+c+i)}}t&&o.push("hidden");var s=$t.apply(void 0,[r.className].concat(o));return j.a.createElement("section",Jt()({},r,{className:s}))}}]),n}(j.a.Component),Zt=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){return j.a.createElement("div",Jt()({},this.props,{className:$t(this.props.className,"wrapper")}))}}]),n}(j.a.Component),Xt=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){return j.a.createElement("button",Jt()({},this.props,{className:$t(this.props.className,"button")}))}}]),n}(j.a.Component);v()(Xt,"defaultProps",{className:""});var Qt=function(e){return j.a.createElement("textarea",e)},en=function(e){return j.a.createElement("input",e)},tn=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o,a;return E()(this,n),o=t.call(this,e,r),v()(ae()(o),"onChange",(function(e){var t,n=o.props,r=n.onChange,a=n.multiple,i=[].slice.call(e.target.options);t=a?i.filter((function(e){return e.selected})).map((function(e){return e.value})):e.target.value,o.setState({value:t}),r&&r(t)})),a=e.value?e.value:e.multiple?[""]:"",o.state={value:a},o}return S()(n,[{key:"componentWillReceiveProps",value:function(e){e.value!==this.props.value&&this.setState({value:e.value})}},{key:"render",value:function(){var e,t,n=this.props,r=n.allowedValues,o=n.multiple,a=n.allowEmptyValue,i=n.disabled,c=(null===(e=this.state.value)||void 0===e||null===(t=e.toJS)||void 0===t?void 0:t.call(e))||this.state.value;return j.a.createElement("select",{className:this.props.className,multiple:o,value:c,onChange:this.onChange,disabled:i},a?j.a.createElement("option",{value:""},"--"):null,r.map((function(e,t){return j.a.createElement("option",{key:t,value:String(e)},String(e))})))}}]),n}(j.a.Component);v()(tn,"defaultProps",{multiple:!1,allowEmptyValue:!0});var nn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){return j.a.createElement("a",Jt()({},this.props,{rel:"noopener noreferrer",className:$t(this.props.className,"link")}))}}]),n}(j.a.Component),rn=function(e){var t=e.children;return j.a.createElement("div",{className:"no-margin"}," ",t," ")},on=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"renderNotAnimated",value:function(){return this.props.isOpened?j.a.createElement(rn,null,this.props.children):j.a.createElement("noscript",null)}},{key:"render",value:function(){var e=this.props,t=e.animated,n=e.isOpened,r=e.children;return t?(r=n?r:null,j.a.createElement(rn,null,r)):this.renderNotAnimated()}}]),n}(j.a.Component);v()(on,"defaultProps",{isOpened:!1,animated:!1});var an=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return(e=t.call.apply(t,[this].concat(o))).setTagShown=e._setTagShown.bind(ae()(e)),e}return S()(n,[{key:"_setTagShown",value:function(e,t){this.props.layoutActions.show(e,t)}},{key:"showOp",value:function(e,t){this.props.layoutActions.show(e,t)}},{key:"render",value:function(){var e=this.props,t=e.specSelectors,n=e.layoutSelectors,r=e.layoutActions,o=e.getComponent,a=t.taggedOperations(),i=o("Collapse");return j.a.createElement("div",null,j.a.createElement("h4",{className:"overview-title"},"Overview"),a.map((function(e,t){var o=e.get("operations"),a=["overview-tags",t],c=n.isShown(a,!0);return j.a.createElement("div",{key:"overview-"+t},j.a.createElement("h4",{onClick:function(){return r.show(a,!c)},className:"link overview-tag"}," ",c?"-":"+",t),j.a.createElement(i,{isOpened:c,animated:!0},o.map((function(e){var t=e.toObject(),o=t.path,a=t.method,i=t.id,c=i,s=n.isShown(["operations",c]);return j.a.createElement(cn,{key:i,path:o,method:a,id:o+"-"+a,shown:s,showOpId:c,showOpIdPrefix:"operations",href:"#operation-".concat(c),onClick:r.show})})).toArray()))})).toArray(),a.size<1&&j.a.createElement("h3",null," No operations defined in spec! "))}}]),n}(j.a.Component),cn=function(e){ce()(n,e);var t=ue()(n);function n(e){var r;return E()(this,n),(r=t.call(this,e)).onClick=r._onClick.bind(ae()(r)),r}return S()(n,[{key:"_onClick",value:function(){var e=this.props,t=e.showOpId,n=e.showOpIdPrefix;(0,e.onClick)([n,t],!e.shown)}},{key:"render",value:function(){var e=this.props,t=e.id,n=e.method,r=e.shown,o=e.href;return j.a.createElement(nn,{href:o,onClick:this.onClick,className:"block opblock-link ".concat(r?"shown":"")},j.a.createElement("div",null,j.a.createElement("small",{className:"bold-label-".concat(n)},n.toUpperCase()),j.a.createElement("span",{className:"bold-label"},t)))}}]),n}(j.a.Component),sn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"componentDidMount",value:function(){this.props.initialValue&&(this.inputRef.value=this.props.initialValue)}},{key:"render",value:function(){var e=this,t=this.props,n=(t.value,t.defaultValue,Ht()(t,["value","defaultValue"]));return j.a.createElement("input",Jt()({},n,{ref:function(t){return e.inputRef=t}}))}}]),n}(j.a.Component),un=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.host,n=e.basePath;return j.a.createElement("pre",{className:"base-url"},"[ Base URL: ",t,n," ]")}}]),n}(j.a.Component),ln=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.data,n=e.getComponent,r=t.get("name")||"the developer",o=t.get("url"),a=t.get("email"),i=n("Link");return j.a.createElement("div",{className:"info__contact"},o&&j.a.createElement("div",null,j.a.createElement(i,{href:Object(D.G)(o),target:"_blank"},r," - Website")),a&&j.a.createElement(i,{href:Object(D.G)("mailto:".concat(a))},o?"Send email to ".concat(r):"Contact ".concat(r)))}}]),n}(j.a.Component),pn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.license,n=(0,e.getComponent)("Link"),r=t.get("name")||"License",o=t.get("url");return j.a.createElement("div",{className:"info__license"},o?j.a.createElement(n,{target:"_blank",href:Object(D.G)(o)},r):j.a.createElement("span",null,r))}}]),n}(j.a.Component),fn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.url,n=(0,e.getComponent)("Link");return j.a.createElement(n,{target:"_blank",href:Object(D.G)(t)},j.a.createElement("span",{className:"url"}," ",t," "))}}]),n}(j.a.PureComponent),dn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.info,n=e.url,r=e.host,o=e.basePath,a=e.getComponent,i=e.externalDocs,c=t.get("version"),s=t.get("description"),u=t.get("title"),l=t.get("termsOfService"),p=t.get("contact"),f=t.get("license"),d=(i||Object(O.fromJS)({})).toJS(),h=d.url,m=d.description,v=a("Markdown",!0),g=a("Link"),y=a("VersionStamp"),b=a("InfoUrl"),E=a("InfoBasePath");return j.a.createElement("div",{className:"info"},j.a.createElement("hgroup",{className:"main"},j.a.createElement("h2",{className:"title"},u,c&&j.a.createElement(y,{version:c})),r||o?j.a.createElement(E,{host:r,basePath:o}):null,n&&j.a.createElement(b,{getComponent:a,url:n})),j.a.createElement("div",{className:"description"},j.a.createElement(v,{source:s})),l&&j.a.createElement("div",{className:"info__tos"},j.a.createElement(g,{target:"_blank",href:Object(D.G)(l)},"Terms of service")),p&&p.size?j.a.createElement(ln,{getComponent:a,data:p}):null,f&&f.size?j.a.createElement(pn,{getComponent:a,license:f}):null,h?j.a.createElement(g,{className:"info__extdocs",target:"_blank",href:Object(D.G)(h)},m||h):null)}}]),n}(j.a.Component),hn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.specSelectors,n=e.getComponent,r=t.info(),o=t.url(),a=t.basePath(),i=t.host(),c=t.externalDocs(),s=n("info");return j.a.createElement("div",null,r&&r.count()?j.a.createElement(s,{info:r,url:o,host:i,basePath:a,externalDocs:c,getComponent:n}):null)}}]),n}(j.a.Component),mn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){return null}}]),n}(j.a.Component),vn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){return j.a.createElement("div",{className:"footer"})}}]),n}(j.a.Component),gn=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onFilterChange",(function(t){var n=t.target.value;e.props.layoutActions.updateFilter(n)})),e}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.specSelectors,n=e.layoutSelectors,r=(0,e.getComponent)("Col"),o="loading"===t.loadingStatus(),a="failed"===t.loadingStatus(),i=n.currentFilter(),c=["operation-filter-input"];return a&&c.push("failed"),o&&c.push("loading"),j.a.createElement("div",null,null===i||!1===i?null:j.a.createElement("div",{className:"filter-container"},j.a.createElement(r,{className:"filter wrapper",mobile:12},j.a.createElement("input",{className:c.join(" "),placeholder:"Filter by tag",type:"text",onChange:this.onFilterChange,value:!0===i||"true"===i?"":i,disabled:o}))))}}]),n}(j.a.Component),yn=Function.prototype,bn=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;return E()(this,n),o=t.call(this,e,r),v()(ae()(o),"updateValues",(function(e){var t=e.param,n=e.isExecute,r=e.consumesValue,a=void 0===r?"":r,i=/xml/i.test(a),c=/json/i.test(a),s=i?t.get("value_xml"):t.get("value");if(void 0!==s){var u=!s&&c?"{}":s;o.setState({value:u}),o.onChange(u,{isXml:i,isEditBox:n})}else i?o.onChange(o.sample("xml"),{isXml:i,isEditBox:n}):o.onChange(o.sample(),{isEditBox:n})})),v()(ae()(o),"sample",(function(e){var t=o.props,n=t.param,r=(0,t.fn.inferSchema)(n.toJS());return Object(D.o)(r,e,{includeWriteOnly:!0})})),v()(ae()(o),"onChange",(function(e,t){var n=t.isEditBox,r=t.isXml;o.setState({value:e,isEditBox:n}),o._onChange(e,r)})),v()(ae()(o),"_onChange",(function(e,t){(o.props.onChange||yn)(e,t)})),v()(ae()(o),"handleOnChange",(function(e){var t=o.props.consumesValue,n=/xml/i.test(t),r=e.target.value;o.onChange(r,{isXml:n})})),v()(ae()(o),"toggleIsEditBox",(function(){return o.setState((function(e){return{isEditBox:!e.isEditBox}}))})),o.state={isEditBox:!1,value:""},o}return S()(n,[{key:"componentDidMount",value:function(){this.updateValues.call(this,this.props)}},{key:"componentWillReceiveProps",value:function(e){this.updateValues.call(this,e)}},{key:"render",value:function(){var e=this.props,t=e.onChangeConsumes,r=e.param,o=e.isExecute,a=e.specSelectors,i=e.pathMethod,c=e.getConfigs,s=e.getComponent,u=s("Button"),l=s("TextArea"),p=s("highlightCode"),f=s("contentType"),d=(a?a.parameterWithMetaByIdentity(i,r):r).get("errors",Object(O.List)()),h=a.contentTypeValues(i).get("requestContentType"),m=this.props.consumes&&this.props.consumes.size?this.props.consumes:n.defaultProp.consumes,v=this.state,g=v.value,y=v.isEditBox;return j.a.createElement("div",{className:"body-param","data-param-name":r.get("name"),"data-param-in":r.get("in")},y&&o?j.a.createElement(l,{className:"body-param__text"+(d.count()?" invalid":""),value:g,onChange:this.handleOnChange}):g&&j.a.createElement(p,{className:"body-param__example",getConfigs:c,value:g}),j.a.createElement("div",{className:"body-param-options"},o?j.a.createElement("div",{className:"body-param-edit"},j.a.createElement(u,{className:y?"btn cancel body-param__example-edit":"btn edit body-param__example-edit",onClick:this.toggleIsEditBox},y?"Cancel":"Edit")):null,j.a.createElement("label",{htmlFor:""},j.a.createElement("span",null,"Parameter content type"),j.a.createElement(f,{value:h,contentTypes:m,onChange:t,className:"body-param-content-type"}))))}}]),n}(w.PureComponent);v()(bn,"defaultProp",{consumes:Object(O.fromJS)(["application/json"]),param:Object(O.fromJS)({}),onChange:yn,onChangeConsumes:yn});var En=n(31),xn=n.n(En),Sn=function(e){return e.indexOf("_**[]")<0?e:e.split("_**[]")[0].trim()};var wn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.request,n=e.getConfigs,r=function(e){var t=[],n=!1,r=e.get("headers");if(t.push("curl"),t.push("-X",e.get("method")),t.push('"'.concat(e.get("url"),'"')),r&&r.size){var o,a=xn()(e.get("headers").entries());try{for(a.s();!(o=a.n()).done;){var c=o.value,s=$e()(c,2),u=s[0],l=s[1];t.push("-H "),t.push('"'.concat(u,": ").concat(l,'"')),n=n||/^content-type$/i.test(u)&&/^multipart\/form-data$/i.test(l)}}catch(e){a.e(e)}finally{a.f()}}if(e.get("body"))if(n&&["POST","PUT","PATCH"].includes(e.get("method"))){var p,f=xn()(e.get("body").entrySeq());try{for(f.s();!(p=f.n()).done;){var d=$e()(p.value,2),h=d[0],m=d[1],v=Sn(h);t.push("-F"),m instanceof M.a.File?t.push('"'.concat(v,"=@").concat(m.name).concat(m.type?";type=".concat(m.type):"",'"')):t.push('"'.concat(v,"=").concat(m,'"'))}}catch(e){f.e(e)}finally{f.f()}}else t.push("-d"),t.push(i()(e.get("body")).replace(/\\n/g,""));else e.get("body")||"POST"!==e.get("method")||(t.push("-d"),t.push('""'));return t.join(" ")}(t),o=n(),a=ht()(o,"syntaxHighlight.activated")?j.a.createElement(Ge.Light,{language:"bash",className:"curl microlight",onWheel:this.preventYScrollingBeyondElement,style:ft(ht()(o,"syntaxHighlight.theme"))},r):j.a.createElement("textarea",{readOnly:!0,className:"curl",value:r});return j.a.createElement("div",{className:"curl-command"},j.a.createElement("h4",null,"Curl"),j.a.createElement("div",{className:"copy-to-clipboard"},j.a.createElement(gt.CopyToClipboard,{text:r},j.a.createElement("button",null))),j.a.createElement("div",null,a))}}]),n}(j.a.Component),jn=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onChange",(function(t){e.setScheme(t.target.value)})),v()(ae()(e),"setScheme",(function(t){var n=e.props,r=n.path,o=n.method;n.specActions.setScheme(t,r,o)})),e}return S()(n,[{key:"componentWillMount",value:function(){var e=this.props.schemes;this.setScheme(e.first())}},{key:"componentWillReceiveProps",value:function(e){this.props.currentScheme&&e.schemes.includes(this.props.currentScheme)||this.setScheme(e.schemes.first())}},{key:"render",value:function(){var e=this.props,t=e.schemes,n=e.currentScheme;return j.a.createElement("label",{htmlFor:"schemes"},j.a.createElement("span",{className:"schemes-title"},"Schemes"),j.a.createElement("select",{onChange:this.onChange,value:n},t.valueSeq().map((function(e){return j.a.createElement("option",{value:e,key:e},e)})).toArray()))}}]),n}(j.a.Component),_n=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.specActions,n=e.specSelectors,r=e.getComponent,o=n.operationScheme(),a=n.schemes(),i=r("schemes");return a&&a.size?j.a.createElement(i,{currentScheme:o,schemes:a,specActions:t}):null}}]),n}(j.a.Component),On=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;E()(this,n),o=t.call(this,e,r),v()(ae()(o),"toggleCollapsed",(function(){o.props.onToggle&&o.props.onToggle(o.props.modelName,!o.state.expanded),o.setState({expanded:!o.state.expanded})})),v()(ae()(o),"onLoad",(function(e){if(e){var t=o.props.modelName,n=o.props.layoutSelectors.getScrollToKey();C.a.is(n,C.a.fromJS(["models",t]))&&o.toggleCollapsed(),o.props.layoutActions.readyToScroll(["models",t],e.parentElement)}}));var a=o.props,i=a.expanded,c=a.collapsedContent;return o.state={expanded:i,collapsedContent:c||n.defaultProps.collapsedContent},o}return S()(n,[{key:"componentDidMount",value:function(){var e=this.props,t=e.hideSelfOnExpand,n=e.expanded,r=e.modelName;t&&n&&this.props.onToggle(r,n)}},{key:"componentWillReceiveProps",value:function(e){this.props.expanded!==e.expanded&&this.setState({expanded:e.expanded})}},{key:"render",value:function(){var e=this.props,t=e.title,n=e.classes;return this.state.expanded&&this.props.hideSelfOnExpand?j.a.createElement("span",{className:n||""},this.props.children):j.a.createElement("span",{className:n||""},t&&j.a.createElement("span",{onClick:this.toggleCollapsed,className:"pointer"},t),j.a.createElement("span",{onClick:this.toggleCollapsed,className:"pointer"},j.a.createElement("span",{className:"model-toggle"+(this.state.expanded?"":" collapsed")})),this.state.expanded?this.props.children:this.state.collapsedContent)}}]),n}(w.Component);v()(On,"defaultProps",{collapsedContent:"{...}",expanded:!1,title:null,onToggle:function(){},hideSelfOnExpand:!1});var Cn=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;E()(this,n),o=t.call(this,e,r),v()(ae()(o),"activeTab",(function(e){var t=e.target.dataset.name;o.setState({activeTab:t})}));var a=o.props,i=a.getConfigs,c=a.isExecute,s=i().defaultModelRendering,u=s;return"example"!==s&&"model"!==s&&(u="example"),c&&(u="example"),o.state={activeTab:u},o}return S()(n,[{key:"componentWillReceiveProps",value:function(e){e.isExecute&&!this.props.isExecute&&this.props.example&&this.setState({activeTab:"example"})}},{key:"render",value:function(){var e=this.props,t=e.getComponent,n=e.specSelectors,r=e.schema,o=e.example,a=e.isExecute,i=e.getConfigs,c=e.specPath,s=e.includeReadOnly,u=e.includeWriteOnly,l=i().defaultModelExpandDepth,p=t("ModelWrapper"),f=t("highlightCode"),d=n.isOAS3();return j.a.createElement("div",{className:"model-example"},j.a.createElement("ul",{className:"tab"},j.a.createElement("li",{className:"tabitem"+("example"===this.state.activeTab?" active":"")},j.a.createElement("a",{className:"tablinks","data-name":"example",onClick:this.activeTab},a?"Edit Value":"Example Value")),r?j.a.createElement("li",{className:"tabitem"+("model"===this.state.activeTab?" active":"")},j.a.createElement("a",{className:"tablinks"+(a?" inactive":""),"data-name":"model",onClick:this.activeTab},d?"Schema":"Model")):null),j.a.createElement("div",null,"example"===this.state.activeTab?o||j.a.createElement(f,{value:"(no example available)"}):null,"model"===this.state.activeTab&&j.a.createElement(p,{schema:r,getComponent:t,getConfigs:i,specSelectors:n,expandDepth:l,specPath:c,includeReadOnly:s,includeWriteOnly:u})))}}]),n}(j.a.Component),An=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"getSchemaBasePath",(function(){return e.props.specSelectors.isOAS3()?["components","schemas"]:["definitions"]})),v()(ae()(e),"onToggle",(function(t,n){e.props.layoutActions&&e.props.layoutActions.show([].concat(kt()(e.getSchemaBasePath()),[t]),n)})),e}return S()(n,[{key:"render",value:function(){var e,t=this.props,n=t.getComponent,r=t.getConfigs,o=n("Model");return this.props.layoutSelectors&&(e=this.props.layoutSelectors.isShown(["models",this.props.name])),j.a.createElement("div",{className:"model-box"},j.a.createElement(o,Jt()({},this.props,{getConfigs:r,expanded:e,depth:1,onToggle:this.onToggle,expandDepth:this.props.expandDepth||0})))}}]),n}(w.Component),kn=n(152),Pn=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"getSchemaBasePath",(function(){return e.props.specSelectors.isOAS3()?["components","schemas"]:["definitions"]})),v()(ae()(e),"getCollapsedContent",(function(){return" "})),v()(ae()(e),"handleToggle",(function(t,n){e.props.layoutActions.show(["models",t],n),n&&e.props.specActions.requestResolvedSubtree([].concat(kt()(e.getSchemaBasePath()),[t]))})),v()(ae()(e),"onLoad",(function(t){if(t){var n=t.getAttribute("data-name");e.props.layoutActions.readyToScroll(["models",n],t)}})),e}return S()(n,[{key:"render",value:function(){var e=this,t=this.props,n=t.specSelectors,r=t.getComponent,o=t.layoutSelectors,a=t.layoutActions,i=t.getConfigs,c=n.definitions(),s=i(),u=s.docExpansion,l=s.defaultModelsExpandDepth;if(!c.size||l<0)return null;var p=o.isShown("models",l>0&&"none"!==u),f=this.getSchemaBasePath(),d=n.isOAS3(),h=r("ModelWrapper"),m=r("Collapse"),v=r("ModelCollapse"),g=r("JumpToPath");return j.a.createElement("section",{className:p?"models is-open":"models"},j.a.createElement("h4",{onClick:function(){return a.show("models",!p)}},j.a.createElement("span",null,d?"Schemas":"Models"),j.a.createElement("svg",{width:"20",height:"20"},j.a.createElement("use",{xlinkHref:p?"#large-arrow-down":"#large-arrow"}))),j.a.createElement(m,{isOpened:p},c.entrySeq().map((function(t){var c=$e()(t,1)[0],s=[].concat(kt()(f),[c]),u=n.specResolvedSubtree(s),p=n.specJson().getIn(s),d=O.Map.isMap(u)?u:C.a.Map(),m=O.Map.isMap(p)?p:C.a.Map(),y=d.get("title")||m.get("title")||c,b=o.isShown(["models",c],!1);b&&0===d.size&&m.size>0&&e.props.specActions.requestResolvedSubtree([].concat(kt()(e.getSchemaBasePath()),[c]));var E=C.a.List([].concat(kt()(f),[c])),x=j.a.createElement(h,{name:c,expandDepth:l,schema:d||C.a.Map(),displayName:y,specPath:E,getComponent:r,specSelectors:n,getConfigs:i,layoutSelectors:o,layoutActions:a,includeReadOnly:!0,includeWriteOnly:!0}),S=j.a.createElement("span",{className:"model-box"},j.a.createElement("span",{className:"model model-title"},y));return j.a.createElement("div",{id:"model-".concat(c),className:"model-container",key:"models-section-".concat(c),"data-name":c,ref:e.onLoad},j.a.createElement("span",{className:"models-jump-to-path"},j.a.createElement(g,{specPath:E})),j.a.createElement(v,{classes:"model-box",collapsedContent:e.getCollapsedContent(c),onToggle:e.handleToggle,title:S,displayName:y,modelName:c,hideSelfOnExpand:!0,expanded:l>0&&b},x))})).toArray()))}}]),n}(w.Component),In=function(e){var t=e.value,n=(0,e.getComponent)("ModelCollapse"),r=j.a.createElement("span",null,"Array [ ",t.count()," ]");return j.a.createElement("span",{className:"prop-enum"},"Enum:",j.a.createElement("br",null),j.a.createElement(n,{collapsedContent:r},"[ ",t.join(", ")," ]"))},Tn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.schema,n=e.name,r=e.displayName,o=e.isRef,a=e.getComponent,c=e.getConfigs,s=e.depth,u=e.onToggle,l=e.expanded,p=e.specPath,f=Ht()(e,["schema","name","displayName","isRef","getComponent","getConfigs","depth","onToggle","expanded","specPath"]),d=f.specSelectors,h=f.expandDepth,m=f.includeReadOnly,v=f.includeWriteOnly,g=d.isOAS3;if(!t)return null;var y=c().showExtensions,b=t.get("description"),E=t.get("properties"),x=t.get("additionalProperties"),S=t.get("title")||r||n,w=t.get("required"),_=t.filter((function(e,t){return-1!==["maxProperties","minProperties","nullable"].indexOf(t)})),C=a("JumpToPath",!0),A=a("Markdown",!0),k=a("Model"),P=a("ModelCollapse"),I=a("Property"),T=function(){return j.a.createElement("span",{className:"model-jump-to-path"},j.a.createElement(C,{specPath:p}))},N=j.a.createElement("span",null,j.a.createElement("span",null,"{"),"...",j.a.createElement("span",null,"}"),o?j.a.createElement(T,null):""),R=d.isOAS3()?t.get("anyOf"):null,M=d.isOAS3()?t.get("oneOf"):null,D=d.isOAS3()?t.get("not"):null,q=S&&j.a.createElement("span",{className:"model-title"},o&&t.get("$$ref")&&j.a.createElement("span",{className:"model-hint"},t.get("$$ref")),j.a.createElement("span",{className:"model-title__text"},S));return j.a.createElement("span",{className:"model"},j.a.createElement(P,{modelName:n,title:q,onToggle:u,expanded:!!l||s<=h,collapsedContent:N},j.a.createElement("span",{className:"brace-open object"},"{"),o?j.a.createElement(T,null):null,j.a.createElement("span",{className:"inner-object"},j.a.createElement("table",{className:"model"},j.a.createElement("tbody",null,b?j.a.createElement("tr",{className:"description"},j.a.createElement("td",null,"description:"),j.a.createElement("td",null,j.a.createElement(A,{source:b}))):null,E&&E.size?E.entrySeq().filter((function(e){var t=$e()(e,2)[1];return(!t.get("readOnly")||m)&&(!t.get("writeOnly")||v)})).map((function(e){var t=$e()(e,2),r=t[0],o=t[1],i=g()&&o.get("deprecated"),u=O.List.isList(w)&&w.contains(r),l=["property-row"];return i&&l.push("deprecated"),u&&l.push("required"),j.a.createElement("tr",{key:r,className:l.join(" ")},j.a.createElement("td",null,r,u&&j.a.createElement("span",{className:"star"},"*")),j.a.createElement("td",null,j.a.createElement(k,Jt()({key:"object-".concat(n,"-").concat(r,"_").concat(o)},f,{required:u,getComponent:a,specPath:p.push("properties",r),getConfigs:c,schema:o,depth:s+1}))))})).toArray():null,y?j.a.createElement("tr",null,j.a.createElement("td",null," ")):null,y?t.entrySeq().map((function(e){var t=$e()(e,2),n=t[0],r=t[1];if("x-"===n.slice(0,2)){var o=r?r.toJS?r.toJS():r:null;return j.a.createElement("tr",{key:n,className:"extension"},j.a.createElement("td",null,n),j.a.createElement("td",null,i()(o)))}})).toArray():null,x&&x.size?j.a.createElement("tr",null,j.a.createElement("td",null,"< * >:"),j.a.createElement("td",null,j.a.createElement(k,Jt()({},f,{required:!1,getComponent:a,specPath:p.push("additionalProperties"),getConfigs:c,schema:x,depth:s+1})))):null,R?j.a.createElement("tr",null,j.a.createElement("td",null,"anyOf ->"),j.a.createElement("td",null,R.map((function(e,t){return j.a.createElement("div",{key:t},j.a.createElement(k,Jt()({},f,{required:!1,getComponent:a,specPath:p.push("anyOf",t),getConfigs:c,schema:e,depth:s+1})))})))):null,M?j.a.createElement("tr",null,j.a.createElement("td",null,"oneOf ->"),j.a.createElement("td",null,M.map((function(e,t){return j.a.createElement("div",{key:t},j.a.createElement(k,Jt()({},f,{required:!1,getComponent:a,specPath:p.push("oneOf",t),getConfigs:c,schema:e,depth:s+1})))})))):null,D?j.a.createElement("tr",null,j.a.createElement("td",null,"not ->"),j.a.createElement("td",null,j.a.createElement("div",null,j.a.createElement(k,Jt()({},f,{required:!1,getComponent:a,specPath:p.push("not"),getConfigs:c,schema:D,depth:s+1}))))):null))),j.a.createElement("span",{className:"brace-close"},"}")),_.size?_.entrySeq().map((function(e){var t=$e()(e,2),n=t[0],r=t[1];return j.a.createElement(I,{key:"".concat(n,"-").concat(r),propKey:n,propVal:r,propClass:"property"})})):null)}}]),n}(w.Component),Nn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.getComponent,n=e.getConfigs,r=e.schema,o=e.depth,a=e.expandDepth,i=e.name,c=e.displayName,s=e.specPath,u=r.get("description"),l=r.get("items"),p=r.get("title")||c||i,f=r.filter((function(e,t){return-1===["type","items","description","$$ref"].indexOf(t)})),d=t("Markdown",!0),h=t("ModelCollapse"),m=t("Model"),v=t("Property"),g=p&&j.a.createElement("span",{className:"model-title"},j.a.createElement("span",{className:"model-title__text"},p));return j.a.createElement("span",{className:"model"},j.a.createElement(h,{title:g,expanded:o<=a,collapsedContent:"[...]"},"[",f.size?f.entrySeq().map((function(e){var t=$e()(e,2),n=t[0],r=t[1];return j.a.createElement(v,{key:"".concat(n,"-").concat(r),propKey:n,propVal:r,propClass:"property"})})):null,u?j.a.createElement(d,{source:u}):f.size?j.a.createElement("div",{className:"markdown"}):null,j.a.createElement("span",null,j.a.createElement(m,Jt()({},this.props,{getConfigs:n,specPath:s.push("items"),name:null,schema:l,required:!1,depth:o+1}))),"]"))}}]),n}(w.Component),Rn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.schema,n=e.getComponent,r=e.getConfigs,o=e.name,a=e.displayName,i=e.depth,c=r().showExtensions;if(!t||!t.get)return j.a.createElement("div",null);var s=t.get("type"),u=t.get("format"),l=t.get("xml"),p=t.get("enum"),f=t.get("title")||a||o,d=t.get("description"),h=Object(D.m)(t),m=t.filter((function(e,t){return-1===["enum","type","format","description","$$ref"].indexOf(t)})).filterNot((function(e,t){return h.has(t)})),v=n("Markdown",!0),g=n("EnumModel"),y=n("Property");return j.a.createElement("span",{className:"model"},j.a.createElement("span",{className:"prop"},o&&j.a.createElement("span",{className:"".concat(1===i&&"model-title"," prop-name")},f),j.a.createElement("span",{className:"prop-type"},s),u&&j.a.createElement("span",{className:"prop-format"},"($",u,")"),m.size?m.entrySeq().map((function(e){var t=$e()(e,2),n=t[0],r=t[1];return j.a.createElement(y,{key:"".concat(n,"-").concat(r),propKey:n,propVal:r,propClass:"property primitive"})})):null,c&&h.size?h.entrySeq().map((function(e){var t=$e()(e,2),n=t[0],r=t[1];return j.a.createElement(y,{key:"".concat(n,"-").concat(r),propKey:n,propVal:r,propClass:"property primitive"})})):null,d?j.a.createElement(v,{source:d}):null,l&&l.size?j.a.createElement("span",null,j.a.createElement("br",null),j.a.createElement("span",{className:"property primitive"},"xml:"),l.entrySeq().map((function(e){var t=$e()(e,2),n=t[0],r=t[1];return j.a.createElement("span",{key:"".concat(n,"-").concat(r),className:"property primitive"},j.a.createElement("br",null),"   ",n,": ",String(r))})).toArray()):null,p&&j.a.createElement(g,{value:p,getComponent:n})))}}]),n}(w.Component),Mn=function(e){var t=e.propKey,n=e.propVal,r=e.propClass;return j.a.createElement("span",{className:r},j.a.createElement("br",null),t,": ",String(n))},Dn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.onTryoutClick,n=e.onCancelClick,r=e.enabled;return j.a.createElement("div",{className:"try-out"},r?j.a.createElement("button",{className:"btn try-out__btn cancel",onClick:n},"Cancel"):j.a.createElement("button",{className:"btn try-out__btn",onClick:t},"Try it out "))}}]),n}(j.a.Component);v()(Dn,"defaultProps",{onTryoutClick:Function.prototype,onCancelClick:Function.prototype,enabled:!1});var qn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.bypass,n=e.isSwagger2,r=e.isOAS3,o=e.alsoShow;return t?j.a.createElement("div",null,this.props.children):n&&r?j.a.createElement("div",{className:"version-pragma"},o,j.a.createElement("div",{className:"version-pragma__message version-pragma__message--ambiguous"},j.a.createElement("div",null,j.a.createElement("h3",null,"Unable to render this definition"),j.a.createElement("p",null,j.a.createElement("code",null,"swagger")," and ",j.a.createElement("code",null,"openapi")," fields cannot be present in the same Swagger or OpenAPI definition. Please remove one of the fields."),j.a.createElement("p",null,"Supported version fields are ",j.a.createElement("code",null,"swagger: ",'"2.0"')," and those that match ",j.a.createElement("code",null,"openapi: 3.0.n")," (for example, ",j.a.createElement("code",null,"openapi: 3.0.0"),").")))):n||r?j.a.createElement("div",null,this.props.children):j.a.createElement("div",{className:"version-pragma"},o,j.a.createElement("div",{className:"version-pragma__message version-pragma__message--missing"},j.a.createElement("div",null,j.a.createElement("h3",null,"Unable to render this definition"),j.a.createElement("p",null,"The provided definition does not specify a valid version field."),j.a.createElement("p",null,"Please indicate a valid Swagger or OpenAPI version field. Supported version fields are ",j.a.createElement("code",null,"swagger: ",'"2.0"')," and those that match ",j.a.createElement("code",null,"openapi: 3.0.n")," (for example, ",j.a.createElement("code",null,"openapi: 3.0.0"),")."))))}}]),n}(j.a.PureComponent);v()(qn,"defaultProps",{alsoShow:null,children:null,bypass:!1});var Ln=function(e){var t=e.version;return j.a.createElement("small",null,j.a.createElement("pre",{className:"version"}," ",t," "))},Bn=function(e){var t=e.enabled,n=e.path,r=e.text;return j.a.createElement("a",{className:"nostyle",onClick:t?function(e){return e.preventDefault()}:null,href:t?"#/".concat(n):null},j.a.createElement("span",null,r))},Un=function(){return j.a.createElement("div",null,j.a.createElement("svg",{xmlns:"http://www.w3.org/2000/svg",xmlnsXlink:"http://www.w3.org/1999/xlink",className:"svg-assets"},j.a.createElement("defs",null,j.a.createElement("symbol",{viewBox:"0 0 20 20",id:"unlocked"},j.a.createElement("path",{d:"M15.8 8H14V5.6C14 2.703 12.665 1 10 1 7.334 1 6 2.703 6 5.6V6h2v-.801C8 3.754 8.797 3 10 3c1.203 0 2 .754 2 2.199V8H4c-.553 0-1 .646-1 1.199V17c0 .549.428 1.139.951 1.307l1.197.387C5.672 18.861 6.55 19 7.1 19h5.8c.549 0 1.428-.139 1.951-.307l1.196-.387c.524-.167.953-.757.953-1.306V9.199C17 8.646 16.352 8 15.8 8z"})),j.a.createElement("symbol",{viewBox:"0 0 20 20",id:"locked"},j.a.createElement("path",{d:"M15.8 8H14V5.6C14 2.703 12.665 1 10 1 7.334 1 6 2.703 6 5.6V8H4c-.553 0-1 .646-1 1.199V17c0 .549.428 1.139.951 1.307l1.197.387C5.672 18.861 6.55 19 7.1 19h5.8c.549 0 1.428-.139 1.951-.307l1.196-.387c.524-.167.953-.757.953-1.306V9.199C17 8.646 16.352 8 15.8 8zM12 8H8V5.199C8 3.754 8.797 3 10 3c1.203 0 2 .754 2 2.199V8z"})),j.a.createElement("symbol",{viewBox:"0 0 20 20",id:"close"},j.a.createElement("path",{d:"M14.348 14.849c-.469.469-1.229.469-1.697 0L10 11.819l-2.651 3.029c-.469.469-1.229.469-1.697 0-.469-.469-.469-1.229 0-1.697l2.758-3.15-2.759-3.152c-.469-.469-.469-1.228 0-1.697.469-.469 1.228-.469 1.697 0L10 8.183l2.651-3.031c.469-.469 1.228-.469 1.697 0 .469.469.469 1.229 0 1.697l-2.758 3.152 2.758 3.15c.469.469.469 1.229 0 1.698z"})),j.a.createElement("symbol",{viewBox:"0 0 20 20",id:"large-arrow"},j.a.createElement("path",{d:"M13.25 10L6.109 2.58c-.268-.27-.268-.707 0-.979.268-.27.701-.27.969 0l7.83 7.908c.268.271.268.709 0 .979l-7.83 7.908c-.268.271-.701.27-.969 0-.268-.269-.268-.707 0-.979L13.25 10z"})),j.a.createElement("symbol",{viewBox:"0 0 20 20",id:"large-arrow-down"},j.a.createElement("path",{d:"M17.418 6.109c.272-.268.709-.268.979 0s.271.701 0 .969l-7.908 7.83c-.27.268-.707.268-.979 0l-7.908-7.83c-.27-.268-.27-.701 0-.969.271-.268.709-.268.979 0L10 13.25l7.418-7.141z"})),j.a.createElement("symbol",{viewBox:"0 0 24 24",id:"jump-to"},j.a.createElement("path",{d:"M19 7v4H5.83l3.58-3.59L8 6l-6 6 6 6 1.41-1.41L5.83 13H21V7z"})),j.a.createElement("symbol",{viewBox:"0 0 24 24",id:"expand"},j.a.createElement("path",{d:"M10 18h4v-2h-4v2zM3 6v2h18V6H3zm3 7h12v-2H6v2z"})))))},Vn=n(154),zn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.errSelectors,n=e.specSelectors,r=e.getComponent,o=r("SvgAssets"),a=r("InfoContainer",!0),i=r("VersionPragmaFilter"),c=r("operations",!0),s=r("Models",!0),u=r("Row"),l=r("Col"),p=r("errors",!0),f=r("ServersContainer",!0),d=r("SchemesContainer",!0),h=r("AuthorizeBtnContainer",!0),m=r("FilterContainer",!0),v=n.isSwagger2(),g=n.isOAS3(),y=!n.specStr(),b=n.loadingStatus(),E=null;if("loading"===b&&(E=j.a.createElement("div",{className:"info"},j.a.createElement("div",{className:"loading-container"},j.a.createElement("div",{className:"loading"})))),"failed"===b&&(E=j.a.createElement("div",{className:"info"},j.a.createElement("div",{className:"loading-container"},j.a.createElement("h4",{className:"title"},"Failed to load API definition."),j.a.createElement(p,null)))),"failedConfig"===b){var x=t.lastError(),S=x?x.get("message"):"";E=j.a.createElement("div",{className:"info failed-config"},j.a.createElement("div",{className:"loading-container"},j.a.createElement("h4",{className:"title"},"Failed to load remote configuration."),j.a.createElement("p",null,S)))}if(!E&&y&&(E=j.a.createElement("h4",null,"No API definition provided.")),E)return j.a.createElement("div",{className:"swagger-ui"},j.a.createElement("div",{className:"loading-container"},E));var w=n.servers(),_=n.schemes(),O=w&&w.size,C=_&&_.size,A=!!n.securityDefinitions();return j.a.createElement("div",{className:"swagger-ui"},j.a.createElement(o,null),j.a.createElement(i,{isSwagger2:v,isOAS3:g,alsoShow:j.a.createElement(p,null)},j.a.createElement(p,null),j.a.createElement(u,{className:"information-container"},j.a.createElement(l,{mobile:12},j.a.createElement(a,null))),O||C||A?j.a.createElement("div",{className:"scheme-container"},j.a.createElement(l,{className:"schemes wrapper",mobile:12},O?j.a.createElement(f,null):null,C?j.a.createElement(d,null):null,A?j.a.createElement(h,null):null)):null,j.a.createElement(m,null),j.a.createElement(u,null,j.a.createElement(l,{mobile:12,desktop:12},j.a.createElement(c,null))),j.a.createElement(u,null,j.a.createElement(l,{mobile:12,desktop:12},j.a.createElement(s,null)))))}}]),n}(j.a.Component),Fn=n(270),Jn=n.n(Fn),Wn={value:"",onChange:function(){},schema:{},keyName:"",required:!1,errors:Object(O.List)()},Hn=function(e){ce()(n,e);var t=ue()(n);function n(){return E()(this,n),t.apply(this,arguments)}return S()(n,[{key:"componentDidMount",value:function(){var e=this.props,t=e.dispatchInitialValue,n=e.value,r=e.onChange;t&&r(n)}},{key:"render",value:function(){var e=this.props,t=e.schema,n=e.errors,r=e.value,o=e.onChange,a=e.getComponent,i=e.fn,c=e.disabled,s=t&&t.get?t.get("format"):null,u=t&&t.get?t.get("type"):null,l=function(e){return a(e,!1,{failSilently:!0})},p=u?l(s?"JsonSchema_".concat(u,"_").concat(s):"JsonSchema_".concat(u)):a("JsonSchema_string");return p||(p=a("JsonSchema_string")),j.a.createElement(p,Jt()({},this.props,{errors:n,fn:i,getComponent:a,value:r,onChange:o,schema:t,disabled:c}))}}]),n}(w.Component);v()(Hn,"defaultProps",Wn);var $n=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onChange",(function(t){var n=e.props.schema&&"file"===e.props.schema.get("type")?t.target.files[0]:t.target.value;e.props.onChange(n,e.props.keyName)})),v()(ae()(e),"onEnumChange",(function(t){return e.props.onChange(t)})),e}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.getComponent,n=e.value,r=e.schema,o=e.errors,a=e.required,i=e.description,c=e.disabled,s=r&&r.get?r.get("enum"):null,u=r&&r.get?r.get("format"):null,l=r&&r.get?r.get("type"):null,p=r&&r.get?r.get("in"):null;if(n||(n=""),o=o.toJS?o.toJS():[],s){var f=t("Select");return j.a.createElement(f,{className:o.length?"invalid":"",title:o.length?o:"",allowedValues:s,value:n,allowEmptyValue:!a,disabled:c,onChange:this.onEnumChange})}var d=c||p&&"formData"===p&&!("FormData"in window),h=t("Input");return l&&"file"===l?j.a.createElement(h,{type:"file",className:o.length?"invalid":"",title:o.length?o:"",onChange:this.onChange,disabled:d}):j.a.createElement(Jn.a,{type:u&&"password"===u?"password":"text",className:o.length?"invalid":"",title:o.length?o:"",value:n,minLength:0,debounceTimeout:350,placeholder:i,onChange:this.onChange,disabled:d})}}]),n}(w.Component);v()($n,"defaultProps",Wn);var Yn=function(e){ce()(n,e);var t=ue()(n);function n(e,r){var o;return E()(this,n),o=t.call(this,e,r),v()(ae()(o),"onChange",(function(){o.props.onChange(o.state.value)})),v()(ae()(o),"onItemChange",(function(e,t){o.setState((function(n){return{value:n.value.set(t,e)}}),o.onChange)})),v()(ae()(o),"removeItem",(function(e){o.setState((function(t){return{value:t.value.delete(e)}}),o.onChange)})),v()(ae()(o),"addItem",(function(){var e=Qn(o.state.value);o.setState((function(){return{value:e.push("")}}),o.onChange)})),v()(ae()(o),"onEnumChange",(function(e){o.setState((function(){return{value:e}}),o.onChange)})),o.state={value:Qn(e.value)},o}return S()(n,[{key:"componentWillReceiveProps",value:function(e){e.value!==this.state.value&&this.setState({value:e.value})}},{key:"render",value:function(){var e=this,t=this.props,n=t.getComponent,r=t.required,o=t.schema,a=t.errors,i=t.fn,c=t.disabled;a=a.toJS?a.toJS():[];var s,u=this.state.value,l=!!(u&&u.count&&u.count()>0),p=o.getIn(["items","enum"]),f=o.getIn(["items","type"]),d=o.getIn(["items","format"]),h=o.getIn(["items","schema"]),m=!1,v="file"===f||"string"===f&&"binary"===d;if(f&&d?s=n("JsonSchema_".concat(f,"_").concat(d)):"boolean"!==f&&"array"!==f&&"object"!==f||(s=n("JsonSchema_".concat(f))),s||v||(m=!0),p){var g=n("Select");return j.a.createElement(g,{className:a.length?"invalid":"",title:a.length?a:"",multiple:!0,value:u,disabled:c,allowedValues:p,allowEmptyValue:!r,onChange:this.onEnumChange})}var y=n("Button");return j.a.createElement("div",{className:"json-schema-array"},l?u.map((function(t,r){if(a.length){var o=a.filter((function(e){return e.index===r}));o.length&&(a=[o[0].error+r])}return j.a.createElement("div",{key:r,className:"json-schema-form-item"},v?j.a.createElement(Gn,{value:t,onChange:function(t){return e.onItemChange(t,r)},disabled:c,errors:a,getComponent:n}):m?j.a.createElement(Kn,{value:t,onChange:function(t){return e.onItemChange(t,r)},disabled:c,errors:a}):j.a.createElement(s,Jt()({},e.props,{value:t,onChange:function(t){return e.onItemChange(t,r)},disabled:c,errors:a,schema:h,getComponent:n,fn:i})),c?null:j.a.createElement(y,{className:"btn btn-sm json-schema-form-item-remove",onClick:function(){return e.removeItem(r)}}," - "))})):null,c?null:j.a.createElement(y,{className:"btn btn-sm json-schema-form-item-add ".concat(a.length?"invalid":null),onClick:this.addItem},"Add item"))}}]),n}(w.PureComponent);v()(Yn,"defaultProps",Wn);var Kn=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onChange",(function(t){var n=t.target.value;e.props.onChange(n,e.props.keyName)})),e}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.value,n=e.errors,r=e.description,o=e.disabled;return t||(t=""),n=n.toJS?n.toJS():[],j.a.createElement(Jn.a,{type:"text",className:n.length?"invalid":"",title:n.length?n:"",value:t,minLength:0,debounceTimeout:350,placeholder:r,onChange:this.onChange,disabled:o})}}]),n}(w.Component);v()(Kn,"defaultProps",Wn);var Gn=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onFileChange",(function(t){var n=t.target.files[0];e.props.onChange(n,e.props.keyName)})),e}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.getComponent,n=e.errors,r=e.disabled,o=t("Input"),a=r||!("FormData"in window);return j.a.createElement(o,{type:"file",className:n.length?"invalid":"",title:n.length?n:"",onChange:this.onFileChange,disabled:a})}}]),n}(w.Component);v()(Gn,"defaultProps",Wn);var Zn=function(e){ce()(n,e);var t=ue()(n);function n(){var e;E()(this,n);for(var r=arguments.length,o=new Array(r),a=0;a<r;a++)o[a]=arguments[a];return e=t.call.apply(t,[this].concat(o)),v()(ae()(e),"onEnumChange",(function(t){return e.props.onChange(t)})),e}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.getComponent,n=e.value,r=e.errors,o=e.schema,a=e.required,i=e.disabled;r=r.toJS?r.toJS():[];var c=o&&o.get?o.get("enum"):null;c||(c=Object(O.fromJS)(["true","false"]));var s=t("Select");return j.a.createElement(s,{className:r.length?"invalid":"",title:r.length?r:"",value:String(n),disabled:i,allowedValues:c,allowEmptyValue:!a,onChange:this.onEnumChange})}}]),n}(w.Component);v()(Zn,"defaultProps",Wn);var Xn=function(e){ce()(n,e);var t=ue()(n);function n(){var e;return E()(this,n),e=t.call(this),v()(ae()(e),"onChange",(function(t){e.props.onChange(t)})),v()(ae()(e),"handleOnChange",(function(t){var n=t.target.value;e.onChange(n)})),e}return S()(n,[{key:"render",value:function(){var e=this.props,t=e.getComponent,n=e.value,r=e.errors,o=e.disabled,a=t("TextArea");return j.a.createElement("div",null,j.a.createElement(a,{className:xt()({invalid:r.size}),title:r.size?r.join(", "):"",value:Object(D.J)(n),disabled:o,onChange:this.handleOnChange}))}}]),n}(w.PureComponent);function Qn(e){return O.List.isList(e)?e:Object(O.List)()}v()(Xn,"defaultProps",Wn);var er=function(){var e={components:{App:fe,authorizationPopup:de,authorizeBtn:he,AuthorizeBtnContainer:me,authorizeOperationBtn:ve,auths:ge,AuthItem:ye,authError:be,oauth2:Pe,apiKeyAuth:Ee,basicAuth:xe,clear:Ie,liveResponse:Re,InitializedInput:sn,info:dn,InfoContainer:hn,JumpToPath:mn,onlineValidatorBadge:Me.a,operations:Le,operation:Ue,OperationSummary:Fe,OperationSummaryMethod:Je,OperationSummaryPath:We,highlightCode:yt,responses:bt,response:St,responseBody:Ct,parameters:Pt,parameterRow:Mt,execute:Dt,headers:qt,errors:Lt,contentType:zt,overview:an,footer:vn,FilterContainer:gn,ParamBody:bn,curl:wn,schemes:jn,SchemesContainer:_n,modelExample:Cn,ModelWrapper:An,ModelCollapse:On,Model:kn.a,Models:Pn,EnumModel:In,ObjectModel:Tn,ArrayModel:Nn,PrimitiveModel:Rn,Property:Mn,TryItOutButton:Dn,Markdown:Vn.a,BaseLayout:zn,VersionPragmaFilter:qn,VersionStamp:Ln,OperationExt:Ye,OperationExtRow:Ke,ParameterExt:It,ParameterIncludeEmpty:Nt,OperationTag:Be,OperationContainer:pe,DeepLink:Bn,InfoUrl:fn,InfoBasePath:un,SvgAssets:Un,Example:Se,ExamplesSelect:_e,ExamplesSelectValueRetainer:Ce}},t={components:r},n={components:o};return[ee.default,X.default,K.default,$.default,H.default,J.default,W.default,Y.default,e,t,G.default,n,Z.default,Q.default,te.default,ne.default,re.default]},tr=n(238);function nr(){return[er,tr.default]}var rr=n(260);var or=!0,ar="g7a6999b",ir="3.31.1",cr="ip-172-31-21-173",sr="Thu, 30 Jul 2020 18:08:50 GMT";function ur(e){M.a.versions=M.a.versions||{},M.a.versions.swaggerUi={version:ir,gitRevision:ar,gitDirty:or,buildTimestamp:sr,machine:cr};var t={dom_id:null,domNode:null,spec:{},url:"",urls:null,layout:"BaseLayout",docExpansion:"list",maxDisplayedTags:null,filter:null,validatorUrl:"https://validator.swagger.io/validator",oauth2RedirectUrl:"".concat(window.location.protocol,"//").concat(window.location.host,"/oauth2-redirect.html"),configs:{},custom:{},displayOperationId:!1,displayRequestDuration:!1,deepLinking:!1,requestInterceptor:function(e){return e},responseInterceptor:function(e){return e},showMutatedRequest:!0,defaultModelRendering:"example",defaultModelExpandDepth:1,defaultModelsExpandDepth:1,showExtensions:!1,showCommonExtensions:!1,withCredentials:void 0,supportedSubmitMethods:["get","put","post","delete","options","head","patch","trace"],presets:[nr],plugins:[],initialState:{},fn:{},components:{},syntaxHighlight:{activated:!0,theme:"agate"}},n=Object(D.D)(),r=e.domNode;delete e.domNode;var o=f()({},t,e,n),a={system:{configs:o.configs},plugins:o.presets,state:f()({layout:{layout:o.layout,filter:o.filter},spec:{spec:"",url:o.url}},o.initialState)};if(o.initialState)for(var c in o.initialState)o.initialState.hasOwnProperty(c)&&void 0===o.initialState[c]&&delete a.state[c];var u=new L(a);u.register([o.plugins,function(){return{fn:o.fn,components:o.components,state:o.state}}]);var p=u.getSystem(),d=function(e){var t=p.specSelectors.getLocalConfig?p.specSelectors.getLocalConfig():{},a=f()({},t,o,e||{},n);if(r&&(a.domNode=r),u.setConfigs(a),p.configsActions.loaded(),null!==e&&(!n.url&&"object"===l()(a.spec)&&s()(a.spec).length?(p.specActions.updateUrl(""),p.specActions.updateLoadingStatus("success"),p.specActions.updateSpec(i()(a.spec))):p.specActions.download&&a.url&&!a.urls&&(p.specActions.updateUrl(a.url),p.specActions.download(a.url))),a.domNode)p.render(a.domNode,"App");else if(a.dom_id){var c=document.querySelector(a.dom_id);p.render(c,"App")}else null===a.dom_id||null===a.domNode||console.error("Skipped rendering: no `dom_id` or `domNode` was specified");return p},h=n.config||o.configUrl;return h&&p.specActions&&p.specActions.getConfigByUrl&&(!p.specActions.getConfigByUrl||p.specActions.getConfigByUrl({url:h,loadRemoteConfig:!0,requestInterceptor:o.requestInterceptor,responseInterceptor:o.responseInterceptor},d))?(p.specActions.getConfigByUrl(h,d),p):d()}ur.presets={apis:nr},ur.plugins=rr.default}]).default}));
-//# sourceMappingURL=swagger-ui.js.map
\ No newline at end of file
@@ -9,9 +9,9 @@ import (
 )
 
 func testBeers(t *testing.T) {
-	seeds.SeedUsers()
-	seeds.SeedBeerTransfers()
-	defer seeds.TruncateAll()
+	seedUsers()
+	seedBeerTransfers()
+	defer wipe()
 
 	t.Run("expect GET /beers to have default pagination", func(t *testing.T) {
 		res, err := http.Get(apiURL + "/beers")
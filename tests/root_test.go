@@ -2,8 +2,12 @@ package tests
 
 import (
 	"appdoki-be/app"
-	"appdoki-be/tests/seeder"
+	"appdoki-be/app/seed"
+	"context"
 	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -11,15 +15,39 @@ import (
 )
 
 var apiURL string
-var seeds *seeder.Seeder
+var seeds *seed.Seeder
 
 func TestMain(m *testing.M) {
 	apiURL = os.Getenv("API_URL")
-	seeds = seeder.NewSeeder(os.Getenv("DB_URI"))
-	seeds.TruncateAll()
+	db, err := sqlx.Connect("postgres", os.Getenv("DB_URI"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	seeds = seed.NewSeeder(db)
+	wipe()
 	m.Run()
 }
 
+// seedUsers seeds the users of the data set of the seed command, without their beers
+func seedUsers() {
+	if _, _, err := seeds.SeedUsers(context.Background()); err != nil {
+		log.Fatalf("seeding the users failed: %+v", err)
+	}
+}
+
+// seedBeerTransfers seeds the beers of the data set of the seed command
+func seedBeerTransfers() {
+	if _, err := seeds.SeedTransfers(context.Background()); err != nil {
+		log.Fatalf("seeding the beers failed: %+v", err)
+	}
+}
+
+func wipe() {
+	if err := seeds.Wipe(context.Background()); err != nil {
+		log.Fatalf("wiping the database failed: %+v", err)
+	}
+}
+
 func TestAPI_Root(t *testing.T) {
 	t.Run("/", testRoot)
 	t.Run("/users", testUsers)
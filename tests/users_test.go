@@ -11,8 +11,8 @@ import (
 func testUsers(t *testing.T) {
 	t.Run("expect GET /users to return an empty list of users", emptyListUsers)
 
-	seeds.SeedUsers()
-	defer seeds.TruncateUsers()
+	seedUsers()
+	defer wipe()
 
 	t.Run("expect GET /users to return a list of users", listUsers)
 